@@ -7,17 +7,23 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/FreePeak/cortex/pkg/server"
 
 	"github.com/FreePeak/db-mcp-server/internal/config"
+	"github.com/FreePeak/db-mcp-server/internal/delivery/compression"
 	"github.com/FreePeak/db-mcp-server/internal/delivery/mcp"
 	"github.com/FreePeak/db-mcp-server/internal/logger"
 	"github.com/FreePeak/db-mcp-server/internal/repository"
@@ -65,6 +71,7 @@ func main() {
 	serverHost := flag.String("h", "localhost", "Server host for SSE transport")
 	dbConfigJSON := flag.String("db-config", "", "JSON string with database configuration")
 	logLevel := flag.String("log-level", "info", "Log level (debug, info, warn, error)")
+	compress := flag.Bool("compress", false, "Compress large SSE transport responses with zstd or gzip for clients that accept either")
 	flag.Parse()
 
 	// Initialize logger
@@ -103,6 +110,11 @@ func main() {
 			logger.Warn("Warning: failed to set SERVER_PORT env: %v", err)
 		}
 	}
+	if *compress {
+		if err := os.Setenv("RESPONSE_COMPRESSION", "true"); err != nil {
+			logger.Warn("Warning: failed to set RESPONSE_COMPRESSION env: %v", err)
+		}
+	}
 	// Set DB_CONFIG environment variable if provided via flag
 	if *dbConfigJSON != "" {
 		if err := os.Setenv("DB_CONFIG", *dbConfigJSON); err != nil {
@@ -116,9 +128,10 @@ func main() {
 		logger.Warn("Warning: Failed to load configuration: %v", err)
 		// Create a default config if loading fails
 		cfg = &config.Config{
-			ServerPort:    finalServerPort,
-			TransportMode: *transportMode,
-			ConfigPath:    finalConfigPath,
+			ServerPort:          finalServerPort,
+			TransportMode:       *transportMode,
+			ConfigPath:          finalConfigPath,
+			ResponseCompression: *compress,
 		}
 	}
 
@@ -150,6 +163,7 @@ func main() {
 	dbRepo := repository.NewDatabaseRepository()
 	dbUseCase := usecase.NewDatabaseUseCase(dbRepo)
 	toolRegistry := mcp.NewToolRegistry(mcpServer)
+	toolRegistry.LimitConcurrency(cfg.MaxConcurrentTools)
 
 	// Set the database use case in the tool registry
 	ctx := context.Background()
@@ -169,6 +183,13 @@ func main() {
 	}
 	logger.Info("Finished registering tools")
 
+	// Keep the schema cache warm and current for databases that opt in with
+	// warm_schema_cache and schema_change_events
+	backgroundCtx, stopBackground := context.WithCancel(ctx)
+	defer stopBackground()
+	mcp.StartSchemaCrawler(backgroundCtx, dbUseCase)
+	mcp.StartSchemaChangeListeners(backgroundCtx, dbUseCase)
+
 	// If we have databases, display the available tools
 	if len(dbIDs) > 0 {
 		logger.Info("Available database tools:")
@@ -184,14 +205,82 @@ func main() {
 		logger.Info("    - list_databases: List all available databases with detailed connection information")
 		logger.Info("    - sql: Execute SQL on any database (requires database parameter)")
 		logger.Info("    - db_stats: Retrieve comprehensive database statistics and metrics")
+		logger.Info("    - get_db_settings: Show server configuration settings, highlighting values changed from default")
 		logger.Info("    - table_stats: Retrieve detailed statistics for a specific database table")
+		logger.Info("    - row_counts: Estimated and optionally exact row counts of every table in a schema")
+		logger.Info("    - largest_objects: Rank the largest tables, indexes, materialized views and TOAST tables with their growth since the previous run")
+		logger.Info("    - get_locks: Show blocked sessions, the sessions blocking them and the locks each session holds")
+		logger.Info("    - get_active_sessions: List active sessions with state, wait event, duration and current query, longest running first")
+		logger.Info("    - kill_session: Cancel a session's query or terminate the session after previewing it; requires confirm")
 		logger.Info("    - get_indexes: Retrieve all indexes from a database with detailed information")
 		logger.Info("    - get_constraints: Retrieve all constraints from a database with detailed information")
+		logger.Info("    - constraint_status: Report NOT VALID, deferrable and exclusion constraints")
 		logger.Info("    - get_views: Retrieve all views from a database with their definitions")
+		logger.Info("    - get_materialized_views: List materialized views with size, population state and last write, or refresh one after confirmation")
 		logger.Info("    - get_types: Retrieve all custom data types from a database")
+		logger.Info("    - get_functions: Retrieve user-defined functions and stored procedures with signatures, return types, language, volatility and optionally source")
+		logger.Info("    - get_sequences: List sequences with their current values, owning columns and the percentage of their range used")
+		logger.Info("    - get_extensions: List installed PostgreSQL extensions with versions and available updates, and whether the extensions other tools need are installed")
+		logger.Info("    - get_foreign_servers: List foreign data wrappers, servers, user mappings and foreign tables")
+		logger.Info("    - get_tablespaces: List tablespaces with location and size, and the largest objects in each")
 		logger.Info("    - get_schemas: Retrieve all schemas from a database with detailed information")
 		logger.Info("    - get_sample_data: Retrieve a sample of data from a database table")
 		logger.Info("    - get_unique_values: Retrieve all unique values from a column in a database table")
+		logger.Info("    - export_erd: Export an entity-relationship diagram as Mermaid or Graphviz DOT")
+		logger.Info("    - doc_coverage: Report tables and columns lacking comments, ranked by query frequency")
+		logger.Info("    - get_object_comments: Read comments on tables, views, columns and functions, or set one")
+		logger.Info("    - get_charsets_collations: Report character sets and collations and flag join columns whose collations differ")
+		logger.Info("    - build_query: Build and execute a dialect-correct query from structured parameters")
+		logger.Info("    - modify_rows: Preview and apply an UPDATE guarded by a required filter")
+		logger.Info("    - delete_rows: Preview and apply a DELETE guarded by a required filter")
+		logger.Info("    - search_schema: Find tables and columns by name pattern or comment keyword")
+		logger.Info("    - find_join_path: Find foreign key join paths between two tables")
+		logger.Info("    - suggest_joins: Suggest direct and transitive joins among a set of tables")
+		logger.Info("    - review_schema: Flag schema design smells with explanations and suggested DDL")
+		logger.Info("    - compare_plans: Compare EXPLAIN plans across databases, query versions or a baseline")
+		logger.Info("    - diff_results: Diff the results of two queries, keyed by selected columns")
+		logger.Info("    - timeseries_summary: Bucket rows by hour, day or week with counts, aggregates, gaps and spikes")
+		logger.Info("    - table_usage: Per-table read/write activity heatmap with hot and dead tables")
+		logger.Info("    - translate_sql: Translate SQL between MySQL and PostgreSQL, flagging constructs that need manual review")
+		logger.Info("    - check_enum_drift: Find column values outside a declared enum, CHECK constraint or reference list")
+		logger.Info("    - explain_query: Show a query's execution plan as a tree, raw JSON or a plain-English explanation")
+		logger.Info("    - migrate: Apply or revert versioned .sql migrations tracked in schema_migrations, with dry-run")
+		logger.Info("    - migration_status: Report applied and pending migrations, checksums and schema drift")
+		logger.Info("    - snapshot_schema: Save the schema as a named snapshot in structured JSON and canonical DDL")
+		logger.Info("    - compare_snapshot: Diff the live schema against a named snapshot to catch out-of-band changes")
+		logger.Info("    - infer_schema: Infer CREATE TABLE DDL from a CSV or JSONL file, optionally creating and loading the table")
+		logger.Info("    - create_index: Build an index online (CONCURRENTLY / ALGORITHM=INPLACE) with progress reporting")
+		logger.Info("    - run_ddl: Run a schema change with lock_timeout/lock_wait_timeout, retries with backoff on lock contention, and a report of the locks it needs and the sessions that would block it")
+		logger.Info("    - manage_partitions: Create future time-range partitions, attach/detach partitions and drop partitions older than a retention period (generates DDL, runs it with execute)")
+		logger.Info("    - get_partitions: Show the partitioning scheme, boundaries, rows and size per partition of PostgreSQL and MySQL partitioned tables")
+		logger.Info("    - table_hierarchy: Render PostgreSQL partition and inheritance trees with default and detach-pending partitions")
+		logger.Info("    - backup: Take a compressed logical backup of the database or selected tables with pg_dump/mysqldump or a CSV export into the backups directory")
+		logger.Info("    - list_backups: List recorded backups with their tables, size, duration and status")
+		logger.Info("    - restore: Restore a named backup (whole or selected tables) after verifying its checksums, refusing to overwrite existing tables unless forced")
+		logger.Info("    - replication_slots: List, create and drop replication slots with warnings about slots holding back WAL (MySQL: binlog retention)")
+		logger.Info("    - replication_status: Report replication topology and lag in seconds and bytes")
+		logger.Info("    - publications: List, create and drop PostgreSQL publications, warning about published tables without a replica identity")
+		logger.Info("    - manage_users: Create, alter and drop database users with generated passwords, connection limits and expiry; requires allow_admin")
+		logger.Info("    - manage_grants: Grant or revoke read_only, read_write or all privileges on a schema or tables, or only generate the statements with dry_run")
+		logger.Info("    - get_roles_and_privileges: List roles and their memberships with schema and table privileges, warning about risky grants")
+		logger.Info("    - analyze_table: Refresh optimizer statistics with ANALYZE and compare the table and key column statistics before and after")
+		logger.Info("    - run_maintenance: Run VACUUM, ANALYZE, REINDEX or OPTIMIZE TABLE on specific tables with their size before and after")
+		logger.Info("    - rename_object: Rename a table or column after enumerating dependent views, foreign keys, triggers and routines, running every needed statement together")
+		logger.Info("    - clone_table: Copy a table's structure with its indexes and constraints, optionally with all, a random sample or a filtered subset of its rows")
+		logger.Info("    - fix_sequences: Detect and fix sequences and auto-increment counters lagging behind MAX(id), e.g. after a restore")
+		logger.Info("    - reindex: Rebuild invalid and bloated indexes online (REINDEX CONCURRENTLY, or an in-place table rebuild on MySQL) with size before and after")
+		logger.Info("    - unused_indexes: List rarely scanned indexes, excluding primary key and unique ones, with their size, the space dropping them would free and the drop statements")
+		logger.Info("    - index_bloat: Estimate the bloat percent and wasted bytes of every PostgreSQL btree index, with REINDEX statements for those over the threshold")
+		logger.Info("    - duplicate_indexes: Find exact duplicate indexes and indexes whose columns are a prefix of another index, with the space they waste and drop statements")
+		logger.Info("    - missing_index_suggestions: Suggest indexes for sequential-scan-heavy tables and, given a workload query, for the tables it reads in full, with the estimated benefit (hypopg when installed on PostgreSQL)")
+		logger.Info("    - refresh_schema: Reload the cached schema metadata of a database")
+		logger.Info("    - fetch_rows: Read the next page of a query result whose rows past the result_memory_limit were spilled to disk, by the cursor the query returned")
+		logger.Info("    - spatial_summary: Report SRIDs, extents and spatial indexes of PostGIS and MySQL geometry columns")
+		logger.Info("    - explore_json: Infer the key structure of a JSON/JSONB column and suggest expression indexes or generated columns for filtered paths")
+		logger.Info("    - search_text: Run a ranked full-text search for plain keywords over table columns (tsvector/tsquery on PostgreSQL, MATCH ... AGAINST on MySQL)")
+		logger.Info("    - vector_search: Run a pgvector similarity search from a vector or a reference row and report HNSW/IVFFlat indexes")
+		logger.Info("    - tail_changes: Show recent row changes from a logical replication slot (wal2json/pgoutput) or the MySQL binary log")
+		logger.Info("    - federated_query: Join or union the results of sub-queries on two or more databases in memory")
 	}
 
 	// If no database connections, register mock tools to ensure at least some tools are available
@@ -228,15 +317,37 @@ func main() {
 				logger.Warn("Warning: failed to set MCP_DISABLE_LOGGING env: %v", err)
 			}
 		}
-		// Set the server address
-		mcpServer.SetAddress(fmt.Sprintf(":%d", cfg.ServerPort))
+		// Set the server address; with compression the MCP server listens on a loopback port
+		// behind a compressing proxy on the public one
+		var proxyServer *http.Server
+		serve := mcpServer.ServeHTTP
+		if cfg.ResponseCompression {
+			internal, err := newLoopbackServer(mcpServer)
+			if err != nil {
+				logger.Error("Failed to reserve a port for the MCP server: %v", err)
+				os.Exit(1)
+			}
+			serve = internal.serve
+			proxyServer = &http.Server{
+				Addr:    fmt.Sprintf(":%d", cfg.ServerPort),
+				Handler: compression.Proxy(internal.target, compression.MinSize),
+			}
+			logger.Info("Compressing responses with zstd or gzip for clients that accept either")
+		} else {
+			mcpServer.SetAddress(fmt.Sprintf(":%d", cfg.ServerPort))
+		}
 
 		// Start the server
-		errCh := make(chan error, 1)
+		errCh := make(chan error, 2)
 		go func() {
 			logger.Info("Starting server...")
-			errCh <- mcpServer.ServeHTTP()
+			errCh <- serve()
 		}()
+		if proxyServer != nil {
+			go func() {
+				errCh <- proxyServer.ListenAndServe()
+			}()
+		}
 
 		// Wait for interrupt or error
 		select {
@@ -251,6 +362,11 @@ func main() {
 			defer shutdownCancel()
 
 			// Shutdown the server
+			if proxyServer != nil {
+				if err := proxyServer.Shutdown(shutdownCtx); err != nil {
+					logger.Error("Error during compression proxy shutdown: %v", err)
+				}
+			}
 			if err := mcpServer.Shutdown(shutdownCtx); err != nil {
 				logger.Error("Error during server shutdown: %v", err)
 			}
@@ -292,3 +408,57 @@ func main() {
 
 	logger.Info("Server shutdown complete")
 }
+
+// loopbackBindAttempts is how many loopback ports the MCP server tries before giving up
+const loopbackBindAttempts = 5
+
+// loopbackServer runs the MCP server on a loopback port that only the compression proxy reaches
+type loopbackServer struct {
+	mcpServer *server.MCPServer
+	addr      atomic.Value // string
+}
+
+// newLoopbackServer reserves a loopback address for the MCP server
+func newLoopbackServer(mcpServer *server.MCPServer) (*loopbackServer, error) {
+	addr, err := loopbackAddress()
+	if err != nil {
+		return nil, err
+	}
+	s := &loopbackServer{mcpServer: mcpServer}
+	s.addr.Store(addr)
+	return s, nil
+}
+
+// serve runs the MCP server until it stops. The server can only be given an address, not a
+// listener, so another process can take the reserved port before the server binds it; the
+// server then moves to a new port, which the proxy follows through target.
+func (s *loopbackServer) serve() error {
+	for attempt := 1; ; attempt++ {
+		addr := s.addr.Load().(string)
+		s.mcpServer.SetAddress(addr)
+		err := s.mcpServer.ServeHTTP()
+		if !errors.Is(err, syscall.EADDRINUSE) || attempt == loopbackBindAttempts {
+			return err
+		}
+		logger.Warn("Loopback address %s was taken before the MCP server bound it, moving to another port", addr)
+		if addr, err = loopbackAddress(); err != nil {
+			return err
+		}
+		s.addr.Store(addr)
+	}
+}
+
+// target returns the URL the MCP server currently listens on
+func (s *loopbackServer) target() *url.URL {
+	return &url.URL{Scheme: "http", Host: s.addr.Load().(string)}
+}
+
+// loopbackAddress returns a free loopback address for a server that only a local proxy reaches
+func loopbackAddress() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	addr := listener.Addr().String()
+	return addr, listener.Close()
+}
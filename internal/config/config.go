@@ -22,6 +22,11 @@ type Config struct {
 	MultiDBConfig  *db.MultiDBConfig // New multi-database config
 	ConfigPath     string            // Path to the configuration file
 	DisableLogging bool              // When true, disables logging in stdio/SSE transport
+	// ResponseCompression zstd- or gzip-compresses large SSE transport responses for clients that
+	// accept either
+	ResponseCompression bool
+	// MaxConcurrentTools bounds the tool calls running at once across all databases
+	MaxConcurrentTools int
 }
 
 // DatabaseConfig holds database configuration (legacy support)
@@ -81,12 +86,27 @@ func LoadConfig() (*Config, error) {
 		disableLogging = true
 	}
 
+	// Parse MAX_CONCURRENT_TOOLS env var; the config file overrides it
+	maxConcurrentTools, err := strconv.Atoi(getEnv("MAX_CONCURRENT_TOOLS", "0"))
+	if err != nil {
+		logger.Warn("Warning: Invalid MAX_CONCURRENT_TOOLS value, using the default")
+		maxConcurrentTools = 0
+	}
+
+	// Parse RESPONSE_COMPRESSION env var
+	responseCompression := false
+	if v := getEnv("RESPONSE_COMPRESSION", "false"); v == "true" || v == "1" {
+		responseCompression = true
+	}
+
 	config := &Config{
-		ServerPort:     port,
-		TransportMode:  getEnv("TRANSPORT_MODE", "sse"),
-		LogLevel:       getEnv("LOG_LEVEL", "info"),
-		ConfigPath:     configPath,
-		DisableLogging: disableLogging,
+		ServerPort:          port,
+		TransportMode:       getEnv("TRANSPORT_MODE", "sse"),
+		LogLevel:            getEnv("LOG_LEVEL", "info"),
+		ConfigPath:          configPath,
+		DisableLogging:      disableLogging,
+		ResponseCompression: responseCompression,
+		MaxConcurrentTools:  maxConcurrentTools,
 		DBConfig: DatabaseConfig{
 			Type:     getEnv("DB_TYPE", "mysql"),
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -111,6 +131,9 @@ func LoadConfig() (*Config, error) {
 		}
 
 		config.MultiDBConfig = &multiDBConfig
+		if multiDBConfig.MaxConcurrentTools != 0 {
+			config.MaxConcurrentTools = multiDBConfig.MaxConcurrentTools
+		}
 	} else {
 		logger.Info("Warning: Config file not found at %s, using environment variables", config.ConfigPath)
 		// If no JSON config found, create a single connection config from environment variables
@@ -0,0 +1,179 @@
+// Package compression zstd- or gzip-compresses HTTP and SSE responses for clients that accept
+// either
+package compression
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// MinSize is the smallest response body worth compressing; event streams are always
+// compressed since the tool results they carry can be arbitrarily large
+const MinSize = 1024
+
+// encoder is the part of a gzip or zstd writer that compresses a response body
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// encoders pools the writers of each supported encoding, in order of preference
+var encoders = map[string]*sync.Pool{
+	"zstd": {New: func() interface{} {
+		// A single goroutine per writer, and a window small enough for every client to decode
+		writer, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1), zstd.WithWindowSize(1<<20))
+		return writer
+	}},
+	"gzip": {New: func() interface{} { return gzip.NewWriter(nil) }},
+}
+
+// preference lists the encodings a client accepting several with the same weight gets first
+var preference = []string{"zstd", "gzip"}
+
+// Handler compresses the responses of next that are at least minSize bytes in their first
+// write, or are event streams, when the request accepts zstd or gzip
+func Handler(next http.Handler, minSize int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := acceptedEncoding(r)
+		if encoding == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &responseWriter{ResponseWriter: w, minSize: minSize, encoding: encoding}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// Proxy forwards every request to the server target returns, compressing the responses as
+// Handler does. It puts compression in front of an HTTP server whose handler cannot be wrapped
+// directly. target is called for every request, so the proxy follows a server that moves.
+func Proxy(target func() *url.URL, minSize int) http.Handler {
+	proxy := &httputil.ReverseProxy{
+		Director: func(r *http.Request) {
+			httputil.NewSingleHostReverseProxy(target()).Director(r)
+			// The proxy compresses, so the client's encodings do not reach the target
+			r.Header.Del("Accept-Encoding")
+		},
+		// Deliver every SSE event as soon as it is written
+		FlushInterval: -1,
+	}
+	return Handler(proxy, minSize)
+}
+
+// acceptedEncoding returns the supported encoding a request weighs highest, preferring zstd
+// over gzip at equal weight, or "" when it accepts neither
+func acceptedEncoding(r *http.Request) string {
+	weights := make(map[string]float64)
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := encoders[name]; !ok {
+			continue
+		}
+		weight := 1.0
+		// gzip;q=0 refuses it
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if weight, err = strconv.ParseFloat(q, 64); err != nil {
+				weight = 0
+			}
+		}
+		weights[name] = weight
+	}
+	best := ""
+	for _, name := range preference {
+		if weights[name] > 0 && (best == "" || weights[name] > weights[best]) {
+			best = name
+		}
+	}
+	return best
+}
+
+// responseWriter holds back the status line until the first write or flush, when it decides
+// whether to compress the body
+type responseWriter struct {
+	http.ResponseWriter
+	minSize  int
+	encoding string
+	status   int
+	decided  bool
+	encoder  encoder
+}
+
+// WriteHeader implements http.ResponseWriter
+func (w *responseWriter) WriteHeader(status int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.status = status
+}
+
+// Write implements http.ResponseWriter
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		w.decide(len(p))
+	}
+	if w.encoder != nil {
+		return w.encoder.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher, pushing compressed SSE events out as they are written
+func (w *responseWriter) Flush() {
+	if !w.decided {
+		w.decide(0)
+	}
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			return
+		}
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// decide starts compressing when the body is large enough or an event stream, then sends
+// the status line
+func (w *responseWriter) decide(size int) {
+	w.decided = true
+	header := w.Header()
+	eventStream := strings.HasPrefix(header.Get("Content-Type"), "text/event-stream")
+	bodyAllowed := w.status != http.StatusNoContent && w.status != http.StatusNotModified
+	if header.Get("Content-Encoding") == "" && bodyAllowed && (eventStream || size >= w.minSize) {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		w.encoder = encoders[w.encoding].Get().(encoder)
+		w.encoder.Reset(w.ResponseWriter)
+	}
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+}
+
+// close finishes the compressed body and returns the writer to the pool
+func (w *responseWriter) close() {
+	if !w.decided {
+		w.decide(0)
+	}
+	if w.encoder == nil {
+		return
+	}
+	_ = w.encoder.Close()
+	w.encoder.Reset(nil)
+	encoders[w.encoding].Put(w.encoder)
+	w.encoder = nil
+}
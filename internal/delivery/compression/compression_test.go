@@ -0,0 +1,135 @@
+package compression
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+)
+
+// get requests a path with the given Accept-Encoding, without the client decompressing it
+func get(t *testing.T, server *httptest.Server, path, acceptEncoding string) *http.Response {
+	req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	assert.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestHandlerCompressesLargeResponses(t *testing.T) {
+	large := strings.Repeat(`{"id":1,"name":"alice"},`, 100)
+	server := httptest.NewServer(Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/small" {
+			fmt.Fprint(w, `{"ok":true}`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, large)
+	}), MinSize))
+	defer server.Close()
+
+	resp := get(t, server, "/large", "br, gzip")
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+	gz, err := gzip.NewReader(resp.Body)
+	assert.NoError(t, err)
+	body, err := io.ReadAll(gz)
+	assert.NoError(t, err)
+	assert.Equal(t, large, string(body))
+
+	resp = get(t, server, "/large", "gzip, zstd")
+	assert.Equal(t, "zstd", resp.Header.Get("Content-Encoding"))
+	zr, err := zstd.NewReader(resp.Body)
+	assert.NoError(t, err)
+	defer zr.Close()
+	body, err = io.ReadAll(zr)
+	assert.NoError(t, err)
+	assert.Equal(t, large, string(body))
+
+	resp = get(t, server, "/small", "gzip")
+	assert.Empty(t, resp.Header.Get("Content-Encoding"))
+
+	// Clients that accept neither zstd nor gzip get plain responses
+	for _, acceptEncoding := range []string{"", "br", "gzip;q=0", "zstd;q=0, gzip;q=0"} {
+		resp = get(t, server, "/large", acceptEncoding)
+		assert.Empty(t, resp.Header.Get("Content-Encoding"), acceptEncoding)
+		body, err = io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, large, string(body))
+	}
+}
+
+func TestProxyStreamsCompressedEvents(t *testing.T) {
+	next := make(chan struct{})
+	defer close(next)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 2; i++ {
+			fmt.Fprintf(w, "event: message\ndata: %d\n\n", i)
+			w.(http.Flusher).Flush()
+			<-next
+		}
+	}))
+	defer backend.Close()
+	target, err := url.Parse(backend.URL)
+	assert.NoError(t, err)
+	server := httptest.NewServer(Proxy(func() *url.URL { return target }, MinSize))
+	defer server.Close()
+
+	resp := get(t, server, "/sse", "gzip")
+	assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+	gz, err := gzip.NewReader(resp.Body)
+	assert.NoError(t, err)
+	readEvents(t, bufio.NewReader(gz), next)
+
+	resp = get(t, server, "/sse", "zstd")
+	assert.Equal(t, "zstd", resp.Header.Get("Content-Encoding"))
+	zr, err := zstd.NewReader(resp.Body)
+	assert.NoError(t, err)
+	defer zr.Close()
+	readEvents(t, bufio.NewReader(zr), next)
+}
+
+// readEvents reads the two events of the test stream, each while the stream is still open
+func readEvents(t *testing.T, events *bufio.Reader, next chan<- struct{}) {
+	for i := 0; i < 2; i++ {
+		line, err := events.ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, "event: message\n", line)
+		line, err = events.ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("data: %d\n", i), line)
+		_, err = events.ReadString('\n')
+		assert.NoError(t, err)
+		next <- struct{}{}
+	}
+}
+
+func TestAcceptedEncoding(t *testing.T) {
+	for acceptEncoding, want := range map[string]string{
+		"":                        "",
+		"br":                      "",
+		"gzip":                    "gzip",
+		"GZIP;q=0.5":              "gzip",
+		"zstd":                    "zstd",
+		"gzip, deflate, br, zstd": "zstd",
+		"zstd;q=0.5, gzip":        "gzip",
+		"zstd;q=0, gzip;q=0.1":    "gzip",
+		"zstd;q=bad":              "",
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept-Encoding", acceptEncoding)
+		assert.Equal(t, want, acceptedEncoding(r), acceptEncoding)
+	}
+}
@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// passwordAlphabet avoids quotes and backslashes so generated passwords need no escaping in SQL
+const passwordAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// generatedPasswordLength gives about 190 bits of entropy
+const generatedPasswordLength = 32
+
+// requireAdmin refuses administrative tools on connections that did not opt in with allow_admin
+func requireAdmin(useCase UseCaseProvider, dbID, tool string) error {
+	config, err := useCase.GetDatabaseConfig(dbID)
+	if err != nil {
+		return fmt.Errorf("failed to get database config: %w", err)
+	}
+	if !config.AllowAdmin {
+		return fmt.Errorf("%s is an administrative tool and is disabled for database %s; set allow_admin in its connection config to enable it", tool, dbID)
+	}
+	return nil
+}
+
+// generatePassword returns a random password from a cryptographically secure source
+func generatePassword() (string, error) {
+	max := big.NewInt(int64(len(passwordAlphabet)))
+	password := make([]byte, generatedPasswordLength)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		password[i] = passwordAlphabet[n.Int64()]
+	}
+	return string(password), nil
+}
+
+// redactSecret removes a secret from text that may end up in logs or errors
+func redactSecret(text, secret string) string {
+	if secret == "" {
+		return text
+	}
+	return strings.ReplaceAll(text, secret, "********")
+}
@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// AggregateTool handles running aggregation pipelines on a document database
+type AggregateTool struct {
+	BaseToolType
+}
+
+// NewAggregateTool creates a new aggregate tool type
+func NewAggregateTool() *AggregateTool {
+	return &AggregateTool{
+		BaseToolType: BaseToolType{
+			name:        "aggregate",
+			description: "Run a read-only MongoDB aggregation pipeline on a collection, or on the database for stages such as $documents or $currentOp when no collection is given, and return the resulting documents as relaxed Extended JSON. The pipeline is a JSON array of stages and may use Extended JSON for BSON values, such as {\"$oid\": \"...\"} or {\"$date\": \"2024-01-01T00:00:00Z\"}. $out and $merge are refused, as they write.",
+		},
+	}
+}
+
+// CreateTool creates an aggregate tool
+func (t *AggregateTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Run a read-only MongoDB aggregation pipeline"),
+		tools.WithString("database",
+			tools.Description("Database ID to run the pipeline on"),
+			tools.Required(),
+		),
+		tools.WithString("collection",
+			tools.Description("Collection to aggregate (optional, leave empty for a database-level pipeline)"),
+		),
+		tools.WithString("pipeline",
+			tools.Description(`JSON array of pipeline stages, such as [{"$match": {"status": "paid"}}, {"$group": {"_id": "$customer", "total": {"$sum": "$amount"}}}]`),
+			tools.Required(),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of documents to return (default: 100, max: 10000)"),
+		),
+	)
+}
+
+// writeStages are the stages that make a pipeline write its output
+var writeStages = map[string]bool{"$out": true, "$merge": true}
+
+// HandleRequest handles aggregate tool requests
+func (t *AggregateTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	collection := input.optionalString("collection", "")
+	pipeline := input.requiredString("pipeline")
+	limit := input.intBetween("limit", 100, 1, 10000)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if err := checkReadOnlyPipeline(pipeline); err != nil {
+		return nil, err
+	}
+
+	store, err := useCase.GetDocumentStore(targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Running aggregation on database %s, collection %s: %s", targetDbID, collection, pipeline)
+
+	// One document more than the limit tells whether the result was cut
+	documents, err := store.Aggregate(ctx, collection, pipeline, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run pipeline: %w", err)
+	}
+	truncated := len(documents) > limit
+	if truncated {
+		documents = documents[:limit]
+	}
+
+	var response strings.Builder
+	if collection == "" {
+		response.WriteString(fmt.Sprintf("# Aggregation on Database %s\n\n", targetDbID))
+	} else {
+		response.WriteString(fmt.Sprintf("# Aggregation on Collection %s in Database %s\n\n", collection, targetDbID))
+	}
+	response.WriteString(formatDocuments(documents))
+	if truncated {
+		response.WriteString(fmt.Sprintf("\nShowing the first %d documents; raise limit or add a $limit stage to see more.\n", limit))
+	} else {
+		response.WriteString(fmt.Sprintf("\n%d documents\n", len(documents)))
+	}
+	return createTextResponse(response.String()), nil
+}
+
+// checkReadOnlyPipeline checks that a pipeline is a JSON array of single-operator stages
+// none of which writes
+func checkReadOnlyPipeline(pipeline string) error {
+	var stages []map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(pipeline), &stages); err != nil {
+		return fmt.Errorf("pipeline must be a JSON array of stages: %w", err)
+	}
+	for i, stage := range stages {
+		if len(stage) != 1 {
+			return fmt.Errorf("stage %d must have exactly one operator, such as $match", i+1)
+		}
+		for operator := range stage {
+			if writeStages[operator] {
+				return fmt.Errorf("stage %d uses %s, which writes; the aggregate tool only reads", i+1, operator)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateTool(t *testing.T) {
+	store := &fakeDocumentStore{documents: canonical(t, `[
+		{"_id": "alice", "total": {"$numberInt": "30"}},
+		{"_id": "bob", "total": {"$numberDouble": "12.5"}},
+		{"_id": "carol", "total": {"$numberLong": "7"}}
+	]`)}
+	pipeline := `[{"$match": {"status": "paid"}}, {"$group": {"_id": "$customer", "total": {"$sum": "$amount"}}}]`
+
+	text := documentToolText(t, NewAggregateTool(), store, map[string]interface{}{"database": "shop", "collection": "orders", "pipeline": pipeline})
+	assert.Contains(t, text, "# Aggregation on Collection orders in Database shop")
+	assert.Contains(t, text, "\"_id\": \"bob\",\n    \"total\": 12.5")
+	assert.Contains(t, text, "3 documents")
+	assert.Equal(t, []string{"orders " + pipeline}, store.pipelines)
+	assert.Equal(t, []int{101}, store.limits)
+
+	// One document more than the limit is read to tell that the result was cut
+	text = documentToolText(t, NewAggregateTool(), store, map[string]interface{}{"database": "shop", "pipeline": `[{"$documents": []}]`, "limit": 2})
+	assert.Contains(t, text, "# Aggregation on Database shop")
+	assert.NotContains(t, text, "carol")
+	assert.Contains(t, text, "Showing the first 2 documents")
+	assert.Equal(t, 3, store.limits[1])
+}
+
+func TestAggregateToolRefusesWrites(t *testing.T) {
+	store := &fakeDocumentStore{}
+	run := func(pipeline string) error {
+		request := server.ToolCallRequest{Parameters: map[string]interface{}{"database": "shop", "collection": "orders", "pipeline": pipeline}}
+		_, err := NewAggregateTool().HandleRequest(context.Background(), request, "", &mockUseCase{documents: store})
+		return err
+	}
+
+	assert.EqualError(t, run(`[{"$match": {}}, {"$out": "copy"}]`), "stage 2 uses $out, which writes; the aggregate tool only reads")
+	assert.EqualError(t, run(`[{"$merge": {"into": "copy"}}]`), "stage 1 uses $merge, which writes; the aggregate tool only reads")
+	assert.EqualError(t, run(`[{"$match": {}, "$limit": 1}]`), "stage 1 must have exactly one operator, such as $match")
+	assert.ErrorContains(t, run(`{"$match": {}}`), "pipeline must be a JSON array of stages")
+	assert.Empty(t, store.pipelines)
+}
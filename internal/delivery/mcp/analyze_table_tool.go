@@ -0,0 +1,401 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// optimizerStatistics is a snapshot of the planner statistics of a table
+type optimizerStatistics struct {
+	// Table holds table-level values by label, in the order of Labels
+	Labels []string
+	Table  map[string]string
+
+	// Headers name the per-column values in Columns
+	Headers []string
+	Columns map[string][]string
+	Order   []string
+}
+
+// AnalyzeTableTool handles refreshing optimizer statistics
+type AnalyzeTableTool struct {
+	BaseToolType
+}
+
+// NewAnalyzeTableTool creates a new analyze tool type
+func NewAnalyzeTableTool() *AnalyzeTableTool {
+	return &AnalyzeTableTool{
+		BaseToolType: BaseToolType{
+			name:        "analyze_table",
+			description: "Refresh the optimizer statistics of a table with ANALYZE (PostgreSQL) or ANALYZE TABLE (MySQL) and report the statistics of its key columns before and after. On PostgreSQL, statistics_target samples more rows for this run only; on MySQL, histogram_buckets builds or refreshes histograms on the given columns. Columns default to the indexed ones.",
+		},
+	}
+}
+
+// CreateTool creates an analyze tool
+func (t *AnalyzeTableTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Refresh optimizer statistics of a table and compare them before and after"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Table to analyze"),
+			tools.Required(),
+		),
+		tools.WithArray("columns",
+			tools.Description("Columns to analyze and report (default: all columns analyzed, indexed columns reported)"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithNumber("statistics_target",
+			tools.Description("PostgreSQL default_statistics_target for this run (1-10000)"),
+		),
+		tools.WithNumber("histogram_buckets",
+			tools.Description("MySQL: update histograms on the given columns with this many buckets (1-1024)"),
+		),
+	)
+}
+
+// HandleRequest handles analyze tool requests
+func (t *AnalyzeTableTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	table := input.requiredString("table")
+	columns := input.stringList("columns")
+	statisticsTarget := input.intBetween("statistics_target", 0, 1, 10000)
+	buckets := input.intBetween("histogram_buckets", 0, 1, 1024)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for analyze_table: %s", dbType)
+	}
+	analyzer, err := dialect.StatisticsAnalyzer()
+	if err != nil {
+		return nil, unsupportedAdminError("analyze_table", dbType, err)
+	}
+	if err := analyzer.validate(statisticsTarget, buckets); err != nil {
+		return nil, err
+	}
+	if buckets > 0 && len(columns) == 0 {
+		return nil, fmt.Errorf("histogram_buckets needs the columns to build histograms on")
+	}
+
+	before, err := analyzer.load(ctx, useCase, targetDbID, table, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	statements := analyzer.statements(table, columns, statisticsTarget, buckets)
+	messages, err := analyzer.run(ctx, useCase, targetDbID, statements)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze %s: %w", table, err)
+	}
+	logger.Info("Analyzed table %s on database %s", table, targetDbID)
+
+	after, err := analyzer.load(ctx, useCase, targetDbID, table, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Analyze %s for Database %s\n\n", table, targetDbID))
+	response.WriteString("```sql\n")
+	for _, statement := range statements {
+		response.WriteString(statement + ";\n")
+	}
+	response.WriteString("```\n\n")
+	for _, message := range messages {
+		response.WriteString(fmt.Sprintf("- %s\n", message))
+	}
+	if len(messages) > 0 {
+		response.WriteString("\n")
+	}
+	writeStatisticsComparison(&response, before, after)
+
+	return createTextResponse(response.String()), nil
+}
+
+// statisticsAnalyzer is the engine-specific part of refreshing optimizer statistics
+type statisticsAnalyzer interface {
+	// validate rejects the options the engine has no equivalent for
+	validate(statisticsTarget, buckets int) error
+	// statements builds the statements refreshing the statistics of a table
+	statements(table string, columns []string, statisticsTarget, buckets int) []string
+	// run executes the statements and returns the messages the engine reported
+	run(ctx context.Context, useCase UseCaseProvider, dbID string, statements []string) ([]string, error)
+	// load reads the table and column statistics the planner uses
+	load(ctx context.Context, useCase UseCaseProvider, dbID, table string, columns []string) (*optimizerStatistics, error)
+}
+
+// quoteColumns quotes column names for the database type
+func quoteColumns(dbType string, columns []string) []string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = quoteIdentifier(dbType, column)
+	}
+	return quoted
+}
+
+// postgresStatisticsAnalyzer runs ANALYZE, raising the statistics target for the run if asked
+type postgresStatisticsAnalyzer struct{}
+
+func (postgresStatisticsAnalyzer) validate(_, buckets int) error {
+	if buckets > 0 {
+		return fmt.Errorf("histogram_buckets is MySQL only; use statistics_target on PostgreSQL")
+	}
+	return nil
+}
+
+func (postgresStatisticsAnalyzer) statements(table string, columns []string, statisticsTarget, _ int) []string {
+	analyze := "ANALYZE " + quoteIdentifier("postgres", table)
+	if len(columns) > 0 {
+		analyze += " (" + strings.Join(quoteColumns("postgres", columns), ", ") + ")"
+	}
+	if statisticsTarget > 0 {
+		return []string{fmt.Sprintf("SET LOCAL default_statistics_target = %d", statisticsTarget), analyze}
+	}
+	return []string{analyze}
+}
+
+func (postgresStatisticsAnalyzer) run(ctx context.Context, useCase UseCaseProvider, dbID string, statements []string) ([]string, error) {
+	if len(statements) > 1 {
+		// SET LOCAL only lasts for the batch transaction, so the setting does not leak into the pool
+		return nil, useCase.ExecuteBatch(ctx, dbID, statements, nil)
+	}
+	_, err := useCase.ExecuteStatement(ctx, dbID, statements[0], nil)
+	return nil, err
+}
+
+func (postgresStatisticsAnalyzer) load(ctx context.Context, useCase UseCaseProvider, dbID, table string, columns []string) (*optimizerStatistics, error) {
+	schema, name := splitQualifiedName(table)
+	stats := &optimizerStatistics{Table: map[string]string{}, Columns: map[string][]string{}}
+
+	if schema == "" {
+		schema = "public"
+	}
+	stats.Labels = []string{"Estimated rows", "Pages", "Last analyzed", "Rows modified since"}
+	params := newSQLParams("postgres")
+	result, err := useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT c.reltuples::bigint, c.relpages,
+       COALESCE(GREATEST(t.last_analyze, t.last_autoanalyze)::text, ''),
+       COALESCE(t.n_mod_since_analyze, 0)
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_stat_user_tables t ON t.relid = c.oid
+WHERE n.nspname = %s AND c.relname = %s`, params.add(schema), params.add(name)), params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read statistics of %s: %w", table, err)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) < 4 {
+		return nil, fmt.Errorf("table %s does not exist", table)
+	}
+	row := result.Rows[0]
+	stats.Table["Estimated rows"] = fmt.Sprintf("%d", valueInt64(row[0]))
+	stats.Table["Pages"] = fmt.Sprintf("%d", valueInt64(row[1]))
+	stats.Table["Last analyzed"] = valueString(row[2])
+	stats.Table["Rows modified since"] = fmt.Sprintf("%d", valueInt64(row[3]))
+
+	// Without explicit columns, report the indexed ones: those are the ones plans hinge on
+	stats.Headers = []string{"Distinct", "Null fraction", "Avg width", "Correlation", "MCVs", "Histogram bounds"}
+	params = newSQLParams("postgres")
+	filter := `s.attname IN (
+    SELECT a.attname FROM pg_index i
+    JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+    WHERE i.indrelid = format('%I.%I', s.schemaname, s.tablename)::regclass)`
+	query := `
+SELECT s.attname, s.n_distinct, s.null_frac, s.avg_width, COALESCE(s.correlation::text, ''),
+       COALESCE(array_length(s.most_common_freqs, 1), 0), COALESCE(array_length(s.histogram_bounds, 1), 0)
+FROM pg_stats s
+WHERE s.schemaname = %s AND s.tablename = %s AND %s
+ORDER BY s.attname`
+	schemaParam, nameParam := params.add(schema), params.add(name)
+	if len(columns) > 0 {
+		placeholders := make([]string, len(columns))
+		for i, column := range columns {
+			placeholders[i] = params.add(column)
+		}
+		filter = "s.attname IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+	result, err = useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(query, schemaParam, nameParam, filter), params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read column statistics of %s: %w", table, err)
+	}
+	for _, row := range result.Rows {
+		if len(row) < 7 {
+			continue
+		}
+		column := valueString(row[0])
+		stats.Order = append(stats.Order, column)
+		stats.Columns[column] = []string{
+			formatDistinct(valueFloat64(row[1])),
+			fmt.Sprintf("%.3f", valueFloat64(row[2])),
+			fmt.Sprintf("%d", valueInt64(row[3])),
+			valueString(row[4]),
+			fmt.Sprintf("%d", valueInt64(row[5])),
+			fmt.Sprintf("%d", valueInt64(row[6])),
+		}
+	}
+	return stats, nil
+}
+
+// mysqlStatisticsAnalyzer runs ANALYZE TABLE, updating histograms if asked
+type mysqlStatisticsAnalyzer struct{}
+
+func (mysqlStatisticsAnalyzer) validate(statisticsTarget, _ int) error {
+	if statisticsTarget > 0 {
+		return fmt.Errorf("statistics_target is PostgreSQL only; use histogram_buckets on MySQL")
+	}
+	return nil
+}
+
+func (mysqlStatisticsAnalyzer) statements(table string, columns []string, _, buckets int) []string {
+	statements := []string{"ANALYZE TABLE " + quoteIdentifier("mysql", table)}
+	if buckets > 0 {
+		statements = append(statements, fmt.Sprintf("ANALYZE TABLE %s UPDATE HISTOGRAM ON %s WITH %d BUCKETS",
+			quoteIdentifier("mysql", table), strings.Join(quoteColumns("mysql", columns), ", "), buckets))
+	}
+	return statements
+}
+
+// run reads the result rows, since ANALYZE TABLE reports problems as rows rather than errors
+func (mysqlStatisticsAnalyzer) run(ctx context.Context, useCase UseCaseProvider, dbID string, statements []string) ([]string, error) {
+	var messages []string
+	for _, statement := range statements {
+		result, err := useCase.ExecuteQuery(ctx, dbID, statement, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range result.Rows {
+			if len(row) >= 4 {
+				messages = append(messages, fmt.Sprintf("%s: %s", valueString(row[2]), valueString(row[3])))
+				if strings.EqualFold(valueString(row[2]), "error") {
+					return nil, fmt.Errorf("%s", valueString(row[3]))
+				}
+			}
+		}
+	}
+	return messages, nil
+}
+
+func (mysqlStatisticsAnalyzer) load(ctx context.Context, useCase UseCaseProvider, dbID, table string, columns []string) (*optimizerStatistics, error) {
+	schema, name := splitQualifiedName(table)
+	stats := &optimizerStatistics{Table: map[string]string{}, Columns: map[string][]string{}}
+
+	schemaExpr := "DATABASE()"
+	params := newSQLParams("mysql")
+	if schema != "" {
+		schemaExpr = params.add(schema)
+	}
+	stats.Labels = []string{"Estimated rows", "Statistics updated"}
+	result, err := useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT TABLE_ROWS, COALESCE(CAST(UPDATE_TIME AS CHAR), '')
+FROM information_schema.TABLES
+WHERE TABLE_SCHEMA = %s AND TABLE_NAME = %s`, schemaExpr, params.add(name)), params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read statistics of %s: %w", table, err)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) < 2 {
+		return nil, fmt.Errorf("table %s does not exist", table)
+	}
+	stats.Table["Estimated rows"] = fmt.Sprintf("%d", valueInt64(result.Rows[0][0]))
+	stats.Table["Statistics updated"] = valueString(result.Rows[0][1])
+
+	stats.Headers = []string{"Index cardinality", "Histogram", "Histogram buckets"}
+	params = newSQLParams("mysql")
+	if schema != "" {
+		schemaExpr = params.add(schema)
+	}
+	nameParam := params.add(name)
+	result, err = useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT c.COLUMN_NAME,
+       COALESCE(CAST(MAX(s.CARDINALITY) AS CHAR), ''),
+       COALESCE(MAX(JSON_UNQUOTE(JSON_EXTRACT(h.HISTOGRAM, '$."histogram-type"'))), ''),
+       COALESCE(MAX(JSON_LENGTH(JSON_EXTRACT(h.HISTOGRAM, '$.buckets'))), 0)
+FROM information_schema.COLUMNS c
+LEFT JOIN information_schema.STATISTICS s
+  ON s.TABLE_SCHEMA = c.TABLE_SCHEMA AND s.TABLE_NAME = c.TABLE_NAME AND s.COLUMN_NAME = c.COLUMN_NAME
+LEFT JOIN information_schema.COLUMN_STATISTICS h
+  ON h.SCHEMA_NAME = c.TABLE_SCHEMA AND h.TABLE_NAME = c.TABLE_NAME AND h.COLUMN_NAME = c.COLUMN_NAME
+WHERE c.TABLE_SCHEMA = %s AND c.TABLE_NAME = %s
+GROUP BY c.COLUMN_NAME, c.ORDINAL_POSITION
+ORDER BY c.ORDINAL_POSITION`, schemaExpr, nameParam), params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read column statistics of %s: %w", table, err)
+	}
+	for _, row := range result.Rows {
+		if len(row) < 4 {
+			continue
+		}
+		column := valueString(row[0])
+		cardinality, histogram := valueString(row[1]), valueString(row[2])
+		// Without explicit columns, report the columns with index or histogram statistics
+		if len(columns) > 0 && !containsFold(columns, column) || len(columns) == 0 && cardinality == "" && histogram == "" {
+			continue
+		}
+		stats.Order = append(stats.Order, column)
+		stats.Columns[column] = []string{cardinality, histogram, fmt.Sprintf("%d", valueInt64(row[3]))}
+	}
+	return stats, nil
+}
+
+// formatDistinct renders pg_stats.n_distinct, where negative values are a fraction of the row count
+func formatDistinct(n float64) string {
+	if n < 0 {
+		return fmt.Sprintf("%.1f%% of rows", -n*100)
+	}
+	return fmt.Sprintf("%.0f", n)
+}
+
+// statisticsChange renders a value that may have changed between two snapshots
+func statisticsChange(before, after string) string {
+	if before == after {
+		return after
+	}
+	if before == "" {
+		before = "-"
+	}
+	return before + " → " + after
+}
+
+// writeStatisticsComparison renders the statistics before and after analyzing
+func writeStatisticsComparison(sb *strings.Builder, before, after *optimizerStatistics) {
+	sb.WriteString("## Table\n\n")
+	for _, label := range after.Labels {
+		sb.WriteString(fmt.Sprintf("- %s: %s\n", label, statisticsChange(before.Table[label], after.Table[label])))
+	}
+
+	sb.WriteString("\n## Columns\n\n")
+	if len(after.Order) == 0 {
+		sb.WriteString("No column statistics to report.\n")
+		return
+	}
+	sb.WriteString("| Column | " + strings.Join(after.Headers, " | ") + " |\n")
+	sb.WriteString("|--------|" + strings.Repeat("------|", len(after.Headers)) + "\n")
+	for _, column := range after.Order {
+		values := after.Columns[column]
+		previous := before.Columns[column]
+		cells := make([]string, len(values))
+		for i, value := range values {
+			old := ""
+			if i < len(previous) {
+				old = previous[i]
+			}
+			cells[i] = statisticsChange(old, value)
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s |\n", column, strings.Join(cells, " | ")))
+	}
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestAnalyzeStatements(t *testing.T) {
+	assert.Equal(t, []string{`ANALYZE "sales"."orders"`}, postgresStatisticsAnalyzer{}.statements("sales.orders", nil, 0, 0))
+	assert.Equal(t, []string{"SET LOCAL default_statistics_target = 500", `ANALYZE "orders" ("customer_id", "status")`},
+		postgresStatisticsAnalyzer{}.statements("orders", []string{"customer_id", "status"}, 500, 0))
+	assert.Equal(t, []string{"ANALYZE TABLE `orders`", "ANALYZE TABLE `orders` UPDATE HISTOGRAM ON `status` WITH 64 BUCKETS"},
+		mysqlStatisticsAnalyzer{}.statements("orders", []string{"status"}, 0, 64))
+}
+
+func TestWriteStatisticsComparison(t *testing.T) {
+	before := &optimizerStatistics{
+		Labels: []string{"Estimated rows"}, Table: map[string]string{"Estimated rows": "-1"},
+		Headers: []string{"Distinct", "Null fraction"}, Columns: map[string][]string{},
+	}
+	after := &optimizerStatistics{
+		Labels: []string{"Estimated rows"}, Table: map[string]string{"Estimated rows": "1200"},
+		Headers: []string{"Distinct", "Null fraction"},
+		Columns: map[string][]string{"status": {"4", "0.000"}}, Order: []string{"status"},
+	}
+	var sb strings.Builder
+	writeStatisticsComparison(&sb, before, after)
+	assert.Contains(t, sb.String(), "- Estimated rows: -1 → 1200")
+	assert.Contains(t, sb.String(), "| status | - → 4 | - → 0.000 |")
+	assert.Equal(t, "25.0% of rows", formatDistinct(-0.25))
+}
+
+func TestAnalyzeTableToolPostgres(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_class c": {Rows: [][]interface{}{{int64(1200), int64(15), "2024-03-01 12:00:00+00", int64(40)}}},
+			"FROM pg_stats s": {Rows: [][]interface{}{{"customer_id", float64(-0.5), float64(0), int64(4), "0.02", int64(0), int64(101)}}},
+		},
+	}
+
+	result, err := NewAnalyzeTableTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "table": "orders", "statistics_target": float64(500)},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"SET LOCAL default_statistics_target = 500", `ANALYZE "orders"`}}, useCase.batches)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "- Rows modified since: 40")
+	assert.Contains(t, text, "| customer_id | 50.0% of rows | 0.000 | 4 | 0.02 | 0 | 101 |")
+
+	_, err = NewAnalyzeTableTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "table": "orders", "histogram_buckets": float64(8)},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "MySQL only")
+
+	_, err = NewAnalyzeTableTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "table": "missing"},
+	}, "pg1", &mockUseCase{dbType: "postgres"})
+	assert.ErrorContains(t, err, "table missing does not exist")
+}
+
+func TestAnalyzeTableToolMySQL(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"information_schema.TABLES": {Rows: [][]interface{}{{int64(980), "2024-03-01 12:00:00"}}},
+			"information_schema.COLUMNS c": {Rows: [][]interface{}{
+				{"id", "980", "", int64(0)},
+				{"note", "", "", int64(0)},
+				{"status", "", "singleton", int64(4)},
+			}},
+			"ANALYZE TABLE": {Rows: [][]interface{}{{"shop.orders", "analyze", "status", "OK"}}},
+		},
+	}
+
+	result, err := NewAnalyzeTableTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "table": "orders"},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "- status: OK")
+	assert.Contains(t, text, "| id | 980 |  | 0 |")
+	assert.Contains(t, text, "| status |  | singleton | 4 |")
+	assert.NotContains(t, text, "| note |")
+
+	_, err = NewAnalyzeTableTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "table": "orders", "histogram_buckets": float64(16)},
+	}, "mysql1", useCase)
+	assert.ErrorContains(t, err, "needs the columns")
+}
@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// auditRecord is the audit trail entry of a change a tool made, or tried to make, to a database
+type auditRecord struct {
+	Time      time.Time `json:"time"`
+	Tool      string    `json:"tool"`
+	Session   string    `json:"session"`
+	Database  string    `json:"database"`
+	Action    string    `json:"action"`
+	Target    string    `json:"target,omitempty"`
+	Statement string    `json:"statement"`
+	Result    string    `json:"result"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// auditSink receives the audit records of the mutating tools. It writes each as one JSON line
+// to the server log; tests replace it to capture the records.
+var auditSink = func(record auditRecord) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		logger.Error("audit: failed to encode record of %s on database %s: %v", record.Tool, record.Database, err)
+		return
+	}
+	logger.Info("audit: %s", line)
+}
+
+// audit completes a record with the calling session and the outcome of the change and hands it
+// to the audit sink
+func audit(request server.ToolCallRequest, record auditRecord, err error) {
+	record.Time = time.Now().UTC()
+	if request.Session != nil {
+		record.Session = request.Session.ID
+	}
+	record.Result = "ok"
+	if err != nil {
+		record.Result = "failed"
+		record.Error = err.Error()
+	}
+	auditSink(record)
+}
@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+// captureAudit collects the audit records written until the test ends
+func captureAudit(t *testing.T) *[]auditRecord {
+	var records []auditRecord
+	sink := auditSink
+	auditSink = func(record auditRecord) { records = append(records, record) }
+	t.Cleanup(func() { auditSink = sink })
+	return &records
+}
+
+func TestManageUsersWritesRedactedAuditRecords(t *testing.T) {
+	records := captureAudit(t)
+	useCase := &mockUseCase{dbType: "postgres", config: domain.DatabaseConnectionConfig{AllowAdmin: true, User: "admin"}}
+	_, err := NewManageUsersTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "action": "create", "user": "app"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+
+	assert.Len(t, *records, 1)
+	record := (*records)[0]
+	assert.Equal(t, "manage_users", record.Tool)
+	assert.Equal(t, "create", record.Action)
+	assert.Equal(t, "app", record.Target)
+	assert.Equal(t, `CREATE ROLE "app" WITH LOGIN PASSWORD '********'`, record.Statement)
+	assert.Equal(t, "ok", record.Result)
+}
+
+func TestRedisCommandAuditsChangesOnly(t *testing.T) {
+	records := captureAudit(t)
+	store := &fakeKeyValueStore{replies: map[string]interface{}{"GET a": "1", "DEL a": int64(1)}}
+	useCase := &mockUseCase{keyValues: store}
+	run := func(command string) {
+		params := map[string]interface{}{"database": "cache", "command": command, "allow_write": true}
+		_, err := NewRedisCommandTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+		assert.NoError(t, err)
+	}
+
+	run("GET a")
+	assert.Empty(t, *records)
+	run("DEL a")
+	assert.Equal(t, []auditRecord{{Tool: "redis_command", Database: "cache", Action: "write", Target: "DEL", Statement: "DEL a", Result: "ok"}},
+		withoutTime(*records))
+}
+
+// withoutTime clears the time of audit records so they can be compared
+func withoutTime(records []auditRecord) []auditRecord {
+	cleared := make([]auditRecord, len(records))
+	for i, record := range records {
+		record.Time = cleared[i].Time
+		cleared[i] = record
+	}
+	return cleared
+}
@@ -0,0 +1,260 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// backupFileNameReplacer matches the characters replaced in per-table backup file names
+var backupFileNameReplacer = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// BackupTool handles taking logical backups
+type BackupTool struct {
+	BaseToolType
+}
+
+// NewBackupTool creates a new backup tool type
+func NewBackupTool() *BackupTool {
+	return &BackupTool{
+		BaseToolType: BaseToolType{
+			name:        "backup",
+			description: "Take a logical backup of the database or a list of tables into the configured backups directory. The dump method runs pg_dump (custom or plain SQL format) or mysqldump with the connection's credentials; the copy method needs no client tools and exports each table as CSV next to the DDL of the tables. Files are gzip-compressed unless compression is none, and every backup records its files, sizes, SHA-256 checksums and timing in backup.json so it can be found with list_backups.",
+		},
+	}
+}
+
+// CreateTool creates a backup tool
+func (t *BackupTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Take a logical backup with pg_dump, mysqldump or a CSV export"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithArray("tables",
+			tools.Description("Tables to back up (default: the whole database)"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithString("method",
+			tools.Description("dump (pg_dump/mysqldump) or copy (CSV export through the connection) (default: dump)"),
+		),
+		tools.WithString("format",
+			tools.Description("Dump format on PostgreSQL: custom or sql (default: custom); MySQL dumps are always sql"),
+		),
+		tools.WithString("compression",
+			tools.Description("gzip or none (default: gzip)"),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema whose tables the copy method exports when no tables are given"),
+		),
+		tools.WithString("label",
+			tools.Description("Label appended to the backup ID"),
+		),
+	)
+}
+
+// HandleRequest handles backup tool requests
+func (t *BackupTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	tables := input.stringList("tables")
+	method := input.choice("method", "dump", "dump", "copy")
+	format := input.choice("format", "", "custom", "sql", "csv")
+	compression := input.choice("compression", "gzip", "gzip", "none")
+	schema := input.optionalString("schema", "")
+	label := input.optionalString("label", "")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if label != "" && !snapshotNamePattern.MatchString(label) {
+
+		return nil, fmt.Errorf("invalid label %q: use letters, digits, '.', '_' and '-'", label)
+	}
+
+	config, err := useCase.GetDatabaseConfig(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database configuration: %w", err)
+	}
+	dir, err := backupsDir(useCase, targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect := strings.ToLower(dbType)
+
+	switch method {
+	case "dump":
+		if format == "" {
+			tool, err := lookupDumpTool(dialect, "backup")
+			if err != nil {
+				return nil, err
+			}
+			format = tool.defaultFormat()
+		}
+		if format == "csv" {
+			return nil, fmt.Errorf("the dump method writes custom or sql")
+		}
+	case "copy":
+		if format != "" && format != "csv" {
+			return nil, fmt.Errorf("the copy method always writes csv")
+		}
+		format = "csv"
+	}
+
+	start := time.Now()
+	record := &backupRecord{
+		ID:          newBackupID(start, label),
+		Label:       label,
+		Database:    targetDbID,
+		Type:        dialect,
+		Method:      method,
+		Format:      format,
+		Compression: compression,
+		Tables:      tables,
+		StartedAt:   start.UTC(),
+	}
+	backupDir := filepath.Join(dir, record.ID)
+	if _, err := os.Stat(backupDir); err == nil {
+		return nil, fmt.Errorf("backup %s already exists", record.ID)
+	}
+	if err := os.MkdirAll(backupDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory %s: %w", backupDir, err)
+	}
+
+	logger.Info("Starting %s backup %s of database %s", method, record.ID, targetDbID)
+	if method == "dump" {
+		err = runDumpBackup(ctx, record, backupDir, config)
+	} else {
+		err = runCopyBackup(ctx, useCase, record, backupDir, schema)
+	}
+	record.FinishedAt = time.Now().UTC()
+	if err != nil {
+		// Keep the metadata of the failed backup but not its partial files
+		record.Status, record.Error, record.Files = "failed", err.Error(), nil
+		if removeErr := os.RemoveAll(backupDir); removeErr == nil {
+			_ = os.MkdirAll(backupDir, 0o755)
+		}
+		if saveErr := saveBackupRecord(dir, record); saveErr != nil {
+			logger.Warn("Failed to record failed backup %s: %v", record.ID, saveErr)
+		}
+		return nil, fmt.Errorf("backup %s failed: %w", record.ID, err)
+	}
+	record.Status = "completed"
+	if err := saveBackupRecord(dir, record); err != nil {
+		return nil, err
+	}
+	logger.Info("Backup %s of database %s completed: %s", record.ID, targetDbID, formatBackupSize(record.totalBytes()))
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Backup for Database %s\n\n", targetDbID))
+	response.WriteString(fmt.Sprintf("Backup ID: %s\n", record.ID))
+	response.WriteString(fmt.Sprintf("Directory: %s\n", backupDir))
+	response.WriteString(fmt.Sprintf("Method: %s (%s, compression %s)\n", method, format, compression))
+	if len(record.Tables) > 0 {
+		response.WriteString(fmt.Sprintf("Tables: %s\n", strings.Join(record.Tables, ", ")))
+	}
+	response.WriteString(fmt.Sprintf("Duration: %s\n\n", record.FinishedAt.Sub(record.StartedAt).Round(time.Millisecond)))
+	response.WriteString("| File | Table | Rows | Size | SHA-256 |\n")
+	response.WriteString("|------|-------|------|------|---------|\n")
+	for _, f := range record.Files {
+		rows := ""
+		if f.Table != "" {
+			rows = fmt.Sprintf("%d", f.Rows)
+		}
+		response.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", f.Path, f.Table, rows, formatBackupSize(f.Bytes), f.SHA256))
+	}
+	response.WriteString(fmt.Sprintf("\nTotal size: %s\n", formatBackupSize(record.totalBytes())))
+
+	return createTextResponse(response.String()), nil
+}
+
+// runDumpBackup writes the output of pg_dump or mysqldump into the backup directory
+func runDumpBackup(ctx context.Context, record *backupRecord, backupDir string, config *domain.DatabaseConnectionConfig) error {
+	name, args, env, err := dumpCommand(record.Type, config, record.Format, record.Compression, record.Tables)
+	if err != nil {
+		return err
+	}
+	fileName := "dump.sql"
+	// The custom format compresses by itself
+	compress := record.Compression == "gzip"
+	if record.Format == "custom" {
+		fileName, compress = "dump.pgdump", false
+	}
+
+	out, err := createBackupFile(backupDir, fileName, compress)
+	if err != nil {
+		return err
+	}
+	runErr := runDumpCommand(ctx, name, args, env, out)
+	file, closeErr := out.close()
+	if runErr != nil {
+		return runErr
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to write backup file: %w", closeErr)
+	}
+	record.Files = append(record.Files, file)
+	return nil
+}
+
+// runCopyBackup exports the DDL of the tables and each table as CSV
+func runCopyBackup(ctx context.Context, useCase UseCaseProvider, record *backupRecord, backupDir, schema string) error {
+	meta, err := loadSchemaMetadata(ctx, useCase, record.Database, schema)
+	if err != nil {
+		return err
+	}
+	if len(record.Tables) == 0 {
+		for _, table := range meta.Tables {
+			record.Tables = append(record.Tables, table.Name)
+		}
+	}
+	if len(record.Tables) == 0 {
+		return fmt.Errorf("no tables to back up")
+	}
+
+	ddl, err := createBackupFile(backupDir, "schema.sql", false)
+	if err != nil {
+		return err
+	}
+	_, writeErr := ddl.Write([]byte(renderSchemaDDL(filterSchemaTables(meta, record.Tables))))
+	file, closeErr := ddl.close()
+	if writeErr != nil {
+		return fmt.Errorf("failed to write schema: %w", writeErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to write schema: %w", closeErr)
+	}
+	record.Files = append(record.Files, file)
+
+	for _, table := range record.Tables {
+		out, err := createBackupFile(backupDir, backupFileNameReplacer.ReplaceAllString(table, "_")+".csv", record.Compression == "gzip")
+		if err != nil {
+			return err
+		}
+		rows, exportErr := exportTableCSV(ctx, useCase, record.Database, record.Type, table, out)
+		file, closeErr := out.close()
+		if exportErr != nil {
+			return exportErr
+		}
+		if closeErr != nil {
+			return fmt.Errorf("failed to write backup file: %w", closeErr)
+		}
+		file.Table, file.Rows = table, rows
+		record.Files = append(record.Files, file)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package mcp
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func fakeDumpCommand(t *testing.T, output string, err error) *[]string {
+	var calls []string
+	previous := runDumpCommand
+	runDumpCommand = func(ctx context.Context, name string, args, env []string, out io.Writer) error {
+		calls = append(append(calls, name), args...)
+		_, _ = io.WriteString(out, output)
+		return err
+	}
+	t.Cleanup(func() { runDumpCommand = previous })
+	return &calls
+}
+
+func TestDumpCommand(t *testing.T) {
+	config := &domain.DatabaseConnectionConfig{Host: "db", Port: 5432, User: "app", Password: "secret", Name: "shop"}
+	name, args, env, err := dumpCommand("postgres", config, "custom", "gzip", []string{"orders"})
+	assert.NoError(t, err)
+	assert.Equal(t, "pg_dump", name)
+	assert.Equal(t, []string{"--host", "db", "--port", "5432", "--username", "app", "--dbname", "shop", "--no-password",
+		"--format", "custom", "--compress", "6", "--table", "orders"}, args)
+	assert.Equal(t, []string{"PGPASSWORD=secret"}, env)
+
+	name, args, _, err = dumpCommand("mysql", config, "sql", "none", []string{"orders", "items"})
+	assert.NoError(t, err)
+	assert.Equal(t, "mysqldump", name)
+	assert.Equal(t, []string{"shop", "orders", "items"}, args[len(args)-3:])
+
+	_, _, _, err = dumpCommand("mysql", config, "custom", "gzip", nil)
+	assert.Error(t, err)
+}
+
+func TestBackupToolDumpAndList(t *testing.T) {
+	dir := t.TempDir()
+	calls := fakeDumpCommand(t, "CREATE TABLE orders ();\n", nil)
+	useCase := &mockUseCase{dbType: "postgres", config: domain.DatabaseConnectionConfig{BackupsDir: dir, Name: "shop"}}
+
+	result, err := NewBackupTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "format": "sql", "label": "nightly"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	assert.Equal(t, "pg_dump", (*calls)[0])
+	assert.Contains(t, *calls, "plain")
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "Method: dump (sql, compression gzip)")
+	assert.Contains(t, text, "| dump.sql.gz |  |  |")
+
+	backups, err := listBackups(dir)
+	assert.NoError(t, err)
+	assert.Len(t, backups, 1)
+	assert.Equal(t, "completed", backups[0].Status)
+	assert.Regexp(t, `^\d{8}T\d{6}Z_nightly$`, backups[0].ID)
+
+	file, err := os.Open(filepath.Join(dir, backups[0].ID, "dump.sql.gz"))
+	assert.NoError(t, err)
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	assert.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE orders ();\n", string(content))
+
+	fakeDumpCommand(t, "partial", errors.New("pg_dump failed: exit status 1: permission denied"))
+	_, err = NewBackupTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "label": "retry"},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "permission denied")
+
+	result, err = NewListBackupsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	text = result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "_nightly | ")
+	assert.Contains(t, text, "| dump (sql, gzip) | all |")
+	assert.Contains(t, text, "| failed: pg_dump failed: exit status 1: permission denied |")
+}
+
+func TestBackupToolCopy(t *testing.T) {
+	dir := t.TempDir()
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		config: domain.DatabaseConnectionConfig{BackupsDir: dir},
+		results: map[string]*domain.QueryResult{
+			"FROM `orders`": {
+				Columns: []string{"id", "note"},
+				Rows:    [][]interface{}{{int64(1), "first, with comma"}, {int64(2), nil}},
+			},
+		},
+	}
+
+	result, err := NewBackupTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "method": "copy", "tables": []interface{}{"orders"}, "compression": "none"},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| orders.csv | orders | 2 |")
+
+	backups, err := listBackups(dir)
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(dir, backups[0].ID, "orders.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, "id,note\n1,\"first, with comma\"\n2,\\N\n", string(content))
+	assert.Equal(t, "csv", backups[0].Format)
+	assert.Len(t, backups[0].Files, 2)
+}
+
+func TestExportTableCSVPostgresCopy(t *testing.T) {
+	calls := fakeDumpCommand(t, "id,note\n1,\"two\nlines\"\n2,\\N\n", nil)
+	useCase := &mockUseCase{dbType: "postgres", config: domain.DatabaseConnectionConfig{Host: "db", Port: 5432, User: "app", Name: "shop"}}
+
+	var out strings.Builder
+	rows, err := exportTableCSV(context.Background(), useCase, "pg1", "postgres", "orders", &out)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), rows)
+	assert.Equal(t, "id,note\n1,\"two\nlines\"\n2,\\N\n", out.String())
+	assert.Equal(t, "psql", (*calls)[0])
+	assert.Equal(t, `COPY "orders" TO STDOUT WITH (FORMAT csv, HEADER true, NULL '\N')`, (*calls)[len(*calls)-1])
+	assert.Empty(t, useCase.queries)
+}
+
+func TestExportTableCSVWithoutPsql(t *testing.T) {
+	fakeDumpCommand(t, "", exec.ErrNotFound)
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			`FROM "orders"`: {Columns: []string{"id"}, Rows: [][]interface{}{{int64(1)}, {nil}}},
+		},
+	}
+
+	var out strings.Builder
+	rows, err := exportTableCSV(context.Background(), useCase, "pg1", "postgres", "orders", &out)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), rows)
+	assert.Equal(t, "id\n1\n\\N\n", out.String())
+}
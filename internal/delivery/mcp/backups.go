@@ -0,0 +1,576 @@
+package mcp
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// backupMetadataFile is the name of the metadata file inside every backup directory
+const backupMetadataFile = "backup.json"
+
+// backupNullMarker stands for NULL in CSV exports, as in COPY's text format
+const backupNullMarker = `\N`
+
+// runDumpCommand runs an external dump program writing its output to out; tests replace it
+var runDumpCommand = func(ctx context.Context, name string, args, env []string, out io.Writer) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = out
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s failed: %w: %s", name, err, msg)
+		}
+		return fmt.Errorf("%s failed: %w", name, err)
+	}
+	return nil
+}
+
+// backupFile is one file written by a backup
+type backupFile struct {
+	Path   string `json:"path"` // relative to the backup directory
+	Table  string `json:"table,omitempty"`
+	Rows   int64  `json:"rows,omitempty"`
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256"`
+}
+
+// backupRecord is the metadata stored with every backup
+type backupRecord struct {
+	ID          string       `json:"id"`
+	Label       string       `json:"label,omitempty"`
+	Database    string       `json:"database"`
+	Type        string       `json:"type"`
+	Method      string       `json:"method"` // dump or copy
+	Format      string       `json:"format"` // custom, sql or csv
+	Compression string       `json:"compression"`
+	Tables      []string     `json:"tables,omitempty"`
+	Files       []backupFile `json:"files"`
+	Status      string       `json:"status"`
+	Error       string       `json:"error,omitempty"`
+	StartedAt   time.Time    `json:"started_at"`
+	FinishedAt  time.Time    `json:"finished_at"`
+}
+
+// totalBytes returns the size of all files of the backup
+func (b *backupRecord) totalBytes() int64 {
+	var total int64
+	for _, f := range b.Files {
+		total += f.Bytes
+	}
+	return total
+}
+
+// backupsDir returns the backup directory configured for a database
+func backupsDir(useCase UseCaseProvider, dbID string) (string, error) {
+	config, err := useCase.GetDatabaseConfig(dbID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get database configuration: %w", err)
+	}
+	if config.BackupsDir == "" {
+		return "", fmt.Errorf("no backups directory configured for database %s", dbID)
+	}
+	return config.BackupsDir, nil
+}
+
+// newBackupID builds a sortable backup ID from the start time and an optional label
+func newBackupID(start time.Time, label string) string {
+	id := start.UTC().Format("20060102T150405Z")
+	if label != "" {
+		id += "_" + label
+	}
+	return id
+}
+
+// saveBackupRecord writes the metadata of a backup into its directory
+func saveBackupRecord(dir string, record *backupRecord) error {
+	content, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode backup metadata: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, record.ID, backupMetadataFile), append(content, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write backup metadata: %w", err)
+	}
+	return nil
+}
+
+// listBackups reads the metadata of every backup in a directory, newest first;
+// a missing directory has none
+func listBackups(dir string) ([]*backupRecord, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backups directory %s: %w", dir, err)
+	}
+
+	var backups []*backupRecord
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		record, err := loadBackupRecord(dir, entry.Name())
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, record)
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].StartedAt.After(backups[j].StartedAt) })
+	return backups, nil
+}
+
+// loadBackupRecord reads the metadata of one backup
+func loadBackupRecord(dir, id string) (*backupRecord, error) {
+	if !snapshotNamePattern.MatchString(id) {
+		return nil, fmt.Errorf("invalid backup ID %q", id)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, id, backupMetadataFile))
+	if err != nil {
+		return nil, err
+	}
+	var record backupRecord
+	if err := json.Unmarshal(content, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse backup %s: %w", id, err)
+	}
+	return &record, nil
+}
+
+// dumpTool runs the dump and restore programs of a database engine
+type dumpTool interface {
+	// defaultFormat is the format dumps are written in when none is asked for
+	defaultFormat() string
+	// dumpCommand builds the invocation writing a dump to stdout; the password is passed in the
+	// environment so it does not show up in the process list
+	dumpCommand(config *domain.DatabaseConnectionConfig, format, compression string, tables []string) (string, []string, []string, error)
+	// restoreCommand builds the invocation restoring a dump from stdin
+	restoreCommand(config *domain.DatabaseConnectionConfig, format string, tables []string, clean bool) (string, []string, []string, error)
+	// restoreNotes explains what a restore of the format leaves behind
+	restoreNotes(format string, tables []string, clean bool) []string
+}
+
+// lookupDumpTool returns the dump tool of a database type, failing with the feature it is
+// needed for
+func lookupDumpTool(dbType, feature string) (dumpTool, error) {
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for %s: %s", feature, dbType)
+	}
+	tool, err := dialect.DumpTool()
+	if err != nil {
+		return nil, unsupportedAdminError(feature, dbType, err)
+	}
+	return tool, nil
+}
+
+// dumpCommand builds the dump invocation for a backup of a database type
+func dumpCommand(dbType string, config *domain.DatabaseConnectionConfig, format, compression string, tables []string) (string, []string, []string, error) {
+	tool, err := lookupDumpTool(dbType, "backup")
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return tool.dumpCommand(config, format, compression, tables)
+}
+
+// postgresDumpTool dumps with pg_dump and restores with pg_restore or psql
+type postgresDumpTool struct{}
+
+func (postgresDumpTool) defaultFormat() string { return "custom" }
+
+func (postgresDumpTool) dumpCommand(config *domain.DatabaseConnectionConfig, format, compression string, tables []string) (string, []string, []string, error) {
+	args := []string{"--host", config.Host, "--port", strconv.Itoa(config.Port), "--username", config.User, "--dbname", config.Name, "--no-password"}
+	if format == "custom" {
+		level := "6"
+		if compression == "none" {
+			level = "0"
+		}
+		args = append(args, "--format", "custom", "--compress", level)
+	} else {
+		args = append(args, "--format", "plain")
+	}
+	for _, table := range tables {
+		args = append(args, "--table", table)
+	}
+	return "pg_dump", args, []string{"PGPASSWORD=" + config.Password}, nil
+}
+
+func (postgresDumpTool) restoreCommand(config *domain.DatabaseConnectionConfig, format string, tables []string, clean bool) (string, []string, []string, error) {
+	connection := []string{"--host", config.Host, "--port", strconv.Itoa(config.Port), "--username", config.User, "--dbname", config.Name, "--no-password"}
+	env := []string{"PGPASSWORD=" + config.Password}
+	if format == "custom" {
+		args := append(connection, "--no-owner", "--exit-on-error")
+		if clean {
+			args = append(args, "--clean", "--if-exists")
+		}
+		for _, table := range tables {
+			args = append(args, "--table", table)
+		}
+		return "pg_restore", args, env, nil
+	}
+	if len(tables) > 0 {
+		return "", nil, nil, fmt.Errorf("plain SQL dumps cannot be restored table by table; use a custom-format or copy backup")
+	}
+	return "psql", append(connection, "--set", "ON_ERROR_STOP=1", "--single-transaction", "--quiet"), env, nil
+}
+
+func (postgresDumpTool) restoreNotes(format string, tables []string, clean bool) []string {
+	var notes []string
+	if format == "custom" && len(tables) > 0 {
+		notes = append(notes, "pg_restore --table restores the table definitions and data only; recreate their indexes, constraints and triggers separately if they are missing.")
+	}
+	if format == "sql" && clean {
+		notes = append(notes, "Plain SQL dumps do not drop existing tables; the restore runs in a single transaction and is rolled back if a CREATE statement conflicts.")
+	}
+	return notes
+}
+
+// mysqlDumpTool dumps with mysqldump and restores with the mysql client
+type mysqlDumpTool struct{}
+
+func (mysqlDumpTool) defaultFormat() string { return "sql" }
+
+func (mysqlDumpTool) dumpCommand(config *domain.DatabaseConnectionConfig, format, compression string, tables []string) (string, []string, []string, error) {
+	if format == "custom" {
+		return "", nil, nil, fmt.Errorf("the custom format is only available on PostgreSQL")
+	}
+	args := []string{"--host", config.Host, "--port", strconv.Itoa(config.Port), "--user", config.User,
+		"--single-transaction", "--routines", "--triggers", config.Name}
+	args = append(args, tables...)
+	return "mysqldump", args, []string{"MYSQL_PWD=" + config.Password}, nil
+}
+
+func (mysqlDumpTool) restoreCommand(config *domain.DatabaseConnectionConfig, format string, tables []string, clean bool) (string, []string, []string, error) {
+	if len(tables) > 0 {
+		return "", nil, nil, fmt.Errorf("mysqldump backups cannot be restored table by table; use a copy backup")
+	}
+	return "mysql", []string{"--host", config.Host, "--port", strconv.Itoa(config.Port), "--user", config.User, config.Name},
+		[]string{"MYSQL_PWD=" + config.Password}, nil
+}
+
+func (mysqlDumpTool) restoreNotes(string, []string, bool) []string { return nil }
+
+// backupWriter writes one backup file, compressing it if asked, and keeps its size and checksum
+type backupWriter struct {
+	file   *os.File
+	gzip   *gzip.Writer
+	writer io.Writer
+	path   string
+	digest func() string
+}
+
+// createBackupFile creates a file in the backup directory
+func createBackupFile(dir, name string, compress bool) (*backupWriter, error) {
+	if compress {
+		name += ".gz"
+	}
+	file, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup file: %w", err)
+	}
+	hash := sha256.New()
+	w := &backupWriter{file: file, path: name, digest: func() string { return hex.EncodeToString(hash.Sum(nil)) }}
+	// The checksum covers the bytes on disk so restores can verify the file as stored
+	out := io.MultiWriter(file, hash)
+	w.writer = out
+	if compress {
+		w.gzip = gzip.NewWriter(out)
+		w.writer = w.gzip
+	}
+	return w, nil
+}
+
+// Write implements io.Writer
+func (w *backupWriter) Write(p []byte) (int, error) {
+	return w.writer.Write(p)
+}
+
+// close finishes the file and describes it
+func (w *backupWriter) close() (backupFile, error) {
+	if w.gzip != nil {
+		if err := w.gzip.Close(); err != nil {
+			_ = w.file.Close()
+			return backupFile{}, err
+		}
+	}
+	info, err := w.file.Stat()
+	if err != nil {
+		_ = w.file.Close()
+		return backupFile{}, err
+	}
+	if err := w.file.Close(); err != nil {
+		return backupFile{}, err
+	}
+	return backupFile{Path: w.path, Bytes: info.Size(), SHA256: w.digest()}, nil
+}
+
+// exportTableCSV writes all rows of a table as CSV with a header, using \N for NULL. Databases with
+// a CSV export program, such as PostgreSQL with COPY TO STDOUT through psql, are exported with it;
+// other databases, and those whose program is not installed, stream the rows through the driver. Neither holds the table in memory.
+func exportTableCSV(ctx context.Context, useCase UseCaseProvider, dbID, dialect, table string, out io.Writer) (int64, error) {
+	if copyOut, ok := csvExportCommandFor(dialect); ok {
+		config, err := useCase.GetDatabaseConfig(dbID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get database config: %w", err)
+		}
+		counter := &csvRecordCounter{out: out}
+		name, args, env := copyOut(config, quoteIdentifier(dialect, table))
+		err = runDumpCommand(ctx, name, args, env, counter)
+		if err == nil {
+			return max(counter.records-1, 0), nil
+		}
+		if !errors.Is(err, exec.ErrNotFound) || counter.written {
+			return 0, fmt.Errorf("failed to copy table %s: %w", table, err)
+		}
+		logger.Warn("%s is not installed, streaming table %s through the driver instead", name, table)
+	}
+
+	w := &csvRowWriter{w: csv.NewWriter(out)}
+	rows, err := useCase.StreamQuery(ctx, dbID, "SELECT * FROM "+quoteIdentifier(dialect, table), nil, w)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read table %s: %w", table, err)
+	}
+	w.w.Flush()
+	return rows, w.w.Error()
+}
+
+// csvExportCommand builds the invocation of a client program writing a table to stdout as CSV
+// with a header and \N for NULL
+type csvExportCommand func(config *domain.DatabaseConnectionConfig, table string) (string, []string, []string)
+
+// csvExportCommandFor returns the CSV export program of a database type, if it has one
+func csvExportCommandFor(dbType string) (csvExportCommand, bool) {
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, false
+	}
+	return dialect.CSVExportCommand()
+}
+
+// copyOutCommand builds the psql invocation that copies a table to stdout as CSV
+func copyOutCommand(config *domain.DatabaseConnectionConfig, table string) (string, []string, []string) {
+	copySQL := fmt.Sprintf(`COPY %s TO STDOUT WITH (FORMAT csv, HEADER true, NULL '%s')`, table, backupNullMarker)
+	args := []string{"--host", config.Host, "--port", strconv.Itoa(config.Port), "--username", config.User, "--dbname", config.Name, "--no-password",
+		"--no-psqlrc", "--quiet", "--set", "ON_ERROR_STOP=1", "--command", copySQL}
+	return "psql", args, []string{"PGPASSWORD=" + config.Password}
+}
+
+// csvRowWriter is a RowHandler writing each row as a CSV record
+type csvRowWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+// Columns implements domain.RowHandler by writing the header
+func (c *csvRowWriter) Columns(columns []string) error {
+	c.record = make([]string, len(columns))
+	return c.w.Write(columns)
+}
+
+// Row implements domain.RowHandler
+func (c *csvRowWriter) Row(values []interface{}) error {
+	for i := range c.record {
+		c.record[i] = backupCSVValue(values[i])
+	}
+	return c.w.Write(c.record)
+}
+
+// csvRecordCounter passes CSV through to out and counts its records, skipping line breaks inside quoted fields
+type csvRecordCounter struct {
+	out     io.Writer
+	quoted  bool
+	written bool
+	records int64
+}
+
+// Write implements io.Writer
+func (c *csvRecordCounter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		switch {
+		case b == '"':
+			c.quoted = !c.quoted
+		case b == '\n' && !c.quoted:
+			c.records++
+		}
+	}
+	c.written = c.written || len(p) > 0
+	return c.out.Write(p)
+}
+
+// backupCSVValue renders a scanned value for a CSV export
+func backupCSVValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return backupNullMarker
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	default:
+		return valueString(val)
+	}
+}
+
+// filterSchemaTables keeps the given tables of the schema and the foreign keys between them
+func filterSchemaTables(meta *schemaMetadata, tables []string) *schemaMetadata {
+	filtered := &schemaMetadata{DatabaseType: meta.DatabaseType, Schema: meta.Schema}
+	for _, table := range meta.Tables {
+		if containsFold(tables, table.Name) {
+			filtered.Tables = append(filtered.Tables, table)
+		}
+	}
+	for _, fk := range meta.ForeignKeys {
+		if containsFold(tables, fk.Table) && containsFold(tables, fk.RefTable) {
+			filtered.ForeignKeys = append(filtered.ForeignKeys, fk)
+		}
+	}
+	return filtered
+}
+
+// formatBackupSize renders a byte count for humans
+func formatBackupSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// runRestoreCommand runs an external restore program reading its input from in; tests replace it
+var runRestoreCommand = func(ctx context.Context, name string, args, env []string, in io.Reader) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = in
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%s failed: %w: %s", name, err, msg)
+		}
+		return fmt.Errorf("%s failed: %w", name, err)
+	}
+	return nil
+}
+
+// findBackup looks up a completed backup by ID or label; "latest" or an empty name picks the newest
+func findBackup(dir, name string) (*backupRecord, error) {
+	if name != "" && name != "latest" {
+		if record, err := loadBackupRecord(dir, name); err == nil {
+			if record.Status != "completed" {
+				return nil, fmt.Errorf("backup %s did not complete: %s", name, record.Error)
+			}
+			return record, nil
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	backups, err := listBackups(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range backups {
+		if record.Status == "completed" && (name == "" || name == "latest" || record.Label == name) {
+			return record, nil
+		}
+	}
+	return nil, fmt.Errorf("no completed backup %q found in %s", name, dir)
+}
+
+// verifyBackupFile checks that a backup file still has the size and checksum recorded for it
+func verifyBackupFile(backupDir string, f backupFile) error {
+	file, err := os.Open(filepath.Join(backupDir, f.Path))
+	if err != nil {
+		return fmt.Errorf("backup file %s is missing: %w", f.Path, err)
+	}
+	defer file.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return fmt.Errorf("failed to read backup file %s: %w", f.Path, err)
+	}
+	if size != f.Bytes || hex.EncodeToString(hash.Sum(nil)) != f.SHA256 {
+		return fmt.Errorf("backup file %s does not match its recorded checksum; it was modified or is corrupt", f.Path)
+	}
+	return nil
+}
+
+// backupFileReader reads a backup file, decompressing it if it was gzipped
+type backupFileReader struct {
+	io.Reader
+	file *os.File
+}
+
+// openBackupFile opens a backup file for reading; report, if set, is called as the file is read
+func openBackupFile(backupDir string, f backupFile, report func(read, total int64)) (*backupFileReader, error) {
+	file, err := os.Open(filepath.Join(backupDir, f.Path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup file %s: %w", f.Path, err)
+	}
+	var in io.Reader = file
+	if report != nil {
+		in = newProgressReader(file, f.Bytes, report)
+	}
+	r := &backupFileReader{Reader: in, file: file}
+	if strings.HasSuffix(f.Path, ".gz") {
+		gz, err := gzip.NewReader(in)
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to decompress backup file %s: %w", f.Path, err)
+		}
+		r.Reader = gz
+	}
+	return r, nil
+}
+
+// Close closes the underlying file
+func (r *backupFileReader) Close() error {
+	return r.file.Close()
+}
+
+// readBackupCSV reads a CSV export back into its header and rows, turning \N into NULL
+func readBackupCSV(r io.Reader) ([]string, [][]interface{}, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	var rows [][]interface{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return header, rows, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		row := make([]interface{}, len(record))
+		for i, value := range record {
+			if value != backupNullMarker {
+				row[i] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+}
@@ -0,0 +1,307 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// BuildQueryTool handles building and executing SELECT queries from structured parameters
+type BuildQueryTool struct {
+	BaseToolType
+}
+
+// NewBuildQueryTool creates a new build query tool type
+func NewBuildQueryTool() *BuildQueryTool {
+	return &BuildQueryTool{
+		BaseToolType: BaseToolType{
+			name:        "build_query",
+			description: "Build and execute a SELECT query from structured parameters instead of free-form SQL. You describe the table, the columns and aggregates to return, typed filter conditions, joins, grouping, ordering and limits; the tool generates SQL with correctly quoted identifiers for the target database and binds every filter value as a query parameter. This eliminates SQL injection and dialect mistakes (quoting, placeholders) from generated queries. Set execute to false to only see the generated SQL and parameters.",
+		},
+	}
+}
+
+// CreateTool creates a build query tool
+func (t *BuildQueryTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Build and execute a dialect-correct SELECT query from structured parameters"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Table to select from (optionally schema-qualified)"),
+			tools.Required(),
+		),
+		tools.WithArray("columns",
+			tools.Description("Columns to select, optionally qualified as table.column (default: all columns)"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithArray("aggregates",
+			tools.Description("Aggregates to select: objects with function (count, sum, avg, min, max), column (* for count) and optional alias"),
+			tools.Items(map[string]interface{}{"type": "object"}),
+		),
+		tools.WithArray("filters",
+			tools.Description("Conditions combined with AND: objects with column, operator (=, !=, <, <=, >, >=, like, not like, in, not in, between, is null, is not null), value, or values for in/between"),
+			tools.Items(map[string]interface{}{"type": "object"}),
+		),
+		tools.WithArray("joins",
+			tools.Description("Joins: objects with type (inner, left, right, full), table, left_column and right_column"),
+			tools.Items(map[string]interface{}{"type": "object"}),
+		),
+		tools.WithArray("group_by",
+			tools.Description("Columns to group by"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithArray("order_by",
+			tools.Description("Ordering: objects with column and direction (asc or desc)"),
+			tools.Items(map[string]interface{}{"type": "object"}),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of rows to return (default: 100)"),
+		),
+		tools.WithNumber("offset",
+			tools.Description("Number of rows to skip (default: 0)"),
+		),
+		tools.WithBoolean("execute",
+			tools.Description("Whether to execute the query; when false only the generated SQL is returned (default: true)"),
+		),
+	)
+}
+
+// HandleRequest handles build query tool requests
+func (t *BuildQueryTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	execute := input.optionalBool("execute", true)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	query, err := parseStructuredQuery(request.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get database type to determine the SQL dialect
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for query builder: %s", dbType)
+	}
+	if err := query.check(dialect); err != nil {
+		return nil, err
+	}
+
+	sql, params := query.build(dbType)
+	logger.Info("Built query for database %s: %s", targetDbID, sql)
+
+	// Format the response
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Built Query for Database %s\n\n", targetDbID))
+	response.WriteString(fmt.Sprintf("```sql\n%s\n```\n\n", sql))
+	if len(params) > 0 {
+		response.WriteString(fmt.Sprintf("Parameters: %v\n\n", params))
+	}
+
+	if execute {
+		result, err := useCase.ExecuteQuery(ctx, targetDbID, sql, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute built query: %w", err)
+		}
+		response.WriteString(formatQueryResult(result))
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// queryAggregate is an aggregate expression in a structured query
+type queryAggregate struct {
+	Function string
+	Column   string
+	Alias    string
+}
+
+// queryJoin is an equi-join in a structured query
+type queryJoin struct {
+	Type        string
+	Table       string
+	LeftColumn  string
+	RightColumn string
+}
+
+// queryOrder is an ORDER BY term in a structured query
+type queryOrder struct {
+	Column    string
+	Direction string
+}
+
+// structuredQuery describes a SELECT query without any raw SQL fragments
+type structuredQuery struct {
+	Table      string
+	Columns    []string
+	Aggregates []queryAggregate
+	Joins      []queryJoin
+	Filters    []queryFilter
+	GroupBy    []string
+	OrderBy    []queryOrder
+	Limit      int
+	Offset     int
+}
+
+var aggregateFunctions = map[string]bool{"count": true, "sum": true, "avg": true, "min": true, "max": true}
+
+var joinTypes = map[string]string{"inner": "INNER JOIN", "left": "LEFT JOIN", "right": "RIGHT JOIN", "full": "FULL JOIN"}
+
+// parseStructuredQuery validates tool parameters and converts them into a structured query
+func parseStructuredQuery(parameters map[string]interface{}) (*structuredQuery, error) {
+	input := newToolParams(parameters)
+	query := &structuredQuery{
+		Table:   input.requiredString("table"),
+		Columns: input.stringList("columns"),
+		GroupBy: input.stringList("group_by"),
+		Limit:   input.intAtLeast("limit", 100, 0),
+		Offset:  input.intAtLeast("offset", 0, 0),
+	}
+	aggregates := input.objectList("aggregates")
+	joins := input.objectList("joins")
+	orders := input.objectList("order_by")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if query.Limit == 0 {
+		query.Limit = 100
+	}
+
+	var err error
+	if query.Filters, err = parseQueryFilters(input.raw("filters")); err != nil {
+		return nil, err
+	}
+
+	for i, m := range aggregates {
+		function, _ := m["function"].(string)
+		function = strings.ToLower(function)
+		if !aggregateFunctions[function] {
+			return nil, fmt.Errorf("aggregate %d has unsupported function: %s", i+1, function)
+		}
+		column, _ := m["column"].(string)
+		if column == "" {
+			if function != "count" {
+				return nil, fmt.Errorf("aggregate %d must have a column", i+1)
+			}
+			column = "*"
+		}
+		alias, _ := m["alias"].(string)
+		query.Aggregates = append(query.Aggregates, queryAggregate{Function: function, Column: column, Alias: alias})
+	}
+
+	for i, m := range joins {
+		join := queryJoin{Type: "inner"}
+		if joinType, ok := m["type"].(string); ok && joinType != "" {
+			join.Type = strings.ToLower(joinType)
+		}
+		if _, ok := joinTypes[join.Type]; !ok {
+			return nil, fmt.Errorf("join %d has unsupported type: %s", i+1, join.Type)
+		}
+		join.Table, _ = m["table"].(string)
+		join.LeftColumn, _ = m["left_column"].(string)
+		join.RightColumn, _ = m["right_column"].(string)
+		if join.Table == "" || join.LeftColumn == "" || join.RightColumn == "" {
+			return nil, fmt.Errorf("join %d must have table, left_column and right_column", i+1)
+		}
+		query.Joins = append(query.Joins, join)
+	}
+
+	for i, m := range orders {
+		order := queryOrder{Direction: "ASC"}
+		order.Column, _ = m["column"].(string)
+		if order.Column == "" {
+			return nil, fmt.Errorf("order_by %d must have a column", i+1)
+		}
+		if direction, ok := m["direction"].(string); ok && direction != "" {
+			order.Direction = strings.ToUpper(direction)
+		}
+		if order.Direction != "ASC" && order.Direction != "DESC" {
+			return nil, fmt.Errorf("order_by %d direction must be asc or desc", i+1)
+		}
+		query.OrderBy = append(query.OrderBy, order)
+	}
+
+	return query, nil
+}
+
+// check rejects the parts of a query the engine cannot run
+func (q *structuredQuery) check(dialect Dialect) error {
+	for _, join := range q.Joins {
+		if join.Type == "full" && !dialect.FullJoin() {
+			return fmt.Errorf("full joins are not supported on %s; combine a left and a right join with UNION instead", dialect.Name())
+		}
+	}
+	return nil
+}
+
+// build renders the query for the database type and returns it with its bound parameters
+func (q *structuredQuery) build(dbType string) (string, []interface{}) {
+	params := newSQLParams(dbType)
+
+	var selectList []string
+	for _, column := range q.Columns {
+		selectList = append(selectList, quoteIdentifier(dbType, column))
+	}
+	for _, agg := range q.Aggregates {
+		expr := fmt.Sprintf("%s(%s)", strings.ToUpper(agg.Function), quoteIdentifier(dbType, agg.Column))
+		if agg.Alias != "" {
+			expr += " AS " + quoteIdentifier(dbType, agg.Alias)
+		}
+		selectList = append(selectList, expr)
+	}
+	if len(selectList) == 0 {
+		selectList = []string{"*"}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("SELECT ")
+	sb.WriteString(strings.Join(selectList, ", "))
+	sb.WriteString("\nFROM ")
+	sb.WriteString(quoteIdentifier(dbType, q.Table))
+
+	for _, join := range q.Joins {
+		sb.WriteString(fmt.Sprintf("\n%s %s ON %s = %s", joinTypes[join.Type], quoteIdentifier(dbType, join.Table),
+			quoteIdentifier(dbType, join.LeftColumn), quoteIdentifier(dbType, join.RightColumn)))
+	}
+
+	if where := buildWhereClause(q.Filters, params); where != "" {
+		sb.WriteString("\nWHERE ")
+		sb.WriteString(where)
+	}
+
+	if len(q.GroupBy) > 0 {
+		groups := make([]string, len(q.GroupBy))
+		for i, column := range q.GroupBy {
+			groups[i] = quoteIdentifier(dbType, column)
+		}
+		sb.WriteString("\nGROUP BY ")
+		sb.WriteString(strings.Join(groups, ", "))
+	}
+
+	if len(q.OrderBy) > 0 {
+		orders := make([]string, len(q.OrderBy))
+		for i, order := range q.OrderBy {
+			orders[i] = quoteIdentifier(dbType, order.Column) + " " + order.Direction
+		}
+		sb.WriteString("\nORDER BY ")
+		sb.WriteString(strings.Join(orders, ", "))
+	}
+
+	sb.WriteString("\n" + dialectFor(dbType).PageClause(q.Limit, q.Offset))
+
+	return sb.String(), params.values
+}
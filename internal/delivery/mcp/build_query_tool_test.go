@@ -0,0 +1,140 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructuredQueryBuildPostgres(t *testing.T) {
+	query, err := parseStructuredQuery(map[string]interface{}{
+		"table":   "public.orders",
+		"columns": []interface{}{"orders.status", "users.email"},
+		"aggregates": []interface{}{
+			map[string]interface{}{"function": "count", "alias": "total"},
+		},
+		"joins": []interface{}{
+			map[string]interface{}{"type": "left", "table": "users", "left_column": "orders.user_id", "right_column": "users.id"},
+		},
+		"filters": []interface{}{
+			map[string]interface{}{"column": "orders.amount", "operator": ">=", "value": float64(10)},
+			map[string]interface{}{"column": "orders.status", "operator": "in", "values": []interface{}{"paid", "shipped"}},
+			map[string]interface{}{"column": "orders.deleted_at", "operator": "is null"},
+		},
+		"group_by": []interface{}{"orders.status", "users.email"},
+		"order_by": []interface{}{map[string]interface{}{"column": "total", "direction": "desc"}},
+		"limit":    float64(5),
+		"offset":   float64(10),
+	})
+	assert.NoError(t, err)
+
+	sql, params := query.build("postgres")
+	assert.Equal(t, `SELECT "orders"."status", "users"."email", COUNT(*) AS "total"
+FROM "public"."orders"
+LEFT JOIN "users" ON "orders"."user_id" = "users"."id"
+WHERE "orders"."amount" >= $1 AND "orders"."status" IN ($2, $3) AND "orders"."deleted_at" IS NULL
+GROUP BY "orders"."status", "users"."email"
+ORDER BY "total" DESC
+LIMIT 5 OFFSET 10`, sql)
+	assert.Equal(t, []interface{}{int64(10), "paid", "shipped"}, params)
+}
+
+func TestStructuredQueryBuildMySQL(t *testing.T) {
+	query, err := parseStructuredQuery(map[string]interface{}{
+		"table": "orders",
+		"filters": []interface{}{
+			map[string]interface{}{"column": "created_at", "operator": "between", "values": []interface{}{"2024-01-01", "2024-02-01"}},
+			map[string]interface{}{"column": "note", "operator": "NOT  LIKE", "value": "%test%"},
+		},
+	})
+	assert.NoError(t, err)
+
+	sql, params := query.build("mysql")
+	assert.Equal(t, "SELECT *\nFROM `orders`\nWHERE `created_at` BETWEEN ? AND ? AND `note` NOT LIKE ?\nLIMIT 100", sql)
+	assert.Equal(t, []interface{}{"2024-01-01", "2024-02-01", "%test%"}, params)
+}
+
+func TestStructuredQueryQuotesHostileIdentifiers(t *testing.T) {
+	query, err := parseStructuredQuery(map[string]interface{}{
+		"table":   "users",
+		"columns": []interface{}{"name\"; DROP TABLE users; --"},
+		"filters": []interface{}{
+			map[string]interface{}{"column": "id", "value": "1 OR 1=1"},
+		},
+	})
+	assert.NoError(t, err)
+
+	sql, params := query.build("postgres")
+	assert.Contains(t, sql, `SELECT "name""; DROP TABLE users; --"`)
+	assert.Contains(t, sql, `WHERE "id" = $1`)
+	assert.Equal(t, []interface{}{"1 OR 1=1"}, params)
+
+	sql, _ = query.build("mysql")
+	assert.Contains(t, sql, "SELECT `name\"; DROP TABLE users; --`")
+}
+
+func TestParseStructuredQueryRejectsInvalidInput(t *testing.T) {
+	cases := map[string]map[string]interface{}{
+		"missing table": {},
+		"bad operator": {"table": "t", "filters": []interface{}{
+			map[string]interface{}{"column": "a", "operator": "; DELETE", "value": 1.0},
+		}},
+		"in without values": {"table": "t", "filters": []interface{}{
+			map[string]interface{}{"column": "a", "operator": "in"},
+		}},
+		"bad aggregate": {"table": "t", "aggregates": []interface{}{
+			map[string]interface{}{"function": "pg_sleep", "column": "a"},
+		}},
+		"bad join type": {"table": "t", "joins": []interface{}{
+			map[string]interface{}{"type": "cross", "table": "u", "left_column": "t.a", "right_column": "u.a"},
+		}},
+		"bad direction": {"table": "t", "order_by": []interface{}{
+			map[string]interface{}{"column": "a", "direction": "sideways"},
+		}},
+		"negative limit": {"table": "t", "limit": float64(-1)},
+	}
+
+	for name, params := range cases {
+		_, err := parseStructuredQuery(params)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestBuildQueryToolWithoutExecute(t *testing.T) {
+	useCase := &mockUseCase{dbType: "mysql"}
+	tool := NewBuildQueryTool()
+
+	result, err := tool.HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{
+			"database": "mysql1",
+			"table":    "orders",
+			"execute":  false,
+		},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Empty(t, useCase.queries)
+}
+
+func TestStructuredQueryCheckFullJoin(t *testing.T) {
+	query, err := parseStructuredQuery(map[string]interface{}{
+		"table": "orders",
+		"joins": []interface{}{
+			map[string]interface{}{"type": "full", "table": "users", "left_column": "orders.user_id", "right_column": "users.id"},
+		},
+		"offset": float64(20),
+	})
+	assert.NoError(t, err)
+
+	postgres, _ := lookupDialect("postgres")
+	assert.NoError(t, query.check(postgres))
+	for _, dbType := range []string{"mysql", "tidb"} {
+		dialect, _ := lookupDialect(dbType)
+		assert.ErrorContains(t, query.check(dialect), "full joins are not supported", dbType)
+	}
+
+	sql, _ := query.build("firebird")
+	assert.Contains(t, sql, "\nROWS 21 TO 120")
+}
@@ -0,0 +1,375 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// rowChange is one row change read from the WAL or the binary log
+type rowChange struct {
+	// Position is the LSN on PostgreSQL and file:position on MySQL
+	Position string
+	// Time is the commit time of the transaction, zero when the source does not record it
+	Time      time.Time
+	Operation string
+	Schema    string
+	Table     string
+	// New holds the values after an insert or update, Old the key or old values of an update
+	// or delete as far as the replica identity logs them
+	New []changeValue
+	Old []changeValue
+	// Statement is the SQL that made the change, when the binary log records it
+	Statement string
+}
+
+// changeValue is one column value of a changed row
+type changeValue struct {
+	Column string
+	Value  string
+	Null   bool
+	// Quoted is set for values written as string literals
+	Quoted bool
+	// Unchanged marks a TOASTed value the WAL leaves out because the update did not touch it
+	Unchanged bool
+}
+
+// String renders a value as column=value
+func (v changeValue) String() string {
+	switch {
+	case v.Unchanged:
+		return v.Column + "=(unchanged)"
+	case v.Null:
+		return v.Column + "=NULL"
+	case v.Quoted:
+		return v.Column + "='" + strings.ReplaceAll(v.Value, "'", "''") + "'"
+	default:
+		return v.Column + "=" + v.Value
+	}
+}
+
+// errInvalidPgoutput is returned for pgoutput messages that cannot be decoded
+var errInvalidPgoutput = errors.New("invalid pgoutput message")
+
+// postgresEpoch is where PostgreSQL timestamps count microseconds from
+var postgresEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// unquotedTypeOIDs are the PostgreSQL types whose values are written without quotes: bool,
+// int8, int2, int4, oid, float4, float8 and numeric
+var unquotedTypeOIDs = map[uint32]bool{16: true, 20: true, 21: true, 23: true, 26: true, 700: true, 701: true, 1700: true}
+
+// pgoutputRelation is the description of a table pgoutput sends before its first change
+type pgoutputRelation struct {
+	Schema  string
+	Table   string
+	Columns []string
+	Types   []uint32
+}
+
+// pgoutputDecoder decodes the messages of the pgoutput plugin, protocol version 1, keeping the
+// relations and the commit time of the transaction being read
+type pgoutputDecoder struct {
+	relations map[uint32]*pgoutputRelation
+	// commitTime is the time of the transaction whose changes follow its Begin message
+	commitTime time.Time
+}
+
+// newPgoutputDecoder creates a decoder that knows no relations yet
+func newPgoutputDecoder() *pgoutputDecoder {
+	return &pgoutputDecoder{relations: make(map[uint32]*pgoutputRelation)}
+}
+
+// decode reads one message and returns the row changes it holds; transaction, relation and
+// type messages update the decoder and return none
+func (d *pgoutputDecoder) decode(lsn string, data []byte) ([]rowChange, error) {
+	r := &pgoutputReader{data: data}
+	kind := r.byte()
+	switch kind {
+	case 'B':
+		r.skip(8)
+		d.commitTime = postgresEpoch.Add(time.Duration(r.int64()) * time.Microsecond)
+		r.skip(4)
+	case 'R':
+		id := r.uint32()
+		relation := &pgoutputRelation{Schema: r.string(), Table: r.string()}
+		if relation.Schema == "" {
+			relation.Schema = "pg_catalog"
+		}
+		r.skip(1)
+		count := int(r.uint16())
+		for i := 0; i < count && r.err == nil; i++ {
+			r.skip(1)
+			relation.Columns = append(relation.Columns, r.string())
+			relation.Types = append(relation.Types, r.uint32())
+			r.skip(4)
+		}
+		if r.err == nil {
+			d.relations[id] = relation
+		}
+	case 'I', 'U', 'D':
+		relation, ok := d.relations[r.uint32()]
+		if r.err == nil && !ok {
+			return nil, fmt.Errorf("%w: change to a relation that was not described", errInvalidPgoutput)
+		}
+		change := rowChange{Position: lsn, Time: d.commitTime, Operation: map[byte]string{'I': "INSERT", 'U': "UPDATE", 'D': "DELETE"}[kind]}
+		if relation != nil {
+			change.Schema, change.Table = relation.Schema, relation.Table
+		}
+		for r.err == nil && r.pos < len(r.data) {
+			switch tuple := r.byte(); tuple {
+			case 'K', 'O':
+				change.Old = r.tuple(relation)
+			case 'N':
+				change.New = r.tuple(relation)
+			default:
+				r.err = errInvalidPgoutput
+			}
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+		return []rowChange{change}, nil
+	case 'T':
+		count := int(r.uint32())
+		r.skip(1)
+		var changes []rowChange
+		for i := 0; i < count && r.err == nil; i++ {
+			change := rowChange{Position: lsn, Time: d.commitTime, Operation: "TRUNCATE"}
+			if relation, ok := d.relations[r.uint32()]; ok {
+				change.Schema, change.Table = relation.Schema, relation.Table
+			}
+			changes = append(changes, change)
+		}
+		return changes, r.err
+	default:
+		// Commit, origin, type and logical messages carry no row changes
+	}
+	return nil, r.err
+}
+
+// pgoutputReader reads the big-endian fields of a pgoutput message, remembering the first
+// failure so a message is checked once at the end
+type pgoutputReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *pgoutputReader) take(n int) []byte {
+	if r.err != nil || n < 0 || len(r.data)-r.pos < n {
+		r.err = errInvalidPgoutput
+		// Fixed-size fields still get zero bytes to decode, so callers need not check each read
+		if n < 0 || n > 8 {
+			return nil
+		}
+		return make([]byte, n)
+	}
+	value := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return value
+}
+
+func (r *pgoutputReader) skip(n int)         { r.take(n) }
+func (r *pgoutputReader) byte() byte         { return r.take(1)[0] }
+func (r *pgoutputReader) uint16() uint16     { return binary.BigEndian.Uint16(r.take(2)) }
+func (r *pgoutputReader) uint32() uint32     { return binary.BigEndian.Uint32(r.take(4)) }
+func (r *pgoutputReader) int64() int64       { return int64(binary.BigEndian.Uint64(r.take(8))) }
+func (r *pgoutputReader) bytes(n int) []byte { return r.take(n) }
+
+// string reads a null-terminated string
+func (r *pgoutputReader) string() string {
+	if r.err != nil {
+		return ""
+	}
+	end := bytes.IndexByte(r.data[r.pos:], 0)
+	if end < 0 {
+		r.err = errInvalidPgoutput
+		return ""
+	}
+	value := string(r.data[r.pos : r.pos+end])
+	r.pos += end + 1
+	return value
+}
+
+// tuple reads the column values of a row
+func (r *pgoutputReader) tuple(relation *pgoutputRelation) []changeValue {
+	count := int(r.uint16())
+	values := make([]changeValue, 0, count)
+	for i := 0; i < count && r.err == nil; i++ {
+		value := changeValue{Column: fmt.Sprintf("column%d", i+1), Quoted: true}
+		if relation != nil && i < len(relation.Columns) {
+			value.Column = relation.Columns[i]
+			value.Quoted = !unquotedTypeOIDs[relation.Types[i]]
+		}
+		switch r.byte() {
+		case 'n':
+			value.Null = true
+		case 'u':
+			value.Unchanged = true
+		case 't':
+			value.Value = string(r.bytes(int(r.uint32())))
+		default:
+			r.err = errInvalidPgoutput
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// byteaValue returns the bytes of a bytea result, which arrives as bytes or as \x hex text
+func byteaValue(v interface{}) []byte {
+	switch val := v.(type) {
+	case []byte:
+		return val
+	case string:
+		if strings.HasPrefix(val, `\x`) {
+			if data, err := hex.DecodeString(val[2:]); err == nil {
+				return data
+			}
+		}
+		return []byte(val)
+	default:
+		return nil
+	}
+}
+
+// wal2jsonChange is one line of wal2json format version 2
+type wal2jsonChange struct {
+	Action    string `json:"action"`
+	Timestamp string `json:"timestamp"`
+	Schema    string `json:"schema"`
+	Table     string `json:"table"`
+	Columns   []struct {
+		Name  string          `json:"name"`
+		Value json.RawMessage `json:"value"`
+	} `json:"columns"`
+	Identity []struct {
+		Name  string          `json:"name"`
+		Value json.RawMessage `json:"value"`
+	} `json:"identity"`
+}
+
+// wal2jsonTimeLayout is the layout of wal2json timestamps, such as 2024-05-01 10:00:00.123456+00
+const wal2jsonTimeLayout = "2006-01-02 15:04:05.999999999-07"
+
+// wal2jsonDecoder decodes wal2json format version 2, carrying the commit time of a
+// transaction's Begin line to its changes
+type wal2jsonDecoder struct {
+	commitTime time.Time
+}
+
+// decode reads one line and returns the row change it holds, if any
+func (d *wal2jsonDecoder) decode(lsn, line string) ([]rowChange, error) {
+	var message wal2jsonChange
+	if err := json.Unmarshal([]byte(line), &message); err != nil {
+		return nil, fmt.Errorf("invalid wal2json output: %w", err)
+	}
+	if message.Timestamp != "" {
+		if t, err := time.Parse(wal2jsonTimeLayout, message.Timestamp); err == nil {
+			d.commitTime = t
+		}
+	}
+	operation, ok := map[string]string{"I": "INSERT", "U": "UPDATE", "D": "DELETE", "T": "TRUNCATE"}[message.Action]
+	if !ok {
+		return nil, nil
+	}
+	change := rowChange{Position: lsn, Time: d.commitTime, Operation: operation, Schema: message.Schema, Table: message.Table}
+	for _, column := range message.Columns {
+		change.New = append(change.New, jsonChangeValue(column.Name, column.Value))
+	}
+	for _, column := range message.Identity {
+		change.Old = append(change.Old, jsonChangeValue(column.Name, column.Value))
+	}
+	return []rowChange{change}, nil
+}
+
+// jsonChangeValue converts a JSON column value
+func jsonChangeValue(column string, raw json.RawMessage) changeValue {
+	var text string
+	switch {
+	case len(raw) == 0 || string(raw) == "null":
+		return changeValue{Column: column, Null: true}
+	case json.Unmarshal(raw, &text) == nil:
+		return changeValue{Column: column, Value: text, Quoted: true}
+	default:
+		return changeValue{Column: column, Value: string(raw)}
+	}
+}
+
+// binlogTableMap matches the table a Table_map event maps an id to: table_id: 108 (shop.orders)
+var binlogTableMap = regexp.MustCompile(`table_id: (\d+) \(([^.]*)\.(.*)\)`)
+
+// binlogTableID matches the table id of a rows event
+var binlogTableID = regexp.MustCompile(`table_id: (\d+)`)
+
+// binlogStatementTable matches the table a statement changes
+var binlogStatementTable = regexp.MustCompile("(?i)^(?:INSERT(?:\\s+IGNORE)?\\s+INTO|REPLACE\\s+INTO|UPDATE(?:\\s+IGNORE)?|DELETE\\s+FROM|TRUNCATE(?:\\s+TABLE)?)\\s+((?:`[^`]+`|\\w+)(?:\\.(?:`[^`]+`|\\w+))?)")
+
+// binlogDecoder reads the rows of SHOW BINLOG EVENTS. Row-based events name the table and
+// operation but not the values, which only a replication client can read; the statement is
+// known for statement-based logging or with binlog_rows_query_log_events.
+type binlogDecoder struct {
+	schema    string
+	tables    map[string][2]string
+	statement string
+}
+
+// newBinlogDecoder creates a decoder that knows no tables yet
+func newBinlogDecoder() *binlogDecoder {
+	return &binlogDecoder{tables: make(map[string][2]string)}
+}
+
+// decode reads one event: log name, position, event type, server id, end position and info
+func (d *binlogDecoder) decode(row []interface{}) []rowChange {
+	if len(row) < 6 {
+		return nil
+	}
+	position := fmt.Sprintf("%s:%d", valueString(row[0]), valueInt64(row[1]))
+	eventType, info := valueString(row[2]), strings.TrimSpace(valueString(row[5]))
+	switch {
+	case eventType == "Table_map":
+		if match := binlogTableMap.FindStringSubmatch(info); match != nil {
+			d.tables[match[1]] = [2]string{match[2], match[3]}
+		}
+	case eventType == "Rows_query":
+		d.statement = strings.TrimSpace(strings.TrimPrefix(info, "#"))
+	case eventType == "Xid":
+		d.statement = ""
+	case strings.HasPrefix(eventType, "Write_rows"), strings.HasPrefix(eventType, "Update_rows"), strings.HasPrefix(eventType, "Delete_rows"):
+		operation := map[byte]string{'W': "INSERT", 'U': "UPDATE", 'D': "DELETE"}[eventType[0]]
+		change := rowChange{Position: position, Operation: operation, Statement: d.statement}
+		if match := binlogTableID.FindStringSubmatch(info); match != nil {
+			table := d.tables[match[1]]
+			change.Schema, change.Table = table[0], table[1]
+		}
+		return []rowChange{change}
+	case eventType == "Query":
+		schema := ""
+		if strings.HasPrefix(info, "use ") {
+			if end := strings.Index(info, ";"); end > 0 {
+				schema = strings.Trim(info[4:end], "` ")
+				info = strings.TrimSpace(info[end+1:])
+			}
+		}
+		match := binlogStatementTable.FindStringSubmatch(info)
+		if match == nil {
+			return nil
+		}
+		table := strings.ReplaceAll(match[1], "`", "")
+		if dot := strings.Index(table, "."); dot >= 0 {
+			schema, table = table[:dot], table[dot+1:]
+		}
+		operation := strings.ToUpper(strings.Fields(info)[0])
+		if operation == "REPLACE" {
+			operation = "INSERT"
+		}
+		return []rowChange{{Position: position, Operation: operation, Schema: schema, Table: table, Statement: info}}
+	}
+	return nil
+}
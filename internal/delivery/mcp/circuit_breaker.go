@@ -0,0 +1,309 @@
+package mcp
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// defaultBreakerFailures is how many connection failures in a row open the breaker of a
+// database whose connection sets no circuit_breaker_failures
+const defaultBreakerFailures = 5
+
+// defaultBreakerCooldown is how long an open breaker fails calls fast before letting one
+// through to probe the database
+const defaultBreakerCooldown = 30 * time.Second
+
+// breakerClock returns the current time; tests replace it
+var breakerClock = time.Now
+
+// errDatabaseUnavailable is returned without touching a database whose breaker is open
+var errDatabaseUnavailable = errors.New("database temporarily unavailable")
+
+// circuitBreaker tracks the health of one database. It opens after repeated connection
+// failures, fails calls fast while open, and once the cooldown has passed lets
+// a single call through: its success closes the breaker and its failure opens it again.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	failures int
+	lastErr  error
+	open     bool
+	retryAt  time.Time
+	probing  bool
+}
+
+// breakerSet holds the circuit breaker of each database
+type breakerSet struct {
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+// databaseBreakers is the breaker set shared by all tool calls
+var databaseBreakers = newBreakerSet()
+
+// newBreakerSet creates a breaker set with every database healthy
+func newBreakerSet() *breakerSet {
+	return &breakerSet{breakers: make(map[string]*circuitBreaker)}
+}
+
+// breakerOf returns the breaker of a database, configured from its connection the first time
+// the database is used; nil means the connection disabled it. s.mu must be held.
+func (s *breakerSet) breakerOf(useCase UseCaseProvider, dbID string) *circuitBreaker {
+	breaker, ok := s.breakers[dbID]
+	if ok {
+		return breaker
+	}
+	threshold, cooldown := defaultBreakerFailures, defaultBreakerCooldown
+	if config, err := useCase.GetDatabaseConfig(dbID); err == nil && config != nil {
+		if config.CircuitBreakerFailures != 0 {
+			threshold = config.CircuitBreakerFailures
+		}
+		if config.CircuitBreakerCooldown > 0 {
+			cooldown = config.CircuitBreakerCooldown
+		}
+	}
+	if threshold > 0 {
+		breaker = &circuitBreaker{threshold: threshold, cooldown: cooldown}
+	}
+	s.breakers[dbID] = breaker
+	return breaker
+}
+
+// allow reports whether a call may run on a database, and whether it is the probe of an
+// open breaker whose outcome decides if the breaker closes
+func (s *breakerSet) allow(useCase UseCaseProvider, dbID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	breaker := s.breakerOf(useCase, dbID)
+	if err := breaker.failFast(dbID); err != nil || breaker == nil || !breaker.open {
+		return false, err
+	}
+	breaker.probing = true
+	logger.Info("Probing database %s after its circuit breaker cooldown", dbID)
+	return true, nil
+}
+
+// failFast returns the error calls on an open breaker fail with, or nil once a call may go
+// through; a nil breaker never fails
+func (b *circuitBreaker) failFast(dbID string) error {
+	if b == nil || !b.open {
+		return nil
+	}
+	now := breakerClock()
+	if b.probing {
+		return fmt.Errorf("%w: database %s failed %d times in a row (last error: %v); another call is checking whether it recovered",
+			errDatabaseUnavailable, dbID, b.failures, b.lastErr)
+	}
+	if now.Before(b.retryAt) {
+		return fmt.Errorf("%w: database %s failed %d times in a row (last error: %v); retrying in %s",
+			errDatabaseUnavailable, dbID, b.failures, b.lastErr, roundDuration(b.retryAt.Sub(now)))
+	}
+	return nil
+}
+
+// record counts the outcome of a call on a database. Connection failures count against it;
+// any other outcome, errors included, shows the database answered, except a call the caller
+// cancelled or whose own deadline passed, which says nothing either way.
+func (s *breakerSet) record(ctx context.Context, dbID string, probe bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	breaker := s.breakers[dbID]
+	if breaker == nil {
+		return
+	}
+	if probe {
+		breaker.probing = false
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled), ctx.Err() != nil && errors.Is(err, context.DeadlineExceeded):
+		// The caller gave up, or its query outran a timeout the caller chose; neither says
+		// the database is down, and a probe ending this way leaves the next call to probe
+	case unavailableError(err):
+		breaker.failures++
+		breaker.lastErr = err
+		if probe || (!breaker.open && breaker.failures >= breaker.threshold) {
+			breaker.open = true
+			breaker.retryAt = breakerClock().Add(breaker.cooldown)
+			logger.Warn("Circuit breaker of database %s opened after %d failures in a row, last: %v", dbID, breaker.failures, err)
+		}
+	default:
+		if breaker.open {
+			logger.Info("Circuit breaker of database %s closed, the database recovered", dbID)
+		}
+		breaker.failures, breaker.lastErr, breaker.open = 0, nil, false
+	}
+}
+
+// unavailableError reports whether an error means the connection to the database failed or
+// timed out, rather than the database rejecting the call. Dial timeouts match
+// context.DeadlineExceeded as well, so network errors are checked first; record leaves out
+// the deadlines of the caller itself.
+func unavailableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	// A deadline the caller did not set, such as the connect timeout of the pool
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// guard wraps a use case so calls on a database whose breaker is open fail fast
+func (s *breakerSet) guard(useCase UseCaseProvider) *guardedUseCase {
+	return &guardedUseCase{UseCaseProvider: useCase, breakers: s}
+}
+
+// guardedUseCase runs the database calls of a tool call through the breaker of their database
+type guardedUseCase struct {
+	UseCaseProvider
+	breakers *breakerSet
+}
+
+// call runs fn on a database unless its breaker is open, and records the outcome against
+// the context of the caller
+func (g *guardedUseCase) call(ctx context.Context, dbID string, fn func() error) error {
+	probe, err := g.breakers.allow(g.UseCaseProvider, dbID)
+	if err != nil {
+		return err
+	}
+	err = fn()
+	g.breakers.record(ctx, dbID, probe, err)
+	return err
+}
+
+// callBounded runs fn like call, under the query timeout of the connection the use case
+// applies as well, so a query outrunning it is told apart from a connection timing out
+func (g *guardedUseCase) callBounded(ctx context.Context, dbID string, fn func(ctx context.Context) error) error {
+	ctx, cancel := g.queryDeadline(ctx, dbID)
+	defer cancel()
+	return g.call(ctx, dbID, func() error { return fn(ctx) })
+}
+
+// queryDeadline bounds ctx by the query timeout of the connection, as the use case does
+func (g *guardedUseCase) queryDeadline(ctx context.Context, dbID string) (context.Context, context.CancelFunc) {
+	config, err := g.UseCaseProvider.GetDatabaseConfig(dbID)
+	if err != nil || config == nil || config.QueryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= config.QueryTimeout {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, config.QueryTimeout)
+}
+
+// GetDatabaseType reads the type of a database unless its breaker is open
+func (g *guardedUseCase) GetDatabaseType(dbID string) (string, error) {
+	var dbType string
+	err := g.call(context.Background(), dbID, func() (err error) {
+		dbType, err = g.UseCaseProvider.GetDatabaseType(dbID)
+		return err
+	})
+	return dbType, err
+}
+
+// ExecuteQuery runs a query unless the breaker of its database is open
+func (g *guardedUseCase) ExecuteQuery(ctx context.Context, dbID, query string, params []interface{}) (*domain.QueryResult, error) {
+	var result *domain.QueryResult
+	err := g.callBounded(ctx, dbID, func(ctx context.Context) (err error) {
+		result, err = g.UseCaseProvider.ExecuteQuery(ctx, dbID, query, params)
+		return err
+	})
+	return result, err
+}
+
+// StreamQuery streams the rows of a query unless the breaker of its database is open
+func (g *guardedUseCase) StreamQuery(ctx context.Context, dbID, query string, params []interface{}, handler domain.RowHandler) (int64, error) {
+	var count int64
+	err := g.callBounded(ctx, dbID, func(ctx context.Context) (err error) {
+		count, err = g.UseCaseProvider.StreamQuery(ctx, dbID, query, params, handler)
+		return err
+	})
+	return count, err
+}
+
+// ExecuteStatement runs a statement unless the breaker of its database is open
+func (g *guardedUseCase) ExecuteStatement(ctx context.Context, dbID, statement string, params []interface{}) (string, error) {
+	var result string
+	err := g.callBounded(ctx, dbID, func(ctx context.Context) (err error) {
+		result, err = g.UseCaseProvider.ExecuteStatement(ctx, dbID, statement, params)
+		return err
+	})
+	return result, err
+}
+
+// ExecuteTransaction runs a transaction action unless the breaker of its database is open
+func (g *guardedUseCase) ExecuteTransaction(ctx context.Context, dbID, action string, txID string, statement string, params []interface{}, readOnly bool) (string, map[string]interface{}, error) {
+	var result string
+	var metadata map[string]interface{}
+	err := g.call(ctx, dbID, func() (err error) {
+		result, metadata, err = g.UseCaseProvider.ExecuteTransaction(ctx, dbID, action, txID, statement, params, readOnly)
+		return err
+	})
+	return result, metadata, err
+}
+
+// ExecuteBatch runs a batch of statements unless the breaker of its database is open
+func (g *guardedUseCase) ExecuteBatch(ctx context.Context, dbID string, statements []string, params [][]interface{}) error {
+	return g.callBounded(ctx, dbID, func(ctx context.Context) error {
+		return g.UseCaseProvider.ExecuteBatch(ctx, dbID, statements, params)
+	})
+}
+
+// GetDatabaseInfo reads the database information unless the breaker of the database is open
+func (g *guardedUseCase) GetDatabaseInfo(ctx context.Context, dbID string) (map[string]interface{}, error) {
+	var info map[string]interface{}
+	err := g.callBounded(ctx, dbID, func(ctx context.Context) (err error) {
+		info, err = g.UseCaseProvider.GetDatabaseInfo(ctx, dbID)
+		return err
+	})
+	return info, err
+}
+
+// GetDocumentStore opens the document store unless the breaker of its database is open
+func (g *guardedUseCase) GetDocumentStore(dbID string) (domain.DocumentStore, error) {
+	var store domain.DocumentStore
+	err := g.call(context.Background(), dbID, func() (err error) {
+		store, err = g.UseCaseProvider.GetDocumentStore(dbID)
+		return err
+	})
+	return store, err
+}
+
+// GetKeyValueStore opens the key-value store unless the breaker of its database is open
+func (g *guardedUseCase) GetKeyValueStore(dbID string) (domain.KeyValueStore, error) {
+	var store domain.KeyValueStore
+	err := g.call(context.Background(), dbID, func() (err error) {
+		store, err = g.UseCaseProvider.GetKeyValueStore(dbID)
+		return err
+	})
+	return store, err
+}
+
+// GetSearchStore opens the search store unless the breaker of its database is open
+func (g *guardedUseCase) GetSearchStore(dbID string) (domain.SearchStore, error) {
+	var store domain.SearchStore
+	err := g.call(context.Background(), dbID, func() (err error) {
+		store, err = g.UseCaseProvider.GetSearchStore(dbID)
+		return err
+	})
+	return store, err
+}
+
+// Listen subscribes to a notification channel unless the breaker of its database is open
+func (g *guardedUseCase) Listen(ctx context.Context, dbID, channel string, handle func(payload string)) error {
+	return g.call(ctx, dbID, func() error {
+		return g.UseCaseProvider.Listen(ctx, dbID, channel, handle)
+	})
+}
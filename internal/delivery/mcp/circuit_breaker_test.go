@@ -0,0 +1,190 @@
+package mcp
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+// useBreakerClock points the breaker clock at a time the test controls
+func useBreakerClock(t *testing.T) *time.Time {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breakerClock = func() time.Time { return now }
+	t.Cleanup(func() { breakerClock = time.Now })
+	return &now
+}
+
+func TestCircuitBreakerOpensAfterRepeatedConnectionFailures(t *testing.T) {
+	now := useBreakerClock(t)
+	refused := fmt.Errorf("query execution failed: %w", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")})
+	mock := &mockUseCase{
+		dbType:   "postgres",
+		queryErr: refused,
+		config:   domain.DatabaseConnectionConfig{CircuitBreakerFailures: 3, CircuitBreakerCooldown: time.Minute},
+	}
+	useCase := newBreakerSet().guard(mock)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := useCase.ExecuteQuery(ctx, "db1", "SELECT 1", nil)
+		assert.ErrorIs(t, err, refused)
+	}
+
+	// The breaker is open: calls fail fast without reaching the database
+	_, err := useCase.ExecuteQuery(ctx, "db1", "SELECT 1", nil)
+	assert.ErrorIs(t, err, errDatabaseUnavailable)
+	assert.Contains(t, err.Error(), "database db1 failed 3 times in a row")
+	assert.Contains(t, err.Error(), "retrying in 1m0s")
+	_, err = useCase.GetDatabaseType("db1")
+	assert.ErrorIs(t, err, errDatabaseUnavailable)
+	assert.Len(t, mock.queries, 3)
+
+	// Other databases are not affected
+	_, err = useCase.ExecuteQuery(ctx, "db2", "SELECT 1", nil)
+	assert.ErrorIs(t, err, refused)
+
+	// After the cooldown one call probes the database; its failure opens the breaker again
+	*now = now.Add(time.Minute)
+	_, err = useCase.ExecuteQuery(ctx, "db1", "SELECT 1", nil)
+	assert.ErrorIs(t, err, refused)
+	_, err = useCase.ExecuteQuery(ctx, "db1", "SELECT 1", nil)
+	assert.ErrorIs(t, err, errDatabaseUnavailable)
+
+	// A successful probe closes it
+	*now = now.Add(time.Minute)
+	mock.queryErr = nil
+	_, err = useCase.ExecuteQuery(ctx, "db1", "SELECT 1", nil)
+	assert.NoError(t, err)
+	_, err = useCase.ExecuteQuery(ctx, "db1", "SELECT 1", nil)
+	assert.NoError(t, err)
+}
+
+func TestCircuitBreakerIgnoresQueryErrors(t *testing.T) {
+	useBreakerClock(t)
+	mock := &mockUseCase{
+		queryErr: errors.New(`syntax error at or near "SELEC"`),
+		config:   domain.DatabaseConnectionConfig{CircuitBreakerFailures: 1},
+	}
+	useCase := newBreakerSet().guard(mock)
+
+	// The database answered, so its errors do not open the breaker
+	for i := 0; i < 3; i++ {
+		_, err := useCase.ExecuteQuery(context.Background(), "db1", "SELEC 1", nil)
+		assert.NotErrorIs(t, err, errDatabaseUnavailable)
+	}
+
+	// Neither do callers giving up
+	mock.queryErr = context.Canceled
+	_, err := useCase.ExecuteQuery(context.Background(), "db1", "SELECT 1", nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	mock.queryErr = nil
+	_, err = useCase.ExecuteQuery(context.Background(), "db1", "SELECT 1", nil)
+	assert.NoError(t, err)
+
+	// Nor do queries that outrun a timeout of the caller
+	mock.queryErr = fmt.Errorf("query execution failed: %w", context.DeadlineExceeded)
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		_, err = useCase.ExecuteQuery(expired, "db1", "SELECT 1", nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+
+	// Broken connections do
+	mock.queryErr = fmt.Errorf("query execution failed: %w", driver.ErrBadConn)
+	_, err = useCase.ExecuteQuery(context.Background(), "db1", "SELECT 1", nil)
+	assert.ErrorIs(t, err, driver.ErrBadConn)
+	_, err = useCase.ExecuteQuery(context.Background(), "db1", "SELECT 1", nil)
+	assert.ErrorIs(t, err, errDatabaseUnavailable)
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	mock := &mockUseCase{
+		queryErr: context.DeadlineExceeded,
+		config:   domain.DatabaseConnectionConfig{CircuitBreakerFailures: -1},
+	}
+	useCase := newBreakerSet().guard(mock)
+
+	for i := 0; i < 10; i++ {
+		_, err := useCase.ExecuteQuery(context.Background(), "db1", "SELECT 1", nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+	assert.Len(t, mock.queries, 10)
+}
+
+func TestCircuitBreakerCountsDialTimeouts(t *testing.T) {
+	useBreakerClock(t)
+	// A dial outrunning the timeout of the dialer, rather than of the caller, matches
+	// context.DeadlineExceeded as well
+	_, dialErr := (&net.Dialer{Timeout: time.Nanosecond}).Dial("tcp", "127.0.0.1:1")
+	assert.ErrorIs(t, dialErr, context.DeadlineExceeded)
+	mock := &mockUseCase{
+		queryErr: fmt.Errorf("failed to connect: %w", dialErr),
+		config:   domain.DatabaseConnectionConfig{CircuitBreakerFailures: 2},
+	}
+	useCase := newBreakerSet().guard(mock)
+
+	for i := 0; i < 2; i++ {
+		_, err := useCase.ExecuteQuery(context.Background(), "db1", "SELECT 1", nil)
+		assert.ErrorIs(t, err, dialErr)
+	}
+	_, err := useCase.ExecuteQuery(context.Background(), "db1", "SELECT 1", nil)
+	assert.ErrorIs(t, err, errDatabaseUnavailable)
+
+	// So do deadlines the caller did not set, such as the connect timeout of the pool
+	mock.queryErr = context.DeadlineExceeded
+	useCase = newBreakerSet().guard(mock)
+	for i := 0; i < 2; i++ {
+		_, err = useCase.ExecuteQuery(context.Background(), "db1", "SELECT 1", nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+	_, err = useCase.ExecuteQuery(context.Background(), "db1", "SELECT 1", nil)
+	assert.ErrorIs(t, err, errDatabaseUnavailable)
+}
+
+func TestCircuitBreakerIgnoresQueryTimeout(t *testing.T) {
+	useBreakerClock(t)
+	mock := &mockUseCase{
+		queryErr: fmt.Errorf("query execution failed: %w", context.DeadlineExceeded),
+		config:   domain.DatabaseConnectionConfig{CircuitBreakerFailures: 1, QueryTimeout: time.Nanosecond},
+	}
+	useCase := newBreakerSet().guard(mock)
+
+	// The query timeout of the connection is a deadline of the caller, not a failure
+	for i := 0; i < 3; i++ {
+		_, err := useCase.ExecuteQuery(context.Background(), "db1", "SELECT 1", nil)
+		assert.NotErrorIs(t, err, errDatabaseUnavailable)
+	}
+}
+
+func TestCircuitBreakerProbesWithDatabaseType(t *testing.T) {
+	now := useBreakerClock(t)
+	mock := &mockUseCase{
+		dbType:   "postgres",
+		queryErr: driver.ErrBadConn,
+		config:   domain.DatabaseConnectionConfig{CircuitBreakerFailures: 1, CircuitBreakerCooldown: time.Minute},
+	}
+	useCase := newBreakerSet().guard(mock)
+
+	_, err := useCase.ExecuteQuery(context.Background(), "db1", "SELECT 1", nil)
+	assert.ErrorIs(t, err, driver.ErrBadConn)
+	_, err = useCase.GetDatabaseType("db1")
+	assert.ErrorIs(t, err, errDatabaseUnavailable)
+
+	// After the cooldown reading the type is the probe, and its success closes the breaker
+	*now = now.Add(time.Minute)
+	dbType, err := useCase.GetDatabaseType("db1")
+	assert.NoError(t, err)
+	assert.Equal(t, "postgres", dbType)
+	mock.queryErr = nil
+	_, err = useCase.ExecuteQuery(context.Background(), "db1", "SELECT 1", nil)
+	assert.NoError(t, err)
+}
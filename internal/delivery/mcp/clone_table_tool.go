@@ -0,0 +1,552 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// cloneColumn is a column of the source table as far as copying its data is concerned
+type cloneColumn struct {
+	Name      string
+	Generated bool
+	Identity  bool
+	// Sequence is set when the column defaults to nextval() of a sequence the clone would share
+	Sequence string
+}
+
+// cloneForeignKey is a foreign key of the source table with its column lists already quoted
+type cloneForeignKey struct {
+	Name       string
+	Columns    string
+	RefTable   string
+	RefColumns string
+	OnUpdate   string
+	OnDelete   string
+	SelfRef    bool
+}
+
+// cloneOptions selects what clone_table copies besides the columns
+type cloneOptions struct {
+	Indexes       bool
+	Constraints   bool
+	Data          bool
+	SamplePercent float64
+	Where         string
+}
+
+// postgresReferentialActions maps pg_constraint action codes to their SQL
+var postgresReferentialActions = map[string]string{
+	"a": "NO ACTION",
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+// CloneTableTool handles copying a table's structure and optionally its data
+type CloneTableTool struct {
+	BaseToolType
+}
+
+// NewCloneTableTool creates a new clone tool type
+func NewCloneTableTool() *CloneTableTool {
+	return &CloneTableTool{
+		BaseToolType: BaseToolType{
+			name:        "clone_table",
+			description: "Create a copy of a table for safe experimentation: the structure with CREATE TABLE ... (LIKE ... INCLUDING ALL) on PostgreSQL or CREATE TABLE ... LIKE on MySQL, optionally with its indexes, constraints (including foreign keys, which LIKE never copies) and all data, a random percentage of it, or the rows matching a filter. Everything runs in one batch; use dry_run to review the statements.",
+		},
+	}
+}
+
+// CreateTool creates a clone tool
+func (t *CloneTableTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Copy a table's structure and optionally a subset of its data"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("source",
+			tools.Description("Table to clone"),
+			tools.Required(),
+		),
+		tools.WithString("target",
+			tools.Description("Name of the new table (defaults to the schema of the source)"),
+			tools.Required(),
+		),
+		tools.WithBoolean("include_indexes",
+			tools.Description("Copy the indexes (default: true)"),
+		),
+		tools.WithBoolean("include_constraints",
+			tools.Description("Copy check constraints and foreign keys (default: true)"),
+		),
+		tools.WithBoolean("include_data",
+			tools.Description("Copy the rows (default: false; implied by sample_percent and where)"),
+		),
+		tools.WithNumber("sample_percent",
+			tools.Description("Copy a random sample of about this percentage of the rows (0-100)"),
+		),
+		tools.WithString("where",
+			tools.Description("Copy only rows matching this SQL condition"),
+		),
+		tools.WithBoolean("dry_run",
+			tools.Description("Only show the statements without executing them (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles clone tool requests
+func (t *CloneTableTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	source := input.requiredString("source")
+	target := input.requiredString("target")
+	options := cloneOptions{
+		Indexes:       input.optionalBool("include_indexes", true),
+		Constraints:   input.optionalBool("include_constraints", true),
+		Data:          input.optionalBool("include_data", false),
+		SamplePercent: input.optionalFloat("sample_percent", 0),
+		Where:         strings.TrimSpace(input.optionalString("where", "")),
+	}
+	dryRun := input.optionalBool("dry_run", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if input.has("sample_percent") && (options.SamplePercent <= 0 || options.SamplePercent > 100) {
+		return nil, fmt.Errorf("sample_percent parameter must be greater than 0 and at most 100")
+	}
+	if options.SamplePercent > 0 || options.Where != "" {
+		options.Data = true
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for clone_table: %s", dbType)
+	}
+	cloner, err := dialect.TableCloner()
+	if err != nil {
+		return nil, unsupportedAdminError("clone_table", dbType, err)
+	}
+	if options.Where != "" {
+		tokens, err := tokenizeSQL(options.Where, dialect.Name())
+		if err != nil {
+			return nil, fmt.Errorf("invalid where condition: %w", err)
+		}
+		for _, token := range tokens {
+			if token.is(";") {
+				return nil, fmt.Errorf("where must be a single condition")
+			}
+		}
+	}
+
+	sourceSchema, sourceName := splitQualifiedName(source)
+	targetSchema, targetName := splitQualifiedName(target)
+	if targetSchema == "" {
+		targetSchema = sourceSchema
+	}
+	if sourceSchema == "" {
+		sourceSchema, targetSchema = cloner.defaultSchema(), cloner.defaultSchema()
+	}
+	sourceIdent := quoteIdentifier(dialect.Name(), qualifiedName(sourceSchema, sourceName))
+	targetIdent := quoteIdentifier(dialect.Name(), qualifiedName(targetSchema, targetName))
+
+	exists, err := cloneTableExists(ctx, useCase, targetDbID, cloner, targetSchema, targetName)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("table %s already exists", qualifiedName(targetSchema, targetName))
+	}
+	columns, err := loadCloneColumns(ctx, useCase, targetDbID, cloner, sourceSchema, sourceName)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s does not exist", source)
+	}
+	var foreignKeys []cloneForeignKey
+	if options.Constraints {
+		if foreignKeys, err = loadCloneForeignKeys(ctx, useCase, targetDbID, cloner, sourceSchema, sourceName); err != nil {
+			return nil, err
+		}
+	}
+
+	// Data goes in before the foreign keys so they are validated once rather than row by row
+	statements, notes, err := cloner.createStatements(ctx, useCase, targetDbID, sourceSchema, sourceName, sourceIdent, targetIdent, columns, options)
+	if err != nil {
+		return nil, err
+	}
+	if options.Data {
+		statements = append(statements, cloner.copyStatements(sourceIdent, targetIdent, columns, options)...)
+	}
+	statements = append(statements, cloneForeignKeyStatements(dialect.Name(), targetIdent, targetName, sourceName, foreignKeys)...)
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Clone %s to %s for Database %s\n\n", source, qualifiedName(targetSchema, targetName), targetDbID))
+	response.WriteString("```sql\n")
+	for _, statement := range statements {
+		response.WriteString(statement + ";\n")
+	}
+	response.WriteString("```\n")
+	if len(notes) > 0 {
+		response.WriteString("\n")
+		for _, note := range notes {
+			response.WriteString(fmt.Sprintf("Note: %s\n", note))
+		}
+	}
+
+	if dryRun {
+		response.WriteString("\nDry run: nothing was executed.\n")
+		return createTextResponse(response.String()), nil
+	}
+	err = useCase.ExecuteBatch(ctx, targetDbID, statements, nil)
+	audit(request, auditRecord{
+		Tool:      "clone_table",
+		Database:  targetDbID,
+		Action:    "clone",
+		Target:    fmt.Sprintf("%s to %s", source, qualifiedName(targetSchema, targetName)),
+		Statement: strings.Join(statements, ";\n"),
+	}, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone %s: %w", source, err)
+	}
+	logger.Info("Cloned table %s to %s on database %s", source, target, targetDbID)
+
+	response.WriteString(fmt.Sprintf("\nCreated %s.\n", qualifiedName(targetSchema, targetName)))
+	if options.Data {
+		result, err := useCase.ExecuteQuery(ctx, targetDbID, "SELECT COUNT(*) FROM "+targetIdent, nil)
+		if err == nil && len(result.Rows) > 0 && len(result.Rows[0]) > 0 {
+			response.WriteString(fmt.Sprintf("Copied %d row(s).\n", valueInt64(result.Rows[0][0])))
+		}
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// tableCloner is the engine-specific part of cloning a table
+type tableCloner interface {
+	// defaultSchema is the schema of a source named without one, empty for the current database
+	defaultSchema() string
+	// existsQuery reports whether a table exists
+	existsQuery(schema, name string) (string, []interface{})
+	// columnsQuery lists the columns of a table as name, generated, identity and shared sequence
+	columnsQuery(schema, name string) (string, []interface{})
+	// foreignKeysQuery lists the foreign keys of a table in the fields of cloneForeignKey, with
+	// the column lists quoted
+	foreignKeysQuery(schema, name string) (string, []interface{})
+	// referentialAction returns the SQL of an update or delete rule read by foreignKeysQuery
+	referentialAction(rule string) string
+	// createStatements builds the statements creating the empty clone, with notes on what it
+	// shares with the source or leaves out
+	createStatements(ctx context.Context, useCase UseCaseProvider, dbID, sourceSchema, sourceName, sourceIdent, targetIdent string, columns []cloneColumn, options cloneOptions) ([]string, []string, error)
+	// copyStatements builds the statements copying the selected rows into the clone
+	copyStatements(sourceIdent, targetIdent string, columns []cloneColumn, options cloneOptions) []string
+}
+
+// postgresTableCloner clones with CREATE TABLE ... (LIKE ... INCLUDING ALL)
+type postgresTableCloner struct{}
+
+func (postgresTableCloner) defaultSchema() string { return "public" }
+
+func (postgresTableCloner) existsQuery(schema, name string) (string, []interface{}) {
+	return "SELECT to_regclass($1) IS NOT NULL", []interface{}{quoteIdentifier("postgres", qualifiedName(schema, name))}
+}
+
+// columnsQuery reads attgenerated through to_jsonb, since it only exists from PostgreSQL 12 on
+func (postgresTableCloner) columnsQuery(schema, name string) (string, []interface{}) {
+	return `
+SELECT a.attname,
+       COALESCE(to_jsonb(a) ->> 'attgenerated', '') <> '',
+       a.attidentity <> '',
+       CASE WHEN pg_get_expr(d.adbin, d.adrelid) LIKE 'nextval(%'
+            THEN substring(pg_get_expr(d.adbin, d.adrelid) from '''(.*)''') ELSE '' END
+FROM pg_attribute a
+LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE a.attrelid = to_regclass($1) AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY a.attnum`, []interface{}{quoteIdentifier("postgres", qualifiedName(schema, name))}
+}
+
+func (postgresTableCloner) foreignKeysQuery(schema, name string) (string, []interface{}) {
+	return `
+SELECT c.conname,
+       (SELECT string_agg(quote_ident(a.attname), ', ' ORDER BY k.ord)
+        FROM unnest(c.conkey) WITH ORDINALITY k(attnum, ord)
+        JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum),
+       c.confrelid::regclass::text,
+       (SELECT string_agg(quote_ident(a.attname), ', ' ORDER BY k.ord)
+        FROM unnest(c.confkey) WITH ORDINALITY k(attnum, ord)
+        JOIN pg_attribute a ON a.attrelid = c.confrelid AND a.attnum = k.attnum),
+       c.confupdtype::text,
+       c.confdeltype::text,
+       c.confrelid = c.conrelid
+FROM pg_constraint c
+WHERE c.conrelid = to_regclass($1) AND c.contype = 'f'
+ORDER BY c.conname`, []interface{}{quoteIdentifier("postgres", qualifiedName(schema, name))}
+}
+
+func (postgresTableCloner) referentialAction(rule string) string {
+	return postgresReferentialActions[rule]
+}
+
+func (postgresTableCloner) createStatements(_ context.Context, _ UseCaseProvider, _, _, sourceName, sourceIdent, targetIdent string, columns []cloneColumn, options cloneOptions) ([]string, []string, error) {
+	create := fmt.Sprintf("CREATE TABLE %s (LIKE %s INCLUDING ALL", targetIdent, sourceIdent)
+	if !options.Indexes {
+		create += " EXCLUDING INDEXES"
+	}
+	if !options.Constraints {
+		create += " EXCLUDING CONSTRAINTS"
+	}
+	var notes []string
+	for _, column := range columns {
+		if column.Sequence != "" {
+			notes = append(notes, fmt.Sprintf("Column %s defaults to nextval of %s, which the clone shares with %s; inserts into either advance it.", column.Name, column.Sequence, sourceName))
+		}
+	}
+	return []string{create + ")"}, notes, nil
+}
+
+// copyStatements keeps identity values as they are in the source; since copied values do not
+// advance the clone's own identity sequences, those are moved past them
+func (postgresTableCloner) copyStatements(sourceIdent, targetIdent string, columns []cloneColumn, options cloneOptions) []string {
+	from := sourceIdent
+	if options.SamplePercent > 0 && options.SamplePercent < 100 {
+		from += fmt.Sprintf(" TABLESAMPLE BERNOULLI (%g)", options.SamplePercent)
+	}
+	statements := []string{cloneInsertStatement("postgres", targetIdent, " OVERRIDING SYSTEM VALUE", from, columns, cloneConditions(nil, options))}
+	for _, column := range columns {
+		if column.Identity {
+			col := quoteIdentifier("postgres", column.Name)
+			statements = append(statements, fmt.Sprintf("SELECT setval(pg_get_serial_sequence(%s, %s), COALESCE(MAX(%s), 0) + 1, false) FROM %s",
+				quoteLiteral("postgres", targetIdent), quoteLiteral("postgres", column.Name), col, targetIdent))
+		}
+	}
+	return statements
+}
+
+// mysqlTableCloner clones with CREATE TABLE ... LIKE
+type mysqlTableCloner struct{}
+
+func (mysqlTableCloner) defaultSchema() string { return "" }
+
+func (mysqlTableCloner) existsQuery(schema, name string) (string, []interface{}) {
+	params := newSQLParams("mysql")
+	schemaExpr := mysqlCloneSchema(params, schema)
+	return fmt.Sprintf("SELECT COUNT(*) > 0 FROM information_schema.TABLES WHERE TABLE_SCHEMA = %s AND TABLE_NAME = %s", schemaExpr, params.add(name)), params.values
+}
+
+func (mysqlTableCloner) columnsQuery(schema, name string) (string, []interface{}) {
+	params := newSQLParams("mysql")
+	schemaExpr := mysqlCloneSchema(params, schema)
+	return fmt.Sprintf(`
+SELECT COLUMN_NAME, EXTRA LIKE '%%GENERATED%%', 0, ''
+FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA = %s AND TABLE_NAME = %s
+ORDER BY ORDINAL_POSITION`, schemaExpr, params.add(name)), params.values
+}
+
+func (mysqlTableCloner) foreignKeysQuery(schema, name string) (string, []interface{}) {
+	params := newSQLParams("mysql")
+	schemaExpr := mysqlCloneSchema(params, schema)
+	return fmt.Sprintf(`
+SELECT k.CONSTRAINT_NAME,
+       GROUP_CONCAT(CONCAT('`+"`"+`', REPLACE(k.COLUMN_NAME, '`+"`"+`', '`+"``"+`'), '`+"`"+`') ORDER BY k.ORDINAL_POSITION SEPARATOR ', '),
+       CONCAT('`+"`"+`', REPLACE(k.REFERENCED_TABLE_SCHEMA, '`+"`"+`', '`+"``"+`'), '`+"`.`"+`', REPLACE(k.REFERENCED_TABLE_NAME, '`+"`"+`', '`+"``"+`'), '`+"`"+`'),
+       GROUP_CONCAT(CONCAT('`+"`"+`', REPLACE(k.REFERENCED_COLUMN_NAME, '`+"`"+`', '`+"``"+`'), '`+"`"+`') ORDER BY k.ORDINAL_POSITION SEPARATOR ', '),
+       MAX(r.UPDATE_RULE),
+       MAX(r.DELETE_RULE),
+       MAX(k.REFERENCED_TABLE_SCHEMA = k.TABLE_SCHEMA AND k.REFERENCED_TABLE_NAME = k.TABLE_NAME)
+FROM information_schema.KEY_COLUMN_USAGE k
+JOIN information_schema.REFERENTIAL_CONSTRAINTS r
+  ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME
+WHERE k.TABLE_SCHEMA = %s AND k.TABLE_NAME = %s AND k.REFERENCED_TABLE_NAME IS NOT NULL
+GROUP BY k.CONSTRAINT_NAME, k.REFERENCED_TABLE_SCHEMA, k.REFERENCED_TABLE_NAME
+ORDER BY k.CONSTRAINT_NAME`, schemaExpr, params.add(name)), params.values
+}
+
+func (mysqlTableCloner) referentialAction(rule string) string { return rule }
+
+// createStatements drops the secondary indexes from the clone when they are not wanted, since
+// CREATE TABLE ... LIKE always copies them
+func (mysqlTableCloner) createStatements(ctx context.Context, useCase UseCaseProvider, dbID, sourceSchema, sourceName, sourceIdent, targetIdent string, _ []cloneColumn, options cloneOptions) ([]string, []string, error) {
+	statements := []string{fmt.Sprintf("CREATE TABLE %s LIKE %s", targetIdent, sourceIdent)}
+	var notes []string
+	if !options.Indexes {
+		secondaryIndexes, err := loadMySQLSecondaryIndexes(ctx, useCase, dbID, sourceSchema, sourceName)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(secondaryIndexes) > 0 {
+			drops := make([]string, len(secondaryIndexes))
+			for i, index := range secondaryIndexes {
+				drops[i] = "DROP INDEX " + quoteIdentifier("mysql", index)
+			}
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s %s", targetIdent, strings.Join(drops, ", ")))
+		}
+		notes = append(notes, "MySQL always copies the primary key; other indexes are dropped after creating the clone.")
+	}
+	if !options.Constraints {
+		notes = append(notes, "CREATE TABLE ... LIKE copies CHECK constraints on MySQL; only foreign keys are left out.")
+	}
+	return statements, notes, nil
+}
+
+// copyStatements samples by filtering on RAND(), since MySQL has no TABLESAMPLE
+func (mysqlTableCloner) copyStatements(sourceIdent, targetIdent string, columns []cloneColumn, options cloneOptions) []string {
+	var conditions []string
+	if options.SamplePercent > 0 && options.SamplePercent < 100 {
+		conditions = append(conditions, fmt.Sprintf("RAND() < %g", options.SamplePercent/100))
+	}
+	return []string{cloneInsertStatement("mysql", targetIdent, "", sourceIdent, columns, cloneConditions(conditions, options))}
+}
+
+// mysqlCloneSchema returns the expression for schema in a MySQL query, the current database
+// when it is empty
+func mysqlCloneSchema(params *sqlParams, schema string) string {
+	if schema == "" {
+		return "DATABASE()"
+	}
+	return params.add(schema)
+}
+
+// cloneConditions adds the where option to the sampling conditions of the copy
+func cloneConditions(conditions []string, options cloneOptions) []string {
+	if options.Where != "" {
+		conditions = append(conditions, "("+options.Where+")")
+	}
+	return conditions
+}
+
+// cloneInsertStatement builds the INSERT ... SELECT copying the columns that are not generated
+// from the rows of from matching the conditions
+func cloneInsertStatement(dbType, targetIdent, modifier, from string, columns []cloneColumn, conditions []string) string {
+	var names []string
+	for _, column := range columns {
+		if !column.Generated {
+			names = append(names, quoteIdentifier(dbType, column.Name))
+		}
+	}
+	list := strings.Join(names, ", ")
+	insert := fmt.Sprintf("INSERT INTO %s (%s)%s SELECT %s FROM %s", targetIdent, list, modifier, list, from)
+	if len(conditions) > 0 {
+		insert += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return insert
+}
+
+// cloneForeignKeyStatements builds the statements adding the foreign keys of the source to the
+// clone, renamed after it
+func cloneForeignKeyStatements(dbType, targetIdent, targetName, sourceName string, foreignKeys []cloneForeignKey) []string {
+	var statements []string
+	for _, fk := range foreignKeys {
+		refTable := fk.RefTable
+		if fk.SelfRef {
+			// A self-reference of the source becomes a self-reference of the clone
+			refTable = targetIdent
+		}
+		name := targetName + "_" + fk.Name
+		if strings.HasPrefix(fk.Name, sourceName+"_") {
+			name = targetName + strings.TrimPrefix(fk.Name, sourceName)
+		}
+		statement := fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+			targetIdent, quoteIdentifier(dbType, name), fk.Columns, refTable, fk.RefColumns)
+		if fk.OnUpdate != "" && fk.OnUpdate != "NO ACTION" {
+			statement += " ON UPDATE " + fk.OnUpdate
+		}
+		if fk.OnDelete != "" && fk.OnDelete != "NO ACTION" {
+			statement += " ON DELETE " + fk.OnDelete
+		}
+		statements = append(statements, statement)
+	}
+	return statements
+}
+
+// cloneTableExists reports whether the target table already exists
+func cloneTableExists(ctx context.Context, useCase UseCaseProvider, dbID string, cloner tableCloner, schema, name string) (bool, error) {
+	query, params := cloner.existsQuery(schema, name)
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, params)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for table %s: %w", name, err)
+	}
+	return len(result.Rows) > 0 && len(result.Rows[0]) > 0 && valueBool(result.Rows[0][0]), nil
+}
+
+// loadCloneColumns reads the columns of the source table; none means it does not exist
+func loadCloneColumns(ctx context.Context, useCase UseCaseProvider, dbID string, cloner tableCloner, schema, name string) ([]cloneColumn, error) {
+	query, params := cloner.columnsQuery(schema, name)
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", name, err)
+	}
+	var columns []cloneColumn
+	for _, row := range result.Rows {
+		if len(row) < 4 {
+			continue
+		}
+		columns = append(columns, cloneColumn{
+			Name:      valueString(row[0]),
+			Generated: valueBool(row[1]),
+			Identity:  valueBool(row[2]),
+			Sequence:  valueString(row[3]),
+		})
+	}
+	return columns, nil
+}
+
+// loadCloneForeignKeys reads the foreign keys of the source table
+func loadCloneForeignKeys(ctx context.Context, useCase UseCaseProvider, dbID string, cloner tableCloner, schema, name string) ([]cloneForeignKey, error) {
+	query, params := cloner.foreignKeysQuery(schema, name)
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read foreign keys of %s: %w", name, err)
+	}
+	var foreignKeys []cloneForeignKey
+	for _, row := range result.Rows {
+		if len(row) < 7 {
+			continue
+		}
+		foreignKeys = append(foreignKeys, cloneForeignKey{
+			Name:       valueString(row[0]),
+			Columns:    valueString(row[1]),
+			RefTable:   valueString(row[2]),
+			RefColumns: valueString(row[3]),
+			OnUpdate:   cloner.referentialAction(valueString(row[4])),
+			OnDelete:   cloner.referentialAction(valueString(row[5])),
+			SelfRef:    valueBool(row[6]),
+		})
+	}
+	return foreignKeys, nil
+}
+
+// loadMySQLSecondaryIndexes lists the indexes besides the primary key, which CREATE TABLE ... LIKE always copies
+func loadMySQLSecondaryIndexes(ctx context.Context, useCase UseCaseProvider, dbID, schema, name string) ([]string, error) {
+	params := newSQLParams("mysql")
+	schemaExpr := "DATABASE()"
+	if schema != "" {
+		schemaExpr = params.add(schema)
+	}
+	result, err := useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT DISTINCT INDEX_NAME
+FROM information_schema.STATISTICS
+WHERE TABLE_SCHEMA = %s AND TABLE_NAME = %s AND INDEX_NAME <> 'PRIMARY'
+ORDER BY INDEX_NAME`, schemaExpr, params.add(name)), params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexes of %s: %w", name, err)
+	}
+	var indexes []string
+	for _, row := range result.Rows {
+		if len(row) > 0 {
+			indexes = append(indexes, valueString(row[0]))
+		}
+	}
+	return indexes, nil
+}
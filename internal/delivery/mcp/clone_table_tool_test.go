@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestCloneTableToolPostgres(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"IS NOT NULL":          {Rows: [][]interface{}{{false}}},
+			"LEFT JOIN pg_attrdef": {Rows: [][]interface{}{{"id", false, true, ""}, {"parent_id", false, false, ""}, {"total", true, false, ""}}},
+			"c.contype = 'f'": {Rows: [][]interface{}{
+				{"orders_parent_id_fkey", `parent_id`, "orders", `id`, "a", "c", true},
+				{"orders_customer_id_fkey", `customer_id`, "customers", `id`, "a", "a", false},
+			}},
+			"SELECT COUNT(*) FROM": {Rows: [][]interface{}{{int64(12)}}},
+		},
+	}
+
+	result, err := NewCloneTableTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "source": "orders", "target": "orders_copy", "sample_percent": float64(10), "where": "status = 'paid'"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{
+		`CREATE TABLE "public"."orders_copy" (LIKE "public"."orders" INCLUDING ALL)`,
+		`INSERT INTO "public"."orders_copy" ("id", "parent_id") OVERRIDING SYSTEM VALUE SELECT "id", "parent_id" FROM "public"."orders" TABLESAMPLE BERNOULLI (10) WHERE (status = 'paid')`,
+		`SELECT setval(pg_get_serial_sequence('"public"."orders_copy"', 'id'), COALESCE(MAX("id"), 0) + 1, false) FROM "public"."orders_copy"`,
+		`ALTER TABLE "public"."orders_copy" ADD CONSTRAINT "orders_copy_parent_id_fkey" FOREIGN KEY (parent_id) REFERENCES "public"."orders_copy" (id) ON DELETE CASCADE`,
+		`ALTER TABLE "public"."orders_copy" ADD CONSTRAINT "orders_copy_customer_id_fkey" FOREIGN KEY (customer_id) REFERENCES customers (id)`,
+	}}, useCase.batches)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "Copied 12 row(s).")
+
+	useCase.batches = nil
+	useCase.results["LEFT JOIN pg_attrdef"] = &domain.QueryResult{Rows: [][]interface{}{{"id", false, false, "orders_id_seq"}}}
+	result, err = NewCloneTableTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "source": "orders", "target": "orders_copy", "include_indexes": false, "include_constraints": false, "dry_run": true},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	assert.Empty(t, useCase.batches)
+	text = result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, `CREATE TABLE "public"."orders_copy" (LIKE "public"."orders" INCLUDING ALL EXCLUDING INDEXES EXCLUDING CONSTRAINTS);`)
+	assert.Contains(t, text, "Note: Column id defaults to nextval of orders_id_seq, which the clone shares with orders")
+	assert.NotContains(t, text, "INSERT INTO")
+
+	useCase.results["IS NOT NULL"] = &domain.QueryResult{Rows: [][]interface{}{{true}}}
+	_, err = NewCloneTableTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "source": "orders", "target": "orders_copy"},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "already exists")
+
+	_, err = NewCloneTableTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "source": "orders", "target": "orders_copy", "where": "true; DROP TABLE orders"},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "single condition")
+}
+
+func TestCloneTableToolMySQL(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"information_schema.COLUMNS":    {Rows: [][]interface{}{{"id", int64(0), int64(0), ""}, {"total_cents", int64(1), int64(0), ""}}},
+			"information_schema.STATISTICS": {Rows: [][]interface{}{{"idx_status"}, {"idx_customer"}}},
+			"REFERENTIAL_CONSTRAINTS":       {Rows: [][]interface{}{{"fk_customer", "`customer_id`", "`shop`.`customers`", "`id`", "RESTRICT", "NO ACTION", int64(0)}}},
+		},
+	}
+
+	_, err := NewCloneTableTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "source": "orders", "target": "orders_sample", "include_indexes": false, "sample_percent": float64(5)},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"CREATE TABLE `orders_sample` LIKE `orders`",
+		"ALTER TABLE `orders_sample` DROP INDEX `idx_status`, DROP INDEX `idx_customer`",
+		"INSERT INTO `orders_sample` (`id`) SELECT `id` FROM `orders` WHERE RAND() < 0.05",
+		"ALTER TABLE `orders_sample` ADD CONSTRAINT `orders_sample_fk_customer` FOREIGN KEY (`customer_id`) REFERENCES `shop`.`customers` (`id`) ON UPDATE RESTRICT",
+	}, useCase.batches[0])
+
+	_, err = NewCloneTableTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "source": "missing", "target": "copy"},
+	}, "mysql1", &mockUseCase{dbType: "mysql"})
+	assert.ErrorContains(t, err, "table missing does not exist")
+}
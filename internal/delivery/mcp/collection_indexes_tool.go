@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// CollectionIndexesTool handles listing the indexes of a collection
+type CollectionIndexesTool struct {
+	BaseToolType
+}
+
+// NewCollectionIndexesTool creates a new collection indexes tool type
+func NewCollectionIndexesTool() *CollectionIndexesTool {
+	return &CollectionIndexesTool{
+		BaseToolType: BaseToolType{
+			name:        "collection_indexes",
+			description: "List the indexes of a MongoDB collection: the indexed fields in order with their direction or kind (1, -1, text, 2dsphere, hashed), and whether the index is unique, sparse, partial, hidden or expires documents after a TTL.",
+		},
+	}
+}
+
+// CreateTool creates a collection indexes tool
+func (t *CollectionIndexesTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("List the indexes of a MongoDB collection"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("collection",
+			tools.Description("Collection to list the indexes of"),
+			tools.Required(),
+		),
+	)
+}
+
+// HandleRequest handles collection indexes tool requests
+func (t *CollectionIndexesTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	collection := input.requiredString("collection")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	store, err := useCase.GetDocumentStore(targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := store.ListIndexes(ctx, collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes of %s: %w", collection, err)
+	}
+	logger.Info("Listed %d indexes of collection %s of database %s", len(indexes), collection, targetDbID)
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Indexes of Collection %s in Database %s\n\n", collection, targetDbID))
+	response.WriteString("| Name | Keys | Unique | Sparse | Hidden | TTL (seconds) | Partial Filter |\n")
+	response.WriteString("|------|------|--------|--------|--------|---------------|----------------|\n")
+	for _, index := range indexes {
+		index, _ = relaxedValue(index).(map[string]interface{})
+		var keys []string
+		fields, _ := index["key"].([]interface{})
+		for _, field := range fields {
+			spec, _ := field.(map[string]interface{})
+			for name, kind := range spec {
+				keys = append(keys, fmt.Sprintf("%s: %v", name, kind))
+			}
+		}
+		unique, _ := index["unique"].(bool)
+		sparse, _ := index["sparse"].(bool)
+		hidden, _ := index["hidden"].(bool)
+		ttl := ""
+		if seconds, ok := index["expireAfterSeconds"]; ok {
+			ttl = fmt.Sprint(seconds)
+		}
+		response.WriteString(fmt.Sprintf("| %v | %s | %v | %v | %v | %s | %s |\n",
+			index["name"], strings.Join(keys, ", "), unique, sparse, hidden, ttl, compactJSON(index["partialFilterExpression"])))
+	}
+	response.WriteString(fmt.Sprintf("\n%d indexes\n", len(indexes)))
+	return createTextResponse(response.String()), nil
+}
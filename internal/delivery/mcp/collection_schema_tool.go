@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// CollectionSchemaTool handles inferring the schema of a collection from sampled documents
+type CollectionSchemaTool struct {
+	BaseToolType
+}
+
+// NewCollectionSchemaTool creates a new collection schema tool type
+func NewCollectionSchemaTool() *CollectionSchemaTool {
+	return &CollectionSchemaTool{
+		BaseToolType: BaseToolType{
+			name:        "collection_schema",
+			description: "Infer the schema of a MongoDB collection from a random sample of its documents: every field path, including nested documents and array elements, with the share of documents containing it, the BSON types found there (objectId, string, int, long, double, date, ...) and an example value. Fields found with several types or in few documents point at inconsistent data.",
+		},
+	}
+}
+
+// CreateTool creates a collection schema tool
+func (t *CollectionSchemaTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Infer the schema of a MongoDB collection by sampling documents"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("collection",
+			tools.Description("Collection to sample"),
+			tools.Required(),
+		),
+		tools.WithNumber("sample_size",
+			tools.Description("Number of documents to sample (default: 100, max: 10000)"),
+		),
+		tools.WithNumber("max_depth",
+			tools.Description("How many levels of nesting to report (default: 5, max: 20)"),
+		),
+	)
+}
+
+// HandleRequest handles collection schema tool requests
+func (t *CollectionSchemaTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	collection := input.requiredString("collection")
+	sampleSize := input.intBetween("sample_size", 100, 1, 10000)
+	maxDepth := input.intBetween("max_depth", 5, 1, 20)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	store, err := useCase.GetDocumentStore(targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	documents, err := store.SampleDocuments(ctx, collection, sampleSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample %s: %w", collection, err)
+	}
+	structure := newJSONStructure(maxDepth)
+	for _, document := range documents {
+		structure.addValue(bsonTyped(document))
+	}
+	logger.Info("Inferred schema of collection %s of database %s: %d documents, %d paths", collection, targetDbID, structure.sampled, len(structure.paths))
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Schema of Collection %s in Database %s\n\n", collection, targetDbID))
+	response.WriteString(fmt.Sprintf("Sampled: %d random documents\n\n", structure.sampled))
+	if structure.sampled == 0 {
+		response.WriteString("The collection has no documents.\n")
+		return createTextResponse(response.String()), nil
+	}
+	writeJSONPaths(&response, structure)
+	if structure.truncated {
+		response.WriteString(fmt.Sprintf("\nOnly the first %d distinct paths are reported; documents keyed by ids or dates produce a path per key and are better explored with a lower max_depth.\n", maxJSONPaths))
+	}
+	return createTextResponse(response.String()), nil
+}
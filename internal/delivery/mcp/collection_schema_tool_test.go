@@ -0,0 +1,33 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionSchemaTool(t *testing.T) {
+	store := &fakeDocumentStore{documents: canonical(t, `[
+		{"_id": {"$oid": "65f1c0ffee0000000000abcd"}, "status": "paid", "total": {"$numberDouble": "12.5"},
+		 "created": {"$date": {"$numberLong": "1704067200000"}}, "items": [{"sku": "a", "qty": {"$numberInt": "2"}}]},
+		{"_id": {"$oid": "65f1c0ffee0000000000abce"}, "status": "open", "total": {"$numberInt": "10"}, "items": []},
+		{"_id": {"$oid": "65f1c0ffee0000000000abcf"}, "status": null, "customer": {"id": {"$numberLong": "7"}}}
+	]`)}
+
+	text := documentToolText(t, NewCollectionSchemaTool(), store, map[string]interface{}{"database": "shop", "collection": "orders"})
+	assert.Contains(t, text, "# Schema of Collection orders in Database shop")
+	assert.Contains(t, text, "Sampled: 3 random documents")
+	assert.Contains(t, text, "| _id | 3 | 100.0% | objectId 3 | 65f1c0ffee0000000000abcd |")
+	assert.Contains(t, text, "| created | 1 | 33.3% | date 1 | 2024-01-01T00:00:00Z |")
+	assert.Contains(t, text, "| customer.id | 1 | 33.3% | long 1 | 7 |")
+	assert.Contains(t, text, "| items[].qty | 1 | 33.3% | int 1 | 2 |")
+	assert.Contains(t, text, "| status | 3 | 100.0% | string 2, null 1 | paid |")
+	assert.Contains(t, text, "| total | 2 | 66.7% | double 1, int 1 | 12.5 |")
+
+	// The sample size is passed to the store
+	text = documentToolText(t, NewCollectionSchemaTool(), store, map[string]interface{}{"database": "shop", "collection": "orders", "sample_size": 1})
+	assert.Contains(t, text, "Sampled: 1 random documents")
+
+	empty := documentToolText(t, NewCollectionSchemaTool(), &fakeDocumentStore{}, map[string]interface{}{"database": "shop", "collection": "orders"})
+	assert.Contains(t, empty, "The collection has no documents.")
+}
@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// CollectionStatsTool handles reading the statistics of a collection
+type CollectionStatsTool struct {
+	BaseToolType
+}
+
+// NewCollectionStatsTool creates a new collection stats tool type
+func NewCollectionStatsTool() *CollectionStatsTool {
+	return &CollectionStatsTool{
+		BaseToolType: BaseToolType{
+			name:        "collection_stats",
+			description: "Read the collStats statistics of a MongoDB collection: document count, data size before compression, average document size, storage size and reusable free space, the size of every index, and whether the collection is capped or sharded. Set raw to get the full collStats output, including the storage engine details.",
+		},
+	}
+}
+
+// CreateTool creates a collection stats tool
+func (t *CollectionStatsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Read the collStats statistics of a MongoDB collection"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("collection",
+			tools.Description("Collection to read statistics of"),
+			tools.Required(),
+		),
+		tools.WithBoolean("raw",
+			tools.Description("Append the full collStats output (default: false)"),
+		),
+	)
+}
+
+// collectionSizes are the collStats fields holding byte counts, in the order they are shown
+var collectionSizes = []struct{ field, label string }{
+	{"size", "Data size (uncompressed)"},
+	{"avgObjSize", "Average document size"},
+	{"storageSize", "Storage size"},
+	{"freeStorageSize", "Reusable free storage"},
+	{"totalIndexSize", "Total index size"},
+	{"totalSize", "Total size"},
+}
+
+// HandleRequest handles collection stats tool requests
+func (t *CollectionStatsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	collection := input.requiredString("collection")
+	raw := input.optionalBool("raw", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	store, err := useCase.GetDocumentStore(targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := store.CollectionStats(ctx, collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read statistics of %s: %w", collection, err)
+	}
+	logger.Info("Read statistics of collection %s of database %s", collection, targetDbID)
+	relaxed, _ := relaxedValue(stats).(map[string]interface{})
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Statistics of Collection %s in Database %s\n\n", collection, targetDbID))
+	response.WriteString("| Metric | Value |\n")
+	response.WriteString("|--------|-------|\n")
+	response.WriteString(fmt.Sprintf("| Documents | %v |\n", relaxed["count"]))
+	for _, size := range collectionSizes {
+		if bytes, ok := relaxed[size.field].(json.Number); ok {
+			if n, err := bytes.Float64(); err == nil {
+				response.WriteString(fmt.Sprintf("| %s | %s |\n", size.label, formatBackupSize(int64(n))))
+			}
+		}
+	}
+	response.WriteString(fmt.Sprintf("| Indexes | %v |\n", relaxed["nindexes"]))
+	capped, _ := relaxed["capped"].(bool)
+	response.WriteString(fmt.Sprintf("| Capped | %v |\n", capped))
+	sharded, _ := relaxed["sharded"].(bool)
+	response.WriteString(fmt.Sprintf("| Sharded | %v |\n", sharded))
+
+	if indexSizes, ok := relaxed["indexSizes"].(map[string]interface{}); ok && len(indexSizes) > 0 {
+		names := make([]string, 0, len(indexSizes))
+		for name := range indexSizes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		response.WriteString("\n## Index Sizes\n\n")
+		response.WriteString("| Index | Size |\n")
+		response.WriteString("|-------|------|\n")
+		for _, name := range names {
+			size := fmt.Sprint(indexSizes[name])
+			if bytes, ok := indexSizes[name].(json.Number); ok {
+				if n, err := bytes.Float64(); err == nil {
+					size = formatBackupSize(int64(n))
+				}
+			}
+			response.WriteString(fmt.Sprintf("| %s | %s |\n", name, size))
+		}
+	}
+
+	if raw {
+		response.WriteString("\n## collStats Output\n\n")
+		response.WriteString(formatDocuments([]map[string]interface{}{stats}))
+	}
+	return createTextResponse(response.String()), nil
+}
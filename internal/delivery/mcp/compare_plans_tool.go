@@ -0,0 +1,212 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// ComparePlansTool handles comparing EXPLAIN plans
+type ComparePlansTool struct {
+	BaseToolType
+}
+
+// NewComparePlansTool creates a new compare plans tool type
+func NewComparePlansTool() *ComparePlansTool {
+	return &ComparePlansTool{
+		BaseToolType: BaseToolType{
+			name:        "compare_plans",
+			description: "Compare the execution plans of a query to catch plan regressions. The tool runs EXPLAIN (without executing the query) against two databases, such as staging and production, or for two versions of a query, or against a baseline plan captured earlier, for example before adding an index. It diffs the node types, estimated costs, index usage and full table scans and summarizes what changed. Every report includes the JSON plan so it can be passed back as baseline_plan later.",
+		},
+	}
+}
+
+// CreateTool creates a compare plans tool
+func (t *ComparePlansTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Compare EXPLAIN plans across databases, query versions or a baseline"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("query",
+			tools.Description("SQL query to explain"),
+			tools.Required(),
+		),
+		tools.WithString("compare_database",
+			tools.Description("Database ID to compare against (optional, defaults to database)"),
+		),
+		tools.WithString("compare_query",
+			tools.Description("Alternative query to compare against (optional, defaults to query)"),
+		),
+		tools.WithString("baseline_plan",
+			tools.Description("JSON plan from an earlier run to compare the current plan against (optional)"),
+		),
+	)
+}
+
+// HandleRequest handles compare plans tool requests
+func (t *ComparePlansTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	query := input.requiredString("query")
+	compareDbID := input.optionalString("compare_database", "")
+	compareQuery := input.optionalString("compare_query", "")
+	baselinePlan := input.optionalString("baseline_plan", "")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	var before, after *queryPlan
+	var beforeLabel, afterLabel string
+	var err error
+	if baselinePlan != "" {
+		logger.Info("Comparing plan of query on database %s against a baseline", targetDbID)
+
+		dbType, err := useCase.GetDatabaseType(targetDbID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get database type: %w", err)
+		}
+		if before, err = parseQueryPlan(dbType, baselinePlan); err != nil {
+			return nil, fmt.Errorf("invalid baseline_plan: %w", err)
+		}
+		beforeLabel = "baseline"
+		if compareQuery != "" {
+			query = compareQuery
+		}
+		if after, err = loadQueryPlan(ctx, useCase, targetDbID, query); err != nil {
+			return nil, err
+		}
+		afterLabel = "current on " + targetDbID
+	} else {
+		if compareDbID == "" && compareQuery == "" {
+			return nil, fmt.Errorf("one of compare_database, compare_query or baseline_plan must be provided")
+		}
+		if compareDbID == "" {
+			compareDbID = targetDbID
+		}
+		if compareQuery == "" {
+			compareQuery = query
+		}
+
+		logger.Info("Comparing plans of query on database %s and %s", targetDbID, compareDbID)
+
+		if before, err = loadQueryPlan(ctx, useCase, targetDbID, query); err != nil {
+			return nil, fmt.Errorf("failed to explain on %s: %w", targetDbID, err)
+		}
+		if after, err = loadQueryPlan(ctx, useCase, compareDbID, compareQuery); err != nil {
+			return nil, fmt.Errorf("failed to explain on %s: %w", compareDbID, err)
+		}
+		beforeLabel, afterLabel = "A: "+targetDbID, "B: "+compareDbID
+		if compareQuery != query {
+			beforeLabel += " (query)"
+			afterLabel += " (compare_query)"
+		}
+	}
+
+	// Format the response
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Plan Comparison for Database %s\n\n", targetDbID))
+	response.WriteString("## Changes\n\n")
+	response.WriteString(formatPlanDiff(before, after))
+	response.WriteString(fmt.Sprintf("\n## Plan %s\n\n```\n%s```\n", beforeLabel, before.render()))
+	response.WriteString(fmt.Sprintf("\n## Plan %s\n\n```\n%s```\n", afterLabel, after.render()))
+	response.WriteString(fmt.Sprintf("\n## JSON Plan %s\n\n```json\n%s\n```\n", afterLabel, after.Raw))
+
+	return createTextResponse(response.String()), nil
+}
+
+// formatPlanDiff summarizes the differences between two plans as a markdown list
+func formatPlanDiff(before, after *queryPlan) string {
+	var sb strings.Builder
+
+	costChange := ""
+	if before.TotalCost > 0 {
+		costChange = fmt.Sprintf(" (%+.1f%%)", (after.TotalCost-before.TotalCost)*100/before.TotalCost)
+	}
+	sb.WriteString(fmt.Sprintf("- Total cost: %.2f -> %.2f%s\n", before.TotalCost, after.TotalCost, costChange))
+	if before.Root.Rows > 0 || after.Root.Rows > 0 {
+		sb.WriteString(fmt.Sprintf("- Estimated rows: %.0f -> %.0f\n", before.Root.Rows, after.Root.Rows))
+	}
+
+	beforeCounts, afterCounts := before.nodeTypeCounts(), after.nodeTypeCounts()
+	var nodeTypes []string
+	for nodeType := range beforeCounts {
+		nodeTypes = append(nodeTypes, nodeType)
+	}
+	for nodeType := range afterCounts {
+		if _, ok := beforeCounts[nodeType]; !ok {
+			nodeTypes = append(nodeTypes, nodeType)
+		}
+	}
+	sort.Strings(nodeTypes)
+	var nodeChanges []string
+	for _, nodeType := range nodeTypes {
+		if beforeCounts[nodeType] != afterCounts[nodeType] {
+			nodeChanges = append(nodeChanges, fmt.Sprintf("%s %d -> %d", nodeType, beforeCounts[nodeType], afterCounts[nodeType]))
+		}
+	}
+	if len(nodeChanges) > 0 {
+		sb.WriteString("- Node types: " + strings.Join(nodeChanges, ", ") + "\n")
+	} else {
+		sb.WriteString("- Node types: unchanged\n")
+	}
+
+	added, removed := diffSets(before.indexesUsed(), after.indexesUsed())
+	if len(added) > 0 {
+		sb.WriteString("- Indexes now used: " + strings.Join(added, ", ") + "\n")
+	}
+	if len(removed) > 0 {
+		sb.WriteString("- Indexes no longer used: " + strings.Join(removed, ", ") + "\n")
+	}
+
+	added, removed = diffSets(before.fullScans(), after.fullScans())
+	if len(added) > 0 {
+		sb.WriteString("- New full table scans: " + strings.Join(added, ", ") + "\n")
+	}
+	if len(removed) > 0 {
+		sb.WriteString("- Full table scans removed: " + strings.Join(removed, ", ") + "\n")
+	}
+
+	switch {
+	case after.TotalCost > before.TotalCost*1.1:
+		sb.WriteString("\nVerdict: the second plan is more expensive; this looks like a regression.\n")
+	case after.TotalCost < before.TotalCost*0.9:
+		sb.WriteString("\nVerdict: the second plan is cheaper; this looks like an improvement.\n")
+	case len(nodeChanges) > 0:
+		sb.WriteString("\nVerdict: the plan shape changed but the estimated cost is similar.\n")
+	default:
+		sb.WriteString("\nVerdict: no significant change.\n")
+	}
+
+	return sb.String()
+}
+
+// diffSets returns the values only in b (added) and only in a (removed); both inputs are sorted
+func diffSets(a, b []string) ([]string, []string) {
+	inA := make(map[string]bool, len(a))
+	for _, value := range a {
+		inA[value] = true
+	}
+	inB := make(map[string]bool, len(b))
+	var added []string
+	for _, value := range b {
+		inB[value] = true
+		if !inA[value] {
+			added = append(added, value)
+		}
+	}
+	var removed []string
+	for _, value := range a {
+		if !inB[value] {
+			removed = append(removed, value)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+const testPostgresSeqScanPlan = `[{"Plan": {"Node Type": "Hash Join", "Total Cost": 2350.5, "Plan Rows": 120,
+  "Plans": [
+    {"Node Type": "Seq Scan", "Relation Name": "orders", "Total Cost": 1800.0, "Plan Rows": 50000},
+    {"Node Type": "Hash", "Total Cost": 35.0, "Plan Rows": 1,
+     "Plans": [{"Node Type": "Seq Scan", "Relation Name": "users", "Total Cost": 35.0, "Plan Rows": 1}]}
+  ]}}]`
+
+const testPostgresIndexPlan = `[{"Plan": {"Node Type": "Nested Loop", "Total Cost": 24.6, "Plan Rows": 120,
+  "Plans": [
+    {"Node Type": "Seq Scan", "Relation Name": "users", "Total Cost": 35.0, "Plan Rows": 1},
+    {"Node Type": "Index Scan", "Relation Name": "orders", "Index Name": "idx_orders_user", "Total Cost": 12.3, "Plan Rows": 120}
+  ]}}]`
+
+const testMySQLPlan = `{"query_block": {"select_id": 1, "cost_info": {"query_cost": "120.50"},
+  "nested_loop": [
+    {"table": {"table_name": "users", "access_type": "ALL", "rows_examined_per_scan": 100, "cost_info": {"prefix_cost": "10.25"}}},
+    {"table": {"table_name": "orders", "access_type": "ref", "key": "idx_orders_user", "rows_examined_per_scan": 5, "cost_info": {"prefix_cost": "120.50"}}}
+  ]}}`
+
+func TestParsePostgresPlan(t *testing.T) {
+	plan, err := parseQueryPlan("postgres", testPostgresSeqScanPlan)
+	assert.NoError(t, err)
+	assert.Equal(t, 2350.5, plan.TotalCost)
+	assert.Equal(t, map[string]int{"Hash Join": 1, "Seq Scan": 2, "Hash": 1}, plan.nodeTypeCounts())
+	assert.Equal(t, []string{"orders", "users"}, plan.fullScans())
+	assert.Empty(t, plan.indexesUsed())
+
+	yb, err := parseQueryPlan("yugabyte", testPostgresSeqScanPlan)
+	assert.NoError(t, err)
+	assert.Equal(t, plan.TotalCost, yb.TotalCost)
+	assert.NotNil(t, mostExpensivePlanNode(yb))
+
+	gp, err := parseQueryPlan("greenplum", testPostgresSeqScanPlan)
+	assert.NoError(t, err)
+	assert.Equal(t, plan.fullScans(), gp.fullScans())
+}
+
+func TestParseMySQLPlan(t *testing.T) {
+	plan, err := parseQueryPlan("mysql", testMySQLPlan)
+	assert.NoError(t, err)
+	assert.Equal(t, 120.5, plan.TotalCost)
+	assert.Equal(t, []string{"users"}, plan.fullScans())
+	assert.Equal(t, []string{"idx_orders_user"}, plan.indexesUsed())
+	assert.Equal(t, "-> query_block (cost=120.50 rows=0)\n"+
+		"  -> nested_loop\n"+
+		"    -> ALL on users (cost=10.25 rows=100)\n"+
+		"    -> ref on orders using idx_orders_user (cost=120.50 rows=5)\n", plan.render())
+}
+
+func TestFormatPlanDiff(t *testing.T) {
+	before, err := parseQueryPlan("postgres", testPostgresSeqScanPlan)
+	assert.NoError(t, err)
+	after, err := parseQueryPlan("postgres", testPostgresIndexPlan)
+	assert.NoError(t, err)
+
+	diff := formatPlanDiff(before, after)
+	assert.Contains(t, diff, "- Total cost: 2350.50 -> 24.60 (-99.0%)")
+	assert.Contains(t, diff, "Hash 1 -> 0")
+	assert.Contains(t, diff, "Index Scan 0 -> 1")
+	assert.Contains(t, diff, "- Indexes now used: idx_orders_user")
+	assert.Contains(t, diff, "- Full table scans removed: orders")
+	assert.Contains(t, diff, "improvement")
+}
+
+func TestComparePlansToolAgainstBaseline(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType:  "postgres",
+		results: map[string]*domain.QueryResult{"EXPLAIN (FORMAT JSON)": {Rows: [][]interface{}{{testPostgresIndexPlan}}}},
+	}
+
+	result, err := NewComparePlansTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{
+			"database":      "pg1",
+			"query":         "SELECT * FROM orders JOIN users ON users.id = orders.user_id;",
+			"baseline_plan": testPostgresSeqScanPlan,
+		},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, []string{"EXPLAIN (FORMAT JSON) SELECT * FROM orders JOIN users ON users.id = orders.user_id"}, useCase.queries)
+}
+
+func TestComparePlansToolRequiresComparison(t *testing.T) {
+	_, err := NewComparePlansTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "query": "SELECT 1"},
+	}, "pg1", &mockUseCase{dbType: "postgres"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// CompareSnapshotTool handles diffing the live schema against a saved snapshot
+type CompareSnapshotTool struct {
+	BaseToolType
+}
+
+// NewCompareSnapshotTool creates a new snapshot comparison tool type
+func NewCompareSnapshotTool() *CompareSnapshotTool {
+	return &CompareSnapshotTool{
+		BaseToolType: BaseToolType{
+			name:        "compare_snapshot",
+			description: "Compare the live schema with a snapshot saved by snapshot_schema and list every difference: added and removed tables, added, removed and changed columns (type, nullability, collation, comment), primary key and index changes, and added or removed foreign keys. Useful for detecting schema changes made outside of migrations.",
+		},
+	}
+}
+
+// CreateTool creates a snapshot comparison tool
+func (t *CompareSnapshotTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Diff the live schema against a saved schema snapshot"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("snapshot",
+			tools.Description("Name of the snapshot to compare with (default: the most recent snapshot)"),
+		),
+	)
+}
+
+// HandleRequest handles snapshot comparison tool requests
+func (t *CompareSnapshotTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	name := input.optionalString("snapshot", "")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	dir, err := snapshotsDir(useCase, targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := loadSnapshot(dir, name)
+	if err != nil {
+		return nil, err
+	}
+	live, err := loadSchemaMetadata(ctx, useCase, targetDbID, snapshot.Schema.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := diffSchemaMetadata(snapshot.Schema, live)
+	logger.Info("Compared database %s with snapshot %s: %d changed tables", targetDbID, snapshot.Name, len(diff.ChangedTables))
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Schema Changes for Database %s\n\n", targetDbID))
+	response.WriteString(fmt.Sprintf("Compared with snapshot %s taken %s (%d tables).\n",
+		snapshot.Name, snapshot.CreatedAt.Format("2006-01-02 15:04:05 MST"), len(snapshot.Schema.Tables)))
+	if snapshot.Database != targetDbID || snapshot.Schema.DatabaseType != live.DatabaseType {
+		response.WriteString(fmt.Sprintf("Note: the snapshot was taken from %s database %s.\n", snapshot.Schema.DatabaseType, snapshot.Database))
+	}
+	response.WriteString("\n")
+	response.WriteString(formatSchemaDiff(diff))
+
+	return createTextResponse(response.String()), nil
+}
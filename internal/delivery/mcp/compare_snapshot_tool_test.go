@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestSnapshotAndCompareSchema(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "pg1")
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		config: domain.DatabaseConnectionConfig{SnapshotsDir: dir},
+		results: map[string]*domain.QueryResult{
+			"col_description": {Rows: [][]interface{}{
+				{"public", "users", "id", "integer", false, nil, nil, nil},
+				{"public", "users", "email", "text", true, nil, nil, nil},
+			}},
+		},
+	}
+
+	result, err := NewSnapshotSchemaTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "name": "baseline"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "Saved snapshot baseline of schema public: 1 tables, 0 foreign keys.")
+
+	ddl, err := os.ReadFile(filepath.Join(dir, "baseline.sql"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(ddl), "CREATE TABLE \"users\" (\n  \"id\" integer NOT NULL,\n  \"email\" text\n);")
+
+	_, err = NewSnapshotSchemaTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "name": "baseline"},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "snapshot baseline already exists")
+	_, err = NewSnapshotSchemaTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "name": "../escape"},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "invalid snapshot name")
+
+	// Compare the unchanged schema, then after an out-of-band change
+	result, err = NewCompareSnapshotTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	text = result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "Compared with snapshot baseline taken ")
+	assert.Contains(t, text, "No changes: the live schema matches the snapshot.")
+
+	useCase.results["col_description"] = &domain.QueryResult{Rows: [][]interface{}{
+		{"public", "users", "id", "integer", false, nil, nil, nil},
+		{"public", "users", "email", "text", false, nil, nil, nil},
+	}}
+	result, err = NewCompareSnapshotTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "snapshot": "baseline"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	text = result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "### users\n\n- Column email nullability changed: NULL -> NOT NULL\n")
+
+	_, err = NewCompareSnapshotTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "snapshot": "missing"},
+	}, "pg1", useCase)
+	assert.Error(t, err)
+}
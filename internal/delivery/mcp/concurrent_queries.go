@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+// statsQueryWorkers bounds how many independent queries run at once on one connection
+const statsQueryWorkers = 4
+
+// queryOutcome is the result or error of one query of a list
+type queryOutcome struct {
+	query  string
+	result *domain.QueryResult
+	err    error
+}
+
+// connectionSlots hands out the worker slots of each connection, shared by all tool calls
+var connectionSlots = struct {
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}{slots: make(map[string]chan struct{})}
+
+// slotsFor returns the worker slots of a connection
+func slotsFor(dbID string) chan struct{} {
+	connectionSlots.mu.Lock()
+	defer connectionSlots.mu.Unlock()
+	slots, ok := connectionSlots.slots[dbID]
+	if !ok {
+		slots = make(chan struct{}, statsQueryWorkers)
+		connectionSlots.slots[dbID] = slots
+	}
+	return slots
+}
+
+// runQueriesConcurrently runs independent read queries in parallel, at most statsQueryWorkers at a
+// time per connection, and returns their outcomes in the order of the queries
+func runQueriesConcurrently(ctx context.Context, useCase UseCaseProvider, dbID string, queries []string) []queryOutcome {
+	outcomes := make([]queryOutcome, len(queries))
+	slots := slotsFor(dbID)
+	var wg sync.WaitGroup
+	for i, query := range queries {
+		outcomes[i].query = query
+		wg.Add(1)
+		go func(outcome *queryOutcome) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				outcome.err = ctx.Err()
+				return
+			}
+			defer func() { <-slots }()
+			outcome.result, outcome.err = useCase.ExecuteQuery(ctx, dbID, outcome.query, nil)
+		}(&outcomes[i])
+	}
+	wg.Wait()
+	return outcomes
+}
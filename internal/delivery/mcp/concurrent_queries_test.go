@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+// slowUseCase answers every query after a delay and records how many ran at once
+type slowUseCase struct {
+	mockUseCase
+	delay    time.Duration
+	mu       sync.Mutex
+	running  int
+	maxSeen  int
+	failWith string
+}
+
+func (s *slowUseCase) ExecuteQuery(ctx context.Context, dbID, query string, params []interface{}) (*domain.QueryResult, error) {
+	s.mu.Lock()
+	s.running++
+	s.maxSeen = max(s.maxSeen, s.running)
+	s.mu.Unlock()
+	time.Sleep(s.delay)
+	s.mu.Lock()
+	s.running--
+	s.mu.Unlock()
+	if s.failWith != "" && strings.Contains(query, s.failWith) {
+		return nil, fmt.Errorf("query failed")
+	}
+	return &domain.QueryResult{Columns: []string{"query"}, Rows: [][]interface{}{{query}}}, nil
+}
+
+func TestRunQueriesConcurrently(t *testing.T) {
+	useCase := &slowUseCase{delay: 20 * time.Millisecond, failWith: "q3"}
+	queries := []string{"q0", "q1", "q2", "q3", "q4", "q5", "q6", "q7"}
+
+	start := time.Now()
+	outcomes := runQueriesConcurrently(context.Background(), useCase, "concurrent", queries)
+	elapsed := time.Since(start)
+
+	assert.Len(t, outcomes, len(queries))
+	for i, outcome := range outcomes {
+		assert.Equal(t, queries[i], outcome.query)
+		if i == 3 {
+			assert.EqualError(t, outcome.err, "query failed")
+			continue
+		}
+		assert.NoError(t, outcome.err)
+		assert.Equal(t, queries[i], outcome.result.Rows[0][0])
+	}
+	assert.Equal(t, statsQueryWorkers, useCase.maxSeen)
+	assert.Less(t, elapsed, time.Duration(len(queries))*useCase.delay)
+}
+
+func TestRunQueriesConcurrentlyCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// Hold every slot so no query can start
+	slots := slotsFor("cancelled")
+	for i := 0; i < statsQueryWorkers; i++ {
+		slots <- struct{}{}
+	}
+	t.Cleanup(func() {
+		for i := 0; i < statsQueryWorkers; i++ {
+			<-slots
+		}
+	})
+
+	outcomes := runQueriesConcurrently(ctx, &slowUseCase{}, "cancelled", []string{"q0"})
+	assert.ErrorIs(t, outcomes[0].err, context.Canceled)
+}
+
+func TestTableStatsKeepsQueryOrder(t *testing.T) {
+	useCase := &slowUseCase{mockUseCase: mockUseCase{dbType: "mysql"}, delay: 5 * time.Millisecond}
+	request := server.ToolCallRequest{Parameters: map[string]interface{}{"database": "ordered", "table": "orders", "detailed": true}}
+
+	result, err := NewTableStatsTool().HandleRequest(context.Background(), request, "ordered", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+
+	last := -1
+	for _, section := range getMySQLTableStatsSections("orders") {
+		position := strings.Index(text, section.query)
+		assert.Greater(t, position, last)
+		last = position
+	}
+}
@@ -0,0 +1,194 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// ConstraintStatusTool handles reporting the validation, deferral and exclusion details of constraints
+type ConstraintStatusTool struct {
+	BaseToolType
+}
+
+// NewConstraintStatusTool creates a new constraint status tool type
+func NewConstraintStatusTool() *ConstraintStatusTool {
+	return &ConstraintStatusTool{
+		BaseToolType: BaseToolType{
+			name:        "constraint_status",
+			description: "Report the PostgreSQL constraint details get_constraints does not show: constraints created NOT VALID, whose existing rows were never checked, with the ALTER TABLE ... VALIDATE CONSTRAINT statement for each; deferrable constraints and whether they are initially deferred; and exclusion constraints with their index method, each column or expression with the operator it is compared with, and their predicate. Constraints of every user schema are listed unless a schema or table is given.",
+		},
+	}
+}
+
+// CreateTool creates a constraint status tool
+func (t *ConstraintStatusTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Report NOT VALID, deferrable and exclusion constraints with their details"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to inspect (default: all user schemas)"),
+		),
+		tools.WithString("table",
+			tools.Description("Only inspect the constraints of this table"),
+		),
+	)
+}
+
+// HandleRequest handles constraint status tool requests
+func (t *ConstraintStatusTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema := input.optionalString("schema", "")
+	tableSchema, table := splitQualifiedName(input.optionalString("table", ""))
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if tableSchema != "" {
+		schema = tableSchema
+	}
+
+	logger.Info("Getting constraint status for database %s, schema %s, table %s", targetDbID, schema, table)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for constraint status: %s", dbType)
+	}
+	sections, ok := dialect.ConstraintStatusSections(schema, table)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for constraint status: %s", dbType)
+	}
+
+	var response strings.Builder
+	if table != "" {
+		response.WriteString(fmt.Sprintf("# Constraint Status of Table %s in Database %s\n", qualifiedName(schema, table), targetDbID))
+	} else {
+		response.WriteString(fmt.Sprintf("# Constraint Status in Database %s\n", targetDbID))
+	}
+	if err := writeReportSections(ctx, useCase, targetDbID, sections, &response); err != nil {
+		return nil, err
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// postgresConstraintFilter restricts constraints c of table t in schema n to the user schemas,
+// the schema $1 and the table $2, when given
+const postgresConstraintFilter = `
+WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg_toast%'
+  AND ($1 = '' OR n.nspname = $1)
+  AND ($2 = '' OR t.relname = $2)`
+
+// postgresConstraintType names the kind of constraint c
+const postgresConstraintType = `
+    CASE c.contype
+        WHEN 'c' THEN 'CHECK'
+        WHEN 'f' THEN 'FOREIGN KEY'
+        WHEN 'p' THEN 'PRIMARY KEY'
+        WHEN 'u' THEN 'UNIQUE'
+        WHEN 'x' THEN 'EXCLUDE'
+        WHEN 'n' THEN 'NOT NULL'
+        WHEN 't' THEN 'CONSTRAINT TRIGGER'
+        ELSE c.contype::text
+    END AS constraint_type`
+
+// postgresConstraintStatusSections returns the constraints not validated, the deferrable
+// constraints and the exclusion constraints of the user schemas. The elements of an exclusion
+// constraint pair each key of its index with the operator at the same position of conexclop.
+func postgresConstraintStatusSections(schema, table string) []reportSection {
+	params := []interface{}{schema, table}
+	return []reportSection{
+		{
+			title: "Constraints Not Validated",
+			query: `
+SELECT
+    n.nspname AS schema_name,
+    t.relname AS table_name,
+    c.conname AS constraint_name,` + postgresConstraintType + `,
+    pg_get_constraintdef(c.oid, true) AS definition,
+    format('ALTER TABLE %I.%I VALIDATE CONSTRAINT %I;', n.nspname, t.relname, c.conname) AS validate_statement
+FROM pg_constraint c
+JOIN pg_class t ON t.oid = c.conrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace` + postgresConstraintFilter + `
+  AND NOT c.convalidated
+ORDER BY 1, 2, 3`,
+			params: params,
+			warn: func(row []interface{}) []string {
+				if len(row) < 6 {
+					return nil
+				}
+				return []string{fmt.Sprintf("%s constraint %s on %s was created NOT VALID, so rows that existed before it are not checked; `%s` checks them without blocking reads or writes.",
+					valueString(row[3]), valueString(row[2]), qualifiedName(valueString(row[0]), valueString(row[1])), valueString(row[5]))}
+			},
+		},
+		{
+			title: "Deferrable Constraints",
+			query: `
+SELECT
+    n.nspname AS schema_name,
+    t.relname AS table_name,
+    c.conname AS constraint_name,` + postgresConstraintType + `,
+    c.condeferred AS initially_deferred,
+    pg_get_constraintdef(c.oid, true) AS definition
+FROM pg_constraint c
+JOIN pg_class t ON t.oid = c.conrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace` + postgresConstraintFilter + `
+  AND c.condeferrable
+ORDER BY 1, 2, 3`,
+			params: params,
+			warn: func(row []interface{}) []string {
+				if len(row) < 5 {
+					return nil
+				}
+				name := fmt.Sprintf("%s on %s", valueString(row[2]), qualifiedName(valueString(row[0]), valueString(row[1])))
+				var warnings []string
+				if valueBool(row[4]) {
+					warnings = append(warnings, fmt.Sprintf("Constraint %s is INITIALLY DEFERRED, so a violation is only reported at COMMIT rather than by the statement that caused it.", name))
+				}
+				switch valueString(row[3]) {
+				case "PRIMARY KEY", "UNIQUE":
+					warnings = append(warnings, fmt.Sprintf("Constraint %s is deferrable, so INSERT ... ON CONFLICT cannot use it as an arbiter and foreign keys cannot reference it.", name))
+				}
+				return warnings
+			},
+		},
+		{
+			title: "Exclusion Constraints",
+			query: `
+SELECT
+    n.nspname AS schema_name,
+    t.relname AS table_name,
+    c.conname AS constraint_name,
+    am.amname AS index_method,
+    (SELECT string_agg(pg_get_indexdef(c.conindid, k.ord::int, true) || ' WITH ' || o.oprname, ', ' ORDER BY k.ord)
+     FROM unnest(c.conexclop) WITH ORDINALITY k(opoid, ord)
+     JOIN pg_operator o ON o.oid = k.opoid) AS elements,
+    (SELECT string_agg(k.opoid::regoperator::text, ', ' ORDER BY k.ord)
+     FROM unnest(c.conexclop) WITH ORDINALITY k(opoid, ord)) AS operators,
+    COALESCE(pg_get_expr(i.indpred, i.indrelid, true), '') AS predicate,
+    CASE WHEN c.condeferred THEN 'initially deferred' WHEN c.condeferrable THEN 'deferrable' ELSE 'no' END AS deferrable
+FROM pg_constraint c
+JOIN pg_class t ON t.oid = c.conrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+JOIN pg_index i ON i.indexrelid = c.conindid
+JOIN pg_class ic ON ic.oid = c.conindid
+JOIN pg_am am ON am.oid = ic.relam` + postgresConstraintFilter + `
+  AND c.contype = 'x'
+ORDER BY 1, 2, 3`,
+			params: params,
+		},
+	}
+}
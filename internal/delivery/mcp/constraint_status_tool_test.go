@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestConstraintStatusReportsConstraints(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"AND NOT c.convalidated": {
+				Columns: []string{"schema_name", "table_name", "constraint_name", "constraint_type", "definition", "validate_statement"},
+				Rows: [][]interface{}{
+					{"public", "bookings", "bookings_room_fk", "FOREIGN KEY", "FOREIGN KEY (room_id) REFERENCES rooms(id) NOT VALID", "ALTER TABLE public.bookings VALIDATE CONSTRAINT bookings_room_fk;"},
+				},
+			},
+			"AND c.condeferrable": {
+				Columns: []string{"schema_name", "table_name", "constraint_name", "constraint_type", "initially_deferred", "definition"},
+				Rows: [][]interface{}{
+					{"public", "bookings", "bookings_code_key", "UNIQUE", true, "UNIQUE (code) DEFERRABLE INITIALLY DEFERRED"},
+					{"public", "bookings", "bookings_guest_fk", "FOREIGN KEY", false, "FOREIGN KEY (guest_id) REFERENCES guests(id) DEFERRABLE"},
+				},
+			},
+			"AND c.contype = 'x'": {
+				Columns: []string{"schema_name", "table_name", "constraint_name", "index_method", "elements", "operators", "predicate", "deferrable"},
+				Rows: [][]interface{}{
+					{"public", "bookings", "bookings_no_overlap", "gist", "room_id WITH =, during WITH &&", "=(integer,integer), &&(anyrange,anyrange)", "NOT cancelled", "no"},
+				},
+			},
+		},
+	}
+	result, err := NewConstraintStatusTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "pg1", "table": "public.bookings"}}, "", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+
+	assert.Len(t, useCase.queries, 3)
+	assert.Contains(t, text, "# Constraint Status of Table public.bookings in Database pg1")
+	assert.Contains(t, text, "room_id WITH =, during WITH &&")
+	assert.Contains(t, text, "- FOREIGN KEY constraint bookings_room_fk on public.bookings was created NOT VALID, so rows that existed before it are not checked; `ALTER TABLE public.bookings VALIDATE CONSTRAINT bookings_room_fk;` checks them")
+	assert.Contains(t, text, "- Constraint bookings_code_key on public.bookings is INITIALLY DEFERRED")
+	assert.Contains(t, text, "- Constraint bookings_code_key on public.bookings is deferrable, so INSERT ... ON CONFLICT cannot use it")
+	assert.NotContains(t, text, "bookings_guest_fk on public.bookings is")
+
+	_, err = NewConstraintStatusTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "my1"}}, "", &mockUseCase{dbType: "mysql"})
+	assert.ErrorContains(t, err, "unsupported database type for constraint status: mysql")
+}
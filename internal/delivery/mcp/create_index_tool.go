@@ -0,0 +1,426 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// indexProgressInterval is how often the progress of an index build is polled
+var indexProgressInterval = time.Second
+
+// indexNameReplacer matches the characters dropped from generated index names
+var indexNameReplacer = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// indexBuilder is the engine-specific part of building an index online
+type indexBuilder interface {
+	// methods returns the index access methods the engine accepts
+	methods() map[string]bool
+	// statement renders the CREATE INDEX statement of spec on its quoted, ordered columns
+	statement(spec indexBuildSpec, columns []string) (string, error)
+	// progressQuery returns the query reading the phase, work done and work estimated of a
+	// build on table, or false when the engine does not report progress
+	progressQuery(table string) (string, []interface{}, bool)
+	// failureNote cleans up after a failed build or explains how to get past it, returning
+	// what it found or an empty string
+	failureNote(ctx context.Context, useCase UseCaseProvider, dbID string, spec indexBuildSpec, err error) string
+}
+
+// indexBuildProgress is one progress sample of a running index build
+type indexBuildProgress struct {
+	Elapsed time.Duration
+	Phase   string
+	Done    int64
+	Total   int64
+}
+
+// indexBuildSpec describes the index to create
+type indexBuildSpec struct {
+	Name      string
+	Table     string
+	Columns   []string
+	Unique    bool
+	Method    string
+	Where     string
+	Online    bool
+	Algorithm string // MySQL only: inplace or copy
+}
+
+// CreateIndexTool handles building indexes without blocking writes
+type CreateIndexTool struct {
+	BaseToolType
+}
+
+// NewCreateIndexTool creates a new index creation tool type
+func NewCreateIndexTool() *CreateIndexTool {
+	return &CreateIndexTool{
+		BaseToolType: BaseToolType{
+			name:        "create_index",
+			description: "Create an index without blocking writes. On PostgreSQL the index is built with CREATE INDEX CONCURRENTLY, progress is sampled from pg_stat_progress_create_index while it runs, and the invalid index left behind by a failed build is dropped. On MySQL the index is built with ALGORITHM=INPLACE and LOCK=NONE so the statement fails instead of silently copying the table; adding an index cannot use ALGORITHM=INSTANT. Use dry_run to see the statement first.",
+		},
+	}
+}
+
+// CreateTool creates an index creation tool
+func (t *CreateIndexTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Build an index online with progress reporting"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Table to index"),
+			tools.Required(),
+		),
+		tools.WithArray("columns",
+			tools.Description("Columns of the index in order, optionally followed by ASC or DESC"),
+			tools.Required(),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithString("name",
+			tools.Description("Index name (default: idx_<table>_<columns>)"),
+		),
+		tools.WithBoolean("unique",
+			tools.Description("Create a unique index (default: false)"),
+		),
+		tools.WithString("method",
+			tools.Description("Index method, e.g. btree, hash, gin, gist or brin (default: the database default)"),
+		),
+		tools.WithString("where",
+			tools.Description("Predicate of a partial index (PostgreSQL only)"),
+		),
+		tools.WithBoolean("online",
+			tools.Description("Build without blocking writes (default: true)"),
+		),
+		tools.WithString("algorithm",
+			tools.Description("MySQL algorithm: inplace or copy (default: inplace when online)"),
+		),
+		tools.WithBoolean("dry_run",
+			tools.Description("Only show the statement that would run (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles index creation tool requests
+func (t *CreateIndexTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	spec := indexBuildSpec{
+		Table:     input.requiredString("table"),
+		Columns:   input.stringList("columns"),
+		Name:      input.optionalString("name", ""),
+		Unique:    input.optionalBool("unique", false),
+		Method:    strings.ToLower(input.optionalString("method", "")),
+		Where:     strings.TrimSpace(input.optionalString("where", "")),
+		Online:    input.optionalBool("online", true),
+		Algorithm: strings.ToLower(input.optionalString("algorithm", "")),
+	}
+	dryRun := input.optionalBool("dry_run", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if len(spec.Columns) == 0 {
+		return nil, fmt.Errorf("columns parameter must be a non-empty array of column names")
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for create_index: %s", dbType)
+	}
+	if spec.Name == "" {
+		spec.Name = defaultIndexName(spec.Table, spec.Columns)
+	}
+	statement, err := createIndexStatement(dialect, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Create Index for Database %s\n\n", targetDbID))
+	response.WriteString(fmt.Sprintf("```sql\n%s\n```\n\n", statement))
+	if dryRun {
+		response.WriteString("Dry run: the index was not created.\n")
+		return createTextResponse(response.String()), nil
+	}
+
+	logger.Info("Creating index %s on %s in database %s", spec.Name, spec.Table, targetDbID)
+	start := time.Now()
+	builder, _ := dialect.IndexBuilder()
+	progress, err := runIndexBuild(ctx, useCase, targetDbID, builder, spec, statement)
+	if err != nil {
+		note := ""
+		if n := builder.failureNote(ctx, useCase, targetDbID, spec, err); n != "" {
+			note = " (" + n + ")"
+		}
+		return nil, fmt.Errorf("failed to create index %s%s: %w", spec.Name, note, err)
+	}
+
+	logger.Info("Created index %s on %s in database %s in %s", spec.Name, spec.Table, targetDbID, time.Since(start).Round(time.Millisecond))
+	response.WriteString(fmt.Sprintf("Created index %s on %s in %s.\n", spec.Name, spec.Table, time.Since(start).Round(time.Millisecond)))
+	if len(progress) > 0 {
+		response.WriteString("\n## Progress\n\n")
+		for _, p := range progress {
+			response.WriteString(fmt.Sprintf("- %s: %s", p.Elapsed.Round(time.Second), p.Phase))
+			if p.Total > 0 {
+				response.WriteString(fmt.Sprintf(" (%.1f%%)", progressPercent(p)))
+			}
+			response.WriteString("\n")
+		}
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// defaultIndexName builds idx_<table>_<columns>, truncated to the 63 characters PostgreSQL allows
+func defaultIndexName(table string, columns []string) string {
+	_, table = splitQualifiedName(table)
+	parts := []string{"idx", table}
+	for _, column := range columns {
+		parts = append(parts, strings.Fields(column)[0])
+	}
+	name := strings.Trim(indexNameReplacer.ReplaceAllString(strings.ToLower(strings.Join(parts, "_")), "_"), "_")
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return name
+}
+
+// createIndexStatement renders the CREATE INDEX statement for the dialect
+func createIndexStatement(dialect Dialect, spec indexBuildSpec) (string, error) {
+	builder, err := dialect.IndexBuilder()
+	if err != nil {
+		return "", unsupportedAdminError("create_index", dialect.Name(), err)
+	}
+	if spec.Method != "" && !builder.methods()[spec.Method] {
+		return "", fmt.Errorf("unsupported index method for %s: %s", dialect.Name(), spec.Method)
+	}
+
+	columns := make([]string, len(spec.Columns))
+	for i, column := range spec.Columns {
+		fields := strings.Fields(column)
+		if len(fields) == 0 || len(fields) > 2 {
+			return "", fmt.Errorf("invalid index column: %q", column)
+		}
+		columns[i] = quoteIdentifier(dialect.Name(), fields[0])
+		if len(fields) == 2 {
+			order := strings.ToUpper(fields[1])
+			if order != "ASC" && order != "DESC" {
+				return "", fmt.Errorf("invalid sort order in index column %q (expected ASC or DESC)", column)
+			}
+			columns[i] += " " + order
+		}
+	}
+	return builder.statement(spec, columns)
+}
+
+// createIndexPrefix starts a CREATE INDEX statement
+func createIndexPrefix(spec indexBuildSpec) string {
+	if spec.Unique {
+		return "CREATE UNIQUE INDEX "
+	}
+	return "CREATE INDEX "
+}
+
+// postgresIndexBuilder builds indexes with CREATE INDEX CONCURRENTLY
+type postgresIndexBuilder struct{}
+
+func (postgresIndexBuilder) methods() map[string]bool {
+	return map[string]bool{"btree": true, "hash": true, "gin": true, "gist": true, "spgist": true, "brin": true}
+}
+
+func (postgresIndexBuilder) statement(spec indexBuildSpec, columns []string) (string, error) {
+	if spec.Algorithm != "" {
+		return "", fmt.Errorf("algorithm only applies to MySQL")
+	}
+	var sb strings.Builder
+	sb.WriteString(createIndexPrefix(spec))
+	if spec.Online {
+		sb.WriteString("CONCURRENTLY ")
+	}
+	sb.WriteString(fmt.Sprintf("%s ON %s", quoteIdentifier("postgres", spec.Name), quoteIdentifier("postgres", spec.Table)))
+	if spec.Method != "" {
+		sb.WriteString(" USING " + spec.Method)
+	}
+	sb.WriteString(" (" + strings.Join(columns, ", ") + ")")
+	if spec.Where != "" {
+		sb.WriteString(" WHERE " + spec.Where)
+	}
+	return sb.String(), nil
+}
+
+func (postgresIndexBuilder) progressQuery(table string) (string, []interface{}, bool) {
+	return `
+SELECT
+    phase,
+    CASE WHEN blocks_total > 0 THEN blocks_done WHEN tuples_total > 0 THEN tuples_done ELSE lockers_done END AS done,
+    CASE WHEN blocks_total > 0 THEN blocks_total WHEN tuples_total > 0 THEN tuples_total ELSE lockers_total END AS total
+FROM pg_stat_progress_create_index
+WHERE relid = $1::regclass`, []interface{}{table}, true
+}
+
+// failureNote drops the invalid index a failed concurrent build leaves behind
+func (postgresIndexBuilder) failureNote(ctx context.Context, useCase UseCaseProvider, dbID string, spec indexBuildSpec, _ error) string {
+	if !spec.Online {
+		return ""
+	}
+	return dropInvalidIndex(ctx, useCase, dbID, spec)
+}
+
+// mysqlIndexBuilder builds indexes with ALGORITHM=INPLACE and LOCK=NONE, so the statement
+// fails instead of silently copying the table
+type mysqlIndexBuilder struct{}
+
+func (mysqlIndexBuilder) methods() map[string]bool {
+	return map[string]bool{"btree": true, "hash": true}
+}
+
+func (mysqlIndexBuilder) statement(spec indexBuildSpec, columns []string) (string, error) {
+	if spec.Where != "" {
+		return "", fmt.Errorf("partial indexes are not supported on MySQL")
+	}
+	algorithm := spec.Algorithm
+	switch algorithm {
+	case "":
+		if spec.Online {
+			algorithm = "inplace"
+		}
+	case "instant":
+		return "", fmt.Errorf("MySQL cannot add an index with ALGORITHM=INSTANT; use inplace (online, the default) or copy")
+	case "inplace", "copy":
+	default:
+		return "", fmt.Errorf("invalid algorithm: %s (expected inplace or copy)", algorithm)
+	}
+	var sb strings.Builder
+	sb.WriteString(createIndexPrefix(spec))
+	sb.WriteString(fmt.Sprintf("%s ON %s (%s)", quoteIdentifier("mysql", spec.Name), quoteIdentifier("mysql", spec.Table), strings.Join(columns, ", ")))
+	if spec.Method != "" {
+		sb.WriteString(" USING " + strings.ToUpper(spec.Method))
+	}
+	if algorithm != "" {
+		sb.WriteString(" ALGORITHM=" + strings.ToUpper(algorithm))
+	}
+	if algorithm == "inplace" {
+		sb.WriteString(" LOCK=NONE")
+	}
+	return sb.String(), nil
+}
+
+// progressQuery reads the InnoDB ALTER stages; stage instruments must be enabled in
+// performance_schema for it to report anything
+func (mysqlIndexBuilder) progressQuery(string) (string, []interface{}, bool) {
+	return `
+SELECT
+    REPLACE(event_name, 'stage/innodb/', '') AS phase,
+    work_completed,
+    work_estimated
+FROM performance_schema.events_stages_current
+WHERE event_name LIKE 'stage/innodb/alter%'`, nil, true
+}
+
+func (mysqlIndexBuilder) failureNote(_ context.Context, _ UseCaseProvider, _ string, spec indexBuildSpec, err error) string {
+	if spec.Online && strings.Contains(strings.ToUpper(err.Error()), "ALGORITHM") {
+		return "the index cannot be built in place on this table; retry with algorithm copy to allow a blocking table copy"
+	}
+	return ""
+}
+
+// runIndexBuild executes the statement while sampling the build progress; a sample is
+// kept whenever the phase changes or the build advances by at least ten percent
+func runIndexBuild(ctx context.Context, useCase UseCaseProvider, dbID string, builder indexBuilder, spec indexBuildSpec, statement string) ([]indexBuildProgress, error) {
+	done := make(chan error, 1)
+	go func() {
+		_, err := useCase.ExecuteStatement(ctx, dbID, statement, nil)
+		done <- err
+	}()
+
+	start := time.Now()
+	ticker := time.NewTicker(indexProgressInterval)
+	defer ticker.Stop()
+
+	var progress []indexBuildProgress
+	for {
+		select {
+		case err := <-done:
+			return progress, err
+		case <-ticker.C:
+			sample, ok := sampleIndexProgress(ctx, useCase, dbID, builder, spec.Table)
+			if !ok {
+				continue
+			}
+			sample.Elapsed = time.Since(start)
+			if n := len(progress); n == 0 || progress[n-1].Phase != sample.Phase || progressPercent(sample)-progressPercent(progress[n-1]) >= 10 {
+				progress = append(progress, sample)
+				logger.Info("Index %s on database %s: %s", spec.Name, dbID, sample.Phase)
+			}
+		}
+	}
+}
+
+// sampleIndexProgress reads the current progress of an index build on the table
+func sampleIndexProgress(ctx context.Context, useCase UseCaseProvider, dbID string, builder indexBuilder, table string) (indexBuildProgress, bool) {
+	query, params, ok := builder.progressQuery(table)
+	if !ok {
+		return indexBuildProgress{}, false
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, params)
+	if err != nil || len(result.Rows) == 0 {
+		return indexBuildProgress{}, false
+	}
+	row := result.Rows[0]
+	return indexBuildProgress{Phase: valueString(row[0]), Done: valueInt64(row[1]), Total: valueInt64(row[2])}, true
+}
+
+// progressPercent returns the completion of a progress sample in percent
+func progressPercent(p indexBuildProgress) float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return float64(p.Done) * 100 / float64(p.Total)
+}
+
+// dropInvalidIndex removes the invalid index a failed CREATE INDEX CONCURRENTLY leaves behind
+// and describes what happened
+func dropInvalidIndex(ctx context.Context, useCase UseCaseProvider, dbID string, spec indexBuildSpec) string {
+	schema, _ := splitQualifiedName(spec.Table)
+	schemaFilter := "n.nspname = current_schema()"
+	params := []interface{}{spec.Name}
+	if schema != "" {
+		schemaFilter = "n.nspname = $2"
+		params = append(params, schema)
+	}
+	result, err := useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT COUNT(*)
+FROM pg_index i
+JOIN pg_class c ON c.oid = i.indexrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relname = $1 AND %s AND NOT i.indisvalid`, schemaFilter), params)
+	if err != nil {
+		return fmt.Sprintf("could not check for an invalid index: %v", err)
+	}
+	if len(result.Rows) == 0 || valueInt64(result.Rows[0][0]) == 0 {
+		return "no invalid index was left behind"
+	}
+
+	name := spec.Name
+	if schema != "" {
+		name = schema + "." + name
+	}
+	if _, err := useCase.ExecuteStatement(ctx, dbID, "DROP INDEX CONCURRENTLY IF EXISTS "+quoteIdentifier("postgres", name), nil); err != nil {
+		return fmt.Sprintf("the invalid index %s could not be dropped, drop it manually: %v", name, err)
+	}
+	logger.Warn("Dropped invalid index %s on database %s after a failed build", name, dbID)
+	return fmt.Sprintf("the invalid index %s left by the failed build was dropped", name)
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestCreateIndexStatement(t *testing.T) {
+	spec := indexBuildSpec{Name: "idx_orders_user_id", Table: "orders", Columns: []string{"user_id", "created_at desc"}, Online: true}
+
+	statement, err := createIndexStatement(dialects["postgres"], indexBuildSpec{
+		Name: spec.Name, Table: spec.Table, Columns: spec.Columns, Online: true, Unique: true, Method: "btree", Where: "deleted_at IS NULL",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `CREATE UNIQUE INDEX CONCURRENTLY "idx_orders_user_id" ON "orders" USING btree ("user_id", "created_at" DESC) WHERE deleted_at IS NULL`, statement)
+
+	statement, err = createIndexStatement(dialects["mysql"], spec)
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE INDEX `idx_orders_user_id` ON `orders` (`user_id`, `created_at` DESC) ALGORITHM=INPLACE LOCK=NONE", statement)
+
+	spec.Algorithm = "instant"
+	_, err = createIndexStatement(dialects["mysql"], spec)
+	assert.ErrorContains(t, err, "ALGORITHM=INSTANT")
+
+	_, err = createIndexStatement(dialects["mysql"], indexBuildSpec{Name: "i", Table: "t", Columns: []string{"a"}, Where: "a > 1"})
+	assert.Error(t, err)
+	_, err = createIndexStatement(dialects["postgres"], indexBuildSpec{Name: "i", Table: "t", Columns: []string{"a sideways"}})
+	assert.Error(t, err)
+
+	assert.Equal(t, "idx_orders_user_id_created_at", defaultIndexName("public.orders", []string{"user_id", "created_at DESC"}))
+}
+
+func TestCreateIndexToolReportsProgress(t *testing.T) {
+	indexProgressInterval = 10 * time.Millisecond
+	defer func() { indexProgressInterval = time.Second }()
+
+	useCase := &mockUseCase{
+		dbType:         "postgres",
+		statementDelay: 100 * time.Millisecond,
+		results: map[string]*domain.QueryResult{
+			"pg_stat_progress_create_index": {Rows: [][]interface{}{{"building index: scanning table", int64(50), int64(200)}}},
+		},
+	}
+
+	result, err := NewCreateIndexTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "table": "orders", "columns": []interface{}{"user_id"}},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, `CREATE INDEX CONCURRENTLY "idx_orders_user_id" ON "orders" ("user_id")`)
+	assert.Contains(t, text, "Created index idx_orders_user_id on orders")
+	assert.Contains(t, text, "## Progress\n\n- 0s: building index: scanning table (25.0%)\n")
+}
+
+func TestCreateIndexToolDropsInvalidIndex(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType:        "postgres",
+		failStatement: "CREATE UNIQUE INDEX",
+		results: map[string]*domain.QueryResult{
+			"indisvalid": {Rows: [][]interface{}{{int64(1)}}},
+		},
+	}
+
+	_, err := NewCreateIndexTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "table": "users", "columns": []interface{}{"email"}, "unique": true},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "failed to create index idx_users_email (the invalid index idx_users_email left by the failed build was dropped)")
+	assert.Equal(t, `DROP INDEX CONCURRENTLY IF EXISTS "idx_users_email"`, useCase.queries[len(useCase.queries)-1])
+}
+
+func TestCreateIndexToolDryRun(t *testing.T) {
+	useCase := &mockUseCase{dbType: "mysql"}
+
+	result, err := NewCreateIndexTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "table": "users", "columns": []interface{}{"email"}, "online": false, "dry_run": true},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "CREATE INDEX `idx_users_email` ON `users` (`email`)\n```")
+	assert.Contains(t, text, "Dry run: the index was not created.")
+	assert.Empty(t, useCase.queries)
+}
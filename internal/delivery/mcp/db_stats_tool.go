@@ -42,18 +42,11 @@ func (t *DbStatsTool) CreateTool(name string, dbID string) interface{} {
 
 // HandleRequest handles database statistics tool requests
 func (t *DbStatsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
-	// Extract database ID from parameters
-	targetDbID, ok := request.Parameters["database"].(string)
-	if !ok {
-		return nil, fmt.Errorf("database parameter must be a string")
-	}
-
-	// Extract detailed flag
-	detailed := false
-	if request.Parameters["detailed"] != nil {
-		if detailedParam, ok := request.Parameters["detailed"].(bool); ok {
-			detailed = detailedParam
-		}
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	detailed := input.optionalBool("detailed", false)
+	if err := input.err(); err != nil {
+		return nil, err
 	}
 
 	logger.Info("Getting database statistics for %s (detailed: %v)", targetDbID, detailed)
@@ -64,33 +57,33 @@ func (t *DbStatsTool) HandleRequest(ctx context.Context, request server.ToolCall
 		return nil, fmt.Errorf("failed to get database type: %w", err)
 	}
 
-	// Define queries based on database type
-	var queries []string
-	switch strings.ToLower(dbType) {
-	case "postgres":
-		queries = getPostgresStatsQueries(detailed)
-	case "mysql":
-		queries = getMySQLStatsQueries(detailed)
-	default:
+	// Redis reports its statistics through INFO rather than SQL
+	if dbType == "redis" {
+		return redisStats(ctx, useCase, targetDbID, detailed)
+	}
+
+	// Build queries in the dialect of the database
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
 		return nil, fmt.Errorf("unsupported database type for statistics: %s", dbType)
 	}
+	queries := dialect.DatabaseStatsQueries(detailed)
 
 	// Execute each query and combine results
 	var results strings.Builder
 	results.WriteString(fmt.Sprintf("# Database Statistics for %s (%s)\n\n", targetDbID, dbType))
 
-	for _, query := range queries {
-		// Execute the query
-		result, err := useCase.ExecuteQuery(ctx, targetDbID, query, nil)
-		if err != nil {
+	// The queries are independent, so they run concurrently and their sections keep the query order
+	for _, outcome := range runQueriesConcurrently(ctx, useCase, targetDbID, queries) {
+		if outcome.err != nil {
 			// Log the error but continue with other queries
-			logger.Warn("Error executing stats query: %v", err)
-			results.WriteString(fmt.Sprintf("Error executing query: %s\n%v\n\n", query, err))
+			logger.Warn("Error executing stats query: %v", outcome.err)
+			results.WriteString(fmt.Sprintf("Error executing query: %s\n%v\n\n", outcome.query, outcome.err))
 			continue
 		}
 
 		// Add the result
-		results.WriteString(result)
+		results.WriteString(formatQueryResult(outcome.result))
 		results.WriteString("\n\n")
 	}
 
@@ -242,3 +235,960 @@ func getMySQLStatsQueries(detailed bool) []string {
 
 	return queries
 }
+
+// getSQLiteStatsQueries returns queries for SQLite statistics
+func getSQLiteStatsQueries(detailed bool) []string {
+	// Basic queries
+	queries := []string{
+		// Database file size
+		`SELECT 
+			p.page_count * s.page_size AS size_bytes,
+			p.page_count,
+			s.page_size,
+			f.freelist_count AS free_pages
+		FROM pragma_page_count p, pragma_page_size s, pragma_freelist_count f;`,
+
+		// Settings that matter for concurrency and integrity
+		`SELECT 
+			sqlite_version() AS version,
+			j.journal_mode,
+			fk.foreign_keys,
+			e.encoding
+		FROM pragma_journal_mode j, pragma_foreign_keys fk, pragma_encoding e;`,
+
+		// Object counts
+		`SELECT 
+			type AS object_type,
+			COUNT(*) AS object_count
+		FROM sqlite_master
+		WHERE name NOT LIKE 'sqlite_%'
+		GROUP BY type
+		ORDER BY type;`,
+	}
+
+	// Add detailed queries if requested
+	if detailed {
+		detailedQueries := []string{
+			// Table statistics gathered by ANALYZE
+			`SELECT 
+				tbl AS table_name,
+				idx AS index_name,
+				stat
+			FROM sqlite_stat1
+			ORDER BY tbl, idx;`,
+
+			// Compile options of the embedded library
+			`SELECT compile_options FROM pragma_compile_options ORDER BY compile_options;`,
+		}
+
+		queries = append(queries, detailedQueries...)
+	}
+
+	return queries
+}
+
+// getClickHouseStatsQueries returns queries for ClickHouse statistics
+func getClickHouseStatsQueries(detailed bool) []string {
+	// Basic queries
+	queries := []string{
+		// Server version and uptime
+		`SELECT 
+			version() AS version,
+			uptime() AS uptime_seconds,
+			currentDatabase() AS database;`,
+
+		// Storage of the current database
+		`SELECT 
+			count(DISTINCT table) AS tables,
+			count() AS active_parts,
+			sum(rows) AS rows,
+			formatReadableSize(sum(data_compressed_bytes)) AS compressed_size,
+			formatReadableSize(sum(data_uncompressed_bytes)) AS uncompressed_size,
+			round(sum(data_uncompressed_bytes) / nullIf(sum(data_compressed_bytes), 0), 2) AS compression_ratio
+		FROM system.parts
+		WHERE database = currentDatabase()
+		AND active;`,
+
+		// Largest tables
+		`SELECT 
+			table AS table_name,
+			count() AS parts,
+			sum(rows) AS rows,
+			formatReadableSize(sum(data_compressed_bytes)) AS compressed_size
+		FROM system.parts
+		WHERE database = currentDatabase()
+		AND active
+		GROUP BY table
+		ORDER BY sum(data_compressed_bytes) DESC
+		LIMIT 20;`,
+	}
+
+	// Add detailed queries if requested
+	if detailed {
+		detailedQueries := []string{
+			// Merges in progress
+			`SELECT 
+				table AS table_name,
+				num_parts,
+				round(progress * 100, 1) AS progress_pct,
+				round(elapsed) AS elapsed_seconds
+			FROM system.merges
+			WHERE database = currentDatabase()
+			ORDER BY elapsed DESC;`,
+
+			// Unfinished mutations
+			`SELECT 
+				table AS table_name,
+				mutation_id,
+				command,
+				parts_to_do,
+				latest_fail_reason
+			FROM system.mutations
+			WHERE database = currentDatabase()
+			AND NOT is_done
+			ORDER BY create_time;`,
+
+			// Running queries
+			`SELECT 
+				query_id,
+				user,
+				round(elapsed) AS elapsed_seconds,
+				read_rows,
+				formatReadableSize(memory_usage) AS memory
+			FROM system.processes
+			ORDER BY elapsed DESC;`,
+		}
+
+		queries = append(queries, detailedQueries...)
+	}
+
+	return queries
+}
+
+// getBigQueryStatsQueries returns queries for the statistics of a BigQuery dataset; storage
+// is reported in logical bytes and in the bytes storage is billed for
+func getBigQueryStatsQueries(detailed bool) []string {
+	// Basic queries
+	queries := []string{
+		// Object counts
+		`SELECT 
+			table_type,
+			COUNT(*) AS object_count
+		FROM INFORMATION_SCHEMA.TABLES
+		GROUP BY table_type
+		ORDER BY table_type;`,
+
+		// Storage of the dataset
+		`SELECT 
+			COUNT(DISTINCT table_name) AS tables,
+			COUNT(*) AS partitions,
+			SUM(total_rows) AS total_rows,
+			ROUND(SUM(total_logical_bytes) / POW(1024, 3), 3) AS logical_gb,
+			ROUND(SUM(total_billable_bytes) / POW(1024, 3), 3) AS billable_gb
+		FROM INFORMATION_SCHEMA.PARTITIONS;`,
+
+		// Largest tables
+		`SELECT 
+			table_name,
+			COUNT(*) AS partitions,
+			SUM(total_rows) AS total_rows,
+			ROUND(SUM(total_billable_bytes) / POW(1024, 3), 3) AS billable_gb
+		FROM INFORMATION_SCHEMA.PARTITIONS
+		GROUP BY table_name
+		ORDER BY SUM(total_billable_bytes) DESC
+		LIMIT 20;`,
+	}
+
+	// Add detailed queries if requested
+	if detailed {
+		detailedQueries := []string{
+			// Table options such as partition expiration and required partition filters
+			`SELECT 
+				table_name,
+				option_name,
+				option_value
+			FROM INFORMATION_SCHEMA.TABLE_OPTIONS
+			ORDER BY table_name, option_name;`,
+
+			// Storage tiers
+			`SELECT 
+				storage_tier,
+				COUNT(*) AS partitions,
+				ROUND(SUM(total_billable_bytes) / POW(1024, 3), 3) AS billable_gb
+			FROM INFORMATION_SCHEMA.PARTITIONS
+			GROUP BY storage_tier
+			ORDER BY storage_tier;`,
+		}
+
+		queries = append(queries, detailedQueries...)
+	}
+
+	return queries
+}
+
+// getCockroachStatsQueries returns queries for CockroachDB statistics, read from
+// crdb_internal and the range and zone configuration statements
+func getCockroachStatsQueries(detailed bool) []string {
+	// Basic queries
+	queries := []string{
+		// Database size
+		`SELECT 
+			count(*) AS ranges,
+			round(sum(range_size_mb), 2) AS database_size_mb
+		FROM [SHOW RANGES WITH DETAILS];`,
+
+		// Session statistics
+		`SELECT 
+			count(*) AS total_sessions,
+			sum(CASE WHEN active_queries <> '' THEN 1 ELSE 0 END) AS active_sessions
+		FROM crdb_internal.cluster_sessions;`,
+
+		// Table statistics
+		`SELECT 
+			t.schema_name,
+			t.name AS table_name,
+			s.estimated_row_count AS row_count
+		FROM crdb_internal.tables t
+		LEFT JOIN crdb_internal.table_row_statistics s ON s.table_id = t.table_id
+		WHERE t.database_name = current_database()
+		AND t.state = 'PUBLIC'
+		ORDER BY s.estimated_row_count DESC NULLS LAST
+		LIMIT 10;`,
+	}
+
+	// Add detailed queries if requested
+	if detailed {
+		detailedQueries := []string{
+			// Index statistics
+			`SELECT 
+				i.descriptor_name AS table_name,
+				i.index_name,
+				u.total_reads,
+				u.last_read
+			FROM crdb_internal.index_usage_statistics u
+			JOIN crdb_internal.table_indexes i
+				ON i.descriptor_id = u.table_id
+				AND i.index_id = u.index_id
+			ORDER BY u.total_reads DESC
+			LIMIT 10;`,
+
+			// Replicas and leases per store
+			`SELECT 
+				node_id,
+				store_id,
+				range_count AS replicas,
+				lease_count AS leases,
+				round(used / 1073741824.0, 2) AS used_gb,
+				round(available / 1073741824.0, 2) AS available_gb
+			FROM crdb_internal.kv_store_status
+			ORDER BY node_id, store_id;`,
+
+			// Zone configurations
+			`SELECT 
+				target,
+				raw_config_sql
+			FROM [SHOW ALL ZONE CONFIGURATIONS];`,
+		}
+
+		queries = append(queries, detailedQueries...)
+	}
+
+	return queries
+}
+
+// getYugabyteStatsQueries returns queries for YugabyteDB statistics: the tablet servers of
+// the universe and the tablets of each table. Row counts are the estimates of the last
+// ANALYZE, as the tablets keep no PostgreSQL pages to measure.
+func getYugabyteStatsQueries(detailed bool) []string {
+	// Basic queries
+	queries := []string{
+		// Database and universe
+		`SELECT 
+			current_database() AS database,
+			version() AS version,
+			yb_is_database_colocated() AS colocated,
+			(SELECT count(*) FROM yb_servers()) AS tablet_servers;`,
+
+		// Tablet servers
+		`SELECT 
+			host,
+			port,
+			node_type,
+			cloud,
+			region,
+			zone,
+			num_connections
+		FROM yb_servers()
+		ORDER BY cloud, region, zone, host;`,
+
+		// Connection statistics of the node serving this connection
+		`SELECT 
+			count(*) AS total_connections,
+			sum(CASE WHEN state = 'active' THEN 1 ELSE 0 END) AS active_connections,
+			sum(CASE WHEN state = 'idle' THEN 1 ELSE 0 END) AS idle_connections
+		FROM pg_stat_activity;`,
+
+		// Table statistics
+		`SELECT 
+			n.nspname AS schemaname,
+			c.relname AS table_name,
+			p.num_tablets,
+			CASE WHEN p.num_hash_key_columns > 0 THEN 'hash' ELSE 'range' END AS sharding,
+			p.is_colocated,
+			c.reltuples::bigint AS estimated_rows
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		CROSS JOIN LATERAL yb_table_properties(c.oid) p
+		WHERE c.relkind IN ('r', 'p')
+		AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+		ORDER BY p.num_tablets DESC, c.reltuples DESC
+		LIMIT 10;`,
+	}
+
+	// Add detailed queries if requested
+	if detailed {
+		detailedQueries := []string{
+			// Index statistics; a primary key is stored with its table and has no tablets
+			`SELECT 
+				ui.schemaname,
+				ui.relname AS table_name,
+				ui.indexrelname AS index_name,
+				p.num_tablets,
+				ui.idx_scan AS index_scans
+			FROM pg_stat_user_indexes ui
+			JOIN pg_index x ON x.indexrelid = ui.indexrelid
+			CROSS JOIN LATERAL yb_table_properties(ui.indexrelid) p
+			WHERE NOT x.indisprimary
+			ORDER BY ui.idx_scan DESC
+			LIMIT 10;`,
+
+			// Tablets hosted by the node serving this connection
+			`SELECT 
+				table_type,
+				count(DISTINCT table_id) AS tables,
+				count(*) AS tablets
+			FROM yb_local_tablets
+			GROUP BY table_type
+			ORDER BY table_type;`,
+
+			// Tablegroups that colocate tables
+			`SELECT 
+				p.tablegroup_oid,
+				count(*) AS tables,
+				string_agg(c.relname, ', ' ORDER BY c.relname) AS table_names
+			FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			CROSS JOIN LATERAL yb_table_properties(c.oid) p
+			WHERE c.relkind IN ('r', 'p')
+			AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+			AND p.is_colocated
+			GROUP BY p.tablegroup_oid;`,
+		}
+
+		queries = append(queries, detailedQueries...)
+	}
+
+	return queries
+}
+
+// getTiDBStatsQueries returns queries for TiDB statistics: the cluster, its TiKV regions and
+// the TiFlash replicas of the database
+func getTiDBStatsQueries(detailed bool) []string {
+	// Basic queries
+	queries := []string{
+		// Database size
+		`SELECT 
+			table_schema AS database_name,
+			ROUND(SUM(data_length + index_length) / 1024 / 1024, 2) AS size_mb
+		FROM information_schema.tables
+		WHERE table_schema = DATABASE()
+		GROUP BY table_schema;`,
+
+		// Cluster components
+		`SELECT 
+			type,
+			instance,
+			version,
+			start_time,
+			uptime
+		FROM information_schema.cluster_info
+		ORDER BY type, instance;`,
+
+		// Connection statistics
+		`SELECT 
+			instance,
+			COUNT(*) AS total_connections,
+			SUM(CASE WHEN command <> 'Sleep' THEN 1 ELSE 0 END) AS active_connections
+		FROM information_schema.cluster_processlist
+		GROUP BY instance;`,
+
+		// Table statistics
+		`SELECT 
+			table_name,
+			table_rows,
+			ROUND((data_length + index_length) / 1024 / 1024, 2) AS size_mb,
+			tidb_pk_type AS primary_key_type
+		FROM information_schema.tables
+		WHERE table_schema = DATABASE()
+		ORDER BY (data_length + index_length) DESC
+		LIMIT 10;`,
+
+		// Hottest regions by read and write traffic
+		`SELECT 
+			table_name,
+			index_name,
+			type,
+			region_count,
+			max_hot_degree,
+			ROUND(flow_bytes / 1024 / 1024, 2) AS flow_mb
+		FROM information_schema.tidb_hot_regions
+		WHERE db_name = DATABASE()
+		ORDER BY flow_bytes DESC
+		LIMIT 10;`,
+
+		// TiFlash replicas
+		`SELECT 
+			table_name,
+			replica_count,
+			location_labels,
+			available,
+			progress
+		FROM information_schema.tiflash_replica
+		WHERE table_schema = DATABASE()
+		ORDER BY table_name;`,
+	}
+
+	// Add detailed queries if requested
+	if detailed {
+		detailedQueries := []string{
+			// Regions per table
+			`SELECT 
+				table_name,
+				COUNT(DISTINCT region_id) AS regions,
+				ROUND(SUM(approximate_size), 2) AS approximate_size_mb,
+				SUM(approximate_keys) AS approximate_keys
+			FROM information_schema.tikv_region_status
+			WHERE db_name = DATABASE()
+			GROUP BY table_name
+			ORDER BY regions DESC
+			LIMIT 10;`,
+
+			// TiKV and TiFlash stores
+			`SELECT 
+				store_id,
+				address,
+				store_state_name,
+				label,
+				capacity,
+				available,
+				leader_count,
+				region_count
+			FROM information_schema.tikv_store_status
+			ORDER BY store_id;`,
+		}
+
+		queries = append(queries, detailedQueries...)
+	}
+
+	return queries
+}
+
+// getSpannerStatsQueries returns queries for Spanner statistics; the schema is counted from
+// INFORMATION_SCHEMA and sizes and workload come from the latest hour of SPANNER_SYS
+func getSpannerStatsQueries(detailed bool) []string {
+	// Basic queries
+	queries := []string{
+		// Object counts
+		`SELECT 
+			table_type,
+			COUNTIF(parent_table_name IS NULL) AS top_level,
+			COUNTIF(parent_table_name IS NOT NULL) AS interleaved,
+			COUNT(*) AS object_count
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE table_schema NOT IN ('INFORMATION_SCHEMA', 'SPANNER_SYS')
+		GROUP BY table_type
+		ORDER BY table_type;`,
+
+		// Largest tables and indexes
+		`SELECT 
+			table_name,
+			ROUND(used_bytes / POW(1024, 3), 3) AS used_gb,
+			ROUND(used_ssd_bytes / POW(1024, 3), 3) AS used_ssd_gb,
+			ROUND(used_hdd_bytes / POW(1024, 3), 3) AS used_hdd_gb
+		FROM SPANNER_SYS.TABLE_SIZES_STATS_1HOUR
+		WHERE interval_end = (SELECT MAX(interval_end) FROM SPANNER_SYS.TABLE_SIZES_STATS_1HOUR)
+		ORDER BY used_bytes DESC
+		LIMIT 20;`,
+
+		// Queries with the most CPU time
+		`SELECT 
+			text_fingerprint,
+			SUBSTR(text, 1, 200) AS query,
+			execution_count,
+			ROUND(avg_latency_seconds, 4) AS avg_latency_seconds,
+			ROUND(avg_cpu_seconds, 4) AS avg_cpu_seconds,
+			avg_rows,
+			avg_rows_scanned
+		FROM SPANNER_SYS.QUERY_STATS_TOP_HOUR
+		WHERE interval_end = (SELECT MAX(interval_end) FROM SPANNER_SYS.QUERY_STATS_TOP_HOUR)
+		ORDER BY avg_cpu_seconds * execution_count DESC
+		LIMIT 10;`,
+
+		// Transactions with the most commits and aborts
+		`SELECT 
+			fprint,
+			ARRAY_TO_STRING(write_constructive_columns, ', ') AS written_columns,
+			commit_attempt_count,
+			commit_abort_count,
+			ROUND(avg_commit_latency_seconds, 4) AS avg_commit_latency_seconds,
+			ROUND(avg_total_latency_seconds, 4) AS avg_total_latency_seconds
+		FROM SPANNER_SYS.TXN_STATS_TOP_HOUR
+		WHERE interval_end = (SELECT MAX(interval_end) FROM SPANNER_SYS.TXN_STATS_TOP_HOUR)
+		ORDER BY commit_attempt_count DESC
+		LIMIT 10;`,
+	}
+
+	// Add detailed queries if requested
+	if detailed {
+		detailedQueries := []string{
+			// Row ranges with the longest lock waits
+			`SELECT 
+				CAST(row_range_start_key AS STRING) AS row_range_start_key,
+				lock_wait_seconds
+			FROM SPANNER_SYS.LOCK_STATS_TOP_HOUR
+			WHERE interval_end = (SELECT MAX(interval_end) FROM SPANNER_SYS.LOCK_STATS_TOP_HOUR)
+			ORDER BY lock_wait_seconds DESC
+			LIMIT 10;`,
+
+			// Queries running for the longest time
+			`SELECT 
+				start_time,
+				text_fingerprint,
+				SUBSTR(text, 1, 200) AS query,
+				session_id
+			FROM SPANNER_SYS.OLDEST_ACTIVE_QUERIES
+			ORDER BY start_time
+			LIMIT 10;`,
+		}
+
+		queries = append(queries, detailedQueries...)
+	}
+
+	return queries
+}
+
+// getHANAStatsQueries returns queries for SAP HANA statistics: the system and its services,
+// and the tables of the user schemas by row count, size and column-store memory
+func getHANAStatsQueries(detailed bool) []string {
+	// Basic queries
+	queries := []string{
+		// Database version and uptime
+		`SELECT 
+			SYSTEM_ID AS system_id,
+			DATABASE_NAME AS database_name,
+			HOST AS host,
+			VERSION AS version,
+			START_TIME AS start_time,
+			SECONDS_BETWEEN(START_TIME, CURRENT_TIMESTAMP) AS uptime_seconds
+		FROM M_DATABASE;`,
+
+		// Memory of each service
+		`SELECT 
+			HOST AS host,
+			SERVICE_NAME AS service_name,
+			ROUND(TOTAL_MEMORY_USED_SIZE / 1024 / 1024 / 1024, 2) AS used_gb,
+			ROUND(EFFECTIVE_ALLOCATION_LIMIT / 1024 / 1024 / 1024, 2) AS allocation_limit_gb
+		FROM M_SERVICE_MEMORY
+		ORDER BY TOTAL_MEMORY_USED_SIZE DESC;`,
+
+		// Tables and column-store memory by schema
+		`SELECT 
+			t.SCHEMA_NAME AS schema_name,
+			COUNT(*) AS table_count,
+			SUM(t.RECORD_COUNT) AS row_count,
+			ROUND(SUM(t.TABLE_SIZE) / 1024 / 1024, 2) AS table_size_mb,
+			ROUND(SUM(cs.MEMORY_SIZE_IN_TOTAL) / 1024 / 1024, 2) AS column_store_memory_mb
+		FROM M_TABLES t
+		LEFT JOIN (
+			SELECT SCHEMA_NAME, TABLE_NAME, SUM(MEMORY_SIZE_IN_TOTAL) AS MEMORY_SIZE_IN_TOTAL
+			FROM M_CS_TABLES
+			GROUP BY SCHEMA_NAME, TABLE_NAME
+		) cs ON cs.SCHEMA_NAME = t.SCHEMA_NAME AND cs.TABLE_NAME = t.TABLE_NAME
+		WHERE ` + hanaUserSchemaFilter("t") + `
+		GROUP BY t.SCHEMA_NAME
+		ORDER BY table_size_mb DESC;`,
+
+		// Column-store tables holding the most memory
+		`SELECT 
+			SCHEMA_NAME AS schema_name,
+			TABLE_NAME AS table_name,
+			SUM(RECORD_COUNT) AS row_count,
+			ROUND(SUM(MEMORY_SIZE_IN_TOTAL) / 1024 / 1024, 2) AS memory_mb,
+			ROUND(SUM(MEMORY_SIZE_IN_DELTA) / 1024 / 1024, 2) AS delta_mb,
+			MIN(LOADED) AS loaded
+		FROM M_CS_TABLES t
+		WHERE ` + hanaUserSchemaFilter("t") + `
+		GROUP BY SCHEMA_NAME, TABLE_NAME
+		ORDER BY memory_mb DESC
+		LIMIT 20;`,
+
+		// Connections by status
+		`SELECT 
+			CONNECTION_STATUS AS connection_status,
+			COUNT(*) AS connection_count
+		FROM M_CONNECTIONS
+		WHERE CONNECTION_ID > 0
+		GROUP BY CONNECTION_STATUS
+		ORDER BY connection_count DESC;`,
+	}
+
+	// Add detailed queries if requested
+	if detailed {
+		detailedQueries := []string{
+			// CPU, memory and disk of each host
+			`SELECT 
+				HOST AS host,
+				ROUND(USED_PHYSICAL_MEMORY / 1024 / 1024 / 1024, 2) AS used_memory_gb,
+				ROUND(FREE_PHYSICAL_MEMORY / 1024 / 1024 / 1024, 2) AS free_memory_gb,
+				ROUND(INSTANCE_TOTAL_MEMORY_USED_SIZE / 1024 / 1024 / 1024, 2) AS instance_memory_gb,
+				TOTAL_CPU_USER_TIME AS cpu_user_ms,
+				TOTAL_CPU_SYSTEM_TIME AS cpu_system_ms
+			FROM M_HOST_RESOURCE_UTILIZATION;`,
+
+			// Statements with the most total execution time
+			`SELECT 
+				SUBSTR(STATEMENT_STRING, 1, 200) AS query,
+				EXECUTION_COUNT AS execution_count,
+				ROUND(TOTAL_EXECUTION_TIME / 1000, 2) AS total_ms,
+				ROUND(AVG_EXECUTION_TIME / 1000, 2) AS avg_ms,
+				TOTAL_RESULT_RECORD_COUNT AS total_rows
+			FROM M_SQL_PLAN_CACHE
+			ORDER BY TOTAL_EXECUTION_TIME DESC
+			LIMIT 10;`,
+		}
+
+		queries = append(queries, detailedQueries...)
+	}
+
+	return queries
+}
+
+// getFirebirdStatsQueries returns queries for Firebird statistics from MON$DATABASE and the
+// other monitoring tables. The gap between the next and the oldest active transaction shows
+// record versions that garbage collection cannot remove yet.
+func getFirebirdStatsQueries(detailed bool) []string {
+	// Basic queries
+	queries := []string{
+		// Engine, file and on-disk structure
+		`SELECT 
+			RDB$GET_CONTEXT('SYSTEM', 'ENGINE_VERSION') AS engine_version,
+			MON$DATABASE_NAME AS database_file,
+			MON$ODS_MAJOR || '.' || MON$ODS_MINOR AS ods_version,
+			MON$SQL_DIALECT AS sql_dialect,
+			MON$PAGE_SIZE AS page_size,
+			MON$PAGES AS pages,
+			ROUND(MON$PAGES * MON$PAGE_SIZE / 1024.0 / 1024.0, 2) AS size_mb,
+			MON$PAGE_BUFFERS AS page_buffers,
+			MON$FORCED_WRITES AS forced_writes,
+			MON$READ_ONLY AS read_only,
+			MON$CREATION_DATE AS created_at
+		FROM MON$DATABASE;`,
+
+		// Transaction markers and sweep
+		`SELECT 
+			MON$OLDEST_TRANSACTION AS oldest_interesting,
+			MON$OLDEST_ACTIVE AS oldest_active,
+			MON$OLDEST_SNAPSHOT AS oldest_snapshot,
+			MON$NEXT_TRANSACTION AS next_transaction,
+			MON$NEXT_TRANSACTION - MON$OLDEST_ACTIVE AS active_gap,
+			MON$SWEEP_INTERVAL AS sweep_interval
+		FROM MON$DATABASE;`,
+
+		// User objects by kind
+		`SELECT 'TABLE' AS object_type, COUNT(*) AS object_count FROM RDB$RELATIONS WHERE COALESCE(RDB$SYSTEM_FLAG, 0) = 0 AND RDB$VIEW_BLR IS NULL
+		UNION ALL SELECT 'VIEW', COUNT(*) FROM RDB$RELATIONS WHERE COALESCE(RDB$SYSTEM_FLAG, 0) = 0 AND RDB$VIEW_BLR IS NOT NULL
+		UNION ALL SELECT 'INDEX', COUNT(*) FROM RDB$INDICES WHERE COALESCE(RDB$SYSTEM_FLAG, 0) = 0
+		UNION ALL SELECT 'TRIGGER', COUNT(*) FROM RDB$TRIGGERS WHERE COALESCE(RDB$SYSTEM_FLAG, 0) = 0
+		UNION ALL SELECT 'PROCEDURE', COUNT(*) FROM RDB$PROCEDURES WHERE COALESCE(RDB$SYSTEM_FLAG, 0) = 0
+		UNION ALL SELECT 'GENERATOR', COUNT(*) FROM RDB$GENERATORS WHERE COALESCE(RDB$SYSTEM_FLAG, 0) = 0;`,
+
+		// Attachments by user and state
+		`SELECT 
+			TRIM(MON$USER) AS user_name,
+			CASE MON$STATE WHEN 1 THEN 'ACTIVE' ELSE 'IDLE' END AS state,
+			COUNT(*) AS attachments
+		FROM MON$ATTACHMENTS
+		WHERE MON$SYSTEM_FLAG = 0
+		GROUP BY MON$USER, MON$STATE
+		ORDER BY attachments DESC;`,
+	}
+
+	// Add detailed queries if requested
+	if detailed {
+		detailedQueries := []string{
+			// Oldest open transactions, which hold back garbage collection
+			`SELECT FIRST 10
+				t.MON$TRANSACTION_ID AS transaction_id,
+				TRIM(a.MON$USER) AS user_name,
+				TRIM(a.MON$REMOTE_ADDRESS) AS remote_address,
+				t.MON$TIMESTAMP AS started_at,
+				t.MON$ISOLATION_MODE AS isolation_mode,
+				t.MON$READ_ONLY AS read_only
+			FROM MON$TRANSACTIONS t
+			JOIN MON$ATTACHMENTS a ON a.MON$ATTACHMENT_ID = t.MON$ATTACHMENT_ID
+			ORDER BY t.MON$TRANSACTION_ID;`,
+
+			// Statements running now
+			`SELECT 
+				s.MON$STATEMENT_ID AS statement_id,
+				TRIM(a.MON$USER) AS user_name,
+				s.MON$TIMESTAMP AS started_at,
+				CAST(SUBSTRING(s.MON$SQL_TEXT FROM 1 FOR 200) AS VARCHAR(200)) AS query
+			FROM MON$STATEMENTS s
+			JOIN MON$ATTACHMENTS a ON a.MON$ATTACHMENT_ID = s.MON$ATTACHMENT_ID
+			WHERE s.MON$STATE = 1
+			AND s.MON$ATTACHMENT_ID <> CURRENT_CONNECTION
+			ORDER BY s.MON$TIMESTAMP;`,
+		}
+
+		queries = append(queries, detailedQueries...)
+	}
+
+	return queries
+}
+
+// getGreenplumStatsQueries returns queries for Greenplum statistics: the segments of the
+// cluster, database and table sizes summed over them, and how tables are distributed
+func getGreenplumStatsQueries(detailed bool) []string {
+	// Basic queries
+	queries := []string{
+		// Database version
+		"SELECT version() AS version;",
+
+		// Coordinator, segments and mirrors with their state
+		`SELECT 
+			content AS segment,
+			CASE role WHEN 'p' THEN 'primary' ELSE 'mirror' END AS role,
+			preferred_role = role AS in_preferred_role,
+			CASE status WHEN 'u' THEN 'up' ELSE 'down' END AS status,
+			CASE mode WHEN 's' THEN 'synchronized' WHEN 'n' THEN 'not synchronized' ELSE mode::text END AS mode,
+			hostname,
+			port
+		FROM gp_segment_configuration
+		ORDER BY content, role;`,
+
+		// Database size over all segments
+		`SELECT 
+			sodddatname AS database_name,
+			pg_size_pretty(sodddatsize::bigint) AS size
+		FROM gp_toolkit.gp_size_of_database
+		WHERE sodddatname = current_database();`,
+
+		// Tables by distribution policy
+		`SELECT 
+			CASE
+				WHEN p.policytype = 'r' THEN 'replicated'
+				WHEN array_length(p.distkey::int2[], 1) IS NULL THEN 'random'
+				ELSE 'hash'
+			END AS distribution,
+			COUNT(*) AS table_count
+		FROM gp_distribution_policy p
+		JOIN pg_class c ON c.oid = p.localoid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname NOT IN ('pg_catalog', 'information_schema', 'gp_toolkit')
+		GROUP BY 1
+		ORDER BY 2 DESC;`,
+
+		// Largest tables over all segments
+		`SELECT 
+			sotdschemaname AS schema_name,
+			sotdtablename AS table_name,
+			pg_size_pretty((sotdsize + sotdtoastsize + sotdadditionalsize)::bigint) AS total_size
+		FROM gp_toolkit.gp_size_of_table_disk
+		ORDER BY sotdsize + sotdtoastsize + sotdadditionalsize DESC
+		LIMIT 10;`,
+	}
+
+	// Add detailed queries if requested
+	if detailed {
+		detailedQueries := []string{
+			// Tables with the most skew over the segments
+			`SELECT 
+				skcnamespace AS schema_name,
+				skcrelname AS table_name,
+				skccoeff AS skew_coefficient
+			FROM gp_toolkit.gp_skew_coefficients
+			ORDER BY skccoeff DESC
+			LIMIT 10;`,
+
+			// Tables whose pages hold many more rows than the statistics expect
+			`SELECT 
+				bdinspname AS schema_name,
+				bdirelname AS table_name,
+				bdirelpages AS pages,
+				bdiexppages AS expected_pages,
+				bdidiag AS diagnosis
+			FROM gp_toolkit.gp_bloat_diag
+			ORDER BY bdirelpages - bdiexppages DESC
+			LIMIT 10;`,
+		}
+
+		queries = append(queries, detailedQueries...)
+	}
+
+	return queries
+}
+
+// getDatabricksStatsQueries returns queries for Databricks statistics: the session and
+// version, the Unity Catalog catalogs, and the tables of the catalog of the connection
+func getDatabricksStatsQueries(detailed bool) []string {
+	// Basic queries
+	queries := []string{
+		// Session and version
+		`SELECT 
+			current_catalog() AS catalog,
+			current_schema() AS schema,
+			current_user() AS user,
+			current_version().dbsql_version AS dbsql_version,
+			current_version().dbr_version AS dbr_version;`,
+
+		// Catalogs of the metastore
+		`SELECT 
+			catalog_name,
+			catalog_owner,
+			comment,
+			created
+		FROM system.information_schema.catalogs
+		WHERE catalog_name <> 'system'
+		ORDER BY catalog_name;`,
+
+		// Tables and views of the catalog of the connection
+		`SELECT 
+			table_schema,
+			count_if(table_type NOT IN ('VIEW', 'MATERIALIZED_VIEW')) AS tables,
+			count_if(table_type IN ('VIEW', 'MATERIALIZED_VIEW')) AS views
+		FROM information_schema.tables
+		WHERE table_schema <> 'information_schema'
+		GROUP BY table_schema
+		ORDER BY table_schema;`,
+
+		// Table types and storage formats
+		`SELECT 
+			table_type,
+			data_source_format,
+			COUNT(*) AS tables
+		FROM information_schema.tables
+		WHERE table_schema <> 'information_schema'
+		GROUP BY table_type, data_source_format
+		ORDER BY tables DESC;`,
+	}
+
+	// Add detailed queries if requested
+	if detailed {
+		detailedQueries := []string{
+			// Recently changed tables
+			`SELECT 
+				table_schema,
+				table_name,
+				table_type,
+				last_altered,
+				last_altered_by
+			FROM information_schema.tables
+			WHERE table_schema <> 'information_schema'
+			ORDER BY last_altered DESC
+			LIMIT 20;`,
+
+			// Recent statements, from the query history system table
+			`SELECT 
+				statement_id,
+				executed_by,
+				execution_status,
+				start_time,
+				total_duration_ms,
+				substr(statement_text, 1, 200) AS statement_text
+			FROM system.query.history
+			ORDER BY start_time DESC
+			LIMIT 20;`,
+		}
+
+		queries = append(queries, detailedQueries...)
+	}
+
+	return queries
+}
+
+// getTrinoStatsQueries returns queries for Trino statistics: the cluster and its catalogs,
+// and the tables of the default catalog
+func getTrinoStatsQueries(detailed bool) []string {
+	// Basic queries
+	queries := []string{
+		// Cluster nodes
+		`SELECT 
+			node_id,
+			http_uri,
+			node_version,
+			coordinator,
+			state
+		FROM system.runtime.nodes
+		ORDER BY coordinator DESC, node_id;`,
+
+		// Catalogs and their connectors
+		`SELECT 
+			catalog_name,
+			connector_name
+		FROM system.metadata.catalogs
+		ORDER BY catalog_name;`,
+
+		// Query statistics
+		`SELECT 
+			state,
+			COUNT(*) AS queries
+		FROM system.runtime.queries
+		GROUP BY state
+		ORDER BY state;`,
+
+		// Tables of the default catalog
+		`SELECT 
+			table_schema,
+			count_if(table_type = 'BASE TABLE') AS tables,
+			count_if(table_type = 'VIEW') AS views
+		FROM information_schema.tables
+		WHERE table_schema <> 'information_schema'
+		GROUP BY table_schema
+		ORDER BY table_schema;`,
+	}
+
+	// Add detailed queries if requested
+	if detailed {
+		detailedQueries := []string{
+			// Queued and running queries
+			`SELECT 
+				query_id,
+				state,
+				"user",
+				source,
+				queued_time_ms,
+				created,
+				substr(query, 1, 200) AS query
+			FROM system.runtime.queries
+			WHERE state IN ('QUEUED', 'PLANNING', 'STARTING', 'RUNNING')
+			ORDER BY created
+			LIMIT 20;`,
+
+			// Running tasks per node
+			`SELECT 
+				node_id,
+				COUNT(*) AS tasks,
+				SUM(running_splits) AS running_splits,
+				SUM(queued_splits) AS queued_splits
+			FROM system.runtime.tasks
+			WHERE state = 'RUNNING'
+			GROUP BY node_id
+			ORDER BY node_id;`,
+		}
+
+		queries = append(queries, detailedQueries...)
+	}
+
+	return queries
+}
@@ -0,0 +1,363 @@
+package mcp
+
+import (
+	"strings"
+)
+
+// postgresLockStrength orders the PostgreSQL table lock modes DDL takes, weakest first
+var postgresLockStrength = map[string]int{
+	"SHARE UPDATE EXCLUSIVE": 1,
+	"SHARE":                  2,
+	"SHARE ROW EXCLUSIVE":    3,
+	"EXCLUSIVE":              4,
+	"ACCESS EXCLUSIVE":       5,
+}
+
+// mysqlLockStrength orders the MySQL metadata lock types DDL takes, weakest first
+var mysqlLockStrength = map[string]int{
+	"SHARED_UPGRADABLE": 1,
+	"EXCLUSIVE":         2,
+}
+
+// ddlLockEffects describes what other sessions can no longer do while a lock is held
+var ddlLockEffects = map[string]string{
+	"postgres:ACCESS EXCLUSIVE":       "blocks all reads and writes",
+	"postgres:EXCLUSIVE":              "blocks all writes, allows plain reads",
+	"postgres:SHARE ROW EXCLUSIVE":    "blocks writes and other schema changes, allows reads",
+	"postgres:SHARE":                  "blocks writes, allows reads",
+	"postgres:SHARE UPDATE EXCLUSIVE": "blocks other schema changes and VACUUM, allows reads and writes",
+	"mysql:EXCLUSIVE":                 "blocks all access while held and first waits for every open transaction that used the table",
+	"mysql:SHARED_UPGRADABLE":         "blocks other schema changes, allows reads and writes",
+}
+
+// volatileDefaultFunctions make ADD COLUMN ... DEFAULT rewrite the whole table on PostgreSQL
+var volatileDefaultFunctions = []string{"random", "gen_random_uuid", "uuid_generate_v4", "clock_timestamp", "timeofday", "nextval"}
+
+// ddlLock is a lock a DDL statement takes on a relation
+type ddlLock struct {
+	Relation string
+	Mode     string
+}
+
+// ddlLockPlan lists the locks a DDL statement needs and what to watch out for
+type ddlLockPlan struct {
+	Dialect    string
+	Locks      []ddlLock
+	Notes      []string
+	Concurrent bool // the statement uses CONCURRENTLY and cannot run inside a transaction
+	Recognized bool
+}
+
+// effect describes what a lock of the plan blocks
+func (p *ddlLockPlan) effect(mode string) string {
+	return ddlLockEffects[p.Dialect+":"+mode]
+}
+
+// relations returns the names of the locked relations
+func (p *ddlLockPlan) relations() []string {
+	names := make([]string, len(p.Locks))
+	for i, lock := range p.Locks {
+		names[i] = lock.Relation
+	}
+	return names
+}
+
+// lock records a lock, keeping the strongest mode per relation
+func (p *ddlLockPlan) lock(relation, mode string) {
+	strength := postgresLockStrength
+	if p.Dialect == "mysql" {
+		strength = mysqlLockStrength
+	}
+	for i, l := range p.Locks {
+		if strings.EqualFold(l.Relation, relation) {
+			if strength[mode] > strength[l.Mode] {
+				p.Locks[i].Mode = mode
+			}
+			return
+		}
+	}
+	p.Locks = append(p.Locks, ddlLock{Relation: relation, Mode: mode})
+}
+
+// note records a warning once
+func (p *ddlLockPlan) note(text string) {
+	for _, n := range p.Notes {
+		if n == text {
+			return
+		}
+	}
+	p.Notes = append(p.Notes, text)
+}
+
+// exclusive returns the strongest lock mode of the dialect
+func (p *ddlLockPlan) exclusive() string {
+	if p.Dialect == "mysql" {
+		return "EXCLUSIVE"
+	}
+	return "ACCESS EXCLUSIVE"
+}
+
+// referenceLock returns the lock a foreign key takes on the referenced table
+func (p *ddlLockPlan) referenceLock() string {
+	if p.Dialect == "mysql" {
+		return "SHARED_UPGRADABLE"
+	}
+	return "SHARE ROW EXCLUSIVE"
+}
+
+// analyzeDDLLocks works out the locks a single DDL statement takes from its text alone
+func analyzeDDLLocks(statement, dialect string) (*ddlLockPlan, error) {
+	tokens, err := tokenizeSQL(statement, dialect)
+	if err != nil {
+		return nil, err
+	}
+	toks := significantTokens(tokens)
+	plan := &ddlLockPlan{Dialect: dialect, Recognized: true}
+	plan.Concurrent = containsWord(toks, "CONCURRENTLY")
+	p := &ddlParser{toks: toks}
+
+	switch {
+	case p.accept("CREATE"):
+		p.accept("OR", "REPLACE")
+		p.accept("UNIQUE")
+		switch {
+		case p.accept("INDEX"):
+			concurrently := p.accept("CONCURRENTLY")
+			p.accept("IF", "NOT", "EXISTS")
+			if !p.peek("ON") {
+				p.name()
+			}
+			if !p.accept("ON") {
+				plan.Recognized = false
+				break
+			}
+			p.accept("ONLY")
+			schema, table, _ := p.name()
+			mode := "SHARE"
+			switch {
+			case dialect == "mysql":
+				mode = "EXCLUSIVE"
+				plan.note("An in-place index build holds the exclusive metadata lock only briefly at the start and end; create_index adds ALGORITHM=INPLACE, LOCK=NONE to make sure of it.")
+			case concurrently:
+				mode = "SHARE UPDATE EXCLUSIVE"
+			default:
+				plan.note("The index is built while writes are blocked; CREATE INDEX CONCURRENTLY (or create_index) avoids that.")
+			}
+			plan.lock(qualifiedName(schema, table), mode)
+		case p.accept("TRIGGER"):
+			for !p.done() && !p.accept("ON") {
+				p.i++
+			}
+			schema, table, ok := p.name()
+			if !ok {
+				plan.Recognized = false
+				break
+			}
+			plan.lock(qualifiedName(schema, table), "SHARE ROW EXCLUSIVE")
+		case p.peek("TABLE") || p.peek("TEMPORARY") || p.peek("TEMP") || p.peek("UNLOGGED"):
+			plan.note("Creating a table takes no locks on existing tables other than the ones it references.")
+		default:
+			plan.Recognized = false
+		}
+	case p.accept("DROP"):
+		switch {
+		case p.accept("TABLE"):
+			p.accept("IF", "EXISTS")
+			for _, name := range p.nameList() {
+				plan.lock(name, plan.exclusive())
+			}
+		case p.accept("INDEX"):
+			concurrently := p.accept("CONCURRENTLY")
+			p.accept("IF", "EXISTS")
+			schema, name, _ := p.name()
+			relation := qualifiedName(schema, name)
+			if p.accept("ON") {
+				// MySQL names the table
+				tableSchema, table, _ := p.name()
+				relation = qualifiedName(tableSchema, table)
+			}
+			mode := plan.exclusive()
+			if concurrently {
+				mode = "SHARE UPDATE EXCLUSIVE"
+			}
+			plan.lock(relation, mode)
+		default:
+			plan.Recognized = false
+		}
+	case p.accept("TRUNCATE"):
+		p.accept("TABLE")
+		p.accept("ONLY")
+		for _, name := range p.nameList() {
+			plan.lock(name, plan.exclusive())
+		}
+	case p.accept("RENAME", "TABLE"):
+		for {
+			schema, from, ok := p.name()
+			if !ok || !p.accept("TO") {
+				break
+			}
+			toSchema, to, _ := p.name()
+			plan.lock(qualifiedName(schema, from), "EXCLUSIVE")
+			plan.lock(qualifiedName(toSchema, to), "EXCLUSIVE")
+			if !p.accept(",") {
+				break
+			}
+		}
+	case p.accept("ALTER", "INDEX"):
+		p.accept("IF", "EXISTS")
+		schema, name, _ := p.name()
+		mode := plan.exclusive()
+		if p.peek("RENAME") || p.peek("SET") || p.peek("RESET") {
+			mode = "SHARE UPDATE EXCLUSIVE"
+		}
+		plan.lock(qualifiedName(schema, name), mode)
+	case p.accept("ALTER", "TABLE"):
+		p.accept("IF", "EXISTS")
+		p.accept("ONLY")
+		schema, name, ok := p.name()
+		if !ok {
+			plan.Recognized = false
+			break
+		}
+		table := qualifiedName(schema, name)
+		for _, action := range splitArgs(p.rest()) {
+			if dialect == "mysql" {
+				plan.lock(table, "EXCLUSIVE")
+				analyzeMySQLAlterAction(plan, action)
+			} else {
+				analyzePostgresAlterAction(plan, table, action)
+			}
+		}
+		if dialect == "mysql" {
+			plan.note("In-place changes hold the exclusive metadata lock only briefly at the start and end; changes that need ALGORITHM=COPY also block writes for the whole table copy. Add ALGORITHM=INPLACE, LOCK=NONE to make the statement fail instead of copying.")
+		}
+	default:
+		plan.Recognized = false
+	}
+
+	// Foreign keys lock the referenced tables too
+	for i, tok := range toks {
+		if tok.is("REFERENCES") {
+			ref := &ddlParser{toks: toks, i: i + 1}
+			if schema, table, ok := ref.name(); ok {
+				plan.lock(qualifiedName(schema, table), plan.referenceLock())
+			}
+		}
+	}
+	return plan, nil
+}
+
+// analyzePostgresAlterAction records the lock and caveats of one ALTER TABLE action
+func analyzePostgresAlterAction(plan *ddlLockPlan, table string, action []sqlToken) {
+	p := &ddlParser{toks: action}
+	notValid := containsWord(action, "VALID") && containsWord(action, "NOT")
+	validateNote := "Adding the constraint checks every existing row while holding the lock; add it NOT VALID and run VALIDATE CONSTRAINT separately."
+
+	switch {
+	case p.accept("ADD"):
+		if p.accept("CONSTRAINT") {
+			p.name()
+		}
+		switch {
+		case p.peek("FOREIGN"):
+			plan.lock(table, "SHARE ROW EXCLUSIVE")
+			if !notValid {
+				plan.note(validateNote)
+			}
+		case p.peek("CHECK"):
+			plan.lock(table, "ACCESS EXCLUSIVE")
+			if !notValid {
+				plan.note(validateNote)
+			}
+		case p.peek("PRIMARY") || p.peek("UNIQUE") || p.peek("EXCLUDE"):
+			plan.lock(table, "ACCESS EXCLUSIVE")
+			if !containsWord(action, "USING") {
+				plan.note("The constraint builds its index while holding the lock; build the index first with CREATE UNIQUE INDEX CONCURRENTLY and add the constraint USING INDEX.")
+			}
+		default:
+			plan.lock(table, "ACCESS EXCLUSIVE")
+			if containsWord(action, volatileDefaultFunctions...) {
+				plan.note("The new column has a volatile default, which rewrites the whole table while holding the lock.")
+			}
+		}
+	case p.accept("VALIDATE"):
+		plan.lock(table, "SHARE UPDATE EXCLUSIVE")
+	case p.accept("ALTER"):
+		p.accept("COLUMN")
+		p.name()
+		switch {
+		case p.peek("TYPE") || p.accept("SET", "DATA"):
+			plan.lock(table, "ACCESS EXCLUSIVE")
+			plan.note("Changing a column type rewrites the table and its indexes while holding the lock, unless the new type is binary compatible.")
+		case p.accept("SET", "NOT", "NULL"):
+			plan.lock(table, "ACCESS EXCLUSIVE")
+			plan.note("SET NOT NULL scans the whole table while holding the lock; a validated CHECK (col IS NOT NULL) constraint lets it skip the scan.")
+		case p.peek("SET") && len(p.rest()) > 1 && (p.rest()[1].is("STATISTICS") || p.rest()[1].is("(")):
+			plan.lock(table, "SHARE UPDATE EXCLUSIVE")
+		case p.peek("RESET"):
+			plan.lock(table, "SHARE UPDATE EXCLUSIVE")
+		default:
+			plan.lock(table, "ACCESS EXCLUSIVE")
+		}
+	case p.peek("SET") && len(p.rest()) > 1 && p.rest()[1].is("("), p.peek("RESET"), p.accept("CLUSTER", "ON"), p.accept("SET", "WITHOUT", "CLUSTER"):
+		plan.lock(table, "SHARE UPDATE EXCLUSIVE")
+	case p.peek("ENABLE") || p.peek("DISABLE"):
+		plan.lock(table, "SHARE ROW EXCLUSIVE")
+	case p.accept("ATTACH", "PARTITION"):
+		plan.lock(table, "SHARE UPDATE EXCLUSIVE")
+		if schema, partition, ok := p.name(); ok {
+			plan.lock(qualifiedName(schema, partition), "ACCESS EXCLUSIVE")
+		}
+	case p.accept("DETACH", "PARTITION"):
+		mode := "ACCESS EXCLUSIVE"
+		if containsWord(action, "CONCURRENTLY") {
+			mode = "SHARE UPDATE EXCLUSIVE"
+		}
+		plan.lock(table, mode)
+	case p.accept("SET", "TABLESPACE"):
+		plan.lock(table, "ACCESS EXCLUSIVE")
+		plan.note("Moving the table to another tablespace copies it while holding the lock.")
+	default:
+		plan.lock(table, "ACCESS EXCLUSIVE")
+	}
+}
+
+// analyzeMySQLAlterAction records the caveats of one MySQL ALTER TABLE action
+func analyzeMySQLAlterAction(plan *ddlLockPlan, action []sqlToken) {
+	p := &ddlParser{toks: action}
+	switch {
+	case p.accept("MODIFY"), p.accept("CHANGE"):
+		plan.note("Changing a column definition other than its name or default usually rebuilds the table with ALGORITHM=COPY, blocking writes for the whole rebuild.")
+	case p.accept("ADD"):
+		if p.accept("CONSTRAINT") {
+			p.name()
+		}
+		if p.peek("FOREIGN") && !containsWord(action, "FOREIGN_KEY_CHECKS") {
+			plan.note("Adding a foreign key in place requires foreign_key_checks=0; otherwise MySQL copies the table.")
+		}
+		if p.peek("PRIMARY") {
+			plan.note("Adding a primary key rebuilds the table.")
+		}
+	case p.accept("DROP", "PRIMARY"):
+		plan.note("Dropping the primary key rebuilds the table with ALGORITHM=COPY.")
+	}
+}
+
+// significantTokens drops whitespace and comments
+func significantTokens(tokens []sqlToken) []sqlToken {
+	significant := make([]sqlToken, 0, len(tokens))
+	for _, tok := range tokens {
+		if tok.kind != sqlSpace && tok.kind != sqlComment && !tok.is(";") {
+			significant = append(significant, tok)
+		}
+	}
+	return significant
+}
+
+// qualifiedName joins an optional schema and a name
+func qualifiedName(schema, name string) string {
+	if schema == "" {
+		return name
+	}
+	return schema + "." + name
+}
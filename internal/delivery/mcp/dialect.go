@@ -0,0 +1,1084 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect holds the engine-specific SQL used by the tools, so a handler looks up the
+// dialect of its database once instead of switching on the database type. Adding an
+// engine means implementing this interface and registering it in dialects; embedding
+// unsupportedAdmin leaves out the administration tools until the engine implements them.
+type Dialect interface {
+	adminDialect
+
+	// Name returns the database type the dialect serves, in lower case
+	Name() string
+	// QuoteIdent quotes a single unqualified identifier
+	QuoteIdent(ident string) string
+	// QuoteLiteral quotes a string literal for statements that cannot take bound parameters
+	QuoteLiteral(value string) string
+	// Placeholder returns the placeholder of the n-th bound parameter, starting at 1
+	Placeholder(n int) string
+	// RandomFunc returns the expression that orders rows randomly
+	RandomFunc() string
+	// LimitClause returns the clause that caps a result at n rows
+	LimitClause(n int) string
+	// PageClause returns the clause that skips offset rows and caps the rest at limit rows
+	PageClause(limit, offset int) string
+	// FullJoin reports whether the engine runs FULL OUTER JOIN
+	FullJoin() bool
+	// ExplainQuery returns the statement that explains a query as JSON, or an empty string
+	// when the engine cannot explain queries
+	ExplainQuery(query string) string
+	// PlanFormat returns how to read the output of ExplainQuery, or false when the plans of the
+	// engine are not parsed
+	PlanFormat() (planFormat, bool)
+	// IndexQuery returns the query listing indexes, optionally of one table
+	IndexQuery(tableName string, detailed bool) string
+	// ConstraintQuery returns the query listing constraints, optionally of one table and type
+	ConstraintQuery(tableName, constraintType string) string
+	// TableStatsSections returns the sections of table statistics, cheap ones first
+	TableStatsSections(tableName string) []tableStatsSection
+	// DatabaseStatsQueries returns the queries that describe the whole database
+	DatabaseStatsQueries(detailed bool) []string
+	// SchemaQuery returns the query listing schemas, optionally one by name
+	SchemaQuery(schemaName string, includeSystemSchemas bool) string
+	// ViewQuery returns the query listing views, optionally one by name
+	ViewQuery(viewName string, includeDefinition bool) string
+	// TypeQuery returns the query listing custom data types, or false when the engine has none
+	TypeQuery(typeName string) (string, bool)
+	// FunctionQuery returns the query listing user-defined functions and procedures,
+	// optionally one by name, or false when the engine has none
+	FunctionQuery(functionName string, includeSource bool) (string, bool)
+	// SequenceQuery returns the query listing sequences with their owning columns, optionally
+	// of one schema, or false when the engine has none
+	SequenceQuery(schema string) (string, bool)
+	// ExtensionQuery returns the query listing installed and available extensions, or false
+	// when the engine has no extensions
+	ExtensionQuery() (string, bool)
+	// SchemaMetadataQueries returns the queries that load tables, keys and indexes of a schema;
+	// an empty schema selects the engine's default
+	SchemaMetadataQueries(schema string) (string, schemaMetadataQueries)
+	// EnforcesKeys reports whether primary and foreign keys are enforced and backed by
+	// indexes, which the key and index checks of review_schema assume
+	EnforcesKeys() bool
+}
+
+// dialects holds the supported dialects by database type
+var dialects = map[string]Dialect{
+	"postgres":    postgresDialect{},
+	"cockroachdb": cockroachDialect{},
+	"yugabyte":    yugabyteDialect{},
+	"greenplum":   greenplumDialect{},
+	"mysql":       mysqlDialect{},
+	"tidb":        tidbDialect{},
+	"sqlite":      sqliteDialect{},
+	"clickhouse":  clickhouseDialect{},
+	"bigquery":    bigqueryDialect{},
+	"spanner":     spannerDialect{},
+	"hana":        hanaDialect{},
+	"firebird":    firebirdDialect{},
+	"trino":       trinoDialect{},
+	"databricks":  databricksDialect{},
+}
+
+// lookupDialect returns the dialect of a database type, or false when the type is not supported
+func lookupDialect(dbType string) (Dialect, bool) {
+	dialect, ok := dialects[strings.ToLower(dbType)]
+	return dialect, ok
+}
+
+// dialectFor returns the dialect of a database type, falling back to MySQL rules for
+// helpers that only quote or bind and have always treated unknown types that way
+func dialectFor(dbType string) Dialect {
+	if dialect, ok := lookupDialect(dbType); ok {
+		return dialect
+	}
+	return mysqlDialect{}
+}
+
+// limitOffset returns the LIMIT and OFFSET clause most engines share
+func limitOffset(limit, offset int) string {
+	if offset == 0 {
+		return fmt.Sprintf("LIMIT %d", limit)
+	}
+	return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+}
+
+// postgresDialect is the PostgreSQL dialect
+type postgresDialect struct {
+	postgresAdmin
+}
+
+func (postgresDialect) Name() string { return "postgres" }
+
+func (postgresDialect) QuoteIdent(ident string) string {
+	return "\"" + strings.Replace(ident, "\"", "\"\"", -1) + "\""
+}
+
+func (postgresDialect) QuoteLiteral(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
+
+func (postgresDialect) Placeholder(n int) string { return fmt.Sprintf("$%d", n) }
+
+func (postgresDialect) RandomFunc() string { return "RANDOM()" }
+
+func (postgresDialect) LimitClause(n int) string { return fmt.Sprintf("LIMIT %d", n) }
+
+func (postgresDialect) PageClause(limit, offset int) string { return limitOffset(limit, offset) }
+
+func (postgresDialect) FullJoin() bool { return true }
+
+func (postgresDialect) ExplainQuery(query string) string { return "EXPLAIN (FORMAT JSON) " + query }
+
+func (postgresDialect) PlanFormat() (planFormat, bool) { return postgresPlanFormat, true }
+
+func (postgresDialect) IndexQuery(tableName string, detailed bool) string {
+	return getPostgresIndexesQuery(tableName, detailed)
+}
+
+func (postgresDialect) ConstraintQuery(tableName, constraintType string) string {
+	return getPostgresConstraintsQuery(tableName, constraintType)
+}
+
+func (postgresDialect) TableStatsSections(tableName string) []tableStatsSection {
+	return getPostgresTableStatsSections(tableName)
+}
+
+func (postgresDialect) DatabaseStatsQueries(detailed bool) []string {
+	return getPostgresStatsQueries(detailed)
+}
+
+func (postgresDialect) SchemaQuery(schemaName string, includeSystemSchemas bool) string {
+	return getPostgresSchemasQuery(schemaName, includeSystemSchemas)
+}
+
+func (postgresDialect) ViewQuery(viewName string, includeDefinition bool) string {
+	return getPostgresViewsQuery(viewName, includeDefinition)
+}
+
+func (postgresDialect) TypeQuery(typeName string) (string, bool) {
+	return getPostgresTypesQuery(typeName), true
+}
+
+func (postgresDialect) FunctionQuery(functionName string, includeSource bool) (string, bool) {
+	return getPostgresFunctionsQuery(functionName, includeSource), true
+}
+
+func (postgresDialect) SequenceQuery(schema string) (string, bool) {
+	return getPostgresSequencesQuery(schema), true
+}
+
+func (postgresDialect) ExtensionQuery() (string, bool) {
+	return getPostgresExtensionsQuery(), true
+}
+
+func (postgresDialect) SchemaMetadataQueries(schema string) (string, schemaMetadataQueries) {
+	if schema == "" {
+		schema = "public"
+	}
+	return schema, getPostgresSchemaMetadataQueries(schema)
+}
+
+func (postgresDialect) EnforcesKeys() bool { return true }
+
+// cockroachDialect is the CockroachDB dialect. CockroachDB speaks the PostgreSQL dialect and
+// serves its catalog, but keeps its statistics in crdb_internal rather than pg_stat_*.
+type cockroachDialect struct {
+	postgresDialect
+	unsupportedAdmin
+}
+
+func (cockroachDialect) Name() string { return "cockroachdb" }
+
+// ExplainQuery returns an empty statement; CockroachDB's EXPLAIN has no JSON format
+func (cockroachDialect) ExplainQuery(string) string { return "" }
+
+// PlanFormat reports false; CockroachDB plans have their own shape
+func (cockroachDialect) PlanFormat() (planFormat, bool) { return planFormat{}, false }
+
+func (cockroachDialect) TableStatsSections(tableName string) []tableStatsSection {
+	return getCockroachTableStatsSections(tableName)
+}
+
+func (cockroachDialect) DatabaseStatsQueries(detailed bool) []string {
+	return getCockroachStatsQueries(detailed)
+}
+
+// ExtensionQuery reports false; CockroachDB builds in what PostgreSQL loads as extensions
+func (cockroachDialect) ExtensionQuery() (string, bool) { return "", false }
+
+// yugabyteDialect is the YugabyteDB dialect. Its YSQL API speaks the PostgreSQL dialect and
+// serves its catalog, but tables live in DocDB tablets, so pg_relation_size and the vacuum
+// statistics say nothing about them; sizes and distribution come from yb_table_properties,
+// yb_servers and yb_local_tablets instead.
+type yugabyteDialect struct {
+	postgresDialect
+	unsupportedAdmin
+}
+
+func (yugabyteDialect) Name() string { return "yugabyte" }
+
+func (yugabyteDialect) TableStatsSections(tableName string) []tableStatsSection {
+	return getYugabyteTableStatsSections(tableName)
+}
+
+func (yugabyteDialect) DatabaseStatsQueries(detailed bool) []string {
+	return getYugabyteStatsQueries(detailed)
+}
+
+// greenplumDialect is the Greenplum dialect. Its coordinator speaks the PostgreSQL dialect
+// and serves the catalog, but the rows of a table are spread over the segments, where the
+// PostgreSQL size functions called on the coordinator see none of them; distribution, skew
+// and sizes come from gp_distribution_policy, the gp_toolkit views and gp_dist_random.
+type greenplumDialect struct {
+	postgresDialect
+	unsupportedAdmin
+}
+
+func (greenplumDialect) Name() string { return "greenplum" }
+
+func (greenplumDialect) TableStatsSections(tableName string) []tableStatsSection {
+	return getGreenplumTableStatsSections(tableName)
+}
+
+func (greenplumDialect) DatabaseStatsQueries(detailed bool) []string {
+	return getGreenplumStatsQueries(detailed)
+}
+
+// mysqlDialect is the MySQL dialect
+type mysqlDialect struct {
+	mysqlAdmin
+}
+
+func (mysqlDialect) Name() string { return "mysql" }
+
+func (mysqlDialect) QuoteIdent(ident string) string {
+	return "`" + strings.Replace(ident, "`", "``", -1) + "`"
+}
+
+func (mysqlDialect) QuoteLiteral(value string) string {
+	value = strings.Replace(value, "'", "''", -1)
+	return "'" + strings.Replace(value, "\\", "\\\\", -1) + "'"
+}
+
+func (mysqlDialect) Placeholder(int) string { return "?" }
+
+func (mysqlDialect) RandomFunc() string { return "RAND()" }
+
+func (mysqlDialect) LimitClause(n int) string { return fmt.Sprintf("LIMIT %d", n) }
+
+func (mysqlDialect) PageClause(limit, offset int) string { return limitOffset(limit, offset) }
+
+// FullJoin returns false; MySQL has no FULL OUTER JOIN
+func (mysqlDialect) FullJoin() bool { return false }
+
+func (mysqlDialect) ExplainQuery(query string) string { return "EXPLAIN FORMAT=JSON " + query }
+
+func (mysqlDialect) PlanFormat() (planFormat, bool) { return mysqlPlanFormat, true }
+
+func (mysqlDialect) IndexQuery(tableName string, detailed bool) string {
+	return getMySQLIndexesQuery(tableName, detailed)
+}
+
+func (mysqlDialect) ConstraintQuery(tableName, constraintType string) string {
+	return getMySQLConstraintsQuery(tableName, constraintType)
+}
+
+func (mysqlDialect) TableStatsSections(tableName string) []tableStatsSection {
+	return getMySQLTableStatsSections(tableName)
+}
+
+func (mysqlDialect) DatabaseStatsQueries(detailed bool) []string {
+	return getMySQLStatsQueries(detailed)
+}
+
+// SchemaQuery ignores includeSystemSchemas; MySQL lists every database it can see
+func (mysqlDialect) SchemaQuery(schemaName string, _ bool) string {
+	return getMySQLSchemasQuery(schemaName)
+}
+
+func (mysqlDialect) ViewQuery(viewName string, includeDefinition bool) string {
+	return getMySQLViewsQuery(viewName, includeDefinition)
+}
+
+// TypeQuery reports false; MySQL only has built-in data types
+func (mysqlDialect) TypeQuery(string) (string, bool) { return "", false }
+
+func (mysqlDialect) FunctionQuery(functionName string, includeSource bool) (string, bool) {
+	return getMySQLFunctionsQuery(functionName, includeSource), true
+}
+
+// SequenceQuery lists the AUTO_INCREMENT counters, which stand in for sequences in MySQL
+func (mysqlDialect) SequenceQuery(schema string) (string, bool) {
+	return getMySQLSequencesQuery(schema), true
+}
+
+// ExtensionQuery reports false; MySQL features are built in or loaded as server plugins
+func (mysqlDialect) ExtensionQuery() (string, bool) { return "", false }
+
+func (mysqlDialect) SchemaMetadataQueries(schema string) (string, schemaMetadataQueries) {
+	return schema, getMySQLSchemaMetadataQueries(schema)
+}
+
+func (mysqlDialect) EnforcesKeys() bool { return true }
+
+// tidbDialect is the TiDB dialect. TiDB speaks the MySQL dialect, but its storage is spread
+// over TiKV regions and TiFlash replicas rather than an InnoDB buffer pool.
+type tidbDialect struct {
+	mysqlDialect
+	unsupportedAdmin
+}
+
+func (tidbDialect) Name() string { return "tidb" }
+
+// ExplainQuery returns an empty statement; TiDB's EXPLAIN has no MySQL JSON format
+func (tidbDialect) ExplainQuery(string) string { return "" }
+
+// PlanFormat reports false; TiDB plans have their own shape
+func (tidbDialect) PlanFormat() (planFormat, bool) { return planFormat{}, false }
+
+func (tidbDialect) DatabaseStatsQueries(detailed bool) []string {
+	return getTiDBStatsQueries(detailed)
+}
+
+// FunctionQuery reports false; TiDB has no stored functions or procedures
+func (tidbDialect) FunctionQuery(string, bool) (string, bool) { return "", false }
+
+// sqliteDialect is the SQLite dialect; its catalog is read through pragma table functions
+type sqliteDialect struct {
+	unsupportedAdmin
+}
+
+func (sqliteDialect) Name() string { return "sqlite" }
+
+func (sqliteDialect) QuoteIdent(ident string) string {
+	return "\"" + strings.Replace(ident, "\"", "\"\"", -1) + "\""
+}
+
+func (sqliteDialect) QuoteLiteral(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
+
+func (sqliteDialect) Placeholder(int) string { return "?" }
+
+func (sqliteDialect) RandomFunc() string { return "RANDOM()" }
+
+func (sqliteDialect) LimitClause(n int) string { return fmt.Sprintf("LIMIT %d", n) }
+
+func (sqliteDialect) PageClause(limit, offset int) string { return limitOffset(limit, offset) }
+
+// FullJoin returns true; SQLite runs FULL OUTER JOIN since 3.39
+func (sqliteDialect) FullJoin() bool { return true }
+
+// ExplainQuery returns EXPLAIN QUERY PLAN, which has no JSON format; loadQueryPlan converts
+// its rows instead
+func (sqliteDialect) ExplainQuery(query string) string { return "EXPLAIN QUERY PLAN " + query }
+
+func (sqliteDialect) PlanFormat() (planFormat, bool) { return sqlitePlanFormat, true }
+
+func (sqliteDialect) IndexQuery(tableName string, detailed bool) string {
+	return getSQLiteIndexesQuery(tableName, detailed)
+}
+
+func (sqliteDialect) ConstraintQuery(tableName, constraintType string) string {
+	return getSQLiteConstraintsQuery(tableName, constraintType)
+}
+
+func (sqliteDialect) TableStatsSections(tableName string) []tableStatsSection {
+	return getSQLiteTableStatsSections(tableName)
+}
+
+func (sqliteDialect) DatabaseStatsQueries(detailed bool) []string {
+	return getSQLiteStatsQueries(detailed)
+}
+
+// SchemaQuery ignores includeSystemSchemas; SQLite lists the main, temp and attached databases
+func (sqliteDialect) SchemaQuery(schemaName string, _ bool) string {
+	return getSQLiteSchemasQuery(schemaName)
+}
+
+func (sqliteDialect) ViewQuery(viewName string, includeDefinition bool) string {
+	return getSQLiteViewsQuery(viewName, includeDefinition)
+}
+
+// TypeQuery reports false; SQLite has no user-defined data types
+func (sqliteDialect) TypeQuery(string) (string, bool) { return "", false }
+
+// FunctionQuery reports false; SQLite functions are registered by the application
+func (sqliteDialect) FunctionQuery(string, bool) (string, bool) { return "", false }
+
+// SequenceQuery reports false; SQLite has no sequences, and rowids grow within 64 bits
+func (sqliteDialect) SequenceQuery(string) (string, bool) { return "", false }
+
+// ExtensionQuery reports false; SQLite extensions are loaded by each connection, not the database
+func (sqliteDialect) ExtensionQuery() (string, bool) { return "", false }
+
+func (sqliteDialect) SchemaMetadataQueries(schema string) (string, schemaMetadataQueries) {
+	if schema == "" {
+		schema = "main"
+	}
+	return schema, getSQLiteSchemaMetadataQueries(schema)
+}
+
+func (sqliteDialect) EnforcesKeys() bool { return true }
+
+func (sqliteDialect) SchemaSearchQuery() (schemaSearchQuery, bool) {
+	return sqliteSchemaSearchQuery, true
+}
+
+func (sqliteDialect) SchemaFixer() (schemaFixer, bool) { return sqliteSchemaFixer{}, true }
+
+// TableExistsQuery looks the table up in sqlite_master, which migrate keeps its table in
+func (sqliteDialect) TableExistsQuery() (string, bool) {
+	return "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", true
+}
+
+// clickhouseDialect is the ClickHouse dialect, reached over ClickHouse's MySQL-compatible
+// interface; its catalog is read from the system tables of the current database
+type clickhouseDialect struct {
+	unsupportedAdmin
+}
+
+func (clickhouseDialect) Name() string { return "clickhouse" }
+
+func (clickhouseDialect) QuoteIdent(ident string) string {
+	ident = strings.Replace(ident, "\\", "\\\\", -1)
+	return "`" + strings.Replace(ident, "`", "``", -1) + "`"
+}
+
+func (clickhouseDialect) QuoteLiteral(value string) string {
+	value = strings.Replace(value, "\\", "\\\\", -1)
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
+
+func (clickhouseDialect) Placeholder(int) string { return "?" }
+
+func (clickhouseDialect) RandomFunc() string { return "rand()" }
+
+func (clickhouseDialect) LimitClause(n int) string { return fmt.Sprintf("LIMIT %d", n) }
+
+func (clickhouseDialect) PageClause(limit, offset int) string { return limitOffset(limit, offset) }
+
+func (clickhouseDialect) FullJoin() bool { return true }
+
+// ExplainQuery returns the JSON plan of ClickHouse's EXPLAIN; its plan steps carry no costs
+// or row estimates, so parseQueryPlan does not read them
+func (clickhouseDialect) ExplainQuery(query string) string {
+	return "EXPLAIN json = 1, description = 0 " + query
+}
+
+// PlanFormat reports false; ClickHouse plan steps carry nothing parseQueryPlan reads
+func (clickhouseDialect) PlanFormat() (planFormat, bool) { return planFormat{}, false }
+
+func (clickhouseDialect) IndexQuery(tableName string, detailed bool) string {
+	return getClickHouseIndexesQuery(tableName, detailed)
+}
+
+func (clickhouseDialect) ConstraintQuery(tableName, constraintType string) string {
+	return getClickHouseConstraintsQuery(tableName, constraintType)
+}
+
+func (clickhouseDialect) TableStatsSections(tableName string) []tableStatsSection {
+	return getClickHouseTableStatsSections(tableName)
+}
+
+func (clickhouseDialect) DatabaseStatsQueries(detailed bool) []string {
+	return getClickHouseStatsQueries(detailed)
+}
+
+func (clickhouseDialect) SchemaQuery(schemaName string, includeSystemSchemas bool) string {
+	return getClickHouseSchemasQuery(schemaName, includeSystemSchemas)
+}
+
+func (clickhouseDialect) ViewQuery(viewName string, includeDefinition bool) string {
+	return getClickHouseViewsQuery(viewName, includeDefinition)
+}
+
+// TypeQuery reports false; ClickHouse has no user-defined data types
+func (clickhouseDialect) TypeQuery(string) (string, bool) { return "", false }
+
+func (clickhouseDialect) FunctionQuery(functionName string, includeSource bool) (string, bool) {
+	return getClickHouseFunctionsQuery(functionName, includeSource), true
+}
+
+// SequenceQuery reports false; ClickHouse has no sequences or auto-increment columns
+func (clickhouseDialect) SequenceQuery(string) (string, bool) { return "", false }
+
+// ExtensionQuery reports false; ClickHouse has no extensions
+func (clickhouseDialect) ExtensionQuery() (string, bool) { return "", false }
+
+func (clickhouseDialect) SchemaMetadataQueries(schema string) (string, schemaMetadataQueries) {
+	return schema, getClickHouseSchemaMetadataQueries(schema)
+}
+
+// EnforcesKeys reports false; ClickHouse keys only order the data
+func (clickhouseDialect) EnforcesKeys() bool { return false }
+
+// bigqueryDialect is the BigQuery dialect (GoogleSQL); its catalog is read from the
+// INFORMATION_SCHEMA views of the connection's default dataset
+type bigqueryDialect struct {
+	unsupportedAdmin
+}
+
+func (bigqueryDialect) Name() string { return "bigquery" }
+
+func (bigqueryDialect) QuoteIdent(ident string) string {
+	ident = strings.Replace(ident, "\\", "\\\\", -1)
+	return "`" + strings.Replace(ident, "`", "\\`", -1) + "`"
+}
+
+func (bigqueryDialect) QuoteLiteral(value string) string {
+	value = strings.Replace(value, "\\", "\\\\", -1)
+	return "'" + strings.Replace(value, "'", "\\'", -1) + "'"
+}
+
+func (bigqueryDialect) Placeholder(int) string { return "?" }
+
+func (bigqueryDialect) RandomFunc() string { return "RAND()" }
+
+func (bigqueryDialect) LimitClause(n int) string { return fmt.Sprintf("LIMIT %d", n) }
+
+func (bigqueryDialect) PageClause(limit, offset int) string { return limitOffset(limit, offset) }
+
+func (bigqueryDialect) FullJoin() bool { return true }
+
+// ExplainQuery returns an empty statement; BigQuery has no EXPLAIN and only reports the
+// plan of a job after it ran
+func (bigqueryDialect) ExplainQuery(string) string { return "" }
+
+func (bigqueryDialect) PlanFormat() (planFormat, bool) { return planFormat{}, false }
+
+func (bigqueryDialect) IndexQuery(tableName string, detailed bool) string {
+	return getBigQueryIndexesQuery(tableName, detailed)
+}
+
+func (bigqueryDialect) ConstraintQuery(tableName, constraintType string) string {
+	return getBigQueryConstraintsQuery(tableName, constraintType)
+}
+
+func (bigqueryDialect) TableStatsSections(tableName string) []tableStatsSection {
+	return getBigQueryTableStatsSections(tableName)
+}
+
+func (bigqueryDialect) DatabaseStatsQueries(detailed bool) []string {
+	return getBigQueryStatsQueries(detailed)
+}
+
+// SchemaQuery ignores includeSystemSchemas; BigQuery datasets hold no system tables
+func (bigqueryDialect) SchemaQuery(schemaName string, _ bool) string {
+	return getBigQuerySchemasQuery(schemaName)
+}
+
+func (bigqueryDialect) ViewQuery(viewName string, includeDefinition bool) string {
+	return getBigQueryViewsQuery(viewName, includeDefinition)
+}
+
+// TypeQuery reports false; BigQuery has no user-defined data types
+func (bigqueryDialect) TypeQuery(string) (string, bool) { return "", false }
+
+func (bigqueryDialect) FunctionQuery(functionName string, includeSource bool) (string, bool) {
+	return getBigQueryFunctionsQuery(functionName, includeSource), true
+}
+
+// SequenceQuery reports false; BigQuery has no sequences
+func (bigqueryDialect) SequenceQuery(string) (string, bool) { return "", false }
+
+// ExtensionQuery reports false; BigQuery has no extensions
+func (bigqueryDialect) ExtensionQuery() (string, bool) { return "", false }
+
+func (bigqueryDialect) SchemaMetadataQueries(schema string) (string, schemaMetadataQueries) {
+	return schema, getBigQuerySchemaMetadataQueries(schema)
+}
+
+// EnforcesKeys reports false; BigQuery keys are not enforced
+func (bigqueryDialect) EnforcesKeys() bool { return false }
+
+// spannerDialect is the Spanner dialect (GoogleSQL). Its catalog is read from
+// INFORMATION_SCHEMA, where the tables of the default schema have an empty table_schema,
+// and its statistics from the SPANNER_SYS tables, which cannot be joined with
+// INFORMATION_SCHEMA in one query.
+type spannerDialect struct {
+	unsupportedAdmin
+}
+
+func (spannerDialect) Name() string { return "spanner" }
+
+func (spannerDialect) QuoteIdent(ident string) string {
+	ident = strings.Replace(ident, "\\", "\\\\", -1)
+	return "`" + strings.Replace(ident, "`", "\\`", -1) + "`"
+}
+
+func (spannerDialect) QuoteLiteral(value string) string {
+	value = strings.Replace(value, "\\", "\\\\", -1)
+	return "'" + strings.Replace(value, "'", "\\'", -1) + "'"
+}
+
+func (spannerDialect) Placeholder(n int) string { return fmt.Sprintf("@p%d", n) }
+
+// RandomFunc hashes a fresh UUID per row; Spanner has no RAND()
+func (spannerDialect) RandomFunc() string { return "FARM_FINGERPRINT(GENERATE_UUID())" }
+
+func (spannerDialect) LimitClause(n int) string { return fmt.Sprintf("LIMIT %d", n) }
+
+func (spannerDialect) PageClause(limit, offset int) string { return limitOffset(limit, offset) }
+
+func (spannerDialect) FullJoin() bool { return true }
+
+// ExplainQuery returns an empty statement; Spanner has no EXPLAIN and only returns plans
+// when a query is sent in plan mode
+func (spannerDialect) ExplainQuery(string) string { return "" }
+
+func (spannerDialect) PlanFormat() (planFormat, bool) { return planFormat{}, false }
+
+func (spannerDialect) IndexQuery(tableName string, detailed bool) string {
+	return getSpannerIndexesQuery(tableName, detailed)
+}
+
+func (spannerDialect) ConstraintQuery(tableName, constraintType string) string {
+	return getSpannerConstraintsQuery(tableName, constraintType)
+}
+
+func (spannerDialect) TableStatsSections(tableName string) []tableStatsSection {
+	return getSpannerTableStatsSections(tableName)
+}
+
+func (spannerDialect) DatabaseStatsQueries(detailed bool) []string {
+	return getSpannerStatsQueries(detailed)
+}
+
+func (spannerDialect) SchemaQuery(schemaName string, includeSystemSchemas bool) string {
+	return getSpannerSchemasQuery(schemaName, includeSystemSchemas)
+}
+
+func (spannerDialect) ViewQuery(viewName string, includeDefinition bool) string {
+	return getSpannerViewsQuery(viewName, includeDefinition)
+}
+
+// TypeQuery reports false; Spanner has no user-defined data types
+func (spannerDialect) TypeQuery(string) (string, bool) { return "", false }
+
+// FunctionQuery reports false; Spanner has no user-defined functions or procedures
+func (spannerDialect) FunctionQuery(string, bool) (string, bool) { return "", false }
+
+// SequenceQuery reports false; Spanner sequences are bit-reversed and have no current value
+func (spannerDialect) SequenceQuery(string) (string, bool) { return "", false }
+
+// ExtensionQuery reports false; Spanner has no extensions
+func (spannerDialect) ExtensionQuery() (string, bool) { return "", false }
+
+// SchemaMetadataQueries keeps an empty schema, which is the name of the default schema
+func (spannerDialect) SchemaMetadataQueries(schema string) (string, schemaMetadataQueries) {
+	return schema, getSpannerSchemaMetadataQueries(schema)
+}
+
+func (spannerDialect) EnforcesKeys() bool { return true }
+
+// spannerTableFilter matches the INFORMATION_SCHEMA rows of a table given as name or
+// schema.name; an unqualified name is looked up in the default schema
+func spannerTableFilter(alias, tableName string) string {
+	schema, table := splitQualifiedName(tableName)
+	return fmt.Sprintf("%[1]s.table_schema = %[2]s AND %[1]s.table_name = %[3]s",
+		alias, quoteLiteral("spanner", schema), quoteLiteral("spanner", table))
+}
+
+// hanaDialect is the SAP HANA dialect. Its catalog is read from the SYS views and its
+// statistics from the monitoring views (M_*), where column-store tables report the memory
+// they hold in M_CS_TABLES; an unqualified name belongs to the schema of the session.
+type hanaDialect struct {
+	unsupportedAdmin
+}
+
+func (hanaDialect) Name() string { return "hana" }
+
+func (hanaDialect) QuoteIdent(ident string) string {
+	return "\"" + strings.Replace(ident, "\"", "\"\"", -1) + "\""
+}
+
+func (hanaDialect) QuoteLiteral(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
+
+func (hanaDialect) Placeholder(int) string { return "?" }
+
+func (hanaDialect) RandomFunc() string { return "RAND()" }
+
+func (hanaDialect) LimitClause(n int) string { return fmt.Sprintf("LIMIT %d", n) }
+
+func (hanaDialect) PageClause(limit, offset int) string { return limitOffset(limit, offset) }
+
+func (hanaDialect) FullJoin() bool { return true }
+
+// ExplainQuery returns an empty statement; HANA writes plans into EXPLAIN_PLAN_TABLE
+// instead of returning them
+func (hanaDialect) ExplainQuery(string) string { return "" }
+
+func (hanaDialect) PlanFormat() (planFormat, bool) { return planFormat{}, false }
+
+func (hanaDialect) IndexQuery(tableName string, detailed bool) string {
+	return getHANAIndexesQuery(tableName, detailed)
+}
+
+func (hanaDialect) ConstraintQuery(tableName, constraintType string) string {
+	return getHANAConstraintsQuery(tableName, constraintType)
+}
+
+func (hanaDialect) TableStatsSections(tableName string) []tableStatsSection {
+	return getHANATableStatsSections(tableName)
+}
+
+func (hanaDialect) DatabaseStatsQueries(detailed bool) []string {
+	return getHANAStatsQueries(detailed)
+}
+
+func (hanaDialect) SchemaQuery(schemaName string, includeSystemSchemas bool) string {
+	return getHANASchemasQuery(schemaName, includeSystemSchemas)
+}
+
+func (hanaDialect) ViewQuery(viewName string, includeDefinition bool) string {
+	return getHANAViewsQuery(viewName, includeDefinition)
+}
+
+// TypeQuery reports false; the table types of HANA are not column types
+func (hanaDialect) TypeQuery(string) (string, bool) { return "", false }
+
+func (hanaDialect) FunctionQuery(functionName string, includeSource bool) (string, bool) {
+	return getHANAFunctionsQuery(functionName, includeSource), true
+}
+
+func (hanaDialect) SequenceQuery(schema string) (string, bool) {
+	return getHANASequencesQuery(schema), true
+}
+
+// ExtensionQuery reports false; HANA has no extensions
+func (hanaDialect) ExtensionQuery() (string, bool) { return "", false }
+
+func (hanaDialect) SchemaMetadataQueries(schema string) (string, schemaMetadataQueries) {
+	return schema, getHANASchemaMetadataQueries(schema)
+}
+
+func (hanaDialect) EnforcesKeys() bool { return true }
+
+// hanaTableFilter matches the SYS or M_* rows of a table given as name or schema.name; an
+// unqualified name is looked up in the schema of the session
+func hanaTableFilter(alias, tableName string) string {
+	schema, table := splitQualifiedName(tableName)
+	schemaFilter := "CURRENT_SCHEMA"
+	if schema != "" {
+		schemaFilter = quoteLiteral("hana", schema)
+	}
+	return fmt.Sprintf("%[1]s.SCHEMA_NAME = %[2]s AND %[1]s.TABLE_NAME = %[3]s",
+		alias, schemaFilter, quoteLiteral("hana", table))
+}
+
+// hanaUserSchemaFilter leaves out the SYS schema and the _SYS_ schemas HANA keeps for the
+// repository, statistics and other services
+func hanaUserSchemaFilter(alias string) string {
+	return fmt.Sprintf(`%[1]s.SCHEMA_NAME <> 'SYS' AND %[1]s.SCHEMA_NAME NOT LIKE '\_SYS\_%%' ESCAPE '\'`, alias)
+}
+
+// firebirdDialect is the Firebird dialect. Its catalog is read from the RDB$ system tables,
+// whose names are fixed-width CHAR columns trimmed for display, and its statistics from the
+// MON$ monitoring tables. Firebird has no schemas, so tables are named on their own.
+type firebirdDialect struct {
+	unsupportedAdmin
+}
+
+func (firebirdDialect) Name() string { return "firebird" }
+
+func (firebirdDialect) QuoteIdent(ident string) string {
+	return "\"" + strings.Replace(ident, "\"", "\"\"", -1) + "\""
+}
+
+func (firebirdDialect) QuoteLiteral(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
+
+func (firebirdDialect) Placeholder(int) string { return "?" }
+
+func (firebirdDialect) RandomFunc() string { return "RAND()" }
+
+// LimitClause returns ROWS, which Firebird accepts since 2.0, unlike FETCH FIRST
+func (firebirdDialect) LimitClause(n int) string { return fmt.Sprintf("ROWS %d", n) }
+
+// PageClause returns ROWS m TO n, which counts rows from 1
+func (firebirdDialect) PageClause(limit, offset int) string {
+	if offset == 0 {
+		return fmt.Sprintf("ROWS %d", limit)
+	}
+	return fmt.Sprintf("ROWS %d TO %d", offset+1, offset+limit)
+}
+
+func (firebirdDialect) FullJoin() bool { return true }
+
+// ExplainQuery returns an empty statement; Firebird reports plans through the API of a
+// prepared statement, not through SQL
+func (firebirdDialect) ExplainQuery(string) string { return "" }
+
+func (firebirdDialect) PlanFormat() (planFormat, bool) { return planFormat{}, false }
+
+func (firebirdDialect) IndexQuery(tableName string, detailed bool) string {
+	return getFirebirdIndexesQuery(tableName, detailed)
+}
+
+func (firebirdDialect) ConstraintQuery(tableName, constraintType string) string {
+	return getFirebirdConstraintsQuery(tableName, constraintType)
+}
+
+func (firebirdDialect) TableStatsSections(tableName string) []tableStatsSection {
+	return getFirebirdTableStatsSections(tableName)
+}
+
+func (firebirdDialect) DatabaseStatsQueries(detailed bool) []string {
+	return getFirebirdStatsQueries(detailed)
+}
+
+// SchemaQuery ignores includeSystemSchemas; Firebird lists the database it is connected to
+func (firebirdDialect) SchemaQuery(schemaName string, _ bool) string {
+	return getFirebirdSchemasQuery(schemaName)
+}
+
+func (firebirdDialect) ViewQuery(viewName string, includeDefinition bool) string {
+	return getFirebirdViewsQuery(viewName, includeDefinition)
+}
+
+// TypeQuery lists domains, the user-defined column types of Firebird
+func (firebirdDialect) TypeQuery(typeName string) (string, bool) {
+	return getFirebirdDomainsQuery(typeName), true
+}
+
+func (firebirdDialect) FunctionQuery(functionName string, includeSource bool) (string, bool) {
+	return getFirebirdFunctionsQuery(functionName, includeSource), true
+}
+
+// SequenceQuery reports false; the value of a Firebird generator is only read with GEN_ID,
+// one generator at a time
+func (firebirdDialect) SequenceQuery(string) (string, bool) { return "", false }
+
+// ExtensionQuery reports false; Firebird has no extensions
+func (firebirdDialect) ExtensionQuery() (string, bool) { return "", false }
+
+// SchemaMetadataQueries ignores the schema; the tables of a Firebird database have none
+func (firebirdDialect) SchemaMetadataQueries(string) (string, schemaMetadataQueries) {
+	return "", getFirebirdSchemaMetadataQueries()
+}
+
+func (firebirdDialect) EnforcesKeys() bool { return true }
+
+// firebirdTypeExpression spells the type of an RDB$FIELDS row the way it is declared; the
+// table stores a type code, with scaled integers for NUMERIC and DECIMAL
+func firebirdTypeExpression(alias string) string {
+	return strings.NewReplacer("f.", alias+".").Replace(`CASE
+        WHEN f.RDB$FIELD_TYPE IN (7, 8, 16, 26) AND f.RDB$FIELD_SUB_TYPE = 1
+            THEN 'NUMERIC(' || f.RDB$FIELD_PRECISION || ', ' || -f.RDB$FIELD_SCALE || ')'
+        WHEN f.RDB$FIELD_TYPE IN (7, 8, 16, 26) AND f.RDB$FIELD_SUB_TYPE = 2
+            THEN 'DECIMAL(' || f.RDB$FIELD_PRECISION || ', ' || -f.RDB$FIELD_SCALE || ')'
+        WHEN f.RDB$FIELD_TYPE = 7 THEN 'SMALLINT'
+        WHEN f.RDB$FIELD_TYPE = 8 THEN 'INTEGER'
+        WHEN f.RDB$FIELD_TYPE = 16 THEN 'BIGINT'
+        WHEN f.RDB$FIELD_TYPE = 26 THEN 'INT128'
+        WHEN f.RDB$FIELD_TYPE = 10 THEN 'FLOAT'
+        WHEN f.RDB$FIELD_TYPE = 27 THEN 'DOUBLE PRECISION'
+        WHEN f.RDB$FIELD_TYPE = 24 THEN 'DECFLOAT(16)'
+        WHEN f.RDB$FIELD_TYPE = 25 THEN 'DECFLOAT(34)'
+        WHEN f.RDB$FIELD_TYPE = 23 THEN 'BOOLEAN'
+        WHEN f.RDB$FIELD_TYPE = 12 THEN 'DATE'
+        WHEN f.RDB$FIELD_TYPE = 13 THEN 'TIME'
+        WHEN f.RDB$FIELD_TYPE = 28 THEN 'TIME WITH TIME ZONE'
+        WHEN f.RDB$FIELD_TYPE = 35 THEN 'TIMESTAMP'
+        WHEN f.RDB$FIELD_TYPE = 29 THEN 'TIMESTAMP WITH TIME ZONE'
+        WHEN f.RDB$FIELD_TYPE = 14 THEN 'CHAR(' || COALESCE(f.RDB$CHARACTER_LENGTH, f.RDB$FIELD_LENGTH) || ')'
+        WHEN f.RDB$FIELD_TYPE = 37 THEN 'VARCHAR(' || COALESCE(f.RDB$CHARACTER_LENGTH, f.RDB$FIELD_LENGTH) || ')'
+        WHEN f.RDB$FIELD_TYPE = 261 AND f.RDB$FIELD_SUB_TYPE = 1 THEN 'BLOB SUB_TYPE TEXT'
+        WHEN f.RDB$FIELD_TYPE = 261 THEN 'BLOB'
+        ELSE 'TYPE ' || f.RDB$FIELD_TYPE
+    END`)
+}
+
+// trinoDialect is the Trino dialect. Trino federates the catalogs of its connectors;
+// information_schema belongs to the default catalog of the connection, and the system
+// catalog describes every catalog and the cluster.
+type trinoDialect struct {
+	unsupportedAdmin
+}
+
+func (trinoDialect) Name() string { return "trino" }
+
+func (trinoDialect) QuoteIdent(ident string) string {
+	return "\"" + strings.Replace(ident, "\"", "\"\"", -1) + "\""
+}
+
+func (trinoDialect) QuoteLiteral(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
+
+func (trinoDialect) Placeholder(int) string { return "?" }
+
+func (trinoDialect) RandomFunc() string { return "random()" }
+
+func (trinoDialect) LimitClause(n int) string { return fmt.Sprintf("LIMIT %d", n) }
+
+// PageClause puts OFFSET first, the order Trino requires
+func (trinoDialect) PageClause(limit, offset int) string {
+	if offset == 0 {
+		return fmt.Sprintf("LIMIT %d", limit)
+	}
+	return fmt.Sprintf("OFFSET %d LIMIT %d", offset, limit)
+}
+
+func (trinoDialect) FullJoin() bool { return true }
+
+// ExplainQuery returns an empty statement; Trino's JSON plans have their own shape
+func (trinoDialect) ExplainQuery(string) string { return "" }
+
+func (trinoDialect) PlanFormat() (planFormat, bool) { return planFormat{}, false }
+
+func (trinoDialect) IndexQuery(tableName string, detailed bool) string {
+	return getTrinoIndexesQuery(tableName, detailed)
+}
+
+func (trinoDialect) ConstraintQuery(tableName, constraintType string) string {
+	return getTrinoConstraintsQuery(tableName, constraintType)
+}
+
+func (trinoDialect) TableStatsSections(tableName string) []tableStatsSection {
+	return getTrinoTableStatsSections(tableName)
+}
+
+func (trinoDialect) DatabaseStatsQueries(detailed bool) []string {
+	return getTrinoStatsQueries(detailed)
+}
+
+func (trinoDialect) SchemaQuery(schemaName string, includeSystemSchemas bool) string {
+	return getTrinoSchemasQuery(schemaName, includeSystemSchemas)
+}
+
+func (trinoDialect) ViewQuery(viewName string, includeDefinition bool) string {
+	return getTrinoViewsQuery(viewName, includeDefinition)
+}
+
+// TypeQuery reports false; Trino has no user-defined data types
+func (trinoDialect) TypeQuery(string) (string, bool) { return "", false }
+
+// FunctionQuery reports false; Trino keeps no routines in the catalogs it federates
+func (trinoDialect) FunctionQuery(string, bool) (string, bool) { return "", false }
+
+// SequenceQuery reports false; Trino has no sequences
+func (trinoDialect) SequenceQuery(string) (string, bool) { return "", false }
+
+// ExtensionQuery reports false; Trino plugins are installed on the coordinator, not in a catalog
+func (trinoDialect) ExtensionQuery() (string, bool) { return "", false }
+
+func (trinoDialect) SchemaMetadataQueries(schema string) (string, schemaMetadataQueries) {
+	return schema, getTrinoSchemaMetadataQueries(schema)
+}
+
+// EnforcesKeys reports false; Trino exposes no keys
+func (trinoDialect) EnforcesKeys() bool { return false }
+
+// databricksDialect is the Databricks SQL dialect. Unity Catalog names a table with three
+// parts, catalog.schema.table; system.information_schema describes every catalog of the
+// metastore, and unqualified names resolve in the catalog and schema of the connection.
+type databricksDialect struct {
+	unsupportedAdmin
+}
+
+func (databricksDialect) Name() string { return "databricks" }
+
+func (databricksDialect) QuoteIdent(ident string) string {
+	return "`" + strings.Replace(ident, "`", "``", -1) + "`"
+}
+
+// QuoteLiteral escapes backslashes too, as Databricks string literals take escape sequences
+func (databricksDialect) QuoteLiteral(value string) string {
+	value = strings.Replace(value, "\\", "\\\\", -1)
+	return "'" + strings.Replace(value, "'", "\\'", -1) + "'"
+}
+
+// Placeholder returns a named marker; the statement execution API only binds named parameters
+func (databricksDialect) Placeholder(n int) string { return fmt.Sprintf(":p%d", n) }
+
+func (databricksDialect) RandomFunc() string { return "rand()" }
+
+func (databricksDialect) LimitClause(n int) string { return fmt.Sprintf("LIMIT %d", n) }
+
+func (databricksDialect) PageClause(limit, offset int) string { return limitOffset(limit, offset) }
+
+func (databricksDialect) FullJoin() bool { return true }
+
+// ExplainQuery returns an empty statement; Databricks explains queries as text only
+func (databricksDialect) ExplainQuery(string) string { return "" }
+
+func (databricksDialect) PlanFormat() (planFormat, bool) { return planFormat{}, false }
+
+func (databricksDialect) IndexQuery(tableName string, detailed bool) string {
+	return getDatabricksIndexesQuery(tableName, detailed)
+}
+
+func (databricksDialect) ConstraintQuery(tableName, constraintType string) string {
+	return getDatabricksConstraintsQuery(tableName, constraintType)
+}
+
+func (databricksDialect) TableStatsSections(tableName string) []tableStatsSection {
+	return getDatabricksTableStatsSections(tableName)
+}
+
+func (databricksDialect) DatabaseStatsQueries(detailed bool) []string {
+	return getDatabricksStatsQueries(detailed)
+}
+
+func (databricksDialect) SchemaQuery(schemaName string, includeSystemSchemas bool) string {
+	return getDatabricksSchemasQuery(schemaName, includeSystemSchemas)
+}
+
+func (databricksDialect) ViewQuery(viewName string, includeDefinition bool) string {
+	return getDatabricksViewsQuery(viewName, includeDefinition)
+}
+
+// TypeQuery reports false; Databricks has no user-defined data types
+func (databricksDialect) TypeQuery(string) (string, bool) { return "", false }
+
+func (databricksDialect) FunctionQuery(functionName string, includeSource bool) (string, bool) {
+	return getDatabricksFunctionsQuery(functionName, includeSource), true
+}
+
+// SequenceQuery reports false; Databricks identity columns do not expose their high-water mark
+// in the catalog
+func (databricksDialect) SequenceQuery(string) (string, bool) { return "", false }
+
+// ExtensionQuery reports false; Databricks has no extensions
+func (databricksDialect) ExtensionQuery() (string, bool) { return "", false }
+
+func (databricksDialect) SchemaMetadataQueries(schema string) (string, schemaMetadataQueries) {
+	return schema, getDatabricksSchemaMetadataQueries(schema)
+}
+
+// EnforcesKeys reports false; Databricks keys are not enforced
+func (databricksDialect) EnforcesKeys() bool { return false }
+
+// databricksTableFilter matches the system.information_schema rows of a table given as
+// table, schema.table or catalog.schema.table; missing parts are those of the connection
+func databricksTableFilter(alias, tableName string) string {
+	schema, table := splitQualifiedName(tableName)
+	catalog, schema := splitQualifiedName(schema)
+	catalogFilter, schemaFilter := "current_catalog()", "current_schema()"
+	if catalog != "" {
+		catalogFilter = quoteLiteral("databricks", catalog)
+	}
+	if schema != "" {
+		schemaFilter = quoteLiteral("databricks", schema)
+	}
+	return fmt.Sprintf("%[1]s.table_catalog = %[2]s AND %[1]s.table_schema = %[3]s AND %[1]s.table_name = %[4]s",
+		alias, catalogFilter, schemaFilter, quoteLiteral("databricks", table))
+}
+
+// databricksCatalogFilter matches the rows of the catalog of the connection, leaving out its
+// information_schema, unless a table is named
+func databricksCatalogFilter(alias, tableName string) string {
+	if tableName != "" {
+		return databricksTableFilter(alias, tableName)
+	}
+	return fmt.Sprintf("%[1]s.table_catalog = current_catalog() AND %[1]s.table_schema <> 'information_schema'", alias)
+}
@@ -0,0 +1,744 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// errAdminUnsupported is returned by the administration tools an engine has no equivalent for
+var errAdminUnsupported = errors.New("not supported by this database type")
+
+// unsupportedAdminError names the database type a tool does not support, or passes on the
+// engine's explanation of what to use instead
+func unsupportedAdminError(feature, dbType string, err error) error {
+	if errors.Is(err, errAdminUnsupported) {
+		return fmt.Errorf("unsupported database type for %s: %s", feature, dbType)
+	}
+	return err
+}
+
+// adminDialect holds the engine-specific SQL and procedures of the administration tools:
+// sessions and locks, index and table maintenance, schema changes and server settings. A
+// query the engine cannot answer reports false and a procedure it cannot run returns an error,
+// errAdminUnsupported unless the engine explains what to use instead, and the tool refuses the
+// database rather than switching on its type.
+type adminDialect interface {
+	// ActiveSessionsQuery returns the query listing the client sessions other than this one
+	// that have been in their state for at least $1 seconds, at most $2 of them, in the columns
+	// read by readActiveSessions
+	ActiveSessionsQuery(includeIdle bool) (string, bool)
+	// SessionQuery returns the query for the session $1, in the columns of ActiveSessionsQuery
+	// followed by whether it is the session running the query
+	SessionQuery() (string, bool)
+	// SessionRunning reports whether a session in a state of ActiveSessionsQuery is executing
+	// a statement
+	SessionRunning(state string) bool
+	// SessionIdleInTransaction reports whether a session in a state of the lock queries holds
+	// a transaction open between statements
+	SessionIdleInTransaction(state string) bool
+	// KillSessionStatement returns the statement that cancels the query of a session or
+	// terminates it, and whether it is a query reporting if the session was signalled
+	KillSessionStatement(sessionID int64, terminate bool) (string, bool)
+	// LockQueries returns the query listing the sessions waiting for a lock with the session
+	// blocking them, and the query listing the locks held, bound to a limit
+	LockQueries() (waits, held string, ok bool)
+	// IndexBuilder returns how create_index builds an index online
+	IndexBuilder() (indexBuilder, error)
+	// IndexAdvisor returns how missing_index_suggestions finds and costs missing indexes
+	IndexAdvisor() (indexAdvisor, error)
+	// Reindexer returns how reindex selects and rebuilds bloated or invalid indexes
+	Reindexer() (reindexer, error)
+	// IndexStates returns how index_bloat reads the indexes whose bloat it estimates
+	IndexStates() (indexStateLoader, error)
+	// UnusedIndexQueries returns the queries of unused_indexes for indexes of schema with at
+	// most maxScans scans and at least minBytes, at most limit of them
+	UnusedIndexQueries(schema string, maxScans int, minBytes int64, limit int) (unusedIndexQueries, bool)
+	// IndexDefinitionsQuery returns the query listing the indexes of a schema or table in the
+	// columns read by duplicate_indexes, with its parameters
+	IndexDefinitionsQuery(schema, table string) (string, []interface{}, bool)
+	// DropIndexStatement returns the statement that drops an index with the least locking
+	DropIndexStatement(schema, table, index string) string
+	// RowEstimatesQuery returns the query for the row estimate of every table of the schema $1,
+	// and whether it reports when each table was last analyzed
+	RowEstimatesQuery() (query string, analyzed bool, ok bool)
+	// LargestObjectsQuery returns the query for the $1 largest tables and indexes
+	LargestObjectsQuery() (string, bool)
+	// TableUsageQuery returns the query for the activity counters of the tables of a schema, or
+	// of all user schemas, with its parameters
+	TableUsageQuery(schema string) (string, []interface{}, bool)
+	// StatisticsAnalyzer returns how analyze_table refreshes and reads optimizer statistics
+	StatisticsAnalyzer() (statisticsAnalyzer, error)
+	// TableMaintainer returns how run_maintenance vacuums, analyzes and rebuilds tables
+	TableMaintainer() (tableMaintainer, error)
+	// PartitionReader returns how get_partitions reads partitioned tables
+	PartitionReader() (partitionReader, error)
+	// PartitionManager returns how manage_partitions creates, attaches, detaches and drops
+	// time-range partitions
+	PartitionManager() (partitionManager, error)
+	// TableHierarchyQuery returns the query for the parent/child links between the tables of
+	// the trees touching schema $1, in the columns read by buildTableHierarchy
+	TableHierarchyQuery() (string, error)
+	// ConstraintStatusSections returns the sections of constraint_status for the constraints
+	// of a schema or table
+	ConstraintStatusSections(schema, table string) ([]reportSection, bool)
+	// DDLRunner returns how run_ddl works out the locks of a schema change and runs it
+	DDLRunner() (ddlRunner, error)
+	// RenamePlanner returns how rename_object renames a table or column with its dependents
+	RenamePlanner() (renamePlanner, error)
+	// TableCloner returns how clone_table copies the structure and rows of a table
+	TableCloner() (tableCloner, error)
+	// EnumDriftQueries returns the queries for the values a column declares in its type or CHECK
+	// constraints
+	EnumDriftQueries(schema, table, column string) (enumDriftQueries, bool)
+	// SequenceFixer returns how fix_sequences finds and moves lagging counters
+	SequenceFixer() (sequenceFixer, error)
+	// ObjectCommenter returns how get_object_comments reads and sets the comments of schema objects
+	ObjectCommenter() (objectCommenter, error)
+	// TablespaceSections returns the sections of get_tablespaces for one tablespace or all of
+	// them, listing at most limit objects
+	TablespaceSections(tablespace string, limit int) ([]reportSection, bool)
+	// CollationSections returns the sections of get_charsets_collations for a schema, listing at
+	// most limit columns
+	CollationSections(schema string, limit int) ([]reportSection, bool)
+	// PrivilegeSections returns the sections of get_roles_and_privileges for one role or all of
+	// them, with the grants on a schema or on all of them
+	PrivilegeSections(role, schema string) ([]reportSection, bool)
+	// ForeignServerSections returns the sections of get_foreign_servers for one server or all
+	// of them
+	ForeignServerSections(serverName string) ([]reportSection, bool)
+	// MaterializedViewsQuery returns the query for the materialized views of schema $1, or named
+	// $2, in the columns read by loadMaterializedViews
+	MaterializedViewsQuery() (string, bool)
+	// SettingsLoader returns how get_db_settings reads the server settings
+	SettingsLoader() (settingsLoader, error)
+	// ReplicationStatusReader returns how replication_status reads the replicas and sources of
+	// a server
+	ReplicationStatusReader() (replicationStatusReader, error)
+	// ReplicationSlotManager returns how replication_slots lists, creates and drops slots
+	ReplicationSlotManager() (replicationSlotManager, error)
+	// UserManager returns how manage_users lists, creates, alters and drops users
+	UserManager() (userManager, error)
+	// GrantPlanner returns how manage_grants expands a template into grants
+	GrantPlanner() (grantPlanner, error)
+	// HypertableQueries returns the queries of get_hypertables for one table or all of them
+	HypertableQueries(tableName string) (hypertableQueries, bool)
+	// Publisher returns how publications manages the publications of logical replication
+	Publisher() (publisher, error)
+	// ChangeReader returns how tail_changes reads recent row changes
+	ChangeReader() (changeReader, error)
+	// TimeBucket truncates a timestamp column to the start of its hour, day or week
+	TimeBucket(column, bucket string) (string, bool)
+	// VectorSearcher returns how vector_search finds vector columns and nearest rows
+	VectorSearcher() (vectorSearcher, error)
+	// SequenceUsageNote returns a caveat on the values get_sequences reports, or ""
+	SequenceUsageNote() string
+	// DumpTool returns how backup and restore run the dump and restore programs
+	DumpTool() (dumpTool, error)
+	// CSVExportCommand returns the client program copy backups export tables with, if any
+	CSVExportCommand() (csvExportCommand, bool)
+	// TruncateTablesStatement empties several quoted tables in one statement, so foreign keys between
+	// them do not get in the way; false means one TRUNCATE TABLE per table
+	TruncateTablesStatement(tables []string) (string, bool)
+	// ForeignKeyChecksStatements returns the statements disabling and re-enabling foreign key
+	// checks for the session, if they are checked statement by statement
+	ForeignKeyChecksStatements() (string, string, bool)
+	// TableExistsQuery returns the query counting the tables of the current schema with the
+	// name bound to its only parameter
+	TableExistsQuery() (string, bool)
+	// MigrationFailureNote returns what a failed migration may have left behind, or ""
+	MigrationFailureNote() string
+	// SchemaFixer returns how review_schema writes the DDL fixing its findings
+	SchemaFixer() (schemaFixer, bool)
+	// SchemaSearchQuery returns how search_schema builds its query
+	SchemaSearchQuery() (schemaSearchQuery, bool)
+	// TableReadsQuery returns the query for the number of reads of each table of a schema, with
+	// its parameters
+	TableReadsQuery(schema string) (string, []interface{}, bool)
+	// SchemaChangeTriggerStatements returns the statements installing triggers that publish
+	// every schema change on schemaChangeChannel
+	SchemaChangeTriggerStatements() ([]string, bool)
+	// TextSearcher returns how search_text renders full-text searches
+	TextSearcher() (textSearcher, error)
+	// JSONExplorer returns how explore_json samples JSON columns and indexes their paths
+	JSONExplorer() (jsonExplorer, error)
+	// SpatialCatalog returns how spatial_summary lists spatial columns and reads their extents
+	SpatialCatalog() (spatialCatalog, error)
+	// InferredTypes returns the type names infer_schema gives inferred columns
+	InferredTypes() inferredTypeNames
+}
+
+// unsupportedAdmin answers false to every administration query. Dialects embed it and
+// override the queries their engine supports; a dialect derived from another embeds it next
+// to its parent to opt in to the parent's queries one by one, since the shallower embedded
+// methods win.
+type unsupportedAdmin struct{}
+
+func (unsupportedAdmin) ActiveSessionsQuery(bool) (string, bool) { return "", false }
+
+func (unsupportedAdmin) SessionQuery() (string, bool) { return "", false }
+
+func (unsupportedAdmin) SessionRunning(string) bool { return false }
+
+func (unsupportedAdmin) SessionIdleInTransaction(string) bool { return false }
+
+func (unsupportedAdmin) KillSessionStatement(int64, bool) (string, bool) { return "", false }
+
+func (unsupportedAdmin) LockQueries() (string, string, bool) { return "", "", false }
+
+func (unsupportedAdmin) IndexBuilder() (indexBuilder, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) IndexAdvisor() (indexAdvisor, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) Reindexer() (reindexer, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) IndexStates() (indexStateLoader, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) UnusedIndexQueries(string, int, int64, int) (unusedIndexQueries, bool) {
+	return unusedIndexQueries{}, false
+}
+
+func (unsupportedAdmin) IndexDefinitionsQuery(string, string) (string, []interface{}, bool) {
+	return "", nil, false
+}
+
+func (unsupportedAdmin) DropIndexStatement(string, string, string) string { return "" }
+
+func (unsupportedAdmin) RowEstimatesQuery() (string, bool, bool) { return "", false, false }
+
+func (unsupportedAdmin) LargestObjectsQuery() (string, bool) { return "", false }
+
+func (unsupportedAdmin) TableUsageQuery(string) (string, []interface{}, bool) { return "", nil, false }
+
+func (unsupportedAdmin) StatisticsAnalyzer() (statisticsAnalyzer, error) {
+	return nil, errAdminUnsupported
+}
+
+func (unsupportedAdmin) TableMaintainer() (tableMaintainer, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) PartitionReader() (partitionReader, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) PartitionManager() (partitionManager, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) TableHierarchyQuery() (string, error) { return "", errAdminUnsupported }
+
+func (unsupportedAdmin) ConstraintStatusSections(string, string) ([]reportSection, bool) {
+	return nil, false
+}
+
+func (unsupportedAdmin) DDLRunner() (ddlRunner, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) RenamePlanner() (renamePlanner, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) TableCloner() (tableCloner, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) EnumDriftQueries(string, string, string) (enumDriftQueries, bool) {
+	return enumDriftQueries{}, false
+}
+
+func (unsupportedAdmin) SequenceFixer() (sequenceFixer, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) ObjectCommenter() (objectCommenter, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) TablespaceSections(string, int) ([]reportSection, bool) { return nil, false }
+
+func (unsupportedAdmin) CollationSections(string, int) ([]reportSection, bool) { return nil, false }
+
+func (unsupportedAdmin) PrivilegeSections(string, string) ([]reportSection, bool) { return nil, false }
+
+func (unsupportedAdmin) ForeignServerSections(string) ([]reportSection, bool) { return nil, false }
+
+func (unsupportedAdmin) MaterializedViewsQuery() (string, bool) { return "", false }
+
+func (unsupportedAdmin) SettingsLoader() (settingsLoader, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) ReplicationStatusReader() (replicationStatusReader, error) {
+	return nil, errAdminUnsupported
+}
+
+func (unsupportedAdmin) ReplicationSlotManager() (replicationSlotManager, error) {
+	return nil, errAdminUnsupported
+}
+
+func (unsupportedAdmin) UserManager() (userManager, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) GrantPlanner() (grantPlanner, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) HypertableQueries(string) (hypertableQueries, bool) {
+	return hypertableQueries{}, false
+}
+
+func (unsupportedAdmin) Publisher() (publisher, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) ChangeReader() (changeReader, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) TimeBucket(string, string) (string, bool) { return "", false }
+
+func (unsupportedAdmin) VectorSearcher() (vectorSearcher, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) SequenceUsageNote() string { return "" }
+
+func (unsupportedAdmin) DumpTool() (dumpTool, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) CSVExportCommand() (csvExportCommand, bool) { return nil, false }
+
+func (unsupportedAdmin) TruncateTablesStatement([]string) (string, bool) { return "", false }
+
+func (unsupportedAdmin) ForeignKeyChecksStatements() (string, string, bool) { return "", "", false }
+
+func (unsupportedAdmin) TableExistsQuery() (string, bool) { return "", false }
+
+func (unsupportedAdmin) MigrationFailureNote() string { return "" }
+
+func (unsupportedAdmin) SchemaFixer() (schemaFixer, bool) { return nil, false }
+
+func (unsupportedAdmin) SchemaSearchQuery() (schemaSearchQuery, bool) { return nil, false }
+
+func (unsupportedAdmin) TableReadsQuery(string) (string, []interface{}, bool) { return "", nil, false }
+
+func (unsupportedAdmin) SchemaChangeTriggerStatements() ([]string, bool) { return nil, false }
+
+func (unsupportedAdmin) TextSearcher() (textSearcher, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) JSONExplorer() (jsonExplorer, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) SpatialCatalog() (spatialCatalog, error) { return nil, errAdminUnsupported }
+
+func (unsupportedAdmin) InferredTypes() inferredTypeNames { return mysqlInferredTypes }
+
+// postgresAdmin holds the PostgreSQL administration queries
+type postgresAdmin struct{}
+
+func (postgresAdmin) ActiveSessionsQuery(includeIdle bool) (string, bool) {
+	return getPostgresActiveSessionsQuery(includeIdle), true
+}
+
+func (postgresAdmin) SessionQuery() (string, bool) { return getPostgresSessionQuery(), true }
+
+func (postgresAdmin) SessionRunning(state string) bool { return state == "active" }
+
+func (postgresAdmin) SessionIdleInTransaction(state string) bool {
+	return strings.HasPrefix(state, "idle in transaction")
+}
+
+// KillSessionStatement returns a query; the backend functions report false rather than
+// failing when the pid is not a backend
+func (postgresAdmin) KillSessionStatement(sessionID int64, terminate bool) (string, bool) {
+	if terminate {
+		return fmt.Sprintf("SELECT pg_terminate_backend(%d)", sessionID), true
+	}
+	return fmt.Sprintf("SELECT pg_cancel_backend(%d)", sessionID), true
+}
+
+func (postgresAdmin) LockQueries() (string, string, bool) {
+	return getPostgresLockWaitsQuery(), getPostgresHeldLocksQuery(), true
+}
+
+func (postgresAdmin) IndexBuilder() (indexBuilder, error) { return postgresIndexBuilder{}, nil }
+
+func (postgresAdmin) IndexAdvisor() (indexAdvisor, error) { return postgresIndexAdvisor{}, nil }
+
+func (postgresAdmin) Reindexer() (reindexer, error) { return postgresReindexer{}, nil }
+
+func (postgresAdmin) IndexStates() (indexStateLoader, error) { return loadPostgresIndexStates, nil }
+
+func (postgresAdmin) UnusedIndexQueries(schema string, maxScans int, minBytes int64, limit int) (unusedIndexQueries, bool) {
+	return unusedIndexQueries{
+		statsAge: getPostgresStatsAgeQuery(),
+		indexes:  getPostgresUnusedIndexesQuery(),
+		params:   []interface{}{schema, maxScans, minBytes, limit},
+		engine:   "PostgreSQL",
+	}, true
+}
+
+func (postgresAdmin) IndexDefinitionsQuery(schema, table string) (string, []interface{}, bool) {
+	return getPostgresIndexDefinitionsQuery(), []interface{}{schema, table}, true
+}
+
+func (postgresAdmin) DropIndexStatement(schema, _, index string) string {
+	return fmt.Sprintf("DROP INDEX CONCURRENTLY %s;", quoteIdentifier("postgres", qualifiedName(schema, index)))
+}
+
+func (postgresAdmin) RowEstimatesQuery() (string, bool, bool) {
+	return getPostgresRowEstimatesQuery(), true, true
+}
+
+func (postgresAdmin) LargestObjectsQuery() (string, bool) {
+	return getPostgresLargestObjectsQuery(), true
+}
+
+func (postgresAdmin) TableUsageQuery(schema string) (string, []interface{}, bool) {
+	query, params := getPostgresTableUsageQuery(schema)
+	return query, params, true
+}
+
+func (postgresAdmin) StatisticsAnalyzer() (statisticsAnalyzer, error) {
+	return postgresStatisticsAnalyzer{}, nil
+}
+
+func (postgresAdmin) TableMaintainer() (tableMaintainer, error) { return postgresMaintainer{}, nil }
+
+func (postgresAdmin) PartitionReader() (partitionReader, error) {
+	return postgresPartitionReader{}, nil
+}
+
+func (postgresAdmin) PartitionManager() (partitionManager, error) {
+	return postgresPartitionManager{}, nil
+}
+
+func (postgresAdmin) TableHierarchyQuery() (string, error) {
+	return getPostgresTableHierarchyQuery(), nil
+}
+
+func (postgresAdmin) ConstraintStatusSections(schema, table string) ([]reportSection, bool) {
+	return postgresConstraintStatusSections(schema, table), true
+}
+
+func (postgresAdmin) DDLRunner() (ddlRunner, error) { return postgresDDLRunner{}, nil }
+
+func (postgresAdmin) RenamePlanner() (renamePlanner, error) { return planPostgresRename, nil }
+
+func (postgresAdmin) TableCloner() (tableCloner, error) { return postgresTableCloner{}, nil }
+
+func (postgresAdmin) EnumDriftQueries(schema, table, column string) (enumDriftQueries, bool) {
+	return getPostgresEnumDriftQueries(schema, table, column), true
+}
+
+func (postgresAdmin) SequenceFixer() (sequenceFixer, error) { return postgresSequenceFixer{}, nil }
+
+func (postgresAdmin) ObjectCommenter() (objectCommenter, error) { return postgresCommenter{}, nil }
+
+func (postgresAdmin) TablespaceSections(tablespace string, limit int) ([]reportSection, bool) {
+	return postgresTablespaceSections(tablespace, limit), true
+}
+
+func (postgresAdmin) CollationSections(schema string, limit int) ([]reportSection, bool) {
+	return postgresCollationSections(schema, limit), true
+}
+
+func (postgresAdmin) PrivilegeSections(role, schema string) ([]reportSection, bool) {
+	return postgresPrivilegeSections(role, schema), true
+}
+
+func (postgresAdmin) ForeignServerSections(serverName string) ([]reportSection, bool) {
+	return postgresForeignServerSections(serverName), true
+}
+
+func (postgresAdmin) MaterializedViewsQuery() (string, bool) {
+	return getPostgresMaterializedViewsQuery(), true
+}
+
+func (postgresAdmin) SettingsLoader() (settingsLoader, error) { return loadPostgresSettings, nil }
+
+func (postgresAdmin) ReplicationStatusReader() (replicationStatusReader, error) {
+	return readPostgresReplicationStatus, nil
+}
+
+func (postgresAdmin) ReplicationSlotManager() (replicationSlotManager, error) {
+	return postgresSlotManager{}, nil
+}
+
+func (postgresAdmin) UserManager() (userManager, error) { return postgresUserManager{}, nil }
+
+func (postgresAdmin) GrantPlanner() (grantPlanner, error) { return postgresGrantStatements, nil }
+
+func (postgresAdmin) HypertableQueries(tableName string) (hypertableQueries, bool) {
+	return getPostgresHypertableQueries(tableName), true
+}
+
+func (postgresAdmin) Publisher() (publisher, error) { return postgresPublisher{}, nil }
+
+func (postgresAdmin) ChangeReader() (changeReader, error) { return readPostgresChanges, nil }
+
+func (postgresAdmin) TimeBucket(column, bucket string) (string, bool) {
+	return postgresTimeBucket(column, bucket), true
+}
+
+func (postgresAdmin) VectorSearcher() (vectorSearcher, error) { return pgvectorSearcher{}, nil }
+
+func (postgresAdmin) SequenceUsageNote() string { return "" }
+
+func (postgresAdmin) DumpTool() (dumpTool, error) { return postgresDumpTool{}, nil }
+
+func (postgresAdmin) CSVExportCommand() (csvExportCommand, bool) { return copyOutCommand, true }
+
+func (postgresAdmin) TruncateTablesStatement(tables []string) (string, bool) {
+	return "TRUNCATE " + strings.Join(tables, ", "), true
+}
+
+// ForeignKeyChecksStatements reports false; a TRUNCATE of every table with references between
+// them is checked as a whole
+func (postgresAdmin) ForeignKeyChecksStatements() (string, string, bool) { return "", "", false }
+
+func (postgresAdmin) TableExistsQuery() (string, bool) {
+	return "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = $1", true
+}
+
+// MigrationFailureNote returns ""; migrations run in a transaction, DDL included
+func (postgresAdmin) MigrationFailureNote() string { return "" }
+
+func (postgresAdmin) SchemaFixer() (schemaFixer, bool) { return postgresSchemaFixer{}, true }
+
+func (postgresAdmin) SchemaSearchQuery() (schemaSearchQuery, bool) {
+	return postgresSchemaSearchQuery, true
+}
+
+func (postgresAdmin) TableReadsQuery(schema string) (string, []interface{}, bool) {
+	query, params := getPostgresTableReadsQuery(schema)
+	return query, params, true
+}
+
+func (postgresAdmin) SchemaChangeTriggerStatements() ([]string, bool) {
+	return schemaChangeTriggerStatements(), true
+}
+
+func (postgresAdmin) TextSearcher() (textSearcher, error) { return postgresTextSearcher{}, nil }
+
+func (postgresAdmin) JSONExplorer() (jsonExplorer, error) { return postgresJSONExplorer{}, nil }
+
+func (postgresAdmin) SpatialCatalog() (spatialCatalog, error) { return postGISCatalog{}, nil }
+
+func (postgresAdmin) InferredTypes() inferredTypeNames { return postgresInferredTypes }
+
+// mysqlAdmin holds the MySQL administration queries
+type mysqlAdmin struct{}
+
+func (mysqlAdmin) ActiveSessionsQuery(includeIdle bool) (string, bool) {
+	return getMySQLActiveSessionsQuery(includeIdle), true
+}
+
+func (mysqlAdmin) SessionQuery() (string, bool) { return getMySQLSessionQuery(), true }
+
+// SessionRunning reports a connection not sleeping; MySQL shows the command of a thread
+func (mysqlAdmin) SessionRunning(state string) bool { return !strings.EqualFold(state, "Sleep") }
+
+// SessionIdleInTransaction reports a sleeping connection; a transaction between statements
+// shows as one
+func (mysqlAdmin) SessionIdleInTransaction(state string) bool {
+	return strings.EqualFold(state, "Sleep")
+}
+
+func (mysqlAdmin) KillSessionStatement(sessionID int64, terminate bool) (string, bool) {
+	if terminate {
+		return fmt.Sprintf("KILL %d", sessionID), false
+	}
+	return fmt.Sprintf("KILL QUERY %d", sessionID), false
+}
+
+func (mysqlAdmin) LockQueries() (string, string, bool) {
+	return getMySQLLockWaitsQuery(), getMySQLHeldLocksQuery(), true
+}
+
+func (mysqlAdmin) IndexBuilder() (indexBuilder, error) { return mysqlIndexBuilder{}, nil }
+
+func (mysqlAdmin) IndexAdvisor() (indexAdvisor, error) { return mysqlIndexAdvisor{}, nil }
+
+func (mysqlAdmin) Reindexer() (reindexer, error) { return mysqlReindexer{}, nil }
+
+func (mysqlAdmin) IndexStates() (indexStateLoader, error) {
+	return nil, errors.New("InnoDB does not expose free space per index; use reindex with dry_run to find fragmented tables")
+}
+
+// UnusedIndexQueries binds the schema twice, once for the default schema; InnoDB refuses to
+// drop the index a foreign key relies on
+func (mysqlAdmin) UnusedIndexQueries(schema string, maxScans int, minBytes int64, limit int) (unusedIndexQueries, bool) {
+	return unusedIndexQueries{
+		statsAge:            getMySQLStatsAgeQuery(),
+		indexes:             getMySQLUnusedIndexesQuery(),
+		params:              []interface{}{schema, schema, maxScans, minBytes, limit},
+		engine:              "MySQL",
+		foreignKeyPinsIndex: true,
+		hideHint:            "On MySQL 8.0, ALTER TABLE ... ALTER INDEX ... INVISIBLE hides an index from the optimizer without dropping it, so you can confirm nothing slows down first.",
+	}, true
+}
+
+func (mysqlAdmin) IndexDefinitionsQuery(schema, table string) (string, []interface{}, bool) {
+	return getMySQLIndexDefinitionsQuery(), []interface{}{schema, schema, table, table}, true
+}
+
+func (mysqlAdmin) DropIndexStatement(schema, table, index string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP INDEX %s;", quoteIdentifier("mysql", qualifiedName(schema, table)), quoteIdentifier("mysql", index))
+}
+
+func (mysqlAdmin) RowEstimatesQuery() (string, bool, bool) {
+	return getMySQLRowEstimatesQuery(), false, true
+}
+
+func (mysqlAdmin) LargestObjectsQuery() (string, bool) { return getMySQLLargestObjectsQuery(), true }
+
+func (mysqlAdmin) TableUsageQuery(schema string) (string, []interface{}, bool) {
+	query, params := getMySQLTableUsageQuery(schema)
+	return query, params, true
+}
+
+func (mysqlAdmin) StatisticsAnalyzer() (statisticsAnalyzer, error) {
+	return mysqlStatisticsAnalyzer{}, nil
+}
+
+func (mysqlAdmin) TableMaintainer() (tableMaintainer, error) { return mysqlMaintainer{}, nil }
+
+func (mysqlAdmin) PartitionReader() (partitionReader, error) { return mysqlPartitionReader{}, nil }
+
+func (mysqlAdmin) PartitionManager() (partitionManager, error) { return mysqlPartitionManager{}, nil }
+
+func (mysqlAdmin) TableHierarchyQuery() (string, error) {
+	return "", errors.New("MySQL has no table inheritance; use get_partitions for the partitions of a MySQL table")
+}
+
+// ConstraintStatusSections reports false: MySQL constraints are always validated and never
+// deferrable, and it has no exclusion constraints
+func (mysqlAdmin) ConstraintStatusSections(string, string) ([]reportSection, bool) { return nil, false }
+
+func (mysqlAdmin) DDLRunner() (ddlRunner, error) { return mysqlDDLRunner{}, nil }
+
+func (mysqlAdmin) RenamePlanner() (renamePlanner, error) { return planMySQLRename, nil }
+
+func (mysqlAdmin) TableCloner() (tableCloner, error) { return mysqlTableCloner{}, nil }
+
+func (mysqlAdmin) EnumDriftQueries(schema, table, column string) (enumDriftQueries, bool) {
+	return getMySQLEnumDriftQueries(schema, table, column), true
+}
+
+func (mysqlAdmin) SequenceFixer() (sequenceFixer, error) { return mysqlSequenceFixer{}, nil }
+
+func (mysqlAdmin) ObjectCommenter() (objectCommenter, error) { return mysqlCommenter{}, nil }
+
+func (mysqlAdmin) TablespaceSections(tablespace string, limit int) ([]reportSection, bool) {
+	return mysqlTablespaceSections(tablespace, limit), true
+}
+
+func (mysqlAdmin) CollationSections(schema string, limit int) ([]reportSection, bool) {
+	return mysqlCollationSections(schema, limit), true
+}
+
+func (mysqlAdmin) PrivilegeSections(role, schema string) ([]reportSection, bool) {
+	return mysqlPrivilegeSections(role, schema), true
+}
+
+// ForeignServerSections reports false; the CREATE SERVER entries of the FEDERATED engine are
+// not covered
+func (mysqlAdmin) ForeignServerSections(string) ([]reportSection, bool) { return nil, false }
+
+// MaterializedViewsQuery reports false, as MySQL has no materialized views
+func (mysqlAdmin) MaterializedViewsQuery() (string, bool) { return "", false }
+
+func (mysqlAdmin) SettingsLoader() (settingsLoader, error) { return loadMySQLSettings, nil }
+
+func (mysqlAdmin) ReplicationStatusReader() (replicationStatusReader, error) {
+	return readMySQLReplicationStatus, nil
+}
+
+func (mysqlAdmin) ReplicationSlotManager() (replicationSlotManager, error) {
+	return mysqlSlotManager{}, nil
+}
+
+func (mysqlAdmin) UserManager() (userManager, error) { return mysqlUserManager{}, nil }
+
+func (mysqlAdmin) GrantPlanner() (grantPlanner, error) { return mysqlGrantStatements, nil }
+
+// HypertableQueries reports false, as TimescaleDB only extends PostgreSQL
+func (mysqlAdmin) HypertableQueries(string) (hypertableQueries, bool) {
+	return hypertableQueries{}, false
+}
+
+// Publisher returns an error, as MySQL replicates from its binary log without publications
+func (mysqlAdmin) Publisher() (publisher, error) { return nil, errAdminUnsupported }
+
+func (mysqlAdmin) ChangeReader() (changeReader, error) { return readMySQLChanges, nil }
+
+func (mysqlAdmin) TimeBucket(column, bucket string) (string, bool) {
+	return mysqlTimeBucket(column, bucket), true
+}
+
+// VectorSearcher returns an error; the VECTOR type of MySQL 9 has no distance functions
+// outside HeatWave
+func (mysqlAdmin) VectorSearcher() (vectorSearcher, error) { return nil, errAdminUnsupported }
+
+func (mysqlAdmin) SequenceUsageNote() string {
+	return "information_schema.TABLES.AUTO_INCREMENT may be cached (information_schema_stats_expiry), so the last values can lag behind."
+}
+
+func (mysqlAdmin) DumpTool() (dumpTool, error) { return mysqlDumpTool{}, nil }
+
+// CSVExportCommand reports false; SELECT ... INTO OUTFILE writes on the server, not the client
+func (mysqlAdmin) CSVExportCommand() (csvExportCommand, bool) { return nil, false }
+
+// TruncateTablesStatement reports false, as MySQL truncates one table per statement
+func (mysqlAdmin) TruncateTablesStatement([]string) (string, bool) { return "", false }
+
+func (mysqlAdmin) ForeignKeyChecksStatements() (string, string, bool) {
+	return "SET FOREIGN_KEY_CHECKS = 0", "SET FOREIGN_KEY_CHECKS = 1", true
+}
+
+func (mysqlAdmin) TableExistsQuery() (string, bool) {
+	return "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?", true
+}
+
+func (mysqlAdmin) MigrationFailureNote() string {
+	return "MySQL commits DDL implicitly, so part of this migration may have been applied"
+}
+
+func (mysqlAdmin) SchemaFixer() (schemaFixer, bool) { return mysqlSchemaFixer{}, true }
+
+func (mysqlAdmin) SchemaSearchQuery() (schemaSearchQuery, bool) { return mysqlSchemaSearchQuery, true }
+
+func (mysqlAdmin) TableReadsQuery(schema string) (string, []interface{}, bool) {
+	query, params := getMySQLTableReadsQuery(schema)
+	return query, params, true
+}
+
+// SchemaChangeTriggerStatements reports false; MySQL has no triggers on DDL
+func (mysqlAdmin) SchemaChangeTriggerStatements() ([]string, bool) { return nil, false }
+
+func (mysqlAdmin) TextSearcher() (textSearcher, error) { return mysqlTextSearcher{}, nil }
+
+func (mysqlAdmin) JSONExplorer() (jsonExplorer, error) { return mysqlJSONExplorer{}, nil }
+
+func (mysqlAdmin) SpatialCatalog() (spatialCatalog, error) { return mysqlSpatialCatalog{}, nil }
+
+func (mysqlAdmin) InferredTypes() inferredTypeNames { return mysqlInferredTypes }
+
+// TiDB lists its sessions in the MySQL information_schema.PROCESSLIST and accepts the MySQL
+// KILL statements once global kill is enabled, the default since TiDB 6.1; its lock views are
+// its own. Its information_schema.TABLES carries the MySQL row estimates and its
+// information_schema.PARTITIONS the MySQL partition layout, and it keeps table, column and
+// routine comments in the MySQL information_schema columns. It answers the MySQL character
+// set, collation and privilege queries, and caches AUTO_INCREMENT the MySQL way.
+
+func (d tidbDialect) ActiveSessionsQuery(includeIdle bool) (string, bool) {
+	return d.mysqlAdmin.ActiveSessionsQuery(includeIdle)
+}
+
+func (d tidbDialect) SessionQuery() (string, bool) { return d.mysqlAdmin.SessionQuery() }
+
+func (d tidbDialect) SessionRunning(state string) bool { return d.mysqlAdmin.SessionRunning(state) }
+
+func (d tidbDialect) KillSessionStatement(sessionID int64, terminate bool) (string, bool) {
+	return d.mysqlAdmin.KillSessionStatement(sessionID, terminate)
+}
+
+func (d tidbDialect) RowEstimatesQuery() (string, bool, bool) {
+	return d.mysqlAdmin.RowEstimatesQuery()
+}
+
+func (d tidbDialect) PartitionReader() (partitionReader, error) {
+	return d.mysqlAdmin.PartitionReader()
+}
+
+func (d tidbDialect) TableHierarchyQuery() (string, error) { return d.mysqlAdmin.TableHierarchyQuery() }
+
+func (d tidbDialect) ObjectCommenter() (objectCommenter, error) {
+	return d.mysqlAdmin.ObjectCommenter()
+}
+
+func (d tidbDialect) CollationSections(schema string, limit int) ([]reportSection, bool) {
+	return d.mysqlAdmin.CollationSections(schema, limit)
+}
+
+func (d tidbDialect) PrivilegeSections(role, schema string) ([]reportSection, bool) {
+	return d.mysqlAdmin.PrivilegeSections(role, schema)
+}
+
+func (d tidbDialect) SequenceUsageNote() string { return d.mysqlAdmin.SequenceUsageNote() }
+
+func (d tidbDialect) SchemaFixer() (schemaFixer, bool) { return d.mysqlAdmin.SchemaFixer() }
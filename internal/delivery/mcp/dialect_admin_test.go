@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// adminSupport lists the administration queries a dialect answers
+func adminSupport(d Dialect) []string {
+	var supported []string
+	add := func(name string, ok bool) {
+		if ok {
+			supported = append(supported, name)
+		}
+	}
+	_, ok := d.ActiveSessionsQuery(false)
+	add("active_sessions", ok)
+	_, ok = d.SessionQuery()
+	add("session", ok)
+	statement, _ := d.KillSessionStatement(1, true)
+	add("kill_session", statement != "")
+	_, _, ok = d.LockQueries()
+	add("locks", ok)
+	_, err := d.IndexBuilder()
+	add("create_index", err == nil)
+	_, err = d.IndexAdvisor()
+	add("index_advisor", err == nil)
+	_, err = d.Reindexer()
+	add("reindex", err == nil)
+	_, err = d.IndexStates()
+	add("index_bloat", err == nil)
+	_, ok = d.UnusedIndexQueries("", 0, 0, 10)
+	add("unused_indexes", ok)
+	_, _, ok = d.IndexDefinitionsQuery("", "")
+	add("duplicate_indexes", ok)
+	_, _, ok = d.RowEstimatesQuery()
+	add("row_counts", ok)
+	_, ok = d.LargestObjectsQuery()
+	add("largest_objects", ok)
+	_, _, ok = d.TableUsageQuery("")
+	add("table_usage", ok)
+	_, err = d.StatisticsAnalyzer()
+	add("analyze_table", err == nil)
+	_, err = d.TableMaintainer()
+	add("run_maintenance", err == nil)
+	_, err = d.PartitionReader()
+	add("partitions", err == nil)
+	_, err = d.PartitionManager()
+	add("manage_partitions", err == nil)
+	_, err = d.TableHierarchyQuery()
+	add("table_hierarchy", err == nil)
+	_, ok = d.ConstraintStatusSections("", "")
+	add("constraint_status", ok)
+	_, err = d.DDLRunner()
+	add("run_ddl", err == nil)
+	_, err = d.RenamePlanner()
+	add("rename_object", err == nil)
+	_, err = d.TableCloner()
+	add("clone_table", err == nil)
+	_, ok = d.EnumDriftQueries("", "orders", "status")
+	add("enum_drift", ok)
+	_, err = d.SequenceFixer()
+	add("fix_sequences", err == nil)
+	_, err = d.ObjectCommenter()
+	add("object_comments", err == nil)
+	_, ok = d.TablespaceSections("", 10)
+	add("tablespaces", ok)
+	_, ok = d.CollationSections("", 10)
+	add("collations", ok)
+	_, ok = d.PrivilegeSections("", "")
+	add("privileges", ok)
+	_, ok = d.ForeignServerSections("")
+	add("foreign_servers", ok)
+	_, ok = d.MaterializedViewsQuery()
+	add("materialized_views", ok)
+	_, err = d.SettingsLoader()
+	add("settings", err == nil)
+	_, err = d.ReplicationStatusReader()
+	add("replication_status", err == nil)
+	_, err = d.ReplicationSlotManager()
+	add("replication_slots", err == nil)
+	_, err = d.UserManager()
+	add("manage_users", err == nil)
+	_, err = d.GrantPlanner()
+	add("manage_grants", err == nil)
+	_, ok = d.HypertableQueries("")
+	add("hypertables", ok)
+	_, err = d.Publisher()
+	add("publications", err == nil)
+	_, err = d.ChangeReader()
+	add("tail_changes", err == nil)
+	_, ok = d.TimeBucket("created_at", "day")
+	add("timeseries", ok)
+	_, err = d.VectorSearcher()
+	add("vector_search", err == nil)
+	_, err = d.DumpTool()
+	add("backup", err == nil)
+	_, ok = d.CSVExportCommand()
+	add("csv_export", ok)
+	_, ok = d.TableExistsQuery()
+	add("migrations", ok)
+	_, ok = d.SchemaFixer()
+	add("schema_fixes", ok)
+	_, err = d.TextSearcher()
+	add("search_text", err == nil)
+	_, err = d.JSONExplorer()
+	add("explore_json", err == nil)
+	_, err = d.SpatialCatalog()
+	add("spatial_summary", err == nil)
+	sort.Strings(supported)
+	return supported
+}
+
+// features joins groups of administration queries into the sorted list adminSupport returns
+func features(groups ...[]string) []string {
+	var all []string
+	for _, group := range groups {
+		all = append(all, group...)
+	}
+	sort.Strings(all)
+	return all
+}
+
+func TestAdminDialectSupport(t *testing.T) {
+	sessions := []string{"active_sessions", "kill_session", "session"}
+	indexes := []string{"create_index", "duplicate_indexes", "index_advisor", "reindex", "unused_indexes"}
+	tables := []string{"analyze_table", "largest_objects", "manage_partitions", "partitions", "row_counts", "run_maintenance", "table_usage"}
+	schemaChanges := []string{"clone_table", "enum_drift", "fix_sequences", "object_comments", "rename_object", "run_ddl"}
+	server := []string{"collations", "manage_grants", "manage_users", "privileges", "replication_slots", "replication_status", "settings", "tablespaces"}
+	changes := []string{"tail_changes", "timeseries"}
+	backups := []string{"backup", "migrations"}
+	search := []string{"explore_json", "search_text", "spatial_summary"}
+	expected := map[string][]string{
+		"postgres": features(sessions, []string{"locks"}, indexes, tables, schemaChanges, server, changes, backups, search,
+			[]string{"constraint_status", "csv_export", "foreign_servers", "hypertables", "index_bloat", "materialized_views", "publications", "schema_fixes", "table_hierarchy", "vector_search"}),
+		// InnoDB does not expose free space per index, MySQL has no table inheritance, and
+		// its extensions are storage engines rather than catalog objects
+		"mysql": features(sessions, []string{"locks"}, indexes, tables, schemaChanges, server, changes, backups, search, []string{"schema_fixes"}),
+		// TiDB shares the MySQL process list, KILL statements and information_schema tables,
+		// not the InnoDB lock views
+		"tidb": features(sessions, []string{"collations", "object_comments", "partitions", "privileges", "row_counts", "schema_fixes"}),
+		// SQLite keeps the migrations table in sqlite_master and rebuilds tables to fix them
+		"sqlite": {"migrations", "schema_fixes"},
+	}
+	for name, dialect := range dialects {
+		assert.Equal(t, expected[name], adminSupport(dialect), name)
+	}
+}
+
+func TestAdminDialectSessions(t *testing.T) {
+	pg, _ := lookupDialect("postgres")
+	tidb, _ := lookupDialect("tidb")
+
+	statement, reportsSignal := pg.KillSessionStatement(42, false)
+	assert.Equal(t, "SELECT pg_cancel_backend(42)", statement)
+	assert.True(t, reportsSignal)
+	statement, reportsSignal = tidb.KillSessionStatement(42, true)
+	assert.Equal(t, "KILL 42", statement)
+	assert.False(t, reportsSignal)
+
+	assert.True(t, pg.SessionRunning("active"))
+	assert.False(t, pg.SessionRunning("idle in transaction"))
+	assert.True(t, pg.SessionIdleInTransaction("idle in transaction (aborted)"))
+	assert.True(t, tidb.SessionRunning("Query"))
+	assert.False(t, tidb.SessionRunning("Sleep"))
+}
+
+func TestAdminDialectIndexes(t *testing.T) {
+	pg, _ := lookupDialect("postgres")
+	mysql, _ := lookupDialect("mysql")
+
+	assert.Equal(t, `DROP INDEX CONCURRENTLY "app"."orders_idx";`, pg.DropIndexStatement("app", "orders", "orders_idx"))
+	assert.Equal(t, "ALTER TABLE `app`.`orders` DROP INDEX `orders_idx`;", mysql.DropIndexStatement("app", "orders", "orders_idx"))
+
+	_, err := mysql.IndexStates()
+	assert.EqualError(t, unsupportedAdminError("index bloat", "mysql", err), "InnoDB does not expose free space per index; use reindex with dry_run to find fragmented tables")
+	cockroach, _ := lookupDialect("cockroachdb")
+	_, err = cockroach.Reindexer()
+	assert.EqualError(t, unsupportedAdminError("reindex", "cockroachdb", err), "unsupported database type for reindex: cockroachdb")
+}
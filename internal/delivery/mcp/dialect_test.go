@@ -0,0 +1,303 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupDialect(t *testing.T) {
+	dialect, ok := lookupDialect("PostgreSQL")
+	assert.False(t, ok)
+	assert.Nil(t, dialect)
+
+	dialect, ok = lookupDialect("Postgres")
+	assert.True(t, ok)
+	assert.Equal(t, "postgres", dialect.Name())
+
+	dialect, ok = lookupDialect("mysql")
+	assert.True(t, ok)
+	assert.Equal(t, "mysql", dialect.Name())
+
+	dialect, ok = lookupDialect("sqlite")
+	assert.True(t, ok)
+	assert.Equal(t, "sqlite", dialect.Name())
+
+	assert.Equal(t, "mysql", dialectFor("oracle").Name())
+}
+
+func TestDialectQuoting(t *testing.T) {
+	pg, _ := lookupDialect("postgres")
+	my, _ := lookupDialect("mysql")
+
+	assert.Equal(t, `"my""table"`, pg.QuoteIdent(`my"table`))
+	assert.Equal(t, "`my``table`", my.QuoteIdent("my`table"))
+	assert.Equal(t, `'it''s \n'`, pg.QuoteLiteral(`it's \n`))
+	assert.Equal(t, `'it''s \\n'`, my.QuoteLiteral(`it's \n`))
+	assert.Equal(t, "$3", pg.Placeholder(3))
+	assert.Equal(t, "?", my.Placeholder(3))
+	assert.Equal(t, "RANDOM()", pg.RandomFunc())
+	assert.Equal(t, "RAND()", my.RandomFunc())
+	assert.Equal(t, "LIMIT 10", my.LimitClause(10))
+	assert.Equal(t, "LIMIT 10", pg.PageClause(10, 0))
+	assert.Equal(t, "LIMIT 10 OFFSET 20", my.PageClause(10, 20))
+}
+
+func TestDialectQueries(t *testing.T) {
+	pg, _ := lookupDialect("postgres")
+	my, _ := lookupDialect("mysql")
+
+	query, ok := pg.TypeQuery("")
+	assert.True(t, ok)
+	assert.Contains(t, query, "pg_type")
+	_, ok = my.TypeQuery("")
+	assert.False(t, ok)
+
+	schema, _ := pg.SchemaMetadataQueries("")
+	assert.Equal(t, "public", schema)
+	schema, _ = my.SchemaMetadataQueries("")
+	assert.Equal(t, "", schema)
+
+	assert.Equal(t, "EXPLAIN (FORMAT JSON) SELECT 1", pg.ExplainQuery("SELECT 1"))
+	assert.Equal(t, "EXPLAIN FORMAT=JSON SELECT 1", my.ExplainQuery("SELECT 1"))
+}
+
+func TestClickHouseDialect(t *testing.T) {
+	ch, ok := lookupDialect("ClickHouse")
+	assert.True(t, ok)
+	assert.Equal(t, "`my``table`", ch.QuoteIdent("my`table"))
+	assert.Equal(t, `'it''s a\\b'`, ch.QuoteLiteral(`it's a\b`))
+	assert.Equal(t, "rand()", ch.RandomFunc())
+	_, ok = ch.TypeQuery("")
+	assert.False(t, ok)
+
+	_, queries := ch.SchemaMetadataQueries("")
+	assert.Contains(t, queries.columns, "c.database = currentDatabase()")
+	assert.Nil(t, queries.params)
+	_, queries = ch.SchemaMetadataQueries("web's")
+	assert.Equal(t, 2, strings.Count(queries.indexes, "database = 'web''s'"))
+	assert.Contains(t, ch.ConstraintQuery("events", "primary key"), "'PRIMARY KEY' = 'PRIMARY KEY'")
+}
+
+func TestCockroachDialect(t *testing.T) {
+	crdb, ok := lookupDialect("CockroachDB")
+	assert.True(t, ok)
+	assert.Equal(t, "cockroachdb", crdb.Name())
+	assert.Equal(t, `"my""table"`, crdb.QuoteIdent(`my"table`))
+	assert.Equal(t, "$2", crdb.Placeholder(2))
+	_, err := explainQuery("cockroachdb", "SELECT 1")
+	assert.Error(t, err)
+
+	// The PostgreSQL catalog queries are shared, the statistics are not
+	schema, _ := crdb.SchemaMetadataQueries("")
+	assert.Equal(t, "public", schema)
+	for _, query := range crdb.DatabaseStatsQueries(true) {
+		assert.NotContains(t, query, "pg_stat_")
+		assert.NotContains(t, query, "pg_buffercache")
+	}
+}
+
+func TestTiDBDialect(t *testing.T) {
+	tidb, ok := lookupDialect("TiDB")
+	assert.True(t, ok)
+	assert.Equal(t, "tidb", tidb.Name())
+	assert.Equal(t, "`my``table`", tidb.QuoteIdent("my`table"))
+	_, err := explainQuery("tidb", "SELECT 1")
+	assert.Error(t, err)
+
+	queries := strings.Join(tidb.DatabaseStatsQueries(false), "\n")
+	assert.Contains(t, queries, "information_schema.tidb_hot_regions")
+	assert.Contains(t, queries, "information_schema.cluster_info")
+	assert.Contains(t, queries, "information_schema.tiflash_replica")
+	assert.NotContains(t, strings.Join(tidb.DatabaseStatsQueries(true), "\n"), "Innodb_buffer_pool")
+}
+
+func TestYugabyteDialect(t *testing.T) {
+	yb, ok := lookupDialect("Yugabyte")
+	assert.True(t, ok)
+	assert.Equal(t, "yugabyte", yb.Name())
+	assert.Equal(t, "$2", yb.Placeholder(2))
+	assert.Contains(t, yb.ExplainQuery("SELECT 1"), "FORMAT JSON")
+
+	// Sizes come from the tablets, not from the PostgreSQL storage functions
+	schema, _ := yb.SchemaMetadataQueries("")
+	assert.Equal(t, "public", schema)
+	queries := strings.Join(yb.DatabaseStatsQueries(true), "\n")
+	assert.Contains(t, queries, "yb_servers()")
+	assert.Contains(t, queries, "yb_table_properties(c.oid)")
+	assert.Contains(t, queries, "yb_local_tablets")
+	assert.NotContains(t, queries, "pg_database_size")
+	assert.NotContains(t, queries, "pg_total_relation_size")
+	assert.NotContains(t, strings.Join(yb.DatabaseStatsQueries(false), "\n"), "yb_local_tablets")
+}
+
+func TestGreenplumDialect(t *testing.T) {
+	gp, ok := lookupDialect("Greenplum")
+	assert.True(t, ok)
+	assert.Equal(t, "greenplum", gp.Name())
+	assert.Equal(t, "$2", gp.Placeholder(2))
+	assert.Contains(t, gp.ExplainQuery("SELECT 1"), "FORMAT JSON")
+
+	// Sizes are summed over the segments rather than read on the coordinator
+	queries := strings.Join(gp.DatabaseStatsQueries(true), "\n")
+	assert.Contains(t, queries, "gp_segment_configuration")
+	assert.Contains(t, queries, "gp_toolkit.gp_size_of_database")
+	assert.Contains(t, queries, "gp_toolkit.gp_skew_coefficients")
+	assert.NotContains(t, queries, "pg_database_size")
+	assert.NotContains(t, strings.Join(gp.DatabaseStatsQueries(false), "\n"), "gp_skew_coefficients")
+}
+
+func TestTrinoDialect(t *testing.T) {
+	trino, ok := lookupDialect("Trino")
+	assert.True(t, ok)
+	assert.Equal(t, `"hive"."web"."events"`, quoteIdentifier("trino", "hive.web.events"))
+	assert.Equal(t, `'it''s'`, trino.QuoteLiteral("it's"))
+	assert.Equal(t, "OFFSET 20 LIMIT 10", trino.PageClause(10, 20))
+	_, err := explainQuery("trino", "SELECT 1")
+	assert.Error(t, err)
+
+	_, queries := trino.SchemaMetadataQueries("")
+	assert.Contains(t, queries.columns, "c.table_schema = current_schema")
+	assert.Nil(t, queries.params)
+	_, queries = trino.SchemaMetadataQueries("web")
+	assert.Contains(t, queries.columns, "c.table_schema = 'web'")
+	assert.Contains(t, trino.SchemaQuery("web", true), "s.table_schem = 'web'")
+	assert.NotContains(t, trino.SchemaQuery("", true), "'system'")
+}
+
+func TestDatabricksDialect(t *testing.T) {
+	dbx, ok := lookupDialect("Databricks")
+	assert.True(t, ok)
+	assert.Equal(t, "`main`.`web`.`my``table`", quoteIdentifier("databricks", "main.web.my`table"))
+	assert.Equal(t, `'it\'s a\\b'`, dbx.QuoteLiteral(`it's a\b`))
+	assert.Equal(t, ":p2", dbx.Placeholder(2))
+	_, err := explainQuery("databricks", "SELECT 1")
+	assert.Error(t, err)
+
+	// Unity Catalog names resolve against the catalog and schema of the connection
+	assert.Contains(t, dbx.ConstraintQuery("events", "foreign key"),
+		"tc.table_catalog = current_catalog() AND tc.table_schema = current_schema() AND tc.table_name = 'events' AND tc.constraint_type = 'FOREIGN KEY'")
+	assert.Contains(t, dbx.IndexQuery("main.web.events", false),
+		"c.table_catalog = 'main' AND c.table_schema = 'web' AND c.table_name = 'events'")
+	assert.Contains(t, dbx.IndexQuery("", false), "c.table_catalog = current_catalog() AND c.table_schema <> 'information_schema'")
+	assert.Contains(t, dbx.ViewQuery("web.daily", true), "AND v.table_schema = 'web' AND v.table_name = 'daily'")
+	assert.Contains(t, dbx.SchemaQuery("main.web", true), "AND s.catalog_name = 'main' AND s.schema_name = 'web'")
+	assert.Contains(t, dbx.SchemaQuery("", false), "s.catalog_name <> 'system'")
+	assert.NotContains(t, dbx.SchemaQuery("", true), "'system'")
+
+	_, queries := dbx.SchemaMetadataQueries("")
+	assert.Contains(t, queries.columns, "c.table_schema = current_schema()")
+	assert.Nil(t, queries.params)
+	_, queries = dbx.SchemaMetadataQueries("web")
+	assert.Contains(t, queries.constraints, "tc.table_schema = 'web'")
+	assert.NotContains(t, strings.Join(dbx.DatabaseStatsQueries(false), "\n"), "system.query.history")
+}
+
+func TestBigQueryDialect(t *testing.T) {
+	bq, ok := lookupDialect("BigQuery")
+	assert.True(t, ok)
+	assert.Equal(t, "`my\\`table`", bq.QuoteIdent("my`table"))
+	assert.Equal(t, `'it\'s a\\b'`, bq.QuoteLiteral(`it's a\b`))
+	assert.Equal(t, "RAND()", bq.RandomFunc())
+	assert.Equal(t, "", bq.ExplainQuery("SELECT 1"))
+	_, err := explainQuery("bigquery", "SELECT 1")
+	assert.Error(t, err)
+
+	_, queries := bq.SchemaMetadataQueries("")
+	assert.Contains(t, queries.columns, "FROM INFORMATION_SCHEMA.COLUMNS c")
+	assert.Nil(t, queries.params)
+	_, queries = bq.SchemaMetadataQueries("sales")
+	assert.Contains(t, queries.constraints, "FROM `sales`.INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc")
+	assert.Contains(t, bq.ViewQuery("daily", true), "table_type IN ('VIEW', 'MATERIALIZED VIEW') AND table_name = 'daily'")
+}
+
+func TestSpannerDialect(t *testing.T) {
+	sp, ok := lookupDialect("Spanner")
+	assert.True(t, ok)
+	assert.Equal(t, "`my\\`table`", sp.QuoteIdent("my`table"))
+	assert.Equal(t, `'it\'s'`, sp.QuoteLiteral("it's"))
+	assert.Equal(t, "@p2", sp.Placeholder(2))
+	_, err := explainQuery("spanner", "SELECT 1")
+	assert.Error(t, err)
+
+	assert.Contains(t, sp.IndexQuery("Albums", true), "i.table_schema = '' AND i.table_name = 'Albums'")
+	assert.Contains(t, sp.IndexQuery("", true), "AS storing_columns")
+	constraints := sp.ConstraintQuery("sales.Albums", "interleave")
+	assert.Contains(t, constraints, "c.table_schema = 'sales' AND c.table_name = 'Albums'")
+	assert.Contains(t, constraints, "c.constraint_type = 'INTERLEAVE'")
+	assert.Contains(t, constraints, "CK_IS_NOT_NULL_")
+
+	schema, queries := sp.SchemaMetadataQueries("")
+	assert.Equal(t, "", schema)
+	assert.Contains(t, queries.columns, "c.table_schema = ''")
+	assert.Contains(t, queries.constraints, "'INTERLEAVE'")
+	assert.NotContains(t, sp.SchemaQuery("", false)+strings.Join(sp.DatabaseStatsQueries(true), "\n"), "information_schema.")
+	assert.Contains(t, strings.Join(sp.DatabaseStatsQueries(true), "\n"), "SPANNER_SYS.OLDEST_ACTIVE_QUERIES")
+	assert.NotContains(t, strings.Join(sp.DatabaseStatsQueries(false), "\n"), "OLDEST_ACTIVE_QUERIES")
+}
+
+func TestHANADialect(t *testing.T) {
+	hana, ok := lookupDialect("HANA")
+	assert.True(t, ok)
+	assert.Equal(t, `"my""table"`, hana.QuoteIdent(`my"table`))
+	assert.Equal(t, "'it''s'", hana.QuoteLiteral("it's"))
+	assert.Equal(t, "?", hana.Placeholder(2))
+	_, err := explainQuery("hana", "SELECT 1")
+	assert.Error(t, err)
+
+	assert.Contains(t, hana.IndexQuery("ORDERS", true), "i.SCHEMA_NAME = CURRENT_SCHEMA AND i.TABLE_NAME = 'ORDERS'")
+	constraints := hana.ConstraintQuery("SALES.ORDERS", "foreign key")
+	assert.Contains(t, constraints, "r.SCHEMA_NAME = 'SALES' AND r.TABLE_NAME = 'ORDERS'")
+	assert.Contains(t, constraints, "c.constraint_type = 'FOREIGN KEY'")
+	assert.Contains(t, hana.ViewQuery("SALES.OPEN_ORDERS", false), "v.SCHEMA_NAME = 'SALES' AND v.VIEW_NAME = 'OPEN_ORDERS'")
+	assert.Contains(t, hana.SchemaQuery("", false), `NOT LIKE '\_SYS\_%' ESCAPE '\'`)
+
+	schema, queries := hana.SchemaMetadataQueries("")
+	assert.Equal(t, "", schema)
+	assert.Contains(t, queries.columns, "c.SCHEMA_NAME = CURRENT_SCHEMA")
+	assert.Contains(t, queries.constraints, "SYS.REFERENTIAL_CONSTRAINTS")
+	assert.Contains(t, strings.Join(hana.DatabaseStatsQueries(true), "\n"), "M_SQL_PLAN_CACHE")
+	assert.NotContains(t, strings.Join(hana.DatabaseStatsQueries(false), "\n"), "M_SQL_PLAN_CACHE")
+}
+
+func TestFirebirdDialect(t *testing.T) {
+	fb, ok := lookupDialect("Firebird")
+	assert.True(t, ok)
+	assert.Equal(t, `"my""table"`, fb.QuoteIdent(`my"table`))
+	assert.Equal(t, "ROWS 5", fb.LimitClause(5))
+	assert.Equal(t, "ROWS 21 TO 30", fb.PageClause(10, 20))
+	assert.Equal(t, `SELECT * FROM "CUSTOMERS" ORDER BY RAND() ROWS 5`,
+		buildSampleDataQuery("firebird", "CUSTOMERS", 5, "", "", true))
+	_, err := explainQuery("firebird", "SELECT 1")
+	assert.Error(t, err)
+
+	indexes := fb.IndexQuery("INVOICES", true)
+	assert.Contains(t, indexes, "FROM RDB$INDICES i")
+	assert.Contains(t, indexes, "i.RDB$RELATION_NAME = 'INVOICES'")
+	assert.Contains(t, indexes, "AS selectivity")
+	constraints := fb.ConstraintQuery("INVOICES", "foreign key")
+	assert.Contains(t, constraints, "RDB$REF_CONSTRAINTS")
+	assert.Contains(t, constraints, "c.RDB$CONSTRAINT_TYPE = 'FOREIGN KEY'")
+	assert.Contains(t, constraints, "c.RDB$CONSTRAINT_TYPE <> 'NOT NULL'")
+	assert.Contains(t, fb.ViewQuery("OPEN_INVOICES", true), "CAST(r.RDB$VIEW_SOURCE AS VARCHAR(32765)) AS view_definition")
+	assert.Contains(t, fb.ViewQuery("", false), "r.RDB$VIEW_BLR IS NOT NULL")
+
+	domains, ok := fb.TypeQuery("D_AMOUNT")
+	assert.True(t, ok)
+	assert.Contains(t, domains, "f.RDB$FIELD_NAME = 'D_AMOUNT'")
+	assert.Contains(t, domains, "'NUMERIC(' || f.RDB$FIELD_PRECISION")
+
+	schema, queries := fb.SchemaMetadataQueries("PUBLIC")
+	assert.Equal(t, "", schema)
+	assert.Contains(t, queries.columns, "cl.RDB$COLLATION_NAME")
+	assert.Contains(t, strings.Join(fb.DatabaseStatsQueries(true), "\n"), "MON$STATEMENTS")
+	assert.NotContains(t, strings.Join(fb.DatabaseStatsQueries(false), "\n"), "MON$STATEMENTS")
+}
+
+func TestSampleDataQueryUsesDialect(t *testing.T) {
+	assert.Equal(t, `SELECT * FROM "users" ORDER BY RANDOM() LIMIT 5`,
+		buildSampleDataQuery("postgres", "users", 5, "", "", true))
+	assert.Equal(t, "SELECT * FROM `users` WHERE id > 1 ORDER BY id LIMIT 5",
+		buildSampleDataQuery("mysql", "users", 5, "id > 1", "id", false))
+}
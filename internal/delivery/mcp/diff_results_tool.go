@@ -0,0 +1,346 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// DiffResultsTool handles comparing the results of two queries
+type DiffResultsTool struct {
+	BaseToolType
+}
+
+// NewDiffResultsTool creates a new diff results tool type
+func NewDiffResultsTool() *DiffResultsTool {
+	return &DiffResultsTool{
+		BaseToolType: BaseToolType{
+			name:        "diff_results",
+			description: "Execute two queries, possibly against different databases, and report the rows present in one result but not the other. With key columns, rows are matched by key and rows whose other columns differ are reported as changed; without key columns whole rows are compared, including duplicates. Use it to verify that a refactored reporting query returns the same data as the original, or that two environments hold the same data.",
+		},
+	}
+}
+
+// CreateTool creates a diff results tool
+func (t *DiffResultsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Diff the results of two queries, keyed by selected columns"),
+		tools.WithString("database",
+			tools.Description("Database ID to run the first query on"),
+			tools.Required(),
+		),
+		tools.WithString("query",
+			tools.Description("First SQL query"),
+			tools.Required(),
+		),
+		tools.WithString("compare_database",
+			tools.Description("Database ID to run the second query on (optional, defaults to database)"),
+		),
+		tools.WithString("compare_query",
+			tools.Description("Second SQL query (optional, defaults to query)"),
+		),
+		tools.WithArray("key_columns",
+			tools.Description("Columns identifying a row (optional, whole rows are compared when empty)"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of differing rows to list per section (default: 50)"),
+		),
+	)
+}
+
+// HandleRequest handles diff results tool requests
+func (t *DiffResultsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	query := input.requiredString("query")
+	compareDbID := input.optionalString("compare_database", targetDbID)
+	compareQuery := input.optionalString("compare_query", query)
+	keyColumns := input.stringList("key_columns")
+	limit := input.intAtLeast("limit", 50, 1)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if compareDbID == targetDbID && compareQuery == query {
+		return nil, fmt.Errorf("compare_database or compare_query must differ from database and query")
+	}
+
+	logger.Info("Diffing query results between databases %s and %s", targetDbID, compareDbID)
+
+	first, err := useCase.ExecuteQuery(ctx, targetDbID, query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query on %s: %w", targetDbID, err)
+	}
+	second, err := useCase.ExecuteQuery(ctx, compareDbID, compareQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query on %s: %w", compareDbID, err)
+	}
+
+	diff, err := diffQueryResults(first, second, keyColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	// Format the response
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Result Diff for Database %s\n\n", targetDbID))
+	response.WriteString(fmt.Sprintf("A: %s, B: %s\n\n", describeDiffSide(targetDbID, query == compareQuery, "query"),
+		describeDiffSide(compareDbID, query == compareQuery, "compare_query")))
+	response.WriteString(formatResultDiff(diff, limit))
+
+	return createTextResponse(response.String()), nil
+}
+
+// describeDiffSide labels one side of a diff by database and, when the queries differ, by query
+func describeDiffSide(dbID string, sameQuery bool, queryName string) string {
+	if sameQuery {
+		return dbID
+	}
+	return fmt.Sprintf("%s (%s)", dbID, queryName)
+}
+
+// changedRow is a row whose key exists in both results but whose other values differ
+type changedRow struct {
+	Key     []string
+	Columns []string
+	First   []string
+	Second  []string
+}
+
+// resultDiff describes the differences between two query results
+type resultDiff struct {
+	Columns       []string // columns compared
+	KeyColumns    []string // empty when whole rows are compared
+	OnlyFirst     []string // columns only present in the first result
+	OnlySecond    []string // columns only present in the second result
+	FirstRows     int
+	SecondRows    int
+	Matching      int
+	MissingFirst  [][]string // rows only in the second result
+	MissingSecond [][]string // rows only in the first result
+	Changed       []changedRow
+	DuplicateKeys int
+}
+
+// diffQueryResults compares two results on their common columns, matching rows by key columns
+// or, when no keys are given, by whole rows counted as a multiset
+func diffQueryResults(first, second *domain.QueryResult, keyColumns []string) (*resultDiff, error) {
+	diff := &resultDiff{KeyColumns: keyColumns, FirstRows: len(first.Rows), SecondRows: len(second.Rows)}
+
+	secondIndex := columnIndex(second.Columns)
+	firstIndex := columnIndex(first.Columns)
+	for _, column := range first.Columns {
+		if _, ok := secondIndex[column]; ok {
+			diff.Columns = append(diff.Columns, column)
+		} else {
+			diff.OnlyFirst = append(diff.OnlyFirst, column)
+		}
+	}
+	for _, column := range second.Columns {
+		if _, ok := firstIndex[column]; !ok {
+			diff.OnlySecond = append(diff.OnlySecond, column)
+		}
+	}
+	if len(diff.Columns) == 0 {
+		return nil, fmt.Errorf("the query results have no columns in common")
+	}
+	for _, key := range keyColumns {
+		_, inFirst := firstIndex[key]
+		_, inSecond := secondIndex[key]
+		if !inFirst || !inSecond {
+			return nil, fmt.Errorf("key column %s is not present in both results", key)
+		}
+	}
+
+	project := func(row []interface{}, index map[string]int, columns []string) []string {
+		values := make([]string, len(columns))
+		for i, column := range columns {
+			values[i] = formatDiffValue(row[index[column]])
+		}
+		return values
+	}
+	join := func(values []string) string {
+		return strings.Join(values, "\x1f")
+	}
+
+	if len(keyColumns) == 0 {
+		// Whole-row comparison: count occurrences so duplicates are diffed too
+		counts := make(map[string]int)
+		for _, row := range second.Rows {
+			counts[join(project(row, secondIndex, diff.Columns))]++
+		}
+		for _, row := range first.Rows {
+			values := project(row, firstIndex, diff.Columns)
+			key := join(values)
+			if counts[key] > 0 {
+				counts[key]--
+				diff.Matching++
+			} else {
+				diff.MissingSecond = append(diff.MissingSecond, values)
+			}
+		}
+		for _, row := range second.Rows {
+			values := project(row, secondIndex, diff.Columns)
+			key := join(values)
+			if counts[key] > 0 {
+				counts[key]--
+				diff.MissingFirst = append(diff.MissingFirst, values)
+			}
+		}
+		return diff, nil
+	}
+
+	var valueColumns []string
+	keySet := make(map[string]bool, len(keyColumns))
+	for _, key := range keyColumns {
+		keySet[key] = true
+	}
+	for _, column := range diff.Columns {
+		if !keySet[column] {
+			valueColumns = append(valueColumns, column)
+		}
+	}
+
+	secondByKey := make(map[string][]interface{}, len(second.Rows))
+	var secondOrder []string
+	for _, row := range second.Rows {
+		key := join(project(row, secondIndex, keyColumns))
+		if _, ok := secondByKey[key]; ok {
+			diff.DuplicateKeys++
+			continue
+		}
+		secondByKey[key] = row
+		secondOrder = append(secondOrder, key)
+	}
+
+	seen := make(map[string]bool, len(first.Rows))
+	for _, row := range first.Rows {
+		keyValues := project(row, firstIndex, keyColumns)
+		key := join(keyValues)
+		if seen[key] {
+			diff.DuplicateKeys++
+			continue
+		}
+		seen[key] = true
+
+		other, ok := secondByKey[key]
+		if !ok {
+			diff.MissingSecond = append(diff.MissingSecond, project(row, firstIndex, diff.Columns))
+			continue
+		}
+		change := changedRow{Key: keyValues}
+		firstValues := project(row, firstIndex, valueColumns)
+		secondValues := project(other, secondIndex, valueColumns)
+		for i, column := range valueColumns {
+			if firstValues[i] != secondValues[i] {
+				change.Columns = append(change.Columns, column)
+				change.First = append(change.First, firstValues[i])
+				change.Second = append(change.Second, secondValues[i])
+			}
+		}
+		if len(change.Columns) > 0 {
+			diff.Changed = append(diff.Changed, change)
+		} else {
+			diff.Matching++
+		}
+	}
+	for _, key := range secondOrder {
+		if !seen[key] {
+			diff.MissingFirst = append(diff.MissingFirst, project(secondByKey[key], secondIndex, diff.Columns))
+		}
+	}
+
+	return diff, nil
+}
+
+// columnIndex maps column names to their position
+func columnIndex(columns []string) map[string]int {
+	index := make(map[string]int, len(columns))
+	for i, column := range columns {
+		if _, ok := index[column]; !ok {
+			index[column] = i
+		}
+	}
+	return index
+}
+
+// formatDiffValue renders a scanned value for comparison and display
+func formatDiffValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case string, []byte:
+		return valueString(val)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// formatResultDiff renders a result diff as markdown
+func formatResultDiff(diff *resultDiff, limit int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Rows in A: %d, rows in B: %d\n", diff.FirstRows, diff.SecondRows))
+	if len(diff.KeyColumns) > 0 {
+		sb.WriteString(fmt.Sprintf("Matched by: %s\n", strings.Join(diff.KeyColumns, ", ")))
+	} else {
+		sb.WriteString("Matched by: whole rows\n")
+	}
+	sb.WriteString(fmt.Sprintf("Matching: %d, only in A: %d, only in B: %d, changed: %d\n",
+		diff.Matching, len(diff.MissingSecond), len(diff.MissingFirst), len(diff.Changed)))
+	if len(diff.OnlyFirst) > 0 {
+		sb.WriteString(fmt.Sprintf("Columns only in A (not compared): %s\n", strings.Join(diff.OnlyFirst, ", ")))
+	}
+	if len(diff.OnlySecond) > 0 {
+		sb.WriteString(fmt.Sprintf("Columns only in B (not compared): %s\n", strings.Join(diff.OnlySecond, ", ")))
+	}
+	if diff.DuplicateKeys > 0 {
+		sb.WriteString(fmt.Sprintf("Warning: %d rows with duplicate keys were skipped; the key columns do not identify rows uniquely\n", diff.DuplicateKeys))
+	}
+
+	if len(diff.MissingSecond) == 0 && len(diff.MissingFirst) == 0 && len(diff.Changed) == 0 {
+		sb.WriteString("\nThe results are identical.\n")
+		return sb.String()
+	}
+
+	writeRows := func(title string, rows [][]string) {
+		if len(rows) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("\n## %s\n\n", title))
+		sb.WriteString("| " + strings.Join(diff.Columns, " | ") + " |\n")
+		sb.WriteString("|" + strings.Repeat("---|", len(diff.Columns)) + "\n")
+		for i, row := range rows {
+			if i >= limit {
+				sb.WriteString(fmt.Sprintf("\n... %d more rows not shown\n", len(rows)-limit))
+				break
+			}
+			sb.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		}
+	}
+	writeRows("Only in A", diff.MissingSecond)
+	writeRows("Only in B", diff.MissingFirst)
+
+	if len(diff.Changed) > 0 {
+		sb.WriteString("\n## Changed\n\n")
+		sb.WriteString("| Key | Column | A | B |\n")
+		sb.WriteString("|-----|--------|---|---|\n")
+		for i, change := range diff.Changed {
+			if i >= limit {
+				sb.WriteString(fmt.Sprintf("\n... %d more rows not shown\n", len(diff.Changed)-limit))
+				break
+			}
+			key := strings.Join(change.Key, ", ")
+			for j, column := range change.Columns {
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", key, column, change.First[j], change.Second[j]))
+			}
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestDiffQueryResultsByKey(t *testing.T) {
+	first := &domain.QueryResult{
+		Columns: []string{"region", "revenue", "orders"},
+		Rows: [][]interface{}{
+			{"north", int64(100), int64(3)},
+			{"south", int64(200), int64(5)},
+			{"east", int64(50), nil},
+		},
+	}
+	second := &domain.QueryResult{
+		Columns: []string{"region", "revenue"},
+		Rows: [][]interface{}{
+			{[]byte("north"), float64(100)},
+			{"south", int64(210)},
+			{"west", int64(75)},
+		},
+	}
+
+	diff, err := diffQueryResults(first, second, []string{"region"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"region", "revenue"}, diff.Columns)
+	assert.Equal(t, []string{"orders"}, diff.OnlyFirst)
+	assert.Equal(t, 1, diff.Matching)
+	assert.Equal(t, [][]string{{"east", "50"}}, diff.MissingSecond)
+	assert.Equal(t, [][]string{{"west", "75"}}, diff.MissingFirst)
+	assert.Equal(t, []changedRow{{Key: []string{"south"}, Columns: []string{"revenue"}, First: []string{"200"}, Second: []string{"210"}}}, diff.Changed)
+}
+
+func TestDiffQueryResultsWholeRows(t *testing.T) {
+	first := &domain.QueryResult{
+		Columns: []string{"id", "name"},
+		Rows:    [][]interface{}{{int64(1), "a"}, {int64(1), "a"}, {int64(2), nil}},
+	}
+	second := &domain.QueryResult{
+		Columns: []string{"id", "name"},
+		Rows:    [][]interface{}{{int64(1), "a"}, {int64(2), nil}, {int64(3), "c"}},
+	}
+
+	diff, err := diffQueryResults(first, second, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, diff.Matching)
+	assert.Equal(t, [][]string{{"1", "a"}}, diff.MissingSecond)
+	assert.Equal(t, [][]string{{"3", "c"}}, diff.MissingFirst)
+}
+
+func TestDiffQueryResultsRejectsUnknownKey(t *testing.T) {
+	result := &domain.QueryResult{Columns: []string{"id"}}
+	_, err := diffQueryResults(result, result, []string{"missing"})
+	assert.Error(t, err)
+}
+
+func TestDiffResultsToolRequiresDifferentSides(t *testing.T) {
+	_, err := NewDiffResultsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "query": "SELECT 1", "compare_database": "pg1"},
+	}, "pg1", &mockUseCase{dbType: "postgres"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,229 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// DocCoverageTool handles reporting schema documentation coverage
+type DocCoverageTool struct {
+	BaseToolType
+}
+
+// NewDocCoverageTool creates a new documentation coverage tool type
+func NewDocCoverageTool() *DocCoverageTool {
+	return &DocCoverageTool{
+		BaseToolType: BaseToolType{
+			name:        "doc_coverage",
+			description: "Report which tables and columns lack comments or descriptions. This tool computes the documentation coverage of a schema and lists the undocumented tables and columns, ranked by how frequently each table is read according to the database's own statistics (pg_stat_user_tables on PostgreSQL, performance_schema on MySQL). Use it to prioritize documenting the objects that are actually queried the most.",
+		},
+	}
+}
+
+// CreateTool creates a documentation coverage tool
+func (t *DocCoverageTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Report tables and columns lacking comments, ranked by query frequency"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to inspect (optional, defaults to public on PostgreSQL and the current database on MySQL)"),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of tables to list (default: 50)"),
+		),
+	)
+}
+
+// HandleRequest handles documentation coverage tool requests
+func (t *DocCoverageTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema := input.optionalString("schema", "")
+	limit := input.intAtLeast("limit", 50, 1)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Getting documentation coverage for database %s, schema %s", targetDbID, schema)
+
+	meta, err := schemaCache.load(ctx, useCase, targetDbID, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get documentation coverage: %w", err)
+	}
+
+	// Read counts are best effort: statistics may be disabled or inaccessible
+	reads, err := loadTableReadCounts(ctx, useCase, targetDbID, meta)
+	if err != nil {
+		logger.Warn("Error loading table read statistics: %v", err)
+	}
+
+	report := buildDocCoverageReport(meta, reads)
+
+	// Format the response
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Documentation Coverage for Database %s\n\n", targetDbID))
+	response.WriteString(formatDocCoverageReport(report, limit, reads != nil))
+
+	return createTextResponse(response.String()), nil
+}
+
+// docCoverageEntry describes the documentation state of one table
+type docCoverageEntry struct {
+	Table               string
+	Reads               int64
+	TableDocumented     bool
+	UndocumentedColumns []string
+	TotalColumns        int
+}
+
+// docCoverageReport aggregates documentation coverage for a schema
+type docCoverageReport struct {
+	TotalTables       int
+	DocumentedTables  int
+	TotalColumns      int
+	DocumentedColumns int
+	Entries           []docCoverageEntry
+}
+
+// buildDocCoverageReport computes coverage and ranks undocumented tables by read count
+func buildDocCoverageReport(meta *schemaMetadata, reads map[string]int64) docCoverageReport {
+	var report docCoverageReport
+	for _, table := range meta.Tables {
+		entry := docCoverageEntry{
+			Table:           table.Name,
+			Reads:           reads[schemaTableKey(table.Schema, table.Name)],
+			TableDocumented: strings.TrimSpace(table.Comment) != "",
+			TotalColumns:    len(table.Columns),
+		}
+		for _, col := range table.Columns {
+			if strings.TrimSpace(col.Comment) == "" {
+				entry.UndocumentedColumns = append(entry.UndocumentedColumns, col.Name)
+			}
+		}
+
+		report.TotalTables++
+		report.TotalColumns += entry.TotalColumns
+		report.DocumentedColumns += entry.TotalColumns - len(entry.UndocumentedColumns)
+		if entry.TableDocumented {
+			report.DocumentedTables++
+		}
+		if !entry.TableDocumented || len(entry.UndocumentedColumns) > 0 {
+			report.Entries = append(report.Entries, entry)
+		}
+	}
+
+	// Most frequently read tables first, then the least documented
+	sort.SliceStable(report.Entries, func(i, j int) bool {
+		a, b := report.Entries[i], report.Entries[j]
+		if a.Reads != b.Reads {
+			return a.Reads > b.Reads
+		}
+		if len(a.UndocumentedColumns) != len(b.UndocumentedColumns) {
+			return len(a.UndocumentedColumns) > len(b.UndocumentedColumns)
+		}
+		return a.Table < b.Table
+	})
+
+	return report
+}
+
+// formatDocCoverageReport renders a coverage report as markdown
+func formatDocCoverageReport(report docCoverageReport, limit int, hasReads bool) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Tables documented: %d/%d (%s)\n", report.DocumentedTables, report.TotalTables,
+		formatPercent(report.DocumentedTables, report.TotalTables)))
+	sb.WriteString(fmt.Sprintf("Columns documented: %d/%d (%s)\n\n", report.DocumentedColumns, report.TotalColumns,
+		formatPercent(report.DocumentedColumns, report.TotalColumns)))
+
+	if len(report.Entries) == 0 {
+		sb.WriteString("All tables and columns are documented.\n")
+		return sb.String()
+	}
+	if !hasReads {
+		sb.WriteString("Table read statistics are unavailable; tables are ranked by undocumented columns.\n\n")
+	}
+
+	sb.WriteString("| # | Table | Reads | Table Comment | Undocumented Columns |\n")
+	sb.WriteString("|---|-------|-------|---------------|----------------------|\n")
+	for i, entry := range report.Entries {
+		if limit > 0 && i >= limit {
+			sb.WriteString(fmt.Sprintf("\n... %d more tables not shown\n", len(report.Entries)-limit))
+			break
+		}
+		tableComment := "missing"
+		if entry.TableDocumented {
+			tableComment = "ok"
+		}
+		columns := fmt.Sprintf("%d/%d", len(entry.UndocumentedColumns), entry.TotalColumns)
+		if len(entry.UndocumentedColumns) > 0 {
+			columns += ": " + strings.Join(entry.UndocumentedColumns, ", ")
+		}
+		sb.WriteString(fmt.Sprintf("| %d | %s | %d | %s | %s |\n", i+1, entry.Table, entry.Reads, tableComment, columns))
+	}
+
+	return sb.String()
+}
+
+// formatPercent formats part/total as a percentage
+func formatPercent(part, total int) string {
+	if total == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(total))
+}
+
+// getPostgresTableReadsQuery returns the sequential and index scans of the tables of a schema
+func getPostgresTableReadsQuery(schema string) (string, []interface{}) {
+	return `
+SELECT schemaname, relname, COALESCE(seq_scan, 0) + COALESCE(idx_scan, 0) AS reads
+FROM pg_stat_user_tables
+WHERE schemaname = $1;`, []interface{}{schema}
+}
+
+// getMySQLTableReadsQuery returns the read operations of the tables of a schema, or of the
+// current database
+func getMySQLTableReadsQuery(schema string) (string, []interface{}) {
+	schemaFilter := "DATABASE()"
+	var params []interface{}
+	if schema != "" {
+		schemaFilter = "?"
+		params = []interface{}{schema}
+	}
+	return fmt.Sprintf(`
+SELECT object_schema, object_name, count_read AS reads
+FROM performance_schema.table_io_waits_summary_by_table
+WHERE object_schema = %s;`, schemaFilter), params
+}
+
+// loadTableReadCounts returns the number of reads per table keyed by schemaTableKey
+func loadTableReadCounts(ctx context.Context, useCase UseCaseProvider, dbID string, meta *schemaMetadata) (map[string]int64, error) {
+	dialect, ok := lookupDialect(meta.DatabaseType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for table statistics: %s", meta.DatabaseType)
+	}
+	query, params, ok := dialect.TableReadsQuery(meta.Schema)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for table statistics: %s", meta.DatabaseType)
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	reads := make(map[string]int64, len(result.Rows))
+	for _, row := range result.Rows {
+		reads[schemaTableKey(valueString(row[0]), valueString(row[1]))] = valueInt64(row[2])
+	}
+	return reads, nil
+}
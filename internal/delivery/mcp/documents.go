@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bsonWrapperKinds maps the key of a canonical Extended JSON wrapper to the BSON type it
+// holds, named as the $type operator names it
+var bsonWrapperKinds = map[string]string{
+	"$oid":               "objectId",
+	"$numberInt":         "int",
+	"$numberLong":        "long",
+	"$numberDouble":      "double",
+	"$numberDecimal":     "decimal",
+	"$date":              "date",
+	"$binary":            "binData",
+	"$timestamp":         "timestamp",
+	"$regularExpression": "regex",
+	"$symbol":            "symbol",
+	"$code":              "javascript",
+	"$minKey":            "minKey",
+	"$maxKey":            "maxKey",
+	"$undefined":         "undefined",
+	"$dbPointer":         "dbPointer",
+}
+
+// bsonScalar is a BSON value without a JSON counterpart, such as an ObjectId or a date; the
+// document tools replace the Extended JSON wrappers by it so the JSON structure reports its type
+type bsonScalar struct {
+	kind string
+	text string
+}
+
+// String returns the value as relaxed Extended JSON shows it
+func (s bsonScalar) String() string {
+	return s.text
+}
+
+// bsonWrapperKind returns the BSON type of an Extended JSON wrapper such as {"$oid": "..."},
+// and false for an ordinary document
+func bsonWrapperKind(document map[string]interface{}) (string, bool) {
+	if _, ok := document["$code"]; ok && len(document) <= 2 {
+		return "javascript", true
+	}
+	if len(document) != 1 {
+		return "", false
+	}
+	for key := range document {
+		kind, ok := bsonWrapperKinds[key]
+		return kind, ok
+	}
+	return "", false
+}
+
+// bsonTyped replaces the Extended JSON wrappers of a canonical document by bsonScalars
+func bsonTyped(value interface{}) interface{} {
+	switch val := value.(type) {
+	case map[string]interface{}:
+		if kind, ok := bsonWrapperKind(val); ok {
+			return bsonScalar{kind: kind, text: bsonScalarText(val)}
+		}
+		typed := make(map[string]interface{}, len(val))
+		for key, item := range val {
+			typed[key] = bsonTyped(item)
+		}
+		return typed
+	case []interface{}:
+		typed := make([]interface{}, len(val))
+		for i, item := range val {
+			typed[i] = bsonTyped(item)
+		}
+		return typed
+	default:
+		return value
+	}
+}
+
+// bsonScalarText renders an Extended JSON wrapper as short text: the number, the date, the
+// hex of an ObjectId, or the wrapper itself for the other types
+func bsonScalarText(wrapper map[string]interface{}) string {
+	switch relaxed := relaxedValue(wrapper).(type) {
+	case json.Number:
+		return relaxed.String()
+	case map[string]interface{}:
+		for _, inner := range relaxed {
+			if text, ok := inner.(string); ok && len(relaxed) == 1 {
+				return text
+			}
+		}
+	}
+	raw, _ := json.Marshal(wrapper)
+	return string(raw)
+}
+
+// relaxedValue turns canonical Extended JSON into relaxed Extended JSON for display: numbers
+// become plain JSON numbers and dates ISO-8601 strings, while types such as ObjectId keep
+// their wrapper
+func relaxedValue(value interface{}) interface{} {
+	switch val := value.(type) {
+	case map[string]interface{}:
+		if len(val) == 1 {
+			for key, inner := range val {
+				if relaxed, ok := relaxedWrapper(key, inner); ok {
+					return relaxed
+				}
+			}
+		}
+		relaxed := make(map[string]interface{}, len(val))
+		for key, item := range val {
+			relaxed[key] = relaxedValue(item)
+		}
+		return relaxed
+	case []interface{}:
+		relaxed := make([]interface{}, len(val))
+		for i, item := range val {
+			relaxed[i] = relaxedValue(item)
+		}
+		return relaxed
+	default:
+		return value
+	}
+}
+
+// relaxedWrapper returns the relaxed form of a number or date wrapper
+func relaxedWrapper(key string, inner interface{}) (interface{}, bool) {
+	switch key {
+	case "$numberInt", "$numberLong":
+		if text, ok := inner.(string); ok {
+			return json.Number(text), true
+		}
+	case "$numberDouble":
+		// NaN and the infinities have no JSON number
+		if text, ok := inner.(string); ok {
+			if f, err := strconv.ParseFloat(text, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+				return json.Number(text), true
+			}
+		}
+	case "$date":
+		if millis, ok := inner.(map[string]interface{}); ok {
+			if text, ok := millis["$numberLong"].(string); ok {
+				if ms, err := strconv.ParseInt(text, 10, 64); err == nil {
+					return map[string]interface{}{"$date": time.UnixMilli(ms).UTC().Format(time.RFC3339Nano)}, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// formatDocuments renders documents as an indented JSON array in relaxed Extended JSON
+func formatDocuments(documents []map[string]interface{}) string {
+	relaxed := make([]interface{}, len(documents))
+	for i, document := range documents {
+		relaxed[i] = relaxedValue(document)
+	}
+	raw, err := json.MarshalIndent(relaxed, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return "```json\n" + string(raw) + "\n```\n"
+}
+
+// compactJSON renders a value as one line of relaxed Extended JSON, for table cells
+func compactJSON(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	raw, err := json.Marshal(relaxedValue(value))
+	if err != nil {
+		return err.Error()
+	}
+	return strings.ReplaceAll(string(raw), "|", "\\|")
+}
@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDocumentStore is a DocumentStore returning canned canonical Extended JSON documents
+type fakeDocumentStore struct {
+	collections []map[string]interface{}
+	documents   []map[string]interface{}
+	stats       map[string]interface{}
+	indexes     []map[string]interface{}
+	// pipelines records the collection, pipeline and limit of every Aggregate call
+	pipelines []string
+	limits    []int
+}
+
+func (f *fakeDocumentStore) ListCollections(ctx context.Context) ([]map[string]interface{}, error) {
+	return f.collections, nil
+}
+
+func (f *fakeDocumentStore) SampleDocuments(ctx context.Context, collection string, size int) ([]map[string]interface{}, error) {
+	if len(f.documents) > size {
+		return f.documents[:size], nil
+	}
+	return f.documents, nil
+}
+
+func (f *fakeDocumentStore) CollectionStats(ctx context.Context, collection string) (map[string]interface{}, error) {
+	return f.stats, nil
+}
+
+func (f *fakeDocumentStore) ListIndexes(ctx context.Context, collection string) ([]map[string]interface{}, error) {
+	return f.indexes, nil
+}
+
+func (f *fakeDocumentStore) Aggregate(ctx context.Context, collection, pipeline string, limit int) ([]map[string]interface{}, error) {
+	f.pipelines = append(f.pipelines, collection+" "+pipeline)
+	f.limits = append(f.limits, limit)
+	if len(f.documents) > limit {
+		return f.documents[:limit], nil
+	}
+	return f.documents, nil
+}
+
+// canonical decodes canonical Extended JSON documents
+func canonical(t *testing.T, text string) []map[string]interface{} {
+	var documents []map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &documents); err != nil {
+		t.Fatalf("invalid test documents: %v", err)
+	}
+	return documents
+}
+
+// documentToolText runs a document tool and returns the text of its response
+func documentToolText(t *testing.T, tool ToolType, store *fakeDocumentStore, params map[string]interface{}) string {
+	result, err := tool.HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", &mockUseCase{documents: store})
+	if err != nil {
+		t.Fatalf("tool failed: %v", err)
+	}
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+}
+
+func TestRelaxedValue(t *testing.T) {
+	document := canonical(t, `[{
+		"_id": {"$oid": "65f1c0ffee0000000000abcd"},
+		"qty": {"$numberInt": "5"},
+		"total": {"$numberLong": "9007199254740993"},
+		"price": {"$numberDouble": "1.5"},
+		"ratio": {"$numberDouble": "NaN"},
+		"created": {"$date": {"$numberLong": "1704067200000"}},
+		"items": [{"sku": "a", "n": {"$numberInt": "2"}}]
+	}]`)[0]
+
+	raw, err := json.Marshal(relaxedValue(document))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"_id": {"$oid": "65f1c0ffee0000000000abcd"},
+		"qty": 5,
+		"total": 9007199254740993,
+		"price": 1.5,
+		"ratio": {"$numberDouble": "NaN"},
+		"created": {"$date": "2024-01-01T00:00:00Z"},
+		"items": [{"sku": "a", "n": 2}]
+	}`, string(raw))
+}
+
+func TestBSONTyped(t *testing.T) {
+	document := canonical(t, `[{"_id": {"$oid": "65f1c0ffee0000000000abcd"}, "created": {"$date": {"$numberLong": "0"}}, "code": {"$code": "f()", "$scope": {}}, "address": {"city": "Hanoi"}}]`)[0]
+
+	typed := bsonTyped(document).(map[string]interface{})
+	assert.Equal(t, bsonScalar{kind: "objectId", text: "65f1c0ffee0000000000abcd"}, typed["_id"])
+	assert.Equal(t, bsonScalar{kind: "date", text: "1970-01-01T00:00:00Z"}, typed["created"])
+	assert.Equal(t, "javascript", typed["code"].(bsonScalar).kind)
+	assert.Equal(t, map[string]interface{}{"city": "Hanoi"}, typed["address"])
+}
+
+func TestListCollectionsTool(t *testing.T) {
+	store := &fakeDocumentStore{collections: canonical(t, `[
+		{"name": "orders", "type": "collection", "options": {}, "info": {"readOnly": false}},
+		{"name": "events", "type": "timeseries", "options": {"timeseries": {"timeField": "ts"}}, "info": {"readOnly": false}},
+		{"name": "paid_orders", "type": "view", "options": {"viewOn": "orders"}, "info": {"readOnly": true}}
+	]`)}
+
+	text := documentToolText(t, NewListCollectionsTool(), store, map[string]interface{}{"database": "shop"})
+	assert.Contains(t, text, "# Collections in Database shop")
+	assert.Contains(t, text, "| events | timeseries | false | {\"timeseries\":{\"timeField\":\"ts\"}} |\n| orders | collection | false |  |\n| paid_orders | view | true | {\"viewOn\":\"orders\"} |")
+	assert.Contains(t, text, "3 collections")
+}
+
+func TestDocumentToolsRefuseSQLDatabases(t *testing.T) {
+	request := server.ToolCallRequest{Parameters: map[string]interface{}{"database": "pg", "collection": "orders"}}
+	_, err := NewCollectionIndexesTool().HandleRequest(context.Background(), request, "", &mockUseCase{dbType: "postgres"})
+	assert.EqualError(t, err, "database pg is not a document database")
+}
+
+func TestCollectionStatsTool(t *testing.T) {
+	store := &fakeDocumentStore{stats: canonical(t, `[{
+		"ns": "shop.orders",
+		"count": {"$numberInt": "1200"},
+		"size": {"$numberInt": "2097152"},
+		"avgObjSize": {"$numberInt": "1747"},
+		"storageSize": {"$numberInt": "1048576"},
+		"totalIndexSize": {"$numberInt": "65536"},
+		"nindexes": {"$numberInt": "2"},
+		"indexSizes": {"_id_": {"$numberInt": "40960"}, "status_1_created_-1": {"$numberInt": "24576"}},
+		"capped": false
+	}]`)[0]}
+
+	text := documentToolText(t, NewCollectionStatsTool(), store, map[string]interface{}{"database": "shop", "collection": "orders"})
+	assert.Contains(t, text, "| Documents | 1200 |")
+	assert.Contains(t, text, "| Data size (uncompressed) | 2.0 MiB |")
+	assert.Contains(t, text, "| Average document size | 1.7 KiB |")
+	assert.Contains(t, text, "| Indexes | 2 |")
+	assert.Contains(t, text, "| Sharded | false |")
+	assert.Contains(t, text, "| _id_ | 40.0 KiB |\n| status_1_created_-1 | 24.0 KiB |")
+	assert.NotContains(t, text, "Reusable free storage")
+	assert.NotContains(t, text, "collStats Output")
+
+	text = documentToolText(t, NewCollectionStatsTool(), store, map[string]interface{}{"database": "shop", "collection": "orders", "raw": true})
+	assert.Contains(t, text, `"ns": "shop.orders"`)
+}
+
+func TestCollectionIndexesTool(t *testing.T) {
+	store := &fakeDocumentStore{indexes: canonical(t, `[
+		{"v": {"$numberInt": "2"}, "key": [{"_id": {"$numberInt": "1"}}], "name": "_id_"},
+		{"v": {"$numberInt": "2"}, "key": [{"status": {"$numberInt": "1"}}, {"created": {"$numberInt": "-1"}}], "name": "status_1_created_-1",
+		 "partialFilterExpression": {"status": {"$exists": true}}},
+		{"v": {"$numberInt": "2"}, "key": [{"email": {"$numberInt": "1"}}], "name": "email_1", "unique": true, "sparse": true},
+		{"v": {"$numberInt": "2"}, "key": [{"seen": {"$numberInt": "1"}}], "name": "seen_1", "expireAfterSeconds": {"$numberInt": "3600"}}
+	]`)}
+
+	text := documentToolText(t, NewCollectionIndexesTool(), store, map[string]interface{}{"database": "shop", "collection": "users"})
+	assert.Contains(t, text, "| _id_ | _id: 1 | false | false | false |  |  |")
+	assert.Contains(t, text, "| status_1_created_-1 | status: 1, created: -1 | false | false | false |  | {\"status\":{\"$exists\":true}} |")
+	assert.Contains(t, text, "| email_1 | email: 1 | true | true | false |  |  |")
+	assert.Contains(t, text, "| seen_1 | seen: 1 | false | false | false | 3600 |  |")
+	assert.Contains(t, text, "4 indexes")
+}
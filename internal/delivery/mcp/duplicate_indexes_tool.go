@@ -0,0 +1,300 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// indexDefinition is an index with the key columns that decide whether another index covers it
+type indexDefinition struct {
+	Schema    string
+	Table     string
+	Name      string
+	Method    string
+	Unique    bool
+	Primary   bool
+	Columns   []string
+	OpClasses []string // PostgreSQL only: operator class of every key column
+	Predicate string   // PostgreSQL only: WHERE clause of a partial index
+	Size      int64
+}
+
+// rank orders the indexes kept among exact duplicates: primary key, then unique, then others
+func (d indexDefinition) rank() int {
+	switch {
+	case d.Primary:
+		return 2
+	case d.Unique:
+		return 1
+	}
+	return 0
+}
+
+// redundantIndex is an index another index of the same table makes unnecessary
+type redundantIndex struct {
+	Index     indexDefinition
+	CoveredBy indexDefinition
+	Exact     bool
+}
+
+// DuplicateIndexesTool handles finding redundant indexes
+type DuplicateIndexesTool struct {
+	BaseToolType
+}
+
+// NewDuplicateIndexesTool creates a new duplicate indexes tool type
+func NewDuplicateIndexesTool() *DuplicateIndexesTool {
+	return &DuplicateIndexesTool{
+		BaseToolType: BaseToolType{
+			name:        "duplicate_indexes",
+			description: "Find redundant indexes: exact duplicates, which index the same columns of a table the same way, and btree indexes whose columns are a leading prefix of another index on the same table, so every lookup they serve can use the wider index. Indexes only compare when they have the same type and, on PostgreSQL, the same operator classes and partial index condition. A unique index is never redundant to a wider index, since it enforces a rule the wider one does not; among exact duplicates the primary key and unique indexes are kept. The report lists each redundant index with the index covering it, the space wasted across the database and the statements to drop them. Nothing is dropped.",
+		},
+	}
+}
+
+// CreateTool creates a duplicate indexes tool
+func (t *DuplicateIndexesTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Find duplicate and prefix-redundant indexes and the space they waste"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to inspect (optional, leave empty for all user schemas)"),
+		),
+		tools.WithString("table",
+			tools.Description("Only inspect the indexes of this table (optional)"),
+		),
+	)
+}
+
+// HandleRequest handles duplicate indexes tool requests
+func (t *DuplicateIndexesTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema := input.optionalString("schema", "")
+	tableSchema, table := splitQualifiedName(input.optionalString("table", ""))
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if tableSchema != "" {
+		schema = tableSchema
+	}
+
+	logger.Info("Finding duplicate indexes for database %s, schema %s", targetDbID, schema)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for duplicate indexes: %s", dbType)
+	}
+	query, params, ok := dialect.IndexDefinitionsQuery(schema, table)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for duplicate indexes: %s", dbType)
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, targetDbID, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes: %w", err)
+	}
+	var indexes []indexDefinition
+	for _, row := range result.Rows {
+		if len(row) < 10 {
+			continue
+		}
+		d := indexDefinition{
+			Schema:    valueString(row[0]),
+			Table:     valueString(row[1]),
+			Name:      valueString(row[2]),
+			Method:    strings.ToLower(valueString(row[3])),
+			Unique:    valueBool(row[4]),
+			Primary:   valueBool(row[5]),
+			Columns:   strings.Split(valueString(row[6]), "\n"),
+			Predicate: valueString(row[8]),
+			Size:      valueInt64(row[9]),
+		}
+		if opClasses := valueString(row[7]); opClasses != "" {
+			d.OpClasses = strings.Fields(opClasses)
+		}
+		indexes = append(indexes, d)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Duplicate Indexes in Database %s\n\n", targetDbID))
+	writeRedundantIndexes(&response, dialect, findRedundantIndexes(indexes))
+
+	return createTextResponse(response.String()), nil
+}
+
+// findRedundantIndexes returns the indexes covered by another index of their table, each with
+// the widest index covering it
+func findRedundantIndexes(indexes []indexDefinition) []redundantIndex {
+	byTable := make(map[string][]indexDefinition)
+	var tables []string
+	for _, d := range indexes {
+		key := schemaTableKey(d.Schema, d.Table)
+		if _, ok := byTable[key]; !ok {
+			tables = append(tables, key)
+		}
+		byTable[key] = append(byTable[key], d)
+	}
+	sort.Strings(tables)
+
+	var redundant []redundantIndex
+	for _, key := range tables {
+		group := byTable[key]
+		sort.SliceStable(group, func(i, j int) bool { return group[i].Name < group[j].Name })
+		for i, d := range group {
+			best := -1
+			for j, other := range group {
+				if i == j || !indexCovers(other, j, d, i) {
+					continue
+				}
+				if best < 0 || len(other.Columns) > len(group[best].Columns) ||
+					(len(other.Columns) == len(group[best].Columns) && other.rank() > group[best].rank()) {
+					best = j
+				}
+			}
+			if best >= 0 {
+				redundant = append(redundant, redundantIndex{Index: d, CoveredBy: group[best], Exact: len(d.Columns) == len(group[best].Columns)})
+			}
+		}
+	}
+	return redundant
+}
+
+// indexCovers reports whether index a (at position ai of its table) makes index b (at bi)
+// redundant. Of two exact duplicates, the one ranked higher or named first covers the other.
+func indexCovers(a indexDefinition, ai int, b indexDefinition, bi int) bool {
+	if a.Method != b.Method || a.Predicate != b.Predicate || len(b.Columns) > len(a.Columns) {
+		return false
+	}
+	for k := range b.Columns {
+		if a.Columns[k] != b.Columns[k] || opClassAt(a, k) != opClassAt(b, k) {
+			return false
+		}
+	}
+	if len(a.Columns) == len(b.Columns) {
+		return a.rank() > b.rank() || (a.rank() == b.rank() && ai < bi)
+	}
+	// Only a btree serves lookups on a leading prefix, and a unique prefix enforces more
+	return a.Method == "btree" && !b.Unique && !b.Primary
+}
+
+// opClassAt returns the operator class of the key column at position k, if known
+func opClassAt(d indexDefinition, k int) string {
+	if k < len(d.OpClasses) {
+		return d.OpClasses[k]
+	}
+	return ""
+}
+
+// writeRedundantIndexes renders the redundant indexes, the space they waste and their drop statements
+func writeRedundantIndexes(sb *strings.Builder, dialect Dialect, redundant []redundantIndex) {
+	if len(redundant) == 0 {
+		sb.WriteString("No duplicate or redundant indexes found.\n")
+		return
+	}
+
+	sb.WriteString("| Table | Redundant Index | Columns | Covered By | Columns | Reason | Size |\n")
+	sb.WriteString("|-------|-----------------|---------|------------|---------|--------|------|\n")
+	var wasted int64
+	var statements, warnings []string
+	for _, r := range redundant {
+		reason := "prefix"
+		if r.Exact {
+			reason = "exact duplicate"
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+			qualifiedName(r.Index.Schema, r.Index.Table), r.Index.Name, queryCell(strings.Join(r.Index.Columns, ", ")),
+			r.CoveredBy.Name, queryCell(strings.Join(r.CoveredBy.Columns, ", ")), reason, formatBackupSize(r.Index.Size)))
+		wasted += r.Index.Size
+
+		if r.Index.Unique || r.Index.Primary {
+			warnings = append(warnings, fmt.Sprintf("%s enforces uniqueness like %s; if it backs a constraint, drop the constraint instead of the index.", r.Index.Name, r.CoveredBy.Name))
+		}
+		statements = append(statements, dialect.DropIndexStatement(r.Index.Schema, r.Index.Table, r.Index.Name))
+	}
+	sb.WriteString(fmt.Sprintf("\n%d redundant indexes waste about %s.\n", len(redundant), formatBackupSize(wasted)))
+
+	sb.WriteString("\n## Drop Statements\n\n")
+	sb.WriteString(fmt.Sprintf("```sql\n%s\n```\n", strings.Join(statements, "\n")))
+
+	if len(warnings) > 0 {
+		sb.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+}
+
+// getPostgresIndexDefinitionsQuery returns a query for the valid indexes of the user schemas
+// with their key columns, one per line, and operator classes
+func getPostgresIndexDefinitionsQuery() string {
+	return `
+SELECT
+    n.nspname AS schema_name,
+    t.relname AS table_name,
+    ic.relname AS index_name,
+    am.amname AS method,
+    i.indisunique,
+    i.indisprimary,
+    (SELECT string_agg(pg_get_indexdef(i.indexrelid, k, true), E'\n' ORDER BY k)
+     FROM generate_series(1, i.indnkeyatts) k) AS columns,
+    array_to_string((string_to_array(i.indclass::text, ' '))[1:i.indnkeyatts], ' ') AS opclasses,
+    COALESCE(pg_get_expr(i.indpred, i.indrelid), '') AS predicate,
+    pg_relation_size(i.indexrelid) AS index_bytes
+FROM pg_index i
+JOIN pg_class ic ON ic.oid = i.indexrelid
+JOIN pg_class t ON t.oid = i.indrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+JOIN pg_am am ON am.oid = ic.relam
+WHERE i.indisvalid
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg_toast%'
+  AND ($1 = '' OR n.nspname = $1)
+  AND ($2 = '' OR t.relname = $2)
+ORDER BY 1, 2, 3;`
+}
+
+// getMySQLIndexDefinitionsQuery returns a query for the indexes of the user schemas with their
+// key columns, one per line. Prefix lengths are part of the column, and functional key parts
+// are named after their index so they never match another index. Index sizes come from
+// mysql.innodb_index_stats, which needs SELECT on the mysql schema.
+func getMySQLIndexDefinitionsQuery() string {
+	return `
+SELECT
+    s.TABLE_SCHEMA AS schema_name,
+    s.TABLE_NAME AS table_name,
+    s.INDEX_NAME AS index_name,
+    MAX(s.INDEX_TYPE) AS method,
+    MIN(s.NON_UNIQUE) = 0 AS is_unique,
+    s.INDEX_NAME = 'PRIMARY' AS is_primary,
+    GROUP_CONCAT(
+        COALESCE(CONCAT(s.COLUMN_NAME, IF(s.SUB_PART IS NULL, '', CONCAT('(', s.SUB_PART, ')'))),
+                 CONCAT('(expression of ', s.INDEX_NAME, ')'))
+        ORDER BY s.SEQ_IN_INDEX SEPARATOR '\n') AS columns,
+    '' AS opclasses,
+    '' AS predicate,
+    COALESCE(MAX(st.stat_value) * @@innodb_page_size, 0) AS index_bytes
+FROM information_schema.STATISTICS s
+LEFT JOIN mysql.innodb_index_stats st
+    ON st.database_name = s.TABLE_SCHEMA AND st.table_name = s.TABLE_NAME
+   AND st.index_name = s.INDEX_NAME AND st.stat_name = 'size'
+WHERE s.TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+  AND (? = '' OR s.TABLE_SCHEMA = ?)
+  AND (? = '' OR s.TABLE_NAME = ?)
+GROUP BY s.TABLE_SCHEMA, s.TABLE_NAME, s.INDEX_NAME
+ORDER BY 1, 2, 3;`
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestFindRedundantIndexes(t *testing.T) {
+	index := func(name, method string, unique bool, columns ...string) indexDefinition {
+		return indexDefinition{Schema: "public", Table: "orders", Name: name, Method: method, Unique: unique, Columns: columns}
+	}
+	redundant := findRedundantIndexes([]indexDefinition{
+		index("orders_customer_idx", "btree", false, "customer_id"),
+		index("orders_customer_date_idx", "btree", false, "customer_id", "created_at"),
+		index("orders_customer_date_idx2", "btree", false, "customer_id", "created_at"),
+		index("orders_number_key", "btree", true, "number"),
+		index("orders_number_idx", "btree", false, "number"),
+		index("orders_number_total_idx", "btree", false, "number", "total"),
+		index("orders_tags_idx", "gin", false, "tags"),
+		index("orders_tags_status_idx", "gin", false, "tags", "status"),
+	})
+
+	var got []string
+	for _, r := range redundant {
+		got = append(got, r.Index.Name+" by "+r.CoveredBy.Name)
+	}
+	assert.Equal(t, []string{
+		"orders_customer_date_idx2 by orders_customer_date_idx",
+		"orders_customer_idx by orders_customer_date_idx",
+		"orders_number_idx by orders_number_total_idx",
+	}, got)
+	assert.True(t, redundant[0].Exact)
+	assert.False(t, redundant[1].Exact)
+}
+
+func TestDuplicateIndexesToolPostgres(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_index i": {Rows: [][]interface{}{
+				{"public", "orders", "orders_pkey", "btree", true, true, "id", "1978", "", int64(8 << 20)},
+				{"public", "orders", "orders_id_idx", "btree", false, false, "id", "1978", "", int64(4 << 20)},
+				{"public", "orders", "orders_name_idx", "btree", false, false, "name", "3126", "", int64(1 << 20)},
+				{"public", "orders", "orders_name_pattern_idx", "btree", false, false, "name\nid", "10055 1978", "", int64(1 << 20)},
+				{"public", "orders", "orders_open_idx", "btree", false, false, "status", "3126", "(status = 'open'::text)", int64(1 << 20)},
+				{"public", "orders", "orders_status_idx", "btree", false, false, "status\nid", "3126 1978", "", int64(1 << 20)},
+			}},
+		},
+	}
+
+	result, err := NewDuplicateIndexesTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| public.orders | orders_id_idx | id | orders_pkey | id | exact duplicate | 4.0 MiB |")
+	assert.NotContains(t, text, "| orders_name_idx |")
+	assert.NotContains(t, text, "| orders_open_idx |")
+	assert.Contains(t, text, "1 redundant indexes waste about 4.0 MiB.")
+	assert.Contains(t, text, `DROP INDEX CONCURRENTLY "public"."orders_id_idx";`)
+}
+
+func TestDuplicateIndexesToolMySQL(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"information_schema.STATISTICS": {Rows: [][]interface{}{
+				{"shop", "orders", "idx_customer", "BTREE", int64(0), int64(0), "customer_id", "", "", int64(1 << 20)},
+				{"shop", "orders", "idx_customer_date", "BTREE", int64(0), int64(0), "customer_id\ncreated_at", "", "", int64(2 << 20)},
+				{"shop", "orders", "uq_email", "BTREE", int64(1), int64(0), "email", "", "", int64(1 << 20)},
+				{"shop", "orders", "uq_email_2", "BTREE", int64(1), int64(0), "email", "", "", int64(1 << 20)},
+			}},
+		},
+	}
+
+	result, err := NewDuplicateIndexesTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "my1", "table": "shop.orders"},
+	}, "my1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| shop.orders | idx_customer | customer_id | idx_customer_date | customer_id, created_at | prefix | 1.0 MiB |")
+	assert.Contains(t, text, "ALTER TABLE `shop`.`orders` DROP INDEX `uq_email_2`;")
+	assert.Contains(t, text, "uq_email_2 enforces uniqueness like uq_email")
+	assert.Contains(t, text, "2 redundant indexes waste about 2.0 MiB.")
+}
@@ -0,0 +1,411 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// EnumDriftTool handles checking column values against their allowed set
+type EnumDriftTool struct {
+	BaseToolType
+}
+
+// NewEnumDriftTool creates a new enum drift tool type
+func NewEnumDriftTool() *EnumDriftTool {
+	return &EnumDriftTool{
+		BaseToolType: BaseToolType{
+			name:        "check_enum_drift",
+			description: "Compare the values actually stored in a column against its allowed set and report unexpected values with their row counts. The allowed set is taken from the column's ENUM type or CHECK constraint, or from a reference list (for example the values an application knows about). When both exist, values declared in the database but missing from the reference list, and vice versa, are reported too. Allowed values that never occur are listed as unused.",
+		},
+	}
+}
+
+// CreateTool creates an enum drift tool
+func (t *EnumDriftTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Find column values that fall outside a declared enum, CHECK constraint or reference list"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Table to check, optionally schema-qualified"),
+			tools.Required(),
+		),
+		tools.WithString("column",
+			tools.Description("Column holding the enum or lookup value"),
+			tools.Required(),
+		),
+		tools.WithArray("allowed",
+			tools.Description("Reference list of allowed values (optional, defaults to the declared enum or CHECK constraint)"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of unexpected values to list (default: 50)"),
+		),
+	)
+}
+
+// HandleRequest handles enum drift tool requests
+func (t *EnumDriftTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	table := input.requiredString("table")
+	column := input.requiredString("column")
+	reference := input.stringList("allowed")
+	limit := input.intAtLeast("limit", 50, 1)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for enum drift: %s", dbType)
+	}
+	schema, name := splitQualifiedName(table)
+	queries, ok := dialect.EnumDriftQueries(schema, name, column)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for enum drift: %s", dbType)
+	}
+
+	logger.Info("Checking enum drift for %s.%s in database %s", table, column, targetDbID)
+
+	declared, err := loadDeclaredValues(ctx, useCase, targetDbID, dialect.Name(), queries, table, column)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load declared values: %w", err)
+	}
+
+	allowed := reference
+	if len(allowed) == 0 {
+		allowed = declared.Values
+	}
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("column %s.%s has no ENUM type or CHECK constraint listing its values; pass the allowed parameter", table, column)
+	}
+
+	drift, err := checkEnumDrift(ctx, useCase, targetDbID, dialect.Name(), queries.castToText, table, column, allowed, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check column values: %w", err)
+	}
+	drift.Declared = declared
+	drift.Reference = reference
+
+	// Format the response
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Enum Drift for %s.%s in Database %s\n\n", table, column, targetDbID))
+	response.WriteString(formatEnumDrift(drift))
+
+	return createTextResponse(response.String()), nil
+}
+
+// declaredValues holds the allowed values a column declares in the schema
+type declaredValues struct {
+	Source string // "enum", "set" or "check constraint"
+	Values []string
+}
+
+// enumDriftQueries are the queries check_enum_drift reads the declared values of a column with
+type enumDriftQueries struct {
+	enum       string
+	enumParams []interface{}
+	// enumPerRow is set when each row of the enum query is a value rather than one row holding
+	// the column type that lists them
+	enumPerRow  bool
+	check       string
+	checkParams []interface{}
+	// castToText compares the column as text, so values outside an enum type do not fail to parse
+	castToText bool
+}
+
+// getPostgresEnumDriftQueries returns the labels of the enum type of a column and the CHECK
+// constraints on it
+func getPostgresEnumDriftQueries(schema, table, column string) enumDriftQueries {
+	schemaFilter := "n.nspname = ANY (current_schemas(false))"
+	params := []interface{}{table, column}
+	if schema != "" {
+		schemaFilter = "n.nspname = $3"
+		params = append(params, schema)
+	}
+	return enumDriftQueries{
+		enum: fmt.Sprintf(`
+SELECT e.enumlabel
+FROM pg_catalog.pg_attribute a
+JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_catalog.pg_enum e ON e.enumtypid = a.atttypid
+WHERE c.relname = $1 AND a.attname = $2 AND %s
+ORDER BY e.enumsortorder;`, schemaFilter),
+		enumParams: params,
+		enumPerRow: true,
+		check: fmt.Sprintf(`
+SELECT pg_catalog.pg_get_constraintdef(con.oid)
+FROM pg_catalog.pg_constraint con
+JOIN pg_catalog.pg_class c ON c.oid = con.conrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_catalog.pg_attribute a ON a.attrelid = c.oid AND a.attnum = ANY (con.conkey)
+WHERE con.contype = 'c' AND c.relname = $1 AND a.attname = $2 AND %s;`, schemaFilter),
+		checkParams: params,
+		castToText:  true,
+	}
+}
+
+// getMySQLEnumDriftQueries returns the ENUM or SET type of a column and the CHECK constraints
+// of its table
+func getMySQLEnumDriftQueries(schema, table, column string) enumDriftQueries {
+	schemaFilter := "DATABASE()"
+	params := []interface{}{table, column}
+	checkParams := []interface{}{table}
+	if schema != "" {
+		schemaFilter = "?"
+		params = append(params, schema)
+		checkParams = append(checkParams, schema)
+	}
+	return enumDriftQueries{
+		enum: fmt.Sprintf(`
+SELECT column_type
+FROM information_schema.columns
+WHERE table_name = ? AND column_name = ? AND table_schema = %s
+AND data_type IN ('enum', 'set');`, schemaFilter),
+		enumParams: params,
+		check: fmt.Sprintf(`
+SELECT cc.check_clause
+FROM information_schema.table_constraints tc
+JOIN information_schema.check_constraints cc
+  ON cc.constraint_schema = tc.constraint_schema AND cc.constraint_name = tc.constraint_name
+WHERE tc.table_name = ? AND tc.table_schema = %s AND tc.constraint_type = 'CHECK';`, schemaFilter),
+		checkParams: checkParams,
+	}
+}
+
+// loadDeclaredValues reads the values allowed by the column's ENUM type or CHECK constraints
+func loadDeclaredValues(ctx context.Context, useCase UseCaseProvider, dbID, dbType string, queries enumDriftQueries, table, column string) (declaredValues, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, queries.enum, queries.enumParams)
+	if err != nil {
+		return declaredValues{}, err
+	}
+	if len(result.Rows) > 0 {
+		if queries.enumPerRow {
+			values := make([]string, 0, len(result.Rows))
+			for _, row := range result.Rows {
+				values = append(values, valueString(row[0]))
+			}
+			return declaredValues{Source: "enum", Values: values}, nil
+		}
+		columnType := valueString(result.Rows[0][0])
+		source := "enum"
+		if strings.HasPrefix(strings.ToLower(columnType), "set") {
+			source = "set"
+		}
+		return declaredValues{Source: source, Values: sqlStringLiterals(columnType, dbType, "")}, nil
+	}
+
+	// CHECK constraints are not available on older MySQL versions, so failures are not fatal.
+	// The clauses of all table constraints are loaded and filtered by column name below.
+	result, err = useCase.ExecuteQuery(ctx, dbID, queries.check, queries.checkParams)
+	if err != nil {
+		logger.Warn("Error loading CHECK constraints for %s: %v", table, err)
+		return declaredValues{}, nil
+	}
+	var values []string
+	for _, row := range result.Rows {
+		values = append(values, sqlStringLiterals(valueString(row[0]), dbType, column)...)
+	}
+	if len(values) == 0 {
+		return declaredValues{}, nil
+	}
+	return declaredValues{Source: "check constraint", Values: uniqueStrings(values)}, nil
+}
+
+// sqlStringLiterals returns the string literals of a type or constraint definition;
+// when column is set, definitions that do not mention the column yield nothing
+func sqlStringLiterals(definition, dbType, column string) []string {
+	tokens, err := tokenizeSQL(definition, strings.ToLower(dbType))
+	if err != nil {
+		return nil
+	}
+	mentioned := column == ""
+	var literals []string
+	for _, tok := range tokens {
+		switch tok.kind {
+		case sqlString:
+			literals = append(literals, tok.text)
+		case sqlWord, sqlQuotedIdent:
+			if strings.EqualFold(tok.text, column) {
+				mentioned = true
+			}
+		}
+	}
+	if !mentioned {
+		return nil
+	}
+	return literals
+}
+
+// uniqueStrings removes duplicates while keeping the first occurrence order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := values[:0]
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// enumValueCount is a distinct column value with the number of rows holding it
+type enumValueCount struct {
+	Value string
+	Rows  int64
+}
+
+// enumDrift is the outcome of comparing stored values with the allowed set
+type enumDrift struct {
+	Allowed    []string
+	Declared   declaredValues
+	Reference  []string
+	Unexpected []enumValueCount
+	// MoreUnexpected is set when Unexpected was cut off at the limit
+	MoreUnexpected bool
+	Used           map[string]int64
+	NullRows       int64
+}
+
+// checkEnumDrift counts the stored values inside and outside the allowed set
+func checkEnumDrift(ctx context.Context, useCase UseCaseProvider, dbID, dbType string, castToText bool, table, column string, allowed []string, limit int) (*enumDrift, error) {
+	columnExpr := quoteIdentifier(dbType, column)
+	if castToText {
+		columnExpr = fmt.Sprintf("CAST(%s AS TEXT)", columnExpr)
+	}
+	tableName := quoteIdentifier(dbType, table)
+
+	inList := func(params *sqlParams) string {
+		placeholders := make([]string, len(allowed))
+		for i, value := range allowed {
+			placeholders[i] = params.add(value)
+		}
+		return strings.Join(placeholders, ", ")
+	}
+
+	drift := &enumDrift{Allowed: allowed, Used: make(map[string]int64)}
+
+	params := newSQLParams(dbType)
+	query := fmt.Sprintf("SELECT %s, COUNT(*) FROM %s WHERE %s IN (%s) GROUP BY 1",
+		columnExpr, tableName, columnExpr, inList(params))
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, params.values)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range result.Rows {
+		drift.Used[valueString(row[0])] = valueInt64(row[1])
+	}
+
+	params = newSQLParams(dbType)
+	query = fmt.Sprintf("SELECT %s, COUNT(*) FROM %s WHERE %s NOT IN (%s) OR %s IS NULL GROUP BY 1 ORDER BY 2 DESC %s",
+		columnExpr, tableName, columnExpr, inList(params), columnExpr, dialectFor(dbType).LimitClause(limit+1))
+	result, err = useCase.ExecuteQuery(ctx, dbID, query, params.values)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range result.Rows {
+		if row[0] == nil {
+			drift.NullRows = valueInt64(row[1])
+			continue
+		}
+		if len(drift.Unexpected) == limit {
+			drift.MoreUnexpected = true
+			continue
+		}
+		drift.Unexpected = append(drift.Unexpected, enumValueCount{Value: valueString(row[0]), Rows: valueInt64(row[1])})
+	}
+
+	return drift, nil
+}
+
+// closeEnumValue returns the allowed value that differs from value only by case or surrounding whitespace
+func closeEnumValue(value string, allowed []string) string {
+	for _, candidate := range allowed {
+		if strings.EqualFold(strings.TrimSpace(value), candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// formatEnumDrift renders unexpected, unused and mismatched values
+func formatEnumDrift(drift *enumDrift) string {
+	var sb strings.Builder
+
+	source := "reference list"
+	if len(drift.Reference) == 0 {
+		source = drift.Declared.Source
+	}
+	sb.WriteString(fmt.Sprintf("Allowed values (%s): %s\n\n", source, strings.Join(drift.Allowed, ", ")))
+	if source == "set" {
+		sb.WriteString("Note: rows holding several SET members are compared as a whole and show up as unexpected.\n\n")
+	}
+
+	if len(drift.Unexpected) == 0 {
+		sb.WriteString("No unexpected values found.\n")
+	} else {
+		var total int64
+		for _, value := range drift.Unexpected {
+			total += value.Rows
+		}
+		more := ""
+		if drift.MoreUnexpected {
+			more = "+"
+		}
+		sb.WriteString(fmt.Sprintf("Unexpected values: %d%s distinct, %d%s rows\n\n", len(drift.Unexpected), more, total, more))
+		sb.WriteString("| Value | Rows | Note |\n")
+		sb.WriteString("|-------|------|------|\n")
+		for _, value := range drift.Unexpected {
+			note := ""
+			if closest := closeEnumValue(value.Value, drift.Allowed); closest != "" {
+				note = fmt.Sprintf("differs from '%s' only by case or whitespace", closest)
+			}
+			sb.WriteString(fmt.Sprintf("| '%s' | %d | %s |\n", value.Value, value.Rows, note))
+		}
+	}
+	if drift.NullRows > 0 {
+		sb.WriteString(fmt.Sprintf("\nNULL values: %d rows\n", drift.NullRows))
+	}
+
+	var unused []string
+	for _, value := range drift.Allowed {
+		if drift.Used[value] == 0 {
+			unused = append(unused, value)
+		}
+	}
+	if len(unused) > 0 {
+		sb.WriteString(fmt.Sprintf("\nAllowed but never used: %s\n", strings.Join(unused, ", ")))
+	}
+
+	// Compare the declared values with the reference list when both are present
+	if len(drift.Reference) > 0 && len(drift.Declared.Values) > 0 {
+		onlyReference, onlyDeclared := diffSets(drift.Declared.Values, drift.Reference)
+		sort.Strings(onlyDeclared)
+		sort.Strings(onlyReference)
+		if len(onlyDeclared) > 0 {
+			sb.WriteString(fmt.Sprintf("\nDeclared in the %s but missing from the reference list: %s\n", drift.Declared.Source, strings.Join(onlyDeclared, ", ")))
+		}
+		if len(onlyReference) > 0 {
+			sb.WriteString(fmt.Sprintf("\nIn the reference list but not declared in the %s: %s\n", drift.Declared.Source, strings.Join(onlyReference, ", ")))
+		}
+	}
+
+	return sb.String()
+}
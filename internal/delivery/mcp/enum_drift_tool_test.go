@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestSQLStringLiterals(t *testing.T) {
+	assert.Equal(t, []string{"new", "it's", "done"}, sqlStringLiterals("enum('new','it''s','done')", "mysql", ""))
+
+	check := "CHECK (((status)::text = ANY ((ARRAY['active'::character varying, 'closed'::character varying])::text[])))"
+	assert.Equal(t, []string{"active", "closed"}, sqlStringLiterals(check, "postgres", "status"))
+	assert.Empty(t, sqlStringLiterals(check, "postgres", "kind"))
+}
+
+func TestEnumDriftToolWithDeclaredEnum(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"column_type":   {Rows: [][]interface{}{{[]byte("enum('active','closed','archived')")}}},
+			"`status` IN (": {Rows: [][]interface{}{{"active", int64(90)}, {"closed", int64(8)}}},
+			"NOT IN (": {Rows: [][]interface{}{
+				{"Active ", int64(3)},
+				{"", int64(2)},
+				{nil, int64(5)},
+			}},
+		},
+	}
+
+	result, err := NewEnumDriftTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "table": "accounts", "column": "status"},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "Allowed values (enum): active, closed, archived")
+	assert.Contains(t, text, "Unexpected values: 2 distinct, 5 rows")
+	assert.Contains(t, text, "| 'Active ' | 3 | differs from 'active' only by case or whitespace |")
+	assert.Contains(t, text, "NULL values: 5 rows")
+	assert.Contains(t, text, "Allowed but never used: archived")
+	assert.Contains(t, useCase.queries[len(useCase.queries)-1],
+		"SELECT `status`, COUNT(*) FROM `accounts` WHERE `status` NOT IN (?, ?, ?) OR `status` IS NULL")
+}
+
+func TestFormatEnumDriftComparesReference(t *testing.T) {
+	text := formatEnumDrift(&enumDrift{
+		Allowed:   []string{"active", "pending"},
+		Declared:  declaredValues{Source: "check constraint", Values: []string{"active", "closed"}},
+		Reference: []string{"active", "pending"},
+		Used:      map[string]int64{"active": 4, "pending": 1},
+	})
+	assert.Contains(t, text, "Allowed values (reference list): active, pending")
+	assert.Contains(t, text, "No unexpected values found.")
+	assert.Contains(t, text, "Declared in the check constraint but missing from the reference list: closed")
+	assert.Contains(t, text, "In the reference list but not declared in the check constraint: pending")
+}
+
+func TestEnumDriftToolRequiresAllowedValues(t *testing.T) {
+	_, err := NewEnumDriftTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "table": "accounts", "column": "status"},
+	}, "pg1", &mockUseCase{dbType: "postgres"})
+	assert.Error(t, err)
+}
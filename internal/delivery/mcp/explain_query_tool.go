@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// explainModes lists the output modes of the explain tool
+var explainModes = []string{"tree", "json", "natural"}
+
+// ExplainQueryTool handles showing the execution plan of a query
+type ExplainQueryTool struct {
+	BaseToolType
+}
+
+// NewExplainQueryTool creates a new explain tool type
+func NewExplainQueryTool() *ExplainQueryTool {
+	return &ExplainQueryTool{
+		BaseToolType: BaseToolType{
+			name:        "explain_query",
+			description: "Show the execution plan of a query without running it. The plan can be returned as an indented tree, as the raw JSON from the database, or in natural mode as a plain-English explanation: which indexes are used, where the big scans and sorts are, and why a nested loop was chosen. The explanation is computed from the plan structure alone, so the same plan always gives the same explanation.",
+		},
+	}
+}
+
+// CreateTool creates an explain tool
+func (t *ExplainQueryTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Show a query's execution plan as a tree, raw JSON or plain English"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("query",
+			tools.Description("SQL query to explain"),
+			tools.Required(),
+		),
+		tools.WithString("mode",
+			tools.Description("Output mode: tree, json or natural (default: tree)"),
+		),
+	)
+}
+
+// HandleRequest handles explain tool requests
+func (t *ExplainQueryTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	query := input.requiredString("query")
+	mode := input.choice("mode", "tree", explainModes...)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Explaining query on database %s in %s mode", targetDbID, mode)
+
+	plan, err := loadQueryPlan(ctx, useCase, targetDbID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Format the response
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Query Plan for Database %s\n\n", targetDbID))
+	switch mode {
+	case "json":
+		response.WriteString("```json\n" + plan.Raw + "\n```\n")
+	case "natural":
+		response.WriteString(explainPlanInEnglish(plan))
+	default:
+		response.WriteString(fmt.Sprintf("Total cost: %.2f\n\n", plan.TotalCost))
+		response.WriteString("```\n" + plan.render() + "```\n")
+	}
+
+	return createTextResponse(response.String()), nil
+}
@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+const testPostgresNestedLoopPlan = `[{"Plan": {"Node Type": "Sort", "Total Cost": 40250.0, "Plan Rows": 25000, "Sort Key": ["o.created_at DESC"],
+  "Plans": [
+    {"Node Type": "Nested Loop", "Total Cost": 36000.0, "Plan Rows": 25000,
+     "Plans": [
+       {"Node Type": "Index Scan", "Relation Name": "users", "Index Name": "users_pkey", "Total Cost": 8.3, "Plan Rows": 1, "Index Cond": "(id = 42)"},
+       {"Node Type": "Seq Scan", "Relation Name": "orders", "Total Cost": 35000.0, "Plan Rows": 25000, "Filter": "(user_id = 42)"}
+     ]}
+  ]}}]`
+
+func TestExplainPlanInEnglishPostgres(t *testing.T) {
+	plan, err := parseQueryPlan("postgres", testPostgresNestedLoopPlan)
+	assert.NoError(t, err)
+
+	text := explainPlanInEnglish(plan)
+	assert.Contains(t, text, "The planner estimates a total cost of 40250.00 and about 25000 result rows.")
+	assert.Contains(t, text, "1. Looks up rows of users through index users_pkey, using the condition (id = 42).\n"+
+		"2. Reads every row of orders sequentially (about 25000 rows), using the condition (user_id = 42).\n"+
+		"3. Joins with a nested loop: for each of about 1 outer rows it looks up matching rows in the inner input from orders. "+
+		"The planner chose it because the outer side is small, but the inner side is not read through an index")
+	assert.Contains(t, text, "4. Sorts about 25000 rows by o.created_at DESC; a sort this size may spill to disk")
+	assert.Contains(t, text, "- Indexes used: users_pkey")
+	assert.Contains(t, text, "- Big full table scans: orders (about 25000 rows)")
+	assert.Contains(t, text, "- Most expensive step: Seq Scan on orders (cost=35000.00 rows=25000)")
+}
+
+func TestExplainPlanInEnglishMySQL(t *testing.T) {
+	plan, err := parseQueryPlan("mysql", `{"query_block": {"cost_info": {"query_cost": "120.50"},
+  "ordering_operation": {"using_filesort": true,
+    "nested_loop": [
+      {"table": {"table_name": "users", "access_type": "ALL", "rows_examined_per_scan": 100}},
+      {"table": {"table_name": "orders", "access_type": "ref", "key": "idx_orders_user", "rows_examined_per_scan": 5}}
+    ]}}}`)
+	assert.NoError(t, err)
+
+	text := explainPlanInEnglish(plan)
+	assert.Contains(t, text, "Joins users, orders with nested loops, starting from users; orders is looked up through index idx_orders_user.")
+	assert.Contains(t, text, "Sorts the rows with a filesort because no index provides the requested order.")
+	assert.Contains(t, text, "- Expensive sorts: a filesort for ORDER BY or GROUP BY")
+	assert.NotContains(t, text, "Most expensive step")
+}
+
+func TestExplainQueryTool(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType:  "postgres",
+		results: map[string]*domain.QueryResult{"EXPLAIN (FORMAT JSON)": {Rows: [][]interface{}{{testPostgresNestedLoopPlan}}}},
+	}
+
+	result, err := NewExplainQueryTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "query": "SELECT 1", "mode": "natural"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "Steps:\n1. Looks up rows of users")
+
+	result, err = NewExplainQueryTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "query": "SELECT 1"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	text = result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "-> Sort (cost=40250.00 rows=25000)")
+
+	_, err = NewExplainQueryTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "query": "SELECT 1", "mode": "poetry"},
+	}, "pg1", useCase)
+	assert.Error(t, err)
+}
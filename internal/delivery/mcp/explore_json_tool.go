@@ -0,0 +1,234 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// ExploreJSONTool handles inferring the structure of a JSON column
+type ExploreJSONTool struct {
+	BaseToolType
+}
+
+// NewExploreJSONTool creates a new JSON exploration tool type
+func NewExploreJSONTool() *ExploreJSONTool {
+	return &ExploreJSONTool{
+		BaseToolType: BaseToolType{
+			name:        "explore_json",
+			description: "Sample a JSON or JSONB column (or a text column holding JSON) and infer its key structure: every path with the share of values containing it, the JSON types found there and an example. Suggests an expression index and a generated column for the paths given in filtered_paths, or for the most common scalar paths when none are given, typed from the values seen in the sample.",
+		},
+	}
+}
+
+// CreateTool creates a JSON exploration tool
+func (t *ExploreJSONTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Infer the key structure of a JSON column and suggest indexes for filtered paths"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Table holding the column, optionally schema-qualified"),
+			tools.Required(),
+		),
+		tools.WithString("column",
+			tools.Description("JSON column to explore"),
+			tools.Required(),
+		),
+		tools.WithNumber("sample_rows",
+			tools.Description("Number of non-null values to sample (default: 1000, max: 100000)"),
+		),
+		tools.WithBoolean("random",
+			tools.Description("Sample random rows instead of the first ones, which scans the table (default: false)"),
+		),
+		tools.WithNumber("max_depth",
+			tools.Description("How many levels of nesting to report (default: 5, max: 20)"),
+		),
+		tools.WithArray("filtered_paths",
+			tools.Description("Paths your queries filter on, such as customer.id or $.status, to suggest indexes for (default: the most common scalar paths)"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+	)
+}
+
+// HandleRequest handles JSON exploration tool requests
+func (t *ExploreJSONTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema, table := splitQualifiedName(input.requiredString("table"))
+	column := input.requiredString("column")
+	sampleRows := input.intBetween("sample_rows", 1000, 1, 100000)
+	random := input.optionalBool("random", false)
+	maxDepth := input.intBetween("max_depth", 5, 1, 20)
+	filtered := input.stringList("filtered_paths")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for explore_json: %s", dbType)
+	}
+	explorer, err := dialect.JSONExplorer()
+	if err != nil {
+		return nil, unsupportedAdminError("explore_json", dbType, err)
+	}
+
+	columnType, err := loadJSONColumnType(ctx, useCase, targetDbID, explorer, schema, table, column)
+	if err != nil {
+		return nil, err
+	}
+
+	target := dialect.QuoteIdent(table)
+	if schema != "" {
+		target = dialect.QuoteIdent(schema) + "." + target
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s IS NOT NULL", explorer.textValue(dialect.QuoteIdent(column)), target, dialect.QuoteIdent(column))
+	if random {
+		query += " ORDER BY " + dialect.RandomFunc()
+	}
+	query += " " + dialect.LimitClause(sampleRows)
+
+	result, err := useCase.ExecuteQuery(ctx, targetDbID, query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample %s.%s: %w", qualifiedName(schema, table), column, err)
+	}
+	structure := newJSONStructure(maxDepth)
+	for _, row := range result.Rows {
+		if len(row) > 0 {
+			structure.add(valueString(row[0]))
+		}
+	}
+	logger.Info("Explored JSON column %s.%s of database %s: %d values, %d paths", qualifiedName(schema, table), column, targetDbID, structure.sampled, len(structure.paths))
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# JSON Structure of %s.%s for Database %s\n\n", qualifiedName(schema, table), column, targetDbID))
+	order := "first"
+	if random {
+		order = "random"
+	}
+	response.WriteString(fmt.Sprintf("Column type: %s\n", columnType))
+	response.WriteString(fmt.Sprintf("Sampled: %d %s non-null values\n", len(result.Rows), order))
+	if structure.sampled == 0 {
+		if structure.invalid > 0 {
+			response.WriteString(fmt.Sprintf("\nNone of the sampled values is valid JSON (first error: %v).\n", structure.firstErr))
+		} else {
+			response.WriteString("\nThe column has no non-null values.\n")
+		}
+		return createTextResponse(response.String()), nil
+	}
+	response.WriteString(fmt.Sprintf("Top-level types: %s\n\n", (&jsonPathStats{types: structure.rootTypes}).typeSummary()))
+	writeJSONPaths(&response, structure)
+
+	var warnings []string
+	paths := make([]string, 0, len(filtered))
+	for _, path := range filtered {
+		normalized := normalizeJSONPath(path)
+		if _, ok := structure.paths[normalized]; !ok {
+			warnings = append(warnings, fmt.Sprintf("Path %s was not found in the sampled values.", path))
+			continue
+		}
+		paths = append(paths, normalized)
+	}
+	if len(filtered) == 0 {
+		paths = structure.candidatePaths(3)
+	}
+	warnings = append(warnings, writeJSONSuggestions(&response, dialect, explorer, schema, table, column, columnType, structure, paths, len(filtered) > 0)...)
+
+	if structure.invalid > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d sampled values are not valid JSON and were skipped (first error: %v).", structure.invalid, structure.firstErr))
+	}
+	if structure.truncated {
+		warnings = append(warnings, fmt.Sprintf("Only the first %d distinct paths are reported; objects keyed by ids or dates produce a path per key and are better explored with a lower max_depth.", maxJSONPaths))
+	}
+	if len(warnings) > 0 {
+		response.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			response.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+	return createTextResponse(response.String()), nil
+}
+
+// loadJSONColumnType returns the declared type of the explored column in lower case
+func loadJSONColumnType(ctx context.Context, useCase UseCaseProvider, dbID string, explorer jsonExplorer, schema, table, column string) (string, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, explorer.columnTypeQuery(), []interface{}{schema, table, column})
+	if err != nil {
+		return "", fmt.Errorf("failed to look up column %s: %w", column, err)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) == 0 {
+		return "", fmt.Errorf("column %s not found in table %s", column, qualifiedName(schema, table))
+	}
+	return strings.ToLower(valueString(result.Rows[0][0])), nil
+}
+
+// writeJSONPaths renders the table of paths
+func writeJSONPaths(sb *strings.Builder, structure *jsonStructure) {
+	sb.WriteString("| Path | Rows | Share | Types | Example |\n")
+	sb.WriteString("|------|------|-------|-------|---------|\n")
+	for _, path := range structure.sortedPaths() {
+		stats := structure.paths[path]
+		example := []rune(stats.example)
+		if len(example) > 40 {
+			example = append(example[:37], []rune("...")...)
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %d | %.1f%% | %s | %s |\n",
+			strings.ReplaceAll(path, "|", "\\|"), stats.rows, 100*float64(stats.rows)/float64(structure.sampled),
+			stats.typeSummary(), strings.ReplaceAll(string(example), "|", "\\|")))
+	}
+	sb.WriteString(fmt.Sprintf("\n%d paths\n", len(structure.paths)))
+}
+
+// writeJSONSuggestions renders index suggestions for paths and returns warnings about paths
+// that cannot take one
+func writeJSONSuggestions(sb *strings.Builder, dialect Dialect, explorer jsonExplorer, schema, table, column, columnType string, structure *jsonStructure, paths []string, requested bool) []string {
+	var warnings []string
+	var suggestions strings.Builder
+	for _, path := range paths {
+		stats := structure.paths[path]
+		kind, scalar := stats.scalarType()
+		switch {
+		case stats.inArray():
+			warnings = append(warnings, fmt.Sprintf("Path %s runs through an array, so a single expression index cannot cover it; use %s.", path, explorer.arrayIndexHint()))
+			continue
+		case !scalar:
+			warnings = append(warnings, fmt.Sprintf("Path %s holds objects or arrays; index the scalar paths below it instead.", path))
+			continue
+		}
+		if types := len(stats.types) - min(stats.types["null"], 1); types > 1 {
+			warnings = append(warnings, fmt.Sprintf("Path %s mixes types (%s), so its suggested index compares values as text.", path, stats.typeSummary()))
+		}
+		suggestions.WriteString(fmt.Sprintf("\n### %s (%s, in %d of %d values)\n\n```sql\n%s```\n",
+			path, kind, stats.rows, structure.sampled, jsonIndexSuggestion(dialect, schema, table, column, columnType, stats, kind)))
+	}
+
+	target := dialect.QuoteIdent(table)
+	if schema != "" {
+		target = dialect.QuoteIdent(schema) + "." + target
+	}
+	containment := explorer.containmentIndex(target, column, columnType)
+	if suggestions.Len() == 0 && containment == "" {
+		return warnings
+	}
+	sb.WriteString("\n## Index Suggestions\n")
+	if !requested {
+		sb.WriteString("\nNo filtered_paths were given, so these cover the most common scalar paths; pass the paths your queries filter on for targeted suggestions.\n")
+	}
+	sb.WriteString(suggestions.String())
+	if containment != "" {
+		sb.WriteString(fmt.Sprintf("\nFor containment filters on many paths (%s @> '{\"key\": \"value\"}'), one index covers them all:\n\n```sql\n%s\n```\n",
+			dialect.QuoteIdent(column), containment))
+	}
+	return warnings
+}
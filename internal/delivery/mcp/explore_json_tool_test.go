@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestJSONStructure(t *testing.T) {
+	structure := newJSONStructure(5)
+	structure.add(`{"status": "paid", "total": 10, "customer": {"id": 7}, "items": [{"sku": "a"}, {"sku": "b"}]}`)
+	structure.add(`{"status": "open", "total": 12.5, "customer": {"id": 8}, "content-type": "x"}`)
+	structure.add(`{"status": null, "items": []}`)
+	structure.add(`not json`)
+
+	assert.Equal(t, 3, structure.sampled)
+	assert.Equal(t, 1, structure.invalid)
+	assert.Equal(t, []string{`"content-type"`, "customer", "customer.id", "items", "items[]", "items[].sku", "status", "total"}, structure.sortedPaths())
+
+	// Array elements count once per value
+	assert.Equal(t, 1, structure.paths["items[].sku"].rows)
+	assert.Equal(t, 2, structure.paths["items[].sku"].types["string"])
+
+	kind, ok := structure.paths["total"].scalarType()
+	assert.True(t, ok)
+	assert.Equal(t, "number", kind)
+	kind, _ = structure.paths["customer.id"].scalarType()
+	assert.Equal(t, "integer", kind)
+	_, ok = structure.paths["customer"].scalarType()
+	assert.False(t, ok)
+	assert.Equal(t, "string 2, null 1", structure.paths["status"].typeSummary())
+
+	// Scalar paths outside arrays in at least half of the values, most common first
+	assert.Equal(t, []string{"status", "customer.id", "total"}, structure.candidatePaths(3))
+
+	shallow := newJSONStructure(1)
+	shallow.add(`{"customer": {"id": 7}}`)
+	assert.Equal(t, []string{"customer"}, shallow.sortedPaths())
+}
+
+func TestNormalizeJSONPath(t *testing.T) {
+	assert.Equal(t, "items[].sku", normalizeJSONPath("$.items[0].sku"))
+	assert.Equal(t, "items[].sku", normalizeJSONPath("items[*].sku"))
+	assert.Equal(t, "status", normalizeJSONPath(" $.status "))
+}
+
+func TestJSONIndexSuggestion(t *testing.T) {
+	stats := &jsonPathStats{steps: []jsonStep{{key: "customer"}, {key: "id"}}}
+
+	postgres, _ := lookupDialect("postgres")
+	sql := jsonIndexSuggestion(postgres, "public", "orders", "data", "jsonb", stats, "integer")
+	assert.Contains(t, sql, `CREATE INDEX "data_customer_id_idx" ON "public"."orders" ((("data"->'customer'->>'id')::bigint));`)
+	assert.Contains(t, sql, `ALTER TABLE "public"."orders" ADD COLUMN "data_customer_id" bigint GENERATED ALWAYS AS (("data"->'customer'->>'id')::bigint) STORED;`)
+
+	// Text columns holding JSON are cast first
+	sql = jsonIndexSuggestion(postgres, "", "orders", "data", "text", stats, "string")
+	assert.Contains(t, sql, `CREATE INDEX "data_customer_id_idx" ON "orders" (("data"::jsonb->'customer'->>'id'));`)
+
+	mysql, _ := lookupDialect("mysql")
+	sql = jsonIndexSuggestion(mysql, "", "orders", "data", "json", stats, "string")
+	assert.Contains(t, sql, "CREATE INDEX `data_customer_id_idx` ON `orders` ((CAST(JSON_UNQUOTE(JSON_EXTRACT(`data`, '$.customer.id')) AS CHAR(255)) COLLATE utf8mb4_bin));")
+	assert.Contains(t, sql, "ALTER TABLE `orders` ADD COLUMN `data_customer_id` VARCHAR(255) GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(`data`, '$.customer.id'))) VIRTUAL, ADD INDEX (`data_customer_id`);")
+}
+
+func TestExploreJSONTool(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"data_type": {Rows: [][]interface{}{{"jsonb"}}},
+			"IS NOT NULL": {Rows: [][]interface{}{
+				{`{"status": "paid", "tags": ["a"]}`},
+				{[]byte(`{"status": "open", "tags": []}`)},
+			}},
+		},
+	}
+	request := server.ToolCallRequest{Parameters: map[string]interface{}{
+		"database":       "db1",
+		"table":          "public.orders",
+		"column":         "data",
+		"filtered_paths": []interface{}{"$.status", "tags[0]", "missing"},
+	}}
+
+	result, err := NewExploreJSONTool().HandleRequest(context.Background(), request, "", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+
+	assert.Contains(t, useCase.queries, `SELECT "data"::text FROM "public"."orders" WHERE "data" IS NOT NULL LIMIT 1000`)
+	assert.Contains(t, text, "| status | 2 | 100.0% | string 2 | paid |")
+	assert.Contains(t, text, "| tags[] | 1 | 50.0% | string 1 | a |")
+	assert.Contains(t, text, `CREATE INDEX "data_status_idx" ON "public"."orders" (("data"->>'status'));`)
+	assert.Contains(t, text, `USING GIN ("data" jsonb_path_ops)`)
+	assert.Contains(t, text, "Path tags[] runs through an array")
+	assert.Contains(t, text, "Path missing was not found in the sampled values.")
+}
+
+func TestExploreJSONToolUnknownColumn(t *testing.T) {
+	useCase := &mockUseCase{dbType: "mysql"}
+	request := server.ToolCallRequest{Parameters: map[string]interface{}{"database": "db1", "table": "orders", "column": "data"}}
+
+	_, err := NewExploreJSONTool().HandleRequest(context.Background(), request, "", useCase)
+	assert.EqualError(t, err, "column data not found in table orders")
+}
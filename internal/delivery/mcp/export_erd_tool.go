@@ -0,0 +1,288 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// ExportERDTool handles exporting entity-relationship diagrams
+type ExportERDTool struct {
+	BaseToolType
+}
+
+// NewExportERDTool creates a new export ERD tool type
+func NewExportERDTool() *ExportERDTool {
+	return &ExportERDTool{
+		BaseToolType: BaseToolType{
+			name:        "export_erd",
+			description: "Export an entity-relationship diagram of a database schema as a Mermaid erDiagram or a Graphviz DOT document. The diagram includes tables, columns with their types, primary and foreign key markers, and the relationships between tables with cardinality derived from the foreign keys (one-to-one when the foreign key columns are unique, optional when they are nullable). You can export the whole schema or a selected set of tables, optionally together with the tables within a number of foreign key hops of them. The output can be pasted directly into documentation that renders Mermaid or Graphviz.",
+		},
+	}
+}
+
+// CreateTool creates an export ERD tool
+func (t *ExportERDTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Export an entity-relationship diagram as Mermaid or Graphviz DOT"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to export (optional, defaults to public on PostgreSQL and the current database on MySQL)"),
+		),
+		tools.WithArray("tables",
+			tools.Description("Tables to include (optional, leave empty for all tables in the schema)"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithNumber("depth",
+			tools.Description("Also include tables up to this many foreign key hops from the selected tables, in either direction (default: 0, requires tables)"),
+		),
+		tools.WithString("format",
+			tools.Description("Diagram format: mermaid or dot (default: mermaid)"),
+		),
+		tools.WithBoolean("include_columns",
+			tools.Description("Whether to include columns in the diagram (default: true)"),
+		),
+	)
+}
+
+// HandleRequest handles export ERD tool requests
+func (t *ExportERDTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema := input.optionalString("schema", "")
+	tableNames := input.stringList("tables")
+	depth := input.intBetween("depth", 0, 0, 10)
+	format := input.choice("format", "mermaid", "mermaid", "dot")
+	includeColumns := input.optionalBool("include_columns", true)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if depth > 0 && len(tableNames) == 0 {
+		return nil, fmt.Errorf("depth requires the tables parameter")
+	}
+
+	logger.Info("Exporting %s ERD for database %s, schema %s, tables %v", format, targetDbID, schema, tableNames)
+
+	meta, err := schemaCache.load(ctx, useCase, targetDbID, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export ERD: %w", err)
+	}
+	if depth > 0 {
+		tableNames, err = relatedTables(meta, tableNames, depth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export ERD: %w", err)
+		}
+	}
+	if len(tableNames) > 0 {
+		meta, err = meta.filterTables(tableNames)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export ERD: %w", err)
+		}
+	}
+
+	// Format the response
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Entity-Relationship Diagram for Database %s\n\n", targetDbID))
+	response.WriteString(fmt.Sprintf("Tables: %d, Relationships: %d\n\n", len(meta.Tables), len(meta.ForeignKeys)))
+	if format == "dot" {
+		response.WriteString("```dot\n")
+		response.WriteString(renderDotERD(meta, includeColumns))
+	} else {
+		response.WriteString("```mermaid\n")
+		response.WriteString(renderMermaidERD(meta, includeColumns))
+	}
+	response.WriteString("```\n")
+
+	return createTextResponse(response.String()), nil
+}
+
+// relatedTables returns the given tables and those within depth foreign key hops of them
+func relatedTables(meta *schemaMetadata, names []string, depth int) ([]string, error) {
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		table := meta.table(name)
+		if table == nil {
+			return nil, fmt.Errorf("table %s not found", name)
+		}
+		keys = append(keys, schemaTableKey(table.Schema, table.Name))
+	}
+	return newJoinGraph(meta).neighborhood(keys, depth), nil
+}
+
+// erdRelationship captures the cardinality of a foreign key for rendering
+type erdRelationship struct {
+	fk       schemaForeignKey
+	unique   bool // child side is unique: one-to-one instead of one-to-many
+	optional bool // child columns are nullable: the parent is optional
+}
+
+// erdRelationships resolves the cardinality of each foreign key in the metadata
+func erdRelationships(meta *schemaMetadata) []erdRelationship {
+	relationships := make([]erdRelationship, 0, len(meta.ForeignKeys))
+	for _, fk := range meta.ForeignKeys {
+		rel := erdRelationship{fk: fk}
+		if child := meta.table(schemaTableKey(fk.Schema, fk.Table)); child != nil {
+			rel.unique = child.isUnique(fk.Columns)
+			rel.optional = child.hasNullableColumn(fk.Columns)
+		}
+		relationships = append(relationships, rel)
+	}
+	return relationships
+}
+
+// columnKeyMarkers returns the PK/FK/UK markers for a column
+func columnKeyMarkers(meta *schemaMetadata, table *schemaTable, column string) []string {
+	var markers []string
+	for _, pk := range table.PrimaryKey {
+		if pk == column {
+			markers = append(markers, "PK")
+			break
+		}
+	}
+	for _, fk := range meta.ForeignKeys {
+		if fk.Schema != table.Schema || fk.Table != table.Name {
+			continue
+		}
+		found := false
+		for _, col := range fk.Columns {
+			if col == column {
+				found = true
+				break
+			}
+		}
+		if found {
+			markers = append(markers, "FK")
+			break
+		}
+	}
+	if len(markers) == 0 && table.isUnique([]string{column}) {
+		markers = append(markers, "UK")
+	}
+	return markers
+}
+
+var nonIdentifierChars = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// mermaidIdent converts a name into a token Mermaid accepts as an entity or type name
+func mermaidIdent(name string) string {
+	ident := strings.Trim(nonIdentifierChars.ReplaceAllString(name, "_"), "_")
+	if ident == "" {
+		return "unnamed"
+	}
+	return ident
+}
+
+// renderMermaidERD renders schema metadata as a Mermaid erDiagram
+func renderMermaidERD(meta *schemaMetadata, includeColumns bool) string {
+	var sb strings.Builder
+	sb.WriteString("erDiagram\n")
+
+	for _, table := range meta.Tables {
+		sb.WriteString(fmt.Sprintf("    %s {\n", mermaidIdent(table.Name)))
+		if includeColumns {
+			for _, col := range table.Columns {
+				line := fmt.Sprintf("        %s %s", mermaidIdent(col.DataType), mermaidIdent(col.Name))
+				if markers := columnKeyMarkers(meta, table, col.Name); len(markers) > 0 {
+					line += " " + strings.Join(markers, ",")
+				}
+				if col.Comment != "" {
+					line += fmt.Sprintf(" \"%s\"", strings.ReplaceAll(col.Comment, "\"", "'"))
+				}
+				sb.WriteString(line + "\n")
+			}
+		}
+		sb.WriteString("    }\n")
+	}
+
+	for _, rel := range erdRelationships(meta) {
+		// Child side: zero-or-one when unique, zero-or-many otherwise
+		childSide := "}o"
+		if rel.unique {
+			childSide = "|o"
+		}
+		// Parent side: zero-or-one when the foreign key is nullable, exactly one otherwise
+		parentSide := "||"
+		if rel.optional {
+			parentSide = "o|"
+		}
+		sb.WriteString(fmt.Sprintf("    %s %s--%s %s : \"%s\"\n",
+			mermaidIdent(rel.fk.Table), childSide, parentSide, mermaidIdent(rel.fk.RefTable),
+			strings.Join(rel.fk.Columns, ", ")))
+	}
+
+	return sb.String()
+}
+
+// dotRecordEscape escapes characters that have special meaning in DOT record labels
+func dotRecordEscape(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		`{`, `\{`,
+		`}`, `\}`,
+		`|`, `\|`,
+		`<`, `\<`,
+		`>`, `\>`,
+	)
+	return replacer.Replace(s)
+}
+
+// dotQuote quotes a string as a DOT identifier
+func dotQuote(s string) string {
+	return "\"" + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + "\""
+}
+
+// renderDotERD renders schema metadata as a Graphviz DOT digraph
+func renderDotERD(meta *schemaMetadata, includeColumns bool) string {
+	var sb strings.Builder
+	sb.WriteString("digraph erd {\n")
+	sb.WriteString("    rankdir=LR;\n")
+	sb.WriteString("    node [shape=record, fontname=\"Helvetica\"];\n")
+	sb.WriteString("    edge [dir=both, fontname=\"Helvetica\"];\n\n")
+
+	for _, table := range meta.Tables {
+		label := dotRecordEscape(table.Name)
+		if includeColumns {
+			var fields []string
+			for _, col := range table.Columns {
+				field := fmt.Sprintf("%s : %s", col.Name, col.DataType)
+				if markers := columnKeyMarkers(meta, table, col.Name); len(markers) > 0 {
+					field += fmt.Sprintf(" (%s)", strings.Join(markers, ", "))
+				}
+				fields = append(fields, dotRecordEscape(field)+`\l`)
+			}
+			label = fmt.Sprintf("{%s|%s}", label, strings.Join(fields, ""))
+		}
+		sb.WriteString(fmt.Sprintf("    %s [label=\"%s\"];\n", dotQuote(table.Name), label))
+	}
+
+	if len(meta.ForeignKeys) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, rel := range erdRelationships(meta) {
+		// Tail (child): crow's foot for many, tee for one
+		tail := "crow"
+		if rel.unique {
+			tail = "tee"
+		}
+		// Head (parent): tee for mandatory, tee with circle for optional
+		head := "tee"
+		if rel.optional {
+			head = "teeodot"
+		}
+		sb.WriteString(fmt.Sprintf("    %s -> %s [label=%s, arrowtail=%s, arrowhead=%s];\n",
+			dotQuote(rel.fk.Table), dotQuote(rel.fk.RefTable), dotQuote(strings.Join(rel.fk.Columns, ", ")), tail, head))
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func testSchemaMetadata() *schemaMetadata {
+	users := &schemaTable{
+		Schema: "public",
+		Name:   "users",
+		Columns: []schemaColumn{
+			{Name: "id", DataType: "integer"},
+			{Name: "email", DataType: "character varying(255)", Comment: "Login \"email\""},
+		},
+		PrimaryKey: []string{"id"},
+		Unique:     [][]string{{"email"}},
+	}
+	orders := &schemaTable{
+		Schema: "public",
+		Name:   "orders",
+		Columns: []schemaColumn{
+			{Name: "id", DataType: "integer"},
+			{Name: "user_id", DataType: "integer"},
+			{Name: "coupon_id", DataType: "integer", Nullable: true},
+		},
+		PrimaryKey: []string{"id"},
+	}
+	profiles := &schemaTable{
+		Schema: "public",
+		Name:   "profiles",
+		Columns: []schemaColumn{
+			{Name: "user_id", DataType: "integer"},
+		},
+		PrimaryKey: []string{"user_id"},
+	}
+	coupons := &schemaTable{
+		Schema:     "public",
+		Name:       "coupons",
+		Columns:    []schemaColumn{{Name: "id", DataType: "integer"}},
+		PrimaryKey: []string{"id"},
+	}
+	return &schemaMetadata{
+		DatabaseType: "postgres",
+		Schema:       "public",
+		Tables:       []*schemaTable{users, orders, profiles, coupons},
+		ForeignKeys: []schemaForeignKey{
+			{Name: "orders_user_fk", Schema: "public", Table: "orders", Columns: []string{"user_id"}, RefSchema: "public", RefTable: "users", RefColumns: []string{"id"}},
+			{Name: "orders_coupon_fk", Schema: "public", Table: "orders", Columns: []string{"coupon_id"}, RefSchema: "public", RefTable: "coupons", RefColumns: []string{"id"}},
+			{Name: "profiles_user_fk", Schema: "public", Table: "profiles", Columns: []string{"user_id"}, RefSchema: "public", RefTable: "users", RefColumns: []string{"id"}},
+		},
+	}
+}
+
+func TestRenderMermaidERD(t *testing.T) {
+	out := renderMermaidERD(testSchemaMetadata(), true)
+
+	assert.True(t, strings.HasPrefix(out, "erDiagram\n"))
+	assert.Contains(t, out, "        integer id PK\n")
+	assert.Contains(t, out, "        character_varying_255 email UK \"Login 'email'\"\n")
+	assert.Contains(t, out, "        integer user_id PK,FK\n")
+	// Many orders per user, user required
+	assert.Contains(t, out, "    orders }o--|| users : \"user_id\"\n")
+	// Nullable foreign key makes the parent optional
+	assert.Contains(t, out, "    orders }o--o| coupons : \"coupon_id\"\n")
+	// Unique foreign key is one-to-one
+	assert.Contains(t, out, "    profiles |o--|| users : \"user_id\"\n")
+}
+
+func TestRenderMermaidERDWithoutColumns(t *testing.T) {
+	out := renderMermaidERD(testSchemaMetadata(), false)
+
+	assert.Contains(t, out, "    users {\n    }\n")
+	assert.NotContains(t, out, "integer")
+}
+
+func TestRenderDotERD(t *testing.T) {
+	out := renderDotERD(testSchemaMetadata(), true)
+
+	assert.True(t, strings.HasPrefix(out, "digraph erd {\n"))
+	assert.True(t, strings.HasSuffix(out, "}\n"))
+	assert.Contains(t, out, `"users" [label="{users|id : integer (PK)\lemail : character varying(255) (UK)\l}"];`)
+	assert.Contains(t, out, `"orders" -> "users" [label="user_id", arrowtail=crow, arrowhead=tee];`)
+	assert.Contains(t, out, `"orders" -> "coupons" [label="coupon_id", arrowtail=crow, arrowhead=teeodot];`)
+	assert.Contains(t, out, `"profiles" -> "users" [label="user_id", arrowtail=tee, arrowhead=tee];`)
+}
+
+func TestFilterTables(t *testing.T) {
+	meta, err := testSchemaMetadata().filterTables([]string{"orders", "public.users"})
+	assert.NoError(t, err)
+	assert.Len(t, meta.Tables, 2)
+	assert.Len(t, meta.ForeignKeys, 1)
+	assert.Equal(t, "orders_user_fk", meta.ForeignKeys[0].Name)
+
+	_, err = testSchemaMetadata().filterTables([]string{"missing"})
+	assert.Error(t, err)
+}
+
+func TestLoadSchemaMetadata(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"information_schema.columns": {Rows: [][]interface{}{
+				{"app", "users", "id", "int", "NO", "", "Application users", nil},
+				{"app", "orders", "id", "int", "NO", "", "", nil},
+				{"app", "orders", "user_id", "int", "YES", "Owner", "", nil},
+			}},
+			"information_schema.table_constraints": {Rows: [][]interface{}{
+				{"fk_orders_users", "FOREIGN KEY", "app", "orders", "user_id", "app", "users", "id"},
+				{"PRIMARY", "PRIMARY KEY", "app", "orders", "id", nil, nil, nil},
+				{"PRIMARY", "PRIMARY KEY", "app", "users", "id", nil, nil, nil},
+			}},
+			"information_schema.statistics": {Rows: [][]interface{}{
+				{"app", "orders", "idx_user", "0", "0", "user_id"},
+			}},
+		},
+	}
+
+	meta, err := loadSchemaMetadata(context.Background(), useCase, "test", "app")
+	assert.NoError(t, err)
+	assert.Len(t, meta.Tables, 2)
+
+	users := meta.table("users")
+	assert.NotNil(t, users)
+	assert.Equal(t, "Application users", users.Comment)
+	assert.Equal(t, []string{"id"}, users.PrimaryKey)
+
+	orders := meta.table("app.orders")
+	assert.NotNil(t, orders)
+	assert.True(t, orders.column("user_id").Nullable)
+	assert.Equal(t, []string{"id"}, orders.PrimaryKey)
+	assert.Len(t, orders.Indexes, 1)
+	assert.False(t, orders.Indexes[0].Unique)
+
+	assert.Len(t, meta.ForeignKeys, 1)
+	assert.Equal(t, "users", meta.ForeignKeys[0].RefTable)
+	assert.Equal(t, []string{"id"}, meta.ForeignKeys[0].RefColumns)
+}
+
+func TestLoadSchemaMetadataSpannerInterleaving(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "spanner",
+		results: map[string]*domain.QueryResult{
+			"FROM INFORMATION_SCHEMA.COLUMNS c": {Rows: [][]interface{}{
+				{"", "Albums", "SingerId", "INT64", "NO", "", "", ""},
+				{"", "Albums", "AlbumId", "INT64", "NO", "", "", ""},
+				{"", "Singers", "SingerId", "INT64", "NO", "", "", ""},
+			}},
+			"FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc": {Rows: [][]interface{}{
+				{"INTERLEAVE IN Singers", "INTERLEAVE", "", "Albums", "SingerId", "", "Singers", "SingerId", int64(1)},
+				{"PK_Albums", "PRIMARY KEY", "", "Albums", "SingerId", nil, nil, nil, int64(1)},
+				{"PK_Albums", "PRIMARY KEY", "", "Albums", "AlbumId", nil, nil, nil, int64(2)},
+				{"PK_Singers", "PRIMARY KEY", "", "Singers", "SingerId", nil, nil, nil, int64(1)},
+			}},
+		},
+	}
+
+	meta, err := loadSchemaMetadata(context.Background(), useCase, "sp1", "")
+	assert.NoError(t, err)
+	albums := meta.table("Albums")
+	assert.Equal(t, "Singers", albums.InterleavedIn)
+	assert.Equal(t, []string{"SingerId", "AlbumId"}, albums.PrimaryKey)
+	assert.Empty(t, meta.table("Singers").InterleavedIn)
+
+	assert.Len(t, meta.ForeignKeys, 1)
+	assert.Equal(t, schemaForeignKey{Name: "INTERLEAVE IN Singers", Table: "Albums", Columns: []string{"SingerId"}, RefTable: "Singers", RefColumns: []string{"SingerId"}}, meta.ForeignKeys[0])
+}
+
+func TestExportERDToolRejectsUnknownFormat(t *testing.T) {
+	tool := NewExportERDTool()
+	_, err := tool.HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "test", "format": "svg"},
+	}, "", &mockUseCase{dbType: "postgres"})
+	assert.Error(t, err)
+}
+
+func TestRelatedTables(t *testing.T) {
+	names, err := relatedTables(testSchemaMetadata(), []string{"profiles"}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"public.profiles", "public.users"}, names)
+
+	names, err = relatedTables(testSchemaMetadata(), []string{"profiles"}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"public.profiles", "public.users", "public.orders"}, names)
+
+	names, err = relatedTables(testSchemaMetadata(), []string{"profiles"}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"public.profiles", "public.users", "public.orders", "public.coupons"}, names)
+
+	_, err = relatedTables(testSchemaMetadata(), []string{"missing"}, 1)
+	assert.ErrorContains(t, err, "table missing not found")
+}
+
+func TestExportERDToolDepthRequiresTables(t *testing.T) {
+	_, err := NewExportERDTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "test", "depth": 1},
+	}, "", &mockUseCase{dbType: "postgres"})
+	assert.ErrorContains(t, err, "depth requires the tables parameter")
+}
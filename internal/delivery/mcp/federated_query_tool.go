@@ -0,0 +1,351 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// maxFederatedRows caps the rows a federated join may produce in memory
+const maxFederatedRows = 200000
+
+// errSourceFull stops reading a source once it returned its row limit
+var errSourceFull = errors.New("source row limit reached")
+
+// federatedSource is one sub-query of a federated query
+type federatedSource struct {
+	Name     string
+	Database string
+	Query    string
+	Params   []interface{}
+	// Key holds the columns the source is joined on
+	Key []string
+
+	Columns   []string
+	Rows      [][]interface{}
+	Truncated bool
+}
+
+// sourceCollector gathers the rows of a source up to its limit
+type sourceCollector struct {
+	source *federatedSource
+	limit  int
+}
+
+func (c *sourceCollector) Columns(columns []string) error {
+	c.source.Columns = columns
+	return nil
+}
+
+func (c *sourceCollector) Row(values []interface{}) error {
+	if len(c.source.Rows) >= c.limit {
+		c.source.Truncated = true
+		return errSourceFull
+	}
+	c.source.Rows = append(c.source.Rows, values)
+	return nil
+}
+
+// FederatedQueryTool handles queries spanning several databases
+type FederatedQueryTool struct {
+	BaseToolType
+}
+
+// NewFederatedQueryTool creates a new federated query tool type
+func NewFederatedQueryTool() *FederatedQueryTool {
+	return &FederatedQueryTool{
+		BaseToolType: BaseToolType{
+			name:        "federated_query",
+			description: "Answer a question spanning several configured databases in one call: run a sub-query on each database and join or union their rows in memory. A join matches rows on key columns (compared as text, NULL keys never match), folding each source into the rows of the first; inner, left and full joins are supported, and columns are named source.column. A union stacks the rows, aligning columns by name and adding a source column. Each source reads at most row_limit rows, so joins over larger results are flagged as incomplete; filter and aggregate in the sub-queries to keep them small.",
+		},
+	}
+}
+
+// CreateTool creates a federated query tool
+func (t *FederatedQueryTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(name, append([]tools.ToolOption{
+		tools.WithDescription("Join or union the results of sub-queries on two or more databases"),
+		tools.WithArray("sources",
+			tools.Description("Sub-queries: objects with database, query, optional params, optional name (default: the database ID) and key, the column or columns to join on"),
+			tools.Items(map[string]interface{}{"type": "object"}),
+			tools.Required(),
+		),
+		tools.WithString("combine",
+			tools.Description("join or union (default: join)"),
+		),
+		tools.WithString("join_type",
+			tools.Description("inner, left (keep every row of the first source) or full (default: inner)"),
+		),
+		tools.WithArray("join_columns",
+			tools.Description("Columns to join on in every source that sets no key"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithBoolean("distinct",
+			tools.Description("Drop duplicate rows from a union (default: false)"),
+		),
+		tools.WithNumber("row_limit",
+			tools.Description("Maximum number of rows read from each source (default: 10000, max: 100000)"),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of combined rows to return (default: 100, max: 10000)"),
+		),
+	}, resultParameters()...)...)
+}
+
+// HandleRequest handles federated query tool requests
+func (t *FederatedQueryTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	rawSources := input.objectList("sources")
+	combine := input.choice("combine", "join", "join", "union")
+	joinType := input.choice("join_type", "inner", "inner", "left", "full")
+	joinColumns := input.stringList("join_columns")
+	distinct := input.optionalBool("distinct", false)
+	rowLimit := input.intBetween("row_limit", 10000, 1, 100000)
+	limit := input.intBetween("limit", 100, 1, 10000)
+	options := readResultOptions(input)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	sources, err := parseFederatedSources(rawSources, joinColumns, combine == "join")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, source := range sources {
+		logger.Info("Running federated sub-query %s on database %s", source.Name, source.Database)
+		_, err := useCase.StreamQuery(ctx, source.Database, source.Query, source.Params, &sourceCollector{source: source, limit: rowLimit})
+		if err != nil && !errors.Is(err, errSourceFull) {
+			return nil, fmt.Errorf("failed to run source %s on database %s: %w", source.Name, source.Database, err)
+		}
+	}
+
+	var combined *domain.QueryResult
+	if combine == "join" {
+		combined, err = joinFederatedSources(sources, joinType)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		combined = unionFederatedSources(sources, distinct)
+	}
+	total := len(combined.Rows)
+	if total > limit {
+		combined.Rows = combined.Rows[:limit]
+	}
+
+	var response strings.Builder
+	response.WriteString("# Federated Query\n\n")
+	response.WriteString("| Source | Database | Rows | Key |\n")
+	response.WriteString("|--------|----------|------|-----|\n")
+	var warnings []string
+	for _, source := range sources {
+		rows := fmt.Sprintf("%d", len(source.Rows))
+		if source.Truncated {
+			rows += "+"
+			warnings = append(warnings, fmt.Sprintf("Source %s returned more than %d rows and only the first %d were combined, so the result may be incomplete; filter or aggregate in its query, or raise row_limit.", source.Name, rowLimit, rowLimit))
+		}
+		response.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", source.Name, source.Database, rows, strings.Join(source.Key, ", ")))
+	}
+	description := "union"
+	if combine == "join" {
+		description = joinType + " join"
+	} else if distinct {
+		description = "distinct union"
+	}
+	response.WriteString(fmt.Sprintf("\nCombined with a %s: %d rows", description, total))
+	if total > limit {
+		response.WriteString(fmt.Sprintf(", showing the first %d", limit))
+	}
+	response.WriteString("\n\n")
+	response.WriteString(encodeQueryResult(combined, options))
+
+	if len(warnings) > 0 {
+		response.WriteString("\n\n## Warnings\n\n")
+		for _, w := range warnings {
+			response.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+	return createTextResponse(response.String()), nil
+}
+
+// parseFederatedSources validates the sources parameter; joined sources need keys of equal length
+func parseFederatedSources(raw []map[string]interface{}, joinColumns []string, join bool) ([]*federatedSource, error) {
+	if len(raw) < 2 {
+		return nil, fmt.Errorf("sources parameter must list at least two sub-queries")
+	}
+	sources := make([]*federatedSource, 0, len(raw))
+	names := make(map[string]bool, len(raw))
+	for i, m := range raw {
+		item := newToolParams(m)
+		source := &federatedSource{
+			Database: item.requiredString("database"),
+			Query:    item.requiredString("query"),
+			Params:   item.list("params"),
+		}
+		source.Name = item.optionalString("name", source.Database)
+		switch key := m["key"].(type) {
+		case string:
+			source.Key = []string{key}
+		default:
+			source.Key = item.stringList("key")
+		}
+		if err := item.err(); err != nil {
+			return nil, fmt.Errorf("source %d: %w", i+1, err)
+		}
+		if names[source.Name] {
+			return nil, fmt.Errorf("source %d: name %s is already used; give sources on the same database a name", i+1, source.Name)
+		}
+		names[source.Name] = true
+		if len(source.Key) == 0 {
+			source.Key = joinColumns
+		}
+		if join {
+			if len(source.Key) == 0 {
+				return nil, fmt.Errorf("source %s has no key; set key on each source or join_columns", source.Name)
+			}
+			if len(sources) > 0 && len(source.Key) != len(sources[0].Key) {
+				return nil, fmt.Errorf("source %s joins on %d columns but source %s on %d", source.Name, len(source.Key), sources[0].Name, len(sources[0].Key))
+			}
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// federatedKey returns the text of the key columns of a row, or false when a key is NULL
+func federatedKey(row []interface{}, positions []int) (string, bool) {
+	parts := make([]string, len(positions))
+	for i, position := range positions {
+		if position >= len(row) || row[position] == nil {
+			return "", false
+		}
+		parts[i] = formatDiffValue(row[position])
+	}
+	return strings.Join(parts, "\x00"), true
+}
+
+// keyPositions finds the key columns of a source in its result
+func (s *federatedSource) keyPositions() ([]int, error) {
+	index := columnIndex(s.Columns)
+	positions := make([]int, len(s.Key))
+	for i, column := range s.Key {
+		position, ok := index[column]
+		if !ok {
+			return nil, fmt.Errorf("key column %s is not in the result of source %s (columns: %s)", column, s.Name, strings.Join(s.Columns, ", "))
+		}
+		positions[i] = position
+	}
+	return positions, nil
+}
+
+// joinFederatedSources joins every source into the rows of the first on their keys
+func joinFederatedSources(sources []*federatedSource, joinType string) (*domain.QueryResult, error) {
+	first := sources[0]
+	firstKey, err := first.keyPositions()
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.QueryResult{Rows: first.Rows}
+	for _, column := range first.Columns {
+		result.Columns = append(result.Columns, first.Name+"."+column)
+	}
+
+	for _, source := range sources[1:] {
+		positions, err := source.keyPositions()
+		if err != nil {
+			return nil, err
+		}
+		index := make(map[string][]int)
+		for i, row := range source.Rows {
+			if key, ok := federatedKey(row, positions); ok {
+				index[key] = append(index[key], i)
+			}
+		}
+
+		width := len(result.Columns)
+		matched := make([]bool, len(source.Rows))
+		var rows [][]interface{}
+		for _, row := range result.Rows {
+			var matches []int
+			if key, ok := federatedKey(row, firstKey); ok {
+				matches = index[key]
+			}
+			if len(matches) == 0 && joinType != "inner" {
+				matches = []int{-1}
+			}
+			for _, match := range matches {
+				joined := make([]interface{}, width, width+len(source.Columns))
+				copy(joined, row)
+				if match < 0 {
+					joined = append(joined, make([]interface{}, len(source.Columns))...)
+				} else {
+					matched[match] = true
+					joined = append(joined, source.Rows[match]...)
+				}
+				rows = append(rows, joined)
+			}
+			if len(rows) > maxFederatedRows {
+				return nil, fmt.Errorf("the join produced more than %d rows; join on more selective keys or filter the sources", maxFederatedRows)
+			}
+		}
+		if joinType == "full" {
+			for i, row := range source.Rows {
+				if !matched[i] {
+					rows = append(rows, append(make([]interface{}, width, width+len(row)), row...))
+				}
+			}
+		}
+		for _, column := range source.Columns {
+			result.Columns = append(result.Columns, source.Name+"."+column)
+		}
+		result.Rows = rows
+	}
+	return result, nil
+}
+
+// unionFederatedSources stacks the rows of the sources, aligning columns by name
+func unionFederatedSources(sources []*federatedSource, distinct bool) *domain.QueryResult {
+	result := &domain.QueryResult{Columns: []string{"source"}}
+	positions := make(map[string]int)
+	for _, source := range sources {
+		for _, column := range source.Columns {
+			if _, ok := positions[column]; !ok {
+				positions[column] = len(result.Columns)
+				result.Columns = append(result.Columns, column)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, source := range sources {
+		for _, row := range source.Rows {
+			values := make([]interface{}, len(result.Columns))
+			values[0] = source.Name
+			for i, column := range source.Columns {
+				if i < len(row) {
+					values[positions[column]] = row[i]
+				}
+			}
+			if distinct {
+				parts := make([]string, len(values)-1)
+				for i, value := range values[1:] {
+					parts[i] = formatDiffValue(value)
+				}
+				key := strings.Join(parts, "\x00")
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+			result.Rows = append(result.Rows, values)
+		}
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func federatedTestSources() []*federatedSource {
+	return []*federatedSource{
+		{
+			Name:    "crm",
+			Key:     []string{"id"},
+			Columns: []string{"id", "name"},
+			Rows: [][]interface{}{
+				{int64(1), "Ada"},
+				{int64(2), "Grace"},
+				{nil, "Nobody"},
+			},
+		},
+		{
+			Name:    "billing",
+			Key:     []string{"customer_id"},
+			Columns: []string{"customer_id", "total"},
+			Rows: [][]interface{}{
+				{float64(1), int64(30)},
+				{int64(1), int64(12)},
+				{int64(3), int64(7)},
+			},
+		},
+	}
+}
+
+func TestJoinFederatedSources(t *testing.T) {
+	inner, err := joinFederatedSources(federatedTestSources(), "inner")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"crm.id", "crm.name", "billing.customer_id", "billing.total"}, inner.Columns)
+	assert.Equal(t, [][]interface{}{
+		{int64(1), "Ada", float64(1), int64(30)},
+		{int64(1), "Ada", int64(1), int64(12)},
+	}, inner.Rows)
+
+	left, err := joinFederatedSources(federatedTestSources(), "left")
+	assert.NoError(t, err)
+	assert.Len(t, left.Rows, 4)
+	assert.Equal(t, []interface{}{int64(2), "Grace", nil, nil}, left.Rows[2])
+	assert.Equal(t, []interface{}{nil, "Nobody", nil, nil}, left.Rows[3])
+
+	full, err := joinFederatedSources(federatedTestSources(), "full")
+	assert.NoError(t, err)
+	assert.Len(t, full.Rows, 5)
+	assert.Equal(t, []interface{}{nil, nil, int64(3), int64(7)}, full.Rows[4])
+
+	sources := federatedTestSources()
+	sources[1].Key = []string{"missing"}
+	_, err = joinFederatedSources(sources, "inner")
+	assert.ErrorContains(t, err, "key column missing is not in the result of source billing")
+}
+
+func TestUnionFederatedSources(t *testing.T) {
+	sources := []*federatedSource{
+		{Name: "eu", Columns: []string{"sku", "qty"}, Rows: [][]interface{}{{"a", int64(1)}, {"b", int64(2)}}},
+		{Name: "us", Columns: []string{"qty", "sku", "note"}, Rows: [][]interface{}{{int64(1), "a", nil}, {int64(5), "c", "new"}}},
+	}
+
+	union := unionFederatedSources(sources, false)
+	assert.Equal(t, []string{"source", "sku", "qty", "note"}, union.Columns)
+	assert.Equal(t, [][]interface{}{
+		{"eu", "a", int64(1), nil},
+		{"eu", "b", int64(2), nil},
+		{"us", "a", int64(1), nil},
+		{"us", "c", int64(5), "new"},
+	}, union.Rows)
+
+	distinct := unionFederatedSources(sources, true)
+	assert.Len(t, distinct.Rows, 3)
+	assert.Equal(t, "c", distinct.Rows[2][1])
+}
+
+func TestParseFederatedSources(t *testing.T) {
+	_, err := parseFederatedSources([]map[string]interface{}{{"database": "a", "query": "SELECT 1"}}, nil, true)
+	assert.ErrorContains(t, err, "at least two")
+
+	_, err = parseFederatedSources([]map[string]interface{}{
+		{"database": "a", "query": "SELECT 1", "key": "id"},
+		{"database": "a", "query": "SELECT 2", "key": "id"},
+	}, nil, true)
+	assert.ErrorContains(t, err, "name a is already used")
+
+	_, err = parseFederatedSources([]map[string]interface{}{
+		{"database": "a", "query": "SELECT 1"},
+		{"database": "b", "query": "SELECT 2"},
+	}, nil, true)
+	assert.ErrorContains(t, err, "source a has no key")
+
+	_, err = parseFederatedSources([]map[string]interface{}{
+		{"database": "a", "query": "SELECT 1", "key": []interface{}{"id", "region"}},
+		{"database": "b", "query": "SELECT 2"},
+	}, []string{"id"}, true)
+	assert.ErrorContains(t, err, "source b joins on 1 columns but source a on 2")
+
+	sources, err := parseFederatedSources([]map[string]interface{}{
+		{"database": "a", "query": "SELECT 1", "name": "orders"},
+		{"database": "b", "query": "SELECT 2", "params": []interface{}{"x"}},
+	}, []string{"id"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "orders", sources[0].Name)
+	assert.Equal(t, "b", sources[1].Name)
+	assert.Equal(t, []string{"id"}, sources[1].Key)
+	assert.Equal(t, []interface{}{"x"}, sources[1].Params)
+}
+
+func TestFederatedQueryToolHandleRequest(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM customers": {
+				Columns: []string{"id", "name"},
+				Rows:    [][]interface{}{{int64(1), "Ada"}, {int64(2), "Grace"}, {int64(3), "Linus"}},
+			},
+			"FROM invoices": {
+				Columns: []string{"customer_id", "total"},
+				Rows:    [][]interface{}{{int64(2), int64(40)}, {int64(1), int64(10)}},
+			},
+		},
+	}
+	tool := NewFederatedQueryTool()
+	request := server.ToolCallRequest{Parameters: map[string]interface{}{
+		"sources": []interface{}{
+			map[string]interface{}{"database": "crm", "query": "SELECT id, name FROM customers", "key": "id"},
+			map[string]interface{}{"database": "billing", "query": "SELECT customer_id, total FROM invoices", "key": "customer_id"},
+		},
+		"join_type": "left",
+		"row_limit": float64(2),
+		"limit":     float64(1),
+	}}
+
+	result, err := tool.HandleRequest(context.Background(), request, "", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| crm | crm | 2+ | id |")
+	assert.Contains(t, text, "| billing | billing | 2 | customer_id |")
+	assert.Contains(t, text, "Combined with a left join: 2 rows, showing the first 1")
+	assert.Contains(t, text, "crm.name")
+	assert.Contains(t, text, "billing.total")
+	assert.Contains(t, text, "Source crm returned more than 2 rows")
+	assert.NotContains(t, text, "Linus")
+
+	useCase.queryErr = assert.AnError
+	_, err = tool.HandleRequest(context.Background(), request, "", useCase)
+	assert.ErrorContains(t, err, "failed to run source crm on database crm")
+}
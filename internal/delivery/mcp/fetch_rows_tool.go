@@ -0,0 +1,62 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// FetchRowsTool reads the rows of a query result that were spilled to disk
+type FetchRowsTool struct {
+	BaseToolType
+}
+
+// NewFetchRowsTool creates a new fetch rows tool type
+func NewFetchRowsTool() *FetchRowsTool {
+	return &FetchRowsTool{
+		BaseToolType: BaseToolType{
+			name:        "fetch_rows",
+			description: "Read the next page of a query result whose rows past the connection's result_memory_limit were spilled to disk. Query tools return a cursor for such results; pass it here until no cursor is returned. Cursors expire 10 minutes after their last read.",
+		},
+	}
+}
+
+// CreateTool creates a fetch rows tool
+func (t *FetchRowsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(name, append([]tools.ToolOption{
+		tools.WithDescription("Read the next page of a spilled query result by cursor"),
+		tools.WithString("database",
+			tools.Description("Database ID the query ran on"),
+			tools.Required(),
+		),
+		tools.WithString("cursor",
+			tools.Description("Cursor returned with the query result"),
+			tools.Required(),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Most rows to return (default: 1000)"),
+		),
+	}, resultParameters()...)...)
+}
+
+// HandleRequest handles fetch rows tool requests
+func (t *FetchRowsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	cursor := input.requiredString("cursor")
+	limit := input.intAtLeast("limit", 1000, 1)
+	options := readResultOptions(input)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Fetching up to %d spilled rows of database %s with cursor %s", limit, targetDbID, cursor)
+
+	result, err := useCase.FetchRows(ctx, targetDbID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return createTextResponse(encodeQueryResult(result, options)), nil
+}
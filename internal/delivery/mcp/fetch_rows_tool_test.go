@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestFetchRowsTool(t *testing.T) {
+	useCase := &mockUseCase{dbType: "postgres", pages: map[string]*domain.QueryResult{
+		"first": {Columns: []string{"id"}, Rows: [][]interface{}{{int64(3)}, {int64(4)}}, Cursor: "first", Remaining: 1},
+		"last":  {Columns: []string{"id"}, Rows: [][]interface{}{{int64(5)}}},
+	}}
+
+	result, err := NewFetchRowsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "cursor": "first", "limit": float64(2)},
+	}, "", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "3\n4\n\nTotal rows: 2\n1 more rows were spilled to disk; read them with fetch_rows and cursor first")
+
+	result, err = NewFetchRowsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "cursor": "last", "format": "json"},
+	}, "", useCase)
+	assert.NoError(t, err)
+	text = result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(text), &decoded))
+	assert.Equal(t, float64(1), decoded["total_rows"])
+	assert.NotContains(t, decoded, "cursor")
+
+	_, err = NewFetchRowsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "cursor": "gone"},
+	}, "", useCase)
+	assert.ErrorContains(t, err, "expired")
+
+	_, err = NewFetchRowsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "cursor": "first", "limit": float64(0)},
+	}, "", useCase)
+	assert.Error(t, err)
+}
@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// FindJoinPathTool handles finding foreign key paths between two tables
+type FindJoinPathTool struct {
+	BaseToolType
+}
+
+// NewFindJoinPathTool creates a new find join path tool type
+func NewFindJoinPathTool() *FindJoinPathTool {
+	return &FindJoinPathTool{
+		BaseToolType: BaseToolType{
+			name:        "find_join_path",
+			description: "Find how two tables are connected through foreign keys. This tool searches the foreign key graph of a schema for the shortest path between two tables and alternative routes, and returns each path with the ready-to-use FROM/JOIN clause and its ON conditions. Use it instead of guessing join conditions between tables that are not directly related.",
+		},
+	}
+}
+
+// CreateTool creates a find join path tool
+func (t *FindJoinPathTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Find foreign key join paths between two tables"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("from_table",
+			tools.Description("Table to start from"),
+			tools.Required(),
+		),
+		tools.WithString("to_table",
+			tools.Description("Table to reach"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to inspect (optional, defaults to public on PostgreSQL and the current database on MySQL)"),
+		),
+		tools.WithNumber("max_depth",
+			tools.Description("Maximum number of joins in a path (default: 4)"),
+		),
+		tools.WithNumber("max_paths",
+			tools.Description("Maximum number of paths to return (default: 3)"),
+		),
+	)
+}
+
+// HandleRequest handles find join path tool requests
+func (t *FindJoinPathTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	fromTable := input.requiredString("from_table")
+	toTable := input.requiredString("to_table")
+	schema := input.optionalString("schema", "")
+	maxDepth := input.intAtLeast("max_depth", 4, 1)
+	maxPaths := input.intAtLeast("max_paths", 3, 1)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Finding join path from %s to %s in database %s", fromTable, toTable, targetDbID)
+
+	meta, err := schemaCache.load(ctx, useCase, targetDbID, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find join path: %w", err)
+	}
+	from := meta.table(fromTable)
+	if from == nil {
+		return nil, fmt.Errorf("table %s not found", fromTable)
+	}
+	to := meta.table(toTable)
+	if to == nil {
+		return nil, fmt.Errorf("table %s not found", toTable)
+	}
+
+	graph := newJoinGraph(meta)
+	paths := graph.paths(schemaTableKey(from.Schema, from.Name), schemaTableKey(to.Schema, to.Name), maxDepth, maxPaths)
+
+	// Format the response
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Join Paths from %s to %s in Database %s\n\n", from.Name, to.Name, targetDbID))
+	if len(paths) == 0 {
+		response.WriteString(fmt.Sprintf("No foreign key path found within %d joins.\n", maxDepth))
+		return createTextResponse(response.String()), nil
+	}
+
+	for i, path := range paths {
+		label := "alternative"
+		if i == 0 {
+			label = "shortest"
+		}
+		joins := "joins"
+		if len(path) == 1 {
+			joins = "join"
+		}
+		response.WriteString(fmt.Sprintf("## Path %d (%s, %d %s)\n\n", i+1, label, len(path), joins))
+		response.WriteString(graph.describe(path) + "\n\n")
+		response.WriteString(fmt.Sprintf("```sql\n%s\n```\n\n", graph.joinClause(meta.DatabaseType, path)))
+	}
+
+	return createTextResponse(response.String()), nil
+}
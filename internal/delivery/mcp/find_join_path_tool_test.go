@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinGraphDirectPath(t *testing.T) {
+	meta := testSchemaMetadata()
+	graph := newJoinGraph(meta)
+
+	paths := graph.paths("public.orders", "public.users", 4, 3)
+	assert.Len(t, paths, 1)
+	assert.Equal(t, "orders(user_id) -> users(id)", graph.describe(paths[0]))
+	assert.Equal(t, "FROM \"orders\"\nJOIN \"users\" ON \"users\".\"id\" = \"orders\".\"user_id\"",
+		graph.joinClause("postgres", paths[0]))
+}
+
+func TestJoinGraphTransitivePath(t *testing.T) {
+	meta := testSchemaMetadata()
+	graph := newJoinGraph(meta)
+
+	paths := graph.paths("public.profiles", "public.coupons", 4, 3)
+	assert.Len(t, paths, 1)
+	assert.Equal(t, "profiles(user_id) -> users(id), users(id) <- orders(user_id), orders(coupon_id) -> coupons(id)",
+		graph.describe(paths[0]))
+	assert.Equal(t, "FROM `profiles`\n"+
+		"JOIN `users` ON `users`.`id` = `profiles`.`user_id`\n"+
+		"JOIN `orders` ON `orders`.`user_id` = `users`.`id`\n"+
+		"JOIN `coupons` ON `coupons`.`id` = `orders`.`coupon_id`",
+		graph.joinClause("mysql", paths[0]))
+
+	assert.Empty(t, graph.paths("public.profiles", "public.coupons", 2, 3))
+}
+
+func TestJoinGraphAlternativePaths(t *testing.T) {
+	meta := testSchemaMetadata()
+	// A second route from orders to users through the user's profile
+	meta.ForeignKeys = append(meta.ForeignKeys, schemaForeignKey{
+		Name: "orders_profile_fk", Schema: "public", Table: "orders", Columns: []string{"user_id"},
+		RefSchema: "public", RefTable: "profiles", RefColumns: []string{"user_id"},
+	})
+	graph := newJoinGraph(meta)
+
+	paths := graph.paths("public.orders", "public.users", 4, 3)
+	assert.Len(t, paths, 2)
+	assert.Len(t, paths[0], 1)
+	assert.Len(t, paths[1], 2)
+}
@@ -0,0 +1,294 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// sequenceCounter is a sequence or auto-increment counter feeding a table column
+type sequenceCounter struct {
+	Schema string
+	Table  string
+	Column string
+	// Sequence is the quoted sequence name on PostgreSQL and empty on MySQL
+	Sequence  string
+	NextValue int64
+	MaxValue  int64
+	HasRows   bool
+}
+
+// lagging reports whether the next generated value would collide with an existing row
+func (c sequenceCounter) lagging() bool {
+	return c.HasRows && c.NextValue <= c.MaxValue
+}
+
+// FixSequencesTool handles detecting and repairing sequences that lag behind their columns
+type FixSequencesTool struct {
+	BaseToolType
+}
+
+// NewFixSequencesTool creates a new sequence repair tool type
+func NewFixSequencesTool() *FixSequencesTool {
+	return &FixSequencesTool{
+		BaseToolType: BaseToolType{
+			name:        "fix_sequences",
+			description: "Detect sequences (PostgreSQL serial and identity columns) and AUTO_INCREMENT counters (MySQL) whose next value is not above the column's MAX, which causes duplicate key errors typically after restores or bulk loads with explicit ids, and fix them with setval or ALTER TABLE ... AUTO_INCREMENT. Use dry_run to only report the drift and the statements.",
+		},
+	}
+}
+
+// CreateTool creates a sequence repair tool
+func (t *FixSequencesTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Detect and fix sequences and auto-increment counters lagging behind MAX(id)"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to check (default: public on PostgreSQL, the current database on MySQL)"),
+		),
+		tools.WithArray("tables",
+			tools.Description("Only check these tables"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithBoolean("dry_run",
+			tools.Description("Only report lagging counters and the fix statements (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles sequence repair tool requests
+func (t *FixSequencesTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema := input.optionalString("schema", "")
+	tables := input.stringList("tables")
+	dryRun := input.optionalBool("dry_run", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for fix_sequences: %s", dbType)
+	}
+	fixer, err := dialect.SequenceFixer()
+	if err != nil {
+		return nil, unsupportedAdminError("fix_sequences", dbType, err)
+	}
+	if schema == "" {
+		schema = fixer.defaultSchema()
+	}
+
+	counters, err := loadSequenceCounters(ctx, useCase, targetDbID, fixer, schema, tables)
+	if err != nil {
+		return nil, err
+	}
+	if err := loadSequenceColumnMaxima(ctx, useCase, targetDbID, dialect.Name(), counters); err != nil {
+		return nil, err
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Sequence Drift for Database %s\n\n", targetDbID))
+	if len(counters) == 0 {
+		response.WriteString("No sequence or auto-increment columns found.\n")
+		return createTextResponse(response.String()), nil
+	}
+
+	response.WriteString("| Table | Column | Counter | Next value | MAX | Status |\n")
+	response.WriteString("|-------|--------|---------|------------|-----|--------|\n")
+	var statements []string
+	for _, counter := range counters {
+		counterName, maxValue, status := counter.Sequence, "-", "ok"
+		if counterName == "" {
+			counterName = "AUTO_INCREMENT"
+		}
+		if counter.HasRows {
+			maxValue = fmt.Sprintf("%d", counter.MaxValue)
+		}
+		if counter.lagging() {
+			status = fmt.Sprintf("lagging by %d", counter.MaxValue-counter.NextValue+1)
+			statements = append(statements, fixer.fixStatement(counter))
+		}
+		response.WriteString(fmt.Sprintf("| %s | %s | %s | %d | %s | %s |\n",
+			qualifiedName(counter.Schema, counter.Table), counter.Column, counterName, counter.NextValue, maxValue, status))
+	}
+
+	if len(statements) == 0 {
+		response.WriteString("\nAll counters are ahead of their columns.\n")
+		return createTextResponse(response.String()), nil
+	}
+	response.WriteString("\n```sql\n")
+	for _, statement := range statements {
+		response.WriteString(statement + ";\n")
+	}
+	response.WriteString("```\n")
+	if note := fixer.note(); note != "" {
+		response.WriteString(fmt.Sprintf("\nNote: %s\n", note))
+	}
+
+	if dryRun {
+		response.WriteString("\nDry run: nothing was executed.\n")
+		return createTextResponse(response.String()), nil
+	}
+	err = useCase.ExecuteBatch(ctx, targetDbID, statements, nil)
+	audit(request, auditRecord{
+		Tool:      "fix_sequences",
+		Database:  targetDbID,
+		Action:    "fix",
+		Statement: strings.Join(statements, ";\n"),
+	}, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fix sequences: %w", err)
+	}
+	logger.Info("Fixed %d lagging sequence(s) on database %s", len(statements), targetDbID)
+	response.WriteString(fmt.Sprintf("\nFixed %d counter(s).\n", len(statements)))
+
+	return createTextResponse(response.String()), nil
+}
+
+// sequenceFixer is the engine-specific part of repairing lagging counters
+type sequenceFixer interface {
+	// defaultSchema is the schema checked when none is given, empty for the current database
+	defaultSchema() string
+	// countersQuery lists the columns fed by a counter in the fields of sequenceCounter
+	countersQuery(schema string, tables []string) (string, []interface{})
+	// fixStatement moves a counter so the next generated value is MAX + 1
+	fixStatement(counter sequenceCounter) string
+	// note is shown below the fix statements, if not empty
+	note() string
+}
+
+// postgresSequenceFixer repairs the sequences of serial and identity columns with setval
+type postgresSequenceFixer struct{}
+
+func (postgresSequenceFixer) defaultSchema() string { return "public" }
+
+// countersQuery finds the sequences through pg_depend: serial columns own their sequence with
+// an 'a' dependency, identity columns with an 'i' one
+func (postgresSequenceFixer) countersQuery(schema string, tables []string) (string, []interface{}) {
+	params := newSQLParams("postgres")
+	query := fmt.Sprintf(`
+SELECT tn.nspname, t.relname, a.attname,
+       quote_ident(sn.nspname) || '.' || quote_ident(s.relname),
+       COALESCE(ps.last_value + ps.increment_by, ps.start_value)
+FROM pg_depend d
+JOIN pg_class s ON s.oid = d.objid AND s.relkind = 'S'
+JOIN pg_namespace sn ON sn.oid = s.relnamespace
+JOIN pg_sequences ps ON ps.schemaname = sn.nspname AND ps.sequencename = s.relname
+JOIN pg_class t ON t.oid = d.refobjid
+JOIN pg_namespace tn ON tn.oid = t.relnamespace
+JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = d.refobjsubid
+WHERE d.classid = 'pg_class'::regclass AND d.refclassid = 'pg_class'::regclass
+  AND d.deptype IN ('a', 'i') AND ps.increment_by > 0 AND tn.nspname = %s`, params.add(schema))
+	if len(tables) > 0 {
+		query += " AND t.relname IN (" + sequenceTableList(params, tables) + ")"
+	}
+	return query + "\nORDER BY t.relname, a.attnum", params.values
+}
+
+func (postgresSequenceFixer) fixStatement(counter sequenceCounter) string {
+	return fmt.Sprintf("SELECT setval(%s, %d)", quoteLiteral("postgres", counter.Sequence), counter.MaxValue)
+}
+
+func (postgresSequenceFixer) note() string { return "" }
+
+// mysqlSequenceFixer repairs AUTO_INCREMENT counters with ALTER TABLE
+type mysqlSequenceFixer struct{}
+
+func (mysqlSequenceFixer) defaultSchema() string { return "" }
+
+func (mysqlSequenceFixer) countersQuery(schema string, tables []string) (string, []interface{}) {
+	params := newSQLParams("mysql")
+	schemaExpr := "DATABASE()"
+	if schema != "" {
+		schemaExpr = params.add(schema)
+	}
+	query := fmt.Sprintf(`
+SELECT c.TABLE_SCHEMA, c.TABLE_NAME, c.COLUMN_NAME, '', COALESCE(t.AUTO_INCREMENT, 1)
+FROM information_schema.COLUMNS c
+JOIN information_schema.TABLES t ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+WHERE c.TABLE_SCHEMA = %s AND c.EXTRA LIKE '%%auto_increment%%' AND t.TABLE_TYPE = 'BASE TABLE'`, schemaExpr)
+	if len(tables) > 0 {
+		query += " AND c.TABLE_NAME IN (" + sequenceTableList(params, tables) + ")"
+	}
+	return query + "\nORDER BY c.TABLE_NAME", params.values
+}
+
+func (mysqlSequenceFixer) fixStatement(counter sequenceCounter) string {
+	return fmt.Sprintf("ALTER TABLE %s AUTO_INCREMENT = %d", quoteIdentifier("mysql", qualifiedName(counter.Schema, counter.Table)), counter.MaxValue+1)
+}
+
+func (mysqlSequenceFixer) note() string {
+	return "information_schema.TABLES.AUTO_INCREMENT may be cached (information_schema_stats_expiry); setting it to MAX + 1 is harmless when it is already ahead."
+}
+
+// loadSequenceCounters lists the columns fed by a sequence or auto-increment counter with the counter's next value
+func loadSequenceCounters(ctx context.Context, useCase UseCaseProvider, dbID string, fixer sequenceFixer, schema string, tables []string) ([]sequenceCounter, error) {
+	query, params := fixer.countersQuery(schema, tables)
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sequences: %w", err)
+	}
+	var counters []sequenceCounter
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		counters = append(counters, sequenceCounter{
+			Schema:    valueString(row[0]),
+			Table:     valueString(row[1]),
+			Column:    valueString(row[2]),
+			Sequence:  valueString(row[3]),
+			NextValue: valueInt64(row[4]),
+		})
+	}
+	return counters, nil
+}
+
+// sequenceTableList binds the table filter as a placeholder list
+func sequenceTableList(params *sqlParams, tables []string) string {
+	placeholders := make([]string, len(tables))
+	for i, table := range tables {
+		placeholders[i] = params.add(table)
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// loadSequenceColumnMaxima reads MAX of every counter's column in a single query
+func loadSequenceColumnMaxima(ctx context.Context, useCase UseCaseProvider, dbID, dialect string, counters []sequenceCounter) error {
+	if len(counters) == 0 {
+		return nil
+	}
+	parts := make([]string, len(counters))
+	for i, counter := range counters {
+		parts[i] = fmt.Sprintf("SELECT %d, MAX(%s) FROM %s", i, quoteIdentifier(dialect, counter.Column),
+			quoteIdentifier(dialect, qualifiedName(counter.Schema, counter.Table)))
+	}
+	result, err := useCase.ExecuteQuery(ctx, dbID, strings.Join(parts, "\nUNION ALL\n"), nil)
+	if err != nil {
+		return fmt.Errorf("failed to read column maxima: %w", err)
+	}
+	for _, row := range result.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		index := int(valueInt64(row[0]))
+		if index < 0 || index >= len(counters) || row[1] == nil {
+			continue
+		}
+		counters[index].MaxValue, counters[index].HasRows = valueInt64(row[1]), true
+	}
+	return nil
+}
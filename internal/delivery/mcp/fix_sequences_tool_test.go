@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestFixSequencesToolPostgres(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_depend d": {Rows: [][]interface{}{
+				{"public", "orders", "id", "public.orders_id_seq", int64(11)},
+				{"public", "items", "id", "public.items_id_seq", int64(501)},
+				{"public", "notes", "id", "public.notes_id_seq", int64(1)},
+			}},
+			"UNION ALL": {Rows: [][]interface{}{{int64(0), int64(250)}, {int64(1), int64(500)}, {int64(2), nil}}},
+		},
+	}
+
+	result, err := NewFixSequencesTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	assert.Contains(t, useCase.queries[1], `SELECT 0, MAX("id") FROM "public"."orders"`)
+	assert.Equal(t, [][]string{{"SELECT setval('public.orders_id_seq', 250)"}}, useCase.batches)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| public.orders | id | public.orders_id_seq | 11 | 250 | lagging by 240 |")
+	assert.Contains(t, text, "| public.items | id | public.items_id_seq | 501 | 500 | ok |")
+	assert.Contains(t, text, "| public.notes | id | public.notes_id_seq | 1 | - | ok |")
+	assert.Contains(t, text, "Fixed 1 counter(s).")
+}
+
+func TestFixSequencesToolMySQL(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"information_schema.COLUMNS c": {Rows: [][]interface{}{{"shop", "orders", "id", "", int64(3)}}},
+			"SELECT 0, MAX(":               {Rows: [][]interface{}{{int64(0), int64(42)}}},
+		},
+	}
+
+	result, err := NewFixSequencesTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "tables": []interface{}{"orders"}, "dry_run": true},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+	assert.Contains(t, useCase.queries[0], "c.TABLE_NAME IN (?)")
+	assert.Empty(t, useCase.batches)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| shop.orders | id | AUTO_INCREMENT | 3 | 42 | lagging by 40 |")
+	assert.Contains(t, text, "ALTER TABLE `shop`.`orders` AUTO_INCREMENT = 43;")
+	assert.Contains(t, text, "Dry run: nothing was executed.")
+}
@@ -2,11 +2,11 @@ package mcp
 
 import (
 	"context"
-	"fmt"
 	"strings"
 
 	"github.com/FreePeak/cortex/pkg/server"
 	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/domain"
 	"github.com/FreePeak/db-mcp-server/internal/logger"
 )
 
@@ -27,8 +27,7 @@ func NewGenericSQLTool() *GenericSQLTool {
 
 // CreateTool creates a generic SQL tool
 func (t *GenericSQLTool) CreateTool(name string, dbID string) interface{} {
-	return tools.NewTool(
-		name,
+	return tools.NewTool(name, append([]tools.ToolOption{
 		tools.WithDescription("Execute SQL queries or statements on any configured database"),
 		tools.WithString("sql",
 			tools.Description("SQL query or statement to execute"),
@@ -45,44 +44,25 @@ func (t *GenericSQLTool) CreateTool(name string, dbID string) interface{} {
 		tools.WithBoolean("isQuery",
 			tools.Description("Set to true for SELECT queries, false for statements (INSERT, UPDATE, DELETE)"),
 		),
-	)
+	}, resultParameters()...)...)
 }
 
 // HandleRequest handles generic SQL tool requests
 func (t *GenericSQLTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
-	// Extract SQL statement/query
-	sql, ok := request.Parameters["sql"].(string)
-	if !ok {
-		return nil, fmt.Errorf("sql parameter must be a string")
-	}
-
-	// Extract database ID from parameters
-	targetDbID, ok := request.Parameters["database"].(string)
-	if !ok {
-		return nil, fmt.Errorf("database parameter must be a string")
-	}
-
-	// Extract parameters
-	var sqlParams []interface{}
-	if request.Parameters["params"] != nil {
-		if paramsArr, ok := request.Parameters["params"].([]interface{}); ok {
-			sqlParams = paramsArr
-		}
-	}
+	input := newToolParams(request.Parameters)
+	sql := input.requiredString("sql")
+	targetDbID := input.requiredString("database")
+	sqlParams := input.list("params")
 
-	// Determine if this is a query or a statement
-	isQuery := false
-	if request.Parameters["isQuery"] != nil {
-		if isQueryParam, ok := request.Parameters["isQuery"].(bool); ok {
-			isQuery = isQueryParam
-		}
-	} else {
-		// Auto-detect if not specified
-		sqlUpper := strings.TrimSpace(strings.ToUpper(sql))
-		isQuery = strings.HasPrefix(sqlUpper, "SELECT") || 
-			strings.HasPrefix(sqlUpper, "SHOW") || 
-			strings.HasPrefix(sqlUpper, "DESCRIBE") || 
-			strings.HasPrefix(sqlUpper, "EXPLAIN")
+	// Determine if this is a query or a statement, auto-detecting it if not specified
+	sqlUpper := strings.TrimSpace(strings.ToUpper(sql))
+	isQuery := input.optionalBool("isQuery", strings.HasPrefix(sqlUpper, "SELECT") ||
+		strings.HasPrefix(sqlUpper, "SHOW") ||
+		strings.HasPrefix(sqlUpper, "DESCRIBE") ||
+		strings.HasPrefix(sqlUpper, "EXPLAIN"))
+	options := readResultOptions(input)
+	if err := input.err(); err != nil {
+		return nil, err
 	}
 
 	logger.Info("Executing SQL on database %s (isQuery: %v): %s", targetDbID, isQuery, sql)
@@ -92,7 +72,11 @@ func (t *GenericSQLTool) HandleRequest(ctx context.Context, request server.ToolC
 
 	if isQuery {
 		// Execute as a query (SELECT)
-		result, err = useCase.ExecuteQuery(ctx, targetDbID, sql, sqlParams)
+		var rows *domain.QueryResult
+		rows, err = useCase.ExecuteQuery(ctx, targetDbID, sql, sqlParams)
+		if err == nil {
+			result = encodeQueryResult(rows, options)
+		}
 	} else {
 		// Execute as a statement (INSERT, UPDATE, DELETE)
 		result, err = useCase.ExecuteStatement(ctx, targetDbID, sql, sqlParams)
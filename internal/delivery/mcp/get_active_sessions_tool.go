@@ -0,0 +1,231 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// idleInTransactionWarnSeconds is how long a session may sit idle in an open transaction
+// before it is called out
+const idleInTransactionWarnSeconds = 60
+
+// GetActiveSessionsTool handles listing the sessions connected to a database
+type GetActiveSessionsTool struct {
+	BaseToolType
+}
+
+// NewGetActiveSessionsTool creates a new get active sessions tool type
+func NewGetActiveSessionsTool() *GetActiveSessionsTool {
+	return &GetActiveSessionsTool{
+		BaseToolType: BaseToolType{
+			name:        "get_active_sessions",
+			description: "List the sessions connected to a database with what they are doing: user, client address, state, what they wait on, how long the current query or state has lasted and the query text, longest running first. Reads pg_stat_activity on PostgreSQL and the process list on MySQL and TiDB. Idle sessions are left out unless requested. Queries running past a threshold and sessions idle in an open transaction are called out with the statement that cancels or ends them. Use this tool after db_stats shows many active connections to see which sessions they are.",
+		},
+	}
+}
+
+// CreateTool creates a get active sessions tool
+func (t *GetActiveSessionsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("List active sessions and their queries, longest running first"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithBoolean("include_idle",
+			tools.Description("Whether to also list idle sessions (default: false)"),
+		),
+		tools.WithNumber("min_duration",
+			tools.Description("Only list sessions whose current query or state has lasted at least this many seconds (default: 0)"),
+		),
+		tools.WithNumber("warn_seconds",
+			tools.Description("Running time in seconds after which a query is called out as long running (default: 300)"),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of sessions to list (default: 50)"),
+		),
+	)
+}
+
+// HandleRequest handles get active sessions tool requests
+func (t *GetActiveSessionsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	includeIdle := input.optionalBool("include_idle", false)
+	minDuration := input.intAtLeast("min_duration", 0, 0)
+	warnSeconds := input.intAtLeast("warn_seconds", 300, 1)
+	limit := input.intBetween("limit", 50, 1, 1000)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Getting active sessions for database %s, include_idle %v", targetDbID, includeIdle)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for active sessions: %s", dbType)
+	}
+	query, ok := dialect.ActiveSessionsQuery(includeIdle)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for active sessions: %s", dbType)
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, targetDbID, query, []interface{}{minDuration, limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active sessions: %w", err)
+	}
+	sessions := readActiveSessions(result.Rows)
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Active Sessions in Database %s\n\n", targetDbID))
+	writeActiveSessions(&response, sessions, limit)
+
+	if warnings := activeSessionWarnings(dialect, sessions, warnSeconds); len(warnings) > 0 {
+		response.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			response.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// activeSession is a session connected to the database
+type activeSession struct {
+	ID          int64
+	User        string
+	Client      string
+	Application string // application name on PostgreSQL, default database on MySQL
+	State       string
+	WaitEvent   string
+	Seconds     int64 // how long the current query has run, or the current state has lasted
+	XactSeconds int64 // age of the open transaction; 0 when none is open
+	Query       string
+}
+
+// getPostgresActiveSessionsQuery returns a query listing the client sessions of the database
+// other than this one. For an active session the duration is that of its query; otherwise it
+// is how long the session has been in its state.
+func getPostgresActiveSessionsQuery(includeIdle bool) string {
+	idleFilter := "\n  AND state <> 'idle'"
+	if includeIdle {
+		idleFilter = ""
+	}
+	return `
+SELECT
+    pid,
+    usename,
+    COALESCE(host(client_addr), 'local') AS client,
+    application_name,
+    state,
+    CASE WHEN wait_event IS NOT NULL THEN wait_event_type || ': ' || wait_event END AS wait_event,
+    COALESCE(EXTRACT(EPOCH FROM now() - CASE WHEN state = 'active' THEN query_start ELSE state_change END)::bigint, 0) AS duration_seconds,
+    COALESCE(EXTRACT(EPOCH FROM now() - xact_start)::bigint, 0) AS xact_seconds,
+    left(query, 1000) AS query
+FROM pg_stat_activity
+WHERE datname = current_database()
+  AND backend_type = 'client backend'
+  AND pid <> pg_backend_pid()` + idleFilter + `
+  AND COALESCE(EXTRACT(EPOCH FROM now() - CASE WHEN state = 'active' THEN query_start ELSE state_change END), 0) >= $1
+ORDER BY state = 'active' DESC, duration_seconds DESC
+LIMIT $2;`
+}
+
+// getMySQLActiveSessionsQuery returns a query listing the client connections other than this
+// one. MySQL reports what a thread waits on as its state; the transaction age comes from
+// information_schema.INNODB_TRX.
+func getMySQLActiveSessionsQuery(includeIdle bool) string {
+	idleFilter := "\n  AND p.COMMAND <> 'Sleep'"
+	if includeIdle {
+		idleFilter = ""
+	}
+	return `
+SELECT
+    p.ID,
+    p.USER,
+    SUBSTRING_INDEX(p.HOST, ':', 1) AS client,
+    p.DB,
+    p.COMMAND,
+    NULLIF(p.STATE, '') AS wait_event,
+    p.TIME AS duration_seconds,
+    COALESCE(TIMESTAMPDIFF(SECOND, x.trx_started, NOW()), 0) AS xact_seconds,
+    LEFT(p.INFO, 1000) AS query
+FROM information_schema.PROCESSLIST p
+LEFT JOIN information_schema.INNODB_TRX x ON x.trx_mysql_thread_id = p.ID
+WHERE p.ID <> CONNECTION_ID()
+  AND p.COMMAND NOT IN ('Daemon', 'Binlog Dump', 'Binlog Dump GTID')` + idleFilter + `
+  AND p.TIME >= ?
+ORDER BY p.COMMAND = 'Sleep', p.TIME DESC
+LIMIT ?;`
+}
+
+// readActiveSessions reads the rows of an active sessions query
+func readActiveSessions(rows [][]interface{}) []activeSession {
+	sessions := make([]activeSession, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 9 {
+			continue
+		}
+		sessions = append(sessions, activeSession{
+			ID:          valueInt64(row[0]),
+			User:        valueString(row[1]),
+			Client:      valueString(row[2]),
+			Application: valueString(row[3]),
+			State:       valueString(row[4]),
+			WaitEvent:   valueString(row[5]),
+			Seconds:     valueInt64(row[6]),
+			XactSeconds: valueInt64(row[7]),
+			Query:       valueString(row[8]),
+		})
+	}
+	return sessions
+}
+
+// writeActiveSessions renders sessions as a table
+func writeActiveSessions(sb *strings.Builder, sessions []activeSession, limit int) {
+	if len(sessions) == 0 {
+		sb.WriteString("No other session matches.\n")
+		return
+	}
+	sb.WriteString("| Session | User | Client | Application / database | State | Waiting on | Duration | Transaction age | Query |\n")
+	sb.WriteString("|---------|------|--------|------------------------|-------|------------|----------|-----------------|-------|\n")
+	for _, s := range sessions {
+		xact := "-"
+		if s.XactSeconds > 0 {
+			xact = fmt.Sprintf("%ds", s.XactSeconds)
+		}
+		sb.WriteString(fmt.Sprintf("| %d | %s | %s | %s | %s | %s | %ds | %s | %s |\n",
+			s.ID, s.User, orDash(s.Client), orDash(s.Application), s.State, orDash(s.WaitEvent), s.Seconds, xact, queryCell(s.Query)))
+	}
+	if len(sessions) == limit {
+		sb.WriteString(fmt.Sprintf("\nShowing the first %d sessions; raise limit to see more.\n", limit))
+	}
+}
+
+// activeSessionWarnings calls out long running queries and sessions holding a transaction open
+// while doing nothing, with the statement that cancels or ends them
+func activeSessionWarnings(dialect Dialect, sessions []activeSession, warnSeconds int) []string {
+	var warnings []string
+	for _, s := range sessions {
+		running := dialect.SessionRunning(s.State)
+		switch {
+		case running && s.Seconds >= int64(warnSeconds):
+			cancel, _ := dialect.KillSessionStatement(s.ID, false)
+			warnings = append(warnings, fmt.Sprintf("Session %d (%s) has run its query for %ds; cancel it with %s.", s.ID, s.User, s.Seconds, cancel))
+		case !running && s.XactSeconds >= idleInTransactionWarnSeconds:
+			kill, _ := dialect.KillSessionStatement(s.ID, true)
+			warnings = append(warnings, fmt.Sprintf("Session %d (%s) is idle in a transaction opened %ds ago, holding its locks and keeping old row versions from being cleaned up; end it with %s.", s.ID, s.User, s.XactSeconds, kill))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func getActiveSessionsText(t *testing.T, useCase *mockUseCase, params map[string]interface{}) string {
+	result, err := NewGetActiveSessionsTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	assert.NoError(t, err)
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+}
+
+func TestGetActiveSessionsListsPostgresSessions(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{"FROM pg_stat_activity": {Rows: [][]interface{}{
+			{int64(311), "report", "10.0.0.5", "metabase", "active", "IO: DataFileRead", int64(900), int64(900), "SELECT count(*)\nFROM events"},
+			{int64(312), "app", "local", "psql", "idle in transaction", "Client: ClientRead", int64(75), int64(80), "UPDATE accounts SET balance = 0"},
+			{int64(313), "app", "10.0.0.6", "", "active", nil, int64(2), int64(2), "SELECT 1"},
+		}}},
+	}
+	text := getActiveSessionsText(t, useCase, map[string]interface{}{"database": "pg1", "min_duration": float64(1)})
+
+	assert.Contains(t, useCase.queries[0], "AND state <> 'idle'")
+	assert.Contains(t, text, "| 311 | report | 10.0.0.5 | metabase | active | IO: DataFileRead | 900s | 900s | SELECT count(*) FROM events |")
+	assert.Contains(t, text, "| 313 | app | 10.0.0.6 | - | active | - | 2s | 2s | SELECT 1 |")
+	assert.Contains(t, text, "- Session 311 (report) has run its query for 900s; cancel it with SELECT pg_cancel_backend(311).\n")
+	assert.Contains(t, text, "- Session 312 (app) is idle in a transaction opened 80s ago")
+	assert.Contains(t, text, "end it with SELECT pg_terminate_backend(312).\n")
+	assert.NotContains(t, text, "Session 313")
+
+	useCase = &mockUseCase{dbType: "postgres"}
+	text = getActiveSessionsText(t, useCase, map[string]interface{}{"database": "pg1", "include_idle": true})
+	assert.NotContains(t, useCase.queries[0], "state <> 'idle'")
+	assert.Contains(t, text, "No other session matches.\n")
+}
+
+func TestGetActiveSessionsListsMySQLProcesses(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "tidb",
+		results: map[string]*domain.QueryResult{"information_schema.PROCESSLIST": {Rows: [][]interface{}{
+			{int64(41), "etl", "10.1.2.3", "shop", "Query", "Sending data", int64(400), int64(0), "INSERT INTO archive SELECT * FROM orders"},
+			{int64(42), "app", "10.1.2.4", "shop", "Sleep", nil, int64(90), int64(95), nil},
+		}}},
+	}
+	text := getActiveSessionsText(t, useCase, map[string]interface{}{"database": "tidb1", "include_idle": true, "warn_seconds": float64(300)})
+
+	assert.NotContains(t, useCase.queries[0], "p.COMMAND <> 'Sleep'")
+	assert.Contains(t, text, "| 41 | etl | 10.1.2.3 | shop | Query | Sending data | 400s | - | INSERT INTO archive SELECT * FROM orders |")
+	assert.Contains(t, text, "- Session 41 (etl) has run its query for 400s; cancel it with KILL QUERY 41.\n")
+	assert.Contains(t, text, "end it with KILL 42.\n")
+
+	_, err := NewGetActiveSessionsTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "db1"}}, "", &mockUseCase{dbType: "sqlite"})
+	assert.ErrorContains(t, err, "unsupported database type for active sessions: sqlite")
+}
@@ -0,0 +1,288 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// GetCharsetsCollationsTool handles reporting character sets and collations
+type GetCharsetsCollationsTool struct {
+	BaseToolType
+}
+
+// NewGetCharsetsCollationsTool creates a new get charsets and collations tool type
+func NewGetCharsetsCollationsTool() *GetCharsetsCollationsTool {
+	return &GetCharsetsCollationsTool{
+		BaseToolType: BaseToolType{
+			name:        "get_charsets_collations",
+			description: "Report the character sets and collations of a schema: the database defaults, the tables and columns that depart from them, and indexed columns of the same name whose collations differ between tables. Joining such columns forces a conversion that keeps the index on the converted side from being used, or fails outright, so each one is called out. On MySQL the schema, table and column character sets and collations are read from information_schema; on PostgreSQL the database encoding, LC_COLLATE and LC_CTYPE and the columns with an explicit collation are read from the catalog.",
+		},
+	}
+}
+
+// CreateTool creates a get charsets and collations tool
+func (t *GetCharsetsCollationsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Report character sets and collations and flag join columns whose collations differ"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to inspect (optional, defaults to the current schema on PostgreSQL and the current database on MySQL)"),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of rows to list per section (default: 100)"),
+		),
+	)
+}
+
+// HandleRequest handles get charsets and collations tool requests
+func (t *GetCharsetsCollationsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema := input.optionalString("schema", "")
+	limit := input.intBetween("limit", 100, 1, 10000)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Getting character sets and collations for database %s, schema %s", targetDbID, schema)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for character sets and collations: %s", dbType)
+	}
+	sections, ok := dialect.CollationSections(schema, limit)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for character sets and collations: %s", dbType)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Character Sets and Collations in Database %s\n", targetDbID))
+	if err := writeReportSections(ctx, useCase, targetDbID, sections, &response); err != nil {
+		return nil, err
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// isUTF8MB3 reports whether a MySQL character set or collation is the 3-byte utf8, which
+// cannot store characters outside the Basic Multilingual Plane such as emoji
+func isUTF8MB3(name string) bool {
+	name = strings.ToLower(name)
+	return name == "utf8" || name == "utf8mb3" || strings.HasPrefix(name, "utf8_") || strings.HasPrefix(name, "utf8mb3_")
+}
+
+// postgresCollationSections returns the encoding and locale of the database, the columns of
+// a schema with an explicit collation and the same-named indexed columns whose collations differ
+func postgresCollationSections(schema string, limit int) []reportSection {
+	params := []interface{}{schema, limit}
+	return []reportSection{
+		{
+			title: "Database",
+			query: `
+SELECT
+    d.datname AS database_name,
+    pg_encoding_to_char(d.encoding) AS encoding,
+    d.datcollate AS lc_collate,
+    d.datctype AS lc_ctype,
+    current_setting('client_encoding') AS client_encoding
+FROM pg_database d
+WHERE d.datname = current_database()`,
+			warn: func(row []interface{}) []string {
+				if len(row) < 5 {
+					return nil
+				}
+				var warnings []string
+				encoding, collate, client := valueString(row[1]), valueString(row[2]), valueString(row[4])
+				if encoding == "SQL_ASCII" {
+					warnings = append(warnings, "The database encoding is SQL_ASCII, which stores bytes without validating them, so text in different encodings can mix and cannot be converted reliably.")
+				}
+				switch collate {
+				case "C", "POSIX", "C.UTF-8", "C.utf8":
+				default:
+					warnings = append(warnings, fmt.Sprintf("LC_COLLATE is %s, so b-tree indexes on text columns only serve LIKE 'prefix%%' when they are created with text_pattern_ops or COLLATE \"C\".", collate))
+				}
+				if client != "" && !strings.EqualFold(client, encoding) {
+					warnings = append(warnings, fmt.Sprintf("This connection uses client_encoding %s, so every text value is converted from and to %s.", client, encoding))
+				}
+				return warnings
+			},
+		},
+		{
+			title: "Columns With an Explicit Collation",
+			query: `
+SELECT
+    c.relname AS table_name,
+    a.attname AS column_name,
+    format_type(a.atttypid, a.atttypmod) AS data_type,
+    co.collname AS collation
+FROM pg_attribute a
+JOIN pg_class c ON c.oid = a.attrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_collation co ON co.oid = a.attcollation
+WHERE c.relkind IN ('r', 'p')
+  AND NOT c.relispartition
+  AND a.attnum > 0
+  AND NOT a.attisdropped
+  AND co.collname <> 'default'
+  AND n.nspname = COALESCE(NULLIF($1, ''), current_schema())
+ORDER BY c.relname, a.attnum
+LIMIT $2`,
+			params: params,
+		},
+		{
+			title: "Indexed Columns With Differing Collations",
+			query: `
+SELECT
+    a1.attname AS column_name,
+    c1.relname AS table_name,
+    co1.collname AS collation,
+    c2.relname AS other_table,
+    co2.collname AS other_collation
+FROM pg_attribute a1
+JOIN pg_class c1 ON c1.oid = a1.attrelid
+JOIN pg_namespace n ON n.oid = c1.relnamespace
+JOIN pg_class c2 ON c2.relnamespace = c1.relnamespace AND c2.relname > c1.relname
+JOIN pg_attribute a2 ON a2.attrelid = c2.oid AND a2.attname = a1.attname
+JOIN pg_collation co1 ON co1.oid = a1.attcollation
+JOIN pg_collation co2 ON co2.oid = a2.attcollation
+WHERE c1.relkind IN ('r', 'p') AND NOT c1.relispartition
+  AND c2.relkind IN ('r', 'p') AND NOT c2.relispartition
+  AND a1.attnum > 0 AND NOT a1.attisdropped
+  AND a2.attnum > 0 AND NOT a2.attisdropped
+  AND a1.attcollation <> a2.attcollation
+  AND n.nspname = COALESCE(NULLIF($1, ''), current_schema())
+  AND (EXISTS (SELECT 1 FROM pg_index i WHERE i.indrelid = c1.oid AND i.indkey[0] = a1.attnum)
+       OR EXISTS (SELECT 1 FROM pg_index i WHERE i.indrelid = c2.oid AND i.indkey[0] = a2.attnum))
+ORDER BY 1, 2, 4
+LIMIT $2`,
+			params: params,
+			warn: func(row []interface{}) []string {
+				if len(row) < 5 {
+					return nil
+				}
+				column := valueString(row[0])
+				return []string{fmt.Sprintf("Comparing %[2]s.%[1]s (%[3]s) with %[4]s.%[1]s (%[5]s) fails with \"could not determine which collation to use\" unless one side gets a COLLATE clause, and that side's index cannot be used.",
+					column, valueString(row[1]), valueString(row[2]), valueString(row[3]), valueString(row[4]))}
+			},
+		},
+	}
+}
+
+// mysqlCollationSections returns the defaults of a schema, the tables and columns departing
+// from them and the same-named indexed columns whose collations differ
+func mysqlCollationSections(schema string, limit int) []reportSection {
+	params := []interface{}{schema, limit}
+	return []reportSection{
+		{
+			title: "Schema",
+			query: `
+SELECT
+    s.SCHEMA_NAME AS schema_name,
+    s.DEFAULT_CHARACTER_SET_NAME AS charset,
+    s.DEFAULT_COLLATION_NAME AS collation,
+    @@character_set_server AS server_charset,
+    @@collation_server AS server_collation,
+    @@collation_connection AS connection_collation
+FROM information_schema.SCHEMATA s
+WHERE s.SCHEMA_NAME = COALESCE(NULLIF(?, ''), DATABASE())`,
+			params: []interface{}{schema},
+			warn: func(row []interface{}) []string {
+				if len(row) < 3 || !isUTF8MB3(valueString(row[1])) {
+					return nil
+				}
+				return []string{fmt.Sprintf("Schema %s defaults to utf8mb3, which cannot store 4-byte characters such as emoji; new tables should use utf8mb4.", valueString(row[0]))}
+			},
+		},
+		{
+			title: "Tables Not Using the Schema Collation",
+			query: `
+SELECT
+    t.TABLE_NAME AS table_name,
+    SUBSTRING_INDEX(t.TABLE_COLLATION, '_', 1) AS charset,
+    t.TABLE_COLLATION AS collation,
+    s.DEFAULT_COLLATION_NAME AS schema_collation
+FROM information_schema.TABLES t
+JOIN information_schema.SCHEMATA s ON s.SCHEMA_NAME = t.TABLE_SCHEMA
+WHERE t.TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+  AND t.TABLE_TYPE = 'BASE TABLE'
+  AND t.TABLE_COLLATION <> s.DEFAULT_COLLATION_NAME
+ORDER BY t.TABLE_NAME
+LIMIT ?`,
+			params: params,
+			warn: func(row []interface{}) []string {
+				if len(row) < 3 || !isUTF8MB3(valueString(row[2])) {
+					return nil
+				}
+				return []string{fmt.Sprintf("Table %s uses utf8mb3, which cannot store 4-byte characters such as emoji.", valueString(row[0]))}
+			},
+		},
+		{
+			title: "Columns Not Using the Table Collation",
+			query: `
+SELECT
+    c.TABLE_NAME AS table_name,
+    c.COLUMN_NAME AS column_name,
+    c.COLUMN_TYPE AS data_type,
+    c.CHARACTER_SET_NAME AS charset,
+    c.COLLATION_NAME AS collation,
+    t.TABLE_COLLATION AS table_collation
+FROM information_schema.COLUMNS c
+JOIN information_schema.TABLES t ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+WHERE c.TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+  AND t.TABLE_TYPE = 'BASE TABLE'
+  AND c.COLLATION_NAME IS NOT NULL
+  AND c.COLLATION_NAME <> t.TABLE_COLLATION
+ORDER BY c.TABLE_NAME, c.ORDINAL_POSITION
+LIMIT ?`,
+			params: params,
+		},
+		{
+			title: "Indexed Columns With Differing Collations",
+			query: `
+SELECT
+    c1.COLUMN_NAME AS column_name,
+    c1.TABLE_NAME AS table_name,
+    c1.COLLATION_NAME AS collation,
+    c2.TABLE_NAME AS other_table,
+    c2.COLLATION_NAME AS other_collation
+FROM information_schema.COLUMNS c1
+JOIN information_schema.COLUMNS c2
+  ON c2.TABLE_SCHEMA = c1.TABLE_SCHEMA AND c2.COLUMN_NAME = c1.COLUMN_NAME AND c2.TABLE_NAME > c1.TABLE_NAME
+WHERE c1.TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+  AND c1.COLLATION_NAME IS NOT NULL
+  AND c2.COLLATION_NAME IS NOT NULL
+  AND c1.COLLATION_NAME <> c2.COLLATION_NAME
+  AND EXISTS (
+      SELECT 1 FROM information_schema.STATISTICS st
+      WHERE st.TABLE_SCHEMA = c1.TABLE_SCHEMA
+        AND st.TABLE_NAME IN (c1.TABLE_NAME, c2.TABLE_NAME)
+        AND st.COLUMN_NAME = c1.COLUMN_NAME
+        AND st.SEQ_IN_INDEX = 1
+  )
+ORDER BY 1, 2, 4
+LIMIT ?`,
+			params: params,
+			warn: func(row []interface{}) []string {
+				if len(row) < 5 {
+					return nil
+				}
+				return []string{fmt.Sprintf("Joining %[2]s.%[1]s (%[3]s) with %[4]s.%[1]s (%[5]s) either fails with an illegal mix of collations or converts one side, and an index on the converted side cannot be used.",
+					valueString(row[0]), valueString(row[1]), valueString(row[2]), valueString(row[3]), valueString(row[4]))}
+			},
+		},
+	}
+}
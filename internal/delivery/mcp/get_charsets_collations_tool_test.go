@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func getCharsetsCollations(useCase *mockUseCase, params map[string]interface{}) (string, error) {
+	result, err := NewGetCharsetsCollationsTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	if err != nil {
+		return "", err
+	}
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string), nil
+}
+
+func TestGetCharsetsCollationsMySQL(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"FROM information_schema.SCHEMATA s\nWHERE": {
+				Columns: []string{"schema_name", "charset", "collation", "server_charset", "server_collation", "connection_collation"},
+				Rows:    [][]interface{}{{"shop", "utf8mb3", "utf8mb3_general_ci", "utf8mb4", "utf8mb4_0900_ai_ci", "utf8mb4_0900_ai_ci"}},
+			},
+			"c2.TABLE_NAME AS other_table": {
+				Columns: []string{"column_name", "table_name", "collation", "other_table", "other_collation"},
+				Rows:    [][]interface{}{{"customer_id", "customers", "utf8mb4_0900_ai_ci", "orders", "utf8mb3_general_ci"}},
+			},
+		},
+	}
+	text, err := getCharsetsCollations(useCase, map[string]interface{}{"database": "db1", "schema": "shop", "limit": 10})
+	assert.NoError(t, err)
+
+	assert.Len(t, useCase.queries, 4)
+	assert.Contains(t, text, "\n## Tables Not Using the Schema Collation\n\nNone.\n")
+	assert.Contains(t, text, "- Schema shop defaults to utf8mb3, which cannot store 4-byte characters such as emoji")
+	assert.Contains(t, text, "- Joining customers.customer_id (utf8mb4_0900_ai_ci) with orders.customer_id (utf8mb3_general_ci) either fails with an illegal mix of collations")
+
+	_, err = getCharsetsCollations(&mockUseCase{dbType: "sqlite"}, map[string]interface{}{"database": "lite"})
+	assert.ErrorContains(t, err, "unsupported database type for character sets and collations: sqlite")
+}
+
+func TestGetCharsetsCollationsPostgres(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_database d": {
+				Columns: []string{"database_name", "encoding", "lc_collate", "lc_ctype", "client_encoding"},
+				Rows:    [][]interface{}{{"app", "UTF8", "en_US.UTF-8", "en_US.UTF-8", "LATIN1"}},
+			},
+		},
+	}
+	text, err := getCharsetsCollations(useCase, map[string]interface{}{"database": "pg1"})
+	assert.NoError(t, err)
+
+	assert.Len(t, useCase.queries, 3)
+	assert.Contains(t, text, "- LC_COLLATE is en_US.UTF-8, so b-tree indexes on text columns only serve LIKE 'prefix%' when")
+	assert.Contains(t, text, "- This connection uses client_encoding LATIN1, so every text value is converted from and to UTF8.")
+	assert.Contains(t, text, "\n## Indexed Columns With Differing Collations\n\nNone.\n")
+}
+
+func TestIsUTF8MB3(t *testing.T) {
+	assert.True(t, isUTF8MB3("utf8"))
+	assert.True(t, isUTF8MB3("utf8mb3_unicode_ci"))
+	assert.True(t, isUTF8MB3("utf8_general_ci"))
+	assert.False(t, isUTF8MB3("utf8mb4_0900_ai_ci"))
+	assert.False(t, isUTF8MB3("latin1_swedish_ci"))
+}
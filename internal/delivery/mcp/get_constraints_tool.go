@@ -38,33 +38,19 @@ func (t *GetConstraintsTool) CreateTool(name string, dbID string) interface{} {
 			tools.Description("Table name to get constraints for (optional, leave empty for all tables)"),
 		),
 		tools.WithString("constraint_type",
-			tools.Description("Type of constraint to retrieve (optional: PRIMARY KEY, FOREIGN KEY, UNIQUE, CHECK, EXCLUSION)"),
+			tools.Description("Type of constraint to retrieve (optional: PRIMARY KEY, FOREIGN KEY, UNIQUE, CHECK, EXCLUSION, or INTERLEAVE on Spanner)"),
 		),
 	)
 }
 
 // HandleRequest handles get constraints tool requests
 func (t *GetConstraintsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
-	// Extract database ID from parameters
-	targetDbID, ok := request.Parameters["database"].(string)
-	if !ok {
-		return nil, fmt.Errorf("database parameter must be a string")
-	}
-
-	// Extract table name (optional)
-	tableName := ""
-	if request.Parameters["table"] != nil {
-		if tableParam, ok := request.Parameters["table"].(string); ok {
-			tableName = tableParam
-		}
-	}
-
-	// Extract constraint type (optional)
-	constraintType := ""
-	if request.Parameters["constraint_type"] != nil {
-		if typeParam, ok := request.Parameters["constraint_type"].(string); ok {
-			constraintType = typeParam
-		}
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	tableName := input.optionalString("table", "")
+	constraintType := input.optionalString("constraint_type", "")
+	if err := input.err(); err != nil {
+		return nil, err
 	}
 
 	logger.Info("Getting constraints for database %s, table %s, type %s", targetDbID, tableName, constraintType)
@@ -75,16 +61,12 @@ func (t *GetConstraintsTool) HandleRequest(ctx context.Context, request server.T
 		return nil, fmt.Errorf("failed to get database type: %w", err)
 	}
 
-	// Define query based on database type
-	var query string
-	switch strings.ToLower(dbType) {
-	case "postgres":
-		query = getPostgresConstraintsQuery(tableName, constraintType)
-	case "mysql":
-		query = getMySQLConstraintsQuery(tableName, constraintType)
-	default:
+	// Build query in the dialect of the database
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
 		return nil, fmt.Errorf("unsupported database type for constraints: %s", dbType)
 	}
+	query := dialect.ConstraintQuery(tableName, constraintType)
 
 	// Execute the query
 	result, err := useCase.ExecuteQuery(ctx, targetDbID, query, nil)
@@ -107,7 +89,7 @@ func (t *GetConstraintsTool) HandleRequest(ctx context.Context, request server.T
 			response.WriteString(fmt.Sprintf("# %s Constraints for Table %s in Database %s\n\n", constraintType, tableName, targetDbID))
 		}
 	}
-	response.WriteString(result)
+	response.WriteString(formatQueryResult(result))
 
 	return createTextResponse(response.String()), nil
 }
@@ -229,3 +211,372 @@ ORDER BY tc.table_name, tc.constraint_name;`
 
 	return baseQuery
 }
+
+// getSQLiteConstraintsQuery returns a query for SQLite constraints; SQLite does not name
+// primary and foreign keys, so they are named after their table
+func getSQLiteConstraintsQuery(tableName, constraintType string) string {
+	// Base query for SQLite constraints
+	baseQuery := `
+SELECT * FROM (
+    SELECT 
+        'main' AS table_schema,
+        m.name AS table_name,
+        m.name || '_pkey' AS constraint_name,
+        'PRIMARY KEY' AS constraint_type,
+        (SELECT group_concat(name, ', ') FROM (SELECT name FROM pragma_table_info(m.name) WHERE pk > 0 ORDER BY pk)) AS column_names,
+        NULL AS referenced_table,
+        NULL AS referenced_columns
+    FROM sqlite_master m
+    WHERE m.type = 'table'
+    AND EXISTS (SELECT 1 FROM pragma_table_info(m.name) WHERE pk > 0)
+    UNION ALL
+    SELECT 
+        'main',
+        m.name,
+        il.name,
+        'UNIQUE',
+        (SELECT group_concat(name, ', ') FROM (SELECT name FROM pragma_index_info(il.name) ORDER BY seqno)),
+        NULL,
+        NULL
+    FROM sqlite_master m
+    JOIN pragma_index_list(m.name) il
+    WHERE m.type = 'table'
+    AND il.origin = 'u'
+    UNION ALL
+    SELECT 
+        'main',
+        m.name,
+        m.name || '_fk' || fk.id,
+        'FOREIGN KEY',
+        group_concat(fk."from", ', '),
+        fk."table",
+        group_concat(fk."to", ', ')
+    FROM sqlite_master m
+    JOIN pragma_foreign_key_list(m.name) fk
+    WHERE m.type = 'table'
+    GROUP BY m.name, fk.id, fk."table"
+) AS c
+WHERE c.table_name NOT LIKE 'sqlite_%'`
+
+	if tableName != "" {
+		// Escape table name for safety
+		safeTableName := strings.Replace(tableName, "'", "''", -1)
+		baseQuery += fmt.Sprintf(" AND c.table_name = '%s'", safeTableName)
+	}
+
+	if constraintType != "" {
+		// Escape constraint type for safety
+		safeConstraintType := strings.Replace(strings.ToUpper(constraintType), "'", "''", -1)
+		baseQuery += fmt.Sprintf(" AND c.constraint_type = '%s'", safeConstraintType)
+	}
+
+	baseQuery += `
+ORDER BY c.table_name, c.constraint_name;`
+
+	return baseQuery
+}
+
+// getClickHouseConstraintsQuery returns a query for ClickHouse constraints. ClickHouse has no
+// unique or foreign keys and its primary key does not enforce uniqueness; CHECK constraints
+// are only kept in the table definitions, so the primary keys are all there is to list.
+func getClickHouseConstraintsQuery(tableName, constraintType string) string {
+	// Base query for ClickHouse constraints
+	baseQuery := `
+SELECT 
+    database AS table_schema,
+    name AS table_name,
+    'PRIMARY' AS constraint_name,
+    'PRIMARY KEY' AS constraint_type,
+    primary_key AS column_names
+FROM system.tables
+WHERE database = currentDatabase()
+AND primary_key != ''`
+
+	if tableName != "" {
+		baseQuery += fmt.Sprintf(" AND name = %s", quoteLiteral("clickhouse", tableName))
+	}
+
+	if constraintType != "" {
+		baseQuery += fmt.Sprintf(" AND 'PRIMARY KEY' = %s", quoteLiteral("clickhouse", strings.ToUpper(constraintType)))
+	}
+
+	baseQuery += `
+ORDER BY name;`
+
+	return baseQuery
+}
+
+// getBigQueryConstraintsQuery returns a query for BigQuery constraints; its primary and
+// foreign keys are not enforced, only used by the optimizer
+func getBigQueryConstraintsQuery(tableName, constraintType string) string {
+	// Base query for BigQuery constraints
+	baseQuery := `
+SELECT 
+    tc.table_schema,
+    tc.table_name,
+    tc.constraint_name,
+    tc.constraint_type,
+    STRING_AGG(kcu.column_name, ', ' ORDER BY kcu.ordinal_position) AS column_names,
+    (SELECT ANY_VALUE(ccu.table_name)
+     FROM INFORMATION_SCHEMA.CONSTRAINT_COLUMN_USAGE ccu
+     WHERE tc.constraint_type = 'FOREIGN KEY'
+     AND ccu.constraint_name = tc.constraint_name) AS referenced_table,
+    (SELECT STRING_AGG(ccu.column_name, ', ')
+     FROM INFORMATION_SCHEMA.CONSTRAINT_COLUMN_USAGE ccu
+     WHERE tc.constraint_type = 'FOREIGN KEY'
+     AND ccu.constraint_name = tc.constraint_name) AS referenced_columns,
+    tc.enforced
+FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+    ON kcu.constraint_name = tc.constraint_name
+    AND kcu.table_name = tc.table_name
+WHERE 1 = 1`
+
+	if tableName != "" {
+		baseQuery += fmt.Sprintf(" AND tc.table_name = %s", quoteLiteral("bigquery", tableName))
+	}
+
+	if constraintType != "" {
+		baseQuery += fmt.Sprintf(" AND tc.constraint_type = %s", quoteLiteral("bigquery", strings.ToUpper(constraintType)))
+	}
+
+	baseQuery += `
+GROUP BY tc.table_schema, tc.table_name, tc.constraint_name, tc.constraint_type, tc.enforced
+ORDER BY tc.table_name, tc.constraint_name;`
+
+	return baseQuery
+}
+
+// getSpannerConstraintsQuery returns a query for Spanner constraints. Interleaving is listed
+// as an INTERLEAVE constraint of the child table on the primary key of its parent, and the
+// CHECK constraints Spanner keeps for NOT NULL columns are left out.
+func getSpannerConstraintsQuery(tableName, constraintType string) string {
+	// Base query for Spanner constraints; a foreign key references the columns of the
+	// primary key or unique index named by its referential constraint
+	baseQuery := `
+SELECT * FROM (
+    SELECT 
+        tc.table_schema,
+        tc.table_name,
+        tc.constraint_name,
+        tc.constraint_type,
+        (SELECT STRING_AGG(kcu.column_name, ', ' ORDER BY kcu.ordinal_position)
+         FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+         WHERE kcu.constraint_schema = tc.constraint_schema
+         AND kcu.constraint_name = tc.constraint_name) AS column_names,
+        (SELECT ANY_VALUE(rk.table_name)
+         FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE rk
+         WHERE rk.constraint_schema = rc.unique_constraint_schema
+         AND rk.constraint_name = rc.unique_constraint_name) AS referenced_table,
+        (SELECT STRING_AGG(rk.column_name, ', ' ORDER BY rk.ordinal_position)
+         FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE rk
+         WHERE rk.constraint_schema = rc.unique_constraint_schema
+         AND rk.constraint_name = rc.unique_constraint_name) AS referenced_columns,
+        rc.delete_rule AS on_delete,
+        cc.check_clause,
+        tc.enforced
+    FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+    LEFT JOIN INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS rc
+        ON rc.constraint_schema = tc.constraint_schema
+        AND rc.constraint_name = tc.constraint_name
+    LEFT JOIN INFORMATION_SCHEMA.CHECK_CONSTRAINTS cc
+        ON cc.constraint_schema = tc.constraint_schema
+        AND cc.constraint_name = tc.constraint_name
+    WHERE tc.table_schema NOT IN ('INFORMATION_SCHEMA', 'SPANNER_SYS')
+    AND NOT STARTS_WITH(tc.constraint_name, 'CK_IS_NOT_NULL_')
+    UNION ALL
+    SELECT 
+        t.table_schema,
+        t.table_name,
+        CONCAT('INTERLEAVE IN ', t.parent_table_name),
+        'INTERLEAVE',
+        pk.column_names,
+        t.parent_table_name,
+        pk.column_names,
+        t.on_delete_action,
+        CAST(NULL AS STRING),
+        'YES'
+    FROM INFORMATION_SCHEMA.TABLES t
+    JOIN (
+        SELECT table_schema, table_name, STRING_AGG(column_name, ', ' ORDER BY ordinal_position) AS column_names
+        FROM INFORMATION_SCHEMA.INDEX_COLUMNS
+        WHERE index_type = 'PRIMARY_KEY'
+        GROUP BY table_schema, table_name
+    ) pk
+        ON pk.table_schema = t.table_schema
+        AND pk.table_name = t.parent_table_name
+    WHERE t.parent_table_name IS NOT NULL
+) AS c
+WHERE 1 = 1`
+
+	if tableName != "" {
+		baseQuery += " AND " + spannerTableFilter("c", tableName)
+	}
+
+	if constraintType != "" {
+		baseQuery += fmt.Sprintf(" AND c.constraint_type = %s", quoteLiteral("spanner", strings.ToUpper(constraintType)))
+	}
+
+	baseQuery += `
+ORDER BY c.table_schema, c.table_name, c.constraint_name;`
+
+	return baseQuery
+}
+
+// getHANAConstraintsQuery returns a query for SAP HANA constraints; primary keys, unique keys
+// and checks come from SYS.CONSTRAINTS and foreign keys from SYS.REFERENTIAL_CONSTRAINTS,
+// both with one row per column
+func getHANAConstraintsQuery(tableName, constraintType string) string {
+	// The table is matched inside both halves, where the catalog columns are still in scope
+	keyFilter := hanaUserSchemaFilter("k")
+	foreignKeyFilter := hanaUserSchemaFilter("r")
+	if tableName != "" {
+		keyFilter += " AND " + hanaTableFilter("k", tableName)
+		foreignKeyFilter += " AND " + hanaTableFilter("r", tableName)
+	}
+
+	baseQuery := `
+SELECT * FROM (
+    SELECT 
+        k.SCHEMA_NAME AS table_schema,
+        k.TABLE_NAME AS table_name,
+        k.CONSTRAINT_NAME AS constraint_name,
+        CASE
+            WHEN k.IS_PRIMARY_KEY = 'TRUE' THEN 'PRIMARY KEY'
+            WHEN k.IS_UNIQUE_KEY = 'TRUE' THEN 'UNIQUE'
+            ELSE 'CHECK'
+        END AS constraint_type,
+        STRING_AGG(k.COLUMN_NAME, ', ' ORDER BY k.POSITION) AS column_names,
+        CAST(NULL AS NVARCHAR(256)) AS referenced_table,
+        CAST(NULL AS NVARCHAR(5000)) AS referenced_columns,
+        CAST(NULL AS NVARCHAR(32)) AS on_delete,
+        MAX(k.CHECK_CONDITION) AS check_clause
+    FROM SYS.CONSTRAINTS k
+    WHERE ` + keyFilter + `
+    GROUP BY k.SCHEMA_NAME, k.TABLE_NAME, k.CONSTRAINT_NAME, k.IS_PRIMARY_KEY, k.IS_UNIQUE_KEY
+    UNION ALL
+    SELECT 
+        r.SCHEMA_NAME,
+        r.TABLE_NAME,
+        r.CONSTRAINT_NAME,
+        'FOREIGN KEY',
+        STRING_AGG(r.COLUMN_NAME, ', ' ORDER BY r.POSITION),
+        r.REFERENCED_SCHEMA_NAME || '.' || r.REFERENCED_TABLE_NAME,
+        STRING_AGG(r.REFERENCED_COLUMN_NAME, ', ' ORDER BY r.POSITION),
+        r.DELETE_RULE,
+        NULL
+    FROM SYS.REFERENTIAL_CONSTRAINTS r
+    WHERE ` + foreignKeyFilter + `
+    GROUP BY r.SCHEMA_NAME, r.TABLE_NAME, r.CONSTRAINT_NAME, r.REFERENCED_SCHEMA_NAME, r.REFERENCED_TABLE_NAME, r.DELETE_RULE
+) AS c
+WHERE 1 = 1`
+
+	if constraintType != "" {
+		baseQuery += fmt.Sprintf(" AND c.constraint_type = %s", quoteLiteral("hana", strings.ToUpper(constraintType)))
+	}
+
+	baseQuery += `
+ORDER BY c.table_schema, c.table_name, c.constraint_name;`
+
+	return baseQuery
+}
+
+// getFirebirdConstraintsQuery returns a query for Firebird constraints. Keys list the
+// segments of the index that enforces them, a foreign key pairs them with the unique key it
+// references, and a check shows the source of the trigger Firebird generated for it; the
+// NOT NULL constraints Firebird records for columns are left out.
+func getFirebirdConstraintsQuery(tableName, constraintType string) string {
+	// Base query for Firebird constraints
+	baseQuery := `
+SELECT 
+    TRIM(c.RDB$RELATION_NAME) AS table_name,
+    TRIM(c.RDB$CONSTRAINT_NAME) AS constraint_name,
+    TRIM(c.RDB$CONSTRAINT_TYPE) AS constraint_type,
+    (SELECT LIST(TRIM(s.RDB$FIELD_NAME), ', ')
+     FROM RDB$INDEX_SEGMENTS s
+     WHERE s.RDB$INDEX_NAME = c.RDB$INDEX_NAME) AS column_names,
+    TRIM(uq.RDB$RELATION_NAME) AS referenced_table,
+    (SELECT LIST(TRIM(s.RDB$FIELD_NAME), ', ')
+     FROM RDB$INDEX_SEGMENTS s
+     WHERE s.RDB$INDEX_NAME = uq.RDB$INDEX_NAME) AS referenced_columns,
+    TRIM(rc.RDB$UPDATE_RULE) AS on_update,
+    TRIM(rc.RDB$DELETE_RULE) AS on_delete,
+    (SELECT FIRST 1 CAST(t.RDB$TRIGGER_SOURCE AS VARCHAR(8191))
+     FROM RDB$CHECK_CONSTRAINTS cc
+     JOIN RDB$TRIGGERS t ON t.RDB$TRIGGER_NAME = cc.RDB$TRIGGER_NAME
+     WHERE cc.RDB$CONSTRAINT_NAME = c.RDB$CONSTRAINT_NAME
+     AND c.RDB$CONSTRAINT_TYPE = 'CHECK') AS check_clause
+FROM RDB$RELATION_CONSTRAINTS c
+LEFT JOIN RDB$REF_CONSTRAINTS rc ON rc.RDB$CONSTRAINT_NAME = c.RDB$CONSTRAINT_NAME
+LEFT JOIN RDB$RELATION_CONSTRAINTS uq ON uq.RDB$CONSTRAINT_NAME = rc.RDB$CONST_NAME_UQ
+WHERE c.RDB$CONSTRAINT_TYPE <> 'NOT NULL'`
+
+	if tableName != "" {
+		baseQuery += fmt.Sprintf(" AND c.RDB$RELATION_NAME = %s", quoteLiteral("firebird", tableName))
+	}
+
+	if constraintType != "" {
+		baseQuery += fmt.Sprintf(" AND c.RDB$CONSTRAINT_TYPE = %s", quoteLiteral("firebird", strings.ToUpper(constraintType)))
+	}
+
+	baseQuery += `
+ORDER BY c.RDB$RELATION_NAME, c.RDB$CONSTRAINT_NAME;`
+
+	return baseQuery
+}
+
+// getDatabricksConstraintsQuery returns a query for the primary and foreign keys of Unity
+// Catalog tables; they are informational, not enforced
+func getDatabricksConstraintsQuery(tableName, constraintType string) string {
+	// Base query for Databricks constraints; a foreign key references the columns of the
+	// key named by its referential constraint, matched by position
+	baseQuery := `
+SELECT 
+    tc.table_catalog,
+    tc.table_schema,
+    tc.table_name,
+    tc.constraint_name,
+    tc.constraint_type,
+    concat_ws(', ', transform(array_sort(collect_list(struct(kcu.ordinal_position, kcu.column_name))), k -> k.column_name)) AS column_names,
+    concat_ws('.', first(uk.table_catalog), first(uk.table_schema), first(uk.table_name)) AS referenced_table,
+    concat_ws(', ', transform(array_sort(collect_list(struct(kcu.ordinal_position, uk.column_name))), k -> k.column_name)) AS referenced_columns,
+    tc.enforced
+FROM system.information_schema.table_constraints tc
+JOIN system.information_schema.key_column_usage kcu
+    ON kcu.constraint_catalog = tc.constraint_catalog
+    AND kcu.constraint_schema = tc.constraint_schema
+    AND kcu.constraint_name = tc.constraint_name
+LEFT JOIN system.information_schema.referential_constraints rc
+    ON rc.constraint_catalog = tc.constraint_catalog
+    AND rc.constraint_schema = tc.constraint_schema
+    AND rc.constraint_name = tc.constraint_name
+LEFT JOIN system.information_schema.key_column_usage uk
+    ON uk.constraint_catalog = rc.unique_constraint_catalog
+    AND uk.constraint_schema = rc.unique_constraint_schema
+    AND uk.constraint_name = rc.unique_constraint_name
+    AND uk.ordinal_position = kcu.position_in_unique_constraint
+WHERE ` + databricksCatalogFilter("tc", tableName)
+
+	if constraintType != "" {
+		baseQuery += fmt.Sprintf(" AND tc.constraint_type = %s", quoteLiteral("databricks", strings.ToUpper(constraintType)))
+	}
+
+	baseQuery += `
+GROUP BY tc.table_catalog, tc.table_schema, tc.table_name, tc.constraint_name, tc.constraint_type, tc.enforced
+ORDER BY tc.table_schema, tc.table_name, tc.constraint_name;`
+
+	return baseQuery
+}
+
+// getTrinoConstraintsQuery returns a query with the shape of the constraint listing and no
+// rows; Trino connectors do not expose keys
+func getTrinoConstraintsQuery(tableName, constraintType string) string {
+	return `
+SELECT 
+    '' AS table_schema,
+    '' AS table_name,
+    '' AS constraint_name,
+    '' AS constraint_type,
+    '' AS column_names
+WHERE false;`
+}
@@ -0,0 +1,288 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// dbSetting is one server configuration parameter
+type dbSetting struct {
+	Name           string
+	Value          string
+	Default        string // empty when the server does not expose it
+	Category       string
+	Source         string
+	Description    string
+	Changed        bool
+	PendingRestart bool
+}
+
+// GetDBSettingsTool handles reporting server configuration
+type GetDBSettingsTool struct {
+	BaseToolType
+}
+
+// NewGetDBSettingsTool creates a new get database settings tool type
+func NewGetDBSettingsTool() *GetDBSettingsTool {
+	return &GetDBSettingsTool{
+		BaseToolType: BaseToolType{
+			name:        "get_db_settings",
+			description: "Show the server configuration (pg_settings on PostgreSQL, the global variables on MySQL) with where each value comes from and whether it differs from the built-in default. Without filters it lists only the changed settings and the categories to drill into; a category or name pattern lists every matching setting. PostgreSQL categories are its own, such as \"Resource Usage / Memory\"; MySQL has none, so the category of a variable is the first word of its name, such as innodb or binlog. MySQL does not expose default values, so a variable counts as changed when it was set anywhere other than the compiled-in default. Use it alongside db_stats when discussing tuning.",
+		},
+	}
+}
+
+// CreateTool creates a get database settings tool
+func (t *GetDBSettingsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Show server configuration settings, highlighting values changed from default"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("category",
+			tools.Description("Case-insensitive substring of the category, such as memory, wal or innodb (optional)"),
+		),
+		tools.WithString("pattern",
+			tools.Description("Case-insensitive substring of the setting name (optional)"),
+		),
+		tools.WithBoolean("changed_only",
+			tools.Description("Only list settings changed from their default (default: true without category and pattern, false otherwise)"),
+		),
+	)
+}
+
+// HandleRequest handles get database settings tool requests
+func (t *GetDBSettingsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	category := strings.ToLower(strings.TrimSpace(input.optionalString("category", "")))
+	pattern := strings.ToLower(strings.TrimSpace(input.optionalString("pattern", "")))
+	filtered := category != "" || pattern != ""
+	changedOnly := input.optionalBool("changed_only", !filtered)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Getting settings for database %s, category %q, pattern %q", targetDbID, category, pattern)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for settings: %s", dbType)
+	}
+	loadSettings, err := dialect.SettingsLoader()
+	if err != nil {
+		return nil, unsupportedAdminError("settings", dbType, err)
+	}
+	settings, err := loadSettings(ctx, useCase, targetDbID)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []dbSetting
+	changed := 0
+	for _, s := range settings {
+		if category != "" && !strings.Contains(strings.ToLower(s.Category), category) {
+			continue
+		}
+		if pattern != "" && !strings.Contains(strings.ToLower(s.Name), pattern) {
+			continue
+		}
+		if s.Changed {
+			changed++
+		} else if changedOnly {
+			continue
+		}
+		matching = append(matching, s)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Settings of Database %s\n\n", targetDbID))
+	if category != "" {
+		response.WriteString(fmt.Sprintf("Category: `%s`\n", category))
+	}
+	if pattern != "" {
+		response.WriteString(fmt.Sprintf("Name pattern: `%s`\n", pattern))
+	}
+	if changedOnly {
+		response.WriteString(fmt.Sprintf("\n## Changed From Default (%d)\n\n", len(matching)))
+	} else {
+		response.WriteString(fmt.Sprintf("\n## Settings (%d, %d changed from default)\n\n", len(matching), changed))
+	}
+	writeDBSettings(&response, matching, !changedOnly)
+
+	if !filtered {
+		response.WriteString("\n## Categories\n\n")
+		writeSettingCategories(&response, settings)
+	}
+
+	var warnings []string
+	for _, s := range matching {
+		if s.PendingRestart {
+			warnings = append(warnings, fmt.Sprintf("%s was changed in the configuration file but only takes effect after a restart.", s.Name))
+		}
+	}
+	if len(warnings) > 0 {
+		response.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			response.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// writeDBSettings renders settings as a table, marking the changed ones when unchanged
+// settings are listed too
+func writeDBSettings(sb *strings.Builder, settings []dbSetting, markChanged bool) {
+	if len(settings) == 0 {
+		sb.WriteString("None.\n")
+		return
+	}
+	if markChanged {
+		sb.WriteString("| Name | Value | Default | Source | Changed | Category | Description |\n")
+		sb.WriteString("|------|-------|---------|--------|---------|----------|-------------|\n")
+	} else {
+		sb.WriteString("| Name | Value | Default | Source | Category | Description |\n")
+		sb.WriteString("|------|-------|---------|--------|----------|-------------|\n")
+	}
+	for _, s := range settings {
+		changed := ""
+		if markChanged {
+			changed = " " + yesNo(s.Changed) + " |"
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |%s %s | %s |\n",
+			s.Name, queryCell(s.Value), queryCell(s.Default), orDash(s.Source), changed, orDash(s.Category), queryCell(s.Description)))
+	}
+}
+
+// writeSettingCategories renders how many settings each category has and how many changed
+func writeSettingCategories(sb *strings.Builder, settings []dbSetting) {
+	var order []string
+	total := make(map[string]int)
+	changed := make(map[string]int)
+	for _, s := range settings {
+		if _, ok := total[s.Category]; !ok {
+			order = append(order, s.Category)
+		}
+		total[s.Category]++
+		if s.Changed {
+			changed[s.Category]++
+		}
+	}
+	if len(order) == 0 {
+		sb.WriteString("None.\n")
+		return
+	}
+	sb.WriteString("| Category | Settings | Changed |\n")
+	sb.WriteString("|----------|----------|---------|\n")
+	for _, c := range order {
+		sb.WriteString(fmt.Sprintf("| %s | %d | %d |\n", orDash(c), total[c], changed[c]))
+	}
+}
+
+// settingsLoader reads the server settings with their source and whether they were changed
+type settingsLoader func(ctx context.Context, useCase UseCaseProvider, dbID string) ([]dbSetting, error)
+
+// loadPostgresSettings reads pg_settings. A setting counts as changed when it was set by any
+// source other than the built-in default or a value computed at startup, to something other
+// than its boot value.
+func loadPostgresSettings(ctx context.Context, useCase UseCaseProvider, dbID string) ([]dbSetting, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, getPostgresSettingsQuery(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	settings := make([]dbSetting, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 8 {
+			continue
+		}
+		s := dbSetting{
+			Name:           valueString(row[0]),
+			Value:          valueString(row[1]),
+			Default:        valueString(row[2]),
+			Category:       valueString(row[4]),
+			Source:         valueString(row[5]),
+			Description:    valueString(row[6]),
+			PendingRestart: valueBool(row[7]),
+		}
+		s.Changed = s.Source != "default" && s.Source != "override" && s.Value != s.Default
+		if unit := valueString(row[3]); unit != "" {
+			s.Value += " " + unit
+			if s.Default != "" {
+				s.Default += " " + unit
+			}
+		}
+		settings = append(settings, s)
+	}
+	return settings, nil
+}
+
+// loadMySQLSettings reads the global variables with the source of their value. MySQL exposes
+// no defaults, so a variable counts as changed when its value did not come from the server
+// binary.
+func loadMySQLSettings(ctx context.Context, useCase UseCaseProvider, dbID string) ([]dbSetting, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, getMySQLSettingsQuery(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	settings := make([]dbSetting, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		name := valueString(row[0])
+		category := name
+		if idx := strings.Index(name, "_"); idx > 0 {
+			category = name[:idx]
+		}
+		source := strings.ToLower(valueString(row[2]))
+		settings = append(settings, dbSetting{
+			Name:     name,
+			Value:    valueString(row[1]),
+			Category: category,
+			Source:   source,
+			Changed:  source != "" && source != "compiled",
+		})
+	}
+	return settings, nil
+}
+
+// getPostgresSettingsQuery returns a query for every setting with its boot value and source
+func getPostgresSettingsQuery() string {
+	return `
+SELECT
+    name,
+    setting,
+    COALESCE(boot_val, '') AS boot_val,
+    COALESCE(unit, '') AS unit,
+    category,
+    source,
+    short_desc,
+    pending_restart
+FROM pg_settings
+ORDER BY category, name;`
+}
+
+// getMySQLSettingsQuery returns a query for every global variable with the source of its value
+func getMySQLSettingsQuery() string {
+	return `
+SELECT
+    g.VARIABLE_NAME,
+    g.VARIABLE_VALUE,
+    COALESCE(vi.VARIABLE_SOURCE, '') AS variable_source
+FROM performance_schema.global_variables g
+LEFT JOIN performance_schema.variables_info vi ON vi.VARIABLE_NAME = g.VARIABLE_NAME
+ORDER BY g.VARIABLE_NAME;`
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func getDBSettings(useCase *mockUseCase, params map[string]interface{}) (string, error) {
+	result, err := NewGetDBSettingsTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	if err != nil {
+		return "", err
+	}
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string), nil
+}
+
+func TestGetDBSettingsPostgres(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_settings": {Rows: [][]interface{}{
+				{"shared_buffers", "524288", "16384", "8kB", "Resource Usage / Memory", "configuration file", "Sets the number of shared memory buffers used by the server.", true},
+				{"work_mem", "4096", "4096", "kB", "Resource Usage / Memory", "configuration file", "Sets the maximum memory to be used for query workspaces.", false},
+				{"wal_buffers", "2048", "-1", "8kB", "Write-Ahead Log / Settings", "override", "Sets the number of disk-page buffers in shared memory for WAL.", false},
+				{"random_page_cost", "1.1", "4", "", "Query Tuning / Planner Cost Constants", "configuration file", "Sets the planner's estimate of the cost of a nonsequentially fetched disk page.", false},
+			}},
+		},
+	}
+	text, err := getDBSettings(useCase, map[string]interface{}{"database": "pg1"})
+	assert.NoError(t, err)
+
+	assert.Contains(t, text, "## Changed From Default (2)")
+	assert.Contains(t, text, "| shared_buffers | 524288 8kB | 16384 8kB | configuration file | Resource Usage / Memory |")
+	assert.Contains(t, text, "| random_page_cost | 1.1 | 4 | configuration file |")
+	assert.NotContains(t, text, "| work_mem |")
+	assert.NotContains(t, text, "| wal_buffers |")
+	assert.Contains(t, text, "| Resource Usage / Memory | 2 | 1 |")
+	assert.Contains(t, text, "- shared_buffers was changed in the configuration file but only takes effect after a restart.")
+
+	text, err = getDBSettings(useCase, map[string]interface{}{"database": "pg1", "category": "Memory"})
+	assert.NoError(t, err)
+	assert.Contains(t, text, "## Settings (2, 1 changed from default)")
+	assert.Contains(t, text, "| work_mem | 4096 kB | 4096 kB | configuration file | no | Resource Usage / Memory |")
+	assert.NotContains(t, text, "## Categories")
+}
+
+func TestGetDBSettingsMySQL(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"performance_schema.global_variables": {Rows: [][]interface{}{
+				{"innodb_buffer_pool_size", "8589934592", "EXPLICIT"},
+				{"innodb_flush_log_at_trx_commit", "1", "COMPILED"},
+				{"max_connections", "151", "COMPILED"},
+			}},
+		},
+	}
+	text, err := getDBSettings(useCase, map[string]interface{}{"database": "db1", "pattern": "innodb"})
+	assert.NoError(t, err)
+	assert.Contains(t, text, "| innodb_buffer_pool_size | 8589934592 | - | explicit | yes | innodb | - |")
+	assert.Contains(t, text, "| innodb_flush_log_at_trx_commit | 1 | - | compiled | no | innodb | - |")
+	assert.NotContains(t, text, "max_connections")
+
+	_, err = getDBSettings(&mockUseCase{dbType: "sqlite"}, map[string]interface{}{"database": "lite"})
+	assert.ErrorContains(t, err, "unsupported database type for settings: sqlite")
+}
@@ -0,0 +1,231 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// toolExtension is an extension that other tools of the server read from
+type toolExtension struct {
+	Name   string
+	UsedBy string
+	// Preload is set for extensions whose views only work once their library is in
+	// shared_preload_libraries
+	Preload bool
+}
+
+// toolExtensions lists the extensions that other tools depend on
+var toolExtensions = []toolExtension{
+	{Name: "pg_stat_statements", UsedBy: "per-query execution statistics read through the query tool", Preload: true},
+	{Name: "pg_buffercache", UsedBy: "the buffer cache section of db_stats with detailed"},
+	{Name: "timescaledb", UsedBy: "get_hypertables and the hypertable section of table_stats", Preload: true},
+	{Name: "postgis", UsedBy: "spatial_summary"},
+	{Name: "vector", UsedBy: "vector_search"},
+}
+
+// GetExtensionsTool handles listing the extensions of a database
+type GetExtensionsTool struct {
+	BaseToolType
+}
+
+// NewGetExtensionsTool creates a new get extensions tool type
+func NewGetExtensionsTool() *GetExtensionsTool {
+	return &GetExtensionsTool{
+		BaseToolType: BaseToolType{
+			name:        "get_extensions",
+			description: "List the extensions installed in a PostgreSQL database with their installed version, the version the server offers and whether an update is available, and optionally the extensions that can be installed. It also reports whether the extensions other tools read from (pg_stat_statements, pg_buffercache, timescaledb, postgis, vector) are installed and preloaded. Use this tool before relying on extension views, whose queries fail when the extension is missing.",
+		},
+	}
+}
+
+// CreateTool creates a get extensions tool
+func (t *GetExtensionsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("List installed PostgreSQL extensions with versions and available updates"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("extension",
+			tools.Description("Extension name (optional, leave empty for all extensions)"),
+		),
+		tools.WithBoolean("include_available",
+			tools.Description("Whether to also list extensions that are available but not installed (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles get extensions tool requests
+func (t *GetExtensionsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	extensionName := input.optionalString("extension", "")
+	includeAvailable := input.optionalBool("include_available", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Getting extensions for database %s, extension %s", targetDbID, extensionName)
+
+	// Get database type to determine which queries to run
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for extensions: %s", dbType)
+	}
+	query, ok := dialect.ExtensionQuery()
+	if !ok {
+		return createTextResponse(fmt.Sprintf("Database type %s does not support extensions in the same way as PostgreSQL. Its features are built into the server, so there is nothing to install or check.", dialect.Name())), nil
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, targetDbID, query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get extensions: %w", err)
+	}
+	extensions, preloaded := readExtensions(result)
+
+	var response strings.Builder
+	if extensionName != "" {
+		response.WriteString(fmt.Sprintf("# Extension %s in Database %s\n\n", extensionName, targetDbID))
+		ext, found := findExtension(extensions, extensionName)
+		if !found {
+			response.WriteString(fmt.Sprintf("Extension %s is neither installed nor available on this server.\n", extensionName))
+			return createTextResponse(response.String()), nil
+		}
+		writeExtensions(&response, []extensionInfo{ext})
+		return createTextResponse(response.String()), nil
+	}
+
+	response.WriteString(fmt.Sprintf("# Extensions in Database %s\n\n", targetDbID))
+	var listed []extensionInfo
+	for _, ext := range extensions {
+		if ext.Installed != "" || includeAvailable {
+			listed = append(listed, ext)
+		}
+	}
+	writeExtensions(&response, listed)
+	writeToolExtensions(&response, extensions, preloaded)
+
+	return createTextResponse(response.String()), nil
+}
+
+// extensionInfo is an extension that is installed, available or both
+type extensionInfo struct {
+	Name           string
+	Installed      string // installed version; empty when not installed
+	DefaultVersion string // version CREATE EXTENSION and ALTER EXTENSION UPDATE install; empty when the server no longer has the extension
+	Schema         string
+	Description    string
+}
+
+// status describes whether an extension is installed and up to date
+func (e extensionInfo) status() string {
+	switch {
+	case e.Installed == "":
+		return "available"
+	case e.DefaultVersion == "":
+		return "installed, no longer available on the server"
+	case e.Installed != e.DefaultVersion:
+		return fmt.Sprintf("update available: ALTER EXTENSION %s UPDATE", quoteIdentifier("postgres", e.Name))
+	default:
+		return "up to date"
+	}
+}
+
+// readExtensions reads the rows of an extension query, together with the libraries in
+// shared_preload_libraries
+func readExtensions(result *domain.QueryResult) ([]extensionInfo, map[string]bool) {
+	var extensions []extensionInfo
+	preloaded := make(map[string]bool)
+	for _, row := range result.Rows {
+		if len(row) < 6 {
+			continue
+		}
+		ext := extensionInfo{
+			Name:           valueString(row[0]),
+			Installed:      valueString(row[1]),
+			DefaultVersion: valueString(row[2]),
+			Schema:         valueString(row[3]),
+			Description:    valueString(row[4]),
+		}
+		extensions = append(extensions, ext)
+		for _, library := range strings.Split(valueString(row[5]), ",") {
+			if library = strings.Trim(strings.TrimSpace(library), `"`); library != "" {
+				preloaded[library] = true
+			}
+		}
+	}
+	return extensions, preloaded
+}
+
+// findExtension returns the extension with the given name
+func findExtension(extensions []extensionInfo, name string) (extensionInfo, bool) {
+	for _, ext := range extensions {
+		if ext.Name == name {
+			return ext, true
+		}
+	}
+	return extensionInfo{}, false
+}
+
+// writeExtensions renders extensions as a table
+func writeExtensions(sb *strings.Builder, extensions []extensionInfo) {
+	if len(extensions) == 0 {
+		sb.WriteString("No extensions installed.\n")
+		return
+	}
+	sb.WriteString("| Extension | Installed | Default version | Schema | Status | Description |\n")
+	sb.WriteString("|-----------|-----------|-----------------|--------|--------|-------------|\n")
+	for _, e := range extensions {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+			e.Name, orDash(e.Installed), orDash(e.DefaultVersion), orDash(e.Schema), e.status(), e.Description))
+	}
+}
+
+// writeToolExtensions reports whether the extensions other tools read from can be used
+func writeToolExtensions(sb *strings.Builder, extensions []extensionInfo, preloaded map[string]bool) {
+	sb.WriteString("\n## Extensions Used by Tools\n\n")
+	for _, te := range toolExtensions {
+		ext, found := findExtension(extensions, te.Name)
+		var state string
+		switch {
+		case !found:
+			state = "not available on this server"
+		case ext.Installed == "":
+			state = fmt.Sprintf("not installed; install version %s with CREATE EXTENSION %s", ext.DefaultVersion, te.Name)
+		case te.Preload && !preloaded[te.Name]:
+			state = fmt.Sprintf("installed (%s), but missing from shared_preload_libraries, so its views fail until it is added and the server restarts", ext.Installed)
+		default:
+			state = fmt.Sprintf("installed (%s)", ext.Installed)
+		}
+		sb.WriteString(fmt.Sprintf("- %s, for %s: %s\n", te.Name, te.UsedBy, state))
+	}
+}
+
+// getPostgresExtensionsQuery returns a query for the extensions that are installed in the
+// database or that the server can install, with the libraries it preloads
+func getPostgresExtensionsQuery() string {
+	return `
+SELECT
+    COALESCE(e.extname, a.name) AS extension_name,
+    e.extversion AS installed_version,
+    a.default_version,
+    n.nspname AS schema_name,
+    COALESCE(a.comment, obj_description(e.oid, 'pg_extension')) AS description,
+    current_setting('shared_preload_libraries') AS preload_libraries
+FROM pg_available_extensions a
+FULL JOIN pg_extension e ON e.extname = a.name
+LEFT JOIN pg_namespace n ON n.oid = e.extnamespace
+ORDER BY 1;`
+}
@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func getExtensionsText(t *testing.T, useCase *mockUseCase, params map[string]interface{}) string {
+	result, err := NewGetExtensionsTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	assert.NoError(t, err)
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+}
+
+func postgresExtensionsUseCase() *mockUseCase {
+	preload := "pg_stat_statements, \"auto_explain\""
+	return &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{"FROM pg_available_extensions a": {Rows: [][]interface{}{
+			{"pg_buffercache", nil, "1.4", nil, "examine the shared buffer cache", preload},
+			{"pg_stat_statements", "1.10", "1.10", "public", "track planning and execution statistics of all SQL statements executed", preload},
+			{"plpgsql", "1.0", "1.0", "pg_catalog", "PL/pgSQL procedural language", preload},
+			{"postgis", "3.3.2", "3.4.2", "public", "PostGIS geometry and geography spatial types and functions", preload},
+			{"timescaledb", "2.14.2", "2.14.2", "public", "Enables scalable inserts and complex queries for time-series data", preload},
+		}}},
+	}
+}
+
+func TestGetExtensionsListsInstalledExtensions(t *testing.T) {
+	useCase := postgresExtensionsUseCase()
+	text := getExtensionsText(t, useCase, map[string]interface{}{"database": "pg1"})
+
+	assert.Contains(t, useCase.queries[0], "FULL JOIN pg_extension e ON e.extname = a.name")
+	assert.Contains(t, text, "| postgis | 3.3.2 | 3.4.2 | public | update available: ALTER EXTENSION \"postgis\" UPDATE |")
+	assert.Contains(t, text, "| plpgsql | 1.0 | 1.0 | pg_catalog | up to date |")
+	assert.NotContains(t, text, "| pg_buffercache |")
+
+	assert.Contains(t, text, "- pg_stat_statements, for per-query execution statistics read through the query tool: installed (1.10)\n")
+	assert.Contains(t, text, "- pg_buffercache, for the buffer cache section of db_stats with detailed: not installed; install version 1.4 with CREATE EXTENSION pg_buffercache\n")
+	assert.Contains(t, text, "- timescaledb, for get_hypertables and the hypertable section of table_stats: installed (2.14.2), but missing from shared_preload_libraries")
+	assert.Contains(t, text, "- vector, for vector_search: not available on this server\n")
+}
+
+func TestGetExtensionsShowsOneOrAvailableExtensions(t *testing.T) {
+	text := getExtensionsText(t, postgresExtensionsUseCase(), map[string]interface{}{"database": "pg1", "include_available": true})
+	assert.Contains(t, text, "| pg_buffercache | - | 1.4 | - | available | examine the shared buffer cache |")
+
+	text = getExtensionsText(t, postgresExtensionsUseCase(), map[string]interface{}{"database": "pg1", "extension": "timescaledb"})
+	assert.Contains(t, text, "# Extension timescaledb in Database pg1\n")
+	assert.Contains(t, text, "| timescaledb | 2.14.2 | 2.14.2 | public | up to date |")
+	assert.NotContains(t, text, "postgis")
+
+	text = getExtensionsText(t, postgresExtensionsUseCase(), map[string]interface{}{"database": "pg1", "extension": "pg_cron"})
+	assert.Contains(t, text, "Extension pg_cron is neither installed nor available on this server.")
+}
+
+func TestGetExtensionsShortCircuitsWithoutExtensions(t *testing.T) {
+	for _, dbType := range []string{"mysql", "tidb", "cockroachdb", "sqlite", "clickhouse"} {
+		useCase := &mockUseCase{dbType: dbType}
+		text := getExtensionsText(t, useCase, map[string]interface{}{"database": "db1"})
+		assert.Contains(t, text, "does not support extensions in the same way as PostgreSQL", dbType)
+		assert.Empty(t, useCase.queries, dbType)
+	}
+
+	_, ok := dialects["yugabyte"].ExtensionQuery()
+	assert.True(t, ok)
+}
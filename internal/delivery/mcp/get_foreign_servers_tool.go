@@ -0,0 +1,180 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// GetForeignServersTool handles reporting the foreign data wrappers of a database
+type GetForeignServersTool struct {
+	BaseToolType
+}
+
+// NewGetForeignServersTool creates a new get foreign servers tool type
+func NewGetForeignServersTool() *GetForeignServersTool {
+	return &GetForeignServersTool{
+		BaseToolType: BaseToolType{
+			name:        "get_foreign_servers",
+			description: "List the foreign data wrappers, foreign servers, user mappings and foreign tables of a PostgreSQL database, showing which tables hold data that actually lives on another server and how that server is reached. Passwords in options are masked. Servers whose foreign tables have no user mapping and user mappings that share credentials with every role are called out. Filter by server name to see one server.",
+		},
+	}
+}
+
+// CreateTool creates a get foreign servers tool
+func (t *GetForeignServersTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("List foreign data wrappers, servers, user mappings and foreign tables"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("server",
+			tools.Description("Foreign server name (optional, leave empty for all servers)"),
+		),
+	)
+}
+
+// HandleRequest handles get foreign servers tool requests
+func (t *GetForeignServersTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	serverName := input.optionalString("server", "")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Getting foreign servers for database %s, server %s", targetDbID, serverName)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for foreign servers: %s", dbType)
+	}
+	sections, ok := dialect.ForeignServerSections(serverName)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for foreign servers: %s", dbType)
+	}
+
+	var response strings.Builder
+	if serverName == "" {
+		response.WriteString(fmt.Sprintf("# Foreign Servers in Database %s\n", targetDbID))
+	} else {
+		response.WriteString(fmt.Sprintf("# Foreign Server %s in Database %s\n", serverName, targetDbID))
+	}
+	if err := writeReportSections(ctx, useCase, targetDbID, sections, &response); err != nil {
+		return nil, err
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// maskedOptions renders an option array as key=value pairs with passwords masked
+func maskedOptions(column string) string {
+	return fmt.Sprintf(`array_to_string(ARRAY(
+        SELECT CASE WHEN o LIKE 'password=%%' THEN 'password=********' ELSE o END
+        FROM unnest(%s) AS o), ', ')`, column)
+}
+
+// postgresForeignServerSections returns the wrappers, servers, user mappings and foreign tables
+// of a PostgreSQL database, optionally of one server. The options of a user mapping are only
+// visible to the owner of its server, the mapped user and superusers.
+func postgresForeignServerSections(serverName string) []reportSection {
+	params := []interface{}{serverName}
+	return []reportSection{
+		{
+			title: "Foreign Data Wrappers",
+			query: fmt.Sprintf(`
+SELECT
+    w.fdwname AS wrapper_name,
+    pg_get_userbyid(w.fdwowner) AS owner,
+    COALESCE(e.extname, '') AS extension,
+    CASE WHEN w.fdwhandler = 0 THEN '' ELSE w.fdwhandler::regproc::text END AS handler,
+    CASE WHEN w.fdwvalidator = 0 THEN '' ELSE w.fdwvalidator::regproc::text END AS validator,
+    %s AS options
+FROM pg_foreign_data_wrapper w
+LEFT JOIN pg_depend d ON d.classid = 'pg_foreign_data_wrapper'::regclass AND d.objid = w.oid AND d.deptype = 'e'
+LEFT JOIN pg_extension e ON e.oid = d.refobjid
+WHERE $1 = '' OR EXISTS (SELECT 1 FROM pg_foreign_server s WHERE s.srvfdw = w.oid AND s.srvname = $1)
+ORDER BY 1`, maskedOptions("w.fdwoptions")),
+			params: params,
+		},
+		{
+			title: "Foreign Servers",
+			query: fmt.Sprintf(`
+SELECT
+    s.srvname AS server_name,
+    w.fdwname AS wrapper_name,
+    pg_get_userbyid(s.srvowner) AS owner,
+    COALESCE(s.srvtype, '') AS server_type,
+    COALESCE(s.srvversion, '') AS server_version,
+    %s AS options,
+    (SELECT count(*) FROM pg_user_mappings um WHERE um.srvid = s.oid) AS user_mappings,
+    (SELECT count(*) FROM pg_foreign_table ft WHERE ft.ftserver = s.oid) AS foreign_tables
+FROM pg_foreign_server s
+JOIN pg_foreign_data_wrapper w ON w.oid = s.srvfdw
+WHERE $1 = '' OR s.srvname = $1
+ORDER BY 1`, maskedOptions("s.srvoptions")),
+			params: params,
+			warn: func(row []interface{}) []string {
+				// file_fdw reads local files and needs no user mapping
+				if len(row) < 8 || valueString(row[1]) == "file_fdw" || valueInt64(row[6]) > 0 || valueInt64(row[7]) == 0 {
+					return nil
+				}
+				return []string{fmt.Sprintf("Server %[1]s has %[2]d foreign table(s) but no user mapping, so queries on them fail until one is created with CREATE USER MAPPING FOR ... SERVER %[1]s.",
+					quoteIdentifier("postgres", valueString(row[0])), valueInt64(row[7]))}
+			},
+		},
+		{
+			title: "User Mappings",
+			query: fmt.Sprintf(`
+SELECT
+    um.srvname AS server_name,
+    um.usename AS local_user,
+    CASE WHEN um.umoptions IS NULL THEN '(not visible to this role)' ELSE %s END AS options
+FROM pg_user_mappings um
+WHERE $1 = '' OR um.srvname = $1
+ORDER BY 1, 2`, maskedOptions("um.umoptions")),
+			params: params,
+			warn: func(row []interface{}) []string {
+				if len(row) < 3 {
+					return nil
+				}
+				var warnings []string
+				server, user, options := valueString(row[0]), valueString(row[1]), valueString(row[2])
+				if user == "public" && strings.Contains(options, "password=") {
+					warnings = append(warnings, fmt.Sprintf("The PUBLIC user mapping of server %s stores a password, so every role connects with the same remote credentials.", server))
+				}
+				if strings.Contains(options, "password_required=false") {
+					warnings = append(warnings, fmt.Sprintf("The user mapping of %s on server %s sets password_required 'false', letting a non-superuser connect without a password.", user, server))
+				}
+				return warnings
+			},
+		},
+		{
+			title: "Foreign Tables",
+			query: fmt.Sprintf(`
+SELECT
+    n.nspname AS schema_name,
+    c.relname AS table_name,
+    s.srvname AS server_name,
+    %s AS options,
+    (SELECT count(*) FROM pg_attribute a WHERE a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped) AS columns
+FROM pg_foreign_table ft
+JOIN pg_class c ON c.oid = ft.ftrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_foreign_server s ON s.oid = ft.ftserver
+WHERE $1 = '' OR s.srvname = $1
+ORDER BY 1, 2`, maskedOptions("ft.ftoptions")),
+			params: params,
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestGetForeignServersReportsServers(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_foreign_data_wrapper w": {
+				Columns: []string{"wrapper_name", "owner", "extension", "handler", "validator", "options"},
+				Rows:    [][]interface{}{{"postgres_fdw", "postgres", "postgres_fdw", "postgres_fdw_handler", "postgres_fdw_validator", ""}},
+			},
+			"FROM pg_foreign_server s\nJOIN": {
+				Columns: []string{"server_name", "wrapper_name", "owner", "server_type", "server_version", "options", "user_mappings", "foreign_tables"},
+				Rows: [][]interface{}{
+					{"warehouse", "postgres_fdw", "postgres", "", "", "host=dw.internal, dbname=dw", int64(2), int64(3)},
+					{"archive", "postgres_fdw", "postgres", "", "", "host=archive.internal", int64(0), int64(1)},
+					{"csv", "file_fdw", "postgres", "", "", "", int64(0), int64(1)},
+				},
+			},
+			"FROM pg_user_mappings um\n": {
+				Columns: []string{"server_name", "local_user", "options"},
+				Rows: [][]interface{}{
+					{"warehouse", "public", "user=reader, password=********"},
+					{"warehouse", "etl", "user=etl, password=********, password_required=false"},
+				},
+			},
+		},
+	}
+	result, err := NewGetForeignServersTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "pg1"}}, "", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+
+	assert.Len(t, useCase.queries, 4)
+	assert.Contains(t, useCase.queries[2], "WHEN o LIKE 'password=%' THEN 'password=********'")
+	assert.Contains(t, text, "\n## Foreign Servers\n\n")
+	assert.Contains(t, text, "host=dw.internal, dbname=dw")
+	assert.Contains(t, text, "\n## Foreign Tables\n\nNone.\n")
+	assert.Contains(t, text, "- Server \"archive\" has 1 foreign table(s) but no user mapping")
+	assert.NotContains(t, text, "Server \"csv\"")
+	assert.Contains(t, text, "- The PUBLIC user mapping of server warehouse stores a password")
+	assert.Contains(t, text, "- The user mapping of etl on server warehouse sets password_required 'false'")
+
+	_, err = NewGetForeignServersTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "db1"}}, "", &mockUseCase{dbType: "mysql"})
+	assert.ErrorContains(t, err, "unsupported database type for foreign servers: mysql")
+}
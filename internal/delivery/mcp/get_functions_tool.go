@@ -0,0 +1,442 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// GetFunctionsTool handles retrieving the user-defined functions and procedures of a database
+type GetFunctionsTool struct {
+	BaseToolType
+}
+
+// NewGetFunctionsTool creates a new get functions tool type
+func NewGetFunctionsTool() *GetFunctionsTool {
+	return &GetFunctionsTool{
+		BaseToolType: BaseToolType{
+			name:        "get_functions",
+			description: "Retrieve the user-defined functions and stored procedures of a database. This tool lists each routine with its schema, kind, argument signature, return type, language and volatility or determinism, and optionally its full source. Business rules often live in routines, triggers call them, and views and defaults depend on them; use this tool to discover that logic before changing data or schema.",
+		},
+	}
+}
+
+// CreateTool creates a get functions tool
+func (t *GetFunctionsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Retrieve user-defined functions and stored procedures with their signatures"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("function",
+			tools.Description("Function or procedure name, optionally schema-qualified (optional, leave empty for all routines)"),
+		),
+		tools.WithBoolean("include_source",
+			tools.Description("Whether to include the full source of each routine (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles get functions tool requests
+func (t *GetFunctionsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	functionName := input.optionalString("function", "")
+	includeSource := input.optionalBool("include_source", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Getting functions for database %s, function %s, include_source %v", targetDbID, functionName, includeSource)
+
+	// Get database type to determine which queries to run
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+
+	// Build query in the dialect of the database
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for functions: %s", dbType)
+	}
+	query, ok := dialect.FunctionQuery(functionName, includeSource)
+	if !ok {
+		return createTextResponse(fmt.Sprintf("Database type %s has no user-defined functions or stored procedures to list.", dialect.Name())), nil
+	}
+
+	// Execute the query
+	result, err := useCase.ExecuteQuery(ctx, targetDbID, query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get functions: %w", err)
+	}
+
+	// Format the response
+	var response strings.Builder
+	if functionName == "" {
+		response.WriteString(fmt.Sprintf("# All Functions and Procedures in Database %s\n\n", targetDbID))
+	} else {
+		response.WriteString(fmt.Sprintf("# Function %s in Database %s\n\n", functionName, targetDbID))
+	}
+	if len(result.Rows) == 0 {
+		response.WriteString("No user-defined functions or procedures found.")
+	} else {
+		response.WriteString(formatQueryResult(result))
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// getPostgresFunctionsQuery returns a query for PostgreSQL functions, procedures and
+// aggregates of the user schemas; routines that belong to an extension are left out
+func getPostgresFunctionsQuery(functionName string, includeSource bool) string {
+	// Base query for PostgreSQL routines
+	baseQuery := `
+SELECT
+    n.nspname AS schema_name,
+    p.proname AS function_name,
+    CASE p.prokind
+        WHEN 'f' THEN 'FUNCTION'
+        WHEN 'p' THEN 'PROCEDURE'
+        WHEN 'a' THEN 'AGGREGATE'
+        WHEN 'w' THEN 'WINDOW'
+    END AS routine_type,
+    pg_get_function_arguments(p.oid) AS arguments,
+    pg_get_function_result(p.oid) AS return_type,
+    l.lanname AS language,
+    CASE p.provolatile
+        WHEN 'i' THEN 'IMMUTABLE'
+        WHEN 's' THEN 'STABLE'
+        ELSE 'VOLATILE'
+    END AS volatility,
+    CASE WHEN p.prosecdef THEN 'DEFINER' ELSE 'INVOKER' END AS security,
+    obj_description(p.oid, 'pg_proc') AS description`
+
+	if includeSource {
+		// pg_get_functiondef rejects aggregates, whose source is their transition function
+		baseQuery += `,
+    CASE WHEN p.prokind IN ('f', 'p') THEN pg_get_functiondef(p.oid) ELSE p.prosrc END AS source`
+	}
+
+	baseQuery += `
+FROM pg_proc p
+JOIN pg_namespace n ON n.oid = p.pronamespace
+JOIN pg_language l ON l.oid = p.prolang
+WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')
+AND n.nspname NOT LIKE 'pg\_toast%'
+AND n.nspname NOT LIKE 'pg\_temp\_%'
+AND NOT EXISTS (
+    SELECT 1 FROM pg_depend d
+    WHERE d.classid = 'pg_proc'::regclass AND d.objid = p.oid AND d.deptype = 'e'
+)`
+
+	if functionName != "" {
+		schema, name := splitQualifiedName(functionName)
+		if schema != "" {
+			baseQuery += fmt.Sprintf(" AND n.nspname = %s", quoteLiteral("postgres", schema))
+		}
+		baseQuery += fmt.Sprintf(" AND p.proname = %s", quoteLiteral("postgres", name))
+	}
+
+	baseQuery += `
+ORDER BY n.nspname, p.proname, pg_get_function_arguments(p.oid);`
+
+	return baseQuery
+}
+
+// getMySQLFunctionsQuery returns a query for the stored functions and procedures of the
+// current database, or of the schema a qualified name gives
+func getMySQLFunctionsQuery(functionName string, includeSource bool) string {
+	// Base query for MySQL routines; parameter 0 of a function is its return value
+	baseQuery := `
+SELECT
+    r.ROUTINE_SCHEMA AS schema_name,
+    r.ROUTINE_NAME AS function_name,
+    r.ROUTINE_TYPE AS routine_type,
+    (SELECT GROUP_CONCAT(
+        CONCAT_WS(' ', IF(r.ROUTINE_TYPE = 'PROCEDURE', p.PARAMETER_MODE, NULL), p.PARAMETER_NAME, p.DTD_IDENTIFIER)
+        ORDER BY p.ORDINAL_POSITION SEPARATOR ', ')
+     FROM information_schema.PARAMETERS p
+     WHERE p.SPECIFIC_SCHEMA = r.ROUTINE_SCHEMA
+     AND p.SPECIFIC_NAME = r.SPECIFIC_NAME
+     AND p.ROUTINE_TYPE = r.ROUTINE_TYPE
+     AND p.ORDINAL_POSITION > 0) AS arguments,
+    r.DTD_IDENTIFIER AS return_type,
+    r.ROUTINE_BODY AS language,
+    IF(r.IS_DETERMINISTIC = 'YES', 'DETERMINISTIC', 'NOT DETERMINISTIC') AS determinism,
+    r.SQL_DATA_ACCESS AS data_access,
+    r.SECURITY_TYPE AS security,
+    r.ROUTINE_COMMENT AS description`
+
+	if includeSource {
+		baseQuery += `,
+    r.ROUTINE_DEFINITION AS source`
+	}
+
+	baseQuery += `
+FROM information_schema.ROUTINES r`
+
+	schema, name := splitQualifiedName(functionName)
+	if schema != "" {
+		baseQuery += fmt.Sprintf(" WHERE r.ROUTINE_SCHEMA = %s", quoteLiteral("mysql", schema))
+	} else {
+		baseQuery += " WHERE r.ROUTINE_SCHEMA = DATABASE()"
+	}
+	if name != "" {
+		baseQuery += fmt.Sprintf(" AND r.ROUTINE_NAME = %s", quoteLiteral("mysql", name))
+	}
+
+	baseQuery += `
+ORDER BY r.ROUTINE_TYPE, r.ROUTINE_NAME;`
+
+	return baseQuery
+}
+
+// getClickHouseFunctionsQuery returns a query for the user-defined functions of ClickHouse:
+// SQL lambdas created with CREATE FUNCTION and executable functions configured on the server
+func getClickHouseFunctionsQuery(functionName string, includeSource bool) string {
+	// Base query for ClickHouse user-defined functions
+	baseQuery := `
+SELECT
+    name AS function_name,
+    'FUNCTION' AS routine_type,
+    if(origin = 'SQLUserDefined', 'SQL', 'EXECUTABLE') AS language,
+    is_aggregate`
+
+	if includeSource {
+		baseQuery += `,
+    create_query AS source`
+	}
+
+	baseQuery += `
+FROM system.functions
+WHERE origin IN ('SQLUserDefined', 'ExecutableUserDefined')`
+
+	if functionName != "" {
+		baseQuery += fmt.Sprintf(" AND name = %s", quoteLiteral("clickhouse", functionName))
+	}
+
+	baseQuery += `
+ORDER BY name;`
+
+	return baseQuery
+}
+
+// getBigQueryFunctionsQuery returns a query for the routines of the default dataset: SQL and
+// JavaScript functions, table functions and procedures
+func getBigQueryFunctionsQuery(functionName string, includeSource bool) string {
+	// Base query for BigQuery routines
+	baseQuery := `
+SELECT
+    r.routine_schema AS schema_name,
+    r.routine_name AS function_name,
+    r.routine_type,
+    (SELECT STRING_AGG(CONCAT(IFNULL(p.parameter_name, ''), ' ', p.data_type), ', ' ORDER BY p.ordinal_position)
+     FROM INFORMATION_SCHEMA.PARAMETERS p
+     WHERE p.specific_schema = r.specific_schema
+     AND p.specific_name = r.specific_name
+     AND p.is_result = 'NO') AS arguments,
+    r.data_type AS return_type,
+    IFNULL(r.external_language, r.routine_body) AS language,
+    r.created,
+    r.last_altered`
+
+	if includeSource {
+		baseQuery += `,
+    r.ddl AS source`
+	}
+
+	baseQuery += `
+FROM INFORMATION_SCHEMA.ROUTINES r`
+
+	if functionName != "" {
+		baseQuery += fmt.Sprintf(" WHERE r.routine_name = %s", quoteLiteral("bigquery", functionName))
+	}
+
+	baseQuery += `
+ORDER BY r.routine_type, r.routine_name;`
+
+	return baseQuery
+}
+
+// getHANAFunctionsQuery returns a query for the SQLScript functions and procedures of the
+// user schemas, with their parameters from SYS.FUNCTION_PARAMETERS and SYS.PROCEDURE_PARAMETERS
+func getHANAFunctionsQuery(functionName string, includeSource bool) string {
+	source := ""
+	if includeSource {
+		source = `,
+        f.DEFINITION AS source`
+	}
+	procedureSource := strings.Replace(source, "f.", "p.", 1)
+
+	// The name filter applies to both halves of the union
+	functionFilter := " AND " + hanaUserSchemaFilter("f")
+	procedureFilter := " AND " + hanaUserSchemaFilter("p")
+	if functionName != "" {
+		schema, name := splitQualifiedName(functionName)
+		if schema != "" {
+			functionFilter += fmt.Sprintf(" AND f.SCHEMA_NAME = %s", quoteLiteral("hana", schema))
+			procedureFilter += fmt.Sprintf(" AND p.SCHEMA_NAME = %s", quoteLiteral("hana", schema))
+		}
+		functionFilter += fmt.Sprintf(" AND f.FUNCTION_NAME = %s", quoteLiteral("hana", name))
+		procedureFilter += fmt.Sprintf(" AND p.PROCEDURE_NAME = %s", quoteLiteral("hana", name))
+	}
+
+	return `
+SELECT * FROM (
+    SELECT
+        f.SCHEMA_NAME AS schema_name,
+        f.FUNCTION_NAME AS function_name,
+        'FUNCTION' AS routine_type,
+        (SELECT STRING_AGG(a.PARAMETER_NAME || ' ' || a.DATA_TYPE_NAME, ', ' ORDER BY a.POSITION)
+         FROM SYS.FUNCTION_PARAMETERS a
+         WHERE a.FUNCTION_OID = f.FUNCTION_OID AND a.PARAMETER_TYPE = 'IN') AS arguments,
+        (SELECT STRING_AGG(a.DATA_TYPE_NAME, ', ' ORDER BY a.POSITION)
+         FROM SYS.FUNCTION_PARAMETERS a
+         WHERE a.FUNCTION_OID = f.FUNCTION_OID AND a.PARAMETER_TYPE = 'RETURN') AS return_type,
+        'SQLSCRIPT' AS language,
+        f.SQL_SECURITY AS security,
+        f.IS_VALID AS is_valid` + source + `
+    FROM SYS.FUNCTIONS f
+    WHERE 1 = 1` + functionFilter + `
+    UNION ALL
+    SELECT
+        p.SCHEMA_NAME,
+        p.PROCEDURE_NAME,
+        'PROCEDURE',
+        (SELECT STRING_AGG(a.PARAMETER_TYPE || ' ' || a.PARAMETER_NAME || ' ' || a.DATA_TYPE_NAME, ', ' ORDER BY a.POSITION)
+         FROM SYS.PROCEDURE_PARAMETERS a
+         WHERE a.PROCEDURE_OID = p.PROCEDURE_OID),
+        CAST(NULL AS NVARCHAR(5000)),
+        p.PROCEDURE_TYPE,
+        p.SQL_SECURITY,
+        p.IS_VALID` + procedureSource + `
+    FROM SYS.PROCEDURES p
+    WHERE 1 = 1` + procedureFilter + `
+) r
+ORDER BY r.schema_name, r.function_name;`
+}
+
+// getFirebirdFunctionsQuery returns a query for the stored functions and procedures of a
+// Firebird database outside packages; arguments take their type from their domain
+func getFirebirdFunctionsQuery(functionName string, includeSource bool) string {
+	functionSource, procedureSource := "", ""
+	if includeSource {
+		functionSource = `,
+        CAST(fn.RDB$FUNCTION_SOURCE AS VARCHAR(32000)) AS source`
+		procedureSource = `,
+        CAST(p.RDB$PROCEDURE_SOURCE AS VARCHAR(32000))`
+	}
+
+	functionFilter, procedureFilter := "", ""
+	if functionName != "" {
+		functionFilter = fmt.Sprintf(" AND fn.RDB$FUNCTION_NAME = %s", quoteLiteral("firebird", functionName))
+		procedureFilter = fmt.Sprintf(" AND p.RDB$PROCEDURE_NAME = %s", quoteLiteral("firebird", functionName))
+	}
+
+	return `
+SELECT * FROM (
+    SELECT
+        TRIM(fn.RDB$FUNCTION_NAME) AS function_name,
+        'FUNCTION' AS routine_type,
+        (SELECT LIST(TRIM(a.RDB$ARGUMENT_NAME) || ' ' || ` + firebirdTypeExpression("af") + `, ', ')
+         FROM RDB$FUNCTION_ARGUMENTS a
+         JOIN RDB$FIELDS af ON af.RDB$FIELD_NAME = a.RDB$FIELD_SOURCE
+         WHERE a.RDB$FUNCTION_NAME = fn.RDB$FUNCTION_NAME
+         AND a.RDB$PACKAGE_NAME IS NULL
+         AND a.RDB$ARGUMENT_POSITION <> fn.RDB$RETURN_ARGUMENT) AS arguments,
+        (SELECT ` + firebirdTypeExpression("rf") + `
+         FROM RDB$FUNCTION_ARGUMENTS r
+         JOIN RDB$FIELDS rf ON rf.RDB$FIELD_NAME = r.RDB$FIELD_SOURCE
+         WHERE r.RDB$FUNCTION_NAME = fn.RDB$FUNCTION_NAME
+         AND r.RDB$PACKAGE_NAME IS NULL
+         AND r.RDB$ARGUMENT_POSITION = fn.RDB$RETURN_ARGUMENT) AS return_type,
+        COALESCE(TRIM(fn.RDB$ENGINE_NAME), 'PSQL') AS language,
+        CASE WHEN fn.RDB$DETERMINISTIC_FLAG = 1 THEN 'DETERMINISTIC' ELSE 'NOT DETERMINISTIC' END AS determinism,
+        CAST(fn.RDB$DESCRIPTION AS VARCHAR(8191)) AS description` + functionSource + `
+    FROM RDB$FUNCTIONS fn
+    WHERE COALESCE(fn.RDB$SYSTEM_FLAG, 0) = 0
+    AND fn.RDB$PACKAGE_NAME IS NULL` + functionFilter + `
+    UNION ALL
+    SELECT
+        TRIM(p.RDB$PROCEDURE_NAME),
+        CASE WHEN p.RDB$PROCEDURE_TYPE = 1 THEN 'SELECTABLE PROCEDURE' ELSE 'PROCEDURE' END,
+        (SELECT LIST(TRIM(a.RDB$PARAMETER_NAME) || ' ' || ` + firebirdTypeExpression("af") + `, ', ')
+         FROM RDB$PROCEDURE_PARAMETERS a
+         JOIN RDB$FIELDS af ON af.RDB$FIELD_NAME = a.RDB$FIELD_SOURCE
+         WHERE a.RDB$PROCEDURE_NAME = p.RDB$PROCEDURE_NAME
+         AND a.RDB$PACKAGE_NAME IS NULL
+         AND a.RDB$PARAMETER_TYPE = 0),
+        (SELECT LIST(TRIM(a.RDB$PARAMETER_NAME) || ' ' || ` + firebirdTypeExpression("af") + `, ', ')
+         FROM RDB$PROCEDURE_PARAMETERS a
+         JOIN RDB$FIELDS af ON af.RDB$FIELD_NAME = a.RDB$FIELD_SOURCE
+         WHERE a.RDB$PROCEDURE_NAME = p.RDB$PROCEDURE_NAME
+         AND a.RDB$PACKAGE_NAME IS NULL
+         AND a.RDB$PARAMETER_TYPE = 1),
+        COALESCE(TRIM(p.RDB$ENGINE_NAME), 'PSQL'),
+        NULL,
+        CAST(p.RDB$DESCRIPTION AS VARCHAR(8191))` + procedureSource + `
+    FROM RDB$PROCEDURES p
+    WHERE COALESCE(p.RDB$SYSTEM_FLAG, 0) = 0
+    AND p.RDB$PACKAGE_NAME IS NULL` + procedureFilter + `
+) r
+ORDER BY r.function_name;`
+}
+
+// getDatabricksFunctionsQuery returns a query for the Unity Catalog functions of the catalog
+// of the connection; a name may be qualified as schema.function or catalog.schema.function
+func getDatabricksFunctionsQuery(functionName string, includeSource bool) string {
+	// Base query for Databricks functions
+	baseQuery := `
+SELECT
+    r.routine_catalog AS catalog_name,
+    r.routine_schema AS schema_name,
+    r.routine_name AS function_name,
+    r.routine_type,
+    (SELECT concat_ws(', ', transform(array_sort(collect_list(named_struct('position', p.ordinal_position, 'argument', concat_ws(' ', p.parameter_name, p.full_data_type)))), a -> a.argument))
+     FROM system.information_schema.parameters p
+     WHERE p.specific_catalog = r.specific_catalog
+     AND p.specific_schema = r.specific_schema
+     AND p.specific_name = r.specific_name) AS arguments,
+    r.full_data_type AS return_type,
+    coalesce(r.external_language, r.routine_body) AS language,
+    CASE WHEN r.is_deterministic = 'YES' THEN 'DETERMINISTIC' ELSE 'NOT DETERMINISTIC' END AS determinism,
+    r.sql_data_access AS data_access,
+    r.security_type AS security,
+    r.comment AS description`
+
+	if includeSource {
+		baseQuery += `,
+    r.routine_definition AS source`
+	}
+
+	baseQuery += `
+FROM system.information_schema.routines r`
+
+	if functionName != "" {
+		schema, name := splitQualifiedName(functionName)
+		catalog, schema := splitQualifiedName(schema)
+		catalogFilter, schemaFilter := "current_catalog()", "current_schema()"
+		if catalog != "" {
+			catalogFilter = quoteLiteral("databricks", catalog)
+		}
+		if schema != "" {
+			schemaFilter = quoteLiteral("databricks", schema)
+		}
+		baseQuery += fmt.Sprintf(" WHERE r.routine_catalog = %s AND r.routine_schema = %s AND r.routine_name = %s",
+			catalogFilter, schemaFilter, quoteLiteral("databricks", name))
+	} else {
+		baseQuery += " WHERE r.routine_catalog = current_catalog() AND r.routine_schema <> 'information_schema'"
+	}
+
+	baseQuery += `
+ORDER BY r.routine_schema, r.routine_name;`
+
+	return baseQuery
+}
@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func getFunctionsText(t *testing.T, useCase *mockUseCase, params map[string]interface{}) string {
+	result, err := NewGetFunctionsTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	assert.NoError(t, err)
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+}
+
+func TestGetFunctionsListsPostgresRoutines(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{"FROM pg_proc p": {
+			Columns: []string{"schema_name", "function_name", "routine_type", "arguments", "return_type", "language", "volatility", "security", "description", "source"},
+			Rows: [][]interface{}{
+				{"billing", "apply_discount", "FUNCTION", "order_id bigint, pct numeric DEFAULT 10", "numeric", "plpgsql", "VOLATILE", "INVOKER", "Applies a discount", "CREATE OR REPLACE FUNCTION billing.apply_discount"},
+			},
+		}},
+	}
+	text := getFunctionsText(t, useCase, map[string]interface{}{"database": "pg1", "function": "billing.apply_discount", "include_source": true})
+
+	query := useCase.queries[0]
+	assert.Contains(t, query, "n.nspname = 'billing' AND p.proname = 'apply_discount'")
+	assert.Contains(t, query, "pg_get_functiondef(p.oid)")
+	assert.Contains(t, query, "d.deptype = 'e'")
+	assert.Contains(t, text, "# Function billing.apply_discount in Database pg1\n")
+	assert.Contains(t, text, "apply_discount\tFUNCTION\torder_id bigint, pct numeric DEFAULT 10\tnumeric\tplpgsql\tVOLATILE")
+}
+
+func TestGetFunctionsLeavesOutSourceByDefault(t *testing.T) {
+	useCase := &mockUseCase{dbType: "mysql"}
+	text := getFunctionsText(t, useCase, map[string]interface{}{"database": "mysql1"})
+
+	query := useCase.queries[0]
+	assert.Contains(t, query, "r.ROUTINE_SCHEMA = DATABASE()")
+	assert.NotContains(t, query, "ROUTINE_DEFINITION")
+	assert.Contains(t, text, "No user-defined functions or procedures found.")
+
+	assert.NotContains(t, getPostgresFunctionsQuery("", false), "pg_get_functiondef")
+	assert.Contains(t, getMySQLFunctionsQuery("sales.tax", true), "r.ROUTINE_SCHEMA = 'sales' AND r.ROUTINE_NAME = 'tax'")
+}
+
+func TestGetFunctionsReportsEnginesWithoutRoutines(t *testing.T) {
+	for _, dbType := range []string{"sqlite", "spanner", "tidb", "trino"} {
+		useCase := &mockUseCase{dbType: dbType}
+		text := getFunctionsText(t, useCase, map[string]interface{}{"database": "db1"})
+		assert.Contains(t, text, "has no user-defined functions or stored procedures", dbType)
+		assert.Empty(t, useCase.queries, dbType)
+	}
+
+	_, err := NewGetFunctionsTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{}}, "", &mockUseCase{dbType: "postgres"})
+	assert.Error(t, err)
+}
+
+func TestGetFunctionsQueriesPerDialect(t *testing.T) {
+	for dbType, want := range map[string]string{
+		"clickhouse": "origin IN ('SQLUserDefined', 'ExecutableUserDefined') AND name = 'f'",
+		"bigquery":   "FROM INFORMATION_SCHEMA.ROUTINES r WHERE r.routine_name = 'f'",
+		"hana":       "f.FUNCTION_NAME = 'f'",
+		"firebird":   "p.RDB$PROCEDURE_NAME = 'f'",
+		"databricks": "r.routine_catalog = current_catalog() AND r.routine_schema = current_schema() AND r.routine_name = 'f'",
+		"greenplum":  "p.proname = 'f'",
+	} {
+		query, ok := dialects[dbType].FunctionQuery("f", false)
+		assert.True(t, ok, dbType)
+		assert.Contains(t, query, want, dbType)
+	}
+}
@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// GetHypertablesTool handles listing the hypertables of a TimescaleDB database
+type GetHypertablesTool struct {
+	BaseToolType
+}
+
+// NewGetHypertablesTool creates a new get hypertables tool type
+func NewGetHypertablesTool() *GetHypertablesTool {
+	return &GetHypertablesTool{
+		BaseToolType: BaseToolType{
+			name:        "get_hypertables",
+			description: "List the TimescaleDB hypertables of a PostgreSQL database from the timescaledb_information views: time column, chunk interval, chunk counts, compression status with the size before and after compression, and the retention and compression policies, followed by every policy job with its schedule and the outcome of its last run. Warns about failing jobs and about compression enabled without a policy.",
+		},
+	}
+}
+
+// CreateTool creates a get hypertables tool
+func (t *GetHypertablesTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("List TimescaleDB hypertables with chunks, compression and policies"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Only show this hypertable, as name or schema.name (default: every hypertable)"),
+		),
+	)
+}
+
+// HandleRequest handles get hypertables tool requests
+func (t *GetHypertablesTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	tableName := input.optionalString("table", "")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("get_hypertables needs PostgreSQL with the TimescaleDB extension, not %s", dbType)
+	}
+	queries, ok := dialect.HypertableQueries(tableName)
+	if !ok {
+		return nil, fmt.Errorf("get_hypertables needs PostgreSQL with the TimescaleDB extension, not %s", dbType)
+	}
+	version, err := timescaleVersion(ctx, useCase, targetDbID, queries)
+	if err != nil {
+		return nil, err
+	}
+	if version == "" {
+		return nil, fmt.Errorf("the timescaledb extension is not installed in database %s", targetDbID)
+	}
+
+	hypertables, err := useCase.ExecuteQuery(ctx, targetDbID, queries.hypertables, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hypertables: %w", err)
+	}
+	jobs, err := useCase.ExecuteQuery(ctx, targetDbID, queries.jobs, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hypertable policies: %w", err)
+	}
+	logger.Info("Found %d hypertables and %d policy jobs in database %s", len(hypertables.Rows), len(jobs.Rows), targetDbID)
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Hypertables in Database %s\n\nTimescaleDB %s\n\n", targetDbID, version))
+	if len(hypertables.Rows) == 0 {
+		if tableName != "" {
+			response.WriteString(fmt.Sprintf("%s is not a hypertable.\n", tableName))
+		} else {
+			response.WriteString("No hypertables.\n")
+		}
+		return createTextResponse(response.String()), nil
+	}
+
+	var warnings []string
+	response.WriteString("| Hypertable | Time Column | Chunk Interval | Chunks | Compressed Chunks | Compression | Total Size | Before Compression | After Compression | Retention | Compress After |\n")
+	response.WriteString("|------------|-------------|----------------|--------|-------------------|-------------|------------|--------------------|-------------------|-----------|----------------|\n")
+	for _, row := range hypertables.Rows {
+		if len(row) < 11 {
+			continue
+		}
+		cells := make([]string, 11)
+		for i := range cells {
+			cells[i] = valueString(row[i])
+		}
+		response.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		if cells[5] == "enabled" && cells[10] == "" {
+			warnings = append(warnings, fmt.Sprintf("Compression is enabled on %s but no compression policy compresses its chunks; add one with add_compression_policy.", cells[0]))
+		}
+	}
+
+	response.WriteString("\n## Policies\n\n")
+	if len(jobs.Rows) == 0 {
+		response.WriteString("No policy jobs.\n")
+	} else {
+		response.WriteString("| Hypertable | Job | Policy | Schedule | Settings | Last Run | Last Success | Next Start | Failures |\n")
+		response.WriteString("|------------|-----|--------|----------|----------|----------|--------------|------------|----------|\n")
+		for _, row := range jobs.Rows {
+			if len(row) < 10 {
+				continue
+			}
+			hypertable, job, policy := valueString(row[0]), valueString(row[1]), policyName(valueString(row[2]))
+			if !valueBool(row[5]) {
+				policy += " (paused)"
+			}
+			status := valueString(row[6])
+			response.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+				hypertable, job, policy, valueString(row[3]), strings.ReplaceAll(valueString(row[4]), "|", "\\|"),
+				status, valueString(row[7]), valueString(row[8]), valueString(row[9])))
+			if status == "Failed" {
+				warnings = append(warnings, fmt.Sprintf("The last run of job %s (%s on %s) failed; see timescaledb_information.job_errors for the cause.", job, policy, hypertable))
+			}
+		}
+	}
+
+	if len(warnings) > 0 {
+		response.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			response.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+	return createTextResponse(response.String()), nil
+}
@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+// timescaleUseCase is a PostgreSQL database with TimescaleDB and one compressed hypertable
+func timescaleUseCase() *mockUseCase {
+	return &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"extname = 'timescaledb'": {Columns: []string{"extversion"}, Rows: [][]interface{}{{"2.14.2"}}},
+			"timescaledb_information.hypertables h": {
+				Columns: []string{"hypertable", "time_column", "chunk_interval", "chunks", "compressed_chunks", "compression", "total_size", "before_compression", "after_compression", "retention_policy", "compression_policy"},
+				Rows: [][]interface{}{
+					{"public.metrics", "time", "7 days", int64(52), int64(48), "enabled", "1200 MB", "9800 MB", "1100 MB", "1 year", "7 days"},
+					{"public.events", "created_at", "1 day", int64(30), int64(0), "enabled", "300 MB", nil, nil, nil, nil},
+				},
+			},
+			"timescaledb_information.job_stats": {Rows: [][]interface{}{
+				{"public.metrics", int64(1000), "policy_retention", "1 day", `{"drop_after": "1 year", "hypertable_id": 1}`, true, "Success", "2024-05-01 00:00:00+00", "2024-05-02 00:00:00+00", int64(0)},
+				{"public.metrics", int64(1001), "policy_compression", "12:00:00", `{"compress_after": "7 days", "hypertable_id": 1}`, false, "Failed", "2024-04-20 00:00:00+00", "2024-05-01 12:00:00+00", int64(3)},
+			}},
+		},
+	}
+}
+
+func TestGetHypertablesTool(t *testing.T) {
+	useCase := timescaleUseCase()
+	result, err := NewGetHypertablesTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "tsdb"},
+	}, "tsdb", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+
+	assert.Contains(t, text, "TimescaleDB 2.14.2")
+	assert.Contains(t, text, "| public.metrics | time | 7 days | 52 | 48 | enabled | 1200 MB | 9800 MB | 1100 MB | 1 year | 7 days |")
+	assert.Contains(t, text, "| public.metrics | 1000 | retention | 1 day | {\"drop_after\": \"1 year\", \"hypertable_id\": 1} | Success |")
+	assert.Contains(t, text, "| public.metrics | 1001 | compression (paused) | 12:00:00 |")
+	assert.Contains(t, text, "Compression is enabled on public.events but no compression policy compresses its chunks")
+	assert.Contains(t, text, "The last run of job 1001 (compression (paused) on public.metrics) failed")
+
+	// A table filter restricts both views
+	useCase.queries = nil
+	_, err = NewGetHypertablesTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "tsdb", "table": "public.metrics"},
+	}, "tsdb", useCase)
+	assert.NoError(t, err)
+	assert.Contains(t, useCase.queries[1], "WHERE h.hypertable_schema = 'public' AND h.hypertable_name = 'metrics'")
+	assert.Contains(t, useCase.queries[2], "j.hypertable_name IS NOT NULL AND j.hypertable_schema = 'public' AND j.hypertable_name = 'metrics'")
+
+	_, err = NewGetHypertablesTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1"},
+	}, "pg1", &mockUseCase{dbType: "postgres"})
+	assert.EqualError(t, err, "the timescaledb extension is not installed in database pg1")
+
+	_, err = NewGetHypertablesTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "my1"},
+	}, "my1", &mockUseCase{dbType: "mysql"})
+	assert.ErrorContains(t, err, "needs PostgreSQL with the TimescaleDB extension")
+}
+
+func TestTableStatsHypertableSection(t *testing.T) {
+	text := tableStatsText(t, timescaleUseCase(), map[string]interface{}{"database": "tsdb", "table": "metrics"})
+	assert.Contains(t, text, "## hypertable\n")
+	assert.Contains(t, text, "1 year")
+
+	useCase := timescaleUseCase()
+	tableStatsText(t, useCase, map[string]interface{}{"database": "tsdb", "table": "metrics", "sections": []interface{}{"hypertable"}})
+	assert.Len(t, useCase.queries, 2)
+	assert.Contains(t, useCase.queries[1], "h.hypertable_name = 'metrics'")
+}
@@ -45,26 +45,12 @@ func (t *GetIndexesTool) CreateTool(name string, dbID string) interface{} {
 
 // HandleRequest handles get indexes tool requests
 func (t *GetIndexesTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
-	// Extract database ID from parameters
-	targetDbID, ok := request.Parameters["database"].(string)
-	if !ok {
-		return nil, fmt.Errorf("database parameter must be a string")
-	}
-
-	// Extract table name (optional)
-	tableName := ""
-	if request.Parameters["table"] != nil {
-		if tableParam, ok := request.Parameters["table"].(string); ok {
-			tableName = tableParam
-		}
-	}
-
-	// Extract detailed flag
-	detailed := false
-	if request.Parameters["detailed"] != nil {
-		if detailedParam, ok := request.Parameters["detailed"].(bool); ok {
-			detailed = detailedParam
-		}
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	tableName := input.optionalString("table", "")
+	detailed := input.optionalBool("detailed", false)
+	if err := input.err(); err != nil {
+		return nil, err
 	}
 
 	logger.Info("Getting indexes for database %s, table %s (detailed: %v)", targetDbID, tableName, detailed)
@@ -75,16 +61,12 @@ func (t *GetIndexesTool) HandleRequest(ctx context.Context, request server.ToolC
 		return nil, fmt.Errorf("failed to get database type: %w", err)
 	}
 
-	// Define query based on database type
-	var query string
-	switch strings.ToLower(dbType) {
-	case "postgres":
-		query = getPostgresIndexesQuery(tableName, detailed)
-	case "mysql":
-		query = getMySQLIndexesQuery(tableName, detailed)
-	default:
+	// Build query in the dialect of the database
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
 		return nil, fmt.Errorf("unsupported database type for indexes: %s", dbType)
 	}
+	query := dialect.IndexQuery(tableName, detailed)
 
 	// Execute the query
 	result, err := useCase.ExecuteQuery(ctx, targetDbID, query, nil)
@@ -99,7 +81,7 @@ func (t *GetIndexesTool) HandleRequest(ctx context.Context, request server.ToolC
 	} else {
 		response.WriteString(fmt.Sprintf("# Indexes for Table %s in Database %s\n\n", tableName, targetDbID))
 	}
-	response.WriteString(result)
+	response.WriteString(formatQueryResult(result))
 
 	return createTextResponse(response.String()), nil
 }
@@ -200,3 +182,329 @@ ORDER BY table_name, index_name;`
 
 	return baseQuery
 }
+
+// getSQLiteIndexesQuery returns a query for SQLite indexes
+func getSQLiteIndexesQuery(tableName string, detailed bool) string {
+	// Base query for SQLite indexes; expression columns have no name
+	baseQuery := `
+SELECT 
+    m.name AS table_name,
+    il.name AS index_name,
+    'btree' AS index_type,
+    CASE 
+        WHEN il.origin = 'pk' THEN 'PRIMARY KEY'
+        WHEN il."unique" THEN 'UNIQUE'
+        ELSE 'INDEX'
+    END AS constraint_type,
+    (SELECT group_concat(COALESCE(ii.name, '<expression>'), ', ')
+     FROM (SELECT name FROM pragma_index_info(il.name) ORDER BY seqno) ii) AS column_names`
+
+	if detailed {
+		baseQuery += `,
+    CASE il.origin WHEN 'c' THEN 'CREATE INDEX' WHEN 'u' THEN 'UNIQUE constraint' ELSE 'PRIMARY KEY constraint' END AS created_by,
+    s.sql AS index_definition,
+    CASE WHEN il.partial THEN 'Yes' ELSE 'No' END AS is_partial`
+	}
+
+	baseQuery += `
+FROM sqlite_master m
+JOIN pragma_index_list(m.name) il
+LEFT JOIN sqlite_master s ON s.type = 'index' AND s.name = il.name
+WHERE m.type = 'table'`
+
+	if tableName != "" {
+		// Escape table name for safety
+		safeTableName := strings.Replace(tableName, "'", "''", -1)
+		baseQuery += fmt.Sprintf(" AND m.name = '%s'", safeTableName)
+	}
+
+	baseQuery += `
+ORDER BY m.name, il.name;`
+
+	return baseQuery
+}
+
+// getClickHouseIndexesQuery returns a query for ClickHouse indexes: the sparse primary index
+// of each MergeTree table and its data skipping indexes
+func getClickHouseIndexesQuery(tableName string, detailed bool) string {
+	// Base query for ClickHouse indexes; the primary index does not enforce uniqueness
+	baseQuery := `
+SELECT 
+    table_name,
+    index_name,
+    index_type,
+    constraint_type,
+    column_names`
+
+	if detailed {
+		baseQuery += `,
+    granularity,
+    index_definition`
+	}
+
+	baseQuery += `
+FROM (
+    SELECT 
+        name AS table_name,
+        'PRIMARY' AS index_name,
+        'primary' AS index_type,
+        'PRIMARY KEY' AS constraint_type,
+        primary_key AS column_names,
+        CAST(NULL AS Nullable(UInt64)) AS granularity,
+        concat('ORDER BY ', sorting_key) AS index_definition
+    FROM system.tables
+    WHERE database = currentDatabase()
+    AND primary_key != ''
+    UNION ALL
+    SELECT 
+        table,
+        name,
+        type,
+        'INDEX',
+        expr,
+        CAST(granularity AS Nullable(UInt64)),
+        concat('INDEX ', name, ' ', expr, ' TYPE ', type_full, ' GRANULARITY ', toString(granularity))
+    FROM system.data_skipping_indices
+    WHERE database = currentDatabase()
+) AS i`
+
+	if tableName != "" {
+		baseQuery += fmt.Sprintf(" WHERE i.table_name = %s", quoteLiteral("clickhouse", tableName))
+	}
+
+	baseQuery += `
+ORDER BY i.table_name, i.index_name;`
+
+	return baseQuery
+}
+
+// getBigQueryIndexesQuery returns a query for BigQuery indexes: the clustering of each table,
+// which prunes blocks like an index, and its search indexes
+func getBigQueryIndexesQuery(tableName string, detailed bool) string {
+	// Base query for BigQuery indexes
+	baseQuery := `
+SELECT 
+    table_name,
+    index_name,
+    index_type,
+    constraint_type,
+    column_names`
+
+	if detailed {
+		baseQuery += `,
+    index_status,
+    index_definition`
+	}
+
+	baseQuery += `
+FROM (
+    SELECT 
+        table_name,
+        'CLUSTERING' AS index_name,
+        'clustering' AS index_type,
+        'INDEX' AS constraint_type,
+        STRING_AGG(column_name, ', ' ORDER BY clustering_ordinal_position) AS column_names,
+        CAST(NULL AS STRING) AS index_status,
+        CAST(NULL AS STRING) AS index_definition
+    FROM INFORMATION_SCHEMA.COLUMNS
+    WHERE clustering_ordinal_position IS NOT NULL
+    GROUP BY table_name
+    UNION ALL
+    SELECT 
+        i.table_name,
+        i.index_name,
+        'search',
+        'INDEX',
+        STRING_AGG(c.index_column_name, ', '),
+        i.index_status,
+        i.ddl
+    FROM INFORMATION_SCHEMA.SEARCH_INDEXES i
+    LEFT JOIN INFORMATION_SCHEMA.SEARCH_INDEX_COLUMNS c
+        ON c.table_name = i.table_name
+        AND c.index_name = i.index_name
+    GROUP BY i.table_name, i.index_name, i.index_status, i.ddl
+) AS i`
+
+	if tableName != "" {
+		baseQuery += fmt.Sprintf(" WHERE i.table_name = %s", quoteLiteral("bigquery", tableName))
+	}
+
+	baseQuery += `
+ORDER BY i.table_name, i.index_name;`
+
+	return baseQuery
+}
+
+// getSpannerIndexesQuery returns a query for Spanner indexes, the primary key of each table
+// included; the indexes Spanner manages itself to back foreign keys are left out
+func getSpannerIndexesQuery(tableName string, detailed bool) string {
+	// Base query for Spanner indexes; STORING columns have no ordinal position
+	baseQuery := `
+SELECT 
+    i.table_schema,
+    i.table_name,
+    i.index_name,
+    i.index_type,
+    CASE
+        WHEN i.index_type = 'PRIMARY_KEY' THEN 'PRIMARY KEY'
+        WHEN i.is_unique THEN 'UNIQUE'
+        ELSE 'INDEX'
+    END AS constraint_type,
+    STRING_AGG(IF(c.ordinal_position IS NULL, NULL,
+        CONCAT(c.column_name, IF(c.column_ordering = 'DESC', ' DESC', ''))), ', ' ORDER BY c.ordinal_position) AS column_names`
+
+	if detailed {
+		baseQuery += `,
+    STRING_AGG(IF(c.ordinal_position IS NULL, c.column_name, NULL), ', ') AS storing_columns,
+    NULLIF(i.parent_table_name, '') AS interleaved_in,
+    i.is_null_filtered,
+    i.index_state`
+	}
+
+	baseQuery += `
+FROM INFORMATION_SCHEMA.INDEXES i
+JOIN INFORMATION_SCHEMA.INDEX_COLUMNS c
+    ON c.table_schema = i.table_schema
+    AND c.table_name = i.table_name
+    AND c.index_name = i.index_name
+WHERE i.table_schema NOT IN ('INFORMATION_SCHEMA', 'SPANNER_SYS')
+AND NOT i.spanner_is_managed`
+
+	if tableName != "" {
+		baseQuery += " AND " + spannerTableFilter("i", tableName)
+	}
+
+	baseQuery += `
+GROUP BY i.table_schema, i.table_name, i.index_name, i.index_type, i.is_unique, i.parent_table_name, i.is_null_filtered, i.index_state
+ORDER BY i.table_schema, i.table_name, i.index_name;`
+
+	return baseQuery
+}
+
+// getHANAIndexesQuery returns a query for SAP HANA indexes of the user schemas, the
+// primary key and unique constraints included
+func getHANAIndexesQuery(tableName string, detailed bool) string {
+	// Base query for HANA indexes; INDEX_TYPE names the structure, such as CPBTREE or INVERTED VALUE
+	baseQuery := `
+SELECT 
+    i.SCHEMA_NAME AS table_schema,
+    i.TABLE_NAME AS table_name,
+    i.INDEX_NAME AS index_name,
+    i.INDEX_TYPE AS index_type,
+    COALESCE(i."CONSTRAINT", 'INDEX') AS constraint_type,
+    STRING_AGG(c.COLUMN_NAME || CASE WHEN c.ASCENDING_ORDER = 'FALSE' THEN ' DESC' ELSE '' END, ', ' ORDER BY c.POSITION) AS column_names`
+
+	if detailed {
+		baseQuery += `,
+    i.CREATE_TIME AS created_at`
+	}
+
+	baseQuery += `
+FROM SYS.INDEXES i
+JOIN SYS.INDEX_COLUMNS c
+    ON c.SCHEMA_NAME = i.SCHEMA_NAME
+    AND c.TABLE_NAME = i.TABLE_NAME
+    AND c.INDEX_NAME = i.INDEX_NAME
+WHERE ` + hanaUserSchemaFilter("i")
+
+	if tableName != "" {
+		baseQuery += " AND " + hanaTableFilter("i", tableName)
+	}
+
+	baseQuery += `
+GROUP BY i.SCHEMA_NAME, i.TABLE_NAME, i.INDEX_NAME, i.INDEX_TYPE, i."CONSTRAINT", i.CREATE_TIME
+ORDER BY i.SCHEMA_NAME, i.TABLE_NAME, i.INDEX_NAME;`
+
+	return baseQuery
+}
+
+// getFirebirdIndexesQuery returns a query for Firebird indexes of the user tables, with the
+// constraint each one enforces; expression indexes have no columns and show their expression
+func getFirebirdIndexesQuery(tableName string, detailed bool) string {
+	// Base query for Firebird indexes
+	baseQuery := `
+SELECT 
+    TRIM(i.RDB$RELATION_NAME) AS table_name,
+    TRIM(i.RDB$INDEX_NAME) AS index_name,
+    CASE WHEN i.RDB$INDEX_TYPE = 1 THEN 'DESCENDING' ELSE 'ASCENDING' END AS index_type,
+    COALESCE(TRIM(c.RDB$CONSTRAINT_TYPE), CASE WHEN i.RDB$UNIQUE_FLAG = 1 THEN 'UNIQUE' ELSE 'INDEX' END) AS constraint_type,
+    COALESCE(
+        (SELECT LIST(TRIM(s.RDB$FIELD_NAME), ', ')
+         FROM RDB$INDEX_SEGMENTS s
+         WHERE s.RDB$INDEX_NAME = i.RDB$INDEX_NAME),
+        CAST(i.RDB$EXPRESSION_SOURCE AS VARCHAR(1024))) AS column_names`
+
+	if detailed {
+		baseQuery += `,
+    CASE WHEN i.RDB$INDEX_INACTIVE = 1 THEN 'INACTIVE' ELSE 'ACTIVE' END AS status,
+    i.RDB$STATISTICS AS selectivity,
+    TRIM(i.RDB$FOREIGN_KEY) AS referenced_index`
+	}
+
+	baseQuery += `
+FROM RDB$INDICES i
+LEFT JOIN RDB$RELATION_CONSTRAINTS c ON c.RDB$INDEX_NAME = i.RDB$INDEX_NAME
+WHERE COALESCE(i.RDB$SYSTEM_FLAG, 0) = 0`
+
+	if tableName != "" {
+		baseQuery += fmt.Sprintf(" AND i.RDB$RELATION_NAME = %s", quoteLiteral("firebird", tableName))
+	}
+
+	baseQuery += `
+ORDER BY i.RDB$RELATION_NAME, i.RDB$INDEX_NAME;`
+
+	return baseQuery
+}
+
+// getDatabricksIndexesQuery returns a query for the partitioning of Databricks tables, which
+// prunes files like an index; Delta tables have no other indexes
+func getDatabricksIndexesQuery(tableName string, detailed bool) string {
+	// Base query for Databricks indexes
+	baseQuery := `
+SELECT 
+    c.table_catalog,
+    c.table_schema,
+    c.table_name,
+    'PARTITIONING' AS index_name,
+    'partition' AS index_type,
+    'INDEX' AS constraint_type,
+    concat_ws(', ', transform(array_sort(collect_list(struct(c.partition_index, c.column_name))), p -> p.column_name)) AS column_names`
+
+	if detailed {
+		baseQuery += `,
+    first(t.data_source_format) AS data_source_format`
+	}
+
+	baseQuery += `
+FROM system.information_schema.columns c
+JOIN system.information_schema.tables t
+    ON t.table_catalog = c.table_catalog
+    AND t.table_schema = c.table_schema
+    AND t.table_name = c.table_name
+WHERE c.partition_index IS NOT NULL
+AND ` + databricksCatalogFilter("c", tableName) + `
+GROUP BY c.table_catalog, c.table_schema, c.table_name
+ORDER BY c.table_schema, c.table_name;`
+
+	return baseQuery
+}
+
+// getTrinoIndexesQuery returns a query with the shape of the index listing and no rows;
+// Trino connectors do not expose indexes
+func getTrinoIndexesQuery(tableName string, detailed bool) string {
+	baseQuery := `
+SELECT 
+    '' AS table_name,
+    '' AS index_name,
+    '' AS index_type,
+    '' AS constraint_type,
+    '' AS column_names`
+
+	if detailed {
+		baseQuery += `,
+    '' AS index_definition`
+	}
+
+	return baseQuery + `
+WHERE false;`
+}
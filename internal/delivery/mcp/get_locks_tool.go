@@ -0,0 +1,359 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// GetLocksTool handles reporting held locks and the sessions waiting on them
+type GetLocksTool struct {
+	BaseToolType
+}
+
+// NewGetLocksTool creates a new get locks tool type
+func NewGetLocksTool() *GetLocksTool {
+	return &GetLocksTool{
+		BaseToolType: BaseToolType{
+			name:        "get_locks",
+			description: "Report the current locks of a database: which sessions are blocked, what they wait for, which session blocks them and what that session is running, followed by the locks each session holds. Reads pg_locks with pg_stat_activity and pg_blocking_pids on PostgreSQL, and the InnoDB locks of performance_schema.data_locks and data_lock_waits on MySQL 8. Sessions that block others while idle in a transaction are called out with the statement that ends them.",
+		},
+	}
+}
+
+// CreateTool creates a get locks tool
+func (t *GetLocksTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Show held locks, blocked sessions and the queries blocking them"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithBoolean("blocked_only",
+			tools.Description("Only report lock waits, leaving out the locks that block no one (default: false)"),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of held locks to list (default: 100)"),
+		),
+	)
+}
+
+// HandleRequest handles get locks tool requests
+func (t *GetLocksTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	blockedOnly := input.optionalBool("blocked_only", false)
+	limit := input.intBetween("limit", 100, 1, 10000)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Getting locks for database %s, blocked_only %v", targetDbID, blockedOnly)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for locks: %s", dbType)
+	}
+	waitsQuery, heldQuery, ok := dialect.LockQueries()
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for locks: %s", dbType)
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, targetDbID, waitsQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lock waits: %w", err)
+	}
+	waits := readLockWaits(result.Rows)
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Locks in Database %s\n\n", targetDbID))
+	writeLockWaits(&response, waits)
+
+	if !blockedOnly {
+		result, err = useCase.ExecuteQuery(ctx, targetDbID, heldQuery, []interface{}{limit})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get held locks: %w", err)
+		}
+		response.WriteString("\n## Locks Held\n\n")
+		writeHeldLocks(&response, readHeldLocks(result.Rows), limit)
+	}
+
+	if warnings := lockWarnings(dialect, waits); len(warnings) > 0 {
+		response.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			response.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// lockWait is a session waiting for a lock that another session holds
+type lockWait struct {
+	PID         int64
+	User        string
+	WaitSeconds int64
+	Mode        string
+	Object      string
+	Query       string
+
+	BlockerPID         int64
+	BlockerUser        string
+	BlockerState       string
+	BlockerXactSeconds int64
+	BlockerQuery       string
+}
+
+// heldLock is a group of locks a session holds or waits for on one object
+type heldLock struct {
+	PID         int64
+	User        string
+	State       string
+	LockType    string
+	Mode        string
+	Granted     bool
+	Object      string
+	Count       int64
+	XactSeconds int64
+	Query       string
+}
+
+// getPostgresLockWaitsQuery returns a query listing each blocked session once per session
+// blocking it; a session waiting in a queue is blocked both by the holder and by the sessions
+// queued ahead of it
+func getPostgresLockWaitsQuery() string {
+	return `
+SELECT
+    w.pid,
+    w.usename,
+    COALESCE(EXTRACT(EPOCH FROM now() - w.query_start)::bigint, 0) AS wait_seconds,
+    l.mode,
+    CASE
+        WHEN l.relation IS NOT NULL THEN l.relation::regclass::text
+        WHEN l.transactionid IS NOT NULL THEN 'transaction ' || l.transactionid
+        ELSE l.locktype
+    END AS waiting_for,
+    left(w.query, 200) AS blocked_query,
+    b.pid AS blocking_pid,
+    b.usename AS blocking_user,
+    b.state AS blocking_state,
+    COALESCE(EXTRACT(EPOCH FROM now() - b.xact_start)::bigint, 0) AS blocking_xact_seconds,
+    left(b.query, 200) AS blocking_query
+FROM pg_stat_activity w
+JOIN pg_locks l ON l.pid = w.pid AND NOT l.granted
+CROSS JOIN LATERAL unnest(pg_blocking_pids(w.pid)) AS bp(pid)
+JOIN pg_stat_activity b ON b.pid = bp.pid
+WHERE w.datname = current_database()
+ORDER BY wait_seconds DESC, w.pid, b.pid;`
+}
+
+// getPostgresHeldLocksQuery returns a query listing the locks of the other sessions of the
+// database. The lock every transaction takes on its own virtual transaction ID is left out.
+func getPostgresHeldLocksQuery() string {
+	return `
+SELECT
+    a.pid,
+    a.usename,
+    a.state,
+    l.locktype,
+    l.mode,
+    l.granted,
+    CASE
+        WHEN l.relation IS NOT NULL THEN l.relation::regclass::text
+        WHEN l.transactionid IS NOT NULL THEN 'transaction ' || l.transactionid
+        ELSE l.locktype
+    END AS object,
+    count(*) AS locks,
+    COALESCE(EXTRACT(EPOCH FROM now() - a.xact_start)::bigint, 0) AS xact_seconds,
+    left(a.query, 200) AS query
+FROM pg_locks l
+JOIN pg_stat_activity a ON a.pid = l.pid
+WHERE a.pid <> pg_backend_pid()
+  AND a.datname = current_database()
+  AND NOT (l.locktype = 'virtualxid' AND l.granted)
+GROUP BY a.pid, a.usename, a.state, l.locktype, l.mode, l.granted, 7, a.xact_start, a.query
+ORDER BY xact_seconds DESC, a.pid, l.granted
+LIMIT $1;`
+}
+
+// getMySQLLockWaitsQuery returns a query listing the InnoDB lock waits with the transactions
+// on both sides
+func getMySQLLockWaitsQuery() string {
+	return `
+SELECT
+    rt.PROCESSLIST_ID,
+    rt.PROCESSLIST_USER,
+    COALESCE(TIMESTAMPDIFF(SECOND, r.trx_wait_started, NOW()), 0) AS wait_seconds,
+    rl.LOCK_MODE,
+    CONCAT(rl.OBJECT_SCHEMA, '.', rl.OBJECT_NAME, IFNULL(CONCAT(' (', rl.INDEX_NAME, ')'), '')) AS waiting_for,
+    LEFT(COALESCE(r.trx_query, rt.PROCESSLIST_INFO, ''), 200) AS blocked_query,
+    bt.PROCESSLIST_ID AS blocking_id,
+    bt.PROCESSLIST_USER AS blocking_user,
+    COALESCE(NULLIF(bt.PROCESSLIST_STATE, ''), bt.PROCESSLIST_COMMAND) AS blocking_state,
+    COALESCE(TIMESTAMPDIFF(SECOND, b.trx_started, NOW()), 0) AS blocking_xact_seconds,
+    LEFT(COALESCE(b.trx_query, bt.PROCESSLIST_INFO, ''), 200) AS blocking_query
+FROM performance_schema.data_lock_waits w
+JOIN performance_schema.data_locks rl ON rl.ENGINE_LOCK_ID = w.REQUESTING_ENGINE_LOCK_ID
+JOIN performance_schema.threads rt ON rt.THREAD_ID = w.REQUESTING_THREAD_ID
+JOIN performance_schema.threads bt ON bt.THREAD_ID = w.BLOCKING_THREAD_ID
+LEFT JOIN information_schema.innodb_trx r ON r.trx_id = w.REQUESTING_ENGINE_TRANSACTION_ID
+LEFT JOIN information_schema.innodb_trx b ON b.trx_id = w.BLOCKING_ENGINE_TRANSACTION_ID
+ORDER BY wait_seconds DESC, rt.PROCESSLIST_ID, bt.PROCESSLIST_ID;`
+}
+
+// getMySQLHeldLocksQuery returns a query listing the InnoDB locks of the other connections,
+// with the record locks of a transaction on one index counted together
+func getMySQLHeldLocksQuery() string {
+	return `
+SELECT
+    t.PROCESSLIST_ID,
+    t.PROCESSLIST_USER,
+    COALESCE(NULLIF(t.PROCESSLIST_STATE, ''), t.PROCESSLIST_COMMAND) AS state,
+    l.LOCK_TYPE,
+    l.LOCK_MODE,
+    l.LOCK_STATUS = 'GRANTED' AS granted,
+    CONCAT(l.OBJECT_SCHEMA, '.', l.OBJECT_NAME, IFNULL(CONCAT(' (', l.INDEX_NAME, ')'), '')) AS object,
+    COUNT(*) AS locks,
+    COALESCE(TIMESTAMPDIFF(SECOND, MIN(x.trx_started), NOW()), 0) AS xact_seconds,
+    LEFT(COALESCE(MIN(x.trx_query), t.PROCESSLIST_INFO, ''), 200) AS query
+FROM performance_schema.data_locks l
+JOIN performance_schema.threads t ON t.THREAD_ID = l.THREAD_ID
+LEFT JOIN information_schema.innodb_trx x ON x.trx_id = l.ENGINE_TRANSACTION_ID
+WHERE t.PROCESSLIST_ID <> CONNECTION_ID()
+GROUP BY t.PROCESSLIST_ID, t.PROCESSLIST_USER, state, l.LOCK_TYPE, l.LOCK_MODE, granted, object, t.PROCESSLIST_INFO
+ORDER BY xact_seconds DESC, t.PROCESSLIST_ID, granted
+LIMIT ?;`
+}
+
+// readLockWaits reads the rows of a lock waits query
+func readLockWaits(rows [][]interface{}) []lockWait {
+	waits := make([]lockWait, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 11 {
+			continue
+		}
+		waits = append(waits, lockWait{
+			PID:                valueInt64(row[0]),
+			User:               valueString(row[1]),
+			WaitSeconds:        valueInt64(row[2]),
+			Mode:               valueString(row[3]),
+			Object:             valueString(row[4]),
+			Query:              valueString(row[5]),
+			BlockerPID:         valueInt64(row[6]),
+			BlockerUser:        valueString(row[7]),
+			BlockerState:       valueString(row[8]),
+			BlockerXactSeconds: valueInt64(row[9]),
+			BlockerQuery:       valueString(row[10]),
+		})
+	}
+	return waits
+}
+
+// readHeldLocks reads the rows of a held locks query
+func readHeldLocks(rows [][]interface{}) []heldLock {
+	locks := make([]heldLock, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 10 {
+			continue
+		}
+		locks = append(locks, heldLock{
+			PID:         valueInt64(row[0]),
+			User:        valueString(row[1]),
+			State:       valueString(row[2]),
+			LockType:    valueString(row[3]),
+			Mode:        valueString(row[4]),
+			Granted:     valueBool(row[5]),
+			Object:      valueString(row[6]),
+			Count:       valueInt64(row[7]),
+			XactSeconds: valueInt64(row[8]),
+			Query:       valueString(row[9]),
+		})
+	}
+	return locks
+}
+
+// writeLockWaits renders the blocked sessions next to the sessions blocking them
+func writeLockWaits(sb *strings.Builder, waits []lockWait) {
+	sb.WriteString("## Blocked Sessions\n\n")
+	if len(waits) == 0 {
+		sb.WriteString("No session is waiting for a lock.\n")
+		return
+	}
+	sb.WriteString("| Blocked | User | Waiting | Wants | On | Blocked query | Blocked by | User | State | Transaction age | Blocking query |\n")
+	sb.WriteString("|---------|------|---------|-------|----|---------------|------------|------|-------|-----------------|----------------|\n")
+	for _, w := range waits {
+		sb.WriteString(fmt.Sprintf("| %d | %s | %ds | %s | %s | %s | %d | %s | %s | %ds | %s |\n",
+			w.PID, w.User, w.WaitSeconds, w.Mode, w.Object, queryCell(w.Query),
+			w.BlockerPID, w.BlockerUser, w.BlockerState, w.BlockerXactSeconds, queryCell(w.BlockerQuery)))
+	}
+}
+
+// writeHeldLocks renders the locks sessions hold or wait for
+func writeHeldLocks(sb *strings.Builder, locks []heldLock, limit int) {
+	if len(locks) == 0 {
+		sb.WriteString("No other session holds a lock.\n")
+		return
+	}
+	sb.WriteString("| Session | User | State | Lock type | Mode | Granted | Object | Locks | Transaction age | Query |\n")
+	sb.WriteString("|---------|------|-------|-----------|------|---------|--------|-------|-----------------|-------|\n")
+	for _, l := range locks {
+		sb.WriteString(fmt.Sprintf("| %d | %s | %s | %s | %s | %s | %s | %d | %ds | %s |\n",
+			l.PID, l.User, l.State, l.LockType, l.Mode, yesNo(l.Granted), l.Object, l.Count, l.XactSeconds, queryCell(l.Query)))
+	}
+	if len(locks) == limit {
+		sb.WriteString(fmt.Sprintf("\nShowing the first %d groups of locks; raise limit to see more.\n", limit))
+	}
+}
+
+// queryCell fits a query into a table cell
+func queryCell(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if query == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(query, "|", "\\|")
+}
+
+// lockWarnings points out the sessions at the head of lock chains, the ones blocking others
+// without waiting themselves, and how to end those that sit idle in a transaction
+func lockWarnings(dialect Dialect, waits []lockWait) []string {
+	waiting := make(map[int64]bool)
+	for _, w := range waits {
+		waiting[w.PID] = true
+	}
+	var order []int64
+	blocked := make(map[int64]map[int64]bool)
+	blockers := make(map[int64]lockWait)
+	for _, w := range waits {
+		if waiting[w.BlockerPID] {
+			continue
+		}
+		if blocked[w.BlockerPID] == nil {
+			blocked[w.BlockerPID] = make(map[int64]bool)
+			blockers[w.BlockerPID] = w
+			order = append(order, w.BlockerPID)
+		}
+		blocked[w.BlockerPID][w.PID] = true
+	}
+
+	var warnings []string
+	for _, pid := range order {
+		w := blockers[pid]
+		warning := fmt.Sprintf("Session %d (%s) blocks %d session(s) without waiting itself", pid, w.BlockerUser, len(blocked[pid]))
+		if dialect.SessionIdleInTransaction(w.BlockerState) {
+			kill, _ := dialect.KillSessionStatement(pid, true)
+			warning += fmt.Sprintf("; it has sat idle in a transaction opened %ds ago, so commit or roll it back, or end it with %s", w.BlockerXactSeconds, kill)
+		}
+		warnings = append(warnings, warning+".")
+	}
+	return warnings
+}
@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func getLocksText(t *testing.T, useCase *mockUseCase, params map[string]interface{}) string {
+	result, err := NewGetLocksTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	assert.NoError(t, err)
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+}
+
+func TestGetLocksReportsPostgresBlockers(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"pg_blocking_pids(w.pid)": {Rows: [][]interface{}{
+				{int64(201), "app", int64(42), "RowExclusiveLock", "public.orders", "UPDATE orders\n   SET status = 'paid' WHERE id = 7", int64(100), "admin", "idle in transaction", int64(300), "ALTER TABLE orders ADD COLUMN note text"},
+				{int64(202), "app", int64(12), "AccessShareLock", "public.orders", "SELECT * FROM orders", int64(100), "admin", "idle in transaction", int64(300), "ALTER TABLE orders ADD COLUMN note text"},
+				{int64(202), "app", int64(12), "AccessShareLock", "public.orders", "SELECT * FROM orders", int64(201), "app", "active", int64(45), "UPDATE orders SET status = 'paid' WHERE id = 7"},
+			}},
+			"FROM pg_locks l\nJOIN pg_stat_activity a": {Rows: [][]interface{}{
+				{int64(100), "admin", "idle in transaction", "relation", "AccessExclusiveLock", true, "public.orders", int64(1), int64(300), "ALTER TABLE orders ADD COLUMN note text"},
+			}},
+		},
+	}
+	text := getLocksText(t, useCase, map[string]interface{}{"database": "pg1", "limit": float64(1)})
+
+	assert.Len(t, useCase.queries, 2)
+	assert.Contains(t, text, "| 201 | app | 42s | RowExclusiveLock | public.orders | UPDATE orders SET status = 'paid' WHERE id = 7 | 100 | admin | idle in transaction | 300s | ALTER TABLE orders ADD COLUMN note text |")
+	assert.Contains(t, text, "\n## Locks Held\n\n")
+	assert.Contains(t, text, "| 100 | admin | idle in transaction | relation | AccessExclusiveLock | yes | public.orders | 1 | 300s |")
+	assert.Contains(t, text, "Showing the first 1 groups of locks")
+	assert.Contains(t, text, "- Session 100 (admin) blocks 2 session(s) without waiting itself; it has sat idle in a transaction opened 300s ago, so commit or roll it back, or end it with SELECT pg_terminate_backend(100).\n")
+	assert.NotContains(t, text, "Session 201 (app) blocks")
+}
+
+func TestGetLocksReportsMySQLWaits(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"performance_schema.data_lock_waits": {Rows: [][]interface{}{
+				{int64(12), "app", int64(8), "X,REC_NOT_GAP", "shop.orders (PRIMARY)", "UPDATE orders SET total = 0 WHERE id = 1", int64(9), "batch", "Sleep", int64(120), ""},
+			}},
+		},
+	}
+	text := getLocksText(t, useCase, map[string]interface{}{"database": "mysql1", "blocked_only": true})
+
+	assert.Len(t, useCase.queries, 1)
+	assert.Contains(t, text, "| 12 | app | 8s | X,REC_NOT_GAP | shop.orders (PRIMARY) | UPDATE orders SET total = 0 WHERE id = 1 | 9 | batch | Sleep | 120s | - |")
+	assert.NotContains(t, text, "## Locks Held")
+	assert.Contains(t, text, "or end it with KILL 9.\n")
+
+	text = getLocksText(t, &mockUseCase{dbType: "mysql"}, map[string]interface{}{"database": "mysql1"})
+	assert.Contains(t, text, "No session is waiting for a lock.\n")
+	assert.Contains(t, text, "No other session holds a lock.\n")
+	assert.NotContains(t, text, "## Warnings")
+
+	_, err := NewGetLocksTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "db1"}}, "", &mockUseCase{dbType: "sqlite"})
+	assert.ErrorContains(t, err, "unsupported database type for locks: sqlite")
+}
@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// GetMappingTool handles reading the mappings of the indices of a search database
+type GetMappingTool struct {
+	BaseToolType
+}
+
+// NewGetMappingTool creates a new get mapping tool type
+func NewGetMappingTool() *GetMappingTool {
+	return &GetMappingTool{
+		BaseToolType: BaseToolType{
+			name:        "get_mapping",
+			description: "Read the mappings of the Elasticsearch or OpenSearch indices matching a pattern: every field path with its type and parameters such as analyzer, format or index, with objects and nested fields followed by their children and multi-fields such as title.keyword. Indices with the same mapping, such as the daily indices of one log, are shown once.",
+		},
+	}
+}
+
+// CreateTool creates a get mapping tool
+func (t *GetMappingTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Read the field mappings of Elasticsearch or OpenSearch indices"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("index",
+			tools.Description("Index or index pattern, such as orders or logs-*"),
+			tools.Required(),
+		),
+	)
+}
+
+// HandleRequest handles get mapping tool requests
+func (t *GetMappingTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	pattern := input.requiredString("index")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	store, err := useCase.GetSearchStore(targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := store.Mappings(ctx, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mappings: %w", err)
+	}
+	logger.Info("Read the mappings of %d indices matching %s in database %s", len(mappings), pattern, targetDbID)
+
+	// Indices with the same mapping share one section, in the order of their first index
+	var groups []string
+	indicesByMapping := map[string][]string{}
+	mappingByKey := map[string]map[string]interface{}{}
+	names := make([]string, 0, len(mappings))
+	for name := range mappings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		mapping, _ := searchField(mappings[name], "mappings").(map[string]interface{})
+		key := compactJSON(mapping)
+		if _, ok := indicesByMapping[key]; !ok {
+			groups = append(groups, key)
+			mappingByKey[key] = mapping
+		}
+		indicesByMapping[key] = append(indicesByMapping[key], name)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Mappings of %s in Database %s\n\n", pattern, targetDbID))
+	if len(names) == 0 {
+		response.WriteString("No indices match the pattern.\n")
+		return createTextResponse(response.String()), nil
+	}
+	for _, key := range groups {
+		indices := indicesByMapping[key]
+		mapping := mappingByKey[key]
+		if len(indices) == 1 {
+			response.WriteString(fmt.Sprintf("## %s\n\n", indices[0]))
+		} else {
+			response.WriteString(fmt.Sprintf("## %s (%d indices)\n\n", strings.Join(indices, ", "), len(indices)))
+		}
+		if dynamic, ok := mapping["dynamic"]; ok {
+			response.WriteString(fmt.Sprintf("Dynamic mapping: %v\n\n", dynamic))
+		}
+		properties, _ := mapping["properties"].(map[string]interface{})
+		fields := flattenMapping(properties, "")
+		if len(fields) == 0 {
+			response.WriteString("The mapping has no fields.\n\n")
+			continue
+		}
+		response.WriteString("| Field | Type | Parameters |\n")
+		response.WriteString("|-------|------|------------|\n")
+		for _, field := range fields {
+			response.WriteString(fmt.Sprintf("| %s | %s | %s |\n", field.path, field.typ, hitCell(field.details)))
+		}
+		response.WriteString("\n")
+	}
+	return createTextResponse(response.String()), nil
+}
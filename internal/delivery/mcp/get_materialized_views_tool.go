@@ -0,0 +1,288 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// GetMaterializedViewsTool handles listing and refreshing materialized views
+type GetMaterializedViewsTool struct {
+	BaseToolType
+}
+
+// NewGetMaterializedViewsTool creates a new get materialized views tool type
+func NewGetMaterializedViewsTool() *GetMaterializedViewsTool {
+	return &GetMaterializedViewsTool{
+		BaseToolType: BaseToolType{
+			name:        "get_materialized_views",
+			description: "List the materialized views of a PostgreSQL database with their definitions, size, estimated rows, whether they are populated, whether they can be refreshed concurrently and when their data was last written, or refresh one. PostgreSQL does not record refresh times, so the last write is the modification time of the view's data file, shown only when the server lets this connection read it. A refresh runs REFRESH MATERIALIZED VIEW, concurrently when the view has a unique index and is populated; without confirm it only shows the statement and the locks it takes.",
+		},
+	}
+}
+
+// CreateTool creates a get materialized views tool
+func (t *GetMaterializedViewsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("List materialized views with size and population state, or refresh one"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("view",
+			tools.Description("Materialized view name, optionally schema-qualified (optional for list, required for refresh)"),
+		),
+		tools.WithString("action",
+			tools.Description("list or refresh (default: list)"),
+		),
+		tools.WithBoolean("include_definition",
+			tools.Description("Whether to include the SQL definition of each view (default: true)"),
+		),
+		tools.WithBoolean("concurrently",
+			tools.Description("Refresh without blocking readers (default: when the view has a unique index and is populated)"),
+		),
+		tools.WithBoolean("confirm",
+			tools.Description("Set to true to run the refresh; without it the refresh is only previewed (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles get materialized views tool requests
+func (t *GetMaterializedViewsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	viewName := input.optionalString("view", "")
+	action := input.choice("action", "list", "list", "refresh")
+	includeDefinition := input.optionalBool("include_definition", true)
+	concurrently := input.optionalBool("concurrently", false)
+	hasConcurrently := input.has("concurrently")
+	confirm := input.optionalBool("confirm", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if action == "refresh" && viewName == "" {
+		return nil, fmt.Errorf("view parameter is required for refresh")
+	}
+
+	logger.Info("Getting materialized views for database %s, view %s, action %s", targetDbID, viewName, action)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for materialized views: %s", dbType)
+	}
+	query, ok := dialect.MaterializedViewsQuery()
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for materialized views: %s", dbType)
+	}
+
+	views, err := loadMaterializedViews(ctx, useCase, targetDbID, query, viewName)
+	if err != nil {
+		return nil, err
+	}
+
+	var response strings.Builder
+	if action == "list" {
+		if viewName == "" {
+			response.WriteString(fmt.Sprintf("# Materialized Views in Database %s\n\n", targetDbID))
+		} else {
+			response.WriteString(fmt.Sprintf("# Materialized View %s in Database %s\n\n", viewName, targetDbID))
+		}
+		writeMaterializedViews(&response, views, includeDefinition)
+		return createTextResponse(response.String()), nil
+	}
+
+	switch {
+	case len(views) == 0:
+		return nil, fmt.Errorf("materialized view %s not found", viewName)
+	case len(views) > 1:
+		return nil, fmt.Errorf("materialized view %s exists in several schemas; qualify it with its schema", viewName)
+	}
+	view := views[0]
+	if !hasConcurrently {
+		concurrently = view.Populated && view.UniqueIndex
+	}
+	switch {
+	case concurrently && !view.UniqueIndex:
+		return nil, fmt.Errorf("%s cannot be refreshed concurrently: it has no unique index on plain columns covering all rows", view.name())
+	case concurrently && !view.Populated:
+		return nil, fmt.Errorf("%s cannot be refreshed concurrently until it is populated; refresh it once without concurrently", view.name())
+	}
+
+	statement := "REFRESH MATERIALIZED VIEW "
+	if concurrently {
+		statement += "CONCURRENTLY "
+	}
+	statement += quoteIdentifier("postgres", view.name())
+
+	if !confirm {
+		response.WriteString(fmt.Sprintf("# Refresh Preview for %s in Database %s\n\n", view.name(), targetDbID))
+	} else {
+		response.WriteString(fmt.Sprintf("# Refresh %s in Database %s\n\n", view.name(), targetDbID))
+	}
+	response.WriteString(fmt.Sprintf("```sql\n%s;\n```\n\n", statement))
+	if concurrently {
+		response.WriteString("The refresh takes an EXCLUSIVE lock: the view stays readable, but other refreshes wait for it.\n\n")
+	} else {
+		response.WriteString("The refresh takes an ACCESS EXCLUSIVE lock: queries reading the view wait until it finishes.\n\n")
+	}
+
+	if !confirm {
+		response.WriteString(fmt.Sprintf("Nothing was refreshed. Re-run with confirm=true to refresh it; it currently takes %s.\n", formatBackupSize(view.Bytes)))
+		return createTextResponse(response.String()), nil
+	}
+
+	logger.Info("get_materialized_views: %s on database %s", statement, targetDbID)
+	start := time.Now()
+	_, err = useCase.ExecuteStatement(ctx, targetDbID, statement, nil)
+	audit(request, auditRecord{
+		Tool:      "get_materialized_views",
+		Database:  targetDbID,
+		Action:    "refresh",
+		Target:    view.name(),
+		Statement: statement,
+	}, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh %s: %w", view.name(), err)
+	}
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	refreshed, err := loadMaterializedViews(ctx, useCase, targetDbID, query, view.name())
+	if err != nil {
+		return nil, err
+	}
+	response.WriteString(fmt.Sprintf("Refreshed %s in %s.\n\n", view.name(), elapsed))
+	writeMaterializedViews(&response, refreshed, false)
+
+	return createTextResponse(response.String()), nil
+}
+
+// materializedView is a materialized view with its size and state
+type materializedView struct {
+	Schema      string
+	Name        string
+	Owner       string
+	Populated   bool
+	UniqueIndex bool // has a unique index usable by REFRESH ... CONCURRENTLY
+	Bytes       int64
+	Rows        int64 // planner estimate; -1 when the view was never analyzed
+	LastWritten string
+	Definition  string
+}
+
+// name returns the schema-qualified name of the view
+func (v materializedView) name() string {
+	return qualifiedName(v.Schema, v.Name)
+}
+
+// loadMaterializedViews reads the materialized views matching an optionally qualified name,
+// or all of them when the name is empty, with the query of the dialect
+func loadMaterializedViews(ctx context.Context, useCase UseCaseProvider, dbID, query, viewName string) ([]materializedView, error) {
+	schema, name := splitQualifiedName(viewName)
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, []interface{}{schema, name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get materialized views: %w", err)
+	}
+	views := make([]materializedView, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 9 {
+			continue
+		}
+		views = append(views, materializedView{
+			Schema:      valueString(row[0]),
+			Name:        valueString(row[1]),
+			Owner:       valueString(row[2]),
+			Populated:   valueBool(row[3]),
+			UniqueIndex: valueBool(row[4]),
+			Bytes:       valueInt64(row[5]),
+			Rows:        valueInt64(row[6]),
+			LastWritten: valueString(row[7]),
+			Definition:  valueString(row[8]),
+		})
+	}
+	return views, nil
+}
+
+// writeMaterializedViews renders materialized views as a table, followed by their definitions
+// and what stands in the way of using or refreshing them
+func writeMaterializedViews(sb *strings.Builder, views []materializedView, includeDefinition bool) {
+	if len(views) == 0 {
+		sb.WriteString("No materialized views found.\n")
+		return
+	}
+	sb.WriteString("| Materialized view | Owner | Populated | Size | Rows (estimated) | Concurrent refresh | Last written |\n")
+	sb.WriteString("|-------------------|-------|-----------|------|------------------|--------------------|--------------|\n")
+	var warnings []string
+	for _, v := range views {
+		rows := "-"
+		if v.Rows >= 0 {
+			rows = fmt.Sprintf("%d", v.Rows)
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+			v.name(), v.Owner, yesNo(v.Populated), formatBackupSize(v.Bytes), rows, yesNo(v.Populated && v.UniqueIndex), orDash(v.LastWritten)))
+		if !v.Populated {
+			warnings = append(warnings, fmt.Sprintf("%s is not populated; queries reading it fail until it is refreshed.", v.name()))
+		} else if !v.UniqueIndex {
+			warnings = append(warnings, fmt.Sprintf("%s has no unique index, so every refresh blocks its readers; add one to refresh it concurrently.", v.name()))
+		}
+	}
+
+	if includeDefinition {
+		sb.WriteString("\n## Definitions\n")
+		for _, v := range views {
+			sb.WriteString(fmt.Sprintf("\n### %s\n\n```sql\n%s\n```\n", v.name(), strings.TrimSpace(v.Definition)))
+		}
+	}
+
+	if len(warnings) > 0 {
+		sb.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+}
+
+// getPostgresMaterializedViewsQuery returns a query for the materialized views of an optional
+// schema and name. REFRESH ... CONCURRENTLY needs a unique index on plain columns without a
+// predicate. The modification time of the data file is read only where pg_stat_file may be
+// called, which takes superuser rights or an explicit grant.
+func getPostgresMaterializedViewsQuery() string {
+	return `
+SELECT
+    n.nspname AS schema_name,
+    c.relname AS view_name,
+    pg_get_userbyid(c.relowner) AS owner,
+    c.relispopulated AS populated,
+    EXISTS (
+        SELECT 1
+        FROM pg_index i
+        WHERE i.indrelid = c.oid
+          AND i.indisunique
+          AND i.indisvalid
+          AND i.indpred IS NULL
+          AND i.indexprs IS NULL
+    ) AS has_unique_index,
+    pg_total_relation_size(c.oid) AS total_bytes,
+    c.reltuples::bigint AS estimated_rows,
+    CASE
+        WHEN has_function_privilege('pg_catalog.pg_stat_file(text, boolean)', 'EXECUTE')
+        THEN (pg_stat_file(pg_relation_filepath(c.oid), true)).modification::text
+    END AS last_written,
+    pg_get_viewdef(c.oid) AS definition
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind = 'm'
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND ($1 = '' OR n.nspname = $1)
+  AND ($2 = '' OR c.relname = $2)
+ORDER BY n.nspname, c.relname;`
+}
@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func getMaterializedViews(useCase *mockUseCase, params map[string]interface{}) (string, error) {
+	result, err := NewGetMaterializedViewsTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	if err != nil {
+		return "", err
+	}
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string), nil
+}
+
+func materializedViewsUseCase(rows ...[]interface{}) *mockUseCase {
+	return &mockUseCase{
+		dbType:  "postgres",
+		results: map[string]*domain.QueryResult{"c.relkind = 'm'": {Rows: rows}},
+	}
+}
+
+func TestGetMaterializedViewsListsViews(t *testing.T) {
+	useCase := materializedViewsUseCase(
+		[]interface{}{"reporting", "daily_sales", "analyst", true, true, int64(5 * 1024 * 1024), int64(3650), "2026-10-17 02:00:04+00", " SELECT day, sum(total) FROM orders GROUP BY day;"},
+		[]interface{}{"reporting", "top_customers", "analyst", true, false, int64(8192), int64(-1), nil, " SELECT customer_id FROM orders;"},
+		[]interface{}{"public", "pending", "app", false, true, int64(0), int64(0), nil, " SELECT 1;"},
+	)
+	text, err := getMaterializedViews(useCase, map[string]interface{}{"database": "pg1"})
+	assert.NoError(t, err)
+
+	assert.Contains(t, text, "| reporting.daily_sales | analyst | yes | 5.0 MiB | 3650 | yes | 2026-10-17 02:00:04+00 |")
+	assert.Contains(t, text, "| reporting.top_customers | analyst | yes | 8.0 KiB | - | no | - |")
+	assert.Contains(t, text, "| public.pending | app | no | 0 B | 0 | no | - |")
+	assert.Contains(t, text, "### reporting.daily_sales\n\n```sql\nSELECT day, sum(total) FROM orders GROUP BY day;\n```")
+	assert.Contains(t, text, "- reporting.top_customers has no unique index, so every refresh blocks its readers")
+	assert.Contains(t, text, "- public.pending is not populated; queries reading it fail until it is refreshed.")
+
+	_, err = getMaterializedViews(&mockUseCase{dbType: "mysql"}, map[string]interface{}{"database": "mysql1"})
+	assert.ErrorContains(t, err, "unsupported database type for materialized views: mysql")
+}
+
+func TestGetMaterializedViewsRefreshesAfterConfirmation(t *testing.T) {
+	useCase := materializedViewsUseCase(
+		[]interface{}{"reporting", "daily_sales", "analyst", true, true, int64(5 * 1024 * 1024), int64(3650), nil, "SELECT 1;"},
+	)
+	text, err := getMaterializedViews(useCase, map[string]interface{}{"database": "pg1", "view": "reporting.daily_sales", "action": "refresh"})
+	assert.NoError(t, err)
+	assert.Len(t, useCase.queries, 1)
+	assert.Contains(t, text, "```sql\nREFRESH MATERIALIZED VIEW CONCURRENTLY \"reporting\".\"daily_sales\";\n```")
+	assert.Contains(t, text, "Nothing was refreshed. Re-run with confirm=true to refresh it; it currently takes 5.0 MiB.")
+
+	text, err = getMaterializedViews(useCase, map[string]interface{}{"database": "pg1", "view": "reporting.daily_sales", "action": "refresh", "concurrently": false, "confirm": true})
+	assert.NoError(t, err)
+	assert.Equal(t, "REFRESH MATERIALIZED VIEW \"reporting\".\"daily_sales\"", useCase.queries[2])
+	assert.Contains(t, text, "ACCESS EXCLUSIVE lock")
+	assert.Contains(t, text, "Refreshed reporting.daily_sales in ")
+
+	useCase = materializedViewsUseCase(
+		[]interface{}{"public", "pending", "app", false, true, int64(0), int64(0), nil, "SELECT 1;"},
+	)
+	_, err = getMaterializedViews(useCase, map[string]interface{}{"database": "pg1", "view": "pending", "action": "refresh", "concurrently": true})
+	assert.ErrorContains(t, err, "public.pending cannot be refreshed concurrently until it is populated")
+
+	_, err = getMaterializedViews(materializedViewsUseCase(), map[string]interface{}{"database": "pg1", "view": "missing", "action": "refresh"})
+	assert.ErrorContains(t, err, "materialized view missing not found")
+}
@@ -0,0 +1,435 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// GetObjectCommentsTool handles reading and setting the comments of schema objects
+type GetObjectCommentsTool struct {
+	BaseToolType
+}
+
+// NewGetObjectCommentsTool creates a new get object comments tool type
+func NewGetObjectCommentsTool() *GetObjectCommentsTool {
+	return &GetObjectCommentsTool{
+		BaseToolType: BaseToolType{
+			name:        "get_object_comments",
+			description: "Read the comments of the tables, views, columns and functions of a schema (pg_description on PostgreSQL, table, column and routine comments on MySQL), which are often the best documentation of what the data means. By default only objects that have a comment are listed; doc_coverage ranks the ones that lack one. Given set_comment with a table and optionally a column, it builds the COMMENT ON or ALTER TABLE statement that sets the comment instead; without confirm the statement is only shown.",
+		},
+	}
+}
+
+// CreateTool creates a get object comments tool
+func (t *GetObjectCommentsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Read comments on tables, views, columns and functions, or set one"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to read (optional, defaults to the current schema on PostgreSQL and the current database on MySQL)"),
+		),
+		tools.WithString("table",
+			tools.Description("Table or view to read, or whose comment to set (optional for reading)"),
+		),
+		tools.WithString("column",
+			tools.Description("Column whose comment to set (optional, sets the table comment when empty)"),
+		),
+		tools.WithBoolean("include_columns",
+			tools.Description("Whether to list column comments (default: true)"),
+		),
+		tools.WithBoolean("include_undocumented",
+			tools.Description("Whether to also list objects without a comment (default: false)"),
+		),
+		tools.WithString("set_comment",
+			tools.Description("New comment for the table or column; an empty string removes it"),
+		),
+		tools.WithBoolean("confirm",
+			tools.Description("Set to true to run the statement built for set_comment; without it the statement is only shown (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles get object comments tool requests
+func (t *GetObjectCommentsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema := input.optionalString("schema", "")
+	table := input.optionalString("table", "")
+	column := input.optionalString("column", "")
+	includeColumns := input.optionalBool("include_columns", true)
+	includeUndocumented := input.optionalBool("include_undocumented", false)
+	newComment := input.optionalString("set_comment", "")
+	setComment := input.has("set_comment")
+	confirm := input.optionalBool("confirm", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if setComment && table == "" {
+		return nil, fmt.Errorf("table parameter is required for set_comment")
+	}
+	if column != "" && !setComment {
+		return nil, fmt.Errorf("column parameter is only used with set_comment")
+	}
+
+	logger.Info("Getting object comments for database %s, schema %s, table %s", targetDbID, schema, table)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for object comments: %s", dbType)
+	}
+	commenter, err := dialect.ObjectCommenter()
+	if err != nil {
+		return nil, unsupportedAdminError("object comments", dbType, err)
+	}
+
+	if setComment {
+		return setObjectComment(ctx, request, useCase, targetDbID, dialect.Name(), commenter, schema, table, column, newComment, confirm)
+	}
+
+	params := commenter.tableParams(schema, table, includeUndocumented)
+	relations, err := loadObjectComments(ctx, useCase, targetDbID, commenter.relationsQuery(), params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table comments: %w", err)
+	}
+
+	var response strings.Builder
+	if table == "" {
+		response.WriteString(fmt.Sprintf("# Object Comments in Database %s\n", targetDbID))
+	} else {
+		response.WriteString(fmt.Sprintf("# Comments of %s in Database %s\n", table, targetDbID))
+	}
+
+	response.WriteString("\n## Tables and Views\n\n")
+	writeObjectComments(&response, []string{"Schema", "Table", "Kind", "Comment"}, relations)
+
+	if includeColumns {
+		columns, err := loadObjectComments(ctx, useCase, targetDbID, commenter.columnsQuery(), params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get column comments: %w", err)
+		}
+		response.WriteString("\n## Columns\n\n")
+		writeObjectComments(&response, []string{"Table", "Column", "Type", "Comment"}, columns)
+	}
+
+	// Functions belong to the schema rather than to a table
+	if table == "" {
+		routines, err := loadObjectComments(ctx, useCase, targetDbID, commenter.routinesQuery(), []interface{}{schema, includeUndocumented})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get function comments: %w", err)
+		}
+		response.WriteString("\n## Functions\n\n")
+		writeObjectComments(&response, []string{"Function", "Kind", "Comment"}, routines)
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// loadObjectComments runs a comment query and returns its rows as strings
+func loadObjectComments(ctx context.Context, useCase UseCaseProvider, dbID, query string, params []interface{}) ([][]string, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, params)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([][]string, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		values := make([]string, len(row))
+		for i, value := range row {
+			values[i] = valueString(value)
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+// writeObjectComments renders comment rows as a table, flattening multi-line comments
+func writeObjectComments(sb *strings.Builder, headers []string, rows [][]string) {
+	if len(rows) == 0 {
+		sb.WriteString("None.\n")
+		return
+	}
+	sb.WriteString("| " + strings.Join(headers, " | ") + " |\n")
+	separators := make([]string, len(headers))
+	for i, header := range headers {
+		separators[i] = strings.Repeat("-", len(header)+2)
+	}
+	sb.WriteString("|" + strings.Join(separators, "|") + "|\n")
+	for _, row := range rows {
+		cells := make([]string, len(headers))
+		for i := range cells {
+			if i < len(row) {
+				cells[i] = queryCell(row[i])
+			}
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+}
+
+// setObjectComment builds the statement setting the comment of a table or column and runs it
+// when confirmed
+func setObjectComment(ctx context.Context, request server.ToolCallRequest, useCase UseCaseProvider, dbID, dbType string, commenter objectCommenter, schema, table, column, comment string, confirm bool) (interface{}, error) {
+	relations, err := loadObjectComments(ctx, useCase, dbID, commenter.relationsQuery(), commenter.tableParams(schema, table, true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up table %s: %w", table, err)
+	}
+	if len(relations) == 0 || len(relations[0]) < 4 {
+		return nil, fmt.Errorf("table %s not found", qualifiedName(schema, table))
+	}
+	relSchema, relName, kind := relations[0][0], relations[0][1], relations[0][2]
+	target := qualifiedName(relSchema, relName)
+	statement, err := commenter.commentStatement(ctx, useCase, dbID, quoteIdentifier(dbType, target), kind, column, comment)
+	if err != nil {
+		return nil, err
+	}
+	if column != "" {
+		target += "." + column
+	}
+
+	var response strings.Builder
+	if !confirm {
+		response.WriteString(fmt.Sprintf("# Comment Preview for %s in Database %s\n\n", target, dbID))
+	} else {
+		response.WriteString(fmt.Sprintf("# Comment %s in Database %s\n\n", target, dbID))
+	}
+	response.WriteString(fmt.Sprintf("```sql\n%s;\n```\n\n", statement))
+	if note := commenter.columnCommentNote(); note != "" && column != "" {
+		response.WriteString(note + "\n\n")
+	}
+
+	if !confirm {
+		response.WriteString("Nothing was changed. Re-run with confirm=true to set the comment.\n")
+		return createTextResponse(response.String()), nil
+	}
+
+	logger.Info("get_object_comments: %s on database %s", statement, dbID)
+	_, err = useCase.ExecuteStatement(ctx, dbID, statement, nil)
+	audit(request, auditRecord{
+		Tool:      "get_object_comments",
+		Database:  dbID,
+		Action:    "comment",
+		Target:    target,
+		Statement: statement,
+	}, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set the comment of %s: %w", target, err)
+	}
+	if comment == "" {
+		response.WriteString(fmt.Sprintf("Removed the comment of %s.\n", target))
+	} else {
+		response.WriteString(fmt.Sprintf("Set the comment of %s.\n", target))
+	}
+	return createTextResponse(response.String()), nil
+}
+
+// mysqlCommentClause matches the COMMENT clause of a column definition in SHOW CREATE TABLE,
+// where quotes are doubled and backslashes escaped
+var mysqlCommentClause = regexp.MustCompile(` COMMENT '(?:[^'\\]|''|\\.)*'`)
+
+// mysqlColumnDefinition returns the definition of a column as SHOW CREATE TABLE prints it,
+// without its comment
+func mysqlColumnDefinition(ctx context.Context, useCase UseCaseProvider, dbID, tableIdent, column string) (string, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf("SHOW CREATE TABLE %s", tableIdent), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the definition of %s: %w", tableIdent, err)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) < 2 {
+		return "", fmt.Errorf("no definition returned for %s", tableIdent)
+	}
+	prefix := quoteIdentifier("mysql", column) + " "
+	for _, line := range strings.Split(valueString(result.Rows[0][1]), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, prefix) {
+			return mysqlCommentClause.ReplaceAllString(strings.TrimSuffix(line, ","), ""), nil
+		}
+	}
+	return "", fmt.Errorf("column %s not found in table %s", column, tableIdent)
+}
+
+// objectCommenter is the engine-specific part of reading and setting object comments
+type objectCommenter interface {
+	// relationsQuery lists the tables and views of schema $1, or only table $2, with their kind and comment
+	relationsQuery() string
+	// columnsQuery lists the columns of the tables and views relationsQuery reads with their comments
+	columnsQuery() string
+	// routinesQuery lists the functions of schema $1 with their comments
+	routinesQuery() string
+	// tableParams returns the parameters of relationsQuery and columnsQuery
+	tableParams(schema, table string, includeUndocumented bool) []interface{}
+	// commentStatement builds the statement setting the comment of a table of the given kind, or
+	// of one of its columns; an empty comment removes it
+	commentStatement(ctx context.Context, useCase UseCaseProvider, dbID, tableIdent, kind, column, comment string) (string, error)
+	// columnCommentNote explains what setting a column comment does besides that, if anything
+	columnCommentNote() string
+}
+
+// postgresCommenter reads pg_description and sets comments with COMMENT ON
+type postgresCommenter struct{}
+
+func (postgresCommenter) tableParams(schema, table string, includeUndocumented bool) []interface{} {
+	return []interface{}{schema, table, includeUndocumented}
+}
+
+func (postgresCommenter) commentStatement(_ context.Context, _ UseCaseProvider, _, tableIdent, kind, column, comment string) (string, error) {
+	value := "NULL"
+	if comment != "" {
+		value = quoteLiteral("postgres", comment)
+	}
+	if column == "" {
+		return fmt.Sprintf("COMMENT ON %s %s IS %s", strings.ToUpper(kind), tableIdent, value), nil
+	}
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", tableIdent, quoteIdentifier("postgres", column), value), nil
+}
+
+func (postgresCommenter) columnCommentNote() string { return "" }
+
+// relationsQuery returns a query for the tables and views of a schema with their
+// comments. Partitions share the comment of their parent table and are left out.
+func (postgresCommenter) relationsQuery() string {
+	return `
+SELECT
+    n.nspname AS schema_name,
+    c.relname AS table_name,
+    CASE c.relkind
+        WHEN 'v' THEN 'view'
+        WHEN 'm' THEN 'materialized view'
+        WHEN 'f' THEN 'foreign table'
+        ELSE 'table'
+    END AS kind,
+    COALESCE(obj_description(c.oid, 'pg_class'), '') AS comment
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('r', 'p', 'v', 'm', 'f')
+  AND NOT c.relispartition
+  AND n.nspname = COALESCE(NULLIF($1, ''), current_schema())
+  AND ($2 = '' OR c.relname = $2)
+  AND ($3 OR obj_description(c.oid, 'pg_class') IS NOT NULL)
+ORDER BY c.relname;`
+}
+
+// columnsQuery returns a query for the columns of the tables and views of a schema
+// with their comments
+func (postgresCommenter) columnsQuery() string {
+	return `
+SELECT
+    c.relname AS table_name,
+    a.attname AS column_name,
+    format_type(a.atttypid, a.atttypmod) AS data_type,
+    COALESCE(col_description(c.oid, a.attnum), '') AS comment
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped
+WHERE c.relkind IN ('r', 'p', 'v', 'm', 'f')
+  AND NOT c.relispartition
+  AND n.nspname = COALESCE(NULLIF($1, ''), current_schema())
+  AND ($2 = '' OR c.relname = $2)
+  AND ($3 OR col_description(c.oid, a.attnum) IS NOT NULL)
+ORDER BY c.relname, a.attnum;`
+}
+
+// routinesQuery returns a query for the functions and procedures of a schema with
+// their comments. Functions installed by extensions are left out.
+func (postgresCommenter) routinesQuery() string {
+	return `
+SELECT
+    p.proname || '(' || pg_get_function_identity_arguments(p.oid) || ')' AS routine_name,
+    CASE p.prokind
+        WHEN 'p' THEN 'procedure'
+        WHEN 'a' THEN 'aggregate'
+        WHEN 'w' THEN 'window function'
+        ELSE 'function'
+    END AS kind,
+    COALESCE(obj_description(p.oid, 'pg_proc'), '') AS comment
+FROM pg_proc p
+JOIN pg_namespace n ON n.oid = p.pronamespace
+WHERE n.nspname = COALESCE(NULLIF($1, ''), current_schema())
+  AND ($2 OR obj_description(p.oid, 'pg_proc') IS NOT NULL)
+  AND NOT EXISTS (
+      SELECT 1 FROM pg_depend d
+      WHERE d.classid = 'pg_proc'::regclass AND d.objid = p.oid AND d.deptype = 'e'
+  )
+ORDER BY 1;`
+}
+
+// mysqlCommenter reads the comments of information_schema and sets them with ALTER TABLE
+type mysqlCommenter struct{}
+
+// tableParams binds the table twice, as MySQL placeholders are positional
+func (mysqlCommenter) tableParams(schema, table string, includeUndocumented bool) []interface{} {
+	return []interface{}{schema, table, table, includeUndocumented}
+}
+
+// commentStatement sets a column comment by restating the column definition with MODIFY
+// COLUMN, as MySQL has no statement that only sets the comment
+func (mysqlCommenter) commentStatement(ctx context.Context, useCase UseCaseProvider, dbID, tableIdent, kind, column, comment string) (string, error) {
+	switch {
+	case kind == "view":
+		return "", fmt.Errorf("MySQL views cannot have comments; comment the columns of the underlying tables instead")
+	case column == "":
+		return fmt.Sprintf("ALTER TABLE %s COMMENT = %s", tableIdent, quoteLiteral("mysql", comment)), nil
+	}
+	definition, err := mysqlColumnDefinition(ctx, useCase, dbID, tableIdent, column)
+	if err != nil {
+		return "", err
+	}
+	if comment != "" {
+		definition += " COMMENT " + quoteLiteral("mysql", comment)
+	}
+	return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", tableIdent, definition), nil
+}
+
+func (mysqlCommenter) columnCommentNote() string {
+	return "MODIFY COLUMN restates the whole column definition, which is copied from SHOW CREATE TABLE. It only changes metadata, but MySQL may still rebuild the table for some column types."
+}
+
+func (mysqlCommenter) relationsQuery() string {
+	return `
+SELECT
+    TABLE_SCHEMA AS schema_name,
+    TABLE_NAME AS table_name,
+    CASE WHEN TABLE_TYPE = 'VIEW' THEN 'view' ELSE 'table' END AS kind,
+    CASE WHEN TABLE_TYPE = 'VIEW' THEN '' ELSE TABLE_COMMENT END AS comment
+FROM information_schema.TABLES
+WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+  AND (? = '' OR TABLE_NAME = ?)
+  AND (? OR (TABLE_TYPE <> 'VIEW' AND TABLE_COMMENT <> ''))
+ORDER BY TABLE_NAME;`
+}
+
+func (mysqlCommenter) columnsQuery() string {
+	return `
+SELECT
+    TABLE_NAME AS table_name,
+    COLUMN_NAME AS column_name,
+    COLUMN_TYPE AS data_type,
+    COLUMN_COMMENT AS comment
+FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+  AND (? = '' OR TABLE_NAME = ?)
+  AND (? OR COLUMN_COMMENT <> '')
+ORDER BY TABLE_NAME, ORDINAL_POSITION;`
+}
+
+func (mysqlCommenter) routinesQuery() string {
+	return `
+SELECT
+    ROUTINE_NAME AS routine_name,
+    LOWER(ROUTINE_TYPE) AS kind,
+    ROUTINE_COMMENT AS comment
+FROM information_schema.ROUTINES
+WHERE ROUTINE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+  AND (? OR ROUTINE_COMMENT <> '')
+ORDER BY ROUTINE_NAME;`
+}
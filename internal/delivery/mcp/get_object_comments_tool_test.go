@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func getObjectComments(useCase *mockUseCase, params map[string]interface{}) (string, error) {
+	result, err := NewGetObjectCommentsTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	if err != nil {
+		return "", err
+	}
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string), nil
+}
+
+func TestGetObjectCommentsListsComments(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"END AS kind,\n    COALESCE(obj_description(c.oid": {Rows: [][]interface{}{
+				{"public", "orders", "table", "Customer orders.\nOne row per checkout."},
+			}},
+			"col_description(c.oid, a.attnum), '')": {Rows: [][]interface{}{
+				{"orders", "status", "text", "pending | shipped"},
+			}},
+			"FROM pg_proc p": {Rows: [][]interface{}{
+				{"order_total(bigint)", "function", "Sum of the order lines"},
+			}},
+		},
+	}
+	text, err := getObjectComments(useCase, map[string]interface{}{"database": "pg1"})
+	assert.NoError(t, err)
+
+	assert.Len(t, useCase.queries, 3)
+	assert.Contains(t, text, "| public | orders | table | Customer orders. One row per checkout. |")
+	assert.Contains(t, text, "| orders | status | text | pending \\| shipped |")
+	assert.Contains(t, text, "| order_total(bigint) | function | Sum of the order lines |")
+
+	useCase = &mockUseCase{dbType: "mysql"}
+	text, err = getObjectComments(useCase, map[string]interface{}{"database": "db1", "table": "orders", "include_columns": false})
+	assert.NoError(t, err)
+	assert.Len(t, useCase.queries, 1)
+	assert.Contains(t, text, "## Tables and Views\n\nNone.\n")
+	assert.NotContains(t, text, "## Functions")
+
+	_, err = getObjectComments(&mockUseCase{dbType: "sqlite"}, map[string]interface{}{"database": "lite"})
+	assert.ErrorContains(t, err, "unsupported database type for object comments: sqlite")
+}
+
+func TestGetObjectCommentsSetsComment(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_class c": {Rows: [][]interface{}{{"reporting", "daily_sales", "materialized view", ""}}},
+		},
+	}
+	text, err := getObjectComments(useCase, map[string]interface{}{"database": "pg1", "table": "daily_sales", "set_comment": "Sales per day, refreshed nightly"})
+	assert.NoError(t, err)
+	assert.Contains(t, text, "COMMENT ON MATERIALIZED VIEW \"reporting\".\"daily_sales\" IS 'Sales per day, refreshed nightly';")
+	assert.Contains(t, text, "Nothing was changed.")
+	assert.Len(t, useCase.queries, 1)
+
+	text, err = getObjectComments(useCase, map[string]interface{}{"database": "pg1", "table": "daily_sales", "column": "day", "set_comment": "", "confirm": true})
+	assert.NoError(t, err)
+	assert.Equal(t, "COMMENT ON COLUMN \"reporting\".\"daily_sales\".\"day\" IS NULL", useCase.queries[2])
+	assert.Contains(t, text, "Removed the comment of reporting.daily_sales.day.")
+
+	useCase = &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"FROM information_schema.TABLES": {Rows: [][]interface{}{{"shop", "orders", "table", ""}}},
+			"SHOW CREATE TABLE":              {Rows: [][]interface{}{{"orders", "CREATE TABLE `orders` (\n  `id` bigint NOT NULL AUTO_INCREMENT,\n  `status` varchar(20) NOT NULL DEFAULT 'new' COMMENT 'it''s old',\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB"}}},
+		},
+	}
+	text, err = getObjectComments(useCase, map[string]interface{}{"database": "db1", "table": "orders", "column": "status", "set_comment": "Order state"})
+	assert.NoError(t, err)
+	assert.Contains(t, text, "ALTER TABLE `shop`.`orders` MODIFY COLUMN `status` varchar(20) NOT NULL DEFAULT 'new' COMMENT 'Order state';")
+
+	_, err = getObjectComments(useCase, map[string]interface{}{"database": "db1", "table": "orders", "column": "missing", "set_comment": "x"})
+	assert.ErrorContains(t, err, "column missing not found")
+
+	_, err = getObjectComments(&mockUseCase{dbType: "postgres"}, map[string]interface{}{"database": "pg1", "table": "missing", "set_comment": "x"})
+	assert.ErrorContains(t, err, "table missing not found")
+}
@@ -0,0 +1,417 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// GetPartitionsTool handles describing how tables are partitioned
+type GetPartitionsTool struct {
+	BaseToolType
+}
+
+// NewGetPartitionsTool creates a new get partitions tool type
+func NewGetPartitionsTool() *GetPartitionsTool {
+	return &GetPartitionsTool{
+		BaseToolType: BaseToolType{
+			name:        "get_partitions",
+			description: "Show how tables are partitioned. Without a table this lists the partitioned tables with their partitioning scheme, partition count, rows and size; with a table it lists every partition with its boundaries, estimated rows and size, including sub-partitions. Supports PostgreSQL declarative partitioning and MySQL partitioned tables. Use this tool to see where the rows of a partitioned table live, since table_stats only reports the parent table.",
+		},
+	}
+}
+
+// CreateTool creates a get partitions tool
+func (t *GetPartitionsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Show the partitioning scheme, boundaries, rows and size of partitioned tables"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Partitioned table, optionally schema-qualified (optional, leave empty to list all partitioned tables)"),
+		),
+	)
+}
+
+// HandleRequest handles get partitions tool requests
+func (t *GetPartitionsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	table := input.optionalString("table", "")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Getting partitions for database %s, table %s", targetDbID, table)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for partitions: %s", dbType)
+	}
+	reader, err := dialect.PartitionReader()
+	if err != nil {
+		return nil, unsupportedAdminError("partitions", dbType, err)
+	}
+
+	var response strings.Builder
+	if table == "" {
+		tables, err := loadPartitionedTableSummaries(ctx, useCase, targetDbID, reader)
+		if err != nil {
+			return nil, err
+		}
+		response.WriteString(fmt.Sprintf("# Partitioned Tables in Database %s\n\n", targetDbID))
+		writePartitionedTableSummaries(&response, tables)
+		return createTextResponse(response.String()), nil
+	}
+
+	layout, err := reader.layout(ctx, useCase, targetDbID, table)
+	if err != nil {
+		return nil, err
+	}
+	response.WriteString(fmt.Sprintf("# Partitions of %s in Database %s\n\n", table, targetDbID))
+	writePartitionLayout(&response, layout)
+
+	return createTextResponse(response.String()), nil
+}
+
+// partitionedTableSummary is a partitioned table with the totals of its partitions
+type partitionedTableSummary struct {
+	Table      string
+	Key        string
+	Partitions int64
+	Rows       int64
+	Bytes      int64
+}
+
+// partitionDetail is a partition or sub-partition with its boundaries and size
+type partitionDetail struct {
+	Name      string
+	Parent    string // the partition a sub-partition belongs to; empty at the first level
+	Bound     string
+	SubKey    string // the key a PostgreSQL partition is partitioned by in turn
+	Leaf      bool   // holds rows itself rather than through sub-partitions
+	Default   bool   // DEFAULT partition on PostgreSQL, MAXVALUE partition on MySQL
+	Rows      int64
+	RowsKnown bool // false when PostgreSQL has no statistics for the partition yet
+	Bytes     int64
+}
+
+// partitionLayout is the partitioning of one table
+type partitionLayout struct {
+	Key        string
+	SubKey     string // MySQL sub-partitioning, which is the same for every partition
+	Partitions []partitionDetail
+}
+
+// method returns the partitioning method of the layout, such as RANGE or HASH
+func (l *partitionLayout) method() string {
+	method, _, _ := strings.Cut(l.Key, " (")
+	return strings.ToUpper(strings.TrimSpace(method))
+}
+
+// partitionReader is the engine-specific part of reading partitioned tables
+type partitionReader interface {
+	// summariesQuery returns the query listing the partitioned tables with the totals of their
+	// partitions, in the columns read by loadPartitionedTableSummaries
+	summariesQuery() string
+	// layout reads the partitions of a table
+	layout(ctx context.Context, useCase UseCaseProvider, dbID, table string) (*partitionLayout, error)
+}
+
+// postgresPartitionReader reads declarative partition trees
+type postgresPartitionReader struct{}
+
+func (postgresPartitionReader) summariesQuery() string {
+	return `
+SELECT
+    n.nspname || '.' || c.relname AS table_name,
+    pg_get_partkeydef(c.oid) AS partition_key,
+    (SELECT count(*) FROM pg_partition_tree(c.oid) t WHERE t.isleaf AND t.level > 0) AS partitions,
+    (SELECT sum(GREATEST(pc.reltuples, 0))::bigint FROM pg_partition_tree(c.oid) t
+     JOIN pg_class pc ON pc.oid = t.relid WHERE t.isleaf) AS row_estimate,
+    (SELECT sum(pg_total_relation_size(t.relid)) FROM pg_partition_tree(c.oid) t) AS total_bytes
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind = 'p' AND NOT c.relispartition
+AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+ORDER BY 1`
+}
+
+func (postgresPartitionReader) layout(ctx context.Context, useCase UseCaseProvider, dbID, table string) (*partitionLayout, error) {
+	return loadPostgresPartitionLayout(ctx, useCase, dbID, table)
+}
+
+// mysqlPartitionReader reads the partitions of the current database from information_schema
+type mysqlPartitionReader struct{}
+
+func (mysqlPartitionReader) summariesQuery() string {
+	return `
+SELECT
+    TABLE_NAME,
+    CONCAT(PARTITION_METHOD, ' (', PARTITION_EXPRESSION, ')') AS partition_key,
+    COUNT(*) AS partitions,
+    SUM(TABLE_ROWS) AS row_estimate,
+    SUM(DATA_LENGTH + INDEX_LENGTH) AS total_bytes
+FROM information_schema.PARTITIONS
+WHERE TABLE_SCHEMA = DATABASE() AND PARTITION_NAME IS NOT NULL
+GROUP BY TABLE_NAME, PARTITION_METHOD, PARTITION_EXPRESSION
+ORDER BY TABLE_NAME`
+}
+
+func (mysqlPartitionReader) layout(ctx context.Context, useCase UseCaseProvider, dbID, table string) (*partitionLayout, error) {
+	return loadMySQLPartitionLayout(ctx, useCase, dbID, table)
+}
+
+// loadPartitionedTableSummaries lists the partitioned tables of a database
+func loadPartitionedTableSummaries(ctx context.Context, useCase UseCaseProvider, dbID string, reader partitionReader) ([]partitionedTableSummary, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, reader.summariesQuery(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitioned tables: %w", err)
+	}
+	tables := make([]partitionedTableSummary, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		tables = append(tables, partitionedTableSummary{
+			Table:      valueString(row[0]),
+			Key:        strings.ReplaceAll(valueString(row[1]), "`", ""),
+			Partitions: valueInt64(row[2]),
+			Rows:       valueInt64(row[3]),
+			Bytes:      valueInt64(row[4]),
+		})
+	}
+	return tables, nil
+}
+
+// loadPostgresPartitionLayout reads the partition tree of a PostgreSQL table. Row counts are
+// the planner's estimates, which are unknown until a partition has been analyzed.
+func loadPostgresPartitionLayout(ctx context.Context, useCase UseCaseProvider, dbID, table string) (*partitionLayout, error) {
+	relation := quoteIdentifier("postgres", table)
+	result, err := useCase.ExecuteQuery(ctx, dbID, `SELECT pg_get_partkeydef(to_regclass($1))`, []interface{}{relation})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read partition key: %w", err)
+	}
+	if len(result.Rows) == 0 || valueString(result.Rows[0][0]) == "" {
+		return nil, fmt.Errorf("table %s does not exist or is not partitioned", table)
+	}
+	layout := &partitionLayout{Key: valueString(result.Rows[0][0])}
+
+	result, err = useCase.ExecuteQuery(ctx, dbID, `
+SELECT
+    c.relname AS partition_name,
+    CASE WHEN t.level > 1 THEN p.relname END AS parent_name,
+    pg_get_expr(c.relpartbound, c.oid) AS bound,
+    CASE WHEN c.relkind = 'p' THEN pg_get_partkeydef(c.oid) END AS subpartition_key,
+    t.isleaf,
+    c.reltuples::bigint AS row_estimate,
+    CASE WHEN t.isleaf THEN pg_total_relation_size(c.oid) END AS total_bytes
+FROM pg_partition_tree(to_regclass($1)) t
+JOIN pg_class c ON c.oid = t.relid
+JOIN pg_class p ON p.oid = t.parentrelid
+WHERE t.level > 0
+ORDER BY t.level, p.relname, c.relname`, []interface{}{relation})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+	for _, row := range result.Rows {
+		if len(row) < 7 {
+			continue
+		}
+		p := partitionDetail{
+			Name:   valueString(row[0]),
+			Parent: valueString(row[1]),
+			Bound:  valueString(row[2]),
+			SubKey: valueString(row[3]),
+			Leaf:   valueBool(row[4]),
+			Rows:   valueInt64(row[5]),
+			Bytes:  valueInt64(row[6]),
+		}
+		// reltuples is -1 for a table that has never been vacuumed or analyzed
+		p.RowsKnown = p.Leaf && p.Rows >= 0
+		p.Default = p.Bound == "DEFAULT"
+		layout.Partitions = append(layout.Partitions, p)
+	}
+	return layout, nil
+}
+
+// loadMySQLPartitionLayout reads the partitions and sub-partitions of a MySQL table from
+// information_schema; TABLE_ROWS is an estimate for InnoDB
+func loadMySQLPartitionLayout(ctx context.Context, useCase UseCaseProvider, dbID, table string) (*partitionLayout, error) {
+	schema, name := splitQualifiedName(table)
+	schemaFilter := "TABLE_SCHEMA = DATABASE()"
+	params := []interface{}{name}
+	if schema != "" {
+		schemaFilter = "TABLE_SCHEMA = ?"
+		params = append(params, schema)
+	}
+	result, err := useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT
+    PARTITION_NAME,
+    SUBPARTITION_NAME,
+    PARTITION_METHOD,
+    PARTITION_EXPRESSION,
+    SUBPARTITION_METHOD,
+    SUBPARTITION_EXPRESSION,
+    PARTITION_DESCRIPTION,
+    TABLE_ROWS,
+    DATA_LENGTH + INDEX_LENGTH AS total_bytes
+FROM information_schema.PARTITIONS
+WHERE TABLE_NAME = ? AND %s
+ORDER BY PARTITION_ORDINAL_POSITION, SUBPARTITION_ORDINAL_POSITION`, schemaFilter), params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) < 9 || result.Rows[0][0] == nil {
+		return nil, fmt.Errorf("table %s does not exist or is not partitioned", table)
+	}
+
+	first := result.Rows[0]
+	layout := &partitionLayout{Key: mysqlPartitionKey(first[2], first[3])}
+	if first[4] != nil {
+		layout.SubKey = mysqlPartitionKey(first[4], first[5])
+	}
+	method := layout.method()
+	for _, row := range result.Rows {
+		if len(row) < 9 {
+			continue
+		}
+		p := partitionDetail{
+			Name:      valueString(row[0]),
+			Bound:     mysqlPartitionBound(method, valueString(row[6])),
+			Leaf:      true,
+			Default:   strings.EqualFold(valueString(row[6]), "MAXVALUE"),
+			Rows:      valueInt64(row[7]),
+			RowsKnown: true,
+			Bytes:     valueInt64(row[8]),
+		}
+		if row[1] != nil {
+			// Sub-partitions share the bounds of their partition
+			p.Parent, p.Name = p.Name, valueString(row[1])
+		}
+		layout.Partitions = append(layout.Partitions, p)
+	}
+	return layout, nil
+}
+
+// mysqlPartitionKey renders a MySQL partitioning method and expression like PostgreSQL's
+// partition key, as in RANGE (to_days(created_at))
+func mysqlPartitionKey(method, expression interface{}) string {
+	return fmt.Sprintf("%s (%s)", valueString(method), strings.ReplaceAll(valueString(expression), "`", ""))
+}
+
+// mysqlPartitionBound renders the PARTITION_DESCRIPTION of a partition as its VALUES clause;
+// HASH and KEY partitions have no bounds
+func mysqlPartitionBound(method, description string) string {
+	switch {
+	case strings.HasPrefix(method, "RANGE"):
+		if strings.EqualFold(description, "MAXVALUE") {
+			return "VALUES LESS THAN MAXVALUE"
+		}
+		return fmt.Sprintf("VALUES LESS THAN (%s)", description)
+	case strings.HasPrefix(method, "LIST"):
+		return fmt.Sprintf("VALUES IN (%s)", description)
+	default:
+		return ""
+	}
+}
+
+// writePartitionedTableSummaries renders the partitioned tables of a database
+func writePartitionedTableSummaries(sb *strings.Builder, tables []partitionedTableSummary) {
+	if len(tables) == 0 {
+		sb.WriteString("No partitioned tables found.\n")
+		return
+	}
+	sb.WriteString("| Table | Partitioning | Partitions | Rows (estimate) | Size |\n")
+	sb.WriteString("|-------|--------------|------------|-----------------|------|\n")
+	for _, t := range tables {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %d | %d | %s |\n", t.Table, t.Key, t.Partitions, t.Rows, formatBackupSize(t.Bytes)))
+	}
+}
+
+// writePartitionLayout renders the partitions of a table with their share of its rows and
+// warns about rows that fell into a catch-all partition and about skewed hash partitions
+func writePartitionLayout(sb *strings.Builder, layout *partitionLayout) {
+	sb.WriteString(fmt.Sprintf("Partitioning: %s\n", layout.Key))
+	if layout.SubKey != "" {
+		sb.WriteString(fmt.Sprintf("Sub-partitioning: %s\n", layout.SubKey))
+	}
+	if len(layout.Partitions) == 0 {
+		sb.WriteString("\nThe table has no partitions; inserts fail until one is created.\n")
+		return
+	}
+
+	var leaves, totalRows, totalBytes, maxRows int64
+	unknownRows := false
+	for _, p := range layout.Partitions {
+		if !p.Leaf {
+			continue
+		}
+		leaves++
+		totalBytes += p.Bytes
+		if !p.RowsKnown {
+			unknownRows = true
+			continue
+		}
+		totalRows += p.Rows
+		if p.Rows > maxRows {
+			maxRows = p.Rows
+		}
+	}
+	sb.WriteString(fmt.Sprintf("Partitions: %d, rows (estimate): %d, size: %s\n\n", leaves, totalRows, formatBackupSize(totalBytes)))
+
+	sb.WriteString("| Partition | Parent | Bounds | Rows (estimate) | Rows % | Size |\n")
+	sb.WriteString("|-----------|--------|--------|-----------------|--------|------|\n")
+	for _, p := range layout.Partitions {
+		bound := orDash(p.Bound)
+		if p.SubKey != "" {
+			bound += fmt.Sprintf(", sub-partitioned by %s", p.SubKey)
+		}
+		rows, share, size := "-", "-", "-"
+		if p.Leaf {
+			size = formatBackupSize(p.Bytes)
+			rows = "unknown"
+			if p.RowsKnown {
+				rows = fmt.Sprintf("%d", p.Rows)
+				if totalRows > 0 {
+					share = fmt.Sprintf("%.1f%%", float64(p.Rows)*100/float64(totalRows))
+				}
+			}
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n", p.Name, orDash(p.Parent), bound, rows, share, size))
+	}
+
+	var warnings []string
+	for _, p := range layout.Partitions {
+		if p.Default && p.Leaf && p.RowsKnown && p.Rows > 0 {
+			warnings = append(warnings, fmt.Sprintf("The catch-all partition %s holds about %d rows; they fall outside every other partition, so a partition for their range is probably missing.", p.Name, p.Rows))
+		}
+	}
+	method := layout.method()
+	if (method == "HASH" || method == "KEY" || method == "LINEAR HASH" || method == "LINEAR KEY") && leaves > 1 && totalRows > 0 {
+		average := float64(totalRows) / float64(leaves)
+		if float64(maxRows) > 2*average {
+			warnings = append(warnings, fmt.Sprintf("Rows are skewed across the %s partitions: the largest holds %d rows against an average of %.0f; the partition key may have too few distinct values.", method, maxRows, average))
+		}
+	}
+	if unknownRows {
+		warnings = append(warnings, "Some partitions have never been analyzed, so their rows are unknown; run ANALYZE on the table for estimates.")
+	}
+	if len(warnings) > 0 {
+		sb.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func getPartitionsText(t *testing.T, useCase *mockUseCase, params map[string]interface{}) string {
+	result, err := NewGetPartitionsTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	assert.NoError(t, err)
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+}
+
+func TestGetPartitionsShowsPostgresPartitionTree(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"SELECT pg_get_partkeydef": {Rows: [][]interface{}{{"RANGE (created_at)"}}},
+			"FROM pg_partition_tree": {Rows: [][]interface{}{
+				{"events_2024", nil, "FOR VALUES FROM ('2024-01-01') TO ('2025-01-01')", "HASH (tenant_id)", false, int64(-1), nil},
+				{"events_default", nil, "DEFAULT", nil, true, int64(250), int64(65536)},
+				{"events_2024_h0", "events_2024", "FOR VALUES WITH (modulus 2, remainder 0)", nil, true, int64(500), int64(1048576)},
+				{"events_2024_h1", "events_2024", "FOR VALUES WITH (modulus 2, remainder 1)", nil, true, int64(-1), int64(8192)},
+			}},
+		},
+	}
+	text := getPartitionsText(t, useCase, map[string]interface{}{"database": "pg1", "table": "events"})
+
+	assert.Contains(t, useCase.queries[1], "FROM pg_partition_tree(to_regclass($1)) t")
+	assert.Contains(t, text, "# Partitions of events in Database pg1\n\nPartitioning: RANGE (created_at)\nPartitions: 3, rows (estimate): 750, size: 1.1 MiB\n")
+	assert.Contains(t, text, "| events_2024 | - | FOR VALUES FROM ('2024-01-01') TO ('2025-01-01'), sub-partitioned by HASH (tenant_id) | - | - | - |")
+	assert.Contains(t, text, "| events_default | - | DEFAULT | 250 | 33.3% | 64.0 KiB |")
+	assert.Contains(t, text, "| events_2024_h0 | events_2024 | FOR VALUES WITH (modulus 2, remainder 0) | 500 | 66.7% | 1.0 MiB |")
+	assert.Contains(t, text, "| events_2024_h1 | events_2024 | FOR VALUES WITH (modulus 2, remainder 1) | unknown | - | 8.0 KiB |")
+	assert.Contains(t, text, "- The catch-all partition events_default holds about 250 rows;")
+	assert.Contains(t, text, "run ANALYZE on the table")
+}
+
+func TestGetPartitionsShowsMySQLBoundsAndSkew(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{"FROM information_schema.PARTITIONS": {Rows: [][]interface{}{
+			{"p0", nil, "HASH", "`customer_id`", nil, nil, nil, int64(9000), int64(2048)},
+			{"p1", nil, "HASH", "`customer_id`", nil, nil, nil, int64(500), int64(1024)},
+			{"p2", nil, "HASH", "`customer_id`", nil, nil, nil, int64(400), int64(1024)},
+			{"p3", nil, "HASH", "`customer_id`", nil, nil, nil, int64(100), int64(1024)},
+		}}},
+	}
+	text := getPartitionsText(t, useCase, map[string]interface{}{"database": "mysql1", "table": "shop.orders"})
+
+	assert.Contains(t, useCase.queries[0], "WHERE TABLE_NAME = ? AND TABLE_SCHEMA = ?")
+	assert.Contains(t, text, "Partitioning: HASH (customer_id)\nPartitions: 4, rows (estimate): 10000")
+	assert.Contains(t, text, "| p0 | - | - | 9000 | 90.0% | 2.0 KiB |")
+	assert.Contains(t, text, "- Rows are skewed across the HASH partitions: the largest holds 9000 rows against an average of 2500;")
+
+	assert.Equal(t, "VALUES LESS THAN (TO_DAYS('2024-02-01'))", mysqlPartitionBound("RANGE", "TO_DAYS('2024-02-01')"))
+	assert.Equal(t, "VALUES LESS THAN MAXVALUE", mysqlPartitionBound("RANGE COLUMNS", "MAXVALUE"))
+	assert.Equal(t, "VALUES IN ('EU','UK')", mysqlPartitionBound("LIST COLUMNS", "'EU','UK'"))
+}
+
+func TestGetPartitionsListsPartitionedTables(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "tidb",
+		results: map[string]*domain.QueryResult{"GROUP BY TABLE_NAME": {Rows: [][]interface{}{
+			{"logs", "RANGE (to_days(`created`))", int64(12), int64(120000), int64(52428800)},
+		}}},
+	}
+	text := getPartitionsText(t, useCase, map[string]interface{}{"database": "tidb1"})
+	assert.Contains(t, text, "| logs | RANGE (to_days(created)) | 12 | 120000 | 50.0 MiB |")
+
+	_, err := NewGetPartitionsTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "db1", "table": "t"}}, "", &mockUseCase{dbType: "sqlite"})
+	assert.ErrorContains(t, err, "unsupported database type for partitions: sqlite")
+
+	_, err = NewGetPartitionsTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "pg1", "table": "plain"}}, "", &mockUseCase{dbType: "postgres"})
+	assert.ErrorContains(t, err, "table plain does not exist or is not partitioned")
+}
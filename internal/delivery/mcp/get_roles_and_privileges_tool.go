@@ -0,0 +1,310 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// GetRolesAndPrivilegesTool handles reporting roles, memberships and grants
+type GetRolesAndPrivilegesTool struct {
+	BaseToolType
+}
+
+// NewGetRolesAndPrivilegesTool creates a new roles and privileges tool type
+func NewGetRolesAndPrivilegesTool() *GetRolesAndPrivilegesTool {
+	return &GetRolesAndPrivilegesTool{
+		BaseToolType: BaseToolType{
+			name:        "get_roles_and_privileges",
+			description: "Report who can do what in a database, for security reviews. On PostgreSQL it lists the roles with their attributes and memberships, the privileges granted on schemas and on tables, views and sequences, and the default privileges of future objects; on MySQL it lists the accounts with their roles, and the global, database and table privileges. Owners' own privileges are implied and left out. Grants to PUBLIC, superusers that can log in, accounts without passwords and similar risks are listed as warnings.",
+		},
+	}
+}
+
+// CreateTool creates a roles and privileges tool
+func (t *GetRolesAndPrivilegesTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("List roles, their memberships and their schema and table privileges"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("role",
+			tools.Description("Only report this role or user and the grants made to it; grants to PUBLIC are kept on PostgreSQL since they apply to every role (optional)"),
+		),
+		tools.WithString("schema",
+			tools.Description("Only report privileges on this schema (a database on MySQL) and its objects (optional)"),
+		),
+	)
+}
+
+// HandleRequest handles roles and privileges tool requests
+func (t *GetRolesAndPrivilegesTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	role := input.optionalString("role", "")
+	schema := input.optionalString("schema", "")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Getting roles and privileges for database %s, role %s, schema %s", targetDbID, role, schema)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for roles and privileges: %s", dbType)
+	}
+	sections, ok := dialect.PrivilegeSections(role, schema)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for roles and privileges: %s", dbType)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Roles and Privileges in Database %s\n", targetDbID))
+	if err := writeReportSections(ctx, useCase, targetDbID, sections, &response); err != nil {
+		return nil, err
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// postgresPrivilegeSections returns the roles, grants and default privileges of a PostgreSQL
+// database. Grants are read from the ACLs of the catalog, leaving out the owner, who holds
+// every privilege on what it owns; grantee 0 in an ACL is PUBLIC.
+func postgresPrivilegeSections(role, schema string) []reportSection {
+	const grantee = "CASE WHEN a.grantee = 0 THEN 'PUBLIC' ELSE pg_get_userbyid(a.grantee) END"
+	const privileges = "string_agg(a.privilege_type || CASE WHEN a.is_grantable THEN ' (grantable)' ELSE '' END, ', ' ORDER BY a.privilege_type)"
+	const granteeFilter = "($2 = '' OR a.grantee = 0 OR pg_get_userbyid(a.grantee) = $2)"
+	grantParams := []interface{}{schema, role}
+
+	return []reportSection{
+		{
+			title: "Roles",
+			query: `
+SELECT
+    r.rolname AS role_name,
+    r.rolcanlogin AS can_login,
+    r.rolsuper AS superuser,
+    r.rolcreaterole AS create_role,
+    r.rolcreatedb AS create_db,
+    r.rolreplication AS replication,
+    r.rolbypassrls AS bypass_rls,
+    COALESCE((SELECT string_agg(g.rolname || CASE WHEN m.admin_option THEN ' (admin)' ELSE '' END, ', ' ORDER BY g.rolname)
+              FROM pg_auth_members m
+              JOIN pg_roles g ON g.oid = m.roleid
+              WHERE m.member = r.oid), '') AS member_of,
+    r.rolconnlimit AS connection_limit,
+    r.rolvaliduntil AS valid_until
+FROM pg_roles r
+WHERE r.rolname !~ '^pg_' AND ($1 = '' OR r.rolname = $1)
+ORDER BY r.rolname`,
+			params: []interface{}{role},
+			warn: func(row []interface{}) []string {
+				if len(row) < 7 {
+					return nil
+				}
+				var warnings []string
+				name := valueString(row[0])
+				if valueBool(row[1]) && valueBool(row[2]) {
+					warnings = append(warnings, fmt.Sprintf("Role %s is a superuser that can log in; it bypasses every privilege check.", name))
+				}
+				if valueBool(row[6]) && !valueBool(row[2]) {
+					warnings = append(warnings, fmt.Sprintf("Role %s bypasses row-level security policies.", name))
+				}
+				return warnings
+			},
+		},
+		{
+			title: "Schema Privileges",
+			query: fmt.Sprintf(`
+SELECT
+    n.nspname AS schema_name,
+    %s AS grantee,
+    %s AS privileges,
+    pg_get_userbyid(n.nspowner) AS owner
+FROM pg_namespace n
+CROSS JOIN LATERAL aclexplode(n.nspacl) a
+WHERE a.grantee <> n.nspowner
+AND n.nspname <> 'information_schema' AND n.nspname !~ '^pg_'
+AND ($1 = '' OR n.nspname = $1) AND %s
+GROUP BY n.nspname, a.grantee, n.nspowner
+ORDER BY 1, 2`, grantee, privileges, granteeFilter),
+			params: grantParams,
+			warn: func(row []interface{}) []string {
+				if len(row) < 3 || valueString(row[1]) != "PUBLIC" || !strings.Contains(valueString(row[2]), "CREATE") {
+					return nil
+				}
+				return []string{fmt.Sprintf("Every role can create objects in schema %[1]s; revoke it with REVOKE CREATE ON SCHEMA %[1]s FROM PUBLIC.", quoteIdentifier("postgres", valueString(row[0])))}
+			},
+		},
+		{
+			title: "Object Privileges",
+			query: fmt.Sprintf(`
+SELECT
+    n.nspname AS schema_name,
+    c.relname AS object_name,
+    CASE c.relkind
+        WHEN 'r' THEN 'table'
+        WHEN 'p' THEN 'table'
+        WHEN 'v' THEN 'view'
+        WHEN 'm' THEN 'materialized view'
+        WHEN 'S' THEN 'sequence'
+        WHEN 'f' THEN 'foreign table'
+    END AS object_type,
+    %s AS grantee,
+    %s AS privileges,
+    pg_get_userbyid(c.relowner) AS owner
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+CROSS JOIN LATERAL aclexplode(c.relacl) a
+WHERE c.relkind IN ('r', 'p', 'v', 'm', 'S', 'f') AND a.grantee <> c.relowner
+AND n.nspname <> 'information_schema' AND n.nspname !~ '^pg_'
+AND ($1 = '' OR n.nspname = $1) AND %s
+GROUP BY n.nspname, c.relname, c.relkind, a.grantee, c.relowner
+ORDER BY 1, 2, 4`, grantee, privileges, granteeFilter),
+			params: grantParams,
+			warn: func(row []interface{}) []string {
+				if len(row) < 5 || valueString(row[3]) != "PUBLIC" {
+					return nil
+				}
+				return []string{fmt.Sprintf("Every role has %s on %s %s.", valueString(row[4]), valueString(row[2]), qualifiedName(valueString(row[0]), valueString(row[1])))}
+			},
+		},
+		{
+			title: "Default Privileges",
+			query: fmt.Sprintf(`
+SELECT
+    pg_get_userbyid(d.defaclrole) AS created_by,
+    COALESCE(n.nspname, 'all schemas') AS schema_name,
+    CASE d.defaclobjtype
+        WHEN 'r' THEN 'tables'
+        WHEN 'S' THEN 'sequences'
+        WHEN 'f' THEN 'functions'
+        WHEN 'T' THEN 'types'
+        WHEN 'n' THEN 'schemas'
+    END AS object_type,
+    %s AS grantee,
+    %s AS privileges
+FROM pg_default_acl d
+LEFT JOIN pg_namespace n ON n.oid = d.defaclnamespace
+CROSS JOIN LATERAL aclexplode(d.defaclacl) a
+WHERE a.grantee <> d.defaclrole
+AND ($1 = '' OR n.nspname = $1 OR n.oid IS NULL) AND %s
+GROUP BY d.defaclrole, n.nspname, d.defaclobjtype, a.grantee
+ORDER BY 1, 2, 3, 4`, grantee, privileges, granteeFilter),
+			params: grantParams,
+		},
+	}
+}
+
+// mysqlPrivilegeSections returns the accounts and grants of a MySQL server. Grantees are
+// written as 'user'@'host'; global privileges cover every database, and USAGE, which only
+// means the account exists, is left out.
+func mysqlPrivilegeSections(role, schema string) []reportSection {
+	const privileges = "GROUP_CONCAT(PRIVILEGE_TYPE, IF(IS_GRANTABLE = 'YES', ' (grantable)', '') ORDER BY PRIVILEGE_TYPE SEPARATOR ', ')"
+	const granteeFilter = "(? = '' OR SUBSTRING_INDEX(GRANTEE, '@', 1) = CONCAT('''', ?, ''''))"
+	grantParams := []interface{}{schema, schema, role, role}
+
+	return []reportSection{
+		{
+			title: "Accounts",
+			query: `
+SELECT
+    u.User AS user,
+    u.Host AS host,
+    u.account_locked,
+    u.authentication_string = '' AS no_password,
+    (SELECT GROUP_CONCAT(CONCAT(e.FROM_USER, '@', e.FROM_HOST, IF(e.WITH_ADMIN_OPTION = 'Y', ' (admin)', ''))
+            ORDER BY e.FROM_USER, e.FROM_HOST SEPARATOR ', ')
+     FROM mysql.role_edges e
+     WHERE e.TO_USER = u.User AND e.TO_HOST = u.Host) AS member_of
+FROM mysql.user u
+WHERE (? = '' OR u.User = ?)
+ORDER BY u.User, u.Host`,
+			params: []interface{}{role, role},
+			warn: func(row []interface{}) []string {
+				if len(row) < 4 {
+					return nil
+				}
+				account := fmt.Sprintf("'%s'@'%s'", valueString(row[0]), valueString(row[1]))
+				switch {
+				case valueBool(row[2]):
+					return nil
+				case valueString(row[0]) == "":
+					return []string{fmt.Sprintf("Anonymous account %s lets anyone connect; drop it.", account)}
+				case valueBool(row[3]):
+					return []string{fmt.Sprintf("Account %s has no password.", account)}
+				}
+				return nil
+			},
+		},
+		{
+			title: "Global Privileges",
+			query: fmt.Sprintf(`
+SELECT
+    GRANTEE AS grantee,
+    %s AS privileges
+FROM information_schema.USER_PRIVILEGES
+WHERE PRIVILEGE_TYPE <> 'USAGE' AND %s
+GROUP BY GRANTEE
+ORDER BY GRANTEE`, privileges, granteeFilter),
+			params: []interface{}{role, role},
+			warn: func(row []interface{}) []string {
+				if len(row) < 2 {
+					return nil
+				}
+				account, granted := valueString(row[0]), valueString(row[1])
+				var risky []string
+				for _, privilege := range []string{"SUPER", "FILE", "CREATE USER", "SHUTDOWN", "PROCESS"} {
+					if containsFold(strings.Split(strings.ReplaceAll(granted, " (grantable)", ""), ", "), privilege) {
+						risky = append(risky, privilege)
+					}
+				}
+				if len(risky) == 0 {
+					return nil
+				}
+				warning := fmt.Sprintf("Account %s holds the administrative privileges %s", account, strings.Join(risky, ", "))
+				if strings.HasSuffix(account, "@'%'") {
+					warning += " and can connect from any host"
+				}
+				return []string{warning + "."}
+			},
+		},
+		{
+			title: "Database Privileges",
+			query: fmt.Sprintf(`
+SELECT
+    TABLE_SCHEMA AS database_name,
+    GRANTEE AS grantee,
+    %s AS privileges
+FROM information_schema.SCHEMA_PRIVILEGES
+WHERE (? = '' OR TABLE_SCHEMA = ?) AND %s
+GROUP BY TABLE_SCHEMA, GRANTEE
+ORDER BY 1, 2`, privileges, granteeFilter),
+			params: grantParams,
+		},
+		{
+			title: "Table Privileges",
+			query: fmt.Sprintf(`
+SELECT
+    TABLE_SCHEMA AS database_name,
+    TABLE_NAME AS table_name,
+    GRANTEE AS grantee,
+    %s AS privileges
+FROM information_schema.TABLE_PRIVILEGES
+WHERE (? = '' OR TABLE_SCHEMA = ?) AND %s
+GROUP BY TABLE_SCHEMA, TABLE_NAME, GRANTEE
+ORDER BY 1, 2, 3`, privileges, granteeFilter),
+			params: grantParams,
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func getRolesAndPrivilegesText(t *testing.T, useCase *mockUseCase, params map[string]interface{}) string {
+	result, err := NewGetRolesAndPrivilegesTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	assert.NoError(t, err)
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+}
+
+func TestGetRolesAndPrivilegesReviewsPostgres(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_roles r": {
+				Columns: []string{"role_name", "can_login", "superuser", "create_role", "create_db", "replication", "bypass_rls", "member_of", "connection_limit", "valid_until"},
+				Rows: [][]interface{}{
+					{"admin", true, true, true, true, true, true, "", int64(-1), nil},
+					{"analyst", true, false, false, false, false, false, "reporting (admin)", int64(5), nil},
+					{"etl", true, false, false, false, false, true, "", int64(-1), nil},
+				},
+			},
+			"aclexplode(n.nspacl)": {
+				Columns: []string{"schema_name", "grantee", "privileges", "owner"},
+				Rows:    [][]interface{}{{"public", "PUBLIC", "CREATE, USAGE", "pg_database_owner"}},
+			},
+			"aclexplode(c.relacl)": {
+				Columns: []string{"schema_name", "object_name", "object_type", "grantee", "privileges", "owner"},
+				Rows: [][]interface{}{
+					{"public", "users", "table", "PUBLIC", "SELECT", "app"},
+					{"public", "orders", "table", "reporting", "SELECT (grantable)", "app"},
+				},
+			},
+		},
+	}
+	text := getRolesAndPrivilegesText(t, useCase, map[string]interface{}{"database": "pg1", "schema": "public"})
+
+	assert.Len(t, useCase.queries, 4)
+	assert.Contains(t, useCase.queries[1], "a.grantee <> n.nspowner")
+	assert.Contains(t, useCase.queries[2], "($2 = '' OR a.grantee = 0 OR pg_get_userbyid(a.grantee) = $2)")
+	assert.Contains(t, text, "\n## Roles\n\n")
+	assert.Contains(t, text, "reporting (admin)")
+	assert.Contains(t, text, "\n## Default Privileges\n\nNone.\n")
+	assert.Contains(t, text, "- Role admin is a superuser that can log in; it bypasses every privilege check.\n")
+	assert.Contains(t, text, "- Role etl bypasses row-level security policies.\n")
+	assert.NotContains(t, text, "Role admin bypasses")
+	assert.Contains(t, text, "- Every role can create objects in schema \"public\"; revoke it with REVOKE CREATE ON SCHEMA \"public\" FROM PUBLIC.\n")
+	assert.Contains(t, text, "- Every role has SELECT on table public.users.\n")
+	assert.NotContains(t, text, "on table public.orders")
+}
+
+func TestGetRolesAndPrivilegesReviewsMySQL(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"FROM mysql.user u": {
+				Columns: []string{"user", "host", "account_locked", "no_password", "member_of"},
+				Rows: [][]interface{}{
+					{"", "localhost", "N", int64(1), nil},
+					{"app", "%", "N", int64(0), "app_rw@%"},
+					{"backup", "10.0.0.%", "N", int64(1), nil},
+					{"old", "%", "Y", int64(1), nil},
+				},
+			},
+			"information_schema.USER_PRIVILEGES": {
+				Columns: []string{"grantee", "privileges"},
+				Rows: [][]interface{}{
+					{"'app'@'%'", "FILE, SELECT, SUPER (grantable)"},
+					{"'monitor'@'localhost'", "PROCESS"},
+				},
+			},
+		},
+	}
+	text := getRolesAndPrivilegesText(t, useCase, map[string]interface{}{"database": "mysql1", "role": "app"})
+
+	assert.Len(t, useCase.queries, 4)
+	assert.Contains(t, useCase.queries[3], "FROM information_schema.TABLE_PRIVILEGES")
+	assert.Contains(t, text, "- Anonymous account ''@'localhost' lets anyone connect; drop it.\n")
+	assert.Contains(t, text, "- Account 'backup'@'10.0.0.%' has no password.\n")
+	assert.NotContains(t, text, "'old'@'%' has no password")
+	assert.Contains(t, text, "- Account 'app'@'%' holds the administrative privileges SUPER, FILE and can connect from any host.\n")
+	assert.Contains(t, text, "- Account 'monitor'@'localhost' holds the administrative privileges PROCESS.\n")
+	assert.Contains(t, text, "\n## Table Privileges\n\nNone.\n")
+
+	_, err := NewGetRolesAndPrivilegesTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "db1"}}, "", &mockUseCase{dbType: "sqlite"})
+	assert.ErrorContains(t, err, "unsupported database type for roles and privileges: sqlite")
+}
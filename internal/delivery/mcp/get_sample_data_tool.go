@@ -55,48 +55,15 @@ func (t *GetSampleDataTool) CreateTool(name string, dbID string) interface{} {
 
 // HandleRequest handles get sample data tool requests
 func (t *GetSampleDataTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
-	// Extract database ID from parameters
-	targetDbID, ok := request.Parameters["database"].(string)
-	if !ok {
-		return nil, fmt.Errorf("database parameter must be a string")
-	}
-
-	// Extract table name
-	tableName, ok := request.Parameters["table"].(string)
-	if !ok {
-		return nil, fmt.Errorf("table parameter must be a string")
-	}
-
-	// Extract limit (default to 10)
-	limit := 10
-	if request.Parameters["limit"] != nil {
-		if limitParam, ok := request.Parameters["limit"].(float64); ok {
-			limit = int(limitParam)
-		}
-	}
-
-	// Extract where clause (optional)
-	whereClause := ""
-	if request.Parameters["where"] != nil {
-		if whereParam, ok := request.Parameters["where"].(string); ok {
-			whereClause = whereParam
-		}
-	}
-
-	// Extract order by clause (optional)
-	orderByClause := ""
-	if request.Parameters["order_by"] != nil {
-		if orderByParam, ok := request.Parameters["order_by"].(string); ok {
-			orderByClause = orderByParam
-		}
-	}
-
-	// Extract random flag
-	random := false
-	if request.Parameters["random"] != nil {
-		if randomParam, ok := request.Parameters["random"].(bool); ok {
-			random = randomParam
-		}
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	tableName := input.requiredString("table")
+	limit := input.intAtLeast("limit", 10, 1)
+	whereClause := input.optionalString("where", "")
+	orderByClause := input.optionalString("order_by", "")
+	random := input.optionalBool("random", false)
+	if err := input.err(); err != nil {
+		return nil, err
 	}
 
 	logger.Info("Getting sample data for database %s, table %s, limit %d", targetDbID, tableName, limit)
@@ -119,22 +86,15 @@ func (t *GetSampleDataTool) HandleRequest(ctx context.Context, request server.To
 	// Format the response
 	var response strings.Builder
 	response.WriteString(fmt.Sprintf("# Sample Data from Table %s in Database %s\n\n", tableName, targetDbID))
-	response.WriteString(result)
+	response.WriteString(formatQueryResult(result))
 
 	return createTextResponse(response.String()), nil
 }
 
 // buildSampleDataQuery builds a query to retrieve sample data based on parameters
 func buildSampleDataQuery(dbType, tableName string, limit int, whereClause, orderByClause string, random bool) string {
-	// Sanitize table name based on database type
-	var safeTableName string
-	if strings.ToLower(dbType) == "postgres" {
-		// For PostgreSQL, use double quotes for identifiers
-		safeTableName = fmt.Sprintf("\"%s\"", strings.Replace(tableName, "\"", "\"\"", -1))
-	} else {
-		// For MySQL, use backticks for identifiers
-		safeTableName = fmt.Sprintf("`%s`", strings.Replace(tableName, "`", "``", -1))
-	}
+	dialect := dialectFor(dbType)
+	safeTableName := dialect.QuoteIdent(tableName)
 
 	// Build the base query
 	query := fmt.Sprintf("SELECT * FROM %s", safeTableName)
@@ -146,18 +106,12 @@ func buildSampleDataQuery(dbType, tableName string, limit int, whereClause, orde
 
 	// Add ORDER BY clause based on parameters
 	if random {
-		// Use database-specific random function
-		if strings.ToLower(dbType) == "postgres" {
-			query += " ORDER BY RANDOM()"
-		} else {
-			query += " ORDER BY RAND()"
-		}
+		query += " ORDER BY " + dialect.RandomFunc()
 	} else if orderByClause != "" {
 		query += fmt.Sprintf(" ORDER BY %s", orderByClause)
 	}
 
-	// Add LIMIT clause
-	query += fmt.Sprintf(" LIMIT %d", limit)
+	query += " " + dialect.LimitClause(limit)
 
 	return query
 }
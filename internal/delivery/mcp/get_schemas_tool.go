@@ -7,6 +7,7 @@ import (
 
 	"github.com/FreePeak/cortex/pkg/server"
 	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/domain"
 	"github.com/FreePeak/db-mcp-server/internal/logger"
 )
 
@@ -45,26 +46,12 @@ func (t *GetSchemasTool) CreateTool(name string, dbID string) interface{} {
 
 // HandleRequest handles get schemas tool requests
 func (t *GetSchemasTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
-	// Extract database ID from parameters
-	targetDbID, ok := request.Parameters["database"].(string)
-	if !ok {
-		return nil, fmt.Errorf("database parameter must be a string")
-	}
-
-	// Extract schema name (optional)
-	schemaName := ""
-	if request.Parameters["schema"] != nil {
-		if schemaParam, ok := request.Parameters["schema"].(string); ok {
-			schemaName = schemaParam
-		}
-	}
-
-	// Extract include_system_schemas flag
-	includeSystemSchemas := false
-	if request.Parameters["include_system_schemas"] != nil {
-		if includeParam, ok := request.Parameters["include_system_schemas"].(bool); ok {
-			includeSystemSchemas = includeParam
-		}
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schemaName := input.optionalString("schema", "")
+	includeSystemSchemas := input.optionalBool("include_system_schemas", false)
+	if err := input.err(); err != nil {
+		return nil, err
 	}
 
 	logger.Info("Getting schemas for database %s, schema %s, include_system_schemas %v", targetDbID, schemaName, includeSystemSchemas)
@@ -75,16 +62,12 @@ func (t *GetSchemasTool) HandleRequest(ctx context.Context, request server.ToolC
 		return nil, fmt.Errorf("failed to get database type: %w", err)
 	}
 
-	// Define query based on database type
-	var query string
-	switch strings.ToLower(dbType) {
-	case "postgres":
-		query = getPostgresSchemasQuery(schemaName, includeSystemSchemas)
-	case "mysql":
-		query = getMySQLSchemasQuery(schemaName)
-	default:
+	// Build query in the dialect of the database
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
 		return nil, fmt.Errorf("unsupported database type for schemas: %s", dbType)
 	}
+	query := dialect.SchemaQuery(schemaName, includeSystemSchemas)
 
 	// Execute the query
 	result, err := useCase.ExecuteQuery(ctx, targetDbID, query, nil)
@@ -99,11 +82,146 @@ func (t *GetSchemasTool) HandleRequest(ctx context.Context, request server.ToolC
 	} else {
 		response.WriteString(fmt.Sprintf("# Schema Information for %s in Database %s\n\n", schemaName, targetDbID))
 	}
-	response.WriteString(result)
+	if len(result.Columns) > 0 && result.Columns[0] == "catalog_name" {
+		response.WriteString(formatSchemasByCatalog(result))
+	} else if len(result.Columns) == 4 && result.Columns[2] == "parent_table_name" {
+		response.WriteString(formatInterleaveHierarchy(result))
+	} else {
+		response.WriteString(formatQueryResult(result))
+	}
 
 	return createTextResponse(response.String()), nil
 }
 
+// formatSchemasByCatalog formats schemas under a heading per catalog, for engines that
+// federate several catalogs; the first column of the result names the catalog
+func formatSchemasByCatalog(result *domain.QueryResult) string {
+	var catalogs []string
+	byCatalog := make(map[string]*domain.QueryResult)
+	for _, row := range result.Rows {
+		catalog := valueString(row[0])
+		schemas, ok := byCatalog[catalog]
+		if !ok {
+			schemas = &domain.QueryResult{Columns: result.Columns[1:]}
+			byCatalog[catalog] = schemas
+			catalogs = append(catalogs, catalog)
+		}
+		schemas.Rows = append(schemas.Rows, row[1:])
+	}
+	if len(catalogs) == 0 {
+		return formatQueryResult(result)
+	}
+
+	var response strings.Builder
+	for _, catalog := range catalogs {
+		response.WriteString(fmt.Sprintf("## Catalog %s\n\n", catalog))
+		response.WriteString(formatQueryResult(byCatalog[catalog]))
+		response.WriteString("\n\n")
+	}
+	return response.String()
+}
+
+// formatInterleaveHierarchy formats the tables of each schema as a tree, with interleaved
+// tables under their parent; the rows hold schema, table, parent table and on-delete action
+func formatInterleaveHierarchy(result *domain.QueryResult) string {
+	var schemas []string
+	tablesBySchema := make(map[string][]string)
+	children := make(map[string][]string)
+	onDelete := make(map[string]string)
+	parents := make(map[string]string)
+	for _, row := range result.Rows {
+		schema := valueString(row[0])
+		if _, ok := tablesBySchema[schema]; !ok {
+			tablesBySchema[schema] = nil
+			schemas = append(schemas, schema)
+		}
+		table := valueString(row[1])
+		if table == "" {
+			continue
+		}
+		tablesBySchema[schema] = append(tablesBySchema[schema], table)
+		key := schemaTableKey(schema, table)
+		parents[key] = valueString(row[2])
+		onDelete[key] = valueString(row[3])
+		if parent := valueString(row[2]); parent != "" {
+			children[schemaTableKey(schema, parent)] = append(children[schemaTableKey(schema, parent)], table)
+		}
+	}
+	if len(schemas) == 0 {
+		return formatQueryResult(result)
+	}
+
+	var response strings.Builder
+	for _, schema := range schemas {
+		if schema == "" {
+			response.WriteString("## Default schema\n\n")
+		} else {
+			response.WriteString(fmt.Sprintf("## Schema %s\n\n", schema))
+		}
+		tables := tablesBySchema[schema]
+		if len(tables) == 0 {
+			response.WriteString("No tables.\n\n")
+			continue
+		}
+		var writeTree func(table string, depth int)
+		writeTree = func(table string, depth int) {
+			key := schemaTableKey(schema, table)
+			response.WriteString(strings.Repeat("  ", depth) + "- " + table)
+			if depth > 0 && onDelete[key] != "" {
+				response.WriteString(fmt.Sprintf(" (ON DELETE %s)", onDelete[key]))
+			}
+			response.WriteString("\n")
+			for _, child := range children[key] {
+				writeTree(child, depth+1)
+			}
+		}
+		interleaved := 0
+		for _, table := range tables {
+			// Roots are the top-level tables; a parent outside the result keeps its child a root
+			parent := parents[schemaTableKey(schema, table)]
+			if parent != "" {
+				interleaved++
+			}
+			if _, listed := parents[schemaTableKey(schema, parent)]; parent == "" || !listed {
+				writeTree(table, 0)
+			}
+		}
+		response.WriteString(fmt.Sprintf("\n%d tables, %d interleaved in a parent.\n\n", len(tables), interleaved))
+	}
+	return response.String()
+}
+
+// getSpannerSchemasQuery returns a query for the Spanner schemas with one row per table, so
+// get_schemas can show each interleaved table under its parent; the default schema has an
+// empty name
+func getSpannerSchemasQuery(schemaName string, includeSystemSchemas bool) string {
+	baseQuery := `
+SELECT 
+    s.schema_name,
+    t.table_name,
+    t.parent_table_name,
+    t.on_delete_action
+FROM INFORMATION_SCHEMA.SCHEMATA s
+LEFT JOIN INFORMATION_SCHEMA.TABLES t
+    ON t.table_schema = s.schema_name
+    AND t.table_type = 'BASE TABLE'
+WHERE 1 = 1`
+
+	if !includeSystemSchemas {
+		baseQuery += `
+AND s.schema_name NOT IN ('INFORMATION_SCHEMA', 'SPANNER_SYS')`
+	}
+
+	if schemaName != "" {
+		baseQuery += fmt.Sprintf(" AND s.schema_name = %s", quoteLiteral("spanner", schemaName))
+	}
+
+	baseQuery += `
+ORDER BY s.schema_name, t.table_name;`
+
+	return baseQuery
+}
+
 // getPostgresSchemasQuery returns a query for PostgreSQL schemas
 func getPostgresSchemasQuery(schemaName string, includeSystemSchemas bool) string {
 	// Base query for PostgreSQL schemas
@@ -163,3 +281,198 @@ ORDER BY schema_name;`
 
 	return baseQuery
 }
+
+// getSQLiteSchemasQuery returns a query for SQLite schemas (the main, temp and attached databases)
+func getSQLiteSchemasQuery(schemaName string) string {
+	baseQuery := `
+SELECT 
+    d.name AS schema_name,
+    d.file AS file,
+    (SELECT COUNT(*) FROM pragma_table_list t WHERE t.schema = d.name AND t.type = 'table' AND t.name NOT LIKE 'sqlite_%') AS tables_count,
+    (SELECT COUNT(*) FROM pragma_table_list t WHERE t.schema = d.name AND t.type = 'view') AS views_count
+FROM pragma_database_list d`
+
+	if schemaName != "" {
+		// Escape schema name for safety
+		safeSchemaName := strings.Replace(schemaName, "'", "''", -1)
+		baseQuery += fmt.Sprintf(" WHERE d.name = '%s'", safeSchemaName)
+	}
+
+	baseQuery += `
+ORDER BY d.seq;`
+
+	return baseQuery
+}
+
+// getClickHouseSchemasQuery returns a query for ClickHouse databases
+func getClickHouseSchemasQuery(schemaName string, includeSystemSchemas bool) string {
+	baseQuery := `
+SELECT 
+    d.name AS schema_name,
+    d.engine AS engine,
+    t.tables_count,
+    t.views_count
+FROM system.databases d
+LEFT JOIN (
+    SELECT 
+        database,
+        countIf(engine NOT IN ('View', 'MaterializedView', 'LiveView')) AS tables_count,
+        countIf(engine IN ('View', 'MaterializedView', 'LiveView')) AS views_count
+    FROM system.tables
+    GROUP BY database
+) AS t ON t.database = d.name
+WHERE 1 = 1`
+
+	if !includeSystemSchemas {
+		baseQuery += `
+AND d.name NOT IN ('system', 'information_schema', 'INFORMATION_SCHEMA')`
+	}
+
+	if schemaName != "" {
+		baseQuery += fmt.Sprintf(" AND d.name = %s", quoteLiteral("clickhouse", schemaName))
+	}
+
+	baseQuery += `
+ORDER BY d.name;`
+
+	return baseQuery
+}
+
+// getBigQuerySchemasQuery returns a query for the BigQuery datasets of the project in the
+// location the connection runs its jobs in
+func getBigQuerySchemasQuery(schemaName string) string {
+	baseQuery := `
+SELECT 
+    schema_name,
+    location,
+    creation_time,
+    last_modified_time,
+    default_collation_name
+FROM INFORMATION_SCHEMA.SCHEMATA`
+
+	if schemaName != "" {
+		baseQuery += fmt.Sprintf(" WHERE schema_name = %s", quoteLiteral("bigquery", schemaName))
+	}
+
+	baseQuery += `
+ORDER BY schema_name;`
+
+	return baseQuery
+}
+
+// getHANASchemasQuery returns a query for SAP HANA schemas with the number of tables and
+// views in each
+func getHANASchemasQuery(schemaName string, includeSystemSchemas bool) string {
+	baseQuery := `
+SELECT 
+    s.SCHEMA_NAME AS schema_name,
+    s.SCHEMA_OWNER AS owner,
+    s.CREATE_TIME AS created_at,
+    (SELECT COUNT(*) FROM SYS.TABLES t WHERE t.SCHEMA_NAME = s.SCHEMA_NAME) AS tables_count,
+    (SELECT COUNT(*) FROM SYS.VIEWS v WHERE v.SCHEMA_NAME = s.SCHEMA_NAME) AS views_count,
+    (SELECT COUNT(*) FROM SYS.PROCEDURES p WHERE p.SCHEMA_NAME = s.SCHEMA_NAME) AS procedures_count
+FROM SYS.SCHEMAS s
+WHERE 1 = 1`
+
+	if !includeSystemSchemas {
+		baseQuery += `
+AND ` + hanaUserSchemaFilter("s")
+	}
+
+	if schemaName != "" {
+		baseQuery += fmt.Sprintf(" AND s.SCHEMA_NAME = %s", quoteLiteral("hana", schemaName))
+	}
+
+	baseQuery += `
+ORDER BY s.SCHEMA_NAME;`
+
+	return baseQuery
+}
+
+// getFirebirdSchemasQuery returns a query for the Firebird database of the connection with
+// its counts of tables, views and procedures; Firebird has no schemas
+func getFirebirdSchemasQuery(schemaName string) string {
+	baseQuery := `
+SELECT 
+    CAST(RDB$GET_CONTEXT('SYSTEM', 'DB_NAME') AS VARCHAR(255)) AS schema_name,
+    TRIM(d.RDB$CHARACTER_SET_NAME) AS character_set,
+    (SELECT COUNT(*) FROM RDB$RELATIONS r WHERE COALESCE(r.RDB$SYSTEM_FLAG, 0) = 0 AND r.RDB$VIEW_BLR IS NULL) AS tables_count,
+    (SELECT COUNT(*) FROM RDB$RELATIONS r WHERE COALESCE(r.RDB$SYSTEM_FLAG, 0) = 0 AND r.RDB$VIEW_BLR IS NOT NULL) AS views_count,
+    (SELECT COUNT(*) FROM RDB$PROCEDURES p WHERE COALESCE(p.RDB$SYSTEM_FLAG, 0) = 0) AS procedures_count
+FROM RDB$DATABASE d`
+
+	if schemaName != "" {
+		baseQuery += fmt.Sprintf(" WHERE RDB$GET_CONTEXT('SYSTEM', 'DB_NAME') = %s", quoteLiteral("firebird", schemaName))
+	}
+
+	return baseQuery + ";"
+}
+
+// getDatabricksSchemasQuery returns a query for the schemas of every Unity Catalog catalog
+// the user can see, with their number of tables and views; catalogs come first, so
+// get_schemas groups the schemas under their catalog. A name of the form catalog.schema
+// selects the schema of one catalog.
+func getDatabricksSchemasQuery(schemaName string, includeSystemSchemas bool) string {
+	baseQuery := `
+SELECT 
+    s.catalog_name,
+    s.schema_name,
+    s.schema_owner AS owner,
+    s.comment,
+    s.created,
+    COUNT_IF(t.table_type NOT IN ('VIEW', 'MATERIALIZED_VIEW')) AS tables_count,
+    COUNT_IF(t.table_type IN ('VIEW', 'MATERIALIZED_VIEW')) AS views_count
+FROM system.information_schema.schemata s
+LEFT JOIN system.information_schema.tables t
+    ON t.table_catalog = s.catalog_name
+    AND t.table_schema = s.schema_name
+WHERE 1 = 1`
+
+	if !includeSystemSchemas {
+		baseQuery += `
+AND s.catalog_name <> 'system'
+AND s.schema_name <> 'information_schema'`
+	}
+
+	if schemaName != "" {
+		catalog, schema := splitQualifiedName(schemaName)
+		if catalog != "" {
+			baseQuery += fmt.Sprintf(" AND s.catalog_name = %s", quoteLiteral("databricks", catalog))
+		}
+		baseQuery += fmt.Sprintf(" AND s.schema_name = %s", quoteLiteral("databricks", schema))
+	}
+
+	baseQuery += `
+GROUP BY s.catalog_name, s.schema_name, s.schema_owner, s.comment, s.created
+ORDER BY s.catalog_name, s.schema_name;`
+
+	return baseQuery
+}
+
+// getTrinoSchemasQuery returns a query for the schemas of every Trino catalog; catalogs come
+// first, so get_schemas groups the schemas under their catalog
+func getTrinoSchemasQuery(schemaName string, includeSystemSchemas bool) string {
+	baseQuery := `
+SELECT 
+    c.catalog_name,
+    s.table_schem AS schema_name,
+    c.connector_name
+FROM system.metadata.catalogs c
+LEFT JOIN system.jdbc.schemas s ON s.table_catalog = c.catalog_name
+WHERE 1 = 1`
+
+	if !includeSystemSchemas {
+		baseQuery += `
+AND c.catalog_name <> 'system'
+AND (s.table_schem IS NULL OR s.table_schem <> 'information_schema')`
+	}
+
+	if schemaName != "" {
+		baseQuery += fmt.Sprintf(" AND s.table_schem = %s", quoteLiteral("trino", schemaName))
+	}
+
+	baseQuery += `
+ORDER BY c.catalog_name, s.table_schem;`
+
+	return baseQuery
+}
@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestGetSchemasGroupsTrinoCatalogs(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "trino",
+		results: map[string]*domain.QueryResult{"system.metadata.catalogs": {
+			Columns: []string{"catalog_name", "schema_name", "connector_name"},
+			Rows: [][]interface{}{
+				{"hive", "sales", "hive"},
+				{"hive", "web", "hive"},
+				{"pg", "public", "postgresql"},
+			},
+		}},
+	}
+	result, err := NewGetSchemasTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "trino1"}}, "trino1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+
+	assert.Contains(t, useCase.queries[0], "c.catalog_name <> 'system'")
+	assert.Contains(t, text, "## Catalog hive\n")
+	assert.Contains(t, text, "## Catalog pg\n")
+	assert.Less(t, strings.Index(text, "sales"), strings.Index(text, "## Catalog pg"))
+	assert.NotContains(t, text, "catalog_name")
+}
+
+func TestGetSchemasShowsSpannerInterleaving(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "spanner",
+		results: map[string]*domain.QueryResult{"INFORMATION_SCHEMA.SCHEMATA": {
+			Columns: []string{"schema_name", "table_name", "parent_table_name", "on_delete_action"},
+			Rows: [][]interface{}{
+				{"", "Albums", "Singers", "CASCADE"},
+				{"", "Singers", nil, nil},
+				{"", "Songs", "Albums", "NO ACTION"},
+				{"", "Venues", nil, nil},
+				{"archive", nil, nil, nil},
+			},
+		}},
+	}
+	result, err := NewGetSchemasTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "sp1"}}, "sp1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+
+	assert.Contains(t, useCase.queries[0], "s.schema_name NOT IN ('INFORMATION_SCHEMA', 'SPANNER_SYS')")
+	assert.Contains(t, text, "## Default schema\n\n- Singers\n  - Albums (ON DELETE CASCADE)\n    - Songs (ON DELETE NO ACTION)\n- Venues\n\n4 tables, 2 interleaved in a parent.\n")
+	assert.Contains(t, text, "## Schema archive\n\nNo tables.\n")
+}
@@ -0,0 +1,382 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// defaultSequenceWarnPercent is how much of its range a sequence may use before it is warned about
+const defaultSequenceWarnPercent = 75
+
+// GetSequencesTool handles listing sequences and how close they are to running out
+type GetSequencesTool struct {
+	BaseToolType
+}
+
+// NewGetSequencesTool creates a new get sequences tool type
+func NewGetSequencesTool() *GetSequencesTool {
+	return &GetSequencesTool{
+		BaseToolType: BaseToolType{
+			name:        "get_sequences",
+			description: "List the sequences of a database with their current value, increment, minimum and maximum, cache and the column that owns them, and compute how much of its range each one has used. The range ends at the sequence's maximum or at the largest value of the owning column's type, whichever comes first, so a bigint sequence feeding an integer column is measured against the integer limit. MySQL AUTO_INCREMENT counters are listed as sequences of their tables. Use this tool to warn about keys approaching integer overflow before inserts start failing.",
+		},
+	}
+}
+
+// CreateTool creates a get sequences tool
+func (t *GetSequencesTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("List sequences with their current values and the percentage of their range used"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to list (optional, default: all user schemas; the current database for MySQL)"),
+		),
+		tools.WithNumber("warn_percent",
+			tools.Description("Warn about sequences that have used at least this percentage of their range (default: 75)"),
+		),
+	)
+}
+
+// HandleRequest handles get sequences tool requests
+func (t *GetSequencesTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema := input.optionalString("schema", "")
+	warnPercent := input.optionalFloat("warn_percent", defaultSequenceWarnPercent)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if warnPercent <= 0 || warnPercent > 100 {
+		return nil, fmt.Errorf("warn_percent parameter must be greater than 0 and at most 100")
+	}
+
+	logger.Info("Getting sequences for database %s, schema %s", targetDbID, schema)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for sequences: %s", dbType)
+	}
+	query, ok := dialect.SequenceQuery(schema)
+	if !ok {
+		return createTextResponse(fmt.Sprintf("Database type %s has no sequences to list.", dialect.Name())), nil
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, targetDbID, query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sequences: %w", err)
+	}
+
+	var response strings.Builder
+	if schema == "" {
+		response.WriteString(fmt.Sprintf("# Sequences in Database %s\n\n", targetDbID))
+	} else {
+		response.WriteString(fmt.Sprintf("# Sequences in Schema %s of Database %s\n\n", schema, targetDbID))
+	}
+	sequences := readSequenceUsage(result)
+	writeSequenceUsage(&response, sequences, warnPercent)
+	if note := dialect.SequenceUsageNote(); len(sequences) > 0 && note != "" {
+		response.WriteString("\nNote: " + note + "\n")
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// sequenceUsage is a sequence with the part of its range it has used
+type sequenceUsage struct {
+	Schema      string
+	Name        string
+	DataType    string
+	LastValue   string
+	Increment   string
+	MinValue    string
+	MaxValue    string
+	Cache       string
+	Cycle       bool
+	OwnerTable  string
+	OwnerColumn string
+	ColumnType  string
+
+	// Limit is the value the sequence runs out at, and LimitedBy names what sets it
+	Limit       float64
+	LimitText   string
+	LimitedBy   string
+	UsedPercent float64
+}
+
+// readSequenceUsage reads the rows of a sequence query, whose columns are schema_name,
+// sequence_name, data_type, last_value, increment_by, min_value, max_value, cache_size,
+// cycle, owner_table, owner_column and column_type
+func readSequenceUsage(result *domain.QueryResult) []sequenceUsage {
+	var sequences []sequenceUsage
+	for _, row := range result.Rows {
+		if len(row) < 12 {
+			continue
+		}
+		s := sequenceUsage{
+			Schema:      valueString(row[0]),
+			Name:        valueString(row[1]),
+			DataType:    valueString(row[2]),
+			LastValue:   valueString(row[3]),
+			Increment:   valueString(row[4]),
+			MinValue:    valueString(row[5]),
+			MaxValue:    valueString(row[6]),
+			Cache:       valueString(row[7]),
+			Cycle:       valueBool(row[8]),
+			OwnerTable:  valueString(row[9]),
+			OwnerColumn: valueString(row[10]),
+			ColumnType:  valueString(row[11]),
+		}
+		s.measure(row[3], valueFloat64(row[4]), row[5], row[6])
+		sequences = append(sequences, s)
+	}
+	return sequences
+}
+
+// measure computes the limit of a sequence and the percentage of its range it has used.
+// An ascending sequence runs from its minimum up to its maximum, a descending one from its
+// maximum down to its minimum; either end is narrowed to what the owning column can hold.
+// A sequence that has never been used has used nothing.
+func (s *sequenceUsage) measure(last interface{}, increment float64, minValue, maxValue interface{}) {
+	column, hasRange := integerTypeRange(s.ColumnType)
+	descending := increment < 0
+
+	var start float64
+	hasLimit := false
+	if descending {
+		start = valueFloat64(maxValue)
+		if minValue != nil {
+			s.Limit, s.LimitText, s.LimitedBy, hasLimit = valueFloat64(minValue), s.MinValue, "its minimum", true
+		}
+		if hasRange && (!hasLimit || column.Min > s.Limit) {
+			s.Limit, s.LimitText, s.LimitedBy, hasLimit = column.Min, column.MinText, s.columnLimitName("minimum"), true
+		}
+	} else {
+		start = valueFloat64(minValue)
+		if maxValue != nil {
+			s.Limit, s.LimitText, s.LimitedBy, hasLimit = valueFloat64(maxValue), s.MaxValue, "its maximum", true
+		}
+		if hasRange && (!hasLimit || column.Max < s.Limit) {
+			s.Limit, s.LimitText, s.LimitedBy, hasLimit = column.Max, column.MaxText, s.columnLimitName("maximum"), true
+		}
+	}
+	if !hasLimit || last == nil || s.Limit == start {
+		return
+	}
+	used := (valueFloat64(last) - start) / (s.Limit - start) * 100
+	s.UsedPercent = math.Max(0, math.Min(100, used))
+}
+
+// columnLimitName describes the end of the owning column's type as a limit
+func (s *sequenceUsage) columnLimitName(end string) string {
+	return fmt.Sprintf("the %s of the %s column %s", end, s.ColumnType, qualifiedName(s.OwnerTable, s.OwnerColumn))
+}
+
+// integerRange is the range of values of an integer type, with its ends written out exactly
+type integerRange struct {
+	Min, Max         float64
+	MinText, MaxText string
+}
+
+// integerTypeRange returns the values an integer column type holds, for PostgreSQL names
+// such as integer and int8 and MySQL column types such as int(11) unsigned
+func integerTypeRange(columnType string) (integerRange, bool) {
+	columnType = strings.ToLower(strings.TrimSpace(columnType))
+	base := columnType
+	if i := strings.IndexAny(base, "( "); i >= 0 {
+		base = base[:i]
+	}
+	var bits int
+	switch base {
+	case "tinyint":
+		bits = 8
+	case "smallint", "int2":
+		bits = 16
+	case "mediumint":
+		bits = 24
+	case "integer", "int", "int4":
+		bits = 32
+	case "bigint", "int8":
+		bits = 64
+	default:
+		return integerRange{}, false
+	}
+	if strings.Contains(columnType, "unsigned") {
+		max := uint64(math.MaxUint64) >> (64 - bits)
+		return integerRange{Max: float64(max), MinText: "0", MaxText: strconv.FormatUint(max, 10)}, true
+	}
+	max := int64(math.MaxInt64) >> (64 - bits)
+	return integerRange{
+		Min: float64(-max - 1), Max: float64(max),
+		MinText: strconv.FormatInt(-max-1, 10), MaxText: strconv.FormatInt(max, 10),
+	}, true
+}
+
+// writeSequenceUsage renders the sequences and warns about those past the threshold, the
+// fullest first
+func writeSequenceUsage(sb *strings.Builder, sequences []sequenceUsage, warnPercent float64) {
+	if len(sequences) == 0 {
+		sb.WriteString("No sequences found.\n")
+		return
+	}
+	sb.WriteString("| Sequence | Owned by | Type | Last value | Increment | Min | Max | Cache | Cycle | Used |\n")
+	sb.WriteString("|----------|----------|------|------------|-----------|-----|-----|-------|-------|------|\n")
+	var full []sequenceUsage
+	for _, s := range sequences {
+		owner := "-"
+		if s.OwnerColumn != "" {
+			owner = qualifiedName(s.OwnerTable, s.OwnerColumn)
+			if s.ColumnType != "" {
+				owner += " (" + s.ColumnType + ")"
+			}
+		}
+		last := s.LastValue
+		if last == "" {
+			last = "never used"
+		}
+		cycle := "no"
+		if s.Cycle {
+			cycle = "yes"
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s | %s | %s | %.1f%% |\n",
+			qualifiedName(s.Schema, s.Name), owner, s.DataType, last, s.Increment,
+			orDash(s.MinValue), orDash(s.MaxValue), orDash(s.Cache), cycle, s.UsedPercent))
+		if s.UsedPercent >= warnPercent {
+			full = append(full, s)
+		}
+	}
+
+	if len(full) == 0 {
+		return
+	}
+	sort.SliceStable(full, func(i, j int) bool { return full[i].UsedPercent > full[j].UsedPercent })
+	sb.WriteString("\n## Warnings\n\n")
+	for _, s := range full {
+		outcome := "inserts fail once it runs out"
+		if s.Cycle {
+			outcome = "it wraps around and reuses old values once it runs out"
+		}
+		sb.WriteString(fmt.Sprintf("- %s has used %.1f%% of its range: %s of %s, %s; %s.\n",
+			qualifiedName(s.Schema, s.Name), s.UsedPercent, s.LastValue, s.LimitText, s.LimitedBy, outcome))
+	}
+}
+
+// orDash returns a value, or a dash when it is empty
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// getPostgresSequencesQuery returns a query for the sequences of the user schemas, or of one
+// schema, with the column of a serial or identity they belong to. last_value is NULL for a
+// sequence that has never been used or that the user may not read.
+func getPostgresSequencesQuery(schema string) string {
+	query := `
+SELECT
+    ps.schemaname AS schema_name,
+    ps.sequencename AS sequence_name,
+    ps.data_type::text AS data_type,
+    ps.last_value,
+    ps.increment_by,
+    ps.min_value,
+    ps.max_value,
+    ps.cache_size,
+    ps.cycle,
+    CASE WHEN t.oid IS NOT NULL THEN tn.nspname || '.' || t.relname END AS owner_table,
+    a.attname AS owner_column,
+    format_type(a.atttypid, a.atttypmod) AS column_type
+FROM pg_sequences ps
+JOIN pg_namespace sn ON sn.nspname = ps.schemaname
+JOIN pg_class s ON s.relnamespace = sn.oid AND s.relname = ps.sequencename
+LEFT JOIN pg_depend d ON d.classid = 'pg_class'::regclass AND d.objid = s.oid
+    AND d.refclassid = 'pg_class'::regclass AND d.deptype IN ('a', 'i')
+LEFT JOIN pg_class t ON t.oid = d.refobjid
+LEFT JOIN pg_namespace tn ON tn.oid = t.relnamespace
+LEFT JOIN pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid`
+
+	if schema != "" {
+		query += fmt.Sprintf("\nWHERE ps.schemaname = %s", quoteLiteral("postgres", schema))
+	} else {
+		query += "\nWHERE ps.schemaname NOT IN ('pg_catalog', 'information_schema')"
+	}
+
+	query += `
+ORDER BY ps.schemaname, ps.sequencename;`
+
+	return query
+}
+
+// getMySQLSequencesQuery returns a query for the AUTO_INCREMENT counters of the current
+// database, or of one schema. The counter holds the next value, so the last one handed out
+// is one less; an empty table has used none.
+func getMySQLSequencesQuery(schema string) string {
+	schemaExpr := "DATABASE()"
+	if schema != "" {
+		schemaExpr = quoteLiteral("mysql", schema)
+	}
+	return fmt.Sprintf(`
+SELECT
+    t.TABLE_SCHEMA AS schema_name,
+    t.TABLE_NAME AS sequence_name,
+    c.DATA_TYPE AS data_type,
+    IF(t.AUTO_INCREMENT > 1, t.AUTO_INCREMENT - 1, NULL) AS last_value,
+    @@auto_increment_increment AS increment_by,
+    @@auto_increment_offset AS min_value,
+    NULL AS max_value,
+    NULL AS cache_size,
+    'NO' AS cycle,
+    t.TABLE_NAME AS owner_table,
+    c.COLUMN_NAME AS owner_column,
+    c.COLUMN_TYPE AS column_type
+FROM information_schema.TABLES t
+JOIN information_schema.COLUMNS c ON c.TABLE_SCHEMA = t.TABLE_SCHEMA AND c.TABLE_NAME = t.TABLE_NAME
+WHERE t.TABLE_SCHEMA = %s AND t.TABLE_TYPE = 'BASE TABLE' AND c.EXTRA LIKE '%%auto_increment%%'
+ORDER BY t.TABLE_NAME;`, schemaExpr)
+}
+
+// getHANASequencesQuery returns a query for the sequences of the user schemas, or of one
+// schema. The current value is held in memory by each index server, so the highest is taken;
+// a sequence that no server has loaded has none. Identity columns are backed by sequences
+// that the catalog does not link to their column.
+func getHANASequencesQuery(schema string) string {
+	filter := hanaUserSchemaFilter("s")
+	if schema != "" {
+		filter = fmt.Sprintf("s.SCHEMA_NAME = %s", quoteLiteral("hana", schema))
+	}
+	return fmt.Sprintf(`
+SELECT
+    s.SCHEMA_NAME AS schema_name,
+    s.SEQUENCE_NAME AS sequence_name,
+    'BIGINT' AS data_type,
+    (SELECT MAX(m.CURRENT_VALUE) FROM SYS.M_SEQUENCES m
+     WHERE m.SCHEMA_NAME = s.SCHEMA_NAME AND m.SEQUENCE_NAME = s.SEQUENCE_NAME) AS last_value,
+    s.INCREMENT_BY AS increment_by,
+    s.MIN_VALUE AS min_value,
+    s.MAX_VALUE AS max_value,
+    s.CACHE_SIZE AS cache_size,
+    s.IS_CYCLED AS cycle,
+    CAST(NULL AS NVARCHAR(256)) AS owner_table,
+    CAST(NULL AS NVARCHAR(256)) AS owner_column,
+    CAST(NULL AS NVARCHAR(256)) AS column_type
+FROM SYS.SEQUENCES s
+WHERE %s
+ORDER BY s.SCHEMA_NAME, s.SEQUENCE_NAME;`, filter)
+}
@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+var sequenceColumns = []string{"schema_name", "sequence_name", "data_type", "last_value", "increment_by", "min_value", "max_value", "cache_size", "cycle", "owner_table", "owner_column", "column_type"}
+
+func getSequencesText(t *testing.T, useCase *mockUseCase, params map[string]interface{}) string {
+	result, err := NewGetSequencesTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	assert.NoError(t, err)
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+}
+
+func TestGetSequencesMeasuresAgainstOwningColumn(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{"FROM pg_sequences ps": {
+			Columns: sequenceColumns,
+			Rows: [][]interface{}{
+				// A bigint sequence behind an integer column runs out at the integer limit
+				{"public", "orders_id_seq", "bigint", int64(1932735283), int64(1), int64(1), int64(9223372036854775807), int64(1), false, "public.orders", "id", "integer"},
+				{"public", "events_id_seq", "bigint", int64(1000), int64(1), int64(1), int64(9223372036854775807), int64(20), false, "public.events", "id", "bigint"},
+				{"public", "ticket_seq", "smallint", nil, int64(1), int64(1), int64(32767), int64(1), true, nil, nil, nil},
+				{"public", "countdown_seq", "integer", int64(-1800000000), int64(-1), int64(-2147483648), int64(-1), int64(1), false, nil, nil, nil},
+			},
+		}},
+	}
+	text := getSequencesText(t, useCase, map[string]interface{}{"database": "pg1", "schema": "public"})
+
+	query := useCase.queries[0]
+	assert.Contains(t, query, "WHERE ps.schemaname = 'public'")
+	assert.Contains(t, query, "d.deptype IN ('a', 'i')")
+	assert.Contains(t, text, "# Sequences in Schema public of Database pg1\n")
+	assert.Contains(t, text, "| public.orders_id_seq | public.orders.id (integer) | bigint | 1932735283 | 1 | 1 | 9223372036854775807 | 1 | no | 90.0% |")
+	assert.Contains(t, text, "| public.events_id_seq | public.events.id (bigint) | bigint | 1000 | 1 | 1 | 9223372036854775807 | 20 | no | 0.0% |")
+	assert.Contains(t, text, "| public.ticket_seq | - | smallint | never used | 1 | 1 | 32767 | 1 | yes | 0.0% |")
+	assert.Contains(t, text, "| public.countdown_seq | - | integer | -1800000000 | -1 | -2147483648 | -1 | 1 | no | 83.8% |")
+
+	assert.Contains(t, text, "## Warnings\n\n- public.orders_id_seq has used 90.0% of its range: 1932735283 of 2147483647, the maximum of the integer column public.orders.id; inserts fail once it runs out.\n- public.countdown_seq has used 83.8% of its range: -1800000000 of -2147483648, its minimum;")
+	assert.NotContains(t, text, "events_id_seq has used")
+}
+
+func TestGetSequencesReadsMySQLAutoIncrement(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{"AUTO_INCREMENT": {
+			Columns: sequenceColumns,
+			Rows: [][]interface{}{
+				{"shop", "carts", "tinyint", "200", "1", "1", nil, nil, "NO", "carts", "id", "tinyint(3) unsigned"},
+				{"shop", "orders", "int", "100", "1", "1", nil, nil, "NO", "orders", "id", "int(11)"},
+			},
+		}},
+	}
+	text := getSequencesText(t, useCase, map[string]interface{}{"database": "mysql1", "warn_percent": 50})
+
+	assert.Contains(t, useCase.queries[0], "WHERE t.TABLE_SCHEMA = DATABASE()")
+	assert.Contains(t, text, "| shop.carts | carts.id (tinyint(3) unsigned) | tinyint | 200 | 1 | 1 | - | - | no | 78.3% |")
+	assert.Contains(t, text, "- shop.carts has used 78.3% of its range: 200 of 255, the maximum of the tinyint(3) unsigned column carts.id;")
+	assert.NotContains(t, text, "shop.orders has used")
+	assert.Contains(t, text, "information_schema_stats_expiry")
+
+	assert.Contains(t, getMySQLSequencesQuery("sales"), "WHERE t.TABLE_SCHEMA = 'sales'")
+	assert.Contains(t, getHANASequencesQuery(""), "s.SCHEMA_NAME <> 'SYS'")
+}
+
+func TestGetSequencesReportsEnginesWithoutSequences(t *testing.T) {
+	for _, dbType := range []string{"sqlite", "clickhouse", "bigquery", "spanner", "firebird", "trino", "databricks"} {
+		useCase := &mockUseCase{dbType: dbType}
+		text := getSequencesText(t, useCase, map[string]interface{}{"database": "db1"})
+		assert.Contains(t, text, "has no sequences to list", dbType)
+		assert.Empty(t, useCase.queries, dbType)
+	}
+
+	_, err := NewGetSequencesTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "pg1", "warn_percent": 150}}, "", &mockUseCase{dbType: "postgres"})
+	assert.Error(t, err)
+}
+
+func TestIntegerTypeRange(t *testing.T) {
+	for columnType, want := range map[string]string{
+		"integer":             "2147483647",
+		"int8":                "9223372036854775807",
+		"bigint(20) unsigned": "18446744073709551615",
+		"mediumint(8)":        "8388607",
+		"smallint":            "32767",
+	} {
+		r, ok := integerTypeRange(columnType)
+		assert.True(t, ok, columnType)
+		assert.Equal(t, want, r.MaxText, columnType)
+	}
+	r, _ := integerTypeRange("smallint")
+	assert.Equal(t, "-32768", r.MinText)
+	_, ok := integerTypeRange("numeric(20,0)")
+	assert.False(t, ok)
+}
@@ -0,0 +1,216 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// GetTablespacesTool handles reporting tablespaces and the objects stored in them
+type GetTablespacesTool struct {
+	BaseToolType
+}
+
+// NewGetTablespacesTool creates a new get tablespaces tool type
+func NewGetTablespacesTool() *GetTablespacesTool {
+	return &GetTablespacesTool{
+		BaseToolType: BaseToolType{
+			name:        "get_tablespaces",
+			description: "List the tablespaces of a database with their location and size, followed by the largest tables and indexes and the tablespace each lives in, for capacity planning. On PostgreSQL the tablespaces come from pg_tablespace and the objects of the current database are listed. On MySQL the InnoDB data files are grouped into the system, general, undo and temporary tablespaces, with file-per-table tablespaces summed into one row, and indexes always live in the tablespace of their table. Free space held inside the files is called out.",
+		},
+	}
+}
+
+// CreateTool creates a get tablespaces tool
+func (t *GetTablespacesTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("List tablespaces with location and size, and the largest objects in each"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("tablespace",
+			tools.Description("Tablespace name (optional, leave empty for all tablespaces; (file-per-table) selects MySQL file-per-table tablespaces)"),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of tables and indexes to list (default: 50)"),
+		),
+	)
+}
+
+// HandleRequest handles get tablespaces tool requests
+func (t *GetTablespacesTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	tablespace := input.optionalString("tablespace", "")
+	limit := input.intBetween("limit", 50, 1, 10000)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Getting tablespaces for database %s, tablespace %s", targetDbID, tablespace)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for tablespaces: %s", dbType)
+	}
+	sections, ok := dialect.TablespaceSections(tablespace, limit)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for tablespaces: %s", dbType)
+	}
+
+	var response strings.Builder
+	if tablespace == "" {
+		response.WriteString(fmt.Sprintf("# Tablespaces in Database %s\n", targetDbID))
+	} else {
+		response.WriteString(fmt.Sprintf("# Tablespace %s in Database %s\n", tablespace, targetDbID))
+	}
+	if err := writeReportSections(ctx, useCase, targetDbID, sections, &response); err != nil {
+		return nil, err
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// postgresTablespaceSections returns the tablespaces of a PostgreSQL cluster and the largest
+// tables and indexes of the current database. Objects without an explicit tablespace live in
+// the default tablespace of the database. The size of a tablespace other than the database
+// default is only visible with CREATE on it or membership in pg_read_all_stats.
+func postgresTablespaceSections(tablespace string, limit int) []reportSection {
+	return []reportSection{
+		{
+			title: "Tablespaces",
+			query: `
+SELECT
+    t.spcname AS tablespace,
+    pg_get_userbyid(t.spcowner) AS owner,
+    CASE WHEN t.spcname IN ('pg_default', 'pg_global') THEN '(data directory)' ELSE pg_tablespace_location(t.oid) END AS location,
+    CASE
+        WHEN t.oid = d.dattablespace
+          OR has_tablespace_privilege(t.oid, 'CREATE')
+          OR pg_has_role('pg_read_all_stats', 'MEMBER')
+        THEN pg_size_pretty(pg_tablespace_size(t.oid))
+    END AS size,
+    (SELECT count(*) FROM pg_class c
+     WHERE c.relkind IN ('r', 'p', 'm', 'i', 'I')
+       AND (c.reltablespace = t.oid OR (c.reltablespace = 0 AND t.oid = d.dattablespace))) AS objects_in_database,
+    t.oid = d.dattablespace AS database_default,
+    COALESCE(array_to_string(t.spcoptions, ', '), '') AS options
+FROM pg_tablespace t
+CROSS JOIN pg_database d
+WHERE d.datname = current_database()
+  AND ($1 = '' OR t.spcname = $1)
+ORDER BY 1`,
+			params: []interface{}{tablespace},
+			warn: func(row []interface{}) []string {
+				if len(row) < 4 || row[3] != nil {
+					return nil
+				}
+				return []string{fmt.Sprintf("The size of tablespace %s is not visible to this role; it needs CREATE on the tablespace or membership in pg_read_all_stats.", valueString(row[0]))}
+			},
+		},
+		{
+			title: "Largest Tables and Indexes",
+			query: `
+SELECT
+    COALESCE(t.spcname, dt.spcname) AS tablespace,
+    n.nspname AS schema_name,
+    c.relname AS object_name,
+    CASE WHEN c.relkind = 'i' THEN 'index' WHEN c.relkind = 'm' THEN 'materialized view' ELSE 'table' END AS kind,
+    pg_size_pretty(CASE WHEN c.relkind = 'i' THEN pg_relation_size(c.oid) ELSE pg_table_size(c.oid) END) AS size
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+CROSS JOIN pg_database d
+JOIN pg_tablespace dt ON dt.oid = d.dattablespace
+LEFT JOIN pg_tablespace t ON t.oid = c.reltablespace
+WHERE d.datname = current_database()
+  AND c.relkind IN ('r', 'm', 'i')
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg_toast%'
+  AND ($1 = '' OR COALESCE(t.spcname, dt.spcname) = $1)
+ORDER BY CASE WHEN c.relkind = 'i' THEN pg_relation_size(c.oid) ELSE pg_table_size(c.oid) END DESC
+LIMIT $2`,
+			params: []interface{}{tablespace, limit},
+		},
+	}
+}
+
+// mysqlTablespaceFreeWarnMB is the free space inside a MySQL tablespace worth pointing out
+const mysqlTablespaceFreeWarnMB = 1024
+
+// mysqlTablespaceSections returns the InnoDB tablespaces of a MySQL server, with
+// file-per-table tablespaces summed into one row, and the largest tables with their tablespace
+func mysqlTablespaceSections(tablespace string, limit int) []reportSection {
+	return []reportSection{
+		{
+			title: "Tablespaces",
+			query: `
+SELECT *
+FROM (
+    SELECT
+        CASE
+            WHEN f.TABLESPACE_NAME LIKE '%/%' OR f.TABLESPACE_NAME LIKE 'innodb_file_per_table%' THEN '(file-per-table)'
+            ELSE f.TABLESPACE_NAME
+        END AS tablespace,
+        f.FILE_TYPE AS file_type,
+        COUNT(*) AS files,
+        CASE WHEN COUNT(*) = 1 THEN MAX(f.FILE_NAME) ELSE '' END AS location,
+        ROUND(SUM(f.TOTAL_EXTENTS * f.EXTENT_SIZE) / 1024 / 1024, 1) AS size_mb,
+        ROUND(SUM(f.DATA_FREE) / 1024 / 1024, 1) AS free_mb
+    FROM information_schema.FILES f
+    WHERE f.ENGINE = 'InnoDB'
+    GROUP BY 1, 2
+) ts
+WHERE ? = '' OR ts.tablespace = ?
+ORDER BY ts.size_mb DESC`,
+			params: []interface{}{tablespace, tablespace},
+			warn: func(row []interface{}) []string {
+				if len(row) < 6 {
+					return nil
+				}
+				name, size, free := valueString(row[0]), valueFloat64(row[4]), valueFloat64(row[5])
+				if free < mysqlTablespaceFreeWarnMB || free < size/4 {
+					return nil
+				}
+				if name == "(file-per-table)" {
+					return []string{fmt.Sprintf("File-per-table tablespaces hold %.1f MB of free space; rebuilding the tables with the most free space (run_maintenance with optimize) returns it to the filesystem.", free)}
+				}
+				return []string{fmt.Sprintf("Tablespace %s holds %.1f MB of free space; it is reused for new data but never returned to the filesystem.", name, free)}
+			},
+		},
+		{
+			title: "Largest Tables",
+			query: `
+SELECT *
+FROM (
+    SELECT
+        CASE WHEN ts.SPACE_TYPE = 'Single' THEN '(file-per-table)' ELSE COALESCE(ts.NAME, '') END AS tablespace,
+        t.TABLE_SCHEMA AS schema_name,
+        t.TABLE_NAME AS table_name,
+        t.ENGINE AS engine,
+        ROUND(t.DATA_LENGTH / 1024 / 1024, 1) AS data_mb,
+        ROUND(t.INDEX_LENGTH / 1024 / 1024, 1) AS index_mb,
+        ROUND(t.DATA_FREE / 1024 / 1024, 1) AS free_mb
+    FROM information_schema.TABLES t
+    LEFT JOIN information_schema.INNODB_TABLES it ON it.NAME = CONCAT(t.TABLE_SCHEMA, '/', t.TABLE_NAME)
+    LEFT JOIN information_schema.INNODB_TABLESPACES ts ON ts.SPACE = it.SPACE
+    WHERE t.TABLE_TYPE = 'BASE TABLE'
+      AND t.TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+) tbl
+WHERE ? = '' OR tbl.tablespace = ?
+ORDER BY tbl.data_mb + tbl.index_mb DESC
+LIMIT ?`,
+			params: []interface{}{tablespace, tablespace, limit},
+		},
+	}
+}
@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestGetTablespacesReportsTablespaces(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"FROM information_schema.FILES f": {
+				Columns: []string{"tablespace", "file_type", "files", "location", "size_mb", "free_mb"},
+				Rows: [][]interface{}{
+					{"(file-per-table)", "TABLESPACE", int64(120), "", []byte("20480.0"), []byte("8192.0")},
+					{"innodb_system", "TABLESPACE", int64(1), "./ibdata1", []byte("12.0"), []byte("4.0")},
+					{"archive", "TABLESPACE", int64(1), "/data/archive.ibd", []byte("4096.0"), []byte("2048.0")},
+				},
+			},
+		},
+	}
+	result, err := NewGetTablespacesTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "db1"}}, "", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+
+	assert.Len(t, useCase.queries, 2)
+	assert.Contains(t, text, "/data/archive.ibd")
+	assert.Contains(t, text, "\n## Largest Tables\n\nNone.\n")
+	assert.Contains(t, text, "- File-per-table tablespaces hold 8192.0 MB of free space")
+	assert.Contains(t, text, "- Tablespace archive holds 2048.0 MB of free space; it is reused for new data but never returned")
+	assert.NotContains(t, text, "innodb_system holds")
+
+	useCase = &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_tablespace t\nCROSS JOIN": {
+				Columns: []string{"tablespace", "owner", "location", "size", "objects_in_database", "database_default", "options"},
+				Rows:    [][]interface{}{{"fast", "postgres", "/mnt/nvme/pg", nil, int64(3), false, ""}},
+			},
+		},
+	}
+	result, err = NewGetTablespacesTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "pg1", "tablespace": "fast"}}, "", useCase)
+	assert.NoError(t, err)
+	text = result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "# Tablespace fast in Database pg1")
+	assert.Contains(t, text, "- The size of tablespace fast is not visible to this role")
+
+	_, err = NewGetTablespacesTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "lite"}}, "", &mockUseCase{dbType: "sqlite"})
+	assert.ErrorContains(t, err, "unsupported database type for tablespaces: sqlite")
+}
@@ -42,18 +42,11 @@ func (t *GetTypesTool) CreateTool(name string, dbID string) interface{} {
 
 // HandleRequest handles get types tool requests
 func (t *GetTypesTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
-	// Extract database ID from parameters
-	targetDbID, ok := request.Parameters["database"].(string)
-	if !ok {
-		return nil, fmt.Errorf("database parameter must be a string")
-	}
-
-	// Extract type name (optional)
-	typeName := ""
-	if request.Parameters["type_name"] != nil {
-		if typeParam, ok := request.Parameters["type_name"].(string); ok {
-			typeName = typeParam
-		}
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	typeName := input.optionalString("type_name", "")
+	if err := input.err(); err != nil {
+		return nil, err
 	}
 
 	logger.Info("Getting custom data types for database %s, type %s", targetDbID, typeName)
@@ -64,17 +57,16 @@ func (t *GetTypesTool) HandleRequest(ctx context.Context, request server.ToolCal
 		return nil, fmt.Errorf("failed to get database type: %w", err)
 	}
 
-	// Define query based on database type
-	var query string
-	switch strings.ToLower(dbType) {
-	case "postgres":
-		query = getPostgresTypesQuery(typeName)
-	case "mysql":
-		// MySQL doesn't have true custom types like PostgreSQL
-		return createTextResponse("MySQL does not support custom data types in the same way as PostgreSQL. It only has built-in data types."), nil
-	default:
+	// Build query in the dialect of the database
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
 		return nil, fmt.Errorf("unsupported database type for custom data types: %s", dbType)
 	}
+	query, ok := dialect.TypeQuery(typeName)
+	if !ok {
+		// MySQL doesn't have true custom types like PostgreSQL
+		return createTextResponse(fmt.Sprintf("Database type %s does not support custom data types in the same way as PostgreSQL. It only has built-in data types.", dialect.Name())), nil
+	}
 
 	// Execute the query
 	result, err := useCase.ExecuteQuery(ctx, targetDbID, query, nil)
@@ -89,7 +81,7 @@ func (t *GetTypesTool) HandleRequest(ctx context.Context, request server.ToolCal
 	} else {
 		response.WriteString(fmt.Sprintf("# Custom Data Type Definition for %s in Database %s\n\n", typeName, targetDbID))
 	}
-	response.WriteString(result)
+	response.WriteString(formatQueryResult(result))
 
 	return createTextResponse(response.String()), nil
 }
@@ -143,3 +135,29 @@ ORDER BY n.nspname, t.typname;`
 
 	return baseQuery
 }
+
+// getFirebirdDomainsQuery returns a query for Firebird domains, the named column types that
+// carry their own default, NOT NULL and CHECK rules
+func getFirebirdDomainsQuery(typeName string) string {
+	baseQuery := `
+SELECT 
+    TRIM(f.RDB$FIELD_NAME) AS type_name,
+    'DOMAIN' AS type_category,
+    ` + firebirdTypeExpression("f") + ` ||
+        CASE WHEN f.RDB$NULL_FLAG = 1 THEN ' NOT NULL' ELSE '' END AS type_definition,
+    CAST(f.RDB$DEFAULT_SOURCE AS VARCHAR(1024)) AS default_value,
+    CAST(f.RDB$VALIDATION_SOURCE AS VARCHAR(8191)) AS check_clause,
+    CAST(f.RDB$DESCRIPTION AS VARCHAR(8191)) AS description
+FROM RDB$FIELDS f
+WHERE COALESCE(f.RDB$SYSTEM_FLAG, 0) = 0
+AND f.RDB$FIELD_NAME NOT STARTING WITH 'RDB$'`
+
+	if typeName != "" {
+		baseQuery += fmt.Sprintf(" AND f.RDB$FIELD_NAME = %s", quoteLiteral("firebird", typeName))
+	}
+
+	baseQuery += `
+ORDER BY f.RDB$FIELD_NAME;`
+
+	return baseQuery
+}
@@ -59,54 +59,16 @@ func (t *GetUniqueValuesTool) CreateTool(name string, dbID string) interface{} {
 
 // HandleRequest handles get unique values tool requests
 func (t *GetUniqueValuesTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
-	// Extract database ID from parameters
-	targetDbID, ok := request.Parameters["database"].(string)
-	if !ok {
-		return nil, fmt.Errorf("database parameter must be a string")
-	}
-
-	// Extract table name
-	tableName, ok := request.Parameters["table"].(string)
-	if !ok {
-		return nil, fmt.Errorf("table parameter must be a string")
-	}
-
-	// Extract column name
-	columnName, ok := request.Parameters["column"].(string)
-	if !ok {
-		return nil, fmt.Errorf("column parameter must be a string")
-	}
-
-	// Extract limit (default to 100)
-	limit := 100
-	if request.Parameters["limit"] != nil {
-		if limitParam, ok := request.Parameters["limit"].(float64); ok {
-			limit = int(limitParam)
-		}
-	}
-
-	// Extract where clause (optional)
-	whereClause := ""
-	if request.Parameters["where"] != nil {
-		if whereParam, ok := request.Parameters["where"].(string); ok {
-			whereClause = whereParam
-		}
-	}
-
-	// Extract include_counts flag (default to true)
-	includeCounts := true
-	if request.Parameters["include_counts"] != nil {
-		if includeCountsParam, ok := request.Parameters["include_counts"].(bool); ok {
-			includeCounts = includeCountsParam
-		}
-	}
-
-	// Extract include_nulls flag (default to true)
-	includeNulls := true
-	if request.Parameters["include_nulls"] != nil {
-		if includeNullsParam, ok := request.Parameters["include_nulls"].(bool); ok {
-			includeNulls = includeNullsParam
-		}
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	tableName := input.requiredString("table")
+	columnName := input.requiredString("column")
+	limit := input.intAtLeast("limit", 100, 1)
+	whereClause := input.optionalString("where", "")
+	includeCounts := input.optionalBool("include_counts", true)
+	includeNulls := input.optionalBool("include_nulls", true)
+	if err := input.err(); err != nil {
+		return nil, err
 	}
 
 	logger.Info("Getting unique values for database %s, table %s, column %s", targetDbID, tableName, columnName)
@@ -129,24 +91,16 @@ func (t *GetUniqueValuesTool) HandleRequest(ctx context.Context, request server.
 	// Format the response
 	var response strings.Builder
 	response.WriteString(fmt.Sprintf("# Unique Values in Column %s of Table %s in Database %s\n\n", columnName, tableName, targetDbID))
-	response.WriteString(result)
+	response.WriteString(formatQueryResult(result))
 
 	return createTextResponse(response.String()), nil
 }
 
 // buildUniqueValuesQuery builds a query to retrieve unique values based on parameters
 func buildUniqueValuesQuery(dbType, tableName, columnName string, limit int, whereClause string, includeCounts, includeNulls bool) string {
-	// Sanitize identifiers based on database type
-	var safeTableName, safeColumnName string
-	if strings.ToLower(dbType) == "postgres" {
-		// For PostgreSQL, use double quotes for identifiers
-		safeTableName = fmt.Sprintf("\"%s\"", strings.Replace(tableName, "\"", "\"\"", -1))
-		safeColumnName = fmt.Sprintf("\"%s\"", strings.Replace(columnName, "\"", "\"\"", -1))
-	} else {
-		// For MySQL, use backticks for identifiers
-		safeTableName = fmt.Sprintf("`%s`", strings.Replace(tableName, "`", "``", -1))
-		safeColumnName = fmt.Sprintf("`%s`", strings.Replace(columnName, "`", "``", -1))
-	}
+	dialect := dialectFor(dbType)
+	safeTableName := dialect.QuoteIdent(tableName)
+	safeColumnName := dialect.QuoteIdent(columnName)
 
 	// Build the base query
 	var query string
@@ -180,8 +134,7 @@ func buildUniqueValuesQuery(dbType, tableName, columnName string, limit int, whe
 		query += fmt.Sprintf(" ORDER BY %s", safeColumnName)
 	}
 
-	// Add LIMIT clause
-	query += fmt.Sprintf(" LIMIT %d", limit)
+	query += " " + dialect.LimitClause(limit)
 
 	return query
 }
@@ -45,26 +45,12 @@ func (t *GetViewsTool) CreateTool(name string, dbID string) interface{} {
 
 // HandleRequest handles get views tool requests
 func (t *GetViewsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
-	// Extract database ID from parameters
-	targetDbID, ok := request.Parameters["database"].(string)
-	if !ok {
-		return nil, fmt.Errorf("database parameter must be a string")
-	}
-
-	// Extract view name (optional)
-	viewName := ""
-	if request.Parameters["view"] != nil {
-		if viewParam, ok := request.Parameters["view"].(string); ok {
-			viewName = viewParam
-		}
-	}
-
-	// Extract include_definition flag (default to true)
-	includeDefinition := true
-	if request.Parameters["include_definition"] != nil {
-		if includeDefParam, ok := request.Parameters["include_definition"].(bool); ok {
-			includeDefinition = includeDefParam
-		}
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	viewName := input.optionalString("view", "")
+	includeDefinition := input.optionalBool("include_definition", true)
+	if err := input.err(); err != nil {
+		return nil, err
 	}
 
 	logger.Info("Getting views for database %s, view %s, include_definition %v", targetDbID, viewName, includeDefinition)
@@ -75,16 +61,12 @@ func (t *GetViewsTool) HandleRequest(ctx context.Context, request server.ToolCal
 		return nil, fmt.Errorf("failed to get database type: %w", err)
 	}
 
-	// Define query based on database type
-	var query string
-	switch strings.ToLower(dbType) {
-	case "postgres":
-		query = getPostgresViewsQuery(viewName, includeDefinition)
-	case "mysql":
-		query = getMySQLViewsQuery(viewName, includeDefinition)
-	default:
+	// Build query in the dialect of the database
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
 		return nil, fmt.Errorf("unsupported database type for views: %s", dbType)
 	}
+	query := dialect.ViewQuery(viewName, includeDefinition)
 
 	// Execute the query
 	result, err := useCase.ExecuteQuery(ctx, targetDbID, query, nil)
@@ -99,7 +81,7 @@ func (t *GetViewsTool) HandleRequest(ctx context.Context, request server.ToolCal
 	} else {
 		response.WriteString(fmt.Sprintf("# View Definition for %s in Database %s\n\n", viewName, targetDbID))
 	}
-	response.WriteString(result)
+	response.WriteString(formatQueryResult(result))
 
 	return createTextResponse(response.String()), nil
 }
@@ -178,3 +160,285 @@ ORDER BY table_schema, table_name;`
 
 	return baseQuery
 }
+
+// getSQLiteViewsQuery returns a query for SQLite views
+func getSQLiteViewsQuery(viewName string, includeDefinition bool) string {
+	// Base query for SQLite views
+	baseQuery := `
+SELECT 
+    'main' AS schema_name,
+    name AS view_name`
+
+	if includeDefinition {
+		baseQuery += `,
+    sql AS view_definition`
+	} else {
+		baseQuery += `,
+    'Definition not included' AS view_definition`
+	}
+
+	baseQuery += `
+FROM sqlite_master
+WHERE type = 'view'`
+
+	if viewName != "" {
+		// Escape view name for safety
+		safeViewName := strings.Replace(viewName, "'", "''", -1)
+		baseQuery += fmt.Sprintf(" AND name = '%s'", safeViewName)
+	}
+
+	baseQuery += `
+ORDER BY name;`
+
+	return baseQuery
+}
+
+// getClickHouseViewsQuery returns a query for ClickHouse views, materialized views included
+func getClickHouseViewsQuery(viewName string, includeDefinition bool) string {
+	// Base query for ClickHouse views
+	baseQuery := `
+SELECT 
+    database AS schema_name,
+    name AS view_name,
+    engine AS view_type`
+
+	if includeDefinition {
+		baseQuery += `,
+    as_select AS view_definition`
+	} else {
+		baseQuery += `,
+    'Definition not included' AS view_definition`
+	}
+
+	baseQuery += `
+FROM system.tables
+WHERE database = currentDatabase()
+AND engine IN ('View', 'MaterializedView', 'LiveView')`
+
+	if viewName != "" {
+		baseQuery += fmt.Sprintf(" AND name = %s", quoteLiteral("clickhouse", viewName))
+	}
+
+	baseQuery += `
+ORDER BY name;`
+
+	return baseQuery
+}
+
+// getBigQueryViewsQuery returns a query for BigQuery views, materialized views included
+func getBigQueryViewsQuery(viewName string, includeDefinition bool) string {
+	// Base query for BigQuery views
+	baseQuery := `
+SELECT 
+    table_schema AS schema_name,
+    table_name AS view_name,
+    table_type AS view_type`
+
+	if includeDefinition {
+		baseQuery += `,
+    ddl AS view_definition`
+	} else {
+		baseQuery += `,
+    'Definition not included' AS view_definition`
+	}
+
+	baseQuery += `
+FROM INFORMATION_SCHEMA.TABLES
+WHERE table_type IN ('VIEW', 'MATERIALIZED VIEW')`
+
+	if viewName != "" {
+		baseQuery += fmt.Sprintf(" AND table_name = %s", quoteLiteral("bigquery", viewName))
+	}
+
+	baseQuery += `
+ORDER BY table_name;`
+
+	return baseQuery
+}
+
+// getSpannerViewsQuery returns a query for Spanner views of the user schemas
+func getSpannerViewsQuery(viewName string, includeDefinition bool) string {
+	// Base query for Spanner views
+	baseQuery := `
+SELECT 
+    table_schema AS schema_name,
+    table_name AS view_name,
+    security_type`
+
+	if includeDefinition {
+		baseQuery += `,
+    view_definition`
+	} else {
+		baseQuery += `,
+    'Definition not included' AS view_definition`
+	}
+
+	baseQuery += `
+FROM INFORMATION_SCHEMA.VIEWS
+WHERE table_schema NOT IN ('INFORMATION_SCHEMA', 'SPANNER_SYS')`
+
+	if viewName != "" {
+		baseQuery += " AND " + spannerTableFilter("VIEWS", viewName)
+	}
+
+	baseQuery += `
+ORDER BY table_schema, table_name;`
+
+	return baseQuery
+}
+
+// getHANAViewsQuery returns a query for SAP HANA views of the user schemas; calculation
+// views generated by the repository are listed with their view type
+func getHANAViewsQuery(viewName string, includeDefinition bool) string {
+	// Base query for HANA views
+	baseQuery := `
+SELECT 
+    SCHEMA_NAME AS schema_name,
+    VIEW_NAME AS view_name,
+    VIEW_TYPE AS view_type,
+    IS_VALID AS is_valid,
+    COMMENTS AS description`
+
+	if includeDefinition {
+		baseQuery += `,
+    DEFINITION AS view_definition`
+	} else {
+		baseQuery += `,
+    'Definition not included' AS view_definition`
+	}
+
+	baseQuery += `
+FROM SYS.VIEWS v
+WHERE ` + hanaUserSchemaFilter("v")
+
+	if viewName != "" {
+		schema, view := splitQualifiedName(viewName)
+		schemaFilter := "CURRENT_SCHEMA"
+		if schema != "" {
+			schemaFilter = quoteLiteral("hana", schema)
+		}
+		baseQuery += fmt.Sprintf(" AND v.SCHEMA_NAME = %s AND v.VIEW_NAME = %s", schemaFilter, quoteLiteral("hana", view))
+	}
+
+	baseQuery += `
+ORDER BY SCHEMA_NAME, VIEW_NAME;`
+
+	return baseQuery
+}
+
+// getFirebirdViewsQuery returns a query for Firebird views; their source is kept as written
+// in RDB$VIEW_SOURCE
+func getFirebirdViewsQuery(viewName string, includeDefinition bool) string {
+	// Base query for Firebird views
+	baseQuery := `
+SELECT 
+    TRIM(r.RDB$RELATION_NAME) AS view_name,
+    TRIM(r.RDB$OWNER_NAME) AS owner,
+    CAST(r.RDB$DESCRIPTION AS VARCHAR(8191)) AS description,
+    (SELECT LIST(DISTINCT TRIM(d.RDB$DEPENDED_ON_NAME), ', ')
+     FROM RDB$DEPENDENCIES d
+     WHERE d.RDB$DEPENDENT_NAME = r.RDB$RELATION_NAME
+     AND d.RDB$DEPENDENT_TYPE = 1
+     AND d.RDB$DEPENDED_ON_TYPE = 0) AS base_tables`
+
+	if includeDefinition {
+		baseQuery += `,
+    CAST(r.RDB$VIEW_SOURCE AS VARCHAR(32765)) AS view_definition`
+	} else {
+		baseQuery += `,
+    'Definition not included' AS view_definition`
+	}
+
+	baseQuery += `
+FROM RDB$RELATIONS r
+WHERE r.RDB$VIEW_BLR IS NOT NULL
+AND COALESCE(r.RDB$SYSTEM_FLAG, 0) = 0`
+
+	if viewName != "" {
+		baseQuery += fmt.Sprintf(" AND r.RDB$RELATION_NAME = %s", quoteLiteral("firebird", viewName))
+	}
+
+	baseQuery += `
+ORDER BY r.RDB$RELATION_NAME;`
+
+	return baseQuery
+}
+
+// getDatabricksViewsQuery returns a query for the views and materialized views of every
+// Unity Catalog catalog; a name may be qualified as schema.view or catalog.schema.view
+func getDatabricksViewsQuery(viewName string, includeDefinition bool) string {
+	// Base query for Databricks views
+	baseQuery := `
+SELECT 
+    v.table_catalog AS catalog_name,
+    v.table_schema AS schema_name,
+    v.table_name AS view_name,
+    t.table_type AS view_type,
+    t.table_owner AS owner,
+    t.comment`
+
+	if includeDefinition {
+		baseQuery += `,
+    v.view_definition`
+	} else {
+		baseQuery += `,
+    'Definition not included' AS view_definition`
+	}
+
+	baseQuery += `
+FROM system.information_schema.views v
+JOIN system.information_schema.tables t
+    ON t.table_catalog = v.table_catalog
+    AND t.table_schema = v.table_schema
+    AND t.table_name = v.table_name
+WHERE v.table_catalog <> 'system'
+AND v.table_schema <> 'information_schema'`
+
+	if viewName != "" {
+		schema, view := splitQualifiedName(viewName)
+		catalog, schema := splitQualifiedName(schema)
+		if catalog != "" {
+			baseQuery += fmt.Sprintf(" AND v.table_catalog = %s", quoteLiteral("databricks", catalog))
+		}
+		if schema != "" {
+			baseQuery += fmt.Sprintf(" AND v.table_schema = %s", quoteLiteral("databricks", schema))
+		}
+		baseQuery += fmt.Sprintf(" AND v.table_name = %s", quoteLiteral("databricks", view))
+	}
+
+	baseQuery += `
+ORDER BY v.table_catalog, v.table_schema, v.table_name;`
+
+	return baseQuery
+}
+
+// getTrinoViewsQuery returns a query for the views of the default catalog
+func getTrinoViewsQuery(viewName string, includeDefinition bool) string {
+	// Base query for Trino views
+	baseQuery := `
+SELECT 
+    table_catalog AS catalog_name,
+    table_schema AS schema_name,
+    table_name AS view_name`
+
+	if includeDefinition {
+		baseQuery += `,
+    view_definition`
+	} else {
+		baseQuery += `,
+    'Definition not included' AS view_definition`
+	}
+
+	baseQuery += `
+FROM information_schema.views
+WHERE table_schema <> 'information_schema'`
+
+	if viewName != "" {
+		baseQuery += fmt.Sprintf(" AND table_name = %s", quoteLiteral("trino", viewName))
+	}
+
+	baseQuery += `
+ORDER BY table_schema, table_name;`
+
+	return baseQuery
+}
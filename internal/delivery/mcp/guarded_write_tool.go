@@ -0,0 +1,251 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// ModifyRowsTool handles guarded UPDATE statements built from structured parameters
+type ModifyRowsTool struct {
+	BaseToolType
+}
+
+// NewModifyRowsTool creates a new modify rows tool type
+func NewModifyRowsTool() *ModifyRowsTool {
+	return &ModifyRowsTool{
+		BaseToolType: BaseToolType{
+			name:        "modify_rows",
+			description: "[DANGEROUS] Update rows of a table using structured parameters with a mandatory filter. The tool refuses to run without at least one filter condition, so a whole table can never be updated by accident. Without confirm it only previews the change: it reports the generated UPDATE and how many rows match the filter. Re-run with confirm set to true to apply it, optionally passing expected_rows so the update is aborted if the number of matching rows has changed since the preview.",
+		},
+	}
+}
+
+// CreateTool creates a modify rows tool
+func (t *ModifyRowsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Preview and apply an UPDATE guarded by a required structured filter"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Table to update (optionally schema-qualified)"),
+			tools.Required(),
+		),
+		tools.WithObject("values",
+			tools.Description("New values as an object mapping column names to values"),
+			tools.Required(),
+		),
+		tools.WithArray("filters",
+			tools.Description("Required conditions combined with AND: objects with column, operator (=, !=, <, <=, >, >=, like, not like, in, not in, between, is null, is not null), value, or values for in/between"),
+			tools.Items(map[string]interface{}{"type": "object"}),
+			tools.Required(),
+		),
+		tools.WithBoolean("confirm",
+			tools.Description("Apply the update; when false only the affected row count is previewed (default: false)"),
+		),
+		tools.WithNumber("expected_rows",
+			tools.Description("Abort unless exactly this many rows match the filter (optional, use the previewed count)"),
+		),
+	)
+}
+
+// HandleRequest handles modify rows tool requests
+func (t *ModifyRowsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	write, err := parseGuardedWrite(request.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	write.values = newToolParams(request.Parameters).object("values")
+	if len(write.values) == 0 {
+		return nil, fmt.Errorf("values parameter must be a non-empty object")
+	}
+
+	return runGuardedWrite(ctx, request, useCase, write, t.name, "Update")
+}
+
+// DeleteRowsTool handles guarded DELETE statements built from structured parameters
+type DeleteRowsTool struct {
+	BaseToolType
+}
+
+// NewDeleteRowsTool creates a new delete rows tool type
+func NewDeleteRowsTool() *DeleteRowsTool {
+	return &DeleteRowsTool{
+		BaseToolType: BaseToolType{
+			name:        "delete_rows",
+			description: "[DANGEROUS] Delete rows from a table using a mandatory structured filter. The tool refuses to run without at least one filter condition, so a whole table can never be emptied by accident. Without confirm it only previews the change: it reports the generated DELETE and how many rows match the filter. Re-run with confirm set to true to apply it, optionally passing expected_rows so the delete is aborted if the number of matching rows has changed since the preview.",
+		},
+	}
+}
+
+// CreateTool creates a delete rows tool
+func (t *DeleteRowsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Preview and apply a DELETE guarded by a required structured filter"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Table to delete from (optionally schema-qualified)"),
+			tools.Required(),
+		),
+		tools.WithArray("filters",
+			tools.Description("Required conditions combined with AND: objects with column, operator (=, !=, <, <=, >, >=, like, not like, in, not in, between, is null, is not null), value, or values for in/between"),
+			tools.Items(map[string]interface{}{"type": "object"}),
+			tools.Required(),
+		),
+		tools.WithBoolean("confirm",
+			tools.Description("Apply the delete; when false only the affected row count is previewed (default: false)"),
+		),
+		tools.WithNumber("expected_rows",
+			tools.Description("Abort unless exactly this many rows match the filter (optional, use the previewed count)"),
+		),
+	)
+}
+
+// HandleRequest handles delete rows tool requests
+func (t *DeleteRowsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	write, err := parseGuardedWrite(request.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	return runGuardedWrite(ctx, request, useCase, write, t.name, "Delete")
+}
+
+// guardedWrite describes an UPDATE (when values are set) or DELETE restricted by filters
+type guardedWrite struct {
+	dbID         string
+	table        string
+	filters      []queryFilter
+	values       map[string]interface{}
+	confirm      bool
+	expectedRows int64
+	hasExpected  bool
+}
+
+// parseGuardedWrite extracts the parameters shared by the guarded write tools
+func parseGuardedWrite(parameters map[string]interface{}) (*guardedWrite, error) {
+	input := newToolParams(parameters)
+	write := &guardedWrite{
+		dbID:         input.requiredString("database"),
+		table:        input.requiredString("table"),
+		confirm:      input.optionalBool("confirm", false),
+		expectedRows: int64(input.intAtLeast("expected_rows", 0, 0)),
+		hasExpected:  input.has("expected_rows"),
+	}
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if write.filters, err = parseQueryFilters(input.raw("filters")); err != nil {
+		return nil, err
+	}
+	if len(write.filters) == 0 {
+		return nil, fmt.Errorf("filters parameter must contain at least one condition")
+	}
+
+	return write, nil
+}
+
+// buildCount renders the preview query counting the rows matched by the filters
+func (w *guardedWrite) buildCount(dbType string) (string, []interface{}) {
+	params := newSQLParams(dbType)
+	where := buildWhereClause(w.filters, params)
+	return fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", quoteIdentifier(dbType, w.table), where), params.values
+}
+
+// buildStatement renders the UPDATE or DELETE statement
+func (w *guardedWrite) buildStatement(dbType string) (string, []interface{}) {
+	params := newSQLParams(dbType)
+	table := quoteIdentifier(dbType, w.table)
+	if len(w.values) == 0 {
+		where := buildWhereClause(w.filters, params)
+		return fmt.Sprintf("DELETE FROM %s WHERE %s", table, where), params.values
+	}
+
+	columns := make([]string, 0, len(w.values))
+	for column := range w.values {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = fmt.Sprintf("%s = %s", quoteIdentifier(dbType, column), params.add(w.values[column]))
+	}
+	where := buildWhereClause(w.filters, params)
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, strings.Join(assignments, ", "), where), params.values
+}
+
+// runGuardedWrite previews the rows matched by a guarded write and executes it once confirmed
+func runGuardedWrite(ctx context.Context, request server.ToolCallRequest, useCase UseCaseProvider, write *guardedWrite, tool, verb string) (interface{}, error) {
+	dbType, err := useCase.GetDatabaseType(write.dbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	if _, ok := lookupDialect(dbType); !ok {
+		return nil, fmt.Errorf("unsupported database type for %s: %s", strings.ToLower(verb), dbType)
+	}
+
+	countQuery, countParams := write.buildCount(dbType)
+	countResult, err := useCase.ExecuteQuery(ctx, write.dbID, countQuery, countParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to preview affected rows: %w", err)
+	}
+	var matched int64
+	if len(countResult.Rows) > 0 && len(countResult.Rows[0]) > 0 {
+		matched = valueInt64(countResult.Rows[0][0])
+	}
+
+	statement, params := write.buildStatement(dbType)
+
+	var response strings.Builder
+	if !write.confirm {
+		response.WriteString(fmt.Sprintf("# %s Preview for Database %s\n\n", verb, write.dbID))
+	} else {
+		response.WriteString(fmt.Sprintf("# %s on Database %s\n\n", verb, write.dbID))
+	}
+	response.WriteString(fmt.Sprintf("```sql\n%s\n```\n\n", statement))
+	if len(params) > 0 {
+		response.WriteString(fmt.Sprintf("Parameters: %v\n\n", params))
+	}
+	response.WriteString(fmt.Sprintf("Rows matching filter: %d\n\n", matched))
+
+	if !write.confirm {
+		response.WriteString(fmt.Sprintf("Nothing was changed. Re-run with confirm=true and expected_rows=%d to apply.\n", matched))
+		return createTextResponse(response.String()), nil
+	}
+
+	if write.hasExpected && write.expectedRows != matched {
+		return nil, fmt.Errorf("aborted: %d rows match the filter but expected_rows is %d", matched, write.expectedRows)
+	}
+
+	logger.Info("Executing guarded %s on database %s: %s", strings.ToLower(verb), write.dbID, statement)
+	result, err := useCase.ExecuteStatement(ctx, write.dbID, statement, params)
+	audit(request, auditRecord{
+		Tool:      tool,
+		Database:  write.dbID,
+		Action:    strings.ToLower(verb),
+		Target:    write.table,
+		Statement: statement,
+	}, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s: %w", strings.ToLower(verb), err)
+	}
+	response.WriteString(result)
+
+	return createTextResponse(response.String()), nil
+}
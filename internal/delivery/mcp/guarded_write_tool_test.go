@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestGuardedWriteBuildUpdate(t *testing.T) {
+	write, err := parseGuardedWrite(map[string]interface{}{
+		"database": "pg1",
+		"table":    "public.users",
+		"filters": []interface{}{
+			map[string]interface{}{"column": "id", "operator": "in", "values": []interface{}{float64(1), float64(2)}},
+		},
+	})
+	assert.NoError(t, err)
+	write.values = map[string]interface{}{"status": "inactive", "note": nil}
+
+	statement, params := write.buildStatement("postgres")
+	assert.Equal(t, `UPDATE "public"."users" SET "note" = $1, "status" = $2 WHERE "id" IN ($3, $4)`, statement)
+	assert.Equal(t, []interface{}{nil, "inactive", int64(1), int64(2)}, params)
+
+	count, countParams := write.buildCount("postgres")
+	assert.Equal(t, `SELECT COUNT(*) FROM "public"."users" WHERE "id" IN ($1, $2)`, count)
+	assert.Equal(t, []interface{}{int64(1), int64(2)}, countParams)
+}
+
+func TestGuardedWriteBuildDelete(t *testing.T) {
+	write, err := parseGuardedWrite(map[string]interface{}{
+		"database": "mysql1",
+		"table":    "sessions",
+		"filters": []interface{}{
+			map[string]interface{}{"column": "expires_at", "operator": "<", "value": "2024-01-01"},
+		},
+	})
+	assert.NoError(t, err)
+
+	statement, params := write.buildStatement("mysql")
+	assert.Equal(t, "DELETE FROM `sessions` WHERE `expires_at` < ?", statement)
+	assert.Equal(t, []interface{}{"2024-01-01"}, params)
+}
+
+func TestGuardedWriteRequiresFilter(t *testing.T) {
+	_, err := parseGuardedWrite(map[string]interface{}{"database": "mysql1", "table": "users"})
+	assert.Error(t, err)
+
+	_, err = parseGuardedWrite(map[string]interface{}{"database": "mysql1", "table": "users", "filters": []interface{}{}})
+	assert.Error(t, err)
+}
+
+func TestDeleteRowsPreviewDoesNotExecute(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType:  "mysql",
+		results: map[string]*domain.QueryResult{"SELECT COUNT(*)": {Columns: []string{"count"}, Rows: [][]interface{}{{int64(3)}}}},
+	}
+
+	result, err := NewDeleteRowsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{
+			"database": "mysql1",
+			"table":    "sessions",
+			"filters":  []interface{}{map[string]interface{}{"column": "user_id", "value": float64(7)}},
+		},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Len(t, useCase.queries, 1)
+}
+
+func TestModifyRowsAbortsOnUnexpectedCount(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType:  "mysql",
+		results: map[string]*domain.QueryResult{"SELECT COUNT(*)": {Columns: []string{"count"}, Rows: [][]interface{}{{int64(5)}}}},
+	}
+	params := map[string]interface{}{
+		"database":      "mysql1",
+		"table":         "users",
+		"values":        map[string]interface{}{"status": "inactive"},
+		"filters":       []interface{}{map[string]interface{}{"column": "id", "operator": "<", "value": float64(10)}},
+		"confirm":       true,
+		"expected_rows": float64(4),
+	}
+
+	_, err := NewModifyRowsTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "mysql1", useCase)
+	assert.Error(t, err)
+	assert.Len(t, useCase.queries, 1)
+
+	params["expected_rows"] = float64(5)
+	_, err = NewModifyRowsTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "mysql1", useCase)
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `users` SET `status` = ? WHERE `id` < ?", useCase.queries[len(useCase.queries)-1])
+}
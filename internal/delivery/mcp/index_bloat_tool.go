@@ -0,0 +1,203 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// indexBloat is the estimated bloat of an index
+type indexBloat struct {
+	postgresIndexState
+	Percent   float64
+	Wasted    int64
+	Estimated bool
+	Reindex   bool
+}
+
+// IndexBloatTool handles estimating index bloat
+type IndexBloatTool struct {
+	BaseToolType
+}
+
+// NewIndexBloatTool creates a new index bloat tool type
+func NewIndexBloatTool() *IndexBloatTool {
+	return &IndexBloatTool{
+		BaseToolType: BaseToolType{
+			name:        "index_bloat",
+			description: "Estimate how much of each PostgreSQL btree index is free space left behind by updates and deletes, with the bloat percentage and wasted bytes per index, largest waste first, and the REINDEX statements for the indexes above the bloat and size thresholds. The estimate is the one reindex uses: the expected size is computed from the tuple count and the average key width in the planner statistics, so it is rough and can be off after large changes that ANALYZE has not seen yet. Invalid indexes are always recommended for a rebuild; indexes of other types or on expressions cannot be estimated. InnoDB does not expose free space per index; on MySQL use reindex with dry_run to find fragmented tables.",
+		},
+	}
+}
+
+// CreateTool creates an index bloat tool
+func (t *IndexBloatTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Estimate bloat percent and wasted bytes per index with REINDEX recommendations"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to scan (default: public)"),
+		),
+		tools.WithString("table",
+			tools.Description("Only estimate the indexes of this table"),
+		),
+		tools.WithNumber("min_bloat_percent",
+			tools.Description("Recommend a REINDEX when the estimated bloat is at least this percentage (default: 30)"),
+		),
+		tools.WithNumber("min_size_mb",
+			tools.Description("Do not recommend a REINDEX for indexes smaller than this many MiB (default: 1)"),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of indexes to list, most wasted space first (default: 50)"),
+		),
+	)
+}
+
+// HandleRequest handles index bloat tool requests
+func (t *IndexBloatTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	options := reindexOptions{
+		Schema:          input.optionalString("schema", ""),
+		MinBloatPercent: input.optionalFloat("min_bloat_percent", 30),
+	}
+	if schema, table := splitQualifiedName(input.optionalString("table", "")); table != "" {
+		options.Table = table
+		if schema != "" {
+			options.Schema = schema
+		}
+	}
+	minSizeMB := input.optionalFloat("min_size_mb", 1)
+	limit := input.intBetween("limit", 50, 1, 10000)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if options.MinBloatPercent < 0 || options.MinBloatPercent > 100 {
+		return nil, fmt.Errorf("min_bloat_percent parameter must be between 0 and 100")
+	}
+	if minSizeMB < 0 {
+		return nil, fmt.Errorf("min_size_mb parameter must be at least 0")
+	}
+	options.MinSizeBytes = int64(minSizeMB * (1 << 20))
+	if options.Schema == "" {
+		options.Schema = "public"
+	}
+
+	logger.Info("Estimating index bloat for database %s, schema %s", targetDbID, options.Schema)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for index bloat: %s", dbType)
+	}
+	loadIndexStates, err := dialect.IndexStates()
+	if err != nil {
+		return nil, unsupportedAdminError("index bloat", dbType, err)
+	}
+
+	states, err := loadIndexStates(ctx, useCase, targetDbID, options)
+	if err != nil {
+		return nil, err
+	}
+	bloats := estimateIndexBloat(states, options)
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Index Bloat in Database %s\n\n", targetDbID))
+	if options.Table != "" {
+		response.WriteString(fmt.Sprintf("Table: %s\n\n", qualifiedName(options.Schema, options.Table)))
+	} else {
+		response.WriteString(fmt.Sprintf("Schema: %s\n\n", options.Schema))
+	}
+	writeIndexBloat(&response, bloats, options, limit)
+
+	return createTextResponse(response.String()), nil
+}
+
+// indexStateLoader reads the indexes of a schema or table with what the bloat estimate needs
+type indexStateLoader func(ctx context.Context, useCase UseCaseProvider, dbID string, options reindexOptions) ([]postgresIndexState, error)
+
+// estimateIndexBloat estimates the bloat of every index, most wasted space first, and marks
+// the invalid indexes and those over both thresholds for a rebuild
+func estimateIndexBloat(states []postgresIndexState, options reindexOptions) []indexBloat {
+	bloats := make([]indexBloat, 0, len(states))
+	for _, state := range states {
+		b := indexBloat{postgresIndexState: state}
+		b.Percent, b.Estimated = state.bloatPercent()
+		if b.Estimated {
+			b.Wasted = int64(float64(state.Size) * b.Percent / 100)
+		}
+		b.Reindex = !state.Valid ||
+			(b.Estimated && b.Percent >= options.MinBloatPercent && state.Size >= options.MinSizeBytes)
+		bloats = append(bloats, b)
+	}
+	sort.SliceStable(bloats, func(i, j int) bool {
+		if bloats[i].Wasted != bloats[j].Wasted {
+			return bloats[i].Wasted > bloats[j].Wasted
+		}
+		return bloats[i].Size > bloats[j].Size
+	})
+	return bloats
+}
+
+// writeIndexBloat renders the estimates, the totals and the recommended REINDEX statements
+func writeIndexBloat(sb *strings.Builder, bloats []indexBloat, options reindexOptions, limit int) {
+	if len(bloats) == 0 {
+		sb.WriteString("No indexes found.\n")
+		return
+	}
+
+	sb.WriteString("| Index | Table | Method | Size | Bloat | Wasted | Reindex |\n")
+	sb.WriteString("|-------|-------|--------|------|-------|--------|---------|\n")
+	var wasted int64
+	var statements []string
+	unestimated := 0
+	for i, b := range bloats {
+		wasted += b.Wasted
+		if !b.Estimated {
+			unestimated++
+		}
+		if b.Reindex {
+			statements = append(statements, fmt.Sprintf("REINDEX INDEX CONCURRENTLY %s;", quoteIdentifier("postgres", qualifiedName(b.Schema, b.Index))))
+		}
+		if i >= limit {
+			continue
+		}
+		percent, waste := "-", "-"
+		if b.Estimated {
+			percent, waste = fmt.Sprintf("~%.0f%%", b.Percent), formatBackupSize(b.Wasted)
+		}
+		reindex := yesNo(b.Reindex)
+		if !b.Valid {
+			reindex += " (invalid)"
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+			b.Index, b.Table, b.Method, formatBackupSize(b.Size), percent, waste, reindex))
+	}
+	if len(bloats) > limit {
+		sb.WriteString(fmt.Sprintf("\nShowing %d of %d indexes.\n", limit, len(bloats)))
+	}
+
+	sb.WriteString(fmt.Sprintf("\nAbout %s wasted across %d indexes; %d recommended for a REINDEX (invalid, or at least %.0f%% bloat and %s).\n",
+		formatBackupSize(wasted), len(bloats), len(statements), options.MinBloatPercent, formatBackupSize(options.MinSizeBytes)))
+	if unestimated > 0 {
+		sb.WriteString(fmt.Sprintf("%d indexes are not btree or are on expressions, so their bloat cannot be estimated.\n", unestimated))
+	}
+
+	if len(statements) > 0 {
+		sb.WriteString("\n## Recommended REINDEX Statements\n\n")
+		sb.WriteString(fmt.Sprintf("```sql\n%s\n```\n", strings.Join(statements, "\n")))
+		sb.WriteString("\nThe reindex tool runs them one at a time and reports the size of each index afterwards.\n")
+	}
+}
@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestIndexBloatTool(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_index i": {Rows: [][]interface{}{
+				{"public", "orders_status_idx", "orders", true, int64(2 << 20), float64(100000), "btree", int64(4), true, int64(8192)},
+				{"public", "orders_pkey", "orders", true, int64(8 << 20), float64(100000), "btree", int64(4), true, int64(8192)},
+				{"public", "orders_tags_idx", "orders", true, int64(4 << 20), float64(100000), "gin", int64(0), true, int64(8192)},
+				{"public", "orders_total_idx", "orders", false, int64(16384), float64(0), "btree", int64(8), true, int64(8192)},
+			}},
+		},
+	}
+
+	result, err := NewIndexBloatTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "table": "orders"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| orders_pkey | orders | btree | 8.0 MiB | ~73% | 5.9 MiB | yes |\n"+
+		"| orders_total_idx | orders | btree | 16.0 KiB | ~50% | 8.0 KiB | yes (invalid) |\n"+
+		"| orders_tags_idx | orders | gin | 4.0 MiB | - | - | no |\n"+
+		"| orders_status_idx | orders | btree | 2.0 MiB | ~0% | 0 B | no |")
+	assert.Contains(t, text, "2 recommended for a REINDEX")
+	assert.Contains(t, text, "1 indexes are not btree or are on expressions")
+	assert.Contains(t, text, `REINDEX INDEX CONCURRENTLY "public"."orders_pkey";`+"\n"+`REINDEX INDEX CONCURRENTLY "public"."orders_total_idx";`)
+
+	useCase.dbType = "mysql"
+	_, err = NewIndexBloatTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "my1"},
+	}, "my1", useCase)
+	assert.ErrorContains(t, err, "InnoDB does not expose free space per index")
+}
@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// IndexStatsTool handles reading the statistics of the indices of a search database
+type IndexStatsTool struct {
+	BaseToolType
+}
+
+// NewIndexStatsTool creates a new index stats tool type
+func NewIndexStatsTool() *IndexStatsTool {
+	return &IndexStatsTool{
+		BaseToolType: BaseToolType{
+			name:        "index_stats",
+			description: "Read the _stats statistics of the Elasticsearch or OpenSearch indices matching a pattern: documents, deleted documents, primary and total store size, segments, indexing operations, and search queries with their average time, per index and for all of them together.",
+		},
+	}
+}
+
+// CreateTool creates an index stats tool
+func (t *IndexStatsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Read the statistics of Elasticsearch or OpenSearch indices"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("index",
+			tools.Description("Index pattern, such as logs-* (default: every index)"),
+		),
+	)
+}
+
+// HandleRequest handles index stats tool requests
+func (t *IndexStatsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	pattern := input.optionalString("index", "")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	store, err := useCase.GetSearchStore(targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := store.IndexStats(ctx, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index statistics: %w", err)
+	}
+	indices, _ := stats["indices"].(map[string]interface{})
+	logger.Info("Read the statistics of %d indices in database %s", len(indices), targetDbID)
+
+	title := pattern
+	if title == "" {
+		title = "All Indices"
+	}
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Index Statistics for %s in Database %s\n\n", title, targetDbID))
+	if len(indices) == 0 {
+		response.WriteString("No indices match the pattern.\n")
+		return createTextResponse(response.String()), nil
+	}
+
+	names := make([]string, 0, len(indices))
+	for name := range indices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	response.WriteString("| Index | Documents | Deleted | Primary Size | Total Size | Segments | Indexing Ops | Search Queries | Avg Query Time |\n")
+	response.WriteString("|-------|-----------|---------|--------------|------------|----------|--------------|----------------|----------------|\n")
+	for _, name := range names {
+		writeIndexStatsRow(&response, name, indices[name])
+	}
+	if len(names) > 1 {
+		writeIndexStatsRow(&response, "**All**", stats["_all"])
+	}
+	return createTextResponse(response.String()), nil
+}
+
+// writeIndexStatsRow writes the statistics of one index; documents come from the primaries,
+// activity from all shard copies
+func writeIndexStatsRow(sb *strings.Builder, name string, stats interface{}) {
+	avgQueryTime := ""
+	queries, _ := searchInt(searchField(stats, "total.search.query_total"))
+	if millis, ok := searchInt(searchField(stats, "total.search.query_time_in_millis")); ok && queries > 0 {
+		avgQueryTime = (time.Duration(millis) * time.Millisecond / time.Duration(queries)).String()
+	}
+	sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s | %s | %s |\n", name,
+		searchCount(searchField(stats, "primaries.docs.count")),
+		searchCount(searchField(stats, "primaries.docs.deleted")),
+		searchSize(searchField(stats, "primaries.store.size_in_bytes")),
+		searchSize(searchField(stats, "total.store.size_in_bytes")),
+		searchCount(searchField(stats, "total.segments.count")),
+		searchCount(searchField(stats, "total.indexing.index_total")),
+		searchCount(searchField(stats, "total.search.query_total")),
+		avgQueryTime))
+}
@@ -0,0 +1,162 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// searchInt returns a number of a search engine response, which holds numbers as json.Number
+// and some _cat values as strings
+func searchInt(value interface{}) (int64, bool) {
+	switch number := value.(type) {
+	case json.Number:
+		if n, err := number.Int64(); err == nil {
+			return n, true
+		}
+		f, err := number.Float64()
+		return int64(f), err == nil
+	case string:
+		n, err := strconv.ParseInt(number, 10, 64)
+		return n, err == nil
+	case float64:
+		return int64(number), true
+	default:
+		return 0, false
+	}
+}
+
+// searchField returns the value at a dotted path of a response, such as primaries.docs.count
+func searchField(value interface{}, path string) interface{} {
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+	return value
+}
+
+// searchText formats a scalar of a response, or an empty string when it is missing
+func searchText(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// searchCount formats a count of a response, or an empty string when it is missing
+func searchCount(value interface{}) string {
+	if n, ok := searchInt(value); ok {
+		return strconv.FormatInt(n, 10)
+	}
+	return ""
+}
+
+// searchSize formats a byte count of a response, or an empty string when it is missing
+func searchSize(value interface{}) string {
+	if n, ok := searchInt(value); ok {
+		return formatBackupSize(n)
+	}
+	return ""
+}
+
+// flattenSource adds the fields of a document to row under dotted paths, such as user.id;
+// arrays are kept whole as JSON. It returns the paths in sorted order.
+func flattenSource(source map[string]interface{}, prefix string, row map[string]string) []string {
+	names := make([]string, 0, len(source))
+	for name := range source {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var paths []string
+	for _, name := range names {
+		path := prefix + name
+		switch value := source[name].(type) {
+		case map[string]interface{}:
+			paths = append(paths, flattenSource(value, path+".", row)...)
+		case nil:
+			row[path] = "NULL"
+			paths = append(paths, path)
+		case string:
+			row[path] = value
+			paths = append(paths, path)
+		case json.Number:
+			row[path] = value.String()
+			paths = append(paths, path)
+		default:
+			row[path] = compactJSON(value)
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// hitCell returns a value for a markdown table cell, on one line and cut to 200 bytes
+func hitCell(value string) string {
+	const maxCellLength = 200
+	if len(value) > maxCellLength {
+		value = value[:maxCellLength] + "…"
+	}
+	value = strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+	return strings.ReplaceAll(value, "|", "\\|")
+}
+
+// mappingField is one field of a mapping, with the parameters other than its type
+type mappingField struct {
+	path    string
+	typ     string
+	details string
+}
+
+// flattenMapping lists the fields of the properties of a mapping, with objects and nested
+// fields followed by their children and multi-fields, such as title.keyword, after their parent
+func flattenMapping(properties map[string]interface{}, prefix string) []mappingField {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var fields []mappingField
+	for _, name := range names {
+		definition, _ := properties[name].(map[string]interface{})
+		field := mappingField{path: prefix + name, typ: fmt.Sprint(definition["type"])}
+		if definition["type"] == nil {
+			field.typ = "object"
+		}
+		var details []string
+		for key, value := range definition {
+			if key == "type" || key == "properties" || key == "fields" {
+				continue
+			}
+			text, ok := value.(string)
+			if !ok {
+				text = compactJSON(value)
+			}
+			details = append(details, key+": "+text)
+		}
+		sort.Strings(details)
+		field.details = strings.Join(details, ", ")
+		fields = append(fields, field)
+
+		if multi, ok := definition["fields"].(map[string]interface{}); ok {
+			for _, sub := range flattenMapping(multi, field.path+".") {
+				if sub.details == "" {
+					sub.details = "multi-field"
+				} else {
+					sub.details = "multi-field, " + sub.details
+				}
+				fields = append(fields, sub)
+			}
+		}
+		if children, ok := definition["properties"].(map[string]interface{}); ok {
+			fields = append(fields, flattenMapping(children, field.path+".")...)
+		}
+	}
+	return fields
+}
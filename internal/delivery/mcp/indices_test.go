@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSearchStore is a SearchStore returning canned REST responses
+type fakeSearchStore struct {
+	indices  []map[string]interface{}
+	mappings map[string]interface{}
+	stats    map[string]interface{}
+	result   map[string]interface{}
+	// bodies records the pattern and body of every Search call
+	bodies []string
+}
+
+func (f *fakeSearchStore) ListIndices(ctx context.Context, pattern string, includeHidden bool) ([]map[string]interface{}, error) {
+	return f.indices, nil
+}
+
+func (f *fakeSearchStore) Mappings(ctx context.Context, pattern string) (map[string]interface{}, error) {
+	return f.mappings, nil
+}
+
+func (f *fakeSearchStore) IndexStats(ctx context.Context, pattern string) (map[string]interface{}, error) {
+	return f.stats, nil
+}
+
+func (f *fakeSearchStore) Search(ctx context.Context, pattern, body string) (map[string]interface{}, error) {
+	f.bodies = append(f.bodies, pattern+" "+body)
+	return f.result, nil
+}
+
+// searchResponse decodes a JSON response the way the client does, with numbers as json.Number
+func searchResponse(t *testing.T, text string) map[string]interface{} {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var response map[string]interface{}
+	if err := decoder.Decode(&response); err != nil {
+		t.Fatalf("invalid test response: %v", err)
+	}
+	return response
+}
+
+// searchToolText runs a search tool and returns the text of its response
+func searchToolText(t *testing.T, tool ToolType, store *fakeSearchStore, params map[string]interface{}) string {
+	result, err := tool.HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", &mockUseCase{search: store})
+	if err != nil {
+		t.Fatalf("tool failed: %v", err)
+	}
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+}
+
+func TestListIndicesTool(t *testing.T) {
+	store := &fakeSearchStore{indices: []map[string]interface{}{
+		{"health": "green", "status": "open", "index": "logs-1", "pri": "1", "rep": "1", "docs.count": "1200", "docs.deleted": "3", "store.size": "4194304", "pri.store.size": "2097152"},
+		{"health": nil, "status": "close", "index": "logs-old", "pri": "1", "rep": "1", "docs.count": nil, "docs.deleted": nil, "store.size": nil, "pri.store.size": nil},
+	}}
+
+	text := searchToolText(t, NewListIndicesTool(), store, map[string]interface{}{"database": "search", "index": "logs-*"})
+	assert.Contains(t, text, "# Indices Matching logs-* in Database search")
+	assert.Contains(t, text, "| logs-1 | green | open | 1 | 1 | 1200 | 3 | 2.0 MiB | 4.0 MiB |\n| logs-old |  | close | 1 | 1 |  |  |  |  |")
+	assert.Contains(t, text, "2 indices")
+
+	_, err := NewListIndicesTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "pg"}}, "", &mockUseCase{dbType: "postgres"})
+	assert.EqualError(t, err, "database pg is not a search database")
+}
+
+func TestGetMappingTool(t *testing.T) {
+	mapping := `{"mappings": {"dynamic": "strict", "properties": {
+		"message": {"type": "text", "analyzer": "english", "fields": {"keyword": {"type": "keyword", "ignore_above": 256}}},
+		"user": {"properties": {"id": {"type": "long"}, "name": {"type": "keyword", "index": false}}},
+		"@timestamp": {"type": "date", "format": "strict_date_optional_time"}
+	}}}`
+	store := &fakeSearchStore{mappings: searchResponse(t, `{"logs-2": `+mapping+`, "logs-1": `+mapping+`, "orders": {"mappings": {}}}`)}
+
+	text := searchToolText(t, NewGetMappingTool(), store, map[string]interface{}{"database": "search", "index": "*"})
+	assert.Contains(t, text, "## logs-1, logs-2 (2 indices)\n\nDynamic mapping: strict\n\n")
+	assert.Contains(t, text, "| @timestamp | date | format: strict_date_optional_time |\n"+
+		"| message | text | analyzer: english |\n"+
+		"| message.keyword | keyword | multi-field, ignore_above: 256 |\n"+
+		"| user | object |  |\n"+
+		"| user.id | long |  |\n"+
+		"| user.name | keyword | index: false |")
+	assert.Contains(t, text, "## orders\n\nThe mapping has no fields.")
+}
+
+func TestIndexStatsTool(t *testing.T) {
+	store := &fakeSearchStore{stats: searchResponse(t, `{
+		"_all": {"primaries": {"docs": {"count": 1500, "deleted": 5}, "store": {"size_in_bytes": 3145728}},
+		         "total": {"store": {"size_in_bytes": 6291456}, "segments": {"count": 12}, "indexing": {"index_total": 3000}, "search": {"query_total": 40, "query_time_in_millis": 200}}},
+		"indices": {
+			"orders": {"primaries": {"docs": {"count": 1000, "deleted": 5}, "store": {"size_in_bytes": 2097152}},
+			           "total": {"store": {"size_in_bytes": 4194304}, "segments": {"count": 8}, "indexing": {"index_total": 2000}, "search": {"query_total": 40, "query_time_in_millis": 200}}},
+			"customers": {"primaries": {"docs": {"count": 500, "deleted": 0}, "store": {"size_in_bytes": 1048576}},
+			              "total": {"store": {"size_in_bytes": 2097152}, "segments": {"count": 4}, "indexing": {"index_total": 1000}, "search": {"query_total": 0, "query_time_in_millis": 0}}}
+		}
+	}`)}
+
+	text := searchToolText(t, NewIndexStatsTool(), store, map[string]interface{}{"database": "search"})
+	assert.Contains(t, text, "# Index Statistics for All Indices in Database search")
+	assert.Contains(t, text, "| customers | 500 | 0 | 1.0 MiB | 2.0 MiB | 4 | 1000 | 0 |  |\n"+
+		"| orders | 1000 | 5 | 2.0 MiB | 4.0 MiB | 8 | 2000 | 40 | 5ms |\n"+
+		"| **All** | 1500 | 5 | 3.0 MiB | 6.0 MiB | 12 | 3000 | 40 | 5ms |")
+}
+
+func TestSearchIndexTool(t *testing.T) {
+	store := &fakeSearchStore{result: searchResponse(t, `{
+		"took": 7, "timed_out": false, "_shards": {"total": 2, "failed": 0},
+		"hits": {"total": {"value": 10000, "relation": "gte"}, "hits": [
+			{"_index": "logs-1", "_id": "a", "_score": 1.5, "_source": {"message": "disk | full\nagain", "user": {"id": 9007199254740993}}},
+			{"_index": "logs-2", "_id": "b", "_score": 1.2, "_source": {"message": "timeout", "tags": ["db", "slow"], "level": null}}
+		]},
+		"aggregations": {"levels": {"buckets": [{"key": "error", "doc_count": 42}]}}
+	}`)}
+
+	text := searchToolText(t, NewSearchIndexTool(), store, map[string]interface{}{
+		"database": "search", "index": "logs-*",
+		"query": `{"query": {"match": {"message": "disk"}}, "size": 500, "sort": [{"@timestamp": "desc"}]}`,
+		"limit": 50,
+	})
+	assert.Equal(t, []string{`logs-* {"query":{"match":{"message":"disk"}},"size":50,"sort":[{"@timestamp":"desc"}]}`}, store.bodies)
+	assert.Contains(t, text, "Matched: at least 10000 documents, took 7 ms")
+	assert.Contains(t, text, "| _index | _id | _score | message | user.id | level | tags |\n|---|---|---|---|---|---|---|\n"+
+		"| logs-1 | a | 1.5 | disk \\| full again | 9007199254740993 |  |  |\n"+
+		"| logs-2 | b | 1.2 | timeout |  | NULL | [\"db\",\"slow\"] |")
+	assert.Contains(t, text, "2 hits")
+	assert.Contains(t, text, "## Aggregations\n\n```json\n{\n  \"levels\": {")
+
+	// A smaller size is kept and the default query matches everything
+	store.bodies = nil
+	searchToolText(t, NewSearchIndexTool(), store, map[string]interface{}{"database": "search", "index": "logs-*", "query": `{"size": 5}`})
+	searchToolText(t, NewSearchIndexTool(), store, map[string]interface{}{"database": "search", "index": "logs-*"})
+	assert.Equal(t, []string{`logs-* {"size":5}`, `logs-* {"query":{"match_all":{}},"size":20}`}, store.bodies)
+
+	_, err := NewSearchIndexTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "search", "index": "logs", "query": `[1]`}}, "", &mockUseCase{search: store})
+	assert.EqualError(t, err, `query must be a JSON object such as {"query": {"match_all": {}}}`)
+}
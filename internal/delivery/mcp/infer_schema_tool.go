@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// InferSchemaTool handles inferring a table definition from a sample file
+type InferSchemaTool struct {
+	BaseToolType
+}
+
+// NewInferSchemaTool creates a new schema inference tool type
+func NewInferSchemaTool() *InferSchemaTool {
+	return &InferSchemaTool{
+		BaseToolType: BaseToolType{
+			name:        "infer_schema",
+			description: "Inspect a CSV or JSONL file on the server, infer column names and types, and emit the CREATE TABLE DDL for the database's dialect. Detects integers, decimals, booleans, dates, timestamps and JSON values; integers with leading zeros stay text. Strict inference picks the narrowest types that fit the sample (INTEGER, NUMERIC(p, s), VARCHAR(n), NOT NULL where no value is empty); lenient inference picks wide, nullable types. Optionally creates the table and loads every row in a single transaction.",
+		},
+	}
+}
+
+// CreateTool creates a schema inference tool
+func (t *InferSchemaTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Infer CREATE TABLE DDL from a CSV or JSONL file, optionally creating and loading the table"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("path",
+			tools.Description("Path of the CSV or JSONL file on the server"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Name of the table (default: derived from the file name)"),
+		),
+		tools.WithString("format",
+			tools.Description("File format: csv or jsonl (default: from the file extension)"),
+		),
+		tools.WithString("delimiter",
+			tools.Description("CSV field delimiter (default: comma, tab for .tsv files)"),
+		),
+		tools.WithBoolean("header",
+			tools.Description("Whether the first CSV row holds the column names (default: true)"),
+		),
+		tools.WithString("strictness",
+			tools.Description("strict for the narrowest types that fit the sample, lenient for wide nullable types (default: lenient)"),
+		),
+		tools.WithNumber("sample_rows",
+			tools.Description("Number of rows to inspect (default: 1000, 0 for all; loading always inspects all rows)"),
+		),
+		tools.WithBoolean("create",
+			tools.Description("Create the table (default: false)"),
+		),
+		tools.WithBoolean("load",
+			tools.Description("Load the rows of the file into the table (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles schema inference tool requests
+func (t *InferSchemaTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	path := input.requiredString("path")
+	table := input.optionalString("table", defaultTableName(path))
+	format := strings.ToLower(input.optionalString("format", ""))
+	delimiter := input.optionalString("delimiter", "")
+	header := input.optionalBool("header", true)
+	strictness := input.choice("strictness", "lenient", "strict", "lenient")
+	strict := strictness == "strict"
+	sampleRows := input.intAtLeast("sample_rows", 1000, 0)
+	create := input.optionalBool("create", false)
+	load := input.optionalBool("load", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if load {
+		// Types inferred from a sample may not fit the rest of the file
+		sampleRows = 0
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect := strings.ToLower(dbType)
+
+	data, err := readSampleFile(path, format, delimiter, header, sampleRows, strict)
+	if err != nil {
+		return nil, err
+	}
+	columns := inferColumns(data)
+	ddl := createTableDDL(dialect, table, columns, strict)
+
+	logger.Info("Inferred schema of %s for database %s: %d columns from %d rows", path, targetDbID, len(columns), len(data.Rows))
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Inferred Schema for Database %s\n\n", targetDbID))
+	sampled := fmt.Sprintf("%d rows", len(data.Rows))
+	if data.Truncated {
+		sampled = fmt.Sprintf("first %d rows", len(data.Rows))
+	}
+	response.WriteString(fmt.Sprintf("Source: %s (%s, %s inspected)\n", path, data.Format, sampled))
+	response.WriteString(fmt.Sprintf("Strictness: %s\n\n", strictness))
+
+	response.WriteString("| Column | Source | Type | Empty | Example |\n")
+	response.WriteString("|--------|--------|------|-------|---------|\n")
+	for _, col := range columns {
+		example := []rune(col.Example)
+		if len(example) > 40 {
+			example = append(example[:37], []rune("...")...)
+		}
+		response.WriteString(fmt.Sprintf("| %s | %s | %s | %d | %s |\n",
+			col.Name, col.Source, col.sqlType(dialect, strict), col.Nulls, strings.ReplaceAll(string(example), "|", "\\|")))
+	}
+	response.WriteString(fmt.Sprintf("\n```sql\n%s;\n```\n", ddl))
+
+	if create {
+		if _, err := useCase.ExecuteStatement(ctx, targetDbID, ddl, nil); err != nil {
+			return nil, fmt.Errorf("failed to create table %s: %w", table, err)
+		}
+		logger.Info("Created table %s on database %s", table, targetDbID)
+		response.WriteString(fmt.Sprintf("\nCreated table %s.\n", table))
+	}
+
+	if load {
+		statements, params := insertStatements(dialect, table, columns, data.Rows)
+		if err := useCase.ExecuteBatch(ctx, targetDbID, statements, params); err != nil {
+			return nil, fmt.Errorf("failed to load %s into %s, no rows were loaded: %w", path, table, err)
+		}
+		logger.Info("Loaded %d rows into %s on database %s", len(data.Rows), table, targetDbID)
+		response.WriteString(fmt.Sprintf("\nLoaded %d rows into %s.\n", len(data.Rows), table))
+	}
+
+	return createTextResponse(response.String()), nil
+}
@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCustomersCSV = `Customer ID,Name,Zip Code,Balance,Active,Signed Up,Last Seen
+1,Ada,02139,10.50,true,2024-01-05,2024-01-05 10:00:00
+2,"Grace, Jr.",94105,7,false,2024-02-10,2024-02-10
+3,Linus,,1200.125,true,2024-03-15,
+`
+
+func TestInferColumnsFromCSV(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{"customers.csv": testCustomersCSV})
+	data, err := readSampleFile(filepath.Join(dir, "customers.csv"), "", "", true, 0, true)
+	assert.NoError(t, err)
+	assert.Len(t, data.Rows, 3)
+
+	columns := inferColumns(data)
+	assert.Equal(t, `CREATE TABLE "customers" (
+  "customer_id" INTEGER NOT NULL,
+  "name" VARCHAR(10) NOT NULL,
+  "zip_code" VARCHAR(5),
+  "balance" NUMERIC(7, 3) NOT NULL,
+  "active" BOOLEAN NOT NULL,
+  "signed_up" DATE NOT NULL,
+  "last_seen" TIMESTAMP
+)`, createTableDDL("postgres", "customers", columns, true))
+	assert.Equal(t, "CREATE TABLE `customers` (\n"+
+		"  `customer_id` BIGINT,\n"+
+		"  `name` TEXT,\n"+
+		"  `zip_code` TEXT,\n"+
+		"  `balance` DOUBLE,\n"+
+		"  `active` BOOLEAN,\n"+
+		"  `signed_up` DATE,\n"+
+		"  `last_seen` DATETIME\n)", createTableDDL("mysql", "customers", columns, false))
+
+	truncated, err := readSampleFile(filepath.Join(dir, "customers.csv"), "", "", true, 2, true)
+	assert.NoError(t, err)
+	assert.True(t, truncated.Truncated)
+	assert.Len(t, truncated.Rows, 2)
+}
+
+func TestInferColumnsFromJSONL(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{"events.jsonl": `{"id": 1, "kind": "click", "payload": {"x": 1}, "at": "2024-01-05T10:00:00Z"}
+{"id": 2, "kind": "true", "at": "2024-01-06T11:00:00Z", "score": 1.5}
+`})
+	data, err := readSampleFile(filepath.Join(dir, "events.jsonl"), "", "", true, 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "kind", "payload", "at", "score"}, data.Columns)
+
+	columns := inferColumns(data)
+	types := make([]string, len(columns))
+	for i, col := range columns {
+		types[i] = col.sqlType("postgres", false)
+	}
+	assert.Equal(t, []string{"BIGINT", "TEXT", "JSONB", "TIMESTAMP", "DOUBLE PRECISION"}, types)
+	assert.Equal(t, time.Date(2024, 1, 6, 11, 0, 0, 0, time.UTC), columns[3].bindValue(data.Rows[1][3]))
+	assert.Nil(t, columns[2].bindValue(data.Rows[1][2]))
+}
+
+func TestInferSchemaToolCreatesAndLoads(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{"customers.csv": testCustomersCSV})
+	useCase := &mockUseCase{dbType: "mysql"}
+
+	result, err := NewInferSchemaTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{
+			"database":    "mysql1",
+			"path":        filepath.Join(dir, "customers.csv"),
+			"table":       "crm_customers",
+			"sample_rows": float64(1),
+			"create":      true,
+			"load":        true,
+		},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "(csv, 3 rows inspected)")
+	assert.Contains(t, text, "| zip_code | Zip Code | TEXT | 1 | 02139 |")
+	assert.Contains(t, text, "Created table crm_customers.")
+	assert.Contains(t, text, "Loaded 3 rows into crm_customers.")
+	assert.Contains(t, useCase.queries[0], "CREATE TABLE `crm_customers`")
+	assert.Len(t, useCase.batches, 1)
+	assert.Equal(t, "INSERT INTO `crm_customers` (`customer_id`, `name`, `zip_code`, `balance`, `active`, `signed_up`, `last_seen`) VALUES "+
+		"(?, ?, ?, ?, ?, ?, ?), (?, ?, ?, ?, ?, ?, ?), (?, ?, ?, ?, ?, ?, ?)", useCase.batches[0][0])
+
+	_, err = NewInferSchemaTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "path": filepath.Join(dir, "customers.csv"), "strictness": "loose"},
+	}, "mysql1", useCase)
+	assert.EqualError(t, err, "strictness parameter must be strict or lenient")
+}
@@ -0,0 +1,234 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// InspectKeyTool handles reading one key of a key-value database
+type InspectKeyTool struct {
+	BaseToolType
+}
+
+// NewInspectKeyTool creates a new inspect key tool type
+func NewInspectKeyTool() *InspectKeyTool {
+	return &InspectKeyTool{
+		BaseToolType: BaseToolType{
+			name:        "inspect_key",
+			description: "Inspect one Redis key: its type, time to live, memory usage, internal encoding and length, with a preview of its value. Strings show their first 4 KiB, lists, sorted sets and streams their first elements, and sets and hashes the first members SSCAN or HSCAN returns.",
+		},
+	}
+}
+
+// CreateTool creates an inspect key tool
+func (t *InspectKeyTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Inspect one Redis key and preview its value"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("key",
+			tools.Description("Key to inspect"),
+			tools.Required(),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of elements to preview (default: 20, max: 1000)"),
+		),
+	)
+}
+
+// maxStringPreview is how many bytes of a string value are shown
+const maxStringPreview = 4096
+
+// keyLengthCommands are the commands returning the length of a key of each type
+var keyLengthCommands = map[string]string{
+	"string": "STRLEN",
+	"list":   "LLEN",
+	"set":    "SCARD",
+	"zset":   "ZCARD",
+	"hash":   "HLEN",
+	"stream": "XLEN",
+}
+
+// HandleRequest handles inspect key tool requests
+func (t *InspectKeyTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	key := input.requiredString("key")
+	limit := input.intBetween("limit", 20, 1, 1000)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	store, err := useCase.GetKeyValueStore(targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	info, err := describeKey(ctx, store, key, "")
+	if err != nil {
+		return nil, err
+	}
+	if info.keyType == "none" {
+		return nil, fmt.Errorf("key %s does not exist in database %s", key, targetDbID)
+	}
+	logger.Info("Inspecting %s key %s in database %s", info.keyType, key, targetDbID)
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Key %s in Database %s\n\n", key, targetDbID))
+	response.WriteString("| Property | Value |\n")
+	response.WriteString("|----------|-------|\n")
+	response.WriteString(fmt.Sprintf("| Type | %s |\n", info.keyType))
+	response.WriteString(fmt.Sprintf("| TTL | %s |\n", info.ttl))
+	response.WriteString(fmt.Sprintf("| Memory | %s |\n", info.memory))
+	if reply, err := store.Command(ctx, "OBJECT", "ENCODING", key); err == nil {
+		response.WriteString(fmt.Sprintf("| Encoding | %s |\n", redisText(reply)))
+	}
+	length := int64(-1)
+	if command, ok := keyLengthCommands[info.keyType]; ok {
+		reply, err := store.Command(ctx, command, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the length of %s: %w", key, err)
+		}
+		length, _ = redisInt(reply)
+		response.WriteString(fmt.Sprintf("| Length | %d |\n", length))
+	}
+
+	preview, shown, err := previewKey(ctx, store, key, info.keyType, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	if preview == "" {
+		return createTextResponse(response.String()), nil
+	}
+	response.WriteString("\n## Value\n\n")
+	response.WriteString(preview)
+	if info.keyType != "string" && length > int64(shown) {
+		response.WriteString(fmt.Sprintf("\nShowing %d of %d elements; raise limit to see more.\n", shown, length))
+	}
+	return createTextResponse(response.String()), nil
+}
+
+// previewKey reads up to limit elements of a key and formats them for its type. It returns the
+// preview and the number of elements in it; types it cannot read, such as modules' types, have
+// no preview.
+func previewKey(ctx context.Context, store domain.KeyValueStore, key, keyType string, limit int) (string, int, error) {
+	var sb strings.Builder
+	switch keyType {
+	case "string":
+		reply, err := store.Command(ctx, "GETRANGE", key, "0", strconv.Itoa(maxStringPreview-1))
+		if err != nil {
+			return "", 0, err
+		}
+		sb.WriteString("```\n" + redisText(reply) + "\n```\n")
+		return sb.String(), 1, nil
+	case "list":
+		items, err := commandList(ctx, store, "LRANGE", key, "0", strconv.Itoa(limit-1))
+		if err != nil {
+			return "", 0, err
+		}
+		sb.WriteString("| Index | Value |\n|-------|-------|\n")
+		for i, item := range items {
+			sb.WriteString(fmt.Sprintf("| %d | %s |\n", i, redisCell(redisText(item))))
+		}
+		return sb.String(), len(items), nil
+	case "set":
+		members, err := scanMembers(ctx, store, "SSCAN", key, limit)
+		if err != nil {
+			return "", 0, err
+		}
+		sb.WriteString("| Member |\n|--------|\n")
+		for _, member := range members {
+			sb.WriteString(fmt.Sprintf("| %s |\n", redisCell(redisText(member))))
+		}
+		return sb.String(), len(members), nil
+	case "zset":
+		items, err := commandList(ctx, store, "ZRANGE", key, "0", strconv.Itoa(limit-1), "WITHSCORES")
+		if err != nil {
+			return "", 0, err
+		}
+		sb.WriteString("| Member | Score |\n|--------|-------|\n")
+		for i := 0; i+1 < len(items); i += 2 {
+			sb.WriteString(fmt.Sprintf("| %s | %s |\n", redisCell(redisText(items[i])), redisText(items[i+1])))
+		}
+		return sb.String(), len(items) / 2, nil
+	case "hash":
+		items, err := scanMembers(ctx, store, "HSCAN", key, 2*limit)
+		if err != nil {
+			return "", 0, err
+		}
+		sb.WriteString("| Field | Value |\n|-------|-------|\n")
+		for i := 0; i+1 < len(items); i += 2 {
+			sb.WriteString(fmt.Sprintf("| %s | %s |\n", redisCell(redisText(items[i])), redisCell(redisText(items[i+1]))))
+		}
+		return sb.String(), len(items) / 2, nil
+	case "stream":
+		entries, err := commandList(ctx, store, "XRANGE", key, "-", "+", "COUNT", strconv.Itoa(limit))
+		if err != nil {
+			return "", 0, err
+		}
+		sb.WriteString("| ID | Fields |\n|----|--------|\n")
+		for _, entry := range entries {
+			parts, _ := entry.([]interface{})
+			if len(parts) != 2 {
+				continue
+			}
+			fields, _ := parts[1].([]interface{})
+			pairs := make([]string, 0, len(fields)/2)
+			for i := 0; i+1 < len(fields); i += 2 {
+				pairs = append(pairs, redisText(fields[i])+"="+redisText(fields[i+1]))
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s |\n", redisText(parts[0]), redisCell(strings.Join(pairs, " "))))
+		}
+		return sb.String(), len(entries), nil
+	default:
+		return "", 0, nil
+	}
+}
+
+// commandList runs a command whose reply is an array
+func commandList(ctx context.Context, store domain.KeyValueStore, args ...string) ([]interface{}, error) {
+	reply, err := store.Command(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	items, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected %s reply: %v", args[0], reply)
+	}
+	return items, nil
+}
+
+// scanMembers iterates SSCAN or HSCAN on a key until it has limit items or the key was visited;
+// HSCAN returns fields and values as consecutive items
+func scanMembers(ctx context.Context, store domain.KeyValueStore, command, key string, limit int) ([]interface{}, error) {
+	var items []interface{}
+	cursor := "0"
+	for {
+		page, err := commandList(ctx, store, command, key, cursor, "COUNT", strconv.Itoa(limit))
+		if err != nil {
+			return nil, err
+		}
+		if len(page) != 2 {
+			return nil, fmt.Errorf("unexpected %s reply: %v", command, page)
+		}
+		batch, _ := page[1].([]interface{})
+		items = append(items, batch...)
+		cursor = redisText(page[0])
+		if cursor == "0" || len(items) >= limit {
+			break
+		}
+	}
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
@@ -0,0 +1,201 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// joinEdge is a foreign key traversed in one direction between two tables
+type joinEdge struct {
+	fk      schemaForeignKey
+	from    string // key of the table the traversal starts from
+	to      string // key of the table the traversal reaches
+	reverse bool   // traversal goes from the referenced table to the referencing one
+}
+
+// joinGraph is an undirected graph of tables connected by foreign keys
+type joinGraph struct {
+	meta  *schemaMetadata
+	edges map[string][]joinEdge
+}
+
+// newJoinGraph builds the join graph of the metadata, ignoring self-referencing foreign keys
+func newJoinGraph(meta *schemaMetadata) *joinGraph {
+	g := &joinGraph{meta: meta, edges: make(map[string][]joinEdge)}
+	for _, fk := range meta.ForeignKeys {
+		child := schemaTableKey(fk.Schema, fk.Table)
+		parent := schemaTableKey(fk.RefSchema, fk.RefTable)
+		if child == parent {
+			continue
+		}
+		g.edges[child] = append(g.edges[child], joinEdge{fk: fk, from: child, to: parent})
+		g.edges[parent] = append(g.edges[parent], joinEdge{fk: fk, from: parent, to: child, reverse: true})
+	}
+	return g
+}
+
+// maxJoinPathExpansions bounds the path search on large, densely connected schemas
+const maxJoinPathExpansions = 10000
+
+// paths returns up to maxPaths simple paths from one table to another using at most
+// maxDepth joins, shortest first
+func (g *joinGraph) paths(from, to string, maxDepth, maxPaths int) [][]joinEdge {
+	type partial struct {
+		last    string
+		edges   []joinEdge
+		visited map[string]bool
+	}
+
+	var found [][]joinEdge
+	queue := []partial{{last: from, visited: map[string]bool{from: true}}}
+	expansions := 0
+	for len(queue) > 0 && len(found) < maxPaths && expansions < maxJoinPathExpansions {
+		current := queue[0]
+		queue = queue[1:]
+		if len(current.edges) >= maxDepth {
+			continue
+		}
+		for _, edge := range g.edges[current.last] {
+			if current.visited[edge.to] {
+				continue
+			}
+			expansions++
+			edges := append(append([]joinEdge(nil), current.edges...), edge)
+			if edge.to == to {
+				found = append(found, edges)
+				if len(found) >= maxPaths {
+					break
+				}
+				continue
+			}
+			visited := make(map[string]bool, len(current.visited)+1)
+			for k := range current.visited {
+				visited[k] = true
+			}
+			visited[edge.to] = true
+			queue = append(queue, partial{last: edge.to, edges: edges, visited: visited})
+		}
+	}
+	return found
+}
+
+// tableRef renders a table key as a quoted reference, omitting the schema of the metadata
+func (g *joinGraph) tableRef(dbType, key string) string {
+	schema, table := splitQualifiedName(key)
+	if schema == "" || schema == g.meta.Schema {
+		return quoteIdentifier(dbType, table)
+	}
+	return quoteIdentifier(dbType, schema) + "." + quoteIdentifier(dbType, table)
+}
+
+// condition renders the ON condition of a join edge
+func (g *joinGraph) condition(dbType string, edge joinEdge) string {
+	child := g.tableRef(dbType, schemaTableKey(edge.fk.Schema, edge.fk.Table))
+	parent := g.tableRef(dbType, schemaTableKey(edge.fk.RefSchema, edge.fk.RefTable))
+	conditions := make([]string, len(edge.fk.Columns))
+	for i, column := range edge.fk.Columns {
+		refColumn := ""
+		if i < len(edge.fk.RefColumns) {
+			refColumn = edge.fk.RefColumns[i]
+		}
+		// The newly joined table comes first, as it is usually written
+		left := fmt.Sprintf("%s.%s", parent, quoteIdentifier(dbType, refColumn))
+		right := fmt.Sprintf("%s.%s", child, quoteIdentifier(dbType, column))
+		if edge.reverse {
+			left, right = right, left
+		}
+		conditions[i] = left + " = " + right
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// joinClause renders the FROM/JOIN clause following a path from its first table
+func (g *joinGraph) joinClause(dbType string, path []joinEdge) string {
+	if len(path) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("FROM " + g.tableRef(dbType, path[0].from))
+	for _, edge := range path {
+		sb.WriteString(fmt.Sprintf("\nJOIN %s ON %s", g.tableRef(dbType, edge.to), g.condition(dbType, edge)))
+	}
+	return sb.String()
+}
+
+// describe renders a path as a readable chain of column references
+func (g *joinGraph) describe(path []joinEdge) string {
+	steps := make([]string, len(path))
+	for i, edge := range path {
+		child := fmt.Sprintf("%s(%s)", edge.fk.Table, strings.Join(edge.fk.Columns, ", "))
+		parent := fmt.Sprintf("%s(%s)", edge.fk.RefTable, strings.Join(edge.fk.RefColumns, ", "))
+		if edge.reverse {
+			steps[i] = fmt.Sprintf("%s <- %s", parent, child)
+		} else {
+			steps[i] = fmt.Sprintf("%s -> %s", child, parent)
+		}
+	}
+	return strings.Join(steps, ", ")
+}
+
+// linkTableParents returns the tables a many-to-many link table connects, or nil when the
+// table is not a link table. A link table has foreign keys to at least two distinct tables
+// and a primary key made up only of foreign key columns.
+func (g *joinGraph) linkTableParents(key string) []string {
+	table := g.meta.table(key)
+	if table == nil || len(table.PrimaryKey) == 0 {
+		return nil
+	}
+
+	fkColumns := make(map[string]bool)
+	var parents []string
+	seen := make(map[string]bool)
+	for _, edge := range g.edges[key] {
+		if edge.reverse {
+			continue
+		}
+		for _, column := range edge.fk.Columns {
+			fkColumns[column] = true
+		}
+		if !seen[edge.to] {
+			seen[edge.to] = true
+			parents = append(parents, edge.to)
+		}
+	}
+	if len(parents) < 2 {
+		return nil
+	}
+	for _, column := range table.PrimaryKey {
+		if !fkColumns[column] {
+			return nil
+		}
+	}
+	return parents
+}
+
+// neighborhood returns the given tables followed by every table reachable from them in at
+// most depth foreign key hops, in either direction, nearest first
+func (g *joinGraph) neighborhood(keys []string, depth int) []string {
+	visited := make(map[string]bool)
+	var result, frontier []string
+	for _, key := range keys {
+		if !visited[key] {
+			visited[key] = true
+			result = append(result, key)
+			frontier = append(frontier, key)
+		}
+	}
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		var next []string
+		for _, key := range frontier {
+			for _, edge := range g.edges[key] {
+				if !visited[edge.to] {
+					visited[edge.to] = true
+					result = append(result, edge.to)
+					next = append(next, edge.to)
+				}
+			}
+		}
+		frontier = next
+	}
+	return result
+}
@@ -0,0 +1,429 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxJSONPaths caps the distinct paths tracked, so objects keyed by ids or dates do not
+// grow the report without bound
+const maxJSONPaths = 500
+
+// jsonStep is one step of a path into a JSON document: an object key, or any array element
+type jsonStep struct {
+	key   string
+	array bool
+}
+
+// jsonPathStats describes one path of a JSON column across the sampled values
+type jsonPathStats struct {
+	steps []jsonStep
+	// rows is the number of sampled values containing the path
+	rows int
+	// types counts each JSON type found at the path
+	types   map[string]int
+	example string
+	lastRow int
+}
+
+// jsonStructure is the key structure inferred from a sample of JSON values
+type jsonStructure struct {
+	sampled   int
+	invalid   int
+	firstErr  error
+	rootTypes map[string]int
+	paths     map[string]*jsonPathStats
+	// truncated is set when paths past maxJSONPaths were dropped
+	truncated bool
+	maxDepth  int
+}
+
+// plainJSONKey matches keys written without quotes in paths
+var plainJSONKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// jsonArrayIndex matches array subscripts in paths given by the caller
+var jsonArrayIndex = regexp.MustCompile(`\[(\*|[0-9]+)\]`)
+
+// newJSONStructure creates an empty structure that descends at most maxDepth levels
+func newJSONStructure(maxDepth int) *jsonStructure {
+	return &jsonStructure{rootTypes: make(map[string]int), paths: make(map[string]*jsonPathStats), maxDepth: maxDepth}
+}
+
+// addValue records the paths of a decoded value
+func (s *jsonStructure) addValue(value interface{}) {
+	s.sampled++
+	s.rootTypes[jsonType(value)]++
+	s.walk(value, nil, 0)
+}
+
+// add parses one JSON value and records its paths
+func (s *jsonStructure) add(text string) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var value interface{}
+	err := decoder.Decode(&value)
+	if err == nil {
+		if _, extra := decoder.Token(); extra != io.EOF {
+			err = fmt.Errorf("unexpected data after the JSON value")
+		}
+	}
+	if err != nil {
+		s.invalid++
+		if s.firstErr == nil {
+			s.firstErr = err
+		}
+		return
+	}
+	s.addValue(value)
+}
+
+// walk records the paths below a value
+func (s *jsonStructure) walk(value interface{}, steps []jsonStep, depth int) {
+	if len(steps) > 0 {
+		s.record(steps, value)
+	}
+	if depth >= s.maxDepth {
+		return
+	}
+	switch val := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			s.walk(val[key], appendStep(steps, jsonStep{key: key}), depth+1)
+		}
+	case []interface{}:
+		for _, item := range val {
+			s.walk(item, appendStep(steps, jsonStep{array: true}), depth+1)
+		}
+	}
+}
+
+// record counts a value found at a path, once per sampled row
+func (s *jsonStructure) record(steps []jsonStep, value interface{}) {
+	path := jsonPathString(steps)
+	stats, ok := s.paths[path]
+	if !ok {
+		if len(s.paths) >= maxJSONPaths {
+			s.truncated = true
+			return
+		}
+		stats = &jsonPathStats{steps: steps, types: make(map[string]int)}
+		s.paths[path] = stats
+	}
+	if stats.lastRow != s.sampled {
+		stats.lastRow = s.sampled
+		stats.rows++
+	}
+	kind := jsonType(value)
+	stats.types[kind]++
+	if stats.example == "" && kind != "object" && kind != "array" && kind != "null" {
+		stats.example = fmt.Sprintf("%v", value)
+	}
+}
+
+// sortedPaths returns the recorded paths in path order, which keeps children under their parent
+func (s *jsonStructure) sortedPaths() []string {
+	paths := make([]string, 0, len(s.paths))
+	for path := range s.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// candidatePaths picks the paths worth indexing when the caller named none: scalar paths
+// outside arrays found in at least half of the sampled values, most common first
+func (s *jsonStructure) candidatePaths(limit int) []string {
+	var candidates []string
+	for path, stats := range s.paths {
+		if _, ok := stats.scalarType(); ok && !stats.inArray() && stats.rows*2 >= s.sampled {
+			candidates = append(candidates, path)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := s.paths[candidates[i]], s.paths[candidates[j]]
+		if a.rows != b.rows {
+			return a.rows > b.rows
+		}
+		return candidates[i] < candidates[j]
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates
+}
+
+// inArray reports whether the path runs through an array
+func (p *jsonPathStats) inArray() bool {
+	for _, step := range p.steps {
+		if step.array {
+			return true
+		}
+	}
+	return false
+}
+
+// scalarType returns the scalar type an index on the path should use: integer or number when
+// every value is numeric, boolean when every value is, and string otherwise. It is false when
+// the path holds objects or arrays.
+func (p *jsonPathStats) scalarType() (string, bool) {
+	if p.types["object"] > 0 || p.types["array"] > 0 {
+		return "", false
+	}
+	switch {
+	case p.types["string"] > 0:
+		return "string", true
+	case p.types["boolean"] > 0 && p.types["integer"]+p.types["number"] > 0:
+		return "string", true
+	case p.types["boolean"] > 0:
+		return "boolean", true
+	case p.types["number"] > 0:
+		return "number", true
+	case p.types["integer"] > 0:
+		return "integer", true
+	default:
+		return "", false
+	}
+}
+
+// typeSummary lists the types found at the path, most common first
+func (p *jsonPathStats) typeSummary() string {
+	kinds := make([]string, 0, len(p.types))
+	for kind := range p.types {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		if p.types[kinds[i]] != p.types[kinds[j]] {
+			return p.types[kinds[i]] > p.types[kinds[j]]
+		}
+		return kinds[i] < kinds[j]
+	})
+	parts := make([]string, len(kinds))
+	for i, kind := range kinds {
+		parts[i] = fmt.Sprintf("%s %d", kind, p.types[kind])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(value interface{}) string {
+	switch val := value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if strings.ContainsAny(val.String(), ".eE") {
+			return "number"
+		}
+		return "integer"
+	case bsonScalar:
+		return val.kind
+	default:
+		return "unknown"
+	}
+}
+
+// appendStep returns a copy of steps with one more step, so paths recorded earlier keep theirs
+func appendStep(steps []jsonStep, step jsonStep) []jsonStep {
+	next := make([]jsonStep, len(steps), len(steps)+1)
+	copy(next, steps)
+	return append(next, step)
+}
+
+// jsonPathString renders a path as dotted keys with [] for array elements, quoting keys that
+// are not plain identifiers: customer.address.city, items[].sku, "content-type"
+func jsonPathString(steps []jsonStep) string {
+	var sb strings.Builder
+	for i, step := range steps {
+		if step.array {
+			sb.WriteString("[]")
+			continue
+		}
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+		if plainJSONKey.MatchString(step.key) {
+			sb.WriteString(step.key)
+		} else {
+			sb.WriteString(fmt.Sprintf("%q", step.key))
+		}
+	}
+	return sb.String()
+}
+
+// normalizeJSONPath turns a path given by the caller into the form paths are reported in,
+// accepting a leading $ and array subscripts: $.items[0].sku becomes items[].sku
+func normalizeJSONPath(path string) string {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	path = strings.TrimPrefix(path, ".")
+	return jsonArrayIndex.ReplaceAllString(path, "[]")
+}
+
+// jsonExplorer reads JSON columns and writes the indexes serving filters on their paths
+type jsonExplorer interface {
+	// columnTypeQuery returns the query for the declared type of a column, bound to the
+	// schema, table and column
+	columnTypeQuery() string
+	// textValue returns the expression reading a quoted JSON column as text
+	textValue(column string) string
+	// arrayIndexHint names the index that covers a path running through an array
+	arrayIndexHint() string
+	// pathExpression returns the expression extracting a scalar path as text, and the same
+	// value converted to the SQL type of its JSON type
+	pathExpression(column, columnType string, steps []jsonStep, kind string) (string, string)
+	// indexSuggestion renders the expression index and the generated column on the quoted
+	// target table that would let filters on a path use an index
+	indexSuggestion(target, generated, indexName, text, typed, kind string) string
+	// containmentIndex returns the index serving containment filters on every path of the
+	// column, or "" when the column type has none
+	containmentIndex(target, column, columnType string) string
+}
+
+// postgresJSONExplorer reads json and jsonb columns, and JSON kept in text columns
+type postgresJSONExplorer struct{}
+
+func (postgresJSONExplorer) columnTypeQuery() string {
+	return `SELECT data_type FROM information_schema.columns
+WHERE table_schema = COALESCE(NULLIF($1, ''), current_schema()) AND table_name = $2 AND column_name = $3`
+}
+
+func (postgresJSONExplorer) textValue(column string) string { return column + "::text" }
+
+func (postgresJSONExplorer) arrayIndexHint() string {
+	return "a GIN index on the column, filtering with @> containment"
+}
+
+func (postgresJSONExplorer) pathExpression(column, columnType string, steps []jsonStep, kind string) (string, string) {
+	expr := quoteIdentifier("postgres", column)
+	if columnType != "json" && columnType != "jsonb" {
+		expr += "::jsonb"
+	}
+	for i, step := range steps {
+		operator := "->"
+		if i == len(steps)-1 {
+			operator = "->>"
+		}
+		expr += operator + quoteLiteral("postgres", step.key)
+	}
+	switch kind {
+	case "integer":
+		return expr, fmt.Sprintf("(%s)::bigint", expr)
+	case "number":
+		return expr, fmt.Sprintf("(%s)::numeric", expr)
+	case "boolean":
+		return expr, fmt.Sprintf("(%s)::boolean", expr)
+	default:
+		return expr, expr
+	}
+}
+
+func (postgresJSONExplorer) indexSuggestion(target, generated, indexName, text, typed, kind string) string {
+	sqlType := map[string]string{"integer": "bigint", "number": "numeric", "boolean": "boolean", "string": "text"}[kind]
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("-- Expression index; queries must filter on the same expression\nCREATE INDEX %s ON %s ((%s));\n",
+		quoteIdentifier("postgres", indexName), target, typed))
+	sb.WriteString(fmt.Sprintf("-- Or a generated column (PostgreSQL 12+), which queries can filter on by name\nALTER TABLE %s ADD COLUMN %s %s GENERATED ALWAYS AS (%s) STORED;\nCREATE INDEX ON %s (%s);\n",
+		target, quoteIdentifier("postgres", generated), sqlType, typed, target, quoteIdentifier("postgres", generated)))
+	return sb.String()
+}
+
+func (postgresJSONExplorer) containmentIndex(target, column, columnType string) string {
+	if columnType != "jsonb" {
+		return ""
+	}
+	return fmt.Sprintf("CREATE INDEX ON %s USING GIN (%s jsonb_path_ops);", target, quoteIdentifier("postgres", column))
+}
+
+// mysqlJSONExplorer reads JSON columns with JSON_EXTRACT
+type mysqlJSONExplorer struct{}
+
+func (mysqlJSONExplorer) columnTypeQuery() string {
+	return `SELECT DATA_TYPE FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE()) AND TABLE_NAME = ? AND COLUMN_NAME = ?`
+}
+
+func (mysqlJSONExplorer) textValue(column string) string {
+	return fmt.Sprintf("CAST(%s AS CHAR)", column)
+}
+
+func (mysqlJSONExplorer) arrayIndexHint() string {
+	return "a multi-valued index such as CREATE INDEX ... ((CAST(col->'$.path' AS CHAR(64) ARRAY)))"
+}
+
+func (mysqlJSONExplorer) pathExpression(column, columnType string, steps []jsonStep, kind string) (string, string) {
+	var path strings.Builder
+	path.WriteString("$")
+	for _, step := range steps {
+		if plainJSONKey.MatchString(step.key) {
+			path.WriteString("." + step.key)
+		} else {
+			path.WriteString(fmt.Sprintf(".%q", step.key))
+		}
+	}
+	extract := fmt.Sprintf("JSON_EXTRACT(%s, %s)", quoteIdentifier("mysql", column), quoteLiteral("mysql", path.String()))
+	text := fmt.Sprintf("JSON_UNQUOTE(%s)", extract)
+	switch kind {
+	case "integer":
+		return text, fmt.Sprintf("CAST(%s AS SIGNED)", extract)
+	case "number":
+		return text, fmt.Sprintf("CAST(%s AS DOUBLE)", extract)
+	default:
+		return text, fmt.Sprintf("CAST(%s AS CHAR(255)) COLLATE utf8mb4_bin", text)
+	}
+}
+
+func (mysqlJSONExplorer) indexSuggestion(target, generated, indexName, text, typed, kind string) string {
+	sqlType := map[string]string{"integer": "BIGINT", "number": "DOUBLE", "boolean": "VARCHAR(5)", "string": "VARCHAR(255)"}[kind]
+	generatedExpr := text
+	if kind == "integer" || kind == "number" {
+		generatedExpr = typed
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("-- Expression index (MySQL 8.0.13+); queries must filter on the same expression\nCREATE INDEX %s ON %s ((%s));\n",
+		quoteIdentifier("mysql", indexName), target, typed))
+	sb.WriteString(fmt.Sprintf("-- Or an indexed generated column, which queries can filter on by name\nALTER TABLE %s ADD COLUMN %s %s GENERATED ALWAYS AS (%s) VIRTUAL, ADD INDEX (%s);\n",
+		target, quoteIdentifier("mysql", generated), sqlType, generatedExpr, quoteIdentifier("mysql", generated)))
+	return sb.String()
+}
+
+// containmentIndex returns ""; JSON_CONTAINS cannot use an index outside multi-valued ones
+func (mysqlJSONExplorer) containmentIndex(string, string, string) string { return "" }
+
+// jsonIndexSuggestion renders the expression index and the generated column that would let
+// filters on a path use an index
+func jsonIndexSuggestion(dialect Dialect, schema, table, column, columnType string, stats *jsonPathStats, kind string) string {
+	explorer, err := dialect.JSONExplorer()
+	if err != nil {
+		return ""
+	}
+	target := dialect.QuoteIdent(table)
+	if schema != "" {
+		target = dialect.QuoteIdent(schema) + "." + target
+	}
+	keys := make([]string, len(stats.steps))
+	for i, step := range stats.steps {
+		keys[i] = step.key
+	}
+	generated := sanitizeColumnName(column+"_"+strings.Join(keys, "_"), 0)
+	indexName := generated + "_idx"
+	if len(indexName) > 63 {
+		indexName = indexName[:63]
+	}
+	text, typed := explorer.pathExpression(column, columnType, stats.steps, kind)
+	return explorer.indexSuggestion(target, generated, indexName, text, typed, kind)
+}
@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redisText returns a scalar reply as text
+func redisText(reply interface{}) string {
+	switch value := reply.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// redisInt returns an integer reply, which some commands send as a string
+func redisInt(reply interface{}) (int64, bool) {
+	switch value := reply.(type) {
+	case int64:
+		return value, true
+	case string:
+		n, err := strconv.ParseInt(value, 10, 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// redisCell returns a value for a markdown table cell, cut to maxCellLength bytes
+func redisCell(value string) string {
+	const maxCellLength = 200
+	if len(value) > maxCellLength {
+		value = value[:maxCellLength] + "…"
+	}
+	value = strconv.Quote(value)
+	return strings.ReplaceAll(value[1:len(value)-1], "|", "\\|")
+}
+
+// formatRedisReply formats a reply the way redis-cli prints it
+func formatRedisReply(reply interface{}) string {
+	var sb strings.Builder
+	writeRedisReply(&sb, reply, "")
+	return sb.String()
+}
+
+func writeRedisReply(sb *strings.Builder, reply interface{}, indent string) {
+	switch value := reply.(type) {
+	case nil:
+		sb.WriteString("(nil)\n")
+	case int64:
+		sb.WriteString(fmt.Sprintf("(integer) %d\n", value))
+	case string:
+		sb.WriteString(strconv.Quote(value) + "\n")
+	case error:
+		sb.WriteString("(error) " + value.Error() + "\n")
+	case []interface{}:
+		if len(value) == 0 {
+			sb.WriteString("(empty array)\n")
+			return
+		}
+		width := len(strconv.Itoa(len(value)))
+		for i, item := range value {
+			if i > 0 {
+				sb.WriteString(indent)
+			}
+			prefix := fmt.Sprintf("%*d) ", width, i+1)
+			sb.WriteString(prefix)
+			writeRedisReply(sb, item, indent+strings.Repeat(" ", len(prefix)))
+		}
+	default:
+		sb.WriteString(fmt.Sprint(value) + "\n")
+	}
+}
+
+// infoSection is one section of INFO output, such as Memory, with its fields in order
+type infoSection struct {
+	name   string
+	fields [][2]string
+}
+
+// parseRedisInfo parses INFO output into its sections
+func parseRedisInfo(text string) []infoSection {
+	var sections []infoSection
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "# "):
+			sections = append(sections, infoSection{name: strings.TrimPrefix(line, "# ")})
+		case line == "" || len(sections) == 0:
+		default:
+			if name, value, ok := strings.Cut(line, ":"); ok {
+				last := &sections[len(sections)-1]
+				last.fields = append(last.fields, [2]string{name, value})
+			}
+		}
+	}
+	return sections
+}
+
+// infoFields returns the fields of every INFO section by name
+func infoFields(sections []infoSection) map[string]string {
+	fields := map[string]string{}
+	for _, section := range sections {
+		for _, field := range section.fields {
+			fields[field[0]] = field[1]
+		}
+	}
+	return fields
+}
+
+// parseInfoValues parses values such as keys=12,expires=3,avg_ttl=0 from the Keyspace and
+// Commandstats sections
+func parseInfoValues(text string) map[string]string {
+	values := map[string]string{}
+	for _, pair := range strings.Split(text, ",") {
+		if name, value, ok := strings.Cut(pair, "="); ok {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// formatTTL formats the reply of TTL: -1 means the key never expires and -2 that it is gone
+func formatTTL(seconds int64) string {
+	switch {
+	case seconds == -1:
+		return "none"
+	case seconds < 0:
+		return "expired"
+	default:
+		return (time.Duration(seconds) * time.Second).String()
+	}
+}
@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeKeyValueStore is a KeyValueStore answering commands, joined by spaces, with canned replies
+type fakeKeyValueStore struct {
+	replies map[string]interface{}
+	// commands records every command sent
+	commands []string
+}
+
+func (f *fakeKeyValueStore) Command(ctx context.Context, args ...string) (interface{}, error) {
+	command := strings.Join(args, " ")
+	f.commands = append(f.commands, command)
+	reply, ok := f.replies[command]
+	if !ok {
+		return nil, fmt.Errorf("ERR unknown command '%s'", args[0])
+	}
+	if err, ok := reply.(error); ok {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// keyToolText runs a key tool and returns the text of its response
+func keyToolText(t *testing.T, tool ToolType, useCase *mockUseCase, params map[string]interface{}) string {
+	result, err := tool.HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	if err != nil {
+		t.Fatalf("tool failed: %v", err)
+	}
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+}
+
+func TestFormatRedisReply(t *testing.T) {
+	assert.Equal(t, "(nil)\n", formatRedisReply(nil))
+	assert.Equal(t, "(integer) 3\n", formatRedisReply(int64(3)))
+	assert.Equal(t, "\"a \\\"b\\\"\\n\"\n", formatRedisReply("a \"b\"\n"))
+	assert.Equal(t, "(empty array)\n", formatRedisReply([]interface{}{}))
+	assert.Equal(t, "1) \"0\"\n2) 1) \"user:1\"\n   2) (nil)\n3) (error) ERR no\n",
+		formatRedisReply([]interface{}{"0", []interface{}{"user:1", nil}, fmt.Errorf("ERR no")}))
+}
+
+func TestScanKeysTool(t *testing.T) {
+	store := &fakeKeyValueStore{replies: map[string]interface{}{
+		"SCAN 0 MATCH user:* COUNT 1000":  []interface{}{"17", []interface{}{"user:2", "user:1"}},
+		"SCAN 17 MATCH user:* COUNT 1000": []interface{}{"0", []interface{}{"user:1", "user:3|x"}},
+		"TYPE user:1":                     "hash",
+		"TYPE user:2":                     "string",
+		"TYPE user:3|x":                   "zset",
+		"TTL user:1":                      int64(-1),
+		"TTL user:2":                      int64(90),
+		"TTL user:3|x":                    int64(-1),
+		"MEMORY USAGE user:1":             int64(2048),
+		"MEMORY USAGE user:2":             int64(56),
+		"MEMORY USAGE user:3|x":           fmt.Errorf("NOPERM this user has no permissions to run the 'memory' command"),
+	}}
+	useCase := &mockUseCase{keyValues: store}
+
+	text := keyToolText(t, NewScanKeysTool(), useCase, map[string]interface{}{"database": "cache", "pattern": "user:*"})
+	assert.Contains(t, text, "# Keys Matching user:* in Database cache")
+	assert.Contains(t, text, "| user:1 | hash | none | 2.0 KiB |\n| user:2 | string | 1m30s | 56 B |\n| user:3\\|x | zset | none |  |")
+	assert.Contains(t, text, "3 keys")
+
+	// The scan stops once it holds one key more than the limit
+	store.commands = nil
+	text = keyToolText(t, NewScanKeysTool(), useCase, map[string]interface{}{"database": "cache", "pattern": "user:*", "limit": 1})
+	assert.Contains(t, text, "| user:1 | hash |")
+	assert.NotContains(t, text, "user:2")
+	assert.Contains(t, text, "Showing the first 1 keys")
+	assert.Equal(t, "SCAN 0 MATCH user:* COUNT 1000", store.commands[0])
+	assert.Equal(t, "TYPE user:1", store.commands[1])
+
+	// A type filter goes to SCAN and saves the TYPE calls
+	store.replies["SCAN 0 MATCH * COUNT 1000 TYPE hash"] = []interface{}{"0", []interface{}{"user:1"}}
+	store.commands = nil
+	text = keyToolText(t, NewScanKeysTool(), useCase, map[string]interface{}{"database": "cache", "type": "hash"})
+	assert.Contains(t, text, "| user:1 | hash | none | 2.0 KiB |")
+	assert.NotContains(t, store.commands, "TYPE user:1")
+
+	_, err := NewScanKeysTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "pg"}}, "", &mockUseCase{dbType: "postgres"})
+	assert.EqualError(t, err, "database pg is not a key-value database")
+}
+
+func TestInspectKeyTool(t *testing.T) {
+	store := &fakeKeyValueStore{replies: map[string]interface{}{
+		"TYPE leaders":                  "zset",
+		"TTL leaders":                   int64(3600),
+		"MEMORY USAGE leaders":          int64(512),
+		"OBJECT ENCODING leaders":       "listpack",
+		"ZCARD leaders":                 int64(5),
+		"ZRANGE leaders 0 1 WITHSCORES": []interface{}{"alice", "120", "bob", "97.5"},
+		"TYPE user:1":                   "hash",
+		"TTL user:1":                    int64(-1),
+		"MEMORY USAGE user:1":           int64(96),
+		"OBJECT ENCODING user:1":        "listpack",
+		"HLEN user:1":                   int64(2),
+		"HSCAN user:1 0 COUNT 40":       []interface{}{"0", []interface{}{"name", "Alice", "email", "a@example.com"}},
+		"TYPE greeting":                 "string",
+		"TTL greeting":                  int64(-1),
+		"MEMORY USAGE greeting":         int64(64),
+		"OBJECT ENCODING greeting":      "embstr",
+		"STRLEN greeting":               int64(5),
+		"GETRANGE greeting 0 4095":      "hello",
+		"TYPE missing":                  "none",
+		"TTL missing":                   int64(-2),
+		"MEMORY USAGE missing":          nil,
+	}}
+	useCase := &mockUseCase{keyValues: store}
+
+	text := keyToolText(t, NewInspectKeyTool(), useCase, map[string]interface{}{"database": "cache", "key": "leaders", "limit": 2})
+	assert.Contains(t, text, "| Type | zset |\n| TTL | 1h0m0s |\n| Memory | 512 B |\n| Encoding | listpack |\n| Length | 5 |")
+	assert.Contains(t, text, "| Member | Score |\n|--------|-------|\n| alice | 120 |\n| bob | 97.5 |")
+	assert.Contains(t, text, "Showing 2 of 5 elements")
+
+	text = keyToolText(t, NewInspectKeyTool(), useCase, map[string]interface{}{"database": "cache", "key": "user:1"})
+	assert.Contains(t, text, "| name | Alice |\n| email | a@example.com |")
+	assert.NotContains(t, text, "Showing")
+
+	text = keyToolText(t, NewInspectKeyTool(), useCase, map[string]interface{}{"database": "cache", "key": "greeting"})
+	assert.Contains(t, text, "```\nhello\n```")
+
+	_, err := NewInspectKeyTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "cache", "key": "missing"}}, "", useCase)
+	assert.EqualError(t, err, "key missing does not exist in database cache")
+}
+
+func TestRedisDbStats(t *testing.T) {
+	info := "# Server\r\nredis_version:7.2.4\r\nredis_mode:standalone\r\nuptime_in_seconds:93784\r\n\r\n" +
+		"# Memory\r\nused_memory_human:1.50M\r\nmaxmemory_human:0B\r\nmaxmemory_policy:noeviction\r\n\r\n" +
+		"# Stats\r\ntotal_commands_processed:1000\r\nkeyspace_hits:90\r\nkeyspace_misses:10\r\n\r\n" +
+		"# Replication\r\nrole:master\r\n\r\n# Keyspace\r\ndb0:keys=120,expires=20,avg_ttl=65000\r\ndb2:keys=3,expires=0,avg_ttl=0\r\n"
+	store := &fakeKeyValueStore{replies: map[string]interface{}{
+		"INFO":              info,
+		"INFO commandstats": "# Commandstats\r\ncmdstat_get:calls=10,usec=50,usec_per_call=5.00\r\ncmdstat_scan:calls=2,usec=4000,usec_per_call=2000.00\r\n",
+		"SLOWLOG GET 10": []interface{}{
+			[]interface{}{int64(4), int64(1704067200), int64(15000), []interface{}{"SCAN", "0", "MATCH", "*"}, "127.0.0.1:5000", ""},
+		},
+	}}
+	useCase := &mockUseCase{dbType: "redis", keyValues: store}
+
+	text := keyToolText(t, NewDbStatsTool(), useCase, map[string]interface{}{"database": "cache"})
+	assert.Contains(t, text, "# Database Statistics for cache (redis)")
+	assert.Contains(t, text, "| Version | 7.2.4 |\n| Mode | standalone |\n| Role | master |\n| Uptime | 26h3m4s |")
+	assert.Contains(t, text, "| Memory limit | none |")
+	assert.Contains(t, text, "| Commands processed | 1000 |\n| Keyspace hit ratio | 90.0% |")
+	assert.Contains(t, text, "| db0 | 120 | 20 | 1m5s |\n| db2 | 3 | 0 | none |")
+	assert.NotContains(t, text, "Command Statistics")
+
+	text = keyToolText(t, NewDbStatsTool(), useCase, map[string]interface{}{"database": "cache", "detailed": true})
+	assert.Contains(t, text, "| scan | 2 | 4ms | 2ms |\n| get | 10 | 50µs | 5µs |")
+	assert.Contains(t, text, "| 4 | 2024-01-01T00:00:00Z | 15ms | SCAN 0 MATCH * |")
+}
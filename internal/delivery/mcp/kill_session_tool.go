@@ -0,0 +1,181 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// KillSessionTool handles cancelling the query of a session or terminating the session
+type KillSessionTool struct {
+	BaseToolType
+}
+
+// NewKillSessionTool creates a new kill session tool type
+func NewKillSessionTool() *KillSessionTool {
+	return &KillSessionTool{
+		BaseToolType: BaseToolType{
+			name:        "kill_session",
+			description: "[DANGEROUS] Cancel the running query of a session, or terminate the session and roll back its open transaction, using pg_cancel_backend/pg_terminate_backend on PostgreSQL and KILL QUERY/KILL on MySQL and TiDB. Without confirm it only shows the session that would be affected and the statement that would end it. Re-run with confirm set to true to execute it. Every kill is logged. Find the session to end with get_active_sessions or get_locks.",
+		},
+	}
+}
+
+// CreateTool creates a kill session tool
+func (t *KillSessionTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("[DANGEROUS] Cancel a session's query or terminate the session; requires confirm"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithNumber("session",
+			tools.Description("Session to end: the PostgreSQL backend pid or the MySQL connection ID"),
+			tools.Required(),
+		),
+		tools.WithString("mode",
+			tools.Description("cancel to stop only the running query, terminate to close the session and roll back its transaction (default: cancel)"),
+		),
+		tools.WithBoolean("confirm",
+			tools.Description("Set to true to execute; without it the session is only previewed (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles kill session tool requests
+func (t *KillSessionTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	if !input.has("session") {
+		input.fail("session parameter is required")
+	}
+	sessionID := input.intAtLeast("session", 0, 1)
+	mode := input.choice("mode", "cancel", "cancel", "terminate")
+	confirm := input.optionalBool("confirm", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for kill_session: %s", dbType)
+	}
+	lookupQuery, ok := dialect.SessionQuery()
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for kill_session: %s", dbType)
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, targetDbID, lookupQuery, []interface{}{sessionID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up session %d: %w", sessionID, err)
+	}
+	sessions := readActiveSessions(result.Rows)
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("session %d not found; it may already have ended", sessionID)
+	}
+	session := sessions[0]
+	if len(result.Rows[0]) > 9 && valueBool(result.Rows[0][9]) {
+		return nil, fmt.Errorf("refusing to kill session %d, the connection this server is using", sessionID)
+	}
+	statement, reportsSignal := dialect.KillSessionStatement(int64(sessionID), mode == "terminate")
+
+	var response strings.Builder
+	if !confirm {
+		response.WriteString(fmt.Sprintf("# Kill Session Preview for Database %s\n\n", targetDbID))
+	} else {
+		response.WriteString(fmt.Sprintf("# Kill Session on Database %s\n\n", targetDbID))
+	}
+	writeActiveSessions(&response, sessions, 0)
+	response.WriteString(fmt.Sprintf("\n```sql\n%s;\n```\n\n", statement))
+
+	if !confirm {
+		response.WriteString(fmt.Sprintf("Nothing was done. Re-run with confirm=true to %s session %d.\n", mode, sessionID))
+		return createTextResponse(response.String()), nil
+	}
+
+	logger.Info("kill_session: %s session %d (user %s, query %q) on database %s", mode, sessionID, session.User, queryCell(session.Query), targetDbID)
+	err = signalSession(ctx, useCase, targetDbID, statement, reportsSignal)
+	audit(request, auditRecord{
+		Tool:      "kill_session",
+		Database:  targetDbID,
+		Action:    mode,
+		Target:    fmt.Sprintf("session %d (user %s)", sessionID, session.User),
+		Statement: statement,
+	}, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s session %d: %w", mode, sessionID, err)
+	}
+
+	if mode == "cancel" {
+		response.WriteString(fmt.Sprintf("Cancelled the running query of session %d; the session stays connected and an open transaction is not rolled back until its client does so.\n", sessionID))
+	} else {
+		response.WriteString(fmt.Sprintf("Terminated session %d; its open transaction is rolled back.\n", sessionID))
+	}
+	return createTextResponse(response.String()), nil
+}
+
+// signalSession runs the statement that cancels or terminates a session, checking the result
+// of a statement that reports whether the session was signalled
+func signalSession(ctx context.Context, useCase UseCaseProvider, dbID, statement string, reportsSignal bool) error {
+	if !reportsSignal {
+		_, err := useCase.ExecuteStatement(ctx, dbID, statement, nil)
+		return err
+	}
+	result, err := useCase.ExecuteQuery(ctx, dbID, statement, nil)
+	if err != nil {
+		return err
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) == 0 || !valueBool(result.Rows[0][0]) {
+		return errors.New("it ended before it could be signalled")
+	}
+	return nil
+}
+
+// getPostgresSessionQuery returns a query for one session, in the columns of the active
+// sessions query followed by whether it is the session running the query
+func getPostgresSessionQuery() string {
+	return `
+SELECT
+    pid,
+    usename,
+    COALESCE(host(client_addr), 'local') AS client,
+    application_name,
+    state,
+    CASE WHEN wait_event IS NOT NULL THEN wait_event_type || ': ' || wait_event END AS wait_event,
+    COALESCE(EXTRACT(EPOCH FROM now() - CASE WHEN state = 'active' THEN query_start ELSE state_change END)::bigint, 0) AS duration_seconds,
+    COALESCE(EXTRACT(EPOCH FROM now() - xact_start)::bigint, 0) AS xact_seconds,
+    left(query, 1000) AS query,
+    pid = pg_backend_pid() AS is_current
+FROM pg_stat_activity
+WHERE pid = $1;`
+}
+
+// getMySQLSessionQuery returns a query for one connection, in the columns of the active
+// sessions query followed by whether it is the connection running the query
+func getMySQLSessionQuery() string {
+	return `
+SELECT
+    p.ID,
+    p.USER,
+    SUBSTRING_INDEX(p.HOST, ':', 1) AS client,
+    p.DB,
+    p.COMMAND,
+    NULLIF(p.STATE, '') AS wait_event,
+    p.TIME AS duration_seconds,
+    COALESCE(TIMESTAMPDIFF(SECOND, x.trx_started, NOW()), 0) AS xact_seconds,
+    LEFT(p.INFO, 1000) AS query,
+    p.ID = CONNECTION_ID() AS is_current
+FROM information_schema.PROCESSLIST p
+LEFT JOIN information_schema.INNODB_TRX x ON x.trx_mysql_thread_id = p.ID
+WHERE p.ID = ?;`
+}
@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func killSession(useCase *mockUseCase, params map[string]interface{}) (string, error) {
+	result, err := NewKillSessionTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	if err != nil {
+		return "", err
+	}
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string), nil
+}
+
+func postgresSessionUseCase(signalled bool) *mockUseCase {
+	return &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_stat_activity": {Rows: [][]interface{}{
+				{int64(4711), "batch", "10.0.0.9", "loader", "idle in transaction", "Client: ClientRead", int64(600), int64(610), "UPDATE jobs SET done = true", false},
+			}},
+			"pg_terminate_backend(4711)": {Rows: [][]interface{}{{signalled}}},
+		},
+	}
+}
+
+func TestKillSessionPreviewsUntilConfirmed(t *testing.T) {
+	useCase := postgresSessionUseCase(true)
+	text, err := killSession(useCase, map[string]interface{}{"database": "pg1", "session": float64(4711), "mode": "terminate"})
+	assert.NoError(t, err)
+	assert.Len(t, useCase.queries, 1)
+	assert.Contains(t, text, "# Kill Session Preview for Database pg1\n")
+	assert.Contains(t, text, "| 4711 | batch | 10.0.0.9 | loader | idle in transaction | Client: ClientRead | 600s | 610s | UPDATE jobs SET done = true |")
+	assert.Contains(t, text, "```sql\nSELECT pg_terminate_backend(4711);\n```")
+	assert.Contains(t, text, "Nothing was done. Re-run with confirm=true to terminate session 4711.\n")
+
+	text, err = killSession(useCase, map[string]interface{}{"database": "pg1", "session": float64(4711), "mode": "terminate", "confirm": true})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT pg_terminate_backend(4711)", useCase.queries[len(useCase.queries)-1])
+	assert.Contains(t, text, "Terminated session 4711; its open transaction is rolled back.\n")
+
+	_, err = killSession(postgresSessionUseCase(false), map[string]interface{}{"database": "pg1", "session": float64(4711), "mode": "terminate", "confirm": true})
+	assert.ErrorContains(t, err, "failed to terminate session 4711: it ended before it could be signalled")
+}
+
+func TestKillSessionCancelsMySQLQuery(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{"information_schema.PROCESSLIST": {Rows: [][]interface{}{
+			{int64(41), "etl", "10.1.2.3", "shop", "Query", "Sending data", int64(400), int64(400), "INSERT INTO archive SELECT * FROM orders", int64(0)},
+		}}},
+	}
+	text, err := killSession(useCase, map[string]interface{}{"database": "mysql1", "session": float64(41), "confirm": true})
+	assert.NoError(t, err)
+	assert.Equal(t, "KILL QUERY 41", useCase.queries[1])
+	assert.Contains(t, text, "Cancelled the running query of session 41")
+}
+
+func TestKillSessionRefusesUnsafeRequests(t *testing.T) {
+	_, err := killSession(&mockUseCase{dbType: "postgres"}, map[string]interface{}{"database": "pg1"})
+	assert.ErrorContains(t, err, "session parameter is required")
+
+	_, err = killSession(&mockUseCase{dbType: "postgres"}, map[string]interface{}{"database": "pg1", "session": float64(1), "mode": "drop"})
+	assert.Error(t, err)
+
+	useCase := &mockUseCase{dbType: "postgres"}
+	_, err = killSession(useCase, map[string]interface{}{"database": "pg1", "session": float64(99), "confirm": true})
+	assert.ErrorContains(t, err, "session 99 not found")
+	assert.Len(t, useCase.queries, 1)
+
+	useCase = &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{"information_schema.PROCESSLIST": {Rows: [][]interface{}{
+			{int64(7), "mcp", "localhost", "shop", "Query", "executing", int64(0), int64(0), "SELECT", int64(1)},
+		}}},
+	}
+	_, err = killSession(useCase, map[string]interface{}{"database": "mysql1", "session": float64(7), "confirm": true})
+	assert.ErrorContains(t, err, "refusing to kill session 7")
+	assert.Len(t, useCase.queries, 1)
+}
+
+func TestKillSessionWritesAuditRecords(t *testing.T) {
+	var records []auditRecord
+	sink := auditSink
+	auditSink = func(record auditRecord) { records = append(records, record) }
+	defer func() { auditSink = sink }()
+
+	kill := func(useCase *mockUseCase) error {
+		request := server.ToolCallRequest{
+			Parameters: map[string]interface{}{"database": "pg1", "session": float64(4711), "mode": "terminate", "confirm": true},
+			Session:    &types.ClientSession{ID: "s1"},
+		}
+		_, err := NewKillSessionTool().HandleRequest(context.Background(), request, "", useCase)
+		return err
+	}
+
+	_, err := killSession(postgresSessionUseCase(true), map[string]interface{}{"database": "pg1", "session": float64(4711), "mode": "terminate"})
+	assert.NoError(t, err)
+	assert.Empty(t, records, "a preview is not audited")
+
+	assert.NoError(t, kill(postgresSessionUseCase(true)))
+	assert.Error(t, kill(postgresSessionUseCase(false)))
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, "kill_session", records[0].Tool)
+		assert.Equal(t, "s1", records[0].Session)
+		assert.Equal(t, "pg1", records[0].Database)
+		assert.Equal(t, "terminate", records[0].Action)
+		assert.Equal(t, "session 4711 (user batch)", records[0].Target)
+		assert.Equal(t, "SELECT pg_terminate_backend(4711)", records[0].Statement)
+		assert.Equal(t, "ok", records[0].Result)
+		assert.Empty(t, records[0].Error)
+		assert.False(t, records[0].Time.IsZero())
+
+		assert.Equal(t, "failed", records[1].Result)
+		assert.Equal(t, "it ended before it could be signalled", records[1].Error)
+	}
+}
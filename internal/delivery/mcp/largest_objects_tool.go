@@ -0,0 +1,272 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// sizeSnapshotFile is where largest_objects keeps the sizes of its previous run, under the
+// snapshots directory of the database
+var sizeSnapshotFile = filepath.Join("sizes", "largest_objects.json")
+
+// storedObject is a table, index, materialized view or TOAST table and its size on disk
+type storedObject struct {
+	Schema string
+	Name   string
+	Kind   string
+	Table  string // table an index or TOAST table belongs to
+	Bytes  int64
+}
+
+// key identifies the object across size snapshots
+func (o storedObject) key() string {
+	return o.Kind + ":" + qualifiedName(o.Schema, o.Name)
+}
+
+// sizeSnapshot is a saved copy of the object sizes of a database
+type sizeSnapshot struct {
+	Database  string           `json:"database"`
+	CreatedAt time.Time        `json:"created_at"`
+	Sizes     map[string]int64 `json:"sizes"`
+}
+
+// LargestObjectsTool handles ranking the largest objects of a database
+type LargestObjectsTool struct {
+	BaseToolType
+}
+
+// NewLargestObjectsTool creates a new largest objects tool type
+func NewLargestObjectsTool() *LargestObjectsTool {
+	return &LargestObjectsTool{
+		BaseToolType: BaseToolType{
+			name:        "largest_objects",
+			description: "Rank the largest tables, indexes, materialized views and TOAST tables across the whole database by their size on disk, with the pretty-printed and raw byte size of each and the table an index or TOAST table belongs to. On MySQL the data and the secondary indexes of each table are ranked separately. The sizes of every run are saved in the snapshots directory of the database, and the next run reports how much each object grew since then; set save_snapshot to false to keep comparing against the same baseline.",
+		},
+	}
+}
+
+// CreateTool creates a largest objects tool
+func (t *LargestObjectsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Rank the largest tables, indexes, materialized views and TOAST tables with growth"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Number of objects to rank (default: 20)"),
+		),
+		tools.WithBoolean("save_snapshot",
+			tools.Description("Save the current sizes as the baseline for the next run (default: true)"),
+		),
+	)
+}
+
+// HandleRequest handles largest objects tool requests
+func (t *LargestObjectsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	limit := input.intBetween("limit", 20, 1, 1000)
+	save := input.optionalBool("save_snapshot", true)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Ranking largest objects for database %s", targetDbID)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for largest objects: %s", dbType)
+	}
+	query, ok := dialect.LargestObjectsQuery()
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for largest objects: %s", dbType)
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, targetDbID, query, []interface{}{limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object sizes: %w", err)
+	}
+	var objects []storedObject
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		objects = append(objects, storedObject{
+			Schema: valueString(row[0]),
+			Name:   valueString(row[1]),
+			Kind:   valueString(row[2]),
+			Table:  valueString(row[3]),
+			Bytes:  valueInt64(row[4]),
+		})
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Largest Objects in Database %s\n\n", targetDbID))
+
+	// Growth is a bonus; a database without a usable snapshots directory still gets its ranking
+	var previous *sizeSnapshot
+	path := ""
+	if dir, err := snapshotsDir(useCase, targetDbID); err != nil {
+		response.WriteString(fmt.Sprintf("Growth is not tracked: %v.\n\n", err))
+	} else {
+		path = filepath.Join(dir, sizeSnapshotFile)
+		if previous, err = readSizeSnapshot(path); err != nil {
+			logger.Warn("Ignoring size snapshot of database %s: %v", targetDbID, err)
+			previous = nil
+		}
+		if previous != nil {
+			response.WriteString(fmt.Sprintf("Growth since the snapshot of %s (%s ago).\n\n",
+				previous.CreatedAt.Format(time.RFC3339), time.Since(previous.CreatedAt).Round(time.Minute)))
+		} else {
+			response.WriteString("No previous size snapshot; growth is reported from the next run on.\n\n")
+		}
+	}
+	writeLargestObjects(&response, objects, previous)
+
+	if path != "" && save {
+		snapshot := &sizeSnapshot{Database: targetDbID, CreatedAt: time.Now().UTC(), Sizes: make(map[string]int64, len(objects))}
+		for _, o := range objects {
+			snapshot.Sizes[o.key()] = o.Bytes
+		}
+		if err := writeSizeSnapshot(path, snapshot); err != nil {
+			return nil, err
+		}
+		response.WriteString(fmt.Sprintf("\nSaved these sizes to %s as the baseline for the next run.\n", path))
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// writeLargestObjects renders the ranking with the growth of each object since the snapshot
+func writeLargestObjects(sb *strings.Builder, objects []storedObject, previous *sizeSnapshot) {
+	if len(objects) == 0 {
+		sb.WriteString("No objects found.\n")
+		return
+	}
+
+	sb.WriteString("| # | Object | Kind | Table | Size | Bytes | Growth |\n")
+	sb.WriteString("|---|--------|------|-------|------|-------|--------|\n")
+	var total, growth int64
+	for i, o := range objects {
+		change := "-"
+		if previous != nil {
+			if before, ok := previous.Sizes[o.key()]; ok {
+				change = formatSizeChange(o.Bytes - before)
+				growth += o.Bytes - before
+			} else {
+				change = "new"
+			}
+		}
+		sb.WriteString(fmt.Sprintf("| %d | %s | %s | %s | %s | %d | %s |\n",
+			i+1, qualifiedName(o.Schema, o.Name), o.Kind, orDash(o.Table), formatBackupSize(o.Bytes), o.Bytes, change))
+		total += o.Bytes
+	}
+	sb.WriteString(fmt.Sprintf("\nThese %d objects take %s (%d bytes)", len(objects), formatBackupSize(total), total))
+	if previous != nil {
+		sb.WriteString(fmt.Sprintf("; the ones in the snapshot grew by %s", formatSizeChange(growth)))
+	}
+	sb.WriteString(".\n")
+}
+
+// formatSizeChange renders a size difference with its sign
+func formatSizeChange(bytes int64) string {
+	if bytes < 0 {
+		return "-" + formatBackupSize(-bytes)
+	}
+	return "+" + formatBackupSize(bytes)
+}
+
+// readSizeSnapshot decodes a size snapshot; a missing file has none
+func readSizeSnapshot(path string) (*sizeSnapshot, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read size snapshot %s: %w", path, err)
+	}
+	var snapshot sizeSnapshot
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode size snapshot %s: %w", path, err)
+	}
+	return &snapshot, nil
+}
+
+// writeSizeSnapshot replaces the size snapshot
+func writeSizeSnapshot(path string, snapshot *sizeSnapshot) error {
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode size snapshot: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create size snapshot directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write size snapshot: %w", err)
+	}
+	return nil
+}
+
+// getPostgresLargestObjectsQuery returns a query for the $1 largest relations of the database
+// outside the system catalogs, each with the table an index or TOAST table belongs to. Sizes
+// are of the relation alone, so a table does not include its indexes or TOAST table.
+func getPostgresLargestObjectsQuery() string {
+	return `
+SELECT
+    n.nspname AS schema_name,
+    c.relname AS object_name,
+    CASE c.relkind
+        WHEN 'r' THEN 'table'
+        WHEN 'i' THEN 'index'
+        WHEN 'm' THEN 'materialized view'
+        WHEN 't' THEN 'TOAST table'
+    END AS kind,
+    COALESCE(
+        (SELECT o.oid::regclass::text FROM pg_class o WHERE o.reltoastrelid = c.oid),
+        (SELECT i.indrelid::regclass::text FROM pg_index i WHERE i.indexrelid = c.oid),
+        '') AS table_name,
+    pg_relation_size(c.oid) AS bytes
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('r', 'i', 'm', 't')
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+ORDER BY bytes DESC
+LIMIT $1;`
+}
+
+// getMySQLLargestObjectsQuery returns a query for the ? largest table data and secondary index
+// sets of the user schemas. InnoDB stores the data in the clustered primary key, so the table
+// size includes it.
+func getMySQLLargestObjectsQuery() string {
+	return `
+SELECT schema_name, object_name, kind, table_name, bytes
+FROM (
+    SELECT TABLE_SCHEMA AS schema_name, TABLE_NAME AS object_name, 'table' AS kind, '' AS table_name, DATA_LENGTH AS bytes
+    FROM information_schema.TABLES
+    WHERE TABLE_TYPE = 'BASE TABLE'
+      AND TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+    UNION ALL
+    SELECT TABLE_SCHEMA, CONCAT(TABLE_NAME, ' indexes'), 'secondary indexes', TABLE_NAME, INDEX_LENGTH
+    FROM information_schema.TABLES
+    WHERE TABLE_TYPE = 'BASE TABLE'
+      AND TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+      AND INDEX_LENGTH > 0
+) objects
+ORDER BY bytes DESC
+LIMIT ?;`
+}
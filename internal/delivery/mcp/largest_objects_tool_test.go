@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestLargestObjectsTool(t *testing.T) {
+	dir := t.TempDir()
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		config: domain.DatabaseConnectionConfig{SnapshotsDir: dir},
+		results: map[string]*domain.QueryResult{
+			"pg_relation_size": {Rows: [][]interface{}{
+				{"public", "orders", "table", "", int64(8 << 20)},
+				{"pg_toast", "pg_toast_16384", "TOAST table", "orders", int64(4 << 20)},
+				{"public", "orders_pkey", "index", "orders", int64(2 << 20)},
+			}},
+		},
+	}
+	request := server.ToolCallRequest{Parameters: map[string]interface{}{"database": "pg1"}}
+
+	result, err := NewLargestObjectsTool().HandleRequest(context.Background(), request, "pg1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "No previous size snapshot")
+	assert.Contains(t, text, "| 2 | pg_toast.pg_toast_16384 | TOAST table | orders | 4.0 MiB | 4194304 | - |")
+	assert.Contains(t, text, "These 3 objects take 14.0 MiB (14680064 bytes).")
+	assert.FileExists(t, filepath.Join(dir, "sizes", "largest_objects.json"))
+
+	// Shrink the snapshot so the next run sees growth
+	snapshot, err := readSizeSnapshot(filepath.Join(dir, sizeSnapshotFile))
+	assert.NoError(t, err)
+	snapshot.CreatedAt = time.Now().Add(-24 * time.Hour)
+	snapshot.Sizes["table:public.orders"] = 6 << 20
+	delete(snapshot.Sizes, "index:public.orders_pkey")
+	assert.NoError(t, writeSizeSnapshot(filepath.Join(dir, sizeSnapshotFile), snapshot))
+
+	request.Parameters["save_snapshot"] = false
+	result, err = NewLargestObjectsTool().HandleRequest(context.Background(), request, "pg1", useCase)
+	assert.NoError(t, err)
+	text = result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "(24h0m0s ago)")
+	assert.Contains(t, text, "| 1 | public.orders | table | - | 8.0 MiB | 8388608 | +2.0 MiB |")
+	assert.Contains(t, text, "| 3 | public.orders_pkey | index | orders | 2.0 MiB | 2097152 | new |")
+	assert.Contains(t, text, "the ones in the snapshot grew by +2.0 MiB")
+	assert.NotContains(t, text, "Saved these sizes")
+
+	content, err := os.ReadFile(filepath.Join(dir, sizeSnapshotFile))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "orders_pkey")
+}
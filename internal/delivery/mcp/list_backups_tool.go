@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+)
+
+// ListBackupsTool handles listing recorded backups
+type ListBackupsTool struct {
+	BaseToolType
+}
+
+// NewListBackupsTool creates a new backup listing tool type
+func NewListBackupsTool() *ListBackupsTool {
+	return &ListBackupsTool{
+		BaseToolType: BaseToolType{
+			name:        "list_backups",
+			description: "List the backups taken with the backup tool, newest first, from the metadata recorded in the backups directory: ID, start time, method and format, tables, size, duration and whether the backup completed or failed.",
+		},
+	}
+}
+
+// CreateTool creates a backup listing tool
+func (t *ListBackupsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("List recorded backups with their size, tables and status"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of backups to list (default: 20)"),
+		),
+		tools.WithString("status",
+			tools.Description("Only list backups with this status: completed or failed"),
+		),
+	)
+}
+
+// HandleRequest handles backup listing tool requests
+func (t *ListBackupsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	limit := input.intAtLeast("limit", 20, 1)
+	status := input.choice("status", "", "completed", "failed")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	dir, err := backupsDir(useCase, targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	backups, err := listBackups(dir)
+	if err != nil {
+		return nil, err
+	}
+	if status != "" {
+		filtered := backups[:0]
+		for _, b := range backups {
+			if b.Status == status {
+				filtered = append(filtered, b)
+			}
+		}
+		backups = filtered
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Backups for Database %s\n\n", targetDbID))
+	response.WriteString(fmt.Sprintf("Directory: %s\n\n", dir))
+	if len(backups) == 0 {
+		response.WriteString("No backups found.\n")
+		return createTextResponse(response.String()), nil
+	}
+
+	total := len(backups)
+	if len(backups) > limit {
+		backups = backups[:limit]
+	}
+	response.WriteString("| ID | Started | Method | Tables | Size | Duration | Status |\n")
+	response.WriteString("|----|---------|--------|--------|------|----------|--------|\n")
+	for _, b := range backups {
+		tables := "all"
+		if len(b.Tables) > 0 {
+			tables = strings.Join(b.Tables, ", ")
+		}
+		status := b.Status
+		if b.Error != "" {
+			status += ": " + strings.Join(strings.Fields(b.Error), " ")
+		}
+		response.WriteString(fmt.Sprintf("| %s | %s | %s (%s, %s) | %s | %s | %s | %s |\n",
+			b.ID, b.StartedAt.Format("2006-01-02 15:04:05"), b.Method, b.Format, b.Compression, tables,
+			formatBackupSize(b.totalBytes()), b.FinishedAt.Sub(b.StartedAt).Round(time.Millisecond), status))
+	}
+	if total > len(backups) {
+		response.WriteString(fmt.Sprintf("\nShowing %d of %d backups.\n", len(backups), total))
+	}
+
+	return createTextResponse(response.String()), nil
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// ListCollectionsTool handles listing the collections of a document database
+type ListCollectionsTool struct {
+	BaseToolType
+}
+
+// NewListCollectionsTool creates a new list collections tool type
+func NewListCollectionsTool() *ListCollectionsTool {
+	return &ListCollectionsTool{
+		BaseToolType: BaseToolType{
+			name:        "list_collections",
+			description: "List the collections and views of a MongoDB database with their type, whether they are read-only, and the options they were created with, such as a capped size, a validator, a time-series layout or the pipeline of a view.",
+		},
+	}
+}
+
+// CreateTool creates a list collections tool
+func (t *ListCollectionsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("List the collections and views of a MongoDB database"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+	)
+}
+
+// HandleRequest handles list collections tool requests
+func (t *ListCollectionsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	store, err := useCase.GetDocumentStore(targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	collections, err := store.ListCollections(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	logger.Info("Listed %d collections of database %s", len(collections), targetDbID)
+
+	sort.Slice(collections, func(i, j int) bool {
+		return fmt.Sprint(collections[i]["name"]) < fmt.Sprint(collections[j]["name"])
+	})
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Collections in Database %s\n\n", targetDbID))
+	if len(collections) == 0 {
+		response.WriteString("The database has no collections.\n")
+		return createTextResponse(response.String()), nil
+	}
+	response.WriteString("| Name | Type | Read Only | Options |\n")
+	response.WriteString("|------|------|-----------|---------|\n")
+	for _, collection := range collections {
+		readOnly := false
+		if info, ok := collection["info"].(map[string]interface{}); ok {
+			readOnly, _ = info["readOnly"].(bool)
+		}
+		options := ""
+		if opts, ok := collection["options"].(map[string]interface{}); ok && len(opts) > 0 {
+			options = compactJSON(opts)
+		}
+		response.WriteString(fmt.Sprintf("| %v | %v | %v | %s |\n", collection["name"], collection["type"], readOnly, options))
+	}
+	response.WriteString(fmt.Sprintf("\n%d collections\n", len(collections)))
+	return createTextResponse(response.String()), nil
+}
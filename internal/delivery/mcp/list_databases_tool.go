@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
 )
 
 // ListDatabasesTool implements the list_databases tool
@@ -22,21 +23,16 @@ func NewListDatabasesTool() *ListDatabasesTool {
 	}
 }
 
-// CreateTool creates a new tool instance
-func (t *ListDatabasesTool) CreateTool(name string, dbID string) server.Tool {
-	return server.Tool{
-		Name:        name,
-		Description: t.description,
-		InputSchema: server.ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"random_string": map[string]interface{}{
-					"type":        "string",
-					"description": "Dummy parameter (optional)",
-				},
-			},
-		},
-	}
+// CreateTool creates a list databases tool
+func (t *ListDatabasesTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription(t.description),
+		// Use any string parameter for compatibility
+		tools.WithString("random_string",
+			tools.Description("Dummy parameter (optional)"),
+		),
+	)
 }
 
 // HandleRequest handles list databases tool requests
@@ -50,7 +46,7 @@ func (t *ListDatabasesTool) HandleRequest(ctx context.Context, request server.To
 
 	for i, dbID := range databases {
 		// Get database info to extract host, port, etc.
-		dbInfo, err := useCase.GetDatabaseInfo(dbID)
+		dbInfo, err := useCase.GetDatabaseInfo(ctx, dbID)
 		if err != nil {
 			// If we can't get detailed info, just show the database ID
 			output += fmt.Sprintf("| %d | %s | Unknown | Unknown | Unknown | Unknown | Unknown |\n", i+1, dbID)
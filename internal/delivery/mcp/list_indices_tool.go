@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// ListIndicesTool handles listing the indices of a search database
+type ListIndicesTool struct {
+	BaseToolType
+}
+
+// NewListIndicesTool creates a new list indices tool type
+func NewListIndicesTool() *ListIndicesTool {
+	return &ListIndicesTool{
+		BaseToolType: BaseToolType{
+			name:        "list_indices",
+			description: "List the indices of an Elasticsearch or OpenSearch cluster that match a pattern, such as logs-*, with their health, status, primary and replica shards, document counts and sizes. Hidden indices, such as the backing indices of data streams, are listed when include_hidden is set.",
+		},
+	}
+}
+
+// CreateTool creates a list indices tool
+func (t *ListIndicesTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("List the indices of an Elasticsearch or OpenSearch cluster"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("index",
+			tools.Description("Index pattern, such as logs-* or orders,customers (default: *)"),
+		),
+		tools.WithBoolean("include_hidden",
+			tools.Description("Also list hidden indices (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles list indices tool requests
+func (t *ListIndicesTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	pattern := input.optionalString("index", "*")
+	includeHidden := input.optionalBool("include_hidden", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	store, err := useCase.GetSearchStore(targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	indices, err := store.ListIndices(ctx, pattern, includeHidden)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indices: %w", err)
+	}
+	logger.Info("Listed %d indices matching %s in database %s", len(indices), pattern, targetDbID)
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Indices Matching %s in Database %s\n\n", pattern, targetDbID))
+	if len(indices) == 0 {
+		response.WriteString("No indices match the pattern.\n")
+		return createTextResponse(response.String()), nil
+	}
+	response.WriteString("| Index | Health | Status | Primaries | Replicas | Documents | Deleted | Primary Size | Total Size |\n")
+	response.WriteString("|-------|--------|--------|-----------|----------|-----------|---------|--------------|------------|\n")
+	for _, index := range indices {
+		response.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			searchText(index["index"]), searchText(index["health"]), searchText(index["status"]),
+			searchCount(index["pri"]), searchCount(index["rep"]),
+			searchCount(index["docs.count"]), searchCount(index["docs.deleted"]),
+			searchSize(index["pri.store.size"]), searchSize(index["store.size"])))
+	}
+	response.WriteString(fmt.Sprintf("\n%d indices\n", len(indices)))
+	return createTextResponse(response.String()), nil
+}
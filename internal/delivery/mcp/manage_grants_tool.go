@@ -0,0 +1,287 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// grantTemplate lists the privileges a role-based template grants on tables and sequences
+type grantTemplate struct {
+	Tables    []string
+	Sequences []string
+	Schema    []string
+	MySQL     []string
+}
+
+// grantTemplates are the role-based privilege sets manage_grants applies
+var grantTemplates = map[string]grantTemplate{
+	"read_only": {
+		Tables:    []string{"SELECT"},
+		Sequences: []string{"SELECT"},
+		Schema:    []string{"USAGE"},
+		MySQL:     []string{"SELECT", "SHOW VIEW"},
+	},
+	"read_write": {
+		Tables:    []string{"SELECT", "INSERT", "UPDATE", "DELETE"},
+		Sequences: []string{"USAGE", "SELECT", "UPDATE"},
+		Schema:    []string{"USAGE"},
+		MySQL:     []string{"SELECT", "INSERT", "UPDATE", "DELETE", "SHOW VIEW"},
+	},
+	"all": {
+		Tables:    []string{"ALL PRIVILEGES"},
+		Sequences: []string{"ALL PRIVILEGES"},
+		Schema:    []string{"USAGE", "CREATE"},
+		MySQL:     []string{"ALL PRIVILEGES"},
+	},
+}
+
+// grantRequest describes the privileges to grant or revoke
+type grantRequest struct {
+	Revoke   bool
+	Template string
+	Grantee  string
+	Host     string
+	Schema   string
+	Tables   []string
+
+	// Future also covers tables created later in the schema (PostgreSQL default privileges)
+	Future bool
+}
+
+// ManageGrantsTool handles applying grant templates
+type ManageGrantsTool struct {
+	BaseToolType
+}
+
+// NewManageGrantsTool creates a new grant management tool type
+func NewManageGrantsTool() *ManageGrantsTool {
+	return &ManageGrantsTool{
+		BaseToolType: BaseToolType{
+			name:        "manage_grants",
+			description: "Grant or revoke role-based privilege templates: read_only, read_write or all, on a whole schema (a database on MySQL) or on specific tables. On PostgreSQL a schema grant also covers sequences and, through default privileges, tables created later. Use dry_run to only generate the GRANT/REVOKE statements for review; executing them requires allow_admin on the connection.",
+		},
+	}
+}
+
+// CreateTool creates a grant management tool
+func (t *ManageGrantsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Apply or generate GRANT/REVOKE statements from role-based templates"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("grantee",
+			tools.Description("User or role receiving the privileges"),
+			tools.Required(),
+		),
+		tools.WithString("template",
+			tools.Description("read_only, read_write or all"),
+			tools.Required(),
+		),
+		tools.WithString("action",
+			tools.Description("grant or revoke (default: grant)"),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to cover (a database on MySQL)"),
+		),
+		tools.WithArray("tables",
+			tools.Description("Tables to cover instead of a whole schema"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithString("host",
+			tools.Description("Host part of the MySQL account (default: %)"),
+		),
+		tools.WithBoolean("include_future",
+			tools.Description("Also cover tables created later in the schema on PostgreSQL (default: true)"),
+		),
+		tools.WithBoolean("dry_run",
+			tools.Description("Only generate the statements without executing them (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles grant management tool requests
+func (t *ManageGrantsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	grant := grantRequest{
+		Grantee:  input.optionalString("grantee", ""),
+		Template: input.requiredChoice("template", "read_only", "read_write", "all"),
+		Revoke:   input.choice("action", "grant", "grant", "revoke") == "revoke",
+		Schema:   input.optionalString("schema", ""),
+		Tables:   input.stringList("tables"),
+		Host:     input.optionalString("host", "%"),
+		Future:   input.optionalBool("include_future", true),
+	}
+	dryRun := input.optionalBool("dry_run", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if !userNamePattern.MatchString(grant.Grantee) {
+		return nil, fmt.Errorf("grantee parameter must be a name of letters, digits, _, $ or -")
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for manage_grants: %s", dbType)
+	}
+	planner, err := dialect.GrantPlanner()
+	if err != nil {
+		return nil, unsupportedAdminError("manage_grants", dbType, err)
+	}
+
+	statements, notes, err := grantStatements(planner, grant)
+	if err != nil {
+		return nil, err
+	}
+
+	verb := "Grant"
+	if grant.Revoke {
+		verb = "Revoke"
+	}
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# %s %s for Database %s\n\n", verb, grant.Template, targetDbID))
+	response.WriteString("```sql\n")
+	for _, statement := range statements {
+		response.WriteString(statement + ";\n")
+	}
+	response.WriteString("```\n")
+	if len(notes) > 0 {
+		response.WriteString("\n")
+		for _, note := range notes {
+			response.WriteString(fmt.Sprintf("Note: %s\n", note))
+		}
+	}
+
+	if dryRun {
+		response.WriteString("\nDry run: nothing was executed.\n")
+		return createTextResponse(response.String()), nil
+	}
+	if err := requireAdmin(useCase, targetDbID, "manage_grants"); err != nil {
+		return nil, fmt.Errorf("%w (use dry_run to only generate the statements)", err)
+	}
+	err = useCase.ExecuteBatch(ctx, targetDbID, statements, nil)
+	audit(request, auditRecord{
+		Tool:      "manage_grants",
+		Database:  targetDbID,
+		Action:    strings.ToLower(verb),
+		Target:    fmt.Sprintf("%s for %s", grant.Template, grant.Grantee),
+		Statement: strings.Join(statements, ";\n"),
+	}, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply grants: %w", err)
+	}
+	logger.Info("manage_grants: %s %s for %s on database %s", strings.ToLower(verb), grant.Template, grant.Grantee, targetDbID)
+	response.WriteString(fmt.Sprintf("\nExecuted %d statement(s).\n", len(statements)))
+
+	return createTextResponse(response.String()), nil
+}
+
+// grantPlanner expands a template into the GRANT or REVOKE statements of the engine, with notes
+type grantPlanner func(grant grantRequest, template grantTemplate) ([]string, []string, error)
+
+// grantStatements checks what a grant request covers and expands its template with the planner
+func grantStatements(planner grantPlanner, grant grantRequest) ([]string, []string, error) {
+	if grant.Schema != "" && len(grant.Tables) > 0 {
+		return nil, nil, fmt.Errorf("schema and tables cannot be combined")
+	}
+	if grant.Schema == "" && len(grant.Tables) == 0 {
+		return nil, nil, fmt.Errorf("schema or tables is required")
+	}
+	return planner(grant, grantTemplates[grant.Template])
+}
+
+// postgresGrantStatements grants on a whole schema with ALL TABLES IN SCHEMA and, for future
+// tables, default privileges, or on single tables with USAGE on their schemas
+func postgresGrantStatements(grant grantRequest, template grantTemplate) ([]string, []string, error) {
+	var statements, notes []string
+	grantee := quoteIdentifier("postgres", grant.Grantee)
+	apply := func(privileges []string, object string) string {
+		if grant.Revoke {
+			return fmt.Sprintf("REVOKE %s ON %s FROM %s", strings.Join(privileges, ", "), object, grantee)
+		}
+		return fmt.Sprintf("GRANT %s ON %s TO %s", strings.Join(privileges, ", "), object, grantee)
+	}
+
+	if grant.Schema != "" {
+		schema := quoteIdentifier("postgres", grant.Schema)
+		tables := apply(template.Tables, "ALL TABLES IN SCHEMA "+schema)
+		sequences := apply(template.Sequences, "ALL SEQUENCES IN SCHEMA "+schema)
+		usage := apply(template.Schema, "SCHEMA "+schema)
+		if grant.Revoke {
+			// Revoke object privileges before the schema ones they depend on
+			statements = append(statements, tables, sequences)
+		} else {
+			statements = append(statements, usage, tables, sequences)
+		}
+		if grant.Future {
+			statements = append(statements,
+				"ALTER DEFAULT PRIVILEGES IN SCHEMA "+schema+" "+apply(template.Tables, "TABLES"),
+				"ALTER DEFAULT PRIVILEGES IN SCHEMA "+schema+" "+apply(template.Sequences, "SEQUENCES"))
+			notes = append(notes, "Default privileges only cover objects later created by the role running these statements; run them as each role that creates tables in the schema.")
+		}
+		if grant.Revoke {
+			statements = append(statements, usage)
+		}
+		return statements, notes, nil
+	}
+
+	var schemas, quoted []string
+	for _, table := range grant.Tables {
+		schema, _ := splitQualifiedName(table)
+		if schema == "" {
+			schema = "public"
+		}
+		if !containsFold(schemas, schema) {
+			schemas = append(schemas, schema)
+		}
+		quoted = append(quoted, quoteIdentifier("postgres", table))
+	}
+	if !grant.Revoke {
+		// USAGE on the schema is needed to reach the tables; it is left alone on revoke
+		// because other grants may rely on it
+		for _, schema := range schemas {
+			statements = append(statements, apply([]string{"USAGE"}, "SCHEMA "+quoteIdentifier("postgres", schema)))
+		}
+		if grant.Template != "read_only" {
+			notes = append(notes, "Inserting into serial or identity columns also needs USAGE on their sequences; grant it on the schema or on the sequences directly.")
+		}
+	}
+	statements = append(statements, apply(template.Tables, "TABLE "+strings.Join(quoted, ", ")))
+	return statements, notes, nil
+}
+
+// mysqlGrantStatements grants to a 'user'@'host' account on schema.* or on single tables
+func mysqlGrantStatements(grant grantRequest, template grantTemplate) ([]string, []string, error) {
+	if !userHostPattern.MatchString(grant.Host) {
+		return nil, nil, fmt.Errorf("invalid host: %s", grant.Host)
+	}
+	var statements, notes []string
+	account := quoteLiteral("mysql", grant.Grantee) + "@" + quoteLiteral("mysql", grant.Host)
+	apply := func(object string) string {
+		if grant.Revoke {
+			return fmt.Sprintf("REVOKE %s ON %s FROM %s", strings.Join(template.MySQL, ", "), object, account)
+		}
+		return fmt.Sprintf("GRANT %s ON %s TO %s", strings.Join(template.MySQL, ", "), object, account)
+	}
+	if grant.Schema != "" {
+		statements = append(statements, apply(quoteIdentifier("mysql", grant.Schema)+".*"))
+		return statements, notes, nil
+	}
+	for _, table := range grant.Tables {
+		statements = append(statements, apply(quoteIdentifier("mysql", table)))
+	}
+	notes = append(notes, "Unqualified tables resolve against the connection's default database.")
+	return statements, notes, nil
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestGrantStatementsPostgres(t *testing.T) {
+	statements, notes, err := grantStatements(postgresGrantStatements, grantRequest{Template: "read_only", Grantee: "analyst", Schema: "reporting", Future: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`GRANT USAGE ON SCHEMA "reporting" TO "analyst"`,
+		`GRANT SELECT ON ALL TABLES IN SCHEMA "reporting" TO "analyst"`,
+		`GRANT SELECT ON ALL SEQUENCES IN SCHEMA "reporting" TO "analyst"`,
+		`ALTER DEFAULT PRIVILEGES IN SCHEMA "reporting" GRANT SELECT ON TABLES TO "analyst"`,
+		`ALTER DEFAULT PRIVILEGES IN SCHEMA "reporting" GRANT SELECT ON SEQUENCES TO "analyst"`,
+	}, statements)
+	assert.Len(t, notes, 1)
+
+	statements, _, err = grantStatements(postgresGrantStatements, grantRequest{Revoke: true, Template: "read_only", Grantee: "analyst", Schema: "reporting"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`REVOKE SELECT ON ALL TABLES IN SCHEMA "reporting" FROM "analyst"`,
+		`REVOKE SELECT ON ALL SEQUENCES IN SCHEMA "reporting" FROM "analyst"`,
+		`REVOKE USAGE ON SCHEMA "reporting" FROM "analyst"`,
+	}, statements)
+
+	statements, _, err = grantStatements(postgresGrantStatements, grantRequest{Template: "read_write", Grantee: "app", Tables: []string{"orders", "sales.items"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`GRANT USAGE ON SCHEMA "public" TO "app"`,
+		`GRANT USAGE ON SCHEMA "sales" TO "app"`,
+		`GRANT SELECT, INSERT, UPDATE, DELETE ON TABLE "orders", "sales"."items" TO "app"`,
+	}, statements)
+
+	_, _, err = grantStatements(postgresGrantStatements, grantRequest{Template: "all", Grantee: "app"})
+	assert.ErrorContains(t, err, "schema or tables is required")
+}
+
+func TestGrantStatementsMySQL(t *testing.T) {
+	statements, _, err := grantStatements(mysqlGrantStatements, grantRequest{Template: "read_write", Grantee: "app", Host: "10.%", Schema: "shop"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GRANT SELECT, INSERT, UPDATE, DELETE, SHOW VIEW ON `shop`.* TO 'app'@'10.%'"}, statements)
+
+	statements, _, err = grantStatements(mysqlGrantStatements, grantRequest{Revoke: true, Template: "all", Grantee: "app", Host: "%", Tables: []string{"shop.orders"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"REVOKE ALL PRIVILEGES ON `shop`.`orders` FROM 'app'@'%'"}, statements)
+}
+
+func TestManageGrantsTool(t *testing.T) {
+	useCase := &mockUseCase{dbType: "postgres"}
+	request := server.ToolCallRequest{Parameters: map[string]interface{}{
+		"database": "pg1", "grantee": "analyst", "template": "read_only", "schema": "public", "dry_run": true,
+	}}
+
+	result, err := NewManageGrantsTool().HandleRequest(context.Background(), request, "pg1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "# Grant read_only for Database pg1")
+	assert.Contains(t, text, `GRANT SELECT ON ALL TABLES IN SCHEMA "public" TO "analyst";`)
+	assert.Contains(t, text, "Dry run: nothing was executed.")
+	assert.Empty(t, useCase.batches)
+
+	// Executing needs allow_admin
+	request.Parameters["dry_run"] = false
+	_, err = NewManageGrantsTool().HandleRequest(context.Background(), request, "pg1", useCase)
+	assert.ErrorContains(t, err, "set allow_admin")
+	assert.Empty(t, useCase.batches)
+
+	useCase.config = domain.DatabaseConnectionConfig{AllowAdmin: true}
+	_, err = NewManageGrantsTool().HandleRequest(context.Background(), request, "pg1", useCase)
+	assert.NoError(t, err)
+	assert.Len(t, useCase.batches, 1)
+	assert.Len(t, useCase.batches[0], 5)
+
+	request.Parameters["template"] = "superuser"
+	_, err = NewManageGrantsTool().HandleRequest(context.Background(), request, "pg1", useCase)
+	assert.EqualError(t, err, "template parameter must be read_only, read_write or all")
+}
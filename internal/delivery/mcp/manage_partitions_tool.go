@@ -0,0 +1,251 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// ManagePartitionsTool handles the lifecycle of time-range partitions
+type ManagePartitionsTool struct {
+	BaseToolType
+}
+
+// NewManagePartitionsTool creates a new partition management tool type
+func NewManagePartitionsTool() *ManagePartitionsTool {
+	return &ManagePartitionsTool{
+		BaseToolType: BaseToolType{
+			name:        "manage_partitions",
+			description: "Manage the partitions of a time-range partitioned table. Actions: list shows the partitions and their ranges; create adds the partitions needed to cover the current period and the next `ahead` periods; attach and detach move an existing table in or out of the partitioned table (PostgreSQL only); drop_expired drops partitions whose whole range is older than retention_days. On MySQL, RANGE COLUMNS and RANGE on TO_DAYS(), UNIX_TIMESTAMP() or YEAR() are supported, and new ranges are split off a MAXVALUE partition when there is one. The DDL is only generated unless execute is set.",
+		},
+	}
+}
+
+// CreateTool creates a partition management tool
+func (t *ManagePartitionsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Create, attach, detach and expire time-range partitions"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Partitioned table"),
+			tools.Required(),
+		),
+		tools.WithString("action",
+			tools.Description("list, create, attach, detach or drop_expired"),
+			tools.Required(),
+		),
+		tools.WithString("interval",
+			tools.Description("Range of each new partition for create: day, week, month or year (default: month)"),
+		),
+		tools.WithNumber("ahead",
+			tools.Description("Number of future periods to create partitions for beyond the current one (default: 3)"),
+		),
+		tools.WithString("prefix",
+			tools.Description("Name prefix of new partitions (default: <table>_p on PostgreSQL, p on MySQL)"),
+		),
+		tools.WithString("partition",
+			tools.Description("Partition table to attach or detach"),
+		),
+		tools.WithString("from",
+			tools.Description("Inclusive lower bound for attach, e.g. 2024-01-01"),
+		),
+		tools.WithString("to",
+			tools.Description("Exclusive upper bound for attach, e.g. 2024-02-01"),
+		),
+		tools.WithBoolean("concurrently",
+			tools.Description("Detach without blocking queries on the parent table (PostgreSQL 14+, default: false)"),
+		),
+		tools.WithNumber("retention_days",
+			tools.Description("Partitions whose range ended more than this many days ago are dropped by drop_expired"),
+		),
+		tools.WithBoolean("execute",
+			tools.Description("Run the generated DDL instead of only showing it (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles partition management tool requests
+func (t *ManagePartitionsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	table := input.requiredString("table")
+	action := input.requiredChoice("action", "list", "create", "attach", "detach", "drop_expired")
+	interval := input.choice("interval", "month", partitionIntervals...)
+	ahead := input.intAtLeast("ahead", 3, 0)
+	prefix := input.optionalString("prefix", "")
+	partition := input.optionalString("partition", "")
+	concurrently := input.optionalBool("concurrently", false)
+	retentionDays := input.intAtLeast("retention_days", -1, 0)
+	execute := input.optionalBool("execute", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for partition management: %s", dbType)
+	}
+	manager, err := dialect.PartitionManager()
+	if err != nil {
+		return nil, unsupportedAdminError("partition management", dbType, err)
+	}
+
+	pt, err := manager.load(ctx, useCase, targetDbID, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Partitions of %s for Database %s\n\n", table, targetDbID))
+	response.WriteString(fmt.Sprintf("Partition key: %s\n\n", pt.Key))
+
+	var statements, notes []string
+	switch action {
+	case "list":
+		writePartitionList(&response, pt)
+		return createTextResponse(response.String()), nil
+	case "create":
+		if prefix == "" {
+			prefix = manager.defaultPrefix(table)
+		}
+		planned := planFuturePartitions(pt, interval, prefix, ahead, partitionNow().UTC())
+		if len(planned) == 0 {
+			notes = append(notes, fmt.Sprintf("Partitions already cover the current %s and the next %d.", interval, ahead))
+			break
+		}
+		statements, err = manager.createStatements(pt, planned)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, manager.createNotes(pt, planned)...)
+	case "attach":
+		if partition == "" {
+			return nil, fmt.Errorf("partition parameter is required for attach")
+		}
+		from, fromOK := valueTime(input.raw("from"))
+		to, toOK := valueTime(input.raw("to"))
+		if !fromOK || !toOK || !to.After(from) {
+			return nil, fmt.Errorf("from and to must be dates with from before to")
+		}
+		statement, err := manager.attachStatement(table, partition, from, to)
+		if err != nil {
+			return nil, err
+		}
+		statements = []string{statement}
+		notes = append(notes, "Attaching scans the whole partition to check its rows fit the range, unless it has a matching CHECK constraint; the parent stays readable and writable meanwhile.")
+	case "detach":
+		if partition == "" {
+			return nil, fmt.Errorf("partition parameter is required for detach")
+		}
+		_, name := splitQualifiedName(partition)
+		if pt.partition(name) == nil {
+			return nil, fmt.Errorf("%s is not a partition of %s", partition, table)
+		}
+		statement, err := manager.detachStatement(table, partition, concurrently)
+		if err != nil {
+			return nil, err
+		}
+		statements = []string{statement}
+	case "drop_expired":
+		if retentionDays < 0 {
+			return nil, fmt.Errorf("retention_days parameter is required for drop_expired")
+		}
+		cutoff := partitionNow().UTC().AddDate(0, 0, -retentionDays)
+		expired := expiredPartitions(pt, cutoff)
+		if len(expired) == 0 {
+			notes = append(notes, fmt.Sprintf("No partition ends before %s.", cutoff.Format("2006-01-02")))
+		} else {
+			statements = manager.dropStatements(pt, expired)
+		}
+		for _, p := range expired {
+			notes = append(notes, fmt.Sprintf("- %s (%s, ~%d rows)", p.Name, formatPartitionRange(p), p.Rows))
+		}
+	default:
+		return nil, fmt.Errorf("invalid action: %s (expected list, create, attach, detach or drop_expired)", action)
+	}
+
+	response.WriteString("## DDL\n\n")
+	if len(statements) == 0 {
+		response.WriteString("Nothing to do.\n")
+	} else {
+		response.WriteString(fmt.Sprintf("```sql\n%s;\n```\n", strings.Join(statements, ";\n\n")))
+	}
+	if len(notes) > 0 {
+		response.WriteString("\n" + strings.Join(notes, "\n") + "\n")
+	}
+	if len(statements) == 0 {
+		return createTextResponse(response.String()), nil
+	}
+	if !execute {
+		response.WriteString("\nThe DDL was not executed; set execute to run it.\n")
+		return createTextResponse(response.String()), nil
+	}
+
+	logger.Info("Running %s partition DDL on %s in database %s", action, table, targetDbID)
+	for i, statement := range statements {
+		if _, err := useCase.ExecuteStatement(ctx, targetDbID, statement, nil); err != nil {
+			return nil, fmt.Errorf("failed to run statement %d of %d: %w", i+1, len(statements), err)
+		}
+	}
+	response.WriteString(fmt.Sprintf("\nExecuted %d statement(s).\n", len(statements)))
+
+	return createTextResponse(response.String()), nil
+}
+
+// writePartitionList renders the partitions of a table
+func writePartitionList(sb *strings.Builder, pt *partitionedTable) {
+	if len(pt.Partitions) == 0 {
+		sb.WriteString("The table has no partitions.\n")
+		return
+	}
+	sb.WriteString("| Partition | From | To | Rows (estimate) |\n")
+	sb.WriteString("|-----------|------|----|-----------------|\n")
+	for _, p := range pt.Partitions {
+		from, to := formatPartitionBound(p.From), formatPartitionBound(p.To)
+		switch {
+		case p.Default && strings.EqualFold(p.Bound, "MAXVALUE"):
+			// A MAXVALUE partition takes every key above the last range
+			to = "MAXVALUE"
+		case p.Default, !p.HasRange:
+			// A DEFAULT partition takes every key no other partition does
+			from, to = p.Bound, ""
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %d |\n", p.Name, from, to, p.Rows))
+	}
+	if latest, ok := pt.latestBound(); ok {
+		sb.WriteString(fmt.Sprintf("\nPartitions cover data up to %s.\n", formatPartitionBound(latest)))
+	}
+}
+
+// formatPartitionRange renders the range of a partition
+func formatPartitionRange(p partitionInfo) string {
+	if p.From.IsZero() {
+		return "before " + formatPartitionBound(p.To)
+	}
+	return formatPartitionBound(p.From) + " to " + formatPartitionBound(p.To)
+}
+
+// formatPartitionBound renders a partition bound, leaving out midnight times
+func formatPartitionBound(t time.Time) string {
+	switch {
+	case t.IsZero():
+		return ""
+	case t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0:
+		return t.Format("2006-01-02")
+	default:
+		return t.Format("2006-01-02 15:04:05")
+	}
+}
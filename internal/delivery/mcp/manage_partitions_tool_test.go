@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func fixPartitionNow(t *testing.T, now time.Time) {
+	previous := partitionNow
+	partitionNow = func() time.Time { return now }
+	t.Cleanup(func() { partitionNow = previous })
+}
+
+func testPostgresPartitions() *mockUseCase {
+	return &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"pg_get_partkeydef": {Rows: [][]interface{}{{"RANGE (created_at)"}}},
+			"pg_inherits": {Rows: [][]interface{}{
+				{"events_p202401", "FOR VALUES FROM ('2024-01-01 00:00:00+00') TO ('2024-02-01 00:00:00+00')", int64(1000)},
+				{"events_p202402", "FOR VALUES FROM ('2024-02-01 00:00:00+00') TO ('2024-03-01 00:00:00+00')", int64(2000)},
+				{"events_default", "DEFAULT", int64(0)},
+			}},
+		},
+	}
+}
+
+func TestPartitionPeriods(t *testing.T) {
+	day := time.Date(2024, 5, 15, 13, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC), truncateToPeriod(day, "week"))
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), truncateToPeriod(day, "month"))
+	assert.Equal(t, "p20240513", partitionName("p", truncateToPeriod(day, "week"), "week"))
+
+	bound, ok := parseMySQLPartitionBound("to_days", "739282")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), bound)
+	bound, ok = parseMySQLPartitionBound("columns", "'2024-02-01'")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), bound)
+
+	_, err := mysqlBoundLiteral("year", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	assert.Error(t, err)
+}
+
+func TestManagePartitionsToolCreate(t *testing.T) {
+	fixPartitionNow(t, time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC))
+	useCase := testPostgresPartitions()
+
+	result, err := NewManagePartitionsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "table": "events", "action": "create", "ahead": float64(1), "execute": true},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "Partition key: RANGE (created_at)")
+	assert.Contains(t, text, `CREATE TABLE IF NOT EXISTS "events_p202403" PARTITION OF "events" FOR VALUES FROM ('2024-03-01') TO ('2024-04-01');`)
+	assert.Contains(t, text, `CREATE TABLE IF NOT EXISTS "events_p202404" PARTITION OF "events" FOR VALUES FROM ('2024-04-01') TO ('2024-05-01');`)
+	assert.NotContains(t, text, "events_p202405")
+	assert.Contains(t, text, "default partition events_default")
+	assert.Contains(t, text, "Executed 2 statement(s).")
+	assert.Contains(t, useCase.queries[len(useCase.queries)-1], `"events_p202404"`)
+
+	result, err = NewManagePartitionsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "table": "events", "action": "list"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	text = result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| events_p202402 | 2024-02-01 | 2024-03-01 | 2000 |")
+	assert.Contains(t, text, "| events_default | DEFAULT |  | 0 |")
+	assert.Contains(t, text, "Partitions cover data up to 2024-03-01.")
+}
+
+func TestManagePartitionsToolMySQL(t *testing.T) {
+	fixPartitionNow(t, time.Date(2024, 2, 20, 0, 0, 0, 0, time.UTC))
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"information_schema.PARTITIONS": {Rows: [][]interface{}{
+				{"p202401", "RANGE", "to_days(`created_at`)", "739282", int64(10)},
+				{"p202402", "RANGE", "to_days(`created_at`)", "739311", int64(20)},
+				{"pmax", "RANGE", "to_days(`created_at`)", "MAXVALUE", int64(0)},
+			}},
+		},
+	}
+
+	result, err := NewManagePartitionsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "table": "events", "action": "create", "ahead": float64(1)},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "ALTER TABLE `events` REORGANIZE PARTITION `pmax` INTO (\n"+
+		"  PARTITION `p202403` VALUES LESS THAN (TO_DAYS('2024-04-01')),\n"+
+		"  PARTITION `pmax` VALUES LESS THAN MAXVALUE\n);")
+	assert.Contains(t, text, "The DDL was not executed")
+
+	result, err = NewManagePartitionsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "table": "events", "action": "drop_expired", "retention_days": float64(15)},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+	text = result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "ALTER TABLE `events` DROP PARTITION `p202401`;")
+	assert.Contains(t, text, "- p202401 (before 2024-02-01, ~10 rows)")
+
+	_, err = NewManagePartitionsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "table": "events", "action": "detach", "partition": "p202401"},
+	}, "mysql1", useCase)
+	assert.ErrorContains(t, err, "only supported on PostgreSQL")
+}
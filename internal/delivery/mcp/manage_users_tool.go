@@ -0,0 +1,406 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// usersNow returns the current time; tests replace it to get stable expiry dates
+var usersNow = time.Now
+
+// userNamePattern restricts user names to ones that need no escaping beyond quoting
+var userNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$-]{0,62}$`)
+
+// userHostPattern matches MySQL account hosts: names, addresses, netmasks and % wildcards
+var userHostPattern = regexp.MustCompile(`^[A-Za-z0-9_.%:/-]{1,255}$`)
+
+// errNoUserChanges refuses an alter that changes nothing
+var errNoUserChanges = errors.New("alter needs at least one of rotate_password, connection_limit, expires_in_days or locked")
+
+// userSpec describes the requested state of a database user
+type userSpec struct {
+	Name     string
+	Host     string
+	Password string
+
+	// ConnectionLimit is only applied when HasConnectionLimit is set; -1 means unlimited
+	ConnectionLimit    int64
+	HasConnectionLimit bool
+
+	// ExpiresInDays is only applied when HasExpiry is set; 0 means the password never expires
+	ExpiresInDays int64
+	HasExpiry     bool
+
+	// Locked is only applied when HasLocked is set
+	Locked    bool
+	HasLocked bool
+}
+
+// ManageUsersTool handles creating, altering and dropping database users
+type ManageUsersTool struct {
+	BaseToolType
+}
+
+// NewManageUsersTool creates a new user management tool type
+func NewManageUsersTool() *ManageUsersTool {
+	return &ManageUsersTool{
+		BaseToolType: BaseToolType{
+			name:        "manage_users",
+			description: "List, create, alter and drop database users (PostgreSQL login roles, MySQL accounts), setting connection limits, password expiry and locking. Passwords are generated from a secure random source, returned once in the response and never logged. This is an administrative tool: it only runs on connections with allow_admin enabled.",
+		},
+	}
+}
+
+// CreateTool creates a user management tool
+func (t *ManageUsersTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Manage database users with generated passwords (requires allow_admin)"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("action",
+			tools.Description("list, create, alter or drop (default: list)"),
+		),
+		tools.WithString("user",
+			tools.Description("User name for create, alter and drop"),
+		),
+		tools.WithString("host",
+			tools.Description("Host part of the MySQL account (default: %)"),
+		),
+		tools.WithBoolean("rotate_password",
+			tools.Description("Generate a new password on alter (create always generates one)"),
+		),
+		tools.WithNumber("connection_limit",
+			tools.Description("Maximum concurrent connections of the user, -1 for unlimited"),
+		),
+		tools.WithNumber("expires_in_days",
+			tools.Description("Days until the password expires, 0 for never"),
+		),
+		tools.WithBoolean("locked",
+			tools.Description("Lock the account so it cannot log in (PostgreSQL: NOLOGIN)"),
+		),
+	)
+}
+
+// HandleRequest handles user management tool requests
+func (t *ManageUsersTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	action := input.choice("action", "list", "list", "create", "alter", "drop")
+	spec := userSpec{
+		Name:               input.optionalString("user", ""),
+		Host:               input.optionalString("host", "%"),
+		ConnectionLimit:    int64(input.intAtLeast("connection_limit", 0, -1)),
+		HasConnectionLimit: input.has("connection_limit"),
+		ExpiresInDays:      int64(input.intAtLeast("expires_in_days", 0, 0)),
+		HasExpiry:          input.has("expires_in_days"),
+		Locked:             input.optionalBool("locked", false),
+		HasLocked:          input.has("locked"),
+	}
+	rotate := input.optionalBool("rotate_password", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if err := requireAdmin(useCase, targetDbID, "manage_users"); err != nil {
+		return nil, err
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for manage_users: %s", dbType)
+	}
+	manager, err := dialect.UserManager()
+	if err != nil {
+		return nil, unsupportedAdminError("manage_users", dbType, err)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Users for Database %s\n\n", targetDbID))
+
+	if action == "list" {
+		if err := manager.writeList(ctx, useCase, targetDbID, &response); err != nil {
+			return nil, err
+		}
+		return createTextResponse(response.String()), nil
+	}
+
+	if !userNamePattern.MatchString(spec.Name) {
+		return nil, fmt.Errorf("user parameter must be a name of letters, digits, _, $ or - for %s", action)
+	}
+	if err := manager.validateAccount(spec); err != nil {
+		return nil, err
+	}
+	if action == "create" || rotate {
+		if spec.Password, err = generatePassword(); err != nil {
+			return nil, err
+		}
+	}
+
+	var statement string
+	switch action {
+	case "create":
+		statement = manager.createStatement(spec)
+	case "alter":
+		if statement, err = manager.alterStatement(spec); err != nil {
+			return nil, err
+		}
+	case "drop":
+		config, err := useCase.GetDatabaseConfig(targetDbID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get database config: %w", err)
+		}
+		if config.User == spec.Name {
+			return nil, fmt.Errorf("refusing to drop %s, the user this server connects as", spec.Name)
+		}
+		statement = manager.dropStatement(spec)
+	default:
+		return nil, fmt.Errorf("invalid action: %s (expected list, create, alter or drop)", action)
+	}
+
+	_, err = useCase.ExecuteStatement(ctx, targetDbID, statement, nil)
+	if err != nil {
+		// Database errors may quote the statement, which carries the password
+		err = fmt.Errorf("failed to %s user %s: %s", action, spec.Name, redactSecret(err.Error(), spec.Password))
+	}
+	audit(request, auditRecord{
+		Tool:      "manage_users",
+		Database:  targetDbID,
+		Action:    action,
+		Target:    spec.Name,
+		Statement: redactSecret(statement, spec.Password),
+	}, err)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("manage_users: %s user %s on database %s", action, spec.Name, targetDbID)
+
+	response.WriteString(fmt.Sprintf("Executed:\n\n```sql\n%s;\n```\n\n", redactSecret(statement, spec.Password)))
+	if spec.Password != "" {
+		response.WriteString(fmt.Sprintf("Password for %s: `%s`\n\n", spec.Name, spec.Password))
+		response.WriteString("Store it in a secret manager now; it is not logged and cannot be shown again.\n")
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// userManager is the engine-specific part of managing database users
+type userManager interface {
+	// validateAccount checks the parts of the account beyond the user name
+	validateAccount(spec userSpec) error
+	// createStatement builds the statement creating a user that can log in unless locked
+	createStatement(spec userSpec) string
+	// alterStatement builds the statement changing the requested attributes of a user
+	alterStatement(spec userSpec) (string, error)
+	// dropStatement builds the statement dropping a user
+	dropStatement(spec userSpec) string
+	// writeList renders the users with their limits, expiry and open connections
+	writeList(ctx context.Context, useCase UseCaseProvider, dbID string, sb *strings.Builder) error
+}
+
+// postgresUserManager manages login roles
+type postgresUserManager struct{}
+
+func (postgresUserManager) validateAccount(userSpec) error { return nil }
+
+func (postgresUserManager) account(spec userSpec) string {
+	return quoteIdentifier("postgres", spec.Name)
+}
+
+// options renders the options of the spec in the order of CREATE ROLE
+func (postgresUserManager) options(spec userSpec) []string {
+	var options []string
+	if spec.HasLocked {
+		if spec.Locked {
+			options = append(options, "NOLOGIN")
+		} else {
+			options = append(options, "LOGIN")
+		}
+	}
+	if spec.Password != "" {
+		options = append(options, "PASSWORD "+quoteLiteral("postgres", spec.Password))
+	}
+	if spec.HasConnectionLimit {
+		options = append(options, fmt.Sprintf("CONNECTION LIMIT %d", spec.ConnectionLimit))
+	}
+	if spec.HasExpiry {
+		options = append(options, "VALID UNTIL "+postgresValidUntil(spec.ExpiresInDays))
+	}
+	return options
+}
+
+func (m postgresUserManager) createStatement(spec userSpec) string {
+	if !spec.HasLocked {
+		spec.Locked, spec.HasLocked = false, true
+	}
+	return "CREATE ROLE " + m.account(spec) + " WITH " + strings.Join(m.options(spec), " ")
+}
+
+func (m postgresUserManager) alterStatement(spec userSpec) (string, error) {
+	options := m.options(spec)
+	if len(options) == 0 {
+		return "", errNoUserChanges
+	}
+	return "ALTER ROLE " + m.account(spec) + " WITH " + strings.Join(options, " "), nil
+}
+
+func (m postgresUserManager) dropStatement(spec userSpec) string {
+	return "DROP ROLE " + m.account(spec)
+}
+
+func (postgresUserManager) writeList(ctx context.Context, useCase UseCaseProvider, dbID string, sb *strings.Builder) error {
+	result, err := useCase.ExecuteQuery(ctx, dbID, `
+SELECT
+    r.rolname,
+    r.rolcanlogin,
+    r.rolsuper,
+    r.rolconnlimit,
+    COALESCE(r.rolvaliduntil::text, ''),
+    (SELECT count(*) FROM pg_stat_activity a WHERE a.usename = r.rolname)
+FROM pg_roles r
+WHERE r.rolname !~ '^pg_'
+ORDER BY r.rolname`, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+	sb.WriteString("| User | Login | Superuser | Connection limit | Valid until | Connections |\n")
+	sb.WriteString("|------|-------|-----------|------------------|-------------|-------------|\n")
+	for _, row := range result.Rows {
+		if len(row) < 6 {
+			continue
+		}
+		limit := "unlimited"
+		if n := valueInt64(row[3]); n >= 0 {
+			limit = fmt.Sprintf("%d", n)
+		}
+		validUntil := valueString(row[4])
+		if validUntil == "" {
+			validUntil = "never"
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %d |\n",
+			valueString(row[0]), yesNo(valueBool(row[1])), yesNo(valueBool(row[2])), limit, validUntil, valueInt64(row[5])))
+	}
+	return nil
+}
+
+// postgresValidUntil renders the VALID UNTIL timestamp of a password expiring in the given days
+func postgresValidUntil(days int64) string {
+	if days == 0 {
+		return "'infinity'"
+	}
+	return "'" + usersNow().UTC().AddDate(0, 0, int(days)).Format("2006-01-02 15:04:05") + "+00'"
+}
+
+// mysqlUserManager manages 'user'@'host' accounts
+type mysqlUserManager struct{}
+
+func (mysqlUserManager) validateAccount(spec userSpec) error {
+	if !userHostPattern.MatchString(spec.Host) {
+		return fmt.Errorf("invalid host: %s", spec.Host)
+	}
+	return nil
+}
+
+func (mysqlUserManager) account(spec userSpec) string {
+	return quoteLiteral("mysql", spec.Name) + "@" + quoteLiteral("mysql", spec.Host)
+}
+
+// options renders the options of the spec in the order of CREATE USER
+func (mysqlUserManager) options(spec userSpec) []string {
+	var options []string
+	if spec.Password != "" {
+		options = append(options, "IDENTIFIED BY "+quoteLiteral("mysql", spec.Password))
+	}
+	if spec.HasConnectionLimit {
+		// MySQL uses 0 for unlimited
+		limit := spec.ConnectionLimit
+		if limit < 0 {
+			limit = 0
+		}
+		options = append(options, fmt.Sprintf("WITH MAX_USER_CONNECTIONS %d", limit))
+	}
+	if spec.HasExpiry {
+		if spec.ExpiresInDays == 0 {
+			options = append(options, "PASSWORD EXPIRE NEVER")
+		} else {
+			options = append(options, fmt.Sprintf("PASSWORD EXPIRE INTERVAL %d DAY", spec.ExpiresInDays))
+		}
+	}
+	if spec.HasLocked {
+		if spec.Locked {
+			options = append(options, "ACCOUNT LOCK")
+		} else {
+			options = append(options, "ACCOUNT UNLOCK")
+		}
+	}
+	return options
+}
+
+func (m mysqlUserManager) createStatement(spec userSpec) string {
+	return strings.Join(append([]string{"CREATE USER " + m.account(spec)}, m.options(spec)...), " ")
+}
+
+func (m mysqlUserManager) alterStatement(spec userSpec) (string, error) {
+	options := m.options(spec)
+	if len(options) == 0 {
+		return "", errNoUserChanges
+	}
+	return strings.Join(append([]string{"ALTER USER " + m.account(spec)}, options...), " "), nil
+}
+
+func (m mysqlUserManager) dropStatement(spec userSpec) string {
+	return "DROP USER " + m.account(spec)
+}
+
+func (mysqlUserManager) writeList(ctx context.Context, useCase UseCaseProvider, dbID string, sb *strings.Builder) error {
+	result, err := useCase.ExecuteQuery(ctx, dbID, `
+SELECT user, host, account_locked, max_user_connections, password_expired,
+       COALESCE(CAST(password_lifetime AS CHAR), ''), COALESCE(CAST(password_last_changed AS CHAR), '')
+FROM mysql.user
+ORDER BY user, host`, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+	sb.WriteString("| User | Host | Locked | Connection limit | Password expired | Password lifetime | Password changed |\n")
+	sb.WriteString("|------|------|--------|------------------|------------------|-------------------|------------------|\n")
+	for _, row := range result.Rows {
+		if len(row) < 7 {
+			continue
+		}
+		limit := "unlimited"
+		if n := valueInt64(row[3]); n > 0 {
+			limit = fmt.Sprintf("%d", n)
+		}
+		lifetime := "default"
+		if days := valueString(row[5]); days != "" {
+			lifetime = days + " days"
+			if days == "0" {
+				lifetime = "never expires"
+			}
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+			valueString(row[0]), valueString(row[1]), yesNo(valueBool(row[2])), limit, yesNo(valueBool(row[4])), lifetime, valueString(row[6])))
+	}
+	return nil
+}
+
+// yesNo renders a flag for a table cell
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestUserStatements(t *testing.T) {
+	previous := usersNow
+	usersNow = func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { usersNow = previous })
+
+	spec := userSpec{Name: "reporting", Host: "10.0.%", Password: "pw", ConnectionLimit: 5, HasConnectionLimit: true, ExpiresInDays: 90, HasExpiry: true}
+	assert.Equal(t, `CREATE ROLE "reporting" WITH LOGIN PASSWORD 'pw' CONNECTION LIMIT 5 VALID UNTIL '2024-05-30 12:00:00+00'`,
+		postgresUserManager{}.createStatement(spec))
+	assert.Equal(t, "CREATE USER 'reporting'@'10.0.%' IDENTIFIED BY 'pw' WITH MAX_USER_CONNECTIONS 5 PASSWORD EXPIRE INTERVAL 90 DAY",
+		mysqlUserManager{}.createStatement(spec))
+
+	spec = userSpec{Name: "reporting", Host: "%", ConnectionLimit: -1, HasConnectionLimit: true, ExpiresInDays: 0, HasExpiry: true, Locked: true, HasLocked: true}
+	statement, err := postgresUserManager{}.alterStatement(spec)
+	assert.NoError(t, err)
+	assert.Equal(t, `ALTER ROLE "reporting" WITH NOLOGIN CONNECTION LIMIT -1 VALID UNTIL 'infinity'`, statement)
+	statement, err = mysqlUserManager{}.alterStatement(spec)
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER USER 'reporting'@'%' WITH MAX_USER_CONNECTIONS 0 PASSWORD EXPIRE NEVER ACCOUNT LOCK", statement)
+
+	_, err = postgresUserManager{}.alterStatement(userSpec{Name: "reporting"})
+	assert.Error(t, err)
+	assert.Equal(t, "DROP USER 'reporting'@'%'", mysqlUserManager{}.dropStatement(userSpec{Name: "reporting", Host: "%"}))
+}
+
+func TestManageUsersToolRequiresAdmin(t *testing.T) {
+	useCase := &mockUseCase{dbType: "postgres"}
+	_, err := NewManageUsersTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "action": "create", "user": "app"},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "set allow_admin")
+	assert.Empty(t, useCase.queries)
+}
+
+func TestManageUsersToolCreate(t *testing.T) {
+	useCase := &mockUseCase{dbType: "postgres", config: domain.DatabaseConnectionConfig{AllowAdmin: true, User: "admin"}}
+	result, err := NewManageUsersTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "action": "create", "user": "app", "connection_limit": float64(10)},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	password := regexp.MustCompile("Password for app: `([A-Za-z0-9]{32})`").FindStringSubmatch(text)
+	assert.Len(t, password, 2)
+	assert.Contains(t, text, `CREATE ROLE "app" WITH LOGIN PASSWORD '********' CONNECTION LIMIT 10;`)
+	assert.Equal(t, `CREATE ROLE "app" WITH LOGIN PASSWORD '`+password[1]+`' CONNECTION LIMIT 10`, useCase.queries[0])
+
+	_, err = NewManageUsersTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "action": "drop", "user": "admin"},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "the user this server connects as")
+
+	_, err = NewManageUsersTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "action": "create", "user": "app'; DROP TABLE x; --"},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "user parameter must be a name")
+}
+
+func TestManageUsersToolRedactsErrors(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType:        "mysql",
+		config:        domain.DatabaseConnectionConfig{AllowAdmin: true},
+		failStatement: "ALTER USER",
+	}
+	_, err := NewManageUsersTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "action": "alter", "user": "app", "rotate_password": true},
+	}, "mysql1", useCase)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "IDENTIFIED BY '********'")
+	assert.NotRegexp(t, `IDENTIFIED BY '[A-Za-z0-9]{32}'`, err.Error())
+}
@@ -0,0 +1,166 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// MigrateTool handles applying and reverting versioned SQL migrations
+type MigrateTool struct {
+	BaseToolType
+}
+
+// NewMigrateTool creates a new migration tool type
+func NewMigrateTool() *MigrateTool {
+	return &MigrateTool{
+		BaseToolType: BaseToolType{
+			name:        "migrate",
+			description: "Apply or revert versioned SQL migrations from the database's configured migrations directory (migrations_dir in the connection config, or $MIGRATIONS_DIR/<database id>). Files are named <version>_<name>.up.sql and <version>_<name>.down.sql. Applied versions are recorded in the schema_migrations table, and each migration runs in its own transaction together with its bookkeeping row. Use dry_run to see which migrations would run and their SQL without changing anything.",
+		},
+	}
+}
+
+// CreateTool creates a migration tool
+func (t *MigrateTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Apply or revert versioned .sql migrations with dry-run support"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("direction",
+			tools.Description("up applies pending migrations, down reverts applied ones (default: up)"),
+		),
+		tools.WithNumber("steps",
+			tools.Description("Maximum number of migrations to run (default: all pending for up, 1 for down)"),
+		),
+		tools.WithNumber("target_version",
+			tools.Description("Migrate up to and including this version, or down to this version (optional)"),
+		),
+		tools.WithBoolean("dry_run",
+			tools.Description("Only show the migrations that would run and their SQL (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles migration tool requests
+func (t *MigrateTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	direction := input.choice("direction", "up", "up", "down")
+	steps := input.intAtLeast("steps", 0, 1)
+	target := int64(input.intAtLeast("target_version", -1, 0))
+	dryRun := input.optionalBool("dry_run", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dir, err := migrationsDir(useCase, targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	all, err := loadMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := loadAppliedMigrations(ctx, useCase, targetDbID, dbType)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := planMigrations(all, applied, direction, target, steps)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Migrating database %s %s: %d migrations (dry run: %v)", targetDbID, direction, len(plan), dryRun)
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Migrations for Database %s\n\n", targetDbID))
+	response.WriteString(fmt.Sprintf("Directory: %s\n", dir))
+	response.WriteString(fmt.Sprintf("Direction: %s\n\n", direction))
+
+	if len(plan) == 0 {
+		if direction == "up" {
+			response.WriteString("Nothing to migrate: all migrations are applied.\n")
+		} else {
+			response.WriteString("Nothing to revert.\n")
+		}
+		return createTextResponse(response.String()), nil
+	}
+
+	if direction == "up" && len(applied) > 0 {
+		latest := applied[len(applied)-1].Version
+		for _, m := range plan {
+			if m.Version < latest {
+				response.WriteString(fmt.Sprintf("Warning: %d_%s is older than the latest applied version %d and will be applied out of order.\n\n", m.Version, m.Name, latest))
+			}
+		}
+	}
+
+	if dryRun {
+		response.WriteString(fmt.Sprintf("Dry run: %d migrations would run. Nothing was applied.\n", len(plan)))
+		for _, m := range plan {
+			script, err := m.script(direction)
+			if err != nil {
+				return nil, err
+			}
+			response.WriteString(fmt.Sprintf("\n## %d_%s (%s)\n\n```sql\n%s\n```\n", m.Version, m.Name, direction, strings.TrimSpace(script)))
+		}
+		return createTextResponse(response.String()), nil
+	}
+
+	// Parse every migration before running any so a broken file does not leave a half-applied plan
+	type migrationBatch struct {
+		statements []string
+		params     [][]interface{}
+	}
+	batches := make([]migrationBatch, len(plan))
+	for i, m := range plan {
+		statements, params, err := migrationStatements(m, direction, dbType)
+		if err != nil {
+			return nil, err
+		}
+		batches[i] = migrationBatch{statements: statements, params: params}
+	}
+
+	if _, err := useCase.ExecuteStatement(ctx, targetDbID, createMigrationsTableStatement(), nil); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", migrationsTable, err)
+	}
+
+	verb := "applied"
+	if direction == "down" {
+		verb = "reverted"
+	}
+	for i, m := range plan {
+		err := useCase.ExecuteBatch(ctx, targetDbID, batches[i].statements, batches[i].params)
+		audit(request, auditRecord{
+			Tool:      "migrate",
+			Database:  targetDbID,
+			Action:    direction,
+			Target:    fmt.Sprintf("%d_%s", m.Version, m.Name),
+			Statement: strings.Join(batches[i].statements, ";\n"),
+		}, err)
+		if err != nil {
+			note := ""
+			if dialect, ok := lookupDialect(dbType); ok && dialect.MigrationFailureNote() != "" {
+				note = " (" + dialect.MigrationFailureNote() + ")"
+			}
+			return nil, fmt.Errorf("migration %d_%s failed after %d of %d migrations ran%s: %w", m.Version, m.Name, i, len(plan), note, err)
+		}
+		logger.Info("Migrated database %s %s: %d_%s", targetDbID, direction, m.Version, m.Name)
+		response.WriteString(fmt.Sprintf("- %d_%s: %s (%d statements)\n", m.Version, m.Name, verb, len(batches[i].statements)-1))
+	}
+	response.WriteString(fmt.Sprintf("\n%d migrations ran successfully.\n", len(plan)))
+
+	return createTextResponse(response.String()), nil
+}
@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestMigrateToolAppliesPendingMigrations(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"0001_create_users.up.sql": "CREATE TABLE users (id INT);\nCREATE INDEX idx_users_id ON users (id);",
+		"0002_add_orders.up.sql":   "CREATE TABLE orders (id INT);",
+	})
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		config: domain.DatabaseConnectionConfig{MigrationsDir: dir},
+		results: map[string]*domain.QueryResult{
+			"information_schema.tables": {Rows: [][]interface{}{{int64(1)}}},
+			"FROM schema_migrations":    {Rows: [][]interface{}{{int64(1), "create_users", "abc", nil}}},
+		},
+	}
+
+	result, err := NewMigrateTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "- 2_add_orders: applied (1 statements)")
+	assert.Equal(t, [][]string{{
+		"CREATE TABLE orders (id INT)",
+		"INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+	}}, useCase.batches)
+}
+
+func TestMigrateToolDryRun(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"0001_create_users.up.sql":   "CREATE TABLE users (id INT);",
+		"0001_create_users.down.sql": "DROP TABLE users;",
+	})
+	useCase := &mockUseCase{dbType: "mysql", config: domain.DatabaseConnectionConfig{MigrationsDir: dir}}
+
+	result, err := NewMigrateTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "dry_run": true},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "Dry run: 1 migrations would run. Nothing was applied.")
+	assert.Contains(t, text, "## 1_create_users (up)\n\n```sql\nCREATE TABLE users (id INT);\n```")
+	assert.Empty(t, useCase.batches)
+	for _, query := range useCase.queries {
+		assert.NotContains(t, query, "CREATE TABLE")
+	}
+}
+
+func TestMigrateToolReportsFailure(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"0001_ok.up.sql":     "CREATE TABLE a (id INT);",
+		"0002_broken.up.sql": "CREATE TABLE broken (id INT);",
+	})
+	useCase := &mockUseCase{dbType: "mysql", config: domain.DatabaseConnectionConfig{MigrationsDir: dir}, failBatch: "broken"}
+
+	_, err := NewMigrateTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1"},
+	}, "mysql1", useCase)
+	assert.ErrorContains(t, err, "migration 2_broken failed after 1 of 2 migrations ran")
+	assert.Len(t, useCase.batches, 1)
+}
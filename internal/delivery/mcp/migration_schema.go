@@ -0,0 +1,565 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// expectedTable is a table the applied migrations should have produced
+type expectedTable struct {
+	Name         string
+	Version      int64 // migration that created or first altered the table
+	Columns      []string
+	ColumnsKnown bool     // false when the table was not created from a column list
+	Dropped      []string // columns dropped by a migration
+}
+
+// expectedIndex is an index the applied migrations should have produced
+type expectedIndex struct {
+	Name    string
+	Table   string
+	Version int64
+}
+
+// expectedSchema is the schema derived by replaying the DDL of the applied migrations.
+// Only the common statements are understood: CREATE/DROP/ALTER/RENAME TABLE and
+// CREATE/DROP/ALTER INDEX. Anything else is ignored.
+type expectedSchema struct {
+	schema        string
+	tables        []*expectedTable
+	indexes       []*expectedIndex
+	droppedTables map[string]int64 // lower-case name -> migration that dropped it
+}
+
+// tableConstraintWords start the items of a column list that are not columns
+var tableConstraintWords = map[string]bool{
+	"CONSTRAINT": true, "PRIMARY": true, "UNIQUE": true, "FOREIGN": true, "CHECK": true,
+	"KEY": true, "INDEX": true, "FULLTEXT": true, "SPATIAL": true, "EXCLUDE": true, "LIKE": true,
+}
+
+// newExpectedSchema creates an empty expected schema; statements qualified with another schema are ignored
+func newExpectedSchema(schema string) *expectedSchema {
+	return &expectedSchema{schema: schema, droppedTables: make(map[string]int64)}
+}
+
+// applyScript replays the DDL statements of one migration script
+func (s *expectedSchema) applyScript(script, dialect string, version int64) error {
+	tokens, err := tokenizeSQL(script, dialect)
+	if err != nil {
+		return err
+	}
+	var statement []sqlToken
+	for _, tok := range tokens {
+		switch {
+		case tok.kind == sqlSpace || tok.kind == sqlComment:
+		case tok.is(";"):
+			s.applyStatement(statement, version)
+			statement = nil
+		default:
+			statement = append(statement, tok)
+		}
+	}
+	s.applyStatement(statement, version)
+	return nil
+}
+
+// applyStatement replays a single statement given as its significant tokens
+func (s *expectedSchema) applyStatement(toks []sqlToken, version int64) {
+	p := &ddlParser{toks: toks}
+	switch {
+	case p.accept("CREATE"):
+		p.accept("OR", "REPLACE")
+		p.accept("GLOBAL")
+		p.accept("LOCAL")
+		if p.accept("TEMPORARY") || p.accept("TEMP") {
+			return
+		}
+		p.accept("UNLOGGED")
+		p.accept("UNIQUE")
+		p.accept("FULLTEXT")
+		p.accept("SPATIAL")
+		switch {
+		case p.accept("TABLE"):
+			s.createTable(p, version)
+		case p.accept("INDEX"):
+			p.accept("CONCURRENTLY")
+			p.accept("IF", "NOT", "EXISTS")
+			if p.peek("ON") {
+				return // unnamed PostgreSQL index
+			}
+			_, name, ok := p.name()
+			if !ok || !p.accept("ON") {
+				return
+			}
+			p.accept("ONLY")
+			if schema, table, ok := p.name(); ok && s.inSchema(schema) {
+				s.addIndex(name, table, version)
+			}
+		}
+	case p.accept("DROP"):
+		switch {
+		case p.accept("TABLE"):
+			p.accept("IF", "EXISTS")
+			for {
+				schema, table, ok := p.name()
+				if !ok {
+					return
+				}
+				if s.inSchema(schema) {
+					s.dropTable(table, version)
+				}
+				if !p.accept(",") {
+					return
+				}
+			}
+		case p.accept("INDEX"):
+			p.accept("CONCURRENTLY")
+			p.accept("IF", "EXISTS")
+			if _, name, ok := p.name(); ok {
+				s.dropIndex(name)
+			}
+		}
+	case p.accept("ALTER"):
+		switch {
+		case p.accept("TABLE"):
+			p.accept("IF", "EXISTS")
+			p.accept("ONLY")
+			schema, name, ok := p.name()
+			if !ok || !s.inSchema(schema) {
+				return
+			}
+			table := s.touchTable(name, version)
+			for _, action := range splitArgs(p.rest()) {
+				s.alterTable(table, &ddlParser{toks: action}, version)
+			}
+		case p.accept("INDEX"):
+			p.accept("IF", "EXISTS")
+			if _, name, ok := p.name(); ok && p.accept("RENAME", "TO") {
+				if _, newName, ok := p.name(); ok {
+					s.renameIndex(name, newName)
+				}
+			}
+		}
+	case p.accept("RENAME", "TABLE"):
+		for {
+			_, from, ok := p.name()
+			if !ok || !p.accept("TO") {
+				return
+			}
+			_, to, ok := p.name()
+			if !ok {
+				return
+			}
+			s.renameTable(from, to, version)
+			if !p.accept(",") {
+				return
+			}
+		}
+	}
+}
+
+// createTable handles CREATE TABLE after the TABLE keyword
+func (s *expectedSchema) createTable(p *ddlParser, version int64) {
+	p.accept("IF", "NOT", "EXISTS")
+	schema, name, ok := p.name()
+	if !ok || !s.inSchema(schema) || p.peek("PARTITION") {
+		// Partitions are not listed as tables in the live schema metadata
+		return
+	}
+	if existing := s.table(name); existing != nil {
+		return // CREATE TABLE IF NOT EXISTS on a table that already exists
+	}
+	delete(s.droppedTables, strings.ToLower(name))
+	table := &expectedTable{Name: name, Version: version}
+	s.tables = append(s.tables, table)
+
+	if !p.peek("(") {
+		return // CREATE TABLE ... AS or LIKE
+	}
+	body, ok := p.parenthesized()
+	if !ok {
+		return
+	}
+	table.ColumnsKnown = !p.peek("INHERITS")
+	for _, item := range splitArgs(body) {
+		if len(item) == 0 {
+			continue
+		}
+		if item[0].kind == sqlWord && tableConstraintWords[strings.ToUpper(item[0].text)] {
+			if item[0].is("LIKE") {
+				table.ColumnsKnown = false
+			}
+			continue
+		}
+		if item[0].kind == sqlWord || item[0].kind == sqlQuotedIdent {
+			table.Columns = append(table.Columns, item[0].text)
+		}
+	}
+}
+
+// alterTable handles one comma-separated action of ALTER TABLE
+func (s *expectedSchema) alterTable(table *expectedTable, p *ddlParser, version int64) {
+	switch {
+	case p.accept("ADD"):
+		if p.accept("INDEX") || p.accept("KEY") {
+			if _, name, ok := p.name(); ok {
+				s.addIndex(name, table.Name, version)
+			}
+			return
+		}
+		if p.peekConstraint() {
+			return
+		}
+		p.accept("COLUMN")
+		p.accept("IF", "NOT", "EXISTS")
+		if _, column, ok := p.name(); ok {
+			table.addColumn(column)
+		}
+	case p.accept("DROP"):
+		if p.accept("INDEX") || p.accept("KEY") {
+			if _, name, ok := p.name(); ok {
+				s.dropIndex(name)
+			}
+			return
+		}
+		if p.peekConstraint() {
+			return
+		}
+		p.accept("COLUMN")
+		p.accept("IF", "EXISTS")
+		if _, column, ok := p.name(); ok {
+			table.dropColumn(column)
+		}
+	case p.accept("RENAME"):
+		switch {
+		case p.accept("COLUMN"):
+			if _, from, ok := p.name(); ok && p.accept("TO") {
+				if _, to, ok := p.name(); ok {
+					table.renameColumn(from, to)
+				}
+			}
+		case p.accept("INDEX") || p.accept("KEY"):
+			if _, from, ok := p.name(); ok && p.accept("TO") {
+				if _, to, ok := p.name(); ok {
+					s.renameIndex(from, to)
+				}
+			}
+		case p.peek("CONSTRAINT"):
+		case p.accept("TO") || p.accept("AS"):
+			if _, to, ok := p.name(); ok {
+				s.renameTable(table.Name, to, version)
+			}
+		default:
+			// PostgreSQL RENAME a TO b renames a column, MySQL RENAME b renames the table
+			if _, name, ok := p.name(); ok {
+				if !p.accept("TO") {
+					s.renameTable(table.Name, name, version)
+				} else if _, to, ok := p.name(); ok {
+					table.renameColumn(name, to)
+				}
+			}
+		}
+	case p.accept("CHANGE"):
+		p.accept("COLUMN")
+		if _, from, ok := p.name(); ok {
+			if _, to, ok := p.name(); ok {
+				table.renameColumn(from, to)
+			}
+		}
+	}
+}
+
+// inSchema reports whether a statement's schema qualifier refers to the compared schema
+func (s *expectedSchema) inSchema(schema string) bool {
+	return schema == "" || s.schema == "" || strings.EqualFold(schema, s.schema)
+}
+
+// table returns the expected table with the given name, or nil
+func (s *expectedSchema) table(name string) *expectedTable {
+	for _, t := range s.tables {
+		if strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+// touchTable returns the expected table, recording a table of unknown columns if no migration created it
+func (s *expectedSchema) touchTable(name string, version int64) *expectedTable {
+	if table := s.table(name); table != nil {
+		return table
+	}
+	table := &expectedTable{Name: name, Version: version}
+	s.tables = append(s.tables, table)
+	return table
+}
+
+// dropTable removes a table and its indexes
+func (s *expectedSchema) dropTable(name string, version int64) {
+	for i, t := range s.tables {
+		if strings.EqualFold(t.Name, name) {
+			s.tables = append(s.tables[:i], s.tables[i+1:]...)
+			break
+		}
+	}
+	kept := s.indexes[:0]
+	for _, index := range s.indexes {
+		if !strings.EqualFold(index.Table, name) {
+			kept = append(kept, index)
+		}
+	}
+	s.indexes = kept
+	s.droppedTables[strings.ToLower(name)] = version
+}
+
+// renameTable renames a table and the table of its indexes
+func (s *expectedSchema) renameTable(from, to string, version int64) {
+	table := s.touchTable(from, version)
+	table.Name = to
+	for _, index := range s.indexes {
+		if strings.EqualFold(index.Table, from) {
+			index.Table = to
+		}
+	}
+	s.droppedTables[strings.ToLower(from)] = version
+	delete(s.droppedTables, strings.ToLower(to))
+}
+
+// addIndex records an index on a table
+func (s *expectedSchema) addIndex(name, table string, version int64) {
+	s.dropIndex(name)
+	s.indexes = append(s.indexes, &expectedIndex{Name: name, Table: table, Version: version})
+}
+
+// dropIndex removes an index
+func (s *expectedSchema) dropIndex(name string) {
+	for i, index := range s.indexes {
+		if strings.EqualFold(index.Name, name) {
+			s.indexes = append(s.indexes[:i], s.indexes[i+1:]...)
+			return
+		}
+	}
+}
+
+// renameIndex renames an index
+func (s *expectedSchema) renameIndex(from, to string) {
+	for _, index := range s.indexes {
+		if strings.EqualFold(index.Name, from) {
+			index.Name = to
+			return
+		}
+	}
+}
+
+// addColumn records a column added to the table
+func (t *expectedTable) addColumn(name string) {
+	t.Dropped = removeFold(t.Dropped, name)
+	t.Columns = append(removeFold(t.Columns, name), name)
+}
+
+// dropColumn records a column dropped from the table
+func (t *expectedTable) dropColumn(name string) {
+	t.Columns = removeFold(t.Columns, name)
+	t.Dropped = append(removeFold(t.Dropped, name), name)
+}
+
+// renameColumn records a renamed column
+func (t *expectedTable) renameColumn(from, to string) {
+	t.dropColumn(from)
+	t.addColumn(to)
+}
+
+// removeFold returns the list without the given name, ignoring case
+func removeFold(names []string, name string) []string {
+	kept := make([]string, 0, len(names))
+	for _, n := range names {
+		if !strings.EqualFold(n, name) {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+// compareExpectedSchema lists the differences between the expected and the live schema
+func compareExpectedSchema(expected *expectedSchema, live *schemaMetadata) []string {
+	liveTable := func(name string) *schemaTable {
+		for _, t := range live.Tables {
+			if strings.EqualFold(t.Name, name) {
+				return t
+			}
+		}
+		return nil
+	}
+	hasColumn := func(t *schemaTable, name string) bool {
+		for _, c := range t.Columns {
+			if strings.EqualFold(c.Name, name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var drift []string
+	for _, table := range expected.tables {
+		actual := liveTable(table.Name)
+		if actual == nil {
+			drift = append(drift, fmt.Sprintf("Table %s (expected since migration %d) is missing", table.Name, table.Version))
+			continue
+		}
+		for _, column := range table.Columns {
+			if !hasColumn(actual, column) {
+				drift = append(drift, fmt.Sprintf("Column %s.%s is missing", table.Name, column))
+			}
+		}
+		for _, column := range table.Dropped {
+			if hasColumn(actual, column) {
+				drift = append(drift, fmt.Sprintf("Column %s.%s was dropped by a migration but still exists", table.Name, column))
+			}
+		}
+		if table.ColumnsKnown {
+			for _, column := range actual.Columns {
+				if !containsFold(table.Columns, column.Name) {
+					drift = append(drift, fmt.Sprintf("Column %s.%s was not added by any migration", table.Name, column.Name))
+				}
+			}
+		}
+	}
+
+	for _, t := range live.Tables {
+		if version, ok := expected.droppedTables[strings.ToLower(t.Name)]; ok && expected.table(t.Name) == nil {
+			drift = append(drift, fmt.Sprintf("Table %s was dropped or renamed by migration %d but still exists", t.Name, version))
+		}
+	}
+
+	for _, index := range expected.indexes {
+		actual := liveTable(index.Table)
+		if actual == nil {
+			continue // already reported as a missing table
+		}
+		found := false
+		for _, i := range actual.Indexes {
+			if strings.EqualFold(i.Name, index.Name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			drift = append(drift, fmt.Sprintf("Index %s on %s (created by migration %d) is missing", index.Name, index.Table, index.Version))
+		}
+	}
+	return drift
+}
+
+// untrackedTables lists the live tables that no migration created
+func untrackedTables(expected *expectedSchema, live *schemaMetadata) []string {
+	var untracked []string
+	for _, t := range live.Tables {
+		if strings.EqualFold(t.Name, migrationsTable) || expected.table(t.Name) != nil {
+			continue
+		}
+		if _, dropped := expected.droppedTables[strings.ToLower(t.Name)]; dropped {
+			continue // reported as drift
+		}
+		untracked = append(untracked, t.Name)
+	}
+	return untracked
+}
+
+// containsFold reports whether the list contains the name, ignoring case
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ddlParser walks the significant tokens of a DDL statement
+type ddlParser struct {
+	toks []sqlToken
+	i    int
+}
+
+// peek reports whether the next token is the given keyword or symbol
+func (p *ddlParser) peek(word string) bool {
+	return p.i < len(p.toks) && p.toks[p.i].is(word)
+}
+
+// accept consumes the given sequence of keywords if the next tokens match all of them
+func (p *ddlParser) accept(words ...string) bool {
+	if p.i+len(words) > len(p.toks) {
+		return false
+	}
+	for j, word := range words {
+		if !p.toks[p.i+j].is(word) {
+			return false
+		}
+	}
+	p.i += len(words)
+	return true
+}
+
+// peekConstraint reports whether the next token starts a table constraint rather than a column
+func (p *ddlParser) peekConstraint() bool {
+	return p.i < len(p.toks) && p.toks[p.i].kind == sqlWord && tableConstraintWords[strings.ToUpper(p.toks[p.i].text)]
+}
+
+// name consumes an optionally schema-qualified object name
+func (p *ddlParser) name() (string, string, bool) {
+	ident := func() (string, bool) {
+		if p.i >= len(p.toks) || (p.toks[p.i].kind != sqlWord && p.toks[p.i].kind != sqlQuotedIdent) {
+			return "", false
+		}
+		p.i++
+		return p.toks[p.i-1].text, true
+	}
+	name, ok := ident()
+	if !ok {
+		return "", "", false
+	}
+	if p.accept(".") {
+		table, ok := ident()
+		if !ok {
+			return "", "", false
+		}
+		return name, table, true
+	}
+	return "", name, true
+}
+
+// parenthesized consumes a parenthesized group and returns the tokens inside it
+func (p *ddlParser) parenthesized() ([]sqlToken, bool) {
+	end := matchingParen(p.toks, p.i)
+	if end < 0 {
+		return nil, false
+	}
+	body := p.toks[p.i+1 : end]
+	p.i = end + 1
+	return body, true
+}
+
+// rest returns the unconsumed tokens
+func (p *ddlParser) rest() []sqlToken {
+	return p.toks[p.i:]
+}
+
+// done reports whether every token has been consumed
+func (p *ddlParser) done() bool {
+	return p.i >= len(p.toks)
+}
+
+// nameList consumes a comma-separated list of object names and returns them schema-qualified
+func (p *ddlParser) nameList() []string {
+	var names []string
+	for {
+		schema, name, ok := p.name()
+		if !ok {
+			return names
+		}
+		names = append(names, qualifiedName(schema, name))
+		if !p.accept(",") {
+			return names
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// migrationState is the status of one migration version, from its file and its recorded row
+type migrationState struct {
+	Version          int64
+	Name             string
+	Status           string // applied, pending, changed or missing file
+	AppliedAt        time.Time
+	Checksum         string // checksum of the file, or the recorded one when the file is missing
+	RecordedChecksum string
+	migration        *migration
+}
+
+// MigrationStatusTool handles reporting applied and pending migrations
+type MigrationStatusTool struct {
+	BaseToolType
+}
+
+// NewMigrationStatusTool creates a new migration status tool type
+func NewMigrationStatusTool() *MigrationStatusTool {
+	return &MigrationStatusTool{
+		BaseToolType: BaseToolType{
+			name:        "migration_status",
+			description: "Report which migrations of the database's migrations directory are applied and which are pending, when the last one was applied and the checksum of each. Detects drift: migration files changed after they were applied, applied versions whose file is gone, pending migrations older than the current version, and differences between the live schema and the tables, columns and indexes the applied migrations create.",
+		},
+	}
+}
+
+// CreateTool creates a migration status tool
+func (t *MigrationStatusTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Show applied and pending migrations with checksums and schema drift"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithBoolean("check_schema",
+			tools.Description("Compare the live schema with the schema the applied migrations create (default: true)"),
+		),
+	)
+}
+
+// HandleRequest handles migration status tool requests
+func (t *MigrationStatusTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	checkSchema := input.optionalBool("check_schema", true)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dir, err := migrationsDir(useCase, targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	all, err := loadMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := loadAppliedMigrations(ctx, useCase, targetDbID, dbType)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Checking migration status of database %s: %d migrations, %d applied", targetDbID, len(all), len(applied))
+
+	states := migrationStates(all, applied)
+	drift := migrationDrift(states)
+
+	var untracked []string
+	if checkSchema {
+		live, err := loadSchemaMetadata(ctx, useCase, targetDbID, "")
+		if err != nil {
+			return nil, err
+		}
+		expected := newExpectedSchema(live.Schema)
+		for _, state := range states {
+			if state.migration == nil || state.Status == "pending" {
+				continue
+			}
+			script, err := state.migration.script("up")
+			if err != nil {
+				return nil, err
+			}
+			if err := expected.applyScript(script, strings.ToLower(dbType), state.Version); err != nil {
+				return nil, fmt.Errorf("failed to parse migration %d_%s: %w", state.Version, state.Name, err)
+			}
+		}
+		drift = append(drift, compareExpectedSchema(expected, live)...)
+		untracked = untrackedTables(expected, live)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Migration Status for Database %s\n\n", targetDbID))
+	response.WriteString(formatMigrationStatus(dir, states, drift, untracked, checkSchema))
+
+	return createTextResponse(response.String()), nil
+}
+
+// migrationStates merges the migration files with the recorded migrations, ordered by version
+func migrationStates(all []*migration, applied []appliedMigration) []migrationState {
+	recorded := make(map[int64]appliedMigration, len(applied))
+	for _, a := range applied {
+		recorded[a.Version] = a
+	}
+
+	states := make([]migrationState, 0, len(all))
+	seen := make(map[int64]bool, len(all))
+	for _, m := range all {
+		seen[m.Version] = true
+		state := migrationState{Version: m.Version, Name: m.Name, Status: "pending", Checksum: m.Checksum, migration: m}
+		if a, ok := recorded[m.Version]; ok {
+			state.Status = "applied"
+			state.AppliedAt = a.AppliedAt
+			state.RecordedChecksum = a.Checksum
+			if a.Checksum != m.Checksum {
+				state.Status = "changed"
+			}
+		}
+		states = append(states, state)
+	}
+	for _, a := range applied {
+		if !seen[a.Version] {
+			states = append(states, migrationState{
+				Version:          a.Version,
+				Name:             a.Name,
+				Status:           "missing file",
+				AppliedAt:        a.AppliedAt,
+				Checksum:         a.Checksum,
+				RecordedChecksum: a.Checksum,
+			})
+		}
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Version < states[j].Version })
+	return states
+}
+
+// migrationDrift lists the differences between the migration files and the recorded migrations
+func migrationDrift(states []migrationState) []string {
+	latest := int64(-1)
+	for _, state := range states {
+		if state.Status != "pending" {
+			latest = state.Version
+		}
+	}
+
+	var drift []string
+	for _, state := range states {
+		switch {
+		case state.Status == "changed":
+			drift = append(drift, fmt.Sprintf("%d_%s was changed after it was applied (recorded checksum %s, file checksum %s)",
+				state.Version, state.Name, shortChecksum(state.RecordedChecksum), shortChecksum(state.Checksum)))
+		case state.Status == "missing file":
+			drift = append(drift, fmt.Sprintf("%d_%s is recorded as applied but its file is missing from the migrations directory", state.Version, state.Name))
+		case state.Status == "pending" && state.Version < latest:
+			drift = append(drift, fmt.Sprintf("%d_%s is pending but older than the latest applied version %d", state.Version, state.Name, latest))
+		}
+	}
+	return drift
+}
+
+// formatMigrationStatus renders the migration states, drift and untracked tables
+func formatMigrationStatus(dir string, states []migrationState, drift, untracked []string, checkSchema bool) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Directory: %s\n", dir))
+
+	appliedCount, pendingCount := 0, 0
+	var last *migrationState
+	for i := range states {
+		state := &states[i]
+		if state.Status == "pending" {
+			pendingCount++
+			continue
+		}
+		appliedCount++
+		if last == nil || state.AppliedAt.After(last.AppliedAt) {
+			last = state
+		}
+	}
+	sb.WriteString(fmt.Sprintf("Applied: %d, pending: %d\n", appliedCount, pendingCount))
+	if last != nil {
+		sb.WriteString(fmt.Sprintf("Last applied: %d_%s", last.Version, last.Name))
+		if !last.AppliedAt.IsZero() {
+			sb.WriteString(" at " + last.AppliedAt.Format("2006-01-02 15:04:05"))
+		}
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString("No migrations have been applied yet.\n")
+	}
+
+	if len(states) > 0 {
+		sb.WriteString("\n| Version | Name | Status | Applied At | Checksum |\n")
+		sb.WriteString("|---------|------|--------|------------|----------|\n")
+		for _, state := range states {
+			appliedAt := ""
+			if !state.AppliedAt.IsZero() {
+				appliedAt = state.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			sb.WriteString(fmt.Sprintf("| %d | %s | %s | %s | %s |\n",
+				state.Version, state.Name, state.Status, appliedAt, shortChecksum(state.Checksum)))
+		}
+	}
+
+	sb.WriteString("\n## Drift\n\n")
+	if len(drift) == 0 {
+		if checkSchema {
+			sb.WriteString("No drift detected: the files and the live schema match the recorded migrations.\n")
+		} else {
+			sb.WriteString("No drift detected between the files and the recorded migrations (live schema not checked).\n")
+		}
+	} else {
+		for _, item := range drift {
+			sb.WriteString("- " + item + "\n")
+		}
+	}
+
+	if len(untracked) > 0 {
+		sb.WriteString(fmt.Sprintf("\nTables not created by any migration: %s\n", strings.Join(untracked, ", ")))
+	}
+	return sb.String()
+}
+
+// shortChecksum abbreviates a checksum for display
+func shortChecksum(checksum string) string {
+	if len(checksum) > 12 {
+		return checksum[:12]
+	}
+	return checksum
+}
@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestExpectedSchema(t *testing.T) {
+	expected := newExpectedSchema("public")
+	scripts := []string{
+		`CREATE TABLE users (
+  id SERIAL PRIMARY KEY,
+  "Name" TEXT NOT NULL,
+  legacy INT,
+  CONSTRAINT users_name_key UNIQUE ("Name")
+);
+CREATE INDEX idx_users_name ON users ("Name");`,
+		`ALTER TABLE users ADD COLUMN email TEXT, DROP COLUMN legacy;
+ALTER TABLE users RENAME "Name" TO full_name;
+CREATE TABLE tmp_import AS SELECT * FROM users;
+CREATE TABLE other.audit (id INT);`,
+		`DROP TABLE IF EXISTS tmp_import;
+ALTER TABLE orders ADD COLUMN total NUMERIC(10, 2);
+ALTER INDEX idx_users_name RENAME TO idx_users_full_name;`,
+	}
+	for i, script := range scripts {
+		assert.NoError(t, expected.applyScript(script, "postgres", int64(i+1)))
+	}
+
+	assert.Len(t, expected.tables, 2)
+	users := expected.table("users")
+	assert.True(t, users.ColumnsKnown)
+	assert.Equal(t, []string{"id", "email", "full_name"}, users.Columns)
+	assert.Equal(t, []string{"legacy", "Name"}, users.Dropped)
+	orders := expected.table("orders")
+	assert.False(t, orders.ColumnsKnown)
+	assert.Equal(t, []string{"total"}, orders.Columns)
+	assert.Equal(t, int64(3), orders.Version)
+	assert.Equal(t, map[string]int64{"tmp_import": 3}, expected.droppedTables)
+	assert.Len(t, expected.indexes, 1)
+	assert.Equal(t, "idx_users_full_name", expected.indexes[0].Name)
+
+	mysql := newExpectedSchema("")
+	assert.NoError(t, mysql.applyScript("CREATE TABLE `a` (`id` INT, KEY `idx_id` (`id`)) ENGINE=InnoDB;\n"+
+		"ALTER TABLE a CHANGE COLUMN id a_id BIGINT, ADD INDEX idx_a_id (a_id), DROP INDEX idx_id;\n"+
+		"RENAME TABLE a TO b;", "mysql", 1))
+	assert.Nil(t, mysql.table("a"))
+	assert.Equal(t, []string{"a_id"}, mysql.table("b").Columns)
+	assert.Len(t, mysql.indexes, 1)
+	assert.Equal(t, "b", mysql.indexes[0].Table)
+}
+
+func TestMigrationStatusToolReportsDrift(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"0001_create_users.up.sql": "CREATE TABLE users (id INT, name TEXT);\nCREATE INDEX idx_users_name ON users (name);",
+		"0002_add_email.up.sql":    "ALTER TABLE users ADD COLUMN email TEXT;",
+		"0003_add_orders.up.sql":   "CREATE TABLE orders (id INT);",
+	})
+	migrations, err := loadMigrations(dir)
+	assert.NoError(t, err)
+
+	appliedAt := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		config: domain.DatabaseConnectionConfig{MigrationsDir: dir},
+		results: map[string]*domain.QueryResult{
+			"information_schema.tables": {Rows: [][]interface{}{{int64(1)}}},
+			"FROM schema_migrations": {Rows: [][]interface{}{
+				{int64(1), "create_users", migrations[0].Checksum, appliedAt.Add(-time.Hour)},
+				{int64(2), "add_email", "0123456789abcdef", appliedAt.Add(-time.Minute)},
+				{int64(5), "add_audit", "fedcba9876543210", appliedAt},
+			}},
+			"col_description": {Rows: [][]interface{}{
+				{"public", "schema_migrations", "version", "bigint", false, nil, nil, nil},
+				{"public", "users", "id", "integer", false, nil, nil, nil},
+				{"public", "users", "name", "text", true, nil, nil, nil},
+				{"public", "users", "nickname", "text", true, nil, nil, nil},
+				{"public", "audit_log", "id", "integer", false, nil, nil, nil},
+			}},
+		},
+	}
+
+	result, err := NewMigrationStatusTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "Applied: 3, pending: 1\n")
+	assert.Contains(t, text, "Last applied: 5_add_audit at 2024-03-01 12:30:00\n")
+	assert.Contains(t, text, "| 1 | create_users | applied | 2024-03-01 11:30:00 | "+migrations[0].Checksum[:12]+" |")
+	assert.Contains(t, text, "| 3 | add_orders | pending |  | ")
+	assert.Contains(t, text, "- 2_add_email was changed after it was applied (recorded checksum 0123456789ab, file checksum "+migrations[1].Checksum[:12]+")")
+	assert.Contains(t, text, "- 5_add_audit is recorded as applied but its file is missing")
+	assert.Contains(t, text, "- 3_add_orders is pending but older than the latest applied version 5")
+	assert.Contains(t, text, "- Column users.email is missing")
+	assert.Contains(t, text, "- Column users.nickname was not added by any migration")
+	assert.Contains(t, text, "- Index idx_users_name on users (created by migration 1) is missing")
+	assert.NotContains(t, text, "Table orders")
+	assert.Contains(t, text, "Tables not created by any migration: audit_log\n")
+}
+
+func TestMigrationStatusToolWithoutSchemaCheck(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{"0001_create_users.up.sql": "CREATE TABLE users (id INT);"})
+	useCase := &mockUseCase{dbType: "mysql", config: domain.DatabaseConnectionConfig{MigrationsDir: dir}}
+
+	result, err := NewMigrationStatusTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "check_schema": false},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "No migrations have been applied yet.")
+	assert.Contains(t, text, "live schema not checked")
+	assert.Len(t, useCase.queries, 1)
+}
@@ -0,0 +1,247 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationsTable records the migrations applied to a database
+const migrationsTable = "schema_migrations"
+
+// migrationFilePattern matches 0001_create_users.up.sql, 0001_create_users.down.sql and 0001_create_users.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([^.]+?)(?:\.(up|down))?\.sql$`)
+
+// migration is a versioned migration read from the migrations directory
+type migration struct {
+	Version  int64
+	Name     string
+	UpPath   string
+	DownPath string
+	Checksum string // SHA-256 of the up script
+}
+
+// appliedMigration is a row of the migrations table
+type appliedMigration struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// loadMigrations reads the migrations of a directory ordered by version
+func loadMigrations(dir string) ([]*migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory %s: %w", dir, err)
+	}
+
+	byVersion := make(map[int64]*migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, m.Name, match[2])
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if match[3] == "down" {
+			m.DownPath = path
+		} else if m.UpPath != "" {
+			return nil, fmt.Errorf("migration %d has more than one up script", version)
+		} else {
+			m.UpPath = path
+		}
+	}
+
+	migrations := make([]*migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.UpPath == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		content, err := os.ReadFile(m.UpPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", m.UpPath, err)
+		}
+		m.Checksum = migrationChecksum(content)
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// migrationChecksum returns the hex SHA-256 of a migration script
+func migrationChecksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// script reads the up or down script of a migration
+func (m *migration) script(direction string) (string, error) {
+	path := m.UpPath
+	if direction == "down" {
+		path = m.DownPath
+	}
+	if path == "" {
+		return "", fmt.Errorf("migration %d_%s has no %s script", m.Version, m.Name, direction)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read migration %s: %w", path, err)
+	}
+	return string(content), nil
+}
+
+// migrationsDir returns the configured migrations directory of a database
+func migrationsDir(useCase UseCaseProvider, dbID string) (string, error) {
+	config, err := useCase.GetDatabaseConfig(dbID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get database configuration: %w", err)
+	}
+	if config.MigrationsDir == "" {
+		return "", fmt.Errorf("no migrations directory configured for database %s", dbID)
+	}
+	return config.MigrationsDir, nil
+}
+
+// loadAppliedMigrations reads the migrations table; a missing table means nothing was applied yet
+func loadAppliedMigrations(ctx context.Context, useCase UseCaseProvider, dbID, dbType string) ([]appliedMigration, error) {
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for migrations: %s", dbType)
+	}
+	existsQuery, ok := dialect.TableExistsQuery()
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for migrations: %s", dbType)
+	}
+	result, err := useCase.ExecuteQuery(ctx, dbID, existsQuery, []interface{}{migrationsTable})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for %s: %w", migrationsTable, err)
+	}
+	if len(result.Rows) == 0 || valueInt64(result.Rows[0][0]) == 0 {
+		return nil, nil
+	}
+
+	result, err = useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(
+		"SELECT version, name, checksum, applied_at FROM %s ORDER BY version", migrationsTable), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", migrationsTable, err)
+	}
+	applied := make([]appliedMigration, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		appliedAt, _ := valueTime(row[3])
+		applied = append(applied, appliedMigration{
+			Version:   valueInt64(row[0]),
+			Name:      valueString(row[1]),
+			Checksum:  valueString(row[2]),
+			AppliedAt: appliedAt,
+		})
+	}
+	return applied, nil
+}
+
+// createMigrationsTableStatement returns the DDL creating the migrations table if it is missing
+func createMigrationsTableStatement() string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  version BIGINT NOT NULL PRIMARY KEY,
+  name VARCHAR(255) NOT NULL,
+  checksum VARCHAR(64) NOT NULL,
+  applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`, migrationsTable)
+}
+
+// planMigrations selects the migrations to run. Up runs pending migrations in version order,
+// down reverts applied migrations newest first. target is inclusive for up and exclusive for
+// down; a negative target means no target. steps limits the count when positive.
+func planMigrations(all []*migration, applied []appliedMigration, direction string, target int64, steps int) ([]*migration, error) {
+	isApplied := make(map[int64]bool, len(applied))
+	for _, a := range applied {
+		isApplied[a.Version] = true
+	}
+
+	var plan []*migration
+	switch direction {
+	case "up":
+		for _, m := range all {
+			if isApplied[m.Version] || (target >= 0 && m.Version > target) {
+				continue
+			}
+			plan = append(plan, m)
+		}
+	case "down":
+		byVersion := make(map[int64]*migration, len(all))
+		for _, m := range all {
+			byVersion[m.Version] = m
+		}
+		if target < 0 && steps <= 0 {
+			steps = 1
+		}
+		for i := len(applied) - 1; i >= 0; i-- {
+			version := applied[i].Version
+			if (target >= 0 && version <= target) || (steps > 0 && len(plan) == steps) {
+				break
+			}
+			m, ok := byVersion[version]
+			if !ok {
+				return nil, fmt.Errorf("applied migration %d_%s has no file in the migrations directory", version, applied[i].Name)
+			}
+			if m.DownPath == "" {
+				return nil, fmt.Errorf("migration %d_%s has no down script", m.Version, m.Name)
+			}
+			plan = append(plan, m)
+		}
+	default:
+		return nil, fmt.Errorf("invalid direction: %s (expected up or down)", direction)
+	}
+
+	if steps > 0 && len(plan) > steps {
+		plan = plan[:steps]
+	}
+	return plan, nil
+}
+
+// migrationStatements returns the statements of one migration step, including the bookkeeping
+// statement that records it, with the parameters of each statement
+func migrationStatements(m *migration, direction, dbType string) ([]string, [][]interface{}, error) {
+	script, err := m.script(direction)
+	if err != nil {
+		return nil, nil, err
+	}
+	statements, err := splitSQLStatements(script, strings.ToLower(dbType))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse migration %d_%s: %w", m.Version, m.Name, err)
+	}
+	params := make([][]interface{}, len(statements))
+
+	p := newSQLParams(dbType)
+	if direction == "up" {
+		statements = append(statements, fmt.Sprintf("INSERT INTO %s (version, name, checksum) VALUES (%s, %s, %s)",
+			migrationsTable, p.add(m.Version), p.add(m.Name), p.add(m.Checksum)))
+	} else {
+		statements = append(statements, fmt.Sprintf("DELETE FROM %s WHERE version = %s", migrationsTable, p.add(m.Version)))
+	}
+	params = append(params, p.values)
+	return statements, params, nil
+}
@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestFiles creates a temporary directory with the given files
+func writeTestFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestLoadMigrations(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"0002_add_orders.up.sql":     "CREATE TABLE orders (id INT);",
+		"0002_add_orders.down.sql":   "DROP TABLE orders;",
+		"0001_create_users.sql":      "CREATE TABLE users (id INT);",
+		"README.md":                  "not a migration",
+		"0003_seed_countries.up.sql": "INSERT INTO countries VALUES (1);",
+	})
+
+	migrations, err := loadMigrations(dir)
+	assert.NoError(t, err)
+	assert.Len(t, migrations, 3)
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "create_users", migrations[0].Name)
+	assert.Empty(t, migrations[0].DownPath)
+	assert.Equal(t, filepath.Join(dir, "0002_add_orders.down.sql"), migrations[1].DownPath)
+	assert.Len(t, migrations[2].Checksum, 64)
+
+	_, err = loadMigrations(writeTestFiles(t, map[string]string{"0001_a.down.sql": "SELECT 1;"}))
+	assert.Error(t, err)
+	_, err = loadMigrations(writeTestFiles(t, map[string]string{"0001_a.sql": "", "0001_b.sql": ""}))
+	assert.Error(t, err)
+}
+
+func TestPlanMigrations(t *testing.T) {
+	all := []*migration{
+		{Version: 1, Name: "a", UpPath: "1.up", DownPath: "1.down"},
+		{Version: 2, Name: "b", UpPath: "2.up"},
+		{Version: 3, Name: "c", UpPath: "3.up", DownPath: "3.down"},
+		{Version: 4, Name: "d", UpPath: "4.up", DownPath: "4.down"},
+	}
+	applied := []appliedMigration{{Version: 1}, {Version: 2}, {Version: 3}}
+	versions := func(plan []*migration) []int64 {
+		var v []int64
+		for _, m := range plan {
+			v = append(v, m.Version)
+		}
+		return v
+	}
+
+	plan, err := planMigrations(all, applied[:1], "up", -1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 3, 4}, versions(plan))
+
+	plan, err = planMigrations(all, applied[:1], "up", 3, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, versions(plan))
+
+	plan, err = planMigrations(all, applied, "down", -1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3}, versions(plan))
+
+	// Reverting past migration 2 fails because it has no down script
+	_, err = planMigrations(all, applied, "down", 1, 0)
+	assert.Error(t, err)
+
+	_, err = planMigrations(all, applied, "sideways", -1, 0)
+	assert.Error(t, err)
+}
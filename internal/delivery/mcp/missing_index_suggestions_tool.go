@@ -0,0 +1,518 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// indexSuggestion is a candidate index for a table the workload query reads in full
+type indexSuggestion struct {
+	Table   string
+	Columns []string
+	Scanned float64 // rows the full scan reads
+	Matched float64 // rows its condition keeps
+
+	// Set when the index was costed as a hypothetical index with hypopg
+	Hypothetical bool
+	Used         bool // whether the planner picked the hypothetical index
+	CostBefore   float64
+	CostAfter    float64
+}
+
+// conditionComparison matches a column compared in a plan condition, such as status = 'open'
+// on PostgreSQL or `shop`.`o`.`status` = 'open' on MySQL; ~~ is how PostgreSQL shows LIKE
+var conditionComparison = regexp.MustCompile("(?i)([\\w.\"`]+)\\)?\\s*(<>|!=|!~~|<=|>=|~~\\*?|=|<|>|\\bIN\\b|\\bBETWEEN\\b|\\bLIKE\\b|\\bIS\\b)")
+
+// conditionCast matches the type casts PostgreSQL adds to plan conditions, such as ::text
+var conditionCast = regexp.MustCompile(`::\w+(?:\s+(?:varying|precision))?(?:\[\])?`)
+
+// queryTableReference matches a table named after FROM or JOIN and its alias
+var queryTableReference = regexp.MustCompile("(?i)\\b(?:FROM|JOIN)\\s+([\\w.\"`]+)(?:\\s+(?:AS\\s+)?([\\w\"`]+))?")
+
+// notTableAliases are the keywords that can follow a table name in place of an alias
+var notTableAliases = map[string]bool{
+	"WHERE": true, "JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true, "CROSS": true,
+	"NATURAL": true, "ON": true, "USING": true, "GROUP": true, "ORDER": true, "HAVING": true,
+	"LIMIT": true, "UNION": true, "WINDOW": true, "FOR": true,
+}
+
+// MissingIndexSuggestionsTool handles suggesting indexes for sequential-scan-heavy tables
+type MissingIndexSuggestionsTool struct {
+	BaseToolType
+}
+
+// NewMissingIndexSuggestionsTool creates a new missing index suggestions tool type
+func NewMissingIndexSuggestionsTool() *MissingIndexSuggestionsTool {
+	return &MissingIndexSuggestionsTool{
+		BaseToolType: BaseToolType{
+			name:        "missing_index_suggestions",
+			description: "Find the tables that are mostly read by full scans, and suggest indexes for them. Without a query it lists the tables with the most rows read by sequential scans since statistics were reset, foreign keys without a supporting index on PostgreSQL and the statements that ran without an index on MySQL. Given a workload query it explains the query, proposes an index on the filtered columns of every table read in full (equality columns first, then one range column) and estimates the benefit: on PostgreSQL with the hypopg extension installed each candidate is costed by the planner as a hypothetical index, otherwise the benefit is judged from the share of scanned rows the filter keeps. Nothing is created; use create_index to build a suggestion.",
+		},
+	}
+}
+
+// CreateTool creates a missing index suggestions tool
+func (t *MissingIndexSuggestionsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Suggest indexes for sequential-scan-heavy tables and a workload query"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("query",
+			tools.Description("Workload query to suggest indexes for (optional); it is explained, not run"),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to inspect (optional, leave empty for all user schemas)"),
+		),
+		tools.WithNumber("min_rows",
+			tools.Description("Ignore tables with fewer estimated rows (default: 10000)"),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of tables and statements to list per section (default: 20)"),
+		),
+	)
+}
+
+// HandleRequest handles missing index suggestions tool requests
+func (t *MissingIndexSuggestionsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	query := strings.TrimSpace(input.optionalString("query", ""))
+	schema := input.optionalString("schema", "")
+	minRows := input.intAtLeast("min_rows", 10000, 0)
+	limit := input.intBetween("limit", 20, 1, 1000)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Suggesting missing indexes for database %s, schema %s", targetDbID, schema)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for missing index suggestions: %s", dbType)
+	}
+	advisor, err := dialect.IndexAdvisor()
+	if err != nil {
+		return nil, unsupportedAdminError("missing index suggestions", dbType, err)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Missing Index Suggestions for Database %s\n", targetDbID))
+
+	if query != "" {
+		plan, err := loadQueryPlan(ctx, useCase, targetDbID, query)
+		if err != nil {
+			return nil, err
+		}
+		suggestions := suggestIndexes(plan, queryTableAliases(query))
+		hypothetical := false
+		if len(suggestions) > 0 {
+			if hypothetical, err = advisor.estimate(ctx, useCase, targetDbID, query, plan, suggestions); err != nil {
+				return nil, err
+			}
+		}
+		response.WriteString("\n## Suggested Indexes for the Query\n\n")
+		writeIndexSuggestions(&response, dialect, advisor, suggestions, hypothetical)
+	}
+
+	if err := writeReportSections(ctx, useCase, targetDbID, advisor.sections(schema, minRows, limit), &response); err != nil {
+		return nil, err
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// writeIndexSuggestions renders the suggestions with their estimated benefit and the
+// statements for those worth building
+func writeIndexSuggestions(sb *strings.Builder, dialect Dialect, advisor indexAdvisor, suggestions []indexSuggestion, hypothetical bool) {
+	if len(suggestions) == 0 {
+		sb.WriteString("The plan reads no table in full with a filter, so no index is suggested.\n")
+		return
+	}
+
+	sb.WriteString("| Table | Columns | Rows Scanned | Rows Matched | Estimated Benefit |\n")
+	sb.WriteString("|-------|---------|--------------|--------------|-------------------|\n")
+	var statements []string
+	for _, s := range suggestions {
+		benefit, worthwhile := s.benefit()
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+			s.Table, strings.Join(s.Columns, ", "), formatPlanRows(s.Scanned), formatPlanRows(s.Matched), benefit))
+		if !worthwhile {
+			continue
+		}
+		statement, err := createIndexStatement(dialect, indexBuildSpec{
+			Name:    defaultIndexName(s.Table, s.Columns),
+			Table:   s.Table,
+			Columns: s.Columns,
+			Online:  true,
+		})
+		if err == nil {
+			statements = append(statements, statement+";")
+		}
+	}
+
+	if hypothetical {
+		sb.WriteString("\nBenefit costed by the planner with hypothetical indexes (hypopg).\n")
+	} else {
+		sb.WriteString("\nBenefit judged from the share of scanned rows the filter keeps" + advisor.uncostedNote() + ".\n")
+	}
+	if len(statements) > 0 {
+		sb.WriteString(fmt.Sprintf("\n```sql\n%s\n```\n", strings.Join(statements, "\n")))
+		sb.WriteString("\nBuild a suggestion with create_index, then check the new plan with compare_plans.\n")
+	}
+}
+
+// benefit describes the estimated benefit of the suggestion and whether it is worth building
+func (s indexSuggestion) benefit() (string, bool) {
+	if s.Hypothetical {
+		if !s.Used || s.CostAfter >= s.CostBefore {
+			return "none: the planner would not use it", false
+		}
+		return fmt.Sprintf("cost %.2f -> %.2f (-%.0f%%)", s.CostBefore, s.CostAfter, 100*(s.CostBefore-s.CostAfter)/s.CostBefore), true
+	}
+	if s.Scanned <= 0 {
+		return "unknown: the table has no row estimate", true
+	}
+	selectivity := 100 * s.Matched / s.Scanned
+	switch {
+	case selectivity <= 1:
+		return fmt.Sprintf("high: the filter keeps %.2f%% of rows", selectivity), true
+	case selectivity <= 10:
+		return fmt.Sprintf("medium: the filter keeps %.1f%% of rows", selectivity), true
+	default:
+		return fmt.Sprintf("low: the filter keeps %.0f%% of rows, so a full scan may stay cheaper", selectivity), false
+	}
+}
+
+// suggestIndexes proposes an index for every full table scan with a condition. MySQL plans
+// name tables by their alias, which aliases maps back to the table.
+func suggestIndexes(plan *queryPlan, aliases map[string]string) []indexSuggestion {
+	var suggestions []indexSuggestion
+	seen := make(map[string]bool)
+	plan.Root.walk(func(node *planNode, depth int) {
+		if !fullScanNodeTypes[node.NodeType] || node.Relation == "" || node.Condition == "" {
+			return
+		}
+		columns := conditionColumns(node.Condition)
+		if len(columns) == 0 {
+			return
+		}
+		s := indexSuggestion{Table: node.Relation, Columns: columns, Matched: node.Rows}
+		if plan.Format.examinedRows {
+			if table, ok := aliases[strings.ToLower(node.Relation)]; ok {
+				s.Table = table
+			}
+			// MySQL estimates the rows examined; the condition keeps the filtered percentage
+			s.Scanned = node.Rows
+			s.Matched = node.Rows * node.Filtered / 100
+		}
+		key := strings.ToLower(s.Table + "(" + strings.Join(columns, ",") + ")")
+		if !seen[key] {
+			seen[key] = true
+			suggestions = append(suggestions, s)
+		}
+	})
+	return suggestions
+}
+
+// conditionColumns returns the columns a plan condition compares, equality comparisons first
+// and then the first range comparison, the order a B-tree index can use them in. Inequality
+// comparisons cannot use an index and are skipped.
+func conditionColumns(condition string) []string {
+	var equality []string
+	var ranged string
+	seen := make(map[string]bool)
+	for _, m := range conditionComparison.FindAllStringSubmatch(conditionCast.ReplaceAllString(condition, ""), -1) {
+		parts := strings.Split(strings.NewReplacer("\"", "", "`", "").Replace(m[1]), ".")
+		column := parts[len(parts)-1]
+		upper := strings.ToUpper(column)
+		if column == "" || (column[0] >= '0' && column[0] <= '9') || upper == "NOT" || upper == "AND" || upper == "OR" || upper == "NULL" {
+			continue
+		}
+		switch op := strings.ToUpper(m[2]); op {
+		case "<>", "!=", "!~~":
+		case "=", "IN", "IS":
+			if !seen[column] {
+				seen[column] = true
+				equality = append(equality, column)
+			}
+		default:
+			if ranged == "" {
+				ranged = column
+			}
+		}
+	}
+	if ranged != "" && !seen[ranged] {
+		equality = append(equality, ranged)
+	}
+	return equality
+}
+
+// queryTableAliases maps the aliases and names of the tables a query reads to their names
+func queryTableAliases(query string) map[string]string {
+	aliases := make(map[string]string)
+	unquote := strings.NewReplacer("\"", "", "`", "")
+	for _, m := range queryTableReference.FindAllStringSubmatch(query, -1) {
+		table := unquote.Replace(m[1])
+		_, name := splitQualifiedName(table)
+		aliases[strings.ToLower(name)] = table
+		if alias := unquote.Replace(m[2]); alias != "" && !notTableAliases[strings.ToUpper(alias)] {
+			aliases[strings.ToLower(alias)] = table
+		}
+	}
+	return aliases
+}
+
+// indexAdvisor is the engine-specific part of suggesting missing indexes
+type indexAdvisor interface {
+	// sections returns the report of the tables read mostly by full scans and the foreign
+	// keys without an index
+	sections(schema string, minRows, limit int) []reportSection
+	// estimate fills in the rows each suggestion scans and, where the engine can, the cost of
+	// the query with the suggested index; it reports whether the suggestions were costed
+	estimate(ctx context.Context, useCase UseCaseProvider, dbID, query string, plan *queryPlan, suggestions []indexSuggestion) (bool, error)
+	// uncostedNote completes the explanation of how uncosted suggestions were judged
+	uncostedNote() string
+}
+
+// postgresIndexAdvisor costs suggestions with hypopg's hypothetical indexes when it is installed
+type postgresIndexAdvisor struct{}
+
+func (postgresIndexAdvisor) sections(schema string, minRows, limit int) []reportSection {
+	return postgresMissingIndexSections(schema, minRows, limit)
+}
+
+func (postgresIndexAdvisor) estimate(ctx context.Context, useCase UseCaseProvider, dbID, query string, plan *queryPlan, suggestions []indexSuggestion) (bool, error) {
+	hypopg, err := hasHypopg(ctx, useCase, dbID)
+	if err != nil {
+		return false, err
+	}
+	for i := range suggestions {
+		s := &suggestions[i]
+		if s.Scanned, err = postgresTableRows(ctx, useCase, dbID, s.Table); err != nil {
+			return false, err
+		}
+		if hypopg {
+			if err := costHypotheticalIndex(ctx, useCase, dbID, query, plan.TotalCost, s); err != nil {
+				return false, err
+			}
+		}
+	}
+	return hypopg, nil
+}
+
+func (postgresIndexAdvisor) uncostedNote() string {
+	return "; install the hypopg extension to have the planner cost each index"
+}
+
+// mysqlIndexAdvisor judges suggestions from the rows of the plan alone
+type mysqlIndexAdvisor struct{}
+
+func (mysqlIndexAdvisor) sections(schema string, minRows, limit int) []reportSection {
+	return mysqlMissingIndexSections(schema, minRows, limit)
+}
+
+func (mysqlIndexAdvisor) estimate(context.Context, UseCaseProvider, string, string, *queryPlan, []indexSuggestion) (bool, error) {
+	return false, nil
+}
+
+func (mysqlIndexAdvisor) uncostedNote() string { return "" }
+
+// hasHypopg reports whether the hypopg extension is installed in the database
+func hasHypopg(ctx context.Context, useCase UseCaseProvider, dbID string) (bool, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, "SELECT 1 FROM pg_extension WHERE extname = 'hypopg'", nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for hypopg: %w", err)
+	}
+	return len(result.Rows) > 0, nil
+}
+
+// postgresTableRows returns the row estimate of a table from its statistics
+func postgresTableRows(ctx context.Context, useCase UseCaseProvider, dbID, table string) (float64, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, "SELECT reltuples FROM pg_class WHERE oid = to_regclass($1)", []interface{}{table})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get row estimate of %s: %w", table, err)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) == 0 {
+		return 0, nil
+	}
+	return valueFloat64(result.Rows[0][0]), nil
+}
+
+// costHypotheticalIndex explains the query with the suggested index created as a hypothetical
+// index. Hypothetical indexes only exist in the session that creates them, so the index is
+// created, the query explained through query_to_xml and the index dropped in one statement.
+func costHypotheticalIndex(ctx context.Context, useCase UseCaseProvider, dbID, query string, costBefore float64, s *indexSuggestion) error {
+	statement, err := createIndexStatement(dialects["postgres"], indexBuildSpec{
+		Name:    defaultIndexName(s.Table, s.Columns),
+		Table:   s.Table,
+		Columns: s.Columns,
+	})
+	if err != nil {
+		return err
+	}
+	explain, err := explainQuery("postgres", query)
+	if err != nil {
+		return err
+	}
+	result, err := useCase.ExecuteQuery(ctx, dbID, getHypotheticalPlanQuery(), []interface{}{explain, statement})
+	if err != nil {
+		return fmt.Errorf("failed to cost hypothetical index on %s: %w", s.Table, err)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) == 0 {
+		return fmt.Errorf("failed to cost hypothetical index on %s: no plan returned", s.Table)
+	}
+	plan, err := parseQueryPlan("postgres", html.UnescapeString(valueString(result.Rows[0][0])))
+	if err != nil {
+		return err
+	}
+
+	s.Hypothetical = true
+	s.CostBefore = costBefore
+	s.CostAfter = plan.TotalCost
+	for _, index := range plan.indexesUsed() {
+		// hypopg names its indexes <oid>btree_table_columns
+		if strings.HasPrefix(index, "<") {
+			s.Used = true
+		}
+	}
+	return nil
+}
+
+// getHypotheticalPlanQuery returns a query that creates the hypothetical index $2, returns the
+// JSON plan of the EXPLAIN statement $1 and drops the index again
+func getHypotheticalPlanQuery() string {
+	return `
+SELECT p.plan, hypopg_drop_index(p.indexrelid)
+FROM (
+    SELECT
+        h.indexrelid,
+        (xpath('/row/QUERY_x0020_PLAN/text()', query_to_xml($1, true, true, '')))[1]::text AS plan
+    FROM hypopg_create_index($2) h
+    OFFSET 0
+) p;`
+}
+
+// postgresMissingIndexSections returns the tables read mostly by sequential scans and the
+// foreign keys without an index whose leading columns are the key columns
+func postgresMissingIndexSections(schema string, minRows, limit int) []reportSection {
+	return []reportSection{
+		{
+			title: "Tables Read Mostly by Sequential Scans",
+			query: `
+SELECT
+    s.schemaname AS schema_name,
+    s.relname AS table_name,
+    s.n_live_tup AS live_rows,
+    s.seq_scan AS seq_scans,
+    s.seq_tup_read AS rows_read_by_seq_scans,
+    s.seq_tup_read / NULLIF(s.seq_scan, 0) AS rows_per_seq_scan,
+    COALESCE(s.idx_scan, 0) AS index_scans
+FROM pg_stat_user_tables s
+WHERE ($1 = '' OR s.schemaname = $1)
+  AND s.n_live_tup >= $2
+  AND s.seq_scan > COALESCE(s.idx_scan, 0)
+ORDER BY s.seq_tup_read DESC
+LIMIT $3`,
+			params: []interface{}{schema, minRows, limit},
+		},
+		{
+			title: "Foreign Keys Without an Index",
+			query: `
+SELECT
+    n.nspname AS schema_name,
+    c.relname AS table_name,
+    fk.conname AS foreign_key,
+    (SELECT string_agg(a.attname, ', ' ORDER BY k.ord)
+     FROM unnest(fk.conkey) WITH ORDINALITY k(attnum, ord)
+     JOIN pg_attribute a ON a.attrelid = fk.conrelid AND a.attnum = k.attnum) AS columns,
+    COALESCE(s.seq_scan, 0) AS seq_scans
+FROM pg_constraint fk
+JOIN pg_class c ON c.oid = fk.conrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_stat_user_tables s ON s.relid = c.oid
+WHERE fk.contype = 'f'
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND ($1 = '' OR n.nspname = $1)
+  AND NOT EXISTS (
+      SELECT 1 FROM pg_index i
+      WHERE i.indrelid = fk.conrelid
+        AND (string_to_array(i.indkey::text, ' ')::int2[])[1:array_length(fk.conkey, 1)] @> fk.conkey
+  )
+ORDER BY seq_scans DESC
+LIMIT $2`,
+			params: []interface{}{schema, limit},
+			warn: func(row []interface{}) []string {
+				if len(row) < 4 {
+					return nil
+				}
+				return []string{fmt.Sprintf("Foreign key %s on %s has no index on (%s); joins on it and deletes from the referenced table scan the whole table.",
+					valueString(row[2]), qualifiedName(valueString(row[0]), valueString(row[1])), valueString(row[3]))}
+			},
+		},
+	}
+}
+
+// mysqlMissingIndexSections returns the tables read mostly by full scans and the statement
+// digests that ran without an index, both from performance_schema
+func mysqlMissingIndexSections(schema string, minRows, limit int) []reportSection {
+	return []reportSection{
+		{
+			title: "Tables Read Mostly by Full Scans",
+			query: `
+SELECT
+    u.OBJECT_SCHEMA AS schema_name,
+    u.OBJECT_NAME AS table_name,
+    t.TABLE_ROWS AS estimated_rows,
+    u.COUNT_READ AS rows_read_by_full_scans,
+    COALESCE(ix.index_reads, 0) AS rows_read_by_indexes
+FROM performance_schema.table_io_waits_summary_by_index_usage u
+JOIN information_schema.TABLES t ON t.TABLE_SCHEMA = u.OBJECT_SCHEMA AND t.TABLE_NAME = u.OBJECT_NAME
+LEFT JOIN (
+    SELECT OBJECT_SCHEMA, OBJECT_NAME, SUM(COUNT_READ) AS index_reads
+    FROM performance_schema.table_io_waits_summary_by_index_usage
+    WHERE INDEX_NAME IS NOT NULL
+    GROUP BY OBJECT_SCHEMA, OBJECT_NAME
+) ix ON ix.OBJECT_SCHEMA = u.OBJECT_SCHEMA AND ix.OBJECT_NAME = u.OBJECT_NAME
+WHERE u.INDEX_NAME IS NULL
+  AND u.OBJECT_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+  AND (? = '' OR u.OBJECT_SCHEMA = ?)
+  AND t.TABLE_ROWS >= ?
+  AND u.COUNT_READ > COALESCE(ix.index_reads, 0)
+ORDER BY u.COUNT_READ DESC
+LIMIT ?`,
+			params: []interface{}{schema, schema, minRows, limit},
+		},
+		{
+			title: "Statements Run Without an Index",
+			query: `
+SELECT
+    COALESCE(SCHEMA_NAME, '') AS schema_name,
+    DIGEST_TEXT AS statement,
+    COUNT_STAR AS executions,
+    SUM_NO_INDEX_USED AS executions_without_index,
+    SUM_ROWS_EXAMINED AS rows_examined,
+    SUM_ROWS_SENT AS rows_sent
+FROM performance_schema.events_statements_summary_by_digest
+WHERE SUM_NO_INDEX_USED > 0
+  AND DIGEST_TEXT IS NOT NULL
+  AND (? = '' OR SCHEMA_NAME = ?)
+ORDER BY SUM_ROWS_EXAMINED DESC
+LIMIT ?`,
+			params: []interface{}{schema, schema, limit},
+		},
+	}
+}
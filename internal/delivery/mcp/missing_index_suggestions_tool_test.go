@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestConditionColumns(t *testing.T) {
+	assert.Equal(t, []string{"status", "customer_id", "created_at"},
+		conditionColumns("((created_at > now()) AND (status = 'open'::text) AND (customer_id = ANY ('{1,2}'::integer[])))"))
+	assert.Equal(t, []string{"status"}, conditionColumns("((`shop`.`o`.`status` = 'open') and (`shop`.`o`.`note` <> ''))"))
+	assert.Equal(t, []string{"name"}, conditionColumns("((name)::text ~~ 'ab%'::text)"))
+	assert.Empty(t, conditionColumns("(note <> ''::text)"))
+}
+
+func TestQueryTableAliases(t *testing.T) {
+	aliases := queryTableAliases("SELECT * FROM shop.orders o JOIN customers AS c ON c.id = o.customer_id WHERE o.status = 'open'")
+	assert.Equal(t, "shop.orders", aliases["o"])
+	assert.Equal(t, "shop.orders", aliases["orders"])
+	assert.Equal(t, "customers", aliases["c"])
+	assert.NotContains(t, aliases, "where")
+}
+
+func TestMissingIndexSuggestionsToolHypopg(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"EXPLAIN (FORMAT JSON)": {Rows: [][]interface{}{{`[{"Plan": {"Node Type": "Seq Scan", "Relation Name": "orders", "Total Cost": 2000.0, "Plan Rows": 50, "Filter": "(status = 'open'::text)"}}]`}}},
+			"pg_extension":          {Rows: [][]interface{}{{int64(1)}}},
+			"reltuples":             {Rows: [][]interface{}{{float64(100000)}}},
+			"hypopg_create_index": {Rows: [][]interface{}{{
+				`[{"Plan": {"Node Type": "Index Scan", "Relation Name": "orders", "Index Name": "&lt;13543&gt;btree_orders_status", "Total Cost": 120.0, "Plan Rows": 50}}]`, true,
+			}}},
+			"rows_per_seq_scan": {Columns: []string{"schema_name", "table_name"}, Rows: [][]interface{}{{"public", "orders"}}},
+		},
+	}
+
+	result, err := NewMissingIndexSuggestionsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "query": "SELECT * FROM orders WHERE status = 'open'"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| orders | status | 100000 | 50 | cost 2000.00 -> 120.00 (-94%) |")
+	assert.Contains(t, text, `CREATE INDEX CONCURRENTLY "idx_orders_status" ON "orders" ("status");`)
+	assert.Contains(t, text, "hypothetical indexes (hypopg)")
+	assert.Contains(t, text, "## Tables Read Mostly by Sequential Scans")
+	assert.Contains(t, text, "## Foreign Keys Without an Index\n\nNone.")
+}
+
+func TestMissingIndexSuggestionsToolMySQLHeuristic(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"EXPLAIN FORMAT=JSON": {Rows: [][]interface{}{{`{"query_block": {"cost_info": {"query_cost": "1000.00"},
+  "nested_loop": [
+    {"table": {"table_name": "o", "access_type": "ALL", "rows_examined_per_scan": 100000, "filtered": "0.50", "attached_condition": "(` + "`shop`.`o`.`status` = 'open'" + `)"}},
+    {"table": {"table_name": "c", "access_type": "ALL", "rows_examined_per_scan": 1000, "filtered": "50.00", "attached_condition": "(` + "`shop`.`c`.`region` > 3" + `)"}}
+  ]}}`}}},
+		},
+	}
+
+	result, err := NewMissingIndexSuggestionsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "my1", "query": "SELECT * FROM orders o JOIN customers c ON c.id = o.customer_id WHERE o.status = 'open'"},
+	}, "my1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| orders | status | 100000 | 500 | high: the filter keeps 0.50% of rows |")
+	assert.Contains(t, text, "| customers | region | 1000 | 500 | low: the filter keeps 50% of rows, so a full scan may stay cheaper |")
+	assert.Contains(t, text, "CREATE INDEX `idx_orders_status` ON `orders` (`status`) ALGORITHM=INPLACE LOCK=NONE;")
+	assert.NotContains(t, text, "idx_customers_region")
+	assert.Contains(t, text, "## Statements Run Without an Index")
+}
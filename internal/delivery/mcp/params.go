@@ -0,0 +1,251 @@
+package mcp
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// toolParams binds the parameters of a tool call. Accessors return the value or its default
+// and remember the first invalid parameter, so handlers read everything they need and then
+// check err once:
+//
+//	input := newToolParams(request.Parameters)
+//	targetDbID := input.requiredString("database")
+//	limit := input.intBetween("limit", 100, 1, 1000)
+//	if err := input.err(); err != nil {
+//		return nil, err
+//	}
+//
+// Missing and null parameters, and empty strings, take the default. A parameter of the wrong
+// type is an error rather than silently ignored.
+type toolParams struct {
+	values  map[string]interface{}
+	invalid error
+}
+
+// newToolParams creates a binder for the parameters of a tool call
+func newToolParams(values map[string]interface{}) *toolParams {
+	return &toolParams{values: values}
+}
+
+// err returns the error of the first invalid parameter
+func (p *toolParams) err() error {
+	return p.invalid
+}
+
+// fail records an error unless an earlier parameter already failed
+func (p *toolParams) fail(format string, args ...interface{}) {
+	if p.invalid == nil {
+		p.invalid = fmt.Errorf(format, args...)
+	}
+}
+
+// has reports whether a parameter was passed with a non-null value
+func (p *toolParams) has(name string) bool {
+	return p.values[name] != nil
+}
+
+// raw returns the unconverted value of a parameter, for parameters with a structure of their own
+func (p *toolParams) raw(name string) interface{} {
+	return p.values[name]
+}
+
+// requiredString returns a string parameter that must be passed and not be blank
+func (p *toolParams) requiredString(name string) string {
+	value, ok := p.values[name].(string)
+	if !ok || strings.TrimSpace(value) == "" {
+		p.fail("%s parameter must be a non-empty string", name)
+	}
+	return value
+}
+
+// optionalString returns a string parameter, or def when it is missing or empty
+func (p *toolParams) optionalString(name, def string) string {
+	if !p.has(name) {
+		return def
+	}
+	value, ok := p.values[name].(string)
+	if !ok {
+		p.fail("%s parameter must be a string", name)
+		return def
+	}
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// choice returns a string parameter in lower case that must be one of allowed, or def when it is missing
+func (p *toolParams) choice(name, def string, allowed ...string) string {
+	value := strings.ToLower(p.optionalString(name, def))
+	for _, option := range allowed {
+		if value == option {
+			return value
+		}
+	}
+	if value != def {
+		p.fail("%s parameter must be %s", name, joinChoices(allowed))
+	}
+	return def
+}
+
+// location returns a time zone parameter given as an IANA name such as UTC or Europe/Berlin,
+// or nil when it is missing
+func (p *toolParams) location(name string) *time.Location {
+	value := p.optionalString(name, "")
+	if value == "" {
+		return nil
+	}
+	location, err := time.LoadLocation(value)
+	if err != nil {
+		p.fail("%s parameter must be an IANA time zone such as UTC or Europe/Berlin", name)
+		return nil
+	}
+	return location
+}
+
+// requiredChoice returns a string parameter in lower case that must be passed and be one of allowed
+func (p *toolParams) requiredChoice(name string, allowed ...string) string {
+	value := strings.ToLower(p.optionalString(name, ""))
+	for _, option := range allowed {
+		if value == option {
+			return value
+		}
+	}
+	p.fail("%s parameter must be %s", name, joinChoices(allowed))
+	return ""
+}
+
+// optionalBool returns a boolean parameter, or def when it is missing
+func (p *toolParams) optionalBool(name string, def bool) bool {
+	if !p.has(name) {
+		return def
+	}
+	value, ok := p.values[name].(bool)
+	if !ok {
+		p.fail("%s parameter must be a boolean", name)
+		return def
+	}
+	return value
+}
+
+// optionalFloat returns a number parameter, or def when it is missing
+func (p *toolParams) optionalFloat(name string, def float64) float64 {
+	if !p.has(name) {
+		return def
+	}
+	switch value := p.values[name].(type) {
+	case float64:
+		return value
+	case int:
+		return float64(value)
+	case int64:
+		return float64(value)
+	}
+	p.fail("%s parameter must be a number", name)
+	return def
+}
+
+// optionalInt returns a whole number parameter, or def when it is missing
+func (p *toolParams) optionalInt(name string, def int) int {
+	if !p.has(name) {
+		return def
+	}
+	value := p.optionalFloat(name, float64(def))
+	if value != math.Trunc(value) {
+		p.fail("%s parameter must be a whole number", name)
+		return def
+	}
+	return int(value)
+}
+
+// intBetween returns a whole number parameter within [min, max], or def when it is missing
+func (p *toolParams) intBetween(name string, def, min, max int) int {
+	if !p.has(name) {
+		return def
+	}
+	value := p.optionalInt(name, def)
+	if value < min || value > max {
+		p.fail("%s parameter must be between %d and %d", name, min, max)
+		return def
+	}
+	return value
+}
+
+// intAtLeast returns a whole number parameter of at least min, or def when it is missing
+func (p *toolParams) intAtLeast(name string, def, min int) int {
+	if !p.has(name) {
+		return def
+	}
+	value := p.optionalInt(name, def)
+	if value < min {
+		p.fail("%s parameter must be at least %d", name, min)
+		return def
+	}
+	return value
+}
+
+// list returns an array parameter of any values, or nil when it is missing
+func (p *toolParams) list(name string) []interface{} {
+	if !p.has(name) {
+		return nil
+	}
+	values, ok := p.values[name].([]interface{})
+	if !ok {
+		p.fail("%s parameter must be an array", name)
+	}
+	return values
+}
+
+// stringList returns an array of strings parameter without its empty items
+func (p *toolParams) stringList(name string) []string {
+	values, err := parseStringArray(p.values[name], name)
+	if err != nil {
+		p.fail("%s", err)
+	}
+	return values
+}
+
+// object returns an object parameter, or nil when it is missing
+func (p *toolParams) object(name string) map[string]interface{} {
+	if !p.has(name) {
+		return nil
+	}
+	value, ok := p.values[name].(map[string]interface{})
+	if !ok {
+		p.fail("%s parameter must be an object", name)
+	}
+	return value
+}
+
+// objectList returns an array of objects parameter, or nil when it is missing
+func (p *toolParams) objectList(name string) []map[string]interface{} {
+	if !p.has(name) {
+		return nil
+	}
+	items, ok := p.values[name].([]interface{})
+	if !ok {
+		p.fail("%s parameter must be an array of objects", name)
+		return nil
+	}
+	values := make([]map[string]interface{}, 0, len(items))
+	for i, item := range items {
+		value, ok := item.(map[string]interface{})
+		if !ok {
+			p.fail("%s item %d must be an object", name, i+1)
+			return nil
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// joinChoices lists allowed values as "a, b or c"
+func joinChoices(allowed []string) string {
+	if len(allowed) <= 1 {
+		return strings.Join(allowed, "")
+	}
+	return strings.Join(allowed[:len(allowed)-1], ", ") + " or " + allowed[len(allowed)-1]
+}
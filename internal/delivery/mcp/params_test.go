@@ -0,0 +1,153 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolParamsDefaults(t *testing.T) {
+	input := newToolParams(map[string]interface{}{
+		"database": "pg1",
+		"schema":   "",
+		"where":    nil,
+	})
+
+	assert.Equal(t, "pg1", input.requiredString("database"))
+	assert.Equal(t, "public", input.optionalString("schema", "public"))
+	assert.Equal(t, "", input.optionalString("where", ""))
+	assert.Equal(t, 50, input.intAtLeast("limit", 50, 1))
+	assert.Equal(t, 100, input.intBetween("limit", 100, 1, 1000))
+	assert.Equal(t, 2.5, input.optionalFloat("ratio", 2.5))
+	assert.True(t, input.optionalBool("online", true))
+	assert.Equal(t, "tree", input.choice("mode", "tree", "tree", "json"))
+	assert.Nil(t, input.stringList("tables"))
+	assert.Nil(t, input.list("params"))
+	assert.Nil(t, input.object("values"))
+	assert.Nil(t, input.objectList("joins"))
+	assert.False(t, input.has("where"))
+	assert.NoError(t, input.err())
+}
+
+func TestToolParamsValues(t *testing.T) {
+	input := newToolParams(map[string]interface{}{
+		"limit":   float64(25),
+		"steps":   3,
+		"ratio":   float64(0.5),
+		"online":  false,
+		"mode":    "JSON",
+		"tables":  []interface{}{"users", "", "orders"},
+		"params":  []interface{}{1, "a"},
+		"values":  map[string]interface{}{"name": "x"},
+		"filters": []interface{}{map[string]interface{}{"column": "id"}},
+	})
+
+	assert.Equal(t, 25, input.intBetween("limit", 100, 1, 1000))
+	assert.Equal(t, 3, input.intAtLeast("steps", 0, 1))
+	assert.Equal(t, 0.5, input.optionalFloat("ratio", 1))
+	assert.False(t, input.optionalBool("online", true))
+	assert.Equal(t, "json", input.choice("mode", "tree", "tree", "json"))
+	assert.Equal(t, "json", input.requiredChoice("mode", "tree", "json"))
+	assert.Equal(t, []string{"users", "orders"}, input.stringList("tables"))
+	assert.Equal(t, []interface{}{1, "a"}, input.list("params"))
+	assert.Equal(t, map[string]interface{}{"name": "x"}, input.object("values"))
+	assert.Len(t, input.objectList("filters"), 1)
+	assert.NoError(t, input.err())
+}
+
+func TestToolParamsErrors(t *testing.T) {
+	cases := map[string]struct {
+		params map[string]interface{}
+		read   func(*toolParams)
+		err    string
+	}{
+		"missing required": {
+			params: map[string]interface{}{},
+			read:   func(p *toolParams) { p.requiredString("database") },
+			err:    "database parameter must be a non-empty string",
+		},
+		"blank required": {
+			params: map[string]interface{}{"query": "  "},
+			read:   func(p *toolParams) { p.requiredString("query") },
+			err:    "query parameter must be a non-empty string",
+		},
+		"unknown time zone": {
+			params: map[string]interface{}{"timezone": "Mars/Olympus_Mons"},
+			read:   func(p *toolParams) { p.location("timezone") },
+			err:    "timezone parameter must be an IANA time zone such as UTC or Europe/Berlin",
+		},
+		"wrong string type": {
+			params: map[string]interface{}{"schema": 1.0},
+			read:   func(p *toolParams) { p.optionalString("schema", "") },
+			err:    "schema parameter must be a string",
+		},
+		"wrong bool type": {
+			params: map[string]interface{}{"dry_run": "yes"},
+			read:   func(p *toolParams) { p.optionalBool("dry_run", false) },
+			err:    "dry_run parameter must be a boolean",
+		},
+		"wrong number type": {
+			params: map[string]interface{}{"limit": "10"},
+			read:   func(p *toolParams) { p.optionalInt("limit", 0) },
+			err:    "limit parameter must be a number",
+		},
+		"fractional int": {
+			params: map[string]interface{}{"limit": 1.5},
+			read:   func(p *toolParams) { p.optionalInt("limit", 0) },
+			err:    "limit parameter must be a whole number",
+		},
+		"below minimum": {
+			params: map[string]interface{}{"limit": 0.0},
+			read:   func(p *toolParams) { p.intAtLeast("limit", 10, 1) },
+			err:    "limit parameter must be at least 1",
+		},
+		"out of range": {
+			params: map[string]interface{}{"statistics_target": 20000.0},
+			read:   func(p *toolParams) { p.intBetween("statistics_target", 0, 1, 10000) },
+			err:    "statistics_target parameter must be between 1 and 10000",
+		},
+		"unknown choice": {
+			params: map[string]interface{}{"format": "svg"},
+			read:   func(p *toolParams) { p.choice("format", "mermaid", "mermaid", "dot") },
+			err:    "format parameter must be mermaid or dot",
+		},
+		"missing required choice": {
+			params: map[string]interface{}{},
+			read:   func(p *toolParams) { p.requiredChoice("template", "read_only", "read_write", "all") },
+			err:    "template parameter must be read_only, read_write or all",
+		},
+		"wrong string list": {
+			params: map[string]interface{}{"tables": []interface{}{"users", 1.0}},
+			read:   func(p *toolParams) { p.stringList("tables") },
+			err:    "tables parameter must be an array of strings",
+		},
+		"wrong list": {
+			params: map[string]interface{}{"params": "1"},
+			read:   func(p *toolParams) { p.list("params") },
+			err:    "params parameter must be an array",
+		},
+		"wrong object": {
+			params: map[string]interface{}{"values": []interface{}{}},
+			read:   func(p *toolParams) { p.object("values") },
+			err:    "values parameter must be an object",
+		},
+		"wrong object item": {
+			params: map[string]interface{}{"joins": []interface{}{map[string]interface{}{}, "users"}},
+			read:   func(p *toolParams) { p.objectList("joins") },
+			err:    "joins item 2 must be an object",
+		},
+	}
+
+	for name, c := range cases {
+		input := newToolParams(c.params)
+		c.read(input)
+		assert.EqualError(t, input.err(), c.err, name)
+	}
+}
+
+func TestToolParamsKeepsFirstError(t *testing.T) {
+	input := newToolParams(map[string]interface{}{"limit": -1.0})
+	input.requiredString("database")
+	input.intAtLeast("limit", 10, 1)
+	assert.EqualError(t, input.err(), "database parameter must be a non-empty string")
+}
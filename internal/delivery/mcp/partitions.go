@@ -0,0 +1,426 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// partitionNow returns the current time; tests replace it to get stable partition ranges
+var partitionNow = time.Now
+
+// postgresRangeBound matches the range bound PostgreSQL reports for a partition
+var postgresRangeBound = regexp.MustCompile(`FROM \('([^']*)'\) TO \('([^']*)'\)`)
+
+// toDaysEpoch is TO_DAYS('1970-01-01') in MySQL
+const toDaysEpoch = 719528
+
+// partitionIntervals lists the supported partition periods
+var partitionIntervals = []string{"day", "week", "month", "year"}
+
+// partitionInfo is one partition of a range-partitioned table
+type partitionInfo struct {
+	Name     string
+	Bound    string
+	From     time.Time // zero when the partition has no lower bound
+	To       time.Time
+	HasRange bool // From/To were recognized as time bounds
+	Default  bool // DEFAULT partition on PostgreSQL, MAXVALUE partition on MySQL
+	Rows     int64
+}
+
+// partitionedTable describes a range-partitioned table and its partitions
+type partitionedTable struct {
+	Table      string
+	Key        string
+	KeyFunc    string // MySQL only: columns, to_days, unix_timestamp or year
+	Partitions []partitionInfo
+}
+
+// latestBound returns the highest upper bound of the time-range partitions
+func (t *partitionedTable) latestBound() (time.Time, bool) {
+	var latest time.Time
+	found := false
+	for _, p := range t.Partitions {
+		if p.HasRange && p.To.After(latest) {
+			latest, found = p.To, true
+		}
+	}
+	return latest, found
+}
+
+// defaultPartition returns the DEFAULT or MAXVALUE partition, if any
+func (t *partitionedTable) defaultPartition() *partitionInfo {
+	for i := range t.Partitions {
+		if t.Partitions[i].Default {
+			return &t.Partitions[i]
+		}
+	}
+	return nil
+}
+
+// partition returns the partition with the given name
+func (t *partitionedTable) partition(name string) *partitionInfo {
+	for i := range t.Partitions {
+		if strings.EqualFold(t.Partitions[i].Name, name) {
+			return &t.Partitions[i]
+		}
+	}
+	return nil
+}
+
+// partitionManager is the engine-specific part of managing time-range partitions
+type partitionManager interface {
+	// load reads the partition key and partitions of a range-partitioned table
+	load(ctx context.Context, useCase UseCaseProvider, dbID, table string) (*partitionedTable, error)
+	// defaultPrefix returns the prefix of new partition names when none is given
+	defaultPrefix(table string) string
+	// createStatements renders the DDL creating the planned partitions
+	createStatements(pt *partitionedTable, planned []plannedPartition) ([]string, error)
+	// createNotes warns about what can make creating the planned partitions fail
+	createNotes(pt *partitionedTable, planned []plannedPartition) []string
+	// attachStatement renders the DDL attaching a table as the partition of a range
+	attachStatement(table, partition string, from, to time.Time) (string, error)
+	// detachStatement renders the DDL turning a partition back into a table
+	detachStatement(table, partition string, concurrently bool) (string, error)
+	// dropStatements renders the DDL dropping the given partitions
+	dropStatements(pt *partitionedTable, partitions []partitionInfo) []string
+}
+
+// postgresPartitionManager manages declarative range partitions, which are tables of their own
+type postgresPartitionManager struct{}
+
+// load reads the partition key and partitions of a PostgreSQL table
+func (postgresPartitionManager) load(ctx context.Context, useCase UseCaseProvider, dbID, table string) (*partitionedTable, error) {
+	relation := quoteIdentifier("postgres", table)
+	result, err := useCase.ExecuteQuery(ctx, dbID, `SELECT pg_get_partkeydef(to_regclass($1))`, []interface{}{relation})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read partition key: %w", err)
+	}
+	if len(result.Rows) == 0 || valueString(result.Rows[0][0]) == "" {
+		return nil, fmt.Errorf("table %s does not exist or is not partitioned", table)
+	}
+	key := valueString(result.Rows[0][0])
+	if !strings.HasPrefix(strings.ToUpper(key), "RANGE") {
+		return nil, fmt.Errorf("table %s is partitioned by %s; only RANGE partitioning is supported", table, key)
+	}
+
+	result, err = useCase.ExecuteQuery(ctx, dbID, `
+SELECT
+    c.relname,
+    pg_get_expr(c.relpartbound, c.oid) AS bound,
+    c.reltuples::bigint AS row_estimate
+FROM pg_inherits i
+JOIN pg_class c ON c.oid = i.inhrelid
+WHERE i.inhparent = to_regclass($1)
+ORDER BY c.relname`, []interface{}{relation})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+
+	pt := &partitionedTable{Table: table, Key: key}
+	for _, row := range result.Rows {
+		p := partitionInfo{Name: valueString(row[0]), Bound: valueString(row[1]), Rows: valueInt64(row[2])}
+		if p.Bound == "DEFAULT" {
+			p.Default = true
+		} else if m := postgresRangeBound.FindStringSubmatch(p.Bound); m != nil {
+			from, fromOK := valueTime(m[1])
+			to, toOK := valueTime(m[2])
+			p.From, p.To, p.HasRange = from, to, fromOK && toOK
+		}
+		pt.Partitions = append(pt.Partitions, p)
+	}
+	return pt, nil
+}
+
+// defaultPrefix names partitions after their table, since they share its schema
+func (postgresPartitionManager) defaultPrefix(table string) string {
+	_, name := splitQualifiedName(table)
+	return name + "_p"
+}
+
+// createStatements creates each partition as a table in the schema of the parent
+func (postgresPartitionManager) createStatements(pt *partitionedTable, planned []plannedPartition) ([]string, error) {
+	schema, _ := splitQualifiedName(pt.Table)
+	statements := make([]string, len(planned))
+	for i, p := range planned {
+		name := p.Name
+		if schema != "" {
+			name = schema + "." + name
+		}
+		statements[i] = fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM (%s) TO (%s)",
+			quoteIdentifier("postgres", name), quoteIdentifier("postgres", pt.Table), postgresBoundLiteral(p.From), postgresBoundLiteral(p.To))
+	}
+	return statements, nil
+}
+
+func (postgresPartitionManager) createNotes(pt *partitionedTable, planned []plannedPartition) []string {
+	if pt.defaultPartition() == nil || len(planned) == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("Creating a partition fails if the default partition %s already holds rows for its range; move those rows out first.", pt.defaultPartition().Name)}
+}
+
+func (postgresPartitionManager) attachStatement(table, partition string, from, to time.Time) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s ATTACH PARTITION %s FOR VALUES FROM (%s) TO (%s)",
+		quoteIdentifier("postgres", table), quoteIdentifier("postgres", partition), postgresBoundLiteral(from), postgresBoundLiteral(to)), nil
+}
+
+func (postgresPartitionManager) detachStatement(table, partition string, concurrently bool) (string, error) {
+	statement := fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s", quoteIdentifier("postgres", table), quoteIdentifier("postgres", partition))
+	if concurrently {
+		statement += " CONCURRENTLY"
+	}
+	return statement, nil
+}
+
+func (postgresPartitionManager) dropStatements(pt *partitionedTable, partitions []partitionInfo) []string {
+	schema, _ := splitQualifiedName(pt.Table)
+	statements := make([]string, len(partitions))
+	for i, p := range partitions {
+		name := p.Name
+		if schema != "" {
+			name = schema + "." + name
+		}
+		statements[i] = "DROP TABLE IF EXISTS " + quoteIdentifier("postgres", name)
+	}
+	return statements
+}
+
+// mysqlPartitionManager manages RANGE and RANGE COLUMNS partitions, which are altered through
+// their table
+type mysqlPartitionManager struct{}
+
+// load reads the partitions of a MySQL table from information_schema
+func (mysqlPartitionManager) load(ctx context.Context, useCase UseCaseProvider, dbID, table string) (*partitionedTable, error) {
+	schema, name := splitQualifiedName(table)
+	schemaFilter := "TABLE_SCHEMA = DATABASE()"
+	params := []interface{}{name}
+	if schema != "" {
+		schemaFilter = "TABLE_SCHEMA = ?"
+		params = append(params, schema)
+	}
+	result, err := useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT
+    PARTITION_NAME,
+    PARTITION_METHOD,
+    PARTITION_EXPRESSION,
+    PARTITION_DESCRIPTION,
+    TABLE_ROWS
+FROM information_schema.PARTITIONS
+WHERE TABLE_NAME = ? AND %s
+ORDER BY PARTITION_ORDINAL_POSITION`, schemaFilter), params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+	if len(result.Rows) == 0 || result.Rows[0][0] == nil {
+		return nil, fmt.Errorf("table %s does not exist or is not partitioned", table)
+	}
+
+	method := strings.ToUpper(valueString(result.Rows[0][1]))
+	expression := strings.ToLower(strings.ReplaceAll(valueString(result.Rows[0][2]), "`", ""))
+	if method != "RANGE" && method != "RANGE COLUMNS" {
+		return nil, fmt.Errorf("table %s is partitioned by %s; only RANGE partitioning is supported", table, method)
+	}
+	pt := &partitionedTable{Table: table, Key: fmt.Sprintf("%s (%s)", method, expression)}
+	switch {
+	case method == "RANGE COLUMNS":
+		pt.KeyFunc = "columns"
+	case strings.HasPrefix(expression, "to_days("):
+		pt.KeyFunc = "to_days"
+	case strings.HasPrefix(expression, "unix_timestamp("):
+		pt.KeyFunc = "unix_timestamp"
+	case strings.HasPrefix(expression, "year("):
+		pt.KeyFunc = "year"
+	}
+
+	var previous time.Time
+	for _, row := range result.Rows {
+		p := partitionInfo{Name: valueString(row[0]), Bound: valueString(row[3]), Rows: valueInt64(row[4])}
+		if strings.EqualFold(p.Bound, "MAXVALUE") {
+			p.Default = true
+		} else if to, ok := parseMySQLPartitionBound(pt.KeyFunc, p.Bound); ok {
+			// Range partitions start where the previous one ends
+			p.From, p.To, p.HasRange = previous, to, true
+			previous = to
+		}
+		pt.Partitions = append(pt.Partitions, p)
+	}
+	return pt, nil
+}
+
+func (mysqlPartitionManager) defaultPrefix(string) string { return "p" }
+
+// createStatements adds the partitions in one statement, splitting new ranges off the MAXVALUE
+// partition when there is one
+func (mysqlPartitionManager) createStatements(pt *partitionedTable, planned []plannedPartition) ([]string, error) {
+	table := quoteIdentifier("mysql", pt.Table)
+	definitions := make([]string, len(planned))
+	for i, p := range planned {
+		to, err := mysqlBoundLiteral(pt.KeyFunc, p.To)
+		if err != nil {
+			return nil, err
+		}
+		definitions[i] = fmt.Sprintf("PARTITION %s VALUES LESS THAN (%s)", quoteIdentifier("mysql", p.Name), to)
+	}
+	if maxPartition := pt.defaultPartition(); maxPartition != nil {
+		name := quoteIdentifier("mysql", maxPartition.Name)
+		definitions = append(definitions, fmt.Sprintf("PARTITION %s VALUES LESS THAN MAXVALUE", name))
+		return []string{fmt.Sprintf("ALTER TABLE %s REORGANIZE PARTITION %s INTO (\n  %s\n)", table, name, strings.Join(definitions, ",\n  "))}, nil
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s ADD PARTITION (\n  %s\n)", table, strings.Join(definitions, ",\n  "))}, nil
+}
+
+func (mysqlPartitionManager) createNotes(*partitionedTable, []plannedPartition) []string { return nil }
+
+func (mysqlPartitionManager) attachStatement(string, string, time.Time, time.Time) (string, error) {
+	return "", fmt.Errorf("attach is only supported on PostgreSQL; on MySQL use ALTER TABLE ... EXCHANGE PARTITION")
+}
+
+func (mysqlPartitionManager) detachStatement(string, string, bool) (string, error) {
+	return "", fmt.Errorf("detach is only supported on PostgreSQL; on MySQL use ALTER TABLE ... EXCHANGE PARTITION with an empty table")
+}
+
+func (mysqlPartitionManager) dropStatements(pt *partitionedTable, partitions []partitionInfo) []string {
+	names := make([]string, len(partitions))
+	for i, p := range partitions {
+		names[i] = quoteIdentifier("mysql", p.Name)
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s DROP PARTITION %s", quoteIdentifier("mysql", pt.Table), strings.Join(names, ", "))}
+}
+
+// parseMySQLPartitionBound converts a VALUES LESS THAN bound to a time
+func parseMySQLPartitionBound(keyFunc, bound string) (time.Time, bool) {
+	switch keyFunc {
+	case "columns":
+		return valueTime(strings.Trim(bound, "'"))
+	case "to_days", "unix_timestamp", "year":
+		n, err := strconv.ParseInt(bound, 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		switch keyFunc {
+		case "to_days":
+			return time.Unix(0, 0).UTC().AddDate(0, 0, int(n-toDaysEpoch)), true
+		case "unix_timestamp":
+			return time.Unix(n, 0).UTC(), true
+		default:
+			return time.Date(int(n), 1, 1, 0, 0, 0, 0, time.UTC), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// truncateToPeriod returns the start of the period containing t
+func truncateToPeriod(t time.Time, interval string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case "week":
+		// Weeks start on Monday
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case "year":
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// nextPeriod returns the start of the period after the one starting at t
+func nextPeriod(t time.Time, interval string) time.Time {
+	switch interval {
+	case "week":
+		return t.AddDate(0, 0, 7)
+	case "month":
+		return t.AddDate(0, 1, 0)
+	case "year":
+		return t.AddDate(1, 0, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// partitionName names the partition of the period starting at t
+func partitionName(prefix string, t time.Time, interval string) string {
+	switch interval {
+	case "month":
+		return prefix + t.Format("200601")
+	case "year":
+		return prefix + t.Format("2006")
+	default:
+		return prefix + t.Format("20060102")
+	}
+}
+
+// postgresBoundLiteral renders a time as a PostgreSQL range partition bound
+func postgresBoundLiteral(t time.Time) string {
+	return "'" + t.Format("2006-01-02") + "'"
+}
+
+// mysqlBoundLiteral renders a time as a VALUES LESS THAN bound for the partition expression
+func mysqlBoundLiteral(keyFunc string, t time.Time) (string, error) {
+	switch keyFunc {
+	case "columns":
+		return "'" + t.Format("2006-01-02") + "'", nil
+	case "to_days":
+		return "TO_DAYS('" + t.Format("2006-01-02") + "')", nil
+	case "unix_timestamp":
+		return "UNIX_TIMESTAMP('" + t.Format("2006-01-02 15:04:05") + "')", nil
+	case "year":
+		if t.Month() != time.January || t.Day() != 1 {
+			return "", fmt.Errorf("tables partitioned by YEAR() can only get yearly partitions")
+		}
+		return strconv.Itoa(t.Year()), nil
+	default:
+		return "", fmt.Errorf("unsupported partition expression; expected RANGE COLUMNS or RANGE on TO_DAYS(), UNIX_TIMESTAMP() or YEAR()")
+	}
+}
+
+// plannedPartition is a partition that does not exist yet
+type plannedPartition struct {
+	Name string
+	From time.Time
+	To   time.Time
+}
+
+// planFuturePartitions lists the partitions needed so the current period and the given
+// number of periods ahead are covered, continuing from the latest existing partition
+func planFuturePartitions(pt *partitionedTable, interval, prefix string, ahead int, now time.Time) []plannedPartition {
+	current := truncateToPeriod(now, interval)
+	end := current
+	for i := 0; i <= ahead; i++ {
+		end = nextPeriod(end, interval)
+	}
+
+	start := current
+	if latest, ok := pt.latestBound(); ok {
+		start = latest
+	}
+	var planned []plannedPartition
+	// Cap the loop so a tiny interval after a long gap cannot generate thousands of tables
+	for from := start; from.Before(end) && len(planned) < 1000; {
+		// The first period may be partial when existing partitions end mid-period
+		to := nextPeriod(truncateToPeriod(from, interval), interval)
+		name := partitionName(prefix, from, interval)
+		if pt.partition(name) == nil {
+			planned = append(planned, plannedPartition{Name: name, From: from, To: to})
+		}
+		from = to
+	}
+	return planned
+}
+
+// expiredPartitions lists the time-range partitions whose whole range is older than the cutoff
+func expiredPartitions(pt *partitionedTable, cutoff time.Time) []partitionInfo {
+	var expired []partitionInfo
+	for _, p := range pt.Partitions {
+		if p.HasRange && !p.Default && !p.To.After(cutoff) {
+			expired = append(expired, p)
+		}
+	}
+	return expired
+}
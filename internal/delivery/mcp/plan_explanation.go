@@ -0,0 +1,260 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// largePlanRows is the row estimate above which scans and sorts are called out as big
+const largePlanRows = 10000
+
+// smallPlanRows is the row estimate below which a nested loop's outer side counts as small
+const smallPlanRows = 1000
+
+// explainPlanInEnglish describes a plan step by step in plain English. The explanation is
+// derived only from the plan structure and estimates, so the same plan always reads the same.
+func explainPlanInEnglish(plan *queryPlan) string {
+	var sb strings.Builder
+	if !plan.Format.costs {
+		sb.WriteString("The database does not report cost estimates")
+	} else {
+		sb.WriteString(fmt.Sprintf("The planner estimates a total cost of %.2f", plan.TotalCost))
+	}
+	if plan.Root.Rows > 0 {
+		sb.WriteString(fmt.Sprintf(" and about %s result rows", formatPlanRows(plan.Root.Rows)))
+	}
+	sb.WriteString(".\n\n")
+
+	// Steps are listed in execution order: inputs before the nodes that consume them
+	var steps []string
+	var visit func(node *planNode)
+	visit = func(node *planNode) {
+		for _, child := range node.Children {
+			visit(child)
+		}
+		if step := describePlanNode(node); step != "" {
+			steps = append(steps, step)
+		}
+	}
+	visit(plan.Root)
+
+	sb.WriteString("Steps:\n")
+	for i, step := range steps {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, step))
+	}
+
+	sb.WriteString("\nSummary:\n")
+	if indexes := plan.indexesUsed(); len(indexes) > 0 {
+		sb.WriteString(fmt.Sprintf("- Indexes used: %s\n", strings.Join(indexes, ", ")))
+	} else {
+		sb.WriteString("- No indexes are used.\n")
+	}
+
+	var largeScans, largeSorts []string
+	plan.Root.walk(func(node *planNode, depth int) {
+		switch {
+		case fullScanNodeTypes[node.NodeType] && node.Rows >= largePlanRows:
+			largeScans = append(largeScans, fmt.Sprintf("%s (about %s rows)", node.Relation, formatPlanRows(node.Rows)))
+		case node.NodeType == "Sort" && node.Rows >= largePlanRows:
+			largeSorts = append(largeSorts, fmt.Sprintf("by %s (about %s rows)", strings.Join(node.SortKey, ", "), formatPlanRows(node.Rows)))
+		case planNodeHasFlag(node, "filesort"):
+			largeSorts = append(largeSorts, "a filesort for ORDER BY or GROUP BY")
+		}
+	})
+	if len(largeScans) > 0 {
+		sb.WriteString(fmt.Sprintf("- Big full table scans: %s\n", strings.Join(largeScans, ", ")))
+	}
+	if len(largeSorts) > 0 {
+		sb.WriteString(fmt.Sprintf("- Expensive sorts: %s\n", strings.Join(largeSorts, ", ")))
+	}
+	if node := mostExpensivePlanNode(plan); node != nil {
+		sb.WriteString(fmt.Sprintf("- Most expensive step: %s\n", node.label()))
+	}
+
+	return sb.String()
+}
+
+// describePlanNode explains what a single node does; nodes that add nothing return ""
+func describePlanNode(node *planNode) string {
+	rel := node.Relation
+	rows := formatPlanRows(node.Rows)
+	var text string
+	switch node.NodeType {
+	// Table access, PostgreSQL
+	case "Seq Scan":
+		text = fmt.Sprintf("Reads every row of %s sequentially (about %s rows)", rel, rows)
+	case "Index Scan":
+		text = fmt.Sprintf("Looks up rows of %s through index %s", rel, node.Index)
+	case "Index Only Scan":
+		text = fmt.Sprintf("Answers from index %s alone without visiting the %s table", node.Index, rel)
+	case "Bitmap Index Scan":
+		text = fmt.Sprintf("Collects the locations of matching rows from index %s", node.Index)
+	case "Bitmap Heap Scan":
+		text = fmt.Sprintf("Fetches the matching rows of %s found by the bitmap index scan", rel)
+	// Table access, MySQL
+	case "ALL":
+		text = fmt.Sprintf("Reads every row of %s (about %s rows)", rel, rows)
+	case "system", "const":
+		text = fmt.Sprintf("Reads the single matching row of %s once", rel)
+	case "eq_ref":
+		text = fmt.Sprintf("Looks up exactly one row of %s through unique index %s for each row of the previous tables", rel, node.Index)
+	case "ref", "ref_or_null":
+		text = fmt.Sprintf("Looks up matching rows of %s through index %s", rel, node.Index)
+	case "range":
+		text = fmt.Sprintf("Reads a range of index %s on %s", node.Index, rel)
+	case "index":
+		text = fmt.Sprintf("Reads the entire index %s of %s", node.Index, rel)
+	case "index_merge":
+		text = fmt.Sprintf("Combines several indexes of %s (%s)", rel, node.Index)
+	// Table access, SQLite
+	case "SCAN":
+		text = fmt.Sprintf("Reads every row of %s", rel)
+	case "SCAN INDEX":
+		text = fmt.Sprintf("Reads the entire index %s of %s", node.Index, rel)
+	case "SEARCH":
+		if node.Index == "rowid" {
+			text = fmt.Sprintf("Looks up rows of %s by rowid", rel)
+		} else {
+			text = fmt.Sprintf("Looks up rows of %s through index %s", rel, node.Index)
+		}
+	// Joins
+	case "Nested Loop":
+		return describeNestedLoop(node)
+	case "nested_loop":
+		return describeMySQLNestedLoop(node)
+	case "Hash Join":
+		text = "Joins the two inputs by building an in-memory hash table from the second input and probing it with each row of the first"
+	case "Merge Join":
+		text = "Joins the two inputs by walking both in the order of the join key"
+	// Sorting and grouping
+	case "Sort":
+		text = fmt.Sprintf("Sorts about %s rows by %s", rows, strings.Join(node.SortKey, ", "))
+		if node.Rows >= largePlanRows {
+			text += "; a sort this size may spill to disk, an index on the sort key could avoid it"
+		}
+	case "ordering_operation":
+		if !planNodeHasFlag(node, "filesort") {
+			return ""
+		}
+		text = "Sorts the rows with a filesort because no index provides the requested order"
+	case "grouping_operation", "duplicates_removal":
+		if !planNodeHasFlag(node, "temporary table") {
+			return ""
+		}
+		text = "Groups the rows in a temporary table"
+	case "Aggregate", "HashAggregate", "GroupAggregate":
+		text = "Aggregates the rows"
+		if node.NodeType == "HashAggregate" {
+			text = "Groups the rows using an in-memory hash table"
+		}
+	case "Limit":
+		text = "Stops once enough rows have been produced for the LIMIT"
+	case "USE TEMP B-TREE":
+		if planNodeHasFlag(node, "filesort") {
+			return "Sorts the rows in a temporary B-tree because no index provides the requested order."
+		}
+		return fmt.Sprintf("Builds a temporary B-tree %s.", node.Condition)
+	case "Hash", "query_block", "Materialize", "Result", "QUERY PLAN":
+		return ""
+	default:
+		text = fmt.Sprintf("Performs a %s step", node.NodeType)
+	}
+
+	if node.Condition != "" {
+		text += fmt.Sprintf(", using the condition %s", node.Condition)
+	}
+	return text + "."
+}
+
+// describeNestedLoop explains a PostgreSQL nested loop and why it was likely chosen
+func describeNestedLoop(node *planNode) string {
+	if len(node.Children) < 2 {
+		return "Joins the inputs with a nested loop."
+	}
+	outer, inner := node.Children[0], planAccessNode(node.Children[1])
+	text := fmt.Sprintf("Joins with a nested loop: for each of about %s outer rows it looks up matching rows in the inner input", formatPlanRows(outer.Rows))
+	if inner != nil && inner.Relation != "" {
+		text += " from " + inner.Relation
+	}
+	text += ". "
+
+	innerIndexed := inner != nil && inner.Index != ""
+	switch {
+	case outer.Rows <= smallPlanRows && innerIndexed:
+		text += fmt.Sprintf("The planner chose it because the outer side is small and each lookup can use index %s.", inner.Index)
+	case outer.Rows <= smallPlanRows:
+		text += "The planner chose it because the outer side is small, but the inner side is not read through an index, so its cost grows with every outer row."
+	case innerIndexed:
+		text += fmt.Sprintf("The outer side is large, so this relies on index %s staying cheap per lookup; check that the row estimate is accurate.", inner.Index)
+	default:
+		text += "The outer side is large and the inner side has no index; this repeats a scan per outer row and is a likely bottleneck."
+	}
+	return text
+}
+
+// describeMySQLNestedLoop explains MySQL's join order and how each joined table is read
+func describeMySQLNestedLoop(node *planNode) string {
+	if len(node.Children) == 0 {
+		return ""
+	}
+	tables := make([]string, 0, len(node.Children))
+	for _, child := range node.Children {
+		tables = append(tables, child.Relation)
+	}
+	text := fmt.Sprintf("Joins %s with nested loops, starting from %s", strings.Join(tables, ", "), tables[0])
+	for _, child := range node.Children[1:] {
+		if fullScanNodeTypes[child.NodeType] {
+			text += fmt.Sprintf("; %s is scanned in full for every row of the previous tables, which is a likely bottleneck", child.Relation)
+		} else if child.Index != "" {
+			text += fmt.Sprintf("; %s is looked up through index %s", child.Relation, child.Index)
+		}
+	}
+	return text + "."
+}
+
+// planAccessNode returns the first node below n that reads a table
+func planAccessNode(n *planNode) *planNode {
+	var found *planNode
+	n.walk(func(node *planNode, depth int) {
+		if found == nil && node.Relation != "" {
+			found = node
+		}
+	})
+	return found
+}
+
+// planNodeHasFlag reports whether a node carries the given MySQL or SQLite flag
+func planNodeHasFlag(node *planNode, flag string) bool {
+	for _, f := range node.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// mostExpensivePlanNode returns the node with the highest cost of its own, excluding the cost
+// of its inputs, in plans whose node costs include their inputs as PostgreSQL's do; MySQL
+// costs are cumulative so it returns nil there
+func mostExpensivePlanNode(plan *queryPlan) *planNode {
+	if !plan.Format.inclusiveCosts {
+		return nil
+	}
+	var best *planNode
+	var bestCost float64
+	plan.Root.walk(func(node *planNode, depth int) {
+		own := node.Cost
+		for _, child := range node.Children {
+			own -= child.Cost
+		}
+		if best == nil || own > bestCost {
+			best, bestCost = node, own
+		}
+	})
+	return best
+}
+
+// formatPlanRows formats a row estimate without decimals
+func formatPlanRows(rows float64) string {
+	return fmt.Sprintf("%.0f", rows)
+}
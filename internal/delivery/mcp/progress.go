@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// progressKey is the context key of the progress sink of a tool call
+type progressKey struct{}
+
+// progressFunc receives the progress of a long-running tool call as a fraction between 0 and 1
+type progressFunc func(progress float64, message string)
+
+// withProgress attaches a progress sink to the context, e.g. one forwarding MCP progress notifications
+func withProgress(ctx context.Context, fn progressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// progressLog records the progress of a tool call so it can be included in the response
+type progressLog struct {
+	mu      sync.Mutex
+	start   time.Time
+	entries []string
+}
+
+// newProgressLog creates a progress log starting now
+func newProgressLog() *progressLog {
+	return &progressLog{start: time.Now()}
+}
+
+// report logs a progress step, records it and forwards it to the progress sink of the context
+func (l *progressLog) report(ctx context.Context, progress float64, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	logger.Info("Progress %.0f%%: %s", progress*100, message)
+
+	l.mu.Lock()
+	l.entries = append(l.entries, fmt.Sprintf("%s [%3.0f%%] %s", time.Since(l.start).Round(time.Millisecond), progress*100, message))
+	l.mu.Unlock()
+
+	if fn, ok := ctx.Value(progressKey{}).(progressFunc); ok && fn != nil {
+		fn(progress, message)
+	}
+}
+
+// lines returns the recorded progress steps
+func (l *progressLog) lines() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.entries...)
+}
+
+// progressReader reports how much of a stream of known size has been read, in steps of a tenth
+type progressReader struct {
+	r      io.Reader
+	total  int64
+	read   int64
+	next   int64
+	report func(read, total int64)
+}
+
+// newProgressReader wraps a reader of total bytes
+func newProgressReader(r io.Reader, total int64, report func(read, total int64)) *progressReader {
+	return &progressReader{r: r, total: total, next: total / 10, report: report}
+}
+
+// Read implements io.Reader
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.r.Read(buf)
+	p.read += int64(n)
+	if p.total > 0 && p.read >= p.next && p.read < p.total {
+		p.report(p.read, p.total)
+		p.next = p.read + p.total/10
+	}
+	return n, err
+}
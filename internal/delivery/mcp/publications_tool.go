@@ -0,0 +1,282 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// publicationOperations are the changes a publication can replicate
+var publicationOperations = []string{"insert", "update", "delete", "truncate"}
+
+// publication is a row of pg_publication with the tables it covers
+type publication struct {
+	Name       string
+	AllTables  bool
+	Operations []string
+	Tables     []string
+}
+
+// PublicationsTool handles PostgreSQL logical replication publications
+type PublicationsTool struct {
+	BaseToolType
+}
+
+// NewPublicationsTool creates a new publication tool type
+func NewPublicationsTool() *PublicationsTool {
+	return &PublicationsTool{
+		BaseToolType: BaseToolType{
+			name:        "publications",
+			description: "List, create and drop PostgreSQL publications for logical replication. The list warns about published tables without a primary key or replica identity, on which UPDATE and DELETE fail once the publication replicates them.",
+		},
+	}
+}
+
+// CreateTool creates a publication tool
+func (t *PublicationsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("List, create and drop logical replication publications"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("action",
+			tools.Description("list, create or drop (default: list)"),
+		),
+		tools.WithString("publication",
+			tools.Description("Publication name for create and drop"),
+		),
+		tools.WithArray("tables",
+			tools.Description("Tables to publish on create"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithBoolean("all_tables",
+			tools.Description("Publish all tables, including ones created later (default: false)"),
+		),
+		tools.WithArray("operations",
+			tools.Description("Operations to publish: insert, update, delete, truncate (default: all)"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+	)
+}
+
+// HandleRequest handles publication tool requests
+func (t *PublicationsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	action := input.choice("action", "list", "list", "create", "drop")
+	name := input.optionalString("publication", "")
+	tables := input.stringList("tables")
+	allTables := input.optionalBool("all_tables", false)
+	operations := input.stringList("operations")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	for i, op := range operations {
+		operations[i] = strings.ToLower(op)
+		if !containsFold(publicationOperations, op) {
+			return nil, fmt.Errorf("invalid operation: %s (expected insert, update, delete or truncate)", op)
+		}
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("publications are only supported on PostgreSQL, not %s", dbType)
+	}
+	publisher, err := dialect.Publisher()
+	if err != nil {
+		return nil, fmt.Errorf("publications are only supported on PostgreSQL, not %s", dbType)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Publications for Database %s\n\n", targetDbID))
+
+	switch action {
+	case "list":
+	case "create":
+		if name == "" {
+			return nil, fmt.Errorf("publication parameter is required for create")
+		}
+		statement, err := publisher.createStatement(name, tables, allTables, operations)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := useCase.ExecuteStatement(ctx, targetDbID, statement, nil); err != nil {
+			return nil, fmt.Errorf("failed to create publication %s: %w", name, err)
+		}
+		logger.Info("Created publication %s on database %s", name, targetDbID)
+		response.WriteString(fmt.Sprintf("Created publication %s:\n\n```sql\n%s;\n```\n\n", name, statement))
+	case "drop":
+		if name == "" {
+			return nil, fmt.Errorf("publication parameter is required for drop")
+		}
+		statement := publisher.dropStatement(name)
+		if _, err := useCase.ExecuteStatement(ctx, targetDbID, statement, nil); err != nil {
+			return nil, fmt.Errorf("failed to drop publication %s: %w", name, err)
+		}
+		logger.Warn("Dropped publication %s on database %s", name, targetDbID)
+		response.WriteString(fmt.Sprintf("Dropped publication %s. Subscriptions to it stop receiving changes; their slots keep holding WAL until they are dropped as well.\n\n", name))
+	default:
+		return nil, fmt.Errorf("invalid action: %s (expected list, create or drop)", action)
+	}
+
+	publications, err := publisher.load(ctx, useCase, targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	if len(publications) == 0 {
+		response.WriteString("No publications.\n")
+		return createTextResponse(response.String()), nil
+	}
+	response.WriteString("| Publication | Operations | Tables |\n")
+	response.WriteString("|-------------|------------|--------|\n")
+	for _, p := range publications {
+		covered := strings.Join(p.Tables, ", ")
+		if p.AllTables {
+			covered = "all tables"
+		}
+		response.WriteString(fmt.Sprintf("| %s | %s | %s |\n", p.Name, strings.Join(p.Operations, ", "), covered))
+	}
+
+	warnings, err := publisher.warnings(ctx, useCase, targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	if len(warnings) > 0 {
+		response.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			response.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// publisher creates, drops and lists the publications logical replication subscribes to
+type publisher interface {
+	// createStatement builds the statement publishing the operations on tables or all tables
+	createStatement(name string, tables []string, allTables bool, operations []string) (string, error)
+	// dropStatement builds the statement dropping a publication
+	dropStatement(name string) string
+	// load reads the publications and the tables they cover
+	load(ctx context.Context, useCase UseCaseProvider, dbID string) ([]publication, error)
+	// warnings finds published tables changes to which cannot be replicated
+	warnings(ctx context.Context, useCase UseCaseProvider, dbID string) ([]string, error)
+}
+
+// postgresPublisher manages the publications of PostgreSQL logical replication
+type postgresPublisher struct{}
+
+func (postgresPublisher) createStatement(name string, tables []string, allTables bool, operations []string) (string, error) {
+	return createPublicationStatement(name, tables, allTables, operations)
+}
+
+func (postgresPublisher) dropStatement(name string) string {
+	return "DROP PUBLICATION " + quoteIdentifier("postgres", name)
+}
+
+func (postgresPublisher) load(ctx context.Context, useCase UseCaseProvider, dbID string) ([]publication, error) {
+	return loadPublications(ctx, useCase, dbID)
+}
+
+func (postgresPublisher) warnings(ctx context.Context, useCase UseCaseProvider, dbID string) ([]string, error) {
+	return publicationWarnings(ctx, useCase, dbID)
+}
+
+// createPublicationStatement builds the CREATE PUBLICATION statement
+func createPublicationStatement(name string, tables []string, allTables bool, operations []string) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("CREATE PUBLICATION " + quoteIdentifier("postgres", name))
+	switch {
+	case allTables && len(tables) > 0:
+		return "", fmt.Errorf("tables and all_tables cannot be combined")
+	case allTables:
+		sb.WriteString(" FOR ALL TABLES")
+	case len(tables) > 0:
+		quoted := make([]string, len(tables))
+		for i, table := range tables {
+			quoted[i] = quoteIdentifier("postgres", table)
+		}
+		sb.WriteString(" FOR TABLE " + strings.Join(quoted, ", "))
+	default:
+		return "", fmt.Errorf("tables or all_tables is required for create")
+	}
+	if len(operations) > 0 {
+		sb.WriteString(fmt.Sprintf(" WITH (publish = '%s')", strings.Join(operations, ", ")))
+	}
+	return sb.String(), nil
+}
+
+// loadPublications reads the publications and the tables they cover
+func loadPublications(ctx context.Context, useCase UseCaseProvider, dbID string) ([]publication, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, `
+SELECT
+    p.pubname,
+    p.puballtables,
+    p.pubinsert,
+    p.pubupdate,
+    p.pubdelete,
+    COALESCE((to_jsonb(p) ->> 'pubtruncate')::boolean, false),
+    COALESCE((
+        SELECT string_agg(t.schemaname || '.' || t.tablename, ',' ORDER BY t.schemaname, t.tablename)
+        FROM pg_publication_tables t
+        WHERE t.pubname = p.pubname AND NOT p.puballtables
+    ), '')
+FROM pg_publication p
+ORDER BY p.pubname`, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list publications: %w", err)
+	}
+	publications := make([]publication, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 7 {
+			continue
+		}
+		p := publication{Name: valueString(row[0]), AllTables: valueBool(row[1])}
+		for i, op := range publicationOperations {
+			if valueBool(row[2+i]) {
+				p.Operations = append(p.Operations, op)
+			}
+		}
+		if tables := valueString(row[6]); tables != "" {
+			p.Tables = strings.Split(tables, ",")
+		}
+		publications = append(publications, p)
+	}
+	return publications, nil
+}
+
+// publicationWarnings finds published tables on which replicated updates and deletes fail
+func publicationWarnings(ctx context.Context, useCase UseCaseProvider, dbID string) ([]string, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, `
+SELECT DISTINCT t.pubname, t.schemaname || '.' || t.tablename
+FROM pg_publication_tables t
+JOIN pg_publication p ON p.pubname = t.pubname
+JOIN pg_class c ON c.oid = format('%I.%I', t.schemaname, t.tablename)::regclass
+WHERE (p.pubupdate OR p.pubdelete)
+  AND (c.relreplident = 'n'
+       OR (c.relreplident = 'd' AND NOT EXISTS (
+           SELECT 1 FROM pg_index i WHERE i.indrelid = c.oid AND i.indisprimary)))
+ORDER BY 1, 2`, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check replica identities: %w", err)
+	}
+	var warnings []string
+	for _, row := range result.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("Table %s in publication %s has no primary key or replica identity; UPDATE and DELETE on it will fail. Add a primary key or set REPLICA IDENTITY.",
+			valueString(row[1]), valueString(row[0])))
+	}
+	return warnings, nil
+}
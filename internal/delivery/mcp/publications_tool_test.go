@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestCreatePublicationStatement(t *testing.T) {
+	statement, err := createPublicationStatement("orders_pub", []string{"orders", "sales.items"}, false, []string{"insert", "update"})
+	assert.NoError(t, err)
+	assert.Equal(t, `CREATE PUBLICATION "orders_pub" FOR TABLE "orders", "sales"."items" WITH (publish = 'insert, update')`, statement)
+
+	statement, err = createPublicationStatement("everything", nil, true, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `CREATE PUBLICATION "everything" FOR ALL TABLES`, statement)
+
+	_, err = createPublicationStatement("p", []string{"orders"}, true, nil)
+	assert.Error(t, err)
+	_, err = createPublicationStatement("p", nil, false, nil)
+	assert.Error(t, err)
+}
+
+func TestPublicationsTool(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"p.puballtables,": {Rows: [][]interface{}{
+				{"orders_pub", false, true, true, true, false, "public.events,public.orders"},
+				{"everything", true, true, false, false, false, ""},
+			}},
+			"relreplident": {Rows: [][]interface{}{{"orders_pub", "public.events"}}},
+		},
+	}
+
+	result, err := NewPublicationsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "action": "create", "publication": "orders_pub", "tables": []interface{}{"orders", "events"}},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	assert.Contains(t, useCase.queries[0], `CREATE PUBLICATION "orders_pub" FOR TABLE "orders", "events"`)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| orders_pub | insert, update, delete | public.events, public.orders |")
+	assert.Contains(t, text, "| everything | insert | all tables |")
+	assert.Contains(t, text, "Table public.events in publication orders_pub has no primary key or replica identity")
+
+	_, err = NewPublicationsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "action": "create", "publication": "p", "all_tables": true, "operations": []interface{}{"upsert"}},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "invalid operation: upsert")
+
+	_, err = NewPublicationsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1"},
+	}, "mysql1", &mockUseCase{dbType: "mysql"})
+	assert.ErrorContains(t, err, "only supported on PostgreSQL")
+}
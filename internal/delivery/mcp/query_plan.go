@@ -0,0 +1,410 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// planNode is a database-independent node of an EXPLAIN plan
+type planNode struct {
+	NodeType  string
+	Relation  string
+	Index     string
+	Cost      float64
+	Rows      float64
+	Condition string   // index, join or filter condition
+	SortKey   []string // PostgreSQL Sort nodes only
+	Flags     []string // MySQL and SQLite extras such as "filesort" or "temporary table"
+	Filtered  float64  // MySQL only: percentage of the examined rows the condition keeps
+	Children  []*planNode
+}
+
+// queryPlan is a parsed EXPLAIN plan
+type queryPlan struct {
+	DatabaseType string
+	Format       planFormat
+	TotalCost    float64
+	Root         *planNode
+	Raw          string
+}
+
+// fullScanNodeTypes are the node types that read a whole table
+var fullScanNodeTypes = map[string]bool{"Seq Scan": true, "ALL": true, "SCAN": true}
+
+// sqlitePlanStep matches the table accesses of SQLite's EXPLAIN QUERY PLAN, such as
+// "SEARCH orders USING INDEX idx_orders_user (user_id=?)"; older versions add TABLE
+var sqlitePlanStep = regexp.MustCompile(`^(SCAN|SEARCH) (?:TABLE )?(\S+)(?: AS \S+)?(?: USING (?:(?:AUTOMATIC )?(?:PARTIAL )?(?:COVERING )?INDEX (\S+)|(INTEGER PRIMARY KEY|PRIMARY KEY)))?(?: \((.*)\))?`)
+
+// explainQuery builds the JSON EXPLAIN statement for a query
+func explainQuery(dbType, query string) (string, error) {
+	query = strings.TrimRight(strings.TrimSpace(query), ";")
+	if strings.HasPrefix(strings.ToUpper(query), "EXPLAIN") {
+		return "", fmt.Errorf("query must not start with EXPLAIN")
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok || dialect.ExplainQuery(query) == "" {
+		return "", fmt.Errorf("unsupported database type for EXPLAIN: %s", dbType)
+	}
+	return dialect.ExplainQuery(query), nil
+}
+
+// loadQueryPlan runs EXPLAIN for a query and parses the resulting plan
+func loadQueryPlan(ctx context.Context, useCase UseCaseProvider, dbID, query string) (*queryPlan, error) {
+	dbType, err := useCase.GetDatabaseType(dbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	statement, err := explainQuery(dbType, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, dbID, statement, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) == 0 {
+		return nil, fmt.Errorf("no explain plan returned")
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for EXPLAIN: %s", dbType)
+	}
+	if format, ok := dialect.PlanFormat(); ok && format.document != nil {
+		return parseQueryPlan(dbType, format.document(result.Columns, result.Rows))
+	}
+	return parseQueryPlan(dbType, valueString(result.Rows[0][0]))
+}
+
+// sqlitePlanJSON encodes the rows of EXPLAIN QUERY PLAN (id, parent, notused, detail) as a
+// JSON array, so SQLite plans can be kept and compared like the JSON plans of other engines
+func sqlitePlanJSON(columns []string, rows [][]interface{}) string {
+	index := columnIndex(columns)
+	steps := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		steps = append(steps, map[string]interface{}{
+			"id":     valueInt64(row[index["id"]]),
+			"parent": valueInt64(row[index["parent"]]),
+			"detail": valueString(row[index["detail"]]),
+		})
+	}
+	raw, _ := json.Marshal(steps)
+	return string(raw)
+}
+
+// planFormat describes the EXPLAIN output of an engine and how to read it
+type planFormat struct {
+	// document extracts the plan from the rows of the EXPLAIN statement; nil takes the first
+	// column of the first row
+	document func(columns []string, rows [][]interface{}) string
+	// parse builds the plan tree and its total cost from the plan document
+	parse func(raw string) (*planNode, float64, error)
+	// costs reports whether the plan carries cost estimates
+	costs bool
+	// inclusiveCosts reports whether the cost of a node includes the cost of its inputs
+	inclusiveCosts bool
+	// examinedRows reports whether scans estimate the rows examined, with the percentage the
+	// condition keeps in Filtered, and name tables by their alias
+	examinedRows bool
+}
+
+// postgresPlanFormat reads EXPLAIN (FORMAT JSON), whose node costs include their inputs
+var postgresPlanFormat = planFormat{parse: parsePostgresPlan, costs: true, inclusiveCosts: true}
+
+// mysqlPlanFormat reads EXPLAIN FORMAT=JSON, whose costs are cumulative along the join order
+var mysqlPlanFormat = planFormat{parse: parseMySQLPlan, costs: true, examinedRows: true}
+
+// sqlitePlanFormat reads EXPLAIN QUERY PLAN, which has neither costs nor row estimates
+var sqlitePlanFormat = planFormat{document: sqlitePlanJSON, parse: parseSQLitePlan}
+
+// parseQueryPlan parses the EXPLAIN output of a database type in the plan format of its dialect
+func parseQueryPlan(dbType, raw string) (*queryPlan, error) {
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for EXPLAIN: %s", dbType)
+	}
+	format, ok := dialect.PlanFormat()
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for EXPLAIN: %s", dbType)
+	}
+	root, totalCost, err := format.parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &queryPlan{DatabaseType: strings.ToLower(dbType), Format: format, TotalCost: totalCost, Root: root, Raw: raw}, nil
+}
+
+// parsePostgresPlan parses a PostgreSQL plan; YugabyteDB and Greenplum plans have its shape
+func parsePostgresPlan(raw string) (*planNode, float64, error) {
+	var doc []map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	if len(doc) == 0 {
+		return nil, 0, fmt.Errorf("failed to parse plan: empty plan")
+	}
+	root, ok := doc[0]["Plan"].(map[string]interface{})
+	if !ok {
+		return nil, 0, fmt.Errorf("failed to parse plan: missing Plan node")
+	}
+	node := parsePostgresPlanNode(root)
+	return node, node.Cost, nil
+}
+
+// parseMySQLPlan parses the query block of a MySQL plan
+func parseMySQLPlan(raw string) (*planNode, float64, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	block, ok := doc["query_block"].(map[string]interface{})
+	if !ok {
+		return nil, 0, fmt.Errorf("failed to parse plan: missing query_block")
+	}
+	root := &planNode{NodeType: "query_block"}
+	if costInfo, ok := block["cost_info"].(map[string]interface{}); ok {
+		root.Cost = valueFloat64(costInfo["query_cost"])
+	}
+	root.Children = parseMySQLPlanChildren(block)
+	return root, root.Cost, nil
+}
+
+// parseSQLitePlan parses the steps sqlitePlanJSON encoded
+func parseSQLitePlan(raw string) (*planNode, float64, error) {
+	var steps []struct {
+		ID     int64  `json:"id"`
+		Parent int64  `json:"parent"`
+		Detail string `json:"detail"`
+	}
+	if err := json.Unmarshal([]byte(raw), &steps); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	// Steps come parent first; a step whose parent is unknown hangs off the root
+	root := &planNode{NodeType: "QUERY PLAN"}
+	nodes := map[int64]*planNode{0: root}
+	for _, step := range steps {
+		node := parseSQLitePlanStep(step.Detail)
+		parent, ok := nodes[step.Parent]
+		if !ok {
+			parent = root
+		}
+		parent.Children = append(parent.Children, node)
+		nodes[step.ID] = node
+	}
+	return root, 0, nil
+}
+
+// parsePostgresPlanNode converts a PostgreSQL plan node and its children
+func parsePostgresPlanNode(m map[string]interface{}) *planNode {
+	node := &planNode{
+		NodeType: valueString(m["Node Type"]),
+		Relation: valueString(m["Relation Name"]),
+		Index:    valueString(m["Index Name"]),
+		Cost:     valueFloat64(m["Total Cost"]),
+		Rows:     valueFloat64(m["Plan Rows"]),
+	}
+	for _, key := range []string{"Index Cond", "Hash Cond", "Merge Cond", "Join Filter", "Recheck Cond", "Filter"} {
+		if condition := valueString(m[key]); condition != "" {
+			node.Condition = condition
+			break
+		}
+	}
+	if keys, ok := m["Sort Key"].([]interface{}); ok {
+		for _, key := range keys {
+			node.SortKey = append(node.SortKey, valueString(key))
+		}
+	}
+	if children, ok := m["Plans"].([]interface{}); ok {
+		for _, child := range children {
+			if childMap, ok := child.(map[string]interface{}); ok {
+				node.Children = append(node.Children, parsePostgresPlanNode(childMap))
+			}
+		}
+	}
+	return node
+}
+
+// parseSQLitePlanStep converts a step of a SQLite plan. Table accesses become SCAN (a full
+// table scan), SCAN INDEX (a full index scan) or SEARCH nodes; temporary B-trees become
+// nodes flagged like MySQL filesorts and temporary tables; other steps keep their text.
+func parseSQLitePlanStep(detail string) *planNode {
+	if m := sqlitePlanStep.FindStringSubmatch(detail); m != nil && detail != "SCAN CONSTANT ROW" {
+		node := &planNode{NodeType: m[1], Relation: m[2], Index: m[3], Condition: m[5]}
+		switch m[4] {
+		case "INTEGER PRIMARY KEY":
+			node.Index = "rowid"
+		case "PRIMARY KEY":
+			node.Index = "PRIMARY KEY"
+		}
+		if node.NodeType == "SCAN" && node.Index != "" {
+			node.NodeType = "SCAN INDEX"
+		}
+		return node
+	}
+	if strings.HasPrefix(detail, "USE TEMP B-TREE") {
+		node := &planNode{NodeType: "USE TEMP B-TREE", Condition: strings.TrimPrefix(detail, "USE TEMP B-TREE ")}
+		if strings.Contains(detail, "ORDER BY") {
+			node.Flags = append(node.Flags, "filesort")
+		} else {
+			node.Flags = append(node.Flags, "temporary table")
+		}
+		return node
+	}
+	return &planNode{NodeType: detail}
+}
+
+// parseMySQLPlanChildren walks a MySQL JSON plan object and returns the nodes it contains.
+// Table accesses become nodes named after their access type; *_operation objects and
+// nested loops become nodes wrapping what they contain.
+func parseMySQLPlanChildren(m map[string]interface{}) []*planNode {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var nodes []*planNode
+	for _, key := range keys {
+		switch value := m[key].(type) {
+		case map[string]interface{}:
+			switch {
+			case key == "table":
+				node := &planNode{
+					NodeType:  valueString(value["access_type"]),
+					Relation:  valueString(value["table_name"]),
+					Index:     valueString(value["key"]),
+					Rows:      valueFloat64(value["rows_examined_per_scan"]),
+					Condition: valueString(value["attached_condition"]),
+					Filtered:  valueFloat64(value["filtered"]),
+				}
+				if costInfo, ok := value["cost_info"].(map[string]interface{}); ok {
+					node.Cost = valueFloat64(costInfo["prefix_cost"])
+				}
+				node.Children = parseMySQLPlanChildren(value)
+				nodes = append(nodes, node)
+			case strings.HasSuffix(key, "_operation") || key == "union_result" || key == "materialized_from_subquery":
+				node := &planNode{NodeType: key, Children: parseMySQLPlanChildren(value)}
+				if valueBool(value["using_filesort"]) {
+					node.Flags = append(node.Flags, "filesort")
+				}
+				if valueBool(value["using_temporary_table"]) {
+					node.Flags = append(node.Flags, "temporary table")
+				}
+				nodes = append(nodes, node)
+			default:
+				nodes = append(nodes, parseMySQLPlanChildren(value)...)
+			}
+		case []interface{}:
+			var children []*planNode
+			for _, item := range value {
+				if itemMap, ok := item.(map[string]interface{}); ok {
+					children = append(children, parseMySQLPlanChildren(itemMap)...)
+				}
+			}
+			if key == "nested_loop" {
+				nodes = append(nodes, &planNode{NodeType: key, Children: children})
+			} else {
+				nodes = append(nodes, children...)
+			}
+		}
+	}
+	return nodes
+}
+
+// valueFloat64 converts a scanned database value or JSON number to a float64
+func valueFloat64(v interface{}) float64 {
+	switch val := v.(type) {
+	case float64:
+		return val
+	case float32:
+		return float64(val)
+	case []byte:
+		f, _ := strconv.ParseFloat(string(val), 64)
+		return f
+	case string:
+		f, _ := strconv.ParseFloat(val, 64)
+		return f
+	}
+	return float64(valueInt64(v))
+}
+
+// walk visits every node of the plan depth-first
+func (n *planNode) walk(visit func(node *planNode, depth int)) {
+	var walk func(node *planNode, depth int)
+	walk = func(node *planNode, depth int) {
+		visit(node, depth)
+		for _, child := range node.Children {
+			walk(child, depth+1)
+		}
+	}
+	walk(n, 0)
+}
+
+// label describes a plan node on a single line
+func (n *planNode) label() string {
+	label := n.NodeType
+	if n.Relation != "" {
+		label += " on " + n.Relation
+	}
+	if n.Index != "" {
+		label += " using " + n.Index
+	}
+	if n.Cost > 0 || n.Rows > 0 {
+		label += fmt.Sprintf(" (cost=%.2f rows=%.0f)", n.Cost, n.Rows)
+	}
+	return label
+}
+
+// render renders the plan as an indented tree
+func (p *queryPlan) render() string {
+	var sb strings.Builder
+	p.Root.walk(func(node *planNode, depth int) {
+		sb.WriteString(strings.Repeat("  ", depth) + "-> " + node.label() + "\n")
+	})
+	return sb.String()
+}
+
+// nodeTypeCounts counts plan nodes by type
+func (p *queryPlan) nodeTypeCounts() map[string]int {
+	counts := make(map[string]int)
+	p.Root.walk(func(node *planNode, depth int) {
+		if node.NodeType != "" {
+			counts[node.NodeType]++
+		}
+	})
+	return counts
+}
+
+// indexesUsed returns the sorted set of indexes the plan reads
+func (p *queryPlan) indexesUsed() []string {
+	return p.collect(func(node *planNode) string { return node.Index })
+}
+
+// fullScans returns the sorted set of tables the plan reads in full
+func (p *queryPlan) fullScans() []string {
+	return p.collect(func(node *planNode) string {
+		if fullScanNodeTypes[node.NodeType] {
+			return node.Relation
+		}
+		return ""
+	})
+}
+
+// collect returns the sorted set of non-empty values extracted from the plan nodes
+func (p *queryPlan) collect(extract func(node *planNode) string) []string {
+	seen := make(map[string]bool)
+	var values []string
+	p.Root.walk(func(node *planNode, depth int) {
+		if value := extract(node); value != "" && !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	})
+	sort.Strings(values)
+	return values
+}
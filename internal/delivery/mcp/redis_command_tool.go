@@ -0,0 +1,327 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// RedisCommandTool handles running one command on a key-value database
+type RedisCommandTool struct {
+	BaseToolType
+}
+
+// NewRedisCommandTool creates a new redis command tool type
+func NewRedisCommandTool() *RedisCommandTool {
+	return &RedisCommandTool{
+		BaseToolType: BaseToolType{
+			name:        "redis_command",
+			description: "Run one Redis command, written as in redis-cli, such as HGETALL user:1 or ZRANGE \"top scores\" 0 9 WITHSCORES, and return its reply as redis-cli prints it. Read-only commands run as they are; commands that write need allow_write, and administrative commands such as FLUSHALL or CONFIG SET, and Lua scripts and functions (EVAL, EVALSHA, FCALL and their _RO variants), which can call any command, also need allow_admin in the connection config. KEYS, blocking commands, subscriptions, MONITOR, SHUTDOWN, DEBUG and commands that change the connection, such as SELECT or MULTI, are refused.",
+		},
+	}
+}
+
+// CreateTool creates a redis command tool
+func (t *RedisCommandTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Run one Redis command, guarded against writes and administration"),
+		tools.WithString("database",
+			tools.Description("Database ID to run the command on"),
+			tools.Required(),
+		),
+		tools.WithString("command",
+			tools.Description(`Command with its arguments as in redis-cli, quoting arguments with spaces, such as GET "user:1 name"`),
+			tools.Required(),
+		),
+		tools.WithBoolean("allow_write",
+			tools.Description("Allow a command that changes data, such as SET, DEL or EXPIRE (default: false)"),
+		),
+	)
+}
+
+// redisReadCommands are the commands that only read. An entry with a subcommand, such as
+// CONFIG GET, allows only that subcommand.
+var redisReadCommands = wordSet(
+	"GET", "MGET", "STRLEN", "GETRANGE", "SUBSTR", "LCS", "EXISTS", "TYPE", "TTL", "PTTL", "EXPIRETIME", "PEXPIRETIME",
+	"DBSIZE", "RANDOMKEY", "SCAN", "SSCAN", "HSCAN", "ZSCAN", "DUMP",
+	"HGET", "HMGET", "HGETALL", "HKEYS", "HVALS", "HLEN", "HEXISTS", "HSTRLEN", "HRANDFIELD",
+	"LRANGE", "LLEN", "LINDEX", "LPOS",
+	"SMEMBERS", "SISMEMBER", "SMISMEMBER", "SCARD", "SRANDMEMBER", "SINTER", "SINTERCARD", "SUNION", "SDIFF",
+	"ZRANGE", "ZRANGEBYSCORE", "ZRANGEBYLEX", "ZREVRANGE", "ZREVRANGEBYSCORE", "ZREVRANGEBYLEX", "ZSCORE", "ZMSCORE",
+	"ZRANK", "ZREVRANK", "ZCARD", "ZCOUNT", "ZLEXCOUNT", "ZRANDMEMBER", "ZINTER", "ZINTERCARD", "ZUNION", "ZDIFF",
+	"XRANGE", "XREVRANGE", "XLEN", "XREAD", "XPENDING", "XINFO STREAM", "XINFO GROUPS", "XINFO CONSUMERS",
+	"GETBIT", "BITCOUNT", "BITPOS", "BITFIELD_RO", "PFCOUNT",
+	"GEOPOS", "GEODIST", "GEOHASH", "GEOSEARCH", "GEORADIUS_RO", "GEORADIUSBYMEMBER_RO",
+	"PING", "ECHO", "TIME", "INFO", "LASTSAVE", "ROLE",
+	"OBJECT ENCODING", "OBJECT FREQ", "OBJECT IDLETIME", "OBJECT REFCOUNT",
+	"MEMORY USAGE", "MEMORY STATS", "MEMORY DOCTOR",
+	"CONFIG GET", "CLIENT LIST", "CLIENT INFO", "CLIENT GETNAME", "CLIENT ID",
+	"SLOWLOG GET", "SLOWLOG LEN", "LATENCY LATEST", "LATENCY HISTORY", "LATENCY DOCTOR",
+	"COMMAND COUNT", "COMMAND INFO", "COMMAND DOCS", "COMMAND LIST", "COMMAND GETKEYS",
+	"CLUSTER INFO", "CLUSTER NODES", "CLUSTER SHARDS", "CLUSTER SLOTS", "CLUSTER KEYSLOT", "CLUSTER COUNTKEYSINSLOT",
+	"FUNCTION LIST", "FUNCTION STATS", "SCRIPT EXISTS", "ACL WHOAMI", "ACL CAT",
+)
+
+// redisAdminCommands administer the server rather than change data
+var redisAdminCommands = wordSet(
+	"FLUSHALL", "FLUSHDB", "SAVE", "BGSAVE", "BGREWRITEAOF", "REPLICAOF", "SLAVEOF", "FAILOVER", "SWAPDB", "MIGRATE",
+)
+
+// redisScriptCommands run Lua scripts or functions, whose redis.call can reach FLUSHALL or
+// any other command the script chooses, so they count as administrative whatever they are
+// named, read-only variants included
+var redisScriptCommands = wordSet(
+	"EVAL", "EVALSHA", "EVAL_RO", "EVALSHA_RO", "FCALL", "FCALL_RO",
+)
+
+// redisAdminContainers are the commands whose subcommands, other than the reads, administer
+// the server
+var redisAdminContainers = wordSet(
+	"CONFIG", "CLIENT", "ACL", "CLUSTER", "SCRIPT", "FUNCTION", "SLOWLOG", "LATENCY", "MEMORY", "MODULE", "COMMAND", "OBJECT",
+)
+
+// redisRefusedCommands can hang, stop or break the pooled connections, so they never run
+var redisRefusedCommands = map[string]string{
+	"KEYS":         "it blocks the server while it walks every key; use scan_keys",
+	"MONITOR":      "it streams every command the server receives",
+	"SUBSCRIBE":    "it turns the connection into a subscriber",
+	"PSUBSCRIBE":   "it turns the connection into a subscriber",
+	"SSUBSCRIBE":   "it turns the connection into a subscriber",
+	"BLPOP":        "it blocks until an element arrives",
+	"BRPOP":        "it blocks until an element arrives",
+	"BLMOVE":       "it blocks until an element arrives",
+	"BLMPOP":       "it blocks until an element arrives",
+	"BRPOPLPUSH":   "it blocks until an element arrives",
+	"BZPOPMIN":     "it blocks until an element arrives",
+	"BZPOPMAX":     "it blocks until an element arrives",
+	"BZMPOP":       "it blocks until an element arrives",
+	"WAIT":         "it blocks until replicas acknowledge",
+	"WAITAOF":      "it blocks until the AOF is synced",
+	"SHUTDOWN":     "it stops the server",
+	"DEBUG":        "it can crash or stall the server",
+	"SYNC":         "it is part of replication",
+	"PSYNC":        "it is part of replication",
+	"SELECT":       "it changes the state of a pooled connection",
+	"AUTH":         "it changes the state of a pooled connection",
+	"HELLO":        "it changes the state of a pooled connection",
+	"RESET":        "it changes the state of a pooled connection",
+	"QUIT":         "it changes the state of a pooled connection",
+	"MULTI":        "it changes the state of a pooled connection",
+	"EXEC":         "it changes the state of a pooled connection",
+	"DISCARD":      "it changes the state of a pooled connection",
+	"WATCH":        "it changes the state of a pooled connection",
+	"UNWATCH":      "it changes the state of a pooled connection",
+	"CLIENT REPLY": "it changes the state of a pooled connection",
+}
+
+// redisCommandKind is how redis_command treats a command
+type redisCommandKind int
+
+const (
+	redisRead redisCommandKind = iota
+	redisWrite
+	redisAdmin
+)
+
+// HandleRequest handles redis command tool requests
+func (t *RedisCommandTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	command := input.requiredString("command")
+	allowWrite := input.optionalBool("allow_write", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	args, err := splitCommandLine(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("command must not be empty")
+	}
+	kind, name, err := classifyRedisCommand(args)
+	if err != nil {
+		return nil, err
+	}
+	if kind == redisAdmin {
+		config, err := useCase.GetDatabaseConfig(targetDbID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get database config: %w", err)
+		}
+		if !config.AllowAdmin {
+			return nil, fmt.Errorf("%s is an administrative command and is disabled for database %s; set allow_admin in its connection config to enable it", name, targetDbID)
+		}
+	}
+	if kind != redisRead && !allowWrite {
+		return nil, fmt.Errorf("%s changes data; set allow_write to run it", name)
+	}
+
+	store, err := useCase.GetKeyValueStore(targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Running %s on database %s", name, targetDbID)
+	reply, err := store.Command(ctx, args...)
+	if kind != redisRead {
+		action := "write"
+		if kind == redisAdmin {
+			action = "admin"
+		}
+		audit(request, auditRecord{
+			Tool:      "redis_command",
+			Database:  targetDbID,
+			Action:    action,
+			Target:    name,
+			Statement: command,
+		}, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w", name, err)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# %s on Database %s\n\n", name, targetDbID))
+	response.WriteString("```\n" + formatRedisReply(reply) + "```\n")
+	return createTextResponse(response.String()), nil
+}
+
+// classifyRedisCommand tells whether a command reads, writes or administers, and returns its
+// name with the subcommand of container commands such as CONFIG GET
+func classifyRedisCommand(args []string) (redisCommandKind, string, error) {
+	name := strings.ToUpper(args[0])
+	full := name
+	if len(args) > 1 {
+		full = name + " " + strings.ToUpper(args[1])
+	}
+
+	for _, candidate := range []string{full, name} {
+		if reason, ok := redisRefusedCommands[candidate]; ok {
+			return 0, candidate, fmt.Errorf("%s is refused: %s", candidate, reason)
+		}
+	}
+	if name == "XREAD" || name == "XREADGROUP" {
+		for _, arg := range args[1:] {
+			if strings.EqualFold(arg, "BLOCK") {
+				return 0, name, fmt.Errorf("%s with BLOCK is refused: it blocks until an entry arrives", name)
+			}
+		}
+	}
+
+	switch {
+	case redisScriptCommands[name]:
+		return redisAdmin, name, nil
+	case redisReadCommands[full]:
+		return redisRead, full, nil
+	case redisReadCommands[name]:
+		return redisRead, name, nil
+	case redisAdminContainers[name]:
+		return redisAdmin, full, nil
+	case redisAdminCommands[name]:
+		return redisAdmin, name, nil
+	default:
+		return redisWrite, name, nil
+	}
+}
+
+// splitCommandLine splits a command line into arguments the way redis-cli does. Double-quoted
+// arguments take escapes such as \n or \x41, single-quoted arguments only \'.
+func splitCommandLine(line string) ([]string, error) {
+	var args []string
+	i := 0
+	for {
+		for i < len(line) && (line[i] == ' ' || line[i] == '\t' || line[i] == '\n' || line[i] == '\r') {
+			i++
+		}
+		if i == len(line) {
+			return args, nil
+		}
+
+		var arg strings.Builder
+		switch line[i] {
+		case '"':
+			i++
+			for {
+				if i >= len(line) {
+					return nil, fmt.Errorf("unbalanced quotes in command")
+				}
+				c := line[i]
+				if c == '"' {
+					i++
+					break
+				}
+				if c == '\\' && i+1 < len(line) {
+					i++
+					switch line[i] {
+					case 'n':
+						arg.WriteByte('\n')
+					case 'r':
+						arg.WriteByte('\r')
+					case 't':
+						arg.WriteByte('\t')
+					case 'x':
+						if i+2 < len(line) {
+							if b, err := strconv.ParseUint(line[i+1:i+3], 16, 8); err == nil {
+								arg.WriteByte(byte(b))
+								i += 3
+								continue
+							}
+						}
+						arg.WriteByte('x')
+					default:
+						arg.WriteByte(line[i])
+					}
+					i++
+					continue
+				}
+				arg.WriteByte(c)
+				i++
+			}
+		case '\'':
+			i++
+			for {
+				if i >= len(line) {
+					return nil, fmt.Errorf("unbalanced quotes in command")
+				}
+				c := line[i]
+				if c == '\'' {
+					i++
+					break
+				}
+				if c == '\\' && i+1 < len(line) && line[i+1] == '\'' {
+					i++
+					c = '\''
+				}
+				arg.WriteByte(c)
+				i++
+			}
+		default:
+			for i < len(line) && line[i] != ' ' && line[i] != '\t' && line[i] != '\n' && line[i] != '\r' {
+				arg.WriteByte(line[i])
+				i++
+			}
+			args = append(args, arg.String())
+			continue
+		}
+		// A closing quote must end the argument
+		if i < len(line) && line[i] != ' ' && line[i] != '\t' && line[i] != '\n' && line[i] != '\r' {
+			return nil, fmt.Errorf("closing quote must be followed by a space")
+		}
+		args = append(args, arg.String())
+	}
+}
+
+// wordSet builds a set of upper-case command names
+func wordSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
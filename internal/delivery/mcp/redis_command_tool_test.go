@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	args, err := splitCommandLine(`  ZRANGE "top scores" 0 9  WITHSCORES`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ZRANGE", "top scores", "0", "9", "WITHSCORES"}, args)
+
+	args, err = splitCommandLine(`SET greeting "line\none \x41\"" 'it\'s' ""`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SET", "greeting", "line\none A\"", "it's", ""}, args)
+
+	_, err = splitCommandLine(`GET "user:1`)
+	assert.EqualError(t, err, "unbalanced quotes in command")
+	_, err = splitCommandLine(`GET "user"1`)
+	assert.EqualError(t, err, "closing quote must be followed by a space")
+}
+
+func TestRedisCommandTool(t *testing.T) {
+	store := &fakeKeyValueStore{replies: map[string]interface{}{
+		"hgetall user:1":       []interface{}{"name", "Alice"},
+		"CONFIG GET maxmemory": []interface{}{"maxmemory", "0"},
+		"DEL user:1":           int64(1),
+		"FLUSHDB":              "OK",
+	}}
+	useCase := &mockUseCase{keyValues: store}
+	run := func(command string, allowWrite bool) (string, error) {
+		params := map[string]interface{}{"database": "cache", "command": command, "allow_write": allowWrite}
+		result, err := NewRedisCommandTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+		if err != nil {
+			return "", err
+		}
+		return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string), nil
+	}
+
+	// Reads run as they are, including read subcommands of admin commands
+	text, err := run("hgetall user:1", false)
+	assert.NoError(t, err)
+	assert.Contains(t, text, "# HGETALL on Database cache")
+	assert.Contains(t, text, "```\n1) \"name\"\n2) \"Alice\"\n```")
+	_, err = run("CONFIG GET maxmemory", false)
+	assert.NoError(t, err)
+
+	// Writes need allow_write
+	_, err = run("DEL user:1", false)
+	assert.EqualError(t, err, "DEL changes data; set allow_write to run it")
+	text, err = run("DEL user:1", true)
+	assert.NoError(t, err)
+	assert.Contains(t, text, "(integer) 1")
+
+	// Administrative commands also need allow_admin
+	_, err = run("FLUSHDB", true)
+	assert.EqualError(t, err, "FLUSHDB is an administrative command and is disabled for database cache; set allow_admin in its connection config to enable it")
+	_, err = run("config set maxmemory 1gb", true)
+	assert.EqualError(t, err, "CONFIG SET is an administrative command and is disabled for database cache; set allow_admin in its connection config to enable it")
+	_, err = run("EVAL \"return redis.call('FLUSHALL')\" 0", true)
+	assert.EqualError(t, err, "EVAL is an administrative command and is disabled for database cache; set allow_admin in its connection config to enable it")
+	_, err = run("fcall_ro stats 0", true)
+	assert.EqualError(t, err, "FCALL_RO is an administrative command and is disabled for database cache; set allow_admin in its connection config to enable it")
+	_, err = run("FUNCTION LOAD code", true)
+	assert.EqualError(t, err, "FUNCTION LOAD is an administrative command and is disabled for database cache; set allow_admin in its connection config to enable it")
+	useCase.config = domain.DatabaseConnectionConfig{AllowAdmin: true}
+	_, err = run("FLUSHDB", false)
+	assert.EqualError(t, err, "FLUSHDB changes data; set allow_write to run it")
+	_, err = run("FLUSHDB", true)
+	assert.NoError(t, err)
+
+	// Some commands never run
+	_, err = run("KEYS *", true)
+	assert.EqualError(t, err, "KEYS is refused: it blocks the server while it walks every key; use scan_keys")
+	_, err = run("BLPOP queue 0", true)
+	assert.EqualError(t, err, "BLPOP is refused: it blocks until an element arrives")
+	_, err = run("XREAD COUNT 1 BLOCK 0 STREAMS events $", false)
+	assert.EqualError(t, err, "XREAD with BLOCK is refused: it blocks until an entry arrives")
+	_, err = run("select 1", true)
+	assert.EqualError(t, err, "SELECT is refused: it changes the state of a pooled connection")
+
+	assert.Equal(t, []string{"hgetall user:1", "CONFIG GET maxmemory", "DEL user:1", "FLUSHDB"}, store.commands)
+}
@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+// redisOverview are the INFO fields db_stats shows for Redis, in order
+var redisOverview = []struct{ field, label string }{
+	{"redis_version", "Version"},
+	{"redis_mode", "Mode"},
+	{"role", "Role"},
+	{"connected_slaves", "Connected replicas"},
+	{"uptime_in_seconds", "Uptime"},
+	{"connected_clients", "Connected clients"},
+	{"blocked_clients", "Blocked clients"},
+	{"used_memory_human", "Memory used"},
+	{"used_memory_peak_human", "Peak memory used"},
+	{"maxmemory_human", "Memory limit"},
+	{"maxmemory_policy", "Eviction policy"},
+	{"mem_fragmentation_ratio", "Fragmentation ratio"},
+	{"total_commands_processed", "Commands processed"},
+	{"instantaneous_ops_per_sec", "Operations per second"},
+	{"expired_keys", "Expired keys"},
+	{"evicted_keys", "Evicted keys"},
+	{"rejected_connections", "Rejected connections"},
+	{"rdb_last_bgsave_status", "Last RDB save"},
+	{"rdb_changes_since_last_save", "Changes since last save"},
+	{"aof_enabled", "AOF enabled"},
+}
+
+// redisStats builds the db_stats report of a Redis database from INFO; detailed adds the
+// commands that took the most time and the slow log
+func redisStats(ctx context.Context, useCase UseCaseProvider, dbID string, detailed bool) (interface{}, error) {
+	store, err := useCase.GetKeyValueStore(dbID)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := store.Command(ctx, "INFO")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read INFO: %w", err)
+	}
+	sections := parseRedisInfo(redisText(reply))
+	fields := infoFields(sections)
+
+	var results strings.Builder
+	results.WriteString(fmt.Sprintf("# Database Statistics for %s (redis)\n\n", dbID))
+	results.WriteString("## Overview\n\n")
+	results.WriteString("| Metric | Value |\n")
+	results.WriteString("|--------|-------|\n")
+	for _, metric := range redisOverview {
+		value, ok := fields[metric.field]
+		if !ok {
+			continue
+		}
+		if metric.field == "uptime_in_seconds" {
+			if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+				value = (time.Duration(seconds) * time.Second).String()
+			}
+		}
+		if metric.field == "maxmemory_human" && value == "0B" {
+			value = "none"
+		}
+		results.WriteString(fmt.Sprintf("| %s | %s |\n", metric.label, value))
+		// The hit ratio follows the commands processed
+		if metric.field == "total_commands_processed" {
+			hits, _ := strconv.ParseFloat(fields["keyspace_hits"], 64)
+			misses, _ := strconv.ParseFloat(fields["keyspace_misses"], 64)
+			if hits+misses > 0 {
+				results.WriteString(fmt.Sprintf("| Keyspace hit ratio | %.1f%% |\n", 100*hits/(hits+misses)))
+			}
+		}
+	}
+
+	results.WriteString("\n## Keyspace\n\n")
+	keyspace := infoSectionFields(sections, "Keyspace")
+	if len(keyspace) == 0 {
+		results.WriteString("No database holds keys.\n")
+	} else {
+		results.WriteString("| Database | Keys | Expiring | Average TTL |\n")
+		results.WriteString("|----------|------|----------|-------------|\n")
+		for _, field := range keyspace {
+			values := parseInfoValues(field[1])
+			avgTTL := "none"
+			if ms, err := strconv.ParseInt(values["avg_ttl"], 10, 64); err == nil && ms > 0 {
+				avgTTL = (time.Duration(ms) * time.Millisecond).Round(time.Second).String()
+			}
+			results.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", field[0], values["keys"], values["expires"], avgTTL))
+		}
+	}
+
+	if detailed {
+		results.WriteString("\n## Command Statistics\n\n")
+		if err := writeRedisCommandStats(ctx, store, &results); err != nil {
+			results.WriteString(fmt.Sprintf("Error reading command statistics: %v\n", err))
+		}
+		results.WriteString("\n## Slow Log\n\n")
+		if err := writeRedisSlowLog(ctx, store, &results); err != nil {
+			results.WriteString(fmt.Sprintf("Error reading the slow log: %v\n", err))
+		}
+	}
+	return createTextResponse(results.String()), nil
+}
+
+// infoSectionFields returns the fields of one INFO section
+func infoSectionFields(sections []infoSection, name string) [][2]string {
+	for _, section := range sections {
+		if strings.EqualFold(section.name, name) {
+			return section.fields
+		}
+	}
+	return nil
+}
+
+// writeRedisCommandStats writes the 20 commands that took the most time from INFO commandstats
+func writeRedisCommandStats(ctx context.Context, store domain.KeyValueStore, sb *strings.Builder) error {
+	reply, err := store.Command(ctx, "INFO", "commandstats")
+	if err != nil {
+		return err
+	}
+	type commandStat struct {
+		name        string
+		calls, usec int64
+	}
+	var stats []commandStat
+	for _, field := range infoSectionFields(parseRedisInfo(redisText(reply)), "Commandstats") {
+		values := parseInfoValues(field[1])
+		calls, _ := strconv.ParseInt(values["calls"], 10, 64)
+		usec, _ := strconv.ParseInt(values["usec"], 10, 64)
+		stats = append(stats, commandStat{name: strings.TrimPrefix(field[0], "cmdstat_"), calls: calls, usec: usec})
+	}
+	if len(stats) == 0 {
+		sb.WriteString("No commands were recorded.\n")
+		return nil
+	}
+	sort.SliceStable(stats, func(i, j int) bool { return stats[i].usec > stats[j].usec })
+	if len(stats) > 20 {
+		stats = stats[:20]
+	}
+	sb.WriteString("| Command | Calls | Total Time | Time per Call |\n")
+	sb.WriteString("|---------|-------|------------|---------------|\n")
+	for _, stat := range stats {
+		perCall := time.Duration(0)
+		if stat.calls > 0 {
+			perCall = time.Duration(stat.usec) * time.Microsecond / time.Duration(stat.calls)
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %d | %s | %s |\n", stat.name, stat.calls, time.Duration(stat.usec)*time.Microsecond, perCall))
+	}
+	return nil
+}
+
+// writeRedisSlowLog writes the last 10 entries of the slow log
+func writeRedisSlowLog(ctx context.Context, store domain.KeyValueStore, sb *strings.Builder) error {
+	entries, err := commandList(ctx, store, "SLOWLOG", "GET", "10")
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		sb.WriteString("The slow log is empty.\n")
+		return nil
+	}
+	sb.WriteString("| ID | Time | Duration | Command |\n")
+	sb.WriteString("|----|------|----------|---------|\n")
+	for _, entry := range entries {
+		parts, _ := entry.([]interface{})
+		if len(parts) < 4 {
+			continue
+		}
+		id, _ := redisInt(parts[0])
+		at, _ := redisInt(parts[1])
+		usec, _ := redisInt(parts[2])
+		var command []string
+		if args, ok := parts[3].([]interface{}); ok {
+			for _, arg := range args {
+				command = append(command, redisText(arg))
+			}
+		}
+		sb.WriteString(fmt.Sprintf("| %d | %s | %s | %s |\n", id, time.Unix(at, 0).UTC().Format(time.RFC3339),
+			time.Duration(usec)*time.Microsecond, redisCell(strings.Join(command, " "))))
+	}
+	return nil
+}
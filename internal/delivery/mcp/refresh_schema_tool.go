@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// RefreshSchemaTool handles dropping and reloading cached schema metadata
+type RefreshSchemaTool struct {
+	BaseToolType
+}
+
+// NewRefreshSchemaTool creates a new schema refresh tool type
+func NewRefreshSchemaTool() *RefreshSchemaTool {
+	return &RefreshSchemaTool{
+		BaseToolType: BaseToolType{
+			name:        "refresh_schema",
+			description: "Drop the cached schema metadata (tables, columns, keys and indexes) of a database and read it again. Metadata tools such as export_erd, find_join_path, suggest_joins, review_schema and doc_coverage cache it for schema_cache_ttl seconds; tools that change the schema refresh it themselves, so call this after changing the schema by other means, such as execute or another client.",
+		},
+	}
+}
+
+// CreateTool creates a schema refresh tool
+func (t *RefreshSchemaTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Reload the cached schema metadata of a database"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to reload right away (default: public on PostgreSQL, the current database on MySQL)"),
+		),
+	)
+}
+
+// HandleRequest handles schema refresh tool requests
+func (t *RefreshSchemaTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema := input.optionalString("schema", "")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	dropped := schemaCache.invalidate(useCase, targetDbID)
+	meta, err := schemaCache.load(ctx, useCase, targetDbID, schema)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Refreshed schema metadata of database %s (%d cached schema(s) dropped)", targetDbID, dropped)
+
+	columns, indexes := 0, 0
+	for _, table := range meta.Tables {
+		columns += len(table.Columns)
+		indexes += len(table.Indexes)
+	}
+	name := meta.Schema
+	if name == "" {
+		name = "current database"
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Schema Refresh for Database %s\n\n", targetDbID))
+	response.WriteString(fmt.Sprintf("Dropped %d cached schema(s).\n\n", dropped))
+	response.WriteString(fmt.Sprintf("Reloaded %s:\n", name))
+	response.WriteString(fmt.Sprintf("- Tables: %d\n", len(meta.Tables)))
+	response.WriteString(fmt.Sprintf("- Columns: %d\n", columns))
+	response.WriteString(fmt.Sprintf("- Indexes: %d\n", indexes))
+	response.WriteString(fmt.Sprintf("- Foreign keys: %d\n", len(meta.ForeignKeys)))
+	if ttl := schemaCacheTTL(useCase, targetDbID); ttl > 0 {
+		response.WriteString(fmt.Sprintf("\nCached for %s.\n", ttl))
+	} else {
+		response.WriteString("\nSchema caching is disabled for this connection.\n")
+	}
+
+	return createTextResponse(response.String()), nil
+}
@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshSchemaTool(t *testing.T) {
+	useCase := newSchemaCacheUseCase()
+	t.Cleanup(func() { schemaCache.invalidate(useCase, "test") })
+
+	_, err := schemaCache.load(context.Background(), useCase, "test", "app")
+	assert.NoError(t, err)
+	loaded := len(useCase.queries)
+
+	request := server.ToolCallRequest{Parameters: map[string]interface{}{"database": "test", "schema": "app"}}
+	result, err := NewRefreshSchemaTool().HandleRequest(context.Background(), request, "test", useCase)
+	assert.NoError(t, err)
+	assert.Len(t, useCase.queries, 2*loaded)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "# Schema Refresh for Database test")
+	assert.Contains(t, text, "Dropped 1 cached schema(s).")
+	assert.Contains(t, text, "Reloaded app:")
+	assert.Contains(t, text, "- Tables: 2\n")
+	assert.Contains(t, text, "- Columns: 3\n")
+	assert.Contains(t, text, "- Indexes: 1\n")
+	assert.Contains(t, text, "- Foreign keys: 1\n")
+	assert.Contains(t, text, "Cached for 5m0s.")
+
+	// The reloaded metadata is cached again
+	_, err = schemaCache.load(context.Background(), useCase, "test", "app")
+	assert.NoError(t, err)
+	assert.Len(t, useCase.queries, 2*loaded)
+}
@@ -0,0 +1,515 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// reindexLeftoverPattern matches the invalid copies a failed REINDEX CONCURRENTLY leaves behind
+var reindexLeftoverPattern = regexp.MustCompile(`_ccnew[0-9]*$`)
+
+// reindexCandidate is an index (PostgreSQL) or table (MySQL) selected for a rebuild
+type reindexCandidate struct {
+	Schema string
+	Table  string
+	// Index is empty on MySQL, where indexes are rebuilt together with their table
+	Index      string
+	Reason     string
+	Statement  string
+	SizeBefore int64
+	SizeAfter  int64
+}
+
+// object returns the qualified name of the rebuilt index or table
+func (c reindexCandidate) object() string {
+	if c.Index == "" {
+		return qualifiedName(c.Schema, c.Table)
+	}
+	return qualifiedName(c.Schema, c.Index)
+}
+
+// reindexOptions selects the indexes to rebuild and how
+type reindexOptions struct {
+	Schema          string
+	Table           string
+	Indexes         []string
+	MinBloatPercent float64
+	MinSizeBytes    int64
+	Online          bool
+}
+
+// postgresIndexState is an index as read from the PostgreSQL catalog
+type postgresIndexState struct {
+	Schema    string
+	Index     string
+	Table     string
+	Valid     bool
+	Size      int64
+	Tuples    float64
+	Method    string
+	KeyWidth  int64
+	Estimable bool
+	BlockSize int64
+}
+
+// bloatPercent estimates how much of a btree index is free space, from its tuple count and the
+// average width of its key columns; the estimate is rough and only meant to rank candidates
+func (s postgresIndexState) bloatPercent() (float64, bool) {
+	if s.Method != "btree" || !s.Estimable || s.Size <= 0 || s.Tuples < 0 || s.BlockSize <= 0 {
+		return 0, false
+	}
+	// Index tuple header and line pointer, keys aligned to 8 bytes, leaves filled to 90%
+	tupleSize := float64((8+s.KeyWidth+7)/8*8 + 4)
+	usable := float64(s.BlockSize-24-16) * 0.9
+	expected := (math.Ceil(s.Tuples*tupleSize/usable) + 1) * float64(s.BlockSize)
+	if float64(s.Size) <= expected {
+		return 0, true
+	}
+	return (float64(s.Size) - expected) * 100 / float64(s.Size), true
+}
+
+// ReindexTool handles rebuilding bloated or invalid indexes
+type ReindexTool struct {
+	BaseToolType
+}
+
+// NewReindexTool creates a new reindex tool type
+func NewReindexTool() *ReindexTool {
+	return &ReindexTool{
+		BaseToolType: BaseToolType{
+			name:        "reindex",
+			description: "Rebuild bloated or invalid indexes and report their size before and after. On PostgreSQL invalid indexes and btree indexes whose estimated bloat exceeds a threshold (or the named indexes) are rebuilt one at a time with REINDEX INDEX CONCURRENTLY, and invalid leftovers of an earlier failed concurrent reindex are dropped. InnoDB cannot rebuild a single index, so on MySQL fragmented tables are rebuilt with all their indexes using ALTER TABLE ... FORCE, ALGORITHM=INPLACE, LOCK=NONE, or OPTIMIZE TABLE when online is false. Use dry_run to only list the candidates.",
+		},
+	}
+}
+
+// CreateTool creates a reindex tool
+func (t *ReindexTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Rebuild bloated or invalid indexes with size before and after"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to scan (default: public on PostgreSQL, the current database on MySQL)"),
+		),
+		tools.WithString("table",
+			tools.Description("Only consider the indexes of this table"),
+		),
+		tools.WithArray("indexes",
+			tools.Description("PostgreSQL: rebuild these indexes regardless of their estimated bloat"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithNumber("min_bloat_percent",
+			tools.Description("Rebuild when the estimated bloat (PostgreSQL) or free space (MySQL) is at least this percentage (default: 30)"),
+		),
+		tools.WithNumber("min_size_mb",
+			tools.Description("Skip indexes and tables smaller than this many MiB (default: 1)"),
+		),
+		tools.WithBoolean("online",
+			tools.Description("Rebuild without blocking writes: REINDEX CONCURRENTLY or ALTER TABLE ... LOCK=NONE (default: true)"),
+		),
+		tools.WithBoolean("dry_run",
+			tools.Description("Only list the candidates and statements (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles reindex tool requests
+func (t *ReindexTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	options := reindexOptions{
+		Schema:          input.optionalString("schema", ""),
+		Indexes:         input.stringList("indexes"),
+		MinBloatPercent: input.optionalFloat("min_bloat_percent", 30),
+		Online:          input.optionalBool("online", true),
+	}
+	if schema, table := splitQualifiedName(input.optionalString("table", "")); table != "" {
+		options.Table = table
+		if schema != "" {
+			options.Schema = schema
+		}
+	}
+	minSizeMB := input.optionalFloat("min_size_mb", 1)
+	dryRun := input.optionalBool("dry_run", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if options.MinBloatPercent < 0 || options.MinBloatPercent > 100 {
+		return nil, fmt.Errorf("min_bloat_percent parameter must be between 0 and 100")
+	}
+	if minSizeMB < 0 {
+		return nil, fmt.Errorf("min_size_mb parameter must be at least 0")
+	}
+	options.MinSizeBytes = int64(minSizeMB * (1 << 20))
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for reindex: %s", dbType)
+	}
+	rebuilder, err := dialect.Reindexer()
+	if err != nil {
+		return nil, unsupportedAdminError("reindex", dbType, err)
+	}
+	candidates, err := rebuilder.plan(ctx, useCase, targetDbID, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Reindex for Database %s\n\n", targetDbID))
+	if len(candidates) == 0 {
+		response.WriteString("No invalid or bloated indexes found.\n")
+		return createTextResponse(response.String()), nil
+	}
+
+	if dryRun {
+		writeReindexCandidates(&response, candidates, false)
+		response.WriteString("\nDry run: nothing was executed.\n")
+		return createTextResponse(response.String()), nil
+	}
+
+	for i, candidate := range candidates {
+		err := rebuilder.run(ctx, useCase, targetDbID, candidate)
+		audit(request, auditRecord{
+			Tool:      "reindex",
+			Database:  targetDbID,
+			Action:    "reindex",
+			Target:    candidate.object(),
+			Statement: candidate.Statement,
+		}, err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rebuild %s after %d of %d rebuild(s): %w", candidate.object(), i, len(candidates), err)
+		}
+		logger.Info("Rebuilt %s on database %s", candidate.object(), targetDbID)
+	}
+	rebuilder.measure(ctx, useCase, targetDbID, options, candidates)
+
+	writeReindexCandidates(&response, candidates, true)
+	var before, after int64
+	for _, candidate := range candidates {
+		if candidate.SizeAfter >= 0 {
+			before += candidate.SizeBefore
+			after += candidate.SizeAfter
+		}
+	}
+	response.WriteString(fmt.Sprintf("\nRebuilt %d object(s); their size went from %s to %s.\n", len(candidates), formatBackupSize(before), formatBackupSize(after)))
+
+	return createTextResponse(response.String()), nil
+}
+
+// writeReindexCandidates renders the candidates with their statements and, once executed, their new sizes
+func writeReindexCandidates(sb *strings.Builder, candidates []reindexCandidate, executed bool) {
+	if executed {
+		sb.WriteString("| Object | Reason | Size before | Size after |\n")
+		sb.WriteString("|--------|--------|-------------|------------|\n")
+	} else {
+		sb.WriteString("| Object | Reason | Size |\n")
+		sb.WriteString("|--------|--------|------|\n")
+	}
+	for _, candidate := range candidates {
+		object := candidate.object()
+		if candidate.Index != "" {
+			object += " on " + candidate.Table
+		}
+		if executed {
+			after := formatBackupSize(candidate.SizeAfter)
+			switch {
+			case candidate.SizeAfter < 0:
+				after = "unknown"
+			case candidate.SizeAfter == 0 && strings.HasPrefix(candidate.Statement, "DROP"):
+				after = "dropped"
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", object, candidate.Reason, formatBackupSize(candidate.SizeBefore), after))
+		} else {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", object, candidate.Reason, formatBackupSize(candidate.SizeBefore)))
+		}
+	}
+	sb.WriteString("\n```sql\n")
+	for _, candidate := range candidates {
+		sb.WriteString(candidate.Statement + ";\n")
+	}
+	sb.WriteString("```\n")
+}
+
+// reindexer is the engine-specific part of rebuilding indexes
+type reindexer interface {
+	// plan completes the options and selects the objects to rebuild with their statements
+	plan(ctx context.Context, useCase UseCaseProvider, dbID string, options *reindexOptions) ([]reindexCandidate, error)
+	// run executes the rebuild of one candidate
+	run(ctx context.Context, useCase UseCaseProvider, dbID string, candidate reindexCandidate) error
+	// measure sets the size of the candidates after their rebuild, -1 when it is unknown
+	measure(ctx context.Context, useCase UseCaseProvider, dbID string, options reindexOptions, candidates []reindexCandidate)
+}
+
+// postgresReindexer rebuilds invalid, bloated or named indexes with REINDEX INDEX
+type postgresReindexer struct{}
+
+func (postgresReindexer) plan(ctx context.Context, useCase UseCaseProvider, dbID string, options *reindexOptions) ([]reindexCandidate, error) {
+	if options.Schema == "" {
+		options.Schema = "public"
+	}
+	return planPostgresReindex(ctx, useCase, dbID, *options)
+}
+
+// run executes one rebuild; concurrent rebuilds cannot run inside a transaction, so each runs
+// on its own
+func (postgresReindexer) run(ctx context.Context, useCase UseCaseProvider, dbID string, candidate reindexCandidate) error {
+	_, err := useCase.ExecuteStatement(ctx, dbID, candidate.Statement, nil)
+	if err != nil && strings.Contains(candidate.Statement, "CONCURRENTLY") && strings.HasPrefix(candidate.Statement, "REINDEX") {
+		return fmt.Errorf("%w (an invalid %s_ccnew index may be left behind; rerun reindex to drop it)", err, candidate.Index)
+	}
+	return err
+}
+
+// measure reads the indexes again; those missing were dropped
+func (postgresReindexer) measure(ctx context.Context, useCase UseCaseProvider, dbID string, options reindexOptions, candidates []reindexCandidate) {
+	after, err := loadPostgresIndexStates(ctx, useCase, dbID, options)
+	for i := range candidates {
+		if err != nil {
+			candidates[i].SizeAfter = -1
+		}
+		for _, state := range after {
+			if state.Schema == candidates[i].Schema && state.Index == candidates[i].Index {
+				candidates[i].SizeAfter = state.Size
+			}
+		}
+	}
+}
+
+// mysqlReindexer rebuilds fragmented InnoDB tables, indexes included
+type mysqlReindexer struct{}
+
+func (mysqlReindexer) plan(ctx context.Context, useCase UseCaseProvider, dbID string, options *reindexOptions) ([]reindexCandidate, error) {
+	if len(options.Indexes) > 0 {
+		return nil, fmt.Errorf("InnoDB rebuilds indexes together with their table; pass table instead of indexes")
+	}
+	return planMySQLReindex(ctx, useCase, dbID, *options)
+}
+
+func (mysqlReindexer) run(ctx context.Context, useCase UseCaseProvider, dbID string, candidate reindexCandidate) error {
+	if !strings.HasPrefix(candidate.Statement, "OPTIMIZE") {
+		_, err := useCase.ExecuteStatement(ctx, dbID, candidate.Statement, nil)
+		return err
+	}
+	// OPTIMIZE TABLE reports problems as result rows rather than errors
+	result, err := useCase.ExecuteQuery(ctx, dbID, candidate.Statement, nil)
+	if err != nil {
+		return err
+	}
+	for _, row := range result.Rows {
+		if len(row) >= 4 && strings.EqualFold(valueString(row[2]), "error") {
+			return fmt.Errorf("%s", valueString(row[3]))
+		}
+	}
+	return nil
+}
+
+func (mysqlReindexer) measure(ctx context.Context, useCase UseCaseProvider, dbID string, options reindexOptions, candidates []reindexCandidate) {
+	sizes := loadMySQLTableSizes(ctx, useCase, dbID, options.Schema, candidates)
+	for i := range candidates {
+		candidates[i].SizeAfter = -1
+		if size, ok := sizes[candidates[i].Table]; ok {
+			candidates[i].SizeAfter = size
+		}
+	}
+}
+
+// planPostgresReindex selects the invalid, bloated or named indexes and their statements
+func planPostgresReindex(ctx context.Context, useCase UseCaseProvider, dbID string, options reindexOptions) ([]reindexCandidate, error) {
+	states, err := loadPostgresIndexStates(ctx, useCase, dbID, options)
+	if err != nil {
+		return nil, err
+	}
+	concurrently := ""
+	if options.Online {
+		concurrently = "CONCURRENTLY "
+	}
+
+	var candidates []reindexCandidate
+	found := map[string]bool{}
+	for _, state := range states {
+		candidate := reindexCandidate{Schema: state.Schema, Table: state.Table, Index: state.Index, SizeBefore: state.Size}
+		bloat, estimable := state.bloatPercent()
+		switch {
+		case len(options.Indexes) > 0:
+			if !containsFold(options.Indexes, state.Index) {
+				continue
+			}
+			found[strings.ToLower(state.Index)] = true
+			candidate.Reason = "requested"
+			if !state.Valid {
+				candidate.Reason = "requested, invalid"
+			}
+		case !state.Valid:
+			candidate.Reason = "invalid"
+		case estimable && bloat >= options.MinBloatPercent && state.Size >= options.MinSizeBytes:
+			candidate.Reason = fmt.Sprintf("~%.0f%% bloat (estimated)", bloat)
+		default:
+			continue
+		}
+
+		name := quoteIdentifier("postgres", qualifiedName(state.Schema, state.Index))
+		if !state.Valid && reindexLeftoverPattern.MatchString(state.Index) {
+			candidate.Reason = "leftover of a failed REINDEX CONCURRENTLY"
+			candidate.Statement = "DROP INDEX " + concurrently + name
+		} else {
+			candidate.Statement = "REINDEX INDEX " + concurrently + name
+		}
+		candidates = append(candidates, candidate)
+	}
+	for _, index := range options.Indexes {
+		if !found[strings.ToLower(index)] {
+			return nil, fmt.Errorf("index %s does not exist in schema %s", index, options.Schema)
+		}
+	}
+	return candidates, nil
+}
+
+// loadPostgresIndexStates reads the indexes of the schema or table with what the bloat estimate needs
+func loadPostgresIndexStates(ctx context.Context, useCase UseCaseProvider, dbID string, options reindexOptions) ([]postgresIndexState, error) {
+	params := newSQLParams("postgres")
+	query := fmt.Sprintf(`
+SELECT n.nspname, ic.relname, t.relname, i.indisvalid,
+       pg_relation_size(ic.oid), ic.reltuples, am.amname,
+       COALESCE((SELECT SUM(s.avg_width) FROM pg_attribute a
+                 JOIN pg_stats s ON s.schemaname = n.nspname AND s.tablename = t.relname AND s.attname = a.attname
+                 WHERE a.attrelid = t.oid AND a.attnum = ANY(i.indkey)), 0),
+       i.indexprs IS NULL AND NOT 0 = ANY(i.indkey),
+       current_setting('block_size')::bigint
+FROM pg_index i
+JOIN pg_class ic ON ic.oid = i.indexrelid
+JOIN pg_class t ON t.oid = i.indrelid
+JOIN pg_namespace n ON n.oid = ic.relnamespace
+JOIN pg_am am ON am.oid = ic.relam
+WHERE n.nspname = %s`, params.add(options.Schema))
+	if options.Table != "" {
+		query += " AND t.relname = " + params.add(options.Table)
+	}
+	query += "\nORDER BY t.relname, ic.relname"
+
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes: %w", err)
+	}
+	var states []postgresIndexState
+	for _, row := range result.Rows {
+		if len(row) < 10 {
+			continue
+		}
+		states = append(states, postgresIndexState{
+			Schema:    valueString(row[0]),
+			Index:     valueString(row[1]),
+			Table:     valueString(row[2]),
+			Valid:     valueBool(row[3]),
+			Size:      valueInt64(row[4]),
+			Tuples:    valueFloat64(row[5]),
+			Method:    valueString(row[6]),
+			KeyWidth:  valueInt64(row[7]),
+			Estimable: valueBool(row[8]),
+			BlockSize: valueInt64(row[9]),
+		})
+	}
+	return states, nil
+}
+
+// planMySQLReindex selects the fragmented InnoDB tables and their rebuild statements
+func planMySQLReindex(ctx context.Context, useCase UseCaseProvider, dbID string, options reindexOptions) ([]reindexCandidate, error) {
+	params := newSQLParams("mysql")
+	schemaExpr := "DATABASE()"
+	if options.Schema != "" {
+		schemaExpr = params.add(options.Schema)
+	}
+	query := fmt.Sprintf(`
+SELECT TABLE_SCHEMA, TABLE_NAME, DATA_LENGTH + INDEX_LENGTH, DATA_FREE
+FROM information_schema.TABLES
+WHERE TABLE_SCHEMA = %s AND ENGINE = 'InnoDB' AND TABLE_TYPE = 'BASE TABLE'`, schemaExpr)
+	if options.Table != "" {
+		query += " AND TABLE_NAME = " + params.add(options.Table)
+	}
+	query += "\nORDER BY TABLE_NAME"
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	var candidates []reindexCandidate
+	for _, row := range result.Rows {
+		if len(row) < 4 {
+			continue
+		}
+		size, free := valueInt64(row[2]), valueInt64(row[3])
+		candidate := reindexCandidate{Schema: valueString(row[0]), Table: valueString(row[1]), SizeBefore: size}
+		fragmentation := 0.0
+		if size+free > 0 {
+			fragmentation = float64(free) * 100 / float64(size+free)
+		}
+		if fragmentation < options.MinBloatPercent || size < options.MinSizeBytes {
+			continue
+		}
+		candidate.Reason = fmt.Sprintf("%.0f%% free space", fragmentation)
+		table := quoteIdentifier("mysql", qualifiedName(candidate.Schema, candidate.Table))
+		if options.Online {
+			candidate.Statement = fmt.Sprintf("ALTER TABLE %s FORCE, ALGORITHM=INPLACE, LOCK=NONE", table)
+		} else {
+			candidate.Statement = "OPTIMIZE TABLE " + table
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	// information_schema sizes may be cached; prefer the persistent InnoDB statistics when readable
+	sizes := loadMySQLTableSizes(ctx, useCase, dbID, options.Schema, candidates)
+	for i := range candidates {
+		if size, ok := sizes[candidates[i].Table]; ok {
+			candidates[i].SizeBefore = size
+		}
+	}
+	return candidates, nil
+}
+
+// loadMySQLTableSizes reads the size of every index of the tables, clustered index included,
+// from mysql.innodb_index_stats, which a rebuild refreshes
+func loadMySQLTableSizes(ctx context.Context, useCase UseCaseProvider, dbID, schema string, candidates []reindexCandidate) map[string]int64 {
+	sizes := map[string]int64{}
+	if len(candidates) == 0 {
+		return sizes
+	}
+	params := newSQLParams("mysql")
+	schemaExpr := "DATABASE()"
+	if schema != "" {
+		schemaExpr = params.add(schema)
+	}
+	placeholders := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		placeholders[i] = params.add(candidate.Table)
+	}
+	result, err := useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT table_name, SUM(stat_value) * @@innodb_page_size
+FROM mysql.innodb_index_stats
+WHERE database_name = %s AND stat_name = 'size' AND table_name IN (%s)
+GROUP BY table_name`, schemaExpr, strings.Join(placeholders, ", ")), params.values)
+	if err != nil {
+		logger.Warn("Could not read InnoDB index statistics on database %s: %v", dbID, err)
+		return sizes
+	}
+	for _, row := range result.Rows {
+		if len(row) >= 2 {
+			sizes[valueString(row[0])] = valueInt64(row[1])
+		}
+	}
+	return sizes
+}
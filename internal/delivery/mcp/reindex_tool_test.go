@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestPostgresIndexBloatPercent(t *testing.T) {
+	state := postgresIndexState{Method: "btree", Estimable: true, Size: 8 << 20, Tuples: 100000, KeyWidth: 4, BlockSize: 8192}
+	bloat, ok := state.bloatPercent()
+	assert.True(t, ok)
+	assert.InDelta(t, 73.2, bloat, 0.1)
+
+	state.Size = 2 << 20
+	bloat, ok = state.bloatPercent()
+	assert.True(t, ok)
+	assert.Zero(t, bloat)
+
+	state.Method = "gin"
+	_, ok = state.bloatPercent()
+	assert.False(t, ok)
+}
+
+func TestReindexToolPostgres(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_index i": {Rows: [][]interface{}{
+				{"public", "orders_pkey", "orders", true, int64(8 << 20), float64(100000), "btree", int64(4), true, int64(8192)},
+				{"public", "orders_status_idx", "orders", true, int64(2 << 20), float64(100000), "btree", int64(4), true, int64(8192)},
+				{"public", "orders_total_idx", "orders", false, int64(16384), float64(0), "btree", int64(8), true, int64(8192)},
+				{"public", "orders_note_idx_ccnew", "orders", false, int64(16384), float64(0), "btree", int64(8), true, int64(8192)},
+			}},
+		},
+	}
+
+	result, err := NewReindexTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "table": "orders", "dry_run": true},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| public.orders_pkey on orders | ~73% bloat (estimated) | 8.0 MiB |")
+	assert.Contains(t, text, "| public.orders_total_idx on orders | invalid | 16.0 KiB |")
+	assert.Contains(t, text, `REINDEX INDEX CONCURRENTLY "public"."orders_total_idx";`)
+	assert.Contains(t, text, `DROP INDEX CONCURRENTLY "public"."orders_note_idx_ccnew";`)
+	assert.NotContains(t, text, "orders_status_idx")
+	assert.Len(t, useCase.queries, 1)
+
+	result, err = NewReindexTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "indexes": []interface{}{"orders_status_idx"}, "online": false},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	assert.Equal(t, `REINDEX INDEX "public"."orders_status_idx"`, useCase.queries[2])
+	text = result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| public.orders_status_idx on orders | requested | 2.0 MiB | 2.0 MiB |")
+
+	_, err = NewReindexTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "indexes": []interface{}{"missing_idx"}},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "index missing_idx does not exist")
+}
+
+func TestReindexToolMySQL(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"information_schema.TABLES": {Rows: [][]interface{}{
+				{"shop", "orders", int64(60 << 20), int64(40 << 20)},
+				{"shop", "items", int64(60 << 20), int64(1 << 20)},
+			}},
+			"mysql.innodb_index_stats": {Rows: [][]interface{}{{"orders", int64(64 << 20)}}},
+		},
+	}
+
+	result, err := NewReindexTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1"},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+	assert.Contains(t, useCase.queries, "ALTER TABLE `shop`.`orders` FORCE, ALGORITHM=INPLACE, LOCK=NONE")
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| shop.orders | 40% free space | 64.0 MiB | 64.0 MiB |")
+	assert.NotContains(t, text, "shop.items")
+
+	_, err = NewReindexTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "indexes": []interface{}{"idx_status"}},
+	}, "mysql1", useCase)
+	assert.ErrorContains(t, err, "pass table instead of indexes")
+}
@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// RenameObjectTool handles renaming tables and columns together with their dependents
+type RenameObjectTool struct {
+	BaseToolType
+}
+
+// NewRenameObjectTool creates a new rename tool type
+func NewRenameObjectTool() *RenameObjectTool {
+	return &RenameObjectTool{
+		BaseToolType: BaseToolType{
+			name:        "rename_object",
+			description: "Rename a table or column safely. Before renaming, the tool enumerates the dependent views, foreign keys, triggers and stored routines, then generates every statement the rename needs: on PostgreSQL the rename itself plus, optionally, view output columns and indexes and constraints named after the table; on MySQL the rename plus recreating each view that references the old name. The statements run together in one transaction (MySQL commits DDL implicitly, so there they run in order instead). Routine and trigger bodies are stored as text and are reported, not rewritten. Use dry_run to review the plan first.",
+		},
+	}
+}
+
+// CreateTool creates a rename tool
+func (t *RenameObjectTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Rename a table or column after enumerating and updating its dependents"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Table to rename, or the table of the column to rename"),
+			tools.Required(),
+		),
+		tools.WithString("column",
+			tools.Description("Column to rename; omit to rename the table"),
+		),
+		tools.WithString("new_name",
+			tools.Description("New name of the table or column"),
+			tools.Required(),
+		),
+		tools.WithBoolean("rename_view_columns",
+			tools.Description("PostgreSQL: also rename view output columns named after the renamed column (default: false, views keep their interface)"),
+		),
+		tools.WithBoolean("rename_related",
+			tools.Description("PostgreSQL: also rename indexes and constraints prefixed with the old table name (default: true)"),
+		),
+		tools.WithBoolean("dry_run",
+			tools.Description("Only report dependents and statements without executing them (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles rename tool requests
+func (t *RenameObjectTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	table := input.requiredString("table")
+	target := renameTarget{
+		Column:            input.optionalString("column", ""),
+		NewName:           input.requiredString("new_name"),
+		RenameViewColumns: input.optionalBool("rename_view_columns", false),
+		RenameRelated:     input.optionalBool("rename_related", true),
+	}
+	dryRun := input.optionalBool("dry_run", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	target.Schema, target.Table = splitQualifiedName(table)
+	if strings.Contains(target.NewName, ".") {
+		return nil, fmt.Errorf("new_name must be a bare name; renaming cannot move a table to another schema")
+	}
+	if target.NewName == target.oldName() {
+		return nil, fmt.Errorf("new_name is the current name")
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for rename_object: %s", dbType)
+	}
+	planRename, err := dialect.RenamePlanner()
+	if err != nil {
+		return nil, unsupportedAdminError("rename_object", dbType, err)
+	}
+	plan, err := planRename(ctx, useCase, targetDbID, target)
+	if err != nil {
+		return nil, err
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Rename %s to %s for Database %s\n\n", renameSubject(target), target.NewName, targetDbID))
+	response.WriteString("## Dependents\n\n")
+	writeRenameDependents(&response, "Views", plan.Views)
+	writeRenameDependents(&response, "Foreign keys", plan.ForeignKeys)
+	writeRenameDependents(&response, "Triggers", plan.Triggers)
+	writeRenameDependents(&response, "Routines mentioning "+target.oldName(), plan.Routines)
+
+	response.WriteString("\n## Statements\n\n```sql\n")
+	for _, statement := range plan.Statements {
+		response.WriteString(statement + ";\n")
+	}
+	response.WriteString("```\n")
+
+	if len(plan.Routines) > 0 {
+		plan.Notes = append(plan.Notes, fmt.Sprintf("Routine bodies are stored as text and still refer to %s; update them after the rename.", target.oldName()))
+	}
+	if len(plan.Notes) > 0 {
+		response.WriteString("\n")
+		for _, note := range plan.Notes {
+			response.WriteString(fmt.Sprintf("Note: %s\n", note))
+		}
+	}
+
+	if dryRun {
+		response.WriteString("\nDry run: nothing was executed.\n")
+		return createTextResponse(response.String()), nil
+	}
+	err = useCase.ExecuteBatch(ctx, targetDbID, plan.Statements, nil)
+	audit(request, auditRecord{
+		Tool:      "rename_object",
+		Database:  targetDbID,
+		Action:    "rename",
+		Target:    renameSubject(target),
+		Statement: strings.Join(plan.Statements, ";\n"),
+	}, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rename %s: %w", renameSubject(target), err)
+	}
+	logger.Info("Renamed %s to %s on database %s", renameSubject(target), target.NewName, targetDbID)
+	response.WriteString(fmt.Sprintf("\nExecuted %d statement(s).\n", len(plan.Statements)))
+
+	return createTextResponse(response.String()), nil
+}
+
+// writeRenameDependents renders one group of dependents
+func writeRenameDependents(sb *strings.Builder, title string, items []string) {
+	if len(items) == 0 {
+		sb.WriteString(fmt.Sprintf("- %s: none\n", title))
+		return
+	}
+	sb.WriteString(fmt.Sprintf("- %s:\n", title))
+	for _, item := range items {
+		sb.WriteString(fmt.Sprintf("  - %s\n", item))
+	}
+}
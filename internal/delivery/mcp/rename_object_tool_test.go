@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestRenameInDefinition(t *testing.T) {
+	definition := "select `shop`.`orders`.`id` AS `id`,`o2`.`orders` AS `orders` from (`shop`.`orders` join `shop`.`archive` `o2`) where `shop`.`orders`.`status` = 'orders'"
+
+	renamed, changed, err := renameInDefinition(definition, "mysql", renameTarget{Schema: "shop", Table: "orders", NewName: "purchases"})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "select `shop`.`purchases`.`id` AS `id`,`o2`.`orders` AS `orders` from (`shop`.`purchases` join `shop`.`archive` `o2`) where `shop`.`purchases`.`status` = 'orders'", renamed)
+
+	renamed, changed, err = renameInDefinition(definition, "mysql", renameTarget{Schema: "shop", Table: "orders", Column: "status", NewName: "state"})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Contains(t, renamed, "where `shop`.`orders`.`state` = 'orders'")
+
+	_, changed, err = renameInDefinition(definition, "mysql", renameTarget{Schema: "shop", Table: "orders", Column: "total", NewName: "amount"})
+	assert.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestRenameObjectToolPostgresTable(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"to_regclass(":    {Rows: [][]interface{}{{true, false, int64(0)}}},
+			"JOIN pg_rewrite": {Rows: [][]interface{}{{"order_totals", false, false}}},
+			"c.contype = 'f'": {Rows: [][]interface{}{{"items_order_id_fkey", "items", "orders"}}},
+			"FROM pg_trigger": {Rows: [][]interface{}{{"orders_audit", "audit_row"}}},
+			"FROM pg_proc":    {Rows: [][]interface{}{{"audit_row()"}}},
+			"UNION ALL":       {Rows: [][]interface{}{{"index", "orders_pkey"}, {"constraint", "orders_total_check"}}},
+		},
+	}
+
+	result, err := NewRenameObjectTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "table": "orders", "new_name": "purchases"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{
+		`ALTER TABLE "public"."orders" RENAME TO "purchases"`,
+		`ALTER INDEX "public"."orders_pkey" RENAME TO "purchases_pkey"`,
+		`ALTER TABLE "public"."purchases" RENAME CONSTRAINT "orders_total_check" TO "purchases_total_check"`,
+	}}, useCase.batches)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "  - order_totals (view, follows the rename automatically)")
+	assert.Contains(t, text, "  - items_order_id_fkey (items → orders)")
+	assert.Contains(t, text, "  - orders_audit (executes audit_row)")
+	assert.Contains(t, text, "Note: Routine bodies are stored as text and still refer to orders")
+	assert.Contains(t, text, "Executed 3 statement(s).")
+}
+
+func TestRenameObjectToolPostgresColumn(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"to_regclass(":    {Rows: [][]interface{}{{true, false, int64(3)}}},
+			"JOIN pg_rewrite": {Rows: [][]interface{}{{"order_totals", false, true}}},
+		},
+	}
+
+	_, err := NewRenameObjectTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "table": "orders", "column": "total", "new_name": "amount", "rename_view_columns": true, "dry_run": true},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	assert.Empty(t, useCase.batches)
+	assert.Contains(t, useCase.queries[1], "d.refobjsubid = 3")
+
+	result, err := NewRenameObjectTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "table": "orders", "column": "total", "new_name": "amount", "rename_view_columns": true},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`ALTER TABLE "public"."orders" RENAME COLUMN "total" TO "amount"`,
+		`ALTER VIEW order_totals RENAME COLUMN "total" TO "amount"`,
+	}, useCase.batches[0])
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "order_totals (view, output column total renamed as well)")
+
+	useCase.results["to_regclass("] = &domain.QueryResult{Rows: [][]interface{}{{true, true, int64(3)}}}
+	_, err = NewRenameObjectTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "table": "orders", "column": "total", "new_name": "amount"},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "amount already exists")
+}
+
+func TestRenameObjectToolMySQL(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"EXISTS (SELECT 1 FROM information_schema": {Rows: [][]interface{}{{int64(1), int64(0), "shop"}}},
+			"information_schema.VIEWS": {Rows: [][]interface{}{
+				{"order_totals", "select `shop`.`orders`.`id` AS `id` from `shop`.`orders`", "DEFINER", "NONE"},
+			}},
+			"information_schema.TRIGGERS": {Rows: [][]interface{}{{"orders_bi", "orders", int64(0)}}},
+		},
+	}
+
+	_, err := NewRenameObjectTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "table": "orders", "new_name": "purchases"},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"RENAME TABLE `shop`.`orders` TO `shop`.`purchases`",
+		"CREATE OR REPLACE SQL SECURITY DEFINER VIEW `order_totals` AS select `shop`.`purchases`.`id` AS `id` from `shop`.`purchases`",
+	}, useCase.batches[0])
+
+	_, err = NewRenameObjectTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "table": "orders", "new_name": "shop.purchases"},
+	}, "mysql1", useCase)
+	assert.ErrorContains(t, err, "must be a bare name")
+}
@@ -0,0 +1,448 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// renameTarget is the table or column being renamed
+type renameTarget struct {
+	Schema  string
+	Table   string
+	Column  string
+	NewName string
+
+	// RenameViewColumns also renames view output columns named after a renamed column (PostgreSQL)
+	RenameViewColumns bool
+	// RenameRelated also renames indexes and constraints named after a renamed table (PostgreSQL)
+	RenameRelated bool
+}
+
+// qualifiedTable returns the table name with its schema, if any
+func (r renameTarget) qualifiedTable() string {
+	return qualifiedName(r.Schema, r.Table)
+}
+
+// oldName returns the name being replaced
+func (r renameTarget) oldName() string {
+	if r.Column != "" {
+		return r.Column
+	}
+	return r.Table
+}
+
+// renamePlan lists what depends on the renamed object and the statements performing the rename
+type renamePlan struct {
+	Views       []string
+	ForeignKeys []string
+	Triggers    []string
+	Routines    []string
+	Statements  []string
+	Notes       []string
+}
+
+// renamePlanner finds the dependents of a table or column and builds the statements renaming
+// it together with them
+type renamePlanner func(ctx context.Context, useCase UseCaseProvider, dbID string, target renameTarget) (*renamePlan, error)
+
+// planPostgresRename enumerates dependents and builds the rename statements for PostgreSQL.
+// Views, foreign keys and triggers reference tables and columns by OID and follow renames on their own;
+// only names derived from the old one and routine bodies, which are stored as text, need attention.
+func planPostgresRename(ctx context.Context, useCase UseCaseProvider, dbID string, target renameTarget) (*renamePlan, error) {
+	const dialect = "postgres"
+	if target.Schema == "" {
+		target.Schema = "public"
+	}
+	relation := quoteIdentifier(dialect, target.Schema) + "." + quoteIdentifier(dialect, target.Table)
+	plan := &renamePlan{}
+
+	params := newSQLParams(dialect)
+	relParam := params.add(relation)
+	existsQuery := fmt.Sprintf("SELECT to_regclass(%s) IS NOT NULL, to_regclass(%s) IS NOT NULL, 0",
+		relParam, params.add(quoteIdentifier(dialect, target.Schema)+"."+quoteIdentifier(dialect, target.NewName)))
+	if target.Column != "" {
+		params = newSQLParams(dialect)
+		relParam = params.add(relation)
+		existsQuery = fmt.Sprintf(`
+SELECT
+    EXISTS (SELECT 1 FROM pg_attribute WHERE attrelid = to_regclass(%[1]s) AND attname = %[2]s AND NOT attisdropped),
+    EXISTS (SELECT 1 FROM pg_attribute WHERE attrelid = to_regclass(%[1]s) AND attname = %[3]s AND NOT attisdropped),
+    COALESCE((SELECT attnum FROM pg_attribute WHERE attrelid = to_regclass(%[1]s) AND attname = %[2]s), 0)`,
+			relParam, params.add(target.Column), params.add(target.NewName))
+	}
+	result, err := useCase.ExecuteQuery(ctx, dbID, existsQuery, params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up %s: %w", target.qualifiedTable(), err)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) < 3 || !valueBool(result.Rows[0][0]) {
+		return nil, fmt.Errorf("%s does not exist", renameSubject(target))
+	}
+	if valueBool(result.Rows[0][1]) {
+		return nil, fmt.Errorf("%s already exists", target.NewName)
+	}
+	attnum := valueInt64(result.Rows[0][2])
+
+	// Views and materialized views depending on the table, or on the column
+	params = newSQLParams(dialect)
+	relParam = params.add(relation)
+	oldParam := params.add(target.oldName())
+	columnFilter := ""
+	if target.Column != "" {
+		columnFilter = fmt.Sprintf(" AND d.refobjsubid = %d", attnum)
+	}
+	result, err = useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT DISTINCT v.oid::regclass::text, v.relkind = 'm',
+       EXISTS (SELECT 1 FROM pg_attribute a WHERE a.attrelid = v.oid AND a.attname = %[2]s)
+FROM pg_depend d
+JOIN pg_rewrite r ON r.oid = d.objid
+JOIN pg_class v ON v.oid = r.ev_class
+WHERE d.classid = 'pg_rewrite'::regclass AND d.refobjid = %[1]s::regclass AND v.oid <> d.refobjid%[3]s
+ORDER BY 1`, relParam, oldParam, columnFilter), params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dependent views: %w", err)
+	}
+	var viewRenames []string
+	for _, row := range result.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		view, materialized, exposesColumn := valueString(row[0]), valueBool(row[1]), valueBool(row[2])
+		kind := "VIEW"
+		if materialized {
+			kind = "MATERIALIZED VIEW"
+		}
+		description := fmt.Sprintf("%s (%s, follows the rename automatically)", view, strings.ToLower(kind))
+		if target.Column != "" && exposesColumn {
+			if target.RenameViewColumns {
+				viewRenames = append(viewRenames, fmt.Sprintf("ALTER %s %s RENAME COLUMN %s TO %s",
+					kind, view, quoteIdentifier(dialect, target.Column), quoteIdentifier(dialect, target.NewName)))
+				description = fmt.Sprintf("%s (%s, output column %s renamed as well)", view, strings.ToLower(kind), target.Column)
+			} else {
+				description = fmt.Sprintf("%s (%s, keeps exposing the column as %s; set rename_view_columns to rename it too)", view, strings.ToLower(kind), target.Column)
+			}
+		}
+		plan.Views = append(plan.Views, description)
+	}
+
+	// Foreign keys from and to the table, or involving the column
+	params = newSQLParams(dialect)
+	relParam = params.add(relation)
+	fkFilter := fmt.Sprintf("(c.conrelid = %[1]s::regclass OR c.confrelid = %[1]s::regclass)", relParam)
+	if target.Column != "" {
+		fkFilter = fmt.Sprintf("((c.conrelid = %[1]s::regclass AND %[2]d = ANY(c.conkey)) OR (c.confrelid = %[1]s::regclass AND %[2]d = ANY(c.confkey)))", relParam, attnum)
+	}
+	result, err = useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT c.conname, c.conrelid::regclass::text, c.confrelid::regclass::text
+FROM pg_constraint c
+WHERE c.contype = 'f' AND %s
+ORDER BY 1`, fkFilter), params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find foreign keys: %w", err)
+	}
+	for _, row := range result.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		plan.ForeignKeys = append(plan.ForeignKeys, fmt.Sprintf("%s (%s → %s)", valueString(row[0]), valueString(row[1]), valueString(row[2])))
+	}
+
+	// Triggers on the table; their functions are checked with the other routines
+	params = newSQLParams(dialect)
+	result, err = useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT t.tgname, t.tgfoid::regproc::text
+FROM pg_trigger t
+WHERE t.tgrelid = %s::regclass AND NOT t.tgisinternal
+ORDER BY 1`, params.add(relation)), params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find triggers: %w", err)
+	}
+	for _, row := range result.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		plan.Triggers = append(plan.Triggers, fmt.Sprintf("%s (executes %s)", valueString(row[0]), valueString(row[1])))
+	}
+
+	// Function and procedure bodies are stored as text and are not rewritten by a rename
+	params = newSQLParams(dialect)
+	result, err = useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT p.oid::regprocedure::text
+FROM pg_proc p
+JOIN pg_namespace n ON n.oid = p.pronamespace
+WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND position(lower(%s) in lower(p.prosrc)) > 0
+ORDER BY 1`, params.add(target.oldName())), params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search routines: %w", err)
+	}
+	for _, row := range result.Rows {
+		if len(row) > 0 {
+			plan.Routines = append(plan.Routines, valueString(row[0]))
+		}
+	}
+
+	if target.Column != "" {
+		plan.Statements = append(plan.Statements, fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s",
+			relation, quoteIdentifier(dialect, target.Column), quoteIdentifier(dialect, target.NewName)))
+		plan.Statements = append(plan.Statements, viewRenames...)
+		return plan, nil
+	}
+
+	plan.Statements = append(plan.Statements, fmt.Sprintf("ALTER TABLE %s RENAME TO %s", relation, quoteIdentifier(dialect, target.NewName)))
+	if !target.RenameRelated {
+		return plan, nil
+	}
+	// Indexes (renaming one also renames the primary key, unique or exclusion constraint it backs)
+	// and the remaining constraints conventionally carry the table name as a prefix
+	params = newSQLParams(dialect)
+	relParam = params.add(relation)
+	prefixParam := params.add(target.Table + "_")
+	result, err = useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT 'index', c.relname
+FROM pg_index i
+JOIN pg_class c ON c.oid = i.indexrelid
+WHERE i.indrelid = %[1]s::regclass AND left(c.relname, length(%[2]s)) = %[2]s
+UNION ALL
+SELECT 'constraint', c.conname
+FROM pg_constraint c
+WHERE c.conrelid = %[1]s::regclass AND c.contype IN ('f', 'c') AND left(c.conname, length(%[2]s)) = %[2]s
+ORDER BY 1 DESC, 2`, relParam, prefixParam), params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find indexes and constraints named after %s: %w", target.Table, err)
+	}
+	renamed := quoteIdentifier(dialect, target.Schema) + "." + quoteIdentifier(dialect, target.NewName)
+	for _, row := range result.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		name := valueString(row[1])
+		newName := target.NewName + strings.TrimPrefix(name, target.Table)
+		if valueString(row[0]) == "index" {
+			plan.Statements = append(plan.Statements, fmt.Sprintf("ALTER INDEX %s.%s RENAME TO %s",
+				quoteIdentifier(dialect, target.Schema), quoteIdentifier(dialect, name), quoteIdentifier(dialect, newName)))
+		} else {
+			plan.Statements = append(plan.Statements, fmt.Sprintf("ALTER TABLE %s RENAME CONSTRAINT %s TO %s",
+				renamed, quoteIdentifier(dialect, name), quoteIdentifier(dialect, newName)))
+		}
+	}
+	return plan, nil
+}
+
+// planMySQLRename enumerates dependents and builds the rename statements for MySQL.
+// Foreign keys follow renames, but views store their definition as text and have to be recreated.
+func planMySQLRename(ctx context.Context, useCase UseCaseProvider, dbID string, target renameTarget) (*renamePlan, error) {
+	const dialect = "mysql"
+	plan := &renamePlan{}
+	schemaExpr := func(params *sqlParams) string {
+		if target.Schema == "" {
+			return "DATABASE()"
+		}
+		return params.add(target.Schema)
+	}
+
+	params := newSQLParams(dialect)
+	var existsQuery string
+	if target.Column == "" {
+		schema := schemaExpr(params)
+		existsQuery = fmt.Sprintf(`
+SELECT
+    EXISTS (SELECT 1 FROM information_schema.TABLES WHERE TABLE_SCHEMA = %[1]s AND TABLE_NAME = %[2]s),
+    EXISTS (SELECT 1 FROM information_schema.TABLES WHERE TABLE_SCHEMA = %[1]s AND TABLE_NAME = %[3]s),
+    DATABASE()`, schema, params.add(target.Table), params.add(target.NewName))
+	} else {
+		schema := schemaExpr(params)
+		table := params.add(target.Table)
+		existsQuery = fmt.Sprintf(`
+SELECT
+    EXISTS (SELECT 1 FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = %[1]s AND TABLE_NAME = %[2]s AND COLUMN_NAME = %[3]s),
+    EXISTS (SELECT 1 FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = %[1]s AND TABLE_NAME = %[2]s AND COLUMN_NAME = %[4]s),
+    DATABASE()`, schema, table, params.add(target.Column), params.add(target.NewName))
+	}
+	result, err := useCase.ExecuteQuery(ctx, dbID, existsQuery, params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up %s: %w", target.qualifiedTable(), err)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) < 3 || !valueBool(result.Rows[0][0]) {
+		return nil, fmt.Errorf("%s does not exist", renameSubject(target))
+	}
+	if valueBool(result.Rows[0][1]) {
+		return nil, fmt.Errorf("%s already exists", target.NewName)
+	}
+	if target.Schema == "" {
+		target.Schema = valueString(result.Rows[0][2])
+	}
+
+	// Views referencing the table; MySQL stores their definitions fully qualified and backquoted
+	params = newSQLParams(dialect)
+	result, err = useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT TABLE_NAME, VIEW_DEFINITION, SECURITY_TYPE, CHECK_OPTION
+FROM information_schema.VIEWS
+WHERE TABLE_SCHEMA = %s AND LOCATE(%s, VIEW_DEFINITION) > 0
+ORDER BY TABLE_NAME`, schemaExpr(params), params.add(quoteIdentifier(dialect, target.Table))), params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dependent views: %w", err)
+	}
+	var viewStatements []string
+	for _, row := range result.Rows {
+		if len(row) < 4 {
+			continue
+		}
+		view := valueString(row[0])
+		definition, changed, err := renameInDefinition(valueString(row[1]), dialect, target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewrite view %s: %w", view, err)
+		}
+		if !changed {
+			continue
+		}
+		statement := fmt.Sprintf("CREATE OR REPLACE SQL SECURITY %s VIEW %s AS %s", valueString(row[2]), quoteIdentifier(dialect, view), definition)
+		if option := valueString(row[3]); option != "" && !strings.EqualFold(option, "NONE") {
+			statement += fmt.Sprintf(" WITH %s CHECK OPTION", option)
+		}
+		viewStatements = append(viewStatements, statement)
+		plan.Views = append(plan.Views, fmt.Sprintf("%s (recreated with the new name)", view))
+	}
+	if len(viewStatements) > 0 {
+		plan.Notes = append(plan.Notes, "Recreated views get the connecting user as definer.")
+	}
+
+	// Foreign keys from and to the table, or involving the column
+	params = newSQLParams(dialect)
+	schema := schemaExpr(params)
+	table := params.add(target.Table)
+	fkFilter := fmt.Sprintf("(TABLE_NAME = %[1]s OR REFERENCED_TABLE_NAME = %[1]s)", table)
+	if target.Column != "" {
+		column := params.add(target.Column)
+		fkFilter = fmt.Sprintf("((TABLE_NAME = %[1]s AND COLUMN_NAME = %[2]s) OR (REFERENCED_TABLE_NAME = %[1]s AND REFERENCED_COLUMN_NAME = %[2]s))", table, column)
+	}
+	result, err = useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT DISTINCT CONSTRAINT_NAME, TABLE_NAME, REFERENCED_TABLE_NAME
+FROM information_schema.KEY_COLUMN_USAGE
+WHERE TABLE_SCHEMA = %s AND REFERENCED_TABLE_NAME IS NOT NULL AND %s
+ORDER BY CONSTRAINT_NAME`, schema, fkFilter), params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find foreign keys: %w", err)
+	}
+	for _, row := range result.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		plan.ForeignKeys = append(plan.ForeignKeys, fmt.Sprintf("%s (%s → %s)", valueString(row[0]), valueString(row[1]), valueString(row[2])))
+	}
+
+	// Triggers on the table, and any trigger whose body mentions the old name
+	params = newSQLParams(dialect)
+	schema = schemaExpr(params)
+	result, err = useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT TRIGGER_NAME, EVENT_OBJECT_TABLE, LOCATE(%[3]s, ACTION_STATEMENT) > 0
+FROM information_schema.TRIGGERS
+WHERE TRIGGER_SCHEMA = %[1]s AND (EVENT_OBJECT_TABLE = %[2]s OR LOCATE(%[3]s, ACTION_STATEMENT) > 0)
+ORDER BY TRIGGER_NAME`, schema, params.add(target.Table), params.add(target.oldName())), params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find triggers: %w", err)
+	}
+	for _, row := range result.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		description := fmt.Sprintf("%s (on %s)", valueString(row[0]), valueString(row[1]))
+		if valueBool(row[2]) {
+			description = fmt.Sprintf("%s (on %s, its body mentions %s and is not rewritten)", valueString(row[0]), valueString(row[1]), target.oldName())
+		}
+		plan.Triggers = append(plan.Triggers, description)
+	}
+
+	// Stored routine bodies are stored as text and are not rewritten by a rename
+	params = newSQLParams(dialect)
+	schema = schemaExpr(params)
+	result, err = useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(`
+SELECT CONCAT(ROUTINE_TYPE, ' ', ROUTINE_NAME)
+FROM information_schema.ROUTINES
+WHERE ROUTINE_SCHEMA = %s AND LOCATE(%s, ROUTINE_DEFINITION) > 0
+ORDER BY ROUTINE_NAME`, schema, params.add(target.oldName())), params.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search routines: %w", err)
+	}
+	for _, row := range result.Rows {
+		if len(row) > 0 {
+			plan.Routines = append(plan.Routines, valueString(row[0]))
+		}
+	}
+
+	tableIdent := quoteIdentifier(dialect, qualifiedName(target.Schema, target.Table))
+	if target.Column != "" {
+		plan.Statements = append(plan.Statements, fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s",
+			tableIdent, quoteIdentifier(dialect, target.Column), quoteIdentifier(dialect, target.NewName)))
+	} else {
+		plan.Statements = append(plan.Statements, fmt.Sprintf("RENAME TABLE %s TO %s",
+			tableIdent, quoteIdentifier(dialect, qualifiedName(target.Schema, target.NewName))))
+	}
+	plan.Statements = append(plan.Statements, viewStatements...)
+	return plan, nil
+}
+
+// renameInDefinition replaces references to the renamed table or column in a view definition as
+// MySQL stores it, fully qualified: tables are matched as schema.table (also qualifying a column)
+// and columns as schema.table.column or table.column. Aliases are left alone, so output column
+// names do not change and the view keeps its interface.
+func renameInDefinition(definition, dialect string, target renameTarget) (string, bool, error) {
+	tokens, err := tokenizeSQL(definition, dialect)
+	if err != nil {
+		return "", false, err
+	}
+	isIdent := func(t sqlToken) bool { return t.kind == sqlWord || t.kind == sqlQuotedIdent }
+	matches := func(t sqlToken, name string) bool {
+		if t.kind == sqlQuotedIdent {
+			return t.text == name
+		}
+		return t.kind == sqlWord && strings.EqualFold(t.text, name)
+	}
+
+	changed := false
+	for i := 0; i < len(tokens); {
+		if !isIdent(tokens[i]) {
+			i++
+			continue
+		}
+		// Collect the dotted chain starting here, e.g. schema.table.column
+		chain := []int{i}
+		j := i + 1
+		for j+1 < len(tokens) && tokens[j].is(".") && isIdent(tokens[j+1]) {
+			chain = append(chain, j+1)
+			j += 2
+		}
+
+		replace := -1
+		if target.Column == "" {
+			if len(chain) >= 2 && matches(tokens[chain[0]], target.Schema) && matches(tokens[chain[1]], target.Table) {
+				replace = chain[1]
+			}
+		} else {
+			n := len(chain)
+			if n >= 2 && matches(tokens[chain[n-2]], target.Table) && matches(tokens[chain[n-1]], target.Column) &&
+				(n == 2 || matches(tokens[chain[n-3]], target.Schema)) {
+				replace = chain[n-1]
+			}
+		}
+		if replace >= 0 {
+			tokens[replace] = sqlToken{kind: sqlQuotedIdent, text: target.NewName, quote: '`'}
+			changed = true
+		}
+		i = j
+	}
+
+	var sb strings.Builder
+	for _, t := range tokens {
+		sb.WriteString(t.render(dialect))
+	}
+	return sb.String(), changed, nil
+}
+
+// renameSubject describes the renamed object for messages
+func renameSubject(target renameTarget) string {
+	if target.Column != "" {
+		return fmt.Sprintf("column %s.%s", target.qualifiedTable(), target.Column)
+	}
+	return fmt.Sprintf("table %s", target.qualifiedTable())
+}
@@ -0,0 +1,386 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// replicationSlot is a row of pg_replication_slots with the WAL it holds back
+type replicationSlot struct {
+	Name          string
+	Plugin        string
+	Type          string
+	Database      string
+	Active        bool
+	ActivePID     int64
+	RestartLSN    string
+	ConfirmedLSN  string
+	RetainedBytes int64
+	WALStatus     string
+	SafeWALSize   int64
+	HasSafeWAL    bool
+}
+
+// binaryLog is a row of SHOW BINARY LOGS
+type binaryLog struct {
+	Name  string
+	Bytes int64
+}
+
+// ReplicationSlotsTool handles PostgreSQL replication slots and MySQL binlog retention
+type ReplicationSlotsTool struct {
+	BaseToolType
+}
+
+// NewReplicationSlotsTool creates a new replication slot tool type
+func NewReplicationSlotsTool() *ReplicationSlotsTool {
+	return &ReplicationSlotsTool{
+		BaseToolType: BaseToolType{
+			name:        "replication_slots",
+			description: "List, create and drop PostgreSQL replication slots. The list shows how much WAL every slot holds back and warns about inactive slots and slots retaining more than warn_retained_mb: an abandoned slot keeps WAL forever and silently fills the disk. On MySQL, list reports the binary logs on disk and the binlog retention settings instead; MySQL has no replication slots to create or drop.",
+		},
+	}
+}
+
+// CreateTool creates a replication slot tool
+func (t *ReplicationSlotsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("List, create and drop replication slots with WAL retention warnings"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("action",
+			tools.Description("list, create or drop (default: list)"),
+		),
+		tools.WithString("slot",
+			tools.Description("Slot name for create and drop"),
+		),
+		tools.WithString("type",
+			tools.Description("Slot type for create: logical or physical (default: logical)"),
+		),
+		tools.WithString("plugin",
+			tools.Description("Output plugin of a logical slot (default: pgoutput)"),
+		),
+		tools.WithNumber("warn_retained_mb",
+			tools.Description("Warn about slots holding back more WAL than this, or binary logs larger than this in total on MySQL (default: 1024)"),
+		),
+	)
+}
+
+// HandleRequest handles replication slot tool requests
+func (t *ReplicationSlotsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	action := input.choice("action", "list", "list", "create", "drop")
+	slot := input.optionalString("slot", "")
+	slotType := input.choice("type", "logical", "logical", "physical")
+	plugin := input.optionalString("plugin", "pgoutput")
+	warnMB := input.optionalFloat("warn_retained_mb", 1024)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if warnMB <= 0 {
+		return nil, fmt.Errorf("warn_retained_mb parameter must be greater than 0")
+	}
+	warnBytes := int64(warnMB * (1 << 20))
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for replication_slots: %s", dbType)
+	}
+	manager, err := dialect.ReplicationSlotManager()
+	if err != nil {
+		return nil, unsupportedAdminError("replication_slots", dbType, err)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Replication Slots for Database %s\n\n", targetDbID))
+
+	switch action {
+	case "list":
+	case "create":
+		if slot == "" {
+			return nil, fmt.Errorf("slot parameter is required for create")
+		}
+		if err := manager.create(ctx, useCase, targetDbID, slot, slotType, plugin, &response); err != nil {
+			return nil, err
+		}
+	case "drop":
+		if slot == "" {
+			return nil, fmt.Errorf("slot parameter is required for drop")
+		}
+		if err := manager.drop(ctx, useCase, targetDbID, slot, &response); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid action: %s (expected list, create or drop)", action)
+	}
+
+	if err := manager.list(ctx, useCase, targetDbID, warnBytes, &response); err != nil {
+		return nil, err
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// replicationSlotManager lists, creates and drops what holds back the change log for replicas
+type replicationSlotManager interface {
+	// list writes what retains the change log and warns above warnBytes
+	list(ctx context.Context, useCase UseCaseProvider, dbID string, warnBytes int64, sb *strings.Builder) error
+	// create creates a slot of the given type and output plugin
+	create(ctx context.Context, useCase UseCaseProvider, dbID, slot, slotType, plugin string, sb *strings.Builder) error
+	// drop drops a slot no client is consuming
+	drop(ctx context.Context, useCase UseCaseProvider, dbID, slot string, sb *strings.Builder) error
+}
+
+// postgresSlotManager manages the replication slots of PostgreSQL
+type postgresSlotManager struct{}
+
+func (postgresSlotManager) list(ctx context.Context, useCase UseCaseProvider, dbID string, warnBytes int64, sb *strings.Builder) error {
+	slots, err := loadReplicationSlots(ctx, useCase, dbID)
+	if err != nil {
+		return err
+	}
+	writeReplicationSlots(sb, slots, warnBytes)
+	return nil
+}
+
+func (postgresSlotManager) create(ctx context.Context, useCase UseCaseProvider, dbID, slot, slotType, plugin string, sb *strings.Builder) error {
+	var query string
+	params := []interface{}{slot}
+	switch slotType {
+	case "logical":
+		query = "SELECT slot_name, lsn::text FROM pg_create_logical_replication_slot($1, $2)"
+		params = append(params, plugin)
+	case "physical":
+		// Reserve WAL right away so the slot is usable before a client first connects
+		query = "SELECT slot_name, lsn::text FROM pg_create_physical_replication_slot($1, true)"
+	default:
+		return fmt.Errorf("invalid slot type: %s (expected logical or physical)", slotType)
+	}
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, params)
+	if err != nil {
+		return fmt.Errorf("failed to create replication slot %s: %w", slot, err)
+	}
+	logger.Info("Created %s replication slot %s on database %s", slotType, slot, dbID)
+	lsn := ""
+	if len(result.Rows) > 0 && len(result.Rows[0]) > 1 {
+		lsn = valueString(result.Rows[0][1])
+	}
+	sb.WriteString(fmt.Sprintf("Created %s replication slot %s", slotType, slot))
+	if lsn != "" {
+		sb.WriteString(fmt.Sprintf(" at LSN %s", lsn))
+	}
+	sb.WriteString(".\n\nThe slot holds back WAL from now on until a client consumes it; drop it if it will not be used.\n\n")
+	return nil
+}
+
+func (postgresSlotManager) drop(ctx context.Context, useCase UseCaseProvider, dbID, slot string, sb *strings.Builder) error {
+	slots, err := loadReplicationSlots(ctx, useCase, dbID)
+	if err != nil {
+		return err
+	}
+	var found *replicationSlot
+	for i := range slots {
+		if slots[i].Name == slot {
+			found = &slots[i]
+		}
+	}
+	if found == nil {
+		return fmt.Errorf("replication slot %s does not exist", slot)
+	}
+	if found.Active {
+		return fmt.Errorf("replication slot %s is in use by process %d; stop the consumer before dropping it", slot, found.ActivePID)
+	}
+	if _, err := useCase.ExecuteQuery(ctx, dbID, "SELECT pg_drop_replication_slot($1)", []interface{}{slot}); err != nil {
+		return fmt.Errorf("failed to drop replication slot %s: %w", slot, err)
+	}
+	logger.Warn("Dropped replication slot %s on database %s", slot, dbID)
+	sb.WriteString(fmt.Sprintf("Dropped replication slot %s, releasing %s of WAL.\n\n", slot, formatBackupSize(found.RetainedBytes)))
+	return nil
+}
+
+// mysqlSlotManager reports the binary log retention, which takes the place of slots on MySQL
+type mysqlSlotManager struct{}
+
+func (mysqlSlotManager) list(ctx context.Context, useCase UseCaseProvider, dbID string, warnBytes int64, sb *strings.Builder) error {
+	return writeBinlogRetention(ctx, useCase, dbID, warnBytes, sb)
+}
+
+func (mysqlSlotManager) create(context.Context, UseCaseProvider, string, string, string, string, *strings.Builder) error {
+	return errMySQLNoSlots
+}
+
+func (mysqlSlotManager) drop(context.Context, UseCaseProvider, string, string, *strings.Builder) error {
+	return errMySQLNoSlots
+}
+
+// errMySQLNoSlots refuses the slot actions other than list on MySQL
+var errMySQLNoSlots = errors.New("MySQL has no replication slots; only list is supported")
+
+// loadReplicationSlots reads the replication slots and the WAL each one retains
+func loadReplicationSlots(ctx context.Context, useCase UseCaseProvider, dbID string) ([]replicationSlot, error) {
+	// wal_status and safe_wal_size only exist from PostgreSQL 13 on, so read them through to_jsonb
+	result, err := useCase.ExecuteQuery(ctx, dbID, `
+SELECT
+    s.slot_name,
+    COALESCE(s.plugin, ''),
+    s.slot_type,
+    COALESCE(s.database, ''),
+    s.active,
+    COALESCE(s.active_pid, 0),
+    COALESCE(s.restart_lsn::text, ''),
+    COALESCE(s.confirmed_flush_lsn::text, ''),
+    COALESCE(pg_wal_lsn_diff(
+        CASE WHEN pg_is_in_recovery() THEN pg_last_wal_receive_lsn() ELSE pg_current_wal_lsn() END,
+        s.restart_lsn), 0)::bigint AS retained_bytes,
+    COALESCE(to_jsonb(s) ->> 'wal_status', ''),
+    (to_jsonb(s) ->> 'safe_wal_size')::bigint
+FROM pg_replication_slots s
+ORDER BY retained_bytes DESC, s.slot_name`, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replication slots: %w", err)
+	}
+	slots := make([]replicationSlot, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 11 {
+			continue
+		}
+		slots = append(slots, replicationSlot{
+			Name:          valueString(row[0]),
+			Plugin:        valueString(row[1]),
+			Type:          valueString(row[2]),
+			Database:      valueString(row[3]),
+			Active:        valueBool(row[4]),
+			ActivePID:     valueInt64(row[5]),
+			RestartLSN:    valueString(row[6]),
+			ConfirmedLSN:  valueString(row[7]),
+			RetainedBytes: valueInt64(row[8]),
+			WALStatus:     valueString(row[9]),
+			SafeWALSize:   valueInt64(row[10]),
+			HasSafeWAL:    row[10] != nil,
+		})
+	}
+	return slots, nil
+}
+
+// replicationSlotWarnings explains which slots put the disk at risk
+func replicationSlotWarnings(slots []replicationSlot, warnBytes int64) []string {
+	var warnings []string
+	for _, s := range slots {
+		switch {
+		case s.WALStatus == "lost":
+			warnings = append(warnings, fmt.Sprintf("Slot %s has lost WAL it needs and can no longer be used; drop it and resynchronize its consumer.", s.Name))
+		case !s.Active && s.RetainedBytes >= warnBytes:
+			warnings = append(warnings, fmt.Sprintf("Slot %s is inactive and holds back %s of WAL, which keeps growing until it is consumed or dropped.", s.Name, formatBackupSize(s.RetainedBytes)))
+		case !s.Active:
+			warnings = append(warnings, fmt.Sprintf("Slot %s is inactive; it retains all WAL written from now on until a consumer reconnects.", s.Name))
+		case s.RetainedBytes >= warnBytes:
+			warnings = append(warnings, fmt.Sprintf("Slot %s holds back %s of WAL; its consumer is falling behind.", s.Name, formatBackupSize(s.RetainedBytes)))
+		}
+		if s.WALStatus == "unreserved" {
+			warnings = append(warnings, fmt.Sprintf("Slot %s is past max_slot_wal_keep_size and will lose WAL at the next checkpoint.", s.Name))
+		}
+	}
+	return warnings
+}
+
+// writeReplicationSlots renders the slots and their warnings
+func writeReplicationSlots(sb *strings.Builder, slots []replicationSlot, warnBytes int64) {
+	if len(slots) == 0 {
+		sb.WriteString("No replication slots.\n")
+		return
+	}
+	sb.WriteString("| Slot | Type | Plugin | Database | Active | Restart LSN | Confirmed LSN | WAL retained | WAL status |\n")
+	sb.WriteString("|------|------|--------|----------|--------|-------------|---------------|--------------|------------|\n")
+	var total int64
+	for _, s := range slots {
+		active := "no"
+		if s.Active {
+			active = fmt.Sprintf("yes (pid %d)", s.ActivePID)
+		}
+		status := s.WALStatus
+		if s.HasSafeWAL {
+			status += fmt.Sprintf(", %s until unreserved", formatBackupSize(s.SafeWALSize))
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			s.Name, s.Type, s.Plugin, s.Database, active, s.RestartLSN, s.ConfirmedLSN, formatBackupSize(s.RetainedBytes), status))
+		total += s.RetainedBytes
+	}
+	sb.WriteString(fmt.Sprintf("\nTotal WAL held back by slots: %s\n", formatBackupSize(total)))
+
+	if warnings := replicationSlotWarnings(slots, warnBytes); len(warnings) > 0 {
+		sb.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+}
+
+// writeBinlogRetention reports the binary logs on disk and how long MySQL keeps them
+func writeBinlogRetention(ctx context.Context, useCase UseCaseProvider, dbID string, warnBytes int64, sb *strings.Builder) error {
+	settings, err := useCase.ExecuteQuery(ctx, dbID, "SELECT @@log_bin, @@binlog_expire_logs_seconds, @@max_binlog_size", nil)
+	if err != nil {
+		return fmt.Errorf("failed to read binlog settings: %w", err)
+	}
+	if len(settings.Rows) == 0 || len(settings.Rows[0]) < 3 || !valueBool(settings.Rows[0][0]) {
+		sb.WriteString("Binary logging is disabled.\n")
+		return nil
+	}
+	expireSeconds := valueInt64(settings.Rows[0][1])
+	maxSize := valueInt64(settings.Rows[0][2])
+
+	result, err := useCase.ExecuteQuery(ctx, dbID, "SHOW BINARY LOGS", nil)
+	if err != nil {
+		return fmt.Errorf("failed to list binary logs: %w", err)
+	}
+	var logs []binaryLog
+	var total int64
+	for _, row := range result.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		log := binaryLog{Name: valueString(row[0]), Bytes: valueInt64(row[1])}
+		logs = append(logs, log)
+		total += log.Bytes
+	}
+
+	sb.WriteString("MySQL has no replication slots; binary logs are kept by time instead.\n\n")
+	if expireSeconds > 0 {
+		sb.WriteString(fmt.Sprintf("Retention (binlog_expire_logs_seconds): %d seconds (%.1f days)\n", expireSeconds, float64(expireSeconds)/86400))
+	} else {
+		sb.WriteString("Retention (binlog_expire_logs_seconds): 0, binary logs are never purged automatically\n")
+	}
+	sb.WriteString(fmt.Sprintf("Maximum file size (max_binlog_size): %s\n", formatBackupSize(maxSize)))
+	sb.WriteString(fmt.Sprintf("Binary logs on disk: %d files, %s\n", len(logs), formatBackupSize(total)))
+	if len(logs) > 0 {
+		sb.WriteString(fmt.Sprintf("Oldest: %s, newest: %s\n", logs[0].Name, logs[len(logs)-1].Name))
+	}
+
+	var warnings []string
+	if expireSeconds == 0 {
+		warnings = append(warnings, "Binary logs are never purged; set binlog_expire_logs_seconds or purge them with PURGE BINARY LOGS once replicas have read them.")
+	}
+	if total >= warnBytes {
+		warnings = append(warnings, fmt.Sprintf("Binary logs take %s of disk; make sure replicas keep up so they can be purged.", formatBackupSize(total)))
+	}
+	if len(warnings) > 0 {
+		sb.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestReplicationSlotsToolList(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_replication_slots": {Rows: [][]interface{}{
+				{"stale_sub", "pgoutput", "logical", "shop", false, int64(0), "0/5000000", "0/5000060", int64(3 << 30), "extended", nil},
+				{"replica_1", "", "physical", "", true, int64(4321), "1/2000000", "", int64(1 << 20), "reserved", int64(512 << 20)},
+			}},
+		},
+	}
+
+	result, err := NewReplicationSlotsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| stale_sub | logical | pgoutput | shop | no | 0/5000000 | 0/5000060 | 3.0 GiB | extended |")
+	assert.Contains(t, text, "| replica_1 | physical |  |  | yes (pid 4321) | 1/2000000 |  | 1.0 MiB | reserved, 512.0 MiB until unreserved |")
+	assert.Contains(t, text, "Slot stale_sub is inactive and holds back 3.0 GiB of WAL")
+	assert.NotContains(t, text, "Slot replica_1")
+}
+
+func TestReplicationSlotsToolCreateAndDrop(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_replication_slots": {Rows: [][]interface{}{
+				{"busy", "pgoutput", "logical", "shop", true, int64(77), "0/1", "0/1", int64(0), "reserved", nil},
+				{"idle", "pgoutput", "logical", "shop", false, int64(0), "0/1", "0/1", int64(2048), "reserved", nil},
+			}},
+		},
+	}
+	tool := NewReplicationSlotsTool()
+
+	_, err := tool.HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "action": "create", "slot": "cdc", "plugin": "wal2json"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	assert.Contains(t, useCase.queries[0], "pg_create_logical_replication_slot($1, $2)")
+
+	_, err = tool.HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "action": "drop", "slot": "busy"},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "in use by process 77")
+
+	result, err := tool.HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "action": "drop", "slot": "idle"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	assert.Contains(t, useCase.queries, "SELECT pg_drop_replication_slot($1)")
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "Dropped replication slot idle, releasing 2.0 KiB of WAL.")
+
+	_, err = tool.HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "action": "create", "slot": "x", "type": "snapshot"},
+	}, "pg1", useCase)
+	assert.EqualError(t, err, "type parameter must be logical or physical")
+}
+
+func TestReplicationSlotsToolMySQLBinlogs(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"@@log_bin":        {Rows: [][]interface{}{{int64(1), int64(0), int64(1 << 30)}}},
+			"SHOW BINARY LOGS": {Rows: [][]interface{}{{"binlog.000001", int64(1 << 30), "No"}, {"binlog.000002", int64(512 << 20), "No"}}},
+		},
+	}
+
+	result, err := NewReplicationSlotsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1"},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "Binary logs on disk: 2 files, 1.5 GiB")
+	assert.Contains(t, text, "Oldest: binlog.000001, newest: binlog.000002")
+	assert.Contains(t, text, "Binary logs are never purged")
+	assert.Contains(t, text, "Binary logs take 1.5 GiB of disk")
+
+	_, err = NewReplicationSlotsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "action": "drop", "slot": "x"},
+	}, "mysql1", useCase)
+	assert.ErrorContains(t, err, "MySQL has no replication slots")
+}
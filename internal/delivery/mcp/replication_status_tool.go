@@ -0,0 +1,429 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// replicationLink is one replication connection, either to a replica of this server or
+// from this server to its source
+type replicationLink struct {
+	Name          string
+	Peer          string
+	State         string
+	Sync          string
+	Position      string
+	LagBytes      int64
+	HasLagBytes   bool
+	LagSeconds    float64
+	HasLagSeconds bool
+	Error         string
+}
+
+// lagBytes renders the byte lag, or - when it is unknown
+func (l replicationLink) lagBytes() string {
+	if !l.HasLagBytes {
+		return "-"
+	}
+	return formatBackupSize(l.LagBytes)
+}
+
+// lagSeconds renders the time lag, or - when it is unknown
+func (l replicationLink) lagSeconds() string {
+	if !l.HasLagSeconds {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", l.LagSeconds)
+}
+
+// replicationStatus is the replication state of one server
+type replicationStatus struct {
+	Role        string
+	Replicas    []replicationLink
+	Upstream    []replicationLink
+	ReplicaNote string
+	Warnings    []string
+}
+
+// ReplicationStatusTool handles reporting replication topology and lag
+type ReplicationStatusTool struct {
+	BaseToolType
+}
+
+// NewReplicationStatusTool creates a new replication status tool type
+func NewReplicationStatusTool() *ReplicationStatusTool {
+	return &ReplicationStatusTool{
+		BaseToolType: BaseToolType{
+			name:        "replication_status",
+			description: "Report the replication topology and lag of a server in one summary: the replicas streaming from it and, when it is itself a standby or replica, the source it replicates from, each with its state and lag in seconds and bytes. On PostgreSQL the replicas come from pg_stat_replication and a standby's own progress from pg_stat_wal_receiver and the WAL replay functions; a standby only knows how far replay trails the WAL it has received. On MySQL the replica side comes from SHOW REPLICA STATUS (SHOW SLAVE STATUS on older servers) and the source side lists the binlog dump threads, since a source does not know the lag of its replicas. Stopped replication threads and lag beyond the thresholds are called out.",
+		},
+	}
+}
+
+// CreateTool creates a replication status tool
+func (t *ReplicationStatusTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Report replication topology and lag in seconds and bytes"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithNumber("warn_lag_seconds",
+			tools.Description("Warn about replicas lagging by more than this many seconds (default: 30)"),
+		),
+		tools.WithNumber("warn_lag_mb",
+			tools.Description("Warn about replicas lagging by more than this much WAL or binlog (default: 100)"),
+		),
+	)
+}
+
+// HandleRequest handles replication status tool requests
+func (t *ReplicationStatusTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	warnSeconds := input.optionalFloat("warn_lag_seconds", 30)
+	warnMB := input.optionalFloat("warn_lag_mb", 100)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if warnSeconds <= 0 {
+		return nil, fmt.Errorf("warn_lag_seconds parameter must be greater than 0")
+	}
+	if warnMB <= 0 {
+		return nil, fmt.Errorf("warn_lag_mb parameter must be greater than 0")
+	}
+	warnBytes := int64(warnMB * (1 << 20))
+
+	logger.Info("Getting replication status for database %s", targetDbID)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for replication status: %s", dbType)
+	}
+	readStatus, err := dialect.ReplicationStatusReader()
+	if err != nil {
+		return nil, unsupportedAdminError("replication status", dbType, err)
+	}
+	status, err := readStatus(ctx, useCase, targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	status.Warnings = append(status.Warnings, replicationLagWarnings(status, warnSeconds, warnBytes)...)
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Replication Status for Database %s\n\n", targetDbID))
+	writeReplicationStatus(&response, status)
+
+	return createTextResponse(response.String()), nil
+}
+
+// replicationStatusReader reads the replication links of a server in both directions
+type replicationStatusReader func(ctx context.Context, useCase UseCaseProvider, dbID string) (*replicationStatus, error)
+
+// readPostgresReplicationStatus reads the replicas streaming from a PostgreSQL server and,
+// on a standby, the progress of its own WAL receiver and replay
+func readPostgresReplicationStatus(ctx context.Context, useCase UseCaseProvider, dbID string) (*replicationStatus, error) {
+	recovery, err := useCase.ExecuteQuery(ctx, dbID, "SELECT pg_is_in_recovery()", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recovery state: %w", err)
+	}
+	standby := len(recovery.Rows) > 0 && len(recovery.Rows[0]) > 0 && valueBool(recovery.Rows[0][0])
+
+	status := &replicationStatus{Role: "primary"}
+	if standby {
+		status.Role = "standby"
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, dbID, getPostgresReplicasQuery(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replicas: %w", err)
+	}
+	for _, row := range result.Rows {
+		if len(row) < 7 {
+			continue
+		}
+		link := replicationLink{
+			Name:        valueString(row[0]),
+			Peer:        valueString(row[1]),
+			State:       valueString(row[2]),
+			Sync:        valueString(row[3]),
+			Position:    valueString(row[4]),
+			LagBytes:    valueInt64(row[5]),
+			HasLagBytes: row[5] != nil,
+		}
+		// replay_lag is reset to NULL once an idle replica has caught up
+		switch {
+		case row[6] != nil:
+			link.LagSeconds, link.HasLagSeconds = valueFloat64(row[6]), true
+		case link.HasLagBytes && link.LagBytes == 0:
+			link.HasLagSeconds = true
+		}
+		if link.State != "streaming" {
+			status.Warnings = append(status.Warnings, fmt.Sprintf("Replica %s is in state %s rather than streaming.", orDash(link.Name), link.State))
+		}
+		status.Replicas = append(status.Replicas, link)
+	}
+
+	if !standby {
+		return status, nil
+	}
+
+	result, err = useCase.ExecuteQuery(ctx, dbID, getPostgresWalReceiverQuery(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get WAL receiver status: %w", err)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) < 9 {
+		return status, nil
+	}
+	row := result.Rows[0]
+	link := replicationLink{
+		Name:          valueString(row[2]),
+		Peer:          valueString(row[1]),
+		State:         valueString(row[0]),
+		Position:      fmt.Sprintf("received %s, replayed %s", orDash(valueString(row[3])), orDash(valueString(row[4]))),
+		LagBytes:      valueInt64(row[5]),
+		HasLagBytes:   row[5] != nil,
+		LagSeconds:    valueFloat64(row[6]),
+		HasLagSeconds: row[6] != nil,
+	}
+	if link.State == "" {
+		link.State = "not streaming"
+		status.Warnings = append(status.Warnings, "No WAL receiver is running, so the standby is not streaming from a primary; it may be replaying WAL from an archive or failing to connect.")
+	}
+	if valueBool(row[7]) {
+		status.Warnings = append(status.Warnings, "WAL replay is paused; SELECT pg_wal_replay_resume() resumes it.")
+	}
+	if row[8] != nil && valueFloat64(row[8]) > 60 {
+		status.Warnings = append(status.Warnings, fmt.Sprintf("The primary has not sent a message for %.0f seconds.", valueFloat64(row[8])))
+	}
+	status.Upstream = append(status.Upstream, link)
+	return status, nil
+}
+
+// readMySQLReplicationStatus reads the replication channels of a MySQL replica and the binlog
+// dump threads serving replicas of this server
+func readMySQLReplicationStatus(ctx context.Context, useCase UseCaseProvider, dbID string) (*replicationStatus, error) {
+	status := &replicationStatus{
+		Role:        "source",
+		ReplicaNote: "A MySQL source does not know the lag of its replicas; run this tool on a replica to see it.",
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, dbID, getMySQLBinlogDumpQuery(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connected replicas: %w", err)
+	}
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		status.Replicas = append(status.Replicas, replicationLink{
+			Name:     fmt.Sprintf("thread %d (%s)", valueInt64(row[0]), valueString(row[1])),
+			Peer:     valueString(row[2]),
+			State:    valueString(row[4]),
+			Position: fmt.Sprintf("connected %ds", valueInt64(row[3])),
+		})
+	}
+
+	// SHOW REPLICA STATUS replaced SHOW SLAVE STATUS in MySQL 8.0.22
+	result, err = useCase.ExecuteQuery(ctx, dbID, "SHOW REPLICA STATUS", nil)
+	if err != nil {
+		result, err = useCase.ExecuteQuery(ctx, dbID, "SHOW SLAVE STATUS", nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get replica status: %w", err)
+		}
+	}
+	if len(result.Rows) > 0 {
+		status.Role = "replica"
+		if len(status.Replicas) > 0 {
+			status.Role = "replica and source"
+		}
+	}
+	for _, row := range result.Rows {
+		link, warnings := mysqlReplicaChannel(result, row)
+		status.Upstream = append(status.Upstream, link)
+		status.Warnings = append(status.Warnings, warnings...)
+	}
+	if len(result.Rows) == 0 && len(status.Replicas) == 0 {
+		status.Role = "not replicating"
+	}
+	return status, nil
+}
+
+// mysqlReplicaChannel turns a row of SHOW REPLICA STATUS, or of SHOW SLAVE STATUS with its
+// older column names, into a replication link
+func mysqlReplicaChannel(result *domain.QueryResult, row []interface{}) (replicationLink, []string) {
+	index := columnIndex(result.Columns)
+	field := func(names ...string) interface{} {
+		for _, name := range names {
+			if i, ok := index[name]; ok && i < len(row) {
+				return row[i]
+			}
+		}
+		return nil
+	}
+	text := func(names ...string) string { return valueString(field(names...)) }
+
+	channel := text("Channel_Name")
+	if channel == "" {
+		channel = "(default)"
+	}
+	ioRunning := text("Replica_IO_Running", "Slave_IO_Running")
+	sqlRunning := text("Replica_SQL_Running", "Slave_SQL_Running")
+	sourceFile := text("Source_Log_File", "Master_Log_File")
+	readPos := valueInt64(field("Read_Source_Log_Pos", "Read_Master_Log_Pos"))
+	execFile := text("Relay_Source_Log_File", "Relay_Master_Log_File")
+	execPos := valueInt64(field("Exec_Source_Log_Pos", "Exec_Master_Log_Pos"))
+
+	link := replicationLink{
+		Name:     channel,
+		Peer:     fmt.Sprintf("%s:%d", text("Source_Host", "Master_Host"), valueInt64(field("Source_Port", "Master_Port"))),
+		State:    fmt.Sprintf("IO %s, SQL %s", orDash(ioRunning), orDash(sqlRunning)),
+		Position: fmt.Sprintf("read %s:%d, executed %s:%d", sourceFile, readPos, execFile, execPos),
+	}
+	// Seconds_Behind_Source is NULL while the SQL thread is stopped
+	if seconds := field("Seconds_Behind_Source", "Seconds_Behind_Master"); seconds != nil {
+		link.LagSeconds, link.HasLagSeconds = valueFloat64(seconds), true
+	}
+	// Byte positions are only comparable within one binary log file
+	if sourceFile != "" && sourceFile == execFile {
+		link.LagBytes, link.HasLagBytes = readPos-execPos, true
+	}
+
+	var errors []string
+	for _, e := range []string{text("Last_IO_Error"), text("Last_SQL_Error")} {
+		if e != "" {
+			errors = append(errors, e)
+		}
+	}
+	link.Error = strings.Join(errors, "; ")
+
+	var warnings []string
+	if ioRunning != "Yes" || sqlRunning != "Yes" {
+		warning := fmt.Sprintf("Replication channel %s is not fully running (IO thread %s, SQL thread %s)", channel, orDash(ioRunning), orDash(sqlRunning))
+		if link.Error != "" {
+			warning += ": " + link.Error
+		}
+		warnings = append(warnings, warning+".")
+	}
+	return link, warnings
+}
+
+// replicationLagWarnings flags the links lagging beyond the thresholds
+func replicationLagWarnings(status *replicationStatus, warnSeconds float64, warnBytes int64) []string {
+	var warnings []string
+	check := func(kind string, link replicationLink) {
+		switch {
+		case link.HasLagSeconds && link.LagSeconds > warnSeconds:
+			warnings = append(warnings, fmt.Sprintf("%s %s lags by %.1f seconds (%s).", kind, orDash(link.Name), link.LagSeconds, link.lagBytes()))
+		case link.HasLagBytes && link.LagBytes > warnBytes:
+			warnings = append(warnings, fmt.Sprintf("%s %s lags by %s.", kind, orDash(link.Name), formatBackupSize(link.LagBytes)))
+		}
+	}
+	for _, link := range status.Replicas {
+		check("Replica", link)
+	}
+	for _, link := range status.Upstream {
+		check("Replication from", link)
+	}
+	return warnings
+}
+
+// writeReplicationStatus renders the replicas, the upstream links and the warnings
+func writeReplicationStatus(sb *strings.Builder, status *replicationStatus) {
+	sb.WriteString(fmt.Sprintf("Role: %s\n", status.Role))
+
+	sb.WriteString("\n## Replicas\n\n")
+	if len(status.Replicas) == 0 {
+		sb.WriteString("No replicas are connected.\n")
+	} else {
+		sb.WriteString("| Replica | Address | State | Sync | Lag (seconds) | Lag (bytes) | Position |\n")
+		sb.WriteString("|---------|---------|-------|------|---------------|-------------|----------|\n")
+		for _, l := range status.Replicas {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+				orDash(l.Name), orDash(l.Peer), orDash(l.State), orDash(l.Sync), l.lagSeconds(), l.lagBytes(), orDash(l.Position)))
+		}
+		if status.ReplicaNote != "" {
+			sb.WriteString(fmt.Sprintf("\n%s\n", status.ReplicaNote))
+		}
+	}
+
+	if len(status.Upstream) > 0 {
+		sb.WriteString("\n## Replicating From\n\n")
+		sb.WriteString("| Channel | Source | State | Lag (seconds) | Lag (bytes) | Position | Error |\n")
+		sb.WriteString("|---------|--------|-------|---------------|-------------|----------|-------|\n")
+		for _, l := range status.Upstream {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+				orDash(l.Name), orDash(l.Peer), orDash(l.State), l.lagSeconds(), l.lagBytes(), orDash(l.Position), queryCell(l.Error)))
+		}
+	}
+
+	if len(status.Warnings) > 0 {
+		sb.WriteString("\n## Warnings\n\n")
+		for _, w := range status.Warnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+}
+
+// getPostgresReplicasQuery returns a query for the replicas streaming from this server, with
+// the WAL they have yet to replay measured from the current WAL position, or from the last
+// received position on a cascading standby
+func getPostgresReplicasQuery() string {
+	return `
+SELECT
+    application_name,
+    COALESCE(host(client_addr), 'local') AS client_addr,
+    state,
+    sync_state,
+    replay_lsn::text AS replay_lsn,
+    pg_wal_lsn_diff(
+        CASE WHEN pg_is_in_recovery() THEN pg_last_wal_receive_lsn() ELSE pg_current_wal_lsn() END,
+        replay_lsn
+    )::bigint AS lag_bytes,
+    EXTRACT(EPOCH FROM replay_lag) AS lag_seconds
+FROM pg_stat_replication
+ORDER BY application_name, client_addr;`
+}
+
+// getPostgresWalReceiverQuery returns a query for the progress of a standby. The time lag is
+// zero when replay has caught up with the received WAL, as the last replayed transaction may
+// be old on an idle primary.
+func getPostgresWalReceiverQuery() string {
+	return `
+SELECT
+    COALESCE(r.status, '') AS status,
+    COALESCE(r.sender_host || ':' || r.sender_port, '') AS sender,
+    COALESCE(r.slot_name, '') AS slot_name,
+    pg_last_wal_receive_lsn()::text AS received_lsn,
+    pg_last_wal_replay_lsn()::text AS replayed_lsn,
+    pg_wal_lsn_diff(pg_last_wal_receive_lsn(), pg_last_wal_replay_lsn())::bigint AS lag_bytes,
+    CASE
+        WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+        ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())
+    END AS lag_seconds,
+    pg_is_wal_replay_paused() AS replay_paused,
+    EXTRACT(EPOCH FROM now() - r.last_msg_receipt_time) AS seconds_since_message
+FROM (SELECT 1) AS one
+LEFT JOIN pg_stat_wal_receiver r ON true;`
+}
+
+// getMySQLBinlogDumpQuery returns a query for the threads sending binary logs to replicas
+func getMySQLBinlogDumpQuery() string {
+	return `
+SELECT ID, USER, HOST, TIME, COALESCE(STATE, '')
+FROM information_schema.PROCESSLIST
+WHERE COMMAND IN ('Binlog Dump', 'Binlog Dump GTID')
+ORDER BY HOST;`
+}
@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func replicationStatusText(t *testing.T, useCase *mockUseCase, params map[string]interface{}) string {
+	result, err := NewReplicationStatusTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	assert.NoError(t, err)
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+}
+
+func TestReplicationStatusPostgresPrimary(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_stat_replication": {Rows: [][]interface{}{
+				{"replica1", "10.0.0.5", "streaming", "async", "0/3000060", int64(0), nil},
+				{"replica2", "10.0.0.6", "catchup", "async", "0/2000000", int64(512 << 20), 95.5},
+			}},
+		},
+	}
+	text := replicationStatusText(t, useCase, map[string]interface{}{"database": "pg1"})
+
+	assert.Contains(t, text, "Role: primary")
+	assert.Contains(t, text, "| replica1 | 10.0.0.5 | streaming | async | 0.0 | 0 B | 0/3000060 |")
+	assert.Contains(t, text, "| replica2 | 10.0.0.6 | catchup | async | 95.5 | 512.0 MiB | 0/2000000 |")
+	assert.Contains(t, text, "- Replica replica2 is in state catchup rather than streaming.")
+	assert.Contains(t, text, "- Replica replica2 lags by 95.5 seconds (512.0 MiB).")
+	assert.NotContains(t, text, "## Replicating From")
+}
+
+func TestReplicationStatusPostgresStandby(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"SELECT pg_is_in_recovery()": {Rows: [][]interface{}{{true}}},
+			"LEFT JOIN pg_stat_wal_receiver": {Rows: [][]interface{}{
+				{"", "", "", "0/5000000", "0/4000000", int64(16 << 20), 12.0, true, nil},
+			}},
+		},
+	}
+	text := replicationStatusText(t, useCase, map[string]interface{}{"database": "pg1", "warn_lag_mb": 8})
+
+	assert.Contains(t, text, "Role: standby")
+	assert.Contains(t, text, "| - | - | not streaming | 12.0 | 16.0 MiB | received 0/5000000, replayed 0/4000000 | - |")
+	assert.Contains(t, text, "- No WAL receiver is running")
+	assert.Contains(t, text, "- WAL replay is paused")
+	assert.Contains(t, text, "- Replication from - lags by 16.0 MiB.")
+}
+
+func TestReplicationStatusMySQLReplica(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"SHOW REPLICA STATUS": {
+				Columns: []string{"Source_Host", "Source_Port", "Source_Log_File", "Read_Source_Log_Pos", "Relay_Source_Log_File", "Exec_Source_Log_Pos",
+					"Replica_IO_Running", "Replica_SQL_Running", "Seconds_Behind_Source", "Last_IO_Error", "Last_SQL_Error", "Channel_Name"},
+				Rows: [][]interface{}{
+					{"db-primary", int64(3306), "binlog.000042", int64(9000), "binlog.000042", int64(1000), "Yes", "No", nil, "", "Duplicate entry '7' for key 'PRIMARY'", ""},
+				},
+			},
+		},
+	}
+	text := replicationStatusText(t, useCase, map[string]interface{}{"database": "db1"})
+
+	assert.Contains(t, text, "Role: replica")
+	assert.Contains(t, text, "No replicas are connected.")
+	assert.Contains(t, text, "| (default) | db-primary:3306 | IO Yes, SQL No | - | 7.8 KiB | read binlog.000042:9000, executed binlog.000042:1000 | Duplicate entry '7' for key 'PRIMARY' |")
+	assert.Contains(t, text, "- Replication channel (default) is not fully running (IO thread Yes, SQL thread No): Duplicate entry")
+
+	_, err := NewReplicationStatusTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "lite"}}, "", &mockUseCase{dbType: "sqlite"})
+	assert.ErrorContains(t, err, "unsupported database type for replication status: sqlite")
+}
@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// reportSection is one catalog query of a report with the warnings its rows raise
+type reportSection struct {
+	title  string
+	query  string
+	params []interface{}
+	// warn returns the warnings about a row of the section, if any
+	warn func(row []interface{}) []string
+}
+
+// writeReportSections runs each section and renders its rows under its title, followed by the
+// warnings of all sections
+func writeReportSections(ctx context.Context, useCase UseCaseProvider, dbID string, sections []reportSection, sb *strings.Builder) error {
+	var warnings []string
+	for _, section := range sections {
+		result, err := useCase.ExecuteQuery(ctx, dbID, section.query, section.params)
+		if err != nil {
+			return fmt.Errorf("failed to get %s: %w", strings.ToLower(section.title), err)
+		}
+		sb.WriteString(fmt.Sprintf("\n## %s\n\n", section.title))
+		if len(result.Rows) == 0 {
+			sb.WriteString("None.\n")
+			continue
+		}
+		sb.WriteString(formatQueryResult(result))
+		if section.warn != nil {
+			for _, row := range result.Rows {
+				warnings = append(warnings, section.warn(row)...)
+			}
+		}
+	}
+	if len(warnings) > 0 {
+		sb.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+	return nil
+}
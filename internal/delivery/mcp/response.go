@@ -2,6 +2,8 @@ package mcp
 
 import (
 	"fmt"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
 )
 
 // TextContent represents a text content item in a response
@@ -101,3 +103,8 @@ func FormatResponse(response interface{}, err error) (interface{}, error) {
 	// For any other type, convert to string and wrap in proper content format
 	return FromString(fmt.Sprintf("%v", response)), nil
 }
+
+// formatQueryResult renders query rows as the tab-separated text the query tools return
+func formatQueryResult(result *domain.QueryResult) string {
+	return encodeQueryResult(result, resultOptions{format: "text"})
+}
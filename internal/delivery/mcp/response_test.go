@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
 )
 
 func TestNewResponse(t *testing.T) {
@@ -210,3 +213,11 @@ func ExampleNewResponse() {
 	fmt.Println(string(output))
 	// Output: {"content":[{"type":"text","text":"Hello, world!"}],"metadata":{"source":"example"}}
 }
+
+func TestFormatQueryResult(t *testing.T) {
+	text := formatQueryResult(&domain.QueryResult{
+		Columns: []string{"id", "name"},
+		Rows:    [][]interface{}{{int64(1), "alice"}, {int64(2), nil}},
+	})
+	assert.Equal(t, "Results:\n\nid\tname\n"+strings.Repeat("-", 80)+"\n1\talice\n2\tNULL\n\nTotal rows: 2", text)
+}
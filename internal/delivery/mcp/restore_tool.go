@@ -0,0 +1,389 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// RestoreTool handles restoring logical backups
+type RestoreTool struct {
+	BaseToolType
+}
+
+// NewRestoreTool creates a new restore tool type
+func NewRestoreTool() *RestoreTool {
+	return &RestoreTool{
+		BaseToolType: BaseToolType{
+			name:        "restore",
+			description: "Restore a backup taken with the backup tool into a database. The backup is found by ID or label (or latest) in the backups directory of source_database, and every file is checked against its recorded SHA-256 checksum first. The restore is refused when the target already has any of the tables being restored (any table at all for a whole-database dump) unless force is set; with force, custom-format dumps are restored with --clean and tables of copy backups are emptied before loading. Individual tables can be restored from custom-format and copy backups. Progress is reported as the restore runs and included in the result.",
+		},
+	}
+}
+
+// CreateTool creates a restore tool
+func (t *RestoreTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Restore a backup into a database with overwrite protection"),
+		tools.WithString("database",
+			tools.Description("Database ID to restore into"),
+			tools.Required(),
+		),
+		tools.WithString("backup",
+			tools.Description("Backup ID or label to restore, or latest"),
+			tools.Required(),
+		),
+		tools.WithString("source_database",
+			tools.Description("Database ID whose backups directory holds the backup (default: the target database)"),
+		),
+		tools.WithArray("tables",
+			tools.Description("Only restore these tables (default: everything in the backup)"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithBoolean("force",
+			tools.Description("Overwrite tables that already exist in the target (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles restore tool requests
+func (t *RestoreTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	name := input.requiredString("backup")
+	sourceDbID := input.optionalString("source_database", targetDbID)
+	tables := input.stringList("tables")
+	force := input.optionalBool("force", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	dir, err := backupsDir(useCase, sourceDbID)
+	if err != nil {
+		return nil, err
+	}
+	record, err := findBackup(dir, name)
+	if err != nil {
+		return nil, err
+	}
+	backupDir := filepath.Join(dir, record.ID)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect := strings.ToLower(dbType)
+	if dialect != record.Type {
+		return nil, fmt.Errorf("backup %s is a %s backup and cannot be restored into %s database %s", record.ID, record.Type, dialect, targetDbID)
+	}
+	for _, table := range tables {
+		if len(record.Tables) > 0 && !containsFold(record.Tables, table) {
+			return nil, fmt.Errorf("table %s is not part of backup %s (tables: %s)", table, record.ID, strings.Join(record.Tables, ", "))
+		}
+	}
+
+	// Refuse to overwrite existing tables unless forced
+	target, err := loadSchemaMetadata(ctx, useCase, targetDbID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect target database: %w", err)
+	}
+	existing := make([]string, 0, len(target.Tables))
+	for _, table := range target.Tables {
+		existing = append(existing, table.Name)
+	}
+	restoring := tables
+	if len(restoring) == 0 {
+		restoring = record.Tables
+	}
+	var conflicts []string
+	for _, table := range existing {
+		// A whole-database dump may contain any table
+		if len(restoring) == 0 || containsFold(restoring, table) {
+			conflicts = append(conflicts, table)
+		}
+	}
+	sort.Strings(conflicts)
+	if len(conflicts) > 0 && !force {
+		return nil, fmt.Errorf("refusing to restore into %s: it already has table(s) %s; set force to overwrite them", targetDbID, strings.Join(conflicts, ", "))
+	}
+
+	files := record.Files
+	if record.Method == "copy" && len(tables) > 0 {
+		files = nil
+		for _, f := range record.Files {
+			if f.Table == "" || containsFold(tables, f.Table) {
+				files = append(files, f)
+			}
+		}
+	}
+	for _, f := range files {
+		if err := verifyBackupFile(backupDir, f); err != nil {
+			return nil, err
+		}
+	}
+
+	progress := newProgressLog()
+	progress.report(ctx, 0, "Restoring backup %s into %s", record.ID, targetDbID)
+	// Drop cached metadata even after a partial restore
+	defer schemaCache.invalidate(useCase, targetDbID)
+	start := time.Now()
+	var notes []string
+	if record.Method == "copy" {
+		notes, err = restoreCopyBackup(ctx, useCase, targetDbID, dialect, record, backupDir, files, restoring, conflicts, progress)
+	} else {
+		notes, err = restoreDumpBackup(ctx, useCase, targetDbID, dialect, record, backupDir, tables, force, progress)
+	}
+	restored := "all tables"
+	if len(restoring) > 0 {
+		restored = strings.Join(restoring, ", ")
+	}
+	audit(request, auditRecord{
+		Tool:      "restore",
+		Database:  targetDbID,
+		Action:    "restore",
+		Target:    restored,
+		Statement: fmt.Sprintf("restore %s %s backup %s of database %s", record.Method, record.Format, record.ID, record.Database),
+	}, err)
+	if err != nil {
+		logger.Warn("Restore of backup %s into %s failed: %v", record.ID, targetDbID, err)
+		return nil, fmt.Errorf("failed to restore backup %s: %w", record.ID, err)
+	}
+	progress.report(ctx, 1, "Restore completed")
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Restore for Database %s\n\n", targetDbID))
+	response.WriteString(fmt.Sprintf("Backup: %s (%s %s, taken %s from %s)\n", record.ID, record.Method, record.Format,
+		record.StartedAt.Format("2006-01-02 15:04:05"), record.Database))
+	if len(tables) > 0 {
+		response.WriteString(fmt.Sprintf("Tables: %s\n", strings.Join(tables, ", ")))
+	}
+	if len(conflicts) > 0 {
+		response.WriteString(fmt.Sprintf("Overwritten tables: %s\n", strings.Join(conflicts, ", ")))
+	}
+	response.WriteString(fmt.Sprintf("Duration: %s\n", time.Since(start).Round(time.Millisecond)))
+	for _, note := range notes {
+		response.WriteString(fmt.Sprintf("\nNote: %s\n", note))
+	}
+	response.WriteString("\n## Progress\n\n")
+	for _, line := range progress.lines() {
+		response.WriteString(fmt.Sprintf("- %s\n", line))
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// restoreDumpBackup feeds a pg_dump or mysqldump backup to pg_restore, psql or mysql
+func restoreDumpBackup(ctx context.Context, useCase UseCaseProvider, dbID, dialect string, record *backupRecord, backupDir string, tables []string, force bool, progress *progressLog) ([]string, error) {
+	if len(record.Files) != 1 {
+		return nil, fmt.Errorf("dump backup %s should have exactly one file, found %d", record.ID, len(record.Files))
+	}
+	config, err := useCase.GetDatabaseConfig(dbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database configuration: %w", err)
+	}
+	tool, err := lookupDumpTool(dialect, "restore")
+	if err != nil {
+		return nil, err
+	}
+	name, args, env, err := tool.restoreCommand(config, record.Format, tables, force)
+	if err != nil {
+		return nil, err
+	}
+	notes := tool.restoreNotes(record.Format, tables, force)
+
+	file := record.Files[0]
+	in, err := openBackupFile(backupDir, file, func(read, total int64) {
+		progress.report(ctx, float64(read)/float64(total), "Read %s of %s", formatBackupSize(read), formatBackupSize(total))
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	progress.report(ctx, 0, "Running %s", name)
+	if err := runRestoreCommand(ctx, name, args, env, in); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// restoreCopyBackup recreates missing tables from the DDL of a copy backup and loads their CSV files;
+// indexes and foreign keys of new tables are created after the data is loaded
+func restoreCopyBackup(ctx context.Context, useCase UseCaseProvider, dbID, dialect string, record *backupRecord, backupDir string, files []backupFile, tables, conflicts []string, progress *progressLog) ([]string, error) {
+	var notes, createTables, afterLoad []string
+	for _, f := range files {
+		if f.Table != "" {
+			continue
+		}
+		in, err := openBackupFile(backupDir, f, nil)
+		if err != nil {
+			return nil, err
+		}
+		script, readErr := io.ReadAll(in)
+		_ = in.Close()
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Path, readErr)
+		}
+		statements, err := splitSQLStatements(string(script), dialect)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", f.Path, err)
+		}
+		for _, statement := range statements {
+			table, refs, isCreate := backupStatementTable(statement, dialect)
+			// Existing tables keep their definition, indexes and constraints
+			if !containsFold(tables, table) || containsFold(conflicts, table) {
+				continue
+			}
+			missing := ""
+			for _, ref := range refs {
+				if !containsFold(tables, ref) && !containsFold(conflicts, ref) {
+					missing = ref
+				}
+			}
+			switch {
+			case missing != "":
+				notes = append(notes, fmt.Sprintf("Skipped a foreign key of %s referencing %s, which is not being restored.", table, missing))
+			case isCreate:
+				createTables = append(createTables, statement)
+			default:
+				afterLoad = append(afterLoad, statement)
+			}
+		}
+	}
+
+	if len(conflicts) > 0 {
+		progress.report(ctx, 0, "Emptying %s", strings.Join(conflicts, ", "))
+		statements, _ := copyRestoreStatements(dialect, emptyTableStatements(dialect, conflicts), nil)
+		if err := useCase.ExecuteBatch(ctx, dbID, statements, nil); err != nil {
+			return nil, fmt.Errorf("failed to empty existing tables: %w", err)
+		}
+	}
+	if len(createTables) > 0 {
+		progress.report(ctx, 0, "Creating %d table(s)", len(createTables))
+		if err := useCase.ExecuteBatch(ctx, dbID, createTables, nil); err != nil {
+			return nil, fmt.Errorf("failed to create tables: %w", err)
+		}
+	}
+
+	var totalRows, loadedRows int64
+	for _, f := range files {
+		totalRows += f.Rows
+	}
+	for _, f := range files {
+		if f.Table == "" {
+			continue
+		}
+		in, err := openBackupFile(backupDir, f, nil)
+		if err != nil {
+			return nil, err
+		}
+		columns, rows, err := readBackupCSV(in)
+		_ = in.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Path, err)
+		}
+		if len(rows) > 0 {
+			statements, params := insertRowStatements(dialect, f.Table, columns, rows)
+			// Tables are loaded one by one, so references may point at rows that are not loaded yet
+			statements, params = copyRestoreStatements(dialect, statements, params)
+			if err := useCase.ExecuteBatch(ctx, dbID, statements, params); err != nil {
+				return nil, fmt.Errorf("failed to load table %s: %w", f.Table, err)
+			}
+		}
+		loadedRows += int64(len(rows))
+		fraction := 1.0
+		if totalRows > 0 {
+			fraction = float64(loadedRows) / float64(totalRows)
+		}
+		progress.report(ctx, fraction, "Loaded %d rows into %s", len(rows), f.Table)
+	}
+
+	if len(afterLoad) > 0 {
+		progress.report(ctx, 1, "Creating %d index(es) and foreign key(s)", len(afterLoad))
+		if err := useCase.ExecuteBatch(ctx, dbID, afterLoad, nil); err != nil {
+			return nil, fmt.Errorf("failed to create indexes and foreign keys: %w", err)
+		}
+	}
+	return notes, nil
+}
+
+// emptyTableStatements removes all rows of the tables
+func emptyTableStatements(dialect string, tables []string) []string {
+	quoted := make([]string, len(tables))
+	for i, table := range tables {
+		quoted[i] = quoteIdentifier(dialect, table)
+	}
+	if d, ok := lookupDialect(dialect); ok {
+		if statement, ok := d.TruncateTablesStatement(quoted); ok {
+			return []string{statement}
+		}
+	}
+	statements := make([]string, len(quoted))
+	for i, table := range quoted {
+		statements[i] = "TRUNCATE TABLE " + table
+	}
+	return statements
+}
+
+// copyRestoreStatements disables foreign key checks around the statements where the database
+// checks them statement by statement, adding the parameters of the extra statements
+func copyRestoreStatements(dialect string, statements []string, params [][]interface{}) ([]string, [][]interface{}) {
+	d, ok := lookupDialect(dialect)
+	if !ok {
+		return statements, params
+	}
+	disable, enable, ok := d.ForeignKeyChecksStatements()
+	if !ok {
+		return statements, params
+	}
+	if params != nil {
+		params = append([][]interface{}{nil}, params...)
+	}
+	wrapped := append([]string{disable}, statements...)
+	return append(wrapped, enable), params
+}
+
+// backupStatementTable returns the table a statement of a backup's DDL belongs to, the tables it
+// references and whether it creates the table
+func backupStatementTable(statement, dialect string) (string, []string, bool) {
+	tokens, err := tokenizeSQL(statement, dialect)
+	if err != nil {
+		return "", nil, false
+	}
+	toks := significantTokens(tokens)
+	var refs []string
+	for i, tok := range toks {
+		if tok.is("REFERENCES") {
+			ref := &ddlParser{toks: toks, i: i + 1}
+			if _, name, ok := ref.name(); ok {
+				refs = append(refs, name)
+			}
+		}
+	}
+
+	p := &ddlParser{toks: toks}
+	switch {
+	case p.accept("CREATE", "TABLE"):
+		_, name, _ := p.name()
+		return name, refs, true
+	case p.accept("CREATE"):
+		p.accept("UNIQUE")
+		for !p.done() && !p.accept("ON") {
+			p.i++
+		}
+		_, name, _ := p.name()
+		return name, refs, false
+	case p.accept("ALTER", "TABLE"):
+		_, name, _ := p.name()
+		return name, refs, false
+	}
+	return "", refs, false
+}
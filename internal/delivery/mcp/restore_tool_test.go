@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+const testBackupSchema = `CREATE TABLE "users" (
+  "id" integer NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE TABLE "orders" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_orders_user_id" ON "orders" ("user_id");
+
+ALTER TABLE "orders" ADD CONSTRAINT "orders_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+`
+
+// writeTestBackup stores a completed backup with the given files, in order
+func writeTestBackup(t *testing.T, dir string, record *backupRecord, files [][2]string, rows map[string]int64) {
+	backupDir := filepath.Join(dir, record.ID)
+	assert.NoError(t, os.MkdirAll(backupDir, 0o755))
+	for _, f := range files {
+		w, err := createBackupFile(backupDir, f[0], false)
+		assert.NoError(t, err)
+		_, err = io.WriteString(w, f[1])
+		assert.NoError(t, err)
+		file, err := w.close()
+		assert.NoError(t, err)
+		if n, ok := rows[f[0]]; ok {
+			file.Table, file.Rows = f[0][:len(f[0])-len(".csv")], n
+		}
+		record.Files = append(record.Files, file)
+	}
+	record.Status = "completed"
+	assert.NoError(t, saveBackupRecord(dir, record))
+}
+
+func TestRestoreToolCopyBackup(t *testing.T) {
+	dir := t.TempDir()
+	writeTestBackup(t, dir, &backupRecord{
+		ID: "20240301T120000Z_nightly", Label: "nightly", Database: "pg1", Type: "postgres",
+		Method: "copy", Format: "csv", Compression: "none", Tables: []string{"users", "orders"},
+		StartedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	}, [][2]string{
+		{"schema.sql", testBackupSchema},
+		{"users.csv", "id\n1\n2\n"},
+		{"orders.csv", "id,user_id\n10,1\n11,\\N\n"},
+	}, map[string]int64{"users.csv": 2, "orders.csv": 2})
+
+	useCase := &mockUseCase{dbType: "postgres", config: domain.DatabaseConnectionConfig{BackupsDir: dir}}
+	var reported []float64
+	ctx := withProgress(context.Background(), func(progress float64, message string) { reported = append(reported, progress) })
+
+	result, err := NewRestoreTool().HandleRequest(ctx, server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "backup": "nightly", "tables": []interface{}{"orders"}},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "Backup: 20240301T120000Z_nightly (copy csv, taken 2024-03-01 12:00:00 from pg1)")
+	assert.Contains(t, text, "Note: Skipped a foreign key of orders referencing users, which is not being restored.")
+	assert.Contains(t, text, "[100%] Loaded 2 rows into orders")
+	assert.Equal(t, 1.0, reported[len(reported)-1])
+
+	assert.Len(t, useCase.batches, 3)
+	assert.Contains(t, useCase.batches[0][0], `CREATE TABLE "orders"`)
+	assert.Equal(t, []string{`INSERT INTO "orders" ("id", "user_id") VALUES ($1, $2), ($3, $4)`}, useCase.batches[1])
+	assert.Equal(t, []string{`CREATE INDEX "idx_orders_user_id" ON "orders" ("user_id")`}, useCase.batches[2])
+}
+
+func TestRestoreToolRefusesToOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	writeTestBackup(t, dir, &backupRecord{
+		ID: "20240301T120000Z", Database: "pg1", Type: "postgres", Method: "copy", Format: "csv",
+		Compression: "none", Tables: []string{"users"},
+	}, [][2]string{{"schema.sql", testBackupSchema}, {"users.csv", "id\n1\n"}}, map[string]int64{"users.csv": 1})
+
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		config: domain.DatabaseConnectionConfig{BackupsDir: dir},
+		results: map[string]*domain.QueryResult{
+			"col_description": {Rows: [][]interface{}{{"public", "users", "id", "integer", false, nil, nil, nil}}},
+		},
+	}
+	request := server.ToolCallRequest{Parameters: map[string]interface{}{"database": "pg1", "backup": "latest"}}
+	_, err := NewRestoreTool().HandleRequest(context.Background(), request, "pg1", useCase)
+	assert.ErrorContains(t, err, "it already has table(s) users; set force")
+	assert.Empty(t, useCase.batches)
+
+	request.Parameters["force"] = true
+	_, err = NewRestoreTool().HandleRequest(context.Background(), request, "pg1", useCase)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`TRUNCATE "users"`}, useCase.batches[0])
+	assert.Len(t, useCase.batches, 2)
+
+	// A modified file is rejected before anything runs
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "20240301T120000Z", "users.csv"), []byte("id\n2\n"), 0o644))
+	_, err = NewRestoreTool().HandleRequest(context.Background(), request, "pg1", useCase)
+	assert.ErrorContains(t, err, "does not match its recorded checksum")
+	assert.Len(t, useCase.batches, 2)
+}
+
+func TestRestoreToolDumpBackup(t *testing.T) {
+	dir := t.TempDir()
+	backupDir := filepath.Join(dir, "20240301T120000Z")
+	assert.NoError(t, os.MkdirAll(backupDir, 0o755))
+	w, err := createBackupFile(backupDir, "dump.sql", true)
+	assert.NoError(t, err)
+	_, _ = io.WriteString(w, "CREATE TABLE t (id int);\n")
+	file, err := w.close()
+	assert.NoError(t, err)
+	assert.NoError(t, saveBackupRecord(dir, &backupRecord{
+		ID: "20240301T120000Z", Database: "pg1", Type: "postgres", Method: "dump", Format: "sql",
+		Compression: "gzip", Files: []backupFile{file}, Status: "completed",
+	}))
+
+	var command []string
+	var input string
+	previous := runRestoreCommand
+	runRestoreCommand = func(ctx context.Context, name string, args, env []string, in io.Reader) error {
+		command = append([]string{name}, args...)
+		content, err := io.ReadAll(in)
+		input = string(content)
+		return err
+	}
+	defer func() { runRestoreCommand = previous }()
+
+	useCase := &mockUseCase{dbType: "postgres", config: domain.DatabaseConnectionConfig{BackupsDir: dir, Name: "shop"}}
+	_, err = NewRestoreTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "backup": "20240301T120000Z"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	assert.Equal(t, "psql", command[0])
+	assert.Contains(t, command, "--single-transaction")
+	assert.Equal(t, "CREATE TABLE t (id int);\n", input)
+
+	_, err = NewRestoreTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "backup": "latest", "tables": []interface{}{"t"}},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "cannot be restored table by table")
+}
@@ -0,0 +1,423 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/FreePeak/cortex/pkg/tools"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+// maxResultBytes caps the encoded size of a query result held in memory; rows past it are
+// left out and the output says so
+var maxResultBytes = 8 << 20
+
+// maxPooledResultBuffer is the largest buffer returned to the pool, so a single huge result
+// does not keep its memory for the life of the process
+const maxPooledResultBuffer = 1 << 20
+
+// resultFormats are the output formats the query tools accept
+var resultFormats = []string{"text", "json", "csv"}
+
+// binaryFormats are the encodings binary values can be shown in
+var binaryFormats = []string{"hex", "base64"}
+
+// defaultBinaryLimit is how many bytes of a binary value are shown when no limit is chosen
+const defaultBinaryLimit = 256
+
+// resultOptions chooses how query results are rendered
+type resultOptions struct {
+	// format is text, json or csv
+	format string
+	// location converts timestamps; nil keeps the time zone of the connection
+	location *time.Location
+	// null is written for NULL in text and CSV; empty selects NULL in text and an empty
+	// field in CSV. JSON always uses null.
+	null string
+	// binary encodes binary values as hex, with a 0x prefix, or as base64
+	binary string
+	// binaryLimit caps the bytes of a binary value that are shown; zero selects defaultBinaryLimit
+	binaryLimit int
+}
+
+// resultParameters are the parameters of the tools returning query results
+func resultParameters() []tools.ToolOption {
+	return []tools.ToolOption{
+		tools.WithString("format",
+			tools.Description("Output format of query results: text, json or csv (default: text)"),
+		),
+		tools.WithString("timezone",
+			tools.Description("IANA time zone timestamps are shown in, such as UTC or Europe/Berlin (default: the connection's timezone)"),
+		),
+		tools.WithString("null",
+			tools.Description("Marker written for NULL values in text and csv output (default: NULL in text, an empty field in csv)"),
+		),
+		tools.WithString("binary_format",
+			tools.Description("Encoding of binary values: hex or base64 (default: hex)"),
+		),
+		tools.WithNumber("binary_limit",
+			tools.Description("Most bytes of a binary value to show; longer values are cut off and their size noted (default: 256)"),
+		),
+	}
+}
+
+// readResultOptions reads the resultParameters of a tool call
+func readResultOptions(input *toolParams) resultOptions {
+	return resultOptions{
+		format:      input.choice("format", "text", resultFormats...),
+		location:    input.location("timezone"),
+		null:        input.optionalString("null", ""),
+		binary:      input.choice("binary_format", "hex", binaryFormats...),
+		binaryLimit: input.intAtLeast("binary_limit", defaultBinaryLimit, 1),
+	}
+}
+
+// resultBuffers pools the buffers query results are encoded into
+var resultBuffers = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// errResultTooLarge stops encoding once the output reaches its size limit
+var errResultTooLarge = errors.New("query result exceeds the output size limit")
+
+// resultEncoder writes query rows into a pooled buffer as they arrive, as tab-separated text,
+// JSON or CSV. It implements domain.RowHandler, so streamed rows are encoded one at a time.
+// Every format renders values the same way: timestamps in RFC 3339, booleans as true and
+// false, numbers without locale formatting and binary values in the chosen encoding.
+type resultEncoder struct {
+	resultOptions
+	limit     int
+	buf       *bytes.Buffer
+	scratch   []byte
+	csv       *csv.Writer
+	record    []string
+	rows      int64
+	truncated bool
+	cursor    string
+	remaining int64
+}
+
+// newResultEncoder returns an encoder rendering rows as options choose that keeps at most
+// limit bytes, or any amount when limit is not positive
+func newResultEncoder(options resultOptions, limit int) *resultEncoder {
+	if options.null == "" && options.format != "csv" {
+		options.null = "NULL"
+	}
+	if options.binaryLimit <= 0 {
+		options.binaryLimit = defaultBinaryLimit
+	}
+	buf := resultBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	e := &resultEncoder{resultOptions: options, limit: limit, buf: buf}
+	if options.format == "csv" {
+		e.csv = csv.NewWriter(buf)
+	}
+	return e
+}
+
+// encodeQueryResult renders query rows as options choose within maxResultBytes, pointing
+// to fetch_rows when the rest of the rows were spilled to disk
+func encodeQueryResult(result *domain.QueryResult, options resultOptions) string {
+	e := newResultEncoder(options, maxResultBytes)
+	e.cursor, e.remaining = result.Cursor, result.Remaining
+	if err := e.Columns(result.Columns); err == nil {
+		for _, row := range result.Rows {
+			if err := e.Row(row); err != nil {
+				break
+			}
+		}
+	}
+	return e.finish()
+}
+
+// Columns implements domain.RowHandler
+func (e *resultEncoder) Columns(columns []string) error {
+	switch e.format {
+	case "json":
+		e.buf.WriteString(`{"columns":[`)
+		for i, column := range columns {
+			if i > 0 {
+				e.buf.WriteByte(',')
+			}
+			e.scratch = appendJSONString(e.scratch[:0], column)
+			e.buf.Write(e.scratch)
+		}
+		e.buf.WriteString(`],"rows":[`)
+	case "csv":
+		if err := e.csv.Write(columns); err != nil {
+			return err
+		}
+		e.csv.Flush()
+		return e.csv.Error()
+	default:
+		e.buf.WriteString("Results:\n\n")
+		for i, column := range columns {
+			if i > 0 {
+				e.buf.WriteByte('\t')
+			}
+			e.buf.WriteString(column)
+		}
+		e.buf.WriteByte('\n')
+		e.buf.WriteString(strings.Repeat("-", 80))
+		e.buf.WriteByte('\n')
+	}
+	return nil
+}
+
+// Row implements domain.RowHandler
+func (e *resultEncoder) Row(values []interface{}) error {
+	if e.truncated {
+		return errResultTooLarge
+	}
+	mark := e.buf.Len()
+	switch e.format {
+	case "json":
+		if e.rows > 0 {
+			e.buf.WriteByte(',')
+		}
+		e.scratch = append(e.scratch[:0], '[')
+		for i, val := range values {
+			if i > 0 {
+				e.scratch = append(e.scratch, ',')
+			}
+			e.scratch = appendJSONValue(e.scratch, e.value(val))
+		}
+		e.scratch = append(e.scratch, ']')
+		e.buf.Write(e.scratch)
+	case "csv":
+		e.record = e.record[:0]
+		for _, val := range values {
+			if val == nil {
+				e.record = append(e.record, e.null)
+				continue
+			}
+			e.scratch = appendTextValue(e.scratch[:0], e.value(val))
+			e.record = append(e.record, string(e.scratch))
+		}
+		if err := e.csv.Write(e.record); err != nil {
+			return err
+		}
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	default:
+		e.scratch = e.scratch[:0]
+		for i, val := range values {
+			if i > 0 {
+				e.scratch = append(e.scratch, '\t')
+			}
+			if val == nil {
+				e.scratch = append(e.scratch, e.null...)
+				continue
+			}
+			e.scratch = appendTextValue(e.scratch, e.value(val))
+		}
+		e.scratch = append(e.scratch, '\n')
+		e.buf.Write(e.scratch)
+	}
+
+	if e.limit > 0 && e.buf.Len() > e.limit {
+		e.buf.Truncate(mark)
+		e.truncated = true
+		return errResultTooLarge
+	}
+	e.rows++
+	return nil
+}
+
+// value prepares a column value for rendering: timestamps move to the location of the
+// encoder and binary values become their encoded text; other values are returned as is
+func (e *resultEncoder) value(val interface{}) interface{} {
+	switch v := val.(type) {
+	case time.Time:
+		if e.location != nil {
+			return v.In(e.location)
+		}
+	case []byte:
+		return string(e.appendBinary(nil, v))
+	}
+	return val
+}
+
+// appendBinary appends a binary value in the encoding of the encoder. Values longer than its
+// binary limit are cut off and followed by their size, as in 0x0102...(4096 bytes).
+func (e *resultEncoder) appendBinary(dst, value []byte) []byte {
+	shown := value
+	if len(shown) > e.binaryLimit {
+		shown = shown[:e.binaryLimit]
+	}
+	if e.binary == "base64" {
+		dst = base64.StdEncoding.AppendEncode(dst, shown)
+	} else {
+		dst = append(dst, "0x"...)
+		dst = hex.AppendEncode(dst, shown)
+	}
+	if len(shown) < len(value) {
+		dst = fmt.Appendf(dst, "...(%d bytes)", len(value))
+	}
+	return dst
+}
+
+// finish closes the output, returns it and gives the buffer back to the pool
+func (e *resultEncoder) finish() string {
+	var note string
+	if e.truncated {
+		note = fmt.Sprintf("the output reached the %s limit; add a LIMIT or select fewer columns to see the rest",
+			formatBackupSize(int64(e.limit)))
+	}
+	var more string
+	if e.cursor != "" {
+		more = fmt.Sprintf("%d more rows were spilled to disk; read them with fetch_rows and cursor %s", e.remaining, e.cursor)
+	}
+	switch e.format {
+	case "json":
+		fmt.Fprintf(e.buf, `],"total_rows":%d,"truncated":%t`, e.rows, e.truncated)
+		if note != "" {
+			e.scratch = appendJSONString(e.scratch[:0], note)
+			e.buf.WriteString(`,"note":`)
+			e.buf.Write(e.scratch)
+		}
+		if e.cursor != "" {
+			e.scratch = appendJSONString(e.scratch[:0], e.cursor)
+			fmt.Fprintf(e.buf, `,"remaining_rows":%d,"cursor":`, e.remaining)
+			e.buf.Write(e.scratch)
+		}
+		e.buf.WriteByte('}')
+	case "csv":
+		if note != "" {
+			fmt.Fprintf(e.buf, "# Truncated after %d rows: %s\n", e.rows, note)
+		}
+		if more != "" {
+			fmt.Fprintf(e.buf, "# %s\n", more)
+		}
+	default:
+		fmt.Fprintf(e.buf, "\nTotal rows: %d", e.rows)
+		if note != "" {
+			fmt.Fprintf(e.buf, " (truncated: %s)", note)
+		}
+		if more != "" {
+			fmt.Fprintf(e.buf, "\n%s", more)
+		}
+	}
+
+	text := e.buf.String()
+	if e.buf.Cap() <= maxPooledResultBuffer {
+		resultBuffers.Put(e.buf)
+	}
+	e.buf = nil
+	return text
+}
+
+// appendTextValue appends a value the way fmt's %v prints it, NULL for nil and timestamps in
+// RFC 3339, without going through fmt for the common column types
+func appendTextValue(dst []byte, v interface{}) []byte {
+	switch val := v.(type) {
+	case nil:
+		return append(dst, "NULL"...)
+	case string:
+		return append(dst, val...)
+	case []byte:
+		return append(dst, val...)
+	case int64:
+		return strconv.AppendInt(dst, val, 10)
+	case int:
+		return strconv.AppendInt(dst, int64(val), 10)
+	case int32:
+		return strconv.AppendInt(dst, int64(val), 10)
+	case uint64:
+		return strconv.AppendUint(dst, val, 10)
+	case float64:
+		return strconv.AppendFloat(dst, val, 'g', -1, 64)
+	case float32:
+		return strconv.AppendFloat(dst, float64(val), 'g', -1, 32)
+	case bool:
+		return strconv.AppendBool(dst, val)
+	case time.Time:
+		return val.AppendFormat(dst, time.RFC3339Nano)
+	default:
+		return fmt.Append(dst, val)
+	}
+}
+
+// appendJSONValue appends a value as JSON; values JSON cannot represent are written as strings
+func appendJSONValue(dst []byte, v interface{}) []byte {
+	switch val := v.(type) {
+	case nil:
+		return append(dst, "null"...)
+	case string:
+		return appendJSONString(dst, val)
+	case []byte:
+		return appendJSONString(dst, string(val))
+	case int64:
+		return strconv.AppendInt(dst, val, 10)
+	case int:
+		return strconv.AppendInt(dst, int64(val), 10)
+	case int32:
+		return strconv.AppendInt(dst, int64(val), 10)
+	case uint64:
+		return strconv.AppendUint(dst, val, 10)
+	case float64:
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			return appendJSONString(dst, strconv.FormatFloat(val, 'g', -1, 64))
+		}
+		return strconv.AppendFloat(dst, val, 'g', -1, 64)
+	case bool:
+		return strconv.AppendBool(dst, val)
+	case time.Time:
+		return appendJSONString(dst, val.Format(time.RFC3339Nano))
+	default:
+		encoded, err := json.Marshal(val)
+		if err != nil {
+			return appendJSONString(dst, fmt.Sprint(val))
+		}
+		return append(dst, encoded...)
+	}
+}
+
+// appendJSONString appends a quoted JSON string, replacing invalid UTF-8 as encoding/json does
+func appendJSONString(dst []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	dst = append(dst, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				dst = append(dst, '\\', c)
+			case c == '\n':
+				dst = append(dst, '\\', 'n')
+			case c == '\r':
+				dst = append(dst, '\\', 'r')
+			case c == '\t':
+				dst = append(dst, '\\', 't')
+			case c < 0x20:
+				dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			default:
+				dst = append(dst, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, `�`...)
+		} else {
+			dst = append(dst, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(dst, '"')
+}
@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func encodingResult() *domain.QueryResult {
+	return &domain.QueryResult{
+		Columns: []string{"id", "name", "score", "created"},
+		Rows: [][]interface{}{
+			{int64(1), "alice", 1.5, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+			{int64(2), "bob, \"the\"\nbuilder", nil, []byte("raw")},
+		},
+	}
+}
+
+func TestEncodeQueryResultJSON(t *testing.T) {
+	var decoded struct {
+		Columns   []string        `json:"columns"`
+		Rows      [][]interface{} `json:"rows"`
+		TotalRows int             `json:"total_rows"`
+		Truncated bool            `json:"truncated"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(encodeQueryResult(encodingResult(), resultOptions{format: "json"})), &decoded))
+	assert.Equal(t, []string{"id", "name", "score", "created"}, decoded.Columns)
+	assert.Equal(t, [][]interface{}{
+		{float64(1), "alice", 1.5, "2024-01-02T03:04:05Z"},
+		{float64(2), "bob, \"the\"\nbuilder", nil, "0x726177"},
+	}, decoded.Rows)
+	assert.Equal(t, 2, decoded.TotalRows)
+	assert.False(t, decoded.Truncated)
+}
+
+func TestEncodeQueryResultCSV(t *testing.T) {
+	assert.Equal(t, "id,name,score,created\n"+
+		"1,alice,1.5,2024-01-02T03:04:05Z\n"+
+		"2,\"bob, \"\"the\"\"\nbuilder\",,0x726177\n", encodeQueryResult(encodingResult(), resultOptions{format: "csv"}))
+}
+
+func TestEncodeQueryResultTimezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(t, err)
+	result := encodingResult()
+
+	assert.Contains(t, encodeQueryResult(result, resultOptions{format: "text"}), "alice\t1.5\t2024-01-02T03:04:05Z\n")
+	assert.Contains(t, encodeQueryResult(result, resultOptions{format: "text", location: tokyo}), "alice\t1.5\t2024-01-02T12:04:05+09:00\n")
+	assert.Contains(t, encodeQueryResult(result, resultOptions{format: "csv", location: tokyo}), "1,alice,1.5,2024-01-02T12:04:05+09:00\n")
+	assert.Contains(t, encodeQueryResult(result, resultOptions{format: "json", location: tokyo}), `"2024-01-02T12:04:05+09:00"`)
+}
+
+func TestEncodeQueryResultValues(t *testing.T) {
+	result := &domain.QueryResult{
+		Columns: []string{"active", "note", "payload", "amount"},
+		Rows: [][]interface{}{
+			{true, nil, []byte{0x00, 0x01, 0xfe, 0xff}, 1234567.5},
+			{false, "", []byte{}, int64(-3)},
+		},
+	}
+
+	assert.Contains(t, encodeQueryResult(result, resultOptions{format: "text"}),
+		"true\tNULL\t0x0001feff\t1.2345675e+06\nfalse\t\t0x\t-3\n")
+	assert.Contains(t, encodeQueryResult(result, resultOptions{format: "text", null: `\N`, binary: "base64"}),
+		"true\t\\N\tAAH+/w==\t1.2345675e+06\n")
+	assert.Contains(t, encodeQueryResult(result, resultOptions{format: "csv", null: "NULL"}),
+		"true,NULL,0x0001feff,1.2345675e+06\n")
+	assert.Contains(t, encodeQueryResult(result, resultOptions{format: "json", null: "ignored"}),
+		`"rows":[[true,null,"0x0001feff",1.2345675e+06],[false,"","0x",-3]]`)
+
+	// Geometries are shown as extended WKT, and as GeoJSON objects in JSON
+	point := domain.Geometry{SRID: 4326, WKT: "POINT(1 2)", GeoJSON: `{"type":"Point","coordinates":[1,2]}`}
+	spatial := &domain.QueryResult{Columns: []string{"location"}, Rows: [][]interface{}{{point}}}
+	assert.Contains(t, encodeQueryResult(spatial, resultOptions{format: "text"}), "SRID=4326;POINT(1 2)\n")
+	assert.Contains(t, encodeQueryResult(spatial, resultOptions{format: "csv"}), "SRID=4326;POINT(1 2)\n")
+	assert.Contains(t, encodeQueryResult(spatial, resultOptions{format: "json"}), `"rows":[[{"type":"Point","coordinates":[1,2]}]]`)
+
+	// Long binary values are cut off and their size noted
+	assert.Contains(t, encodeQueryResult(result, resultOptions{format: "text", binaryLimit: 2}),
+		"true\tNULL\t0x0001...(4 bytes)\t")
+}
+
+func TestAppendJSONString(t *testing.T) {
+	for _, s := range []string{"plain", "tab\there", "ctrl\x01", "quote\"back\\slash", "ünïcode ✓", "bad\xffutf8"} {
+		expected, err := json.Marshal(s)
+		assert.NoError(t, err)
+		var decoded, want string
+		assert.NoError(t, json.Unmarshal(appendJSONString(nil, s), &decoded))
+		assert.NoError(t, json.Unmarshal(expected, &want))
+		assert.Equal(t, want, decoded, s)
+	}
+}
+
+func TestEncodeQueryResultStopsAtLimit(t *testing.T) {
+	limit := maxResultBytes
+	maxResultBytes = 200
+	t.Cleanup(func() { maxResultBytes = limit })
+
+	result := &domain.QueryResult{Columns: []string{"line"}}
+	for i := 0; i < 100; i++ {
+		result.Rows = append(result.Rows, []interface{}{strings.Repeat("x", 20)})
+	}
+
+	text := encodeQueryResult(result, resultOptions{format: "text"})
+	assert.LessOrEqual(t, len(text)-len(text[strings.Index(text, "\nTotal rows"):]), 200)
+	assert.Contains(t, text, "Total rows: 4 (truncated: the output reached the 200 B limit")
+
+	var decoded struct {
+		Rows      [][]interface{} `json:"rows"`
+		TotalRows int             `json:"total_rows"`
+		Truncated bool            `json:"truncated"`
+		Note      string          `json:"note"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(encodeQueryResult(result, resultOptions{format: "json"})), &decoded))
+	assert.True(t, decoded.Truncated)
+	assert.Len(t, decoded.Rows, decoded.TotalRows)
+	assert.Contains(t, decoded.Note, "add a LIMIT")
+
+	assert.Contains(t, encodeQueryResult(result, resultOptions{format: "csv"}), "# Truncated after 9 rows")
+}
+
+func TestEncodeQueryResultPointsToSpilledRows(t *testing.T) {
+	result := &domain.QueryResult{Columns: []string{"id"}, Rows: [][]interface{}{{int64(1)}}, Cursor: "abc", Remaining: 41}
+
+	var decoded struct {
+		RemainingRows int64  `json:"remaining_rows"`
+		Cursor        string `json:"cursor"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(encodeQueryResult(result, resultOptions{format: "json"})), &decoded))
+	assert.Equal(t, int64(41), decoded.RemainingRows)
+	assert.Equal(t, "abc", decoded.Cursor)
+
+	assert.True(t, strings.HasSuffix(encodeQueryResult(result, resultOptions{format: "csv"}), "# 41 more rows were spilled to disk; read them with fetch_rows and cursor abc\n"))
+}
@@ -0,0 +1,457 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// ReviewSchemaTool handles reviewing a schema for design smells
+type ReviewSchemaTool struct {
+	BaseToolType
+}
+
+// NewReviewSchemaTool creates a new review schema tool type
+func NewReviewSchemaTool() *ReviewSchemaTool {
+	return &ReviewSchemaTool{
+		BaseToolType: BaseToolType{
+			name:        "review_schema",
+			description: "Review a schema for common design smells: tables without a primary key, foreign keys without a supporting index, nullable foreign key columns used in joins, overly wide VARCHAR columns, mixed collations and tables without an updated_at column. Each finding comes with an explanation of why it matters and suggested DDL to fix it. The tool only reads metadata; it never applies the suggested DDL.",
+		},
+	}
+}
+
+// CreateTool creates a review schema tool
+func (t *ReviewSchemaTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Flag schema design smells with explanations and suggested DDL"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to review (optional, defaults to public on PostgreSQL and the current database on MySQL)"),
+		),
+		tools.WithArray("tables",
+			tools.Description("Tables to review (optional, leave empty for all tables in the schema)"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithArray("checks",
+			tools.Description("Checks to run (optional, default all): missing_primary_key, unindexed_foreign_key, nullable_join_column, wide_varchar, mixed_collation, missing_updated_at"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithNumber("max_varchar_length",
+			tools.Description("VARCHAR length above which a column is reported as overly wide (default: 1000)"),
+		),
+	)
+}
+
+// HandleRequest handles review schema tool requests
+func (t *ReviewSchemaTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema := input.optionalString("schema", "")
+	tableNames := input.stringList("tables")
+	checks := input.stringList("checks")
+	options := schemaReviewOptions{maxVarcharLength: input.intAtLeast("max_varchar_length", 1000, 1)}
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if len(checks) > 0 {
+		options.checks = make(map[string]bool)
+		for _, check := range checks {
+			if !schemaReviewChecks[check] {
+				return nil, fmt.Errorf("unknown check: %s", check)
+			}
+			options.checks[check] = true
+		}
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	// The key and index checks do not apply where keys are not enforced
+	if dialect, ok := lookupDialect(dbType); ok && !dialect.EnforcesKeys() {
+		return nil, fmt.Errorf("unsupported database type for review_schema: %s", dbType)
+	}
+
+	logger.Info("Reviewing schema %s of database %s", schema, targetDbID)
+
+	meta, err := schemaCache.load(ctx, useCase, targetDbID, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to review schema: %w", err)
+	}
+	// Collations are compared across the whole schema, so remember them before filtering
+	options.defaultCollation = dominantCollation(meta)
+	if len(tableNames) > 0 {
+		meta, err = meta.filterTables(tableNames)
+		if err != nil {
+			return nil, fmt.Errorf("failed to review schema: %w", err)
+		}
+	}
+
+	findings := reviewSchema(meta, options)
+
+	// Format the response
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Schema Review for Database %s\n\n", targetDbID))
+	response.WriteString(formatSchemaFindings(findings, len(meta.Tables)))
+
+	return createTextResponse(response.String()), nil
+}
+
+// schemaReviewChecks lists the checks supported by the schema review
+var schemaReviewChecks = map[string]bool{
+	"missing_primary_key":   true,
+	"unindexed_foreign_key": true,
+	"nullable_join_column":  true,
+	"wide_varchar":          true,
+	"mixed_collation":       true,
+	"missing_updated_at":    true,
+}
+
+// schemaReviewOptions configures a schema review
+type schemaReviewOptions struct {
+	checks           map[string]bool // nil runs every check
+	maxVarcharLength int
+	defaultCollation string
+}
+
+func (o schemaReviewOptions) enabled(check string) bool {
+	return o.checks == nil || o.checks[check]
+}
+
+// schemaFinding is a single design smell found by the schema review
+type schemaFinding struct {
+	Severity    string
+	Check       string
+	Table       string
+	Column      string
+	Explanation string
+	DDL         string
+}
+
+var severityOrder = map[string]int{"high": 0, "warning": 1, "info": 2}
+
+// sqliteRebuildNote is the DDL of findings SQLite's ALTER TABLE cannot fix in place
+const sqliteRebuildNote = "-- SQLite cannot add keys or constraints to existing columns: create the corrected table\n-- under a new name, copy the rows with INSERT INTO ... SELECT, drop the old table and\n-- rename the new one, in one transaction with foreign_keys off"
+
+var varcharLength = regexp.MustCompile(`(?i)^(?:character varying|varchar)\((\d+)\)`)
+
+var updatedAtColumns = map[string]bool{
+	"updated_at": true, "updatedat": true, "modified_at": true, "modifiedat": true,
+	"updated_on": true, "modified_on": true, "last_modified": true, "last_updated": true, "date_modified": true,
+}
+
+// reviewSchema runs the enabled checks against the schema metadata
+func reviewSchema(meta *schemaMetadata, options schemaReviewOptions) []schemaFinding {
+	dbType := meta.DatabaseType
+	var fixer schemaFixer
+	if dialect, ok := lookupDialect(dbType); ok {
+		fixer, _ = dialect.SchemaFixer()
+	}
+	var findings []schemaFinding
+
+	for _, table := range meta.Tables {
+		ref := quoteIdentifier(dbType, table.Name)
+
+		if options.enabled("missing_primary_key") && len(table.PrimaryKey) == 0 {
+			finding := schemaFinding{
+				Severity:    "high",
+				Check:       "missing_primary_key",
+				Table:       table.Name,
+				Explanation: "Without a primary key rows cannot be identified reliably: duplicates can creep in, updates and deletes cannot target a single row, and logical replication and many ORMs refuse to work with the table.",
+			}
+			if fixer != nil {
+				idColumn := ""
+				if col := table.column("id"); col != nil {
+					idColumn = col.Name
+				}
+				finding.DDL = fixer.addPrimaryKey(ref, idColumn)
+			}
+			findings = append(findings, finding)
+		}
+
+		if options.enabled("missing_updated_at") && !hasUpdatedAtColumn(table) {
+			finding := schemaFinding{
+				Severity:    "info",
+				Check:       "missing_updated_at",
+				Table:       table.Name,
+				Explanation: "Without an updated_at column there is no cheap way to find recently changed rows for incremental syncs, caches or audits.",
+			}
+			if fixer != nil {
+				finding.DDL = fixer.addUpdatedAt(ref)
+			}
+			findings = append(findings, finding)
+		}
+
+		for _, col := range table.Columns {
+			if options.enabled("wide_varchar") && (fixer == nil || fixer.checksVarcharLength()) {
+				if m := varcharLength.FindStringSubmatch(col.DataType); m != nil {
+					if length, err := strconv.Atoi(m[1]); err == nil && length > options.maxVarcharLength {
+						finding := schemaFinding{
+							Severity: "info",
+							Check:    "wide_varchar",
+							Table:    table.Name,
+							Column:   col.Name,
+							Explanation: fmt.Sprintf("%s allows %d characters. Such a limit rarely reflects a real constraint on the data, so it validates nothing; very wide columns also cannot be fully indexed and inflate memory grants for sorts and temporary tables.",
+								col.DataType, length),
+						}
+						if fixer != nil {
+							finding.DDL = fixer.relaxVarchar(ref, col)
+						}
+						findings = append(findings, finding)
+					}
+				}
+			}
+
+			if options.enabled("mixed_collation") && col.Collation != "" && options.defaultCollation != "" && col.Collation != options.defaultCollation {
+				finding := schemaFinding{
+					Severity: "warning",
+					Check:    "mixed_collation",
+					Table:    table.Name,
+					Column:   col.Name,
+					Explanation: fmt.Sprintf("This column uses collation %s while most of the schema uses %s. Comparing or joining columns with different collations either fails or prevents index use, and sorting differs between columns.",
+						col.Collation, options.defaultCollation),
+				}
+				if fixer != nil {
+					finding.DDL = fixer.changeCollation(ref, col, options.defaultCollation)
+				}
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	for _, fk := range meta.ForeignKeys {
+		table := meta.table(schemaTableKey(fk.Schema, fk.Table))
+		if table == nil {
+			continue
+		}
+		ref := quoteIdentifier(dbType, table.Name)
+
+		if options.enabled("unindexed_foreign_key") && !hasLeadingIndex(table, fk.Columns) {
+			quoted := make([]string, len(fk.Columns))
+			for i, column := range fk.Columns {
+				quoted[i] = quoteIdentifier(dbType, column)
+			}
+			indexName := quoteIdentifier(dbType, fmt.Sprintf("idx_%s_%s", table.Name, strings.Join(fk.Columns, "_")))
+			findings = append(findings, schemaFinding{
+				Severity: "warning",
+				Check:    "unindexed_foreign_key",
+				Table:    table.Name,
+				Column:   strings.Join(fk.Columns, ", "),
+				Explanation: fmt.Sprintf("Foreign key %s to %s has no index starting with its columns. Joins from %s are slow, and every delete or key update on %s scans %s to check for referencing rows.",
+					fk.Name, fk.RefTable, fk.RefTable, fk.RefTable, table.Name),
+				DDL: fmt.Sprintf("CREATE INDEX %s ON %s (%s);", indexName, ref, strings.Join(quoted, ", ")),
+			})
+		}
+
+		if options.enabled("nullable_join_column") {
+			for _, column := range fk.Columns {
+				col := table.column(column)
+				if col == nil || !col.Nullable {
+					continue
+				}
+				finding := schemaFinding{
+					Severity: "info",
+					Check:    "nullable_join_column",
+					Table:    table.Name,
+					Column:   column,
+					Explanation: fmt.Sprintf("%s joins to %s but accepts NULL. Inner joins silently drop rows without a parent; if every row must have one, enforce it. If the relationship is genuinely optional, use LEFT JOIN.",
+						column, fk.RefTable),
+				}
+				if fixer != nil {
+					finding.DDL = fixer.setNotNull(ref, *col)
+				}
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if severityOrder[findings[i].Severity] != severityOrder[findings[j].Severity] {
+			return severityOrder[findings[i].Severity] < severityOrder[findings[j].Severity]
+		}
+		return findings[i].Table < findings[j].Table
+	})
+	return findings
+}
+
+// hasUpdatedAtColumn reports whether the table has a column tracking the last modification
+func hasUpdatedAtColumn(table *schemaTable) bool {
+	for _, col := range table.Columns {
+		if updatedAtColumns[strings.ToLower(col.Name)] {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLeadingIndex reports whether an index (or the primary key) starts with the given columns in any order
+func hasLeadingIndex(table *schemaTable, columns []string) bool {
+	candidates := [][]string{table.PrimaryKey}
+	for _, index := range table.Indexes {
+		candidates = append(candidates, index.Columns)
+	}
+	for _, candidate := range candidates {
+		if len(candidate) >= len(columns) && sameColumnSet(candidate[:len(columns)], columns) {
+			return true
+		}
+	}
+	return false
+}
+
+// dominantCollation returns the most common column collation of the schema
+func dominantCollation(meta *schemaMetadata) string {
+	counts := make(map[string]int)
+	for _, table := range meta.Tables {
+		for _, col := range table.Columns {
+			if col.Collation != "" {
+				counts[col.Collation]++
+			}
+		}
+	}
+	best := ""
+	for collation, count := range counts {
+		if count > counts[best] || (count == counts[best] && collation < best) {
+			best = collation
+		}
+	}
+	return best
+}
+
+// schemaFixer writes the DDL fixing the findings of the schema review; ref is the quoted table
+type schemaFixer interface {
+	// addPrimaryKey makes idColumn the primary key, or adds one when idColumn is ""
+	addPrimaryKey(ref, idColumn string) string
+	addUpdatedAt(ref string) string
+	// checksVarcharLength reports whether declared VARCHAR lengths are enforced
+	checksVarcharLength() bool
+	relaxVarchar(ref string, col schemaColumn) string
+	changeCollation(ref string, col schemaColumn, collation string) string
+	setNotNull(ref string, col schemaColumn) string
+}
+
+// postgresSchemaFixer fixes findings with PostgreSQL's ALTER COLUMN
+type postgresSchemaFixer struct{}
+
+func (postgresSchemaFixer) addPrimaryKey(ref, idColumn string) string {
+	if idColumn != "" {
+		return fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s);", ref, quoteIdentifier("postgres", idColumn))
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY;", ref)
+}
+
+func (postgresSchemaFixer) addUpdatedAt(ref string) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();\n-- Keep it current with a BEFORE UPDATE trigger that sets NEW.updated_at = now()", ref)
+}
+
+func (postgresSchemaFixer) checksVarcharLength() bool { return true }
+
+func (postgresSchemaFixer) relaxVarchar(ref string, col schemaColumn) string {
+	return fmt.Sprintf("-- Use text, or a limit that matches the data\nALTER TABLE %s ALTER COLUMN %s TYPE text;", ref, quoteIdentifier("postgres", col.Name))
+}
+
+func (postgresSchemaFixer) changeCollation(ref string, col schemaColumn, collation string) string {
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s COLLATE %s;", ref, quoteIdentifier("postgres", col.Name), col.DataType, quoteIdentifier("postgres", collation))
+}
+
+func (postgresSchemaFixer) setNotNull(ref string, col schemaColumn) string {
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", ref, quoteIdentifier("postgres", col.Name))
+}
+
+// mysqlSchemaFixer fixes findings with MySQL's MODIFY, which restates the whole column
+type mysqlSchemaFixer struct{}
+
+func (mysqlSchemaFixer) addPrimaryKey(ref, idColumn string) string {
+	if idColumn != "" {
+		return fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s);", ref, quoteIdentifier("mysql", idColumn))
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY FIRST;", ref)
+}
+
+func (mysqlSchemaFixer) addUpdatedAt(ref string) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;", ref)
+}
+
+func (mysqlSchemaFixer) checksVarcharLength() bool { return true }
+
+func (mysqlSchemaFixer) relaxVarchar(ref string, col schemaColumn) string {
+	return fmt.Sprintf("-- Choose the real maximum length of the data\nALTER TABLE %s MODIFY %s VARCHAR(255)%s;", ref, quoteIdentifier("mysql", col.Name), notNullSuffix(col))
+}
+
+func (mysqlSchemaFixer) changeCollation(ref string, col schemaColumn, collation string) string {
+	return fmt.Sprintf("ALTER TABLE %s MODIFY %s %s COLLATE %s%s;", ref, quoteIdentifier("mysql", col.Name), col.DataType, collation, notNullSuffix(col))
+}
+
+func (mysqlSchemaFixer) setNotNull(ref string, col schemaColumn) string {
+	return fmt.Sprintf("ALTER TABLE %s MODIFY %s %s NOT NULL;", ref, quoteIdentifier("mysql", col.Name), col.DataType)
+}
+
+// sqliteSchemaFixer fixes what SQLite's limited ALTER TABLE allows and points at a table rebuild
+// for the rest
+type sqliteSchemaFixer struct{}
+
+func (sqliteSchemaFixer) addPrimaryKey(string, string) string { return sqliteRebuildNote }
+
+func (sqliteSchemaFixer) addUpdatedAt(ref string) string {
+	return fmt.Sprintf("-- SQLite cannot default an added column to the current time\nALTER TABLE %s ADD COLUMN updated_at TEXT;\n-- Keep it current with an AFTER UPDATE trigger that sets updated_at = CURRENT_TIMESTAMP", ref)
+}
+
+// checksVarcharLength reports false; SQLite ignores declared lengths, so a wide VARCHAR costs
+// nothing there
+func (sqliteSchemaFixer) checksVarcharLength() bool { return false }
+
+func (sqliteSchemaFixer) relaxVarchar(string, schemaColumn) string { return "" }
+
+func (sqliteSchemaFixer) changeCollation(string, schemaColumn, string) string {
+	return sqliteRebuildNote
+}
+
+func (sqliteSchemaFixer) setNotNull(string, schemaColumn) string { return sqliteRebuildNote }
+
+// notNullSuffix keeps NOT NULL on MySQL MODIFY statements, which otherwise drop it
+func notNullSuffix(col schemaColumn) string {
+	if col.Nullable {
+		return ""
+	}
+	return " NOT NULL"
+}
+
+// formatSchemaFindings renders schema review findings as markdown
+func formatSchemaFindings(findings []schemaFinding, tableCount int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Tables reviewed: %d\n", tableCount))
+	if len(findings) == 0 {
+		sb.WriteString("\nNo design issues found.\n")
+		return sb.String()
+	}
+
+	counts := make(map[string]int)
+	for _, finding := range findings {
+		counts[finding.Severity]++
+	}
+	sb.WriteString(fmt.Sprintf("Findings: %d (high: %d, warning: %d, info: %d)\n\n", len(findings), counts["high"], counts["warning"], counts["info"]))
+
+	for i, finding := range findings {
+		subject := finding.Table
+		if finding.Column != "" {
+			subject += "." + finding.Column
+		}
+		sb.WriteString(fmt.Sprintf("## %d. [%s] %s: %s\n\n", i+1, finding.Severity, strings.ReplaceAll(finding.Check, "_", " "), subject))
+		sb.WriteString(finding.Explanation + "\n\n")
+		if finding.DDL != "" {
+			sb.WriteString(fmt.Sprintf("```sql\n%s\n```\n\n", finding.DDL))
+		}
+	}
+	return sb.String()
+}
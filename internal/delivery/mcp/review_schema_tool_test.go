@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func findingChecks(findings []schemaFinding) map[string][]string {
+	checks := make(map[string][]string)
+	for _, finding := range findings {
+		subject := finding.Table
+		if finding.Column != "" {
+			subject += "." + finding.Column
+		}
+		checks[finding.Check] = append(checks[finding.Check], subject)
+	}
+	return checks
+}
+
+func TestReviewSchema(t *testing.T) {
+	meta := testSchemaMetadata()
+	users := meta.table("users")
+	users.Columns = append(users.Columns,
+		schemaColumn{Name: "bio", DataType: "character varying(5000)", Collation: "default"},
+		schemaColumn{Name: "updated_at", DataType: "timestamp with time zone"},
+		schemaColumn{Name: "nickname", DataType: "text", Collation: "C"},
+	)
+	users.Columns[1].Collation = "default"
+	meta.table("orders").Indexes = []schemaIndex{{Name: "idx_orders_user", Columns: []string{"user_id", "id"}}}
+	meta.Tables = append(meta.Tables, &schemaTable{Schema: "public", Name: "audit_log", Columns: []schemaColumn{{Name: "message", DataType: "text"}}})
+
+	options := schemaReviewOptions{maxVarcharLength: 1000, defaultCollation: dominantCollation(meta)}
+	findings := reviewSchema(meta, options)
+	checks := findingChecks(findings)
+
+	assert.Equal(t, "default", options.defaultCollation)
+	assert.Equal(t, []string{"audit_log"}, checks["missing_primary_key"])
+	assert.Equal(t, []string{"orders.coupon_id"}, checks["unindexed_foreign_key"])
+	assert.Equal(t, []string{"orders.coupon_id"}, checks["nullable_join_column"])
+	assert.Equal(t, []string{"users.bio"}, checks["wide_varchar"])
+	assert.Equal(t, []string{"users.nickname"}, checks["mixed_collation"])
+	assert.NotContains(t, checks["missing_updated_at"], "users")
+	assert.Contains(t, checks["missing_updated_at"], "orders")
+
+	// High severity findings come first
+	assert.Equal(t, "missing_primary_key", findings[0].Check)
+	assert.Equal(t, `ALTER TABLE "audit_log" ADD COLUMN id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY;`, findings[0].DDL)
+}
+
+func TestReviewSchemaSelectedChecks(t *testing.T) {
+	meta := testSchemaMetadata()
+	meta.DatabaseType = "mysql"
+
+	findings := reviewSchema(meta, schemaReviewOptions{
+		checks:           map[string]bool{"unindexed_foreign_key": true},
+		maxVarcharLength: 1000,
+	})
+	for _, finding := range findings {
+		assert.Equal(t, "unindexed_foreign_key", finding.Check)
+	}
+	assert.Contains(t, findings[0].DDL, "CREATE INDEX `idx_orders_")
+}
+
+func TestReviewSchemaSQLite(t *testing.T) {
+	meta := testSchemaMetadata()
+	meta.DatabaseType = "sqlite"
+	meta.table("users").Columns = append(meta.table("users").Columns, schemaColumn{Name: "bio", DataType: "VARCHAR(5000)"})
+	meta.Tables = append(meta.Tables, &schemaTable{Schema: "main", Name: "audit_log", Columns: []schemaColumn{{Name: "id", DataType: "INTEGER"}}})
+
+	findings := reviewSchema(meta, schemaReviewOptions{maxVarcharLength: 1000})
+	checks := findingChecks(findings)
+	assert.Empty(t, checks["wide_varchar"])
+	assert.Equal(t, "missing_primary_key", findings[0].Check)
+	assert.Equal(t, sqliteRebuildNote, findings[0].DDL)
+	for _, finding := range findings {
+		assert.NotContains(t, finding.DDL, "MODIFY")
+		if finding.Check == "missing_updated_at" {
+			assert.Contains(t, finding.DDL, "ADD COLUMN updated_at TEXT;")
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// staleEstimateRatio is how far an estimate may be off the exact count before the table's
+// statistics are called stale
+const staleEstimateRatio = 0.2
+
+// tableRowCount is the estimated and, when counted, exact number of rows of a table
+type tableRowCount struct {
+	Schema       string
+	Table        string
+	Estimated    int64
+	LastAnalyzed string // PostgreSQL only
+	Exact        int64
+	Counted      bool
+	Error        string
+}
+
+// RowCountsTool handles counting the rows of every table in a schema
+type RowCountsTool struct {
+	BaseToolType
+}
+
+// NewRowCountsTool creates a new row counts tool type
+func NewRowCountsTool() *RowCountsTool {
+	return &RowCountsTool{
+		BaseToolType: BaseToolType{
+			name:        "row_counts",
+			description: "Return the row count of every table in a schema in one call. Estimates come from the planner statistics (reltuples on PostgreSQL, summed over the partitions of a partitioned table, and TABLE_ROWS on MySQL, which can be off by half for InnoDB) and cost nothing. With exact set, tables estimated at no more than exact_threshold rows are also counted with COUNT(*), one table at a time, and tables whose estimate is far off the exact count are called out so their statistics can be refreshed.",
+		},
+	}
+}
+
+// CreateTool creates a row counts tool
+func (t *RowCountsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Estimated and optionally exact row counts of every table in a schema"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to count (default: the current schema or database)"),
+		),
+		tools.WithBoolean("exact",
+			tools.Description("Also count rows exactly with COUNT(*) for tables up to exact_threshold estimated rows (default: false)"),
+		),
+		tools.WithNumber("exact_threshold",
+			tools.Description("Largest estimated row count to count exactly (default: 100000)"),
+		),
+	)
+}
+
+// HandleRequest handles row counts tool requests
+func (t *RowCountsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema := input.optionalString("schema", "")
+	exact := input.optionalBool("exact", false)
+	threshold := int64(input.intAtLeast("exact_threshold", 100000, 0))
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Counting rows for database %s, schema %s", targetDbID, schema)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for row counts: %s", dbType)
+	}
+	query, withAnalyzed, ok := dialect.RowEstimatesQuery()
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for row counts: %s", dbType)
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, targetDbID, query, []interface{}{schema})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get row estimates: %w", err)
+	}
+	var counts []tableRowCount
+	for _, row := range result.Rows {
+		if len(row) < 4 {
+			continue
+		}
+		counts = append(counts, tableRowCount{
+			Schema:       valueString(row[0]),
+			Table:        valueString(row[1]),
+			Estimated:    valueInt64(row[2]),
+			LastAnalyzed: valueString(row[3]),
+		})
+	}
+
+	if exact {
+		for i := range counts {
+			c := &counts[i]
+			if c.Estimated > threshold {
+				continue
+			}
+			countQuery := "SELECT COUNT(*) FROM " + quoteIdentifier(dialect.Name(), qualifiedName(c.Schema, c.Table))
+			countResult, err := useCase.ExecuteQuery(ctx, targetDbID, countQuery, nil)
+			if err != nil {
+				c.Error = err.Error()
+				continue
+			}
+			if len(countResult.Rows) > 0 && len(countResult.Rows[0]) > 0 {
+				c.Exact, c.Counted = valueInt64(countResult.Rows[0][0]), true
+			}
+		}
+	}
+
+	var response strings.Builder
+	if schema == "" && len(counts) > 0 {
+		schema = counts[0].Schema
+	}
+	response.WriteString(fmt.Sprintf("# Row Counts in Schema %s of Database %s\n\n", orDash(schema), targetDbID))
+	writeRowCounts(&response, counts, withAnalyzed, exact, threshold)
+
+	return createTextResponse(response.String()), nil
+}
+
+// writeRowCounts renders the counts, the total and the tables counted exactly whose
+// statistics are stale, with when each table was last analyzed if the engine reports it
+func writeRowCounts(sb *strings.Builder, counts []tableRowCount, withAnalyzed, exact bool, threshold int64) {
+	if len(counts) == 0 {
+		sb.WriteString("No tables found.\n")
+		return
+	}
+
+	header := "| Table | Estimated Rows |"
+	separator := "|-------|----------------|"
+	if exact {
+		header += " Exact Rows |"
+		separator += "------------|"
+	}
+	if withAnalyzed {
+		header += " Last Analyzed |"
+		separator += "---------------|"
+	}
+	sb.WriteString(header + "\n" + separator + "\n")
+
+	var total int64
+	var skipped int
+	var warnings []string
+	for _, c := range counts {
+		line := fmt.Sprintf("| %s | %d |", c.Table, c.Estimated)
+		rows := c.Estimated
+		if exact {
+			switch {
+			case c.Counted:
+				line += fmt.Sprintf(" %d |", c.Exact)
+				rows = c.Exact
+				if diff := c.Exact - c.Estimated; diff != 0 && float64(abs64(diff)) > staleEstimateRatio*float64(c.Exact) {
+					warnings = append(warnings, fmt.Sprintf("The estimate for %s is off by %d rows; refresh its statistics with analyze_table.", c.Table, diff))
+				}
+			case c.Error != "":
+				line += " error |"
+				warnings = append(warnings, fmt.Sprintf("Counting %s failed: %s", c.Table, c.Error))
+			default:
+				line += " - |"
+				skipped++
+			}
+		}
+		if withAnalyzed {
+			line += fmt.Sprintf(" %s |", orDash(c.LastAnalyzed))
+		}
+		sb.WriteString(line + "\n")
+		total += rows
+	}
+
+	sb.WriteString(fmt.Sprintf("\nTotal: about %d rows in %d tables.\n", total, len(counts)))
+	if skipped > 0 {
+		sb.WriteString(fmt.Sprintf("%d tables estimated at more than %d rows were not counted exactly; raise exact_threshold to count them.\n", skipped, threshold))
+	}
+	if len(warnings) > 0 {
+		sb.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+}
+
+// abs64 returns the absolute value of n
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// getPostgresRowEstimatesQuery returns a query for the row estimate of every table of the
+// schema, or of the current schema. A partitioned table sums its leaf partitions, which are
+// not listed themselves; a table never analyzed falls back to the live tuple count.
+func getPostgresRowEstimatesQuery() string {
+	return `
+SELECT
+    n.nspname AS schema_name,
+    c.relname AS table_name,
+    CASE
+        WHEN c.relkind = 'p' THEN (
+            SELECT COALESCE(SUM(GREATEST(l.reltuples, 0)), 0)::bigint
+            FROM pg_partition_tree(c.oid) pt
+            JOIN pg_class l ON l.oid = pt.relid
+            WHERE pt.isleaf)
+        WHEN c.reltuples < 0 THEN COALESCE(s.n_live_tup, 0)
+        ELSE c.reltuples::bigint
+    END AS estimated_rows,
+    COALESCE(GREATEST(s.last_analyze, s.last_autoanalyze)::text, '') AS last_analyzed
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_stat_user_tables s ON s.relid = c.oid
+WHERE c.relkind IN ('r', 'p')
+  AND NOT c.relispartition
+  AND n.nspname = COALESCE(NULLIF($1, ''), current_schema())
+ORDER BY c.relname;`
+}
+
+// getMySQLRowEstimatesQuery returns a query for the row estimate of every base table of the
+// schema, or of the current database
+func getMySQLRowEstimatesQuery() string {
+	return `
+SELECT
+    TABLE_SCHEMA AS schema_name,
+    TABLE_NAME AS table_name,
+    COALESCE(TABLE_ROWS, 0) AS estimated_rows,
+    '' AS last_analyzed
+FROM information_schema.TABLES
+WHERE TABLE_TYPE = 'BASE TABLE'
+  AND TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+ORDER BY TABLE_NAME;`
+}
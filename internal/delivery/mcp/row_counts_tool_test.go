@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestRowCountsToolPostgres(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"reltuples": {Rows: [][]interface{}{
+				{"public", "events", int64(5000000), "2026-10-01 12:00:00+00"},
+				{"public", "orders", int64(1000), "2026-10-01 12:00:00+00"},
+				{"public", "users", int64(200), ""},
+			}},
+			`FROM "public"."orders"`: {Rows: [][]interface{}{{int64(1500)}}},
+			`FROM "public"."users"`:  {Rows: [][]interface{}{{int64(210)}}},
+		},
+	}
+
+	result, err := NewRowCountsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "exact": true},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "# Row Counts in Schema public of Database pg1")
+	assert.Contains(t, text, "| events | 5000000 | - | 2026-10-01 12:00:00+00 |")
+	assert.Contains(t, text, "| orders | 1000 | 1500 | 2026-10-01 12:00:00+00 |")
+	assert.Contains(t, text, "| users | 200 | 210 | - |")
+	assert.Contains(t, text, "Total: about 5001710 rows in 3 tables.")
+	assert.Contains(t, text, "1 tables estimated at more than 100000 rows were not counted exactly")
+	assert.Contains(t, text, "The estimate for orders is off by 500 rows")
+	assert.NotContains(t, text, "The estimate for users")
+	assert.NotContains(t, useCase.queries, `SELECT COUNT(*) FROM "public"."events"`)
+}
+
+func TestRowCountsToolMySQLEstimatesOnly(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"TABLE_ROWS": {Rows: [][]interface{}{{"shop", "orders", int64(1000), ""}}},
+		},
+	}
+
+	result, err := NewRowCountsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "my1"},
+	}, "my1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| Table | Estimated Rows |\n|-------|----------------|\n| orders | 1000 |")
+	assert.Len(t, useCase.queries, 1)
+}
@@ -0,0 +1,418 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// ddlStatementWords lists the leading keywords of the statements run_ddl accepts
+var ddlStatementWords = []string{"CREATE", "ALTER", "DROP", "TRUNCATE", "RENAME", "COMMENT", "REINDEX"}
+
+// lockContentionErrors are the error fragments that mean the statement gave up waiting for a lock
+var lockContentionErrors = []string{"lock timeout", "lock wait timeout", "deadlock"}
+
+// ddlBlocker is a session holding a lock the DDL statement would wait for
+type ddlBlocker struct {
+	PID         int64
+	User        string
+	State       string
+	XactSeconds int64
+	Mode        string
+	Relation    string
+	Query       string
+}
+
+// ddlRunOptions controls how run_ddl executes a statement
+type ddlRunOptions struct {
+	LockTimeout      time.Duration
+	StatementTimeout time.Duration
+	Retries          int
+	Backoff          time.Duration
+	BlockerThreshold time.Duration
+	Force            bool
+	DryRun           bool
+}
+
+// RunDDLTool handles running schema changes safely on busy databases
+type RunDDLTool struct {
+	BaseToolType
+}
+
+// NewRunDDLTool creates a new DDL runner tool type
+func NewRunDDLTool() *RunDDLTool {
+	return &RunDDLTool{
+		BaseToolType: BaseToolType{
+			name:        "run_ddl",
+			description: "Run a single schema change safely on a busy database. The statement runs with a short lock timeout (lock_timeout on PostgreSQL, lock_wait_timeout on MySQL) so it gives up instead of queueing every other query behind it, and is retried with exponential backoff when it loses the race for its locks. Before running, the tool reports the exact locks the statement needs and checks for open transactions holding locks on the affected tables; the statement is not run while one has been open longer than blocker_threshold_seconds unless force is set. Use dry_run to see the lock report without running anything.",
+		},
+	}
+}
+
+// CreateTool creates a DDL runner tool
+func (t *RunDDLTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Run a schema change with lock timeouts, retries and blocker detection"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("statement",
+			tools.Description("The DDL statement to run (exactly one)"),
+			tools.Required(),
+		),
+		tools.WithNumber("lock_timeout_ms",
+			tools.Description("How long to wait for locks before giving up, in milliseconds (default: 2000; rounded up to whole seconds on MySQL)"),
+		),
+		tools.WithNumber("statement_timeout_ms",
+			tools.Description("Maximum run time of the statement in milliseconds, PostgreSQL only (default: 0, no limit)"),
+		),
+		tools.WithNumber("retries",
+			tools.Description("How often to retry after a lock timeout or deadlock (default: 3)"),
+		),
+		tools.WithNumber("backoff_ms",
+			tools.Description("Wait before the first retry in milliseconds, doubled after every attempt (default: 500)"),
+		),
+		tools.WithNumber("blocker_threshold_seconds",
+			tools.Description("Refuse to run while a transaction holding locks on the affected tables has been open this long (default: 10)"),
+		),
+		tools.WithBoolean("force",
+			tools.Description("Run even if long-running blocking transactions were found (default: false)"),
+		),
+		tools.WithBoolean("dry_run",
+			tools.Description("Only report the locks and blocking sessions (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles DDL runner tool requests
+func (t *RunDDLTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	statement := input.requiredString("statement")
+	opts := ddlRunOptions{
+		LockTimeout:      time.Duration(input.intAtLeast("lock_timeout_ms", 2000, 1)) * time.Millisecond,
+		StatementTimeout: time.Duration(input.intAtLeast("statement_timeout_ms", 0, 1)) * time.Millisecond,
+		Retries:          input.intAtLeast("retries", 3, 0),
+		Backoff:          time.Duration(input.intAtLeast("backoff_ms", 500, 0)) * time.Millisecond,
+		Force:            input.optionalBool("force", false),
+		DryRun:           input.optionalBool("dry_run", false),
+	}
+	thresholdSeconds := input.optionalFloat("blocker_threshold_seconds", 10)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if thresholdSeconds < 0 {
+		return nil, fmt.Errorf("blocker_threshold_seconds parameter must be at least 0")
+	}
+	opts.BlockerThreshold = time.Duration(thresholdSeconds * float64(time.Second))
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for run_ddl: %s", dbType)
+	}
+	runner, err := dialect.DDLRunner()
+	if err != nil {
+		return nil, unsupportedAdminError("run_ddl", dbType, err)
+	}
+
+	statements, err := splitSQLStatements(statement, dialect.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse statement: %w", err)
+	}
+	if len(statements) != 1 {
+		return nil, fmt.Errorf("statement must contain exactly one SQL statement, got %d", len(statements))
+	}
+	statement = statements[0]
+	tokens, err := tokenizeSQL(statement, dialect.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse statement: %w", err)
+	}
+	if toks := significantTokens(tokens); len(toks) == 0 || !containsFold(ddlStatementWords, toks[0].text) {
+		return nil, fmt.Errorf("run_ddl only runs schema changes (%s)", strings.Join(ddlStatementWords, ", "))
+	}
+
+	plan, err := runner.analyze(statement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse statement: %w", err)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Run DDL for Database %s\n\n", targetDbID))
+	response.WriteString(fmt.Sprintf("```sql\n%s\n```\n\n", statement))
+	writeDDLLockPlan(&response, plan)
+
+	blockers, err := findDDLBlockers(ctx, useCase, targetDbID, runner, plan.relations())
+	response.WriteString("\n## Blocking sessions\n\n")
+	longRunning := 0
+	switch {
+	case err != nil:
+		logger.Warn("Could not check for blocking sessions on database %s: %v", targetDbID, err)
+		response.WriteString(fmt.Sprintf("Could not check for blocking sessions: %v\n", err))
+	case len(blockers) == 0:
+		response.WriteString("No other session holds locks on the affected tables.\n")
+	default:
+		response.WriteString("| PID | User | State | Transaction open | Lock | Table | Query |\n")
+		response.WriteString("|-----|------|-------|------------------|------|-------|-------|\n")
+		for _, b := range blockers {
+			if time.Duration(b.XactSeconds)*time.Second >= opts.BlockerThreshold {
+				longRunning++
+			}
+			response.WriteString(fmt.Sprintf("| %d | %s | %s | %ds | %s | %s | %s |\n",
+				b.PID, b.User, b.State, b.XactSeconds, b.Mode, b.Relation, strings.Join(strings.Fields(b.Query), " ")))
+		}
+	}
+
+	execStatements, notes := runner.executionStatements(statement, plan, opts)
+	if opts.DryRun {
+		response.WriteString("\n## Execution\n\n")
+		response.WriteString("Dry run: the statement was not run. It would be executed as:\n\n")
+		response.WriteString(fmt.Sprintf("```sql\n%s;\n```\n", strings.Join(execStatements, ";\n")))
+		for _, note := range notes {
+			response.WriteString(fmt.Sprintf("\n%s\n", note))
+		}
+		return createTextResponse(response.String()), nil
+	}
+	if longRunning > 0 && !opts.Force {
+		response.WriteString("\n## Execution\n\n")
+		response.WriteString(fmt.Sprintf("Not executed: %d session(s) have held locks on the affected tables for at least %s. "+
+			"The statement would wait behind them and block every new query on those tables meanwhile. "+
+			"Wait for them to finish, end them, or set force to run anyway.\n", longRunning, opts.BlockerThreshold))
+		return createTextResponse(response.String()), nil
+	}
+
+	logger.Info("Running DDL on database %s: %s", targetDbID, statement)
+	start := time.Now()
+	attempts, err := retryOnLockContention(ctx, opts.Retries+1, opts.Backoff, func() error {
+		if plan.Concurrent {
+			// Concurrent builds cannot run inside the transaction that scopes SET LOCAL
+			_, err := useCase.ExecuteStatement(ctx, targetDbID, statement, nil)
+			return err
+		}
+		return useCase.ExecuteBatch(ctx, targetDbID, execStatements, nil)
+	})
+	audit(request, auditRecord{
+		Tool:      "run_ddl",
+		Database:  targetDbID,
+		Action:    "ddl",
+		Statement: statement,
+	}, err)
+
+	response.WriteString("\n## Execution\n\n")
+	for _, note := range notes {
+		response.WriteString(note + "\n\n")
+	}
+	for _, attempt := range attempts {
+		response.WriteString(fmt.Sprintf("- %s\n", attempt))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to run DDL after %d attempt(s): %w", len(attempts), err)
+	}
+	logger.Info("DDL on database %s finished in %s after %d attempt(s)", targetDbID, time.Since(start).Round(time.Millisecond), len(attempts))
+	response.WriteString(fmt.Sprintf("\nStatement completed in %s.\n", time.Since(start).Round(time.Millisecond)))
+
+	return createTextResponse(response.String()), nil
+}
+
+// writeDDLLockPlan renders the locks a statement needs
+func writeDDLLockPlan(sb *strings.Builder, plan *ddlLockPlan) {
+	sb.WriteString("## Locks required\n\n")
+	switch {
+	case !plan.Recognized:
+		sb.WriteString(fmt.Sprintf("The locks of this statement could not be determined; assume %s on every table it touches.\n", plan.exclusive()))
+	case len(plan.Locks) == 0:
+		sb.WriteString("No locks on existing tables.\n")
+	default:
+		for _, lock := range plan.Locks {
+			sb.WriteString(fmt.Sprintf("- %s on %s: %s\n", lock.Mode, lock.Relation, plan.effect(lock.Mode)))
+		}
+	}
+	if len(plan.Notes) > 0 {
+		sb.WriteString("\n")
+		for _, note := range plan.Notes {
+			sb.WriteString(fmt.Sprintf("Note: %s\n", note))
+		}
+	}
+}
+
+// ddlRunner is the engine-specific part of running a schema change
+type ddlRunner interface {
+	// analyze works out the locks a single DDL statement takes
+	analyze(statement string) (*ddlLockPlan, error)
+	// executionStatements wraps the statement with the timeout settings of the engine and
+	// explains the settings that could not be applied
+	executionStatements(statement string, plan *ddlLockPlan, opts ddlRunOptions) ([]string, []string)
+	// blockersQuery returns the query for the other sessions holding locks on the relations,
+	// in the columns of ddlBlocker, longest transaction first
+	blockersQuery(relations []string) (string, []interface{})
+}
+
+// postgresDDLRunner runs schema changes under SET LOCAL lock and statement timeouts
+type postgresDDLRunner struct{}
+
+func (postgresDDLRunner) analyze(statement string) (*ddlLockPlan, error) {
+	return analyzeDDLLocks(statement, "postgres")
+}
+
+func (postgresDDLRunner) executionStatements(statement string, plan *ddlLockPlan, opts ddlRunOptions) ([]string, []string) {
+	if plan.Concurrent {
+		return []string{statement}, []string{"The statement uses CONCURRENTLY and cannot run inside a transaction, so lock_timeout and statement_timeout were not applied; it waits for conflicting transactions instead of failing."}
+	}
+	statements := []string{fmt.Sprintf("SET LOCAL lock_timeout = '%dms'", opts.LockTimeout.Milliseconds())}
+	if opts.StatementTimeout > 0 {
+		statements = append(statements, fmt.Sprintf("SET LOCAL statement_timeout = '%dms'", opts.StatementTimeout.Milliseconds()))
+	}
+	return append(statements, statement), nil
+}
+
+func (postgresDDLRunner) blockersQuery(relations []string) (string, []interface{}) {
+	params := newSQLParams("postgres")
+	placeholders := make([]string, len(relations))
+	for i, relation := range relations {
+		placeholders[i] = fmt.Sprintf("to_regclass(%s)", params.add(quoteIdentifier("postgres", relation)))
+	}
+	return fmt.Sprintf(`
+SELECT
+    a.pid,
+    a.usename,
+    a.state,
+    COALESCE(EXTRACT(EPOCH FROM now() - a.xact_start)::bigint, 0) AS xact_seconds,
+    l.mode,
+    l.relation::regclass::text AS relation,
+    left(a.query, 200) AS query
+FROM pg_locks l
+JOIN pg_stat_activity a ON a.pid = l.pid
+WHERE l.relation IN (%s)
+  AND l.granted
+  AND a.pid <> pg_backend_pid()
+ORDER BY xact_seconds DESC`, strings.Join(placeholders, ", ")), params.values
+}
+
+// mysqlDDLRunner runs schema changes under a session lock_wait_timeout
+type mysqlDDLRunner struct{}
+
+func (mysqlDDLRunner) analyze(statement string) (*ddlLockPlan, error) {
+	return analyzeDDLLocks(statement, "mysql")
+}
+
+func (mysqlDDLRunner) executionStatements(statement string, _ *ddlLockPlan, opts ddlRunOptions) ([]string, []string) {
+	var notes []string
+	// lock_wait_timeout only has whole-second precision
+	seconds := int64((opts.LockTimeout + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	if opts.StatementTimeout > 0 {
+		notes = append(notes, "statement_timeout is not supported on MySQL; only the lock wait timeout was applied.")
+	}
+	return []string{
+		fmt.Sprintf("SET SESSION lock_wait_timeout = %d", seconds),
+		statement,
+		"SET SESSION lock_wait_timeout = DEFAULT",
+	}, notes
+}
+
+// blockersQuery reads the metadata locks, which DDL waits for, of tables in the current database
+func (mysqlDDLRunner) blockersQuery(relations []string) (string, []interface{}) {
+	params := newSQLParams("mysql")
+	placeholders := make([]string, len(relations))
+	for i, relation := range relations {
+		_, name := splitQualifiedName(relation)
+		placeholders[i] = params.add(name)
+	}
+	return fmt.Sprintf(`
+SELECT
+    t.PROCESSLIST_ID,
+    t.PROCESSLIST_USER,
+    COALESCE(t.PROCESSLIST_STATE, t.PROCESSLIST_COMMAND),
+    COALESCE(TIMESTAMPDIFF(SECOND, x.trx_started, NOW()), 0) AS xact_seconds,
+    ml.LOCK_TYPE,
+    ml.OBJECT_NAME,
+    LEFT(COALESCE(x.trx_query, t.PROCESSLIST_INFO, ''), 200)
+FROM performance_schema.metadata_locks ml
+JOIN performance_schema.threads t ON t.THREAD_ID = ml.OWNER_THREAD_ID
+LEFT JOIN information_schema.innodb_trx x ON x.trx_mysql_thread_id = t.PROCESSLIST_ID
+WHERE ml.OBJECT_TYPE = 'TABLE'
+  AND ml.OBJECT_SCHEMA = DATABASE()
+  AND ml.OBJECT_NAME IN (%s)
+  AND ml.LOCK_STATUS = 'GRANTED'
+  AND t.PROCESSLIST_ID <> CONNECTION_ID()
+ORDER BY xact_seconds DESC`, strings.Join(placeholders, ", ")), params.values
+}
+
+// findDDLBlockers lists the other sessions holding locks on the relations, longest transaction first
+func findDDLBlockers(ctx context.Context, useCase UseCaseProvider, dbID string, runner ddlRunner, relations []string) ([]ddlBlocker, error) {
+	if len(relations) == 0 {
+		return nil, nil
+	}
+	query, params := runner.blockersQuery(relations)
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, params)
+	if err != nil {
+		return nil, err
+	}
+	blockers := make([]ddlBlocker, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) < 7 {
+			continue
+		}
+		blockers = append(blockers, ddlBlocker{
+			PID:         valueInt64(row[0]),
+			User:        valueString(row[1]),
+			State:       valueString(row[2]),
+			XactSeconds: valueInt64(row[3]),
+			Mode:        valueString(row[4]),
+			Relation:    valueString(row[5]),
+			Query:       valueString(row[6]),
+		})
+	}
+	return blockers, nil
+}
+
+// retryOnLockContention runs the function up to attempts times, retrying only when it fails
+// because it could not get its locks, and returns a log line per attempt
+func retryOnLockContention(ctx context.Context, attempts int, backoff time.Duration, run func() error) ([]string, error) {
+	var log []string
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		err := run()
+		elapsed := time.Since(start).Round(time.Millisecond)
+		if err == nil {
+			log = append(log, fmt.Sprintf("Attempt %d succeeded in %s", attempt, elapsed))
+			return log, nil
+		}
+		if !isLockContentionError(err) || attempt >= attempts {
+			log = append(log, fmt.Sprintf("Attempt %d failed after %s: %v", attempt, elapsed, err))
+			return log, err
+		}
+
+		log = append(log, fmt.Sprintf("Attempt %d gave up waiting for locks after %s, retrying in %s", attempt, elapsed, backoff))
+		logger.Warn("DDL attempt %d hit lock contention, retrying in %s: %v", attempt, backoff, err)
+		select {
+		case <-ctx.Done():
+			return log, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// isLockContentionError reports whether an error means the statement could not get its locks
+func isLockContentionError(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, fragment := range lockContentionErrors {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestAnalyzeDDLLocks(t *testing.T) {
+	tests := []struct {
+		statement string
+		dialect   string
+		locks     []ddlLock
+		notes     int
+	}{
+		{"ALTER TABLE orders ADD COLUMN note TEXT", "postgres", []ddlLock{{"orders", "ACCESS EXCLUSIVE"}}, 0},
+		{"ALTER TABLE orders ADD COLUMN id2 UUID DEFAULT gen_random_uuid()", "postgres", []ddlLock{{"orders", "ACCESS EXCLUSIVE"}}, 1},
+		{"ALTER TABLE public.orders ADD CONSTRAINT orders_user_fk FOREIGN KEY (user_id) REFERENCES users (id) NOT VALID", "postgres",
+			[]ddlLock{{"public.orders", "SHARE ROW EXCLUSIVE"}, {"users", "SHARE ROW EXCLUSIVE"}}, 0},
+		{"ALTER TABLE orders VALIDATE CONSTRAINT orders_user_fk", "postgres", []ddlLock{{"orders", "SHARE UPDATE EXCLUSIVE"}}, 0},
+		{"ALTER TABLE orders ALTER COLUMN total TYPE BIGINT, ALTER COLUMN note SET STATISTICS 500", "postgres", []ddlLock{{"orders", "ACCESS EXCLUSIVE"}}, 1},
+		{"CREATE INDEX CONCURRENTLY idx_orders_user ON orders (user_id)", "postgres", []ddlLock{{"orders", "SHARE UPDATE EXCLUSIVE"}}, 0},
+		{"CREATE UNIQUE INDEX idx_orders_ref ON orders (ref)", "postgres", []ddlLock{{"orders", "SHARE"}}, 1},
+		{"DROP TABLE IF EXISTS a, b CASCADE", "postgres", []ddlLock{{"a", "ACCESS EXCLUSIVE"}, {"b", "ACCESS EXCLUSIVE"}}, 0},
+		{"CREATE TABLE items (id INT, order_id INT REFERENCES orders (id))", "postgres", []ddlLock{{"orders", "SHARE ROW EXCLUSIVE"}}, 1},
+		{"ALTER TABLE `orders` MODIFY total BIGINT", "mysql", []ddlLock{{"orders", "EXCLUSIVE"}}, 2},
+		{"DROP INDEX idx_total ON orders", "mysql", []ddlLock{{"orders", "EXCLUSIVE"}}, 0},
+	}
+	for _, tt := range tests {
+		plan, err := analyzeDDLLocks(tt.statement, tt.dialect)
+		assert.NoError(t, err)
+		assert.True(t, plan.Recognized, tt.statement)
+		assert.Equal(t, tt.locks, plan.Locks, tt.statement)
+		assert.Len(t, plan.Notes, tt.notes, tt.statement)
+	}
+
+	plan, err := analyzeDDLLocks("CREATE INDEX CONCURRENTLY idx ON t (a)", "postgres")
+	assert.NoError(t, err)
+	assert.True(t, plan.Concurrent)
+	plan, err = analyzeDDLLocks("COMMENT ON TABLE t IS 'x'", "postgres")
+	assert.NoError(t, err)
+	assert.False(t, plan.Recognized)
+}
+
+func TestRetryOnLockContention(t *testing.T) {
+	calls := 0
+	log, err := retryOnLockContention(context.Background(), 4, time.Millisecond, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("ERROR: canceling statement due to lock timeout (SQLSTATE 55P03)")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Len(t, log, 3)
+	assert.Contains(t, log[1], "retrying in 2ms")
+
+	calls = 0
+	log, err = retryOnLockContention(context.Background(), 4, time.Millisecond, func() error {
+		calls++
+		return errors.New("column \"x\" does not exist")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Len(t, log, 1)
+
+	calls = 0
+	_, err = retryOnLockContention(context.Background(), 2, time.Millisecond, func() error {
+		calls++
+		return errors.New("Lock wait timeout exceeded; try restarting transaction")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRunDDLToolRunsWithTimeouts(t *testing.T) {
+	useCase := &mockUseCase{dbType: "postgres"}
+
+	result, err := NewRunDDLTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{
+			"database":             "pg1",
+			"statement":            "ALTER TABLE orders ADD COLUMN note TEXT;",
+			"lock_timeout_ms":      float64(1500),
+			"statement_timeout_ms": float64(60000),
+		},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "- ACCESS EXCLUSIVE on orders: blocks all reads and writes\n")
+	assert.Contains(t, text, "No other session holds locks on the affected tables.")
+	assert.Contains(t, text, "- Attempt 1 succeeded")
+	assert.Equal(t, [][]string{{
+		"SET LOCAL lock_timeout = '1500ms'",
+		"SET LOCAL statement_timeout = '60000ms'",
+		"ALTER TABLE orders ADD COLUMN note TEXT",
+	}}, useCase.batches)
+
+	_, err = NewRunDDLTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "statement": "DELETE FROM orders"},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "only runs schema changes")
+}
+
+func TestRunDDLToolStopsOnBlockers(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"performance_schema.metadata_locks": {Rows: [][]interface{}{
+				{int64(42), "app", "Sleep", int64(3600), "SHARED_READ", "orders", "SELECT * FROM orders"},
+			}},
+		},
+	}
+
+	result, err := NewRunDDLTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "statement": "ALTER TABLE orders ADD INDEX idx_total (total)"},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| 42 | app | Sleep | 3600s | SHARED_READ | orders | SELECT * FROM orders |")
+	assert.Contains(t, text, "Not executed: 1 session(s)")
+	assert.Empty(t, useCase.batches)
+
+	result, err = NewRunDDLTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "statement": "ALTER TABLE orders ADD INDEX idx_total (total)", "dry_run": true, "lock_timeout_ms": float64(2500)},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+	text = result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "SET SESSION lock_wait_timeout = 3;\nALTER TABLE orders ADD INDEX idx_total (total);\nSET SESSION lock_wait_timeout = DEFAULT;")
+
+	_, err = NewRunDDLTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "statement": "ALTER TABLE orders ADD INDEX idx_total (total)", "force": true},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+	assert.Len(t, useCase.batches, 1)
+}
@@ -0,0 +1,265 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// tableMaintainer is the engine-specific part of running maintenance on tables
+type tableMaintainer interface {
+	// operations lists the operations with what they lock while they run
+	operations() map[string]string
+	// statement renders the statement running an operation on one table
+	statement(operation, table string, online bool) string
+	// run executes a maintenance statement and summarizes its outcome
+	run(ctx context.Context, useCase UseCaseProvider, dbID, statement string) (string, error)
+	// tableSize returns the bytes a table and its indexes take, failing when the table does
+	// not exist
+	tableSize(ctx context.Context, useCase UseCaseProvider, dbID, table string) (int64, error)
+}
+
+// RunMaintenanceTool handles running VACUUM, ANALYZE, REINDEX and OPTIMIZE on tables
+type RunMaintenanceTool struct {
+	BaseToolType
+}
+
+// NewRunMaintenanceTool creates a new maintenance tool type
+func NewRunMaintenanceTool() *RunMaintenanceTool {
+	return &RunMaintenanceTool{
+		BaseToolType: BaseToolType{
+			name:        "run_maintenance",
+			description: "Run table maintenance: VACUUM, VACUUM ANALYZE, VACUUM FULL, ANALYZE or REINDEX on PostgreSQL, and ANALYZE TABLE or OPTIMIZE TABLE on MySQL, one table at a time, reporting each table's size before and after. Use it to act on the bloat the bloat section of table_stats reports. REINDEX rebuilds every index of a table, concurrently unless online is false; to rebuild only the bloated or invalid indexes, use the reindex tool. Operations that block reads or writes are called out; use dry_run to only see the statements and the current table sizes.",
+		},
+	}
+}
+
+// CreateTool creates a maintenance tool
+func (t *RunMaintenanceTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Run VACUUM, ANALYZE, REINDEX or OPTIMIZE TABLE on specific tables"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("operation",
+			tools.Description("PostgreSQL: vacuum, vacuum_analyze, vacuum_full, analyze or reindex; MySQL: analyze or optimize"),
+			tools.Required(),
+		),
+		tools.WithArray("tables",
+			tools.Description("Tables to maintain, optionally schema-qualified"),
+			tools.Required(),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithBoolean("online",
+			tools.Description("reindex only: rebuild with REINDEX TABLE CONCURRENTLY, without blocking writes (PostgreSQL 12+; default: true)"),
+		),
+		tools.WithBoolean("dry_run",
+			tools.Description("Only show the statements that would run (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles maintenance tool requests
+func (t *RunMaintenanceTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	operation := strings.ToLower(input.requiredString("operation"))
+	tables := input.stringList("tables")
+	online := input.optionalBool("online", true)
+	dryRun := input.optionalBool("dry_run", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("tables parameter must be a non-empty array of table names")
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for run_maintenance: %s", dbType)
+	}
+	maintainer, err := dialect.TableMaintainer()
+	if err != nil {
+		return nil, unsupportedAdminError("run_maintenance", dbType, err)
+	}
+	lockNote, ok := maintainer.operations()[operation]
+	if !ok {
+		return nil, fmt.Errorf("unsupported operation for %s: %s", dialect.Name(), operation)
+	}
+	if operation == "reindex" && online {
+		lockNote = ""
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Maintenance on Database %s\n\n", targetDbID))
+
+	// Look up every table first so a misspelled name fails before anything runs
+	before := make([]int64, len(tables))
+	for i, table := range tables {
+		if before[i], err = maintainer.tableSize(ctx, useCase, targetDbID, table); err != nil {
+			return nil, err
+		}
+	}
+
+	if dryRun {
+		response.WriteString("```sql\n")
+		for _, table := range tables {
+			response.WriteString(maintainer.statement(operation, table, online) + ";\n")
+		}
+		response.WriteString("```\n\n")
+		response.WriteString("| Table | Size |\n")
+		response.WriteString("|-------|------|\n")
+		for i, table := range tables {
+			response.WriteString(fmt.Sprintf("| %s | %s |\n", table, formatBackupSize(before[i])))
+		}
+		response.WriteString("\nDry run: nothing was executed.\n")
+		if lockNote != "" {
+			response.WriteString(fmt.Sprintf("\n## Warnings\n\n- %s\n", lockNote))
+		}
+		return createTextResponse(response.String()), nil
+	}
+
+	response.WriteString("| Table | Statement | Size before | Size after | Result |\n")
+	response.WriteString("|-------|-----------|-------------|------------|--------|\n")
+	for i, table := range tables {
+		statement := maintainer.statement(operation, table, online)
+		logger.Info("run_maintenance: %s on database %s", statement, targetDbID)
+		outcome, err := maintainer.run(ctx, useCase, targetDbID, statement)
+		audit(request, auditRecord{
+			Tool:      "run_maintenance",
+			Database:  targetDbID,
+			Action:    operation,
+			Target:    table,
+			Statement: statement,
+		}, err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to %s %s after completing %d of %d tables: %w", operation, table, i, len(tables), err)
+		}
+		after, err := maintainer.tableSize(ctx, useCase, targetDbID, table)
+		if err != nil {
+			return nil, err
+		}
+		response.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+			table, statement, formatBackupSize(before[i]), formatBackupSize(after), outcome))
+	}
+	if lockNote != "" {
+		response.WriteString(fmt.Sprintf("\n## Warnings\n\n- %s\n", lockNote))
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// postgresMaintainer runs VACUUM, ANALYZE and REINDEX TABLE
+type postgresMaintainer struct{}
+
+func (postgresMaintainer) operations() map[string]string {
+	return map[string]string{
+		"vacuum":         "",
+		"vacuum_analyze": "",
+		"vacuum_full":    "VACUUM FULL rewrites each table under an ACCESS EXCLUSIVE lock, blocking all reads and writes until it finishes, and needs free disk space for a full copy of the table and its indexes.",
+		"analyze":        "",
+		"reindex":        "REINDEX TABLE without online blocks writes to the table, and reads that use its indexes, until it finishes.",
+	}
+}
+
+func (postgresMaintainer) statement(operation, table string, online bool) string {
+	quoted := quoteIdentifier("postgres", table)
+	switch operation {
+	case "vacuum":
+		return "VACUUM " + quoted
+	case "vacuum_analyze":
+		return "VACUUM (ANALYZE) " + quoted
+	case "vacuum_full":
+		return "VACUUM (FULL) " + quoted
+	case "reindex":
+		if online {
+			return "REINDEX TABLE CONCURRENTLY " + quoted
+		}
+		return "REINDEX TABLE " + quoted
+	}
+	return "ANALYZE " + quoted
+}
+
+// run executes the statement on its own; VACUUM and REINDEX CONCURRENTLY cannot run inside a
+// transaction block
+func (postgresMaintainer) run(ctx context.Context, useCase UseCaseProvider, dbID, statement string) (string, error) {
+	if _, err := useCase.ExecuteStatement(ctx, dbID, statement, nil); err != nil {
+		return "", err
+	}
+	return "done", nil
+}
+
+func (postgresMaintainer) tableSize(ctx context.Context, useCase UseCaseProvider, dbID, table string) (int64, error) {
+	return maintenanceTableSize(ctx, useCase, dbID, table,
+		`SELECT pg_total_relation_size(c.oid) FROM pg_class c WHERE c.oid = to_regclass($1) AND c.relkind IN ('r', 'm', 'p')`,
+		[]interface{}{quoteIdentifier("postgres", table)})
+}
+
+// mysqlMaintainer runs ANALYZE TABLE and OPTIMIZE TABLE
+type mysqlMaintainer struct{}
+
+func (mysqlMaintainer) operations() map[string]string {
+	return map[string]string{
+		"analyze":  "",
+		"optimize": "OPTIMIZE TABLE rebuilds InnoDB tables like ALTER TABLE ... FORCE; the rebuild is online, but needs free disk space for a copy of the table.",
+	}
+}
+
+func (mysqlMaintainer) statement(operation, table string, _ bool) string {
+	if operation == "optimize" {
+		return "OPTIMIZE TABLE " + quoteIdentifier("mysql", table)
+	}
+	return "ANALYZE TABLE " + quoteIdentifier("mysql", table)
+}
+
+// run reads the result rows, since ANALYZE and OPTIMIZE TABLE report problems as rows rather
+// than errors
+func (mysqlMaintainer) run(ctx context.Context, useCase UseCaseProvider, dbID, statement string) (string, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, statement, nil)
+	if err != nil {
+		return "", err
+	}
+	var messages []string
+	for _, row := range result.Rows {
+		if len(row) < 4 {
+			continue
+		}
+		if strings.EqualFold(valueString(row[2]), "error") {
+			return "", fmt.Errorf("%s", valueString(row[3]))
+		}
+		messages = append(messages, fmt.Sprintf("%s: %s", valueString(row[2]), valueString(row[3])))
+	}
+	if len(messages) == 0 {
+		return "done", nil
+	}
+	return strings.Join(messages, "; "), nil
+}
+
+func (mysqlMaintainer) tableSize(ctx context.Context, useCase UseCaseProvider, dbID, table string) (int64, error) {
+	schema, name := splitQualifiedName(table)
+	return maintenanceTableSize(ctx, useCase, dbID, table,
+		`SELECT DATA_LENGTH + INDEX_LENGTH FROM information_schema.TABLES WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE()) AND TABLE_NAME = ? AND TABLE_TYPE = 'BASE TABLE'`,
+		[]interface{}{schema, name})
+}
+
+// maintenanceTableSize runs a query for the size of a table, failing when it returns no row
+func maintenanceTableSize(ctx context.Context, useCase UseCaseProvider, dbID, table, query string, params []interface{}) (int64, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up table %s: %w", table, err)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) == 0 {
+		return 0, fmt.Errorf("table %s not found", table)
+	}
+	return valueInt64(result.Rows[0][0]), nil
+}
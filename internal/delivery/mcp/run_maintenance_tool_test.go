@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func runMaintenance(useCase *mockUseCase, params map[string]interface{}) (string, error) {
+	result, err := NewRunMaintenanceTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	if err != nil {
+		return "", err
+	}
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string), nil
+}
+
+func TestRunMaintenanceVacuumsPostgresTables(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType:  "postgres",
+		results: map[string]*domain.QueryResult{"pg_total_relation_size": {Rows: [][]interface{}{{int64(3 * 1024 * 1024)}}}},
+	}
+	text, err := runMaintenance(useCase, map[string]interface{}{
+		"database": "pg1", "operation": "vacuum_full", "tables": []interface{}{"public.events", "Audit"}, "dry_run": true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, useCase.queries, 2)
+	assert.Contains(t, text, "```sql\nVACUUM (FULL) \"public\".\"events\";\nVACUUM (FULL) \"Audit\";\n```")
+	assert.Contains(t, text, "| public.events | 3.0 MiB |")
+	assert.Contains(t, text, "Dry run: nothing was executed.")
+	assert.Contains(t, text, "- VACUUM FULL rewrites each table under an ACCESS EXCLUSIVE lock")
+
+	text, err = runMaintenance(useCase, map[string]interface{}{"database": "pg1", "operation": "reindex", "tables": []interface{}{"events"}})
+	assert.NoError(t, err)
+	assert.Contains(t, useCase.queries, "REINDEX TABLE CONCURRENTLY \"events\"")
+	assert.Contains(t, text, "| events | REINDEX TABLE CONCURRENTLY \"events\" | 3.0 MiB | 3.0 MiB | done |")
+	assert.NotContains(t, text, "## Warnings")
+
+	_, err = runMaintenance(&mockUseCase{dbType: "postgres"}, map[string]interface{}{"database": "pg1", "operation": "vacuum", "tables": []interface{}{"missing"}})
+	assert.ErrorContains(t, err, "table missing not found")
+
+	_, err = runMaintenance(useCase, map[string]interface{}{"database": "pg1", "operation": "optimize", "tables": []interface{}{"events"}})
+	assert.ErrorContains(t, err, "unsupported operation for postgres: optimize")
+}
+
+func TestRunMaintenanceOptimizesMySQLTables(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"information_schema.TABLES": {Rows: [][]interface{}{{int64(2048)}}},
+			"OPTIMIZE TABLE `orders`": {Rows: [][]interface{}{
+				{"shop.orders", "optimize", "note", "Table does not support optimize, doing recreate + analyze instead"},
+				{"shop.orders", "optimize", "status", "OK"},
+			}},
+			"OPTIMIZE TABLE `broken`": {Rows: [][]interface{}{{"shop.broken", "optimize", "Error", "Table 'shop.broken' is marked as crashed"}}},
+		},
+	}
+	text, err := runMaintenance(useCase, map[string]interface{}{"database": "mysql1", "operation": "optimize", "tables": []interface{}{"orders"}})
+	assert.NoError(t, err)
+	assert.Contains(t, text, "| orders | OPTIMIZE TABLE `orders` | 2.0 KiB | 2.0 KiB | note: Table does not support optimize, doing recreate + analyze instead; status: OK |")
+	assert.Contains(t, text, "- OPTIMIZE TABLE rebuilds InnoDB tables")
+
+	_, err = runMaintenance(useCase, map[string]interface{}{"database": "mysql1", "operation": "optimize", "tables": []interface{}{"orders", "broken"}})
+	assert.ErrorContains(t, err, "failed to optimize broken after completing 1 of 2 tables: Table 'shop.broken' is marked as crashed")
+}
@@ -0,0 +1,173 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// ScanKeysTool handles finding the keys of a key-value database that match a pattern
+type ScanKeysTool struct {
+	BaseToolType
+}
+
+// NewScanKeysTool creates a new scan keys tool type
+func NewScanKeysTool() *ScanKeysTool {
+	return &ScanKeysTool{
+		BaseToolType: BaseToolType{
+			name:        "scan_keys",
+			description: "Find the keys of a Redis database that match a glob pattern, such as user:* or session:?????, optionally only keys of one type, and show the type, time to live and memory usage of each. Keys are found with SCAN, which does not block the server the way KEYS does.",
+		},
+	}
+}
+
+// CreateTool creates a scan keys tool
+func (t *ScanKeysTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Find Redis keys matching a pattern with their type, TTL and memory usage"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("pattern",
+			tools.Description("Glob pattern of the keys, such as user:* (default: *)"),
+		),
+		tools.WithString("type",
+			tools.Description("Only keys of this type: string, list, set, zset, hash or stream (optional)"),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of keys to return (default: 100, max: 1000)"),
+		),
+	)
+}
+
+// scanBatch is the COUNT hint of every SCAN call
+const scanBatch = "1000"
+
+// HandleRequest handles scan keys tool requests
+func (t *ScanKeysTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	pattern := input.optionalString("pattern", "*")
+	keyType := input.choice("type", "", "string", "list", "set", "zset", "hash", "stream")
+	limit := input.intBetween("limit", 100, 1, 1000)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	store, err := useCase.GetKeyValueStore(targetDbID)
+	if err != nil {
+		return nil, err
+	}
+
+	// One key more than the limit tells whether the result was cut
+	keys, err := scanKeys(ctx, store, pattern, keyType, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	truncated := len(keys) > limit
+	if truncated {
+		keys = keys[:limit]
+	}
+	logger.Info("Found %d keys matching %s in database %s", len(keys), pattern, targetDbID)
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Keys Matching %s in Database %s\n\n", pattern, targetDbID))
+	if len(keys) == 0 {
+		response.WriteString("No keys match the pattern.\n")
+		return createTextResponse(response.String()), nil
+	}
+	response.WriteString("| Key | Type | TTL | Memory |\n")
+	response.WriteString("|-----|------|-----|--------|\n")
+	for _, key := range keys {
+		info, err := describeKey(ctx, store, key, keyType)
+		if err != nil {
+			return nil, err
+		}
+		response.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", redisCell(key), info.keyType, info.ttl, info.memory))
+	}
+	if truncated {
+		response.WriteString(fmt.Sprintf("\nShowing the first %d keys; narrow the pattern or raise limit to see more.\n", limit))
+	} else {
+		response.WriteString(fmt.Sprintf("\n%d keys\n", len(keys)))
+	}
+	return createTextResponse(response.String()), nil
+}
+
+// scanKeys iterates SCAN until it has found limit keys or the whole key space was visited.
+// SCAN may return a key more than once, so the keys are deduplicated; they come back sorted.
+func scanKeys(ctx context.Context, store domain.KeyValueStore, pattern, keyType string, limit int) ([]string, error) {
+	seen := map[string]bool{}
+	cursor := "0"
+	for {
+		args := []string{"SCAN", cursor, "MATCH", pattern, "COUNT", scanBatch}
+		if keyType != "" {
+			args = append(args, "TYPE", keyType)
+		}
+		reply, err := store.Command(ctx, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan keys: %w", err)
+		}
+		page, ok := reply.([]interface{})
+		if !ok || len(page) != 2 {
+			return nil, fmt.Errorf("unexpected SCAN reply: %v", reply)
+		}
+		batch, _ := page[1].([]interface{})
+		for _, key := range batch {
+			seen[redisText(key)] = true
+		}
+		cursor = redisText(page[0])
+		if cursor == "0" || len(seen) >= limit {
+			break
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+	return keys, nil
+}
+
+// keyInfo is what scan_keys shows of a key
+type keyInfo struct {
+	keyType string
+	ttl     string
+	memory  string
+}
+
+// describeKey reads the type, TTL and memory usage of a key; keyType is used when the type
+// is already known. MEMORY USAGE may be denied by ACLs, which leaves the memory empty.
+func describeKey(ctx context.Context, store domain.KeyValueStore, key, keyType string) (keyInfo, error) {
+	info := keyInfo{keyType: keyType}
+	if info.keyType == "" {
+		reply, err := store.Command(ctx, "TYPE", key)
+		if err != nil {
+			return keyInfo{}, fmt.Errorf("failed to read the type of %s: %w", key, err)
+		}
+		info.keyType = redisText(reply)
+	}
+	reply, err := store.Command(ctx, "TTL", key)
+	if err != nil {
+		return keyInfo{}, fmt.Errorf("failed to read the TTL of %s: %w", key, err)
+	}
+	ttl, _ := redisInt(reply)
+	info.ttl = formatTTL(ttl)
+	if reply, err := store.Command(ctx, "MEMORY", "USAGE", key); err == nil {
+		if bytes, ok := redisInt(reply); ok {
+			info.memory = formatBackupSize(bytes)
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,135 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultSchemaCacheTTL applies when a connection does not configure schema_cache_ttl
+const defaultSchemaCacheTTL = 5 * time.Minute
+
+// schemaCacheNow is the clock used for cache expiry; tests replace it
+var schemaCacheNow = time.Now
+
+// schemaCacheKey identifies cached metadata. The use case is part of the key so separate
+// providers, such as the ones tests create, never share entries.
+type schemaCacheKey struct {
+	useCase UseCaseProvider
+	dbID    string
+	schema  string
+}
+
+// schemaCacheEntry is the metadata of one schema and when it was read
+type schemaCacheEntry struct {
+	meta     *schemaMetadata
+	loadedAt time.Time
+}
+
+// schemaCatalog caches schema metadata per database and schema, so metadata tools do not
+// query the catalog on every call. Cached metadata is shared and must not be modified.
+type schemaCatalog struct {
+	mu      sync.Mutex
+	entries map[schemaCacheKey]schemaCacheEntry
+}
+
+// schemaCache is the catalog shared by all tools
+var schemaCache = &schemaCatalog{entries: make(map[schemaCacheKey]schemaCacheEntry)}
+
+// load returns the cached metadata of a schema, reading it again once the TTL of the
+// connection has passed
+func (c *schemaCatalog) load(ctx context.Context, useCase UseCaseProvider, dbID, schema string) (*schemaMetadata, error) {
+	ttl := schemaCacheTTL(useCase, dbID)
+	key := schemaCacheKey{useCase: sharedUseCase(useCase), dbID: dbID, schema: schema}
+	if ttl > 0 {
+		c.mu.Lock()
+		entry, ok := c.entries[key]
+		c.mu.Unlock()
+		if ok && schemaCacheNow().Sub(entry.loadedAt) < ttl {
+			return entry.meta, nil
+		}
+	}
+
+	meta, err := loadSchemaMetadata(ctx, useCase, dbID, schema)
+	if err != nil {
+		return nil, err
+	}
+	if ttl > 0 {
+		c.mu.Lock()
+		c.entries[key] = schemaCacheEntry{meta: meta, loadedAt: schemaCacheNow()}
+		c.mu.Unlock()
+	}
+	return meta, nil
+}
+
+// warm reloads the metadata of a schema once half of its TTL has passed, so tools keep
+// finding it cached; it reports whether the database was queried
+func (c *schemaCatalog) warm(ctx context.Context, useCase UseCaseProvider, dbID, schema string) (bool, error) {
+	ttl := schemaCacheTTL(useCase, dbID)
+	if ttl <= 0 {
+		return false, nil
+	}
+	key := schemaCacheKey{useCase: sharedUseCase(useCase), dbID: dbID, schema: schema}
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && schemaCacheNow().Sub(entry.loadedAt) < ttl/2 {
+		return false, nil
+	}
+
+	meta, err := loadSchemaMetadata(ctx, useCase, dbID, schema)
+	if err != nil {
+		return true, err
+	}
+	c.mu.Lock()
+	c.entries[key] = schemaCacheEntry{meta: meta, loadedAt: schemaCacheNow()}
+	c.mu.Unlock()
+	return true, nil
+}
+
+// invalidate drops every cached schema of a database, typically after a schema change
+func (c *schemaCatalog) invalidate(useCase UseCaseProvider, dbID string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dropped := 0
+	for key := range c.entries {
+		if key.useCase == sharedUseCase(useCase) && key.dbID == dbID {
+			delete(c.entries, key)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// invalidateChange drops the cached schemas of a database that a schema change touched
+func (c *schemaCatalog) invalidateChange(useCase UseCaseProvider, dbID string, change *schemaChange) int {
+	if change == nil {
+		return 0
+	}
+	if change.all {
+		return c.invalidate(useCase, dbID)
+	}
+	defaultSchema := defaultSchemaName(useCase, dbID)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dropped := 0
+	for key := range c.entries {
+		if key.useCase == sharedUseCase(useCase) && key.dbID == dbID && change.touches(key.schema, defaultSchema) {
+			delete(c.entries, key)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// schemaCacheTTL returns how long metadata of a connection stays cached; zero disables caching
+func schemaCacheTTL(useCase UseCaseProvider, dbID string) time.Duration {
+	config, err := useCase.GetDatabaseConfig(dbID)
+	if err != nil || config == nil || config.SchemaCacheTTL == 0 {
+		return defaultSchemaCacheTTL
+	}
+	if config.SchemaCacheTTL < 0 {
+		return 0
+	}
+	return config.SchemaCacheTTL
+}
@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+// newSchemaCacheUseCase returns a MySQL use case serving a two-table schema
+func newSchemaCacheUseCase() *mockUseCase {
+	return &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"information_schema.columns": {Rows: [][]interface{}{
+				{"app", "users", "id", "int", "NO", "", "", nil},
+				{"app", "orders", "id", "int", "NO", "", "", nil},
+				{"app", "orders", "user_id", "int", "YES", "", "", nil},
+			}},
+			"information_schema.table_constraints": {Rows: [][]interface{}{
+				{"fk_orders_users", "FOREIGN KEY", "app", "orders", "user_id", "app", "users", "id"},
+				{"PRIMARY", "PRIMARY KEY", "app", "users", "id", nil, nil, nil},
+			}},
+			"information_schema.statistics": {Rows: [][]interface{}{
+				{"app", "orders", "idx_user", "0", "0", "user_id"},
+			}},
+		},
+	}
+}
+
+func TestSchemaCacheReusesMetadata(t *testing.T) {
+	useCase := newSchemaCacheUseCase()
+
+	first, err := schemaCache.load(context.Background(), useCase, "test", "app")
+	assert.NoError(t, err)
+	loaded := len(useCase.queries)
+	assert.NotZero(t, loaded)
+
+	second, err := schemaCache.load(context.Background(), useCase, "test", "app")
+	assert.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Len(t, useCase.queries, loaded)
+
+	// Another schema is cached separately
+	_, err = schemaCache.load(context.Background(), useCase, "test", "other")
+	assert.NoError(t, err)
+	assert.Len(t, useCase.queries, 2*loaded)
+
+	assert.Equal(t, 2, schemaCache.invalidate(useCase, "test"))
+	_, err = schemaCache.load(context.Background(), useCase, "test", "app")
+	assert.NoError(t, err)
+	assert.Len(t, useCase.queries, 3*loaded)
+	schemaCache.invalidate(useCase, "test")
+}
+
+func TestSchemaCacheExpires(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	schemaCacheNow = func() time.Time { return now }
+	t.Cleanup(func() { schemaCacheNow = time.Now })
+
+	useCase := newSchemaCacheUseCase()
+	useCase.config.SchemaCacheTTL = time.Minute
+	t.Cleanup(func() { schemaCache.invalidate(useCase, "test") })
+
+	_, err := schemaCache.load(context.Background(), useCase, "test", "app")
+	assert.NoError(t, err)
+	loaded := len(useCase.queries)
+
+	now = now.Add(59 * time.Second)
+	_, err = schemaCache.load(context.Background(), useCase, "test", "app")
+	assert.NoError(t, err)
+	assert.Len(t, useCase.queries, loaded)
+
+	now = now.Add(time.Second)
+	_, err = schemaCache.load(context.Background(), useCase, "test", "app")
+	assert.NoError(t, err)
+	assert.Len(t, useCase.queries, 2*loaded)
+}
+
+func TestSchemaCacheDisabled(t *testing.T) {
+	useCase := newSchemaCacheUseCase()
+	useCase.config.SchemaCacheTTL = -time.Second
+	assert.Zero(t, schemaCacheTTL(useCase, "test"))
+
+	_, err := schemaCache.load(context.Background(), useCase, "test", "app")
+	assert.NoError(t, err)
+	loaded := len(useCase.queries)
+	_, err = schemaCache.load(context.Background(), useCase, "test", "app")
+	assert.NoError(t, err)
+	assert.Len(t, useCase.queries, 2*loaded)
+	assert.Zero(t, schemaCache.invalidate(useCase, "test"))
+}
@@ -0,0 +1,320 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+// schemaChange records which schemas a script changed, so only their cached metadata is dropped
+type schemaChange struct {
+	// schemas holds the lower-case names of the changed schemas; "" stands for the default
+	// schema that unqualified names resolve to
+	schemas map[string]bool
+	// all is set when a statement changed the schema in a way that cannot be narrowed down
+	all bool
+}
+
+// touches reports whether the change affects a schema
+func (c *schemaChange) touches(schema, defaultSchema string) bool {
+	if c.all {
+		return true
+	}
+	if schema == "" {
+		schema = defaultSchema
+	}
+	for changed := range c.schemas {
+		if changed == "" {
+			changed = defaultSchema
+		}
+		if strings.EqualFold(changed, schema) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaNeutralObjects are object kinds whose DDL leaves tables, columns, keys and indexes alone
+var schemaNeutralObjects = map[string]bool{
+	"FUNCTION": true, "PROCEDURE": true, "ROUTINE": true, "AGGREGATE": true, "OPERATOR": true,
+	"CAST": true, "LANGUAGE": true, "COLLATION": true, "CONVERSION": true, "TEXT": true,
+	"ROLE": true, "USER": true, "GROUP": true, "POLICY": true, "PUBLICATION": true,
+	"SUBSCRIPTION": true, "SERVER": true, "TABLESPACE": true, "STATISTICS": true, "EVENT": true,
+	"DEFAULT": true, "SYSTEM": true, "INSTANCE": true, "LOGFILE": true, "RESOURCE": true,
+}
+
+// schemaObjectModifiers precede the object kind in CREATE statements
+var schemaObjectModifiers = []string{
+	"GLOBAL", "LOCAL", "TEMPORARY", "TEMP", "UNLOGGED", "UNIQUE", "FULLTEXT", "SPATIAL",
+	"MATERIALIZED", "RECURSIVE", "FOREIGN",
+}
+
+// detectSchemaChange works out which schemas the DDL of a script changes from its text
+// alone; it returns nil when the script holds no DDL
+func detectSchemaChange(script, dialect string) *schemaChange {
+	tokens, err := tokenizeSQL(script, dialect)
+	if err != nil {
+		// An unterminated literal or comment; the database rejects it or runs something unknown
+		if containsDDLKeyword(script) {
+			return &schemaChange{all: true}
+		}
+		return nil
+	}
+	change := &schemaChange{schemas: make(map[string]bool)}
+	var statement []sqlToken
+	for _, tok := range tokens {
+		switch {
+		case tok.kind == sqlSpace || tok.kind == sqlComment:
+		case tok.is(";"):
+			change.addStatement(statement, dialect)
+			statement = nil
+		default:
+			statement = append(statement, tok)
+		}
+	}
+	change.addStatement(statement, dialect)
+	if !change.all && len(change.schemas) == 0 {
+		return nil
+	}
+	return change
+}
+
+// containsDDLKeyword reports whether a script mentions a DDL keyword anywhere
+func containsDDLKeyword(script string) bool {
+	upper := strings.ToUpper(script)
+	for _, keyword := range []string{"CREATE", "ALTER", "DROP", "RENAME", "COMMENT"} {
+		if strings.Contains(upper, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// addStatement records the schemas a single statement, given as its significant tokens, changes
+func (c *schemaChange) addStatement(toks []sqlToken, dialect string) {
+	p := &ddlParser{toks: toks}
+	switch {
+	case p.accept("CREATE"):
+		p.accept("OR", "REPLACE")
+		for _, modifier := range schemaObjectModifiers {
+			p.accept(modifier)
+		}
+		c.addObject(p, dialect, "CREATE")
+	case p.accept("ALTER"):
+		c.addObject(p, dialect, "ALTER")
+	case p.accept("DROP"):
+		p.accept("TEMPORARY")
+		p.accept("MATERIALIZED")
+		p.accept("FOREIGN")
+		c.addObject(p, dialect, "DROP")
+	case p.accept("RENAME"):
+		// MySQL RENAME TABLE a TO b, c TO d
+		if !p.accept("TABLE") && !p.accept("TABLES") {
+			c.all = true
+			return
+		}
+		for {
+			if !c.addName(p) || !p.accept("TO") || !c.addName(p) || !p.accept(",") {
+				return
+			}
+		}
+	case p.accept("COMMENT", "ON"):
+		column := p.accept("COLUMN")
+		if !column && !p.accept("TABLE") && !p.accept("VIEW") && !p.accept("INDEX") {
+			return
+		}
+		parts := dottedName(p)
+		schemaIndex := len(parts) - 2
+		if column {
+			schemaIndex--
+		}
+		if schemaIndex >= 0 {
+			c.add(parts[schemaIndex])
+		} else {
+			c.add("")
+		}
+	case p.accept("DO"):
+		// An anonymous PostgreSQL block may run any DDL
+		c.all = true
+	}
+}
+
+// addObject records the schemas of the object a CREATE, ALTER or DROP statement names
+func (c *schemaChange) addObject(p *ddlParser, dialect, verb string) {
+	if p.done() {
+		return
+	}
+	kind := strings.ToUpper(p.toks[p.i].text)
+	if schemaNeutralObjects[kind] {
+		return
+	}
+	p.i++
+	switch kind {
+	case "SCHEMA", "DATABASE":
+		if kind == "DATABASE" && dialect != "mysql" {
+			// Another PostgreSQL database has its own connection and cache
+			return
+		}
+		p.accept("IF", "NOT", "EXISTS")
+		p.accept("IF", "EXISTS")
+		if _, name, ok := p.name(); ok {
+			c.add(name)
+			return
+		}
+		c.all = true
+	case "INDEX":
+		p.accept("CONCURRENTLY")
+		p.accept("IF", "NOT", "EXISTS")
+		p.accept("IF", "EXISTS")
+		if verb == "CREATE" {
+			// The index lives in the schema of its table
+			if !p.peek("ON") {
+				p.name()
+			}
+			if !p.accept("ON") {
+				c.all = true
+				return
+			}
+			p.accept("ONLY")
+		}
+		c.addNameList(p)
+		if p.accept("ON") {
+			// MySQL names the table of the dropped index
+			c.addName(p)
+		}
+	case "TRIGGER":
+		for !p.done() && !p.accept("ON") {
+			p.i++
+		}
+		if !c.addName(p) {
+			c.all = true
+		}
+	case "TABLE", "VIEW", "SEQUENCE", "TYPE", "DOMAIN":
+		p.accept("IF", "NOT", "EXISTS")
+		p.accept("IF", "EXISTS")
+		p.accept("ONLY")
+		if verb == "DROP" {
+			c.addNameList(p)
+			return
+		}
+		if !c.addName(p) {
+			c.all = true
+			return
+		}
+		// Objects may move to another schema
+		for !p.done() {
+			switch {
+			case p.accept("SET", "SCHEMA"):
+				if _, schema, ok := p.name(); ok {
+					c.add(schema)
+				}
+			case p.accept("RENAME", "TO"), p.accept("RENAME", "AS"):
+				c.addName(p)
+			default:
+				p.i++
+			}
+		}
+	default:
+		// Anything else, such as CREATE EXTENSION or DROP OWNED, may add or remove tables
+		c.all = true
+	}
+}
+
+// addName consumes an object name and records its schema
+func (c *schemaChange) addName(p *ddlParser) bool {
+	schema, _, ok := p.name()
+	if ok {
+		c.add(schema)
+	}
+	return ok
+}
+
+// addNameList consumes a comma-separated list of object names and records their schemas
+func (c *schemaChange) addNameList(p *ddlParser) {
+	if !c.addName(p) {
+		c.all = true
+		return
+	}
+	for p.accept(",") {
+		if !c.addName(p) {
+			return
+		}
+	}
+}
+
+// add records a changed schema
+func (c *schemaChange) add(schema string) {
+	c.schemas[strings.ToLower(schema)] = true
+}
+
+// dottedName consumes a name of any number of dot-separated parts
+func dottedName(p *ddlParser) []string {
+	var parts []string
+	for !p.done() && (p.toks[p.i].kind == sqlWord || p.toks[p.i].kind == sqlQuotedIdent) {
+		parts = append(parts, p.toks[p.i].text)
+		p.i++
+		if !p.accept(".") {
+			break
+		}
+	}
+	return parts
+}
+
+// defaultSchemaName returns the schema unqualified names resolve to: the default schema of the
+// dialect, such as public on PostgreSQL, or else the connected database, as on MySQL
+func defaultSchemaName(useCase UseCaseProvider, dbID string) string {
+	if dbType, err := useCase.GetDatabaseType(dbID); err == nil {
+		if dialect, ok := lookupDialect(dbType); ok {
+			if schema, _ := dialect.SchemaMetadataQueries(""); schema != "" {
+				return schema
+			}
+		}
+	}
+	if config, err := useCase.GetDatabaseConfig(dbID); err == nil && config != nil {
+		return config.Name
+	}
+	return ""
+}
+
+// schemaWatcher drops the cached metadata that DDL run through it makes stale, so every tool,
+// including sql and execute, sees a schema change right away instead of after the cache TTL
+type schemaWatcher struct {
+	UseCaseProvider
+}
+
+// watchSchemaChanges wraps a use case so the DDL it runs invalidates the schema cache
+func watchSchemaChanges(useCase UseCaseProvider) *schemaWatcher {
+	return &schemaWatcher{UseCaseProvider: useCase}
+}
+
+// changed invalidates the cached schemas a script touched. Failed scripts count as well,
+// since MySQL commits every DDL statement of a script on its own.
+func (w *schemaWatcher) changed(dbID string, scripts ...string) {
+	dbType, err := w.UseCaseProvider.GetDatabaseType(dbID)
+	if err != nil {
+		return
+	}
+	dialect := dialectFor(dbType).Name()
+	for _, script := range scripts {
+		schemaCache.invalidateChange(w.UseCaseProvider, dbID, detectSchemaChange(script, dialect))
+	}
+}
+
+func (w *schemaWatcher) ExecuteQuery(ctx context.Context, dbID, query string, params []interface{}) (*domain.QueryResult, error) {
+	result, err := w.UseCaseProvider.ExecuteQuery(ctx, dbID, query, params)
+	w.changed(dbID, query)
+	return result, err
+}
+
+func (w *schemaWatcher) ExecuteStatement(ctx context.Context, dbID, statement string, params []interface{}) (string, error) {
+	result, err := w.UseCaseProvider.ExecuteStatement(ctx, dbID, statement, params)
+	w.changed(dbID, statement)
+	return result, err
+}
+
+func (w *schemaWatcher) ExecuteBatch(ctx context.Context, dbID string, statements []string, params [][]interface{}) error {
+	err := w.UseCaseProvider.ExecuteBatch(ctx, dbID, statements, params)
+	w.changed(dbID, statements...)
+	return err
+}
@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectSchemaChange(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		script  string
+		schemas []string
+		all     bool
+		none    bool
+	}{
+		{name: "select", dialect: "postgres", script: "SELECT * FROM orders", none: true},
+		{name: "dml", dialect: "postgres", script: "INSERT INTO orders VALUES (1); UPDATE orders SET total = 0", none: true},
+		{name: "alter table", dialect: "postgres", script: "ALTER TABLE sales.orders ADD COLUMN total numeric", schemas: []string{"sales"}},
+		{name: "unqualified index", dialect: "postgres", script: "CREATE UNIQUE INDEX idx ON orders (id)", schemas: []string{""}},
+		{name: "concurrent index", dialect: "postgres", script: "CREATE INDEX CONCURRENTLY IF NOT EXISTS idx ON sales.orders (id)", schemas: []string{"sales"}},
+		{name: "drop tables", dialect: "postgres", script: `DROP TABLE IF EXISTS a.t1, "B".t2 CASCADE`, schemas: []string{"a", "b"}},
+		{name: "move table", dialect: "postgres", script: "ALTER TABLE a.t SET SCHEMA b", schemas: []string{"a", "b"}},
+		{name: "comment on column", dialect: "postgres", script: "COMMENT ON COLUMN sales.orders.total IS 'Sum; of lines'", schemas: []string{"sales"}},
+		{name: "comment on unqualified column", dialect: "postgres", script: "COMMENT ON COLUMN orders.total IS 'x'", schemas: []string{""}},
+		{name: "drop schema", dialect: "postgres", script: "DROP SCHEMA sales CASCADE", schemas: []string{"sales"}},
+		{name: "script", dialect: "postgres", script: "UPDATE t SET a = 1; DROP MATERIALIZED VIEW reports.daily", schemas: []string{"reports"}},
+		{name: "function", dialect: "postgres", script: "CREATE FUNCTION f() RETURNS int AS $$ BEGIN DROP TABLE x; RETURN 1; END $$ LANGUAGE plpgsql", none: true},
+		{name: "user", dialect: "postgres", script: "CREATE USER reporter WITH PASSWORD 'secret'", none: true},
+		{name: "other postgres database", dialect: "postgres", script: "CREATE DATABASE other", none: true},
+		{name: "extension", dialect: "postgres", script: "CREATE EXTENSION postgis", all: true},
+		{name: "anonymous block", dialect: "postgres", script: "DO $$ BEGIN EXECUTE 'DROP TABLE x'; END $$", all: true},
+		{name: "mysql database", dialect: "mysql", script: "CREATE DATABASE IF NOT EXISTS archive", schemas: []string{"archive"}},
+		{name: "mysql rename", dialect: "mysql", script: "RENAME TABLE app.orders TO archive.orders, t1 TO t2", schemas: []string{"", "app", "archive"}},
+		{name: "mysql drop index", dialect: "mysql", script: "DROP INDEX idx ON app.orders", schemas: []string{"", "app"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := detectSchemaChange(tt.script, tt.dialect)
+			if tt.none {
+				assert.Nil(t, change)
+				return
+			}
+			assert.NotNil(t, change)
+			assert.Equal(t, tt.all, change.all)
+			if tt.all {
+				return
+			}
+			var schemas []string
+			for schema := range change.schemas {
+				schemas = append(schemas, schema)
+			}
+			sort.Strings(schemas)
+			assert.Equal(t, tt.schemas, schemas)
+		})
+	}
+}
+
+// cachedSchemas returns the schemas of a database the schema cache holds for a use case
+func cachedSchemas(useCase UseCaseProvider, dbID string) []string {
+	schemaCache.mu.Lock()
+	defer schemaCache.mu.Unlock()
+	var schemas []string
+	for key := range schemaCache.entries {
+		if key.useCase == sharedUseCase(useCase) && key.dbID == dbID {
+			schemas = append(schemas, key.schema)
+		}
+	}
+	sort.Strings(schemas)
+	return schemas
+}
+
+func TestSchemaWatcherInvalidatesChangedSchemas(t *testing.T) {
+	useCase := newSchemaCacheUseCase()
+	useCase.config.Name = "app"
+	t.Cleanup(func() { schemaCache.invalidate(useCase, "test") })
+	for _, schema := range []string{"", "app", "other"} {
+		_, err := schemaCache.load(context.Background(), useCase, "test", schema)
+		assert.NoError(t, err)
+	}
+
+	watched := sessionBudgets.bind(watchSchemaChanges(useCase), server.ToolCallRequest{})
+	_, err := watched.ExecuteQuery(context.Background(), "test", "SELECT * FROM other.orders", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "app", "other"}, cachedSchemas(useCase, "test"))
+
+	_, err = watched.ExecuteStatement(context.Background(), "test", "ALTER TABLE other.orders ADD COLUMN note text", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "app"}, cachedSchemas(useCase, "test"))
+
+	// Unqualified names resolve to the connected database on MySQL
+	assert.NoError(t, watched.ExecuteBatch(context.Background(), "test", []string{"CREATE INDEX idx ON orders (user_id)"}, nil))
+	assert.Empty(t, cachedSchemas(useCase, "test"))
+}
+
+func TestSchemaChangeNotifications(t *testing.T) {
+	useCase := newSchemaCacheUseCase()
+	useCase.notifications = []string{"other"}
+	t.Cleanup(func() { schemaCache.invalidate(useCase, "test") })
+	for _, schema := range []string{"app", "other"} {
+		_, err := schemaCache.load(context.Background(), useCase, "test", schema)
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, listenForSchemaChanges(context.Background(), useCase, "test", schemaChangeTriggerStatements()))
+	assert.Equal(t, [][]string{schemaChangeTriggerStatements()}, useCase.batches)
+	assert.Equal(t, []string{"app"}, cachedSchemas(useCase, "test"))
+
+	// An empty payload means notifications may have been lost
+	useCase.notifications = []string{""}
+	assert.NoError(t, listenForSchemaChanges(context.Background(), useCase, "test", schemaChangeTriggerStatements()))
+	assert.Empty(t, cachedSchemas(useCase, "test"))
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+const (
+	// schemaCrawlDelay is how long after startup the crawler begins, so it does not compete
+	// with the server coming up
+	schemaCrawlDelay = 10 * time.Second
+	// schemaCrawlPause separates two metadata loads, so the crawler never runs more than one
+	// catalog query at a time and leaves room for tool calls
+	schemaCrawlPause = 2 * time.Second
+	// schemaCrawlInterval separates two passes over the databases
+	schemaCrawlInterval = 30 * time.Second
+)
+
+// StartSchemaCrawler keeps the schema cache warm in the background for the databases that set
+// warm_schema_cache, until ctx is cancelled. The default schema of each database is loaded
+// shortly after startup and reloaded once half of its cache TTL has passed, so tools rarely
+// wait for cold catalog queries.
+func StartSchemaCrawler(ctx context.Context, useCase UseCaseProvider) {
+	go crawlSchemas(ctx, useCase, schemaCrawlDelay, schemaCrawlPause, schemaCrawlInterval)
+}
+
+// crawlSchemas runs crawl passes until ctx is cancelled
+func crawlSchemas(ctx context.Context, useCase UseCaseProvider, delay, pause, interval time.Duration) {
+	wait := delay
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		if !crawlPass(ctx, useCase, pause) {
+			return
+		}
+		wait = interval
+	}
+}
+
+// crawlPass warms the default schema of every database that opted in, pausing after each
+// load; it returns false once ctx is cancelled
+func crawlPass(ctx context.Context, useCase UseCaseProvider, pause time.Duration) bool {
+	for _, dbID := range useCase.ListDatabases() {
+		config, err := useCase.GetDatabaseConfig(dbID)
+		if err != nil || config == nil || !config.WarmSchemaCache {
+			continue
+		}
+
+		loaded, err := schemaCache.warm(ctx, useCase, dbID, "")
+		if err != nil {
+			logger.Warn("Schema crawler could not load the metadata of database %s: %v", dbID, err)
+		} else if loaded {
+			logger.Debug("Schema crawler refreshed the metadata of database %s", dbID)
+		}
+		if !loaded {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(pause):
+		}
+	}
+	return ctx.Err() == nil
+}
@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrawlPassWarmsOptedInDatabases(t *testing.T) {
+	now := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+	schemaCacheNow = func() time.Time { return now }
+	t.Cleanup(func() { schemaCacheNow = time.Now })
+
+	useCase := newSchemaCacheUseCase()
+	useCase.config.SchemaCacheTTL = time.Minute
+	useCase.config.WarmSchemaCache = true
+	t.Cleanup(func() { schemaCache.invalidate(useCase, "test") })
+
+	assert.True(t, crawlPass(context.Background(), useCase, 0))
+	loaded := len(useCase.queries)
+	assert.NotZero(t, loaded)
+
+	// Tools find the default schema cached
+	_, err := schemaCache.load(context.Background(), useCase, "test", "")
+	assert.NoError(t, err)
+	assert.Len(t, useCase.queries, loaded)
+
+	// Fresh metadata is left alone, and reloaded once half of the TTL has passed
+	now = now.Add(29 * time.Second)
+	crawlPass(context.Background(), useCase, 0)
+	assert.Len(t, useCase.queries, loaded)
+	now = now.Add(time.Second)
+	crawlPass(context.Background(), useCase, 0)
+	assert.Len(t, useCase.queries, 2*loaded)
+}
+
+func TestCrawlPassSkipsDatabasesNotOptedIn(t *testing.T) {
+	useCase := newSchemaCacheUseCase()
+	assert.True(t, crawlPass(context.Background(), useCase, 0))
+	assert.Empty(t, useCase.queries)
+}
+
+func TestCrawlSchemasStopsWhenCancelled(t *testing.T) {
+	useCase := newSchemaCacheUseCase()
+	useCase.config.WarmSchemaCache = true
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	crawlSchemas(ctx, useCase, time.Hour, time.Hour, time.Hour)
+	assert.Empty(t, useCase.queries)
+}
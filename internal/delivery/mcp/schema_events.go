@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// schemaChangeChannel is the notification channel the event triggers publish changed schemas on
+const schemaChangeChannel = "db_mcp_schema_change"
+
+// schemaChangeTriggerStatements install PostgreSQL event triggers that publish the schema of
+// every object created, altered or dropped, including by clients other than this server.
+// Creating event triggers requires a superuser.
+func schemaChangeTriggerStatements() []string {
+	return []string{
+		fmt.Sprintf(`CREATE OR REPLACE FUNCTION db_mcp_notify_schema_change() RETURNS event_trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+	changed record;
+BEGIN
+	IF TG_EVENT = 'sql_drop' THEN
+		FOR changed IN SELECT DISTINCT schema_name FROM pg_event_trigger_dropped_objects() LOOP
+			PERFORM pg_notify('%[1]s', coalesce(changed.schema_name, ''));
+		END LOOP;
+	ELSE
+		FOR changed IN SELECT DISTINCT schema_name FROM pg_event_trigger_ddl_commands() LOOP
+			PERFORM pg_notify('%[1]s', coalesce(changed.schema_name, ''));
+		END LOOP;
+	END IF;
+END
+$$`, schemaChangeChannel),
+		`DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_event_trigger WHERE evtname = 'db_mcp_schema_change') THEN
+		CREATE EVENT TRIGGER db_mcp_schema_change ON ddl_command_end EXECUTE PROCEDURE db_mcp_notify_schema_change();
+	END IF;
+	IF NOT EXISTS (SELECT 1 FROM pg_event_trigger WHERE evtname = 'db_mcp_schema_drop') THEN
+		CREATE EVENT TRIGGER db_mcp_schema_drop ON sql_drop EXECUTE PROCEDURE db_mcp_notify_schema_change();
+	END IF;
+END
+$$`,
+	}
+}
+
+// StartSchemaChangeListeners listens, until ctx is cancelled, for the schema changes published
+// by event triggers on the PostgreSQL databases that set schema_change_events, and drops the
+// cached metadata of each changed schema
+func StartSchemaChangeListeners(ctx context.Context, useCase UseCaseProvider) {
+	for _, dbID := range useCase.ListDatabases() {
+		config, err := useCase.GetDatabaseConfig(dbID)
+		if err != nil || config == nil || !config.SchemaChangeEvents {
+			continue
+		}
+		statements, ok := schemaChangeTriggersOf(useCase, dbID)
+		if !ok {
+			logger.Warn("schema_change_events is only supported on PostgreSQL, ignoring it for database %s", dbID)
+			continue
+		}
+		go func(dbID string) {
+			if err := listenForSchemaChanges(ctx, useCase, dbID, statements); err != nil {
+				logger.Error("Stopped listening for schema changes of database %s: %v", dbID, err)
+			}
+		}(dbID)
+	}
+}
+
+// schemaChangeTriggersOf returns the statements installing the schema change triggers of a
+// database, or false when its dialect cannot publish schema changes
+func schemaChangeTriggersOf(useCase UseCaseProvider, dbID string) ([]string, bool) {
+	dbType, err := useCase.GetDatabaseType(dbID)
+	if err != nil {
+		return nil, false
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, false
+	}
+	return dialect.SchemaChangeTriggerStatements()
+}
+
+// listenForSchemaChanges installs the event triggers of a database and invalidates its cached
+// metadata on every notification until ctx is cancelled
+func listenForSchemaChanges(ctx context.Context, useCase UseCaseProvider, dbID string, statements []string) error {
+	if err := useCase.ExecuteBatch(ctx, dbID, statements, nil); err != nil {
+		// A superuser may have installed them already
+		logger.Warn("Could not install the schema change event triggers on database %s: %v", dbID, err)
+	}
+	logger.Info("Listening for schema changes of database %s", dbID)
+	return useCase.Listen(ctx, dbID, schemaChangeChannel, func(schema string) {
+		change := &schemaChange{all: schema == "", schemas: map[string]bool{strings.ToLower(schema): true}}
+		dropped := schemaCache.invalidateChange(useCase, dbID, change)
+		logger.Debug("Schema %q of database %s changed, dropped %d cached schemas", schema, dbID, dropped)
+	})
+}
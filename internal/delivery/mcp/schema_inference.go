@@ -0,0 +1,539 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// cellKind is the type detected for a single value of a sample file
+type cellKind int
+
+const (
+	cellNull cellKind = iota
+	cellBool
+	cellInt
+	cellDecimal
+	cellDate
+	cellTimestamp
+	cellJSON
+	cellText
+)
+
+// sampleCell is a value read from a sample file with its detected kind
+type sampleCell struct {
+	text  string
+	kind  cellKind
+	value interface{} // the typed value for the detected kind
+}
+
+// sampleData is the content read from a CSV or JSONL file
+type sampleData struct {
+	Format    string
+	Columns   []string // column names as they appear in the file
+	Rows      [][]sampleCell
+	Truncated bool // reading stopped at the row limit
+}
+
+// decimalPattern matches plain and scientific decimal numbers, rejecting NaN and Inf
+var decimalPattern = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+
+// timestampLayouts are the accepted timestamp formats, tried in order
+var timestampLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04:05.999999999", "2006-01-02 15:04"}
+
+// readSampleFile reads up to limit rows (0 means all) of a CSV or JSONL file.
+// An empty format is taken from the file extension.
+func readSampleFile(path, format, delimiter string, header bool, limit int, strict bool) (*sampleData, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".jsonl", ".ndjson", ".json":
+			format = "jsonl"
+		case ".tsv":
+			format = "csv"
+			if delimiter == "" {
+				delimiter = "\t"
+			}
+		default:
+			format = "csv"
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	switch format {
+	case "csv":
+		return readCSVSample(file, delimiter, header, limit, strict)
+	case "jsonl":
+		return readJSONLSample(file, limit, strict)
+	default:
+		return nil, fmt.Errorf("unsupported format: %s (expected csv or jsonl)", format)
+	}
+}
+
+// readCSVSample reads CSV rows, naming columns from the header row or by position
+func readCSVSample(r io.Reader, delimiter string, header bool, limit int, strict bool) (*sampleData, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	if delimiter != "" {
+		comma, size := utf8.DecodeRuneInString(delimiter)
+		if size != len(delimiter) {
+			return nil, fmt.Errorf("delimiter must be a single character")
+		}
+		reader.Comma = comma
+	}
+
+	data := &sampleData{Format: "csv"}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CSV: %w", err)
+		}
+		if data.Columns == nil {
+			if header {
+				data.Columns = record
+				continue
+			}
+			for i := range record {
+				data.Columns = append(data.Columns, fmt.Sprintf("column%d", i+1))
+			}
+		}
+		if len(record) > len(data.Columns) {
+			return nil, fmt.Errorf("line %d has %d fields but there are %d columns", line, len(record), len(data.Columns))
+		}
+		if limit > 0 && len(data.Rows) == limit {
+			data.Truncated = true
+			break
+		}
+		row := make([]sampleCell, len(data.Columns))
+		for i, field := range record {
+			row[i] = classifyText(field, strict, true)
+		}
+		data.Rows = append(data.Rows, row)
+	}
+	if data.Columns == nil {
+		return nil, fmt.Errorf("the file is empty")
+	}
+	return data, nil
+}
+
+// readJSONLSample reads one JSON object per line; columns are the union of keys in order of appearance
+func readJSONLSample(r io.Reader, limit int, strict bool) (*sampleData, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	data := &sampleData{Format: "jsonl"}
+	position := make(map[string]int)
+	var objects []map[string]json.RawMessage
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if limit > 0 && len(objects) == limit {
+			data.Truncated = true
+			break
+		}
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(text), &object); err != nil {
+			return nil, fmt.Errorf("line %d is not a JSON object: %w", line, err)
+		}
+		for _, key := range orderedJSONKeys(text, object) {
+			if _, ok := position[key]; !ok {
+				position[key] = len(data.Columns)
+				data.Columns = append(data.Columns, key)
+			}
+		}
+		objects = append(objects, object)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read JSONL: %w", err)
+	}
+	if len(data.Columns) == 0 {
+		return nil, fmt.Errorf("the file has no JSON objects with keys")
+	}
+
+	for _, object := range objects {
+		row := make([]sampleCell, len(data.Columns))
+		for key, raw := range object {
+			row[position[key]] = classifyJSON(raw, strict)
+		}
+		data.Rows = append(data.Rows, row)
+	}
+	return data, nil
+}
+
+// orderedJSONKeys returns the keys of a JSON object in the order they appear in its source
+func orderedJSONKeys(source string, object map[string]json.RawMessage) []string {
+	decoder := json.NewDecoder(strings.NewReader(source))
+	keys := make([]string, 0, len(object))
+	if _, err := decoder.Token(); err != nil {
+		return keys
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		key, _ := token.(string)
+		keys = append(keys, key)
+		var skip json.RawMessage
+		if err := decoder.Decode(&skip); err != nil {
+			break
+		}
+	}
+	return keys
+}
+
+// classifyText detects the kind of a text value. Numbers are only detected when numeric is set,
+// and integers with leading zeros (codes, zip codes) stay text.
+func classifyText(text string, strict, numeric bool) sampleCell {
+	cell := sampleCell{text: text, kind: cellText, value: text}
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return sampleCell{kind: cellNull}
+	}
+
+	lower := strings.ToLower(trimmed)
+	switch {
+	case lower == "true" || (!strict && (lower == "t" || lower == "yes" || lower == "y")):
+		return sampleCell{text: text, kind: cellBool, value: true}
+	case lower == "false" || (!strict && (lower == "f" || lower == "no" || lower == "n")):
+		return sampleCell{text: text, kind: cellBool, value: false}
+	}
+
+	if numeric && decimalPattern.MatchString(trimmed) {
+		digits := strings.TrimLeft(trimmed, "+-")
+		if len(digits) > 1 && digits[0] == '0' && digits[1] != '.' {
+			return cell
+		}
+		if i, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+			return sampleCell{text: text, kind: cellInt, value: i}
+		}
+		return sampleCell{text: trimmed, kind: cellDecimal, value: trimmed}
+	}
+
+	if t, err := time.Parse("2006-01-02", trimmed); err == nil {
+		return sampleCell{text: text, kind: cellDate, value: t}
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, trimmed); err == nil {
+			return sampleCell{text: text, kind: cellTimestamp, value: t}
+		}
+	}
+	return cell
+}
+
+// classifyJSON detects the kind of a JSON value; strings are only checked for booleans, dates and timestamps
+func classifyJSON(raw json.RawMessage, strict bool) sampleCell {
+	text := strings.TrimSpace(string(raw))
+	switch {
+	case text == "null":
+		return sampleCell{kind: cellNull}
+	case text == "true" || text == "false":
+		return sampleCell{text: text, kind: cellBool, value: text == "true"}
+	case strings.HasPrefix(text, "{") || strings.HasPrefix(text, "["):
+		return sampleCell{text: text, kind: cellJSON, value: text}
+	case strings.HasPrefix(text, `"`):
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return sampleCell{text: text, kind: cellText, value: text}
+		}
+		cell := classifyText(s, true, false)
+		if cell.kind == cellBool {
+			// A quoted "true" is a string, not a boolean
+			return sampleCell{text: s, kind: cellText, value: s}
+		}
+		return cell
+	default:
+		if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return sampleCell{text: text, kind: cellInt, value: i}
+		}
+		return sampleCell{text: text, kind: cellDecimal, value: text}
+	}
+}
+
+// inferredColumn is a column of a sample file with the statistics used to pick its type
+type inferredColumn struct {
+	Name       string // sanitized SQL column name
+	Source     string // name in the file
+	Kind       cellKind
+	Nulls      int
+	Example    string
+	counts     map[cellKind]int
+	maxLength  int
+	minInt     int64
+	maxInt     int64
+	intDigits  int
+	scale      int
+	scientific bool
+}
+
+// inferColumns computes the kind and statistics of each column of the sample
+func inferColumns(data *sampleData) []*inferredColumn {
+	columns := make([]*inferredColumn, len(data.Columns))
+	used := make(map[string]bool)
+	for i, source := range data.Columns {
+		name := sanitizeColumnName(source, i)
+		for base, n := name, 2; used[name]; n++ {
+			name = fmt.Sprintf("%s_%d", base, n)
+		}
+		used[name] = true
+		columns[i] = &inferredColumn{Name: name, Source: source, counts: make(map[cellKind]int)}
+	}
+
+	for _, row := range data.Rows {
+		for i, cell := range row {
+			col := columns[i]
+			col.counts[cell.kind]++
+			if cell.kind == cellNull {
+				col.Nulls++
+				continue
+			}
+			if col.Example == "" {
+				col.Example = cell.text
+			}
+			if length := utf8.RuneCountInString(cell.text); length > col.maxLength {
+				col.maxLength = length
+			}
+			switch cell.kind {
+			case cellInt:
+				v := cell.value.(int64)
+				if col.counts[cellInt] == 1 || v < col.minInt {
+					col.minInt = v
+				}
+				if col.counts[cellInt] == 1 || v > col.maxInt {
+					col.maxInt = v
+				}
+				col.measureDecimal(cell.text)
+			case cellDecimal:
+				col.measureDecimal(cell.text)
+			}
+		}
+	}
+
+	for _, col := range columns {
+		col.Kind = col.resolveKind()
+	}
+	return columns
+}
+
+// measureDecimal tracks the integer digits and scale needed to store a number exactly
+func (c *inferredColumn) measureDecimal(text string) {
+	text = strings.TrimLeft(strings.TrimSpace(text), "+-")
+	if strings.ContainsAny(text, "eE") {
+		c.scientific = true
+		return
+	}
+	whole, fraction, _ := strings.Cut(text, ".")
+	whole = strings.TrimLeft(whole, "0")
+	if len(whole) > c.intDigits {
+		c.intDigits = len(whole)
+	}
+	if len(fraction) > c.scale {
+		c.scale = len(fraction)
+	}
+}
+
+// resolveKind picks the narrowest kind that fits every non-null value of the column
+func (c *inferredColumn) resolveKind() cellKind {
+	var kinds []cellKind
+	for kind, count := range c.counts {
+		if kind != cellNull && count > 0 {
+			kinds = append(kinds, kind)
+		}
+	}
+	has := func(kind cellKind) bool { return c.counts[kind] > 0 }
+	switch {
+	case len(kinds) == 0:
+		return cellText
+	case len(kinds) == 1:
+		return kinds[0]
+	case len(kinds) == 2 && has(cellInt) && has(cellDecimal):
+		return cellDecimal
+	case len(kinds) == 2 && has(cellDate) && has(cellTimestamp):
+		return cellTimestamp
+	default:
+		return cellText
+	}
+}
+
+// inferredTypeNames are the type names a dialect gives the columns schema inference infers
+type inferredTypeNames struct {
+	integer, decimal, float, timestamp, json string
+	// longVarchar is set when VARCHAR takes lengths beyond 255, which otherwise become TEXT
+	longVarchar bool
+}
+
+// postgresInferredTypes are the names PostgreSQL and its derivatives use
+var postgresInferredTypes = inferredTypeNames{
+	integer: "INTEGER", decimal: "NUMERIC", float: "DOUBLE PRECISION", timestamp: "TIMESTAMP", json: "JSONB",
+	longVarchar: true,
+}
+
+// mysqlInferredTypes are the names MySQL uses, and the default of other dialects
+var mysqlInferredTypes = inferredTypeNames{
+	integer: "INT", decimal: "DECIMAL", float: "DOUBLE", timestamp: "DATETIME", json: "JSON",
+}
+
+// inferredTypesFor returns the type names of a dialect
+func inferredTypesFor(dbType string) inferredTypeNames {
+	if dialect, ok := lookupDialect(dbType); ok {
+		return dialect.InferredTypes()
+	}
+	return mysqlInferredTypes
+}
+
+// sqlType returns the column type for the dialect. Strict inference picks the narrowest type
+// that fits the sample, lenient inference picks wide types that tolerate more data later.
+func (c *inferredColumn) sqlType(dialect string, strict bool) string {
+	names := inferredTypesFor(dialect)
+	switch c.Kind {
+	case cellBool:
+		return "BOOLEAN"
+	case cellInt:
+		if strict && c.minInt >= -1<<31 && c.maxInt < 1<<31 {
+			return names.integer
+		}
+		return "BIGINT"
+	case cellDecimal:
+		precision := c.intDigits + c.scale
+		if strict && !c.scientific && precision > 0 && precision <= 65 && c.scale <= 30 {
+			return fmt.Sprintf("%s(%d, %d)", names.decimal, precision, c.scale)
+		}
+		return names.float
+	case cellDate:
+		return "DATE"
+	case cellTimestamp:
+		return names.timestamp
+	case cellJSON:
+		return names.json
+	default:
+		if strict && c.maxLength > 0 && (names.longVarchar || c.maxLength <= 255) {
+			return fmt.Sprintf("VARCHAR(%d)", c.maxLength)
+		}
+		return "TEXT"
+	}
+}
+
+// notNull reports whether the column is declared NOT NULL: only strict inference does so,
+// and only when the sample has no empty values
+func (c *inferredColumn) notNull(strict bool) bool {
+	return strict && c.Nulls == 0 && len(c.counts) > 0
+}
+
+// bindValue converts a cell to the value bound when loading it into the column
+func (c *inferredColumn) bindValue(cell sampleCell) interface{} {
+	switch {
+	case cell.kind == cellNull:
+		return nil
+	case c.Kind == cellText || c.Kind == cellJSON:
+		return cell.text
+	case c.Kind == cellDecimal:
+		return strings.TrimSpace(cell.text)
+	default:
+		return cell.value
+	}
+}
+
+// columnNameReplacer matches the characters replaced in sanitized column names
+var columnNameReplacer = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// sanitizeColumnName turns a header into a lower-case SQL identifier
+func sanitizeColumnName(name string, index int) string {
+	name = strings.Trim(columnNameReplacer.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "_"), "_")
+	if name == "" {
+		return fmt.Sprintf("column%d", index+1)
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "c_" + name
+	}
+	return name
+}
+
+// createTableDDL renders the CREATE TABLE statement for the inferred columns
+func createTableDDL(dialect, table string, columns []*inferredColumn, strict bool) string {
+	lines := make([]string, len(columns))
+	for i, col := range columns {
+		lines[i] = fmt.Sprintf("  %s %s", quoteIdentifier(dialect, col.Name), col.sqlType(dialect, strict))
+		if col.notNull(strict) {
+			lines[i] += " NOT NULL"
+		}
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n%s\n)", quoteIdentifier(dialect, table), strings.Join(lines, ",\n"))
+}
+
+// insertStatements builds multi-row INSERT statements loading the sample rows
+func insertStatements(dialect, table string, columns []*inferredColumn, rows [][]sampleCell) ([]string, [][]interface{}) {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+	}
+	values := make([][]interface{}, len(rows))
+	for r, row := range rows {
+		values[r] = make([]interface{}, len(columns))
+		for i, col := range columns {
+			values[r][i] = col.bindValue(row[i])
+		}
+	}
+	return insertRowStatements(dialect, table, names, values)
+}
+
+// insertRowStatements builds multi-row INSERT statements for the given column values
+func insertRowStatements(dialect, table string, columns []string, rows [][]interface{}) ([]string, [][]interface{}) {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = quoteIdentifier(dialect, col)
+	}
+	// Stay well below the bind parameter limits of the drivers
+	batchRows := 60000 / len(columns)
+	if batchRows > 500 {
+		batchRows = 500
+	}
+	if batchRows < 1 {
+		batchRows = 1
+	}
+
+	var statements []string
+	var params [][]interface{}
+	for start := 0; start < len(rows); start += batchRows {
+		end := start + batchRows
+		if end > len(rows) {
+			end = len(rows)
+		}
+		p := newSQLParams(dialect)
+		tuples := make([]string, 0, end-start)
+		for _, row := range rows[start:end] {
+			placeholders := make([]string, len(columns))
+			for i := range columns {
+				placeholders[i] = p.add(row[i])
+			}
+			tuples = append(tuples, "("+strings.Join(placeholders, ", ")+")")
+		}
+		statements = append(statements, fmt.Sprintf("INSERT INTO %s (%s) VALUES %s",
+			quoteIdentifier(dialect, table), strings.Join(names, ", "), strings.Join(tuples, ", ")))
+		params = append(params, p.values)
+	}
+	return statements, params
+}
+
+// defaultTableName derives a table name from the file name
+func defaultTableName(path string) string {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return sanitizeColumnName(base, 0)
+}
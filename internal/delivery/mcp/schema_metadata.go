@@ -0,0 +1,1020 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schemaColumn describes a single column of a table
+type schemaColumn struct {
+	Name      string `json:"name"`
+	DataType  string `json:"data_type"`
+	Nullable  bool   `json:"nullable"`
+	Comment   string `json:"comment,omitempty"`
+	Collation string `json:"collation,omitempty"`
+}
+
+// schemaIndex describes an index defined on a table
+type schemaIndex struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique,omitempty"`
+	Primary bool     `json:"primary,omitempty"`
+}
+
+// schemaTable describes a table with its columns, keys and indexes
+type schemaTable struct {
+	Schema     string         `json:"schema,omitempty"`
+	Name       string         `json:"name"`
+	Comment    string         `json:"comment,omitempty"`
+	Columns    []schemaColumn `json:"columns"`
+	PrimaryKey []string       `json:"primary_key,omitempty"`
+	Unique     [][]string     `json:"unique,omitempty"`
+	Indexes    []schemaIndex  `json:"indexes,omitempty"`
+	// InterleavedIn names the parent of a Spanner table stored interleaved with its parent
+	InterleavedIn string `json:"interleaved_in,omitempty"`
+}
+
+// schemaForeignKey describes a foreign key relationship between two tables
+type schemaForeignKey struct {
+	Name       string   `json:"name"`
+	Schema     string   `json:"schema,omitempty"`
+	Table      string   `json:"table"`
+	Columns    []string `json:"columns"`
+	RefSchema  string   `json:"ref_schema"`
+	RefTable   string   `json:"ref_table"`
+	RefColumns []string `json:"ref_columns"`
+}
+
+// schemaMetadata holds the structural metadata of a database schema
+type schemaMetadata struct {
+	DatabaseType string             `json:"database_type"`
+	Schema       string             `json:"schema,omitempty"`
+	Tables       []*schemaTable     `json:"tables"`
+	ForeignKeys  []schemaForeignKey `json:"foreign_keys,omitempty"`
+}
+
+// loadSchemaMetadata reads tables, columns, keys and indexes for a schema.
+// An empty schema selects "public" on PostgreSQL and the current database on MySQL.
+func loadSchemaMetadata(ctx context.Context, useCase UseCaseProvider, dbID, schema string) (*schemaMetadata, error) {
+	dbType, err := useCase.GetDatabaseType(dbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for schema metadata: %s", dbType)
+	}
+	schema, queries := dialect.SchemaMetadataQueries(schema)
+
+	meta := &schemaMetadata{
+		DatabaseType: strings.ToLower(dbType),
+		Schema:       schema,
+	}
+	tablesByKey := make(map[string]*schemaTable)
+
+	// Columns (one row per column, ordered by table and position)
+	columns, err := useCase.ExecuteQuery(ctx, dbID, queries.columns, queries.params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load columns: %w", err)
+	}
+	for _, row := range columns.Rows {
+		key := schemaTableKey(valueString(row[0]), valueString(row[1]))
+		table, ok := tablesByKey[key]
+		if !ok {
+			table = &schemaTable{
+				Schema:  valueString(row[0]),
+				Name:    valueString(row[1]),
+				Comment: valueString(row[6]),
+			}
+			tablesByKey[key] = table
+			meta.Tables = append(meta.Tables, table)
+		}
+		table.Columns = append(table.Columns, schemaColumn{
+			Name:      valueString(row[2]),
+			DataType:  valueString(row[3]),
+			Nullable:  valueBool(row[4]),
+			Comment:   valueString(row[5]),
+			Collation: valueString(row[7]),
+		})
+	}
+
+	// Constraints (one row per constraint column, ordered by position)
+	constraints, err := useCase.ExecuteQuery(ctx, dbID, queries.constraints, queries.params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load constraints: %w", err)
+	}
+	var current *schemaForeignKey
+	var currentKey string
+	var keyColumns []string
+	var keyType string
+	var keyTable *schemaTable
+	flush := func() {
+		if keyTable == nil || len(keyColumns) == 0 {
+			return
+		}
+		switch keyType {
+		case "PRIMARY KEY":
+			keyTable.PrimaryKey = keyColumns
+		case "UNIQUE":
+			keyTable.Unique = append(keyTable.Unique, keyColumns)
+		case "FOREIGN KEY":
+			meta.ForeignKeys = append(meta.ForeignKeys, *current)
+		case "INTERLEAVE":
+			// An interleaved table references its parent on the parent's primary key, so the
+			// relationship tools follow it like a foreign key
+			keyTable.InterleavedIn = current.RefTable
+			meta.ForeignKeys = append(meta.ForeignKeys, *current)
+		}
+	}
+	for _, row := range constraints.Rows {
+		name := valueString(row[0])
+		constraintType := valueString(row[1])
+		tableSchema := valueString(row[2])
+		tableName := valueString(row[3])
+		key := schemaTableKey(tableSchema, tableName) + "." + name
+		if key != currentKey {
+			flush()
+			currentKey = key
+			keyType = constraintType
+			keyTable = tablesByKey[schemaTableKey(tableSchema, tableName)]
+			keyColumns = nil
+			current = &schemaForeignKey{
+				Name:      name,
+				Schema:    tableSchema,
+				Table:     tableName,
+				RefSchema: valueString(row[5]),
+				RefTable:  valueString(row[6]),
+			}
+		}
+		keyColumns = append(keyColumns, valueString(row[4]))
+		if constraintType == "FOREIGN KEY" || constraintType == "INTERLEAVE" {
+			current.Columns = append(current.Columns, valueString(row[4]))
+			current.RefColumns = append(current.RefColumns, valueString(row[7]))
+		}
+	}
+	flush()
+
+	// Indexes (one row per index column, ordered by position)
+	indexes, err := useCase.ExecuteQuery(ctx, dbID, queries.indexes, queries.params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load indexes: %w", err)
+	}
+	for _, row := range indexes.Rows {
+		table := tablesByKey[schemaTableKey(valueString(row[0]), valueString(row[1]))]
+		if table == nil {
+			continue
+		}
+		indexName := valueString(row[2])
+		n := len(table.Indexes)
+		if n == 0 || table.Indexes[n-1].Name != indexName {
+			table.Indexes = append(table.Indexes, schemaIndex{
+				Name:    indexName,
+				Unique:  valueBool(row[3]),
+				Primary: valueBool(row[4]),
+			})
+			n++
+		}
+		table.Indexes[n-1].Columns = append(table.Indexes[n-1].Columns, valueString(row[5]))
+	}
+
+	return meta, nil
+}
+
+// table returns the table with the given name, or nil if it is not part of the metadata
+func (m *schemaMetadata) table(name string) *schemaTable {
+	schema, table := splitQualifiedName(name)
+	for _, t := range m.Tables {
+		if t.Name == table && (schema == "" || t.Schema == schema) {
+			return t
+		}
+	}
+	return nil
+}
+
+// filterTables returns a copy of the metadata restricted to the given tables.
+// Only foreign keys with both ends inside the selection are kept.
+func (m *schemaMetadata) filterTables(names []string) (*schemaMetadata, error) {
+	filtered := &schemaMetadata{
+		DatabaseType: m.DatabaseType,
+		Schema:       m.Schema,
+	}
+	selected := make(map[string]bool)
+	for _, name := range names {
+		table := m.table(name)
+		if table == nil {
+			return nil, fmt.Errorf("table %s not found", name)
+		}
+		key := schemaTableKey(table.Schema, table.Name)
+		if !selected[key] {
+			selected[key] = true
+			filtered.Tables = append(filtered.Tables, table)
+		}
+	}
+	for _, fk := range m.ForeignKeys {
+		if selected[schemaTableKey(fk.Schema, fk.Table)] && selected[schemaTableKey(fk.RefSchema, fk.RefTable)] {
+			filtered.ForeignKeys = append(filtered.ForeignKeys, fk)
+		}
+	}
+	return filtered, nil
+}
+
+// column returns the column with the given name, or nil if it does not exist
+func (t *schemaTable) column(name string) *schemaColumn {
+	for i := range t.Columns {
+		if t.Columns[i].Name == name {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+// isUnique reports whether the column set is covered by the primary key, a unique constraint or a unique index
+func (t *schemaTable) isUnique(columns []string) bool {
+	if sameColumnSet(t.PrimaryKey, columns) {
+		return true
+	}
+	for _, unique := range t.Unique {
+		if sameColumnSet(unique, columns) {
+			return true
+		}
+	}
+	for _, index := range t.Indexes {
+		if index.Unique && sameColumnSet(index.Columns, columns) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasNullableColumn reports whether any of the given columns accepts NULL values
+func (t *schemaTable) hasNullableColumn(columns []string) bool {
+	for _, name := range columns {
+		if col := t.column(name); col != nil && col.Nullable {
+			return true
+		}
+	}
+	return false
+}
+
+// sameColumnSet reports whether two column lists contain the same columns regardless of order
+func sameColumnSet(a, b []string) bool {
+	if len(a) == 0 || len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// schemaTableKey builds a map key for a schema-qualified table
+func schemaTableKey(schema, table string) string {
+	return schema + "." + table
+}
+
+// splitQualifiedName splits "schema.table" into its parts; the schema is empty if not given
+func splitQualifiedName(name string) (string, string) {
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		return name[:idx], name[idx+1:]
+	}
+	return "", name
+}
+
+// valueString converts a scanned database value to a string
+func valueString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// valueBool converts a scanned database value to a bool
+func valueBool(v interface{}) bool {
+	switch val := v.(type) {
+	case bool:
+		return val
+	case int64:
+		return val != 0
+	case string:
+		switch strings.ToUpper(val) {
+		case "YES", "Y", "TRUE", "T", "1":
+			return true
+		}
+	}
+	return false
+}
+
+// valueInt64 converts a scanned database value to an int64
+func valueInt64(v interface{}) int64 {
+	switch val := v.(type) {
+	case int64:
+		return val
+	case int32:
+		return int64(val)
+	case int:
+		return int64(val)
+	case float64:
+		return int64(val)
+	case float32:
+		return int64(val)
+	case string:
+		if n, err := strconv.ParseFloat(val, 64); err == nil {
+			return int64(n)
+		}
+	}
+	return 0
+}
+
+// schemaMetadataQueries holds the queries used to load schema metadata
+type schemaMetadataQueries struct {
+	columns     string
+	constraints string
+	indexes     string
+	params      []interface{}
+}
+
+// getPostgresSchemaMetadataQueries returns the metadata queries for a PostgreSQL schema
+func getPostgresSchemaMetadataQueries(schema string) schemaMetadataQueries {
+	return schemaMetadataQueries{
+		columns: `
+SELECT
+    n.nspname AS table_schema,
+    c.relname AS table_name,
+    a.attname AS column_name,
+    pg_catalog.format_type(a.atttypid, a.atttypmod) AS data_type,
+    NOT a.attnotnull AS is_nullable,
+    pg_catalog.col_description(c.oid, a.attnum) AS column_comment,
+    pg_catalog.obj_description(c.oid, 'pg_class') AS table_comment,
+    co.collname AS collation_name
+FROM pg_catalog.pg_attribute a
+JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_catalog.pg_collation co ON co.oid = a.attcollation
+WHERE c.relkind IN ('r', 'p')
+AND NOT c.relispartition
+AND a.attnum > 0
+AND NOT a.attisdropped
+AND n.nspname = $1
+ORDER BY c.relname, a.attnum;`,
+		constraints: `
+SELECT
+    con.conname AS constraint_name,
+    CASE con.contype WHEN 'p' THEN 'PRIMARY KEY' WHEN 'u' THEN 'UNIQUE' ELSE 'FOREIGN KEY' END AS constraint_type,
+    n.nspname AS table_schema,
+    c.relname AS table_name,
+    a.attname AS column_name,
+    fn.nspname AS referenced_schema,
+    fc.relname AS referenced_table,
+    fa.attname AS referenced_column
+FROM pg_catalog.pg_constraint con
+JOIN pg_catalog.pg_class c ON c.oid = con.conrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+CROSS JOIN LATERAL unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+JOIN pg_catalog.pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+LEFT JOIN pg_catalog.pg_class fc ON fc.oid = con.confrelid
+LEFT JOIN pg_catalog.pg_namespace fn ON fn.oid = fc.relnamespace
+LEFT JOIN pg_catalog.pg_attribute fa ON fa.attrelid = con.confrelid AND fa.attnum = con.confkey[k.ord]
+WHERE con.contype IN ('p', 'u', 'f')
+AND n.nspname = $1
+ORDER BY c.relname, con.conname, k.ord;`,
+		indexes: `
+SELECT
+    n.nspname AS table_schema,
+    t.relname AS table_name,
+    i.relname AS index_name,
+    ix.indisunique AS is_unique,
+    ix.indisprimary AS is_primary,
+    a.attname AS column_name
+FROM pg_catalog.pg_index ix
+JOIN pg_catalog.pg_class i ON i.oid = ix.indexrelid
+JOIN pg_catalog.pg_class t ON t.oid = ix.indrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace
+CROSS JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+JOIN pg_catalog.pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+WHERE n.nspname = $1
+ORDER BY t.relname, i.relname, k.ord;`,
+		params: []interface{}{schema},
+	}
+}
+
+// getMySQLSchemaMetadataQueries returns the metadata queries for a MySQL schema
+func getMySQLSchemaMetadataQueries(schema string) schemaMetadataQueries {
+	schemaFilter := "DATABASE()"
+	var params []interface{}
+	if schema != "" {
+		schemaFilter = "?"
+		params = []interface{}{schema}
+	}
+
+	return schemaMetadataQueries{
+		columns: fmt.Sprintf(`
+SELECT
+    c.table_schema,
+    c.table_name,
+    c.column_name,
+    c.column_type AS data_type,
+    c.is_nullable,
+    c.column_comment,
+    t.table_comment,
+    c.collation_name
+FROM information_schema.columns c
+JOIN information_schema.tables t
+    ON t.table_schema = c.table_schema
+    AND t.table_name = c.table_name
+WHERE c.table_schema = %s
+AND t.table_type = 'BASE TABLE'
+ORDER BY c.table_name, c.ordinal_position;`, schemaFilter),
+		constraints: fmt.Sprintf(`
+SELECT
+    tc.constraint_name,
+    tc.constraint_type,
+    kcu.table_schema,
+    kcu.table_name,
+    kcu.column_name,
+    kcu.referenced_table_schema,
+    kcu.referenced_table_name,
+    kcu.referenced_column_name
+FROM information_schema.table_constraints tc
+JOIN information_schema.key_column_usage kcu
+    ON tc.constraint_name = kcu.constraint_name
+    AND tc.table_schema = kcu.table_schema
+    AND tc.table_name = kcu.table_name
+WHERE tc.table_schema = %s
+AND tc.constraint_type IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+ORDER BY kcu.table_name, tc.constraint_name, kcu.ordinal_position;`, schemaFilter),
+		indexes: fmt.Sprintf(`
+SELECT
+    table_schema,
+    table_name,
+    index_name,
+    non_unique = 0 AS is_unique,
+    index_name = 'PRIMARY' AS is_primary,
+    column_name
+FROM information_schema.statistics
+WHERE table_schema = %s
+ORDER BY table_name, index_name, seq_in_index;`, schemaFilter),
+		params: params,
+	}
+}
+
+// getSQLiteSchemaMetadataQueries returns the metadata queries for a SQLite schema (main,
+// temp or an attached database). Pragma functions take the schema as their last argument.
+// SQLite does not name primary and foreign keys, so they are named after their table.
+func getSQLiteSchemaMetadataQueries(schema string) schemaMetadataQueries {
+	catalog := quoteIdentifier("sqlite", schema) + ".sqlite_master"
+	literal := quoteLiteral("sqlite", schema)
+
+	return schemaMetadataQueries{
+		columns: fmt.Sprintf(`
+SELECT
+    %[2]s AS table_schema,
+    m.name AS table_name,
+    p.name AS column_name,
+    p.type AS data_type,
+    p."notnull" = 0 AS is_nullable,
+    NULL AS column_comment,
+    NULL AS table_comment,
+    NULL AS collation_name
+FROM %[1]s m
+JOIN pragma_table_info(m.name, %[2]s) p
+WHERE m.type = 'table'
+AND m.name NOT LIKE 'sqlite_%%'
+ORDER BY m.name, p.cid;`, catalog, literal),
+		constraints: fmt.Sprintf(`
+SELECT
+    m.name || '_pkey' AS constraint_name,
+    'PRIMARY KEY' AS constraint_type,
+    %[2]s AS table_schema,
+    m.name AS table_name,
+    p.name AS column_name,
+    NULL AS referenced_schema,
+    NULL AS referenced_table,
+    NULL AS referenced_column,
+    p.pk AS ord
+FROM %[1]s m
+JOIN pragma_table_info(m.name, %[2]s) p
+WHERE m.type = 'table' AND p.pk > 0
+UNION ALL
+SELECT il.name, 'UNIQUE', %[2]s, m.name, ii.name, NULL, NULL, NULL, ii.seqno
+FROM %[1]s m
+JOIN pragma_index_list(m.name, %[2]s) il
+JOIN pragma_index_info(il.name, %[2]s) ii
+WHERE m.type = 'table' AND il.origin = 'u'
+UNION ALL
+SELECT m.name || '_fk' || fk.id, 'FOREIGN KEY', %[2]s, m.name, fk."from", %[2]s, fk."table", fk."to", fk.seq
+FROM %[1]s m
+JOIN pragma_foreign_key_list(m.name, %[2]s) fk
+WHERE m.type = 'table'
+ORDER BY 4, 1, 9;`, catalog, literal),
+		indexes: fmt.Sprintf(`
+SELECT
+    %[2]s AS table_schema,
+    m.name AS table_name,
+    il.name AS index_name,
+    il."unique" AS is_unique,
+    il.origin = 'pk' AS is_primary,
+    COALESCE(ii.name, '<expression>') AS column_name
+FROM %[1]s m
+JOIN pragma_index_list(m.name, %[2]s) il
+JOIN pragma_index_info(il.name, %[2]s) ii
+WHERE m.type = 'table'
+ORDER BY m.name, il.name, ii.seqno;`, catalog, literal),
+	}
+}
+
+// getClickHouseSchemaMetadataQueries returns the metadata queries for a ClickHouse database.
+// The primary key is listed as a non-unique index: ClickHouse keys only order the data.
+// ClickHouse has no unique or foreign keys, and system.columns lists key columns in table order.
+func getClickHouseSchemaMetadataQueries(schema string) schemaMetadataQueries {
+	// The indexes query filters twice, so the schema is quoted rather than bound
+	schemaFilter := "currentDatabase()"
+	if schema != "" {
+		schemaFilter = quoteLiteral("clickhouse", schema)
+	}
+
+	return schemaMetadataQueries{
+		columns: fmt.Sprintf(`
+SELECT
+    c.database AS table_schema,
+    c.table AS table_name,
+    c.name AS column_name,
+    c.type AS data_type,
+    startsWith(c.type, 'Nullable(') AS is_nullable,
+    c.comment AS column_comment,
+    t.comment AS table_comment,
+    '' AS collation_name
+FROM system.columns c
+JOIN system.tables t
+    ON t.database = c.database
+    AND t.name = c.table
+WHERE c.database = %s
+AND t.engine NOT IN ('View', 'MaterializedView', 'LiveView')
+AND NOT t.is_temporary
+ORDER BY c.table, c.position;`, schemaFilter),
+		constraints: fmt.Sprintf(`
+SELECT
+    'PRIMARY' AS constraint_name,
+    'PRIMARY KEY' AS constraint_type,
+    database AS table_schema,
+    table AS table_name,
+    name AS column_name,
+    '' AS referenced_table_schema,
+    '' AS referenced_table_name,
+    '' AS referenced_column_name
+FROM system.columns
+WHERE database = %s
+AND is_in_primary_key
+ORDER BY table, position;`, schemaFilter),
+		indexes: fmt.Sprintf(`
+SELECT
+    table_schema,
+    table_name,
+    index_name,
+    0 AS is_unique,
+    index_name = 'PRIMARY' AS is_primary,
+    column_name
+FROM (
+    SELECT database AS table_schema, table AS table_name, 'PRIMARY' AS index_name, name AS column_name, position
+    FROM system.columns
+    WHERE database = %[1]s
+    AND is_in_primary_key
+    UNION ALL
+    SELECT database, table, name, expr, 0
+    FROM system.data_skipping_indices
+    WHERE database = %[1]s
+) AS i
+ORDER BY table_name, index_name, position;`, schemaFilter),
+	}
+}
+
+// getBigQuerySchemaMetadataQueries returns the metadata queries for a BigQuery dataset; an
+// empty schema reads the default dataset of the connection. Keys are unenforced and
+// BigQuery has no indexes, so the indexes query returns no rows.
+func getBigQuerySchemaMetadataQueries(schema string) schemaMetadataQueries {
+	// INFORMATION_SCHEMA belongs to a dataset, so the dataset qualifies the views
+	catalog := "INFORMATION_SCHEMA"
+	if schema != "" {
+		catalog = quoteIdentifier("bigquery", schema) + ".INFORMATION_SCHEMA"
+	}
+
+	return schemaMetadataQueries{
+		columns: fmt.Sprintf(`
+SELECT
+    c.table_schema,
+    c.table_name,
+    c.column_name,
+    c.data_type,
+    c.is_nullable,
+    p.description AS column_comment,
+    o.option_value AS table_comment,
+    c.collation_name
+FROM %[1]s.COLUMNS c
+JOIN %[1]s.TABLES t
+    ON t.table_schema = c.table_schema
+    AND t.table_name = c.table_name
+LEFT JOIN %[1]s.COLUMN_FIELD_PATHS p
+    ON p.table_name = c.table_name
+    AND p.field_path = c.column_name
+LEFT JOIN %[1]s.TABLE_OPTIONS o
+    ON o.table_name = c.table_name
+    AND o.option_name = 'description'
+WHERE t.table_type = 'BASE TABLE'
+ORDER BY c.table_name, c.ordinal_position;`, catalog),
+		constraints: fmt.Sprintf(`
+SELECT
+    tc.constraint_name,
+    tc.constraint_type,
+    kcu.table_schema,
+    kcu.table_name,
+    kcu.column_name,
+    rk.table_schema AS referenced_table_schema,
+    rk.table_name AS referenced_table_name,
+    rk.column_name AS referenced_column_name
+FROM %[1]s.TABLE_CONSTRAINTS tc
+JOIN %[1]s.KEY_COLUMN_USAGE kcu
+    ON tc.constraint_name = kcu.constraint_name
+    AND tc.table_name = kcu.table_name
+LEFT JOIN (
+    SELECT DISTINCT constraint_name, table_schema, table_name
+    FROM %[1]s.CONSTRAINT_COLUMN_USAGE
+) ref
+    ON tc.constraint_type = 'FOREIGN KEY'
+    AND ref.constraint_name = tc.constraint_name
+LEFT JOIN %[1]s.TABLE_CONSTRAINTS rc
+    ON rc.table_name = ref.table_name
+    AND rc.constraint_type = 'PRIMARY KEY'
+LEFT JOIN %[1]s.KEY_COLUMN_USAGE rk
+    ON rk.constraint_name = rc.constraint_name
+    AND rk.table_name = rc.table_name
+    AND rk.ordinal_position = kcu.position_in_unique_constraint
+WHERE tc.constraint_type IN ('PRIMARY KEY', 'FOREIGN KEY')
+ORDER BY kcu.table_name, tc.constraint_name, kcu.ordinal_position;`, catalog),
+		indexes: `
+SELECT
+    '' AS table_schema,
+    '' AS table_name,
+    '' AS index_name,
+    FALSE AS is_unique,
+    FALSE AS is_primary,
+    '' AS column_name
+LIMIT 0;`,
+	}
+}
+
+// getSpannerSchemaMetadataQueries returns the metadata queries for a Spanner schema; an empty
+// schema is the default schema. Each interleaved table adds INTERLEAVE rows that pair the
+// primary key columns it shares with its parent.
+func getSpannerSchemaMetadataQueries(schema string) schemaMetadataQueries {
+	// Spanner has no comments or collations, and the queries take no parameters
+	schemaLiteral := quoteLiteral("spanner", schema)
+
+	return schemaMetadataQueries{
+		columns: fmt.Sprintf(`
+SELECT
+    c.table_schema,
+    c.table_name,
+    c.column_name,
+    c.spanner_type AS data_type,
+    c.is_nullable,
+    '' AS column_comment,
+    '' AS table_comment,
+    '' AS collation_name
+FROM INFORMATION_SCHEMA.COLUMNS c
+JOIN INFORMATION_SCHEMA.TABLES t
+    ON t.table_schema = c.table_schema
+    AND t.table_name = c.table_name
+WHERE t.table_type = 'BASE TABLE'
+AND c.table_schema = %[1]s
+ORDER BY c.table_name, c.ordinal_position;`, schemaLiteral),
+		constraints: fmt.Sprintf(`
+SELECT
+    tc.constraint_name,
+    tc.constraint_type,
+    kcu.table_schema,
+    kcu.table_name,
+    kcu.column_name,
+    rk.table_schema AS referenced_table_schema,
+    rk.table_name AS referenced_table_name,
+    rk.column_name AS referenced_column_name,
+    kcu.ordinal_position AS position
+FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+    ON kcu.constraint_schema = tc.constraint_schema
+    AND kcu.constraint_name = tc.constraint_name
+LEFT JOIN INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS rc
+    ON rc.constraint_schema = tc.constraint_schema
+    AND rc.constraint_name = tc.constraint_name
+LEFT JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE rk
+    ON rk.constraint_schema = rc.unique_constraint_schema
+    AND rk.constraint_name = rc.unique_constraint_name
+    AND rk.ordinal_position = kcu.position_in_unique_constraint
+WHERE tc.constraint_type IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+AND tc.table_schema = %[1]s
+UNION ALL
+SELECT
+    CONCAT('INTERLEAVE IN ', t.parent_table_name),
+    'INTERLEAVE',
+    t.table_schema,
+    t.table_name,
+    k.column_name,
+    t.table_schema,
+    t.parent_table_name,
+    k.column_name,
+    k.ordinal_position
+FROM INFORMATION_SCHEMA.TABLES t
+JOIN INFORMATION_SCHEMA.INDEX_COLUMNS k
+    ON k.table_schema = t.table_schema
+    AND k.table_name = t.parent_table_name
+    AND k.index_type = 'PRIMARY_KEY'
+WHERE t.parent_table_name IS NOT NULL
+AND t.table_schema = %[1]s
+ORDER BY table_name, constraint_name, position;`, schemaLiteral),
+		indexes: fmt.Sprintf(`
+SELECT
+    i.table_schema,
+    i.table_name,
+    i.index_name,
+    i.is_unique,
+    i.index_type = 'PRIMARY_KEY' AS is_primary,
+    c.column_name
+FROM INFORMATION_SCHEMA.INDEXES i
+JOIN INFORMATION_SCHEMA.INDEX_COLUMNS c
+    ON c.table_schema = i.table_schema
+    AND c.table_name = i.table_name
+    AND c.index_name = i.index_name
+WHERE i.table_schema = %[1]s
+AND c.ordinal_position IS NOT NULL
+AND NOT i.spanner_is_managed
+ORDER BY i.table_name, i.index_name, c.ordinal_position;`, schemaLiteral),
+	}
+}
+
+// getHANASchemaMetadataQueries returns the metadata queries for a SAP HANA schema; an empty
+// schema reads the schema of the session
+func getHANASchemaMetadataQueries(schema string) schemaMetadataQueries {
+	// The queries take no parameters, so the schema is quoted rather than bound; HANA has
+	// no boolean select expressions, so the index flags are 'TRUE' or 'FALSE'
+	schemaFilter := "CURRENT_SCHEMA"
+	if schema != "" {
+		schemaFilter = quoteLiteral("hana", schema)
+	}
+
+	return schemaMetadataQueries{
+		columns: fmt.Sprintf(`
+SELECT
+    c.SCHEMA_NAME,
+    c.TABLE_NAME,
+    c.COLUMN_NAME,
+    c.DATA_TYPE_NAME AS data_type,
+    c.IS_NULLABLE,
+    c.COMMENTS AS column_comment,
+    t.COMMENTS AS table_comment,
+    '' AS collation_name
+FROM SYS.TABLE_COLUMNS c
+JOIN SYS.TABLES t
+    ON t.SCHEMA_NAME = c.SCHEMA_NAME
+    AND t.TABLE_NAME = c.TABLE_NAME
+WHERE c.SCHEMA_NAME = %[1]s
+AND t.IS_USER_DEFINED_TYPE = 'FALSE'
+ORDER BY c.TABLE_NAME, c.POSITION;`, schemaFilter),
+		constraints: fmt.Sprintf(`
+SELECT
+    k.CONSTRAINT_NAME,
+    CASE WHEN k.IS_PRIMARY_KEY = 'TRUE' THEN 'PRIMARY KEY' ELSE 'UNIQUE' END AS constraint_type,
+    k.SCHEMA_NAME,
+    k.TABLE_NAME,
+    k.COLUMN_NAME,
+    CAST(NULL AS NVARCHAR(256)) AS referenced_table_schema,
+    CAST(NULL AS NVARCHAR(256)) AS referenced_table_name,
+    CAST(NULL AS NVARCHAR(256)) AS referenced_column_name,
+    k.POSITION
+FROM SYS.CONSTRAINTS k
+WHERE k.SCHEMA_NAME = %[1]s
+AND (k.IS_PRIMARY_KEY = 'TRUE' OR k.IS_UNIQUE_KEY = 'TRUE')
+UNION ALL
+SELECT
+    r.CONSTRAINT_NAME,
+    'FOREIGN KEY',
+    r.SCHEMA_NAME,
+    r.TABLE_NAME,
+    r.COLUMN_NAME,
+    r.REFERENCED_SCHEMA_NAME,
+    r.REFERENCED_TABLE_NAME,
+    r.REFERENCED_COLUMN_NAME,
+    r.POSITION
+FROM SYS.REFERENTIAL_CONSTRAINTS r
+WHERE r.SCHEMA_NAME = %[1]s
+ORDER BY TABLE_NAME, CONSTRAINT_NAME, POSITION;`, schemaFilter),
+		indexes: fmt.Sprintf(`
+SELECT
+    i.SCHEMA_NAME,
+    i.TABLE_NAME,
+    i.INDEX_NAME,
+    CASE WHEN i."CONSTRAINT" IN ('UNIQUE', 'NOT NULL UNIQUE', 'PRIMARY KEY') THEN 'TRUE' ELSE 'FALSE' END AS is_unique,
+    CASE WHEN i."CONSTRAINT" = 'PRIMARY KEY' THEN 'TRUE' ELSE 'FALSE' END AS is_primary,
+    c.COLUMN_NAME
+FROM SYS.INDEXES i
+JOIN SYS.INDEX_COLUMNS c
+    ON c.SCHEMA_NAME = i.SCHEMA_NAME
+    AND c.TABLE_NAME = i.TABLE_NAME
+    AND c.INDEX_NAME = i.INDEX_NAME
+WHERE i.SCHEMA_NAME = %[1]s
+ORDER BY i.TABLE_NAME, i.INDEX_NAME, c.POSITION;`, schemaFilter),
+	}
+}
+
+// getFirebirdSchemaMetadataQueries returns the metadata queries for a Firebird database,
+// whose tables have no schema; key columns come from the segments of the index enforcing
+// each constraint
+func getFirebirdSchemaMetadataQueries() schemaMetadataQueries {
+	return schemaMetadataQueries{
+		columns: `
+SELECT
+    '' AS table_schema,
+    TRIM(rf.RDB$RELATION_NAME) AS table_name,
+    TRIM(rf.RDB$FIELD_NAME) AS column_name,
+    ` + firebirdTypeExpression("f") + ` AS data_type,
+    CASE WHEN COALESCE(rf.RDB$NULL_FLAG, f.RDB$NULL_FLAG, 0) = 1 THEN 'NO' ELSE 'YES' END AS is_nullable,
+    CAST(rf.RDB$DESCRIPTION AS VARCHAR(8191)) AS column_comment,
+    CAST(r.RDB$DESCRIPTION AS VARCHAR(8191)) AS table_comment,
+    TRIM(cl.RDB$COLLATION_NAME) AS collation_name
+FROM RDB$RELATION_FIELDS rf
+JOIN RDB$RELATIONS r ON r.RDB$RELATION_NAME = rf.RDB$RELATION_NAME
+JOIN RDB$FIELDS f ON f.RDB$FIELD_NAME = rf.RDB$FIELD_SOURCE
+LEFT JOIN RDB$COLLATIONS cl
+    ON cl.RDB$COLLATION_ID = COALESCE(rf.RDB$COLLATION_ID, f.RDB$COLLATION_ID)
+    AND cl.RDB$CHARACTER_SET_ID = f.RDB$CHARACTER_SET_ID
+WHERE COALESCE(r.RDB$SYSTEM_FLAG, 0) = 0
+AND r.RDB$VIEW_BLR IS NULL
+ORDER BY rf.RDB$RELATION_NAME, rf.RDB$FIELD_POSITION;`,
+		constraints: `
+SELECT
+    TRIM(c.RDB$CONSTRAINT_NAME) AS constraint_name,
+    TRIM(c.RDB$CONSTRAINT_TYPE) AS constraint_type,
+    '' AS table_schema,
+    TRIM(c.RDB$RELATION_NAME) AS table_name,
+    TRIM(s.RDB$FIELD_NAME) AS column_name,
+    CASE WHEN uq.RDB$RELATION_NAME IS NULL THEN NULL ELSE '' END AS referenced_table_schema,
+    TRIM(uq.RDB$RELATION_NAME) AS referenced_table_name,
+    TRIM(us.RDB$FIELD_NAME) AS referenced_column_name,
+    s.RDB$FIELD_POSITION AS position
+FROM RDB$RELATION_CONSTRAINTS c
+JOIN RDB$INDEX_SEGMENTS s ON s.RDB$INDEX_NAME = c.RDB$INDEX_NAME
+LEFT JOIN RDB$REF_CONSTRAINTS rc ON rc.RDB$CONSTRAINT_NAME = c.RDB$CONSTRAINT_NAME
+LEFT JOIN RDB$RELATION_CONSTRAINTS uq ON uq.RDB$CONSTRAINT_NAME = rc.RDB$CONST_NAME_UQ
+LEFT JOIN RDB$INDEX_SEGMENTS us
+    ON us.RDB$INDEX_NAME = uq.RDB$INDEX_NAME
+    AND us.RDB$FIELD_POSITION = s.RDB$FIELD_POSITION
+WHERE c.RDB$CONSTRAINT_TYPE IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY')
+ORDER BY c.RDB$RELATION_NAME, c.RDB$CONSTRAINT_NAME, s.RDB$FIELD_POSITION;`,
+		indexes: `
+SELECT
+    '' AS table_schema,
+    TRIM(i.RDB$RELATION_NAME) AS table_name,
+    TRIM(i.RDB$INDEX_NAME) AS index_name,
+    CASE WHEN i.RDB$UNIQUE_FLAG = 1 THEN 'TRUE' ELSE 'FALSE' END AS is_unique,
+    CASE WHEN c.RDB$CONSTRAINT_TYPE = 'PRIMARY KEY' THEN 'TRUE' ELSE 'FALSE' END AS is_primary,
+    TRIM(s.RDB$FIELD_NAME) AS column_name
+FROM RDB$INDICES i
+JOIN RDB$INDEX_SEGMENTS s ON s.RDB$INDEX_NAME = i.RDB$INDEX_NAME
+LEFT JOIN RDB$RELATION_CONSTRAINTS c ON c.RDB$INDEX_NAME = i.RDB$INDEX_NAME
+WHERE COALESCE(i.RDB$SYSTEM_FLAG, 0) = 0
+ORDER BY i.RDB$RELATION_NAME, i.RDB$INDEX_NAME, s.RDB$FIELD_POSITION;`,
+	}
+}
+
+// getDatabricksSchemaMetadataQueries returns the metadata queries for a schema of the
+// catalog of the connection; an empty schema reads the default schema. The keys of Unity
+// Catalog are informational, and Delta tables have no indexes, so that query returns no rows.
+func getDatabricksSchemaMetadataQueries(schema string) schemaMetadataQueries {
+	// The key and index queries take no parameters, so the schema is quoted rather than bound
+	schemaFilter := "current_schema()"
+	if schema != "" {
+		schemaFilter = quoteLiteral("databricks", schema)
+	}
+
+	return schemaMetadataQueries{
+		columns: fmt.Sprintf(`
+SELECT
+    c.table_schema,
+    c.table_name,
+    c.column_name,
+    c.full_data_type AS data_type,
+    c.is_nullable,
+    c.comment AS column_comment,
+    t.comment AS table_comment,
+    '' AS collation_name
+FROM information_schema.columns c
+JOIN information_schema.tables t
+    ON t.table_schema = c.table_schema
+    AND t.table_name = c.table_name
+WHERE c.table_schema = %s
+AND t.table_type NOT IN ('VIEW', 'MATERIALIZED_VIEW')
+ORDER BY c.table_name, c.ordinal_position;`, schemaFilter),
+		constraints: fmt.Sprintf(`
+SELECT
+    tc.constraint_name,
+    tc.constraint_type,
+    kcu.table_schema,
+    kcu.table_name,
+    kcu.column_name,
+    uk.table_schema AS referenced_table_schema,
+    uk.table_name AS referenced_table_name,
+    uk.column_name AS referenced_column_name
+FROM information_schema.table_constraints tc
+JOIN information_schema.key_column_usage kcu
+    ON kcu.constraint_schema = tc.constraint_schema
+    AND kcu.constraint_name = tc.constraint_name
+LEFT JOIN information_schema.referential_constraints rc
+    ON rc.constraint_schema = tc.constraint_schema
+    AND rc.constraint_name = tc.constraint_name
+LEFT JOIN information_schema.key_column_usage uk
+    ON uk.constraint_schema = rc.unique_constraint_schema
+    AND uk.constraint_name = rc.unique_constraint_name
+    AND uk.ordinal_position = kcu.position_in_unique_constraint
+WHERE tc.table_schema = %s
+AND tc.constraint_type IN ('PRIMARY KEY', 'FOREIGN KEY')
+ORDER BY kcu.table_name, tc.constraint_name, kcu.ordinal_position;`, schemaFilter),
+		indexes: `
+SELECT
+    '' AS table_schema,
+    '' AS table_name,
+    '' AS index_name,
+    false AS is_unique,
+    false AS is_primary,
+    '' AS column_name
+WHERE false;`,
+	}
+}
+
+// getTrinoSchemaMetadataQueries returns the metadata queries for a schema of the default
+// Trino catalog; an empty schema reads the default schema of the connection. Trino
+// connectors expose no keys or indexes, so those queries return no rows.
+func getTrinoSchemaMetadataQueries(schema string) schemaMetadataQueries {
+	// The key and index queries take no parameters, so the schema is quoted rather than bound
+	schemaFilter := "current_schema"
+	if schema != "" {
+		schemaFilter = quoteLiteral("trino", schema)
+	}
+
+	return schemaMetadataQueries{
+		columns: fmt.Sprintf(`
+SELECT
+    c.table_schema,
+    c.table_name,
+    c.column_name,
+    c.data_type,
+    c.is_nullable,
+    c.comment AS column_comment,
+    tc.comment AS table_comment,
+    '' AS collation_name
+FROM information_schema.columns c
+JOIN information_schema.tables t
+    ON t.table_schema = c.table_schema
+    AND t.table_name = c.table_name
+LEFT JOIN system.metadata.table_comments tc
+    ON tc.catalog_name = c.table_catalog
+    AND tc.schema_name = c.table_schema
+    AND tc.table_name = c.table_name
+WHERE c.table_schema = %s
+AND t.table_type = 'BASE TABLE'
+ORDER BY c.table_name, c.ordinal_position;`, schemaFilter),
+		constraints: `
+SELECT
+    '' AS constraint_name,
+    '' AS constraint_type,
+    '' AS table_schema,
+    '' AS table_name,
+    '' AS column_name,
+    '' AS referenced_table_schema,
+    '' AS referenced_table_name,
+    '' AS referenced_column_name
+WHERE false;`,
+		indexes: `
+SELECT
+    '' AS table_schema,
+    '' AS table_name,
+    '' AS index_name,
+    false AS is_unique,
+    false AS is_primary,
+    '' AS column_name
+WHERE false;`,
+	}
+}
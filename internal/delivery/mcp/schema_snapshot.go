@@ -0,0 +1,381 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// snapshotNamePattern restricts snapshot names to safe file names
+var snapshotNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// schemaSnapshot is a saved copy of the structure of a schema
+type schemaSnapshot struct {
+	Name      string          `json:"name"`
+	Database  string          `json:"database"`
+	CreatedAt time.Time       `json:"created_at"`
+	Schema    *schemaMetadata `json:"schema"`
+}
+
+// snapshotsDir returns the configured snapshots directory of a database
+func snapshotsDir(useCase UseCaseProvider, dbID string) (string, error) {
+	config, err := useCase.GetDatabaseConfig(dbID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get database configuration: %w", err)
+	}
+	if config.SnapshotsDir == "" {
+		return "", fmt.Errorf("no snapshots directory configured for database %s", dbID)
+	}
+	return config.SnapshotsDir, nil
+}
+
+// saveSnapshot writes a snapshot as <name>.json with its canonical DDL next to it as <name>.sql
+func saveSnapshot(dir string, snapshot *schemaSnapshot, overwrite bool) (string, string, error) {
+	if !snapshotNamePattern.MatchString(snapshot.Name) {
+		return "", "", fmt.Errorf("invalid snapshot name %q: use letters, digits, '.', '_' and '-'", snapshot.Name)
+	}
+	jsonPath := filepath.Join(dir, snapshot.Name+".json")
+	ddlPath := filepath.Join(dir, snapshot.Name+".sql")
+	if _, err := os.Stat(jsonPath); err == nil && !overwrite {
+		return "", "", fmt.Errorf("snapshot %s already exists (set overwrite to replace it)", snapshot.Name)
+	}
+
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", fmt.Errorf("failed to create snapshots directory %s: %w", dir, err)
+	}
+	if err := os.WriteFile(jsonPath, append(content, '\n'), 0o644); err != nil {
+		return "", "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.WriteFile(ddlPath, []byte(renderSchemaDDL(snapshot.Schema)), 0o644); err != nil {
+		return "", "", fmt.Errorf("failed to write snapshot DDL: %w", err)
+	}
+	return jsonPath, ddlPath, nil
+}
+
+// listSnapshots reads the snapshots of a directory, oldest first; a missing directory has none
+func listSnapshots(dir string) ([]*schemaSnapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshots directory %s: %w", dir, err)
+	}
+
+	var snapshots []*schemaSnapshot
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		snapshot, err := readSnapshot(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt) })
+	return snapshots, nil
+}
+
+// loadSnapshot reads a snapshot by name, or the most recent one when name is empty
+func loadSnapshot(dir, name string) (*schemaSnapshot, error) {
+	if name != "" {
+		if !snapshotNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid snapshot name %q", name)
+		}
+		return readSnapshot(filepath.Join(dir, name+".json"))
+	}
+	snapshots, err := listSnapshots(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no snapshots found in %s", dir)
+	}
+	return snapshots[len(snapshots)-1], nil
+}
+
+// readSnapshot decodes a snapshot file
+func readSnapshot(path string) (*schemaSnapshot, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", path, err)
+	}
+	var snapshot schemaSnapshot
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot %s: %w", path, err)
+	}
+	if snapshot.Schema == nil {
+		return nil, fmt.Errorf("snapshot %s has no schema", path)
+	}
+	return &snapshot, nil
+}
+
+// renderSchemaDDL renders the schema as canonical DDL: tables and indexes ordered by name,
+// columns in their table order, and foreign keys last so the script runs in one pass
+func renderSchemaDDL(meta *schemaMetadata) string {
+	quote := func(ident string) string { return quoteIdentifier(meta.DatabaseType, ident) }
+	quoteList := func(idents []string) string {
+		quoted := make([]string, len(idents))
+		for i, ident := range idents {
+			quoted[i] = quote(ident)
+		}
+		return strings.Join(quoted, ", ")
+	}
+
+	tables := append([]*schemaTable(nil), meta.Tables...)
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Name < tables[j].Name })
+
+	var sb strings.Builder
+	for _, table := range tables {
+		sb.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", quote(table.Name)))
+		lines := make([]string, 0, len(table.Columns)+1)
+		for _, col := range table.Columns {
+			lines = append(lines, fmt.Sprintf("  %s %s%s", quote(col.Name), col.DataType, notNullSuffix(col)))
+		}
+		if len(table.PrimaryKey) > 0 {
+			lines = append(lines, fmt.Sprintf("  PRIMARY KEY (%s)", quoteList(table.PrimaryKey)))
+		}
+		sb.WriteString(strings.Join(lines, ",\n"))
+		sb.WriteString("\n);\n")
+
+		indexes := append([]schemaIndex(nil), table.Indexes...)
+		sort.Slice(indexes, func(i, j int) bool { return indexes[i].Name < indexes[j].Name })
+		for _, index := range indexes {
+			if index.Primary {
+				continue
+			}
+			unique := ""
+			if index.Unique {
+				unique = "UNIQUE "
+			}
+			sb.WriteString(fmt.Sprintf("CREATE %sINDEX %s ON %s (%s);\n", unique, quote(index.Name), quote(table.Name), quoteList(index.Columns)))
+		}
+		sb.WriteString("\n")
+	}
+
+	foreignKeys := append([]schemaForeignKey(nil), meta.ForeignKeys...)
+	sort.Slice(foreignKeys, func(i, j int) bool {
+		if foreignKeys[i].Table != foreignKeys[j].Table {
+			return foreignKeys[i].Table < foreignKeys[j].Table
+		}
+		return foreignKeys[i].Name < foreignKeys[j].Name
+	})
+	for _, fk := range foreignKeys {
+		ref := fk.RefTable
+		if fk.RefSchema != "" && fk.RefSchema != fk.Schema {
+			ref = fk.RefSchema + "." + fk.RefTable
+		}
+		sb.WriteString(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s);\n",
+			quote(fk.Table), quote(fk.Name), quoteList(fk.Columns), quote(ref), quoteList(fk.RefColumns)))
+	}
+	return sb.String()
+}
+
+// tableDiff lists the changes of one table present in both schemas
+type tableDiff struct {
+	Table   string
+	Changes []string
+}
+
+// schemaDiff is the difference between two versions of a schema
+type schemaDiff struct {
+	AddedTables        []*schemaTable
+	RemovedTables      []*schemaTable
+	ChangedTables      []tableDiff
+	AddedForeignKeys   []schemaForeignKey
+	RemovedForeignKeys []schemaForeignKey
+}
+
+// empty reports whether the schemas are identical
+func (d *schemaDiff) empty() bool {
+	return len(d.AddedTables) == 0 && len(d.RemovedTables) == 0 && len(d.ChangedTables) == 0 &&
+		len(d.AddedForeignKeys) == 0 && len(d.RemovedForeignKeys) == 0
+}
+
+// diffSchemaMetadata compares two versions of a schema, tables matched by name
+func diffSchemaMetadata(before, after *schemaMetadata) *schemaDiff {
+	diff := &schemaDiff{}
+	beforeTables := make(map[string]*schemaTable, len(before.Tables))
+	for _, t := range before.Tables {
+		beforeTables[t.Name] = t
+	}
+	afterTables := make(map[string]*schemaTable, len(after.Tables))
+	for _, t := range after.Tables {
+		afterTables[t.Name] = t
+	}
+
+	for _, t := range after.Tables {
+		old, ok := beforeTables[t.Name]
+		if !ok {
+			diff.AddedTables = append(diff.AddedTables, t)
+			continue
+		}
+		if changes := diffSchemaTable(old, t); len(changes) > 0 {
+			diff.ChangedTables = append(diff.ChangedTables, tableDiff{Table: t.Name, Changes: changes})
+		}
+	}
+	for _, t := range before.Tables {
+		if _, ok := afterTables[t.Name]; !ok {
+			diff.RemovedTables = append(diff.RemovedTables, t)
+		}
+	}
+
+	beforeKeys := make(map[string]bool, len(before.ForeignKeys))
+	for _, fk := range before.ForeignKeys {
+		beforeKeys[foreignKeySignature(fk)] = true
+	}
+	afterKeys := make(map[string]bool, len(after.ForeignKeys))
+	for _, fk := range after.ForeignKeys {
+		afterKeys[foreignKeySignature(fk)] = true
+		if !beforeKeys[foreignKeySignature(fk)] {
+			diff.AddedForeignKeys = append(diff.AddedForeignKeys, fk)
+		}
+	}
+	for _, fk := range before.ForeignKeys {
+		if !afterKeys[foreignKeySignature(fk)] {
+			diff.RemovedForeignKeys = append(diff.RemovedForeignKeys, fk)
+		}
+	}
+
+	sort.Slice(diff.AddedTables, func(i, j int) bool { return diff.AddedTables[i].Name < diff.AddedTables[j].Name })
+	sort.Slice(diff.RemovedTables, func(i, j int) bool { return diff.RemovedTables[i].Name < diff.RemovedTables[j].Name })
+	sort.Slice(diff.ChangedTables, func(i, j int) bool { return diff.ChangedTables[i].Table < diff.ChangedTables[j].Table })
+	return diff
+}
+
+// diffSchemaTable lists the column, key and index changes of a table
+func diffSchemaTable(before, after *schemaTable) []string {
+	var changes []string
+	for _, col := range after.Columns {
+		old := before.column(col.Name)
+		if old == nil {
+			changes = append(changes, fmt.Sprintf("Column %s added: %s%s", col.Name, col.DataType, notNullSuffix(col)))
+			continue
+		}
+		if old.DataType != col.DataType {
+			changes = append(changes, fmt.Sprintf("Column %s type changed: %s -> %s", col.Name, old.DataType, col.DataType))
+		}
+		if old.Nullable != col.Nullable {
+			changes = append(changes, fmt.Sprintf("Column %s nullability changed: %s -> %s", col.Name, nullability(*old), nullability(col)))
+		}
+		if old.Collation != col.Collation {
+			changes = append(changes, fmt.Sprintf("Column %s collation changed: %s -> %s", col.Name, old.Collation, col.Collation))
+		}
+		if old.Comment != col.Comment {
+			changes = append(changes, fmt.Sprintf("Column %s comment changed", col.Name))
+		}
+	}
+	for _, col := range before.Columns {
+		if after.column(col.Name) == nil {
+			changes = append(changes, fmt.Sprintf("Column %s removed", col.Name))
+		}
+	}
+
+	if strings.Join(before.PrimaryKey, ",") != strings.Join(after.PrimaryKey, ",") {
+		changes = append(changes, fmt.Sprintf("Primary key changed: (%s) -> (%s)", strings.Join(before.PrimaryKey, ", "), strings.Join(after.PrimaryKey, ", ")))
+	}
+	if before.Comment != after.Comment {
+		changes = append(changes, "Table comment changed")
+	}
+
+	beforeIndexes := make(map[string]schemaIndex, len(before.Indexes))
+	for _, index := range before.Indexes {
+		beforeIndexes[index.Name] = index
+	}
+	afterIndexes := make(map[string]bool, len(after.Indexes))
+	for _, index := range after.Indexes {
+		afterIndexes[index.Name] = true
+		if index.Primary {
+			continue
+		}
+		old, ok := beforeIndexes[index.Name]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("Index %s added: %s", index.Name, describeIndex(index)))
+		case old.Unique != index.Unique || strings.Join(old.Columns, ",") != strings.Join(index.Columns, ","):
+			changes = append(changes, fmt.Sprintf("Index %s changed: %s -> %s", index.Name, describeIndex(old), describeIndex(index)))
+		}
+	}
+	for _, index := range before.Indexes {
+		if !index.Primary && !afterIndexes[index.Name] {
+			changes = append(changes, fmt.Sprintf("Index %s removed", index.Name))
+		}
+	}
+	return changes
+}
+
+// describeIndex renders an index as "UNIQUE (a, b)" or "(a, b)"
+func describeIndex(index schemaIndex) string {
+	text := "(" + strings.Join(index.Columns, ", ") + ")"
+	if index.Unique {
+		text = "UNIQUE " + text
+	}
+	return text
+}
+
+// nullability renders whether a column accepts NULL
+func nullability(col schemaColumn) string {
+	if col.Nullable {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
+
+// foreignKeySignature identifies a foreign key by its columns, so a renamed constraint is not a change
+func foreignKeySignature(fk schemaForeignKey) string {
+	return fmt.Sprintf("%s(%s) -> %s(%s)", fk.Table, strings.Join(fk.Columns, ", "), fk.RefTable, strings.Join(fk.RefColumns, ", "))
+}
+
+// formatSchemaDiff renders a schema diff grouped by kind of change
+func formatSchemaDiff(diff *schemaDiff) string {
+	if diff.empty() {
+		return "No changes: the live schema matches the snapshot.\n"
+	}
+
+	var sb strings.Builder
+	if len(diff.AddedTables) > 0 {
+		sb.WriteString("## Added Tables\n\n")
+		for _, t := range diff.AddedTables {
+			sb.WriteString(fmt.Sprintf("- %s (%d columns)\n", t.Name, len(t.Columns)))
+		}
+		sb.WriteString("\n")
+	}
+	if len(diff.RemovedTables) > 0 {
+		sb.WriteString("## Removed Tables\n\n")
+		for _, t := range diff.RemovedTables {
+			sb.WriteString(fmt.Sprintf("- %s\n", t.Name))
+		}
+		sb.WriteString("\n")
+	}
+	if len(diff.ChangedTables) > 0 {
+		sb.WriteString("## Changed Tables\n\n")
+		for _, t := range diff.ChangedTables {
+			sb.WriteString(fmt.Sprintf("### %s\n\n", t.Table))
+			for _, change := range t.Changes {
+				sb.WriteString("- " + change + "\n")
+			}
+			sb.WriteString("\n")
+		}
+	}
+	if len(diff.AddedForeignKeys) > 0 || len(diff.RemovedForeignKeys) > 0 {
+		sb.WriteString("## Foreign Keys\n\n")
+		for _, fk := range diff.AddedForeignKeys {
+			sb.WriteString(fmt.Sprintf("- Added %s: %s\n", fk.Name, foreignKeySignature(fk)))
+		}
+		for _, fk := range diff.RemovedForeignKeys {
+			sb.WriteString(fmt.Sprintf("- Removed %s: %s\n", fk.Name, foreignKeySignature(fk)))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testSnapshotSchema() *schemaMetadata {
+	return &schemaMetadata{
+		DatabaseType: "postgres",
+		Schema:       "public",
+		Tables: []*schemaTable{
+			{
+				Schema:     "public",
+				Name:       "users",
+				Columns:    []schemaColumn{{Name: "id", DataType: "integer"}, {Name: "email", DataType: "text", Nullable: true}},
+				PrimaryKey: []string{"id"},
+				Indexes: []schemaIndex{
+					{Name: "users_pkey", Columns: []string{"id"}, Unique: true, Primary: true},
+					{Name: "users_email_key", Columns: []string{"email"}, Unique: true},
+				},
+			},
+			{
+				Schema:     "public",
+				Name:       "orders",
+				Columns:    []schemaColumn{{Name: "id", DataType: "integer"}, {Name: "user_id", DataType: "integer"}},
+				PrimaryKey: []string{"id"},
+			},
+		},
+		ForeignKeys: []schemaForeignKey{{
+			Name: "orders_user_id_fkey", Schema: "public", Table: "orders", Columns: []string{"user_id"},
+			RefSchema: "public", RefTable: "users", RefColumns: []string{"id"},
+		}},
+	}
+}
+
+func TestRenderSchemaDDL(t *testing.T) {
+	assert.Equal(t, `CREATE TABLE "orders" (
+  "id" integer NOT NULL,
+  "user_id" integer NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE TABLE "users" (
+  "id" integer NOT NULL,
+  "email" text,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "users_email_key" ON "users" ("email");
+
+ALTER TABLE "orders" ADD CONSTRAINT "orders_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+`, renderSchemaDDL(testSnapshotSchema()))
+}
+
+func TestDiffSchemaMetadata(t *testing.T) {
+	before := testSnapshotSchema()
+	assert.True(t, diffSchemaMetadata(before, testSnapshotSchema()).empty())
+
+	after := testSnapshotSchema()
+	users := after.Tables[0]
+	users.Columns = []schemaColumn{{Name: "id", DataType: "bigint"}, {Name: "name", DataType: "text"}}
+	users.Indexes = append(users.Indexes[:1], schemaIndex{Name: "idx_users_name", Columns: []string{"name"}})
+	after.Tables = append(after.Tables[:1], &schemaTable{Name: "audit_log", Columns: []schemaColumn{{Name: "id", DataType: "integer"}}})
+	after.ForeignKeys = nil
+
+	diff := diffSchemaMetadata(before, after)
+	assert.Len(t, diff.AddedTables, 1)
+	assert.Equal(t, "orders", diff.RemovedTables[0].Name)
+	assert.Len(t, diff.RemovedForeignKeys, 1)
+	assert.Equal(t, []tableDiff{{Table: "users", Changes: []string{
+		"Column id type changed: integer -> bigint",
+		"Column name added: text NOT NULL",
+		"Column email removed",
+		"Index idx_users_name added: (name)",
+		"Index users_email_key removed",
+	}}}, diff.ChangedTables)
+
+	text := formatSchemaDiff(diff)
+	assert.Contains(t, text, "## Added Tables\n\n- audit_log (1 columns)\n")
+	assert.Contains(t, text, "## Removed Tables\n\n- orders\n")
+	assert.Contains(t, text, "### users\n\n- Column id type changed: integer -> bigint\n")
+	assert.Contains(t, text, "- Removed orders_user_id_fkey: orders(user_id) -> users(id)\n")
+}
@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// SearchIndexTool handles running query DSL searches on a search database
+type SearchIndexTool struct {
+	BaseToolType
+}
+
+// NewSearchIndexTool creates a new search index tool type
+func NewSearchIndexTool() *SearchIndexTool {
+	return &SearchIndexTool{
+		BaseToolType: BaseToolType{
+			name:        "search_index",
+			description: "Run an Elasticsearch or OpenSearch query DSL search on the indices matching a pattern and return the hits as a table, with the fields of every document's _source under dotted paths such as user.id. The request body may hold query, sort, _source, aggregations and other search parameters; its size is capped by limit. Aggregation results follow the table as JSON.",
+		},
+	}
+}
+
+// CreateTool creates a search index tool
+func (t *SearchIndexTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Run a query DSL search on Elasticsearch or OpenSearch indices"),
+		tools.WithString("database",
+			tools.Description("Database ID to search"),
+			tools.Required(),
+		),
+		tools.WithString("index",
+			tools.Description("Index or index pattern to search, such as logs-*"),
+			tools.Required(),
+		),
+		tools.WithString("query",
+			tools.Description(`Search request body as JSON, such as {"query": {"match": {"message": "timeout"}}, "sort": [{"@timestamp": "desc"}]} (default: match_all)`),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of hits to return (default: 20, max: 10000)"),
+		),
+	)
+}
+
+// HandleRequest handles search index tool requests
+func (t *SearchIndexTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	pattern := input.requiredString("index")
+	query := input.optionalString("query", `{"query": {"match_all": {}}}`)
+	limit := input.intBetween("limit", 20, 1, 10000)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	body, err := searchBody(query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := useCase.GetSearchStore(targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Searching %s in database %s: %s", pattern, targetDbID, body)
+	result, err := store.Search(ctx, pattern, body)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Search on %s in Database %s\n\n", pattern, targetDbID))
+	response.WriteString(searchSummary(result) + "\n\n")
+	if searchField(result, "timed_out") == true {
+		response.WriteString("The search timed out; the hits are partial.\n\n")
+	}
+	if failed, _ := searchInt(searchField(result, "_shards.failed")); failed > 0 {
+		response.WriteString(fmt.Sprintf("%d shards failed; the hits are partial.\n\n", failed))
+	}
+
+	hits, _ := searchField(result, "hits.hits").([]interface{})
+	response.WriteString(formatHits(hits))
+
+	if aggregations, ok := result["aggregations"]; ok {
+		raw, err := json.MarshalIndent(aggregations, "", "  ")
+		if err == nil {
+			response.WriteString("\n## Aggregations\n\n```json\n" + string(raw) + "\n```\n")
+		}
+	}
+	return createTextResponse(response.String()), nil
+}
+
+// searchBody checks that a search request body is a JSON object and caps its size at limit
+func searchBody(query string, limit int) (string, error) {
+	decoder := json.NewDecoder(strings.NewReader(query))
+	decoder.UseNumber()
+	var body map[string]interface{}
+	if err := decoder.Decode(&body); err != nil || body == nil {
+		return "", fmt.Errorf("query must be a JSON object such as {\"query\": {\"match_all\": {}}}")
+	}
+	if size, ok := searchInt(body["size"]); !ok || size > int64(limit) {
+		body["size"] = limit
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(body); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// searchSummary tells how many documents matched and how long the search took
+func searchSummary(result map[string]interface{}) string {
+	total := searchField(result, "hits.total")
+	matched := searchCount(total)
+	if object, ok := total.(map[string]interface{}); ok {
+		matched = searchCount(object["value"])
+		if object["relation"] == "gte" {
+			matched = "at least " + matched
+		}
+	}
+	took := searchCount(result["took"])
+	return fmt.Sprintf("Matched: %s documents, took %s ms", matched, took)
+}
+
+// formatHits formats hits as a table with their index when they come from several, their id
+// and score, and the fields of their _source in order of first appearance
+func formatHits(hits []interface{}) string {
+	if len(hits) == 0 {
+		return "No documents matched.\n"
+	}
+	var columns []string
+	seen := map[string]bool{}
+	indices := map[string]bool{}
+	rows := make([]map[string]string, 0, len(hits))
+	for _, hit := range hits {
+		object, _ := hit.(map[string]interface{})
+		row := map[string]string{
+			"_index": searchText(object["_index"]),
+			"_id":    searchText(object["_id"]),
+			"_score": searchText(object["_score"]),
+		}
+		indices[row["_index"]] = true
+		source, _ := object["_source"].(map[string]interface{})
+		for _, path := range flattenSource(source, "", row) {
+			if !seen[path] {
+				seen[path] = true
+				columns = append(columns, path)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	columns = append([]string{"_id", "_score"}, columns...)
+	if len(indices) > 1 {
+		columns = append([]string{"_index"}, columns...)
+	}
+	var sb strings.Builder
+	sb.WriteString("| " + strings.Join(columns, " | ") + " |\n")
+	sb.WriteString("|" + strings.Repeat("---|", len(columns)) + "\n")
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = hitCell(row[column])
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	sb.WriteString(fmt.Sprintf("\n%d hits\n", len(rows)))
+	return sb.String()
+}
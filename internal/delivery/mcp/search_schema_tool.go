@@ -0,0 +1,361 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// SearchSchemaTool handles searching tables and columns by name or comment
+type SearchSchemaTool struct {
+	BaseToolType
+}
+
+// NewSearchSchemaTool creates a new search schema tool type
+func NewSearchSchemaTool() *SearchSchemaTool {
+	return &SearchSchemaTool{
+		BaseToolType: BaseToolType{
+			name:        "search_schema",
+			description: "Find tables and columns whose name matches a pattern or whose comment contains a keyword, across all schemas of a database or across all configured databases. Patterns are case-insensitive substrings and may use * and ? wildcards, or case-insensitive regular expressions when regex is true. Use this tool to answer questions like \"where is the customer email stored?\" in a single call instead of browsing schemas table by table.",
+		},
+	}
+}
+
+// CreateTool creates a search schema tool
+func (t *SearchSchemaTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Find tables and columns by name pattern or comment keyword"),
+		tools.WithString("database",
+			tools.Description("Database ID to use (not needed when all_databases is true)"),
+		),
+		tools.WithString("pattern",
+			tools.Description("Table or column name pattern, case-insensitive substring with optional * and ? wildcards"),
+		),
+		tools.WithString("comment",
+			tools.Description("Keyword to look for in table and column comments"),
+		),
+		tools.WithBoolean("regex",
+			tools.Description("Treat pattern and comment as case-insensitive regular expressions instead of substrings (default: false)"),
+		),
+		tools.WithBoolean("all_databases",
+			tools.Description("Search every configured database (default: false)"),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of matches to list per database (default: 100)"),
+		),
+	)
+}
+
+// HandleRequest handles search schema tool requests
+func (t *SearchSchemaTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	allDatabases := input.optionalBool("all_databases", false)
+	var dbIDs []string
+	if allDatabases {
+		dbIDs = useCase.ListDatabases()
+	} else {
+		dbIDs = []string{input.requiredString("database")}
+	}
+	search := schemaSearch{
+		pattern: strings.TrimSpace(input.optionalString("pattern", "")),
+		comment: strings.TrimSpace(input.optionalString("comment", "")),
+		regex:   input.optionalBool("regex", false),
+	}
+	limit := input.intAtLeast("limit", 100, 1)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if search.pattern == "" && search.comment == "" {
+		return nil, fmt.Errorf("either pattern or comment must be provided")
+	}
+	if search.regex {
+		for _, expr := range []string{search.pattern, search.comment} {
+			if _, err := regexp.Compile(expr); err != nil {
+				return nil, fmt.Errorf("invalid regular expression %q: %w", expr, err)
+			}
+		}
+	}
+
+	logger.Info("Searching schema of databases %v for pattern %q, comment %q", dbIDs, search.pattern, search.comment)
+
+	// Format the response
+	var response strings.Builder
+	response.WriteString("# Schema Search Results\n\n")
+	kind := ""
+	if search.regex {
+		kind = " (regular expression)"
+	}
+	if search.pattern != "" {
+		response.WriteString(fmt.Sprintf("Name pattern%s: `%s`\n", kind, search.pattern))
+	}
+	if search.comment != "" {
+		response.WriteString(fmt.Sprintf("Comment keyword%s: `%s`\n", kind, search.comment))
+	}
+
+	total := 0
+	for _, id := range dbIDs {
+		response.WriteString(fmt.Sprintf("\n## Database %s\n\n", id))
+		matches, err := search.run(ctx, useCase, id)
+		if err != nil {
+			// A single failing database must not hide results from the others
+			if !allDatabases {
+				return nil, fmt.Errorf("failed to search schema: %w", err)
+			}
+			logger.Warn("Error searching schema of database %s: %v", id, err)
+			response.WriteString(fmt.Sprintf("Search failed: %v\n", err))
+			continue
+		}
+		total += len(matches)
+		response.WriteString(formatSchemaMatches(matches, limit))
+	}
+
+	if len(dbIDs) > 1 {
+		response.WriteString(fmt.Sprintf("\nTotal matches: %d across %d databases\n", total, len(dbIDs)))
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// schemaMatch is a table or column that matched a schema search
+type schemaMatch struct {
+	Schema   string
+	Table    string
+	Column   string // empty for a table-level match
+	DataType string
+	Comment  string
+	Reason   string
+}
+
+// schemaSearch holds the criteria of a schema search
+type schemaSearch struct {
+	pattern string
+	comment string
+	regex   bool // pattern and comment are regular expressions rather than wildcard substrings
+}
+
+// likePattern converts a search pattern into a substring LIKE pattern, escaping LIKE
+// wildcards in the input and translating * and ? into their LIKE equivalents
+func likePattern(pattern string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(strings.ToLower(pattern))
+	escaped = strings.NewReplacer("*", "%", "?", "_").Replace(escaped)
+	return "%" + escaped + "%"
+}
+
+// patternRegexp compiles a search pattern into an equivalent case-insensitive regexp
+func patternRegexp(pattern string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(pattern)
+	quoted = strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(quoted)
+	return regexp.MustCompile("(?is)" + quoted)
+}
+
+// matcher compiles a search term into the case-insensitive regexp its matches are checked with
+func (s schemaSearch) matcher(term string) *regexp.Regexp {
+	if s.regex {
+		return regexp.MustCompile("(?is)" + term)
+	}
+	return patternRegexp(term)
+}
+
+// sqlTerm returns the value bound for a search term: the regular expression itself, or
+// the LIKE pattern of a wildcard substring
+func (s schemaSearch) sqlTerm(term string) string {
+	if s.regex {
+		return term
+	}
+	return likePattern(term)
+}
+
+// mysqlMatch returns a case-insensitive MySQL condition matching a column against a bound term
+func (s schemaSearch) mysqlMatch(column, param string) string {
+	if s.regex {
+		return fmt.Sprintf("REGEXP_LIKE(%s, %s, 'i')", column, param)
+	}
+	return fmt.Sprintf("LOWER(%s) LIKE %s", column, param)
+}
+
+// query builds the search query for the database type. Regular expressions are matched by
+// the database where it supports them; the rows are checked again with Go's regexp syntax.
+func (s schemaSearch) query(dbType string) (string, []interface{}, error) {
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported database type for schema search: %s", dbType)
+	}
+	build, ok := dialect.SchemaSearchQuery()
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported database type for schema search: %s", dbType)
+	}
+	params := newSQLParams(dbType)
+	return build(s, params), params.values, nil
+}
+
+// schemaSearchQuery builds the query returning the schema, table, column, type, column comment
+// and table comment of the columns a search may match, binding its terms to params
+type schemaSearchQuery func(s schemaSearch, params *sqlParams) string
+
+// postgresSchemaSearchQuery searches pg_class and pg_attribute, matching case-insensitively
+// with ILIKE or ~*
+func postgresSchemaSearchQuery(s schemaSearch, params *sqlParams) string {
+	var conditions []string
+	op := "ILIKE"
+	if s.regex {
+		op = "~*"
+	}
+	if s.pattern != "" {
+		p := params.add(s.sqlTerm(s.pattern))
+		conditions = append(conditions, fmt.Sprintf("c.relname %[1]s %[2]s OR a.attname %[1]s %[2]s", op, p))
+	}
+	if s.comment != "" {
+		p := params.add(s.sqlTerm(s.comment))
+		conditions = append(conditions, fmt.Sprintf("col_description(c.oid, a.attnum) %[1]s %[2]s OR obj_description(c.oid, 'pg_class') %[1]s %[2]s", op, p))
+	}
+	return fmt.Sprintf(`
+SELECT n.nspname, c.relname, a.attname, format_type(a.atttypid, a.atttypmod),
+       COALESCE(col_description(c.oid, a.attnum), ''), COALESCE(obj_description(c.oid, 'pg_class'), '')
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped
+WHERE c.relkind IN ('r', 'p', 'v', 'm', 'f')
+  AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND n.nspname NOT LIKE 'pg_toast%%'
+  AND (%s)
+ORDER BY n.nspname, c.relname, a.attnum;`, strings.Join(conditions, " OR "))
+}
+
+// mysqlSchemaSearchQuery searches information_schema.COLUMNS and TABLES
+func mysqlSchemaSearchQuery(s schemaSearch, params *sqlParams) string {
+	var conditions []string
+	if s.pattern != "" {
+		term := s.sqlTerm(s.pattern)
+		conditions = append(conditions, fmt.Sprintf("%s OR %s",
+			s.mysqlMatch("c.TABLE_NAME", params.add(term)), s.mysqlMatch("c.COLUMN_NAME", params.add(term))))
+	}
+	if s.comment != "" {
+		term := s.sqlTerm(s.comment)
+		conditions = append(conditions, fmt.Sprintf("%s OR %s",
+			s.mysqlMatch("c.COLUMN_COMMENT", params.add(term)), s.mysqlMatch("t.TABLE_COMMENT", params.add(term))))
+	}
+	return fmt.Sprintf(`
+SELECT c.TABLE_SCHEMA, c.TABLE_NAME, c.COLUMN_NAME, c.COLUMN_TYPE, c.COLUMN_COMMENT, t.TABLE_COMMENT
+FROM information_schema.COLUMNS c
+JOIN information_schema.TABLES t ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+WHERE c.TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+  AND (%s)
+ORDER BY c.TABLE_SCHEMA, c.TABLE_NAME, c.ORDINAL_POSITION;`, strings.Join(conditions, " OR "))
+}
+
+// sqliteSchemaSearchQuery searches sqlite_master with the columns of pragma_table_info
+func sqliteSchemaSearchQuery(s schemaSearch, params *sqlParams) string {
+	// SQLite has no comments, so a comment search matches nothing
+	conditions := []string{"0 = 1"}
+	if s.pattern != "" && s.regex {
+		// SQLite has no built-in REGEXP function, so every column is checked in Go
+		conditions = append(conditions, "1 = 1")
+	} else if s.pattern != "" {
+		like := likePattern(s.pattern)
+		conditions = append(conditions, fmt.Sprintf(`LOWER(m.name) LIKE %s ESCAPE '\' OR LOWER(p.name) LIKE %s ESCAPE '\'`, params.add(like), params.add(like)))
+	}
+	return fmt.Sprintf(`
+SELECT 'main', m.name, p.name, p.type, '', ''
+FROM sqlite_master m
+JOIN pragma_table_info(m.name) p
+WHERE m.type IN ('table', 'view')
+  AND m.name NOT LIKE 'sqlite_%%'
+  AND (%s)
+ORDER BY m.name, p.cid;`, strings.Join(conditions, " OR "))
+}
+
+// run searches one database and classifies each returned row as a table or column match
+func (s schemaSearch) run(ctx context.Context, useCase UseCaseProvider, dbID string) ([]schemaMatch, error) {
+	dbType, err := useCase.GetDatabaseType(dbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+
+	query, params, err := s.query(strings.ToLower(dbType))
+	if err != nil {
+		return nil, err
+	}
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.classify(result.Rows), nil
+}
+
+// classify turns search rows (schema, table, column, type, column comment, table comment)
+// into matches, reporting a table once when its name or comment matched
+func (s schemaSearch) classify(rows [][]interface{}) []schemaMatch {
+	var nameRe, commentRe *regexp.Regexp
+	if s.pattern != "" {
+		nameRe = s.matcher(s.pattern)
+	}
+	if s.comment != "" {
+		commentRe = s.matcher(s.comment)
+	}
+
+	var matches []schemaMatch
+	seenTables := make(map[string]bool)
+	for _, row := range rows {
+		if len(row) < 6 {
+			continue
+		}
+		schema, table, column := valueString(row[0]), valueString(row[1]), valueString(row[2])
+		dataType, columnComment, tableComment := valueString(row[3]), valueString(row[4]), valueString(row[5])
+
+		key := schemaTableKey(schema, table)
+		if !seenTables[key] {
+			var reasons []string
+			if nameRe != nil && nameRe.MatchString(table) {
+				reasons = append(reasons, "table name")
+			}
+			if commentRe != nil && commentRe.MatchString(tableComment) {
+				reasons = append(reasons, "table comment")
+			}
+			if len(reasons) > 0 {
+				seenTables[key] = true
+				matches = append(matches, schemaMatch{Schema: schema, Table: table, Comment: tableComment, Reason: strings.Join(reasons, ", ")})
+			}
+		}
+
+		var reasons []string
+		if nameRe != nil && nameRe.MatchString(column) {
+			reasons = append(reasons, "column name")
+		}
+		if commentRe != nil && commentRe.MatchString(columnComment) {
+			reasons = append(reasons, "column comment")
+		}
+		if len(reasons) > 0 {
+			matches = append(matches, schemaMatch{Schema: schema, Table: table, Column: column, DataType: dataType,
+				Comment: columnComment, Reason: strings.Join(reasons, ", ")})
+		}
+	}
+	return matches
+}
+
+// formatSchemaMatches renders schema search matches as a markdown table
+func formatSchemaMatches(matches []schemaMatch, limit int) string {
+	if len(matches) == 0 {
+		return "No matching tables or columns found.\n"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("| Schema | Table | Column | Type | Matched On | Comment |\n")
+	sb.WriteString("|--------|-------|--------|------|------------|---------|\n")
+	for i, match := range matches {
+		if limit > 0 && i >= limit {
+			sb.WriteString(fmt.Sprintf("\n... %d more matches not shown\n", len(matches)-limit))
+			break
+		}
+		comment := strings.ReplaceAll(strings.ReplaceAll(match.Comment, "|", "\\|"), "\n", " ")
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n",
+			match.Schema, match.Table, match.Column, match.DataType, match.Reason, comment))
+	}
+	return sb.String()
+}
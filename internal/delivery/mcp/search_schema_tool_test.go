@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestLikePattern(t *testing.T) {
+	assert.Equal(t, "%email%", likePattern("Email"))
+	assert.Equal(t, "%cust%%", likePattern("cust*"))
+	assert.Equal(t, `%user\_i_%`, likePattern("user_i?"))
+	assert.Equal(t, `%100\%%`, likePattern("100%"))
+}
+
+func TestSchemaSearchClassify(t *testing.T) {
+	search := schemaSearch{pattern: "email", comment: "contact"}
+	rows := [][]interface{}{
+		{"public", "customers", "id", "integer", "", "Customer contact details"},
+		{"public", "customers", "email", "text", "", "Customer contact details"},
+		{"public", "orders", "notify_to", "text", "Contact address for the order", ""},
+		{"public", "email_log", "id", "integer", "", ""},
+		{"public", "email_log", "sent_at", "timestamp", "", ""},
+	}
+
+	matches := search.classify(rows)
+	assert.Equal(t, []schemaMatch{
+		{Schema: "public", Table: "customers", Comment: "Customer contact details", Reason: "table comment"},
+		{Schema: "public", Table: "customers", Column: "email", DataType: "text", Reason: "column name"},
+		{Schema: "public", Table: "orders", Column: "notify_to", DataType: "text", Comment: "Contact address for the order", Reason: "column comment"},
+		{Schema: "public", Table: "email_log", Reason: "table name"},
+	}, matches)
+}
+
+func TestSchemaSearchQueryBindsPatterns(t *testing.T) {
+	query, params, err := schemaSearch{pattern: "email"}.query("mysql")
+	assert.NoError(t, err)
+	assert.Contains(t, query, "LOWER(c.TABLE_NAME) LIKE ? OR LOWER(c.COLUMN_NAME) LIKE ?")
+	assert.Equal(t, []interface{}{"%email%", "%email%"}, params)
+
+	query, params, err = schemaSearch{pattern: "email", comment: "mail"}.query("postgres")
+	assert.NoError(t, err)
+	assert.Contains(t, query, "c.relname ILIKE $1 OR a.attname ILIKE $1")
+	assert.Contains(t, query, "ILIKE $2")
+	assert.Equal(t, []interface{}{"%email%", "%mail%"}, params)
+}
+
+func TestSearchSchemaToolRequiresCriteria(t *testing.T) {
+	_, err := NewSearchSchemaTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1"},
+	}, "mysql1", &mockUseCase{dbType: "mysql"})
+	assert.Error(t, err)
+}
+
+func TestSearchSchemaToolAllDatabases(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"information_schema.COLUMNS": {Rows: [][]interface{}{{"shop", "customers", "email", "varchar(255)", "", ""}}},
+		},
+	}
+
+	result, err := NewSearchSchemaTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"pattern": "email", "all_databases": true},
+	}, "", useCase)
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Len(t, useCase.queries, len(useCase.ListDatabases()))
+}
+
+func TestSchemaSearchRegex(t *testing.T) {
+	search := schemaSearch{pattern: "^(e|billing_)mail$", regex: true}
+	query, params, err := search.query("postgres")
+	assert.NoError(t, err)
+	assert.Contains(t, query, "c.relname ~* $1 OR a.attname ~* $1")
+	assert.Equal(t, []interface{}{"^(e|billing_)mail$"}, params)
+
+	query, params, err = search.query("mysql")
+	assert.NoError(t, err)
+	assert.Contains(t, query, "REGEXP_LIKE(c.TABLE_NAME, ?, 'i') OR REGEXP_LIKE(c.COLUMN_NAME, ?, 'i')")
+	assert.Len(t, params, 2)
+
+	query, params, err = search.query("sqlite")
+	assert.NoError(t, err)
+	assert.Contains(t, query, "0 = 1 OR 1 = 1")
+	assert.Empty(t, params)
+
+	matches := search.classify([][]interface{}{
+		{"public", "customers", "Email", "text", "", ""},
+		{"public", "customers", "email_verified", "boolean", "", ""},
+		{"public", "invoices", "billing_mail", "text", "", ""},
+	})
+	assert.Len(t, matches, 2)
+	assert.Equal(t, "Email", matches[0].Column)
+	assert.Equal(t, "billing_mail", matches[1].Column)
+
+	_, err = NewSearchSchemaTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "pattern": "(email", "regex": true},
+	}, "", &mockUseCase{dbType: "postgres"})
+	assert.ErrorContains(t, err, "invalid regular expression \"(email\"")
+}
@@ -0,0 +1,379 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// textSearchModes are the ways keywords combine in a full-text search
+var textSearchModes = []string{"all", "any", "phrase"}
+
+// textSearchConfig matches the names of PostgreSQL text search configurations
+var textSearchConfig = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// booleanModeOperators are the characters with a meaning in MySQL boolean mode searches
+const booleanModeOperators = `+-<>()~*"@`
+
+// SearchTextTool handles full-text searches built from plain keywords
+type SearchTextTool struct {
+	BaseToolType
+}
+
+// NewSearchTextTool creates a new full-text search tool type
+func NewSearchTextTool() *SearchTextTool {
+	return &SearchTextTool{
+		BaseToolType: BaseToolType{
+			name:        "search_text",
+			description: "Search text columns of a table for plain keywords and return the matching rows ranked by relevance. Builds the full-text query of the database instead of LIKE scans: to_tsvector, plainto_tsquery (or phraseto_tsquery / websearch_to_tsquery) and ts_rank on PostgreSQL, MATCH ... AGAINST on MySQL. Keywords are bound as parameters, never spliced into the SQL. MySQL needs a FULLTEXT index on exactly the searched columns; PostgreSQL works without one but scans the table, and the tool suggests the matching GIN index.",
+		},
+	}
+}
+
+// CreateTool creates a full-text search tool
+func (t *SearchTextTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Run a ranked full-text search for keywords over table columns"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Table to search (optionally schema-qualified)"),
+			tools.Required(),
+		),
+		tools.WithArray("columns",
+			tools.Description("Text columns to search"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+			tools.Required(),
+		),
+		tools.WithString("query",
+			tools.Description("Plain keywords to search for"),
+			tools.Required(),
+		),
+		tools.WithString("mode",
+			tools.Description("all to match rows containing every keyword, any for rows containing at least one, phrase for the keywords in order (default: all)"),
+		),
+		tools.WithString("language",
+			tools.Description("PostgreSQL text search configuration used for stemming, such as english or simple (default: english)"),
+		),
+		tools.WithArray("return_columns",
+			tools.Description("Columns to return with each match (default: all columns)"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithArray("filters",
+			tools.Description("Extra conditions combined with AND: objects with column, operator, value or values, as in build_query"),
+			tools.Items(map[string]interface{}{"type": "object"}),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of rows to return (default: 20, max: 1000)"),
+		),
+		tools.WithBoolean("execute",
+			tools.Description("Whether to run the search; when false only the generated SQL is returned (default: true)"),
+		),
+	)
+}
+
+// textSearch describes a full-text search over the columns of one table
+type textSearch struct {
+	Table         string
+	Columns       []string
+	Keywords      string
+	Mode          string
+	Language      string
+	ReturnColumns []string
+	Filters       []queryFilter
+	Limit         int
+}
+
+// HandleRequest handles full-text search tool requests
+func (t *SearchTextTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	execute := input.optionalBool("execute", true)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	search, err := parseTextSearch(request.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for search_text: %s", dbType)
+	}
+	searcher, err := dialect.TextSearcher()
+	if err != nil {
+		return nil, unsupportedAdminError("search_text", dbType, err)
+	}
+
+	sql, params, err := search.build(dialect)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Built full-text search for database %s: %s", targetDbID, sql)
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Full-Text Search for Database %s\n\n", targetDbID))
+	response.WriteString(fmt.Sprintf("```sql\n%s\n```\n\n", sql))
+	response.WriteString(fmt.Sprintf("Parameters: %v\n\n", params))
+	if !execute {
+		response.WriteString(fmt.Sprintf("Index that serves this search:\n\n```sql\n%s;\n```\n", search.indexDDL(dialect)))
+		return createTextResponse(response.String()), nil
+	}
+
+	schema, table := splitQualifiedName(search.Table)
+	indexed, err := searcher.hasIndex(ctx, useCase, targetDbID, schema, table, search.Columns)
+	if err != nil {
+		return nil, err
+	}
+	if !indexed {
+		if err := searcher.missingIndexError(search); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, targetDbID, sql, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run full-text search: %w", err)
+	}
+	response.WriteString(formatQueryResult(result))
+
+	if !indexed {
+		response.WriteString(fmt.Sprintf("\n## Warnings\n\n- No full-text index was found on %s, so the search scans the whole table. Create one matching the search with: %s\n",
+			search.Table, search.indexDDL(dialect)))
+	}
+	return createTextResponse(response.String()), nil
+}
+
+// parseTextSearch validates tool parameters and converts them into a full-text search
+func parseTextSearch(parameters map[string]interface{}) (*textSearch, error) {
+	input := newToolParams(parameters)
+	search := &textSearch{
+		Table:         input.requiredString("table"),
+		Columns:       input.stringList("columns"),
+		Keywords:      strings.TrimSpace(input.requiredString("query")),
+		Mode:          input.choice("mode", "all", textSearchModes...),
+		Language:      input.optionalString("language", "english"),
+		ReturnColumns: input.stringList("return_columns"),
+		Limit:         input.intBetween("limit", 20, 1, 1000),
+	}
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if len(search.Columns) == 0 {
+		return nil, fmt.Errorf("columns parameter must name at least one column to search")
+	}
+	if search.Keywords == "" {
+		return nil, fmt.Errorf("query parameter must contain at least one keyword")
+	}
+	if !textSearchConfig.MatchString(search.Language) {
+		return nil, fmt.Errorf("language parameter must be a text search configuration name such as english or simple")
+	}
+
+	var err error
+	if search.Filters, err = parseQueryFilters(input.raw("filters")); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// document returns the PostgreSQL tsvector expression of the searched columns. It joins the
+// columns with || rather than concat_ws, which is not immutable, so a GIN index can be built
+// on the very same expression.
+func (s *textSearch) document() string {
+	config := quoteLiteral("postgres", s.Language)
+	if len(s.Columns) == 1 {
+		return fmt.Sprintf("to_tsvector(%s, %s)", config, quoteIdentifier("postgres", s.Columns[0]))
+	}
+	parts := make([]string, len(s.Columns))
+	for i, column := range s.Columns {
+		parts[i] = fmt.Sprintf("coalesce(%s, '')", quoteIdentifier("postgres", column))
+	}
+	return fmt.Sprintf("to_tsvector(%s, %s)", config, strings.Join(parts, " || ' ' || "))
+}
+
+// booleanKeywords rewrites the keywords as a MySQL boolean mode search, dropping the
+// characters boolean mode would read as operators
+func (s *textSearch) booleanKeywords() string {
+	words := strings.Fields(strings.Map(func(r rune) rune {
+		if strings.ContainsRune(booleanModeOperators, r) {
+			return ' '
+		}
+		return r
+	}, s.Keywords))
+	if s.Mode == "phrase" {
+		return `"` + strings.Join(words, " ") + `"`
+	}
+	for i, word := range words {
+		words[i] = "+" + word
+	}
+	return strings.Join(words, " ")
+}
+
+// build renders the search for a dialect and returns it with its bound parameters
+func (s *textSearch) build(dialect Dialect) (string, []interface{}, error) {
+	searcher, err := dialect.TextSearcher()
+	if err != nil {
+		return "", nil, unsupportedAdminError("search_text", dialect.Name(), err)
+	}
+	params := newSQLParams(dialect.Name())
+	table := quoteIdentifier(dialect.Name(), s.Table)
+	columns := table + ".*"
+	if len(s.ReturnColumns) > 0 {
+		quoted := make([]string, len(s.ReturnColumns))
+		for i, column := range s.ReturnColumns {
+			quoted[i] = quoteIdentifier(dialect.Name(), column)
+		}
+		columns = strings.Join(quoted, ", ")
+	}
+
+	query, err := searcher.query(s, params, table, columns)
+	if err != nil {
+		return "", nil, err
+	}
+	var sb strings.Builder
+	sb.WriteString(query)
+	if where := buildWhereClause(s.Filters, params); where != "" {
+		sb.WriteString(" AND " + where)
+	}
+	sb.WriteString(fmt.Sprintf("\nORDER BY search_rank DESC\n%s", dialect.LimitClause(s.Limit)))
+	return sb.String(), params.values, nil
+}
+
+// indexDDL returns the statement creating the index the search can use, or "" when the
+// dialect has no full-text search
+func (s *textSearch) indexDDL(dialect Dialect) string {
+	searcher, err := dialect.TextSearcher()
+	if err != nil {
+		return ""
+	}
+	return searcher.indexDDL(s, quoteIdentifier(dialect.Name(), s.Table))
+}
+
+// quotedColumns quotes the searched columns
+func (s *textSearch) quotedColumns(dbType string) []string {
+	quoted := make([]string, len(s.Columns))
+	for i, column := range s.Columns {
+		quoted[i] = quoteIdentifier(dbType, column)
+	}
+	return quoted
+}
+
+// textSearcher renders full-text searches with the search functions and indexes of an engine
+type textSearcher interface {
+	// query renders the statement selecting columns of table with their search_rank, up to
+	// its WHERE clause, binding the keywords to params
+	query(s *textSearch, params *sqlParams, table, columns string) (string, error)
+	// indexDDL returns the statement creating the index the search can use
+	indexDDL(s *textSearch, table string) string
+	// hasIndex reports whether the table has a full-text index the search can use
+	hasIndex(ctx context.Context, useCase UseCaseProvider, dbID, schema, table string, columns []string) (bool, error)
+	// missingIndexError returns the error of a search that cannot run without an index, or nil
+	// when it scans the table instead
+	missingIndexError(s *textSearch) error
+}
+
+// postgresTextSearcher matches a tsvector of the columns against a tsquery of the keywords
+type postgresTextSearcher struct{}
+
+func (postgresTextSearcher) query(s *textSearch, params *sqlParams, table, columns string) (string, error) {
+	function := map[string]string{"all": "plainto_tsquery", "any": "websearch_to_tsquery", "phrase": "phraseto_tsquery"}[s.Mode]
+	keywords := s.Keywords
+	if s.Mode == "any" {
+		// websearch_to_tsquery reads "or" between words as an alternative and quotes and
+		// leading dashes as phrase and negation, which plain keywords must not trigger
+		keywords = strings.Join(strings.Fields(strings.NewReplacer(`"`, " ", "-", " ").Replace(keywords)), " or ")
+	}
+	document := s.document()
+	return fmt.Sprintf("SELECT %s, ts_rank(%s, search_query) AS search_rank\nFROM %s CROSS JOIN %s(%s, %s) AS search_query\nWHERE %s @@ search_query",
+		columns, document, table, function, quoteLiteral("postgres", s.Language), params.add(keywords), document), nil
+}
+
+func (postgresTextSearcher) indexDDL(s *textSearch, table string) string {
+	return fmt.Sprintf("CREATE INDEX ON %s USING GIN (%s)", table, s.document())
+}
+
+// hasIndex accepts any index built on to_tsvector, since expression indexes cannot be matched
+// reliably from their definition
+func (postgresTextSearcher) hasIndex(ctx context.Context, useCase UseCaseProvider, dbID, schema, table string, columns []string) (bool, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, `SELECT indexname FROM pg_indexes
+WHERE schemaname = COALESCE(NULLIF($1, ''), current_schema()) AND tablename = $2 AND indexdef ILIKE '%to_tsvector%'`,
+		[]interface{}{schema, table})
+	if err != nil {
+		return false, fmt.Errorf("failed to list full-text indexes: %w", err)
+	}
+	return len(result.Rows) > 0, nil
+}
+
+func (postgresTextSearcher) missingIndexError(*textSearch) error { return nil }
+
+// mysqlTextSearcher matches the columns with MATCH ... AGAINST, which needs a FULLTEXT index
+type mysqlTextSearcher struct{}
+
+func (mysqlTextSearcher) query(s *textSearch, params *sqlParams, table, columns string) (string, error) {
+	keywords, mode := s.booleanKeywords(), "IN BOOLEAN MODE"
+	if s.Mode == "any" {
+		keywords, mode = s.Keywords, "IN NATURAL LANGUAGE MODE"
+	}
+	if strings.Trim(keywords, `"+ `) == "" {
+		return "", fmt.Errorf("query parameter must contain at least one keyword")
+	}
+	match := func() string {
+		return fmt.Sprintf("MATCH (%s) AGAINST (%s %s)", strings.Join(s.quotedColumns("mysql"), ", "), params.add(keywords), mode)
+	}
+	return fmt.Sprintf("SELECT %s, %s AS search_rank\nFROM %s\nWHERE %s", columns, match(), table, match()), nil
+}
+
+func (mysqlTextSearcher) indexDDL(s *textSearch, table string) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD FULLTEXT INDEX (%s)", table, strings.Join(s.quotedColumns("mysql"), ", "))
+}
+
+// hasIndex looks for a FULLTEXT index on exactly the searched columns
+func (mysqlTextSearcher) hasIndex(ctx context.Context, useCase UseCaseProvider, dbID, schema, table string, columns []string) (bool, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, `SELECT INDEX_NAME, GROUP_CONCAT(COLUMN_NAME ORDER BY COLUMN_NAME SEPARATOR ',')
+FROM information_schema.STATISTICS
+WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE()) AND TABLE_NAME = ? AND INDEX_TYPE = 'FULLTEXT'
+GROUP BY INDEX_NAME`, []interface{}{schema, table})
+	if err != nil {
+		return false, fmt.Errorf("failed to list full-text indexes: %w", err)
+	}
+	wanted := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		_, name := splitQualifiedName(column)
+		wanted[strings.ToLower(name)] = true
+	}
+	for _, row := range result.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		indexed := strings.Split(strings.ToLower(valueString(row[1])), ",")
+		if len(indexed) != len(wanted) {
+			continue
+		}
+		matches := true
+		for _, column := range indexed {
+			matches = matches && wanted[column]
+		}
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (mysqlTextSearcher) missingIndexError(s *textSearch) error {
+	return fmt.Errorf("MySQL full-text search needs a FULLTEXT index on exactly the columns %s of %s; create one with: %s",
+		strings.Join(s.Columns, ", "), s.Table, mysqlTextSearcher{}.indexDDL(s, quoteIdentifier("mysql", s.Table)))
+}
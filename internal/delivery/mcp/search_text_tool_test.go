@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestTextSearchBuildPostgres(t *testing.T) {
+	search, err := parseTextSearch(map[string]interface{}{
+		"table":          "public.articles",
+		"columns":        []interface{}{"title", "body"},
+		"query":          "  connection pooling ",
+		"return_columns": []interface{}{"id", "title"},
+		"filters":        []interface{}{map[string]interface{}{"column": "published", "value": true}},
+	})
+	assert.NoError(t, err)
+
+	postgres, _ := lookupDialect("postgres")
+	sql, params, err := search.build(postgres)
+	assert.NoError(t, err)
+	document := `to_tsvector('english', coalesce("title", '') || ' ' || coalesce("body", ''))`
+	assert.Equal(t, `SELECT "id", "title", ts_rank(`+document+`, search_query) AS search_rank
+FROM "public"."articles" CROSS JOIN plainto_tsquery('english', $1) AS search_query
+WHERE `+document+` @@ search_query AND "published" = $2
+ORDER BY search_rank DESC
+LIMIT 20`, sql)
+	assert.Equal(t, []interface{}{"connection pooling", true}, params)
+	assert.Equal(t, `CREATE INDEX ON "public"."articles" USING GIN (`+document+`)`, search.indexDDL(postgres))
+
+	search.Mode = "any"
+	search.Keywords = `pool "timeout" -retry`
+	sql, params, _ = search.build(postgres)
+	assert.Contains(t, sql, "websearch_to_tsquery('english', $1)")
+	assert.Equal(t, "pool or timeout or retry", params[0])
+}
+
+func TestTextSearchBuildMySQL(t *testing.T) {
+	search, err := parseTextSearch(map[string]interface{}{
+		"table":   "articles",
+		"columns": []interface{}{"body"},
+		"query":   "+pool* (timeout)",
+		"limit":   float64(5),
+	})
+	assert.NoError(t, err)
+
+	mysql, _ := lookupDialect("mysql")
+	sql, params, err := search.build(mysql)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT `articles`.*, MATCH (`body`) AGAINST (? IN BOOLEAN MODE) AS search_rank\nFROM `articles`\nWHERE MATCH (`body`) AGAINST (? IN BOOLEAN MODE)\nORDER BY search_rank DESC\nLIMIT 5", sql)
+	assert.Equal(t, []interface{}{"+pool +timeout", "+pool +timeout"}, params)
+
+	search.Mode = "phrase"
+	_, params, _ = search.build(mysql)
+	assert.Equal(t, `"pool timeout"`, params[0])
+
+	search.Keywords = "+-()"
+	_, _, err = search.build(mysql)
+	assert.Error(t, err)
+}
+
+func TestParseTextSearchRejectsInvalidInput(t *testing.T) {
+	_, err := parseTextSearch(map[string]interface{}{"table": "articles", "query": "pool"})
+	assert.Error(t, err)
+
+	_, err = parseTextSearch(map[string]interface{}{"table": "articles", "columns": []interface{}{"body"}, "query": "pool", "language": "english'); DROP"})
+	assert.Error(t, err)
+
+	_, err = parseTextSearch(map[string]interface{}{"table": "articles", "columns": []interface{}{"body"}, "query": "pool", "mode": "fuzzy"})
+	assert.Error(t, err)
+}
+
+func TestSearchTextTool(t *testing.T) {
+	request := server.ToolCallRequest{Parameters: map[string]interface{}{
+		"database": "db1",
+		"table":    "articles",
+		"columns":  []interface{}{"title", "body"},
+		"query":    "pool",
+	}}
+
+	useCase := &mockUseCase{dbType: "mysql"}
+	_, err := NewSearchTextTool().HandleRequest(context.Background(), request, "", useCase)
+	assert.ErrorContains(t, err, "ALTER TABLE `articles` ADD FULLTEXT INDEX (`title`, `body`)")
+
+	useCase = &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"INDEX_TYPE = 'FULLTEXT'": {Rows: [][]interface{}{{"ft_body", "body"}, {"ft_all", "body,title"}}},
+			"search_rank":             {Columns: []string{"id", "search_rank"}, Rows: [][]interface{}{{int64(1), 0.9}}},
+		},
+	}
+	result, err := NewSearchTextTool().HandleRequest(context.Background(), request, "", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "1\t0.9")
+	assert.NotContains(t, text, "## Warnings")
+
+	useCase = &mockUseCase{dbType: "postgres"}
+	result, err = NewSearchTextTool().HandleRequest(context.Background(), request, "", useCase)
+	assert.NoError(t, err)
+	text = result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "No full-text index was found on articles")
+}
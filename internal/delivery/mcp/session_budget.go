@@ -0,0 +1,436 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+// budgetIdleTimeout is how long a session may go without a query before its usage is
+// forgotten. Sessions end without telling the tool handlers, so idle usage stands in for it.
+const budgetIdleTimeout = time.Hour
+
+// budgetSweepInterval is how often charging looks for idle usage to forget
+const budgetSweepInterval = time.Minute
+
+// budgetClock returns the current time; tests replace it
+var budgetClock = time.Now
+
+// sessionlessBudget is the session calls without an MCP session are charged to. Only the stdio
+// transport has none, and it serves a single client, so its calls share one budget the way
+// the calls of one session do.
+const sessionlessBudget = ""
+
+// budgetUsage is what a session has consumed on one database
+type budgetUsage struct {
+	rows      int64
+	bytes     int64
+	queryTime time.Duration
+	lastUsed  time.Time
+}
+
+// idle reports whether the usage has not been charged for longer than budgetIdleTimeout
+func (u *budgetUsage) idle(now time.Time) bool {
+	return now.Sub(u.lastUsed) >= budgetIdleTimeout
+}
+
+// budgetTracker accumulates usage per MCP session and database, forgetting the usage of
+// sessions that went idle
+type budgetTracker struct {
+	mu        sync.Mutex
+	usage     map[string]*budgetUsage
+	lastSweep time.Time
+}
+
+// sessionBudgets is the budget tracker shared by all tool calls
+var sessionBudgets = newBudgetTracker()
+
+// newBudgetTracker creates an empty budget tracker
+func newBudgetTracker() *budgetTracker {
+	return &budgetTracker{usage: make(map[string]*budgetUsage)}
+}
+
+// snapshot returns the usage of a session on a database
+func (b *budgetTracker) snapshot(sessionID, dbID string) budgetUsage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if usage, ok := b.usage[sessionID+"\x00"+dbID]; ok && !usage.idle(budgetClock()) {
+		return *usage
+	}
+	return budgetUsage{}
+}
+
+// charge adds consumption to the usage of a session on a database
+func (b *budgetTracker) charge(sessionID, dbID string, rows, bytes int64, queryTime time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := budgetClock()
+	b.sweep(now)
+	key := sessionID + "\x00" + dbID
+	usage, ok := b.usage[key]
+	if !ok || usage.idle(now) {
+		usage = &budgetUsage{}
+		b.usage[key] = usage
+	}
+	usage.rows += rows
+	usage.bytes += bytes
+	usage.queryTime += queryTime
+	usage.lastUsed = now
+}
+
+// sweep forgets the usage of idle sessions, at most once per budgetSweepInterval. b.mu must
+// be held.
+func (b *budgetTracker) sweep(now time.Time) {
+	if now.Sub(b.lastSweep) < budgetSweepInterval {
+		return
+	}
+	b.lastSweep = now
+	for key, usage := range b.usage {
+		if usage.idle(now) {
+			delete(b.usage, key)
+		}
+	}
+}
+
+// bind returns a use case that charges the queries of one tool call to its session
+func (b *budgetTracker) bind(useCase UseCaseProvider, request server.ToolCallRequest) *budgetedUseCase {
+	session := sessionlessBudget
+	if request.Session != nil {
+		session = request.Session.ID
+	}
+	return &budgetedUseCase{UseCaseProvider: useCase, tracker: b, session: session}
+}
+
+// budgetedUseCase enforces the session budget of each database on the queries of a tool call.
+// Queries are refused once a limit is spent; results that would overrun the row or byte limit
+// are reduced to a sample (unordered queries) or their first rows (ordered queries), and the
+// query time limit bounds how long the next query may run.
+type budgetedUseCase struct {
+	UseCaseProvider
+	tracker *budgetTracker
+	session string
+
+	mu    sync.Mutex
+	used  []string
+	notes []string
+}
+
+// sharedUseCase returns the provider under the per-call budget, circuit breaker and schema
+// watcher wrappers, in whatever order they are stacked, so caches keyed by the use case are
+// shared across tool calls
+func sharedUseCase(useCase UseCaseProvider) UseCaseProvider {
+	for {
+		switch wrapper := useCase.(type) {
+		case *budgetedUseCase:
+			useCase = wrapper.UseCaseProvider
+		case *guardedUseCase:
+			useCase = wrapper.UseCaseProvider
+		case *schemaWatcher:
+			useCase = wrapper.UseCaseProvider
+		default:
+			return useCase
+		}
+	}
+}
+
+// budgetOf returns the session budget configured for a database
+func (u *budgetedUseCase) budgetOf(dbID string) domain.SessionBudget {
+	config, err := u.UseCaseProvider.GetDatabaseConfig(dbID)
+	if err != nil || config == nil {
+		return domain.SessionBudget{}
+	}
+	return config.SessionBudget
+}
+
+// record remembers that the tool call used a budgeted database, and an optional note about it
+func (u *budgetedUseCase) record(dbID, note string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	found := false
+	for _, used := range u.used {
+		if used == dbID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		u.used = append(u.used, dbID)
+	}
+	if note != "" {
+		u.notes = append(u.notes, note)
+	}
+}
+
+// admit checks that the budget is not spent and bounds ctx by the remaining query time
+func (u *budgetedUseCase) admit(ctx context.Context, dbID string, budget domain.SessionBudget) (context.Context, context.CancelFunc, error) {
+	usage := u.tracker.snapshot(u.session, dbID)
+	switch {
+	case budget.MaxRows > 0 && usage.rows >= budget.MaxRows:
+		return nil, nil, fmt.Errorf("session budget for database %s is spent: %d of %d rows read", dbID, usage.rows, budget.MaxRows)
+	case budget.MaxBytes > 0 && usage.bytes >= budget.MaxBytes:
+		return nil, nil, fmt.Errorf("session budget for database %s is spent: %d of %d bytes read", dbID, usage.bytes, budget.MaxBytes)
+	case budget.MaxQueryTime > 0 && usage.queryTime >= budget.MaxQueryTime:
+		return nil, nil, fmt.Errorf("session budget for database %s is spent: %s of %s query time used", dbID, roundDuration(usage.queryTime), budget.MaxQueryTime)
+	}
+	if budget.MaxQueryTime > 0 {
+		ctx, cancel := context.WithTimeout(ctx, budget.MaxQueryTime-usage.queryTime)
+		return ctx, cancel, nil
+	}
+	return ctx, func() {}, nil
+}
+
+// ExecuteQuery runs a query within the session budget, reducing results that would overrun it
+func (u *budgetedUseCase) ExecuteQuery(ctx context.Context, dbID, query string, params []interface{}) (*domain.QueryResult, error) {
+	budget := u.budgetOf(dbID)
+	if !budget.Enabled() {
+		return u.UseCaseProvider.ExecuteQuery(ctx, dbID, query, params)
+	}
+	ctx, cancel, err := u.admit(ctx, dbID, budget)
+	if err != nil {
+		u.record(dbID, "")
+		return nil, err
+	}
+	defer cancel()
+
+	started := time.Now()
+	result, err := u.UseCaseProvider.ExecuteQuery(ctx, dbID, query, params)
+	elapsed := time.Since(started)
+	if err != nil {
+		u.tracker.charge(u.session, dbID, 0, 0, elapsed)
+		u.record(dbID, "")
+		return nil, err
+	}
+
+	return u.fit(dbID, query, result, budget, elapsed), nil
+}
+
+// FetchRows reads spilled rows of a result within the session budget, reducing pages that
+// would overrun it. A reduced page drops its cursor since the rest would overrun it as well.
+func (u *budgetedUseCase) FetchRows(ctx context.Context, dbID, cursor string, limit int) (*domain.QueryResult, error) {
+	budget := u.budgetOf(dbID)
+	if !budget.Enabled() {
+		return u.UseCaseProvider.FetchRows(ctx, dbID, cursor, limit)
+	}
+	ctx, cancel, err := u.admit(ctx, dbID, budget)
+	if err != nil {
+		u.record(dbID, "")
+		return nil, err
+	}
+	defer cancel()
+
+	started := time.Now()
+	result, err := u.UseCaseProvider.FetchRows(ctx, dbID, cursor, limit)
+	elapsed := time.Since(started)
+	if err != nil {
+		u.tracker.charge(u.session, dbID, 0, 0, elapsed)
+		u.record(dbID, "")
+		return nil, err
+	}
+	return u.fit(dbID, "", result, budget, elapsed), nil
+}
+
+// fit reduces a result to the budget left and charges what is returned
+func (u *budgetedUseCase) fit(dbID, query string, result *domain.QueryResult, budget domain.SessionBudget, elapsed time.Duration) *domain.QueryResult {
+	usage := u.tracker.snapshot(u.session, dbID)
+	result, note := fitResultToBudget(result, query, budget, usage)
+	var bytes int64
+	for _, row := range result.Rows {
+		bytes += rowSize(row)
+	}
+	u.tracker.charge(u.session, dbID, int64(len(result.Rows)), bytes, elapsed)
+	u.record(dbID, note)
+	return result
+}
+
+// budgetedRows counts the rows and bytes of a streamed query for the session budget
+type budgetedRows struct {
+	domain.RowHandler
+	bytes int64
+}
+
+// Row implements domain.RowHandler
+func (r *budgetedRows) Row(values []interface{}) error {
+	r.bytes += rowSize(values)
+	return r.RowHandler.Row(values)
+}
+
+// StreamQuery streams a query, charging its rows, bytes and time to the session budget.
+// Streamed exports must be complete, so they are refused once the budget is spent but never reduced.
+func (u *budgetedUseCase) StreamQuery(ctx context.Context, dbID, query string, params []interface{}, handler domain.RowHandler) (int64, error) {
+	budget := u.budgetOf(dbID)
+	if !budget.Enabled() {
+		return u.UseCaseProvider.StreamQuery(ctx, dbID, query, params, handler)
+	}
+	ctx, cancel, err := u.admit(ctx, dbID, budget)
+	if err != nil {
+		u.record(dbID, "")
+		return 0, err
+	}
+	defer cancel()
+
+	started := time.Now()
+	counted := &budgetedRows{RowHandler: handler}
+	rows, err := u.UseCaseProvider.StreamQuery(ctx, dbID, query, params, counted)
+	u.tracker.charge(u.session, dbID, rows, counted.bytes, time.Since(started))
+	u.record(dbID, "")
+	return rows, err
+}
+
+// ExecuteStatement runs a statement, charging its time to the session budget
+func (u *budgetedUseCase) ExecuteStatement(ctx context.Context, dbID, statement string, params []interface{}) (string, error) {
+	budget := u.budgetOf(dbID)
+	if !budget.Enabled() {
+		return u.UseCaseProvider.ExecuteStatement(ctx, dbID, statement, params)
+	}
+	ctx, cancel, err := u.admit(ctx, dbID, budget)
+	if err != nil {
+		u.record(dbID, "")
+		return "", err
+	}
+	defer cancel()
+
+	started := time.Now()
+	result, err := u.UseCaseProvider.ExecuteStatement(ctx, dbID, statement, params)
+	u.tracker.charge(u.session, dbID, 0, 0, time.Since(started))
+	u.record(dbID, "")
+	return result, err
+}
+
+// ExecuteBatch runs a batch of statements, charging its time to the session budget
+func (u *budgetedUseCase) ExecuteBatch(ctx context.Context, dbID string, statements []string, params [][]interface{}) error {
+	budget := u.budgetOf(dbID)
+	if !budget.Enabled() {
+		return u.UseCaseProvider.ExecuteBatch(ctx, dbID, statements, params)
+	}
+	ctx, cancel, err := u.admit(ctx, dbID, budget)
+	if err != nil {
+		u.record(dbID, "")
+		return err
+	}
+	defer cancel()
+
+	started := time.Now()
+	err = u.UseCaseProvider.ExecuteBatch(ctx, dbID, statements, params)
+	u.tracker.charge(u.session, dbID, 0, 0, time.Since(started))
+	u.record(dbID, "")
+	return err
+}
+
+// report describes the reductions made and the budget left on each database the tool call used
+func (u *budgetedUseCase) report() string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if len(u.used) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, note := range u.notes {
+		sb.WriteString(note + "\n")
+	}
+	for _, dbID := range u.used {
+		budget := u.budgetOf(dbID)
+		usage := u.tracker.snapshot(u.session, dbID)
+		var remaining []string
+		if budget.MaxRows > 0 {
+			remaining = append(remaining, fmt.Sprintf("%d of %d rows", max(budget.MaxRows-usage.rows, 0), budget.MaxRows))
+		}
+		if budget.MaxBytes > 0 {
+			remaining = append(remaining, fmt.Sprintf("%d of %d bytes", max(budget.MaxBytes-usage.bytes, 0), budget.MaxBytes))
+		}
+		if budget.MaxQueryTime > 0 {
+			left := budget.MaxQueryTime - usage.queryTime
+			if left < 0 {
+				left = 0
+			}
+			remaining = append(remaining, fmt.Sprintf("%s of %s query time", roundDuration(left), budget.MaxQueryTime))
+		}
+		sb.WriteString(fmt.Sprintf("Session budget remaining for %s: %s\n", dbID, strings.Join(remaining, ", ")))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// fitResultToBudget cuts a result down to the rows and bytes left in the budget and describes the cut.
+// Unordered results keep an evenly spread sample; ordered results keep their first rows.
+func fitResultToBudget(result *domain.QueryResult, query string, budget domain.SessionBudget, usage budgetUsage) (*domain.QueryResult, string) {
+	total := len(result.Rows)
+	keep := total
+	if budget.MaxRows > 0 && int64(keep) > budget.MaxRows-usage.rows {
+		keep = int(max(budget.MaxRows-usage.rows, 0))
+	}
+	if budget.MaxBytes > 0 {
+		left := budget.MaxBytes - usage.bytes
+		var bytes int64
+		for i := 0; i < keep; i++ {
+			bytes += rowSize(result.Rows[i])
+			if bytes > left {
+				keep = i
+				break
+			}
+		}
+	}
+	if keep >= total {
+		return result, ""
+	}
+
+	ordered := strings.Contains(strings.ToUpper(query), "ORDER BY")
+	rows := make([][]interface{}, 0, keep)
+	if ordered {
+		rows = append(rows, result.Rows[:keep]...)
+	} else {
+		for i := 0; i < keep; i++ {
+			rows = append(rows, result.Rows[i*total/keep])
+		}
+	}
+
+	method := "sampled"
+	if ordered {
+		method = "truncated"
+	}
+	note := fmt.Sprintf("Result %s from %d to %d rows to stay within the session budget.", method, total, keep)
+	return &domain.QueryResult{Columns: result.Columns, Rows: rows}, note
+}
+
+// rowSize estimates the bytes of a row as rendered in tool output
+func rowSize(row []interface{}) int64 {
+	var size int64
+	for _, value := range row {
+		switch v := value.(type) {
+		case nil:
+			size += 4
+		case []byte:
+			size += int64(len(v))
+		case string:
+			size += int64(len(v))
+		default:
+			size += int64(len(fmt.Sprintf("%v", v)))
+		}
+	}
+	return size
+}
+
+// roundDuration rounds a duration for display
+func roundDuration(d time.Duration) time.Duration {
+	return d.Round(10 * time.Millisecond)
+}
+
+// appendResponseText adds a text item to a tool response
+func appendResponseText(response interface{}, text string) interface{} {
+	switch resp := response.(type) {
+	case map[string]interface{}:
+		if content, ok := resp["content"].([]map[string]interface{}); ok {
+			resp["content"] = append(content, map[string]interface{}{"type": "text", "text": text})
+		}
+	case *Response:
+		resp.WithText(text)
+	case string:
+		return resp + "\n\n" + text
+	}
+	return response
+}
@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+// numberedRows returns a result of n single-column rows holding 0..n-1
+func numberedRows(n int) *domain.QueryResult {
+	result := &domain.QueryResult{Columns: []string{"id"}}
+	for i := 0; i < n; i++ {
+		result.Rows = append(result.Rows, []interface{}{int64(i)})
+	}
+	return result
+}
+
+func TestSessionBudgetReducesAndRefuses(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType:  "postgres",
+		results: map[string]*domain.QueryResult{"FROM small": numberedRows(4), "FROM big": numberedRows(10)},
+		config:  domain.DatabaseConnectionConfig{SessionBudget: domain.SessionBudget{MaxRows: 6}},
+	}
+	request := server.ToolCallRequest{Session: &types.ClientSession{ID: "s1"}}
+	tracker := newBudgetTracker()
+
+	budgeted := tracker.bind(useCase, request)
+	result, err := budgeted.ExecuteQuery(context.Background(), "test", "SELECT id FROM small", nil)
+	assert.NoError(t, err)
+	assert.Len(t, result.Rows, 4)
+
+	// Only two rows are left: an unordered result is sampled across its range
+	result, err = budgeted.ExecuteQuery(context.Background(), "test", "SELECT id FROM big", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{int64(0)}, {int64(5)}}, result.Rows)
+
+	report := budgeted.report()
+	assert.Contains(t, report, "Result sampled from 10 to 2 rows to stay within the session budget.")
+	assert.Contains(t, report, "Session budget remaining for test: 0 of 6 rows")
+
+	// The next tool call of the same session is refused
+	_, err = tracker.bind(useCase, request).ExecuteQuery(context.Background(), "test", "SELECT id FROM small", nil)
+	assert.EqualError(t, err, "session budget for database test is spent: 6 of 6 rows read")
+
+	// Other sessions have their own budget
+	other := server.ToolCallRequest{Session: &types.ClientSession{ID: "s2"}}
+	result, err = tracker.bind(useCase, other).ExecuteQuery(context.Background(), "test", "SELECT id FROM small", nil)
+	assert.NoError(t, err)
+	assert.Len(t, result.Rows, 4)
+}
+
+func TestSessionBudgetTruncatesOrderedResults(t *testing.T) {
+	budget := domain.SessionBudget{MaxRows: 100, MaxBytes: 3}
+	result, note := fitResultToBudget(numberedRows(10), "SELECT id FROM t ORDER BY id", budget, budgetUsage{})
+	assert.Equal(t, [][]interface{}{{int64(0)}, {int64(1)}, {int64(2)}}, result.Rows)
+	assert.Equal(t, "Result truncated from 10 to 3 rows to stay within the session budget.", note)
+
+	result, note = fitResultToBudget(numberedRows(2), "SELECT id FROM t", budget, budgetUsage{})
+	assert.Len(t, result.Rows, 2)
+	assert.Empty(t, note)
+}
+
+func TestSessionBudgetQueryTime(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		config: domain.DatabaseConnectionConfig{SessionBudget: domain.SessionBudget{MaxQueryTime: time.Second}},
+	}
+	tracker := newBudgetTracker()
+	tracker.charge("", "test", 0, 0, 2*time.Second)
+
+	budgeted := tracker.bind(useCase, server.ToolCallRequest{})
+	_, err := budgeted.ExecuteStatement(context.Background(), "test", "UPDATE t SET a = 1", nil)
+	assert.EqualError(t, err, "session budget for database test is spent: 2s of 1s query time used")
+	assert.Empty(t, useCase.queries)
+	assert.Equal(t, "Session budget remaining for test: 0s of 1s query time", budgeted.report())
+}
+
+func TestSessionBudgetDisabled(t *testing.T) {
+	useCase := &mockUseCase{dbType: "postgres", results: map[string]*domain.QueryResult{"FROM big": numberedRows(10)}}
+	budgeted := newBudgetTracker().bind(useCase, server.ToolCallRequest{})
+	result, err := budgeted.ExecuteQuery(context.Background(), "test", "SELECT id FROM big", nil)
+	assert.NoError(t, err)
+	assert.Len(t, result.Rows, 10)
+	assert.Empty(t, budgeted.report())
+}
+
+func TestAppendResponseText(t *testing.T) {
+	response := appendResponseText(createTextResponse("rows"), "budget").(map[string]interface{})
+	content := response["content"].([]map[string]interface{})
+	assert.Len(t, content, 2)
+	assert.Equal(t, "budget", content[1]["text"])
+
+	assert.Equal(t, "rows\n\nbudget", appendResponseText("rows", "budget"))
+}
+
+func TestSchemaCacheSharedAcrossBudgetedCalls(t *testing.T) {
+	useCase := newSchemaCacheUseCase()
+	t.Cleanup(func() { schemaCache.invalidate(useCase, "test") })
+
+	_, err := schemaCache.load(context.Background(), sessionBudgets.bind(useCase, server.ToolCallRequest{}), "test", "app")
+	assert.NoError(t, err)
+	loaded := len(useCase.queries)
+
+	_, err = schemaCache.load(context.Background(), sessionBudgets.bind(useCase, server.ToolCallRequest{}), "test", "app")
+	assert.NoError(t, err)
+	assert.Len(t, useCase.queries, loaded)
+	assert.Equal(t, 1, schemaCache.invalidate(sessionBudgets.bind(useCase, server.ToolCallRequest{}), "test"))
+}
+
+func TestSchemaCacheSharedThroughToolCallWrappers(t *testing.T) {
+	useCase := newSchemaCacheUseCase()
+	t.Cleanup(func() { schemaCache.invalidate(useCase, "test") })
+	// wrap stacks the wrappers the tool registry puts around every tool call
+	wrap := func() UseCaseProvider {
+		return sessionBudgets.bind(databaseBreakers.guard(watchSchemaChanges(useCase)), server.ToolCallRequest{})
+	}
+
+	_, err := schemaCache.load(context.Background(), wrap(), "test", "app")
+	assert.NoError(t, err)
+	loaded := len(useCase.queries)
+	_, err = schemaCache.load(context.Background(), wrap(), "test", "app")
+	assert.NoError(t, err)
+	assert.Len(t, useCase.queries, loaded)
+	assert.Equal(t, 1, schemaCache.invalidate(wrap(), "test"))
+
+	_, err = schemaCache.load(context.Background(), wrap(), "test", "app")
+	assert.NoError(t, err)
+	_, err = wrap().ExecuteStatement(context.Background(), "test", "ALTER TABLE app.users ADD COLUMN name text", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, schemaCache.invalidate(wrap(), "test"))
+}
+
+func TestSessionBudgetForgetsIdleSessions(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	budgetClock = func() time.Time { return now }
+	t.Cleanup(func() { budgetClock = time.Now })
+	tracker := newBudgetTracker()
+
+	tracker.charge("a", "db1", 10, 100, time.Second)
+	tracker.charge(sessionlessBudget, "db1", 5, 50, 0)
+	now = now.Add(30 * time.Minute)
+	tracker.charge("b", "db1", 1, 1, 0)
+	assert.Equal(t, int64(10), tracker.snapshot("a", "db1").rows)
+
+	// Once idle for budgetIdleTimeout, usage counts as spent by nobody and is swept away
+	now = now.Add(45 * time.Minute)
+	assert.Equal(t, budgetUsage{}, tracker.snapshot("a", "db1"))
+	tracker.charge("b", "db1", 1, 1, 0)
+	assert.Len(t, tracker.usage, 1)
+	assert.Equal(t, int64(2), tracker.snapshot("b", "db1").rows)
+}
@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// SnapshotSchemaTool handles saving a snapshot of a database schema
+type SnapshotSchemaTool struct {
+	BaseToolType
+}
+
+// NewSnapshotSchemaTool creates a new schema snapshot tool type
+func NewSnapshotSchemaTool() *SnapshotSchemaTool {
+	return &SnapshotSchemaTool{
+		BaseToolType: BaseToolType{
+			name:        "snapshot_schema",
+			description: "Capture the full structure of a schema (tables, columns, keys, indexes and foreign keys) to the database's snapshots directory (snapshots_dir in the connection config, or $SNAPSHOTS_DIR/<database id>). Each snapshot is saved as structured JSON and as canonical DDL, and can later be compared with the live database using compare_snapshot.",
+		},
+	}
+}
+
+// CreateTool creates a schema snapshot tool
+func (t *SnapshotSchemaTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Save the schema as a named JSON and DDL snapshot"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("name",
+			tools.Description("Snapshot name (default: the current time, e.g. 20240301-120000)"),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to capture (default: public for PostgreSQL, the current database for MySQL)"),
+		),
+		tools.WithBoolean("overwrite",
+			tools.Description("Replace an existing snapshot with the same name (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles schema snapshot tool requests
+func (t *SnapshotSchemaTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	now := time.Now().UTC()
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	name := input.optionalString("name", now.Format("20060102-150405"))
+	schema := input.optionalString("schema", "")
+	overwrite := input.optionalBool("overwrite", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	dir, err := snapshotsDir(useCase, targetDbID)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := loadSchemaMetadata(ctx, useCase, targetDbID, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &schemaSnapshot{Name: name, Database: targetDbID, CreatedAt: now, Schema: meta}
+	jsonPath, ddlPath, err := saveSnapshot(dir, snapshot, overwrite)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Saved schema snapshot %s of database %s: %d tables", name, targetDbID, len(meta.Tables))
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Schema Snapshot for Database %s\n\n", targetDbID))
+	schemaLabel := meta.Schema
+	if schemaLabel == "" {
+		schemaLabel = "(current database)"
+	}
+	response.WriteString(fmt.Sprintf("Saved snapshot %s of schema %s: %d tables, %d foreign keys.\n\n",
+		name, schemaLabel, len(meta.Tables), len(meta.ForeignKeys)))
+	response.WriteString(fmt.Sprintf("- JSON: %s\n", jsonPath))
+	response.WriteString(fmt.Sprintf("- DDL: %s\n", ddlPath))
+
+	snapshots, err := listSnapshots(dir)
+	if err != nil {
+		logger.Warn("Failed to list snapshots in %s: %v", dir, err)
+	} else if len(snapshots) > 1 {
+		names := make([]string, len(snapshots))
+		for i, s := range snapshots {
+			names[i] = s.Name
+		}
+		response.WriteString(fmt.Sprintf("\nAll snapshots, oldest first: %s\n", strings.Join(names, ", ")))
+	}
+
+	return createTextResponse(response.String()), nil
+}
@@ -0,0 +1,360 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// spatialColumn is a geometry or geography column with what the summary reports about it
+type spatialColumn struct {
+	Schema string
+	Table  string
+	Column string
+	// Kind is geometry or geography on PostgreSQL, and the column type on MySQL
+	Kind         string
+	GeometryType string
+	// SRID is the reference system the column is constrained to; zero means any
+	SRID       int64
+	Dimensions int64
+	Indexes    []string
+	Extent     string
+	ExtentNote string
+}
+
+// name returns the qualified name of the column
+func (c spatialColumn) name() string {
+	return qualifiedName(c.Schema, c.Table) + "." + c.Column
+}
+
+// SpatialSummaryTool reports the spatial columns of a database
+type SpatialSummaryTool struct {
+	BaseToolType
+}
+
+// NewSpatialSummaryTool creates a new spatial summary tool type
+func NewSpatialSummaryTool() *SpatialSummaryTool {
+	return &SpatialSummaryTool{
+		BaseToolType: BaseToolType{
+			name:        "spatial_summary",
+			description: "Summarize the spatial columns of a database: PostGIS geometry and geography columns, or MySQL spatial columns, with their geometry type, SRID, dimensions, extent and spatial indexes. Columns without a spatial index are flagged, since spatial filters on them scan the whole table. The extent is estimated from planner statistics on PostgreSQL unless exact is set; on MySQL it is only computed with exact.",
+		},
+	}
+}
+
+// CreateTool creates a spatial summary tool
+func (t *SpatialSummaryTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Report SRIDs, extents and spatial indexes of geometry columns"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Only report this table, optionally schema-qualified (default: all tables)"),
+		),
+		tools.WithBoolean("exact",
+			tools.Description("Compute extents by scanning the tables instead of estimating them (default: false)"),
+		),
+	)
+}
+
+// HandleRequest handles spatial summary tool requests
+func (t *SpatialSummaryTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema, table := splitQualifiedName(input.optionalString("table", ""))
+	exact := input.optionalBool("exact", false)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for spatial_summary: %s", dbType)
+	}
+	catalog, err := dialect.SpatialCatalog()
+	if err != nil {
+		return nil, unsupportedAdminError("spatial_summary", dbType, err)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Spatial Summary for Database %s\n\n", targetDbID))
+
+	columns, err := catalog.loadColumns(ctx, useCase, targetDbID, schema, table)
+	if err != nil {
+		if note, missing := catalog.missingExtension(err); missing {
+			response.WriteString(note + "\n")
+			return createTextResponse(response.String()), nil
+		}
+		return nil, err
+	}
+	if len(columns) == 0 {
+		response.WriteString("No spatial columns found.\n")
+		return createTextResponse(response.String()), nil
+	}
+
+	logger.Info("Summarizing %d spatial columns of database %s (exact extents: %v)", len(columns), targetDbID, exact)
+	for i := range columns {
+		readSpatialExtent(ctx, useCase, targetDbID, dialect, catalog, &columns[i], exact)
+	}
+	writeSpatialSummary(&response, catalog, columns)
+	return createTextResponse(response.String()), nil
+}
+
+// spatialCatalog lists the spatial columns of a database and reads their extents
+type spatialCatalog interface {
+	// loadColumns reads the spatial columns of a schema, or of one table, with their indexes
+	loadColumns(ctx context.Context, useCase UseCaseProvider, dbID, schema, table string) ([]spatialColumn, error)
+	// missingExtension returns the note explaining that a listing error means the spatial
+	// extension is not installed, or false for any other error
+	missingExtension(err error) (string, bool)
+	// extentQuery returns the query reading the bounding box of a quoted column, or a note
+	// when the extent cannot be read that way
+	extentQuery(c *spatialColumn, table, column string, exact bool) (string, []interface{}, string)
+	// indexFix returns the statement creating a spatial index on a column
+	indexFix(table, column string) string
+	// sridNote adds to the warning about a column without an SRID, or returns ""
+	sridNote() string
+}
+
+// postGISCatalog reads the geometry and geography columns PostGIS registers
+type postGISCatalog struct{}
+
+func (postGISCatalog) loadColumns(ctx context.Context, useCase UseCaseProvider, dbID, schema, table string) ([]spatialColumn, error) {
+	return loadPostGISColumns(ctx, useCase, dbID, schema, table)
+}
+
+func (postGISCatalog) missingExtension(err error) (string, bool) {
+	if !strings.Contains(err.Error(), "does not exist") {
+		return "", false
+	}
+	return "PostGIS is not installed on this database, so it has no geometry or geography columns. Install it with CREATE EXTENSION postgis.", true
+}
+
+func (postGISCatalog) extentQuery(c *spatialColumn, table, column string, exact bool) (string, []interface{}, string) {
+	if exact {
+		return fmt.Sprintf("SELECT ST_Extent(%s::geometry)::text FROM %s", column, table), nil, ""
+	}
+	return "SELECT ST_EstimatedExtent($1, $2, $3)::text", []interface{}{c.Schema, c.Table, c.Column}, ""
+}
+
+func (postGISCatalog) indexFix(table, column string) string {
+	return fmt.Sprintf("CREATE INDEX ON %s USING GIST (%s)", table, column)
+}
+
+func (postGISCatalog) sridNote() string { return "" }
+
+// mysqlSpatialCatalog reads the spatial columns of MySQL and their SPATIAL indexes
+type mysqlSpatialCatalog struct{}
+
+func (mysqlSpatialCatalog) loadColumns(ctx context.Context, useCase UseCaseProvider, dbID, schema, table string) ([]spatialColumn, error) {
+	return loadMySQLSpatialColumns(ctx, useCase, dbID, schema, table)
+}
+
+// missingExtension returns false; spatial types are built into MySQL
+func (mysqlSpatialCatalog) missingExtension(error) (string, bool) { return "", false }
+
+func (mysqlSpatialCatalog) extentQuery(_ *spatialColumn, table, column string, exact bool) (string, []interface{}, string) {
+	if !exact {
+		return "", nil, "not estimated on MySQL; pass exact to compute it"
+	}
+	return fmt.Sprintf("SELECT ST_AsText(ST_Envelope(ST_Collect(%s))) FROM %s", column, table), nil, ""
+}
+
+func (mysqlSpatialCatalog) indexFix(table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD SPATIAL INDEX (%s)", table, column)
+}
+
+func (mysqlSpatialCatalog) sridNote() string {
+	return "MySQL also ignores spatial indexes on columns without an SRID attribute"
+}
+
+// loadPostGISColumns reads the geometry and geography columns PostGIS registers, with the
+// GiST, SP-GiST and BRIN indexes on them
+func loadPostGISColumns(ctx context.Context, useCase UseCaseProvider, dbID, schema, table string) ([]spatialColumn, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, `
+SELECT f_table_schema::text, f_table_name::text, f_geometry_column::text, 'geometry', type, srid, coord_dimension
+FROM geometry_columns
+WHERE ($1 = '' OR f_table_schema = $1) AND ($2 = '' OR f_table_name = $2)
+UNION ALL
+SELECT f_table_schema::text, f_table_name::text, f_geography_column::text, 'geography', type, srid, coord_dimension
+FROM geography_columns
+WHERE ($1 = '' OR f_table_schema = $1) AND ($2 = '' OR f_table_name = $2)
+ORDER BY 1, 2, 3`, []interface{}{schema, table})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spatial columns: %w", err)
+	}
+	columns := spatialColumnsOf(result.Rows)
+
+	indexes, err := useCase.ExecuteQuery(ctx, dbID, `
+SELECT n.nspname, t.relname, a.attname, i.relname, am.amname
+FROM pg_index x
+JOIN pg_class i ON i.oid = x.indexrelid
+JOIN pg_class t ON t.oid = x.indrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+JOIN pg_am am ON am.oid = i.relam
+JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = ANY(x.indkey)
+WHERE am.amname IN ('gist', 'spgist', 'brin')
+  AND ($1 = '' OR n.nspname = $1) AND ($2 = '' OR t.relname = $2)`, []interface{}{schema, table})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spatial indexes: %w", err)
+	}
+	addSpatialIndexes(columns, indexes.Rows)
+	return columns, nil
+}
+
+// loadMySQLSpatialColumns reads the spatial columns of a MySQL schema, the SRID attribute of
+// each and the SPATIAL indexes on them
+func loadMySQLSpatialColumns(ctx context.Context, useCase UseCaseProvider, dbID, schema, table string) ([]spatialColumn, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, `
+SELECT c.TABLE_SCHEMA, c.TABLE_NAME, c.COLUMN_NAME, c.DATA_TYPE, UPPER(c.DATA_TYPE), COALESCE(g.SRS_ID, 0), 2
+FROM information_schema.COLUMNS c
+LEFT JOIN information_schema.ST_GEOMETRY_COLUMNS g
+  ON g.TABLE_SCHEMA = c.TABLE_SCHEMA AND g.TABLE_NAME = c.TABLE_NAME AND g.COLUMN_NAME = c.COLUMN_NAME
+WHERE c.DATA_TYPE IN ('geometry', 'point', 'linestring', 'polygon', 'multipoint', 'multilinestring',
+                      'multipolygon', 'geometrycollection', 'geomcollection')
+  AND c.TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+  AND (? = '' OR c.TABLE_NAME = ?)
+ORDER BY 1, 2, 3`, []interface{}{schema, table, table})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spatial columns: %w", err)
+	}
+	columns := spatialColumnsOf(result.Rows)
+
+	indexes, err := useCase.ExecuteQuery(ctx, dbID, `
+SELECT TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME, INDEX_NAME, 'spatial'
+FROM information_schema.STATISTICS
+WHERE INDEX_TYPE = 'SPATIAL'
+  AND TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+  AND (? = '' OR TABLE_NAME = ?)`, []interface{}{schema, table, table})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spatial indexes: %w", err)
+	}
+	addSpatialIndexes(columns, indexes.Rows)
+	return columns, nil
+}
+
+// spatialColumnsOf converts rows of schema, table, column, kind, type, SRID and dimensions
+func spatialColumnsOf(rows [][]interface{}) []spatialColumn {
+	columns := make([]spatialColumn, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 7 {
+			continue
+		}
+		columns = append(columns, spatialColumn{
+			Schema:       valueString(row[0]),
+			Table:        valueString(row[1]),
+			Column:       valueString(row[2]),
+			Kind:         valueString(row[3]),
+			GeometryType: valueString(row[4]),
+			SRID:         valueInt64(row[5]),
+			Dimensions:   valueInt64(row[6]),
+		})
+	}
+	return columns
+}
+
+// addSpatialIndexes attaches rows of schema, table, column, index name and access method to
+// the columns they index
+func addSpatialIndexes(columns []spatialColumn, rows [][]interface{}) {
+	for _, row := range rows {
+		if len(row) < 5 {
+			continue
+		}
+		schema, table, column := valueString(row[0]), valueString(row[1]), valueString(row[2])
+		for i := range columns {
+			c := &columns[i]
+			if c.Schema == schema && c.Table == table && c.Column == column {
+				c.Indexes = append(c.Indexes, fmt.Sprintf("%s (%s)", valueString(row[3]), valueString(row[4])))
+			}
+		}
+	}
+}
+
+// readSpatialExtent fills in the bounding box of a column. PostgreSQL estimates it from the
+// planner statistics unless exact is set; MySQL has no estimate, so it is only computed
+// with exact. Failures are noted on the column rather than failing the summary.
+func readSpatialExtent(ctx context.Context, useCase UseCaseProvider, dbID string, dialect Dialect, catalog spatialCatalog, c *spatialColumn, exact bool) {
+	table := dialect.QuoteIdent(c.Table)
+	if c.Schema != "" {
+		table = dialect.QuoteIdent(c.Schema) + "." + table
+	}
+	column := dialect.QuoteIdent(c.Column)
+
+	query, params, note := catalog.extentQuery(c, table, column, exact)
+	if query == "" {
+		c.ExtentNote = note
+		return
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, params)
+	if err != nil {
+		c.ExtentNote = fmt.Sprintf("unavailable: %v", err)
+		return
+	}
+	if len(result.Rows) > 0 && len(result.Rows[0]) > 0 {
+		c.Extent = valueString(result.Rows[0][0])
+	}
+	switch {
+	case c.Extent == "" && exact:
+		c.ExtentNote = "empty, the column has no values"
+	case c.Extent == "":
+		c.ExtentNote = "no statistics yet; run ANALYZE or pass exact"
+	case !exact:
+		c.ExtentNote = "estimated"
+	}
+}
+
+// writeSpatialSummary renders the spatial columns and warns about missing indexes and
+// unconstrained reference systems
+func writeSpatialSummary(sb *strings.Builder, catalog spatialCatalog, columns []spatialColumn) {
+	sb.WriteString("| Column | Kind | Geometry type | SRID | Dimensions | Extent | Spatial indexes |\n")
+	sb.WriteString("|--------|------|---------------|------|------------|--------|-----------------|\n")
+	var warnings []string
+	for _, c := range columns {
+		srid := fmt.Sprintf("%d", c.SRID)
+		if c.SRID == 0 {
+			srid = "any"
+		}
+		extent := c.Extent
+		if c.ExtentNote != "" {
+			extent = strings.TrimSpace(fmt.Sprintf("%s (%s)", extent, c.ExtentNote))
+		}
+		indexes := "none"
+		if len(c.Indexes) > 0 {
+			indexes = strings.Join(c.Indexes, ", ")
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %d | %s | %s |\n",
+			c.name(), c.Kind, c.GeometryType, srid, c.Dimensions, extent, indexes))
+
+		if len(c.Indexes) == 0 {
+			fix := catalog.indexFix(qualifiedName(c.Schema, c.Table), c.Column)
+			warnings = append(warnings, fmt.Sprintf("%s has no spatial index, so spatial filters such as ST_Intersects scan the whole table. Create one with %s.", c.name(), fix))
+		}
+		if c.SRID == 0 {
+			note := "values may mix reference systems, and distance and area results depend on each value's SRID"
+			if extra := catalog.sridNote(); extra != "" {
+				note += "; " + extra
+			}
+			warnings = append(warnings, fmt.Sprintf("%s is not constrained to an SRID: %s.", c.name(), note))
+		}
+	}
+	sb.WriteString(fmt.Sprintf("\n%d spatial columns\n", len(columns)))
+
+	if len(warnings) > 0 {
+		sb.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func spatialSummaryText(t *testing.T, useCase *mockUseCase, params map[string]interface{}) string {
+	params["database"] = "db1"
+	result, err := NewSpatialSummaryTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	assert.NoError(t, err)
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+}
+
+func TestSpatialSummaryPostGIS(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM geometry_columns": {Rows: [][]interface{}{
+				{"public", "parcels", "geom", "geometry", "MULTIPOLYGON", int64(4326), int64(2)},
+				{"public", "stops", "location", "geography", "POINT", int64(0), int64(2)},
+			}},
+			"pg_am": {Rows: [][]interface{}{
+				{"public", "parcels", "geom", "parcels_geom_idx", "gist"},
+			}},
+			"ST_EstimatedExtent": {Rows: [][]interface{}{{"BOX(1 2,3 4)"}}},
+		},
+	}
+
+	text := spatialSummaryText(t, useCase, map[string]interface{}{})
+	assert.Contains(t, text, "| public.parcels.geom | geometry | MULTIPOLYGON | 4326 | 2 | BOX(1 2,3 4) (estimated) | parcels_geom_idx (gist) |")
+	assert.Contains(t, text, "| public.stops.location | geography | POINT | any | 2 |")
+	assert.Contains(t, text, "public.stops.location has no spatial index")
+	assert.Contains(t, text, "CREATE INDEX ON public.stops USING GIST (location)")
+	assert.Contains(t, text, "public.stops.location is not constrained to an SRID")
+	assert.NotContains(t, text, "public.parcels.geom has no spatial index")
+}
+
+func TestSpatialSummaryExactExtent(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM geometry_columns": {Rows: [][]interface{}{
+				{"gis", "roads", "path", "geometry", "LINESTRING", int64(3857), int64(3)},
+			}},
+			"ST_Extent(": {Rows: [][]interface{}{{"BOX(0 0,10 10)"}}},
+		},
+	}
+
+	text := spatialSummaryText(t, useCase, map[string]interface{}{"table": "gis.roads", "exact": true})
+	assert.Contains(t, text, "| gis.roads.path | geometry | LINESTRING | 3857 | 3 | BOX(0 0,10 10) | none |")
+	assert.Contains(t, useCase.queries, `SELECT ST_Extent("path"::geometry)::text FROM "gis"."roads"`)
+}
+
+func TestSpatialSummaryWithoutPostGIS(t *testing.T) {
+	useCase := &mockUseCase{dbType: "postgres", queryErr: errors.New(`relation "geometry_columns" does not exist`)}
+
+	text := spatialSummaryText(t, useCase, map[string]interface{}{})
+	assert.Contains(t, text, "PostGIS is not installed")
+}
+
+func TestSpatialSummaryMySQL(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"ST_GEOMETRY_COLUMNS": {Rows: [][]interface{}{
+				{"shop", "stores", "location", "point", "POINT", int64(0), int64(2)},
+			}},
+			"INDEX_TYPE = 'SPATIAL'": {Rows: [][]interface{}{
+				{"shop", "stores", "location", "location", "spatial"},
+			}},
+		},
+	}
+
+	text := spatialSummaryText(t, useCase, map[string]interface{}{})
+	assert.Contains(t, text, "| shop.stores.location | point | POINT | any | 2 | (not estimated on MySQL; pass exact to compute it) | location (spatial) |")
+	assert.Contains(t, text, "MySQL also ignores spatial indexes on columns without an SRID attribute")
+	for _, query := range useCase.queries {
+		assert.NotContains(t, query, "ST_Envelope")
+	}
+}
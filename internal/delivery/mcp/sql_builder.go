@@ -0,0 +1,180 @@
+package mcp
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// quoteIdentifier quotes a possibly qualified identifier ("schema.table", "t.column", "t.*")
+// using the quoting rules of the database type
+func quoteIdentifier(dbType, ident string) string {
+	dialect := dialectFor(dbType)
+	parts := strings.Split(ident, ".")
+	for i, part := range parts {
+		if part == "*" {
+			continue
+		}
+		parts[i] = dialect.QuoteIdent(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// quoteLiteral quotes a string literal for statements that cannot take bound parameters, such as DDL
+func quoteLiteral(dbType, value string) string {
+	return dialectFor(dbType).QuoteLiteral(value)
+}
+
+// sqlParams collects bound parameters and renders the matching placeholders
+type sqlParams struct {
+	dbType  string
+	dialect Dialect
+	values  []interface{}
+}
+
+// newSQLParams creates an empty parameter list for the database type
+func newSQLParams(dbType string) *sqlParams {
+	return &sqlParams{dbType: strings.ToLower(dbType), dialect: dialectFor(dbType)}
+}
+
+// add binds a value and returns its placeholder
+func (p *sqlParams) add(value interface{}) string {
+	p.values = append(p.values, normalizeParamValue(value))
+	return p.dialect.Placeholder(len(p.values))
+}
+
+// normalizeParamValue converts JSON numbers without a fractional part to integers
+// so they bind cleanly to integer columns
+func normalizeParamValue(value interface{}) interface{} {
+	if f, ok := value.(float64); ok && f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return int64(f)
+	}
+	return value
+}
+
+// queryFilter is a typed condition on a single column
+type queryFilter struct {
+	Column   string
+	Operator string
+	Value    interface{}
+	Values   []interface{}
+}
+
+// filterOperators maps accepted filter operators to their SQL form
+var filterOperators = map[string]string{
+	"=":           "=",
+	"!=":          "<>",
+	"<>":          "<>",
+	"<":           "<",
+	"<=":          "<=",
+	">":           ">",
+	">=":          ">=",
+	"like":        "LIKE",
+	"not like":    "NOT LIKE",
+	"in":          "IN",
+	"not in":      "NOT IN",
+	"between":     "BETWEEN",
+	"is null":     "IS NULL",
+	"is not null": "IS NOT NULL",
+}
+
+// parseQueryFilters converts a filters parameter (an array of objects) into typed filters
+func parseQueryFilters(raw interface{}) ([]queryFilter, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("filters parameter must be an array of objects")
+	}
+
+	filters := make([]queryFilter, 0, len(items))
+	for i, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("filter %d must be an object", i+1)
+		}
+		column, _ := m["column"].(string)
+		if column == "" {
+			return nil, fmt.Errorf("filter %d must have a column", i+1)
+		}
+		operator := "="
+		if op, ok := m["operator"].(string); ok && op != "" {
+			operator = strings.ToLower(strings.Join(strings.Fields(op), " "))
+		}
+		if _, ok := filterOperators[operator]; !ok {
+			return nil, fmt.Errorf("filter %d has unsupported operator: %s", i+1, operator)
+		}
+
+		filter := queryFilter{Column: column, Operator: operator, Value: m["value"]}
+		if values, ok := m["values"].([]interface{}); ok {
+			filter.Values = values
+		}
+
+		switch operator {
+		case "in", "not in":
+			if len(filter.Values) == 0 {
+				return nil, fmt.Errorf("filter %d: operator %s requires a non-empty values array", i+1, operator)
+			}
+		case "between":
+			if len(filter.Values) != 2 {
+				return nil, fmt.Errorf("filter %d: operator between requires exactly two values", i+1)
+			}
+		case "is null", "is not null":
+		default:
+			if filter.Value == nil {
+				return nil, fmt.Errorf("filter %d: operator %s requires a value (use \"is null\" to match NULL)", i+1, operator)
+			}
+		}
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+// buildWhereClause renders filters joined by AND, binding every value as a parameter.
+// It returns an empty string when there are no filters.
+func buildWhereClause(filters []queryFilter, params *sqlParams) string {
+	conditions := make([]string, 0, len(filters))
+	for _, filter := range filters {
+		column := quoteIdentifier(params.dbType, filter.Column)
+		operator := filterOperators[filter.Operator]
+		switch filter.Operator {
+		case "is null", "is not null":
+			conditions = append(conditions, fmt.Sprintf("%s %s", column, operator))
+		case "in", "not in":
+			placeholders := make([]string, len(filter.Values))
+			for i, value := range filter.Values {
+				placeholders[i] = params.add(value)
+			}
+			conditions = append(conditions, fmt.Sprintf("%s %s (%s)", column, operator, strings.Join(placeholders, ", ")))
+		case "between":
+			conditions = append(conditions, fmt.Sprintf("%s BETWEEN %s AND %s", column, params.add(filter.Values[0]), params.add(filter.Values[1])))
+		default:
+			conditions = append(conditions, fmt.Sprintf("%s %s %s", column, operator, params.add(filter.Value)))
+		}
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// parseStringArray converts an array parameter into a slice of non-empty strings
+func parseStringArray(raw interface{}, name string) ([]string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s parameter must be an array of strings", name)
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s parameter must be an array of strings", name)
+		}
+		if s != "" {
+			values = append(values, s)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,802 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// sqlTokenKind classifies the lexical tokens of a SQL statement
+type sqlTokenKind int
+
+const (
+	sqlWord sqlTokenKind = iota
+	sqlNumber
+	sqlString
+	sqlQuotedIdent
+	sqlSymbol
+	sqlSpace
+	sqlComment
+)
+
+// sqlToken is a lexical token; strings and quoted identifiers hold their unquoted value
+type sqlToken struct {
+	kind  sqlTokenKind
+	text  string
+	quote rune
+	pos   int // offset of the token in the source, in runes
+}
+
+// is reports whether the token is the given keyword or symbol, ignoring case
+func (t sqlToken) is(text string) bool {
+	return (t.kind == sqlWord || t.kind == sqlSymbol) && strings.EqualFold(t.text, text)
+}
+
+// render returns the token as source text for the given dialect
+func (t sqlToken) render(dialect string) string {
+	switch t.kind {
+	case sqlString:
+		value := t.text
+		if dialect == "mysql" {
+			value = strings.ReplaceAll(value, `\`, `\\`)
+		}
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	case sqlQuotedIdent:
+		if dialect == "mysql" {
+			return "`" + strings.ReplaceAll(t.text, "`", "``") + "`"
+		}
+		return `"` + strings.ReplaceAll(t.text, `"`, `""`) + `"`
+	}
+	return t.text
+}
+
+// fragment returns a token that renders the text verbatim
+func fragment(text string) sqlToken {
+	return sqlToken{kind: sqlSymbol, text: text}
+}
+
+// tokenizeSQL splits a statement into tokens using the quoting rules of the dialect
+func tokenizeSQL(sql, dialect string) ([]sqlToken, error) {
+	runes := []rune(sql)
+	peek := func(i int) rune {
+		if i < len(runes) {
+			return runes[i]
+		}
+		return 0
+	}
+
+	var tokens []sqlToken
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		if r == '$' && dialect == "postgres" {
+			if tag := dollarQuoteTag(runes, i); tag != "" {
+				body := string(runes[i+len(tag):])
+				end := strings.Index(body, tag)
+				if end < 0 {
+					return nil, fmt.Errorf("unterminated %s quote", tag)
+				}
+				value := body[:end]
+				tokens = append(tokens, sqlToken{kind: sqlString, text: value, quote: '$', pos: start})
+				i += len(tag) + len([]rune(value)) + len(tag)
+				continue
+			}
+		}
+		switch {
+		case unicode.IsSpace(r):
+			for i < len(runes) && unicode.IsSpace(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlSpace, text: string(runes[start:i]), pos: start})
+		case (r == '-' && peek(i+1) == '-') || (r == '#' && dialect == "mysql"):
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlComment, text: string(runes[start:i]), pos: start})
+		case r == '/' && peek(i+1) == '*':
+			for i += 2; i < len(runes) && !(runes[i-1] == '*' && runes[i] == '/'); i++ {
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated block comment")
+			}
+			i++
+			tokens = append(tokens, sqlToken{kind: sqlComment, text: string(runes[start:i]), pos: start})
+		case r == '\'' || (r == '"' && dialect == "mysql"):
+			value, next, err := scanQuoted(runes, i, dialect == "mysql")
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, sqlToken{kind: sqlString, text: value, quote: r, pos: start})
+			i = next
+		case r == '"' || r == '`':
+			value, next, err := scanQuoted(runes, i, false)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, sqlToken{kind: sqlQuotedIdent, text: value, quote: r, pos: start})
+			i = next
+		case unicode.IsDigit(r) || (r == '.' && unicode.IsDigit(peek(i+1))):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlNumber, text: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '$') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlWord, text: string(runes[start:i]), pos: start})
+		default:
+			i++
+			if (r == ':' && peek(i) == ':') || (r == '|' && peek(i) == '|') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlSymbol, text: string(runes[start:i]), pos: start})
+		}
+	}
+	return tokens, nil
+}
+
+// scanQuoted reads a quoted string or identifier starting at runes[start] and returns its value
+func scanQuoted(runes []rune, start int, backslashEscapes bool) (string, int, error) {
+	quote := runes[start]
+	var sb strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case backslashEscapes && r == '\\' && i+1 < len(runes):
+			i++
+			switch runes[i] {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case 'r':
+				sb.WriteRune('\r')
+			case '0':
+				sb.WriteRune(0)
+			case '%', '_':
+				// MySQL keeps the backslash so the escape still applies in LIKE patterns
+				sb.WriteRune('\\')
+				sb.WriteRune(runes[i])
+			default:
+				sb.WriteRune(runes[i])
+			}
+		case r == quote:
+			if i+1 < len(runes) && runes[i+1] == quote {
+				sb.WriteRune(quote)
+				i++
+				continue
+			}
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated %c quote", quote)
+}
+
+// dollarQuoteTag returns the PostgreSQL dollar-quote tag such as $$ or $body$ starting at runes[start], or ""
+func dollarQuoteTag(runes []rune, start int) string {
+	for i := start + 1; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '$':
+			return string(runes[start : i+1])
+		case unicode.IsLetter(r) || r == '_' || (unicode.IsDigit(r) && i > start+1):
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// splitSQLStatements splits a script into statements on semicolons outside strings,
+// identifiers and comments; empty statements are dropped. SQLite trigger bodies hold
+// semicolons of their own, so a trigger only ends at a semicolon after END.
+func splitSQLStatements(script, dialect string) ([]string, error) {
+	tokens, err := tokenizeSQL(script, dialect)
+	if err != nil {
+		return nil, err
+	}
+	runes := []rune(script)
+	var statements []string
+	start := 0
+	flush := func(end int) {
+		if statement := strings.TrimSpace(string(runes[start:end])); statement != "" && !onlyComments(tokens, start, end) {
+			statements = append(statements, statement)
+		}
+	}
+	var words int
+	var trigger bool
+	var last sqlToken
+	for _, tok := range tokens {
+		if tok.kind == sqlWord && words < 3 {
+			words++
+			trigger = trigger || (dialect == "sqlite" && words > 1 && tok.is("TRIGGER"))
+		}
+		if tok.is(";") && (!trigger || last.is("END")) {
+			flush(tok.pos)
+			start = tok.pos + 1
+			words, trigger = 0, false
+		}
+		if tok.kind != sqlSpace && tok.kind != sqlComment {
+			last = tok
+		}
+	}
+	flush(len(runes))
+	return statements, nil
+}
+
+// onlyComments reports whether the tokens between the rune offsets start and end are all comments or whitespace
+func onlyComments(tokens []sqlToken, start, end int) bool {
+	for _, tok := range tokens {
+		if tok.pos >= start && tok.pos < end && tok.kind != sqlSpace && tok.kind != sqlComment {
+			return false
+		}
+	}
+	return true
+}
+
+// nextSignificant returns the index of the next token at or after i that is not whitespace or a comment
+func nextSignificant(tokens []sqlToken, i int) int {
+	for i < len(tokens) && (tokens[i].kind == sqlSpace || tokens[i].kind == sqlComment) {
+		i++
+	}
+	return i
+}
+
+// matchingParen returns the index of the parenthesis closing the one at open, or -1
+func matchingParen(tokens []sqlToken, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch {
+		case tokens[i].is("("):
+			depth++
+		case tokens[i].is(")"):
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// trimTokens removes leading and trailing whitespace tokens
+func trimTokens(tokens []sqlToken) []sqlToken {
+	for len(tokens) > 0 && tokens[0].kind == sqlSpace {
+		tokens = tokens[1:]
+	}
+	for len(tokens) > 0 && tokens[len(tokens)-1].kind == sqlSpace {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return tokens
+}
+
+// splitArgs splits the tokens between a function's parentheses on top-level commas
+func splitArgs(tokens []sqlToken) [][]sqlToken {
+	if len(trimTokens(tokens)) == 0 {
+		return nil
+	}
+	var args [][]sqlToken
+	depth, start := 0, 0
+	for i, tok := range tokens {
+		switch {
+		case tok.is("("):
+			depth++
+		case tok.is(")"):
+			depth--
+		case tok.is(",") && depth == 0:
+			args = append(args, trimTokens(tokens[start:i]))
+			start = i + 1
+		}
+	}
+	return append(args, trimTokens(tokens[start:]))
+}
+
+// containsWord reports whether any of the tokens is one of the given keywords
+func containsWord(tokens []sqlToken, words ...string) bool {
+	for _, tok := range tokens {
+		for _, word := range words {
+			if tok.kind == sqlWord && tok.is(word) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// callTokens builds the tokens of a function call
+func callTokens(name string, args ...[]sqlToken) []sqlToken {
+	out := []sqlToken{fragment(name + "(")}
+	for i, arg := range args {
+		if i > 0 {
+			out = append(out, fragment(", "))
+		}
+		out = append(out, arg...)
+	}
+	return append(out, fragment(")"))
+}
+
+// sqlTranslation is the result of translating a statement between dialects
+type sqlTranslation struct {
+	From     string
+	To       string
+	SQL      string
+	Changes  []string
+	Warnings []string
+}
+
+// sqlTranslator rewrites tokens from one dialect to another and records what it did
+type sqlTranslator struct {
+	from        string
+	to          string
+	changes     []string
+	warnings    []string
+	seen        map[string]bool
+	placeholder int
+}
+
+func (tr *sqlTranslator) change(msg string) {
+	if !tr.seen["c:"+msg] {
+		tr.seen["c:"+msg] = true
+		tr.changes = append(tr.changes, msg)
+	}
+}
+
+func (tr *sqlTranslator) warn(msg string) {
+	if !tr.seen["w:"+msg] {
+		tr.seen["w:"+msg] = true
+		tr.warnings = append(tr.warnings, msg)
+	}
+}
+
+// translateSQL converts a statement between MySQL and PostgreSQL, flagging constructs it cannot translate
+func translateSQL(sql, from, to string) (*sqlTranslation, error) {
+	from, to = strings.ToLower(from), strings.ToLower(to)
+	for _, dialect := range []string{from, to} {
+		if dialect != "mysql" && dialect != "postgres" {
+			return nil, fmt.Errorf("unsupported dialect: %s (expected mysql or postgres)", dialect)
+		}
+	}
+	if from == to {
+		return nil, fmt.Errorf("source and target dialects are both %s", from)
+	}
+
+	tokens, err := tokenizeSQL(sql, from)
+	if err != nil {
+		return nil, err
+	}
+
+	tr := &sqlTranslator{from: from, to: to, seen: make(map[string]bool)}
+	var sb strings.Builder
+	for _, tok := range tr.rewrite(tokens) {
+		sb.WriteString(tok.render(to))
+	}
+
+	return &sqlTranslation{
+		From:     from,
+		To:       to,
+		SQL:      sb.String(),
+		Changes:  tr.changes,
+		Warnings: tr.warnings,
+	}, nil
+}
+
+// rewrite translates a token sequence
+func (tr *sqlTranslator) rewrite(tokens []sqlToken) []sqlToken {
+	var out []sqlToken
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch tok.kind {
+		case sqlQuotedIdent:
+			if tok.quote == '`' {
+				tr.change("Backtick-quoted identifiers converted to double quotes")
+			} else {
+				tr.change("Double-quoted identifiers converted to backticks")
+			}
+		case sqlString:
+			if tok.quote == '"' {
+				tr.change("Double-quoted strings converted to single quotes")
+			}
+		case sqlComment:
+			if strings.HasPrefix(tok.text, "#") {
+				tok.text = "--" + tok.text[1:]
+				tr.change("# comments converted to --")
+			}
+		case sqlWord:
+			if next, replaced := tr.rewriteCall(tokens, i, &out); replaced {
+				i = next
+				continue
+			}
+			if next, replaced := tr.rewriteKeyword(tokens, i, &out); replaced {
+				i = next
+				continue
+			}
+		case sqlSymbol:
+			if next, replaced := tr.rewriteSymbol(tokens, i, &out); replaced {
+				i = next
+				continue
+			}
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+// sqlFunctionRewrite translates a call given its already translated arguments
+type sqlFunctionRewrite func(tr *sqlTranslator, args [][]sqlToken) ([]sqlToken, bool)
+
+// sqlFunctionRewrites maps "dialect:FUNCTION" to the rewrite applied when translating away from that dialect
+var sqlFunctionRewrites = map[string]sqlFunctionRewrite{
+	"mysql:IFNULL": func(tr *sqlTranslator, args [][]sqlToken) ([]sqlToken, bool) {
+		if len(args) != 2 {
+			return nil, false
+		}
+		tr.change("IFNULL() converted to COALESCE()")
+		return callTokens("COALESCE", args...), true
+	},
+	"mysql:IF": func(tr *sqlTranslator, args [][]sqlToken) ([]sqlToken, bool) {
+		if len(args) != 3 {
+			return nil, false
+		}
+		tr.change("IF() converted to CASE WHEN")
+		out := append([]sqlToken{fragment("CASE WHEN ")}, args[0]...)
+		out = append(append(out, fragment(" THEN ")), args[1]...)
+		out = append(append(out, fragment(" ELSE ")), args[2]...)
+		return append(out, fragment(" END")), true
+	},
+	"mysql:RAND": func(tr *sqlTranslator, args [][]sqlToken) ([]sqlToken, bool) {
+		if len(args) != 0 {
+			tr.warn("RAND(seed) has no direct equivalent; use SETSEED() before RANDOM()")
+			return nil, false
+		}
+		tr.change("RAND() converted to RANDOM()")
+		return callTokens("RANDOM"), true
+	},
+	"mysql:CURDATE": func(tr *sqlTranslator, args [][]sqlToken) ([]sqlToken, bool) {
+		if len(args) != 0 {
+			return nil, false
+		}
+		tr.change("CURDATE() converted to CURRENT_DATE")
+		return []sqlToken{fragment("CURRENT_DATE")}, true
+	},
+	"mysql:CURTIME": func(tr *sqlTranslator, args [][]sqlToken) ([]sqlToken, bool) {
+		if len(args) != 0 {
+			return nil, false
+		}
+		tr.change("CURTIME() converted to CURRENT_TIME")
+		return []sqlToken{fragment("CURRENT_TIME")}, true
+	},
+	"mysql:DATE_ADD": dateArithmetic("+"),
+	"mysql:ADDDATE":  dateArithmetic("+"),
+	"mysql:DATE_SUB": dateArithmetic("-"),
+	"mysql:SUBDATE":  dateArithmetic("-"),
+	"mysql:DATEDIFF": func(tr *sqlTranslator, args [][]sqlToken) ([]sqlToken, bool) {
+		if len(args) != 2 {
+			return nil, false
+		}
+		tr.change("DATEDIFF() converted to date subtraction")
+		out := append([]sqlToken{fragment("(CAST(")}, args[0]...)
+		out = append(append(out, fragment(" AS DATE) - CAST(")), args[1]...)
+		return append(out, fragment(" AS DATE))")), true
+	},
+	"mysql:UNIX_TIMESTAMP": func(tr *sqlTranslator, args [][]sqlToken) ([]sqlToken, bool) {
+		if len(args) > 1 {
+			return nil, false
+		}
+		tr.change("UNIX_TIMESTAMP() converted to EXTRACT(EPOCH FROM ...)")
+		source := []sqlToken{fragment("NOW()")}
+		if len(args) == 1 {
+			source = args[0]
+		}
+		out := append([]sqlToken{fragment("EXTRACT(EPOCH FROM ")}, source...)
+		return append(out, fragment(")")), true
+	},
+	"mysql:GROUP_CONCAT": func(tr *sqlTranslator, args [][]sqlToken) ([]sqlToken, bool) {
+		if len(args) != 1 || containsWord(args[0], "SEPARATOR", "ORDER", "DISTINCT") {
+			tr.warn("GROUP_CONCAT() with DISTINCT, ORDER BY, SEPARATOR or several arguments must be rewritten as STRING_AGG() by hand")
+			return nil, false
+		}
+		tr.change("GROUP_CONCAT() converted to STRING_AGG()")
+		out := append([]sqlToken{fragment("STRING_AGG(CAST(")}, args[0]...)
+		return append(out, fragment(" AS TEXT), ',')")), true
+	},
+	"mysql:LAST_INSERT_ID": func(tr *sqlTranslator, args [][]sqlToken) ([]sqlToken, bool) {
+		tr.warn("LAST_INSERT_ID() has no equivalent; use INSERT ... RETURNING or LASTVAL()")
+		return nil, false
+	},
+	"postgres:RANDOM": func(tr *sqlTranslator, args [][]sqlToken) ([]sqlToken, bool) {
+		if len(args) != 0 {
+			return nil, false
+		}
+		tr.change("RANDOM() converted to RAND()")
+		return callTokens("RAND"), true
+	},
+	"postgres:STRING_AGG": func(tr *sqlTranslator, args [][]sqlToken) ([]sqlToken, bool) {
+		if len(args) != 2 || containsWord(args[1], "ORDER") {
+			tr.warn("STRING_AGG() with ORDER BY must be rewritten as GROUP_CONCAT() by hand")
+			return nil, false
+		}
+		tr.change("STRING_AGG() converted to GROUP_CONCAT()")
+		out := append([]sqlToken{fragment("GROUP_CONCAT(")}, args[0]...)
+		out = append(append(out, fragment(" SEPARATOR ")), args[1]...)
+		return append(out, fragment(")")), true
+	},
+	"postgres:DATE_TRUNC": func(tr *sqlTranslator, args [][]sqlToken) ([]sqlToken, bool) {
+		tr.warn("DATE_TRUNC() has no equivalent; use DATE(), DATE_FORMAT() or arithmetic on the value")
+		return nil, false
+	},
+	"postgres:TO_CHAR": func(tr *sqlTranslator, args [][]sqlToken) ([]sqlToken, bool) {
+		tr.warn("TO_CHAR() format patterns differ; use DATE_FORMAT() or FORMAT()")
+		return nil, false
+	},
+	"postgres:GENERATE_SERIES": func(tr *sqlTranslator, args [][]sqlToken) ([]sqlToken, bool) {
+		tr.warn("GENERATE_SERIES() has no equivalent; use a recursive CTE")
+		return nil, false
+	},
+}
+
+// dateArithmetic translates DATE_ADD-style calls into interval arithmetic
+func dateArithmetic(operator string) sqlFunctionRewrite {
+	return func(tr *sqlTranslator, args [][]sqlToken) ([]sqlToken, bool) {
+		if len(args) != 2 || len(args[1]) == 0 || !args[1][0].is("INTERVAL") {
+			tr.warn("DATE_ADD()/DATE_SUB() without an INTERVAL argument must be rewritten by hand")
+			return nil, false
+		}
+		tr.change("DATE_ADD()/DATE_SUB() converted to interval arithmetic")
+		out := append([]sqlToken{fragment("(")}, args[0]...)
+		out = append(append(out, fragment(" "+operator+" ")), args[1]...)
+		return append(out, fragment(")")), true
+	}
+}
+
+// rewriteCall translates a function call starting at tokens[i]
+func (tr *sqlTranslator) rewriteCall(tokens []sqlToken, i int, out *[]sqlToken) (int, bool) {
+	rewrite, ok := sqlFunctionRewrites[tr.from+":"+strings.ToUpper(tokens[i].text)]
+	if !ok {
+		return i, false
+	}
+	open := nextSignificant(tokens, i+1)
+	if open >= len(tokens) || !tokens[open].is("(") {
+		return i, false
+	}
+	end := matchingParen(tokens, open)
+	if end < 0 {
+		return i, false
+	}
+
+	inner := tr.rewrite(tokens[open+1 : end])
+	if replacement, ok := rewrite(tr, splitArgs(inner)); ok {
+		*out = append(*out, replacement...)
+		return end, true
+	}
+	*out = append(*out, tokens[i:open+1]...)
+	*out = append(*out, inner...)
+	*out = append(*out, tokens[end])
+	return end, true
+}
+
+// sqlDialectOnlyKeywords lists keywords that need manual rewriting when leaving a dialect
+var sqlDialectOnlyKeywords = map[string]string{
+	"mysql:DUPLICATE":           "ON DUPLICATE KEY UPDATE must be rewritten as ON CONFLICT (...) DO UPDATE",
+	"mysql:AUTO_INCREMENT":      "AUTO_INCREMENT must be rewritten as an identity or serial column",
+	"mysql:UNSIGNED":            "UNSIGNED types have no equivalent; add a CHECK constraint if needed",
+	"mysql:STRAIGHT_JOIN":       "STRAIGHT_JOIN has no equivalent; the planner chooses the join order",
+	"mysql:SQL_CALC_FOUND_ROWS": "SQL_CALC_FOUND_ROWS has no equivalent; run a separate COUNT(*) or use COUNT(*) OVER ()",
+	"mysql:ENGINE":              "Table options such as ENGINE must be removed",
+	"postgres:RETURNING":        "RETURNING is not supported; query the rows after the statement",
+	"postgres:CONFLICT":         "ON CONFLICT must be rewritten as INSERT IGNORE or ON DUPLICATE KEY UPDATE",
+	"postgres:SERIAL":           "SERIAL must be rewritten as an AUTO_INCREMENT column",
+	"postgres:BIGSERIAL":        "BIGSERIAL must be rewritten as a BIGINT AUTO_INCREMENT column",
+}
+
+// mysqlIntervalUnits lists the interval units that translate one-to-one
+var mysqlIntervalUnits = map[string]string{
+	"microsecond": "MICROSECOND",
+	"second":      "SECOND",
+	"sec":         "SECOND",
+	"minute":      "MINUTE",
+	"min":         "MINUTE",
+	"hour":        "HOUR",
+	"day":         "DAY",
+	"week":        "WEEK",
+	"month":       "MONTH",
+	"mon":         "MONTH",
+	"year":        "YEAR",
+}
+
+var postgresIntervalPattern = regexp.MustCompile(`^\s*(-?\d+)\s*([a-zA-Z]+)\s*$`)
+
+// rewriteKeyword translates keyword-level constructs starting at tokens[i]
+func (tr *sqlTranslator) rewriteKeyword(tokens []sqlToken, i int, out *[]sqlToken) (int, bool) {
+	word := strings.ToUpper(tokens[i].text)
+	if msg, ok := sqlDialectOnlyKeywords[tr.from+":"+word]; ok {
+		tr.warn(msg)
+		return i, false
+	}
+
+	next := nextSignificant(tokens, i+1)
+	switch tr.from + ":" + word {
+	case "mysql:LIMIT":
+		// LIMIT offset, count
+		comma := nextSignificant(tokens, next+1)
+		count := nextSignificant(tokens, comma+1)
+		if count < len(tokens) && tokens[comma].is(",") {
+			tr.change("LIMIT offset, count converted to LIMIT count OFFSET offset")
+			offset := tr.rewrite(tokens[next : next+1])
+			*out = append(*out, tokens[i], fragment(" "))
+			*out = append(*out, tr.rewrite(tokens[count:count+1])...)
+			*out = append(*out, fragment(" OFFSET "))
+			*out = append(*out, offset...)
+			return count, true
+		}
+	case "mysql:INTERVAL":
+		unit := nextSignificant(tokens, next+1)
+		if unit >= len(tokens) || tokens[next].kind != sqlNumber {
+			tr.warn("INTERVAL with a non-literal amount must be rewritten as amount * INTERVAL '1 unit'")
+			return i, false
+		}
+		if _, ok := mysqlIntervalUnits[strings.ToLower(tokens[unit].text)]; !ok {
+			tr.warn(fmt.Sprintf("INTERVAL unit %s must be rewritten by hand", strings.ToUpper(tokens[unit].text)))
+			return i, false
+		}
+		tr.change("INTERVAL n UNIT converted to INTERVAL 'n unit'")
+		*out = append(*out, tokens[i], fragment(" "), sqlToken{
+			kind: sqlString,
+			text: tokens[next].text + " " + strings.ToLower(tokens[unit].text),
+		})
+		return unit, true
+	case "mysql:REPLACE":
+		if next < len(tokens) && tokens[next].is("INTO") {
+			tr.warn("REPLACE INTO must be rewritten as INSERT ... ON CONFLICT (...) DO UPDATE")
+		}
+	case "mysql:REGEXP", "mysql:RLIKE":
+		tr.change("REGEXP converted to the case-insensitive ~* operator")
+		*out = append(*out, fragment("~*"))
+		return i, true
+	case "postgres:ILIKE":
+		tr.change("ILIKE converted to LIKE (case-insensitive under MySQL's default collations)")
+		*out = append(*out, fragment("LIKE"))
+		return i, true
+	case "postgres:DISTINCT":
+		if next < len(tokens) && tokens[next].is("ON") {
+			tr.warn("DISTINCT ON must be rewritten with ROW_NUMBER() OVER (PARTITION BY ...)")
+		}
+	case "postgres:OFFSET":
+		if !containsWord(*out, "LIMIT") {
+			tr.warn("OFFSET without LIMIT is not supported; add LIMIT 18446744073709551615")
+		}
+	case "postgres:INTERVAL":
+		if next >= len(tokens) || tokens[next].kind != sqlString {
+			return i, false
+		}
+		match := postgresIntervalPattern.FindStringSubmatch(tokens[next].text)
+		if match == nil {
+			tr.warn(fmt.Sprintf("INTERVAL '%s' must be rewritten by hand", tokens[next].text))
+			return i, false
+		}
+		unit := strings.ToLower(match[2])
+		mysqlUnit, ok := mysqlIntervalUnits[unit]
+		if !ok {
+			mysqlUnit, ok = mysqlIntervalUnits[strings.TrimSuffix(unit, "s")]
+		}
+		if !ok {
+			tr.warn(fmt.Sprintf("INTERVAL '%s' must be rewritten by hand", tokens[next].text))
+			return i, false
+		}
+		tr.change("INTERVAL 'n unit' converted to INTERVAL n UNIT")
+		*out = append(*out, tokens[i], fragment(" "+match[1]+" "+mysqlUnit))
+		return next, true
+	}
+	return i, false
+}
+
+// postgresCastTypes maps PostgreSQL cast targets to MySQL CAST() types
+var postgresCastTypes = map[string]string{
+	"int":       "SIGNED",
+	"int2":      "SIGNED",
+	"int4":      "SIGNED",
+	"int8":      "SIGNED",
+	"integer":   "SIGNED",
+	"smallint":  "SIGNED",
+	"bigint":    "SIGNED",
+	"text":      "CHAR",
+	"varchar":   "CHAR",
+	"date":      "DATE",
+	"time":      "TIME",
+	"timestamp": "DATETIME",
+	"numeric":   "DECIMAL",
+	"decimal":   "DECIMAL",
+	"json":      "JSON",
+	"jsonb":     "JSON",
+}
+
+// rewriteSymbol translates operators and placeholders starting at tokens[i]
+func (tr *sqlTranslator) rewriteSymbol(tokens []sqlToken, i int, out *[]sqlToken) (int, bool) {
+	switch {
+	case tr.from == "mysql" && tokens[i].is("?"):
+		tr.placeholder++
+		tr.change("? placeholders converted to $n")
+		*out = append(*out, fragment(fmt.Sprintf("$%d", tr.placeholder)))
+		return i, true
+	case tr.from == "postgres" && tokens[i].is("$") && i+1 < len(tokens) && tokens[i+1].kind == sqlNumber:
+		if tokens[i+1].text != fmt.Sprintf("%d", tr.placeholder+1) {
+			tr.warn("$n placeholders that are reused or out of order must be rebound by hand")
+			return i, false
+		}
+		tr.placeholder++
+		tr.change("$n placeholders converted to ?")
+		*out = append(*out, fragment("?"))
+		return i + 1, true
+	case tr.from == "postgres" && tokens[i].is("||"):
+		tr.warn("|| is logical OR in MySQL unless PIPES_AS_CONCAT is set; use CONCAT()")
+	case tr.from == "postgres" && tokens[i].is("::"):
+		typeIndex := nextSignificant(tokens, i+1)
+		start := castOperandStart(*out)
+		if typeIndex >= len(tokens) || start < 0 {
+			tr.warn(":: casts must be rewritten as CAST(... AS ...)")
+			return i, false
+		}
+		mysqlType, ok := postgresCastTypes[strings.ToLower(tokens[typeIndex].text)]
+		if !ok {
+			tr.warn(fmt.Sprintf("Cast to %s must be rewritten as CAST(... AS ...)", tokens[typeIndex].text))
+			return i, false
+		}
+		end := typeIndex
+		if open := nextSignificant(tokens, typeIndex+1); open < len(tokens) && tokens[open].is("(") && mysqlType == "DECIMAL" {
+			if closing := matchingParen(tokens, open); closing > 0 {
+				for _, tok := range tokens[open : closing+1] {
+					mysqlType += tok.text
+				}
+				end = closing
+			}
+		}
+		tr.change(":: casts converted to CAST(... AS ...)")
+		operand := append([]sqlToken(nil), (*out)[start:]...)
+		*out = append((*out)[:start], fragment("CAST("))
+		*out = append(*out, operand...)
+		*out = append(*out, fragment(" AS "+mysqlType+")"))
+		return end, true
+	}
+	return i, false
+}
+
+// castOperandStart returns the index in out where the operand of a trailing :: cast begins, or -1
+func castOperandStart(out []sqlToken) int {
+	i := len(out) - 1
+	if i < 0 {
+		return -1
+	}
+	switch last := out[i]; {
+	case last.is(")"):
+		depth := 0
+		for ; i >= 0; i-- {
+			if out[i].is(")") {
+				depth++
+			} else if out[i].is("(") {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
+		}
+		if i < 0 {
+			return -1
+		}
+		if i > 0 && out[i-1].kind == sqlWord {
+			i--
+		}
+		return i
+	case last.kind == sqlString || last.kind == sqlNumber:
+		return i
+	case last.kind == sqlWord || last.kind == sqlQuotedIdent:
+		// Include qualified names such as t.col
+		for i >= 2 && out[i-1].is(".") && (out[i-2].kind == sqlWord || out[i-2].kind == sqlQuotedIdent) {
+			i -= 2
+		}
+		return i
+	}
+	return -1
+}
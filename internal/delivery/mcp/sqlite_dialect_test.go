@@ -0,0 +1,167 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+// sqliteUseCase runs queries on a real SQLite database, so the catalog queries of the
+// SQLite dialect are checked against the engine instead of canned results
+type sqliteUseCase struct {
+	*mockUseCase
+	db *sql.DB
+}
+
+func (s *sqliteUseCase) ExecuteQuery(ctx context.Context, dbID, query string, params []interface{}) (*domain.QueryResult, error) {
+	rows, err := s.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.QueryResult{Columns: columns}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	return result, rows.Err()
+}
+
+func newSQLiteUseCase(t *testing.T) *sqliteUseCase {
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "shop.db")+"?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("Failed to open SQLite database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	for _, statement := range []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL UNIQUE, name TEXT)`,
+		`CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users (id), total REAL, created_at TEXT)`,
+		`CREATE INDEX idx_orders_user ON orders (user_id, created_at)`,
+		`CREATE VIEW big_orders AS SELECT * FROM orders WHERE total > 100`,
+		`INSERT INTO users (email, name) VALUES ('ada@example.com', 'Ada')`,
+		`INSERT INTO orders (user_id, total) VALUES (1, 150)`,
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("Failed to run %s: %v", statement, err)
+		}
+	}
+	return &sqliteUseCase{mockUseCase: &mockUseCase{dbType: "sqlite"}, db: db}
+}
+
+func TestSQLiteDialectQueriesRun(t *testing.T) {
+	useCase := newSQLiteUseCase(t)
+	ctx := context.Background()
+	dialect, ok := lookupDialect("sqlite")
+	assert.True(t, ok)
+	// sqlite_stat1, read by the detailed statistics, only exists once ANALYZE has run
+	_, err := useCase.db.Exec("ANALYZE")
+	assert.NoError(t, err)
+
+	queries := []string{
+		dialect.IndexQuery("", true),
+		dialect.ConstraintQuery("", ""),
+		dialect.SchemaQuery("", false),
+		dialect.ViewQuery("", true),
+	}
+	queries = append(queries, dialect.DatabaseStatsQueries(true)...)
+	for _, section := range dialect.TableStatsSections("orders") {
+		queries = append(queries, section.query)
+	}
+	for _, query := range queries {
+		result, err := useCase.ExecuteQuery(ctx, "shop", query, nil)
+		if assert.NoError(t, err, query) {
+			assert.NotEmpty(t, result.Rows, query)
+		}
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, "shop", dialect.IndexQuery("orders", false), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"orders", "idx_orders_user", "btree", "INDEX", "user_id, created_at"}, result.Rows[0])
+
+	result, err = useCase.ExecuteQuery(ctx, "shop", dialect.ConstraintQuery("orders", "foreign key"), nil)
+	assert.NoError(t, err)
+	assert.Len(t, result.Rows, 1)
+	assert.Equal(t, []interface{}{"main", "orders", "orders_fk0", "FOREIGN KEY", "user_id", "users", "id"}, result.Rows[0])
+}
+
+func TestSQLiteSchemaMetadata(t *testing.T) {
+	meta, err := loadSchemaMetadata(context.Background(), newSQLiteUseCase(t), "shop", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "main", meta.Schema)
+	assert.Len(t, meta.Tables, 2)
+
+	orders := meta.table(schemaTableKey("main", "orders"))
+	assert.NotNil(t, orders)
+	assert.Equal(t, []string{"id"}, orders.PrimaryKey)
+	assert.False(t, orders.column("user_id").Nullable)
+	assert.True(t, orders.column("total").Nullable)
+	assert.Len(t, orders.Indexes, 1)
+	assert.Equal(t, []string{"user_id", "created_at"}, orders.Indexes[0].Columns)
+
+	users := meta.table(schemaTableKey("main", "users"))
+	assert.Equal(t, [][]string{{"email"}}, users.Unique)
+	assert.Equal(t, []schemaForeignKey{{
+		Name: "orders_fk0", Schema: "main", Table: "orders", Columns: []string{"user_id"},
+		RefSchema: "main", RefTable: "users", RefColumns: []string{"id"},
+	}}, meta.ForeignKeys)
+}
+
+func TestSQLiteQueryPlan(t *testing.T) {
+	useCase := newSQLiteUseCase(t)
+	ctx := context.Background()
+
+	plan, err := loadQueryPlan(ctx, useCase, "shop", "SELECT * FROM orders WHERE user_id = 1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"idx_orders_user"}, plan.indexesUsed())
+	assert.Empty(t, plan.fullScans())
+
+	plan, err = loadQueryPlan(ctx, useCase, "shop", "SELECT orders.total FROM orders JOIN users ON users.id = orders.user_id ORDER BY orders.total")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"orders"}, plan.fullScans())
+	assert.Contains(t, plan.render(), "-> SEARCH on users using rowid")
+	explanation := explainPlanInEnglish(plan)
+	assert.Contains(t, explanation, "Reads every row of orders")
+	assert.Contains(t, explanation, "Looks up rows of users by rowid")
+	assert.Contains(t, explanation, "Sorts the rows in a temporary B-tree")
+
+	reparsed, err := parseQueryPlan("sqlite", plan.Raw)
+	assert.NoError(t, err)
+	assert.Equal(t, plan.render(), reparsed.render())
+}
+
+func TestSQLiteSchemaSearchAndMigrations(t *testing.T) {
+	useCase := newSQLiteUseCase(t)
+	ctx := context.Background()
+
+	query, params, err := schemaSearch{pattern: "user"}.query("sqlite")
+	assert.NoError(t, err)
+	result, err := useCase.ExecuteQuery(ctx, "shop", query, params)
+	assert.NoError(t, err)
+	var matches []string
+	for _, row := range result.Rows {
+		matches = append(matches, valueString(row[1])+"."+valueString(row[2]))
+	}
+	assert.Contains(t, matches, "users.email")
+	assert.Contains(t, matches, "orders.user_id")
+
+	applied, err := loadAppliedMigrations(ctx, useCase, "shop", "sqlite")
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+}
@@ -0,0 +1,259 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// SuggestJoinsTool handles suggesting joins among a set of tables
+type SuggestJoinsTool struct {
+	BaseToolType
+}
+
+// NewSuggestJoinsTool creates a new suggest joins tool type
+func NewSuggestJoinsTool() *SuggestJoinsTool {
+	return &SuggestJoinsTool{
+		BaseToolType: BaseToolType{
+			name:        "suggest_joins",
+			description: "Suggest how to join a set of tables. Given several tables, this tool lists the direct foreign key relationships among them and the transitive routes through intermediate tables for pairs that are not directly related, each with a ready-to-use JOIN snippet, plus a combined FROM clause connecting all of them. It warns when a route goes through a many-to-many link table, since joining through it multiplies rows.",
+		},
+	}
+}
+
+// CreateTool creates a suggest joins tool
+func (t *SuggestJoinsTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Suggest direct and transitive joins among a set of tables"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithArray("tables",
+			tools.Description("Tables to join (at least two)"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to inspect (optional, defaults to public on PostgreSQL and the current database on MySQL)"),
+		),
+		tools.WithNumber("max_depth",
+			tools.Description("Maximum number of joins in a transitive route (default: 3)"),
+		),
+	)
+}
+
+// HandleRequest handles suggest joins tool requests
+func (t *SuggestJoinsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	tableNames := input.stringList("tables")
+	schema := input.optionalString("schema", "")
+	maxDepth := input.intAtLeast("max_depth", 3, 1)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if len(tableNames) < 2 {
+		return nil, fmt.Errorf("tables parameter must contain at least two tables")
+	}
+
+	logger.Info("Suggesting joins for tables %v in database %s", tableNames, targetDbID)
+
+	meta, err := schemaCache.load(ctx, useCase, targetDbID, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest joins: %w", err)
+	}
+	var keys []string
+	seen := make(map[string]bool)
+	for _, name := range tableNames {
+		table := meta.table(name)
+		if table == nil {
+			return nil, fmt.Errorf("table %s not found", name)
+		}
+		key := schemaTableKey(table.Schema, table.Name)
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	suggestion := suggestJoins(newJoinGraph(meta), keys, maxDepth)
+
+	// Format the response
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Suggested Joins in Database %s\n\n", targetDbID))
+	response.WriteString(formatJoinSuggestion(suggestion, meta.DatabaseType, maxDepth))
+
+	return createTextResponse(response.String()), nil
+}
+
+// joinSuggestion holds the relationships found among a set of tables
+type joinSuggestion struct {
+	graph        *joinGraph
+	direct       []joinEdge
+	transitive   [][]joinEdge
+	unrelated    [][2]string
+	combined     []joinEdge
+	disconnected []string
+	linkTables   []linkTable
+}
+
+// linkTable is a many-to-many link table and the tables it connects
+type linkTable struct {
+	key     string
+	parents []string
+}
+
+// suggestJoins finds direct relationships, transitive routes and a combined join order for the tables
+func suggestJoins(graph *joinGraph, keys []string, maxDepth int) *joinSuggestion {
+	suggestion := &joinSuggestion{graph: graph}
+	checked := make(map[string]bool)
+	noteLinkTables := func(path []joinEdge) {
+		for _, edge := range path {
+			for _, key := range []string{edge.from, edge.to} {
+				if checked[key] {
+					continue
+				}
+				checked[key] = true
+				if parents := graph.linkTableParents(key); parents != nil {
+					suggestion.linkTables = append(suggestion.linkTables, linkTable{key: key, parents: parents})
+				}
+			}
+		}
+	}
+
+	for i := 0; i < len(keys); i++ {
+		for j := i + 1; j < len(keys); j++ {
+			var direct []joinEdge
+			for _, edge := range graph.edges[keys[i]] {
+				if edge.to == keys[j] {
+					direct = append(direct, edge)
+				}
+			}
+			if len(direct) > 0 {
+				suggestion.direct = append(suggestion.direct, direct...)
+				noteLinkTables(direct)
+				continue
+			}
+			if paths := graph.paths(keys[i], keys[j], maxDepth, 1); len(paths) > 0 {
+				suggestion.transitive = append(suggestion.transitive, paths[0])
+				noteLinkTables(paths[0])
+			} else {
+				suggestion.unrelated = append(suggestion.unrelated, [2]string{keys[i], keys[j]})
+			}
+		}
+	}
+
+	// Grow a join tree from the first table, always adding the closest remaining table
+	joined := map[string]bool{keys[0]: true}
+	joinedOrder := []string{keys[0]}
+	remaining := append([]string(nil), keys[1:]...)
+	for len(remaining) > 0 {
+		var best []joinEdge
+		bestIndex := -1
+		for i, target := range remaining {
+			for _, key := range joinedOrder {
+				paths := graph.paths(key, target, maxDepth, 1)
+				if len(paths) > 0 && (best == nil || len(paths[0]) < len(best)) {
+					best, bestIndex = paths[0], i
+				}
+			}
+		}
+		if bestIndex < 0 {
+			suggestion.disconnected = remaining
+			break
+		}
+		for _, edge := range best {
+			if !joined[edge.to] {
+				joined[edge.to] = true
+				joinedOrder = append(joinedOrder, edge.to)
+				suggestion.combined = append(suggestion.combined, edge)
+			}
+		}
+		remaining = append(remaining[:bestIndex], remaining[bestIndex+1:]...)
+		for i := 0; i < len(remaining); i++ {
+			if joined[remaining[i]] {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				i--
+			}
+		}
+	}
+	return suggestion
+}
+
+// tableName strips the schema from a table key for display
+func tableName(key string) string {
+	_, name := splitQualifiedName(key)
+	return name
+}
+
+// formatJoinSuggestion renders a join suggestion as markdown
+func formatJoinSuggestion(s *joinSuggestion, dbType string, maxDepth int) string {
+	var sb strings.Builder
+
+	sb.WriteString("## Direct Relationships\n\n")
+	if len(s.direct) == 0 {
+		sb.WriteString("None of the tables reference each other directly.\n\n")
+	}
+	for _, edge := range s.direct {
+		sb.WriteString(fmt.Sprintf("- %s\n", s.graph.describe([]joinEdge{edge})))
+		sb.WriteString(fmt.Sprintf("  ```sql\n  JOIN %s ON %s\n  ```\n", s.graph.tableRef(dbType, edge.to), s.graph.condition(dbType, edge)))
+	}
+	if len(s.direct) > 0 {
+		sb.WriteString("\n")
+	}
+
+	if len(s.transitive) > 0 {
+		sb.WriteString("## Transitive Relationships\n\n")
+		for _, path := range s.transitive {
+			var via []string
+			for _, edge := range path[:len(path)-1] {
+				via = append(via, tableName(edge.to))
+			}
+			sb.WriteString(fmt.Sprintf("- %s to %s via %s\n", tableName(path[0].from), tableName(path[len(path)-1].to), strings.Join(via, ", ")))
+			clause := strings.ReplaceAll(s.graph.joinClause(dbType, path), "\n", "\n  ")
+			sb.WriteString(fmt.Sprintf("  ```sql\n  %s\n  ```\n", clause))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(s.unrelated) > 0 {
+		sb.WriteString("## Unrelated Pairs\n\n")
+		for _, pair := range s.unrelated {
+			sb.WriteString(fmt.Sprintf("- %s and %s are not connected within %d joins\n", tableName(pair[0]), tableName(pair[1]), maxDepth))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(s.linkTables) > 0 {
+		sb.WriteString("## Warnings\n\n")
+		for _, link := range s.linkTables {
+			names := make([]string, len(link.parents))
+			for i, parent := range link.parents {
+				names[i] = tableName(parent)
+			}
+			sb.WriteString(fmt.Sprintf("- %s is a many-to-many link table between %s: joining through it returns one row per link, so rows of the joined tables are multiplied. Aggregate, use DISTINCT, or filter with EXISTS when you need one row per entity.\n",
+				tableName(link.key), strings.Join(names, " and ")))
+		}
+		sb.WriteString("\n")
+	}
+
+	if s.combined != nil {
+		sb.WriteString("## Combined Join\n\n")
+		sb.WriteString(fmt.Sprintf("```sql\n%s\n```\n", s.graph.joinClause(dbType, s.combined)))
+	}
+	if len(s.disconnected) > 0 {
+		names := make([]string, len(s.disconnected))
+		for i, key := range s.disconnected {
+			names[i] = tableName(key)
+		}
+		sb.WriteString(fmt.Sprintf("\nCould not connect: %s\n", strings.Join(names, ", ")))
+	}
+
+	return sb.String()
+}
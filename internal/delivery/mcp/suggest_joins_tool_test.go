@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testLinkTableMetadata extends the fixture with tags linked to orders through order_tags
+func testLinkTableMetadata() *schemaMetadata {
+	meta := testSchemaMetadata()
+	meta.Tables = append(meta.Tables,
+		&schemaTable{
+			Schema:     "public",
+			Name:       "tags",
+			Columns:    []schemaColumn{{Name: "id", DataType: "integer"}},
+			PrimaryKey: []string{"id"},
+		},
+		&schemaTable{
+			Schema:     "public",
+			Name:       "order_tags",
+			Columns:    []schemaColumn{{Name: "order_id", DataType: "integer"}, {Name: "tag_id", DataType: "integer"}},
+			PrimaryKey: []string{"order_id", "tag_id"},
+		},
+	)
+	meta.ForeignKeys = append(meta.ForeignKeys,
+		schemaForeignKey{Name: "order_tags_order_fk", Schema: "public", Table: "order_tags", Columns: []string{"order_id"},
+			RefSchema: "public", RefTable: "orders", RefColumns: []string{"id"}},
+		schemaForeignKey{Name: "order_tags_tag_fk", Schema: "public", Table: "order_tags", Columns: []string{"tag_id"},
+			RefSchema: "public", RefTable: "tags", RefColumns: []string{"id"}},
+	)
+	return meta
+}
+
+func TestLinkTableParents(t *testing.T) {
+	graph := newJoinGraph(testLinkTableMetadata())
+
+	assert.Equal(t, []string{"public.orders", "public.tags"}, graph.linkTableParents("public.order_tags"))
+	// orders references two tables but has its own primary key
+	assert.Nil(t, graph.linkTableParents("public.orders"))
+}
+
+func TestSuggestJoins(t *testing.T) {
+	graph := newJoinGraph(testLinkTableMetadata())
+
+	suggestion := suggestJoins(graph, []string{"public.users", "public.orders", "public.tags"}, 3)
+	assert.Len(t, suggestion.direct, 1)
+	assert.Equal(t, "users(id) <- orders(user_id)", graph.describe(suggestion.direct))
+	assert.Len(t, suggestion.transitive, 2)
+	assert.Empty(t, suggestion.unrelated)
+	assert.Equal(t, []linkTable{{key: "public.order_tags", parents: []string{"public.orders", "public.tags"}}}, suggestion.linkTables)
+	assert.Equal(t, "FROM \"users\"\n"+
+		"JOIN \"orders\" ON \"orders\".\"user_id\" = \"users\".\"id\"\n"+
+		"JOIN \"order_tags\" ON \"order_tags\".\"order_id\" = \"orders\".\"id\"\n"+
+		"JOIN \"tags\" ON \"tags\".\"id\" = \"order_tags\".\"tag_id\"",
+		graph.joinClause("postgres", suggestion.combined))
+
+	output := formatJoinSuggestion(suggestion, "postgres", 3)
+	assert.Contains(t, output, "order_tags is a many-to-many link table between orders and tags")
+}
+
+func TestSuggestJoinsUnrelatedTables(t *testing.T) {
+	meta := testSchemaMetadata()
+	meta.Tables = append(meta.Tables, &schemaTable{Schema: "public", Name: "settings"})
+	graph := newJoinGraph(meta)
+
+	suggestion := suggestJoins(graph, []string{"public.users", "public.settings"}, 3)
+	assert.Len(t, suggestion.unrelated, 1)
+	assert.Nil(t, suggestion.combined)
+	assert.Equal(t, []string{"public.settings"}, suggestion.disconnected)
+}
@@ -0,0 +1,260 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// hierarchyTable is a table of a partition or inheritance tree
+type hierarchyTable struct {
+	Name          string // schema-qualified
+	Kind          string // pg_class relkind
+	Key           string // partition key of a partitioned table
+	Bound         string // partition bound; empty for an inheritance child
+	Partition     bool
+	DetachPending bool // DETACH PARTITION CONCURRENTLY has not finished
+	Bytes         int64
+	Children      []string
+	Parents       []string
+}
+
+// TableHierarchyTool handles rendering partition and inheritance trees
+type TableHierarchyTool struct {
+	BaseToolType
+}
+
+// NewTableHierarchyTool creates a new table hierarchy tool type
+func NewTableHierarchyTool() *TableHierarchyTool {
+	return &TableHierarchyTool{
+		BaseToolType: BaseToolType{
+			name:        "table_hierarchy",
+			description: "Render the parent/child relationships of PostgreSQL partitioned and inherited tables as a tree: each partitioned table with its partition key, each partition with its bound, sub-partitions under their partition, default partitions marked, and tables that inherit from another table with INHERITS. Partitions whose DETACH PARTITION CONCURRENTLY was interrupted are marked as detach pending, with the statement that finishes the detach; a partition that was fully detached is an ordinary table again and no longer appears. With a table, only the tree it belongs to is shown. MySQL has no table inheritance; use get_partitions for its partitioned tables.",
+		},
+	}
+}
+
+// CreateTool creates a table hierarchy tool
+func (t *TableHierarchyTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Render partition and inheritance trees, with default and detach-pending partitions"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Only show trees with a table in this schema (default: all user schemas)"),
+		),
+		tools.WithString("table",
+			tools.Description("Only show the tree this table belongs to, optionally schema-qualified"),
+		),
+	)
+}
+
+// HandleRequest handles table hierarchy tool requests
+func (t *TableHierarchyTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema := input.optionalString("schema", "")
+	table := input.optionalString("table", "")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Getting table hierarchy for database %s, schema %s, table %s", targetDbID, schema, table)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for table hierarchy: %s", dbType)
+	}
+	query, err := dialect.TableHierarchyQuery()
+	if err != nil {
+		return nil, unsupportedAdminError("table hierarchy", dbType, err)
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, targetDbID, query, []interface{}{schema})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table hierarchy: %w", err)
+	}
+	tables := buildTableHierarchy(result.Rows)
+
+	roots := hierarchyRoots(tables)
+	if table != "" {
+		tableSchema, name := splitQualifiedName(table)
+		if tableSchema == "" {
+			tableSchema = "public"
+		}
+		root, ok := hierarchyRootOf(tables, qualifiedName(tableSchema, name))
+		if !ok {
+			return nil, fmt.Errorf("table %s is not partitioned and neither inherits nor is inherited from", table)
+		}
+		roots = []string{root}
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Table Hierarchy in Database %s\n\n", targetDbID))
+	writeTableHierarchy(&response, tables, roots)
+
+	return createTextResponse(response.String()), nil
+}
+
+// buildTableHierarchy links the parent/child rows of the hierarchy query into tables by name
+func buildTableHierarchy(rows [][]interface{}) map[string]*hierarchyTable {
+	tables := make(map[string]*hierarchyTable)
+	node := func(name, kind, key string, bytes int64) *hierarchyTable {
+		t, ok := tables[name]
+		if !ok {
+			t = &hierarchyTable{Name: name, Kind: kind, Key: key, Bytes: bytes}
+			tables[name] = t
+		}
+		return t
+	}
+	for _, row := range rows {
+		if len(row) < 13 {
+			continue
+		}
+		parent := node(qualifiedName(valueString(row[0]), valueString(row[1])), valueString(row[2]), valueString(row[3]), valueInt64(row[4]))
+		child := node(qualifiedName(valueString(row[5]), valueString(row[6])), valueString(row[7]), valueString(row[8]), valueInt64(row[11]))
+		child.Partition = valueBool(row[9])
+		child.Bound = valueString(row[10])
+		child.DetachPending = valueBool(row[12])
+		parent.Children = append(parent.Children, child.Name)
+		child.Parents = append(child.Parents, parent.Name)
+	}
+	return tables
+}
+
+// hierarchyRoots returns the tables without a parent, sorted by name
+func hierarchyRoots(tables map[string]*hierarchyTable) []string {
+	var roots []string
+	for name, t := range tables {
+		if len(t.Parents) == 0 {
+			roots = append(roots, name)
+		}
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+// hierarchyRootOf returns the topmost ancestor of a table, following the first parent of a
+// table that inherits from several
+func hierarchyRootOf(tables map[string]*hierarchyTable, name string) (string, bool) {
+	t, ok := tables[name]
+	if !ok {
+		return "", false
+	}
+	for len(t.Parents) > 0 {
+		t = tables[t.Parents[0]]
+	}
+	return t.Name, true
+}
+
+// label describes a table on its line of the tree
+func (t *hierarchyTable) label() string {
+	var parts []string
+	switch {
+	case t.Bound == "DEFAULT":
+		parts = append(parts, "DEFAULT partition")
+	case t.Bound != "":
+		parts = append(parts, t.Bound)
+	case len(t.Parents) > 0 && !t.Partition:
+		parts = append(parts, "inherits")
+	}
+	if t.Key != "" {
+		parts = append(parts, "partitioned by "+t.Key)
+	}
+	if t.Kind == "f" {
+		parts = append(parts, "foreign table")
+	}
+	if t.Kind != "p" {
+		parts = append(parts, formatBackupSize(t.Bytes))
+	}
+	if t.DetachPending {
+		parts = append(parts, "detach pending")
+	}
+	if len(t.Parents) > 1 {
+		// A table inheriting from several parents is listed under each of them
+		parts = append(parts, "inherits from "+strings.Join(t.Parents, ", "))
+	}
+	return fmt.Sprintf("%s (%s)", t.Name, strings.Join(parts, ", "))
+}
+
+// writeTableHierarchy renders the trees under the roots and warns about unfinished detaches
+func writeTableHierarchy(sb *strings.Builder, tables map[string]*hierarchyTable, roots []string) {
+	if len(roots) == 0 {
+		sb.WriteString("No partitioned or inherited tables found.\n")
+		return
+	}
+
+	var warnings []string
+	var writeTree func(name string, depth int)
+	writeTree = func(name string, depth int) {
+		t := tables[name]
+		sb.WriteString(strings.Repeat("  ", depth) + "- " + t.label() + "\n")
+		if t.DetachPending && depth > 0 && len(t.Parents) > 0 {
+			warnings = append(warnings, fmt.Sprintf("Detaching %s from %s was interrupted; finish it with `ALTER TABLE %s DETACH PARTITION %s FINALIZE;`.",
+				t.Name, t.Parents[0], quoteIdentifier("postgres", t.Parents[0]), quoteIdentifier("postgres", t.Name)))
+		}
+		for _, child := range t.Children {
+			writeTree(child, depth+1)
+		}
+	}
+	partitioned, inherited := 0, 0
+	for _, root := range roots {
+		if tables[root].Kind == "p" {
+			partitioned++
+		} else {
+			inherited++
+		}
+		writeTree(root, 0)
+	}
+	sb.WriteString(fmt.Sprintf("\n%d trees: %d rooted at a partitioned table and %d at an inheritance parent.\n", len(roots), partitioned, inherited))
+
+	if len(warnings) > 0 {
+		sb.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+}
+
+// getPostgresTableHierarchyQuery returns a query for every parent/child link between tables
+// of the user schemas, or of the trees touching schema $1. pg_inherits also links partitioned
+// indexes, which are left out. inhdetachpending is read through to_jsonb because it only
+// exists from PostgreSQL 14.
+func getPostgresTableHierarchyQuery() string {
+	return `
+SELECT
+    pn.nspname AS parent_schema,
+    p.relname AS parent_name,
+    p.relkind AS parent_kind,
+    CASE WHEN p.relkind = 'p' THEN pg_get_partkeydef(p.oid) ELSE '' END AS parent_key,
+    CASE WHEN p.relkind = 'p' THEN 0 ELSE pg_total_relation_size(p.oid) END AS parent_bytes,
+    cn.nspname AS child_schema,
+    c.relname AS child_name,
+    c.relkind AS child_kind,
+    CASE WHEN c.relkind = 'p' THEN pg_get_partkeydef(c.oid) ELSE '' END AS child_key,
+    c.relispartition AS is_partition,
+    COALESCE(pg_get_expr(c.relpartbound, c.oid), '') AS bound,
+    CASE WHEN c.relkind = 'p' THEN 0 ELSE pg_total_relation_size(c.oid) END AS child_bytes,
+    COALESCE((to_jsonb(i) ->> 'inhdetachpending')::boolean, false) AS detach_pending
+FROM pg_inherits i
+JOIN pg_class c ON c.oid = i.inhrelid
+JOIN pg_namespace cn ON cn.oid = c.relnamespace
+JOIN pg_class p ON p.oid = i.inhparent
+JOIN pg_namespace pn ON pn.oid = p.relnamespace
+WHERE p.relkind IN ('r', 'p', 'f')
+  AND pn.nspname NOT IN ('pg_catalog', 'information_schema')
+  AND ($1 = '' OR pn.nspname = $1 OR cn.nspname = $1)
+ORDER BY pn.nspname, p.relname, cn.nspname, c.relname;`
+}
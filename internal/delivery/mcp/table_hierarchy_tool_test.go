@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestTableHierarchyRendersTrees(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_inherits i": {Rows: [][]interface{}{
+				{"public", "cities", "r", "", int64(8192), "public", "capitals", "r", "", false, "", int64(16384), false},
+				{"public", "events", "p", "RANGE (created_at)", int64(0), "public", "events_2024", "p", "HASH (tenant_id)", true, "FOR VALUES FROM ('2024-01-01') TO ('2025-01-01')", int64(0), false},
+				{"public", "events", "p", "RANGE (created_at)", int64(0), "public", "events_2023", "r", "", true, "FOR VALUES FROM ('2023-01-01') TO ('2024-01-01')", int64(1048576), true},
+				{"public", "events", "p", "RANGE (created_at)", int64(0), "public", "events_default", "r", "", true, "DEFAULT", int64(65536), false},
+				{"public", "events_2024", "p", "HASH (tenant_id)", int64(0), "public", "events_2024_h0", "r", "", true, "FOR VALUES WITH (modulus 2, remainder 0)", int64(8192), false},
+			}},
+		},
+	}
+	result, err := NewTableHierarchyTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "pg1"}}, "", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+
+	assert.Contains(t, text, "- public.cities (8.0 KiB)\n  - public.capitals (inherits, 16.0 KiB)\n")
+	assert.Contains(t, text, "- public.events (partitioned by RANGE (created_at))\n"+
+		"  - public.events_2024 (FOR VALUES FROM ('2024-01-01') TO ('2025-01-01'), partitioned by HASH (tenant_id))\n"+
+		"    - public.events_2024_h0 (FOR VALUES WITH (modulus 2, remainder 0), 8.0 KiB)\n"+
+		"  - public.events_2023 (FOR VALUES FROM ('2023-01-01') TO ('2024-01-01'), 1.0 MiB, detach pending)\n"+
+		"  - public.events_default (DEFAULT partition, 64.0 KiB)\n")
+	assert.Contains(t, text, "2 trees: 1 rooted at a partitioned table and 1 at an inheritance parent.")
+	assert.Contains(t, text, "- Detaching public.events_2023 from public.events was interrupted; finish it with `ALTER TABLE \"public\".\"events\" DETACH PARTITION \"public\".\"events_2023\" FINALIZE;`.")
+
+	result, err = NewTableHierarchyTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "pg1", "table": "events_2024_h0"}}, "", useCase)
+	assert.NoError(t, err)
+	text = result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "- public.events (partitioned by RANGE (created_at))\n")
+	assert.NotContains(t, text, "public.cities")
+
+	_, err = NewTableHierarchyTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "pg1", "table": "plain"}}, "", useCase)
+	assert.ErrorContains(t, err, "table plain is not partitioned and neither inherits nor is inherited from")
+
+	_, err = NewTableHierarchyTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: map[string]interface{}{"database": "my1"}}, "", &mockUseCase{dbType: "mysql"})
+	assert.ErrorContains(t, err, "use get_partitions")
+}
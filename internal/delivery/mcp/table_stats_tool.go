@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/FreePeak/cortex/pkg/server"
 	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/domain"
 	"github.com/FreePeak/db-mcp-server/internal/logger"
 )
 
@@ -38,35 +41,62 @@ func (t *TableStatsTool) CreateTool(name string, dbID string) interface{} {
 			tools.Description("Table name to get statistics for"),
 			tools.Required(),
 		),
+		tools.WithArray("sections",
+			tools.Description("Sections to compute: overview, columns, indexes, and the expensive io and bloat (PostgreSQL, which adds hypertable when TimescaleDB is installed) or status, index_usage and io (MySQL); SQLite adds foreign_keys and the expensive analyze, ClickHouse adds storage and the expensive partitions and merges, BigQuery adds storage and options and the expensive partitions, Spanner adds interleaved and the expensive size, operations and locks, CockroachDB adds ranges and zone_config and the expensive replicas, YugabyteDB has the expensive io and tablets, Trino has overview, columns, statistics, definition and the expensive row_count (default: the cheap sections)"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
 		tools.WithBoolean("detailed",
-			tools.Description("Whether to include detailed statistics (may be slower)"),
+			tools.Description("Include every section, also the expensive ones, when sections is not given (default: false)"),
 		),
 	)
 }
 
-// HandleRequest handles table statistics tool requests
-func (t *TableStatsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
-	// Extract database ID from parameters
-	targetDbID, ok := request.Parameters["database"].(string)
-	if !ok {
-		return nil, fmt.Errorf("database parameter must be a string")
-	}
+// tableStatsSection is one independently computed part of the table statistics
+type tableStatsSection struct {
+	name  string
+	query string
+	// expensive sections are only computed on request and their results are cached briefly
+	expensive bool
+}
 
-	// Extract table name
-	tableName, ok := request.Parameters["table"].(string)
-	if !ok {
-		return nil, fmt.Errorf("table parameter must be a string")
-	}
+// expensiveStatsTTL is how long the results of expensive sections are reused
+const expensiveStatsTTL = 30 * time.Second
 
-	// Extract detailed flag
-	detailed := false
-	if request.Parameters["detailed"] != nil {
-		if detailedParam, ok := request.Parameters["detailed"].(bool); ok {
-			detailed = detailedParam
-		}
+// tableStatsNow is the clock used for section cache expiry; tests replace it
+var tableStatsNow = time.Now
+
+// tableStatsCacheKey identifies the cached result of one section of one table
+type tableStatsCacheKey struct {
+	useCase UseCaseProvider
+	dbID    string
+	table   string
+	section string
+}
+
+// tableStatsCacheEntry is a section result and when it was computed
+type tableStatsCacheEntry struct {
+	result     *domain.QueryResult
+	computedAt time.Time
+}
+
+// tableStatsCache holds the results of expensive sections
+var tableStatsCache = struct {
+	mu      sync.Mutex
+	entries map[tableStatsCacheKey]tableStatsCacheEntry
+}{entries: make(map[tableStatsCacheKey]tableStatsCacheEntry)}
+
+// HandleRequest handles table statistics tool requests
+func (t *TableStatsTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	tableName := input.requiredString("table")
+	requested := input.stringList("sections")
+	detailed := input.optionalBool("detailed", false)
+	if err := input.err(); err != nil {
+		return nil, err
 	}
 
-	logger.Info("Getting table statistics for %s.%s (detailed: %v)", targetDbID, tableName, detailed)
+	logger.Info("Getting table statistics for %s.%s (sections: %v, detailed: %v)", targetDbID, tableName, requested, detailed)
 
 	// Get database type to determine which queries to run
 	dbType, err := useCase.GetDatabaseType(targetDbID)
@@ -74,58 +104,150 @@ func (t *TableStatsTool) HandleRequest(ctx context.Context, request server.ToolC
 		return nil, fmt.Errorf("failed to get database type: %w", err)
 	}
 
-	// Define queries based on database type
-	var queries []string
-	switch strings.ToLower(dbType) {
-	case "postgres":
-		queries = getPostgresTableStatsQueries(tableName, detailed)
-	case "mysql":
-		queries = getMySQLTableStatsQueries(tableName, detailed)
-	default:
+	// Build queries in the dialect of the database
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
 		return nil, fmt.Errorf("unsupported database type for table statistics: %s", dbType)
 	}
+	catalog := dialect.TableStatsSections(tableName)
+	// With TimescaleDB a hypertable also reports its chunks, compression and policies
+	if queries, ok := dialect.HypertableQueries(tableName); ok && (len(requested) == 0 || containsFold(requested, "hypertable")) {
+		version, err := timescaleVersion(ctx, useCase, targetDbID, queries)
+		if err != nil {
+			logger.Warn("Error checking for TimescaleDB: %v", err)
+		} else if version != "" {
+			catalog = append(catalog, hypertableStatsSection(queries))
+		}
+	}
+	sections, err := selectTableStatsSections(catalog, requested, detailed)
+	if err != nil {
+		return nil, err
+	}
 
-	// Execute each query and combine results
-	var results strings.Builder
-	results.WriteString(fmt.Sprintf("# Table Statistics for %s.%s\n\n", targetDbID, tableName))
+	// Reuse fresh results of expensive sections and run the other queries
+	results := make([]*domain.QueryResult, len(sections))
+	cachedAt := make([]time.Time, len(sections))
+	var queries []string
+	var pending []int
+	for i, section := range sections {
+		if section.expensive {
+			key := tableStatsCacheKey{useCase: sharedUseCase(useCase), dbID: targetDbID, table: tableName, section: section.name}
+			tableStatsCache.mu.Lock()
+			entry, ok := tableStatsCache.entries[key]
+			tableStatsCache.mu.Unlock()
+			if ok && tableStatsNow().Sub(entry.computedAt) < expensiveStatsTTL {
+				results[i], cachedAt[i] = entry.result, entry.computedAt
+				continue
+			}
+		}
+		queries = append(queries, section.query)
+		pending = append(pending, i)
+	}
 
-	for _, query := range queries {
-		// Execute the query
-		result, err := useCase.ExecuteQuery(ctx, targetDbID, query, nil)
-		if err != nil {
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Table Statistics for %s.%s\n\n", targetDbID, tableName))
+
+	// The queries are independent, so they run concurrently and their sections keep the query order
+	failed := make([]error, len(sections))
+	for n, outcome := range runQueriesConcurrently(ctx, useCase, targetDbID, queries) {
+		i := pending[n]
+		if outcome.err != nil {
 			// Log the error but continue with other queries
-			logger.Warn("Error executing table stats query: %v", err)
-			results.WriteString(fmt.Sprintf("Error executing query: %s\n%v\n\n", query, err))
+			logger.Warn("Error executing table stats query: %v", outcome.err)
+			failed[i] = outcome.err
+			continue
+		}
+		results[i] = outcome.result
+		if sections[i].expensive {
+			key := tableStatsCacheKey{useCase: sharedUseCase(useCase), dbID: targetDbID, table: tableName, section: sections[i].name}
+			tableStatsCache.mu.Lock()
+			tableStatsCache.entries[key] = tableStatsCacheEntry{result: outcome.result, computedAt: tableStatsNow()}
+			tableStatsCache.mu.Unlock()
+		}
+	}
+
+	for i, section := range sections {
+		response.WriteString(fmt.Sprintf("## %s\n\n", section.name))
+		if failed[i] != nil {
+			response.WriteString(fmt.Sprintf("Error executing query: %s\n%v\n\n", section.query, failed[i]))
 			continue
 		}
+		if !cachedAt[i].IsZero() {
+			response.WriteString(fmt.Sprintf("Cached result from %s ago.\n\n", tableStatsNow().Sub(cachedAt[i]).Round(time.Second)))
+		}
 
 		// Add the result
-		results.WriteString(result)
-		results.WriteString("\n\n")
+		response.WriteString(formatQueryResult(results[i]))
+		response.WriteString("\n\n")
+	}
+
+	if len(requested) == 0 && !detailed {
+		var optional []string
+		for _, section := range catalog {
+			if section.expensive {
+				optional = append(optional, section.name)
+			}
+		}
+		response.WriteString(fmt.Sprintf("Expensive sections are computed on request: pass sections with %s.\n", joinChoices(optional)))
+	}
+
+	return createTextResponse(response.String()), nil
+}
+
+// selectTableStatsSections picks the requested sections in catalog order. Without a request it
+// picks the cheap sections, or every section when detailed is set.
+func selectTableStatsSections(catalog []tableStatsSection, requested []string, detailed bool) ([]tableStatsSection, error) {
+	if len(requested) == 0 {
+		var selected []tableStatsSection
+		for _, section := range catalog {
+			if detailed || !section.expensive {
+				selected = append(selected, section)
+			}
+		}
+		return selected, nil
 	}
 
-	return createTextResponse(results.String()), nil
+	known := make(map[string]bool)
+	names := make([]string, 0, len(catalog))
+	for _, section := range catalog {
+		known[section.name] = true
+		names = append(names, section.name)
+	}
+	wanted := make(map[string]bool)
+	for _, name := range requested {
+		name = strings.ToLower(name)
+		if !known[name] {
+			return nil, fmt.Errorf("sections parameter must only contain %s", joinChoices(names))
+		}
+		wanted[name] = true
+	}
+	var selected []tableStatsSection
+	for _, section := range catalog {
+		if wanted[section.name] {
+			selected = append(selected, section)
+		}
+	}
+	return selected, nil
 }
 
-// getPostgresTableStatsQueries returns queries for PostgreSQL table statistics
-func getPostgresTableStatsQueries(tableName string, detailed bool) []string {
+// getPostgresTableStatsSections returns the sections of PostgreSQL table statistics
+func getPostgresTableStatsSections(tableName string) []tableStatsSection {
 	// Escape table name for safety
 	safeTableName := strings.Replace(tableName, "'", "''", -1)
 
-	// Basic queries
-	queries := []string{
+	return []tableStatsSection{
 		// Table size and row count
-		fmt.Sprintf(`SELECT 
+		{name: "overview", query: fmt.Sprintf(`SELECT 
 			pg_size_pretty(pg_total_relation_size('%s')) AS total_size,
 			pg_size_pretty(pg_relation_size('%s')) AS table_size,
 			pg_size_pretty(pg_total_relation_size('%s') - pg_relation_size('%s')) AS index_size,
 			n_live_tup AS row_count,
 			n_dead_tup AS dead_tuples
 		FROM pg_stat_user_tables
-		WHERE relname = '%s';`, safeTableName, safeTableName, safeTableName, safeTableName, safeTableName),
+		WHERE relname = '%s';`, safeTableName, safeTableName, safeTableName, safeTableName, safeTableName)},
 		
 		// Column information
-		fmt.Sprintf(`SELECT 
+		{name: "columns", query: fmt.Sprintf(`SELECT 
 			a.attname AS column_name,
 			pg_catalog.format_type(a.atttypid, a.atttypmod) AS data_type,
 			CASE WHEN a.attnotnull THEN 'NOT NULL' ELSE 'NULL' END AS nullable,
@@ -142,10 +264,10 @@ func getPostgresTableStatsQueries(tableName string, detailed bool) []string {
 		AND a.attnum > 0
 		AND NOT a.attisdropped
 		AND n.nspname = 'public'
-		ORDER BY a.attnum;`, safeTableName),
+		ORDER BY a.attnum;`, safeTableName)},
 		
 		// Index information
-		fmt.Sprintf(`SELECT 
+		{name: "indexes", query: fmt.Sprintf(`SELECT 
 			i.relname AS index_name,
 			pg_size_pretty(pg_relation_size(i.relname::regclass)) AS index_size,
 			idx_scan AS index_scans,
@@ -163,106 +285,96 @@ func getPostgresTableStatsQueries(tableName string, detailed bool) []string {
 		WHERE c.relname = '%s'
 		AND n.nspname = 'public'
 		GROUP BY i.relname, ui.idx_scan, ui.idx_tup_read, ui.idx_tup_fetch, a.amname
-		ORDER BY i.relname;`, safeTableName),
-	}
+		ORDER BY i.relname;`, safeTableName)},
 
-	// Add detailed queries if requested
-	if detailed {
-		detailedQueries := []string{
-			// Table I/O statistics
-			fmt.Sprintf(`SELECT 
-				seq_scan AS sequential_scans,
-				seq_tup_read AS sequential_tuples_read,
-				idx_scan AS index_scans,
-				idx_tup_fetch AS index_tuples_fetched,
-				n_tup_ins AS tuples_inserted,
-				n_tup_upd AS tuples_updated,
-				n_tup_del AS tuples_deleted,
-				n_tup_hot_upd AS hot_updates,
-				n_live_tup AS live_tuples,
-				n_dead_tup AS dead_tuples,
-				vacuum_count,
-				autovacuum_count,
-				analyze_count,
-				autoanalyze_count
-			FROM pg_stat_user_tables
-			WHERE relname = '%s';`, safeTableName),
-			
-			// Table bloat estimation
-			fmt.Sprintf(`SELECT 
-				current_database() AS db, schemaname, tblname, 
-				bs*tblpages AS real_size,
-				(tblpages-est_tblpages)*bs AS extra_size,
-				CASE WHEN tblpages > 0
-					THEN 100 * (tblpages-est_tblpages)/tblpages::float
-					ELSE 0
-				END AS extra_ratio, fillfactor,
-				CASE WHEN tblpages > 0 AND tblpages-est_tblpages > 0
-					THEN (bs*(tblpages-est_tblpages)/(tblpages)::float)
-					ELSE 0
-				END AS bloat_size,
-				CASE WHEN tblpages > 0 AND tblpages-est_tblpages > 0
-					THEN pg_size_pretty((bs*(tblpages-est_tblpages))::bigint)
-					ELSE ''
-				END AS bloat_size_pretty,
-				is_na
+		// Table I/O statistics
+		{name: "io", expensive: true, query: fmt.Sprintf(`SELECT 
+			seq_scan AS sequential_scans,
+			seq_tup_read AS sequential_tuples_read,
+			idx_scan AS index_scans,
+			idx_tup_fetch AS index_tuples_fetched,
+			n_tup_ins AS tuples_inserted,
+			n_tup_upd AS tuples_updated,
+			n_tup_del AS tuples_deleted,
+			n_tup_hot_upd AS hot_updates,
+			n_live_tup AS live_tuples,
+			n_dead_tup AS dead_tuples,
+			vacuum_count,
+			autovacuum_count,
+			analyze_count,
+			autoanalyze_count
+		FROM pg_stat_user_tables
+		WHERE relname = '%s';`, safeTableName)},
+		
+		// Table bloat estimation
+		{name: "bloat", expensive: true, query: fmt.Sprintf(`SELECT 
+			current_database() AS db, schemaname, tblname, 
+			bs*tblpages AS real_size,
+			(tblpages-est_tblpages)*bs AS extra_size,
+			CASE WHEN tblpages > 0
+				THEN 100 * (tblpages-est_tblpages)/tblpages::float
+				ELSE 0
+			END AS extra_ratio, fillfactor,
+			CASE WHEN tblpages > 0 AND tblpages-est_tblpages > 0
+				THEN (bs*(tblpages-est_tblpages)/(tblpages)::float)
+				ELSE 0
+			END AS bloat_size,
+			CASE WHEN tblpages > 0 AND tblpages-est_tblpages > 0
+				THEN pg_size_pretty((bs*(tblpages-est_tblpages))::bigint)
+				ELSE ''
+			END AS bloat_size_pretty,
+			is_na
+		FROM (
+			SELECT
+				ceil(reltuples/((bs-page_hdr)/tpl_size)) + ceil(toasttuples/4) AS est_tblpages,
+				tblpages, fillfactor, bs, tblid, schemaname, tblname, heappages, toastpages, is_na
 			FROM (
 				SELECT
-					ceil(reltuples/((bs-page_hdr)/tpl_size)) + ceil(toasttuples/4) AS est_tblpages,
-					tblpages, fillfactor, bs, tblid, schemaname, tblname, heappages, toastpages, is_na
+					( 4 + tpl_hdr_size + tpl_data_size + (2*ma)
+						- CASE WHEN tpl_hdr_size%%ma = 0 THEN ma ELSE tpl_hdr_size%%ma END
+						- CASE WHEN ceil(tpl_data_size)::int%%ma = 0 THEN ma ELSE ceil(tpl_data_size)::int%%ma END
+					) AS tpl_size, bs - page_hdr AS size_per_block, (heappages + toastpages) AS tblpages, heappages,
+					toastpages, reltuples, toasttuples, bs, page_hdr, tblid, schemaname, tblname, fillfactor, is_na
 				FROM (
 					SELECT
-						( 4 + tpl_hdr_size + tpl_data_size + (2*ma)
-							- CASE WHEN tpl_hdr_size%ma = 0 THEN ma ELSE tpl_hdr_size%ma END
-							- CASE WHEN ceil(tpl_data_size)::int%ma = 0 THEN ma ELSE ceil(tpl_data_size)::int%ma END
-						) AS tpl_size, bs - page_hdr AS size_per_block, (heappages + toastpages) AS tblpages, heappages,
-						toastpages, reltuples, toasttuples, bs, page_hdr, tblid, schemaname, tblname, fillfactor, is_na
-					FROM (
-						SELECT
-							tbl.oid AS tblid, ns.nspname AS schemaname, tbl.relname AS tblname, tbl.reltuples,
-							tbl.relpages AS heappages, coalesce(toast.relpages, 0) AS toastpages,
-							coalesce(toast.reltuples, 0) AS toasttuples,
-							coalesce(substring(
-								array_to_string(tbl.reloptions, ' ')
-								FROM 'fillfactor=([0-9]+)')::smallint, 100) AS fillfactor,
-							current_setting('block_size')::numeric AS bs,
-							CASE WHEN version()~'mingw32' OR version()~'64-bit|x86_64|ppc64|ia64|amd64' THEN 8 ELSE 4 END AS ma,
-							24 AS page_hdr,
-							23 + CASE WHEN MAX(coalesce(s.null_frac,0)) > 0 THEN ( 7 + count(*) ) / 8 ELSE 0::int END
-								+ CASE WHEN tbl.relhasoids THEN 4 ELSE 0 END AS tpl_hdr_size,
-							sum( (1-coalesce(s.null_frac, 0)) * coalesce(s.avg_width, 1024) ) AS tpl_data_size,
-							bool_or(att.atttypid = 'pg_catalog.name'::regtype) AS is_na
-						FROM pg_attribute AS att
-							JOIN pg_class AS tbl ON att.attrelid = tbl.oid
-							JOIN pg_namespace AS ns ON ns.oid = tbl.relnamespace
-							LEFT JOIN pg_stats AS s ON s.schemaname=ns.nspname
-								AND s.tablename = tbl.relname AND s.inherited=false AND s.attname=att.attname
-							LEFT JOIN pg_class AS toast ON tbl.reltoastrelid = toast.oid
-						WHERE NOT att.attisdropped
-							AND tbl.relkind = 'r'
-							AND ns.nspname = 'public'
-							AND tbl.relname = '%s'
-						GROUP BY 1,2,3,4,5,6,7,8,9,10
-					) AS s
-				) AS s2
-			) AS s3;`, safeTableName),
-		}
-		
-		queries = append(queries, detailedQueries...)
+						tbl.oid AS tblid, ns.nspname AS schemaname, tbl.relname AS tblname, tbl.reltuples,
+						tbl.relpages AS heappages, coalesce(toast.relpages, 0) AS toastpages,
+						coalesce(toast.reltuples, 0) AS toasttuples,
+						coalesce(substring(
+							array_to_string(tbl.reloptions, ' ')
+							FROM 'fillfactor=([0-9]+)')::smallint, 100) AS fillfactor,
+						current_setting('block_size')::numeric AS bs,
+						CASE WHEN version()~'mingw32' OR version()~'64-bit|x86_64|ppc64|ia64|amd64' THEN 8 ELSE 4 END AS ma,
+						24 AS page_hdr,
+						23 + CASE WHEN MAX(coalesce(s.null_frac,0)) > 0 THEN ( 7 + count(*) ) / 8 ELSE 0::int END
+							+ CASE WHEN tbl.relhasoids THEN 4 ELSE 0 END AS tpl_hdr_size,
+						sum( (1-coalesce(s.null_frac, 0)) * coalesce(s.avg_width, 1024) ) AS tpl_data_size,
+						bool_or(att.atttypid = 'pg_catalog.name'::regtype) AS is_na
+					FROM pg_attribute AS att
+						JOIN pg_class AS tbl ON att.attrelid = tbl.oid
+						JOIN pg_namespace AS ns ON ns.oid = tbl.relnamespace
+						LEFT JOIN pg_stats AS s ON s.schemaname=ns.nspname
+							AND s.tablename = tbl.relname AND s.inherited=false AND s.attname=att.attname
+						LEFT JOIN pg_class AS toast ON tbl.reltoastrelid = toast.oid
+					WHERE NOT att.attisdropped
+						AND tbl.relkind = 'r'
+						AND ns.nspname = 'public'
+						AND tbl.relname = '%s'
+					GROUP BY 1,2,3,4,5,6,7,8,9,10
+				) AS s
+			) AS s2
+		) AS s3;`, safeTableName)},
 	}
-
-	return queries
 }
 
-// getMySQLTableStatsQueries returns queries for MySQL table statistics
-func getMySQLTableStatsQueries(tableName string, detailed bool) []string {
+// getMySQLTableStatsSections returns the sections of MySQL table statistics
+func getMySQLTableStatsSections(tableName string) []tableStatsSection {
 	// Escape table name for safety
 	safeTableName := strings.Replace(tableName, "`", "``", -1)
 
-	// Basic queries
-	queries := []string{
+	return []tableStatsSection{
 		// Table size and row count
-		fmt.Sprintf(`SELECT 
+		{name: "overview", query: fmt.Sprintf(`SELECT 
 			table_name,
 			engine,
 			table_rows,
@@ -272,10 +384,10 @@ func getMySQLTableStatsQueries(tableName string, detailed bool) []string {
 			ROUND((data_length + index_length) / 1024 / 1024, 2) AS total_size_mb
 		FROM information_schema.tables
 		WHERE table_schema = DATABASE()
-		AND table_name = '%s';`, safeTableName),
+		AND table_name = '%s';`, safeTableName)},
 		
 		// Column information
-		fmt.Sprintf(`SELECT 
+		{name: "columns", query: fmt.Sprintf(`SELECT 
 			column_name,
 			column_type,
 			is_nullable,
@@ -285,10 +397,10 @@ func getMySQLTableStatsQueries(tableName string, detailed bool) []string {
 		FROM information_schema.columns
 		WHERE table_schema = DATABASE()
 		AND table_name = '%s'
-		ORDER BY ordinal_position;`, safeTableName),
+		ORDER BY ordinal_position;`, safeTableName)},
 		
 		// Index information
-		fmt.Sprintf(`SELECT 
+		{name: "indexes", query: fmt.Sprintf(`SELECT 
 			index_name,
 			column_name,
 			seq_in_index,
@@ -302,39 +414,866 @@ func getMySQLTableStatsQueries(tableName string, detailed bool) []string {
 		FROM information_schema.statistics
 		WHERE table_schema = DATABASE()
 		AND table_name = '%s'
-		ORDER BY index_name, seq_in_index;`, safeTableName),
-	}
+		ORDER BY index_name, seq_in_index;`, safeTableName)},
 
-	// Add detailed queries if requested
-	if detailed {
-		detailedQueries := []string{
-			// Table I/O statistics
-			fmt.Sprintf(`SHOW TABLE STATUS LIKE '%s';`, safeTableName),
-			
-			// Index usage statistics
-			fmt.Sprintf(`SELECT 
-				index_name,
-				stat_name,
-				stat_value
-			FROM mysql.index_stats
-			WHERE table_name = '%s'
-			ORDER BY index_name, stat_name;`, safeTableName),
-			
-			// Table I/O statistics
-			fmt.Sprintf(`SELECT 
-				table_schema,
-				table_name,
-				rows_read,
-				rows_inserted,
-				rows_updated,
-				rows_deleted
-			FROM information_schema.table_statistics
-			WHERE table_schema = DATABASE()
-			AND table_name = '%s';`, safeTableName),
-		}
+		// Table status
+		{name: "status", expensive: true, query: fmt.Sprintf(`SHOW TABLE STATUS LIKE '%s';`, safeTableName)},
 		
-		queries = append(queries, detailedQueries...)
+		// Index usage statistics
+		{name: "index_usage", expensive: true, query: fmt.Sprintf(`SELECT 
+			index_name,
+			stat_name,
+			stat_value
+		FROM mysql.index_stats
+		WHERE table_name = '%s'
+		ORDER BY index_name, stat_name;`, safeTableName)},
+		
+		// Table I/O statistics
+		{name: "io", expensive: true, query: fmt.Sprintf(`SELECT 
+			table_schema,
+			table_name,
+			rows_read,
+			rows_inserted,
+			rows_updated,
+			rows_deleted
+		FROM information_schema.table_statistics
+		WHERE table_schema = DATABASE()
+		AND table_name = '%s';`, safeTableName)},
+	}
+}
+
+// getSQLiteTableStatsSections returns the sections of SQLite table statistics
+func getSQLiteTableStatsSections(tableName string) []tableStatsSection {
+	// Escape table name for safety
+	safeTableName := strings.Replace(tableName, "'", "''", -1)
+	quotedTableName := quoteIdentifier("sqlite", tableName)
+
+	return []tableStatsSection{
+		// Row count and definition
+		{name: "overview", query: fmt.Sprintf(`SELECT 
+			name AS table_name,
+			(SELECT COUNT(*) FROM %s) AS row_count,
+			(SELECT COUNT(*) FROM pragma_index_list('%s')) AS index_count,
+			sql AS definition
+		FROM sqlite_master
+		WHERE type = 'table'
+		AND name = '%s';`, quotedTableName, safeTableName, safeTableName)},
+
+		// Column information
+		{name: "columns", query: fmt.Sprintf(`SELECT 
+			name AS column_name,
+			type AS data_type,
+			CASE WHEN "notnull" THEN 'NOT NULL' ELSE 'NULL' END AS nullable,
+			CASE WHEN pk > 0 THEN 'PK' ELSE '' END AS is_primary_key,
+			dflt_value AS column_default
+		FROM pragma_table_info('%s')
+		ORDER BY cid;`, safeTableName)},
+
+		// Index information
+		{name: "indexes", query: fmt.Sprintf(`SELECT 
+			il.name AS index_name,
+			(SELECT group_concat(COALESCE(name, '<expression>'), ', ')
+			 FROM (SELECT name FROM pragma_index_info(il.name) ORDER BY seqno)) AS column_names,
+			CASE 
+				WHEN il.origin = 'pk' THEN 'PRIMARY'
+				WHEN il."unique" THEN 'UNIQUE'
+				ELSE 'INDEX'
+			END AS index_type,
+			CASE WHEN il.partial THEN 'YES' ELSE 'NO' END AS is_partial
+		FROM pragma_index_list('%s') il
+		ORDER BY il.name;`, safeTableName)},
+
+		// Foreign keys
+		{name: "foreign_keys", query: fmt.Sprintf(`SELECT 
+			id,
+			"from" AS column_name,
+			"table" AS referenced_table,
+			"to" AS referenced_column,
+			on_update,
+			on_delete
+		FROM pragma_foreign_key_list('%s')
+		ORDER BY id, seq;`, safeTableName)},
+
+		// Statistics gathered by ANALYZE; fails until ANALYZE has run once
+		{name: "analyze", expensive: true, query: fmt.Sprintf(`SELECT 
+			idx AS index_name,
+			stat
+		FROM sqlite_stat1
+		WHERE tbl = '%s'
+		ORDER BY idx;`, safeTableName)},
+	}
+}
+
+// getClickHouseTableStatsSections returns the sections of ClickHouse table statistics; sizes
+// and row counts come from the active data parts, as column storage has no row-level pages
+func getClickHouseTableStatsSections(tableName string) []tableStatsSection {
+	// Quote table name for safety; ClickHouse strings also treat backslashes as escapes
+	tableLiteral := quoteLiteral("clickhouse", tableName)
+
+	return []tableStatsSection{
+		// Engine and keys
+		{name: "overview", query: fmt.Sprintf(`SELECT 
+			name AS table_name,
+			engine,
+			partition_key,
+			sorting_key,
+			primary_key,
+			total_rows,
+			formatReadableSize(total_bytes) AS total_size
+		FROM system.tables
+		WHERE database = currentDatabase()
+		AND name = %s;`, tableLiteral)},
+
+		// Storage of the active parts
+		{name: "storage", query: fmt.Sprintf(`SELECT 
+			uniqExact(partition) AS partitions,
+			count() AS parts,
+			sum(rows) AS rows,
+			formatReadableSize(sum(data_compressed_bytes)) AS compressed_size,
+			formatReadableSize(sum(data_uncompressed_bytes)) AS uncompressed_size,
+			round(sum(data_uncompressed_bytes) / nullIf(sum(data_compressed_bytes), 0), 2) AS compression_ratio,
+			formatReadableSize(sum(primary_key_bytes_in_memory)) AS primary_key_in_memory,
+			max(modification_time) AS last_modified
+		FROM system.parts
+		WHERE database = currentDatabase()
+		AND table = %s
+		AND active;`, tableLiteral)},
+
+		// Column information and compression
+		{name: "columns", query: fmt.Sprintf(`SELECT 
+			name AS column_name,
+			type AS data_type,
+			default_kind,
+			default_expression,
+			compression_codec,
+			formatReadableSize(data_compressed_bytes) AS compressed_size,
+			formatReadableSize(data_uncompressed_bytes) AS uncompressed_size,
+			round(data_uncompressed_bytes / nullIf(data_compressed_bytes, 0), 2) AS compression_ratio,
+			is_in_partition_key,
+			is_in_sorting_key,
+			is_in_primary_key
+		FROM system.columns
+		WHERE database = currentDatabase()
+		AND table = %s
+		ORDER BY position;`, tableLiteral)},
+
+		// Data skipping indexes
+		{name: "indexes", query: fmt.Sprintf(`SELECT 
+			name AS index_name,
+			type AS index_type,
+			expr AS expression,
+			granularity
+		FROM system.data_skipping_indices
+		WHERE database = currentDatabase()
+		AND table = %s
+		ORDER BY name;`, tableLiteral)},
+
+		// Active parts per partition
+		{name: "partitions", expensive: true, query: fmt.Sprintf(`SELECT 
+			partition,
+			count() AS parts,
+			sum(rows) AS rows,
+			formatReadableSize(sum(data_compressed_bytes)) AS compressed_size,
+			formatReadableSize(sum(data_uncompressed_bytes)) AS uncompressed_size,
+			min(min_time) AS min_time,
+			max(max_time) AS max_time
+		FROM system.parts
+		WHERE database = currentDatabase()
+		AND table = %s
+		AND active
+		GROUP BY partition
+		ORDER BY partition;`, tableLiteral)},
+
+		// Merges and mutations in progress
+		{name: "merges", expensive: true, query: fmt.Sprintf(`SELECT 
+			'merge' AS kind,
+			result_part_name AS target,
+			toInt64(num_parts) AS parts,
+			round(progress * 100, 1) AS progress_pct,
+			toInt64(elapsed) AS elapsed_seconds,
+			'' AS command
+		FROM system.merges
+		WHERE database = currentDatabase()
+		AND table = %[1]s
+		UNION ALL
+		SELECT 
+			'mutation',
+			mutation_id,
+			toInt64(parts_to_do),
+			NULL,
+			dateDiff('second', create_time, now()),
+			command
+		FROM system.mutations
+		WHERE database = currentDatabase()
+		AND table = %[1]s
+		AND NOT is_done;`, tableLiteral)},
 	}
+}
+
+// getBigQueryTableStatsSections returns the sections of BigQuery table statistics. Storage
+// comes from the partitions of the table, in logical bytes and in the bytes it is billed for.
+func getBigQueryTableStatsSections(tableName string) []tableStatsSection {
+	// Quote table name for safety; GoogleSQL strings treat backslashes as escapes
+	tableLiteral := quoteLiteral("bigquery", tableName)
+
+	return []tableStatsSection{
+		// Type and partitioning
+		{name: "overview", query: fmt.Sprintf(`SELECT 
+			t.table_name,
+			t.table_type,
+			t.creation_time,
+			(SELECT STRING_AGG(c.column_name, ', ')
+			 FROM INFORMATION_SCHEMA.COLUMNS c
+			 WHERE c.table_name = t.table_name
+			 AND c.is_partitioning_column = 'YES') AS partitioned_by,
+			(SELECT STRING_AGG(c.column_name, ', ' ORDER BY c.clustering_ordinal_position)
+			 FROM INFORMATION_SCHEMA.COLUMNS c
+			 WHERE c.table_name = t.table_name
+			 AND c.clustering_ordinal_position IS NOT NULL) AS clustered_by
+		FROM INFORMATION_SCHEMA.TABLES t
+		WHERE t.table_name = %s;`, tableLiteral)},
+
+		// Rows and billed storage
+		{name: "storage", query: fmt.Sprintf(`SELECT 
+			COUNT(*) AS partitions,
+			SUM(total_rows) AS total_rows,
+			ROUND(SUM(total_logical_bytes) / POW(1024, 3), 3) AS logical_gb,
+			ROUND(SUM(total_billable_bytes) / POW(1024, 3), 3) AS billable_gb,
+			COUNTIF(storage_tier = 'LONG_TERM') AS long_term_partitions,
+			MAX(last_modified_time) AS last_modified
+		FROM INFORMATION_SCHEMA.PARTITIONS
+		WHERE table_name = %s;`, tableLiteral)},
+
+		// Column information
+		{name: "columns", query: fmt.Sprintf(`SELECT 
+			column_name,
+			data_type,
+			is_nullable,
+			is_partitioning_column,
+			clustering_ordinal_position,
+			column_default
+		FROM INFORMATION_SCHEMA.COLUMNS
+		WHERE table_name = %s
+		ORDER BY ordinal_position;`, tableLiteral)},
 
-	return queries
+		// Options such as partition expiration and required partition filters
+		{name: "options", query: fmt.Sprintf(`SELECT 
+			option_name,
+			option_type,
+			option_value
+		FROM INFORMATION_SCHEMA.TABLE_OPTIONS
+		WHERE table_name = %s
+		ORDER BY option_name;`, tableLiteral)},
+
+		// Rows and billed storage per partition
+		{name: "partitions", expensive: true, query: fmt.Sprintf(`SELECT 
+			partition_id,
+			total_rows,
+			ROUND(total_logical_bytes / POW(1024, 2), 2) AS logical_mb,
+			ROUND(total_billable_bytes / POW(1024, 2), 2) AS billable_mb,
+			storage_tier,
+			last_modified_time
+		FROM INFORMATION_SCHEMA.PARTITIONS
+		WHERE table_name = %s
+		ORDER BY partition_id;`, tableLiteral)},
+	}
+}
+
+// getSpannerTableStatsSections returns the sections of Spanner table statistics. The schema
+// sections come from INFORMATION_SCHEMA; size, operations and locks come from the hourly
+// SPANNER_SYS statistics, which cover the last 30 days and name a table as it is written in
+// queries.
+func getSpannerTableStatsSections(tableName string) []tableStatsSection {
+	// Quote table name for safety; GoogleSQL strings treat backslashes as escapes
+	tableFilter := spannerTableFilter("t", tableName)
+	tableLiteral := quoteLiteral("spanner", tableName)
+	_, table := splitQualifiedName(tableName)
+
+	return []tableStatsSection{
+		// Interleaving, row deletion policy and state
+		{name: "overview", query: fmt.Sprintf(`SELECT 
+			t.table_name,
+			t.parent_table_name AS interleaved_in,
+			t.on_delete_action,
+			t.spanner_state,
+			t.row_deletion_policy_expression,
+			(SELECT COUNT(*)
+			 FROM INFORMATION_SCHEMA.TABLES c
+			 WHERE c.table_schema = t.table_schema
+			 AND c.parent_table_name = t.table_name) AS interleaved_children
+		FROM INFORMATION_SCHEMA.TABLES t
+		WHERE %s;`, tableFilter)},
+
+		// Column information
+		{name: "columns", query: fmt.Sprintf(`SELECT 
+			t.column_name,
+			t.spanner_type,
+			t.is_nullable,
+			t.column_default,
+			t.is_generated,
+			t.generation_expression,
+			t.is_stored
+		FROM INFORMATION_SCHEMA.COLUMNS t
+		WHERE %s
+		ORDER BY t.ordinal_position;`, tableFilter)},
+
+		// Primary key and secondary indexes with their key and STORING columns
+		{name: "indexes", query: fmt.Sprintf(`SELECT 
+			t.index_name,
+			t.index_type,
+			t.is_unique,
+			t.is_null_filtered,
+			t.index_state,
+			NULLIF(t.parent_table_name, '') AS interleaved_in,
+			(SELECT STRING_AGG(c.column_name, ', ' ORDER BY c.ordinal_position)
+			 FROM INFORMATION_SCHEMA.INDEX_COLUMNS c
+			 WHERE c.table_schema = t.table_schema
+			 AND c.table_name = t.table_name
+			 AND c.index_name = t.index_name
+			 AND c.ordinal_position IS NOT NULL) AS key_columns,
+			(SELECT STRING_AGG(c.column_name, ', ')
+			 FROM INFORMATION_SCHEMA.INDEX_COLUMNS c
+			 WHERE c.table_schema = t.table_schema
+			 AND c.table_name = t.table_name
+			 AND c.index_name = t.index_name
+			 AND c.ordinal_position IS NULL) AS storing_columns
+		FROM INFORMATION_SCHEMA.INDEXES t
+		WHERE %s
+		ORDER BY t.index_type DESC, t.index_name;`, tableFilter)},
+
+		// Tables interleaved in this one
+		{name: "interleaved", query: fmt.Sprintf(`SELECT 
+			c.table_name,
+			c.on_delete_action,
+			c.spanner_state
+		FROM INFORMATION_SCHEMA.TABLES t
+		JOIN INFORMATION_SCHEMA.TABLES c
+			ON c.table_schema = t.table_schema
+			AND c.parent_table_name = t.table_name
+		WHERE %s
+		ORDER BY c.table_name;`, tableFilter)},
+
+		// Storage over the last day
+		{name: "size", expensive: true, query: fmt.Sprintf(`SELECT 
+			interval_end,
+			ROUND(used_bytes / POW(1024, 2), 2) AS used_mb,
+			ROUND(used_ssd_bytes / POW(1024, 2), 2) AS used_ssd_mb,
+			ROUND(used_hdd_bytes / POW(1024, 2), 2) AS used_hdd_mb
+		FROM SPANNER_SYS.TABLE_SIZES_STATS_1HOUR
+		WHERE table_name = %s
+		ORDER BY interval_end DESC
+		LIMIT 24;`, tableLiteral)},
+
+		// Reads, writes and deletes per hour over the last day
+		{name: "operations", expensive: true, query: fmt.Sprintf(`SELECT 
+			interval_end,
+			read_query_count,
+			write_count,
+			delete_count
+		FROM SPANNER_SYS.TABLE_OPERATIONS_STATS_HOUR
+		WHERE table_name = %s
+		ORDER BY interval_end DESC
+		LIMIT 24;`, tableLiteral)},
+
+		// Row ranges of the table with the longest lock waits; their keys start with the table name
+		{name: "locks", expensive: true, query: fmt.Sprintf(`SELECT 
+			interval_end,
+			CAST(row_range_start_key AS STRING) AS row_range_start_key,
+			lock_wait_seconds
+		FROM SPANNER_SYS.LOCK_STATS_TOP_HOUR
+		WHERE STARTS_WITH(CAST(row_range_start_key AS STRING), %s)
+		ORDER BY lock_wait_seconds DESC
+		LIMIT 20;`, quoteLiteral("spanner", table+"("))},
+	}
+}
+
+// getHANATableStatsSections returns the sections of SAP HANA table statistics. Row count and
+// size come from M_TABLES; column-store tables also report their memory in main and delta
+// storage from M_CS_TABLES, which has one row per partition and none for row-store tables.
+func getHANATableStatsSections(tableName string) []tableStatsSection {
+	// Quote table name for safety
+	tableFilter := hanaTableFilter("t", tableName)
+
+	return []tableStatsSection{
+		// Store, row count and size on disk or in the row store
+		{name: "overview", query: fmt.Sprintf(`SELECT 
+			t.TABLE_NAME AS table_name,
+			t.TABLE_TYPE AS table_type,
+			t.RECORD_COUNT AS row_count,
+			ROUND(t.TABLE_SIZE / 1024 / 1024, 2) AS table_size_mb
+		FROM M_TABLES t
+		WHERE %s;`, tableFilter)},
+
+		// Column-store memory of all partitions
+		{name: "memory", query: fmt.Sprintf(`SELECT 
+			ROUND(SUM(t.MEMORY_SIZE_IN_TOTAL) / 1024 / 1024, 2) AS memory_mb,
+			ROUND(SUM(t.MEMORY_SIZE_IN_MAIN) / 1024 / 1024, 2) AS main_mb,
+			ROUND(SUM(t.MEMORY_SIZE_IN_DELTA) / 1024 / 1024, 2) AS delta_mb,
+			ROUND(SUM(t.ESTIMATED_MAX_MEMORY_SIZE_IN_TOTAL) / 1024 / 1024, 2) AS estimated_max_mb,
+			SUM(t.RAW_RECORD_COUNT_IN_DELTA) AS delta_rows,
+			MIN(t.LOADED) AS loaded,
+			MAX(t.LAST_MERGE_TIME) AS last_delta_merge,
+			SUM(t.READ_COUNT) AS read_count,
+			SUM(t.WRITE_COUNT) AS write_count
+		FROM M_CS_TABLES t
+		WHERE %s;`, tableFilter)},
+
+		// Column information
+		{name: "columns", query: fmt.Sprintf(`SELECT 
+			t.COLUMN_NAME AS column_name,
+			t.DATA_TYPE_NAME AS data_type,
+			t.LENGTH AS length,
+			t.SCALE AS scale,
+			t.IS_NULLABLE AS is_nullable,
+			t.DEFAULT_VALUE AS column_default,
+			t.COMMENTS AS description
+		FROM SYS.TABLE_COLUMNS t
+		WHERE %s
+		ORDER BY t.POSITION;`, tableFilter)},
+
+		// Index information
+		{name: "indexes", query: fmt.Sprintf(`SELECT 
+			t.INDEX_NAME AS index_name,
+			t.INDEX_TYPE AS index_type,
+			t."CONSTRAINT" AS constraint_type,
+			(SELECT STRING_AGG(c.COLUMN_NAME, ', ' ORDER BY c.POSITION)
+			 FROM SYS.INDEX_COLUMNS c
+			 WHERE c.SCHEMA_NAME = t.SCHEMA_NAME
+			 AND c.TABLE_NAME = t.TABLE_NAME
+			 AND c.INDEX_NAME = t.INDEX_NAME) AS column_names
+		FROM SYS.INDEXES t
+		WHERE %s
+		ORDER BY t.INDEX_NAME;`, tableFilter)},
+
+		// Memory, dictionary and compression of each column; reading M_CS_COLUMNS
+		// touches every column of the table
+		{name: "column_memory", expensive: true, query: fmt.Sprintf(`SELECT 
+			t.COLUMN_NAME AS column_name,
+			ROUND(SUM(t.MEMORY_SIZE_IN_TOTAL) / 1024 / 1024, 2) AS memory_mb,
+			ROUND(SUM(t.MEMORY_SIZE_IN_MAIN) / 1024 / 1024, 2) AS main_mb,
+			ROUND(SUM(t.MEMORY_SIZE_IN_DELTA) / 1024 / 1024, 2) AS delta_mb,
+			MAX(t.DISTINCT_COUNT) AS distinct_count,
+			MAX(t.COMPRESSION_TYPE) AS compression_type,
+			MIN(t.LOADED) AS loaded
+		FROM M_CS_COLUMNS t
+		WHERE %s
+		GROUP BY t.COLUMN_NAME
+		ORDER BY memory_mb DESC;`, tableFilter)},
+
+		// Rows and memory of each partition
+		{name: "partitions", expensive: true, query: fmt.Sprintf(`SELECT 
+			t.PART_ID AS partition_id,
+			t.HOST AS host,
+			t.RECORD_COUNT AS row_count,
+			ROUND(t.MEMORY_SIZE_IN_TOTAL / 1024 / 1024, 2) AS memory_mb,
+			ROUND(t.MEMORY_SIZE_IN_DELTA / 1024 / 1024, 2) AS delta_mb,
+			t.LOADED AS loaded,
+			t.LAST_MERGE_TIME AS last_delta_merge
+		FROM M_CS_TABLES t
+		WHERE %s
+		ORDER BY t.PART_ID;`, tableFilter)},
+	}
+}
+
+// getFirebirdTableStatsSections returns the sections of Firebird table statistics. The RDB$
+// tables keep no sizes or row counts, so the row count is an expensive full count; index
+// selectivity is as fresh as the last SET STATISTICS or backup and restore.
+func getFirebirdTableStatsSections(tableName string) []tableStatsSection {
+	// Quote table name for safety
+	tableLiteral := quoteLiteral("firebird", tableName)
+	tableIdent := quoteIdentifier("firebird", tableName)
+
+	return []tableStatsSection{
+		// Kind, owner and metadata format version
+		{name: "overview", query: fmt.Sprintf(`SELECT 
+			TRIM(r.RDB$RELATION_NAME) AS table_name,
+			CASE r.RDB$RELATION_TYPE
+				WHEN 2 THEN 'EXTERNAL'
+				WHEN 4 THEN 'GLOBAL TEMPORARY ON COMMIT PRESERVE ROWS'
+				WHEN 5 THEN 'GLOBAL TEMPORARY ON COMMIT DELETE ROWS'
+				ELSE 'PERSISTENT'
+			END AS table_type,
+			TRIM(r.RDB$OWNER_NAME) AS owner,
+			r.RDB$FORMAT AS format_version,
+			TRIM(r.RDB$EXTERNAL_FILE) AS external_file,
+			CAST(r.RDB$DESCRIPTION AS VARCHAR(8191)) AS description
+		FROM RDB$RELATIONS r
+		WHERE r.RDB$RELATION_NAME = %s;`, tableLiteral)},
+
+		// Column information
+		{name: "columns", query: fmt.Sprintf(`SELECT 
+			TRIM(rf.RDB$FIELD_NAME) AS column_name,
+			%s AS data_type,
+			CASE WHEN rf.RDB$FIELD_SOURCE STARTING WITH 'RDB$' THEN NULL ELSE TRIM(rf.RDB$FIELD_SOURCE) END AS domain,
+			CASE WHEN COALESCE(rf.RDB$NULL_FLAG, f.RDB$NULL_FLAG, 0) = 1 THEN 'NO' ELSE 'YES' END AS is_nullable,
+			CAST(COALESCE(rf.RDB$DEFAULT_SOURCE, f.RDB$DEFAULT_SOURCE) AS VARCHAR(1024)) AS column_default,
+			CAST(f.RDB$COMPUTED_SOURCE AS VARCHAR(1024)) AS computed_by
+		FROM RDB$RELATION_FIELDS rf
+		JOIN RDB$FIELDS f ON f.RDB$FIELD_NAME = rf.RDB$FIELD_SOURCE
+		WHERE rf.RDB$RELATION_NAME = %s
+		ORDER BY rf.RDB$FIELD_POSITION;`, firebirdTypeExpression("f"), tableLiteral)},
+
+		// Index selectivity and state
+		{name: "indexes", query: fmt.Sprintf(`SELECT 
+			TRIM(i.RDB$INDEX_NAME) AS index_name,
+			CASE WHEN i.RDB$UNIQUE_FLAG = 1 THEN 'YES' ELSE 'NO' END AS is_unique,
+			CASE WHEN i.RDB$INDEX_INACTIVE = 1 THEN 'INACTIVE' ELSE 'ACTIVE' END AS status,
+			i.RDB$STATISTICS AS selectivity,
+			(SELECT LIST(TRIM(s.RDB$FIELD_NAME), ', ')
+			 FROM RDB$INDEX_SEGMENTS s
+			 WHERE s.RDB$INDEX_NAME = i.RDB$INDEX_NAME) AS column_names
+		FROM RDB$INDICES i
+		WHERE i.RDB$RELATION_NAME = %s
+		ORDER BY i.RDB$INDEX_NAME;`, tableLiteral)},
+
+		// Triggers, which carry much of the logic of older Firebird applications
+		{name: "triggers", query: fmt.Sprintf(`SELECT 
+			TRIM(t.RDB$TRIGGER_NAME) AS trigger_name,
+			t.RDB$TRIGGER_TYPE AS trigger_type,
+			t.RDB$TRIGGER_SEQUENCE AS position,
+			CASE WHEN t.RDB$TRIGGER_INACTIVE = 1 THEN 'INACTIVE' ELSE 'ACTIVE' END AS status
+		FROM RDB$TRIGGERS t
+		WHERE t.RDB$RELATION_NAME = %s
+		AND COALESCE(t.RDB$SYSTEM_FLAG, 0) = 0
+		ORDER BY t.RDB$TRIGGER_TYPE, t.RDB$TRIGGER_SEQUENCE;`, tableLiteral)},
+
+		// Exact row count, which reads every record version
+		{name: "row_count", expensive: true, query: fmt.Sprintf(`SELECT COUNT(*) AS row_count FROM %s;`, tableIdent)},
+	}
+}
+
+// getCockroachTableStatsSections returns the sections of CockroachDB table statistics. Its
+// size comes from the ranges of the table and its row count from the table statistics.
+func getCockroachTableStatsSections(tableName string) []tableStatsSection {
+	// Quote table name for safety
+	tableLiteral := quoteLiteral("cockroachdb", tableName)
+	tableIdent := quoteIdentifier("cockroachdb", tableName)
+
+	return []tableStatsSection{
+		// Row count and schema state
+		{name: "overview", query: fmt.Sprintf(`SELECT 
+			t.name AS table_name,
+			t.schema_name,
+			s.estimated_row_count AS row_count,
+			t.locality,
+			t.mod_time AS last_schema_change
+		FROM crdb_internal.tables t
+		LEFT JOIN crdb_internal.table_row_statistics s ON s.table_id = t.table_id
+		WHERE t.database_name = current_database()
+		AND t.schema_name = 'public'
+		AND t.name = %s;`, tableLiteral)},
+
+		// Size and distribution of the ranges
+		{name: "ranges", query: fmt.Sprintf(`SELECT 
+			count(*) AS ranges,
+			round(sum(range_size_mb), 2) AS total_size_mb,
+			round(avg(range_size_mb), 2) AS avg_range_size_mb,
+			count(DISTINCT lease_holder) AS leaseholder_nodes
+		FROM [SHOW RANGES FROM TABLE %s WITH DETAILS];`, tableIdent)},
+
+		// Column information
+		{name: "columns", query: fmt.Sprintf(`SELECT 
+			column_name,
+			data_type,
+			is_nullable,
+			column_default,
+			is_hidden
+		FROM information_schema.columns
+		WHERE table_schema = 'public'
+		AND table_name = %s
+		ORDER BY ordinal_position;`, tableLiteral)},
+
+		// Index information
+		{name: "indexes", query: fmt.Sprintf(`SELECT 
+			i.index_name,
+			i.index_type,
+			i.is_unique,
+			i.is_inverted,
+			i.is_sharded,
+			u.total_reads,
+			u.last_read
+		FROM crdb_internal.table_indexes i
+		JOIN crdb_internal.tables t ON t.table_id = i.descriptor_id
+		LEFT JOIN crdb_internal.index_usage_statistics u
+			ON u.table_id = i.descriptor_id
+			AND u.index_id = i.index_id
+		WHERE t.database_name = current_database()
+		AND t.schema_name = 'public'
+		AND t.name = %s
+		ORDER BY i.index_id;`, tableLiteral)},
+
+		// Zone configuration: replication factor, constraints and GC TTL
+		{name: "zone_config", query: fmt.Sprintf(`SELECT 
+			target,
+			raw_config_sql
+		FROM [SHOW ZONE CONFIGURATION FROM TABLE %s];`, tableIdent)},
+
+		// Replicas and leaseholder of every range
+		{name: "replicas", expensive: true, query: fmt.Sprintf(`SELECT 
+			range_id,
+			round(range_size_mb, 2) AS range_size_mb,
+			lease_holder,
+			lease_holder_locality,
+			replicas,
+			replica_localities
+		FROM [SHOW RANGES FROM TABLE %s WITH DETAILS]
+		ORDER BY range_id;`, tableIdent)},
+	}
+}
+
+// getYugabyteTableStatsSections returns the sections of YugabyteDB table statistics. The
+// table is split into tablets rather than pages, so the overview reports its tablets and
+// sharding, and its row count is the estimate of the last ANALYZE.
+func getYugabyteTableStatsSections(tableName string) []tableStatsSection {
+	// Quote table name for safety
+	tableLiteral := quoteLiteral("yugabyte", tableName)
+
+	return []tableStatsSection{
+		// Tablets, sharding and row estimate
+		{name: "overview", query: fmt.Sprintf(`SELECT 
+			c.relname AS table_name,
+			n.nspname AS schema_name,
+			c.reltuples::bigint AS estimated_rows,
+			p.num_tablets,
+			CASE WHEN p.num_hash_key_columns > 0 THEN 'hash' ELSE 'range' END AS sharding,
+			p.num_hash_key_columns,
+			p.is_colocated,
+			s.last_analyze
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		CROSS JOIN LATERAL yb_table_properties(c.oid) p
+		LEFT JOIN pg_stat_user_tables s ON s.relid = c.oid
+		WHERE n.nspname = 'public'
+		AND c.relname = %s;`, tableLiteral)},
+
+		// Column information
+		{name: "columns", query: fmt.Sprintf(`SELECT 
+			column_name,
+			data_type,
+			is_nullable,
+			column_default
+		FROM information_schema.columns
+		WHERE table_schema = 'public'
+		AND table_name = %s
+		ORDER BY ordinal_position;`, tableLiteral)},
+
+		// Secondary indexes with their own tablets; the primary key is stored with the table
+		{name: "indexes", query: fmt.Sprintf(`SELECT 
+			i.relname AS index_name,
+			pg_get_indexdef(i.oid) AS definition,
+			p.num_tablets,
+			CASE WHEN p.num_hash_key_columns > 0 THEN 'hash' ELSE 'range' END AS sharding,
+			ui.idx_scan AS index_scans
+		FROM pg_index x
+		JOIN pg_class i ON i.oid = x.indexrelid
+		JOIN pg_class c ON c.oid = x.indrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		CROSS JOIN LATERAL yb_table_properties(i.oid) p
+		LEFT JOIN pg_stat_user_indexes ui ON ui.indexrelid = i.oid
+		WHERE n.nspname = 'public'
+		AND c.relname = %s
+		AND NOT x.indisprimary
+		ORDER BY i.relname;`, tableLiteral)},
+
+		// Scans and writes counted by the node serving this connection
+		{name: "io", expensive: true, query: fmt.Sprintf(`SELECT 
+			seq_scan AS sequential_scans,
+			seq_tup_read AS sequential_tuples_read,
+			idx_scan AS index_scans,
+			idx_tup_fetch AS index_tuples_fetched,
+			n_tup_ins AS tuples_inserted,
+			n_tup_upd AS tuples_updated,
+			n_tup_del AS tuples_deleted
+		FROM pg_stat_user_tables
+		WHERE schemaname = 'public'
+		AND relname = %s;`, tableLiteral)},
+
+		// Tablets of the table hosted by the node serving this connection, with their key ranges
+		{name: "tablets", expensive: true, query: fmt.Sprintf(`SELECT 
+			tablet_id,
+			encode(partition_key_start, 'hex') AS partition_key_start,
+			encode(partition_key_end, 'hex') AS partition_key_end,
+			state
+		FROM yb_local_tablets
+		WHERE namespace_name = current_database()
+		AND ysql_schema_name = 'public'
+		AND table_name = %s
+		ORDER BY partition_key_start NULLS FIRST;`, tableLiteral)},
+	}
+}
+
+// getGreenplumTableStatsSections returns the sections of Greenplum table statistics. The
+// overview names the distribution key, which decides the segment of each row; size, skew
+// and the size on each segment are expensive, since every segment has to answer them.
+func getGreenplumTableStatsSections(tableName string) []tableStatsSection {
+	// Quote table name for safety
+	tableLiteral := quoteLiteral("greenplum", tableName)
+	regclassLiteral := quoteLiteral("greenplum", quoteIdentifier("greenplum", "public."+tableName))
+
+	return []tableStatsSection{
+		// Distribution policy and row estimate
+		{name: "overview", query: fmt.Sprintf(`SELECT 
+			c.relname AS table_name,
+			n.nspname AS schema_name,
+			CASE
+				WHEN p.policytype = 'r' THEN 'replicated'
+				WHEN array_length(p.distkey::int2[], 1) IS NULL THEN 'random'
+				ELSE 'hash'
+			END AS distribution,
+			pg_get_table_distributedby(c.oid) AS distributed_by,
+			p.numsegments,
+			c.reltuples::bigint AS estimated_rows,
+			s.last_analyze
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN gp_distribution_policy p ON p.localoid = c.oid
+		LEFT JOIN pg_stat_user_tables s ON s.relid = c.oid
+		WHERE n.nspname = 'public'
+		AND c.relname = %s;`, tableLiteral)},
+
+		// Column information
+		{name: "columns", query: fmt.Sprintf(`SELECT 
+			column_name,
+			data_type,
+			is_nullable,
+			column_default
+		FROM information_schema.columns
+		WHERE table_schema = 'public'
+		AND table_name = %s
+		ORDER BY ordinal_position;`, tableLiteral)},
+
+		// Index information
+		{name: "indexes", query: fmt.Sprintf(`SELECT 
+			indexname AS index_name,
+			indexdef AS definition
+		FROM pg_indexes
+		WHERE schemaname = 'public'
+		AND tablename = %s
+		ORDER BY indexname;`, tableLiteral)},
+
+		// Size summed over the segments
+		{name: "size", expensive: true, query: fmt.Sprintf(`SELECT 
+			pg_size_pretty(sotdsize::bigint) AS table_size,
+			pg_size_pretty(sotdtoastsize::bigint) AS toast_size,
+			pg_size_pretty(sotdadditionalsize::bigint) AS additional_size,
+			pg_size_pretty((sotdsize + sotdtoastsize + sotdadditionalsize)::bigint) AS total_size
+		FROM gp_toolkit.gp_size_of_table_disk
+		WHERE sotdschemaname = 'public'
+		AND sotdtablename = %s;`, tableLiteral)},
+
+		// Skew of the rows over the segments; a coefficient of variation near 0 is even, and the
+		// idle fraction is the share of the segments' time spent waiting on the fullest one
+		{name: "skew", expensive: true, query: fmt.Sprintf(`SELECT 
+			k.skccoeff AS skew_coefficient,
+			f.siffraction AS idle_fraction
+		FROM gp_toolkit.gp_skew_coefficients k
+		LEFT JOIN gp_toolkit.gp_skew_idle_fractions f ON f.sifoid = k.skcoid
+		WHERE k.skcnamespace = 'public'
+		AND k.skcrelname = %s;`, tableLiteral)},
+
+		// Size of the table on each segment, computed on the segment itself
+		{name: "segments", expensive: true, query: fmt.Sprintf(`SELECT 
+			gp_segment_id AS segment,
+			pg_relation_size(%[1]s) AS size_bytes,
+			pg_size_pretty(pg_relation_size(%[1]s)) AS size,
+			pg_size_pretty(pg_total_relation_size(%[1]s)) AS total_size
+		FROM gp_dist_random('gp_id')
+		ORDER BY gp_segment_id;`, regclassLiteral)},
+	}
+}
+
+// getDatabricksTableStatsSections returns the sections of Databricks table statistics. The
+// table may be qualified as schema.table or catalog.schema.table; DESCRIBE DETAIL reports
+// the format, files, size, partitioning and clustering of a Delta table.
+func getDatabricksTableStatsSections(tableName string) []tableStatsSection {
+	// Quote table name for safety
+	tableFilter := databricksTableFilter("t", tableName)
+	columnFilter := databricksTableFilter("c", tableName)
+	tableIdent := quoteIdentifier("databricks", tableName)
+
+	return []tableStatsSection{
+		// Table type, format, owner and comment from Unity Catalog
+		{name: "overview", query: fmt.Sprintf(`SELECT 
+			t.table_catalog,
+			t.table_schema,
+			t.table_name,
+			t.table_type,
+			t.data_source_format,
+			t.table_owner,
+			t.comment,
+			t.created,
+			t.last_altered
+		FROM system.information_schema.tables t
+		WHERE %s;`, tableFilter)},
+
+		// Delta table detail: files, size in bytes, partition and clustering columns
+		{name: "detail", query: fmt.Sprintf(`DESCRIBE DETAIL %s;`, tableIdent)},
+
+		// Column information
+		{name: "columns", query: fmt.Sprintf(`SELECT 
+			c.column_name,
+			c.full_data_type,
+			c.is_nullable,
+			c.partition_index,
+			c.comment
+		FROM system.information_schema.columns c
+		WHERE %s
+		ORDER BY c.ordinal_position;`, columnFilter)},
+
+		// Latest operations of the Delta log, such as writes, merges and optimizations
+		{name: "history", expensive: true, query: fmt.Sprintf(`DESCRIBE HISTORY %s LIMIT 10;`, tableIdent)},
+
+		// Exact row count, which scans the table
+		{name: "row_count", expensive: true, query: fmt.Sprintf(`SELECT 
+			COUNT(*) AS row_count
+		FROM %s;`, tableIdent)},
+	}
+}
+
+// getTrinoTableStatsSections returns the sections of Trino table statistics, read from the
+// default schema; the connector reports the statistics it keeps, such as those of a metastore
+func getTrinoTableStatsSections(tableName string) []tableStatsSection {
+	// Quote table name for safety
+	tableLiteral := quoteLiteral("trino", tableName)
+	tableIdent := quoteIdentifier("trino", tableName)
+
+	return []tableStatsSection{
+		// Table type and comment
+		{name: "overview", query: fmt.Sprintf(`SELECT 
+			t.table_catalog,
+			t.table_schema,
+			t.table_name,
+			t.table_type,
+			c.comment
+		FROM information_schema.tables t
+		LEFT JOIN system.metadata.table_comments c
+			ON c.catalog_name = t.table_catalog
+			AND c.schema_name = t.table_schema
+			AND c.table_name = t.table_name
+		WHERE t.table_schema = current_schema
+		AND t.table_name = %s;`, tableLiteral)},
+
+		// Column information
+		{name: "columns", query: fmt.Sprintf(`SELECT 
+			column_name,
+			data_type,
+			is_nullable,
+			comment,
+			extra_info
+		FROM information_schema.columns
+		WHERE table_schema = current_schema
+		AND table_name = %s
+		ORDER BY ordinal_position;`, tableLiteral)},
+
+		// Statistics of the connector: row count, and size, distinct values and nulls per column
+		{name: "statistics", query: fmt.Sprintf(`SHOW STATS FOR %s;`, tableIdent)},
+
+		// Connector properties such as format, location and partitioning
+		{name: "definition", query: fmt.Sprintf(`SHOW CREATE TABLE %s;`, tableIdent)},
+
+		// Exact row count, which scans the table
+		{name: "row_count", expensive: true, query: fmt.Sprintf(`SELECT 
+			COUNT(*) AS row_count
+		FROM %s;`, tableIdent)},
+	}
 }
@@ -0,0 +1,253 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func tableStatsText(t *testing.T, useCase UseCaseProvider, params map[string]interface{}) string {
+	result, err := NewTableStatsTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "pg1", useCase)
+	assert.NoError(t, err)
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+}
+
+func TestTableStatsDefaultSections(t *testing.T) {
+	useCase := &mockUseCase{dbType: "postgres"}
+	text := tableStatsText(t, useCase, map[string]interface{}{"database": "pg1", "table": "orders"})
+
+	// The cheap sections run after the check for TimescaleDB
+	assert.Len(t, useCase.queries, 4)
+	assert.Contains(t, useCase.queries[0], "extname = 'timescaledb'")
+	assert.NotContains(t, text, "## hypertable\n")
+	assert.Contains(t, text, "## overview\n")
+	assert.Contains(t, text, "## columns\n")
+	assert.Contains(t, text, "## indexes\n")
+	assert.NotContains(t, text, "## bloat\n")
+	assert.Contains(t, text, "Expensive sections are computed on request: pass sections with io or bloat.")
+}
+
+func TestTableStatsExpensiveSectionsAreCached(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	previous := tableStatsNow
+	tableStatsNow = func() time.Time { return now }
+	t.Cleanup(func() { tableStatsNow = previous })
+
+	useCase := &mockUseCase{
+		dbType:  "postgres",
+		results: map[string]*domain.QueryResult{"est_tblpages": {Columns: []string{"bloat_size_pretty"}, Rows: [][]interface{}{{"12 MB"}}}},
+	}
+	params := map[string]interface{}{"database": "pg1", "table": "orders", "sections": []interface{}{"bloat", "overview"}}
+
+	text := tableStatsText(t, useCase, params)
+	assert.Len(t, useCase.queries, 2)
+	assert.Less(t, strings.Index(text, "## overview"), strings.Index(text, "## bloat"))
+	assert.Contains(t, text, "12 MB")
+
+	// Within the TTL only the cheap section runs again
+	now = now.Add(10 * time.Second)
+	text = tableStatsText(t, useCase, params)
+	assert.Len(t, useCase.queries, 3)
+	assert.Contains(t, text, "Cached result from 10s ago.")
+	assert.Contains(t, text, "12 MB")
+
+	now = now.Add(expensiveStatsTTL)
+	tableStatsText(t, useCase, params)
+	assert.Len(t, useCase.queries, 5)
+}
+
+func TestTableStatsDetailedAndUnknownSection(t *testing.T) {
+	useCase := &mockUseCase{dbType: "mysql"}
+	text := tableStatsText(t, useCase, map[string]interface{}{"database": "my1", "table": "orders", "detailed": true})
+	assert.Contains(t, text, "## index_usage\n")
+	assert.NotContains(t, text, "Expensive sections are computed on request")
+
+	_, err := NewTableStatsTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "my1", "table": "orders", "sections": []interface{}{"bloat"}},
+	}, "my1", useCase)
+	assert.EqualError(t, err, "sections parameter must only contain overview, columns, indexes, status, index_usage or io")
+}
+
+func TestTableStatsClickHouse(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "clickhouse",
+		results: map[string]*domain.QueryResult{"uniqExact(partition)": {
+			Columns: []string{"partitions", "parts", "rows", "compressed_size", "uncompressed_size", "compression_ratio"},
+			Rows:    [][]interface{}{{"12", "40", "1000000", "9.50 MiB", "61.20 MiB", "6.44"}},
+		}},
+	}
+	text := tableStatsText(t, useCase, map[string]interface{}{"database": "ch1", "table": "events"})
+
+	assert.Len(t, useCase.queries, 4)
+	for _, query := range useCase.queries {
+		assert.Contains(t, query, "database = currentDatabase()")
+		assert.Contains(t, query, "'events'")
+	}
+	assert.Contains(t, text, "## storage\n")
+	assert.Contains(t, text, "6.44")
+	assert.Contains(t, text, "Expensive sections are computed on request: pass sections with partitions or merges.")
+}
+
+func TestTableStatsBigQuery(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "bigquery",
+		results: map[string]*domain.QueryResult{"COUNTIF(storage_tier": {
+			Columns: []string{"partitions", "total_rows", "logical_gb", "billable_gb", "long_term_partitions", "last_modified"},
+			Rows:    [][]interface{}{{int64(30), int64(5000000), 12.5, 4.25, int64(20), "2026-10-01 00:00:00"}},
+		}},
+	}
+	text := tableStatsText(t, useCase, map[string]interface{}{"database": "bq1", "table": "orders"})
+
+	assert.Len(t, useCase.queries, 4)
+	for _, query := range useCase.queries {
+		assert.Contains(t, query, "INFORMATION_SCHEMA")
+		assert.Contains(t, query, "'orders'")
+	}
+	assert.Contains(t, text, "## storage\n")
+	assert.Contains(t, text, "4.25")
+	assert.Contains(t, text, "Expensive sections are computed on request: pass sections with partitions.")
+}
+
+func TestTableStatsSpanner(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "spanner",
+		results: map[string]*domain.QueryResult{"c.parent_table_name = t.table_name\n\t\tWHERE": {
+			Columns: []string{"table_name", "on_delete_action", "spanner_state"},
+			Rows:    [][]interface{}{{"Songs", "CASCADE", "COMMITTED"}},
+		}},
+	}
+	text := tableStatsText(t, useCase, map[string]interface{}{"database": "sp1", "table": "Albums"})
+
+	assert.Len(t, useCase.queries, 4)
+	for _, query := range useCase.queries {
+		assert.Contains(t, query, "INFORMATION_SCHEMA")
+		assert.NotContains(t, query, "SPANNER_SYS")
+		assert.Contains(t, query, "t.table_schema = '' AND t.table_name = 'Albums'")
+	}
+	assert.Contains(t, text, "## interleaved\n")
+	assert.Contains(t, text, "Songs")
+	assert.Contains(t, text, "Expensive sections are computed on request: pass sections with size, operations or locks.")
+
+	useCase = &mockUseCase{dbType: "spanner"}
+	tableStatsText(t, useCase, map[string]interface{}{"database": "sp1", "table": "Albums", "sections": []interface{}{"size", "locks"}})
+	assert.Len(t, useCase.queries, 2)
+	queries := strings.Join(useCase.queries, "\n")
+	assert.Contains(t, queries, "FROM SPANNER_SYS.TABLE_SIZES_STATS_1HOUR\n\t\tWHERE table_name = 'Albums'")
+	assert.Contains(t, queries, "STARTS_WITH(CAST(row_range_start_key AS STRING), 'Albums(')")
+}
+
+func TestTableStatsCockroach(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "cockroachdb",
+		results: map[string]*domain.QueryResult{"count(DISTINCT lease_holder)": {
+			Columns: []string{"ranges", "total_size_mb", "avg_range_size_mb", "leaseholder_nodes"},
+			Rows:    [][]interface{}{{int64(8), 1024.5, 128.06, int64(3)}},
+		}},
+	}
+	text := tableStatsText(t, useCase, map[string]interface{}{"database": "crdb1", "table": "orders"})
+
+	assert.Len(t, useCase.queries, 5)
+	for _, query := range useCase.queries {
+		assert.NotContains(t, query, "pg_stat_user_tables")
+	}
+	assert.Contains(t, text, "## ranges\n")
+	assert.Contains(t, text, "1024.5")
+	assert.Contains(t, text, "Expensive sections are computed on request: pass sections with replicas.")
+}
+
+func TestTableStatsYugabyte(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "yugabyte",
+		results: map[string]*domain.QueryResult{"yb_table_properties(c.oid)": {
+			Columns: []string{"table_name", "schema_name", "estimated_rows", "num_tablets", "sharding", "num_hash_key_columns", "is_colocated", "last_analyze"},
+			Rows:    [][]interface{}{{"orders", "public", int64(120000), int64(12), "hash", int64(1), false, nil}},
+		}},
+	}
+	text := tableStatsText(t, useCase, map[string]interface{}{"database": "yb1", "table": "orders"})
+
+	assert.Len(t, useCase.queries, 3)
+	for _, query := range useCase.queries {
+		assert.NotContains(t, query, "pg_relation_size")
+		assert.NotContains(t, query, "timescaledb")
+		assert.Contains(t, query, "'orders'")
+	}
+	assert.Contains(t, text, "## overview\n")
+	assert.Contains(t, text, "orders\tpublic\t120000\t12\thash")
+	assert.Contains(t, text, "Expensive sections are computed on request: pass sections with io or tablets.")
+}
+
+func TestTableStatsHANA(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "hana",
+		results: map[string]*domain.QueryResult{"FROM M_CS_TABLES t\n\t\tWHERE t.SCHEMA_NAME = 'SALES' AND t.TABLE_NAME = 'ORDERS';": {
+			Columns: []string{"memory_mb", "main_mb", "delta_mb", "estimated_max_mb", "delta_rows", "loaded", "last_delta_merge", "read_count", "write_count"},
+			Rows:    [][]interface{}{{"512.25", "480.5", "31.75", "900.1", int64(2500), "TRUE", nil, int64(42), int64(7)}},
+		}},
+	}
+	text := tableStatsText(t, useCase, map[string]interface{}{"database": "hana1", "table": "SALES.ORDERS", "sections": []interface{}{"overview", "memory", "partitions"}})
+
+	assert.Len(t, useCase.queries, 3)
+	assert.NotContains(t, strings.Join(useCase.queries, "\n"), "M_CS_COLUMNS")
+	assert.Contains(t, text, "## memory\n")
+	assert.Contains(t, text, "512.25\t480.5\t31.75\t900.1\t2500\tTRUE")
+	assert.Contains(t, text, "## partitions\n")
+}
+
+func TestTableStatsGreenplum(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "greenplum",
+		results: map[string]*domain.QueryResult{"gp_dist_random('gp_id')": {
+			Columns: []string{"segment", "size_bytes", "size", "total_size"},
+			Rows: [][]interface{}{
+				{int64(0), int64(8388608), "8192 kB", "8240 kB"},
+				{int64(1), int64(65536), "64 kB", "96 kB"},
+			},
+		}},
+	}
+	text := tableStatsText(t, useCase, map[string]interface{}{"database": "gp1", "table": "orders", "sections": []interface{}{"overview", "skew", "segments"}})
+
+	queries := strings.Join(useCase.queries, "\n")
+	assert.Len(t, useCase.queries, 3)
+	assert.Contains(t, queries, "pg_get_table_distributedby(c.oid)")
+	assert.Contains(t, queries, "gp_toolkit.gp_skew_coefficients")
+	assert.Contains(t, queries, `pg_relation_size('"public"."orders"')`)
+	assert.NotContains(t, queries, "gp_size_of_table_disk")
+	assert.Contains(t, text, "## segments\n")
+	assert.Contains(t, text, "1\t65536\t64 kB\t96 kB")
+}
+
+func TestTableStatsTrino(t *testing.T) {
+	useCase := &mockUseCase{dbType: "trino"}
+	text := tableStatsText(t, useCase, map[string]interface{}{"database": "trino1", "table": "events"})
+
+	assert.Len(t, useCase.queries, 4)
+	assert.Contains(t, useCase.queries, `SHOW STATS FOR "events";`)
+	assert.Contains(t, text, "## statistics\n")
+	assert.Contains(t, text, "Expensive sections are computed on request: pass sections with row_count.")
+}
+
+func TestTableStatsDatabricks(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "databricks",
+		results: map[string]*domain.QueryResult{"DESCRIBE DETAIL": {
+			Columns: []string{"format", "name", "partitionColumns", "clusteringColumns", "numFiles", "sizeInBytes"},
+			Rows:    [][]interface{}{{"delta", "main.web.events", `["day"]`, "[]", int64(48), int64(73400320)}},
+		}},
+	}
+	text := tableStatsText(t, useCase, map[string]interface{}{"database": "dbx1", "table": "main.web.events"})
+
+	queries := strings.Join(useCase.queries, "\n")
+	assert.Len(t, useCase.queries, 3)
+	assert.Contains(t, useCase.queries, "DESCRIBE DETAIL `main`.`web`.`events`;")
+	assert.Contains(t, queries, "t.table_catalog = 'main' AND t.table_schema = 'web' AND t.table_name = 'events'")
+	assert.NotContains(t, queries, "DESCRIBE HISTORY")
+	assert.Contains(t, text, "## detail\n")
+	assert.Contains(t, text, "delta\tmain.web.events\t[\"day\"]\t[]\t48\t73400320")
+	assert.Contains(t, text, "Expensive sections are computed on request: pass sections with history or row_count.")
+}
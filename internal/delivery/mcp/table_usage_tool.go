@@ -0,0 +1,291 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// TableUsageTool handles reporting read/write activity per table
+type TableUsageTool struct {
+	BaseToolType
+}
+
+// NewTableUsageTool creates a new table usage tool type
+func NewTableUsageTool() *TableUsageTool {
+	return &TableUsageTool{
+		BaseToolType: BaseToolType{
+			name:        "table_usage",
+			description: "Show read and write activity per table from the database's own statistics (pg_stat_user_tables on PostgreSQL, performance_schema table I/O on MySQL) as a heatmap. It identifies hot tables that receive most of the activity, and dead tables that have never been read since the statistics were reset. By default the counters since the last statistics reset are shown; with sample_seconds the tool takes two snapshots and reports only the activity in between.",
+		},
+	}
+}
+
+// CreateTool creates a table usage tool
+func (t *TableUsageTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Show per-table read/write activity with hot and dead tables"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to report on (optional, defaults to all user schemas)"),
+		),
+		tools.WithNumber("sample_seconds",
+			tools.Description("Measure activity over this many seconds instead of since the statistics reset (optional, max 300)"),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of tables to list (default: 30)"),
+		),
+	)
+}
+
+// HandleRequest handles table usage tool requests
+func (t *TableUsageTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema := input.optionalString("schema", "")
+	sampleSeconds := input.optionalFloat("sample_seconds", 0)
+	limit := input.intAtLeast("limit", 30, 1)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if sampleSeconds < 0 || sampleSeconds > 300 {
+		return nil, fmt.Errorf("sample_seconds parameter must be between 0 and 300")
+	}
+	sample := time.Duration(sampleSeconds * float64(time.Second))
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for table statistics: %s", dbType)
+	}
+
+	logger.Info("Getting table usage for database %s, schema %s", targetDbID, schema)
+
+	usage, err := loadTableUsage(ctx, useCase, targetDbID, dialect, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table usage: %w", err)
+	}
+	window := "since the statistics were last reset"
+	if sample > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(sample):
+		}
+		later, err := loadTableUsage(ctx, useCase, targetDbID, dialect, schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get table usage: %w", err)
+		}
+		usage = diffTableUsage(usage, later)
+		window = fmt.Sprintf("during a %s sample", sample)
+	}
+
+	// Format the response
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Table Usage for Database %s\n\n", targetDbID))
+	response.WriteString(fmt.Sprintf("Activity %s.\n\n", window))
+	response.WriteString(formatTableUsage(usage, limit, sample > 0))
+
+	return createTextResponse(response.String()), nil
+}
+
+// tableUsage holds the activity counters of one table
+type tableUsage struct {
+	Schema   string
+	Table    string
+	Reads    int64 // scans (PostgreSQL) or read operations (MySQL)
+	RowsRead int64
+	Inserts  int64
+	Updates  int64
+	Deletes  int64
+	LiveRows int64 // estimated, PostgreSQL only
+}
+
+// writes returns the total number of written rows
+func (u tableUsage) writes() int64 {
+	return u.Inserts + u.Updates + u.Deletes
+}
+
+// loadTableUsage reads the activity counters of every table in the schema, or in all user schemas
+func loadTableUsage(ctx context.Context, useCase UseCaseProvider, dbID string, dialect Dialect, schema string) ([]tableUsage, error) {
+	query, params, ok := dialect.TableUsageQuery(schema)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for table statistics: %s", dialect.Name())
+	}
+	result, err := useCase.ExecuteQuery(ctx, dbID, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make([]tableUsage, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		usage = append(usage, tableUsage{
+			Schema:   valueString(row[0]),
+			Table:    valueString(row[1]),
+			Reads:    valueInt64(row[2]),
+			RowsRead: valueInt64(row[3]),
+			Inserts:  valueInt64(row[4]),
+			Updates:  valueInt64(row[5]),
+			Deletes:  valueInt64(row[6]),
+			LiveRows: valueInt64(row[7]),
+		})
+	}
+	return usage, nil
+}
+
+// diffTableUsage returns the activity between two snapshots of the same tables
+func diffTableUsage(before, after []tableUsage) []tableUsage {
+	previous := make(map[string]tableUsage, len(before))
+	for _, u := range before {
+		previous[schemaTableKey(u.Schema, u.Table)] = u
+	}
+	delta := make([]tableUsage, 0, len(after))
+	for _, u := range after {
+		p := previous[schemaTableKey(u.Schema, u.Table)]
+		delta = append(delta, tableUsage{
+			Schema:   u.Schema,
+			Table:    u.Table,
+			Reads:    u.Reads - p.Reads,
+			RowsRead: u.RowsRead - p.RowsRead,
+			Inserts:  u.Inserts - p.Inserts,
+			Updates:  u.Updates - p.Updates,
+			Deletes:  u.Deletes - p.Deletes,
+			LiveRows: u.LiveRows,
+		})
+	}
+	return delta
+}
+
+// heatBar renders a share between 0 and 1 as a ten-step bar
+func heatBar(share float64) string {
+	filled := int(share*10 + 0.5)
+	if share > 0 && filled == 0 {
+		filled = 1
+	}
+	return strings.Repeat("#", filled) + strings.Repeat(".", 10-filled)
+}
+
+// formatTableUsage renders table activity as a heatmap with hot and dead tables
+func formatTableUsage(usage []tableUsage, limit int, sampled bool) string {
+	var sb strings.Builder
+	if len(usage) == 0 {
+		sb.WriteString("No table statistics available.\n")
+		return sb.String()
+	}
+
+	sorted := append([]tableUsage(nil), usage...)
+	activity := func(u tableUsage) int64 { return u.Reads + u.writes() }
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if activity(sorted[i]) != activity(sorted[j]) {
+			return activity(sorted[i]) > activity(sorted[j])
+		}
+		return schemaTableKey(sorted[i].Schema, sorted[i].Table) < schemaTableKey(sorted[j].Schema, sorted[j].Table)
+	})
+
+	var total, maxActivity int64
+	for _, u := range sorted {
+		total += activity(u)
+		if activity(u) > maxActivity {
+			maxActivity = activity(u)
+		}
+	}
+
+	sb.WriteString("| Table | Reads | Rows Read | Inserts | Updates | Deletes | Share | Heat |\n")
+	sb.WriteString("|-------|-------|-----------|---------|---------|---------|-------|------|\n")
+	for i, u := range sorted {
+		if i >= limit {
+			sb.WriteString(fmt.Sprintf("\n... %d more tables not shown\n", len(sorted)-limit))
+			break
+		}
+		var share, heat float64
+		if total > 0 {
+			share = float64(activity(u)) / float64(total)
+		}
+		if maxActivity > 0 {
+			heat = float64(activity(u)) / float64(maxActivity)
+		}
+		sb.WriteString(fmt.Sprintf("| %s.%s | %d | %d | %d | %d | %d | %.1f%% | %s |\n",
+			u.Schema, u.Table, u.Reads, u.RowsRead, u.Inserts, u.Updates, u.Deletes, share*100, heatBar(heat)))
+	}
+
+	// Hot tables: the most active tables that together account for 80% of the activity
+	if total > 0 {
+		var hot []string
+		var cumulative int64
+		for _, u := range sorted {
+			if activity(u) == 0 || float64(cumulative) >= 0.8*float64(total) {
+				break
+			}
+			cumulative += activity(u)
+			hot = append(hot, fmt.Sprintf("%s.%s", u.Schema, u.Table))
+		}
+		sb.WriteString(fmt.Sprintf("\nHot tables (%.0f%% of activity): %s\n", float64(cumulative)*100/float64(total), strings.Join(hot, ", ")))
+	}
+
+	var dead, writeOnly []string
+	for _, u := range sorted {
+		name := fmt.Sprintf("%s.%s", u.Schema, u.Table)
+		switch {
+		case u.Reads == 0 && u.writes() == 0:
+			dead = append(dead, name)
+		case u.Reads == 0:
+			writeOnly = append(writeOnly, name)
+		}
+	}
+	sort.Strings(dead)
+	sort.Strings(writeOnly)
+	qualifier := "never read or written since the statistics reset"
+	if sampled {
+		qualifier = "no activity during the sample"
+	}
+	if len(dead) > 0 {
+		sb.WriteString(fmt.Sprintf("\nDead tables (%s): %s\n", qualifier, strings.Join(dead, ", ")))
+	}
+	if len(writeOnly) > 0 {
+		sb.WriteString(fmt.Sprintf("Written but never read: %s\n", strings.Join(writeOnly, ", ")))
+	}
+
+	return sb.String()
+}
+
+// getPostgresTableUsageQuery returns the query for the activity counters of the tables of the
+// schema, or of all user schemas
+func getPostgresTableUsageQuery(schema string) (string, []interface{}) {
+	query := `
+SELECT schemaname, relname,
+       COALESCE(seq_scan, 0) + COALESCE(idx_scan, 0) AS reads,
+       COALESCE(seq_tup_read, 0) + COALESCE(idx_tup_fetch, 0) AS rows_read,
+       n_tup_ins, n_tup_upd, n_tup_del, n_live_tup
+FROM pg_stat_user_tables`
+	if schema == "" {
+		return query, nil
+	}
+	return query + "\nWHERE schemaname = $1", []interface{}{schema}
+}
+
+// getMySQLTableUsageQuery returns the query for the activity counters of the tables of the
+// schema, or of all user schemas; MySQL does not estimate live rows here
+func getMySQLTableUsageQuery(schema string) (string, []interface{}) {
+	query := `
+SELECT object_schema, object_name, count_read, count_fetch, count_insert, count_update, count_delete, 0
+FROM performance_schema.table_io_waits_summary_by_table
+WHERE object_type = 'TABLE'
+AND object_schema NOT IN ('mysql', 'performance_schema', 'information_schema', 'sys')`
+	if schema == "" {
+		return query, nil
+	}
+	return query + "\nAND object_schema = ?", []interface{}{schema}
+}
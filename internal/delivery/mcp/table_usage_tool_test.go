@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestDiffTableUsage(t *testing.T) {
+	before := []tableUsage{{Schema: "public", Table: "orders", Reads: 10, Inserts: 5}}
+	after := []tableUsage{
+		{Schema: "public", Table: "orders", Reads: 25, Inserts: 6},
+		{Schema: "public", Table: "events", Inserts: 3},
+	}
+
+	assert.Equal(t, []tableUsage{
+		{Schema: "public", Table: "orders", Reads: 15, Inserts: 1},
+		{Schema: "public", Table: "events", Inserts: 3},
+	}, diffTableUsage(before, after))
+}
+
+func TestFormatTableUsage(t *testing.T) {
+	usage := []tableUsage{
+		{Schema: "public", Table: "audit_log", Inserts: 40},
+		{Schema: "public", Table: "legacy_users"},
+		{Schema: "public", Table: "orders", Reads: 900, Updates: 60},
+	}
+
+	text := formatTableUsage(usage, 10, false)
+	assert.Contains(t, text, "| public.orders | 900 | 0 | 0 | 60 | 0 | 96.0% | ########## |")
+	assert.Contains(t, text, "| public.audit_log | 0 | 0 | 40 | 0 | 0 | 4.0% | #......... |")
+	assert.Contains(t, text, "Hot tables (96% of activity): public.orders")
+	assert.Contains(t, text, "Dead tables (never read or written since the statistics reset): public.legacy_users")
+	assert.Contains(t, text, "Written but never read: public.audit_log")
+
+	assert.Contains(t, formatTableUsage(usage, 1, true), "... 2 more tables not shown")
+}
+
+func TestTableUsageTool(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{"pg_stat_user_tables": {
+			Rows: [][]interface{}{{"app", "orders", int64(12), int64(300), int64(4), int64(1), int64(0), int64(1000)}},
+		}},
+	}
+
+	result, err := NewTableUsageTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "schema": "app"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| app.orders | 12 | 300 | 4 | 1 | 0 | 100.0% |")
+	assert.Contains(t, useCase.queries[0], "WHERE schemaname = $1")
+}
@@ -0,0 +1,448 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// changesNow returns the current time; tests replace it
+var changesNow = time.Now
+
+// binlogPageSize is how many binary log events each SHOW BINLOG EVENTS call reads
+const binlogPageSize = 1000
+
+// changeFilter selects the row changes tail_changes reports
+type changeFilter struct {
+	Schema     string
+	Table      string
+	Operations map[string]bool
+	Since      time.Time
+	// Match holds column values the new or old row must have, compared as text
+	Match map[string]string
+}
+
+// matches reports whether a change passes the filter
+func (f *changeFilter) matches(change rowChange) bool {
+	if f.Table != "" && (change.Table != f.Table || (f.Schema != "" && change.Schema != f.Schema)) {
+		return false
+	}
+	if len(f.Operations) > 0 && !f.Operations[change.Operation] {
+		return false
+	}
+	if !f.Since.IsZero() && (change.Time.IsZero() || change.Time.Before(f.Since)) {
+		return false
+	}
+	for column, want := range f.Match {
+		found := false
+		for _, values := range [][]changeValue{change.New, change.Old} {
+			for _, value := range values {
+				found = found || (value.Column == column && !value.Null && !value.Unchanged && value.Value == want)
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// TailChangesTool handles reading recent row changes from the WAL or the binary log
+type TailChangesTool struct {
+	BaseToolType
+}
+
+// NewTailChangesTool creates a new change tailing tool type
+func NewTailChangesTool() *TailChangesTool {
+	return &TailChangesTool{
+		BaseToolType: BaseToolType{
+			name:        "tail_changes",
+			description: "Show recent row changes (inserts, updates, deletes, truncates) to answer questions like \"what changed in the last 10 minutes?\". On PostgreSQL, reads a logical replication slot using the wal2json or pgoutput plugin with row values and commit times; the slot only sees changes made after it was created, so create one first with replication_slots (and a publication for pgoutput). Changes are peeked, leaving the slot where it was, unless consume is set. On MySQL, reads the newest binary log with SHOW BINLOG EVENTS: row-based events give the table and operation but no values or times, and the statement when binlog_rows_query_log_events is on.",
+		},
+	}
+}
+
+// CreateTool creates a change tailing tool
+func (t *TailChangesTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Show recent row changes from logical decoding (PostgreSQL) or the binary log (MySQL)"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Only show changes to this table, optionally schema-qualified (default: all tables)"),
+		),
+		tools.WithArray("operations",
+			tools.Description("Only show these operations: insert, update, delete, truncate (default: all)"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithString("since",
+			tools.Description("Only show changes committed in this window, such as 10m or 2h, or after an RFC 3339 time (PostgreSQL only)"),
+		),
+		tools.WithObject("match",
+			tools.Description("Only show changes whose new or old row has these column values, such as {\"id\": 42}"),
+		),
+		tools.WithNumber("max_events",
+			tools.Description("Number of most recent matching changes to show (default: 100, max: 10000)"),
+		),
+		tools.WithNumber("scan_limit",
+			tools.Description("Maximum number of WAL messages or binlog events to read (default: 10000, max: 1000000)"),
+		),
+		tools.WithString("slot",
+			tools.Description("Logical replication slot to read on PostgreSQL"),
+		),
+		tools.WithString("publication",
+			tools.Description("Publication to decode with on a pgoutput slot"),
+		),
+		tools.WithBoolean("consume",
+			tools.Description("Advance the slot past the changes read, so they are not returned again (default: false)"),
+		),
+		tools.WithString("binlog_file",
+			tools.Description("Binary log to read on MySQL (default: the newest)"),
+		),
+	)
+}
+
+// HandleRequest handles change tailing tool requests
+func (t *TailChangesTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema, table := splitQualifiedName(input.optionalString("table", ""))
+	operations := input.stringList("operations")
+	since := input.optionalString("since", "")
+	match := input.object("match")
+	maxEvents := input.intBetween("max_events", 100, 1, 10000)
+	scanLimit := input.intBetween("scan_limit", 10000, 1, 1000000)
+	slot := input.optionalString("slot", "")
+	publication := input.optionalString("publication", "")
+	consume := input.optionalBool("consume", false)
+	binlogFile := input.optionalString("binlog_file", "")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	filter := &changeFilter{Schema: schema, Table: table, Operations: make(map[string]bool), Match: make(map[string]string)}
+	for _, operation := range operations {
+		operation = strings.ToUpper(operation)
+		if operation != "INSERT" && operation != "UPDATE" && operation != "DELETE" && operation != "TRUNCATE" {
+			return nil, fmt.Errorf("operations parameter must list insert, update, delete or truncate, not %s", operation)
+		}
+		filter.Operations[operation] = true
+	}
+	for column, value := range match {
+		filter.Match[column] = fmt.Sprintf("%v", normalizeParamValue(value))
+	}
+	if since != "" {
+		var err error
+		if filter.Since, err = parseChangesSince(since); err != nil {
+			return nil, err
+		}
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for tail_changes: %s", dbType)
+	}
+	readChanges, err := dialect.ChangeReader()
+	if err != nil {
+		return nil, unsupportedAdminError("tail_changes", dbType, err)
+	}
+	source := changeSource{slot: slot, publication: publication, consume: consume, binlogFile: binlogFile, scanLimit: scanLimit}
+	from, changes, scanned, notes, err := readChanges(ctx, useCase, targetDbID, source, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []rowChange
+	for _, change := range changes {
+		if filter.matches(change) {
+			matched = append(matched, change)
+		}
+	}
+	shown := matched
+	if len(shown) > maxEvents {
+		shown = shown[len(shown)-maxEvents:]
+	}
+	logger.Info("Read %d changes from database %s, %d matching", len(changes), targetDbID, len(matched))
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Recent Changes for Database %s\n\n", targetDbID))
+	response.WriteString(fmt.Sprintf("Source: %s\n", from))
+	response.WriteString(fmt.Sprintf("Read: %d messages, %d row changes, %d matching", scanned, len(changes), len(matched)))
+	if len(shown) < len(matched) {
+		response.WriteString(fmt.Sprintf(", showing the last %d", len(shown)))
+	}
+	response.WriteString("\n\n")
+	writeChangeSummary(&response, matched)
+	writeChanges(&response, shown)
+
+	if len(notes) > 0 {
+		response.WriteString("\n## Warnings\n\n")
+		for _, note := range notes {
+			response.WriteString(fmt.Sprintf("- %s\n", note))
+		}
+	}
+	return createTextResponse(response.String()), nil
+}
+
+// parseChangesSince reads a window such as 10m, or an RFC 3339 time, into the earliest time shown
+func parseChangesSince(since string) (time.Time, error) {
+	if window, err := time.ParseDuration(since); err == nil && window > 0 {
+		return changesNow().Add(-window), nil
+	}
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("since parameter must be a duration such as 10m or an RFC 3339 time")
+}
+
+// changeSource names where to read row changes from and how much of it
+type changeSource struct {
+	slot        string
+	publication string
+	consume     bool
+	binlogFile  string
+	scanLimit   int
+}
+
+// changeReader reads the recent row changes of a database, returning a description of what
+// it read, the changes, how many messages it scanned and notes on what it skipped
+type changeReader func(ctx context.Context, useCase UseCaseProvider, dbID string, source changeSource, filter *changeFilter) (string, []rowChange, int, []string, error)
+
+// readPostgresChanges reads the logical replication slot named in source, which PostgreSQL requires
+func readPostgresChanges(ctx context.Context, useCase UseCaseProvider, dbID string, source changeSource, filter *changeFilter) (string, []rowChange, int, []string, error) {
+	if source.slot == "" {
+		return "", nil, 0, nil, fmt.Errorf("slot parameter is required on PostgreSQL; create a logical slot with replication_slots (plugin wal2json, or pgoutput with a publication)")
+	}
+	changes, scanned, notes, err := readSlotChanges(ctx, useCase, dbID, source.slot, source.publication, filter, source.scanLimit, source.consume)
+	return "replication slot " + source.slot, changes, scanned, notes, err
+}
+
+// readMySQLChanges reads the events of a binary log, which carry neither times nor row values
+func readMySQLChanges(ctx context.Context, useCase UseCaseProvider, dbID string, source changeSource, filter *changeFilter) (string, []rowChange, int, []string, error) {
+	if !filter.Since.IsZero() {
+		return "", nil, 0, nil, fmt.Errorf("since is not available on MySQL: SHOW BINLOG EVENTS carries no event times")
+	}
+	if len(filter.Match) > 0 {
+		return "", nil, 0, nil, fmt.Errorf("match is not available on MySQL: SHOW BINLOG EVENTS carries no row values")
+	}
+	file, changes, scanned, notes, err := readBinlogChanges(ctx, useCase, dbID, source.binlogFile, source.scanLimit)
+	return "binary log " + file, changes, scanned, notes, err
+}
+
+// readSlotChanges peeks at, or consumes, the changes a logical replication slot holds
+func readSlotChanges(ctx context.Context, useCase UseCaseProvider, dbID, slot, publication string, filter *changeFilter,
+	scanLimit int, consume bool) ([]rowChange, int, []string, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, "SELECT COALESCE(plugin, ''), slot_type, active FROM pg_replication_slots WHERE slot_name = $1", []interface{}{slot})
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to look up replication slot %s: %w", slot, err)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) < 3 {
+		return nil, 0, nil, fmt.Errorf("replication slot %s does not exist; create a logical slot with replication_slots", slot)
+	}
+	plugin, slotType := valueString(result.Rows[0][0]), valueString(result.Rows[0][1])
+	if slotType != "logical" {
+		return nil, 0, nil, fmt.Errorf("replication slot %s is a %s slot; tail_changes needs a logical slot", slot, slotType)
+	}
+	if valueBool(result.Rows[0][2]) {
+		return nil, 0, nil, fmt.Errorf("replication slot %s is in use by a replication client; read another slot", slot)
+	}
+
+	verb := "peek"
+	if consume {
+		verb = "get"
+	}
+	var notes []string
+	var changes []rowChange
+	switch plugin {
+	case "wal2json":
+		options := []interface{}{slot, scanLimit}
+		query := "SELECT lsn::text, data FROM pg_logical_slot_%s_changes($1, NULL, $2, 'format-version', '2', 'include-timestamp', '1'"
+		if filter.Table != "" {
+			// wal2json skips the other tables itself; an unqualified table matches in any schema
+			schema := filter.Schema
+			if schema == "" {
+				schema = "*"
+			}
+			query += ", 'add-tables', $3"
+			options = append(options, schema+"."+filter.Table)
+		}
+		result, err = useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(query+")", verb), options)
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("failed to read replication slot %s: %w", slot, err)
+		}
+		decoder := &wal2jsonDecoder{}
+		for _, row := range result.Rows {
+			if len(row) < 2 {
+				continue
+			}
+			decoded, err := decoder.decode(valueString(row[0]), valueString(row[1]))
+			if err != nil {
+				return nil, 0, nil, err
+			}
+			changes = append(changes, decoded...)
+		}
+	case "pgoutput":
+		if publication == "" {
+			return nil, 0, nil, fmt.Errorf("publication parameter is required to read pgoutput slot %s", slot)
+		}
+		result, err = useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf(
+			"SELECT lsn::text, data FROM pg_logical_slot_%s_binary_changes($1, NULL, $2, 'proto_version', '1', 'publication_names', $3)", verb),
+			[]interface{}{slot, scanLimit, publication})
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("failed to read replication slot %s: %w", slot, err)
+		}
+		decoder := newPgoutputDecoder()
+		for _, row := range result.Rows {
+			if len(row) < 2 {
+				continue
+			}
+			decoded, err := decoder.decode(valueString(row[0]), byteaValue(row[1]))
+			if err != nil {
+				return nil, 0, nil, err
+			}
+			changes = append(changes, decoded...)
+		}
+	default:
+		return nil, 0, nil, fmt.Errorf("replication slot %s uses the %s plugin; tail_changes reads wal2json and pgoutput slots", slot, plugin)
+	}
+
+	if len(result.Rows) >= scanLimit {
+		notes = append(notes, fmt.Sprintf("The slot holds more than %d messages and only the oldest were read; raise scan_limit, or set consume to move the slot forward.", scanLimit))
+	}
+	if consume {
+		notes = append(notes, fmt.Sprintf("The changes read were consumed; slot %s will not return them again.", slot))
+	} else if len(result.Rows) > 0 {
+		notes = append(notes, fmt.Sprintf("Slot %s still holds these changes and the WAL behind them; set consume once they are no longer needed, or drop the slot when done.", slot))
+	}
+	return changes, len(result.Rows), notes, nil
+}
+
+// readBinlogChanges reads the changes of a binary log, the newest one unless a file is named
+func readBinlogChanges(ctx context.Context, useCase UseCaseProvider, dbID, file string, scanLimit int) (string, []rowChange, int, []string, error) {
+	if file == "" {
+		logs, err := useCase.ExecuteQuery(ctx, dbID, "SHOW BINARY LOGS", nil)
+		if err != nil {
+			return "", nil, 0, nil, fmt.Errorf("failed to list binary logs (is log_bin enabled?): %w", err)
+		}
+		if len(logs.Rows) == 0 || len(logs.Rows[len(logs.Rows)-1]) == 0 {
+			return "", nil, 0, nil, fmt.Errorf("the server keeps no binary logs; enable log_bin to track changes")
+		}
+		file = valueString(logs.Rows[len(logs.Rows)-1][0])
+	}
+
+	mysql, _ := lookupDialect("mysql")
+	decoder := newBinlogDecoder()
+	var changes []rowChange
+	scanned := 0
+	for scanned < scanLimit {
+		page := binlogPageSize
+		if scanLimit-scanned < page {
+			page = scanLimit - scanned
+		}
+		result, err := useCase.ExecuteQuery(ctx, dbID, fmt.Sprintf("SHOW BINLOG EVENTS IN %s LIMIT %d, %d", mysql.QuoteLiteral(file), scanned, page), nil)
+		if err != nil {
+			return "", nil, 0, nil, fmt.Errorf("failed to read binary log %s: %w", file, err)
+		}
+		for _, row := range result.Rows {
+			changes = append(changes, decoder.decode(row)...)
+		}
+		scanned += len(result.Rows)
+		if len(result.Rows) < page {
+			break
+		}
+	}
+
+	var notes []string
+	if scanned >= scanLimit {
+		notes = append(notes, fmt.Sprintf("Only the first %d events of %s were read, so the most recent changes may be missing; raise scan_limit.", scanLimit, file))
+	}
+	for _, change := range changes {
+		if change.Statement == "" {
+			notes = append(notes, "Row-based events show the table and operation only; turn on binlog_rows_query_log_events to see the statements, and use a binlog client such as mysqlbinlog --verbose for row values.")
+			break
+		}
+	}
+	return file, changes, scanned, notes, nil
+}
+
+// writeChangeSummary counts the changes of each table and operation
+func writeChangeSummary(sb *strings.Builder, changes []rowChange) {
+	if len(changes) == 0 {
+		sb.WriteString("No matching changes.\n")
+		return
+	}
+	counts := make(map[string]map[string]int)
+	for _, change := range changes {
+		name := qualifiedName(change.Schema, change.Table)
+		if counts[name] == nil {
+			counts[name] = make(map[string]int)
+		}
+		counts[name][change.Operation]++
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sb.WriteString("| Table | Inserts | Updates | Deletes | Truncates |\n")
+	sb.WriteString("|-------|---------|---------|---------|-----------|\n")
+	for _, name := range names {
+		c := counts[name]
+		sb.WriteString(fmt.Sprintf("| %s | %d | %d | %d | %d |\n", name, c["INSERT"], c["UPDATE"], c["DELETE"], c["TRUNCATE"]))
+	}
+}
+
+// writeChanges lists the changes, oldest first
+func writeChanges(sb *strings.Builder, changes []rowChange) {
+	if len(changes) == 0 {
+		return
+	}
+	sb.WriteString("\n## Changes\n\n")
+	for _, change := range changes {
+		sb.WriteString("- ")
+		if !change.Time.IsZero() {
+			sb.WriteString(change.Time.UTC().Format(time.RFC3339) + " ")
+		}
+		sb.WriteString(fmt.Sprintf("%s %s at %s", change.Operation, qualifiedName(change.Schema, change.Table), change.Position))
+		if len(change.Old) > 0 {
+			sb.WriteString(": old " + joinChangeValues(change.Old))
+		}
+		if len(change.New) > 0 {
+			if len(change.Old) > 0 {
+				sb.WriteString(", new ")
+			} else {
+				sb.WriteString(": ")
+			}
+			sb.WriteString(joinChangeValues(change.New))
+		}
+		if change.Statement != "" {
+			// A double backtick code span keeps the backticks MySQL quotes names with
+			sb.WriteString(fmt.Sprintf(": `` %s ``", change.Statement))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// joinChangeValues renders the values of a row
+func joinChangeValues(values []changeValue) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = value.String()
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
@@ -0,0 +1,176 @@
+package mcp
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+// pgoutputMessage builds a pgoutput message from bytes, strings (null-terminated) and integers
+func pgoutputMessage(parts ...interface{}) []byte {
+	var data []byte
+	for _, part := range parts {
+		switch v := part.(type) {
+		case byte:
+			data = append(data, v)
+		case string:
+			data = append(append(data, v...), 0)
+		case uint16:
+			data = binary.BigEndian.AppendUint16(data, v)
+		case uint32:
+			data = binary.BigEndian.AppendUint32(data, v)
+		case int64:
+			data = binary.BigEndian.AppendUint64(data, uint64(v))
+		case []byte:
+			data = append(data, v...)
+		}
+	}
+	return data
+}
+
+// pgoutputText is a text column value of a tuple
+func pgoutputText(value string) []byte {
+	return append(binary.BigEndian.AppendUint32([]byte{'t'}, uint32(len(value))), value...)
+}
+
+func TestPgoutputDecoder(t *testing.T) {
+	commit := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	decoder := newPgoutputDecoder()
+	messages := [][]byte{
+		pgoutputMessage(byte('B'), int64(100), commit.Sub(postgresEpoch).Microseconds(), uint32(7)),
+		pgoutputMessage(byte('R'), uint32(16384), "public", "orders", byte('d'), uint16(2),
+			byte(1), "id", uint32(23), uint32(0xffffffff),
+			byte(0), "status", uint32(25), uint32(0xffffffff)),
+		pgoutputMessage(byte('I'), uint32(16384), byte('N'), uint16(2), pgoutputText("1"), pgoutputText("new")),
+		pgoutputMessage(byte('U'), uint32(16384), byte('N'), uint16(2), pgoutputText("1"), []byte{'u'}),
+		pgoutputMessage(byte('D'), uint32(16384), byte('K'), uint16(2), pgoutputText("1"), []byte{'n'}),
+		pgoutputMessage(byte('C'), byte(0), int64(100), int64(120), int64(0)),
+	}
+
+	var changes []rowChange
+	for _, message := range messages {
+		decoded, err := decoder.decode("0/100", message)
+		assert.NoError(t, err)
+		changes = append(changes, decoded...)
+	}
+	assert.Len(t, changes, 3)
+	assert.Equal(t, "INSERT", changes[0].Operation)
+	assert.Equal(t, "public", changes[0].Schema)
+	assert.Equal(t, "orders", changes[0].Table)
+	assert.True(t, changes[0].Time.Equal(commit))
+	assert.Equal(t, "(id=1, status='new')", joinChangeValues(changes[0].New))
+	assert.Equal(t, "(id=1, status=(unchanged))", joinChangeValues(changes[1].New))
+	assert.Equal(t, "DELETE", changes[2].Operation)
+	assert.Equal(t, "(id=1, status=NULL)", joinChangeValues(changes[2].Old))
+
+	_, err := decoder.decode("0/200", pgoutputMessage(byte('I'), uint32(99), byte('N'), uint16(0)))
+	assert.ErrorIs(t, err, errInvalidPgoutput)
+	_, err = decoder.decode("0/200", []byte{'I', 0, 0})
+	assert.ErrorIs(t, err, errInvalidPgoutput)
+}
+
+func TestBinlogDecoder(t *testing.T) {
+	decoder := newBinlogDecoder()
+	events := [][]interface{}{
+		{"binlog.000002", int64(4), "Format_desc", int64(1), int64(126), "Server ver: 8.0.36"},
+		{"binlog.000002", int64(200), "Query", int64(1), int64(280), "BEGIN"},
+		{"binlog.000002", int64(280), "Rows_query", int64(1), int64(340), "# UPDATE orders SET status = 'paid' WHERE id = 1"},
+		{"binlog.000002", int64(340), "Table_map", int64(1), int64(400), "table_id: 108 (shop.orders)"},
+		{"binlog.000002", int64(400), "Update_rows", int64(1), int64(480), "table_id: 108 flags: STMT_END_F"},
+		{"binlog.000002", int64(480), "Xid", int64(1), int64(511), "COMMIT /* xid=42 */"},
+		{"binlog.000002", int64(511), "Query", int64(1), int64(600), "use `shop`; DELETE FROM `customers` WHERE id = 3"},
+	}
+
+	var changes []rowChange
+	for _, event := range events {
+		changes = append(changes, decoder.decode(event)...)
+	}
+	assert.Equal(t, []rowChange{
+		{Position: "binlog.000002:400", Operation: "UPDATE", Schema: "shop", Table: "orders", Statement: "UPDATE orders SET status = 'paid' WHERE id = 1"},
+		{Position: "binlog.000002:511", Operation: "DELETE", Schema: "shop", Table: "customers", Statement: "DELETE FROM `customers` WHERE id = 3"},
+	}, changes)
+}
+
+func TestTailChangesWal2JSON(t *testing.T) {
+	defer func(now func() time.Time) { changesNow = now }(changesNow)
+	changesNow = func() time.Time { return time.Date(2024, 5, 1, 10, 5, 0, 0, time.UTC) }
+
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"pg_replication_slots": {Rows: [][]interface{}{{"wal2json", "logical", false}}},
+			"pg_logical_slot_peek_changes": {Rows: [][]interface{}{
+				{"0/10", `{"action":"B","timestamp":"2024-05-01 09:50:00.000000+00"}`},
+				{"0/11", `{"action":"I","schema":"public","table":"orders","columns":[{"name":"id","type":"integer","value":1}]}`},
+				{"0/12", `{"action":"C","timestamp":"2024-05-01 09:50:00.000000+00"}`},
+				{"0/20", `{"action":"B","timestamp":"2024-05-01 10:01:00.000000+00"}`},
+				{"0/21", `{"action":"U","schema":"public","table":"orders","columns":[{"name":"id","type":"integer","value":1},{"name":"status","type":"text","value":"paid"}],"identity":[{"name":"id","type":"integer","value":1}]}`},
+				{"0/22", `{"action":"I","schema":"public","table":"orders","columns":[{"name":"id","type":"integer","value":2}]}`},
+				{"0/23", `{"action":"C","timestamp":"2024-05-01 10:01:00.000000+00"}`},
+			}},
+		},
+	}
+	request := server.ToolCallRequest{Parameters: map[string]interface{}{
+		"database": "db1",
+		"slot":     "audit",
+		"table":    "orders",
+		"since":    "10m",
+		"match":    map[string]interface{}{"id": float64(1)},
+	}}
+
+	result, err := NewTailChangesTool().HandleRequest(context.Background(), request, "", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+
+	assert.Contains(t, useCase.queries, "SELECT lsn::text, data FROM pg_logical_slot_peek_changes($1, NULL, $2, 'format-version', '2', 'include-timestamp', '1', 'add-tables', $3)")
+	assert.Contains(t, text, "Read: 7 messages, 3 row changes, 1 matching")
+	assert.Contains(t, text, "| public.orders | 0 | 1 | 0 | 0 |")
+	assert.Contains(t, text, "- 2024-05-01T10:01:00Z UPDATE public.orders at 0/21: old (id=1), new (id=1, status='paid')")
+	assert.Contains(t, text, "Slot audit still holds these changes")
+}
+
+func TestTailChangesRejectsInvalidInput(t *testing.T) {
+	call := func(dbType string, params map[string]interface{}) error {
+		useCase := &mockUseCase{dbType: dbType, results: map[string]*domain.QueryResult{
+			"pg_replication_slots": {Rows: [][]interface{}{{"test_decoding", "logical", false}}},
+		}}
+		params["database"] = "db1"
+		_, err := NewTailChangesTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+		return err
+	}
+
+	assert.ErrorContains(t, call("postgres", map[string]interface{}{}), "slot parameter is required")
+	assert.ErrorContains(t, call("postgres", map[string]interface{}{"slot": "s"}), "uses the test_decoding plugin")
+	assert.ErrorContains(t, call("postgres", map[string]interface{}{"slot": "s", "since": "yesterday"}), "since parameter must be")
+	assert.ErrorContains(t, call("postgres", map[string]interface{}{"slot": "s", "operations": []interface{}{"merge"}}), "operations parameter")
+	assert.ErrorContains(t, call("mysql", map[string]interface{}{"since": "10m"}), "since is not available on MySQL")
+}
+
+func TestTailChangesMySQL(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"SHOW BINARY LOGS": {Rows: [][]interface{}{{"binlog.000001", int64(100), "No"}, {"binlog.000002", int64(600), "No"}}},
+			"SHOW BINLOG EVENTS": {Rows: [][]interface{}{
+				{"binlog.000002", int64(340), "Table_map", int64(1), int64(400), "table_id: 108 (shop.orders)"},
+				{"binlog.000002", int64(400), "Write_rows", int64(1), int64(480), "table_id: 108 flags: STMT_END_F"},
+			}},
+		},
+	}
+	request := server.ToolCallRequest{Parameters: map[string]interface{}{"database": "db1", "operations": []interface{}{"insert"}}}
+
+	result, err := NewTailChangesTool().HandleRequest(context.Background(), request, "", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+
+	assert.Contains(t, useCase.queries, "SHOW BINLOG EVENTS IN 'binlog.000002' LIMIT 0, 1000")
+	assert.Contains(t, text, "Source: binary log binlog.000002")
+	assert.Contains(t, text, "- INSERT shop.orders at binlog.000002:400")
+	assert.Contains(t, text, "binlog_rows_query_log_events")
+}
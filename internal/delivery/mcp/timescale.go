@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+)
+
+// timescalePolicies names the background jobs TimescaleDB runs for its policies
+var timescalePolicies = map[string]string{
+	"policy_retention":                    "retention",
+	"policy_compression":                  "compression",
+	"policy_reorder":                      "reorder",
+	"policy_refresh_continuous_aggregate": "continuous aggregate refresh",
+}
+
+// hypertableQueries are the queries reading TimescaleDB hypertables, optionally of one table
+type hypertableQueries struct {
+	// version returns the version of the timescaledb extension, or no row when it is not installed
+	version     string
+	hypertables string
+	jobs        string
+}
+
+// getPostgresHypertableQueries returns the queries of the timescaledb_information views
+func getPostgresHypertableQueries(tableName string) hypertableQueries {
+	return hypertableQueries{
+		version:     "SELECT extversion FROM pg_extension WHERE extname = 'timescaledb'",
+		hypertables: hypertablesQuery(tableName),
+		jobs:        hypertableJobsQuery(tableName),
+	}
+}
+
+// timescaleVersion returns the version of the timescaledb extension, or "" when it is not installed
+func timescaleVersion(ctx context.Context, useCase UseCaseProvider, dbID string, queries hypertableQueries) (string, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, queries.version, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to check for the timescaledb extension: %w", err)
+	}
+	if len(result.Rows) == 0 || len(result.Rows[0]) == 0 {
+		return "", nil
+	}
+	return valueString(result.Rows[0][0]), nil
+}
+
+// hypertableFilter restricts the rows of a timescaledb_information view to one table, given as
+// name or schema.name; alias is the alias of the view in the query
+func hypertableFilter(alias, tableName string) string {
+	if tableName == "" {
+		return ""
+	}
+	schema, table := splitQualifiedName(tableName)
+	filter := fmt.Sprintf("%s.hypertable_name = %s", alias, quoteLiteral("postgres", table))
+	if schema != "" {
+		filter = fmt.Sprintf("%s.hypertable_schema = %s AND %s", alias, quoteLiteral("postgres", schema), filter)
+	}
+	return filter
+}
+
+// hypertablesQuery lists hypertables with their time dimension, chunk counts, compression and
+// the thresholds of their retention and compression policies
+func hypertablesQuery(tableName string) string {
+	where := ""
+	if filter := hypertableFilter("h", tableName); filter != "" {
+		where = "\nWHERE " + filter
+	}
+	return `SELECT
+    h.hypertable_schema || '.' || h.hypertable_name AS hypertable,
+    d.column_name AS time_column,
+    COALESCE(d.time_interval::text, d.integer_interval::text) AS chunk_interval,
+    h.num_chunks AS chunks,
+    COALESCE(s.number_compressed_chunks, 0) AS compressed_chunks,
+    CASE WHEN h.compression_enabled THEN 'enabled' ELSE 'disabled' END AS compression,
+    pg_size_pretty(hypertable_size(format('%I.%I', h.hypertable_schema, h.hypertable_name)::regclass)) AS total_size,
+    pg_size_pretty(s.before_compression_total_bytes) AS before_compression,
+    pg_size_pretty(s.after_compression_total_bytes) AS after_compression,
+    (SELECT COALESCE(j.config->>'drop_after', 'created before ' || (j.config->>'drop_created_before'))
+     FROM timescaledb_information.jobs j
+     WHERE j.hypertable_schema = h.hypertable_schema AND j.hypertable_name = h.hypertable_name
+       AND j.proc_name = 'policy_retention'
+     LIMIT 1) AS retention_policy,
+    (SELECT COALESCE(j.config->>'compress_after', 'created before ' || (j.config->>'compress_created_before'))
+     FROM timescaledb_information.jobs j
+     WHERE j.hypertable_schema = h.hypertable_schema AND j.hypertable_name = h.hypertable_name
+       AND j.proc_name = 'policy_compression'
+     LIMIT 1) AS compression_policy
+FROM timescaledb_information.hypertables h
+LEFT JOIN timescaledb_information.dimensions d
+    ON d.hypertable_schema = h.hypertable_schema AND d.hypertable_name = h.hypertable_name AND d.dimension_number = 1
+LEFT JOIN LATERAL hypertable_compression_stats(format('%I.%I', h.hypertable_schema, h.hypertable_name)::regclass) s
+    ON h.compression_enabled` + where + `
+ORDER BY 1`
+}
+
+// hypertableJobsQuery lists the policy jobs of hypertables with the outcome of their last run
+func hypertableJobsQuery(tableName string) string {
+	where := "j.hypertable_name IS NOT NULL"
+	if filter := hypertableFilter("j", tableName); filter != "" {
+		where += " AND " + filter
+	}
+	return `SELECT
+    j.hypertable_schema || '.' || j.hypertable_name,
+    j.job_id,
+    j.proc_name,
+    j.schedule_interval::text,
+    j.config::text,
+    j.scheduled,
+    s.last_run_status,
+    s.last_successful_finish,
+    s.next_start,
+    s.total_failures
+FROM timescaledb_information.jobs j
+LEFT JOIN timescaledb_information.job_stats s ON s.job_id = j.job_id
+WHERE ` + where + `
+ORDER BY 1, 2`
+}
+
+// hypertableStatsSection is the table_stats section of a hypertable
+func hypertableStatsSection(queries hypertableQueries) tableStatsSection {
+	return tableStatsSection{name: "hypertable", query: queries.hypertables}
+}
+
+// policyName describes the procedure of a TimescaleDB job
+func policyName(proc string) string {
+	if name, ok := timescalePolicies[proc]; ok {
+		return name
+	}
+	return proc
+}
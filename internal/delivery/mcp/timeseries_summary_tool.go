@@ -0,0 +1,427 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// TimeseriesSummaryTool handles bucketed time-series analysis of a table
+type TimeseriesSummaryTool struct {
+	BaseToolType
+}
+
+// NewTimeseriesSummaryTool creates a new time-series summary tool type
+func NewTimeseriesSummaryTool() *TimeseriesSummaryTool {
+	return &TimeseriesSummaryTool{
+		BaseToolType: BaseToolType{
+			name:        "timeseries_summary",
+			description: "Summarize how the rows of a table are distributed over time. Given a table and a timestamp column, this tool buckets the rows by hour, day or week and returns the row count per bucket, optionally with an aggregate (sum, avg, min or max) of a metric column. It also detects gaps (buckets with no rows) and sudden spikes or drops, using a robust deviation from the median, and returns everything as a compact table.",
+		},
+	}
+}
+
+// CreateTool creates a time-series summary tool
+func (t *TimeseriesSummaryTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Bucket a table by time with counts, aggregates, gaps and spikes"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Table to analyze (optionally schema-qualified)"),
+			tools.Required(),
+		),
+		tools.WithString("timestamp_column",
+			tools.Description("Timestamp or date column to bucket by"),
+			tools.Required(),
+		),
+		tools.WithString("metric_column",
+			tools.Description("Numeric column to aggregate per bucket (optional)"),
+		),
+		tools.WithString("aggregate",
+			tools.Description("Aggregate applied to the metric: sum, avg, min or max (default: sum)"),
+		),
+		tools.WithString("bucket",
+			tools.Description("Bucket size: hour, day or week (default: day)"),
+		),
+		tools.WithString("start",
+			tools.Description("Only include rows at or after this timestamp (optional)"),
+		),
+		tools.WithString("end",
+			tools.Description("Only include rows before this timestamp (optional)"),
+		),
+		tools.WithArray("filters",
+			tools.Description("Additional conditions combined with AND: objects with column, operator, value or values"),
+			tools.Items(map[string]interface{}{"type": "object"}),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of most recent buckets to return (default: 200)"),
+		),
+		tools.WithNumber("spike_threshold",
+			tools.Description("Robust z-score above which a bucket is reported as a spike or drop (default: 3.5)"),
+		),
+	)
+}
+
+// HandleRequest handles time-series summary tool requests
+func (t *TimeseriesSummaryTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	spec, err := parseTimeseriesSpec(request.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	query, params, err := spec.query(strings.ToLower(dbType))
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Summarizing time series of %s.%s in database %s", spec.table, spec.timestampColumn, targetDbID)
+
+	result, err := useCase.ExecuteQuery(ctx, targetDbID, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize time series: %w", err)
+	}
+
+	var points []timeseriesPoint
+	for _, row := range result.Rows {
+		bucket, ok := valueTime(row[0])
+		if !ok {
+			continue
+		}
+		point := timeseriesPoint{Bucket: bucket, Count: valueInt64(row[1])}
+		point.Value = float64(point.Count)
+		if spec.metricColumn != "" {
+			point.Value = valueFloat64(row[2])
+		}
+		points = append(points, point)
+	}
+	// Buckets are fetched newest first so the limit keeps the most recent ones
+	sort.Slice(points, func(i, j int) bool { return points[i].Bucket.Before(points[j].Bucket) })
+
+	// Format the response
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Time Series Summary for %s.%s in Database %s\n\n", spec.table, spec.timestampColumn, targetDbID))
+	response.WriteString(formatTimeseriesSummary(spec, points))
+
+	return createTextResponse(response.String()), nil
+}
+
+// timeseriesSpec describes a bucketed time-series query
+type timeseriesSpec struct {
+	table           string
+	timestampColumn string
+	metricColumn    string
+	aggregate       string
+	bucket          string
+	start           string
+	end             string
+	filters         []queryFilter
+	limit           int
+	spikeThreshold  float64
+}
+
+// timeseriesPoint is the aggregated value of one bucket
+type timeseriesPoint struct {
+	Bucket time.Time
+	Count  int64
+	Value  float64
+}
+
+// parseTimeseriesSpec validates tool parameters and converts them into a time-series spec
+func parseTimeseriesSpec(parameters map[string]interface{}) (*timeseriesSpec, error) {
+	input := newToolParams(parameters)
+	spec := &timeseriesSpec{
+		table:           input.requiredString("table"),
+		timestampColumn: input.requiredString("timestamp_column"),
+		metricColumn:    input.optionalString("metric_column", ""),
+		start:           input.optionalString("start", ""),
+		end:             input.optionalString("end", ""),
+		aggregate:       input.choice("aggregate", "sum", "sum", "avg", "min", "max"),
+		bucket:          input.choice("bucket", "day", "hour", "day", "week"),
+		limit:           input.intAtLeast("limit", 200, 1),
+		spikeThreshold:  input.optionalFloat("spike_threshold", 3.5),
+	}
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if spec.spikeThreshold <= 0 {
+		return nil, fmt.Errorf("spike_threshold parameter must be greater than 0")
+	}
+
+	var err error
+	if spec.filters, err = parseQueryFilters(input.raw("filters")); err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+// query renders the bucketed aggregation query for the database type
+func (s *timeseriesSpec) query(dbType string) (string, []interface{}, error) {
+	params := newSQLParams(dbType)
+	ts := quoteIdentifier(dbType, s.timestampColumn)
+
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported database type for time series: %s", dbType)
+	}
+	bucket, ok := dialect.TimeBucket(ts, s.bucket)
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported database type for time series: %s", dbType)
+	}
+
+	selectList := []string{bucket + " AS bucket", "COUNT(*) AS row_count"}
+	if s.metricColumn != "" {
+		selectList = append(selectList, fmt.Sprintf("%s(%s) AS metric", strings.ToUpper(s.aggregate), quoteIdentifier(dbType, s.metricColumn)))
+	}
+
+	conditions := []string{ts + " IS NOT NULL"}
+	if s.start != "" {
+		conditions = append(conditions, ts+" >= "+params.add(s.start))
+	}
+	if s.end != "" {
+		conditions = append(conditions, ts+" < "+params.add(s.end))
+	}
+	if where := buildWhereClause(s.filters, params); where != "" {
+		conditions = append(conditions, where)
+	}
+
+	query := "SELECT " + strings.Join(selectList, ", ") +
+		"\nFROM " + quoteIdentifier(dbType, s.table) +
+		"\nWHERE " + strings.Join(conditions, " AND ") +
+		"\nGROUP BY 1\nORDER BY 1 DESC\n" +
+		dialect.LimitClause(s.limit)
+	return query, params.values, nil
+}
+
+// next returns the start of the bucket following t
+func (s *timeseriesSpec) next(t time.Time) time.Time {
+	switch s.bucket {
+	case "hour":
+		return t.Add(time.Hour)
+	case "week":
+		return t.AddDate(0, 0, 7)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// formatBucket renders a bucket start for display
+func (s *timeseriesSpec) formatBucket(t time.Time) string {
+	if s.bucket == "hour" {
+		return t.Format("2006-01-02 15:00")
+	}
+	return t.Format("2006-01-02")
+}
+
+// timeseriesGap is a run of consecutive empty buckets
+type timeseriesGap struct {
+	From    time.Time
+	To      time.Time
+	Buckets int
+}
+
+// postgresTimeBucket truncates a timestamp to the start of its hour, day or week
+func postgresTimeBucket(column, bucket string) string {
+	return "date_trunc('" + bucket + "', " + column + ")"
+}
+
+// mysqlTimeBucket truncates a timestamp to the start of its hour, day or week
+func mysqlTimeBucket(column, bucket string) string {
+	switch bucket {
+	case "hour":
+		return "DATE_FORMAT(" + column + ", '%Y-%m-%d %H:00:00')"
+	case "week":
+		// Weeks start on Monday, like date_trunc('week', ...) on PostgreSQL
+		return "DATE_SUB(DATE(" + column + "), INTERVAL WEEKDAY(" + column + ") DAY)"
+	default:
+		return "DATE(" + column + ")"
+	}
+}
+
+// findTimeseriesGaps returns the runs of missing buckets between the first and last point
+func findTimeseriesGaps(spec *timeseriesSpec, points []timeseriesPoint) []timeseriesGap {
+	var gaps []timeseriesGap
+	for i := 1; i < len(points); i++ {
+		expected := spec.next(points[i-1].Bucket)
+		if !expected.Before(points[i].Bucket) {
+			continue
+		}
+		gap := timeseriesGap{From: expected}
+		for b := expected; b.Before(points[i].Bucket); b = spec.next(b) {
+			gap.To = b
+			gap.Buckets++
+		}
+		gaps = append(gaps, gap)
+	}
+	return gaps
+}
+
+// findTimeseriesSpikes returns, per point index, +1 for a spike and -1 for a drop, using the
+// robust z-score (deviation from the median scaled by the median absolute deviation)
+func findTimeseriesSpikes(points []timeseriesPoint, threshold float64) map[int]int {
+	if len(points) < 3 {
+		return nil
+	}
+	values := make([]float64, len(points))
+	for i, point := range points {
+		values[i] = point.Value
+	}
+	med := median(values)
+	deviations := make([]float64, len(values))
+	meanDeviation := 0.0
+	for i, value := range values {
+		deviations[i] = math.Abs(value - med)
+		meanDeviation += deviations[i]
+	}
+	meanDeviation /= float64(len(values))
+
+	scale := 1.4826 * median(deviations)
+	if scale == 0 {
+		// More than half of the buckets share the median; fall back to the mean deviation
+		scale = 1.253314 * meanDeviation
+	}
+	if scale == 0 {
+		return nil
+	}
+
+	spikes := make(map[int]int)
+	for i, value := range values {
+		z := (value - med) / scale
+		if z > threshold {
+			spikes[i] = 1
+		} else if z < -threshold {
+			spikes[i] = -1
+		}
+	}
+	return spikes
+}
+
+// median returns the median of the values without modifying them
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// formatTimeseriesSummary renders the buckets, gaps and spikes as markdown
+func formatTimeseriesSummary(spec *timeseriesSpec, points []timeseriesPoint) string {
+	var sb strings.Builder
+	if len(points) == 0 {
+		sb.WriteString("No rows found.\n")
+		return sb.String()
+	}
+
+	metric := "Count"
+	if spec.metricColumn != "" {
+		metric = fmt.Sprintf("%s(%s)", strings.ToUpper(spec.aggregate), spec.metricColumn)
+	}
+	gaps := findTimeseriesGaps(spec, points)
+	spikes := findTimeseriesSpikes(points, spec.spikeThreshold)
+
+	var total int64
+	for _, point := range points {
+		total += point.Count
+	}
+	sb.WriteString(fmt.Sprintf("Bucket: %s, buckets: %d, rows: %d\n", spec.bucket, len(points), total))
+	sb.WriteString(fmt.Sprintf("Range: %s to %s\n", spec.formatBucket(points[0].Bucket), spec.formatBucket(points[len(points)-1].Bucket)))
+	sb.WriteString(fmt.Sprintf("Metric: %s\n", metric))
+	if len(points) == spec.limit {
+		sb.WriteString(fmt.Sprintf("Only the %d most recent buckets are shown.\n", spec.limit))
+	}
+
+	sb.WriteString("\n| Bucket | Rows |")
+	if spec.metricColumn != "" {
+		sb.WriteString(" " + metric + " |")
+	}
+	sb.WriteString(" Flag |\n|--------|------|")
+	if spec.metricColumn != "" {
+		sb.WriteString("------|")
+	}
+	sb.WriteString("------|\n")
+	for i, point := range points {
+		sb.WriteString(fmt.Sprintf("| %s | %d |", spec.formatBucket(point.Bucket), point.Count))
+		if spec.metricColumn != "" {
+			sb.WriteString(fmt.Sprintf(" %s |", formatMetric(point.Value)))
+		}
+		flag := ""
+		switch spikes[i] {
+		case 1:
+			flag = "spike"
+		case -1:
+			flag = "drop"
+		}
+		sb.WriteString(fmt.Sprintf(" %s |\n", flag))
+	}
+
+	if len(gaps) > 0 {
+		missing := 0
+		for _, gap := range gaps {
+			missing += gap.Buckets
+		}
+		sb.WriteString(fmt.Sprintf("\nGaps: %d empty buckets in %d runs\n", missing, len(gaps)))
+		for _, gap := range gaps {
+			if gap.Buckets == 1 {
+				sb.WriteString(fmt.Sprintf("- %s\n", spec.formatBucket(gap.From)))
+			} else {
+				sb.WriteString(fmt.Sprintf("- %s to %s (%d buckets)\n", spec.formatBucket(gap.From), spec.formatBucket(gap.To), gap.Buckets))
+			}
+		}
+	} else {
+		sb.WriteString("\nGaps: none\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Spikes/drops: %d (threshold %.1f)\n", len(spikes), spec.spikeThreshold))
+	return sb.String()
+}
+
+// formatMetric renders a metric value without needless decimals
+func formatMetric(value float64) string {
+	if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.2f", value)
+}
+
+// valueTime converts a scanned date or timestamp value to a time.Time
+func valueTime(v interface{}) (time.Time, bool) {
+	switch val := v.(type) {
+	case time.Time:
+		return val, true
+	case []byte, string:
+		s := valueString(val)
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07", "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"} {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
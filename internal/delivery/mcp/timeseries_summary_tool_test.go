@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestTimeseriesQuery(t *testing.T) {
+	spec, err := parseTimeseriesSpec(map[string]interface{}{
+		"table":            "orders",
+		"timestamp_column": "created_at",
+		"metric_column":    "total",
+		"aggregate":        "AVG",
+		"bucket":           "week",
+		"start":            "2024-01-01",
+		"filters":          []interface{}{map[string]interface{}{"column": "status", "value": "paid"}},
+	})
+	assert.NoError(t, err)
+
+	query, params, err := spec.query("postgres")
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT date_trunc('week', "created_at") AS bucket, COUNT(*) AS row_count, AVG("total") AS metric
+FROM "orders"
+WHERE "created_at" IS NOT NULL AND "created_at" >= $1 AND "status" = $2
+GROUP BY 1
+ORDER BY 1 DESC
+LIMIT 200`, query)
+	assert.Equal(t, []interface{}{"2024-01-01", "paid"}, params)
+
+	query, _, err = spec.query("mysql")
+	assert.NoError(t, err)
+	assert.Contains(t, query, "DATE_SUB(DATE(`created_at`), INTERVAL WEEKDAY(`created_at`) DAY) AS bucket")
+}
+
+func TestParseTimeseriesSpecRejectsInvalidInput(t *testing.T) {
+	base := func() map[string]interface{} {
+		return map[string]interface{}{"table": "orders", "timestamp_column": "created_at"}
+	}
+
+	params := base()
+	params["bucket"] = "minute"
+	_, err := parseTimeseriesSpec(params)
+	assert.Error(t, err)
+
+	params = base()
+	params["aggregate"] = "median"
+	_, err = parseTimeseriesSpec(params)
+	assert.Error(t, err)
+
+	_, err = parseTimeseriesSpec(map[string]interface{}{"table": "orders"})
+	assert.Error(t, err)
+}
+
+func day(d int) time.Time {
+	return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestFindTimeseriesGaps(t *testing.T) {
+	spec := &timeseriesSpec{bucket: "day"}
+	points := []timeseriesPoint{{Bucket: day(1)}, {Bucket: day(2)}, {Bucket: day(4)}, {Bucket: day(8)}}
+
+	gaps := findTimeseriesGaps(spec, points)
+	assert.Equal(t, []timeseriesGap{
+		{From: day(3), To: day(3), Buckets: 1},
+		{From: day(5), To: day(7), Buckets: 3},
+	}, gaps)
+}
+
+func TestFindTimeseriesSpikes(t *testing.T) {
+	values := []float64{100, 98, 103, 101, 99, 950, 102, 4, 100}
+	points := make([]timeseriesPoint, len(values))
+	for i, value := range values {
+		points[i] = timeseriesPoint{Bucket: day(i + 1), Value: value}
+	}
+
+	assert.Equal(t, map[int]int{5: 1, 7: -1}, findTimeseriesSpikes(points, 3.5))
+
+	// A flat series has no spikes
+	flat := []timeseriesPoint{{Value: 5}, {Value: 5}, {Value: 5}}
+	assert.Empty(t, findTimeseriesSpikes(flat, 3.5))
+}
+
+func TestTimeseriesSummaryTool(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{"AS bucket": {
+			Columns: []string{"bucket", "row_count"},
+			Rows: [][]interface{}{
+				{[]byte("2024-01-03"), int64(7)},
+				{[]byte("2024-01-01"), int64(5)},
+			},
+		}},
+	}
+
+	result, err := NewTimeseriesSummaryTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "mysql1", "table": "orders", "timestamp_column": "created_at"},
+	}, "mysql1", useCase)
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "| 2024-01-01 | 5 |")
+	assert.Contains(t, text, "Gaps: 1 empty buckets in 1 runs\n- 2024-01-02")
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// defaultMaxConcurrentTools bounds the tool calls running at once on the server when no
+// limit is configured
+const defaultMaxConcurrentTools = 32
+
+// defaultMaxConcurrentToolsPerDatabase bounds the tool calls running at once on one database
+// when its connection sets no limit
+const defaultMaxConcurrentToolsPerDatabase = 8
+
+// queueReportThreshold is how long a tool call must wait for a worker before its response
+// reports the time it spent queued
+const queueReportThreshold = 50 * time.Millisecond
+
+// toolPool bounds how many tool calls run at once, server-wide and per database. Calls over
+// either limit queue until a worker frees up or their request is cancelled.
+type toolPool struct {
+	// total holds a token per running call; nil means no server-wide limit
+	total chan struct{}
+
+	mu        sync.Mutex
+	databases map[string]chan struct{}
+}
+
+// newToolPool creates a pool running at most total calls at once; zero selects the default
+// and a negative value removes the server-wide limit
+func newToolPool(total int) *toolPool {
+	if total == 0 {
+		total = defaultMaxConcurrentTools
+	}
+	p := &toolPool{databases: make(map[string]chan struct{})}
+	if total > 0 {
+		p.total = make(chan struct{}, total)
+	}
+	return p
+}
+
+// slotsOf returns the worker tokens of a database, sized by its connection's
+// max_concurrent_tools the first time the database is used; nil means no limit. The database
+// parameter comes from the caller, so names without a configured connection get no tokens,
+// which keeps the map from growing with them; their calls fail once they run.
+func (p *toolPool) slotsOf(useCase UseCaseProvider, dbID string) chan struct{} {
+	if dbID == "" {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	slots, ok := p.databases[dbID]
+	if ok {
+		return slots
+	}
+	config, err := useCase.GetDatabaseConfig(dbID)
+	if err != nil || config == nil {
+		return nil
+	}
+	limit := defaultMaxConcurrentToolsPerDatabase
+	if config.MaxConcurrentTools != 0 {
+		limit = config.MaxConcurrentTools
+	}
+	if limit > 0 {
+		slots = make(chan struct{}, limit)
+	}
+	p.databases[dbID] = slots
+	return slots
+}
+
+// acquire waits for a worker on the database and then on the server. It returns the function
+// releasing them, and a note on the time spent queued when that was noticeable.
+func (p *toolPool) acquire(ctx context.Context, useCase UseCaseProvider, dbID string) (func(), string, error) {
+	started := time.Now()
+	// The database comes first, so calls queued for a busy database do not hold server-wide
+	// workers that calls on other databases could use
+	dbSlots := p.slotsOf(useCase, dbID)
+	if err := takeSlot(ctx, dbSlots); err != nil {
+		return nil, "", fmt.Errorf("tool call cancelled after %s in the queue for database %s: %w", roundDuration(time.Since(started)), dbID, err)
+	}
+	if err := takeSlot(ctx, p.total); err != nil {
+		giveSlot(dbSlots)
+		return nil, "", fmt.Errorf("tool call cancelled after %s in the queue for a worker: %w", roundDuration(time.Since(started)), err)
+	}
+	release := func() {
+		giveSlot(p.total)
+		giveSlot(dbSlots)
+	}
+
+	waited := time.Since(started)
+	if waited < queueReportThreshold {
+		return release, "", nil
+	}
+	var limits []string
+	if p.total != nil {
+		limits = append(limits, fmt.Sprintf("at most %d tool calls run at once", cap(p.total)))
+	}
+	if dbSlots != nil {
+		limits = append(limits, fmt.Sprintf("at most %d on database %s", cap(dbSlots), dbID))
+	}
+	note := fmt.Sprintf("Queued for %s waiting for a free worker (%s).", roundDuration(waited), strings.Join(limits, ", "))
+	logger.Info("Tool call on database %s waited %s for a worker", dbID, roundDuration(waited))
+	return release, note, nil
+}
+
+// takeSlot waits for a token of a limit; a nil limit never blocks
+func takeSlot(ctx context.Context, slots chan struct{}) error {
+	if slots == nil {
+		return nil
+	}
+	select {
+	case slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// giveSlot returns a token taken from a limit
+func giveSlot(slots chan struct{}) {
+	if slots != nil {
+		<-slots
+	}
+}
+
+// toolDatabase returns the database a tool call works on: the one the tool was registered
+// for, or the database parameter of tools shared by all databases
+func toolDatabase(request server.ToolCallRequest, dbID string) string {
+	if dbID != "" {
+		return dbID
+	}
+	if database, ok := request.Parameters["database"].(string); ok {
+		return database
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestToolPoolQueuesPastTheServerLimit(t *testing.T) {
+	pool := newToolPool(1)
+	useCase := &mockUseCase{}
+
+	release, note, err := pool.acquire(context.Background(), useCase, "db1")
+	assert.NoError(t, err)
+	assert.Empty(t, note)
+
+	go func() {
+		time.Sleep(2 * queueReportThreshold)
+		release()
+	}()
+	// A call on another database still waits for the only server-wide worker
+	release2, note, err := pool.acquire(context.Background(), useCase, "db2")
+	assert.NoError(t, err)
+	assert.Contains(t, note, "waiting for a free worker")
+	assert.Contains(t, note, "at most 1 tool calls run at once")
+	release2()
+}
+
+func TestToolPoolLimitsEachDatabase(t *testing.T) {
+	pool := newToolPool(-1)
+	useCase := &mockUseCase{config: domain.DatabaseConnectionConfig{MaxConcurrentTools: 1}}
+
+	release, _, err := pool.acquire(context.Background(), useCase, "db1")
+	assert.NoError(t, err)
+
+	// Other databases have their own workers
+	other, note, err := pool.acquire(context.Background(), useCase, "db2")
+	assert.NoError(t, err)
+	assert.Empty(t, note)
+	other()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, _, err = pool.acquire(ctx, useCase, "db1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "in the queue for database db1")
+
+	release()
+	release, _, err = pool.acquire(context.Background(), useCase, "db1")
+	assert.NoError(t, err)
+	release()
+}
+
+// configuredUseCase only has the configuration of the listed databases
+type configuredUseCase struct {
+	*mockUseCase
+	databases []string
+}
+
+func (u *configuredUseCase) GetDatabaseConfig(dbID string) (*domain.DatabaseConnectionConfig, error) {
+	for _, id := range u.databases {
+		if id == dbID {
+			return u.mockUseCase.GetDatabaseConfig(dbID)
+		}
+	}
+	return nil, fmt.Errorf("database configuration not found for ID: %s", dbID)
+}
+
+func TestToolPoolIgnoresUnknownDatabases(t *testing.T) {
+	pool := newToolPool(-1)
+	useCase := &configuredUseCase{mockUseCase: &mockUseCase{}, databases: []string{"db1"}}
+
+	for i := 0; i < 100; i++ {
+		release, _, err := pool.acquire(context.Background(), useCase, fmt.Sprintf("missing%d", i))
+		assert.NoError(t, err)
+		release()
+	}
+	release, _, err := pool.acquire(context.Background(), useCase, "db1")
+	assert.NoError(t, err)
+	release()
+	assert.Len(t, pool.databases, 1)
+}
+
+func TestToolPoolWithoutLimits(t *testing.T) {
+	pool := newToolPool(-1)
+	useCase := &mockUseCase{config: domain.DatabaseConnectionConfig{MaxConcurrentTools: -1}}
+
+	for i := 0; i < 100; i++ {
+		_, note, err := pool.acquire(context.Background(), useCase, "db1")
+		assert.NoError(t, err)
+		assert.Empty(t, note)
+	}
+}
+
+func TestToolDatabase(t *testing.T) {
+	request := server.ToolCallRequest{Parameters: map[string]interface{}{"database": "db2"}}
+	assert.Equal(t, "db1", toolDatabase(request, "db1"))
+	assert.Equal(t, "db2", toolDatabase(request, ""))
+	assert.Equal(t, "", toolDatabase(server.ToolCallRequest{Parameters: map[string]interface{}{}}, ""))
+}
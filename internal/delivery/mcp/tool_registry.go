@@ -21,6 +21,7 @@ type ToolRegistry struct {
 	mcpServer       *server.MCPServer
 	databaseUseCase UseCaseProvider
 	factory         *ToolTypeFactory
+	workers         *toolPool
 }
 
 // NewToolRegistry creates a new tool registry
@@ -30,9 +31,17 @@ func NewToolRegistry(mcpServer *server.MCPServer) *ToolRegistry {
 		server:    NewServerWrapper(mcpServer),
 		mcpServer: mcpServer,
 		factory:   factory,
+		workers:   newToolPool(0),
 	}
 }
 
+// LimitConcurrency sets how many tool calls may run at once across all databases; zero
+// selects the default of 32 and a negative value removes the limit. Each database also
+// runs at most its connection's max_concurrent_tools calls at once.
+func (tr *ToolRegistry) LimitConcurrency(total int) {
+	tr.workers = newToolPool(total)
+}
+
 // RegisterAllTools registers all tools with the server
 func (tr *ToolRegistry) RegisterAllTools(ctx context.Context, useCase UseCaseProvider) error {
 	tr.databaseUseCase = useCase
@@ -77,51 +86,13 @@ func (tr *ToolRegistry) registerDatabaseTools(ctx context.Context, dbID string)
 
 	logger.Info("Registering tools for database %s", dbID)
 
-	// Special case for postgres - skip the database info call that's failing
-	dbType, err := tr.databaseUseCase.GetDatabaseType(dbID)
-	if err == nil && dbType == "postgres" {
-		// For PostgreSQL, we'll manually create a minimal info structure
-		// rather than calling the problematic method
-		logger.Info("Using special handling for PostgreSQL database: %s", dbID)
-
-		// Create a mock database info for PostgreSQL
-		dbInfo := map[string]interface{}{
-			"database": dbID,
-			"tables":   []map[string]interface{}{},
-		}
-
-		logger.Info("Created mock database info for PostgreSQL database %s: %+v", dbID, dbInfo)
-
-		// Register each tool type for this database
-		registrationErrors := 0
-		for _, typeName := range toolTypeNames {
-			// Use simpler tool names: <tooltype>_<dbID>
-			toolName := fmt.Sprintf("%s_%s", typeName, dbID)
-			if err := tr.registerTool(ctx, typeName, toolName, dbID); err != nil {
-				logger.Error("Error registering tool %s: %v", toolName, err)
-				registrationErrors++
-			} else {
-				logger.Info("Successfully registered tool %s", toolName)
-			}
-		}
-
-		if registrationErrors > 0 {
-			return fmt.Errorf("errors occurred while registering %d tools", registrationErrors)
-		}
-
-		logger.Info("Completed registering tools for database %s", dbID)
-		return nil
-	}
-
-	// For other database types, continue with the normal approach
-	// Check if this database actually exists
-	dbInfo, err := tr.databaseUseCase.GetDatabaseInfo(dbID)
-	if err != nil {
-		return fmt.Errorf("failed to get database info for %s: %w", dbID, err)
+	// The type comes from the configuration; reading anything from the database here would
+	// open the pool of every database at startup, defeating max_open_pools, and wait out the
+	// connect timeout of unreachable ones
+	if _, err := tr.databaseUseCase.GetDatabaseType(dbID); err != nil {
+		return fmt.Errorf("failed to get database type for %s: %w", dbID, err)
 	}
 
-	logger.Info("Database %s info: %+v", dbID, dbInfo)
-
 	// Register each tool type for this database
 	registrationErrors := 0
 	for _, typeName := range toolTypeNames {
@@ -155,7 +126,22 @@ func (tr *ToolRegistry) registerTool(ctx context.Context, toolTypeName string, n
 	tool := toolTypeImpl.CreateTool(name, dbID)
 
 	return tr.server.AddTool(ctx, tool, func(ctx context.Context, request server.ToolCallRequest) (interface{}, error) {
-		response, err := toolTypeImpl.HandleRequest(ctx, request, dbID, tr.databaseUseCase)
+		release, queued, err := tr.workers.acquire(ctx, tr.databaseUseCase, toolDatabase(request, dbID))
+		if err != nil {
+			return FormatResponse(nil, err)
+		}
+		defer release()
+
+		useCase := sessionBudgets.bind(databaseBreakers.guard(watchSchemaChanges(tr.databaseUseCase)), request)
+		response, err := toolTypeImpl.HandleRequest(ctx, request, dbID, useCase)
+		if err == nil {
+			if report := useCase.report(); report != "" {
+				response = appendResponseText(response, report)
+			}
+			if queued != "" {
+				response = appendResponseText(response, queued)
+			}
+		}
 		return FormatResponse(response, err)
 	})
 }
@@ -176,16 +162,105 @@ func (tr *ToolRegistry) registerCommonTools(ctx context.Context) {
 
 	// Register generic tools that work with any database
 	genericTools := []string{
-		"sql",               // Generic SQL execution
-		"db_stats",          // Database statistics
-		"table_stats",       // Table statistics
-		"get_indexes",       // Get all indexes
-		"get_constraints",   // Get all constraints
-		"get_views",         // Get all views
-		"get_types",         // Get all types
-		"get_schemas",       // Get all schemas
-		"get_sample_data",   // Get sample data from a table
-		"get_unique_values", // Get unique values from a column
+		"sql",                // Generic SQL execution
+		"db_stats",           // Database statistics
+		"get_db_settings",    // Server configuration with changed values
+		"table_stats",        // Table statistics
+		"row_counts",         // Row counts of every table in a schema
+		"largest_objects",    // Largest relations with growth since the last run
+		"get_locks",          // Held locks, blocked sessions and their blockers
+		"get_indexes",        // Get all indexes
+		"get_constraints",    // Get all constraints
+		"constraint_status",  // NOT VALID, deferrable and exclusion constraints
+		"get_views",          // Get all views
+		"get_types",          // Get all types
+		"get_functions",      // Get user-defined functions and procedures
+		"get_sequences",      // Get sequences and how much of their range is used
+		"get_extensions",     // Get installed extensions and available updates
+		"get_schemas",        // Get all schemas
+		"get_sample_data",    // Get sample data from a table
+		"get_unique_values",  // Get unique values from a column
+		"export_erd",         // Export an entity-relationship diagram
+		"doc_coverage",       // Report schema documentation coverage
+		"build_query",        // Build and run a structured SELECT query
+		"modify_rows",        // Guarded UPDATE with required filter
+		"delete_rows",        // Guarded DELETE with required filter
+		"search_schema",      // Find tables and columns by name or comment
+		"find_join_path",     // Find foreign key paths between two tables
+		"suggest_joins",      // Suggest joins among a set of tables
+		"review_schema",      // Flag schema design smells
+		"compare_plans",      // Compare EXPLAIN plans for regressions
+		"diff_results",       // Diff the results of two queries
+		"timeseries_summary", // Bucket a table by time with gap and spike detection
+		"table_usage",        // Per-table read/write heatmap
+		"translate_sql",      // MySQL/PostgreSQL dialect translation
+		"check_enum_drift",   // Enum/lookup value drift checker
+		"explain_query",      // Query plan as tree, JSON or plain English
+		"migrate",            // Versioned SQL migration runner
+		"migration_status",   // Applied/pending migrations and drift report
+		"snapshot_schema",    // Schema snapshot writer
+		"compare_snapshot",   // Live schema vs snapshot diff
+		"infer_schema",       // CSV/JSONL schema inference and import
+		"create_index",       // Online index builder
+		"run_ddl",            // Schema change with lock timeouts, retries and blocker detection
+		"manage_partitions",  // Time-range partition lifecycle management
+		"get_partitions",     // Partitioning scheme, bounds, rows and size per partition
+		"table_hierarchy",    // Partition and inheritance trees
+		"backup",             // Logical backups with pg_dump, mysqldump or CSV export
+		"list_backups",       // Recorded backups with size and status
+		"restore",            // Restore a backup with overwrite protection
+		"replication_slots",  // Replication slots and binlog retention
+		"replication_status", // Replication topology and lag
+		"publications",       // Logical replication publications
+		"manage_users",       // Database user management (requires allow_admin)
+		"manage_grants",      // Grant/revoke templates (executing requires allow_admin)
+		// Roles, memberships and grants for security reviews
+		"get_roles_and_privileges",
+		// Materialized views with size and population state, and refresh
+		"get_materialized_views",
+		// Foreign data wrappers, servers, user mappings and foreign tables
+		"get_foreign_servers",
+		// Tablespaces with location, size and largest objects
+		"get_tablespaces",
+		// Comments on tables, views, columns and functions, and setting them
+		"get_object_comments",
+		// Character sets and collations with mismatched join columns
+		"get_charsets_collations",
+		// Sessions with their state, wait event and current query
+		"get_active_sessions",
+		// Index candidates for sequential-scan-heavy tables and a workload query
+		"missing_index_suggestions",
+		"kill_session",       // Cancel or terminate a session (requires confirm)
+		"analyze_table",      // Optimizer statistics refresh
+		"run_maintenance",    // VACUUM/ANALYZE/REINDEX/OPTIMIZE on specific tables
+		"rename_object",      // Safe table/column rename with dependents
+		"clone_table",        // Clone table tool
+		"fix_sequences",      // Sequence repair tool
+		"reindex",            // Reindex tool
+		"unused_indexes",     // Rarely scanned indexes that can be dropped
+		"index_bloat",        // Estimated index bloat with REINDEX recommendations
+		"duplicate_indexes",  // Exact duplicate and prefix-redundant indexes
+		"refresh_schema",     // Schema refresh tool
+		"fetch_rows",         // Spilled result pages
+		"spatial_summary",    // SRIDs, extents and spatial indexes of geometry columns
+		"explore_json",       // Key structure of a JSON column with index suggestions
+		"search_text",        // Ranked full-text search built from keywords
+		"vector_search",      // pgvector similarity search and ANN index report
+		"tail_changes",       // Recent row changes from logical decoding or the binlog
+		"federated_query",    // Join or union sub-query results across databases
+		"list_collections",   // MongoDB collections and views
+		"collection_schema",  // MongoDB schema inferred from sampled documents
+		"collection_stats",   // MongoDB collStats statistics
+		"collection_indexes", // MongoDB collection indexes
+		"aggregate",          // Read-only MongoDB aggregation pipeline
+		"scan_keys",          // Redis keys matching a pattern with TYPE, TTL and MEMORY USAGE
+		"inspect_key",        // One Redis key with a preview of its value
+		"redis_command",      // Guarded Redis command execution
+		"list_indices",       // Elasticsearch and OpenSearch indices with health and sizes
+		"get_mapping",        // Field mappings of Elasticsearch and OpenSearch indices
+		"index_stats",        // Elasticsearch and OpenSearch _stats statistics
+		"search_index",       // Query DSL search returning hits as a table
+		"get_hypertables",    // TimescaleDB hypertables with chunks, compression and policies
 	}
 
 	for _, toolType := range genericTools {
@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+// poolCountingUseCase counts the calls that would open the pool of a database
+type poolCountingUseCase struct {
+	*mockUseCase
+	reached []string
+}
+
+func (u *poolCountingUseCase) GetDatabaseInfo(ctx context.Context, dbID string) (map[string]interface{}, error) {
+	u.reached = append(u.reached, "GetDatabaseInfo")
+	return u.mockUseCase.GetDatabaseInfo(ctx, dbID)
+}
+
+func (u *poolCountingUseCase) GetDocumentStore(dbID string) (domain.DocumentStore, error) {
+	u.reached = append(u.reached, "GetDocumentStore")
+	return u.mockUseCase.GetDocumentStore(dbID)
+}
+
+func TestRegisterAllToolsOpensNoPools(t *testing.T) {
+	for _, dbType := range []string{"postgres", "mysql"} {
+		useCase := &poolCountingUseCase{mockUseCase: &mockUseCase{dbType: dbType}}
+		registry := NewToolRegistry(server.NewMCPServer("test", "1.0.0", nil))
+
+		assert.NoError(t, registry.RegisterAllTools(context.Background(), useCase), dbType)
+		assert.Empty(t, useCase.reached, dbType)
+		assert.Empty(t, useCase.queries, dbType)
+	}
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/FreePeak/cortex/pkg/server"
 	"github.com/FreePeak/cortex/pkg/tools"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
 )
 
 // createTextResponse creates a simple response with a text content
@@ -62,12 +64,20 @@ type ToolType interface {
 
 // UseCaseProvider interface abstracts database use case operations
 type UseCaseProvider interface {
-	ExecuteQuery(ctx context.Context, dbID, query string, params []interface{}) (string, error)
+	ExecuteQuery(ctx context.Context, dbID, query string, params []interface{}) (*domain.QueryResult, error)
+	FetchRows(ctx context.Context, dbID, cursor string, limit int) (*domain.QueryResult, error)
+	StreamQuery(ctx context.Context, dbID, query string, params []interface{}, handler domain.RowHandler) (int64, error)
 	ExecuteStatement(ctx context.Context, dbID, statement string, params []interface{}) (string, error)
 	ExecuteTransaction(ctx context.Context, dbID, action string, txID string, statement string, params []interface{}, readOnly bool) (string, map[string]interface{}, error)
-	GetDatabaseInfo(dbID string) (map[string]interface{}, error)
+	ExecuteBatch(ctx context.Context, dbID string, statements []string, params [][]interface{}) error
+	GetDatabaseInfo(ctx context.Context, dbID string) (map[string]interface{}, error)
 	ListDatabases() []string
 	GetDatabaseType(dbID string) (string, error)
+	GetDatabaseConfig(dbID string) (*domain.DatabaseConnectionConfig, error)
+	Listen(ctx context.Context, dbID, channel string, handle func(payload string)) error
+	GetDocumentStore(dbID string) (domain.DocumentStore, error)
+	GetKeyValueStore(dbID string) (domain.KeyValueStore, error)
+	GetSearchStore(dbID string) (domain.SearchStore, error)
 }
 
 // BaseToolType provides common functionality for tool types
@@ -107,8 +117,7 @@ func NewQueryTool() *QueryTool {
 
 // CreateTool creates a query tool
 func (t *QueryTool) CreateTool(name string, dbID string) interface{} {
-	return tools.NewTool(
-		name,
+	return tools.NewTool(name, append([]tools.ToolOption{
 		tools.WithDescription(t.GetDescription(dbID)),
 		tools.WithString("query",
 			tools.Description("SQL query to execute"),
@@ -118,7 +127,7 @@ func (t *QueryTool) CreateTool(name string, dbID string) interface{} {
 			tools.Description("Query parameters"),
 			tools.Items(map[string]interface{}{"type": "string"}),
 		),
-	)
+	}, resultParameters()...)...)
 }
 
 // HandleRequest handles query tool requests
@@ -128,16 +137,12 @@ func (t *QueryTool) HandleRequest(ctx context.Context, request server.ToolCallRe
 		dbID = extractDatabaseIDFromName(request.Name)
 	}
 
-	query, ok := request.Parameters["query"].(string)
-	if !ok {
-		return nil, fmt.Errorf("query parameter must be a string")
-	}
-
-	var queryParams []interface{}
-	if request.Parameters["params"] != nil {
-		if paramsArr, ok := request.Parameters["params"].([]interface{}); ok {
-			queryParams = paramsArr
-		}
+	input := newToolParams(request.Parameters)
+	query := input.requiredString("query")
+	queryParams := input.list("params")
+	options := readResultOptions(input)
+	if err := input.err(); err != nil {
+		return nil, err
 	}
 
 	result, err := useCase.ExecuteQuery(ctx, dbID, query, queryParams)
@@ -145,7 +150,7 @@ func (t *QueryTool) HandleRequest(ctx context.Context, request server.ToolCallRe
 		return nil, err
 	}
 
-	return createTextResponse(result), nil
+	return createTextResponse(encodeQueryResult(result, options)), nil
 }
 
 // extractDatabaseIDFromName extracts the database ID from a tool name
@@ -202,16 +207,11 @@ func (t *ExecuteTool) HandleRequest(ctx context.Context, request server.ToolCall
 		dbID = extractDatabaseIDFromName(request.Name)
 	}
 
-	statement, ok := request.Parameters["statement"].(string)
-	if !ok {
-		return nil, fmt.Errorf("statement parameter must be a string")
-	}
-
-	var statementParams []interface{}
-	if request.Parameters["params"] != nil {
-		if paramsArr, ok := request.Parameters["params"].([]interface{}); ok {
-			statementParams = paramsArr
-		}
+	input := newToolParams(request.Parameters)
+	statement := input.requiredString("statement")
+	statementParams := input.list("params")
+	if err := input.err(); err != nil {
+		return nil, err
 	}
 
 	result, err := useCase.ExecuteStatement(ctx, dbID, statement, statementParams)
@@ -273,43 +273,14 @@ func (t *TransactionTool) HandleRequest(ctx context.Context, request server.Tool
 		dbID = extractDatabaseIDFromName(request.Name)
 	}
 
-	action, ok := request.Parameters["action"].(string)
-	if !ok {
-		return nil, fmt.Errorf("action parameter must be a string")
-	}
-
-	txID := ""
-	if request.Parameters["transactionId"] != nil {
-		var ok bool
-		txID, ok = request.Parameters["transactionId"].(string)
-		if !ok {
-			return nil, fmt.Errorf("transactionId parameter must be a string")
-		}
-	}
-
-	statement := ""
-	if request.Parameters["statement"] != nil {
-		var ok bool
-		statement, ok = request.Parameters["statement"].(string)
-		if !ok {
-			return nil, fmt.Errorf("statement parameter must be a string")
-		}
-	}
-
-	var params []interface{}
-	if request.Parameters["params"] != nil {
-		if paramsArr, ok := request.Parameters["params"].([]interface{}); ok {
-			params = paramsArr
-		}
-	}
-
-	readOnly := false
-	if request.Parameters["readOnly"] != nil {
-		var ok bool
-		readOnly, ok = request.Parameters["readOnly"].(bool)
-		if !ok {
-			return nil, fmt.Errorf("readOnly parameter must be a boolean")
-		}
+	input := newToolParams(request.Parameters)
+	action := input.requiredString("action")
+	txID := input.optionalString("transactionId", "")
+	statement := input.optionalString("statement", "")
+	params := input.list("params")
+	readOnly := input.optionalBool("readOnly", false)
+	if err := input.err(); err != nil {
+		return nil, err
 	}
 
 	message, metadata, err := useCase.ExecuteTransaction(ctx, dbID, action, txID, statement, params, readOnly)
@@ -378,32 +349,13 @@ func (t *PerformanceTool) HandleRequest(ctx context.Context, request server.Tool
 	// This is a simplified implementation
 	// In a real implementation, this would analyze query performance
 
-	action, ok := request.Parameters["action"].(string)
-	if !ok {
-		return nil, fmt.Errorf("action parameter must be a string")
-	}
-
-	var limit int
-	if request.Parameters["limit"] != nil {
-		if limitParam, ok := request.Parameters["limit"].(float64); ok {
-			limit = int(limitParam)
-		}
-	}
-
-	query := ""
-	if request.Parameters["query"] != nil {
-		var ok bool
-		query, ok = request.Parameters["query"].(string)
-		if !ok {
-			return nil, fmt.Errorf("query parameter must be a string")
-		}
-	}
-
-	var threshold int
-	if request.Parameters["threshold"] != nil {
-		if thresholdParam, ok := request.Parameters["threshold"].(float64); ok {
-			threshold = int(thresholdParam)
-		}
+	input := newToolParams(request.Parameters)
+	action := input.requiredString("action")
+	limit := input.optionalInt("limit", 0)
+	query := input.optionalString("query", "")
+	threshold := input.optionalInt("threshold", 0)
+	if err := input.err(); err != nil {
+		return nil, err
 	}
 
 	// This is where we would call the useCase to analyze performance
@@ -463,7 +415,7 @@ func (t *SchemaTool) HandleRequest(ctx context.Context, request server.ToolCallR
 		dbID = extractDatabaseIDFromName(request.Name)
 	}
 
-	info, err := useCase.GetDatabaseInfo(dbID)
+	info, err := useCase.GetDatabaseInfo(ctx, dbID)
 	if err != nil {
 		return nil, err
 	}
@@ -473,83 +425,6 @@ func (t *SchemaTool) HandleRequest(ctx context.Context, request server.ToolCallR
 	return createTextResponse(infoStr), nil
 }
 
-//------------------------------------------------------------------------------
-// ListDatabasesTool implementation
-//------------------------------------------------------------------------------
-
-// ListDatabasesTool handles listing available databases
-type ListDatabasesTool struct {
-	BaseToolType
-}
-
-// NewListDatabasesTool creates a new list databases tool type
-func NewListDatabasesTool() *ListDatabasesTool {
-	return &ListDatabasesTool{
-		BaseToolType: BaseToolType{
-			name:        "list_databases",
-			description: "List all available databases with detailed connection information including database name, host, port, and type. This tool provides a comprehensive overview of all database connections configured in the system, allowing you to identify and select the appropriate database for your operations.",
-		},
-	}
-}
-
-// CreateTool creates a list databases tool
-func (t *ListDatabasesTool) CreateTool(name string, dbID string) interface{} {
-	return tools.NewTool(
-		name,
-		tools.WithDescription(t.GetDescription(dbID)),
-		// Use any string parameter for compatibility
-		tools.WithString("random_string",
-			tools.Description("Dummy parameter (optional)"),
-		),
-	)
-}
-
-// HandleRequest handles list databases tool requests
-func (t *ListDatabasesTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
-	databases := useCase.ListDatabases()
-
-	// Format as text for display
-	output := "Available databases:\n\n"
-	output += "| # | Database ID | Type | Host | Port | Database Name |\n"
-	output += "|---|------------|------|------|------|--------------|\n"
-
-	for i, dbID := range databases {
-		// Get database info to extract host, port, etc.
-		dbInfo, err := useCase.GetDatabaseInfo(dbID)
-		if err != nil {
-			// If we can't get detailed info, just show the database ID
-			output += fmt.Sprintf("| %d | %s | Unknown | Unknown | Unknown | Unknown |\n", i+1, dbID)
-			continue
-		}
-
-		// Extract database type
-		dbType, _ := useCase.GetDatabaseType(dbID)
-		if dbType == "" {
-			dbType = "Unknown"
-		}
-
-		// Extract host, port, and name from dbInfo if available
-		host := "Unknown"
-		port := "Unknown"
-		name := "Unknown"
-
-		// Try to extract database name from dbInfo
-		if dbName, ok := dbInfo["database"].(string); ok {
-			name = dbName
-		}
-
-		// For now, we'll use placeholders for host and port
-		// In a real implementation, these would come from the connection config
-		output += fmt.Sprintf("| %d | %s | %s | %s | %s | %s |\n", i+1, dbID, dbType, host, port, name)
-	}
-
-	if len(databases) == 0 {
-		output += "No databases configured.\n"
-	}
-
-	return createTextResponse(output), nil
-}
-
 //------------------------------------------------------------------------------
 // ToolTypeFactory provides a factory for creating tool types
 //------------------------------------------------------------------------------
@@ -578,17 +453,132 @@ func NewToolTypeFactory() *ToolTypeFactory {
 
 	// Register database statistics tools
 	factory.Register(NewDbStatsTool())
+	factory.Register(NewGetDBSettingsTool())
 	factory.Register(NewTableStatsTool())
+	factory.Register(NewRowCountsTool())
+	factory.Register(NewLargestObjectsTool())
+	factory.Register(NewGetLocksTool())
+	factory.Register(NewGetActiveSessionsTool())
+	factory.Register(NewKillSessionTool())
 
 	// Register pre-generated query tools
 	factory.Register(NewGetIndexesTool())
 	factory.Register(NewGetConstraintsTool())
+	factory.Register(NewConstraintStatusTool())
 	factory.Register(NewGetViewsTool())
+	factory.Register(NewGetMaterializedViewsTool())
 	factory.Register(NewGetTypesTool())
+	factory.Register(NewGetFunctionsTool())
+	factory.Register(NewGetSequencesTool())
+	factory.Register(NewGetExtensionsTool())
+	factory.Register(NewGetForeignServersTool())
+	factory.Register(NewGetTablespacesTool())
 	factory.Register(NewGetSchemasTool())
 	factory.Register(NewGetSampleDataTool())
 	factory.Register(NewGetUniqueValuesTool())
 
+	// Register schema documentation tools
+	factory.Register(NewExportERDTool())
+	factory.Register(NewDocCoverageTool())
+	factory.Register(NewGetObjectCommentsTool())
+	factory.Register(NewGetCharsetsCollationsTool())
+
+	// Register query building tools
+	factory.Register(NewBuildQueryTool())
+
+	// Register guarded write tools
+	factory.Register(NewModifyRowsTool())
+	factory.Register(NewDeleteRowsTool())
+
+	// Register schema search tools
+	factory.Register(NewSearchSchemaTool())
+
+	// Register join discovery tools
+	factory.Register(NewFindJoinPathTool())
+	factory.Register(NewSuggestJoinsTool())
+
+	// Register schema review tools
+	factory.Register(NewReviewSchemaTool())
+
+	// Register query plan and comparison tools
+	factory.Register(NewExplainQueryTool())
+	factory.Register(NewComparePlansTool())
+	factory.Register(NewDiffResultsTool())
+
+	// Register data analysis tools
+	factory.Register(NewTimeseriesSummaryTool())
+	factory.Register(NewTableUsageTool())
+	factory.Register(NewEnumDriftTool())
+
+	// Register dialect translation tools
+	factory.Register(NewTranslateSQLTool())
+
+	// Register migration tools
+	factory.Register(NewMigrateTool())
+	factory.Register(NewMigrationStatusTool())
+
+	// Register schema snapshot tools
+	factory.Register(NewSnapshotSchemaTool())
+	factory.Register(NewCompareSnapshotTool())
+
+	// Register data import tools
+	factory.Register(NewInferSchemaTool())
+
+	// Register schema change tools
+	factory.Register(NewCreateIndexTool())
+	factory.Register(NewRunDDLTool())
+	factory.Register(NewManagePartitionsTool())
+	factory.Register(NewGetPartitionsTool())
+	factory.Register(NewTableHierarchyTool())
+	factory.Register(NewRenameObjectTool())
+	factory.Register(NewCloneTableTool())
+
+	// Register backup tools
+	factory.Register(NewBackupTool())
+	factory.Register(NewListBackupsTool())
+	factory.Register(NewRestoreTool())
+
+	// Register replication tools
+	factory.Register(NewReplicationSlotsTool())
+	factory.Register(NewReplicationStatusTool())
+	factory.Register(NewPublicationsTool())
+
+	// Register administration tools
+	factory.Register(NewManageUsersTool())
+	factory.Register(NewManageGrantsTool())
+	factory.Register(NewGetRolesAndPrivilegesTool())
+
+	// Register maintenance tools
+	factory.Register(NewAnalyzeTableTool())
+	factory.Register(NewRunMaintenanceTool())
+	factory.Register(NewFixSequencesTool())
+	factory.Register(NewReindexTool())
+	factory.Register(NewUnusedIndexesTool())
+	factory.Register(NewIndexBloatTool())
+	factory.Register(NewDuplicateIndexesTool())
+	factory.Register(NewMissingIndexSuggestionsTool())
+	factory.Register(NewRefreshSchemaTool())
+	factory.Register(NewFetchRowsTool())
+	factory.Register(NewSpatialSummaryTool())
+	factory.Register(NewExploreJSONTool())
+	factory.Register(NewSearchTextTool())
+	factory.Register(NewVectorSearchTool())
+	factory.Register(NewTailChangesTool())
+	factory.Register(NewFederatedQueryTool())
+	factory.Register(NewListCollectionsTool())
+	factory.Register(NewCollectionSchemaTool())
+	factory.Register(NewCollectionStatsTool())
+	factory.Register(NewCollectionIndexesTool())
+	factory.Register(NewAggregateTool())
+	factory.Register(NewScanKeysTool())
+	factory.Register(NewInspectKeyTool())
+	factory.Register(NewRedisCommandTool())
+	factory.Register(NewListIndicesTool())
+	factory.Register(NewGetMappingTool())
+	factory.Register(NewIndexStatsTool())
+	factory.Register(NewSearchIndexTool())
+	factory.Register(NewGetHypertablesTool())
+
 	return factory
 }
 
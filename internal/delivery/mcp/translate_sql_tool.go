@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// TranslateSQLTool handles translating SQL between MySQL and PostgreSQL
+type TranslateSQLTool struct {
+	BaseToolType
+}
+
+// NewTranslateSQLTool creates a new SQL translation tool type
+func NewTranslateSQLTool() *TranslateSQLTool {
+	return &TranslateSQLTool{
+		BaseToolType: BaseToolType{
+			name:        "translate_sql",
+			description: "Translate a SQL statement between the MySQL and PostgreSQL dialects. It converts identifier and string quoting, placeholders, LIMIT offset/count, functions such as IFNULL/COALESCE, RAND/RANDOM and GROUP_CONCAT/STRING_AGG, casts and date arithmetic, and lists every construct it could not translate automatically so it can be reviewed by hand. The statement is not executed.",
+		},
+	}
+}
+
+// CreateTool creates a SQL translation tool
+func (t *TranslateSQLTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Translate SQL between MySQL and PostgreSQL, flagging untranslatable constructs"),
+		tools.WithString("query",
+			tools.Description("SQL statement to translate"),
+			tools.Required(),
+		),
+		tools.WithString("from",
+			tools.Description("Source dialect: mysql or postgres (optional, defaults to the opposite of the target)"),
+		),
+		tools.WithString("to",
+			tools.Description("Target dialect: mysql or postgres (optional, defaults to the type of the given database)"),
+		),
+		tools.WithString("database",
+			tools.Description("Database ID whose type is the target dialect (optional)"),
+		),
+	)
+}
+
+// HandleRequest handles SQL translation tool requests
+func (t *TranslateSQLTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	query := input.requiredString("query")
+	from := strings.ToLower(input.optionalString("from", ""))
+	to := strings.ToLower(input.optionalString("to", ""))
+	targetDbID := input.optionalString("database", "")
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+
+	// Infer the target dialect from the database when it was not given
+	if to == "" && targetDbID != "" {
+		dbType, err := useCase.GetDatabaseType(targetDbID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get database type: %w", err)
+		}
+		to = strings.ToLower(dbType)
+	}
+
+	from, to, err := resolveDialects(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Translating SQL from %s to %s", from, to)
+
+	translation, err := translateSQL(query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to translate SQL: %w", err)
+	}
+
+	return createTextResponse(formatSQLTranslation(translation)), nil
+}
+
+// resolveDialects fills in a missing source or target dialect with the other one
+func resolveDialects(from, to string) (string, string, error) {
+	other := map[string]string{"mysql": "postgres", "postgres": "mysql"}
+	switch {
+	case from == "" && to == "":
+		return "", "", fmt.Errorf("from, to or database parameter is required")
+	case from == "":
+		from = other[to]
+	case to == "":
+		to = other[from]
+	}
+	if other[from] == "" || other[to] == "" {
+		return "", "", fmt.Errorf("unsupported dialect translation %s to %s (expected mysql or postgres)", from, to)
+	}
+	return from, to, nil
+}
+
+// formatSQLTranslation renders the translated statement with its changes and warnings
+func formatSQLTranslation(translation *sqlTranslation) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# SQL Translation from %s to %s\n\n", translation.From, translation.To))
+	sb.WriteString("```sql\n")
+	sb.WriteString(strings.TrimSpace(translation.SQL))
+	sb.WriteString("\n```\n")
+
+	if len(translation.Changes) > 0 {
+		sb.WriteString("\nTranslated:\n")
+		for _, change := range translation.Changes {
+			sb.WriteString(fmt.Sprintf("- %s\n", change))
+		}
+	} else {
+		sb.WriteString("\nNo dialect-specific constructs needed translating.\n")
+	}
+
+	if len(translation.Warnings) > 0 {
+		sb.WriteString("\nNeeds manual review:\n")
+		for _, warning := range translation.Warnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", warning))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateMySQLToPostgres(t *testing.T) {
+	translation, err := translateSQL(
+		"SELECT `id`, IFNULL(`name`, \"n/a\") FROM `users` # recent\n"+
+			"WHERE created_at > DATE_SUB(NOW(), INTERVAL 7 DAY) AND note = 'it\\'s' AND age > ? "+
+			"ORDER BY RAND() LIMIT 20, 10",
+		"mysql", "postgres")
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "id", COALESCE("name", 'n/a') FROM "users" -- recent
+WHERE created_at > (NOW() - INTERVAL '7 day') AND note = 'it''s' AND age > $1 ORDER BY RANDOM() LIMIT 10 OFFSET 20`, translation.SQL)
+	assert.Contains(t, translation.Changes, "LIMIT offset, count converted to LIMIT count OFFSET offset")
+	assert.Empty(t, translation.Warnings)
+}
+
+func TestTranslatePostgresToMySQL(t *testing.T) {
+	translation, err := translateSQL(
+		`SELECT "u"."id"::text, STRING_AGG(tag, ', ') FROM users u WHERE u.email ILIKE $1 `+
+			`AND u.created_at > NOW() - INTERVAL '3 days' GROUP BY 1 LIMIT 5 OFFSET 10`,
+		"postgres", "mysql")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT CAST(`u`.`id` AS CHAR), GROUP_CONCAT(tag SEPARATOR ', ') FROM users u WHERE u.email LIKE ? "+
+		"AND u.created_at > NOW() - INTERVAL 3 DAY GROUP BY 1 LIMIT 5 OFFSET 10", translation.SQL)
+	assert.Empty(t, translation.Warnings)
+}
+
+func TestTranslateSQLFlagsUntranslatableConstructs(t *testing.T) {
+	translation, err := translateSQL(
+		"INSERT INTO t (a) VALUES (GROUP_CONCAT(DISTINCT b)) ON DUPLICATE KEY UPDATE a = a + 1",
+		"mysql", "postgres")
+	assert.NoError(t, err)
+	assert.Contains(t, translation.SQL, "GROUP_CONCAT(DISTINCT b)")
+	assert.Len(t, translation.Warnings, 2)
+
+	translation, err = translateSQL("SELECT a || b FROM t RETURNING id", "postgres", "mysql")
+	assert.NoError(t, err)
+	assert.Len(t, translation.Warnings, 2)
+
+	_, err = translateSQL("SELECT 'unterminated", "postgres", "mysql")
+	assert.Error(t, err)
+	_, err = translateSQL("SELECT 1", "mysql", "mysql")
+	assert.Error(t, err)
+}
+
+func TestTranslateSQLTool(t *testing.T) {
+	result, err := NewTranslateSQLTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"query": "SELECT IFNULL(a, 0) FROM t", "database": "pg1"},
+	}, "pg1", &mockUseCase{dbType: "postgres"})
+	assert.NoError(t, err)
+
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "# SQL Translation from mysql to postgres")
+	assert.Contains(t, text, "SELECT COALESCE(a, 0) FROM t")
+
+	_, err = NewTranslateSQLTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"query": "SELECT 1"},
+	}, "pg1", &mockUseCase{dbType: "postgres"})
+	assert.Error(t, err)
+}
+
+func TestSplitSQLStatements(t *testing.T) {
+	statements, err := splitSQLStatements(`-- create the table
+CREATE TABLE notes (body TEXT DEFAULT 'a;b');
+CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN NEW.updated_at = now(); RETURN NEW; END;
+$$ LANGUAGE plpgsql;
+/* trailing; comment */`, "postgres")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"-- create the table\nCREATE TABLE notes (body TEXT DEFAULT 'a;b')",
+		"CREATE FUNCTION touch() RETURNS trigger AS $$\nBEGIN NEW.updated_at = now(); RETURN NEW; END;\n$$ LANGUAGE plpgsql",
+	}, statements)
+}
+
+func TestSplitSQLStatementsSQLiteTrigger(t *testing.T) {
+	statements, err := splitSQLStatements(`CREATE TABLE notes (id INTEGER PRIMARY KEY, updated_at TEXT);
+CREATE TEMP TRIGGER notes_touch AFTER UPDATE ON notes BEGIN
+  UPDATE notes SET updated_at = datetime('now') WHERE id = NEW.id;
+END;
+DELETE FROM notes;`, "sqlite")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"CREATE TABLE notes (id INTEGER PRIMARY KEY, updated_at TEXT)",
+		"CREATE TEMP TRIGGER notes_touch AFTER UPDATE ON notes BEGIN\n  UPDATE notes SET updated_at = datetime('now') WHERE id = NEW.id;\nEND",
+		"DELETE FROM notes",
+	}, statements)
+}
@@ -0,0 +1,287 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// unusedIndex is a secondary index with few or no scans
+type unusedIndex struct {
+	Schema     string
+	Table      string
+	Index      string
+	Scans      int64
+	Bytes      int64
+	Definition string
+	ForeignKey string // foreign keys the index supports, if any
+}
+
+// minUsageStatsAge is how long usage counters should have been collecting before an index
+// with no scans can be trusted to be unused
+const minUsageStatsAge = 7 * 24 * time.Hour
+
+// UnusedIndexesTool handles finding indexes that can be dropped
+type UnusedIndexesTool struct {
+	BaseToolType
+}
+
+// NewUnusedIndexesTool creates a new unused indexes tool type
+func NewUnusedIndexesTool() *UnusedIndexesTool {
+	return &UnusedIndexesTool{
+		BaseToolType: BaseToolType{
+			name:        "unused_indexes",
+			description: "List the indexes that are never or hardly ever scanned, with their size, the space dropping them would free and the statements to drop them. Primary key, unique and constraint indexes are left out, since they enforce data rules rather than speed up reads. Usage comes from pg_stat_user_indexes on PostgreSQL and performance_schema on MySQL, counted since the statistics were last reset or the server started and only on this server, so scans on replicas are not included; the report shows how long the counters cover. Indexes supporting a foreign key are called out. The tool never drops anything itself.",
+		},
+	}
+}
+
+// CreateTool creates an unused indexes tool
+func (t *UnusedIndexesTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("List rarely scanned indexes with their size and drop statements"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("schema",
+			tools.Description("Schema to inspect (optional, leave empty for all user schemas)"),
+		),
+		tools.WithNumber("max_scans",
+			tools.Description("List indexes scanned at most this many times (default: 0)"),
+		),
+		tools.WithNumber("min_size_mb",
+			tools.Description("Only list indexes at least this large (default: 0)"),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Maximum number of indexes to list, largest first (default: 100)"),
+		),
+	)
+}
+
+// HandleRequest handles unused indexes tool requests
+func (t *UnusedIndexesTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	schema := input.optionalString("schema", "")
+	maxScans := input.intAtLeast("max_scans", 0, 0)
+	minSizeMB := input.optionalFloat("min_size_mb", 0)
+	limit := input.intBetween("limit", 100, 1, 10000)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if minSizeMB < 0 {
+		return nil, fmt.Errorf("min_size_mb parameter must not be negative")
+	}
+	minBytes := int64(minSizeMB * (1 << 20))
+
+	logger.Info("Finding unused indexes for database %s, schema %s", targetDbID, schema)
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for unused indexes: %s", dbType)
+	}
+	usage, ok := dialect.UnusedIndexQueries(schema, maxScans, minBytes, limit)
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type for unused indexes: %s", dbType)
+	}
+
+	ageResult, err := useCase.ExecuteQuery(ctx, targetDbID, usage.statsAge, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statistics age: %w", err)
+	}
+	var statsAge time.Duration
+	if len(ageResult.Rows) > 0 && len(ageResult.Rows[0]) > 0 {
+		statsAge = time.Duration(valueFloat64(ageResult.Rows[0][0])) * time.Second
+	}
+
+	result, err := useCase.ExecuteQuery(ctx, targetDbID, usage.indexes, usage.params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index usage: %w", err)
+	}
+	var indexes []unusedIndex
+	for _, row := range result.Rows {
+		if len(row) < 7 {
+			continue
+		}
+		indexes = append(indexes, unusedIndex{
+			Schema:     valueString(row[0]),
+			Table:      valueString(row[1]),
+			Index:      valueString(row[2]),
+			Scans:      valueInt64(row[3]),
+			Bytes:      valueInt64(row[4]),
+			Definition: valueString(row[5]),
+			ForeignKey: valueString(row[6]),
+		})
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Unused Indexes in Database %s\n\n", targetDbID))
+	response.WriteString(fmt.Sprintf("Usage counted over the last %s on this server only; scans on replicas are not included.\n\n", statsAge.Round(time.Minute)))
+	writeUnusedIndexes(&response, dialect, usage, indexes, maxScans, statsAge)
+
+	return createTextResponse(response.String()), nil
+}
+
+// writeUnusedIndexes renders the indexes, their drop statements and the warnings about them
+func writeUnusedIndexes(sb *strings.Builder, dialect Dialect, usage unusedIndexQueries, indexes []unusedIndex, maxScans int, statsAge time.Duration) {
+	if len(indexes) == 0 {
+		sb.WriteString(fmt.Sprintf("No secondary indexes with at most %d scans.\n", maxScans))
+		return
+	}
+
+	sb.WriteString("| Index | Table | Scans | Size | Definition |\n")
+	sb.WriteString("|-------|-------|-------|------|------------|\n")
+	var total int64
+	var statements, warnings []string
+	for _, idx := range indexes {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %d | %s | %s |\n",
+			idx.Index, qualifiedName(idx.Schema, idx.Table), idx.Scans, formatBackupSize(idx.Bytes), queryCell(idx.Definition)))
+		total += idx.Bytes
+
+		switch {
+		case idx.ForeignKey != "" && usage.foreignKeyPinsIndex:
+			warnings = append(warnings, fmt.Sprintf("%s supports foreign key %s; %s will not drop it while the foreign key exists, so it has no drop statement.", idx.Index, idx.ForeignKey, usage.engine))
+			continue
+		case idx.ForeignKey != "":
+			warnings = append(warnings, fmt.Sprintf("%s supports foreign key %s; without it, deleting or updating keys in the referenced table scans %s.", idx.Index, idx.ForeignKey, idx.Table))
+		}
+		statements = append(statements, dialect.DropIndexStatement(idx.Schema, idx.Table, idx.Index))
+	}
+	sb.WriteString(fmt.Sprintf("\n%d indexes, %s could be freed by dropping them.\n", len(indexes), formatBackupSize(total)))
+
+	if len(statements) > 0 {
+		sb.WriteString("\n## Drop Statements\n\n")
+		sb.WriteString(fmt.Sprintf("```sql\n%s\n```\n", strings.Join(statements, "\n")))
+		if usage.hideHint != "" {
+			sb.WriteString("\n" + usage.hideHint + "\n")
+		}
+	}
+
+	if statsAge < minUsageStatsAge {
+		warnings = append([]string{fmt.Sprintf("Usage counters only cover %s; indexes used by weekly or monthly jobs may look unused.", statsAge.Round(time.Minute))}, warnings...)
+	}
+	if len(warnings) > 0 {
+		sb.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			sb.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+}
+
+// unusedIndexQueries are the queries of unused_indexes and what the engine allows when dropping
+// the indexes they find
+type unusedIndexQueries struct {
+	// statsAge returns the seconds the usage counters cover
+	statsAge string
+	// indexes returns the secondary indexes with few scans, bound to params
+	indexes string
+	params  []interface{}
+	// engine names the database in warnings
+	engine string
+	// foreignKeyPinsIndex is set when the engine refuses to drop the index a foreign key relies on
+	foreignKeyPinsIndex bool
+	// hideHint tells how to hide an index from the optimizer before dropping it, when the engine can
+	hideHint string
+}
+
+// getPostgresStatsAgeQuery returns a query for the seconds since the usage statistics of the
+// current database were reset, or since the server started when they never were
+func getPostgresStatsAgeQuery() string {
+	return `
+SELECT EXTRACT(EPOCH FROM now() - GREATEST(COALESCE(stats_reset, pg_postmaster_start_time()), pg_postmaster_start_time()))
+FROM pg_stat_database
+WHERE datname = current_database();`
+}
+
+// getPostgresUnusedIndexesQuery returns a query for the indexes scanned at most $2 times that
+// enforce no constraint, with the foreign keys whose columns lead the index
+func getPostgresUnusedIndexesQuery() string {
+	return `
+SELECT
+    s.schemaname AS schema_name,
+    s.relname AS table_name,
+    s.indexrelname AS index_name,
+    s.idx_scan AS scans,
+    pg_relation_size(s.indexrelid) AS index_bytes,
+    pg_get_indexdef(s.indexrelid) AS definition,
+    COALESCE((
+        SELECT string_agg(fk.conname, ', ')
+        FROM pg_constraint fk
+        WHERE fk.contype = 'f'
+          AND fk.conrelid = i.indrelid
+          AND (string_to_array(i.indkey::text, ' ')::int2[])[1:array_length(fk.conkey, 1)] @> fk.conkey
+    ), '') AS foreign_key
+FROM pg_stat_user_indexes s
+JOIN pg_index i ON i.indexrelid = s.indexrelid
+WHERE NOT i.indisunique
+  AND NOT i.indisprimary
+  AND NOT EXISTS (SELECT 1 FROM pg_constraint c WHERE c.conindid = s.indexrelid)
+  AND ($1 = '' OR s.schemaname = $1)
+  AND s.idx_scan <= $2
+  AND pg_relation_size(s.indexrelid) >= $3
+ORDER BY index_bytes DESC
+LIMIT $4;`
+}
+
+// getMySQLStatsAgeQuery returns a query for the seconds since the server started, when the
+// performance_schema counters were last reset
+func getMySQLStatsAgeQuery() string {
+	return `SELECT VARIABLE_VALUE FROM performance_schema.global_status WHERE VARIABLE_NAME = 'Uptime';`
+}
+
+// getMySQLUnusedIndexesQuery returns a query for the non-unique indexes read at most ? times.
+// Index sizes come from mysql.innodb_index_stats, which needs SELECT on the mysql schema.
+func getMySQLUnusedIndexesQuery() string {
+	return `
+SELECT
+    u.OBJECT_SCHEMA AS schema_name,
+    u.OBJECT_NAME AS table_name,
+    u.INDEX_NAME AS index_name,
+    u.COUNT_READ AS scans,
+    COALESCE(s.stat_value * @@innodb_page_size, 0) AS index_bytes,
+    st.columns AS definition,
+    COALESCE((
+        SELECT GROUP_CONCAT(DISTINCT k.CONSTRAINT_NAME)
+        FROM information_schema.KEY_COLUMN_USAGE k
+        WHERE k.TABLE_SCHEMA = u.OBJECT_SCHEMA
+          AND k.TABLE_NAME = u.OBJECT_NAME
+          AND k.REFERENCED_TABLE_NAME IS NOT NULL
+          AND k.ORDINAL_POSITION = 1
+          AND k.COLUMN_NAME = st.first_column
+    ), '') AS foreign_key
+FROM performance_schema.table_io_waits_summary_by_index_usage u
+JOIN (
+    SELECT
+        TABLE_SCHEMA,
+        TABLE_NAME,
+        INDEX_NAME,
+        GROUP_CONCAT(COLUMN_NAME ORDER BY SEQ_IN_INDEX) AS columns,
+        SUBSTRING_INDEX(GROUP_CONCAT(COLUMN_NAME ORDER BY SEQ_IN_INDEX), ',', 1) AS first_column,
+        MIN(NON_UNIQUE) AS non_unique
+    FROM information_schema.STATISTICS
+    GROUP BY TABLE_SCHEMA, TABLE_NAME, INDEX_NAME
+) st ON st.TABLE_SCHEMA = u.OBJECT_SCHEMA AND st.TABLE_NAME = u.OBJECT_NAME AND st.INDEX_NAME = u.INDEX_NAME
+LEFT JOIN mysql.innodb_index_stats s
+    ON s.database_name = u.OBJECT_SCHEMA AND s.table_name = u.OBJECT_NAME
+   AND s.index_name = u.INDEX_NAME AND s.stat_name = 'size'
+WHERE u.INDEX_NAME IS NOT NULL
+  AND u.INDEX_NAME <> 'PRIMARY'
+  AND st.non_unique = 1
+  AND u.OBJECT_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+  AND (? = '' OR u.OBJECT_SCHEMA = ?)
+  AND u.COUNT_READ <= ?
+  AND COALESCE(s.stat_value * @@innodb_page_size, 0) >= ?
+ORDER BY index_bytes DESC
+LIMIT ?;`
+}
@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func TestUnusedIndexesToolPostgres(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"FROM pg_stat_database": {Rows: [][]interface{}{{float64(2 * 86400)}}},
+			"FROM pg_stat_user_indexes": {Rows: [][]interface{}{
+				{"public", "orders", "orders_note_idx", int64(0), int64(8 << 20), "CREATE INDEX orders_note_idx ON public.orders USING btree (note)", ""},
+				{"public", "orders", "orders_customer_idx", int64(0), int64(2 << 20), "CREATE INDEX orders_customer_idx ON public.orders USING btree (customer_id)", "orders_customer_fk"},
+			}},
+		},
+	}
+
+	result, err := NewUnusedIndexesTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "schema": "public"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "Usage counted over the last 48h0m0s")
+	assert.Contains(t, text, "| orders_note_idx | public.orders | 0 | 8.0 MiB |")
+	assert.Contains(t, text, "2 indexes, 10.0 MiB could be freed by dropping them.")
+	assert.Contains(t, text, `DROP INDEX CONCURRENTLY "public"."orders_customer_idx";`)
+	assert.Contains(t, text, "Usage counters only cover 48h0m0s")
+	assert.Contains(t, text, "orders_customer_idx supports foreign key orders_customer_fk")
+}
+
+func TestUnusedIndexesToolMySQL(t *testing.T) {
+	useCase := &mockUseCase{
+		dbType: "mysql",
+		results: map[string]*domain.QueryResult{
+			"global_status": {Rows: [][]interface{}{{"2592000"}}},
+			"table_io_waits_summary_by_index_usage": {Rows: [][]interface{}{
+				{"shop", "orders", "idx_note", int64(3), int64(4 << 20), "note", ""},
+				{"shop", "orders", "idx_customer", int64(0), int64(1 << 20), "customer_id", "orders_customer_fk"},
+			}},
+		},
+	}
+
+	result, err := NewUnusedIndexesTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "my1", "max_scans": float64(5)},
+	}, "my1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "ALTER TABLE `shop`.`orders` DROP INDEX `idx_note`;")
+	assert.NotContains(t, text, "DROP INDEX `idx_customer`")
+	assert.Contains(t, text, "MySQL will not drop it while the foreign key exists")
+	assert.NotContains(t, text, "Usage counters only cover")
+}
+
+func TestUnusedIndexesToolNone(t *testing.T) {
+	useCase := &mockUseCase{dbType: "postgres"}
+	result, err := NewUnusedIndexesTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1"},
+	}, "pg1", useCase)
+	assert.NoError(t, err)
+	text := result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	assert.Contains(t, text, "No secondary indexes with at most 0 scans.")
+
+	_, err = NewUnusedIndexesTool().HandleRequest(context.Background(), server.ToolCallRequest{
+		Parameters: map[string]interface{}{"database": "pg1", "min_size_mb": float64(-1)},
+	}, "pg1", useCase)
+	assert.ErrorContains(t, err, "min_size_mb parameter must not be negative")
+}
@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+func TestMain(m *testing.M) {
+	// Tool handlers log through the package logger, which must be initialized
+	logger.Initialize("error")
+	os.Exit(m.Run())
+}
+
+// mockUseCase is a UseCaseProvider returning canned results for tool tests
+type mockUseCase struct {
+	mu     sync.Mutex
+	dbType string
+	// results maps a substring of the query to the rows it returns
+	results map[string]*domain.QueryResult
+	// queries records every query that was executed
+	queries []string
+	// config is returned by GetDatabaseConfig
+	config domain.DatabaseConnectionConfig
+	// batches records the statements of every ExecuteBatch call
+	batches [][]string
+	// failBatch makes ExecuteBatch fail for batches containing this substring
+	failBatch string
+	// failStatement makes ExecuteStatement fail for statements containing this substring
+	failStatement string
+	// statementDelay makes ExecuteStatement take this long
+	statementDelay time.Duration
+	// notifications are the payloads Listen delivers before it returns
+	notifications []string
+	// pages maps a cursor to the rows FetchRows returns for it
+	pages map[string]*domain.QueryResult
+	// queryErr makes every ExecuteQuery call fail with this error
+	queryErr error
+	// documents is returned by GetDocumentStore; nil means the database is not a document database
+	documents domain.DocumentStore
+	// keyValues is returned by GetKeyValueStore; nil means the database is not a key-value database
+	keyValues domain.KeyValueStore
+	// search is returned by GetSearchStore; nil means the database is not a search database
+	search domain.SearchStore
+}
+
+func (m *mockUseCase) ExecuteQuery(ctx context.Context, dbID, query string, params []interface{}) (*domain.QueryResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queries = append(m.queries, query)
+	if m.queryErr != nil {
+		return nil, m.queryErr
+	}
+	for fragment, result := range m.results {
+		if strings.Contains(query, fragment) {
+			return result, nil
+		}
+	}
+	return &domain.QueryResult{}, nil
+}
+
+func (m *mockUseCase) FetchRows(ctx context.Context, dbID, cursor string, limit int) (*domain.QueryResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	page, ok := m.pages[cursor]
+	if !ok {
+		return nil, fmt.Errorf("cursor %q is unknown, fully read or expired", cursor)
+	}
+	return page, nil
+}
+
+func (m *mockUseCase) StreamQuery(ctx context.Context, dbID, query string, params []interface{}, handler domain.RowHandler) (int64, error) {
+	result, err := m.ExecuteQuery(ctx, dbID, query, params)
+	if err != nil {
+		return 0, err
+	}
+	if err := handler.Columns(result.Columns); err != nil {
+		return 0, err
+	}
+	for i, row := range result.Rows {
+		if err := handler.Row(row); err != nil {
+			return int64(i), err
+		}
+	}
+	return int64(len(result.Rows)), nil
+}
+
+func (m *mockUseCase) ExecuteStatement(ctx context.Context, dbID, statement string, params []interface{}) (string, error) {
+	time.Sleep(m.statementDelay)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queries = append(m.queries, statement)
+	if m.failStatement != "" && strings.Contains(statement, m.failStatement) {
+		return "", fmt.Errorf("statement failed: %s", statement)
+	}
+	return "Statement executed successfully.", nil
+}
+
+func (m *mockUseCase) ExecuteTransaction(ctx context.Context, dbID, action string, txID string, statement string, params []interface{}, readOnly bool) (string, map[string]interface{}, error) {
+	return "", nil, nil
+}
+
+func (m *mockUseCase) ExecuteBatch(ctx context.Context, dbID string, statements []string, params [][]interface{}) error {
+	for _, statement := range statements {
+		if m.failBatch != "" && strings.Contains(statement, m.failBatch) {
+			return fmt.Errorf("statement failed: %s", statement)
+		}
+	}
+	m.batches = append(m.batches, statements)
+	return nil
+}
+
+func (m *mockUseCase) GetDatabaseInfo(ctx context.Context, dbID string) (map[string]interface{}, error) {
+	return map[string]interface{}{"database": dbID}, nil
+}
+
+func (m *mockUseCase) ListDatabases() []string {
+	return []string{"test"}
+}
+
+func (m *mockUseCase) GetDatabaseType(dbID string) (string, error) {
+	return m.dbType, nil
+}
+
+func (m *mockUseCase) GetDatabaseConfig(dbID string) (*domain.DatabaseConnectionConfig, error) {
+	config := m.config
+	config.ID = dbID
+	return &config, nil
+}
+
+func (m *mockUseCase) GetDocumentStore(dbID string) (domain.DocumentStore, error) {
+	if m.documents == nil {
+		return nil, fmt.Errorf("database %s is not a document database", dbID)
+	}
+	return m.documents, nil
+}
+
+func (m *mockUseCase) GetKeyValueStore(dbID string) (domain.KeyValueStore, error) {
+	if m.keyValues == nil {
+		return nil, fmt.Errorf("database %s is not a key-value database", dbID)
+	}
+	return m.keyValues, nil
+}
+
+func (m *mockUseCase) GetSearchStore(dbID string) (domain.SearchStore, error) {
+	if m.search == nil {
+		return nil, fmt.Errorf("database %s is not a search database", dbID)
+	}
+	return m.search, nil
+}
+
+func (m *mockUseCase) Listen(ctx context.Context, dbID, channel string, handle func(payload string)) error {
+	for _, payload := range m.notifications {
+		handle(payload)
+	}
+	return nil
+}
@@ -0,0 +1,443 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/FreePeak/cortex/pkg/tools"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// vectorMetrics maps each distance metric to its pgvector operator and operator class suffix
+var vectorMetrics = map[string][2]string{
+	"l2":            {"<->", "_l2_ops"},
+	"cosine":        {"<=>", "_cosine_ops"},
+	"inner_product": {"<#>", "_ip_ops"},
+	"l1":            {"<+>", "_l1_ops"},
+}
+
+// vectorDimensions matches the dimensions in a pgvector type such as vector(1536)
+var vectorDimensions = regexp.MustCompile(`\((\d+)\)`)
+
+// vectorColumn is a pgvector column of the searched table
+type vectorColumn struct {
+	Name string
+	// Type is vector, halfvec or sparsevec
+	Type       string
+	Dimensions int
+}
+
+// vectorIndex is an approximate nearest neighbour index on a vector column
+type vectorIndex struct {
+	Name       string
+	Column     string
+	Method     string
+	Metric     string
+	Definition string
+}
+
+// VectorSearchTool handles similarity searches over pgvector columns
+type VectorSearchTool struct {
+	BaseToolType
+}
+
+// NewVectorSearchTool creates a new vector search tool type
+func NewVectorSearchTool() *VectorSearchTool {
+	return &VectorSearchTool{
+		BaseToolType: BaseToolType{
+			name:        "vector_search",
+			description: "Find the rows nearest to a vector in a pgvector column (vector, halfvec or sparsevec) of a PostgreSQL table. Pass the query vector, or a reference row whose own vector is searched with. The search orders by the distance operator of the metric (l2 <->, cosine <=>, inner_product <#>, l1 <+>) with a LIMIT, which lets an HNSW or IVFFlat index built for the same metric answer it. Without a vector or reference row, only reports the vector columns and their HNSW and IVFFlat indexes.",
+		},
+	}
+}
+
+// CreateTool creates a vector search tool
+func (t *VectorSearchTool) CreateTool(name string, dbID string) interface{} {
+	return tools.NewTool(
+		name,
+		tools.WithDescription("Run a pgvector similarity search and report HNSW/IVFFlat indexes"),
+		tools.WithString("database",
+			tools.Description("Database ID to use"),
+			tools.Required(),
+		),
+		tools.WithString("table",
+			tools.Description("Table holding the vectors (optionally schema-qualified)"),
+			tools.Required(),
+		),
+		tools.WithString("column",
+			tools.Description("Vector column to search (default: the table's only vector column)"),
+		),
+		tools.WithArray("vector",
+			tools.Description("Query vector as an array of numbers"),
+			tools.Items(map[string]interface{}{"type": "number"}),
+		),
+		tools.WithObject("reference",
+			tools.Description("Search with the vector of the row matching these column values instead, such as {\"id\": 42}; the row itself is left out of the results"),
+		),
+		tools.WithString("metric",
+			tools.Description("Distance metric: l2, cosine, inner_product or l1 (default: the metric of the column's index, else l2)"),
+		),
+		tools.WithArray("return_columns",
+			tools.Description("Columns to return with each match (default: all columns except the vector)"),
+			tools.Items(map[string]interface{}{"type": "string"}),
+		),
+		tools.WithArray("filters",
+			tools.Description("Extra conditions combined with AND: objects with column, operator, value or values, as in build_query"),
+			tools.Items(map[string]interface{}{"type": "object"}),
+		),
+		tools.WithNumber("limit",
+			tools.Description("Number of nearest rows to return (default: 10, max: 1000)"),
+		),
+	)
+}
+
+// HandleRequest handles vector search tool requests
+func (t *VectorSearchTool) HandleRequest(ctx context.Context, request server.ToolCallRequest, dbID string, useCase UseCaseProvider) (interface{}, error) {
+	input := newToolParams(request.Parameters)
+	targetDbID := input.requiredString("database")
+	tableName := input.requiredString("table")
+	columnName := input.optionalString("column", "")
+	rawVector := input.list("vector")
+	reference := input.object("reference")
+	metric := input.choice("metric", "", "l2", "cosine", "inner_product", "l1")
+	returnColumns := input.stringList("return_columns")
+	limit := input.intBetween("limit", 10, 1, 1000)
+	if err := input.err(); err != nil {
+		return nil, err
+	}
+	if rawVector != nil && reference != nil {
+		return nil, fmt.Errorf("pass either vector or reference, not both")
+	}
+	vector, err := parseVector(rawVector)
+	if err != nil {
+		return nil, err
+	}
+	filters, err := parseQueryFilters(input.raw("filters"))
+	if err != nil {
+		return nil, err
+	}
+
+	dbType, err := useCase.GetDatabaseType(targetDbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database type: %w", err)
+	}
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
+		return nil, fmt.Errorf("vector_search needs PostgreSQL with the pgvector extension, not %s", dbType)
+	}
+	searcher, err := dialect.VectorSearcher()
+	if err != nil {
+		return nil, fmt.Errorf("vector_search needs PostgreSQL with the pgvector extension, not %s", dbType)
+	}
+
+	schema, table := splitQualifiedName(tableName)
+	columns, others, indexes, err := searcher.loadColumns(ctx, useCase, targetDbID, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 && len(others) == 0 {
+		return nil, fmt.Errorf("table %s not found", tableName)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s has no vector, halfvec or sparsevec columns; is the pgvector extension installed?", tableName)
+	}
+	if len(returnColumns) == 0 {
+		// Vectors are long and say little to a reader, so they are left out by default
+		returnColumns = others
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("# Vector Search on %s for Database %s\n\n", tableName, targetDbID))
+	writeVectorColumns(&response, columns, indexes)
+
+	if vector == nil && reference == nil {
+		return createTextResponse(response.String()), nil
+	}
+
+	column, err := pickVectorColumn(columns, columnName)
+	if err != nil {
+		return nil, err
+	}
+	if vector != nil && column.Dimensions > 0 && len(vector) != column.Dimensions {
+		return nil, fmt.Errorf("vector has %d dimensions but column %s holds %d", len(vector), column.Name, column.Dimensions)
+	}
+	var columnIndexes []vectorIndex
+	for _, index := range indexes {
+		if index.Column == column.Name {
+			columnIndexes = append(columnIndexes, index)
+		}
+	}
+	if metric == "" {
+		metric = "l2"
+		if len(columnIndexes) > 0 && columnIndexes[0].Metric != "" {
+			metric = columnIndexes[0].Metric
+		}
+	}
+
+	query, params := searcher.searchQuery(tableName, column, metric, vector, reference, returnColumns, filters, limit)
+	logger.Info("Running vector search on %s.%s of database %s with %s distance", tableName, column.Name, targetDbID, metric)
+	result, err := useCase.ExecuteQuery(ctx, targetDbID, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run vector search: %w", err)
+	}
+
+	response.WriteString(fmt.Sprintf("\n## Nearest Rows\n\n```sql\n%s\n```\n\n", query))
+	response.WriteString(formatQueryResult(result))
+	if len(result.Rows) == 0 && reference != nil {
+		response.WriteString("\nNo rows found; check that the reference row exists and its vector is not NULL.\n")
+	}
+
+	var warnings []string
+	served := false
+	for _, index := range columnIndexes {
+		served = served || index.Metric == metric
+	}
+	switch {
+	case !served:
+		warnings = append(warnings, fmt.Sprintf("No HNSW or IVFFlat index on %s serves %s distance, so the search compares every row exactly. Create one with: %s",
+			column.Name, metric, searcher.indexStatement(tableName, column, metric)))
+	case len(filters) > 0:
+		warnings = append(warnings, "Filters apply after the approximate index scan, so fewer rows than limit may come back; raise hnsw.ef_search or ivfflat.probes, or enable hnsw.iterative_scan on pgvector 0.8+.")
+	}
+	if metric == "inner_product" {
+		warnings = append(warnings, "pgvector's <#> returns the negative inner product, so the most similar rows have the lowest distance.")
+	}
+	if len(warnings) > 0 {
+		response.WriteString("\n## Warnings\n\n")
+		for _, w := range warnings {
+			response.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+	return createTextResponse(response.String()), nil
+}
+
+// vectorSearcher finds the vector columns of a table and the rows nearest to a vector
+type vectorSearcher interface {
+	// loadColumns reads the vector columns, the other columns and the vector indexes of a table
+	loadColumns(ctx context.Context, useCase UseCaseProvider, dbID, schema, table string) ([]vectorColumn, []string, []vectorIndex, error)
+	// searchQuery renders the nearest neighbour query
+	searchQuery(table string, column vectorColumn, metric string, vector []float64, reference map[string]interface{},
+		returnColumns []string, filters []queryFilter, limit int) (string, []interface{})
+	// indexStatement builds the statement creating an index that serves the metric
+	indexStatement(table string, column vectorColumn, metric string) string
+}
+
+// pgvectorSearcher searches the vector, halfvec and sparsevec columns of the pgvector extension
+type pgvectorSearcher struct{}
+
+func (pgvectorSearcher) loadColumns(ctx context.Context, useCase UseCaseProvider, dbID, schema, table string) ([]vectorColumn, []string, []vectorIndex, error) {
+	return loadVectorColumns(ctx, useCase, dbID, schema, table)
+}
+
+func (pgvectorSearcher) searchQuery(table string, column vectorColumn, metric string, vector []float64, reference map[string]interface{},
+	returnColumns []string, filters []queryFilter, limit int) (string, []interface{}) {
+	return buildVectorSearch(table, column, metric, vector, reference, returnColumns, filters, limit)
+}
+
+func (pgvectorSearcher) indexStatement(table string, column vectorColumn, metric string) string {
+	return fmt.Sprintf("CREATE INDEX ON %s USING hnsw (%s %s%s)",
+		quoteIdentifier("postgres", table), quoteIdentifier("postgres", column.Name), column.Type, vectorMetrics[metric][1])
+}
+
+// parseVector converts a vector parameter into numbers
+func parseVector(raw []interface{}) ([]float64, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("vector parameter must not be empty")
+	}
+	vector := make([]float64, len(raw))
+	for i, item := range raw {
+		value, ok := item.(float64)
+		if !ok {
+			return nil, fmt.Errorf("vector item %d must be a number", i+1)
+		}
+		vector[i] = value
+	}
+	return vector, nil
+}
+
+// vectorLiteral renders a vector in the text format of a pgvector type: [1,2,3] for vector and
+// halfvec, {1:1,3:2}/3 with the non-zero elements for sparsevec
+func vectorLiteral(vector []float64, kind string) string {
+	var parts []string
+	for i, value := range vector {
+		text := strconv.FormatFloat(value, 'g', -1, 64)
+		if kind != "sparsevec" {
+			parts = append(parts, text)
+		} else if value != 0 {
+			parts = append(parts, fmt.Sprintf("%d:%s", i+1, text))
+		}
+	}
+	if kind == "sparsevec" {
+		return fmt.Sprintf("{%s}/%d", strings.Join(parts, ","), len(vector))
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+
+// loadVectorColumns reads the columns of a table, split into pgvector columns and the names
+// of the others, and the HNSW and IVFFlat indexes on the vector columns
+func loadVectorColumns(ctx context.Context, useCase UseCaseProvider, dbID, schema, table string) ([]vectorColumn, []string, []vectorIndex, error) {
+	result, err := useCase.ExecuteQuery(ctx, dbID, `
+SELECT a.attname, t.typname, format_type(a.atttypid, a.atttypmod)
+FROM pg_attribute a
+JOIN pg_class c ON c.oid = a.attrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_type t ON t.oid = a.atttypid
+WHERE a.attnum > 0 AND NOT a.attisdropped
+  AND n.nspname = COALESCE(NULLIF($1, ''), current_schema()) AND c.relname = $2
+ORDER BY a.attnum`, []interface{}{schema, table})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to list columns: %w", err)
+	}
+	var columns []vectorColumn
+	var others []string
+	for _, row := range result.Rows {
+		if len(row) < 3 {
+			continue
+		}
+		column := vectorColumn{Name: valueString(row[0]), Type: valueString(row[1])}
+		if column.Type != "vector" && column.Type != "halfvec" && column.Type != "sparsevec" {
+			others = append(others, column.Name)
+			continue
+		}
+		if match := vectorDimensions.FindStringSubmatch(valueString(row[2])); match != nil {
+			column.Dimensions, _ = strconv.Atoi(match[1])
+		}
+		columns = append(columns, column)
+	}
+
+	result, err = useCase.ExecuteQuery(ctx, dbID, `
+SELECT i.relname, a.attname, am.amname, opc.opcname, pg_get_indexdef(i.oid)
+FROM pg_index x
+JOIN pg_class i ON i.oid = x.indexrelid
+JOIN pg_class c ON c.oid = x.indrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_am am ON am.oid = i.relam
+JOIN pg_opclass opc ON opc.oid = x.indclass[0]
+JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = x.indkey[0]
+WHERE am.amname IN ('hnsw', 'ivfflat')
+  AND n.nspname = COALESCE(NULLIF($1, ''), current_schema()) AND c.relname = $2
+ORDER BY i.relname`, []interface{}{schema, table})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to list vector indexes: %w", err)
+	}
+	var indexes []vectorIndex
+	for _, row := range result.Rows {
+		if len(row) < 5 {
+			continue
+		}
+		index := vectorIndex{Name: valueString(row[0]), Column: valueString(row[1]), Method: valueString(row[2]), Definition: valueString(row[4])}
+		for metric, spec := range vectorMetrics {
+			if strings.HasSuffix(valueString(row[3]), spec[1]) {
+				index.Metric = metric
+			}
+		}
+		indexes = append(indexes, index)
+	}
+	return columns, others, indexes, nil
+}
+
+// pickVectorColumn returns the searched column: the named one, or the only vector column
+func pickVectorColumn(columns []vectorColumn, name string) (vectorColumn, error) {
+	if name == "" {
+		if len(columns) > 1 {
+			names := make([]string, len(columns))
+			for i, column := range columns {
+				names[i] = column.Name
+			}
+			return vectorColumn{}, fmt.Errorf("table has several vector columns (%s); pass column to pick one", strings.Join(names, ", "))
+		}
+		return columns[0], nil
+	}
+	for _, column := range columns {
+		if column.Name == name {
+			return column, nil
+		}
+	}
+	return vectorColumn{}, fmt.Errorf("column %s is not a vector column of the table", name)
+}
+
+// buildVectorSearch renders the nearest neighbour query. It orders by the distance expression
+// itself with a LIMIT, the shape pgvector indexes answer.
+func buildVectorSearch(table string, column vectorColumn, metric string, vector []float64, reference map[string]interface{},
+	returnColumns []string, filters []queryFilter, limit int) (string, []interface{}) {
+	postgres := dialectFor("postgres")
+	params := newSQLParams("postgres")
+	quotedTable := quoteIdentifier("postgres", table)
+	quotedColumn := quoteIdentifier("postgres", column.Name)
+
+	var conditions []string
+	var target string
+	if vector != nil {
+		target = fmt.Sprintf("%s::%s", params.add(vectorLiteral(vector, column.Type)), column.Type)
+	} else {
+		keys := make([]string, 0, len(reference))
+		for key := range reference {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		matches := make([]string, len(keys))
+		for i, key := range keys {
+			matches[i] = fmt.Sprintf("%s = %s", quoteIdentifier("postgres", key), params.add(reference[key]))
+		}
+		match := strings.Join(matches, " AND ")
+		target = fmt.Sprintf("(SELECT %s FROM %s WHERE %s %s)", quotedColumn, quotedTable, match, postgres.LimitClause(1))
+		conditions = append(conditions, fmt.Sprintf("NOT (%s)", match))
+	}
+	if where := buildWhereClause(filters, params); where != "" {
+		conditions = append(conditions, where)
+	}
+
+	list := quotedTable + ".*"
+	if len(returnColumns) > 0 {
+		quoted := make([]string, len(returnColumns))
+		for i, name := range returnColumns {
+			quoted[i] = quoteIdentifier("postgres", name)
+		}
+		list = strings.Join(quoted, ", ")
+	}
+
+	distance := fmt.Sprintf("%s %s %s", quotedColumn, vectorMetrics[metric][0], target)
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("SELECT %s, %s AS distance\nFROM %s", list, distance, quotedTable))
+	if len(conditions) > 0 {
+		sb.WriteString("\nWHERE " + strings.Join(conditions, " AND "))
+	}
+	sb.WriteString(fmt.Sprintf("\nORDER BY %s\n%s", distance, postgres.LimitClause(limit)))
+	return sb.String(), params.values
+}
+
+// writeVectorColumns renders the vector columns of the table and their indexes
+func writeVectorColumns(sb *strings.Builder, columns []vectorColumn, indexes []vectorIndex) {
+	sb.WriteString("| Column | Type | Dimensions |\n")
+	sb.WriteString("|--------|------|------------|\n")
+	for _, column := range columns {
+		dimensions := "any"
+		if column.Dimensions > 0 {
+			dimensions = strconv.Itoa(column.Dimensions)
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", column.Name, column.Type, dimensions))
+	}
+
+	sb.WriteString("\n## Indexes\n\n")
+	if len(indexes) == 0 {
+		sb.WriteString("No HNSW or IVFFlat indexes; searches compare every row exactly.\n")
+		return
+	}
+	sb.WriteString("| Index | Column | Method | Metric | Definition |\n")
+	sb.WriteString("|-------|--------|--------|--------|------------|\n")
+	for _, index := range indexes {
+		metric := index.Metric
+		if metric == "" {
+			metric = "unknown"
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", index.Name, index.Column, index.Method, metric, index.Definition))
+	}
+}
@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FreePeak/cortex/pkg/server"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+func vectorUseCase(indexes [][]interface{}) *mockUseCase {
+	return &mockUseCase{
+		dbType: "postgres",
+		results: map[string]*domain.QueryResult{
+			"format_type": {Rows: [][]interface{}{
+				{"id", "int8", "bigint"},
+				{"title", "text", "text"},
+				{"embedding", "vector", "vector(3)"},
+			}},
+			"pg_opclass":  {Rows: indexes},
+			"AS distance": {Columns: []string{"id", "title", "distance"}, Rows: [][]interface{}{{int64(7), "pooling", 0.12}}},
+		},
+	}
+}
+
+func vectorSearchText(t *testing.T, useCase *mockUseCase, params map[string]interface{}) string {
+	params["database"] = "db1"
+	params["table"] = "docs"
+	result, err := NewVectorSearchTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+	assert.NoError(t, err)
+	return result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+}
+
+func TestVectorSearchWithVector(t *testing.T) {
+	useCase := vectorUseCase([][]interface{}{
+		{"docs_embedding_idx", "embedding", "hnsw", "vector_cosine_ops", "CREATE INDEX docs_embedding_idx ON public.docs USING hnsw (embedding vector_cosine_ops)"},
+	})
+
+	text := vectorSearchText(t, useCase, map[string]interface{}{"vector": []interface{}{0.1, 0.2, 0.3}, "limit": float64(5)})
+	// The metric defaults to the one the index serves
+	assert.Contains(t, useCase.queries, `SELECT "id", "title", "embedding" <=> $1::vector AS distance
+FROM "docs"
+ORDER BY "embedding" <=> $1::vector
+LIMIT 5`)
+	assert.Contains(t, text, "| embedding | vector | 3 |")
+	assert.Contains(t, text, "| docs_embedding_idx | embedding | hnsw | cosine |")
+	assert.Contains(t, text, "7\tpooling\t0.12")
+	assert.NotContains(t, text, "## Warnings")
+
+	text = vectorSearchText(t, useCase, map[string]interface{}{"vector": []interface{}{0.1, 0.2, 0.3}, "metric": "l2"})
+	assert.Contains(t, text, `No HNSW or IVFFlat index on embedding serves l2 distance`)
+	assert.Contains(t, text, `CREATE INDEX ON "docs" USING hnsw ("embedding" vector_l2_ops)`)
+}
+
+func TestVectorSearchWithReference(t *testing.T) {
+	useCase := vectorUseCase(nil)
+
+	text := vectorSearchText(t, useCase, map[string]interface{}{
+		"reference": map[string]interface{}{"id": float64(42)},
+		"filters":   []interface{}{map[string]interface{}{"column": "title", "operator": "is not null"}},
+	})
+	assert.Contains(t, useCase.queries, `SELECT "id", "title", "embedding" <-> (SELECT "embedding" FROM "docs" WHERE "id" = $1 LIMIT 1) AS distance
+FROM "docs"
+WHERE NOT ("id" = $1) AND "title" IS NOT NULL
+ORDER BY "embedding" <-> (SELECT "embedding" FROM "docs" WHERE "id" = $1 LIMIT 1)
+LIMIT 10`)
+	assert.Contains(t, text, "No HNSW or IVFFlat indexes")
+}
+
+func TestVectorSearchRejectsInvalidInput(t *testing.T) {
+	useCase := vectorUseCase(nil)
+	request := func(params map[string]interface{}) error {
+		params["database"] = "db1"
+		params["table"] = "docs"
+		_, err := NewVectorSearchTool().HandleRequest(context.Background(), server.ToolCallRequest{Parameters: params}, "", useCase)
+		return err
+	}
+
+	assert.EqualError(t, request(map[string]interface{}{"vector": []interface{}{0.1, 0.2}}), "vector has 2 dimensions but column embedding holds 3")
+	assert.Error(t, request(map[string]interface{}{"vector": []interface{}{"a"}}))
+	assert.Error(t, request(map[string]interface{}{"vector": []interface{}{0.1}, "reference": map[string]interface{}{"id": 1}}))
+
+	useCase.dbType = "mysql"
+	assert.ErrorContains(t, request(map[string]interface{}{}), "needs PostgreSQL")
+}
+
+func TestVectorLiteral(t *testing.T) {
+	assert.Equal(t, "[1,0,2.5]", vectorLiteral([]float64{1, 0, 2.5}, "vector"))
+	assert.Equal(t, "{1:1,3:2.5}/3", vectorLiteral([]float64{1, 0, 2.5}, "sparsevec"))
+}
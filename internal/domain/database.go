@@ -2,6 +2,8 @@ package domain
 
 import (
 	"context"
+	"fmt"
+	"time"
 )
 
 // Database represents a database connection and operations
@@ -20,6 +22,12 @@ type Rows interface {
 	Err() error
 }
 
+// ColumnTyper is implemented by rows that know the database types of their columns
+type ColumnTyper interface {
+	// ColumnTypes returns the database type name of each column, such as BYTEA or VARCHAR
+	ColumnTypes() ([]string, error)
+}
+
 // Result represents the result of a database operation
 type Result interface {
 	RowsAffected() (int64, error)
@@ -34,6 +42,93 @@ type Tx interface {
 	Exec(ctx context.Context, statement string, args ...interface{}) (Result, error)
 }
 
+// QueryResult represents the rows returned by a query in a driver-independent form
+type QueryResult struct {
+	Columns []string
+	Rows    [][]interface{}
+
+	// Cursor names the rows left out of Rows because the result outgrew its memory limit;
+	// they were spilled to disk and are read with FetchRows. Remaining counts them.
+	Cursor    string
+	Remaining int64
+}
+
+// Geometry is a value of a PostGIS or MySQL spatial column, decoded from the well-known
+// binary the database returns
+type Geometry struct {
+	// SRID is the spatial reference system of the coordinates; zero when unknown
+	SRID int
+	// WKT is the geometry in well-known text, such as POINT(1 2)
+	WKT string
+	// GeoJSON is the geometry as a GeoJSON geometry object
+	GeoJSON string
+}
+
+// String returns the geometry in extended well-known text, prefixed by its SRID when known
+func (g Geometry) String() string {
+	if g.SRID == 0 {
+		return g.WKT
+	}
+	return fmt.Sprintf("SRID=%d;%s", g.SRID, g.WKT)
+}
+
+// MarshalJSON renders the geometry as its GeoJSON object
+func (g Geometry) MarshalJSON() ([]byte, error) {
+	return []byte(g.GeoJSON), nil
+}
+
+// RowHandler receives the rows of a streamed query; Columns is called once before the first row
+type RowHandler interface {
+	Columns(columns []string) error
+	Row(values []interface{}) error
+}
+
+// Listener is implemented by databases that deliver asynchronous notifications; an empty
+// payload means notifications may have been lost
+type Listener interface {
+	Listen(ctx context.Context, channel string, handle func(payload string)) error
+}
+
+// DocumentStore is implemented by document databases such as MongoDB, whose collections hold
+// documents instead of rows. Documents are in canonical Extended JSON, which keeps BSON types
+// apart: {"_id": {"$oid": "..."}, "qty": {"$numberInt": "5"}}.
+type DocumentStore interface {
+	// ListCollections returns the name, type, options and info of every collection
+	ListCollections(ctx context.Context) ([]map[string]interface{}, error)
+	// SampleDocuments returns up to size documents of a collection picked at random
+	SampleDocuments(ctx context.Context, collection string, size int) ([]map[string]interface{}, error)
+	// CollectionStats returns the collStats output of a collection
+	CollectionStats(ctx context.Context, collection string) (map[string]interface{}, error)
+	// ListIndexes returns the index specifications of a collection
+	ListIndexes(ctx context.Context, collection string) ([]map[string]interface{}, error)
+	// Aggregate runs a pipeline, a JSON array of stages, on a collection, or on the database
+	// when collection is empty, and returns up to limit documents
+	Aggregate(ctx context.Context, collection, pipeline string, limit int) ([]map[string]interface{}, error)
+}
+
+// KeyValueStore is implemented by key-value databases such as Redis, which hold keys instead
+// of tables
+type KeyValueStore interface {
+	// Command sends a command, such as TYPE user:1, and returns its reply: a string, an int64,
+	// nil for a null reply or []interface{} for an array. An error reply is returned as an error.
+	Command(ctx context.Context, args ...string) (interface{}, error)
+}
+
+// SearchStore is implemented by search engines such as Elasticsearch and OpenSearch, whose
+// indices hold JSON documents. Responses are the decoded JSON of the REST API, with numbers as
+// json.Number so large integers keep their precision.
+type SearchStore interface {
+	// ListIndices returns the _cat/indices rows of the indices matching a pattern such as
+	// logs-*; hidden indices are only listed when includeHidden is set
+	ListIndices(ctx context.Context, pattern string, includeHidden bool) ([]map[string]interface{}, error)
+	// Mappings returns the mappings of the indices matching a pattern, by index name
+	Mappings(ctx context.Context, pattern string) (map[string]interface{}, error)
+	// IndexStats returns the _stats output of the indices matching a pattern
+	IndexStats(ctx context.Context, pattern string) (map[string]interface{}, error)
+	// Search runs a query DSL request body on the indices matching a pattern
+	Search(ctx context.Context, pattern, body string) (map[string]interface{}, error)
+}
+
 // TxOptions represents options for starting a transaction
 type TxOptions struct {
 	ReadOnly bool
@@ -116,6 +211,58 @@ type DatabaseConnectionConfig struct {
 	Password    string
 	Name        string
 	Description string
+
+	MigrationsDir string
+	SnapshotsDir  string
+	BackupsDir    string
+
+	AllowAdmin bool
+
+	// QueryTimeout bounds every query and statement run for a request; zero means no limit
+	QueryTimeout time.Duration
+
+	// SchemaCacheTTL is how long schema metadata stays cached; zero selects the default and a negative value disables caching
+	SchemaCacheTTL time.Duration
+
+	// WarmSchemaCache makes a background crawler keep the schema cache filled
+	WarmSchemaCache bool
+
+	// SchemaChangeEvents makes the server listen for schema changes published by event triggers
+	SchemaChangeEvents bool
+
+	// ResultMemoryLimit is how many bytes of rows a query result keeps in memory before spilling
+	// the rest to disk; zero selects the default and a negative value keeps every row in memory
+	ResultMemoryLimit int64
+
+	// MaxConcurrentTools bounds the tool calls running at once on the database; zero selects
+	// the default and a negative value removes the limit
+	MaxConcurrentTools int
+
+	// CircuitBreakerFailures is how many connection failures in a row make tool
+	// calls on the database fail fast; zero selects the default and a negative value disables it
+	CircuitBreakerFailures int
+
+	// CircuitBreakerCooldown is how long tool calls fail fast before one probes the database
+	CircuitBreakerCooldown time.Duration
+
+	// Timezone is the IANA time zone timestamps of query results are converted to; empty
+	// means UTC
+	Timezone string
+
+	// SessionBudget caps what a single MCP session may read from the database
+	SessionBudget SessionBudget
+}
+
+// SessionBudget limits the rows, bytes and query time one MCP session may consume; zero fields mean no limit
+type SessionBudget struct {
+	MaxRows      int64
+	MaxBytes     int64
+	MaxQueryTime time.Duration
+}
+
+// Enabled reports whether any limit is set
+func (b SessionBudget) Enabled() bool {
+	return b.MaxRows > 0 || b.MaxBytes > 0 || b.MaxQueryTime > 0
 }
 
 // DatabaseRepository defines methods for managing database connections
@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/FreePeak/db-mcp-server/internal/domain"
+	"github.com/FreePeak/db-mcp-server/pkg/db"
 	"github.com/FreePeak/db-mcp-server/pkg/dbtools"
 )
 
@@ -25,11 +27,20 @@ func NewDatabaseRepository() *DatabaseRepository {
 
 // GetDatabase retrieves a database by ID
 func (r *DatabaseRepository) GetDatabase(id string) (domain.Database, error) {
-	db, err := dbtools.GetDatabase(id)
+	database, err := dbtools.GetDatabase(id)
 	if err != nil {
 		return nil, err
 	}
-	return &DatabaseAdapter{db: db}, nil
+	if store, ok := database.(db.DocumentStore); ok {
+		return &DocumentDatabaseAdapter{DatabaseAdapter: DatabaseAdapter{db: database}, DocumentStore: store}, nil
+	}
+	if store, ok := database.(db.KeyValueStore); ok {
+		return &KeyValueDatabaseAdapter{DatabaseAdapter: DatabaseAdapter{db: database}, KeyValueStore: store}, nil
+	}
+	if store, ok := database.(db.SearchStore); ok {
+		return &SearchDatabaseAdapter{DatabaseAdapter: DatabaseAdapter{db: database}, SearchStore: store}, nil
+	}
+	return &DatabaseAdapter{db: database}, nil
 }
 
 // ListDatabases returns a list of available database IDs
@@ -37,27 +48,14 @@ func (r *DatabaseRepository) ListDatabases() []string {
 	return dbtools.ListDatabases()
 }
 
-// GetDatabaseType returns the type of a database by ID
+// GetDatabaseType returns the type of a database by ID. It is read from the configuration,
+// which names the same types the drivers report, so a lazily opened pool stays closed.
 func (r *DatabaseRepository) GetDatabaseType(id string) (string, error) {
-	// Get the database connection to check its actual driver
-	db, err := dbtools.GetDatabase(id)
+	dbType, err := dbtools.GetDatabaseType(id)
 	if err != nil {
-		return "", fmt.Errorf("failed to get database connection for type detection: %w", err)
-	}
-
-	// Use the actual driver name to determine database type
-	driverName := db.DriverName()
-
-	switch driverName {
-	case "postgres":
-		return "postgres", nil
-	case "mysql":
-		return "mysql", nil
-	default:
-		// Unknown database type - return the actual driver name and let the caller handle it
-		// Never default to MySQL as that can cause SQL dialect issues
-		return driverName, nil
+		return "", fmt.Errorf("failed to get database type: %w", err)
 	}
+	return dbType, nil
 }
 
 // GetDatabaseConfig returns the configuration for a database by ID
@@ -78,6 +76,27 @@ func (r *DatabaseRepository) GetDatabaseConfig(id string) (*domain.DatabaseConne
 		Password:    config.Password,
 		Name:        config.Name,
 		Description: config.Description,
+
+		MigrationsDir:  config.MigrationsDir,
+		SnapshotsDir:   config.SnapshotsDir,
+		BackupsDir:     config.BackupsDir,
+		AllowAdmin:     config.AllowAdmin,
+		QueryTimeout:   time.Duration(config.QueryTimeout) * time.Second,
+		SchemaCacheTTL: time.Duration(config.SchemaCacheTTL) * time.Second,
+		SessionBudget: domain.SessionBudget{
+			MaxRows:      config.SessionMaxRows,
+			MaxBytes:     config.SessionMaxBytes,
+			MaxQueryTime: time.Duration(config.SessionMaxQuerySeconds) * time.Second,
+		},
+		WarmSchemaCache:    config.WarmSchemaCache,
+		SchemaChangeEvents: config.SchemaChangeEvents,
+		ResultMemoryLimit:  config.ResultMemoryLimit,
+		MaxConcurrentTools: config.MaxConcurrentTools,
+
+		CircuitBreakerFailures: config.CircuitBreakerFailures,
+		CircuitBreakerCooldown: time.Duration(config.CircuitBreakerCooldown) * time.Second,
+
+		Timezone: config.Timezone,
 	}, nil
 }
 
@@ -122,6 +141,33 @@ func (a *DatabaseAdapter) Begin(ctx context.Context, opts *domain.TxOptions) (do
 	return &TxAdapter{tx: tx}, nil
 }
 
+// Listen subscribes to notifications when the underlying database supports them
+func (a *DatabaseAdapter) Listen(ctx context.Context, channel string, handle func(payload string)) error {
+	listener, ok := a.db.(db.Listener)
+	if !ok {
+		return fmt.Errorf("database does not support notifications")
+	}
+	return listener.Listen(ctx, channel, handle)
+}
+
+// DocumentDatabaseAdapter adapts a document database; it also implements domain.DocumentStore
+type DocumentDatabaseAdapter struct {
+	DatabaseAdapter
+	db.DocumentStore
+}
+
+// KeyValueDatabaseAdapter adapts a key-value database; it also implements domain.KeyValueStore
+type KeyValueDatabaseAdapter struct {
+	DatabaseAdapter
+	db.KeyValueStore
+}
+
+// SearchDatabaseAdapter adapts a search database; it also implements domain.SearchStore
+type SearchDatabaseAdapter struct {
+	DatabaseAdapter
+	db.SearchStore
+}
+
 // RowsAdapter adapts sql.Rows to domain.Rows
 type RowsAdapter struct {
 	rows *sql.Rows
@@ -137,6 +183,19 @@ func (a *RowsAdapter) Columns() ([]string, error) {
 	return a.rows.Columns()
 }
 
+// ColumnTypes returns the database type names of the columns
+func (a *RowsAdapter) ColumnTypes() ([]string, error) {
+	columnTypes, err := a.rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(columnTypes))
+	for i, columnType := range columnTypes {
+		names[i] = columnType.DatabaseTypeName()
+	}
+	return names, nil
+}
+
 // Next advances to the next row
 func (a *RowsAdapter) Next() bool {
 	return a.rows.Next()
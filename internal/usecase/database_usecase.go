@@ -2,8 +2,10 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/FreePeak/db-mcp-server/internal/domain"
@@ -47,6 +49,79 @@ func (f *MySQLQueryFactory) GetTablesQueries() []string {
 	}
 }
 
+// SQLiteQueryFactory creates queries for SQLite
+type SQLiteQueryFactory struct{}
+
+func (f *SQLiteQueryFactory) GetTablesQueries() []string {
+	return []string{
+		"SELECT name AS table_name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'",
+	}
+}
+
+// ClickHouseQueryFactory creates queries for ClickHouse
+type ClickHouseQueryFactory struct{}
+
+func (f *ClickHouseQueryFactory) GetTablesQueries() []string {
+	return []string{
+		"SELECT name AS table_name FROM system.tables WHERE database = currentDatabase() AND NOT is_temporary",
+	}
+}
+
+// BigQueryQueryFactory creates queries for BigQuery
+type BigQueryQueryFactory struct{}
+
+func (f *BigQueryQueryFactory) GetTablesQueries() []string {
+	return []string{
+		"SELECT table_name FROM INFORMATION_SCHEMA.TABLES",
+	}
+}
+
+// SpannerQueryFactory creates queries for Spanner
+type SpannerQueryFactory struct{}
+
+func (f *SpannerQueryFactory) GetTablesQueries() []string {
+	return []string{
+		"SELECT table_name FROM INFORMATION_SCHEMA.TABLES WHERE table_schema = '' AND table_type = 'BASE TABLE'",
+	}
+}
+
+// HANAQueryFactory creates queries for SAP HANA
+type HANAQueryFactory struct{}
+
+func (f *HANAQueryFactory) GetTablesQueries() []string {
+	return []string{
+		"SELECT TABLE_NAME AS table_name FROM SYS.TABLES WHERE SCHEMA_NAME = CURRENT_SCHEMA",
+	}
+}
+
+// FirebirdQueryFactory creates queries for Firebird
+type FirebirdQueryFactory struct{}
+
+func (f *FirebirdQueryFactory) GetTablesQueries() []string {
+	return []string{
+		"SELECT TRIM(RDB$RELATION_NAME) AS table_name FROM RDB$RELATIONS WHERE COALESCE(RDB$SYSTEM_FLAG, 0) = 0 AND RDB$VIEW_BLR IS NULL",
+	}
+}
+
+// TrinoQueryFactory creates queries for Trino
+type TrinoQueryFactory struct{}
+
+func (f *TrinoQueryFactory) GetTablesQueries() []string {
+	return []string{
+		"SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema",
+	}
+}
+
+// DatabricksQueryFactory creates queries for Databricks
+type DatabricksQueryFactory struct{}
+
+func (f *DatabricksQueryFactory) GetTablesQueries() []string {
+	return []string{
+		"SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema()",
+		"SHOW TABLES",
+	}
+}
+
 // GenericQueryFactory creates generic queries for unknown database types
 type GenericQueryFactory struct{}
 
@@ -60,10 +135,27 @@ func (f *GenericQueryFactory) GetTablesQueries() []string {
 // NewQueryFactory creates the appropriate query factory for the database type
 func NewQueryFactory(dbType string) QueryFactory {
 	switch dbType {
-	case "postgres":
+	case "postgres", "cockroachdb", "yugabyte", "greenplum":
+		// CockroachDB, YugabyteDB and Greenplum serve the PostgreSQL catalog tables
 		return &PostgresQueryFactory{}
-	case "mysql":
+	case "mysql", "tidb":
 		return &MySQLQueryFactory{}
+	case "sqlite":
+		return &SQLiteQueryFactory{}
+	case "clickhouse":
+		return &ClickHouseQueryFactory{}
+	case "bigquery":
+		return &BigQueryQueryFactory{}
+	case "spanner":
+		return &SpannerQueryFactory{}
+	case "hana":
+		return &HANAQueryFactory{}
+	case "firebird":
+		return &FirebirdQueryFactory{}
+	case "trino":
+		return &TrinoQueryFactory{}
+	case "databricks":
+		return &DatabricksQueryFactory{}
 	default:
 		logger.Warn("Unknown database type: %s, will use generic query factory", dbType)
 		return &GenericQueryFactory{}
@@ -106,8 +198,53 @@ func (uc *DatabaseUseCase) ListDatabases() []string {
 	return uc.repo.ListDatabases()
 }
 
+// withQueryDeadline bounds ctx by the query timeout of the connection. A deadline the caller
+// already set, such as the one of the MCP request, is kept when it is earlier.
+func (uc *DatabaseUseCase) withQueryDeadline(ctx context.Context, dbID string) (context.Context, context.CancelFunc) {
+	config, err := uc.repo.GetDatabaseConfig(dbID)
+	if err != nil || config == nil || config.QueryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= config.QueryTimeout {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, config.QueryTimeout)
+}
+
+// resultLocations caches the time zones of connections by name, since loading one reads the
+// zone database
+var resultLocations sync.Map
+
+// resultLocation returns the time zone the timestamps of query results on a database are
+// converted to: the zone the connection configures, or UTC
+func (uc *DatabaseUseCase) resultLocation(dbID string) *time.Location {
+	config, err := uc.repo.GetDatabaseConfig(dbID)
+	if err != nil || config == nil || config.Timezone == "" {
+		return time.UTC
+	}
+	if location, ok := resultLocations.Load(config.Timezone); ok {
+		return location.(*time.Location)
+	}
+	location, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		logger.Warn("Unknown timezone %q of database %s, using UTC: %v", config.Timezone, dbID, err)
+		location = time.UTC
+	}
+	resultLocations.Store(config.Timezone, location)
+	return location
+}
+
+// contextError marks a driver error caused by a cancelled or expired context, so callers can
+// tell timeouts apart with errors.Is even when the driver reports its own error
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil && !errors.Is(err, ctxErr) {
+		return fmt.Errorf("%w: %v", ctxErr, err)
+	}
+	return err
+}
+
 // GetDatabaseInfo returns information about a database
-func (uc *DatabaseUseCase) GetDatabaseInfo(dbID string) (map[string]interface{}, error) {
+func (uc *DatabaseUseCase) GetDatabaseInfo(ctx context.Context, dbID string) (map[string]interface{}, error) {
 	// Get database connection
 	db, err := uc.repo.GetDatabase(dbID)
 	if err != nil {
@@ -126,6 +263,72 @@ func (uc *DatabaseUseCase) GetDatabaseInfo(dbID string) (map[string]interface{},
 		description = dbConfig.Description
 	}
 
+	// A document database lists its collections in place of tables
+	if store, ok := db.(domain.DocumentStore); ok {
+		ctx, cancel := uc.withQueryDeadline(ctx, dbID)
+		defer cancel()
+		collections, err := store.ListCollections(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list collections: %w", contextError(ctx, err))
+		}
+		tables := make([]map[string]interface{}, 0, len(collections))
+		for _, collection := range collections {
+			tables = append(tables, map[string]interface{}{"name": collection["name"], "type": collection["type"]})
+		}
+		result := map[string]interface{}{
+			"database": dbID,
+			"dbType":   dbType,
+			"tables":   tables,
+		}
+		if description != "" {
+			result["description"] = description
+		}
+		return result, nil
+	}
+
+	// A search database lists its indices in place of tables
+	if store, ok := db.(domain.SearchStore); ok {
+		ctx, cancel := uc.withQueryDeadline(ctx, dbID)
+		defer cancel()
+		indices, err := store.ListIndices(ctx, "*", false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list indices: %w", contextError(ctx, err))
+		}
+		tables := make([]map[string]interface{}, 0, len(indices))
+		for _, index := range indices {
+			tables = append(tables, map[string]interface{}{"name": index["index"], "type": "index"})
+		}
+		result := map[string]interface{}{
+			"database": dbID,
+			"dbType":   dbType,
+			"tables":   tables,
+		}
+		if description != "" {
+			result["description"] = description
+		}
+		return result, nil
+	}
+
+	// A key-value database has no tables; its key count stands in for them
+	if store, ok := db.(domain.KeyValueStore); ok {
+		ctx, cancel := uc.withQueryDeadline(ctx, dbID)
+		defer cancel()
+		keys, err := store.Command(ctx, "DBSIZE")
+		if err != nil {
+			return nil, fmt.Errorf("failed to count keys: %w", contextError(ctx, err))
+		}
+		result := map[string]interface{}{
+			"database": dbID,
+			"dbType":   dbType,
+			"tables":   []map[string]interface{}{},
+			"keys":     keys,
+		}
+		if description != "" {
+			result["description"] = description
+		}
+		return result, nil
+	}
+
 	// Create appropriate query factory based on database type
 	factory := NewQueryFactory(dbType)
 
@@ -133,10 +336,11 @@ func (uc *DatabaseUseCase) GetDatabaseInfo(dbID string) (map[string]interface{},
 	tableQueries := factory.GetTablesQueries()
 
 	// Execute queries with fallback
-	ctx := context.Background()
+	ctx, cancel := uc.withQueryDeadline(ctx, dbID)
+	defer cancel()
 	rows, err := executeQueriesWithFallback(ctx, db, tableQueries)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get schema information: %w", err)
+		return nil, fmt.Errorf("failed to get schema information: %w", contextError(ctx, err))
 	}
 
 	defer func() {
@@ -198,76 +402,210 @@ func (uc *DatabaseUseCase) GetDatabaseInfo(dbID string) (map[string]interface{},
 	return result, nil
 }
 
-// ExecuteQuery executes a SQL query and returns the formatted results
-func (uc *DatabaseUseCase) ExecuteQuery(ctx context.Context, dbID, query string, params []interface{}) (string, error) {
+// resultCollector is a RowHandler keeping the rows of a QueryResult in memory up to a limit
+// and spilling the rest to disk
+type resultCollector struct {
+	dbID   string
+	result *domain.QueryResult
+	limit  int64
+	size   int64
+	spill  *spilledResult
+}
+
+// Columns implements domain.RowHandler
+func (c *resultCollector) Columns(columns []string) error {
+	c.result.Columns = columns
+	return nil
+}
+
+// Row implements domain.RowHandler
+func (c *resultCollector) Row(values []interface{}) error {
+	if c.spill != nil {
+		return c.spill.write(values)
+	}
+	c.size += rowMemory(values)
+	if c.limit > 0 && c.size > c.limit && len(c.result.Rows) > 0 {
+		spill, err := newSpilledResult(c.dbID, c.result.Columns, c.limit)
+		if err != nil {
+			return err
+		}
+		c.spill = spill
+		return c.spill.write(values)
+	}
+	c.result.Rows = append(c.result.Rows, values)
+	return nil
+}
+
+// ExecuteQuery executes a SQL query and returns its columns and rows. Rows past the memory
+// limit of the connection are spilled to a temporary file and left for FetchRows.
+func (uc *DatabaseUseCase) ExecuteQuery(ctx context.Context, dbID, query string, params []interface{}) (*domain.QueryResult, error) {
+	collector := &resultCollector{dbID: dbID, result: &domain.QueryResult{Rows: [][]interface{}{}}, limit: defaultResultMemoryLimit}
+	if config, err := uc.repo.GetDatabaseConfig(dbID); err == nil && config != nil && config.ResultMemoryLimit != 0 {
+		collector.limit = config.ResultMemoryLimit
+	}
+	_, err := uc.StreamQuery(ctx, dbID, query, params, collector)
+	if collector.spill == nil {
+		if err != nil {
+			return nil, err
+		}
+		return collector.result, nil
+	}
+
+	if err == nil {
+		err = collector.spill.rewind()
+	}
+	if err == nil {
+		collector.result.Cursor, err = spills.add(collector.spill)
+	}
+	if err != nil {
+		collector.spill.remove()
+		return nil, err
+	}
+	collector.result.Remaining = collector.spill.remaining
+	logger.Info("Spilled %d rows of a query on database %s to disk", collector.result.Remaining, dbID)
+	return collector.result, nil
+}
+
+// StreamQuery executes a SQL query and hands its rows to handler as they are read, so large
+// results are never held in memory. It returns the number of rows read.
+func (uc *DatabaseUseCase) StreamQuery(ctx context.Context, dbID, query string, params []interface{}, handler domain.RowHandler) (int64, error) {
 	db, err := uc.repo.GetDatabase(dbID)
 	if err != nil {
-		return "", fmt.Errorf("failed to get database: %w", err)
+		return 0, fmt.Errorf("failed to get database: %w", err)
 	}
 
 	// Execute query
+	ctx, cancel := uc.withQueryDeadline(ctx, dbID)
+	defer cancel()
 	rows, err := db.Query(ctx, query, params...)
 	if err != nil {
-		return "", fmt.Errorf("query execution failed: %w", err)
+		return 0, fmt.Errorf("query execution failed: %w", contextError(ctx, err))
 	}
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
-			err = fmt.Errorf("error closing rows: %w", closeErr)
+			logger.Error("error closing rows: %v", closeErr)
 		}
 	}()
 
-	// Process results into a readable format
 	columns, err := rows.Columns()
 	if err != nil {
-		return "", fmt.Errorf("failed to get column names: %w", err)
+		return 0, fmt.Errorf("failed to get column names: %w", err)
+	}
+	if err := handler.Columns(columns); err != nil {
+		return 0, err
 	}
 
-	// Format results as text
-	var resultText strings.Builder
-	resultText.WriteString("Results:\n\n")
-	resultText.WriteString(strings.Join(columns, "\t") + "\n")
-	resultText.WriteString(strings.Repeat("-", 80) + "\n")
+	// Binary columns keep their bytes and spatial columns are decoded; the driver returns
+	// other columns, such as text and numerics, as bytes too, and those become strings
+	kinds := columnKindsOf(rows, len(columns))
 
 	// Prepare for scanning
+	location := uc.resultLocation(dbID)
 	values := make([]interface{}, len(columns))
 	valuePtrs := make([]interface{}, len(columns))
 	for i := range columns {
 		valuePtrs[i] = &values[i]
 	}
 
-	// Process rows
-	rowCount := 0
+	var count int64
 	for rows.Next() {
-		rowCount++
-		scanErr := rows.Scan(valuePtrs...)
-		if scanErr != nil {
-			return "", fmt.Errorf("failed to scan row: %w", scanErr)
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return count, fmt.Errorf("failed to scan row: %w", err)
 		}
 
-		// Convert to strings and print
-		var rowText []string
-		for i := range columns {
-			val := values[i]
-			if val == nil {
-				rowText = append(rowText, "NULL")
-			} else {
-				switch v := val.(type) {
-				case []byte:
-					rowText = append(rowText, string(v))
-				default:
-					rowText = append(rowText, fmt.Sprintf("%v", v))
-				}
+		// Copy the row, converting driver byte slices to strings and timestamps to the
+		// connection's time zone
+		row := make([]interface{}, len(columns))
+		for i, val := range values {
+			switch v := val.(type) {
+			case []byte:
+				row[i] = kinds[i].value(v)
+			case time.Time:
+				row[i] = v.In(location)
+			default:
+				row[i] = val
 			}
 		}
-		resultText.WriteString(strings.Join(rowText, "\t") + "\n")
+		if err := handler.Row(row); err != nil {
+			return count, err
+		}
+		count++
 	}
 
-	if err = rows.Err(); err != nil {
-		return "", fmt.Errorf("error reading rows: %w", err)
+	if err := rows.Err(); err != nil {
+		return count, fmt.Errorf("error reading rows: %w", contextError(ctx, err))
 	}
 
-	resultText.WriteString(fmt.Sprintf("\nTotal rows: %d", rowCount))
-	return resultText.String(), nil
+	return count, nil
+}
+
+// columnKind is how the bytes the driver returns for a column are converted
+type columnKind int
+
+const (
+	// textColumn values become strings
+	textColumn columnKind = iota
+	// binaryColumn values stay bytes
+	binaryColumn
+	// mysqlGeometryColumn values are MySQL spatial values: an SRID and well-known binary
+	mysqlGeometryColumn
+	// untypedColumn values are of a type the driver does not know, such as the geometry and
+	// geography of PostGIS, which arrive as hex-encoded extended WKB; other values are text
+	untypedColumn
+)
+
+// binaryTypes are the database types whose values are raw bytes rather than text
+var binaryTypes = map[string]bool{
+	"BYTEA": true, "BLOB": true, "TINYBLOB": true, "MEDIUMBLOB": true, "LONGBLOB": true,
+	"BINARY": true, "VARBINARY": true, "BIT": true,
+}
+
+// columnKindsOf returns how to convert the values of each column of rows, when the rows know
+// the types of their columns; otherwise every column is text
+func columnKindsOf(rows domain.Rows, count int) []columnKind {
+	kinds := make([]columnKind, count)
+	typer, ok := rows.(domain.ColumnTyper)
+	if !ok {
+		return kinds
+	}
+	types, err := typer.ColumnTypes()
+	if err != nil {
+		logger.Warn("Failed to read column types, treating every column as text: %v", err)
+		return kinds
+	}
+	for i, name := range types {
+		if i >= count {
+			break
+		}
+		name = strings.ToUpper(name)
+		switch {
+		case binaryTypes[name]:
+			kinds[i] = binaryColumn
+		case name == "GEOMETRY":
+			kinds[i] = mysqlGeometryColumn
+		case name == "":
+			kinds[i] = untypedColumn
+		}
+	}
+	return kinds
+}
+
+// value converts the bytes the driver returned for a column of this kind
+func (k columnKind) value(v []byte) interface{} {
+	switch k {
+	case binaryColumn:
+		return v
+	case mysqlGeometryColumn:
+		if geometry, err := decodeMySQLGeometry(v); err == nil {
+			return geometry
+		}
+		return v
+	case untypedColumn:
+		if geometry, err := decodeHexEWKB(string(v)); err == nil {
+			return geometry
+		}
+	}
+	return string(v)
 }
 
 // ExecuteStatement executes a SQL statement (INSERT, UPDATE, DELETE)
@@ -278,9 +616,11 @@ func (uc *DatabaseUseCase) ExecuteStatement(ctx context.Context, dbID, statement
 	}
 
 	// Execute statement
+	ctx, cancel := uc.withQueryDeadline(ctx, dbID)
+	defer cancel()
 	result, err := db.Exec(ctx, statement, params...)
 	if err != nil {
-		return "", fmt.Errorf("statement execution failed: %w", err)
+		return "", fmt.Errorf("statement execution failed: %w", contextError(ctx, err))
 	}
 
 	// Get rows affected
@@ -344,6 +684,41 @@ func (uc *DatabaseUseCase) ExecuteTransaction(ctx context.Context, dbID, action
 	}
 }
 
+// ExecuteBatch executes statements in a single transaction, rolling back if any of them fails.
+// params holds the parameters of each statement by position and may be shorter than statements.
+func (uc *DatabaseUseCase) ExecuteBatch(ctx context.Context, dbID string, statements []string, params [][]interface{}) error {
+	db, err := uc.repo.GetDatabase(dbID)
+	if err != nil {
+		return fmt.Errorf("failed to get database: %w", err)
+	}
+
+	// The timeout bounds the whole batch rather than each statement
+	ctx, cancel := uc.withQueryDeadline(ctx, dbID)
+	defer cancel()
+	tx, err := db.Begin(ctx, &domain.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", contextError(ctx, err))
+	}
+
+	for i, statement := range statements {
+		var args []interface{}
+		if i < len(params) {
+			args = params[i]
+		}
+		if _, err := tx.Exec(ctx, statement, args...); err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				logger.Error("error rolling back transaction: %v", rbErr)
+			}
+			return fmt.Errorf("statement %d failed: %w", i+1, contextError(ctx, err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", contextError(ctx, err))
+	}
+	return nil
+}
+
 // Helper function to get current Unix timestamp
 func timeNowUnix() int64 {
 	return time.Now().Unix()
@@ -353,3 +728,61 @@ func timeNowUnix() int64 {
 func (uc *DatabaseUseCase) GetDatabaseType(dbID string) (string, error) {
 	return uc.repo.GetDatabaseType(dbID)
 }
+
+// GetDatabaseConfig returns the connection configuration of a database by ID
+func (uc *DatabaseUseCase) GetDatabaseConfig(dbID string) (*domain.DatabaseConnectionConfig, error) {
+	return uc.repo.GetDatabaseConfig(dbID)
+}
+
+// GetDocumentStore returns the document store of a document database such as MongoDB
+func (uc *DatabaseUseCase) GetDocumentStore(dbID string) (domain.DocumentStore, error) {
+	db, err := uc.repo.GetDatabase(dbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database: %w", err)
+	}
+	store, ok := db.(domain.DocumentStore)
+	if !ok {
+		return nil, fmt.Errorf("database %s is not a document database", dbID)
+	}
+	return store, nil
+}
+
+// GetKeyValueStore returns the key-value store of a key-value database such as Redis
+func (uc *DatabaseUseCase) GetKeyValueStore(dbID string) (domain.KeyValueStore, error) {
+	db, err := uc.repo.GetDatabase(dbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database: %w", err)
+	}
+	store, ok := db.(domain.KeyValueStore)
+	if !ok {
+		return nil, fmt.Errorf("database %s is not a key-value database", dbID)
+	}
+	return store, nil
+}
+
+// GetSearchStore returns the search store of a search database such as Elasticsearch
+func (uc *DatabaseUseCase) GetSearchStore(dbID string) (domain.SearchStore, error) {
+	db, err := uc.repo.GetDatabase(dbID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database: %w", err)
+	}
+	store, ok := db.(domain.SearchStore)
+	if !ok {
+		return nil, fmt.Errorf("database %s is not a search database", dbID)
+	}
+	return store, nil
+}
+
+// Listen calls handle with the payload of every notification sent on a channel of a database
+// until ctx is cancelled
+func (uc *DatabaseUseCase) Listen(ctx context.Context, dbID, channel string, handle func(payload string)) error {
+	db, err := uc.repo.GetDatabase(dbID)
+	if err != nil {
+		return fmt.Errorf("failed to get database: %w", err)
+	}
+	listener, ok := db.(domain.Listener)
+	if !ok {
+		return fmt.Errorf("database %s does not support notifications", dbID)
+	}
+	return listener.Listen(ctx, channel, handle)
+}
@@ -0,0 +1,308 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+func TestMain(m *testing.M) {
+	// The use case logs through the package logger, which must be initialized
+	logger.Initialize("error")
+	os.Exit(m.Run())
+}
+
+// blockingDatabase is a database whose calls block until their context is done
+type blockingDatabase struct{}
+
+func (d *blockingDatabase) Query(ctx context.Context, query string, args ...interface{}) (domain.Rows, error) {
+	<-ctx.Done()
+	return nil, errors.New("pq: canceling statement due to user request")
+}
+
+func (d *blockingDatabase) Exec(ctx context.Context, statement string, args ...interface{}) (domain.Result, error) {
+	<-ctx.Done()
+	return nil, errors.New("pq: canceling statement due to user request")
+}
+
+func (d *blockingDatabase) Begin(ctx context.Context, opts *domain.TxOptions) (domain.Tx, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// stubRepository serves a single database with a fixed configuration
+type stubRepository struct {
+	config *domain.DatabaseConnectionConfig
+}
+
+func (r *stubRepository) GetDatabase(id string) (domain.Database, error) {
+	return &blockingDatabase{}, nil
+}
+
+func (r *stubRepository) ListDatabases() []string {
+	return []string{r.config.ID}
+}
+
+func (r *stubRepository) GetDatabaseType(id string) (string, error) {
+	return r.config.Type, nil
+}
+
+func (r *stubRepository) GetDatabaseConfig(id string) (*domain.DatabaseConnectionConfig, error) {
+	return r.config, nil
+}
+
+func TestQueryTimeoutCancelsDatabaseWork(t *testing.T) {
+	uc := NewDatabaseUseCase(&stubRepository{config: &domain.DatabaseConnectionConfig{ID: "pg1", Type: "postgres", QueryTimeout: 20 * time.Millisecond}})
+
+	start := time.Now()
+	_, err := uc.ExecuteQuery(context.Background(), "pg1", "SELECT pg_sleep(60)", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "canceling statement")
+	assert.Less(t, time.Since(start), time.Second)
+
+	_, err = uc.ExecuteStatement(context.Background(), "pg1", "UPDATE orders SET total = 0", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	err = uc.ExecuteBatch(context.Background(), "pg1", []string{"DELETE FROM orders"}, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestQueryTimeoutKeepsCallerContext(t *testing.T) {
+	uc := NewDatabaseUseCase(&stubRepository{config: &domain.DatabaseConnectionConfig{ID: "pg1", Type: "postgres", QueryTimeout: time.Hour}})
+
+	// An earlier request deadline wins over the configured timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := uc.ExecuteQuery(ctx, "pg1", "SELECT 1", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// Cancellation by the client stops the query as well
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err = uc.ExecuteQuery(ctx, "pg1", "SELECT 1", nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// sliceRows is a domain.Rows over fixed values
+type sliceRows struct {
+	columns []string
+	values  [][]interface{}
+	next    int
+	closed  bool
+}
+
+func (r *sliceRows) Close() error               { r.closed = true; return nil }
+func (r *sliceRows) Columns() ([]string, error) { return r.columns, nil }
+func (r *sliceRows) Err() error                 { return nil }
+func (r *sliceRows) Next() bool                 { r.next++; return r.next <= len(r.values) }
+func (r *sliceRows) Scan(dest ...interface{}) error {
+	for i, value := range r.values[r.next-1] {
+		*dest[i].(*interface{}) = value
+	}
+	return nil
+}
+
+// rowsDatabase returns the same rows for every query
+type rowsDatabase struct {
+	blockingDatabase
+	rows domain.Rows
+}
+
+func (d *rowsDatabase) Query(ctx context.Context, query string, args ...interface{}) (domain.Rows, error) {
+	return d.rows, nil
+}
+
+// rowsRepository serves a rowsDatabase
+type rowsRepository struct {
+	stubRepository
+	db *rowsDatabase
+}
+
+func (r *rowsRepository) GetDatabase(id string) (domain.Database, error) {
+	return r.db, nil
+}
+
+// stopAfter is a RowHandler keeping rows until it has limit of them
+type stopAfter struct {
+	limit   int
+	columns []string
+	rows    [][]interface{}
+}
+
+func (h *stopAfter) Columns(columns []string) error {
+	h.columns = columns
+	return nil
+}
+
+func (h *stopAfter) Row(values []interface{}) error {
+	if len(h.rows) == h.limit {
+		return errors.New("enough rows")
+	}
+	h.rows = append(h.rows, values)
+	return nil
+}
+
+func TestStreamQuery(t *testing.T) {
+	rows := &sliceRows{columns: []string{"id", "name"}, values: [][]interface{}{{int64(1), []byte("a")}, {int64(2), nil}, {int64(3), []byte("c")}}}
+	uc := NewDatabaseUseCase(&rowsRepository{
+		stubRepository: stubRepository{config: &domain.DatabaseConnectionConfig{ID: "pg1", Type: "postgres"}},
+		db:             &rowsDatabase{rows: rows},
+	})
+
+	handler := &stopAfter{limit: 2}
+	count, err := uc.StreamQuery(context.Background(), "pg1", "SELECT id, name FROM users", nil, handler)
+	assert.EqualError(t, err, "enough rows")
+	assert.Equal(t, int64(2), count)
+	assert.Equal(t, []string{"id", "name"}, handler.columns)
+	assert.Equal(t, [][]interface{}{{int64(1), "a"}, {int64(2), nil}}, handler.rows)
+	assert.True(t, rows.closed)
+
+	// ExecuteQuery collects every row
+	rows.next, rows.closed = 0, false
+	result, err := uc.ExecuteQuery(context.Background(), "pg1", "SELECT id, name FROM users", nil)
+	assert.NoError(t, err)
+	assert.Len(t, result.Rows, 3)
+}
+
+// typedRows are sliceRows that know the database types of their columns
+type typedRows struct {
+	*sliceRows
+	types []string
+}
+
+func (r *typedRows) ColumnTypes() ([]string, error) { return r.types, nil }
+
+func TestStreamQueryKeepsBinaryColumns(t *testing.T) {
+	rows := &sliceRows{columns: []string{"name", "payload"}, values: [][]interface{}{{[]byte("a"), []byte{0xff, 0x00}}}}
+	db := &rowsDatabase{rows: rows}
+	uc := NewDatabaseUseCase(&rowsRepository{
+		stubRepository: stubRepository{config: &domain.DatabaseConnectionConfig{ID: "pg1", Type: "postgres"}},
+		db:             db,
+	})
+
+	// Without column types every byte slice becomes a string
+	result, err := uc.ExecuteQuery(context.Background(), "pg1", "SELECT name, payload FROM files", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "\xff\x00"}, result.Rows[0])
+
+	// Binary columns keep their bytes
+	rows.next = 0
+	db.rows = &typedRows{sliceRows: rows, types: []string{"TEXT", "bytea"}}
+	result, err = uc.ExecuteQuery(context.Background(), "pg1", "SELECT name, payload FROM files", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", []byte{0xff, 0x00}}, result.Rows[0])
+}
+
+func TestStreamQueryConvertsTimestamps(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 2*60*60))
+	rows := &sliceRows{columns: []string{"created"}, values: [][]interface{}{{created}}}
+	config := &domain.DatabaseConnectionConfig{ID: "pg1", Type: "postgres"}
+	uc := NewDatabaseUseCase(&rowsRepository{stubRepository: stubRepository{config: config}, db: &rowsDatabase{rows: rows}})
+
+	// Timestamps are converted to UTC unless the connection sets a timezone
+	result, err := uc.ExecuteQuery(context.Background(), "pg1", "SELECT created FROM users", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-01-02T01:04:05Z", result.Rows[0][0].(time.Time).Format(time.RFC3339))
+
+	config.Timezone = "Asia/Tokyo"
+	rows.next = 0
+	result, err = uc.ExecuteQuery(context.Background(), "pg1", "SELECT created FROM users", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-01-02T10:04:05+09:00", result.Rows[0][0].(time.Time).Format(time.RFC3339))
+
+	// An unknown zone falls back to UTC
+	config.Timezone = "Mars/Olympus_Mons"
+	rows.next = 0
+	result, err = uc.ExecuteQuery(context.Background(), "pg1", "SELECT created FROM users", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, result.Rows[0][0].(time.Time).Location())
+}
+
+// spillRows returns rows of every value kind a spill file keeps
+func spillRows(n int) [][]interface{} {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	var rows [][]interface{}
+	for i := 0; i < n; i++ {
+		rows = append(rows, []interface{}{int64(i), []byte(fmt.Sprintf("name-%d", i)), nil, created, 1.5, true})
+	}
+	return rows
+}
+
+func TestExecuteQuerySpillsRowsPastMemoryLimit(t *testing.T) {
+	rows := &sliceRows{columns: []string{"id", "name", "note", "created", "score", "active"}, values: spillRows(10)}
+	uc := NewDatabaseUseCase(&rowsRepository{
+		stubRepository: stubRepository{config: &domain.DatabaseConnectionConfig{ID: "pg1", Type: "postgres", ResultMemoryLimit: 400}},
+		db:             &rowsDatabase{rows: rows},
+	})
+
+	result, err := uc.ExecuteQuery(context.Background(), "pg1", "SELECT * FROM users", nil)
+	assert.NoError(t, err)
+	assert.Len(t, result.Rows, 2)
+	assert.Equal(t, int64(8), result.Remaining)
+	assert.NotEmpty(t, result.Cursor)
+
+	_, err = uc.FetchRows(context.Background(), "other", result.Cursor, 0)
+	assert.Error(t, err)
+
+	all := result.Rows
+	cursor := result.Cursor
+	for cursor != "" {
+		page, err := uc.FetchRows(context.Background(), "pg1", cursor, 3)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, len(page.Rows), 3)
+		assert.Equal(t, rows.columns, page.Columns)
+		all = append(all, page.Rows...)
+		cursor = page.Cursor
+	}
+
+	// Spilled rows read back with the types the driver returned
+	expected := spillRows(10)
+	for _, row := range expected {
+		row[1] = string(row[1].([]byte))
+	}
+	assert.Equal(t, expected, all)
+
+	_, err = uc.FetchRows(context.Background(), "pg1", result.Cursor, 3)
+	assert.ErrorContains(t, err, "unknown, fully read or expired")
+}
+
+func TestSpilledResultsExpire(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	spillClock = func() time.Time { return now }
+	t.Cleanup(func() { spillClock = time.Now })
+
+	rows := &sliceRows{columns: []string{"id", "name", "note", "created", "score", "active"}, values: spillRows(5)}
+	uc := NewDatabaseUseCase(&rowsRepository{
+		stubRepository: stubRepository{config: &domain.DatabaseConnectionConfig{ID: "pg1", Type: "postgres", ResultMemoryLimit: 1}},
+		db:             &rowsDatabase{rows: rows},
+	})
+	result, err := uc.ExecuteQuery(context.Background(), "pg1", "SELECT * FROM users", nil)
+	assert.NoError(t, err)
+	assert.Len(t, result.Rows, 1)
+	spill, err := spills.take("pg1", result.Cursor)
+	assert.NoError(t, err)
+
+	// Reading keeps the cursor alive
+	now = now.Add(spillTTL - time.Second)
+	_, err = uc.FetchRows(context.Background(), "pg1", result.Cursor, 1)
+	assert.NoError(t, err)
+	now = now.Add(spillTTL - time.Second)
+	_, err = uc.FetchRows(context.Background(), "pg1", result.Cursor, 1)
+	assert.NoError(t, err)
+
+	now = now.Add(spillTTL)
+	_, err = uc.FetchRows(context.Background(), "pg1", result.Cursor, 1)
+	assert.Error(t, err)
+	_, err = os.Stat(spill.file.Name())
+	assert.True(t, os.IsNotExist(err))
+}
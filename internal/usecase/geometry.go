@@ -0,0 +1,415 @@
+package usecase
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+// Well-known binary geometry types
+const (
+	wkbPoint = iota + 1
+	wkbLineString
+	wkbPolygon
+	wkbMultiPoint
+	wkbMultiLineString
+	wkbMultiPolygon
+	wkbGeometryCollection
+)
+
+// Flags PostGIS extended WKB sets in the type of a geometry
+const (
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
+)
+
+// maxGeometryDepth bounds how deeply geometry collections may nest
+const maxGeometryDepth = 32
+
+// wkbNames are the WKT and GeoJSON names of the geometry types
+var wkbNames = map[uint32][2]string{
+	wkbPoint:              {"POINT", "Point"},
+	wkbLineString:         {"LINESTRING", "LineString"},
+	wkbPolygon:            {"POLYGON", "Polygon"},
+	wkbMultiPoint:         {"MULTIPOINT", "MultiPoint"},
+	wkbMultiLineString:    {"MULTILINESTRING", "MultiLineString"},
+	wkbMultiPolygon:       {"MULTIPOLYGON", "MultiPolygon"},
+	wkbGeometryCollection: {"GEOMETRYCOLLECTION", "GeometryCollection"},
+}
+
+// errInvalidWKB is returned for bytes that are not a well-formed geometry
+var errInvalidWKB = errors.New("invalid well-known binary geometry")
+
+// wkbReader decodes well-known binary, ISO or PostGIS extended, into WKT and GeoJSON at once
+type wkbReader struct {
+	data    []byte
+	pos     int
+	srid    int
+	wkt     []byte
+	geoJSON strings.Builder
+}
+
+// decodeMySQLGeometry decodes a MySQL spatial value: a little-endian SRID followed by WKB
+func decodeMySQLGeometry(value []byte) (domain.Geometry, error) {
+	if len(value) < 4 {
+		return domain.Geometry{}, errInvalidWKB
+	}
+	geometry, err := decodeWKB(value[4:])
+	if err != nil {
+		return domain.Geometry{}, err
+	}
+	geometry.SRID = int(binary.LittleEndian.Uint32(value))
+	return geometry, nil
+}
+
+// decodeHexEWKB decodes the hex-encoded extended WKB PostGIS returns for geometry and
+// geography values
+func decodeHexEWKB(value string) (domain.Geometry, error) {
+	if len(value) < 10 || len(value)%2 != 0 {
+		return domain.Geometry{}, errInvalidWKB
+	}
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		return domain.Geometry{}, errInvalidWKB
+	}
+	return decodeWKB(data)
+}
+
+// decodeWKB decodes a geometry that must take up all of data
+func decodeWKB(data []byte) (domain.Geometry, error) {
+	r := &wkbReader{data: data}
+	if err := r.geometry(0); err != nil {
+		return domain.Geometry{}, err
+	}
+	if r.pos != len(r.data) {
+		return domain.Geometry{}, errInvalidWKB
+	}
+	return domain.Geometry{SRID: r.srid, WKT: string(r.wkt), GeoJSON: r.geoJSON.String()}, nil
+}
+
+// geometry decodes one geometry with its header
+func (r *wkbReader) geometry(depth int) error {
+	if depth > maxGeometryDepth || r.pos >= len(r.data) {
+		return errInvalidWKB
+	}
+	var order binary.ByteOrder
+	switch r.data[r.pos] {
+	case 0:
+		order = binary.BigEndian
+	case 1:
+		order = binary.LittleEndian
+	default:
+		return errInvalidWKB
+	}
+	r.pos++
+	kind, err := r.uint32(order)
+	if err != nil {
+		return err
+	}
+
+	hasZ, hasM := kind&ewkbZ != 0, kind&ewkbM != 0
+	if kind&ewkbSRID != 0 {
+		srid, err := r.uint32(order)
+		if err != nil {
+			return err
+		}
+		if depth == 0 {
+			r.srid = int(srid)
+		}
+	}
+	kind &^= ewkbZ | ewkbM | ewkbSRID
+	// ISO WKB adds 1000 for Z, 2000 for M and 3000 for both
+	switch kind / 1000 {
+	case 0:
+	case 1:
+		hasZ = true
+	case 2:
+		hasM = true
+	case 3:
+		hasZ, hasM = true, true
+	default:
+		return errInvalidWKB
+	}
+	kind %= 1000
+	names, ok := wkbNames[kind]
+	if !ok {
+		return errInvalidWKB
+	}
+	dims := 2
+	if hasZ {
+		dims++
+	}
+	if hasM {
+		dims++
+	}
+
+	// ISO WKT puts a space between the dimensions and the coordinates, as in POINT Z (1 2 3),
+	// and before EMPTY
+	r.wktString(names[0])
+	switch {
+	case hasZ && hasM:
+		r.wktString(" ZM ")
+	case hasZ:
+		r.wktString(" Z ")
+	case hasM:
+		r.wktString(" M ")
+	default:
+		if r.emptyAhead(order, kind, dims) {
+			r.wktByte(' ')
+		}
+	}
+	r.geoJSON.WriteString(`{"type":"`)
+	r.geoJSON.WriteString(names[1])
+	if kind == wkbGeometryCollection {
+		r.geoJSON.WriteString(`","geometries":[`)
+	} else {
+		r.geoJSON.WriteString(`","coordinates":`)
+	}
+
+	switch kind {
+	case wkbPoint:
+		err = r.point(order, dims)
+	case wkbLineString:
+		err = r.points(order, dims)
+	case wkbPolygon:
+		err = r.rings(order, dims)
+	default:
+		err = r.parts(order, kind, depth)
+	}
+	if err != nil {
+		return err
+	}
+	if kind == wkbGeometryCollection {
+		r.geoJSON.WriteByte(']')
+	}
+	r.geoJSON.WriteByte('}')
+	return nil
+}
+
+// point decodes the coordinates of a point; a point whose coordinates are all NaN is empty
+func (r *wkbReader) point(order binary.ByteOrder, dims int) error {
+	coords, err := r.coordinates(order, dims)
+	if err != nil {
+		return err
+	}
+	empty := true
+	for _, c := range coords {
+		empty = empty && math.IsNaN(c)
+	}
+	if empty {
+		r.wktString("EMPTY")
+		r.geoJSON.WriteString("[]")
+		return nil
+	}
+	r.wktByte('(')
+	r.writePosition(coords)
+	r.wktByte(')')
+	return nil
+}
+
+// points decodes a counted list of positions, such as a line string or a ring
+func (r *wkbReader) points(order binary.ByteOrder, dims int) error {
+	count, err := r.count(order, dims*8)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		r.wktString("EMPTY")
+		r.geoJSON.WriteString("[]")
+		return nil
+	}
+	r.wktByte('(')
+	r.geoJSON.WriteByte('[')
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			r.wktByte(',')
+			r.geoJSON.WriteByte(',')
+		}
+		coords, err := r.coordinates(order, dims)
+		if err != nil {
+			return err
+		}
+		r.writePosition(coords)
+	}
+	r.wktByte(')')
+	r.geoJSON.WriteByte(']')
+	return nil
+}
+
+// rings decodes the rings of a polygon
+func (r *wkbReader) rings(order binary.ByteOrder, dims int) error {
+	count, err := r.count(order, 4)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		r.wktString("EMPTY")
+		r.geoJSON.WriteString("[]")
+		return nil
+	}
+	r.wktByte('(')
+	r.geoJSON.WriteByte('[')
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			r.wktByte(',')
+			r.geoJSON.WriteByte(',')
+		}
+		if err := r.points(order, dims); err != nil {
+			return err
+		}
+	}
+	r.wktByte(')')
+	r.geoJSON.WriteByte(']')
+	return nil
+}
+
+// parts decodes the members of a multi geometry or collection. Each member is a geometry of
+// its own; in WKT and GeoJSON the members of multi geometries are written without their type.
+func (r *wkbReader) parts(order binary.ByteOrder, kind uint32, depth int) error {
+	count, err := r.count(order, 5)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		r.wktString("EMPTY")
+		if kind != wkbGeometryCollection {
+			r.geoJSON.WriteString("[]")
+		}
+		return nil
+	}
+	r.wktByte('(')
+	if kind != wkbGeometryCollection {
+		r.geoJSON.WriteByte('[')
+	}
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			r.wktByte(',')
+			r.geoJSON.WriteByte(',')
+		}
+		if kind == wkbGeometryCollection {
+			if err := r.geometry(depth + 1); err != nil {
+				return err
+			}
+			continue
+		}
+		member := &wkbReader{data: r.data, pos: r.pos}
+		if err := member.geometry(depth + 1); err != nil {
+			return err
+		}
+		r.pos = member.pos
+		if err := r.writeMember(member, kind); err != nil {
+			return err
+		}
+	}
+	r.wktByte(')')
+	if kind != wkbGeometryCollection {
+		r.geoJSON.WriteByte(']')
+	}
+	return nil
+}
+
+// writeMember writes a member of a multi geometry without its type, after checking it has
+// the type the multi geometry holds
+func (r *wkbReader) writeMember(member *wkbReader, kind uint32) error {
+	name := wkbNames[kind-3][0]
+	wkt := string(member.wkt)
+	if !strings.HasPrefix(wkt, name) {
+		return errInvalidWKB
+	}
+	r.wktString(strings.TrimLeft(strings.TrimPrefix(wkt, name), " ZM"))
+
+	geoJSON := member.geoJSON.String()
+	start := strings.Index(geoJSON, `"coordinates":`)
+	if start < 0 {
+		return errInvalidWKB
+	}
+	r.geoJSON.WriteString(geoJSON[start+len(`"coordinates":`) : len(geoJSON)-1])
+	return nil
+}
+
+// emptyAhead reports whether the geometry whose header was just read has no coordinates
+func (r *wkbReader) emptyAhead(order binary.ByteOrder, kind uint32, dims int) bool {
+	if kind == wkbPoint {
+		if len(r.data)-r.pos < dims*8 {
+			return false
+		}
+		for i := 0; i < dims; i++ {
+			if !math.IsNaN(math.Float64frombits(order.Uint64(r.data[r.pos+i*8:]))) {
+				return false
+			}
+		}
+		return true
+	}
+	return len(r.data)-r.pos >= 4 && order.Uint32(r.data[r.pos:]) == 0
+}
+
+// wktString appends text to the WKT
+func (r *wkbReader) wktString(text string) {
+	r.wkt = append(r.wkt, text...)
+}
+
+// wktByte appends a character to the WKT
+func (r *wkbReader) wktByte(c byte) {
+	r.wkt = append(r.wkt, c)
+}
+
+// writePosition writes the coordinates of one position
+func (r *wkbReader) writePosition(coords []float64) {
+	r.geoJSON.WriteByte('[')
+	for i, c := range coords {
+		if i > 0 {
+			r.wktByte(' ')
+			r.geoJSON.WriteByte(',')
+		}
+		text := strconv.FormatFloat(c, 'f', -1, 64)
+		r.wktString(text)
+		if math.IsNaN(c) || math.IsInf(c, 0) {
+			text = "null"
+		}
+		r.geoJSON.WriteString(text)
+	}
+	r.geoJSON.WriteByte(']')
+}
+
+// coordinates reads the coordinates of one position
+func (r *wkbReader) coordinates(order binary.ByteOrder, dims int) ([]float64, error) {
+	if len(r.data)-r.pos < dims*8 {
+		return nil, errInvalidWKB
+	}
+	coords := make([]float64, dims)
+	for i := range coords {
+		coords[i] = math.Float64frombits(order.Uint64(r.data[r.pos:]))
+		r.pos += 8
+	}
+	return coords, nil
+}
+
+// count reads the number of items that follow, each at least minSize bytes long, rejecting
+// counts the remaining bytes cannot hold
+func (r *wkbReader) count(order binary.ByteOrder, minSize int) (int, error) {
+	n, err := r.uint32(order)
+	if err != nil {
+		return 0, err
+	}
+	if int64(n)*int64(minSize) > int64(len(r.data)-r.pos) {
+		return 0, errInvalidWKB
+	}
+	return int(n), nil
+}
+
+// uint32 reads a 4-byte unsigned integer
+func (r *wkbReader) uint32(order binary.ByteOrder) (uint32, error) {
+	if len(r.data)-r.pos < 4 {
+		return 0, fmt.Errorf("%w: truncated", errInvalidWKB)
+	}
+	n := order.Uint32(r.data[r.pos:])
+	r.pos += 4
+	return n, nil
+}
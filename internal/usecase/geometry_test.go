@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+)
+
+// wkb builds little-endian well-known binary from a type and numbers; integers are written as
+// 4-byte counts and floats as coordinates
+func wkb(kind uint32, values ...interface{}) []byte {
+	data := []byte{1}
+	data = binary.LittleEndian.AppendUint32(data, kind)
+	for _, value := range values {
+		switch v := value.(type) {
+		case int:
+			data = binary.LittleEndian.AppendUint32(data, uint32(v))
+		case float64:
+			data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+		case []byte:
+			data = append(data, v...)
+		}
+	}
+	return data
+}
+
+func TestDecodeHexEWKB(t *testing.T) {
+	// SELECT 'SRID=4326;POINT(1 2)'::geometry
+	geometry, err := decodeHexEWKB("0101000020E6100000000000000000F03F0000000000000040")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Geometry{SRID: 4326, WKT: "POINT(1 2)", GeoJSON: `{"type":"Point","coordinates":[1,2]}`}, geometry)
+	assert.Equal(t, "SRID=4326;POINT(1 2)", geometry.String())
+
+	for _, value := range []string{"", "hello world", "deadbeef", "0101000000000000000000F03F", "0101000000000000000000F03F000000000000004000"} {
+		_, err := decodeHexEWKB(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestDecodeWKBShapes(t *testing.T) {
+	cases := []struct {
+		data    []byte
+		wkt     string
+		geoJSON string
+	}{
+		{wkb(1001, 1.0, 2.0, 3.0), "POINT Z (1 2 3)", `{"type":"Point","coordinates":[1,2,3]}`},
+		{wkb(1, math.NaN(), math.NaN()), "POINT EMPTY", `{"type":"Point","coordinates":[]}`},
+		{wkb(2, 2, 0.5, -1.0, 2.0, 3.25), "LINESTRING(0.5 -1,2 3.25)", `{"type":"LineString","coordinates":[[0.5,-1],[2,3.25]]}`},
+		{wkb(3, 1, 4, 0.0, 0.0, 1.0, 0.0, 1.0, 1.0, 0.0, 0.0), "POLYGON((0 0,1 0,1 1,0 0))", `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`},
+		{wkb(4, 2, wkb(1, 1.0, 2.0), wkb(1, 3.0, 4.0)), "MULTIPOINT((1 2),(3 4))", `{"type":"MultiPoint","coordinates":[[1,2],[3,4]]}`},
+		{wkb(7, 2, wkb(1, 1.0, 2.0), wkb(2, 2, 0.0, 0.0, 1.0, 1.0)), "GEOMETRYCOLLECTION(POINT(1 2),LINESTRING(0 0,1 1))",
+			`{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2]},{"type":"LineString","coordinates":[[0,0],[1,1]]}]}`},
+		{wkb(6, 0), "MULTIPOLYGON EMPTY", `{"type":"MultiPolygon","coordinates":[]}`},
+	}
+	for _, c := range cases {
+		geometry, err := decodeWKB(c.data)
+		if assert.NoError(t, err, c.wkt) {
+			assert.Equal(t, c.wkt, geometry.WKT)
+			assert.Equal(t, c.geoJSON, geometry.GeoJSON)
+		}
+	}
+
+	// Counts larger than the data are rejected rather than allocated
+	_, err := decodeWKB(wkb(2, 1<<30, 1.0, 2.0))
+	assert.Error(t, err)
+	// Multi geometries only hold members of their own type
+	_, err = decodeWKB(wkb(4, 1, wkb(2, 0)))
+	assert.Error(t, err)
+}
+
+func TestDecodeMySQLGeometry(t *testing.T) {
+	value := binary.LittleEndian.AppendUint32(nil, 3857)
+	value = append(value, wkb(1, 10.0, 20.0)...)
+	geometry, err := decodeMySQLGeometry(value)
+	assert.NoError(t, err)
+	assert.Equal(t, "SRID=3857;POINT(10 20)", geometry.String())
+
+	rows := &typedRows{
+		sliceRows: &sliceRows{columns: []string{"id", "shape", "area"}, values: [][]interface{}{
+			{int64(1), value, []byte(hex.EncodeToString(wkb(1, 1.0, 2.0)))},
+			{int64(2), []byte("bad"), []byte("not a geometry")},
+		}},
+		types: []string{"INT", "GEOMETRY", ""},
+	}
+	kinds := columnKindsOf(rows, 3)
+	assert.Equal(t, []columnKind{textColumn, mysqlGeometryColumn, untypedColumn}, kinds)
+	assert.Equal(t, geometry, kinds[1].value(value))
+	assert.Equal(t, "POINT(1 2)", kinds[2].value(rows.values[0][2].([]byte)).(domain.Geometry).WKT)
+	assert.Equal(t, []byte("bad"), kinds[1].value([]byte("bad")))
+	assert.Equal(t, "not a geometry", kinds[2].value([]byte("not a geometry")))
+}
@@ -0,0 +1,291 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/FreePeak/db-mcp-server/internal/domain"
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+// defaultResultMemoryLimit is how many bytes of rows a query result keeps in memory when the
+// connection sets no result_memory_limit; the rest is spilled to a temporary file
+const defaultResultMemoryLimit = 8 << 20
+
+// spillTTL is how long the spilled rows of a result stay readable after they were last read
+const spillTTL = 10 * time.Minute
+
+// spillClock returns the current time; tests replace it
+var spillClock = time.Now
+
+// spilledValue is a column value in a spill file. Values are tagged with their kind so they
+// read back as the types the driver returned.
+type spilledValue struct {
+	Kind  byte
+	Int   int64
+	Float float64
+	Str   string
+	Bytes []byte
+	Bool  bool
+	Time  time.Time
+
+	// GeoJSON is the GeoJSON of a geometry, whose WKT is in Str and SRID in Int
+	GeoJSON string
+}
+
+// Kinds of spilled values
+const (
+	spilledNull byte = iota
+	spilledInt
+	spilledFloat
+	spilledString
+	spilledBool
+	spilledTime
+	spilledBytes
+	spilledGeometry
+)
+
+// spilledResult holds the rows of a result that did not fit its memory limit in a temporary file
+type spilledResult struct {
+	mu        sync.Mutex
+	dbID      string
+	columns   []string
+	limit     int64
+	file      *os.File
+	buffer    *bufio.Writer
+	encoder   *gob.Encoder
+	decoder   *gob.Decoder
+	remaining int64
+	used      time.Time
+}
+
+// newSpilledResult creates the temporary file rows of a result are spilled to
+func newSpilledResult(dbID string, columns []string, limit int64) (*spilledResult, error) {
+	file, err := os.CreateTemp("", "db-mcp-result-*.gob")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spill file: %w", err)
+	}
+	buffer := bufio.NewWriter(file)
+	return &spilledResult{
+		dbID:    dbID,
+		columns: columns,
+		limit:   limit,
+		file:    file,
+		buffer:  buffer,
+		encoder: gob.NewEncoder(buffer),
+		used:    spillClock(),
+	}, nil
+}
+
+// write appends a row to the spill file
+func (s *spilledResult) write(values []interface{}) error {
+	row := make([]spilledValue, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case int64:
+			row[i] = spilledValue{Kind: spilledInt, Int: v}
+		case float64:
+			row[i] = spilledValue{Kind: spilledFloat, Float: v}
+		case string:
+			row[i] = spilledValue{Kind: spilledString, Str: v}
+		case []byte:
+			row[i] = spilledValue{Kind: spilledBytes, Bytes: v}
+		case domain.Geometry:
+			row[i] = spilledValue{Kind: spilledGeometry, Int: int64(v.SRID), Str: v.WKT, GeoJSON: v.GeoJSON}
+		case bool:
+			row[i] = spilledValue{Kind: spilledBool, Bool: v}
+		case time.Time:
+			row[i] = spilledValue{Kind: spilledTime, Time: v}
+		default:
+			row[i] = spilledValue{Kind: spilledString, Str: fmt.Sprintf("%v", v)}
+		}
+	}
+	if err := s.encoder.Encode(row); err != nil {
+		return fmt.Errorf("failed to spill row: %w", err)
+	}
+	s.remaining++
+	return nil
+}
+
+// rewind ends writing and positions the file at its first row
+func (s *spilledResult) rewind() error {
+	if err := s.buffer.Flush(); err != nil {
+		return fmt.Errorf("failed to spill rows: %w", err)
+	}
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read spilled rows: %w", err)
+	}
+	s.buffer, s.encoder = nil, nil
+	s.decoder = gob.NewDecoder(bufio.NewReader(s.file))
+	return nil
+}
+
+// read returns the next rows of the spill file, at most max of them and no more than its
+// memory limit allows, but always at least one
+func (s *spilledResult) read(max int) ([][]interface{}, error) {
+	var rows [][]interface{}
+	var size int64
+	for s.remaining > 0 && (max <= 0 || len(rows) < max) && (s.limit <= 0 || size < s.limit) {
+		var spilled []spilledValue
+		if err := s.decoder.Decode(&spilled); err != nil {
+			return nil, fmt.Errorf("failed to read spilled rows: %w", err)
+		}
+		row := make([]interface{}, len(spilled))
+		for i, value := range spilled {
+			switch value.Kind {
+			case spilledInt:
+				row[i] = value.Int
+			case spilledFloat:
+				row[i] = value.Float
+			case spilledString:
+				row[i] = value.Str
+			case spilledBytes:
+				row[i] = value.Bytes
+			case spilledGeometry:
+				row[i] = domain.Geometry{SRID: int(value.Int), WKT: value.Str, GeoJSON: value.GeoJSON}
+			case spilledBool:
+				row[i] = value.Bool
+			case spilledTime:
+				row[i] = value.Time
+			}
+		}
+		rows = append(rows, row)
+		size += rowMemory(row)
+		s.remaining--
+	}
+	s.used = spillClock()
+	return rows, nil
+}
+
+// remove deletes the spill file
+func (s *spilledResult) remove() {
+	s.remaining = 0
+	name := s.file.Name()
+	if err := s.file.Close(); err != nil {
+		logger.Warn("Error closing spill file %s: %v", name, err)
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Warn("Error removing spill file %s: %v", name, err)
+	}
+}
+
+// spillRegistry tracks the spilled results that can still be read by cursor
+type spillRegistry struct {
+	mu     sync.Mutex
+	spills map[string]*spilledResult
+}
+
+// spills holds the spilled results of every use case
+var spills = &spillRegistry{spills: make(map[string]*spilledResult)}
+
+// add registers a spilled result and returns its cursor, dropping expired ones
+func (r *spillRegistry) add(spill *spilledResult) (string, error) {
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to create cursor: %w", err)
+	}
+	cursor := hex.EncodeToString(id)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expire()
+	spill.used = spillClock()
+	r.spills[cursor] = spill
+	return cursor, nil
+}
+
+// take returns the spilled result of a cursor on a database
+func (r *spillRegistry) take(dbID, cursor string) (*spilledResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expire()
+	spill, ok := r.spills[cursor]
+	if !ok || spill.dbID != dbID {
+		return nil, fmt.Errorf("cursor %q is unknown, fully read or expired", cursor)
+	}
+	return spill, nil
+}
+
+// done forgets a cursor and deletes its file
+func (r *spillRegistry) done(cursor string, spill *spilledResult) {
+	r.mu.Lock()
+	delete(r.spills, cursor)
+	r.mu.Unlock()
+	spill.remove()
+}
+
+// expire deletes the spilled results not read within spillTTL; r.mu must be held
+func (r *spillRegistry) expire() {
+	now := spillClock()
+	for cursor, spill := range r.spills {
+		if !spill.mu.TryLock() {
+			// Being read right now
+			continue
+		}
+		if now.Sub(spill.used) >= spillTTL {
+			delete(r.spills, cursor)
+			spill.remove()
+		}
+		spill.mu.Unlock()
+	}
+}
+
+// rowMemory estimates the bytes a row takes in memory
+func rowMemory(values []interface{}) int64 {
+	size := int64(24 + 16*len(values))
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			size += int64(len(v))
+		case []byte:
+			size += int64(len(v))
+		case time.Time:
+			size += 24
+		case domain.Geometry:
+			size += int64(len(v.WKT) + len(v.GeoJSON))
+		default:
+			size += 8
+		}
+	}
+	return size
+}
+
+// FetchRows reads the next rows, at most limit of them when limit is positive, of a result
+// whose rows past its memory limit were spilled to disk. The cursor stays valid until every
+// row was read, and the returned result carries it while rows remain.
+func (uc *DatabaseUseCase) FetchRows(ctx context.Context, dbID, cursor string, limit int) (*domain.QueryResult, error) {
+	spill, err := spills.take(dbID, cursor)
+	if err != nil {
+		return nil, err
+	}
+	// Concurrent reads of one cursor take turns; the registry never waits for a cursor
+	// being read, so holding it while forgetting the cursor cannot deadlock
+	spill.mu.Lock()
+	defer spill.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err := spill.read(limit)
+	if err != nil {
+		spills.done(cursor, spill)
+		return nil, err
+	}
+	result := &domain.QueryResult{Columns: spill.columns, Rows: rows, Remaining: spill.remaining}
+	if spill.remaining > 0 {
+		result.Cursor = cursor
+	} else {
+		spills.done(cursor, spill)
+	}
+	return result, nil
+}
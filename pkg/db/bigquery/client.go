@@ -0,0 +1,213 @@
+// Package bigquery is a database/sql driver for Google BigQuery built on its REST API. It
+// authenticates with a service-account key and runs GoogleSQL through jobs.query, so the
+// server can treat BigQuery like its other databases.
+package bigquery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/FreePeak/db-mcp-server/pkg/db/googleauth"
+)
+
+// defaultEndpoint is the base URL of the BigQuery REST API
+const defaultEndpoint = "https://bigquery.googleapis.com/bigquery/v2"
+
+// bigqueryScope is the OAuth scope requested for the service account
+const bigqueryScope = "https://www.googleapis.com/auth/bigquery"
+
+// pollTimeout is how long a single jobs.query or getQueryResults call waits for the job
+const pollTimeout = 10 * time.Second
+
+// client calls the BigQuery REST API of one project with a cached access token
+type client struct {
+	endpoint string
+	project  string
+	dataset  string
+	location string
+	http     *http.Client
+	tokens   *googleauth.TokenSource
+}
+
+// call sends an authorized request to the BigQuery API and decodes the JSON response
+func (c *client) call(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("bigquery: %w", err)
+	}
+	target := c.endpoint + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	var payload io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.do(req, out)
+}
+
+// do sends a request and decodes its JSON response, turning API errors into Go errors
+func (c *client) do(req *http.Request, out interface{}) error {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		// API errors carry an object with a message
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("bigquery: %s", apiErr.Error.Message)
+		}
+		return fmt.Errorf("bigquery: %s", resp.Status)
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// tableSchema is the schema of a query result
+type tableSchema struct {
+	Fields []fieldSchema `json:"fields"`
+}
+
+// fieldSchema is a column of a query result; records nest their own fields
+type fieldSchema struct {
+	Name   string        `json:"name"`
+	Type   string        `json:"type"`
+	Mode   string        `json:"mode"`
+	Fields []fieldSchema `json:"fields"`
+}
+
+// tableRow is a row of a query result in the API's f/v encoding
+type tableRow struct {
+	F []struct {
+		V interface{} `json:"v"`
+	} `json:"f"`
+}
+
+// queryResponse is the response of jobs.query and jobs.getQueryResults
+type queryResponse struct {
+	JobComplete  bool `json:"jobComplete"`
+	JobReference struct {
+		JobID    string `json:"jobId"`
+		Location string `json:"location"`
+	} `json:"jobReference"`
+	Schema              *tableSchema `json:"schema"`
+	Rows                []tableRow   `json:"rows"`
+	PageToken           string       `json:"pageToken"`
+	NumDmlAffectedRows  string       `json:"numDmlAffectedRows"`
+	TotalBytesProcessed string       `json:"totalBytesProcessed"`
+}
+
+// queryParameter is a positional parameter of a query
+type queryParameter struct {
+	ParameterType  parameterType  `json:"parameterType"`
+	ParameterValue parameterValue `json:"parameterValue"`
+}
+
+type parameterType struct {
+	Type string `json:"type"`
+}
+
+type parameterValue struct {
+	Value *string `json:"value"`
+}
+
+// startQuery runs a query through jobs.query, which waits for the job up to pollTimeout
+// and returns the first page of its result
+func (c *client) startQuery(ctx context.Context, query string, params []queryParameter, dryRun bool) (*queryResponse, error) {
+	body := map[string]interface{}{
+		"query":        query,
+		"useLegacySql": false,
+		"timeoutMs":    pollTimeout.Milliseconds(),
+		"dryRun":       dryRun,
+	}
+	if c.dataset != "" {
+		body["defaultDataset"] = map[string]string{"projectId": c.project, "datasetId": c.dataset}
+	}
+	if c.location != "" {
+		body["location"] = c.location
+	}
+	if len(params) > 0 {
+		body["parameterMode"] = "POSITIONAL"
+		body["queryParameters"] = params
+	}
+	var resp queryResponse
+	if err := c.call(ctx, http.MethodPost, "/projects/"+url.PathEscape(c.project)+"/queries", nil, body, &resp); err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return &resp, nil
+	}
+	for !resp.JobComplete {
+		next, err := c.queryResults(ctx, &resp, "")
+		if err != nil {
+			if ctx.Err() != nil {
+				c.cancelJob(&resp)
+			}
+			return nil, err
+		}
+		resp = *next
+	}
+	return &resp, nil
+}
+
+// cancelJob asks BigQuery to stop a job whose caller has gone away; it runs on its own
+// short deadline, as the caller's context is already done
+func (c *client) cancelJob(job *queryResponse) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	query := url.Values{}
+	if job.JobReference.Location != "" {
+		query.Set("location", job.JobReference.Location)
+	}
+	path := "/projects/" + url.PathEscape(c.project) + "/jobs/" + url.PathEscape(job.JobReference.JobID) + "/cancel"
+	var resp map[string]interface{}
+	_ = c.call(ctx, http.MethodPost, path, query, nil, &resp)
+}
+
+// queryResults fetches a page of a job's result, waiting for the job when it still runs
+func (c *client) queryResults(ctx context.Context, job *queryResponse, pageToken string) (*queryResponse, error) {
+	query := url.Values{}
+	query.Set("timeoutMs", fmt.Sprintf("%d", pollTimeout.Milliseconds()))
+	if job.JobReference.Location != "" {
+		query.Set("location", job.JobReference.Location)
+	}
+	if pageToken != "" {
+		query.Set("pageToken", pageToken)
+	}
+	path := "/projects/" + url.PathEscape(c.project) + "/queries/" + url.PathEscape(job.JobReference.JobID)
+	var resp queryResponse
+	if err := c.call(ctx, http.MethodGet, path, query, nil, &resp); err != nil {
+		return nil, err
+	}
+	if resp.JobReference.JobID == "" {
+		resp.JobReference = job.JobReference
+	}
+	return &resp, nil
+}
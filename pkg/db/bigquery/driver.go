@@ -0,0 +1,346 @@
+package bigquery
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/db-mcp-server/pkg/db/googleauth"
+)
+
+func init() {
+	sql.Register("bigquery", &Driver{})
+}
+
+// ErrNoTransactions is returned by Begin; BigQuery jobs run on their own
+var ErrNoTransactions = errors.New("bigquery: transactions are not supported")
+
+// Driver is the database/sql driver of BigQuery. Its DSN has the form
+// bigquery://project/dataset?credentials_file=key.json&location=EU; the project defaults to
+// the one of the key, and endpoint overrides the API URL, such as for an emulator.
+type Driver struct{}
+
+// Open opens a connection for a DSN
+func (d *Driver) Open(dsn string) (driver.Conn, error) {
+	connector, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(context.Background())
+}
+
+// OpenConnector parses a DSN and loads its key once, so the connections of a pool share
+// one access token
+func (d *Driver) OpenConnector(dsn string) (driver.Connector, error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil || parsed.Scheme != "bigquery" {
+		return nil, fmt.Errorf("bigquery: invalid DSN, expected bigquery://project/dataset")
+	}
+	params := parsed.Query()
+	httpClient := &http.Client{}
+	tokens, err := googleauth.NewTokenSource(params.Get("credentials_file"), bigqueryScope, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("bigquery: %w", err)
+	}
+	c := &client{
+		endpoint: strings.TrimRight(params.Get("endpoint"), "/"),
+		project:  parsed.Host,
+		dataset:  strings.Trim(parsed.Path, "/"),
+		location: params.Get("location"),
+		http:     httpClient,
+		tokens:   tokens,
+	}
+	if c.endpoint == "" {
+		c.endpoint = defaultEndpoint
+	}
+	if c.project == "" {
+		c.project = tokens.ProjectID()
+	}
+	if c.project == "" {
+		return nil, fmt.Errorf("bigquery: no project in the DSN or the service-account key")
+	}
+	return &connector{driver: d, client: c}, nil
+}
+
+type connector struct {
+	driver *Driver
+	client *client
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{client: c.client}, nil
+}
+
+func (c *connector) Driver() driver.Driver { return c.driver }
+
+// conn is a connection; BigQuery is stateless over HTTP, so it only holds the client
+type conn struct {
+	client *client
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query}, nil
+}
+
+func (c *conn) Close() error { return nil }
+
+func (c *conn) Begin() (driver.Tx, error) { return nil, ErrNoTransactions }
+
+// Ping checks the credentials and the dataset with a dry run, which is not billed
+func (c *conn) Ping(ctx context.Context) error {
+	_, err := c.client.startQuery(ctx, "SELECT 1", nil, true)
+	return err
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	params, err := queryParameters(args)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.startQuery(ctx, query, params, false)
+	if err != nil {
+		return nil, err
+	}
+	r := &rows{ctx: ctx, client: c.client, job: resp, page: resp.Rows, pageToken: resp.PageToken}
+	if resp.Schema != nil {
+		r.fields = resp.Schema.Fields
+	}
+	return r, nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	params, err := queryParameters(args)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.startQuery(ctx, query, params, false)
+	if err != nil {
+		return nil, err
+	}
+	affected, _ := strconv.ParseInt(resp.NumDmlAffectedRows, 10, 64)
+	return result{affected: affected}, nil
+}
+
+// stmt is a prepared statement; BigQuery has nothing to prepare, so it keeps the query text
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+func (s *stmt) Close() error { return nil }
+
+// NumInput returns -1; the API checks the parameter count
+func (s *stmt) NumInput() int { return -1 }
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+// result reports the rows a DML statement changed
+type result struct {
+	affected int64
+}
+
+func (r result) LastInsertId() (int64, error) {
+	return 0, errors.New("bigquery: LastInsertId is not supported")
+}
+
+func (r result) RowsAffected() (int64, error) { return r.affected, nil }
+
+// queryParameters converts arguments into positional query parameters
+func queryParameters(args []driver.NamedValue) ([]queryParameter, error) {
+	params := make([]queryParameter, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, fmt.Errorf("bigquery: named parameters are not supported, use ?")
+		}
+		var typ, value string
+		switch v := arg.Value.(type) {
+		case nil:
+			params[i] = queryParameter{ParameterType: parameterType{Type: "STRING"}}
+			continue
+		case int64:
+			typ, value = "INT64", strconv.FormatInt(v, 10)
+		case float64:
+			typ, value = "FLOAT64", strconv.FormatFloat(v, 'g', -1, 64)
+		case bool:
+			typ, value = "BOOL", strconv.FormatBool(v)
+		case string:
+			typ, value = "STRING", v
+		case []byte:
+			typ, value = "BYTES", base64.StdEncoding.EncodeToString(v)
+		case time.Time:
+			typ, value = "TIMESTAMP", v.Format("2006-01-02 15:04:05.999999-07:00")
+		default:
+			return nil, fmt.Errorf("bigquery: unsupported parameter type %T", arg.Value)
+		}
+		params[i] = queryParameter{ParameterType: parameterType{Type: typ}, ParameterValue: parameterValue{Value: &value}}
+	}
+	return params, nil
+}
+
+// rows reads a query result page by page
+type rows struct {
+	ctx       context.Context
+	client    *client
+	job       *queryResponse
+	fields    []fieldSchema
+	page      []tableRow
+	pos       int
+	pageToken string
+}
+
+func (r *rows) Columns() []string {
+	columns := make([]string, len(r.fields))
+	for i, field := range r.fields {
+		columns[i] = field.Name
+	}
+	return columns
+}
+
+// ColumnTypeDatabaseTypeName returns the GoogleSQL type of a column
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	field := r.fields[index]
+	if field.Mode == "REPEATED" {
+		return "ARRAY<" + field.Type + ">"
+	}
+	return field.Type
+}
+
+func (r *rows) Close() error { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	for r.pos >= len(r.page) {
+		if r.pageToken == "" {
+			return io.EOF
+		}
+		next, err := r.client.queryResults(r.ctx, r.job, r.pageToken)
+		if err != nil {
+			return err
+		}
+		r.page, r.pos, r.pageToken = next.Rows, 0, next.PageToken
+	}
+	row := r.page[r.pos]
+	r.pos++
+	for i, field := range r.fields {
+		if i >= len(row.F) {
+			dest[i] = nil
+			continue
+		}
+		value, err := convertValue(field, row.F[i].V)
+		if err != nil {
+			return fmt.Errorf("bigquery: column %s: %w", field.Name, err)
+		}
+		dest[i] = value
+	}
+	return nil
+}
+
+// convertValue converts a cell of the f/v encoding into a driver value. Arrays and records
+// become JSON text, as database/sql has no nested values.
+func convertValue(field fieldSchema, v interface{}) (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if field.Mode == "REPEATED" || field.Type == "RECORD" || field.Type == "STRUCT" {
+		raw, err := json.Marshal(nestedValue(field, v))
+		if err != nil {
+			return nil, err
+		}
+		return string(raw), nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value %v", v)
+	}
+	switch field.Type {
+	case "INTEGER", "INT64":
+		return strconv.ParseInt(s, 10, 64)
+	case "FLOAT", "FLOAT64":
+		return strconv.ParseFloat(s, 64)
+	case "BOOLEAN", "BOOL":
+		return s == "true", nil
+	case "TIMESTAMP":
+		// Timestamps arrive as seconds since the epoch with microsecond precision
+		seconds, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, err
+		}
+		whole, frac := math.Modf(seconds)
+		return time.Unix(int64(whole), int64(math.Round(frac*1e6))*1e3).UTC(), nil
+	case "BYTES":
+		return base64.StdEncoding.DecodeString(s)
+	default:
+		// NUMERIC, DATE, DATETIME, TIME, GEOGRAPHY, JSON and STRING keep their text
+		return s, nil
+	}
+}
+
+// nestedValue decodes arrays and records of the f/v encoding into plain Go values
+func nestedValue(field fieldSchema, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	if field.Mode == "REPEATED" {
+		items, _ := v.([]interface{})
+		element := field
+		element.Mode = "NULLABLE"
+		values := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			cell, _ := item.(map[string]interface{})
+			values = append(values, nestedValue(element, cell["v"]))
+		}
+		return values
+	}
+	if field.Type == "RECORD" || field.Type == "STRUCT" {
+		record, _ := v.(map[string]interface{})
+		cells, _ := record["f"].([]interface{})
+		values := make(map[string]interface{}, len(field.Fields))
+		for i, sub := range field.Fields {
+			if i < len(cells) {
+				cell, _ := cells[i].(map[string]interface{})
+				values[sub.Name] = nestedValue(sub, cell["v"])
+			}
+		}
+		return values
+	}
+	value, err := convertValue(field, v)
+	if err != nil {
+		return v
+	}
+	if b, ok := value.([]byte); ok {
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	return value
+}
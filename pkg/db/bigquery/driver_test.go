@@ -0,0 +1,181 @@
+package bigquery
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBigQuery serves the token endpoint and the query endpoints of the API
+type fakeBigQuery struct {
+	t      *testing.T
+	public *rsa.PublicKey
+
+	mu       sync.Mutex
+	tokens   int
+	requests []map[string]interface{}
+	polls    int
+}
+
+func (f *fakeBigQuery) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.URL.Path == "/token":
+		assert.NoError(f.t, r.ParseForm())
+		assert.Equal(f.t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.PostForm.Get("grant_type"))
+		parts := strings.Split(r.PostForm.Get("assertion"), ".")
+		assert.Len(f.t, parts, 3)
+		signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.NoError(f.t, rsa.VerifyPKCS1v15(f.public, crypto.SHA256, digest[:], signature))
+		f.tokens++
+		_, _ = w.Write([]byte(`{"access_token": "secret-token", "expires_in": 3600}`))
+	case r.Header.Get("Authorization") != "Bearer secret-token":
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error": {"message": "Request is missing a valid token"}}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/projects/shop-123/queries":
+		var body map[string]interface{}
+		assert.NoError(f.t, json.NewDecoder(r.Body).Decode(&body))
+		f.requests = append(f.requests, body)
+		if body["query"] == "DELETE FROM orders WHERE id = ?" {
+			_, _ = w.Write([]byte(`{"jobComplete": true, "jobReference": {"jobId": "job_2"}, "numDmlAffectedRows": "3"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jobComplete": false, "jobReference": {"jobId": "job_1", "location": "EU"}}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/projects/shop-123/queries/job_1":
+		assert.Equal(f.t, "EU", r.URL.Query().Get("location"))
+		f.polls++
+		schema := `"schema": {"fields": [
+			{"name": "id", "type": "INTEGER", "mode": "REQUIRED"},
+			{"name": "total", "type": "FLOAT"},
+			{"name": "paid", "type": "BOOLEAN"},
+			{"name": "created_at", "type": "TIMESTAMP"},
+			{"name": "tags", "type": "STRING", "mode": "REPEATED"},
+			{"name": "address", "type": "RECORD", "fields": [{"name": "city", "type": "STRING"}]}
+		]}`
+		if r.URL.Query().Get("pageToken") == "" {
+			_, _ = w.Write([]byte(`{"jobComplete": true, "jobReference": {"jobId": "job_1", "location": "EU"}, ` + schema + `, "pageToken": "p2", "rows": [
+				{"f": [{"v": "1"}, {"v": "12.5"}, {"v": "true"}, {"v": "1.7040672E9"}, {"v": [{"v": "new"}, {"v": "gift"}]}, {"v": {"f": [{"v": "Berlin"}]}}]}
+			]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jobComplete": true, "jobReference": {"jobId": "job_1", "location": "EU"}, ` + schema + `, "rows": [
+			{"f": [{"v": "2"}, {"v": null}, {"v": "false"}, {"v": null}, {"v": []}, {"v": null}]}
+		]}`))
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// newFakeBigQuery starts a fake API and writes a service-account key pointing at it
+func newFakeBigQuery(t *testing.T) (*fakeBigQuery, string) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	fake := &fakeBigQuery{t: t, public: &private.PublicKey}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("Failed to encode key: %v", err)
+	}
+	key, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"project_id":   "shop-123",
+		"client_email": "reader@shop-123.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    server.URL + "/token",
+	})
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := os.WriteFile(path, key, 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	return fake, "bigquery:///sales?location=EU&credentials_file=" + path + "&endpoint=" + server.URL
+}
+
+func TestQueryReadsEveryPage(t *testing.T) {
+	fake, dsn := newFakeBigQuery(t)
+	db, err := sql.Open("bigquery", dsn)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows, err := db.QueryContext(context.Background(), "SELECT * FROM orders WHERE total > ? AND status = ?", 10, "open")
+	assert.NoError(t, err)
+	defer rows.Close()
+	columns, _ := rows.Columns()
+	assert.Equal(t, []string{"id", "total", "paid", "created_at", "tags", "address"}, columns)
+
+	var got [][]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		assert.NoError(t, rows.Scan(pointers...))
+		got = append(got, values)
+	}
+	assert.NoError(t, rows.Err())
+	assert.Equal(t, [][]interface{}{
+		{int64(1), 12.5, true, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), `["new","gift"]`, `{"city":"Berlin"}`},
+		{int64(2), nil, false, nil, `[]`, nil},
+	}, got)
+
+	// The job was polled until complete, then the second page was fetched
+	assert.Equal(t, 2, fake.polls)
+	assert.Equal(t, 1, fake.tokens)
+	request := fake.requests[0]
+	assert.Equal(t, false, request["useLegacySql"])
+	assert.Equal(t, "EU", request["location"])
+	assert.Equal(t, map[string]interface{}{"projectId": "shop-123", "datasetId": "sales"}, request["defaultDataset"])
+	assert.Equal(t, "POSITIONAL", request["parameterMode"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"parameterType": map[string]interface{}{"type": "INT64"}, "parameterValue": map[string]interface{}{"value": "10"}},
+		map[string]interface{}{"parameterType": map[string]interface{}{"type": "STRING"}, "parameterValue": map[string]interface{}{"value": "open"}},
+	}, request["queryParameters"])
+}
+
+func TestExecReportsAffectedRows(t *testing.T) {
+	fake, dsn := newFakeBigQuery(t)
+	db, err := sql.Open("bigquery", dsn)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	result, err := db.Exec("DELETE FROM orders WHERE id = ?", 7)
+	assert.NoError(t, err)
+	affected, _ := result.RowsAffected()
+	assert.Equal(t, int64(3), affected)
+	_, err = db.Begin()
+	assert.ErrorIs(t, err, ErrNoTransactions)
+	assert.Equal(t, 1, fake.tokens)
+}
+
+func TestOpenConnectorValidatesDSN(t *testing.T) {
+	_, err := (&Driver{}).OpenConnector("mysql://shop/sales")
+	assert.Error(t, err)
+
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	_, err = (&Driver{}).OpenConnector("bigquery://shop/sales")
+	assert.ErrorContains(t, err, "credentials_file")
+}
@@ -0,0 +1,223 @@
+// Package databricks is a database/sql driver for Databricks SQL warehouses built on the SQL
+// Statement Execution API. A statement is posted to the workspace with a personal access
+// token, polled until it finishes, and its result is read chunk by chunk, so the server can
+// treat a warehouse like its other databases.
+package databricks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// waitTimeout is how long the workspace holds a request open for a statement to finish
+// before answering with its state
+const waitTimeout = "30s"
+
+// pollInterval is how long a client waits between polls of a running statement
+const pollInterval = 200 * time.Millisecond
+
+// client sends statements to one SQL warehouse of a workspace
+type client struct {
+	endpoint  string
+	token     string
+	warehouse string
+	catalog   string
+	schema    string
+	http      *http.Client
+}
+
+// parameter is a named parameter of a statement; a nil value is NULL
+type parameter struct {
+	Name  string  `json:"name"`
+	Value *string `json:"value,omitempty"`
+	Type  string  `json:"type,omitempty"`
+}
+
+// statementRequest is the body of a statement execution
+type statementRequest struct {
+	Statement     string      `json:"statement"`
+	WarehouseID   string      `json:"warehouse_id"`
+	Catalog       string      `json:"catalog,omitempty"`
+	Schema        string      `json:"schema,omitempty"`
+	Parameters    []parameter `json:"parameters,omitempty"`
+	WaitTimeout   string      `json:"wait_timeout"`
+	OnWaitTimeout string      `json:"on_wait_timeout"`
+	Disposition   string      `json:"disposition"`
+	Format        string      `json:"format"`
+}
+
+// column is a column of a statement's result
+type column struct {
+	Name     string `json:"name"`
+	TypeName string `json:"type_name"`
+	TypeText string `json:"type_text"`
+}
+
+// resultData is one chunk of a result; JSON_ARRAY rows hold every value as a string
+type resultData struct {
+	DataArray             [][]*string `json:"data_array"`
+	NextChunkInternalLink string      `json:"next_chunk_internal_link"`
+}
+
+// statementResponse is the state of a statement, with the first chunk of its result once it
+// has succeeded
+type statementResponse struct {
+	StatementID string `json:"statement_id"`
+	Status      struct {
+		State string `json:"state"`
+		Error *struct {
+			ErrorCode string `json:"error_code"`
+			Message   string `json:"message"`
+		} `json:"error"`
+	} `json:"status"`
+	Manifest *struct {
+		Schema struct {
+			Columns []column `json:"columns"`
+		} `json:"schema"`
+	} `json:"manifest"`
+	Result *resultData `json:"result"`
+}
+
+// running reports whether a statement has yet to finish
+func (r *statementResponse) running() bool {
+	return r.Status.State == "PENDING" || r.Status.State == "RUNNING"
+}
+
+// err returns the error of a statement that failed or was cancelled, or nil
+func (r *statementResponse) err() error {
+	switch r.Status.State {
+	case "SUCCEEDED", "PENDING", "RUNNING":
+		return nil
+	}
+	if r.Status.Error == nil {
+		return fmt.Errorf("databricks: statement %s is %s", r.StatementID, r.Status.State)
+	}
+	if r.Status.Error.ErrorCode != "" {
+		return fmt.Errorf("databricks: %s: %s", r.Status.Error.ErrorCode, r.Status.Error.Message)
+	}
+	return fmt.Errorf("databricks: %s", r.Status.Error.Message)
+}
+
+// columns returns the columns of a succeeded statement
+func (r *statementResponse) columns() []column {
+	if r.Manifest == nil {
+		return nil
+	}
+	return r.Manifest.Schema.Columns
+}
+
+// execute runs a statement on the warehouse and waits until it finishes. The workspace
+// answers within the wait timeout; a statement still running then is polled, and cancelled
+// when the caller goes away.
+func (c *client) execute(ctx context.Context, query string, params []parameter) (*statementResponse, error) {
+	body := statementRequest{
+		Statement:     query,
+		WarehouseID:   c.warehouse,
+		Catalog:       c.catalog,
+		Schema:        c.schema,
+		Parameters:    params,
+		WaitTimeout:   waitTimeout,
+		OnWaitTimeout: "CONTINUE",
+		Disposition:   "INLINE",
+		Format:        "JSON_ARRAY",
+	}
+	var resp statementResponse
+	if err := c.call(ctx, http.MethodPost, "/api/2.0/sql/statements/", body, &resp); err != nil {
+		return nil, err
+	}
+	for resp.running() {
+		select {
+		case <-ctx.Done():
+			c.cancel(resp.StatementID)
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+		if err := c.call(ctx, http.MethodGet, "/api/2.0/sql/statements/"+resp.StatementID, nil, &resp); err != nil {
+			if ctx.Err() != nil {
+				c.cancel(resp.StatementID)
+			}
+			return nil, err
+		}
+	}
+	if err := resp.err(); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// chunk fetches the following chunk of a result from its internal link
+func (c *client) chunk(ctx context.Context, link string) (*resultData, error) {
+	var data resultData
+	if err := c.call(ctx, http.MethodGet, link, nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// cancel stops a statement whose caller has gone away; it runs on its own short deadline,
+// as the caller's context is already done
+func (c *client) cancel(statementID string) {
+	if statementID == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = c.call(ctx, http.MethodPost, "/api/2.0/sql/statements/"+statementID+"/cancel", nil, nil)
+}
+
+// call sends a request with the access token and decodes its JSON response
+func (c *client) call(ctx context.Context, method, path string, body, out interface{}) error {
+	var payload io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		// API errors carry an error code and a message
+		var apiErr struct {
+			ErrorCode string `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("databricks: %s: %s", apiErr.ErrorCode, apiErr.Message)
+		}
+		message := strings.TrimSpace(string(raw))
+		if message == "" {
+			message = resp.Status
+		}
+		return fmt.Errorf("databricks: %s", message)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("databricks: failed to decode response: %w", err)
+	}
+	return nil
+}
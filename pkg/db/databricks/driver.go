@@ -0,0 +1,299 @@
+package databricks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	sql.Register("databricks", &Driver{})
+}
+
+// ErrNoTransactions is returned by Begin; statements run on their own
+var ErrNoTransactions = errors.New("databricks: transactions are not supported")
+
+// Driver is the database/sql driver of Databricks SQL warehouses. Its DSN has the form
+// databricks://token:<personal access token>@host/sql/1.0/warehouses/<id>?catalog=main&schema=default,
+// where the path is the HTTP path of the warehouse; endpoint overrides the workspace URL,
+// such as for a proxy.
+type Driver struct{}
+
+// Open opens a connection for a DSN
+func (d *Driver) Open(dsn string) (driver.Conn, error) {
+	connector, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(context.Background())
+}
+
+// OpenConnector parses a DSN
+func (d *Driver) OpenConnector(dsn string) (driver.Connector, error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil || parsed.Scheme != "databricks" || parsed.Host == "" {
+		return nil, fmt.Errorf("databricks: invalid DSN, expected databricks://token:<token>@host/<http path>")
+	}
+	params := parsed.Query()
+	c := &client{
+		endpoint: strings.TrimRight(params.Get("endpoint"), "/"),
+		catalog:  params.Get("catalog"),
+		schema:   params.Get("schema"),
+		http:     &http.Client{},
+	}
+	if c.endpoint == "" {
+		c.endpoint = "https://" + parsed.Host
+	}
+	if parsed.User != nil {
+		c.token, _ = parsed.User.Password()
+	}
+	if c.token == "" {
+		return nil, fmt.Errorf("databricks: the DSN has no access token")
+	}
+	// The HTTP path of a warehouse ends with its ID, as in /sql/1.0/warehouses/<id>
+	dir, id := path.Split(strings.TrimRight(parsed.Path, "/"))
+	if id == "" || !(strings.HasSuffix(dir, "/warehouses/") || strings.HasSuffix(dir, "/endpoints/")) {
+		return nil, fmt.Errorf("databricks: the HTTP path %q does not name a SQL warehouse", parsed.Path)
+	}
+	c.warehouse = id
+	return &connector{driver: d, client: c}, nil
+}
+
+type connector struct {
+	driver *Driver
+	client *client
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{client: c.client}, nil
+}
+
+func (c *connector) Driver() driver.Driver { return c.driver }
+
+// conn is a connection; statements run over stateless HTTP, so it only holds the client
+type conn struct {
+	client *client
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query}, nil
+}
+
+func (c *conn) Close() error { return nil }
+
+func (c *conn) Begin() (driver.Tx, error) { return nil, ErrNoTransactions }
+
+// Ping runs a trivial statement, which also starts a stopped warehouse
+func (c *conn) Ping(ctx context.Context) error {
+	_, err := c.client.execute(ctx, "SELECT 1", nil)
+	return err
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	params, err := queryParameters(args)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.execute(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+	r := &rows{ctx: ctx, client: c.client, columns: resp.columns()}
+	if resp.Result != nil {
+		r.page, r.nextLink = resp.Result.DataArray, resp.Result.NextChunkInternalLink
+	}
+	return r, nil
+}
+
+// ExecContext runs a statement; DML statements answer with a row whose num_affected_rows
+// column counts the rows they changed
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	params, err := queryParameters(args)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.execute(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+	var affected int64
+	if resp.Result != nil && len(resp.Result.DataArray) > 0 {
+		row := resp.Result.DataArray[0]
+		for i, col := range resp.columns() {
+			if col.Name == "num_affected_rows" && i < len(row) && row[i] != nil {
+				affected, _ = strconv.ParseInt(*row[i], 10, 64)
+			}
+		}
+	}
+	return result{affected: affected}, nil
+}
+
+// stmt is a prepared statement; the warehouse parses it when it runs
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+func (s *stmt) Close() error { return nil }
+
+// NumInput returns -1; the warehouse checks the parameters
+func (s *stmt) NumInput() int { return -1 }
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+// result reports the rows a statement changed
+type result struct {
+	affected int64
+}
+
+func (r result) LastInsertId() (int64, error) {
+	return 0, errors.New("databricks: LastInsertId is not supported")
+}
+
+func (r result) RowsAffected() (int64, error) { return r.affected, nil }
+
+// queryParameters converts arguments into named parameters. The API only knows named
+// markers, so an unnamed argument binds to :p1, :p2 and so on by its position.
+func queryParameters(args []driver.NamedValue) ([]parameter, error) {
+	params := make([]parameter, len(args))
+	for i, arg := range args {
+		p := parameter{Name: arg.Name}
+		if p.Name == "" {
+			p.Name = "p" + strconv.Itoa(arg.Ordinal)
+		}
+		var value string
+		switch v := arg.Value.(type) {
+		case nil:
+			p.Type = "VOID"
+			params[i] = p
+			continue
+		case int64:
+			value, p.Type = strconv.FormatInt(v, 10), "BIGINT"
+		case float64:
+			value, p.Type = strconv.FormatFloat(v, 'g', -1, 64), "DOUBLE"
+		case bool:
+			value, p.Type = strconv.FormatBool(v), "BOOLEAN"
+		case string:
+			value, p.Type = v, "STRING"
+		case []byte:
+			value, p.Type = string(v), "STRING"
+		case time.Time:
+			value, p.Type = v.UTC().Format("2006-01-02T15:04:05.999999Z"), "TIMESTAMP"
+		default:
+			return nil, fmt.Errorf("databricks: unsupported parameter type %T", arg.Value)
+		}
+		p.Value = &value
+		params[i] = p
+	}
+	return params, nil
+}
+
+// rows reads a statement's result chunk by chunk
+type rows struct {
+	ctx      context.Context
+	client   *client
+	columns  []column
+	page     [][]*string
+	nextLink string
+	pos      int
+}
+
+func (r *rows) Columns() []string {
+	names := make([]string, len(r.columns))
+	for i, col := range r.columns {
+		names[i] = col.Name
+	}
+	return names
+}
+
+// ColumnTypeDatabaseTypeName returns the Databricks type of a column
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	return strings.ToUpper(r.columns[index].TypeName)
+}
+
+// Close drops the chunks that were not read; the statement has already finished
+func (r *rows) Close() error {
+	r.page, r.nextLink = nil, ""
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	for r.pos >= len(r.page) {
+		if r.nextLink == "" {
+			return io.EOF
+		}
+		next, err := r.client.chunk(r.ctx, r.nextLink)
+		if err != nil {
+			return err
+		}
+		r.page, r.nextLink, r.pos = next.DataArray, next.NextChunkInternalLink, 0
+	}
+	row := r.page[r.pos]
+	r.pos++
+	for i, col := range r.columns {
+		if i >= len(row) {
+			dest[i] = nil
+			continue
+		}
+		value, err := convertValue(col, row[i])
+		if err != nil {
+			return fmt.Errorf("databricks: column %s: %w", col.Name, err)
+		}
+		dest[i] = value
+	}
+	return nil
+}
+
+// convertValue converts a value of a JSON_ARRAY result, which arrives as text, into a
+// driver value
+func convertValue(col column, v *string) (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch col.TypeName {
+	case "LONG", "INT", "SHORT", "BYTE":
+		return strconv.ParseInt(*v, 10, 64)
+	case "DOUBLE", "FLOAT":
+		return strconv.ParseFloat(*v, 64)
+	case "BOOLEAN":
+		return strconv.ParseBool(*v)
+	case "BINARY":
+		return base64.StdEncoding.DecodeString(*v)
+	default:
+		// Decimals, dates, timestamps, intervals, strings and the JSON text of arrays,
+		// maps and structs keep their text
+		return *v, nil
+	}
+}
@@ -9,10 +9,17 @@ import (
 	"strings"
 	"time"
 
+	// Registers the bigquery, databricks, hana, spanner and trino drivers
+	_ "github.com/FreePeak/db-mcp-server/pkg/db/bigquery"
+	_ "github.com/FreePeak/db-mcp-server/pkg/db/databricks"
+	_ "github.com/FreePeak/db-mcp-server/pkg/db/hana"
+	_ "github.com/FreePeak/db-mcp-server/pkg/db/spanner"
+	_ "github.com/FreePeak/db-mcp-server/pkg/db/trino"
 	"github.com/FreePeak/db-mcp-server/pkg/logger"
 	// Import database drivers
-	_ "github.com/go-sql-driver/mysql"
-	_ "github.com/lib/pq"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
 // Common database errors
@@ -24,6 +31,60 @@ var (
 	ErrNoDatabase     = errors.New("no database connection")
 )
 
+// ClickHouse is reached through its MySQL-compatible interface, TiDB through the MySQL
+// protocol and CockroachDB, YugabyteDB and Greenplum through the PostgreSQL wire protocol. Their drivers
+// are registered again under the engine's name, so the pool reports which engine it serves.
+func init() {
+	sql.Register("clickhouse", &mysql.MySQLDriver{})
+	sql.Register("tidb", &mysql.MySQLDriver{})
+	sql.Register("cockroachdb", &pq.Driver{})
+	sql.Register("yugabyte", &pq.Driver{})
+	sql.Register("greenplum", &pq.Driver{})
+}
+
+// driverRegistered reports whether a database/sql driver is registered under a name
+func driverRegistered(name string) bool {
+	for _, registered := range sql.Drivers() {
+		if registered == name {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultClickHousePort is the port of ClickHouse's MySQL-compatible interface
+const defaultClickHousePort = 9004
+
+// defaultCockroachDBPort is the SQL port of a CockroachDB node
+const defaultCockroachDBPort = 26257
+
+// defaultYugabytePort is the YSQL port of a YugabyteDB tablet server
+const defaultYugabytePort = 5433
+
+// defaultHANAPort is the SQL port of the first tenant database of a SAP HANA system
+const defaultHANAPort = 30015
+
+// defaultFirebirdPort is the port of a Firebird server
+const defaultFirebirdPort = 3050
+
+// defaultTiDBPort is the SQL port of a TiDB server
+const defaultTiDBPort = 4000
+
+// defaultTrinoPort is the HTTP port of a Trino coordinator
+const defaultTrinoPort = 8080
+
+// defaultDatabricksPort is the HTTPS port of a Databricks workspace
+const defaultDatabricksPort = 443
+
+// defaultMongoDBPort is the port of a mongod or mongos server
+const defaultMongoDBPort = 27017
+
+// defaultRedisPort is the port of a Redis server
+const defaultRedisPort = 6379
+
+// defaultSearchPort is the HTTP port of an Elasticsearch or OpenSearch node
+const defaultSearchPort = 9200
+
 // PostgresSSLMode defines the SSL mode for PostgreSQL connections
 type PostgresSSLMode string
 
@@ -55,11 +116,28 @@ type Config struct {
 	TargetSessionAttrs string            // for PostgreSQL 10+
 	Options            map[string]string // Extra connection options
 
+	// BigQuery options; Name is the default dataset
+	Project         string
+	CredentialsFile string // service-account JSON key, defaults to $GOOGLE_APPLICATION_CREDENTIALS
+	Location        string
+
+	// Spanner instance; Spanner shares Project and CredentialsFile, and Name is the database
+	Instance string
+
+	// Databricks options; HTTPPath names the SQL warehouse, Token is a personal access
+	// token, and Name is the default catalog
+	HTTPPath string
+	Token    string
+
 	// Connection pool settings
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
 	ConnMaxIdleTime time.Duration
+
+	// StatementCacheSize caps the prepared statements kept for parameterized queries;
+	// a negative size turns the cache off
+	StatementCacheSize int
 }
 
 // SetDefaults sets default values for the configuration if they are not set
@@ -76,12 +154,15 @@ func (c *Config) SetDefaults() {
 	if c.ConnMaxIdleTime == 0 {
 		c.ConnMaxIdleTime = 5 * time.Minute
 	}
-	if c.Type == "postgres" && c.SSLMode == "" {
+	if (c.Type == "postgres" || c.Type == "cockroachdb" || c.Type == "yugabyte" || c.Type == "greenplum") && c.SSLMode == "" {
 		c.SSLMode = SSLDisable
 	}
 	if c.ConnectTimeout == 0 {
 		c.ConnectTimeout = 10 // Default 10 seconds
 	}
+	if c.StatementCacheSize == 0 {
+		c.StatementCacheSize = 64
+	}
 }
 
 // Database represents a generic database interface
@@ -113,6 +194,7 @@ type database struct {
 	db         *sql.DB
 	driverName string
 	dsn        string
+	stmts      *stmtCache
 }
 
 // buildPostgresConnStr builds a PostgreSQL connection string with all options
@@ -172,6 +254,136 @@ func buildPostgresConnStr(config Config) string {
 	return strings.Join(params, " ")
 }
 
+// buildSQLiteConnStr builds a SQLite connection string; the database name is the path of the
+// database file. Foreign keys are enforced and writers wait on locks unless options say otherwise.
+func buildSQLiteConnStr(config Config) string {
+	params := url.Values{}
+	params.Set("_foreign_keys", "on")
+	params.Set("_busy_timeout", fmt.Sprintf("%d", config.ConnectTimeout*1000))
+	for key, value := range config.Options {
+		params.Set(key, value)
+	}
+	return "file:" + config.Name + "?" + params.Encode()
+}
+
+// buildBigQueryConnStr builds a BigQuery DSN; the database name is the default dataset
+func buildBigQueryConnStr(config Config) string {
+	params := url.Values{}
+	if config.CredentialsFile != "" {
+		params.Set("credentials_file", config.CredentialsFile)
+	}
+	if config.Location != "" {
+		params.Set("location", config.Location)
+	}
+	for key, value := range config.Options {
+		params.Set(key, value)
+	}
+	dsn := "bigquery://" + config.Project + "/" + config.Name
+	if len(params) > 0 {
+		dsn += "?" + params.Encode()
+	}
+	return dsn
+}
+
+// buildSpannerConnStr builds a Spanner DSN; options such as emulator_host pass to the driver
+func buildSpannerConnStr(config Config) string {
+	params := url.Values{}
+	if config.CredentialsFile != "" {
+		params.Set("credentials_file", config.CredentialsFile)
+	}
+	for key, value := range config.Options {
+		params.Set(key, value)
+	}
+	dsn := "spanner://" + config.Project + "/" + config.Instance + "/" + config.Name
+	if len(params) > 0 {
+		dsn += "?" + params.Encode()
+	}
+	return dsn
+}
+
+// buildHANAConnStr builds a SAP HANA DSN; the database name is the default schema, and
+// options such as TLSRootCAFile pass to the driver
+func buildHANAConnStr(config Config) string {
+	params := url.Values{}
+	if config.Name != "" {
+		params.Set("schema", config.Name)
+	}
+	if config.ConnectTimeout > 0 {
+		params.Set("timeout", fmt.Sprintf("%d", config.ConnectTimeout))
+	}
+	for key, value := range config.Options {
+		params.Set(key, value)
+	}
+	dsn := (&url.URL{
+		Scheme: "hana",
+		User:   url.UserPassword(config.User, config.Password),
+		Host:   fmt.Sprintf("%s:%d", config.Host, config.Port),
+	}).String()
+	if len(params) > 0 {
+		dsn += "?" + params.Encode()
+	}
+	return dsn
+}
+
+// buildFirebirdConnStr builds a Firebird DSN for the firebirdsql driver; the database name
+// is the path of the database file on the server or an alias from databases.conf, and
+// options such as role, wire_crypt and auth_plugin_name pass to the driver
+func buildFirebirdConnStr(config Config) string {
+	params := url.Values{}
+	for key, value := range config.Options {
+		params.Set(key, value)
+	}
+	dsn := fmt.Sprintf("%s@%s:%d/%s", url.UserPassword(config.User, config.Password).String(),
+		config.Host, config.Port, strings.TrimPrefix(config.Name, "/"))
+	if len(params) > 0 {
+		dsn += "?" + params.Encode()
+	}
+	return dsn
+}
+
+// buildTrinoConnStr builds a Trino DSN; the database name is the default catalog, and
+// options such as schema and secure pass to the driver
+func buildTrinoConnStr(config Config) string {
+	params := url.Values{}
+	if config.Name != "" {
+		params.Set("catalog", config.Name)
+	}
+	for key, value := range config.Options {
+		params.Set(key, value)
+	}
+	user := url.User(config.User)
+	if config.Password != "" {
+		user = url.UserPassword(config.User, config.Password)
+	}
+	dsn := fmt.Sprintf("trino://%s@%s:%d", user.String(), config.Host, config.Port)
+	if len(params) > 0 {
+		dsn += "?" + params.Encode()
+	}
+	return dsn
+}
+
+// buildDatabricksConnStr builds a Databricks DSN; the database name is the default catalog,
+// and options such as schema pass to the driver
+func buildDatabricksConnStr(config Config) string {
+	params := url.Values{}
+	if config.Name != "" {
+		params.Set("catalog", config.Name)
+	}
+	for key, value := range config.Options {
+		params.Set(key, value)
+	}
+	dsn := (&url.URL{
+		Scheme: "databricks",
+		User:   url.UserPassword("token", config.Token),
+		Host:   fmt.Sprintf("%s:%d", config.Host, config.Port),
+		Path:   "/" + strings.TrimPrefix(config.HTTPPath, "/"),
+	}).String()
+	if len(params) > 0 {
+		dsn += "?" + params.Encode()
+	}
+	return dsn
+}
+
 // NewDatabase creates a new database connection based on the provided configuration
 func NewDatabase(config Config) (Database, error) {
 	// Set default values for the configuration
@@ -184,11 +396,118 @@ func NewDatabase(config Config) (Database, error) {
 	switch config.Type {
 	case "mysql":
 		driverName = "mysql"
-		dsn = fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true",
+		// Arguments are never interpolated client-side: parameterized queries are prepared
+		// on the server and their arguments travel in the binary protocol
+		dsn = fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&interpolateParams=false",
+			config.User, config.Password, config.Host, config.Port, config.Name)
+	case "tidb":
+		driverName = "tidb"
+		if config.Port == 0 {
+			config.Port = defaultTiDBPort
+		}
+		dsn = fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&interpolateParams=false",
 			config.User, config.Password, config.Host, config.Port, config.Name)
 	case "postgres":
 		driverName = "postgres"
 		dsn = buildPostgresConnStr(config)
+	case "cockroachdb":
+		driverName = "cockroachdb"
+		if config.Port == 0 {
+			config.Port = defaultCockroachDBPort
+		}
+		dsn = buildPostgresConnStr(config)
+	case "yugabyte":
+		driverName = "yugabyte"
+		if config.Port == 0 {
+			config.Port = defaultYugabytePort
+		}
+		dsn = buildPostgresConnStr(config)
+	case "greenplum":
+		// The coordinator listens on the PostgreSQL port
+		driverName = "greenplum"
+		dsn = buildPostgresConnStr(config)
+	case "sqlite":
+		driverName = "sqlite3"
+		dsn = buildSQLiteConnStr(config)
+	case "clickhouse":
+		driverName = "clickhouse"
+		if config.Port == 0 {
+			config.Port = defaultClickHousePort
+		}
+		// The MySQL interface of ClickHouse cannot prepare statements, so arguments are
+		// interpolated client-side and the statement cache stays off
+		config.StatementCacheSize = -1
+		dsn = fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&interpolateParams=true",
+			config.User, config.Password, config.Host, config.Port, config.Name)
+	case "bigquery":
+		driverName = "bigquery"
+		// Queries go to the REST API, where there is nothing to prepare
+		config.StatementCacheSize = -1
+		dsn = buildBigQueryConnStr(config)
+	case "spanner":
+		driverName = "spanner"
+		// Statements run through the REST API of a session, where there is nothing to prepare
+		config.StatementCacheSize = -1
+		dsn = buildSpannerConnStr(config)
+	case "hana":
+		driverName = "hana"
+		if config.Port == 0 {
+			config.Port = defaultHANAPort
+		}
+		dsn = buildHANAConnStr(config)
+	case "firebird":
+		driverName = "firebirdsql"
+		if config.Port == 0 {
+			config.Port = defaultFirebirdPort
+		}
+		// Firebird is reached through the firebirdsql driver, which a binary links in by
+		// importing github.com/nakagami/firebirdsql
+		if !driverRegistered(driverName) {
+			return nil, fmt.Errorf("firebird: no database/sql driver is registered as %s: %w", driverName, ErrNotImplemented)
+		}
+		dsn = buildFirebirdConnStr(config)
+	case "trino":
+		driverName = "trino"
+		if config.Port == 0 {
+			config.Port = defaultTrinoPort
+		}
+		// Arguments are bound by the coordinator when the statement runs
+		config.StatementCacheSize = -1
+		dsn = buildTrinoConnStr(config)
+	case "databricks":
+		driverName = "databricks"
+		if config.Port == 0 {
+			config.Port = defaultDatabricksPort
+		}
+		// Statements run through the statement execution API, where there is nothing to prepare
+		config.StatementCacheSize = -1
+		dsn = buildDatabricksConnStr(config)
+	case "mongodb":
+		// MongoDB holds documents and is reached through its own driver, not database/sql
+		if config.Port == 0 {
+			config.Port = defaultMongoDBPort
+		}
+		return &documentDatabase{config: config, uri: buildMongoURI(config)}, nil
+	case "redis":
+		// Redis holds keys and speaks its own protocol
+		if config.Port == 0 {
+			config.Port = defaultRedisPort
+		}
+		options, err := buildRedisOptions(config)
+		if err != nil {
+			return nil, err
+		}
+		return &keyValueDatabase{config: config, options: options}, nil
+	case "elasticsearch", "opensearch":
+		// Elasticsearch and OpenSearch hold indexed documents and are reached over REST
+		if config.Port == 0 {
+			config.Port = defaultSearchPort
+		}
+		search, err := newSearchDatabase(config)
+		if err != nil {
+			return nil, err
+		}
+		return search, nil
 	default:
 		return nil, fmt.Errorf("unsupported database type: %s", config.Type)
 	}
@@ -226,6 +545,9 @@ func (d *database) Connect() error {
 	}
 
 	d.db = db
+	if d.config.StatementCacheSize > 0 {
+		d.stmts = newStmtCache(d.config.StatementCacheSize)
+	}
 	logger.Info("Connected to %s database at %s:%d/%s", d.config.Type, d.config.Host, d.config.Port, d.config.Name)
 
 	return nil
@@ -236,6 +558,9 @@ func (d *database) Close() error {
 	if d.db == nil {
 		return nil
 	}
+	if d.stmts != nil {
+		d.stmts.close()
+	}
 	if err := d.db.Close(); err != nil {
 		logger.Error("Error closing database connection: %v", err)
 		return err
@@ -251,11 +576,18 @@ func (d *database) Ping(ctx context.Context) error {
 	return d.db.PingContext(ctx)
 }
 
-// Query executes a query that returns rows
+// Query executes a query that returns rows; parameterized queries run as cached prepared statements
 func (d *database) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
 	if d.db == nil {
 		return nil, ErrNoDatabase
 	}
+	var rows *sql.Rows
+	if ran, err := d.runPrepared(ctx, query, args, func(stmt *sql.Stmt) (err error) {
+		rows, err = stmt.QueryContext(ctx, args...)
+		return err
+	}); ran {
+		return rows, err
+	}
 	return d.db.QueryContext(ctx, query, args...)
 }
 
@@ -264,17 +596,71 @@ func (d *database) QueryRow(ctx context.Context, query string, args ...interface
 	if d.db == nil {
 		return nil
 	}
+	var row *sql.Row
+	if ran, _ := d.runPrepared(ctx, query, args, func(stmt *sql.Stmt) error {
+		row = stmt.QueryRowContext(ctx, args...)
+		return row.Err()
+	}); ran {
+		return row
+	}
 	return d.db.QueryRowContext(ctx, query, args...)
 }
 
-// Exec executes a query without returning any rows
+// Exec executes a query without returning any rows; parameterized queries run as cached
+// prepared statements
 func (d *database) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
 	if d.db == nil {
 		return nil, ErrNoDatabase
 	}
+	var result sql.Result
+	if ran, err := d.runPrepared(ctx, query, args, func(stmt *sql.Stmt) (err error) {
+		result, err = stmt.ExecContext(ctx, args...)
+		return err
+	}); ran {
+		return result, err
+	}
 	return d.db.ExecContext(ctx, query, args...)
 }
 
+// prepared returns the cached prepared statement of a parameterized query, or nil when the
+// query has no arguments, the cache is off or the server cannot prepare it. Such queries run
+// directly, where the driver still sends arguments separately through an unnamed statement.
+// A returned statement must be released once it has run.
+func (d *database) prepared(ctx context.Context, query string, args []interface{}) *cachedStmt {
+	if d.stmts == nil || len(args) == 0 {
+		return nil
+	}
+	entry, err := d.stmts.get(ctx, d.db, query)
+	if err != nil {
+		logger.Debug("Running query without a cached statement: %v", err)
+		return nil
+	}
+	return entry
+}
+
+// runPrepared runs a parameterized query through its cached statement and reports whether it
+// did; when it did not, the caller runs the query directly. A statement whose plan went stale
+// is evicted and the query prepared again, and so is one closed under the call, once.
+func (d *database) runPrepared(ctx context.Context, query string, args []interface{}, run func(stmt *sql.Stmt) error) (bool, error) {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		entry := d.prepared(ctx, query, args)
+		if entry == nil {
+			return false, nil
+		}
+		err = run(entry.stmt)
+		d.stmts.release(entry)
+		if staleStatementError(err) {
+			d.stmts.evict(entry)
+			continue
+		}
+		if !closedStatementError(err) {
+			break
+		}
+	}
+	return true, err
+}
+
 // BeginTx starts a transaction
 func (d *database) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
 	if d.db == nil {
@@ -297,10 +683,10 @@ func (d *database) DriverName() string {
 func (d *database) ConnectionString() string {
 	// Return masked DSN (hide password)
 	switch d.config.Type {
-	case "mysql":
+	case "mysql", "tidb", "clickhouse":
 		return fmt.Sprintf("%s:***@tcp(%s:%d)/%s",
 			d.config.User, d.config.Host, d.config.Port, d.config.Name)
-	case "postgres":
+	case "postgres", "cockroachdb", "yugabyte", "greenplum":
 		// Create a sanitized version of the connection string
 		params := make([]string, 0)
 
@@ -319,6 +705,21 @@ func (d *database) ConnectionString() string {
 		}
 
 		return strings.Join(params, " ")
+	case "sqlite":
+		return "file:" + d.config.Name
+	case "bigquery":
+		return "bigquery://" + d.config.Project + "/" + d.config.Name
+	case "spanner":
+		return "spanner://" + d.config.Project + "/" + d.config.Instance + "/" + d.config.Name
+	case "hana":
+		return fmt.Sprintf("hana://%s:***@%s:%d?schema=%s", d.config.User, d.config.Host, d.config.Port, d.config.Name)
+	case "firebird":
+		return fmt.Sprintf("%s:***@%s:%d/%s", d.config.User, d.config.Host, d.config.Port, d.config.Name)
+	case "trino":
+		return fmt.Sprintf("trino://%s@%s:%d/%s", d.config.User, d.config.Host, d.config.Port, d.config.Name)
+	case "databricks":
+		return fmt.Sprintf("databricks://token:***@%s:%d/%s?catalog=%s", d.config.Host, d.config.Port,
+			strings.TrimPrefix(d.config.HTTPPath, "/"), d.config.Name)
 	default:
 		return "unknown"
 	}
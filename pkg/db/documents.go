@@ -0,0 +1,189 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/db-mcp-server/pkg/db/mongodb"
+	"github.com/FreePeak/db-mcp-server/pkg/logger"
+)
+
+// DocumentStore is implemented by databases whose collections hold documents instead of rows.
+// Documents are in canonical Extended JSON, such as {"_id": {"$oid": "..."}}.
+type DocumentStore interface {
+	ListCollections(ctx context.Context) ([]map[string]interface{}, error)
+	SampleDocuments(ctx context.Context, collection string, size int) ([]map[string]interface{}, error)
+	CollectionStats(ctx context.Context, collection string) (map[string]interface{}, error)
+	ListIndexes(ctx context.Context, collection string) ([]map[string]interface{}, error)
+	Aggregate(ctx context.Context, collection, pipeline string, limit int) ([]map[string]interface{}, error)
+}
+
+// ErrNoSQL is returned when SQL is run on a database that does not speak it, such as a
+// document database or a key-value store
+var ErrNoSQL = errors.New("the database does not run SQL; use its collection or key tools")
+
+// noSQL is a pool whose connections fail with ErrNoSQL; database/sql has no other way to
+// build a *sql.Row that holds an error
+var noSQL = sql.OpenDB(noSQLConnector{})
+
+type noSQLConnector struct{}
+
+func (noSQLConnector) Connect(context.Context) (driver.Conn, error) { return nil, ErrNoSQL }
+
+func (noSQLConnector) Driver() driver.Driver { return noSQLDriver{} }
+
+type noSQLDriver struct{}
+
+func (noSQLDriver) Open(string) (driver.Conn, error) { return nil, ErrNoSQL }
+
+// documentDatabase is a MongoDB database; it implements DocumentStore, and its SQL methods
+// fail with ErrNoSQL
+type documentDatabase struct {
+	config Config
+	uri    string
+	store  *mongodb.Store
+}
+
+// buildMongoURI builds the URI of a MongoDB connection. A host that already is a mongodb://
+// or mongodb+srv:// URI is used as it is; otherwise the options, such as authSource or
+// replicaSet, become its query.
+func buildMongoURI(config Config) string {
+	if strings.HasPrefix(config.Host, "mongodb://") || strings.HasPrefix(config.Host, "mongodb+srv://") {
+		return config.Host
+	}
+	params := url.Values{}
+	params.Set("connectTimeoutMS", fmt.Sprint(config.ConnectTimeout*1000))
+	if config.MaxOpenConns > 0 {
+		params.Set("maxPoolSize", fmt.Sprint(config.MaxOpenConns))
+	}
+	for key, value := range config.Options {
+		params.Set(key, value)
+	}
+	credentials := ""
+	if config.User != "" {
+		credentials = url.UserPassword(config.User, config.Password).String() + "@"
+	}
+	return fmt.Sprintf("mongodb://%s%s:%d/?%s", credentials, config.Host, config.Port, params.Encode())
+}
+
+// Connect connects to the deployment and checks that it answers
+func (d *documentDatabase) Connect() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(d.config.ConnectTimeout)*time.Second)
+	defer cancel()
+	store, err := mongodb.Open(ctx, d.uri, d.config.Name)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	d.store = store
+	logger.Info("Connected to %s database at %s:%d/%s", d.config.Type, d.config.Host, d.config.Port, d.config.Name)
+	return nil
+}
+
+// Close disconnects from the deployment
+func (d *documentDatabase) Close() error {
+	if d.store == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := d.store.Close(ctx); err != nil {
+		logger.Error("Error closing database connection: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Ping checks that the deployment answers
+func (d *documentDatabase) Ping(ctx context.Context) error {
+	if d.store == nil {
+		return ErrNoDatabase
+	}
+	return d.store.Ping(ctx)
+}
+
+// Query fails with ErrNoSQL
+func (d *documentDatabase) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, ErrNoSQL
+}
+
+// QueryRow returns a row that fails with ErrNoSQL
+func (d *documentDatabase) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return noSQL.QueryRowContext(ctx, query, args...)
+}
+
+// Exec fails with ErrNoSQL
+func (d *documentDatabase) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, ErrNoSQL
+}
+
+// BeginTx fails with ErrNoSQL
+func (d *documentDatabase) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return nil, ErrNoSQL
+}
+
+// DriverName returns mongodb
+func (d *documentDatabase) DriverName() string {
+	return "mongodb"
+}
+
+// ConnectionString returns the URI without the password
+func (d *documentDatabase) ConnectionString() string {
+	if strings.HasPrefix(d.config.Host, "mongodb") {
+		if parsed, err := url.Parse(d.config.Host); err == nil {
+			return parsed.Redacted()
+		}
+		return "mongodb://***"
+	}
+	return fmt.Sprintf("mongodb://%s:***@%s:%d/%s", d.config.User, d.config.Host, d.config.Port, d.config.Name)
+}
+
+// DB returns nil; a document database has no SQL pool
+func (d *documentDatabase) DB() *sql.DB {
+	return nil
+}
+
+// ListCollections lists the collections of the database
+func (d *documentDatabase) ListCollections(ctx context.Context) ([]map[string]interface{}, error) {
+	if d.store == nil {
+		return nil, ErrNoDatabase
+	}
+	return d.store.ListCollections(ctx)
+}
+
+// SampleDocuments returns up to size documents of a collection picked at random
+func (d *documentDatabase) SampleDocuments(ctx context.Context, collection string, size int) ([]map[string]interface{}, error) {
+	if d.store == nil {
+		return nil, ErrNoDatabase
+	}
+	return d.store.SampleDocuments(ctx, collection, size)
+}
+
+// CollectionStats returns the collStats output of a collection
+func (d *documentDatabase) CollectionStats(ctx context.Context, collection string) (map[string]interface{}, error) {
+	if d.store == nil {
+		return nil, ErrNoDatabase
+	}
+	return d.store.CollectionStats(ctx, collection)
+}
+
+// ListIndexes returns the index specifications of a collection
+func (d *documentDatabase) ListIndexes(ctx context.Context, collection string) ([]map[string]interface{}, error) {
+	if d.store == nil {
+		return nil, ErrNoDatabase
+	}
+	return d.store.ListIndexes(ctx, collection)
+}
+
+// Aggregate runs an aggregation pipeline and returns up to limit documents
+func (d *documentDatabase) Aggregate(ctx context.Context, collection, pipeline string, limit int) ([]map[string]interface{}, error) {
+	if d.store == nil {
+		return nil, ErrNoDatabase
+	}
+	return d.store.Aggregate(ctx, collection, pipeline, limit)
+}
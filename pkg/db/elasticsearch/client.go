@@ -0,0 +1,175 @@
+// Package elasticsearch is a small client for the REST API shared by Elasticsearch and
+// OpenSearch. It covers the calls the index tools need: listing indices, reading mappings
+// and statistics, and running query DSL searches.
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultPort is the HTTP port of an Elasticsearch or OpenSearch node
+const DefaultPort = 9200
+
+// catColumns are the _cat/indices columns ListIndices returns
+const catColumns = "health,status,index,uuid,pri,rep,docs.count,docs.deleted,store.size,pri.store.size"
+
+// Options configures a client
+type Options struct {
+	// URL is the base URL of a node, such as https://search.internal:9200
+	URL      string
+	Username string
+	Password string
+	// APIKey, when set, authenticates with an encoded API key instead of the password
+	APIKey string
+	HTTP   *http.Client
+}
+
+// Client sends requests to one cluster
+type Client struct {
+	base    string
+	options Options
+}
+
+// New creates a client for the node at options.URL
+func New(options Options) (*Client, error) {
+	base, err := url.Parse(strings.TrimSuffix(options.URL, "/"))
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("elasticsearch: invalid URL %q, expected http(s)://host:port", options.URL)
+	}
+	if options.HTTP == nil {
+		options.HTTP = http.DefaultClient
+	}
+	return &Client{base: base.String(), options: options}, nil
+}
+
+// Info returns the name, cluster and version of the node
+func (c *Client) Info(ctx context.Context) (map[string]interface{}, error) {
+	var info map[string]interface{}
+	err := c.send(ctx, http.MethodGet, "/", nil, "", &info)
+	return info, err
+}
+
+// ListIndices returns the _cat/indices row of every index matching pattern, sorted by name;
+// hidden indices, such as those of data streams or system features, are only listed when
+// includeHidden is set
+func (c *Client) ListIndices(ctx context.Context, pattern string, includeHidden bool) ([]map[string]interface{}, error) {
+	query := url.Values{"format": {"json"}, "bytes": {"b"}, "h": {catColumns}, "s": {"index"}}
+	if includeHidden {
+		query.Set("expand_wildcards", "all")
+	} else {
+		query.Set("expand_wildcards", "open,closed")
+	}
+	var indices []map[string]interface{}
+	err := c.send(ctx, http.MethodGet, "/_cat/indices/"+indexPath(pattern), query, "", &indices)
+	return indices, err
+}
+
+// Mappings returns the mappings of every index matching pattern, by index name
+func (c *Client) Mappings(ctx context.Context, pattern string) (map[string]interface{}, error) {
+	var mappings map[string]interface{}
+	err := c.send(ctx, http.MethodGet, "/"+indexPath(pattern)+"/_mapping", nil, "", &mappings)
+	return mappings, err
+}
+
+// IndexStats returns the _stats output of the indices matching pattern
+func (c *Client) IndexStats(ctx context.Context, pattern string) (map[string]interface{}, error) {
+	var stats map[string]interface{}
+	err := c.send(ctx, http.MethodGet, "/"+indexPath(pattern)+"/_stats", nil, "", &stats)
+	return stats, err
+}
+
+// Search runs a query DSL request body on the indices matching pattern and returns the
+// response, with its hits and aggregations
+func (c *Client) Search(ctx context.Context, pattern, body string) (map[string]interface{}, error) {
+	var response map[string]interface{}
+	err := c.send(ctx, http.MethodPost, "/"+indexPath(pattern)+"/_search", nil, body, &response)
+	return response, err
+}
+
+// patternUnescaper keeps the wildcards and commas of index patterns readable in paths
+var patternUnescaper = strings.NewReplacer("%2A", "*", "%2C", ",")
+
+// indexPath escapes an index pattern, such as logs-*,metrics-*, for a URL path
+func indexPath(pattern string) string {
+	if pattern == "" {
+		return "_all"
+	}
+	return patternUnescaper.Replace(url.PathEscape(pattern))
+}
+
+// send sends a request to an escaped path and decodes the JSON response into out. Numbers
+// are kept as json.Number, so large integers keep their precision.
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body string, out interface{}) error {
+	target := c.base + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.options.APIKey != "":
+		req.Header.Set("Authorization", "ApiKey "+c.options.APIKey)
+	case c.options.Username != "":
+		req.SetBasicAuth(c.options.Username, c.options.Password)
+	}
+
+	resp, err := c.options.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("elasticsearch: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("elasticsearch: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return responseError(resp.Status, raw)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(out); err != nil {
+		return fmt.Errorf("elasticsearch: failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// responseError builds the error of a failed request from the error object of its body, such
+// as {"error": {"type": "index_not_found_exception", "reason": "no such index [logs]"}}
+func responseError(status string, raw []byte) error {
+	var body struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(raw, &body) == nil && len(body.Error) > 0 {
+		var cause struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		}
+		if json.Unmarshal(body.Error, &cause) == nil && cause.Type != "" {
+			return fmt.Errorf("elasticsearch: %s: %s", cause.Type, cause.Reason)
+		}
+		var message string
+		if json.Unmarshal(body.Error, &message) == nil {
+			return fmt.Errorf("elasticsearch: %s", message)
+		}
+	}
+	message := strings.TrimSpace(string(raw))
+	if message == "" {
+		message = status
+	}
+	return fmt.Errorf("elasticsearch: %s", message)
+}
@@ -0,0 +1,82 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordedRequest is what the fake cluster saw of a request
+type recordedRequest struct {
+	method, path, query, auth, body string
+}
+
+func newFakeCluster(t *testing.T, status int, response string) (*httptest.Server, *[]recordedRequest) {
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{r.Method, r.URL.EscapedPath(), r.URL.RawQuery, r.Header.Get("Authorization"), string(body)})
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestListIndices(t *testing.T) {
+	server, requests := newFakeCluster(t, http.StatusOK, `[{"index": "logs-1", "docs.count": "12", "store.size": "2048"}]`)
+	client, err := New(Options{URL: server.URL + "/", Username: "elastic", Password: "secret"})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	indices, err := client.ListIndices(context.Background(), "logs-*,metrics-*", false)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"index": "logs-1", "docs.count": "12", "store.size": "2048"}}, indices)
+
+	request := (*requests)[0]
+	assert.Equal(t, "GET", request.method)
+	assert.Equal(t, "/_cat/indices/logs-*,metrics-*", request.path)
+	assert.Equal(t, "bytes=b&expand_wildcards=open%2Cclosed&format=json&h=health%2Cstatus%2Cindex%2Cuuid%2Cpri%2Crep%2Cdocs.count%2Cdocs.deleted%2Cstore.size%2Cpri.store.size&s=index", request.query)
+	assert.Equal(t, "Basic ZWxhc3RpYzpzZWNyZXQ=", request.auth)
+}
+
+func TestSearchKeepsNumbers(t *testing.T) {
+	server, requests := newFakeCluster(t, http.StatusOK, `{"took": 3, "hits": {"hits": [{"_id": "1", "_source": {"id": 9007199254740993}}]}}`)
+	client, err := New(Options{URL: server.URL, APIKey: "a2V5OnNlY3JldA=="})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	response, err := client.Search(context.Background(), "orders", `{"size": 1}`)
+	assert.NoError(t, err)
+	hit := response["hits"].(map[string]interface{})["hits"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, json.Number("9007199254740993"), hit["_source"].(map[string]interface{})["id"])
+
+	request := (*requests)[0]
+	assert.Equal(t, "POST", request.method)
+	assert.Equal(t, "/orders/_search", request.path)
+	assert.Equal(t, `{"size": 1}`, request.body)
+	assert.Equal(t, "ApiKey a2V5OnNlY3JldA==", request.auth)
+}
+
+func TestResponseErrors(t *testing.T) {
+	server, _ := newFakeCluster(t, http.StatusNotFound, `{"error": {"root_cause": [], "type": "index_not_found_exception", "reason": "no such index [orders]"}, "status": 404}`)
+	client, _ := New(Options{URL: server.URL})
+	_, err := client.Mappings(context.Background(), "orders")
+	assert.EqualError(t, err, "elasticsearch: index_not_found_exception: no such index [orders]")
+
+	server, _ = newFakeCluster(t, http.StatusUnauthorized, "")
+	client, _ = New(Options{URL: server.URL})
+	_, err = client.IndexStats(context.Background(), "")
+	assert.EqualError(t, err, "elasticsearch: 401 Unauthorized")
+
+	_, err = New(Options{URL: "search.internal:9200"})
+	assert.EqualError(t, err, `elasticsearch: invalid URL "search.internal:9200", expected http(s)://host:port`)
+}
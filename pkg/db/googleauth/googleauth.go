@@ -0,0 +1,170 @@
+// Package googleauth gets OAuth access tokens for Google Cloud APIs from a service-account
+// key, so the REST drivers of BigQuery and Spanner need no Google client library.
+package googleauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// serviceAccountKey holds the fields of a service-account JSON key used to sign tokens
+type serviceAccountKey struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// TokenSource hands out access tokens for one scope, exchanging a freshly signed JWT once
+// the cached token is about to expire
+type TokenSource struct {
+	key    serviceAccountKey
+	signer *rsa.PrivateKey
+	scope  string
+	http   *http.Client
+
+	mu         sync.Mutex
+	token      string
+	tokenUntil time.Time
+}
+
+// NewTokenSource reads a service-account key file, falling back to
+// $GOOGLE_APPLICATION_CREDENTIALS when no path is given
+func NewTokenSource(path, scope string, httpClient *http.Client) (*TokenSource, error) {
+	var key serviceAccountKey
+	if path == "" {
+		path = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	if path == "" {
+		return nil, fmt.Errorf("no service-account key: set credentials_file or GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service-account key: %w", err)
+	}
+	if err := json.Unmarshal(raw, &key); err != nil {
+		return nil, fmt.Errorf("failed to parse service-account key: %w", err)
+	}
+	if key.Type != "service_account" || key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, fmt.Errorf("%s is not a service-account key", path)
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	block, _ := pem.Decode([]byte(key.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("service-account key has no PEM private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse service-account private key: %w", err)
+	}
+	signer, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("service-account private key is not an RSA key")
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &TokenSource{key: key, signer: signer, scope: scope, http: httpClient}, nil
+}
+
+// ProjectID returns the project the service account belongs to
+func (s *TokenSource) ProjectID() string {
+	return s.key.ProjectID
+}
+
+// Token returns a cached access token, or a new one when it is about to expire
+func (s *TokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && time.Now().Before(s.tokenUntil) {
+		return s.token, nil
+	}
+
+	now := time.Now()
+	assertion, err := s.signJWT(map[string]interface{}{
+		"iss":   s.key.ClientEmail,
+		"scope": s.scope,
+		"aud":   s.key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.key.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get access token: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to get access token: %w", err)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		Description string `json:"error_description"`
+	}
+	if err := json.Unmarshal(raw, &token); err != nil || resp.StatusCode >= 300 {
+		if token.Description != "" {
+			return "", fmt.Errorf("failed to get access token: %s", token.Description)
+		}
+		return "", fmt.Errorf("failed to get access token: %s", resp.Status)
+	}
+	s.token = token.AccessToken
+	// Renew a minute early so a token never expires during a request
+	s.tokenUntil = now.Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return s.token, nil
+}
+
+// signJWT signs claims with the service-account key using RS256
+func (s *TokenSource) signJWT(claims map[string]interface{}) (string, error) {
+	header := map[string]string{"alg": "RS256", "typ": "JWT", "kid": s.key.PrivateKeyID}
+	encode := func(v interface{}) (string, error) {
+		raw, err := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(raw), err
+	}
+	h, err := encode(header)
+	if err != nil {
+		return "", err
+	}
+	p, err := encode(claims)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256([]byte(h + "." + p))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.signer, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token request: %w", err)
+	}
+	return h + "." + p + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,145 @@
+package db
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/db-mcp-server/pkg/db/redis"
+	"github.com/FreePeak/db-mcp-server/pkg/logger"
+)
+
+// KeyValueStore is implemented by databases that hold keys instead of tables. Command replies
+// are strings, int64s, nil for null replies and []interface{} for arrays.
+type KeyValueStore interface {
+	Command(ctx context.Context, args ...string) (interface{}, error)
+}
+
+// keyValueDatabase is a Redis database; it implements KeyValueStore, and its SQL methods fail
+// with ErrNoSQL
+type keyValueDatabase struct {
+	config  Config
+	options redis.Options
+	client  *redis.Client
+}
+
+// buildRedisOptions builds the client options of a Redis connection. A host that already is a
+// redis:// or rediss:// URL is used as it is; otherwise Name is the logical database number
+// and the tls option turns TLS on.
+func buildRedisOptions(config Config) (redis.Options, error) {
+	var options redis.Options
+	if strings.HasPrefix(config.Host, "redis://") || strings.HasPrefix(config.Host, "rediss://") {
+		parsed, err := redis.ParseURL(config.Host)
+		if err != nil {
+			return redis.Options{}, err
+		}
+		options = parsed
+	} else {
+		options = redis.Options{
+			Addr:     net.JoinHostPort(config.Host, strconv.Itoa(config.Port)),
+			Username: config.User,
+			Password: config.Password,
+		}
+		if config.Name != "" {
+			db, err := strconv.Atoi(config.Name)
+			if err != nil || db < 0 {
+				return redis.Options{}, fmt.Errorf("redis database name must be a database number, got %q", config.Name)
+			}
+			options.DB = db
+		}
+		if config.Options["tls"] == "true" {
+			options.TLS = &tls.Config{ServerName: config.Host}
+		}
+	}
+	options.DialTimeout = time.Duration(config.ConnectTimeout) * time.Second
+	options.PoolSize = config.MaxIdleConns
+	return options, nil
+}
+
+// Connect opens the client and checks that the server answers
+func (d *keyValueDatabase) Connect() error {
+	client := redis.New(d.options)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(d.config.ConnectTimeout)*time.Second)
+	defer cancel()
+	if _, err := client.Do(ctx, "PING"); err != nil {
+		client.Close()
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	d.client = client
+	logger.Info("Connected to %s database at %s db %d", d.config.Type, d.options.Addr, d.options.DB)
+	return nil
+}
+
+// Close closes the connections of the client
+func (d *keyValueDatabase) Close() error {
+	if d.client == nil {
+		return nil
+	}
+	return d.client.Close()
+}
+
+// Ping checks that the server answers
+func (d *keyValueDatabase) Ping(ctx context.Context) error {
+	_, err := d.Command(ctx, "PING")
+	return err
+}
+
+// Query fails with ErrNoSQL
+func (d *keyValueDatabase) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, ErrNoSQL
+}
+
+// QueryRow returns a row that fails with ErrNoSQL
+func (d *keyValueDatabase) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return noSQL.QueryRowContext(ctx, query, args...)
+}
+
+// Exec fails with ErrNoSQL
+func (d *keyValueDatabase) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, ErrNoSQL
+}
+
+// BeginTx fails with ErrNoSQL
+func (d *keyValueDatabase) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return nil, ErrNoSQL
+}
+
+// DriverName returns redis
+func (d *keyValueDatabase) DriverName() string {
+	return "redis"
+}
+
+// ConnectionString returns the URL of the server without the password
+func (d *keyValueDatabase) ConnectionString() string {
+	scheme := "redis"
+	if d.options.TLS != nil {
+		scheme = "rediss"
+	}
+	connURL := url.URL{Scheme: scheme, Host: d.options.Addr, Path: "/" + strconv.Itoa(d.options.DB)}
+	if d.options.Password != "" {
+		connURL.User = url.UserPassword(d.options.Username, "***")
+	} else if d.options.Username != "" {
+		connURL.User = url.User(d.options.Username)
+	}
+	// UserPassword escapes the mask
+	return strings.Replace(connURL.String(), "%2A%2A%2A@", "***@", 1)
+}
+
+// DB returns nil; a key-value database has no SQL pool
+func (d *keyValueDatabase) DB() *sql.DB {
+	return nil
+}
+
+// Command sends a command, such as SCAN 0 MATCH user:*, and returns its reply
+func (d *keyValueDatabase) Command(ctx context.Context, args ...string) (interface{}, error) {
+	if d.client == nil {
+		return nil, ErrNoDatabase
+	}
+	return d.client.Do(ctx, args...)
+}
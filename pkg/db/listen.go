@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/FreePeak/db-mcp-server/pkg/logger"
+)
+
+// Listener is implemented by databases that can deliver asynchronous notifications
+type Listener interface {
+	// Listen calls handle with the payload of every notification sent on channel until ctx is
+	// cancelled. An empty payload is also delivered after the listening connection was
+	// re-established, since notifications sent meanwhile are lost.
+	Listen(ctx context.Context, channel string, handle func(payload string)) error
+}
+
+// Listen subscribes to a PostgreSQL notification channel on a dedicated connection outside the pool
+func (d *database) Listen(ctx context.Context, channel string, handle func(payload string)) error {
+	if d.driverName != "postgres" {
+		return fmt.Errorf("notifications are not supported on %s", d.driverName)
+	}
+
+	listener := pq.NewListener(d.dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			logger.Warn("Notification listener of %s: %v", d.config.Name, err)
+		}
+	})
+	defer func() {
+		if err := listener.Close(); err != nil {
+			logger.Error("Error closing notification listener: %v", err)
+		}
+	}()
+	if err := listener.Listen(channel); err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", channel, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-listener.Notify:
+			if notification == nil {
+				// The connection was re-established
+				handle("")
+				continue
+			}
+			handle(notification.Extra)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"container/list"
 	"encoding/json"
 	"fmt"
 	"sync"
@@ -12,14 +13,61 @@ import (
 // DatabaseConnectionConfig represents a single database connection configuration
 type DatabaseConnectionConfig struct {
 	ID          string `json:"id"`   // Unique identifier for this connection
-	Type        string `json:"type"` // mysql or postgres
+	Type        string `json:"type"` // mysql, tidb, postgres, cockroachdb, yugabyte, greenplum, sqlite, clickhouse, bigquery, spanner, hana, firebird, trino, databricks, mongodb, redis, elasticsearch or opensearch
 	Host        string `json:"host"`
 	Port        int    `json:"port"`
 	User        string `json:"user"`
 	Password    string `json:"password"`
-	Name        string `json:"name"`
+	Name        string `json:"name"`        // Database name, or the database file path for sqlite
 	Description string `json:"description"` // Optional human-readable description of this connection
 
+	// Directory holding versioned .sql migrations (defaults to $MIGRATIONS_DIR/<id>)
+	MigrationsDir string `json:"migrations_dir,omitempty"`
+
+	// Directory storing schema snapshots (defaults to $SNAPSHOTS_DIR/<id>)
+	SnapshotsDir string `json:"snapshots_dir,omitempty"`
+
+	// Directory storing logical backups (defaults to $BACKUPS_DIR/<id>)
+	BackupsDir string `json:"backups_dir,omitempty"`
+
+	// Enable administrative tools such as manage_users (defaults to false)
+	AllowAdmin bool `json:"allow_admin,omitempty"`
+
+	// Seconds a single query or statement may run before it is cancelled (defaults to $QUERY_TIMEOUT, 0 for no limit)
+	QueryTimeout int `json:"query_timeout,omitempty"`
+
+	// Seconds schema metadata stays cached (defaults to $SCHEMA_CACHE_TTL or 300, negative to disable)
+	SchemaCacheTTL int `json:"schema_cache_ttl,omitempty"`
+
+	// Keep the schema cache warm from a background crawler (defaults to $WARM_SCHEMA_CACHE)
+	WarmSchemaCache bool `json:"warm_schema_cache,omitempty"`
+
+	// Listen for schema changes published by PostgreSQL event triggers (defaults to $SCHEMA_CHANGE_EVENTS)
+	SchemaChangeEvents bool `json:"schema_change_events,omitempty"`
+
+	// Bytes of rows a query result keeps in memory before spilling the rest to disk
+	// (defaults to $RESULT_MEMORY_LIMIT or 8 MiB, negative to keep every row in memory)
+	ResultMemoryLimit int64 `json:"result_memory_limit,omitempty"`
+
+	// Tool calls running at once on this database; more queue
+	// (defaults to $MAX_CONCURRENT_TOOLS_PER_DATABASE or 8, negative for no limit)
+	MaxConcurrentTools int `json:"max_concurrent_tools,omitempty"`
+
+	// Connection failures in a row that make tool calls fail fast, and seconds
+	// before one probes the database again (default to $CIRCUIT_BREAKER_FAILURES or 5 and
+	// $CIRCUIT_BREAKER_COOLDOWN or 30, negative failures to disable)
+	CircuitBreakerFailures int `json:"circuit_breaker_failures,omitempty"`
+	CircuitBreakerCooldown int `json:"circuit_breaker_cooldown,omitempty"`
+
+	// IANA time zone timestamps of query results are converted to (defaults to $RESULT_TIMEZONE or UTC)
+	Timezone string `json:"timezone,omitempty"`
+
+	// Rows, bytes and query seconds one MCP session may consume (default to $SESSION_MAX_ROWS,
+	// $SESSION_MAX_BYTES and $SESSION_MAX_QUERY_SECONDS, 0 for no limit)
+	SessionMaxRows         int64 `json:"session_max_rows,omitempty"`
+	SessionMaxBytes        int64 `json:"session_max_bytes,omitempty"`
+	SessionMaxQuerySeconds int   `json:"session_max_query_seconds,omitempty"`
+
 	// PostgreSQL specific options
 	SSLMode            string            `json:"ssl_mode,omitempty"`
 	SSLCert            string            `json:"ssl_cert,omitempty"`
@@ -30,16 +78,54 @@ type DatabaseConnectionConfig struct {
 	TargetSessionAttrs string            `json:"target_session_attrs,omitempty"`
 	Options            map[string]string `json:"options,omitempty"`
 
+	// BigQuery specific options; name is the default dataset, and the project defaults to
+	// the one of the service-account key
+	Project         string `json:"project,omitempty"`
+	CredentialsFile string `json:"credentials_file,omitempty"`
+	Location        string `json:"location,omitempty"`
+
+	// Spanner specific options; it shares project and credentials_file with BigQuery, and
+	// name is the database
+	Instance string `json:"instance,omitempty"`
+
+	// Databricks specific options; http_path names the SQL warehouse, token is a personal
+	// access token, and name is the default catalog
+	HTTPPath string `json:"http_path,omitempty"`
+	Token    string `json:"token,omitempty"`
+
 	// Connection pool settings
 	MaxOpenConns    int `json:"max_open_conns,omitempty"`
 	MaxIdleConns    int `json:"max_idle_conns,omitempty"`
 	ConnMaxLifetime int `json:"conn_max_lifetime_seconds,omitempty"`  // in seconds
 	ConnMaxIdleTime int `json:"conn_max_idle_time_seconds,omitempty"` // in seconds
+
+	// Prepared statements kept for parameterized queries (defaults to 64, negative to disable)
+	StatementCacheSize int `json:"statement_cache_size,omitempty"`
 }
 
 // MultiDBConfig represents the configuration for multiple database connections
 type MultiDBConfig struct {
 	Connections []DatabaseConnectionConfig `json:"connections"`
+
+	// Connection pools kept open at once; 0 opens every pool at startup and keeps it open
+	MaxOpenPools int `json:"max_open_pools,omitempty"`
+
+	// Tool calls running at once across all databases; more queue
+	// (defaults to $MAX_CONCURRENT_TOOLS or 32, negative for no limit)
+	MaxConcurrentTools int `json:"max_concurrent_tools,omitempty"`
+}
+
+// poolEvictionGrace is how long a pool stays open after it was handed out, so a caller
+// that has not started its query yet does not find it closed
+const poolEvictionGrace = time.Second
+
+// poolClock returns the current time; tests replace it
+var poolClock = time.Now
+
+// poolEntry records when an open pool was last handed out
+type poolEntry struct {
+	id   string
+	used time.Time
 }
 
 // Manager manages multiple database connections
@@ -47,6 +133,13 @@ type Manager struct {
 	mu          sync.RWMutex
 	connections map[string]Database
 	configs     map[string]DatabaseConnectionConfig
+
+	// With maxOpen set, pools open on first use and the least recently used idle
+	// ones are closed once more than maxOpen are open
+	maxOpen int
+	order   *list.List
+	entries map[string]*list.Element
+	open    func(id string, cfg DatabaseConnectionConfig) (Database, error)
 }
 
 // NewDBManager creates a new database manager
@@ -54,6 +147,9 @@ func NewDBManager() *Manager {
 	return &Manager{
 		connections: make(map[string]Database),
 		configs:     make(map[string]DatabaseConnectionConfig),
+		order:       list.New(),
+		entries:     make(map[string]*list.Element),
+		open:        openDatabase,
 	}
 }
 
@@ -69,20 +165,29 @@ func (m *Manager) LoadConfig(configJSON []byte) error {
 		if conn.ID == "" {
 			return fmt.Errorf("database connection ID cannot be empty")
 		}
-		if conn.Type != "mysql" && conn.Type != "postgres" {
+		switch conn.Type {
+		case "mysql", "tidb", "postgres", "cockroachdb", "yugabyte", "greenplum", "sqlite", "clickhouse", "bigquery", "spanner", "hana", "firebird", "trino", "databricks", "mongodb", "redis", "elasticsearch", "opensearch":
+		default:
 			return fmt.Errorf("unsupported database type for connection %s: %s", conn.ID, conn.Type)
 		}
 		m.configs[conn.ID] = conn
 	}
+	m.maxOpen = config.MaxOpenPools
 
 	return nil
 }
 
-// Connect establishes connections to all configured databases
+// Connect establishes connections to all configured databases. When the number of open
+// pools is limited, pools are opened on first use instead.
 func (m *Manager) Connect() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if m.maxOpen > 0 {
+		logger.Info("Opening at most %d of %d database connection pools on demand", m.maxOpen, len(m.configs))
+		return nil
+	}
+
 	// Connect to each database
 	for id, cfg := range m.configs {
 		// Skip if already connected
@@ -90,72 +195,223 @@ func (m *Manager) Connect() error {
 			continue
 		}
 
-		// Create database configuration
-		dbConfig := Config{
-			Type:     cfg.Type,
-			Host:     cfg.Host,
-			Port:     cfg.Port,
-			User:     cfg.User,
-			Password: cfg.Password,
-			Name:     cfg.Name,
-		}
-
-		// Set PostgreSQL-specific options if this is a PostgreSQL database
-		if cfg.Type == "postgres" {
-			dbConfig.SSLMode = PostgresSSLMode(cfg.SSLMode)
-			dbConfig.SSLCert = cfg.SSLCert
-			dbConfig.SSLKey = cfg.SSLKey
-			dbConfig.SSLRootCert = cfg.SSLRootCert
-			dbConfig.ApplicationName = cfg.ApplicationName
-			dbConfig.ConnectTimeout = cfg.ConnectTimeout
-			dbConfig.TargetSessionAttrs = cfg.TargetSessionAttrs
-			dbConfig.Options = cfg.Options
-		}
-
-		// Connection pool settings
-		if cfg.MaxOpenConns > 0 {
-			dbConfig.MaxOpenConns = cfg.MaxOpenConns
-		}
-		if cfg.MaxIdleConns > 0 {
-			dbConfig.MaxIdleConns = cfg.MaxIdleConns
-		}
-		if cfg.ConnMaxLifetime > 0 {
-			dbConfig.ConnMaxLifetime = time.Duration(cfg.ConnMaxLifetime) * time.Second
-		}
-		if cfg.ConnMaxIdleTime > 0 {
-			dbConfig.ConnMaxIdleTime = time.Duration(cfg.ConnMaxIdleTime) * time.Second
-		}
-
-		// Create and connect to database
-		db, err := NewDatabase(dbConfig)
+		db, err := m.open(id, cfg)
 		if err != nil {
-			return fmt.Errorf("failed to create database instance for %s: %w", id, err)
-		}
-
-		if err := db.Connect(); err != nil {
-			return fmt.Errorf("failed to connect to database %s: %w", id, err)
+			return err
 		}
 
 		// Store connected database
 		m.connections[id] = db
+		m.touch(id)
 		logger.Info("Connected to database %s (%s at %s:%d/%s)", id, cfg.Type, cfg.Host, cfg.Port, cfg.Name)
 	}
 
 	return nil
 }
 
-// GetDatabase retrieves a database connection by ID
+// openDatabase creates and connects the database of a connection configuration
+func openDatabase(id string, cfg DatabaseConnectionConfig) (Database, error) {
+	// Create database configuration
+	dbConfig := Config{
+		Type:     cfg.Type,
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+		User:     cfg.User,
+		Password: cfg.Password,
+		Name:     cfg.Name,
+	}
+
+	// Set PostgreSQL-specific options if this is a PostgreSQL, CockroachDB, YugabyteDB or Greenplum database
+	if cfg.Type == "postgres" || cfg.Type == "cockroachdb" || cfg.Type == "yugabyte" || cfg.Type == "greenplum" {
+		dbConfig.SSLMode = PostgresSSLMode(cfg.SSLMode)
+		dbConfig.SSLCert = cfg.SSLCert
+		dbConfig.SSLKey = cfg.SSLKey
+		dbConfig.SSLRootCert = cfg.SSLRootCert
+		dbConfig.ApplicationName = cfg.ApplicationName
+		dbConfig.ConnectTimeout = cfg.ConnectTimeout
+		dbConfig.TargetSessionAttrs = cfg.TargetSessionAttrs
+		dbConfig.Options = cfg.Options
+	}
+
+	// SQLite takes driver options such as _journal_mode or mode=ro from the options map, and
+	// waits up to the connect timeout on a locked database file
+	if cfg.Type == "sqlite" {
+		dbConfig.ConnectTimeout = cfg.ConnectTimeout
+		dbConfig.Options = cfg.Options
+	}
+
+	// BigQuery authenticates with a service-account key rather than a user and password
+	if cfg.Type == "bigquery" {
+		dbConfig.Project = cfg.Project
+		dbConfig.CredentialsFile = cfg.CredentialsFile
+		dbConfig.Location = cfg.Location
+		dbConfig.Options = cfg.Options
+	}
+
+	// Spanner authenticates with a service-account key too, or reaches the emulator given by
+	// the emulator_host option
+	if cfg.Type == "spanner" {
+		dbConfig.Project = cfg.Project
+		dbConfig.Instance = cfg.Instance
+		dbConfig.CredentialsFile = cfg.CredentialsFile
+		dbConfig.Options = cfg.Options
+	}
+
+	// SAP HANA takes TLS settings such as TLSRootCAFile from the options map; the name is
+	// the default schema
+	if cfg.Type == "hana" {
+		dbConfig.ConnectTimeout = cfg.ConnectTimeout
+		dbConfig.Options = cfg.Options
+	}
+
+	// Firebird takes driver parameters such as role and wire_crypt from the options map;
+	// the name is the database path or alias
+	if cfg.Type == "firebird" {
+		dbConfig.Options = cfg.Options
+	}
+
+	// Trino takes the default schema and HTTPS from the options map
+	if cfg.Type == "trino" {
+		dbConfig.Options = cfg.Options
+	}
+
+	// Databricks authenticates with a personal access token and takes the default schema
+	// from the options map
+	if cfg.Type == "databricks" {
+		dbConfig.HTTPPath = cfg.HTTPPath
+		dbConfig.Token = cfg.Token
+		dbConfig.Options = cfg.Options
+	}
+
+	// MongoDB takes URI options such as authSource, replicaSet or tls from the options map
+	if cfg.Type == "mongodb" {
+		dbConfig.ConnectTimeout = cfg.ConnectTimeout
+		dbConfig.Options = cfg.Options
+	}
+
+	// Redis takes the tls switch from the options map; the name is the database number
+	if cfg.Type == "redis" {
+		dbConfig.ConnectTimeout = cfg.ConnectTimeout
+		dbConfig.Options = cfg.Options
+	}
+
+	// Elasticsearch and OpenSearch take the tls switch and an api_key from the options map
+	if cfg.Type == "elasticsearch" || cfg.Type == "opensearch" {
+		dbConfig.ConnectTimeout = cfg.ConnectTimeout
+		dbConfig.Options = cfg.Options
+	}
+
+	// Connection pool settings
+	if cfg.MaxOpenConns > 0 {
+		dbConfig.MaxOpenConns = cfg.MaxOpenConns
+	}
+	if cfg.MaxIdleConns > 0 {
+		dbConfig.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		dbConfig.ConnMaxLifetime = time.Duration(cfg.ConnMaxLifetime) * time.Second
+	}
+	if cfg.ConnMaxIdleTime > 0 {
+		dbConfig.ConnMaxIdleTime = time.Duration(cfg.ConnMaxIdleTime) * time.Second
+	}
+	dbConfig.StatementCacheSize = cfg.StatementCacheSize
+
+	// Create and connect to database
+	db, err := NewDatabase(dbConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database instance for %s: %w", id, err)
+	}
+
+	if err := db.Connect(); err != nil {
+		return nil, fmt.Errorf("failed to connect to database %s: %w", id, err)
+	}
+
+	return db, nil
+}
+
+// GetDatabase retrieves a database connection by ID. When the number of open pools is
+// limited, a closed pool is reopened transparently.
 func (m *Manager) GetDatabase(id string) (Database, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	m.mu.Lock()
+	db, exists := m.connections[id]
+	if exists {
+		m.touch(id)
+		m.evictIdle()
+		m.mu.Unlock()
+		return db, nil
+	}
+	cfg, configured := m.configs[id]
+	lazy := m.maxOpen > 0
+	m.mu.Unlock()
 
 	// Check if the database exists
-	db, exists := m.connections[id]
-	if !exists {
+	if !configured || !lazy {
 		return nil, fmt.Errorf("database connection %s not found", id)
 	}
 
-	return db, nil
+	// Connect without holding the lock, so a slow server does not stall the other databases
+	opened, err := m.open(id, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if db, exists := m.connections[id]; exists {
+		// Another caller opened the pool meanwhile
+		if err := opened.Close(); err != nil {
+			logger.Error("Failed to close duplicate pool of database %s: %v", id, err)
+		}
+		m.touch(id)
+		return db, nil
+	}
+	m.connections[id] = opened
+	m.touch(id)
+	logger.Info("Opened connection pool of database %s (%d open)", id, len(m.connections))
+	m.evictIdle()
+
+	return opened, nil
+}
+
+// touch marks a pool as the most recently used
+func (m *Manager) touch(id string) {
+	if element, ok := m.entries[id]; ok {
+		element.Value.(*poolEntry).used = poolClock()
+		m.order.MoveToFront(element)
+		return
+	}
+	m.entries[id] = m.order.PushFront(&poolEntry{id: id, used: poolClock()})
+}
+
+// forget drops a closed pool from the usage order
+func (m *Manager) forget(id string) {
+	if element, ok := m.entries[id]; ok {
+		m.order.Remove(element)
+		delete(m.entries, id)
+	}
+}
+
+// evictIdle closes the least recently used pools beyond the limit. Pools with connections
+// in use, such as those holding a transaction, and pools handed out within poolEvictionGrace
+// stay open, so the limit is exceeded until they go idle.
+func (m *Manager) evictIdle() {
+	if m.maxOpen <= 0 {
+		return
+	}
+	for element := m.order.Back(); element != nil && len(m.connections) > m.maxOpen; {
+		entry := element.Value.(*poolEntry)
+		element = element.Prev()
+		db := m.connections[entry.id]
+		if poolClock().Sub(entry.used) < poolEvictionGrace || (db.DB() != nil && db.DB().Stats().InUse > 0) {
+			continue
+		}
+		if err := db.Close(); err != nil {
+			logger.Error("Failed to close idle pool of database %s: %v", entry.id, err)
+		}
+		delete(m.connections, entry.id)
+		m.forget(entry.id)
+		logger.Info("Closed idle connection pool of database %s", entry.id)
+	}
 }
 
 // GetDatabaseType returns the type of a database by its ID
@@ -188,6 +444,7 @@ func (m *Manager) CloseAll() error {
 			}
 		}
 		delete(m.connections, id)
+		m.forget(id)
 	}
 
 	return firstErr
@@ -211,6 +468,7 @@ func (m *Manager) Close(id string) error {
 
 	// Remove from connections map
 	delete(m.connections, id)
+	m.forget(id)
 
 	return nil
 }
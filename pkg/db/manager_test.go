@@ -0,0 +1,118 @@
+package db
+
+import (
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FreePeak/db-mcp-server/internal/logger"
+)
+
+func TestMain(m *testing.M) {
+	// The manager logs pools it opens and closes through the package logger
+	logger.Initialize("error")
+	os.Exit(m.Run())
+}
+
+// closingDatabase records whether its pool was closed
+type closingDatabase struct {
+	*MockDatabase
+	closed bool
+}
+
+func (c *closingDatabase) Close() error {
+	c.closed = true
+	return nil
+}
+
+func newPoolManager(t *testing.T, maxOpen int) (*Manager, map[string]int, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	poolClock = func() time.Time { return now }
+	t.Cleanup(func() { poolClock = time.Now })
+
+	manager := NewDBManager()
+	assert.NoError(t, manager.LoadConfig([]byte(`{"max_open_pools": 2, "connections": [
+		{"id": "a", "type": "postgres"}, {"id": "b", "type": "postgres"}, {"id": "c", "type": "mysql"}]}`)))
+	assert.Equal(t, 2, manager.maxOpen)
+	manager.maxOpen = maxOpen
+	opened := make(map[string]int)
+	manager.open = func(id string, _ DatabaseConnectionConfig) (Database, error) {
+		opened[id]++
+		return &closingDatabase{MockDatabase: NewMockDatabase()}, nil
+	}
+	return manager, opened, &now
+}
+
+func TestManagerKeepsRecentlyUsedPoolsOpen(t *testing.T) {
+	manager, opened, now := newPoolManager(t, 2)
+	assert.NoError(t, manager.Connect())
+	assert.Empty(t, manager.GetConnectedDatabases())
+
+	a, err := manager.GetDatabase("a")
+	assert.NoError(t, err)
+	*now = now.Add(time.Minute)
+	_, err = manager.GetDatabase("b")
+	assert.NoError(t, err)
+	*now = now.Add(time.Minute)
+	_, err = manager.GetDatabase("c")
+	assert.NoError(t, err)
+
+	// The least recently used pool is closed and reopened on demand
+	assert.True(t, a.(*closingDatabase).closed)
+	connected := manager.GetConnectedDatabases()
+	sort.Strings(connected)
+	assert.Equal(t, []string{"b", "c"}, connected)
+
+	*now = now.Add(time.Minute)
+	_, err = manager.GetDatabase("a")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 2, "b": 1, "c": 1}, opened)
+	assert.Len(t, manager.GetConnectedDatabases(), 2)
+
+	_, err = manager.GetDatabase("missing")
+	assert.EqualError(t, err, "database connection missing not found")
+}
+
+func TestManagerKeepsPoolsInGraceOpen(t *testing.T) {
+	manager, _, now := newPoolManager(t, 1)
+
+	a, err := manager.GetDatabase("a")
+	assert.NoError(t, err)
+	_, err = manager.GetDatabase("b")
+	assert.NoError(t, err)
+
+	// a was handed out moments ago, so the limit is exceeded until it goes idle
+	assert.False(t, a.(*closingDatabase).closed)
+	assert.Len(t, manager.GetConnectedDatabases(), 2)
+
+	*now = now.Add(poolEvictionGrace)
+	_, err = manager.GetDatabase("b")
+	assert.NoError(t, err)
+	assert.True(t, a.(*closingDatabase).closed)
+	assert.Equal(t, []string{"b"}, manager.GetConnectedDatabases())
+}
+
+func TestManagerWithoutPoolLimit(t *testing.T) {
+	manager, opened, _ := newPoolManager(t, 0)
+	assert.NoError(t, manager.Connect())
+	assert.Len(t, manager.GetConnectedDatabases(), 3)
+
+	assert.NoError(t, manager.Close("a"))
+	_, err := manager.GetDatabase("a")
+	assert.EqualError(t, err, "database connection a not found")
+	assert.Equal(t, map[string]int{"a": 1, "b": 1, "c": 1}, opened)
+}
+
+func TestManagerReadsTypeWithoutOpeningPool(t *testing.T) {
+	manager, opened, _ := newPoolManager(t, 2)
+	assert.NoError(t, manager.Connect())
+
+	dbType, err := manager.GetDatabaseType("c")
+	assert.NoError(t, err)
+	assert.Equal(t, "mysql", dbType)
+	assert.Empty(t, opened)
+	assert.Empty(t, manager.GetConnectedDatabases())
+}
@@ -0,0 +1,182 @@
+// Package mongodb reads a MongoDB database for the document tools: it lists collections,
+// samples and aggregates documents, and reads collection statistics and indexes. Documents are
+// returned in canonical Extended JSON, so BSON types such as ObjectId or Int64 stay apart from
+// strings and doubles.
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Store reads the collections of one database of a MongoDB deployment
+type Store struct {
+	client *mongo.Client
+	db     *mongo.Database
+}
+
+// Open connects to the deployment of a mongodb:// or mongodb+srv:// URI and checks that it
+// answers
+func Open(ctx context.Context, uri, database string) (*Store, error) {
+	if database == "" {
+		return nil, errors.New("mongodb: the connection has no database name")
+	}
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("mongodb: %w", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, fmt.Errorf("mongodb: %w", err)
+	}
+	return &Store{client: client, db: client.Database(database)}, nil
+}
+
+// Close disconnects from the deployment
+func (s *Store) Close(ctx context.Context) error {
+	return s.client.Disconnect(ctx)
+}
+
+// Ping checks that the deployment answers
+func (s *Store) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx, nil)
+}
+
+// ListCollections returns the listCollections entries of the database: the name, the type
+// (collection, view or timeseries), the options and the info of every collection
+func (s *Store) ListCollections(ctx context.Context) ([]map[string]interface{}, error) {
+	cursor, err := s.db.ListCollections(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+	return readAll(ctx, cursor, 0)
+}
+
+// SampleDocuments returns up to size documents of a collection picked at random by $sample
+func (s *Store) SampleDocuments(ctx context.Context, collection string, size int) ([]map[string]interface{}, error) {
+	pipeline := mongo.Pipeline{{{Key: "$sample", Value: bson.D{{Key: "size", Value: size}}}}}
+	cursor, err := s.db.Collection(collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	return readAll(ctx, cursor, size)
+}
+
+// CollectionStats returns the collStats command output of a collection
+func (s *Store) CollectionStats(ctx context.Context, collection string) (map[string]interface{}, error) {
+	raw, err := s.db.RunCommand(ctx, bson.D{{Key: "collStats", Value: collection}}).Raw()
+	if err != nil {
+		return nil, err
+	}
+	return decode(raw)
+}
+
+// ListIndexes returns the index specifications of a collection. The key of a specification
+// is a list of single-field documents, as the order of the fields of a compound index matters
+// and is lost in a map.
+func (s *Store) ListIndexes(ctx context.Context, collection string) ([]map[string]interface{}, error) {
+	cursor, err := s.db.Collection(collection).Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+	indexes := []map[string]interface{}{}
+	for cursor.Next(ctx) {
+		index, err := decode(cursor.Current)
+		if err != nil {
+			return nil, err
+		}
+		if key, ok := cursor.Current.Lookup("key").DocumentOK(); ok {
+			if index["key"], err = keyFields(key); err != nil {
+				return nil, err
+			}
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, cursor.Err()
+}
+
+// keyFields splits an index key into single-field documents in field order
+func keyFields(key bson.Raw) ([]interface{}, error) {
+	elements, err := key.Elements()
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]interface{}, len(elements))
+	for i, element := range elements {
+		raw, err := bson.Marshal(bson.D{{Key: element.Key(), Value: element.Value()}})
+		if err != nil {
+			return nil, err
+		}
+		if fields[i], err = decode(raw); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+// Aggregate runs a pipeline, given as a JSON array of stages in Extended JSON, on a
+// collection, or on the database when collection is empty, and returns up to limit documents;
+// a limit of zero returns them all
+func (s *Store) Aggregate(ctx context.Context, collection, pipeline string, limit int) ([]map[string]interface{}, error) {
+	stages, err := parsePipeline(pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var cursor *mongo.Cursor
+	if collection == "" {
+		cursor, err = s.db.Aggregate(ctx, stages)
+	} else {
+		cursor, err = s.db.Collection(collection).Aggregate(ctx, stages)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return readAll(ctx, cursor, limit)
+}
+
+// parsePipeline parses a JSON array of stages in relaxed or canonical Extended JSON. The
+// stages decode into ordered documents, so the fields of a $sort keep their order.
+func parsePipeline(pipeline string) (bson.A, error) {
+	var wrapped struct {
+		Pipeline bson.A `bson:"pipeline"`
+	}
+	if err := bson.UnmarshalExtJSON([]byte(`{"pipeline": `+pipeline+`}`), false, &wrapped); err != nil {
+		return nil, fmt.Errorf("invalid pipeline: %w", err)
+	}
+	return wrapped.Pipeline, nil
+}
+
+// readAll decodes up to limit documents of a cursor, all of them when limit is zero, and
+// closes it
+func readAll(ctx context.Context, cursor *mongo.Cursor, limit int) ([]map[string]interface{}, error) {
+	defer cursor.Close(context.Background())
+	documents := []map[string]interface{}{}
+	for (limit <= 0 || len(documents) < limit) && cursor.Next(ctx) {
+		document, err := decode(cursor.Current)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	return documents, cursor.Err()
+}
+
+// decode converts a BSON document into canonical Extended JSON values
+func decode(raw bson.Raw) (map[string]interface{}, error) {
+	text, err := bson.MarshalExtJSON(raw, true, false)
+	if err != nil {
+		return nil, err
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(text, &document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
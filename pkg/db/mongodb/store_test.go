@@ -0,0 +1,77 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestDecodeKeepsBSONTypes(t *testing.T) {
+	id, _ := primitive.ObjectIDFromHex("65f1c0ffee0000000000abcd")
+	raw, err := bson.Marshal(bson.D{
+		{Key: "_id", Value: id},
+		{Key: "qty", Value: int32(5)},
+		{Key: "total", Value: int64(9007199254740993)},
+		{Key: "price", Value: 1.5},
+		{Key: "name", Value: "lamp"},
+		{Key: "tags", Value: bson.A{"a", true}},
+		{Key: "shipped", Value: nil},
+	})
+	assert.NoError(t, err)
+
+	document, err := decode(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"$oid": "65f1c0ffee0000000000abcd"}, document["_id"])
+	assert.Equal(t, map[string]interface{}{"$numberInt": "5"}, document["qty"])
+	assert.Equal(t, map[string]interface{}{"$numberLong": "9007199254740993"}, document["total"])
+	assert.Equal(t, map[string]interface{}{"$numberDouble": "1.5"}, document["price"])
+	assert.Equal(t, "lamp", document["name"])
+	assert.Equal(t, []interface{}{"a", true}, document["tags"])
+	assert.Nil(t, document["shipped"])
+}
+
+func TestKeyFieldsKeepOrder(t *testing.T) {
+	raw, err := bson.Marshal(bson.D{{Key: "status", Value: int32(1)}, {Key: "created", Value: int32(-1)}, {Key: "body", Value: "text"}})
+	assert.NoError(t, err)
+
+	fields, err := keyFields(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"status": map[string]interface{}{"$numberInt": "1"}},
+		map[string]interface{}{"created": map[string]interface{}{"$numberInt": "-1"}},
+		map[string]interface{}{"body": "text"},
+	}, fields)
+}
+
+func TestParsePipelineKeepsFieldOrder(t *testing.T) {
+	stages, err := parsePipeline(`[{"$match": {"_id": {"$oid": "65f1c0ffee0000000000abcd"}}}, {"$sort": {"status": 1, "created": -1}}]`)
+	assert.NoError(t, err)
+	assert.Len(t, stages, 2)
+	id, _ := primitive.ObjectIDFromHex("65f1c0ffee0000000000abcd")
+	assert.Equal(t, bson.D{{Key: "$match", Value: bson.D{{Key: "_id", Value: id}}}}, stages[0])
+	assert.Equal(t, bson.D{{Key: "$sort", Value: bson.D{{Key: "status", Value: int32(1)}, {Key: "created", Value: int32(-1)}}}}, stages[1])
+}
+
+func TestOpenRequiresDatabase(t *testing.T) {
+	_, err := Open(context.Background(), "mongodb://localhost:27017", "")
+	assert.ErrorContains(t, err, "no database name")
+}
+
+func TestAggregateRejectsInvalidPipeline(t *testing.T) {
+	// Connecting does not dial, so the pipeline is checked before any server is needed
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://localhost:1").SetServerSelectionTimeout(time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+	store := &Store{client: client, db: client.Database("shop")}
+
+	_, err = store.Aggregate(context.Background(), "orders", `[{"$match": {"status": }]`, 10)
+	assert.ErrorContains(t, err, "invalid pipeline")
+}
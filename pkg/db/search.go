@@ -0,0 +1,172 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/db-mcp-server/pkg/db/elasticsearch"
+	"github.com/FreePeak/db-mcp-server/pkg/logger"
+)
+
+// SearchStore is implemented by search engines whose indices hold JSON documents. Responses
+// are the decoded JSON of the REST API, with numbers as json.Number.
+type SearchStore interface {
+	ListIndices(ctx context.Context, pattern string, includeHidden bool) ([]map[string]interface{}, error)
+	Mappings(ctx context.Context, pattern string) (map[string]interface{}, error)
+	IndexStats(ctx context.Context, pattern string) (map[string]interface{}, error)
+	Search(ctx context.Context, pattern, body string) (map[string]interface{}, error)
+}
+
+// searchDatabase is an Elasticsearch or OpenSearch cluster; it implements SearchStore, and
+// its SQL methods fail with ErrNoSQL
+type searchDatabase struct {
+	config  Config
+	options elasticsearch.Options
+	client  *elasticsearch.Client
+}
+
+// buildSearchURL builds the base URL of an Elasticsearch or OpenSearch connection. A host
+// that already is an http:// or https:// URL is used as it is; otherwise the tls option
+// selects https.
+func buildSearchURL(config Config) string {
+	if strings.HasPrefix(config.Host, "http://") || strings.HasPrefix(config.Host, "https://") {
+		return config.Host
+	}
+	scheme := "http"
+	if config.Options["tls"] == "true" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(config.Host, fmt.Sprint(config.Port)))
+}
+
+// newSearchDatabase builds the client options of an Elasticsearch or OpenSearch connection;
+// the api_key option authenticates with an encoded API key instead of the user and password
+func newSearchDatabase(config Config) (*searchDatabase, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: time.Duration(config.ConnectTimeout) * time.Second}).DialContext
+	if config.MaxIdleConns > 0 {
+		transport.MaxIdleConnsPerHost = config.MaxIdleConns
+	}
+	options := elasticsearch.Options{
+		URL:      buildSearchURL(config),
+		Username: config.User,
+		Password: config.Password,
+		APIKey:   config.Options["api_key"],
+		HTTP:     &http.Client{Transport: transport},
+	}
+	if _, err := elasticsearch.New(options); err != nil {
+		return nil, err
+	}
+	return &searchDatabase{config: config, options: options}, nil
+}
+
+// Connect creates the client and checks that the cluster answers
+func (d *searchDatabase) Connect() error {
+	client, err := elasticsearch.New(d.options)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(d.config.ConnectTimeout)*time.Second)
+	defer cancel()
+	if _, err := client.Info(ctx); err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	d.client = client
+	logger.Info("Connected to %s database at %s", d.config.Type, d.ConnectionString())
+	return nil
+}
+
+// Close releases the idle connections of the client
+func (d *searchDatabase) Close() error {
+	if d.client != nil {
+		d.options.HTTP.CloseIdleConnections()
+	}
+	return nil
+}
+
+// Ping checks that the cluster answers
+func (d *searchDatabase) Ping(ctx context.Context) error {
+	if d.client == nil {
+		return ErrNoDatabase
+	}
+	_, err := d.client.Info(ctx)
+	return err
+}
+
+// Query fails with ErrNoSQL
+func (d *searchDatabase) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, ErrNoSQL
+}
+
+// QueryRow returns a row that fails with ErrNoSQL
+func (d *searchDatabase) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return noSQL.QueryRowContext(ctx, query, args...)
+}
+
+// Exec fails with ErrNoSQL
+func (d *searchDatabase) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, ErrNoSQL
+}
+
+// BeginTx fails with ErrNoSQL
+func (d *searchDatabase) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return nil, ErrNoSQL
+}
+
+// DriverName returns elasticsearch or opensearch
+func (d *searchDatabase) DriverName() string {
+	return d.config.Type
+}
+
+// ConnectionString returns the URL of the cluster without credentials
+func (d *searchDatabase) ConnectionString() string {
+	parsed, err := url.Parse(d.options.URL)
+	if err != nil {
+		return d.config.Type + "://***"
+	}
+	parsed.User = nil
+	return parsed.String()
+}
+
+// DB returns nil; a search database has no SQL pool
+func (d *searchDatabase) DB() *sql.DB {
+	return nil
+}
+
+// ListIndices lists the indices matching a pattern
+func (d *searchDatabase) ListIndices(ctx context.Context, pattern string, includeHidden bool) ([]map[string]interface{}, error) {
+	if d.client == nil {
+		return nil, ErrNoDatabase
+	}
+	return d.client.ListIndices(ctx, pattern, includeHidden)
+}
+
+// Mappings returns the mappings of the indices matching a pattern
+func (d *searchDatabase) Mappings(ctx context.Context, pattern string) (map[string]interface{}, error) {
+	if d.client == nil {
+		return nil, ErrNoDatabase
+	}
+	return d.client.Mappings(ctx, pattern)
+}
+
+// IndexStats returns the statistics of the indices matching a pattern
+func (d *searchDatabase) IndexStats(ctx context.Context, pattern string) (map[string]interface{}, error) {
+	if d.client == nil {
+		return nil, ErrNoDatabase
+	}
+	return d.client.IndexStats(ctx, pattern)
+}
+
+// Search runs a query DSL request body on the indices matching a pattern
+func (d *searchDatabase) Search(ctx context.Context, pattern, body string) (map[string]interface{}, error) {
+	if d.client == nil {
+		return nil, ErrNoDatabase
+	}
+	return d.client.Search(ctx, pattern, body)
+}
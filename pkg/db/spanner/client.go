@@ -0,0 +1,225 @@
+// Package spanner is a database/sql driver for Google Cloud Spanner built on its REST API. It
+// authenticates with a service-account key, runs GoogleSQL through sessions.executeSql and
+// schema changes through the database DDL endpoint, so the server can treat Spanner like its
+// other databases.
+package spanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/db-mcp-server/pkg/db/googleauth"
+)
+
+// defaultEndpoint is the base URL of the Spanner REST API
+const defaultEndpoint = "https://spanner.googleapis.com/v1"
+
+// spannerScope is the OAuth scope requested for the service account; it covers both the
+// data API and the DDL endpoint
+const spannerScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// ddlPollInterval is how long to wait between checks of a running schema change
+const ddlPollInterval = time.Second
+
+// client calls the Spanner REST API of one database; tokens is nil for the emulator,
+// which takes no credentials
+type client struct {
+	endpoint string
+	database string // projects/{project}/instances/{instance}/databases/{database}
+	http     *http.Client
+	tokens   *googleauth.TokenSource
+}
+
+// apiError is an error returned by the Spanner API
+type apiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
+
+func (e *apiError) Error() string {
+	return "spanner: " + e.Message
+}
+
+// sessionNotFound reports whether an error says the session expired or was deleted
+func sessionNotFound(err error) bool {
+	apiErr, ok := err.(*apiError)
+	return ok && apiErr.Status == "NOT_FOUND" && strings.Contains(apiErr.Message, "Session not found")
+}
+
+// call sends an authorized request to the Spanner API and decodes the JSON response
+func (c *client) call(ctx context.Context, method, path string, body, out interface{}) error {
+	var payload io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+"/"+path, payload)
+	if err != nil {
+		return err
+	}
+	if c.tokens != nil {
+		token, err := c.tokens.Token(ctx)
+		if err != nil {
+			return fmt.Errorf("spanner: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var wrapper struct {
+			Error *apiError `json:"error"`
+		}
+		if json.Unmarshal(raw, &wrapper) == nil && wrapper.Error != nil && wrapper.Error.Message != "" {
+			return wrapper.Error
+		}
+		return fmt.Errorf("spanner: %s", resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// fieldType is the type of a column or parameter; arrays and structs nest their own types
+type fieldType struct {
+	Code             string      `json:"code"`
+	ArrayElementType *fieldType  `json:"arrayElementType,omitempty"`
+	StructType       *structType `json:"structType,omitempty"`
+}
+
+// structType lists the fields of a struct or of a result row
+type structType struct {
+	Fields []field `json:"fields"`
+}
+
+// field is a named column of a result or a struct
+type field struct {
+	Name string    `json:"name"`
+	Type fieldType `json:"type"`
+}
+
+// resultSet is the response of executeSql
+type resultSet struct {
+	Metadata struct {
+		RowType     structType `json:"rowType"`
+		Transaction struct {
+			ID string `json:"id"`
+		} `json:"transaction"`
+	} `json:"metadata"`
+	Rows  [][]interface{} `json:"rows"`
+	Stats struct {
+		RowCountExact string `json:"rowCountExact"`
+	} `json:"stats"`
+}
+
+// executeRequest is the body of executeSql
+type executeRequest struct {
+	SQL         string                 `json:"sql"`
+	Params      map[string]interface{} `json:"params,omitempty"`
+	ParamTypes  map[string]fieldType   `json:"paramTypes,omitempty"`
+	Transaction map[string]interface{} `json:"transaction,omitempty"`
+	Seqno       string                 `json:"seqno,omitempty"`
+}
+
+// createSession starts a session on the database and returns its name
+func (c *client) createSession(ctx context.Context) (string, error) {
+	var session struct {
+		Name string `json:"name"`
+	}
+	if err := c.call(ctx, http.MethodPost, c.database+"/sessions", map[string]interface{}{}, &session); err != nil {
+		return "", err
+	}
+	return session.Name, nil
+}
+
+// deleteSession ends a session; it runs on its own short deadline, as it is called on close
+func (c *client) deleteSession(session string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = c.call(ctx, http.MethodDelete, session, nil, nil)
+}
+
+// executeSQL runs a statement in a session
+func (c *client) executeSQL(ctx context.Context, session string, request executeRequest) (*resultSet, error) {
+	var result resultSet
+	if err := c.call(ctx, http.MethodPost, session+":executeSql", request, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// beginTransaction starts a read-write or a strong read-only transaction and returns its id
+func (c *client) beginTransaction(ctx context.Context, session string, readOnly bool) (string, error) {
+	options := map[string]interface{}{"readWrite": map[string]interface{}{}}
+	if readOnly {
+		options = map[string]interface{}{"readOnly": map[string]interface{}{"strong": true}}
+	}
+	var transaction struct {
+		ID string `json:"id"`
+	}
+	if err := c.call(ctx, http.MethodPost, session+":beginTransaction", map[string]interface{}{"options": options}, &transaction); err != nil {
+		return "", err
+	}
+	return transaction.ID, nil
+}
+
+// commit commits a read-write transaction
+func (c *client) commit(ctx context.Context, session, transactionID string) error {
+	return c.call(ctx, http.MethodPost, session+":commit", map[string]interface{}{"transactionId": transactionID}, &map[string]interface{}{})
+}
+
+// rollback abandons a read-write transaction
+func (c *client) rollback(ctx context.Context, session, transactionID string) error {
+	return c.call(ctx, http.MethodPost, session+":rollback", map[string]interface{}{"transactionId": transactionID}, nil)
+}
+
+// operation is a long-running operation, such as a schema change
+type operation struct {
+	Name  string    `json:"name"`
+	Done  bool      `json:"done"`
+	Error *apiError `json:"error"`
+}
+
+// updateDDL applies schema statements and waits for the schema change to finish
+func (c *client) updateDDL(ctx context.Context, statements []string) error {
+	var op operation
+	if err := c.call(ctx, http.MethodPatch, c.database+"/ddl", map[string]interface{}{"statements": statements}, &op); err != nil {
+		return err
+	}
+	for !op.Done {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(ddlPollInterval):
+		}
+		if err := c.call(ctx, http.MethodGet, op.Name, nil, &op); err != nil {
+			return err
+		}
+	}
+	if op.Error != nil {
+		return op.Error
+	}
+	return nil
+}
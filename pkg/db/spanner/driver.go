@@ -0,0 +1,509 @@
+package spanner
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/db-mcp-server/pkg/db/googleauth"
+)
+
+func init() {
+	sql.Register("spanner", &Driver{})
+}
+
+// ddlKeywords start the statements that go to the DDL endpoint instead of executeSql
+var ddlKeywords = []string{"CREATE", "ALTER", "DROP", "RENAME", "GRANT", "REVOKE", "ANALYZE"}
+
+// Driver is the database/sql driver of Spanner. Its DSN has the form
+// spanner://project/instance/database?credentials_file=key.json; the project defaults to the
+// one of the key. emulator_host, or $SPANNER_EMULATOR_HOST, points it at the REST port of the
+// emulator, which takes no credentials, and endpoint overrides the API URL.
+type Driver struct{}
+
+// Open opens a connection for a DSN
+func (d *Driver) Open(dsn string) (driver.Conn, error) {
+	connector, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(context.Background())
+}
+
+// OpenConnector parses a DSN and loads its key once, so the connections of a pool share
+// one access token
+func (d *Driver) OpenConnector(dsn string) (driver.Connector, error) {
+	parsed, err := url.Parse(dsn)
+	var path []string
+	if err == nil {
+		path = strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	}
+	if err != nil || parsed.Scheme != "spanner" || len(path) != 2 || path[0] == "" || path[1] == "" {
+		return nil, fmt.Errorf("spanner: invalid DSN, expected spanner://project/instance/database")
+	}
+	params := parsed.Query()
+	httpClient := &http.Client{}
+	c := &client{
+		endpoint: strings.TrimRight(params.Get("endpoint"), "/"),
+		http:     httpClient,
+	}
+	project := parsed.Host
+
+	emulator := params.Get("emulator_host")
+	if emulator == "" {
+		emulator = os.Getenv("SPANNER_EMULATOR_HOST")
+	}
+	if emulator != "" {
+		if c.endpoint == "" {
+			c.endpoint = "http://" + emulator + "/v1"
+		}
+	} else {
+		c.tokens, err = googleauth.NewTokenSource(params.Get("credentials_file"), spannerScope, httpClient)
+		if err != nil {
+			return nil, fmt.Errorf("spanner: %w", err)
+		}
+		if project == "" {
+			project = c.tokens.ProjectID()
+		}
+	}
+	if c.endpoint == "" {
+		c.endpoint = defaultEndpoint
+	}
+	if project == "" {
+		return nil, fmt.Errorf("spanner: no project in the DSN or the service-account key")
+	}
+	c.database = "projects/" + url.PathEscape(project) + "/instances/" + url.PathEscape(path[0]) + "/databases/" + url.PathEscape(path[1])
+	return &connector{driver: d, client: c}, nil
+}
+
+type connector struct {
+	driver *Driver
+	client *client
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{client: c.client}, nil
+}
+
+func (c *connector) Driver() driver.Driver { return c.driver }
+
+// conn is a connection backed by one Spanner session, created on first use; it holds at
+// most one transaction
+type conn struct {
+	client   *client
+	session  string
+	tx       string
+	readOnly bool
+	seqno    int64
+}
+
+// ensureSession creates the session of the connection when it has none
+func (c *conn) ensureSession(ctx context.Context) error {
+	if c.session != "" {
+		return nil
+	}
+	session, err := c.client.createSession(ctx)
+	if err != nil {
+		return err
+	}
+	c.session = session
+	return nil
+}
+
+// execute runs a statement in the session of the connection. Spanner deletes sessions that
+// sat idle for an hour, so a lost session outside a transaction marks the connection bad
+// and database/sql retries on a new one.
+func (c *conn) execute(ctx context.Context, request executeRequest) (*resultSet, error) {
+	if err := c.ensureSession(ctx); err != nil {
+		return nil, err
+	}
+	result, err := c.client.executeSQL(ctx, c.session, request)
+	if err != nil && sessionNotFound(err) {
+		c.session = ""
+		if c.tx == "" {
+			return nil, driver.ErrBadConn
+		}
+	}
+	return result, err
+}
+
+// inTransaction points a request at the open transaction; statements of a read-write
+// transaction are numbered so Spanner can replay them
+func (c *conn) inTransaction(request *executeRequest) {
+	request.Transaction = map[string]interface{}{"id": c.tx}
+	if !c.readOnly {
+		c.seqno++
+		request.Seqno = strconv.FormatInt(c.seqno, 10)
+	}
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query}, nil
+}
+
+func (c *conn) Close() error {
+	if c.session != "" {
+		c.client.deleteSession(c.session)
+		c.session = ""
+	}
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx starts a read-write transaction, or a strong read-only one when asked for
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if c.tx != "" {
+		return nil, errors.New("spanner: a transaction is already open on this connection")
+	}
+	if err := c.ensureSession(ctx); err != nil {
+		return nil, err
+	}
+	id, err := c.client.beginTransaction(ctx, c.session, opts.ReadOnly)
+	if err != nil {
+		if sessionNotFound(err) {
+			c.session = ""
+			return nil, driver.ErrBadConn
+		}
+		return nil, err
+	}
+	c.tx, c.readOnly, c.seqno = id, opts.ReadOnly, 0
+	return &tx{conn: c}, nil
+}
+
+// Ping checks the credentials and the database with a trivial query
+func (c *conn) Ping(ctx context.Context) error {
+	_, err := c.QueryContext(ctx, "SELECT 1", nil)
+	return err
+}
+
+// QueryContext runs a query in the open transaction, or else in a single-use strong
+// read-only transaction
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	params, types, err := statementParams(args)
+	if err != nil {
+		return nil, err
+	}
+	request := executeRequest{SQL: query, Params: params, ParamTypes: types}
+	if c.tx != "" {
+		c.inTransaction(&request)
+	} else {
+		request.Transaction = map[string]interface{}{"singleUse": map[string]interface{}{"readOnly": map[string]interface{}{"strong": true}}}
+	}
+	result, err := c.execute(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &rows{fields: result.Metadata.RowType.Fields, data: result.Rows}, nil
+}
+
+// ExecContext applies schema statements through the DDL endpoint and runs DML in the open
+// transaction, or else in a read-write transaction of its own that it commits
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if isDDL(query) {
+		if c.tx != "" {
+			return nil, errors.New("spanner: schema changes cannot run in a transaction")
+		}
+		if len(args) > 0 {
+			return nil, errors.New("spanner: schema changes take no parameters")
+		}
+		if err := c.client.updateDDL(ctx, []string{query}); err != nil {
+			return nil, err
+		}
+		return driver.ResultNoRows, nil
+	}
+
+	params, types, err := statementParams(args)
+	if err != nil {
+		return nil, err
+	}
+	request := executeRequest{SQL: query, Params: params, ParamTypes: types}
+	if c.tx != "" {
+		c.inTransaction(&request)
+		result, err := c.execute(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		return rowCount(result), nil
+	}
+
+	request.Transaction = map[string]interface{}{"begin": map[string]interface{}{"readWrite": map[string]interface{}{}}}
+	request.Seqno = "1"
+	result, err := c.execute(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if id := result.Metadata.Transaction.ID; id != "" {
+		if err := c.client.commit(ctx, c.session, id); err != nil {
+			return nil, err
+		}
+	}
+	return rowCount(result), nil
+}
+
+// isDDL reports whether a statement changes the schema
+func isDDL(query string) bool {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return false
+	}
+	keyword := strings.ToUpper(fields[0])
+	for _, ddl := range ddlKeywords {
+		if keyword == ddl {
+			return true
+		}
+	}
+	return false
+}
+
+// rowCount turns the statistics of a DML statement into a result
+func rowCount(result *resultSet) driver.Result {
+	affected, _ := strconv.ParseInt(result.Stats.RowCountExact, 10, 64)
+	return driver.RowsAffected(affected)
+}
+
+// tx is the transaction open on a connection
+type tx struct {
+	conn *conn
+}
+
+// Commit commits a read-write transaction; a read-only transaction only ends
+func (t *tx) Commit() error {
+	c := t.conn
+	id, readOnly := c.tx, c.readOnly
+	c.tx = ""
+	if readOnly {
+		return nil
+	}
+	if c.session == "" {
+		return errors.New("spanner: the session of the transaction expired, so it was not committed")
+	}
+	return c.client.commit(context.Background(), c.session, id)
+}
+
+// Rollback abandons a read-write transaction; a read-only transaction only ends
+func (t *tx) Rollback() error {
+	c := t.conn
+	id, readOnly := c.tx, c.readOnly
+	c.tx = ""
+	if readOnly || c.session == "" {
+		return nil
+	}
+	return c.client.rollback(context.Background(), c.session, id)
+}
+
+// stmt is a prepared statement; Spanner plans statements on the server, so it keeps the
+// query text
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+func (s *stmt) Close() error { return nil }
+
+// NumInput returns -1; the API checks the parameters
+func (s *stmt) NumInput() int { return -1 }
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+// statementParams converts arguments into query parameters: positional arguments bind to
+// @p1, @p2 and so on, named ones to their name. NULL is sent untyped.
+func statementParams(args []driver.NamedValue) (map[string]interface{}, map[string]fieldType, error) {
+	if len(args) == 0 {
+		return nil, nil, nil
+	}
+	params := make(map[string]interface{}, len(args))
+	types := make(map[string]fieldType, len(args))
+	for _, arg := range args {
+		name := arg.Name
+		if name == "" {
+			name = fmt.Sprintf("p%d", arg.Ordinal)
+		}
+		switch v := arg.Value.(type) {
+		case nil:
+			params[name] = nil
+		case int64:
+			params[name], types[name] = strconv.FormatInt(v, 10), fieldType{Code: "INT64"}
+		case float64:
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				params[name] = strconv.FormatFloat(v, 'g', -1, 64)
+			} else {
+				params[name] = v
+			}
+			types[name] = fieldType{Code: "FLOAT64"}
+		case bool:
+			params[name], types[name] = v, fieldType{Code: "BOOL"}
+		case string:
+			params[name], types[name] = v, fieldType{Code: "STRING"}
+		case []byte:
+			params[name], types[name] = base64.StdEncoding.EncodeToString(v), fieldType{Code: "BYTES"}
+		case time.Time:
+			params[name], types[name] = v.UTC().Format(time.RFC3339Nano), fieldType{Code: "TIMESTAMP"}
+		default:
+			return nil, nil, fmt.Errorf("spanner: unsupported parameter type %T", arg.Value)
+		}
+	}
+	return params, types, nil
+}
+
+// rows reads a query result; executeSql returns every row at once
+type rows struct {
+	fields []field
+	data   [][]interface{}
+	pos    int
+}
+
+func (r *rows) Columns() []string {
+	columns := make([]string, len(r.fields))
+	for i, field := range r.fields {
+		columns[i] = field.Name
+	}
+	return columns
+}
+
+// ColumnTypeDatabaseTypeName returns the GoogleSQL type of a column
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	return typeName(r.fields[index].Type)
+}
+
+// typeName spells a type the way Spanner's DDL does
+func typeName(t fieldType) string {
+	if t.Code == "ARRAY" && t.ArrayElementType != nil {
+		return "ARRAY<" + typeName(*t.ArrayElementType) + ">"
+	}
+	return t.Code
+}
+
+func (r *rows) Close() error { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.data) {
+		return io.EOF
+	}
+	row := r.data[r.pos]
+	r.pos++
+	for i, field := range r.fields {
+		if i >= len(row) {
+			dest[i] = nil
+			continue
+		}
+		value, err := convertValue(field.Type, row[i])
+		if err != nil {
+			return fmt.Errorf("spanner: column %s: %w", field.Name, err)
+		}
+		dest[i] = value
+	}
+	return nil
+}
+
+// convertValue converts a cell of a result into a driver value. Arrays and structs become
+// JSON text, as database/sql has no nested values.
+func convertValue(t fieldType, v interface{}) (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if t.Code == "ARRAY" || t.Code == "STRUCT" {
+		raw, err := json.Marshal(nestedValue(t, v))
+		if err != nil {
+			return nil, err
+		}
+		return string(raw), nil
+	}
+	if t.Code == "BOOL" {
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("unexpected value %v", v)
+		}
+		return b, nil
+	}
+	if f, ok := v.(float64); ok {
+		return f, nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value %v", v)
+	}
+	switch t.Code {
+	case "INT64":
+		return strconv.ParseInt(s, 10, 64)
+	case "FLOAT64", "FLOAT32":
+		// NaN and the infinities arrive as strings
+		return strconv.ParseFloat(s, 64)
+	case "TIMESTAMP":
+		return time.Parse(time.RFC3339Nano, s)
+	case "BYTES", "PROTO":
+		return base64.StdEncoding.DecodeString(s)
+	default:
+		// NUMERIC, DATE, JSON, STRING, ENUM and the other types keep their text
+		return s, nil
+	}
+}
+
+// nestedValue decodes arrays and structs into plain Go values
+func nestedValue(t fieldType, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	switch {
+	case t.Code == "ARRAY" && t.ArrayElementType != nil:
+		items, _ := v.([]interface{})
+		values := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			values = append(values, nestedValue(*t.ArrayElementType, item))
+		}
+		return values
+	case t.Code == "STRUCT" && t.StructType != nil:
+		cells, _ := v.([]interface{})
+		values := make(map[string]interface{}, len(t.StructType.Fields))
+		for i, sub := range t.StructType.Fields {
+			if i < len(cells) {
+				values[sub.Name] = nestedValue(sub.Type, cells[i])
+			}
+		}
+		return values
+	}
+	value, err := convertValue(t, v)
+	if err != nil {
+		return v
+	}
+	if b, ok := value.([]byte); ok {
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	return value
+}
@@ -0,0 +1,186 @@
+package spanner
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDatabase = "projects/shop-123/instances/main/databases/orders"
+
+// fakeSpanner serves the session, executeSql, transaction and DDL endpoints of the emulator
+type fakeSpanner struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	sessions int
+	expired  map[string]bool
+	calls    []string
+	bodies   []map[string]interface{}
+}
+
+func (f *fakeSpanner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+	var body map[string]interface{}
+	if r.Body != nil && r.Method != http.MethodGet && r.Method != http.MethodDelete {
+		assert.NoError(f.t, json.NewDecoder(r.Body).Decode(&body))
+	}
+	session, method, _ := strings.Cut(path, ":")
+	f.calls = append(f.calls, r.Method+" "+strings.TrimPrefix(path, testDatabase+"/"))
+	f.bodies = append(f.bodies, body)
+
+	switch {
+	case r.Method == http.MethodPost && path == testDatabase+"/sessions":
+		f.sessions++
+		_, _ = fmt.Fprintf(w, `{"name": "%s/sessions/s%d"}`, testDatabase, f.sessions)
+	case f.expired[session]:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": {"code": 404, "message": "Session not found: ` + session + `", "status": "NOT_FOUND"}}`))
+	case method == "executeSql" && body["sql"] == "SELECT * FROM Orders WHERE Total > @p1 AND Status = @p2":
+		_, _ = w.Write([]byte(`{
+			"metadata": {"rowType": {"fields": [
+				{"name": "OrderId", "type": {"code": "INT64"}},
+				{"name": "Total", "type": {"code": "FLOAT64"}},
+				{"name": "Paid", "type": {"code": "BOOL"}},
+				{"name": "CreatedAt", "type": {"code": "TIMESTAMP"}},
+				{"name": "Tags", "type": {"code": "ARRAY", "arrayElementType": {"code": "STRING"}}},
+				{"name": "Receipt", "type": {"code": "BYTES"}},
+				{"name": "Amount", "type": {"code": "NUMERIC"}}
+			]}},
+			"rows": [
+				["1", 12.5, true, "2024-01-01T00:00:00.5Z", ["new", "gift"], "aGk=", "12.50"],
+				["2", "NaN", false, null, [], null, null]
+			]
+		}`))
+	case method == "executeSql" && strings.HasPrefix(body["sql"].(string), "UPDATE"):
+		response := `{"metadata": {"rowType": {}}, "stats": {"rowCountExact": "3"}}`
+		if _, ok := body["transaction"].(map[string]interface{})["begin"]; ok {
+			response = `{"metadata": {"rowType": {}, "transaction": {"id": "inline-tx"}}, "stats": {"rowCountExact": "3"}}`
+		}
+		_, _ = w.Write([]byte(response))
+	case method == "executeSql":
+		_, _ = w.Write([]byte(`{"metadata": {"rowType": {"fields": [{"name": "", "type": {"code": "INT64"}}]}}, "rows": [["1"]]}`))
+	case method == "beginTransaction":
+		_, _ = w.Write([]byte(`{"id": "tx-1"}`))
+	case method == "commit":
+		_, _ = w.Write([]byte(`{"commitTimestamp": "2024-01-01T00:00:00Z"}`))
+	case method == "rollback", r.Method == http.MethodDelete:
+		_, _ = w.Write([]byte(`{}`))
+	case r.Method == http.MethodPatch && path == testDatabase+"/ddl":
+		_, _ = w.Write([]byte(`{"name": "` + testDatabase + `/operations/ddl-1", "done": false}`))
+	case r.Method == http.MethodGet && path == testDatabase+"/operations/ddl-1":
+		_, _ = w.Write([]byte(`{"name": "` + testDatabase + `/operations/ddl-1", "done": true}`))
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// openFakeSpanner starts a fake emulator and opens a pool of one connection on it
+func openFakeSpanner(t *testing.T) (*fakeSpanner, *sql.DB) {
+	fake := &fakeSpanner{t: t, expired: map[string]bool{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	db, err := sql.Open("spanner", "spanner://shop-123/main/orders?emulator_host="+strings.TrimPrefix(server.URL, "http://"))
+	assert.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return fake, db
+}
+
+func TestQueryDecodesValues(t *testing.T) {
+	fake, db := openFakeSpanner(t)
+
+	rows, err := db.QueryContext(context.Background(), "SELECT * FROM Orders WHERE Total > @p1 AND Status = @p2", 10, "open")
+	assert.NoError(t, err)
+	defer rows.Close()
+	columns, _ := rows.Columns()
+	assert.Equal(t, []string{"OrderId", "Total", "Paid", "CreatedAt", "Tags", "Receipt", "Amount"}, columns)
+	types, _ := rows.ColumnTypes()
+	assert.Equal(t, "ARRAY<STRING>", types[4].DatabaseTypeName())
+
+	var got [][]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		assert.NoError(t, rows.Scan(pointers...))
+		got = append(got, values)
+	}
+	assert.NoError(t, rows.Err())
+	assert.Equal(t, []interface{}{int64(1), 12.5, true, time.Date(2024, 1, 1, 0, 0, 0, 5e8, time.UTC), `["new","gift"]`, []byte("hi"), "12.50"}, got[0])
+	assert.Equal(t, []interface{}{int64(2), false, nil, "[]", nil, nil}, []interface{}{got[1][0], got[1][2], got[1][3], got[1][4], got[1][5], got[1][6]})
+	assert.NotEqual(t, got[1][1], got[1][1]) // NaN
+
+	request := fake.bodies[1]
+	assert.Equal(t, map[string]interface{}{"p1": "10", "p2": "open"}, request["params"])
+	assert.Equal(t, map[string]interface{}{"p1": map[string]interface{}{"code": "INT64"}, "p2": map[string]interface{}{"code": "STRING"}}, request["paramTypes"])
+	assert.Equal(t, map[string]interface{}{"singleUse": map[string]interface{}{"readOnly": map[string]interface{}{"strong": true}}}, request["transaction"])
+}
+
+func TestExecAndTransactions(t *testing.T) {
+	fake, db := openFakeSpanner(t)
+
+	// A statement outside a transaction begins one inline and commits it
+	result, err := db.Exec("UPDATE Orders SET Paid = TRUE WHERE OrderId = @p1", 7)
+	assert.NoError(t, err)
+	affected, _ := result.RowsAffected()
+	assert.Equal(t, int64(3), affected)
+	assert.Equal(t, []string{"POST sessions", "POST sessions/s1:executeSql", "POST sessions/s1:commit"}, fake.calls)
+	assert.Equal(t, "inline-tx", fake.bodies[2]["transactionId"])
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	_, err = tx.Exec("UPDATE Orders SET Paid = FALSE")
+	assert.NoError(t, err)
+	_, err = tx.Exec("UPDATE Orders SET Paid = TRUE")
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.Equal(t, map[string]interface{}{"id": "tx-1"}, fake.bodies[5]["transaction"])
+	assert.Equal(t, "2", fake.bodies[5]["seqno"])
+	assert.Equal(t, "POST sessions/s1:commit", fake.calls[6])
+
+	// Schema changes go to the DDL endpoint and wait for the operation
+	_, err = db.Exec("CREATE INDEX OrdersByStatus ON Orders(Status)")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PATCH ddl", "GET operations/ddl-1"}, fake.calls[7:])
+	assert.Equal(t, []interface{}{"CREATE INDEX OrdersByStatus ON Orders(Status)"}, fake.bodies[7]["statements"])
+}
+
+func TestExpiredSessionIsReplaced(t *testing.T) {
+	fake, db := openFakeSpanner(t)
+	assert.NoError(t, db.Ping())
+
+	fake.mu.Lock()
+	fake.expired[testDatabase+"/sessions/s1"] = true
+	fake.mu.Unlock()
+
+	var one int64
+	assert.NoError(t, db.QueryRow("SELECT 1").Scan(&one))
+	assert.Equal(t, int64(1), one)
+	assert.Equal(t, 2, fake.sessions)
+}
+
+func TestOpenConnectorValidatesDSN(t *testing.T) {
+	_, err := (&Driver{}).OpenConnector("spanner://shop-123/main")
+	assert.ErrorContains(t, err, "expected spanner://project/instance/database")
+
+	t.Setenv("SPANNER_EMULATOR_HOST", "")
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	_, err = (&Driver{}).OpenConnector("spanner://shop-123/main/orders")
+	assert.ErrorContains(t, err, "credentials_file")
+}
@@ -0,0 +1,130 @@
+package db
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+)
+
+// stmtCache keeps server-side prepared statements for parameterized queries, so repeated
+// queries are parsed and planned once per connection and their arguments are always sent
+// separately from the SQL. The least recently used statement is dropped once the cache is
+// full; a dropped statement is closed when the last caller running it is done.
+type stmtCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// cachedStmt is one prepared statement, the query it was prepared from and how many callers
+// are running it
+type cachedStmt struct {
+	query   string
+	stmt    *sql.Stmt
+	users   int
+	dropped bool // no longer in the cache; closed once users reaches zero
+}
+
+// newStmtCache creates a cache holding up to size statements
+func newStmtCache(size int) *stmtCache {
+	return &stmtCache{size: size, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// get returns the prepared statement of a query, preparing it on first use. The caller must
+// release it once the statement has run.
+func (c *stmtCache) get(ctx context.Context, db *sql.DB, query string) (*cachedStmt, error) {
+	c.mu.Lock()
+	if element, ok := c.entries[query]; ok {
+		c.order.MoveToFront(element)
+		entry := element.Value.(*cachedStmt)
+		entry.users++
+		c.mu.Unlock()
+		return entry, nil
+	}
+	c.mu.Unlock()
+
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Another caller may have prepared the same query meanwhile
+	if element, ok := c.entries[query]; ok {
+		_ = stmt.Close()
+		c.order.MoveToFront(element)
+		entry := element.Value.(*cachedStmt)
+		entry.users++
+		return entry, nil
+	}
+	entry := &cachedStmt{query: query, stmt: stmt, users: 1}
+	c.entries[query] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		c.drop(c.order.Back())
+	}
+	return entry, nil
+}
+
+// release ends a use of a statement returned by get, closing it if it was dropped meanwhile
+func (c *stmtCache) release(entry *cachedStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry.users--
+	if entry.dropped && entry.users == 0 {
+		_ = entry.stmt.Close()
+	}
+}
+
+// evict drops a statement whose plan went stale, for example after the schema it was planned
+// against changed. A statement prepared again since then is kept.
+func (c *stmtCache) evict(entry *cachedStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[entry.query]; ok && element.Value.(*cachedStmt) == entry {
+		c.drop(element)
+	}
+}
+
+// drop removes a statement from the cache and closes it unless a caller is running it.
+// c.mu must be held.
+func (c *stmtCache) drop(element *list.Element) {
+	entry := element.Value.(*cachedStmt)
+	c.order.Remove(element)
+	delete(c.entries, entry.query)
+	entry.dropped = true
+	if entry.users == 0 {
+		_ = entry.stmt.Close()
+	}
+}
+
+// close drops every cached statement
+func (c *stmtCache) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.order.Len() > 0 {
+		c.drop(c.order.Back())
+	}
+}
+
+// staleStatementError reports whether a statement failed because its plan no longer matches
+// the schema, so it has to be prepared again. Other errors, such as constraint violations or
+// cancellations, leave the statement cached.
+func staleStatementError(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := err.Error()
+	return strings.Contains(message, "cached plan must not change result type") || // PostgreSQL
+		strings.Contains(message, "needs to be re-prepared") || // MySQL error 1615
+		(strings.Contains(message, "prepared statement") && strings.Contains(message, "does not exist"))
+}
+
+// closedStatementError reports whether a statement was closed under its caller, which happens
+// when the database is closed while a query runs
+func closedStatementError(err error) bool {
+	return err != nil && err.Error() == "sql: statement is closed"
+}
@@ -0,0 +1,218 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingDriver is a database/sql driver that records the statements it prepares
+type countingDriver struct {
+	mu       sync.Mutex
+	prepared []string
+	closed   int
+	// failures are the errors the next executions of a statement return, in order
+	failures map[string][]error
+}
+
+// nextFailure returns the error the next execution of a query fails with, if any
+func (d *countingDriver) nextFailure(query string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.failures[query]) == 0 {
+		return nil
+	}
+	err := d.failures[query][0]
+	d.failures[query] = d.failures[query][1:]
+	return err
+}
+
+func (d *countingDriver) Open(string) (driver.Conn, error) { return &countingConn{driver: d}, nil }
+
+func (d *countingDriver) Connect(context.Context) (driver.Conn, error) { return d.Open("") }
+
+func (d *countingDriver) Driver() driver.Driver { return d }
+
+type countingConn struct{ driver *countingDriver }
+
+func (c *countingConn) Prepare(query string) (driver.Stmt, error) {
+	if query == "UNPREPARABLE ?" {
+		return nil, errors.New("this command is not supported in the prepared statement protocol yet")
+	}
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.driver.prepared = append(c.driver.prepared, query)
+	return &countingStmt{driver: c.driver, query: query}, nil
+}
+
+func (c *countingConn) Close() error              { return nil }
+func (c *countingConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type countingStmt struct {
+	driver *countingDriver
+	query  string
+}
+
+func (s *countingStmt) Close() error {
+	s.driver.mu.Lock()
+	defer s.driver.mu.Unlock()
+	s.driver.closed++
+	return nil
+}
+
+func (s *countingStmt) NumInput() int { return -1 }
+
+func (s *countingStmt) Exec([]driver.Value) (driver.Result, error) {
+	if err := s.driver.nextFailure(s.query); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *countingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &singleRow{value: args[0]}, nil
+}
+
+// singleRow returns one row holding the first argument of the query
+type singleRow struct {
+	value driver.Value
+	done  bool
+}
+
+func (r *singleRow) Columns() []string { return []string{"value"} }
+func (r *singleRow) Close() error      { return nil }
+
+func (r *singleRow) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+func newCountingDatabase(t *testing.T, cacheSize int) (*database, *countingDriver) {
+	counting := &countingDriver{}
+	sqlDB := sql.OpenDB(counting)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return &database{db: sqlDB, stmts: newStmtCache(cacheSize)}, counting
+}
+
+func TestParameterizedQueriesReusePreparedStatements(t *testing.T) {
+	d, counting := newCountingDatabase(t, 2)
+	ctx := context.Background()
+
+	for i := int64(0); i < 3; i++ {
+		var value int64
+		assert.NoError(t, d.QueryRow(ctx, "SELECT ?", i).Scan(&value))
+		assert.Equal(t, i, value)
+	}
+	_, err := d.Exec(ctx, "UPDATE t SET a = ?", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SELECT ?", "UPDATE t SET a = ?"}, counting.prepared)
+
+	// Each new statement evicts the least recently used one
+	rows, err := d.Query(ctx, "DELETE FROM t WHERE a = ?", 1)
+	assert.NoError(t, err)
+	assert.NoError(t, rows.Close())
+	_, err = d.Exec(ctx, "SELECT ?", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SELECT ?", "UPDATE t SET a = ?", "DELETE FROM t WHERE a = ?", "SELECT ?"}, counting.prepared)
+	assert.Equal(t, 2, counting.closed)
+
+	assert.NoError(t, d.Close())
+	assert.Equal(t, 4, counting.closed)
+}
+
+func TestQueriesWithoutCachedStatements(t *testing.T) {
+	d, counting := newCountingDatabase(t, 2)
+	ctx := context.Background()
+
+	// Queries without arguments and statements the server cannot prepare are not cached
+	_, err := d.Exec(ctx, "VACUUM")
+	assert.NoError(t, err)
+	assert.Nil(t, d.prepared(ctx, "UNPREPARABLE ?", []interface{}{1}))
+	assert.Empty(t, d.stmts.entries)
+
+	d.stmts = nil
+	_, err = d.Exec(ctx, "UPDATE t SET a = ?", 1)
+	assert.NoError(t, err)
+	_, err = d.Exec(ctx, "UPDATE t SET a = ?", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"VACUUM", "UPDATE t SET a = ?", "UPDATE t SET a = ?"}, counting.prepared)
+}
+
+func TestStatementsInUseOutliveEviction(t *testing.T) {
+	d, counting := newCountingDatabase(t, 1)
+	ctx := context.Background()
+
+	// A statement pushed out of the cache while a caller runs it is closed only afterwards
+	entry := d.prepared(ctx, "SELECT ?", []interface{}{1})
+	_, err := d.Exec(ctx, "UPDATE t SET a = ?", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, counting.closed)
+	var value int64
+	assert.NoError(t, entry.stmt.QueryRowContext(ctx, 7).Scan(&value))
+	assert.Equal(t, int64(7), value)
+	d.stmts.release(entry)
+	assert.Equal(t, 1, counting.closed)
+}
+
+func TestStaleStatementsArePreparedAgain(t *testing.T) {
+	d, counting := newCountingDatabase(t, 2)
+	ctx := context.Background()
+	counting.failures = map[string][]error{
+		"UPDATE t SET a = ?": {errors.New("pq: cached plan must not change result type")},
+		"INSERT INTO t VALUES (?)": {
+			errors.New("pq: duplicate key value violates unique constraint"),
+			context.Canceled,
+		},
+	}
+
+	// A stale plan is evicted and the statement prepared again for one retry
+	_, err := d.Exec(ctx, "UPDATE t SET a = ?", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"UPDATE t SET a = ?", "UPDATE t SET a = ?"}, counting.prepared)
+	assert.Equal(t, 1, counting.closed)
+
+	// Errors of the query itself keep the statement cached
+	_, err = d.Exec(ctx, "INSERT INTO t VALUES (?)", 1)
+	assert.ErrorContains(t, err, "duplicate key")
+	_, err = d.Exec(ctx, "INSERT INTO t VALUES (?)", 1)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = d.Exec(ctx, "INSERT INTO t VALUES (?)", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"UPDATE t SET a = ?", "UPDATE t SET a = ?", "INSERT INTO t VALUES (?)"}, counting.prepared)
+	assert.Equal(t, 1, counting.closed)
+}
+
+func TestConcurrentQueriesShareEvictedStatements(t *testing.T) {
+	d, _ := newCountingDatabase(t, 1)
+	d.db.SetMaxOpenConns(4)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				query := "SELECT ?"
+				if (i+j)%2 == 0 {
+					query = "SELECT ? AS other"
+				}
+				var value int64
+				assert.NoError(t, d.QueryRow(ctx, query, int64(j)).Scan(&value))
+				assert.Equal(t, int64(j), value)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,189 @@
+// Package trino is a database/sql driver for Trino (and Presto) built on its REST statement
+// protocol. A statement is posted to the coordinator and its result is read by following the
+// nextUri of every response, so the server can treat Trino like its other databases.
+package trino
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// retryDelay is how long a client waits before retrying a request the coordinator refused
+// as busy
+const retryDelay = 100 * time.Millisecond
+
+// client sends statements to one Trino coordinator as one user
+type client struct {
+	server   string
+	user     string
+	password string
+	catalog  string
+	schema   string
+	source   string
+	http     *http.Client
+}
+
+// column is a column of a statement's result
+type column struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	TypeSignature struct {
+		RawType string `json:"rawType"`
+	} `json:"typeSignature"`
+}
+
+// rawType returns the base type of a column, such as varchar for varchar(10)
+func (c column) rawType() string {
+	if c.TypeSignature.RawType != "" {
+		return c.TypeSignature.RawType
+	}
+	if i := strings.IndexByte(c.Type, '('); i >= 0 {
+		return c.Type[:i]
+	}
+	return c.Type
+}
+
+// queryError is the error of a failed statement
+type queryError struct {
+	Message   string `json:"message"`
+	ErrorName string `json:"errorName"`
+}
+
+// queryResults is one response of the statement protocol; data holds the rows of this page
+type queryResults struct {
+	ID          string          `json:"id"`
+	NextURI     string          `json:"nextUri"`
+	Columns     []column        `json:"columns"`
+	Data        [][]interface{} `json:"data"`
+	Error       *queryError     `json:"error"`
+	UpdateType  string          `json:"updateType"`
+	UpdateCount *int64          `json:"updateCount"`
+	Stats       struct {
+		State string `json:"state"`
+	} `json:"stats"`
+}
+
+// err returns the error of a failed statement, or nil
+func (r *queryResults) err() error {
+	if r.Error == nil {
+		return nil
+	}
+	if r.Error.ErrorName != "" {
+		return fmt.Errorf("trino: %s: %s", r.Error.ErrorName, r.Error.Message)
+	}
+	return fmt.Errorf("trino: %s", r.Error.Message)
+}
+
+// startQuery posts a statement to the coordinator. A statement with arguments is sent as a
+// prepared statement and run with EXECUTE ... USING, so Trino binds the arguments.
+func (c *client) startQuery(ctx context.Context, query string, args []string) (*queryResults, error) {
+	headers := http.Header{}
+	body := query
+	if len(args) > 0 {
+		headers.Set("X-Trino-Prepared-Statement", "_stmt="+url.QueryEscape(query))
+		body = "EXECUTE _stmt USING " + strings.Join(args, ", ")
+	}
+	return c.send(ctx, http.MethodPost, c.server+"/v1/statement", headers, body)
+}
+
+// next fetches the following response of a statement
+func (c *client) next(ctx context.Context, results *queryResults) (*queryResults, error) {
+	next, err := c.send(ctx, http.MethodGet, results.NextURI, nil, "")
+	if err != nil && ctx.Err() != nil {
+		c.cancel(results.NextURI)
+	}
+	return next, err
+}
+
+// cancel stops a statement whose caller has gone away; it runs on its own short deadline,
+// as the caller's context is already done
+func (c *client) cancel(nextURI string) {
+	if nextURI == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := c.request(ctx, http.MethodDelete, nextURI, nil, "")
+	if err != nil {
+		return
+	}
+	if resp, err := c.http.Do(req); err == nil {
+		resp.Body.Close()
+	}
+}
+
+// request builds a request carrying the user, the session and the credentials
+func (c *client) request(ctx context.Context, method, target string, headers http.Header, body string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+	req.Header.Set("X-Trino-User", c.user)
+	req.Header.Set("X-Trino-Source", c.source)
+	if c.catalog != "" {
+		req.Header.Set("X-Trino-Catalog", c.catalog)
+	}
+	if c.schema != "" {
+		req.Header.Set("X-Trino-Schema", c.schema)
+	}
+	if c.password != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+	return req, nil
+}
+
+// send sends a request, retrying while the coordinator is busy, and decodes the response.
+// Numbers are kept as json.Number, so large integers keep their precision.
+func (c *client) send(ctx context.Context, method, target string, headers http.Header, body string) (*queryResults, error) {
+	for {
+		req, err := c.request(ctx, method, target, headers, body)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case resp.StatusCode == http.StatusServiceUnavailable:
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(retryDelay):
+			}
+			continue
+		case resp.StatusCode >= 300:
+			message := strings.TrimSpace(string(raw))
+			if message == "" {
+				message = resp.Status
+			}
+			return nil, fmt.Errorf("trino: %s", message)
+		}
+
+		var results queryResults
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		if err := decoder.Decode(&results); err != nil {
+			return nil, fmt.Errorf("trino: failed to decode response: %w", err)
+		}
+		if err := results.err(); err != nil {
+			return nil, err
+		}
+		return &results, nil
+	}
+}
@@ -15,6 +15,28 @@ type DatabaseConnectionConfig struct {
 	Password    string `json:"password"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+
+	MigrationsDir  string `json:"migrations_dir,omitempty"`
+	SnapshotsDir   string `json:"snapshots_dir,omitempty"`
+	BackupsDir     string `json:"backups_dir,omitempty"`
+	AllowAdmin     bool   `json:"allow_admin,omitempty"`
+	QueryTimeout   int    `json:"query_timeout,omitempty"`
+	SchemaCacheTTL int    `json:"schema_cache_ttl,omitempty"`
+
+	WarmSchemaCache    bool `json:"warm_schema_cache,omitempty"`
+	SchemaChangeEvents bool `json:"schema_change_events,omitempty"`
+
+	ResultMemoryLimit  int64 `json:"result_memory_limit,omitempty"`
+	MaxConcurrentTools int   `json:"max_concurrent_tools,omitempty"`
+
+	CircuitBreakerFailures int `json:"circuit_breaker_failures,omitempty"`
+	CircuitBreakerCooldown int `json:"circuit_breaker_cooldown,omitempty"`
+
+	Timezone string `json:"timezone,omitempty"`
+
+	SessionMaxRows         int64 `json:"session_max_rows,omitempty"`
+	SessionMaxBytes        int64 `json:"session_max_bytes,omitempty"`
+	SessionMaxQuerySeconds int   `json:"session_max_query_seconds,omitempty"`
 }
 
 var (
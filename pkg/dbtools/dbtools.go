@@ -28,8 +28,41 @@ type DatabaseType string
 const (
 	// MySQL database type
 	MySQL DatabaseType = "mysql"
+	// TiDB database type, reached through the MySQL protocol
+	TiDB DatabaseType = "tidb"
 	// Postgres database type
 	Postgres DatabaseType = "postgres"
+	// CockroachDB database type, reached through the PostgreSQL wire protocol
+	CockroachDB DatabaseType = "cockroachdb"
+	// YugabyteDB database type, reached through its PostgreSQL-compatible YSQL API
+	Yugabyte DatabaseType = "yugabyte"
+	// Greenplum database type, whose coordinator speaks the PostgreSQL protocol
+	Greenplum DatabaseType = "greenplum"
+	// SQLite database type; the connection name is the database file path
+	SQLite DatabaseType = "sqlite"
+	// ClickHouse database type, reached through its MySQL-compatible interface
+	ClickHouse DatabaseType = "clickhouse"
+	// BigQuery database type; the connection name is the default dataset
+	BigQuery DatabaseType = "bigquery"
+	// Spanner database type; the connection name is the database of the instance
+	Spanner DatabaseType = "spanner"
+	// HANA database type for SAP HANA; the connection name is the default schema
+	HANA DatabaseType = "hana"
+	// Firebird database type; the connection name is the database path or alias
+	Firebird DatabaseType = "firebird"
+	// Trino database type; the connection name is the default catalog
+	Trino DatabaseType = "trino"
+	// Databricks database type for SQL warehouses; the connection name is the default catalog
+	Databricks DatabaseType = "databricks"
+	// MongoDB database type; it holds documents and is read by the collection tools
+	MongoDB DatabaseType = "mongodb"
+	// Redis database type; the connection name is the database number and keys are read by
+	// the key tools
+	Redis DatabaseType = "redis"
+	// Elasticsearch database type; its indices are read by the index tools
+	Elasticsearch DatabaseType = "elasticsearch"
+	// OpenSearch database type; it shares the Elasticsearch REST API and index tools
+	OpenSearch DatabaseType = "opensearch"
 )
 
 // Config represents database configuration
@@ -47,11 +80,51 @@ type ConnectionConfig struct {
 	Name     string       `json:"name"`
 	User     string       `json:"user"`
 	Password string       `json:"password"`
+
+	MigrationsDir  string `json:"migrations_dir,omitempty"`
+	SnapshotsDir   string `json:"snapshots_dir,omitempty"`
+	BackupsDir     string `json:"backups_dir,omitempty"`
+	AllowAdmin     bool   `json:"allow_admin,omitempty"`
+	QueryTimeout   int    `json:"query_timeout,omitempty"`
+	SchemaCacheTTL int    `json:"schema_cache_ttl,omitempty"`
+
+	WarmSchemaCache    bool `json:"warm_schema_cache,omitempty"`
+	SchemaChangeEvents bool `json:"schema_change_events,omitempty"`
+
+	ResultMemoryLimit  int64 `json:"result_memory_limit,omitempty"`
+	MaxConcurrentTools int   `json:"max_concurrent_tools,omitempty"`
+
+	CircuitBreakerFailures int `json:"circuit_breaker_failures,omitempty"`
+	CircuitBreakerCooldown int `json:"circuit_breaker_cooldown,omitempty"`
+
+	Timezone string `json:"timezone,omitempty"`
+
+	SessionMaxRows         int64 `json:"session_max_rows,omitempty"`
+	SessionMaxBytes        int64 `json:"session_max_bytes,omitempty"`
+	SessionMaxQuerySeconds int   `json:"session_max_query_seconds,omitempty"`
+
+	StatementCacheSize int `json:"statement_cache_size,omitempty"`
+
+	// Driver options, such as _journal_mode or mode=ro for SQLite
+	Options map[string]string `json:"options,omitempty"`
+
+	// BigQuery project, service-account key file and location; name is the default dataset
+	Project         string `json:"project,omitempty"`
+	CredentialsFile string `json:"credentials_file,omitempty"`
+	Location        string `json:"location,omitempty"`
+
+	// Spanner instance; Spanner shares the project and key file fields, and name is the database
+	Instance string `json:"instance,omitempty"`
+
+	// Databricks warehouse HTTP path and personal access token; name is the default catalog
+	HTTPPath string `json:"http_path,omitempty"`
+	Token    string `json:"token,omitempty"`
 }
 
 // MultiDBConfig represents configuration for multiple database connections
 type MultiDBConfig struct {
-	Connections []ConnectionConfig `json:"connections"`
+	Connections  []ConnectionConfig `json:"connections"`
+	MaxOpenPools int                `json:"max_open_pools,omitempty"`
 }
 
 // Database connection manager (singleton)
@@ -138,8 +211,8 @@ func InitDatabase(cfg *Config) error {
 		dbPassword := os.Getenv("DB_PASSWORD")
 		dbName := os.Getenv("DB_NAME")
 
-		// If we have basic connection details, create a config
-		if dbHost != "" && dbUser != "" {
+		// If we have basic connection details, create a config; SQLite only needs a file
+		if (dbHost != "" && dbUser != "") || (DatabaseType(dbType) == SQLite && dbName != "") {
 			dbPort, err := strconv.Atoi(dbPortStr)
 			if err != nil || dbPort == 0 {
 				dbPort = 3306 // Default MySQL port
@@ -166,6 +239,9 @@ func InitDatabase(cfg *Config) error {
 	if multiDBConfig == nil || len(multiDBConfig.Connections) == 0 {
 		return fmt.Errorf("no database configuration provided")
 	}
+	if multiDBConfig.MaxOpenPools == 0 {
+		multiDBConfig.MaxOpenPools = _getIntEnv("MAX_OPEN_POOLS", 0)
+	}
 
 	// Convert config to JSON for loading
 	configJSON, err := json.Marshal(multiDBConfig)
@@ -189,6 +265,73 @@ func InitDatabase(cfg *Config) error {
 			Password:    conn.Password,
 			Name:        conn.Name,
 			Description: "", // Default empty description
+
+			MigrationsDir:  conn.MigrationsDir,
+			SnapshotsDir:   conn.SnapshotsDir,
+			BackupsDir:     conn.BackupsDir,
+			AllowAdmin:     conn.AllowAdmin,
+			QueryTimeout:   conn.QueryTimeout,
+			SchemaCacheTTL: conn.SchemaCacheTTL,
+
+			WarmSchemaCache:    conn.WarmSchemaCache,
+			SchemaChangeEvents: conn.SchemaChangeEvents,
+
+			ResultMemoryLimit:  conn.ResultMemoryLimit,
+			MaxConcurrentTools: conn.MaxConcurrentTools,
+
+			CircuitBreakerFailures: conn.CircuitBreakerFailures,
+			CircuitBreakerCooldown: conn.CircuitBreakerCooldown,
+
+			Timezone: conn.Timezone,
+
+			SessionMaxRows:         conn.SessionMaxRows,
+			SessionMaxBytes:        conn.SessionMaxBytes,
+			SessionMaxQuerySeconds: conn.SessionMaxQuerySeconds,
+		}
+		if config.MigrationsDir == "" {
+			config.MigrationsDir = filepath.Join(_getEnv("MIGRATIONS_DIR", "migrations"), conn.ID)
+		}
+		if config.SnapshotsDir == "" {
+			config.SnapshotsDir = filepath.Join(_getEnv("SNAPSHOTS_DIR", "snapshots"), conn.ID)
+		}
+		if config.BackupsDir == "" {
+			config.BackupsDir = filepath.Join(_getEnv("BACKUPS_DIR", "backups"), conn.ID)
+		}
+		if config.QueryTimeout == 0 {
+			config.QueryTimeout = _getIntEnv("QUERY_TIMEOUT", 0)
+		}
+		if config.SchemaCacheTTL == 0 {
+			config.SchemaCacheTTL = _getIntEnv("SCHEMA_CACHE_TTL", 0)
+		}
+		if !config.WarmSchemaCache {
+			config.WarmSchemaCache, _ = strconv.ParseBool(os.Getenv("WARM_SCHEMA_CACHE"))
+		}
+		if !config.SchemaChangeEvents {
+			config.SchemaChangeEvents, _ = strconv.ParseBool(os.Getenv("SCHEMA_CHANGE_EVENTS"))
+		}
+		if config.ResultMemoryLimit == 0 {
+			config.ResultMemoryLimit = int64(_getIntEnv("RESULT_MEMORY_LIMIT", 0))
+		}
+		if config.MaxConcurrentTools == 0 {
+			config.MaxConcurrentTools = _getIntEnv("MAX_CONCURRENT_TOOLS_PER_DATABASE", 0)
+		}
+		if config.CircuitBreakerFailures == 0 {
+			config.CircuitBreakerFailures = _getIntEnv("CIRCUIT_BREAKER_FAILURES", 0)
+		}
+		if config.CircuitBreakerCooldown == 0 {
+			config.CircuitBreakerCooldown = _getIntEnv("CIRCUIT_BREAKER_COOLDOWN", 0)
+		}
+		if config.Timezone == "" {
+			config.Timezone = _getEnv("RESULT_TIMEZONE", "")
+		}
+		if config.SessionMaxRows == 0 {
+			config.SessionMaxRows = int64(_getIntEnv("SESSION_MAX_ROWS", 0))
+		}
+		if config.SessionMaxBytes == 0 {
+			config.SessionMaxBytes = int64(_getIntEnv("SESSION_MAX_BYTES", 0))
+		}
+		if config.SessionMaxQuerySeconds == 0 {
+			config.SessionMaxQuerySeconds = _getIntEnv("SESSION_MAX_QUERY_SECONDS", 0)
 		}
 
 		// Try to get description from the original JSON
@@ -240,6 +383,14 @@ func GetDatabase(id string) (db.Database, error) {
 	return dbManager.GetDatabase(id)
 }
 
+// GetDatabaseType returns the configured type of a database without opening its pool
+func GetDatabaseType(id string) (string, error) {
+	if dbManager == nil {
+		return "", fmt.Errorf("database manager not initialized")
+	}
+	return dbManager.GetDatabaseType(id)
+}
+
 // ListDatabases returns a list of available database connections
 func ListDatabases() []string {
 	if dbManager == nil {
@@ -581,7 +732,7 @@ func _loadConfigFromFile(cfg *Config) (*db.MultiDBConfig, error) {
 	return &dbConfig, nil
 }
 
-// _getEnv gets an environment variable or returns a default value (currently unused)
+// _getEnv gets an environment variable or returns a default value
 func _getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -590,7 +741,7 @@ func _getEnv(key, defaultValue string) string {
 	return value
 }
 
-// _getIntEnv gets an environment variable as an integer or returns a default value (currently unused)
+// _getIntEnv gets an environment variable as an integer or returns a default value
 func _getIntEnv(key string, defaultValue int) int {
 	value := os.Getenv(key)
 	if value == "" {
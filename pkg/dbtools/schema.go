@@ -21,10 +21,26 @@ type DatabaseStrategy interface {
 // NewDatabaseStrategy creates the appropriate strategy for the given database type
 func NewDatabaseStrategy(driverName string) DatabaseStrategy {
 	switch driverName {
-	case "postgres":
+	case "postgres", "cockroachdb", "yugabyte", "greenplum":
 		return &PostgresStrategy{}
-	case "mysql":
+	case "mysql", "tidb":
 		return &MySQLStrategy{}
+	case "sqlite3":
+		return &SQLiteStrategy{}
+	case "clickhouse":
+		return &ClickHouseStrategy{}
+	case "bigquery":
+		return &BigQueryStrategy{}
+	case "spanner":
+		return &SpannerStrategy{}
+	case "hana":
+		return &HANAStrategy{}
+	case "firebirdsql":
+		return &FirebirdStrategy{}
+	case "trino":
+		return &TrinoStrategy{}
+	case "databricks":
+		return &DatabricksStrategy{}
 	default:
 		logger.Warn("Unknown database driver: %s, will use generic strategy", driverName)
 		return &GenericStrategy{}
@@ -246,6 +262,414 @@ func (s *MySQLStrategy) GetRelationshipsQueries(table string) []queryWithArgs {
 	return queries
 }
 
+// SQLiteStrategy implements DatabaseStrategy for SQLite
+type SQLiteStrategy struct{}
+
+// GetTablesQueries returns queries for retrieving tables in SQLite
+func (s *SQLiteStrategy) GetTablesQueries() []queryWithArgs {
+	return []queryWithArgs{
+		{query: "SELECT name AS table_name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name"},
+	}
+}
+
+// GetColumnsQueries returns queries for retrieving columns in SQLite
+func (s *SQLiteStrategy) GetColumnsQueries(table string) []queryWithArgs {
+	return []queryWithArgs{
+		{
+			query: `
+				SELECT name AS column_name, type AS data_type,
+				CASE WHEN "notnull" = 0 THEN 'YES' ELSE 'NO' END AS is_nullable,
+				dflt_value AS column_default
+				FROM pragma_table_info(?)
+				ORDER BY cid
+			`,
+			args: []interface{}{table},
+		},
+	}
+}
+
+// GetRelationshipsQueries returns queries for retrieving relationships in SQLite
+func (s *SQLiteStrategy) GetRelationshipsQueries(table string) []queryWithArgs {
+	query := `
+		SELECT
+			'main' AS table_schema,
+			m.name || '_fk' || fk.id AS constraint_name,
+			m.name AS table_name,
+			fk."from" AS column_name,
+			'main' AS foreign_table_schema,
+			fk."table" AS foreign_table_name,
+			fk."to" AS foreign_column_name
+		FROM sqlite_master m
+		JOIN pragma_foreign_key_list(m.name) fk
+		WHERE m.type = 'table'
+	`
+	if table == "" {
+		return []queryWithArgs{{query: query}}
+	}
+	return []queryWithArgs{{
+		query: query + " AND (m.name = ? OR fk.\"table\" = ?)",
+		args:  []interface{}{table, table},
+	}}
+}
+
+// ClickHouseStrategy implements DatabaseStrategy for ClickHouse
+type ClickHouseStrategy struct{}
+
+// GetTablesQueries returns queries for retrieving tables in ClickHouse
+func (s *ClickHouseStrategy) GetTablesQueries() []queryWithArgs {
+	return []queryWithArgs{
+		{query: "SELECT name AS table_name FROM system.tables WHERE database = currentDatabase() AND NOT is_temporary ORDER BY name"},
+	}
+}
+
+// GetColumnsQueries returns queries for retrieving columns in ClickHouse
+func (s *ClickHouseStrategy) GetColumnsQueries(table string) []queryWithArgs {
+	return []queryWithArgs{
+		{
+			query: `
+				SELECT name AS column_name, type AS data_type,
+				if(startsWith(type, 'Nullable('), 'YES', 'NO') AS is_nullable,
+				default_expression AS column_default
+				FROM system.columns
+				WHERE database = currentDatabase() AND table = ?
+				ORDER BY position
+			`,
+			args: []interface{}{table},
+		},
+	}
+}
+
+// GetRelationshipsQueries returns a query without rows in the usual shape; ClickHouse has no
+// foreign keys
+func (s *ClickHouseStrategy) GetRelationshipsQueries(table string) []queryWithArgs {
+	return []queryWithArgs{{query: `
+		SELECT
+			'' AS table_schema, '' AS constraint_name, '' AS table_name, '' AS column_name,
+			'' AS foreign_table_schema, '' AS foreign_table_name, '' AS foreign_column_name
+		WHERE 0
+	`}}
+}
+
+// BigQueryStrategy implements DatabaseStrategy for BigQuery; unqualified INFORMATION_SCHEMA
+// views resolve to the default dataset of the connection
+type BigQueryStrategy struct{}
+
+// GetTablesQueries returns queries for retrieving tables in BigQuery
+func (s *BigQueryStrategy) GetTablesQueries() []queryWithArgs {
+	return []queryWithArgs{
+		{query: "SELECT table_name FROM INFORMATION_SCHEMA.TABLES ORDER BY table_name"},
+	}
+}
+
+// GetColumnsQueries returns queries for retrieving columns in BigQuery
+func (s *BigQueryStrategy) GetColumnsQueries(table string) []queryWithArgs {
+	return []queryWithArgs{
+		{
+			query: `
+				SELECT column_name, data_type, is_nullable, column_default
+				FROM INFORMATION_SCHEMA.COLUMNS
+				WHERE table_name = ?
+				ORDER BY ordinal_position
+			`,
+			args: []interface{}{table},
+		},
+	}
+}
+
+// GetRelationshipsQueries returns queries for retrieving the unenforced foreign keys of BigQuery
+func (s *BigQueryStrategy) GetRelationshipsQueries(table string) []queryWithArgs {
+	query := `
+		SELECT
+			tc.table_schema,
+			tc.constraint_name,
+			tc.table_name,
+			kcu.column_name,
+			ccu.table_schema AS foreign_table_schema,
+			ccu.table_name AS foreign_table_name,
+			ccu.column_name AS foreign_column_name
+		FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+		JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+			ON tc.constraint_name = kcu.constraint_name
+		JOIN INFORMATION_SCHEMA.CONSTRAINT_COLUMN_USAGE ccu
+			ON tc.constraint_name = ccu.constraint_name
+		WHERE tc.constraint_type = 'FOREIGN KEY'
+	`
+	if table == "" {
+		return []queryWithArgs{{query: query}}
+	}
+	return []queryWithArgs{{
+		query: query + " AND (tc.table_name = ? OR ccu.table_name = ?)",
+		args:  []interface{}{table, table},
+	}}
+}
+
+// SpannerStrategy implements DatabaseStrategy for Spanner; the tables of the default schema
+// have an empty table_schema, and interleaving is reported alongside foreign keys
+type SpannerStrategy struct{}
+
+// GetTablesQueries returns queries for retrieving tables in Spanner
+func (s *SpannerStrategy) GetTablesQueries() []queryWithArgs {
+	return []queryWithArgs{
+		{query: "SELECT table_name FROM INFORMATION_SCHEMA.TABLES WHERE table_schema = '' AND table_type = 'BASE TABLE' ORDER BY table_name"},
+	}
+}
+
+// GetColumnsQueries returns queries for retrieving columns in Spanner
+func (s *SpannerStrategy) GetColumnsQueries(table string) []queryWithArgs {
+	return []queryWithArgs{
+		{
+			query: `
+				SELECT column_name, spanner_type AS data_type, is_nullable, column_default
+				FROM INFORMATION_SCHEMA.COLUMNS
+				WHERE table_schema = '' AND table_name = @p1
+				ORDER BY ordinal_position
+			`,
+			args: []interface{}{table},
+		},
+	}
+}
+
+// GetRelationshipsQueries returns queries for retrieving the foreign keys of Spanner, with
+// each interleaved table listed as a reference to its parent on the primary key it shares
+func (s *SpannerStrategy) GetRelationshipsQueries(table string) []queryWithArgs {
+	foreignKeys := `
+		SELECT
+			tc.table_schema,
+			tc.constraint_name,
+			tc.table_name,
+			kcu.column_name,
+			ccu.table_schema AS foreign_table_schema,
+			ccu.table_name AS foreign_table_name,
+			ccu.column_name AS foreign_column_name
+		FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+		JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+			ON tc.constraint_schema = kcu.constraint_schema AND tc.constraint_name = kcu.constraint_name
+		JOIN INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS rc
+			ON tc.constraint_schema = rc.constraint_schema AND tc.constraint_name = rc.constraint_name
+		JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE ccu
+			ON rc.unique_constraint_schema = ccu.constraint_schema AND rc.unique_constraint_name = ccu.constraint_name
+			AND kcu.position_in_unique_constraint = ccu.ordinal_position
+		WHERE tc.constraint_type = 'FOREIGN KEY'
+	`
+	interleaved := `
+		SELECT
+			t.table_schema,
+			CONCAT('INTERLEAVE IN ', t.parent_table_name) AS constraint_name,
+			t.table_name,
+			k.column_name,
+			t.table_schema AS foreign_table_schema,
+			t.parent_table_name AS foreign_table_name,
+			k.column_name AS foreign_column_name
+		FROM INFORMATION_SCHEMA.TABLES t
+		JOIN INFORMATION_SCHEMA.INDEX_COLUMNS k
+			ON k.table_schema = t.table_schema AND k.table_name = t.parent_table_name AND k.index_type = 'PRIMARY_KEY'
+		WHERE t.parent_table_name IS NOT NULL
+	`
+	if table == "" {
+		return []queryWithArgs{{query: foreignKeys + " UNION ALL " + interleaved}}
+	}
+	return []queryWithArgs{{
+		query: foreignKeys + " AND (tc.table_name = @p1 OR ccu.table_name = @p1) UNION ALL " +
+			interleaved + " AND (t.table_name = @p1 OR t.parent_table_name = @p1)",
+		args: []interface{}{table},
+	}}
+}
+
+// HANAStrategy implements DatabaseStrategy for SAP HANA, reading the SYS catalog views of the
+// schema the session was switched to
+type HANAStrategy struct{}
+
+// GetTablesQueries returns queries for retrieving tables in SAP HANA
+func (s *HANAStrategy) GetTablesQueries() []queryWithArgs {
+	return []queryWithArgs{
+		{query: "SELECT TABLE_NAME AS table_name FROM SYS.TABLES WHERE SCHEMA_NAME = CURRENT_SCHEMA AND IS_USER_DEFINED_TYPE = 'FALSE' ORDER BY TABLE_NAME"},
+	}
+}
+
+// GetColumnsQueries returns queries for retrieving columns in SAP HANA
+func (s *HANAStrategy) GetColumnsQueries(table string) []queryWithArgs {
+	return []queryWithArgs{
+		{
+			query: `
+				SELECT COLUMN_NAME AS column_name, DATA_TYPE_NAME AS data_type,
+				CASE WHEN IS_NULLABLE = 'TRUE' THEN 'YES' ELSE 'NO' END AS is_nullable,
+				DEFAULT_VALUE AS column_default
+				FROM SYS.TABLE_COLUMNS
+				WHERE SCHEMA_NAME = CURRENT_SCHEMA AND TABLE_NAME = ?
+				ORDER BY POSITION
+			`,
+			args: []interface{}{table},
+		},
+	}
+}
+
+// GetRelationshipsQueries returns queries for retrieving the foreign keys of SAP HANA
+func (s *HANAStrategy) GetRelationshipsQueries(table string) []queryWithArgs {
+	query := `
+		SELECT
+			SCHEMA_NAME AS table_schema,
+			CONSTRAINT_NAME AS constraint_name,
+			TABLE_NAME AS table_name,
+			COLUMN_NAME AS column_name,
+			REFERENCED_SCHEMA_NAME AS foreign_table_schema,
+			REFERENCED_TABLE_NAME AS foreign_table_name,
+			REFERENCED_COLUMN_NAME AS foreign_column_name
+		FROM SYS.REFERENTIAL_CONSTRAINTS
+		WHERE SCHEMA_NAME = CURRENT_SCHEMA
+	`
+	if table == "" {
+		return []queryWithArgs{{query: query + " ORDER BY TABLE_NAME, CONSTRAINT_NAME, POSITION"}}
+	}
+	return []queryWithArgs{{
+		query: query + " AND (TABLE_NAME = ? OR REFERENCED_TABLE_NAME = ?) ORDER BY TABLE_NAME, CONSTRAINT_NAME, POSITION",
+		args:  []interface{}{table, table},
+	}}
+}
+
+// FirebirdStrategy implements DatabaseStrategy for Firebird, reading the RDB$ system tables;
+// their names are fixed-width CHAR columns and are trimmed
+type FirebirdStrategy struct{}
+
+// GetTablesQueries returns queries for retrieving tables in Firebird
+func (s *FirebirdStrategy) GetTablesQueries() []queryWithArgs {
+	return []queryWithArgs{
+		{query: "SELECT TRIM(RDB$RELATION_NAME) AS table_name FROM RDB$RELATIONS WHERE COALESCE(RDB$SYSTEM_FLAG, 0) = 0 AND RDB$VIEW_BLR IS NULL ORDER BY RDB$RELATION_NAME"},
+	}
+}
+
+// GetColumnsQueries returns queries for retrieving columns in Firebird
+func (s *FirebirdStrategy) GetColumnsQueries(table string) []queryWithArgs {
+	return []queryWithArgs{
+		{
+			query: `
+				SELECT TRIM(rf.RDB$FIELD_NAME) AS column_name,
+				TRIM(COALESCE(t.RDB$TYPE_NAME, '')) AS data_type,
+				CASE WHEN COALESCE(rf.RDB$NULL_FLAG, f.RDB$NULL_FLAG, 0) = 1 THEN 'NO' ELSE 'YES' END AS is_nullable,
+				CAST(COALESCE(rf.RDB$DEFAULT_SOURCE, f.RDB$DEFAULT_SOURCE) AS VARCHAR(1024)) AS column_default
+				FROM RDB$RELATION_FIELDS rf
+				JOIN RDB$FIELDS f ON f.RDB$FIELD_NAME = rf.RDB$FIELD_SOURCE
+				LEFT JOIN RDB$TYPES t ON t.RDB$FIELD_NAME = 'RDB$FIELD_TYPE' AND t.RDB$TYPE = f.RDB$FIELD_TYPE
+				WHERE rf.RDB$RELATION_NAME = ?
+				ORDER BY rf.RDB$FIELD_POSITION
+			`,
+			args: []interface{}{table},
+		},
+	}
+}
+
+// GetRelationshipsQueries returns queries for retrieving the foreign keys of Firebird; a key
+// and the unique key it references pair their columns through the segments of their indexes
+func (s *FirebirdStrategy) GetRelationshipsQueries(table string) []queryWithArgs {
+	query := `
+		SELECT
+			'' AS table_schema,
+			TRIM(fk.RDB$CONSTRAINT_NAME) AS constraint_name,
+			TRIM(fk.RDB$RELATION_NAME) AS table_name,
+			TRIM(fs.RDB$FIELD_NAME) AS column_name,
+			'' AS foreign_table_schema,
+			TRIM(uq.RDB$RELATION_NAME) AS foreign_table_name,
+			TRIM(us.RDB$FIELD_NAME) AS foreign_column_name
+		FROM RDB$RELATION_CONSTRAINTS fk
+		JOIN RDB$REF_CONSTRAINTS rc ON rc.RDB$CONSTRAINT_NAME = fk.RDB$CONSTRAINT_NAME
+		JOIN RDB$RELATION_CONSTRAINTS uq ON uq.RDB$CONSTRAINT_NAME = rc.RDB$CONST_NAME_UQ
+		JOIN RDB$INDEX_SEGMENTS fs ON fs.RDB$INDEX_NAME = fk.RDB$INDEX_NAME
+		JOIN RDB$INDEX_SEGMENTS us ON us.RDB$INDEX_NAME = uq.RDB$INDEX_NAME AND us.RDB$FIELD_POSITION = fs.RDB$FIELD_POSITION
+		WHERE fk.RDB$CONSTRAINT_TYPE = 'FOREIGN KEY'
+	`
+	if table == "" {
+		return []queryWithArgs{{query: query + " ORDER BY fk.RDB$RELATION_NAME, fk.RDB$CONSTRAINT_NAME, fs.RDB$FIELD_POSITION"}}
+	}
+	return []queryWithArgs{{
+		query: query + " AND (fk.RDB$RELATION_NAME = ? OR uq.RDB$RELATION_NAME = ?) ORDER BY fk.RDB$RELATION_NAME, fk.RDB$CONSTRAINT_NAME, fs.RDB$FIELD_POSITION",
+		args:  []interface{}{table, table},
+	}}
+}
+
+// TrinoStrategy implements DatabaseStrategy for Trino; information_schema belongs to the
+// default catalog of the connection
+type TrinoStrategy struct{}
+
+// GetTablesQueries returns queries for retrieving tables in Trino
+func (s *TrinoStrategy) GetTablesQueries() []queryWithArgs {
+	return []queryWithArgs{
+		{query: "SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema ORDER BY table_name"},
+	}
+}
+
+// GetColumnsQueries returns queries for retrieving columns in Trino
+func (s *TrinoStrategy) GetColumnsQueries(table string) []queryWithArgs {
+	return []queryWithArgs{
+		{
+			query: `
+				SELECT column_name, data_type, is_nullable, column_default
+				FROM information_schema.columns
+				WHERE table_name = ? AND table_schema = current_schema
+				ORDER BY ordinal_position
+			`,
+			args: []interface{}{table},
+		},
+	}
+}
+
+// GetRelationshipsQueries returns an empty result; Trino connectors expose no foreign keys
+func (s *TrinoStrategy) GetRelationshipsQueries(table string) []queryWithArgs {
+	return []queryWithArgs{{query: `
+		SELECT
+			'' AS table_schema, '' AS constraint_name, '' AS table_name, '' AS column_name,
+			'' AS foreign_table_schema, '' AS foreign_table_name, '' AS foreign_column_name
+		WHERE false
+	`}}
+}
+
+// DatabricksStrategy implements DatabaseStrategy for Databricks; information_schema belongs
+// to the default catalog of the connection
+type DatabricksStrategy struct{}
+
+// GetTablesQueries returns queries for retrieving tables in Databricks
+func (s *DatabricksStrategy) GetTablesQueries() []queryWithArgs {
+	return []queryWithArgs{
+		{query: "SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() ORDER BY table_name"},
+		{query: "SHOW TABLES"},
+	}
+}
+
+// GetColumnsQueries returns queries for retrieving columns in Databricks
+func (s *DatabricksStrategy) GetColumnsQueries(table string) []queryWithArgs {
+	return []queryWithArgs{
+		{
+			query: `
+				SELECT column_name, full_data_type AS data_type, is_nullable, column_default
+				FROM information_schema.columns
+				WHERE table_name = :p1 AND table_schema = current_schema()
+				ORDER BY ordinal_position
+			`,
+			args: []interface{}{table},
+		},
+	}
+}
+
+// GetRelationshipsQueries returns queries for the informational foreign keys of Unity Catalog
+func (s *DatabricksStrategy) GetRelationshipsQueries(table string) []queryWithArgs {
+	return []queryWithArgs{{
+		query: `
+			SELECT
+				kcu.table_schema, tc.constraint_name, kcu.table_name, kcu.column_name,
+				ccu.table_schema AS foreign_table_schema,
+				ccu.table_name AS foreign_table_name,
+				ccu.column_name AS foreign_column_name
+			FROM information_schema.table_constraints tc
+			JOIN information_schema.key_column_usage kcu
+				ON tc.constraint_schema = kcu.constraint_schema AND tc.constraint_name = kcu.constraint_name
+			JOIN information_schema.constraint_column_usage ccu
+				ON tc.constraint_schema = ccu.constraint_schema AND tc.constraint_name = ccu.constraint_name
+			WHERE tc.constraint_type = 'FOREIGN KEY'
+				AND tc.table_schema = current_schema()
+				AND (kcu.table_name = :p1 OR ccu.table_name = :p2)
+		`,
+		args: []interface{}{table, table},
+	}}
+}
+
 // GenericStrategy implements DatabaseStrategy for unknown database types
 type GenericStrategy struct{}
 